@@ -0,0 +1,270 @@
+// Package agent is the runner agent deployed inside a private network.
+// It connects to the Bytebase server over an outbound connection, claims the work dispatched to it
+// and executes the work locally against the databases the server cannot reach directly.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/proxy"
+)
+
+const (
+	heartbeatInterval = 30 * time.Second
+	claimInterval     = 3 * time.Second
+	requestTimeout    = 30 * time.Second
+)
+
+// Profile is the configuration of the agent.
+type Profile struct {
+	// ServerURL is the external URL of the Bytebase server, e.g. https://bytebase.example.com.
+	ServerURL string
+	// Token is the agent token generated when the agent is registered on the Bytebase server.
+	Token string
+	// Version is the agent version reported to the server.
+	Version string
+	// ResourceDir is the directory of the embedded binaries such as mysqlbinlog.
+	ResourceDir string
+	// PostgresProxyAddr is the address to serve the proxied Postgres sessions, empty to disable.
+	PostgresProxyAddr string
+	// MySQLProxyAddr is the address to serve the proxied MySQL sessions, empty to disable.
+	MySQLProxyAddr string
+	// ProxyTLSConfig is the TLS configuration of the proxy, nil to serve the proxy without TLS.
+	ProxyTLSConfig *tls.Config
+}
+
+// Agent is the runner agent.
+type Agent struct {
+	profile Profile
+	client  *http.Client
+}
+
+// NewAgent creates an agent.
+func NewAgent(profile Profile) *Agent {
+	return &Agent{
+		profile: profile,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Run sends the heartbeat, executes the claimed tasks and serves the proxy if enabled until ctx is canceled.
+func (a *Agent) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	if a.profile.PostgresProxyAddr != "" || a.profile.MySQLProxyAddr != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := proxy.NewProxy(proxy.Profile{
+				PostgresAddr: a.profile.PostgresProxyAddr,
+				MySQLAddr:    a.profile.MySQLProxyAddr,
+				TLSConfig:    a.profile.ProxyTLSConfig,
+				Version:      a.profile.Version,
+			}, a)
+			if err := p.Run(ctx); err != nil {
+				log.Error("Failed to run proxy", zap.Error(err))
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			if err := a.post(ctx, "/heartbeat", &api.AgentHeartbeat{Version: a.profile.Version}, nil); err != nil {
+				log.Warn("Failed to send heartbeat", zap.Error(err))
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(claimInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// Drain the queue before waiting for the next tick.
+			for {
+				dispatch := &api.AgentTaskDispatch{}
+				found, err := a.claim(ctx, dispatch)
+				if err != nil {
+					log.Warn("Failed to claim task", zap.Error(err))
+					break
+				}
+				if !found {
+					break
+				}
+				wg.Add(1)
+				go func(dispatch *api.AgentTaskDispatch) {
+					defer wg.Done()
+					a.execute(ctx, dispatch)
+				}(dispatch)
+			}
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+	}
+}
+
+func (a *Agent) claim(ctx context.Context, dispatch *api.AgentTaskDispatch) (bool, error) {
+	var found bool
+	err := a.post(ctx, "/task/claim", nil, func(resp *http.Response) error {
+		if resp.StatusCode == http.StatusNoContent {
+			return nil
+		}
+		found = true
+		return json.NewDecoder(resp.Body).Decode(dispatch)
+	})
+	return found, err
+}
+
+func (a *Agent) execute(ctx context.Context, dispatch *api.AgentTaskDispatch) {
+	log.Info("Start agent task",
+		zap.Int("id", dispatch.ID),
+		zap.String("type", string(dispatch.Type)),
+		zap.String("instance", dispatch.ConnectionCtx.InstanceName),
+	)
+	result, err := a.run(ctx, dispatch)
+	if err != nil {
+		result = &api.AgentTaskResult{Error: err.Error()}
+	}
+	if err := a.post(ctx, fmt.Sprintf("/task/%d/result", dispatch.ID), result, nil); err != nil {
+		log.Error("Failed to report agent task result", zap.Int("id", dispatch.ID), zap.Error(err))
+		return
+	}
+	log.Info("Finish agent task", zap.Int("id", dispatch.ID), zap.String("error", result.Error))
+}
+
+func (a *Agent) run(ctx context.Context, dispatch *api.AgentTaskDispatch) (*api.AgentTaskResult, error) {
+	driver, err := db.Open(
+		ctx,
+		dispatch.Engine,
+		db.DriverConfig{ResourceDir: a.profile.ResourceDir},
+		dispatch.ConnectionConfig,
+		dispatch.ConnectionCtx,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect database at %s:%s with user %q, error: %w", dispatch.ConnectionConfig.Host, dispatch.ConnectionConfig.Port, dispatch.ConnectionConfig.Username, err)
+	}
+	defer driver.Close(ctx)
+
+	switch dispatch.Type {
+	case api.AgentTaskInstanceSync:
+		syncResult, err := syncInstance(ctx, driver)
+		if err != nil {
+			return nil, err
+		}
+		return &api.AgentTaskResult{Sync: syncResult}, nil
+	case api.AgentTaskDatabaseMigrate:
+		if dispatch.Payload.MigrationInfo == nil {
+			return nil, fmt.Errorf("missing migration info")
+		}
+		if err := driver.SetupMigrationIfNeeded(ctx); err != nil {
+			return nil, fmt.Errorf("failed to setup migration schema, error: %w", err)
+		}
+		// The driver applies the session settings resolved by the server before executing the statement.
+		migrationID, schema, err := driver.ExecuteMigration(db.WithSessionSetting(ctx, dispatch.Payload.SessionSetting), dispatch.Payload.MigrationInfo, dispatch.Payload.Statement)
+		if err != nil {
+			return nil, err
+		}
+		return &api.AgentTaskResult{Migrate: &api.AgentTaskMigrateResult{MigrationID: migrationID, Schema: schema}}, nil
+	case api.AgentTaskDatabaseQuery:
+		rowSet, err := driver.Query(ctx, dispatch.Payload.Statement, dispatch.Payload.Limit)
+		if err != nil {
+			return nil, err
+		}
+		return &api.AgentTaskResult{Query: &api.AgentTaskQueryResult{RowSet: rowSet}}, nil
+	}
+	return nil, fmt.Errorf("unsupported agent task type %q", dispatch.Type)
+}
+
+func syncInstance(ctx context.Context, driver db.Driver) (*api.AgentTaskSyncResult, error) {
+	// Same as adding the instance on the server, it's OK if the migration schema can't be set up.
+	if err := driver.SetupMigrationIfNeeded(ctx); err != nil {
+		log.Warn("Failed to setup migration schema", zap.Error(err))
+	}
+
+	instanceMeta, err := driver.SyncInstance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.AgentTaskSyncResult{
+		InstanceMeta:     instanceMeta,
+		SchemaVersionMap: make(map[string]string),
+	}
+	for _, database := range instanceMeta.DatabaseList {
+		schema, err := driver.SyncDBSchema(ctx, database.Name)
+		if err != nil {
+			result.ErrorList = append(result.ErrorList, err.Error())
+			continue
+		}
+		result.SchemaList = append(result.SchemaList, schema)
+
+		limit := 1
+		history, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
+			Database: &database.Name,
+			Limit:    &limit,
+		})
+		if err != nil {
+			result.ErrorList = append(result.ErrorList, fmt.Sprintf("failed to get migration history for database %q, error %v", database.Name, err))
+			continue
+		}
+		if len(history) == 1 {
+			result.SchemaVersionMap[database.Name] = history[0].Version
+		}
+	}
+	return result, nil
+}
+
+// post sends the request to the agent API of the server, handle is called on success if not nil.
+func (a *Agent) post(ctx context.Context, path string, body interface{}, handle func(*http.Response) error) error {
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(buf)
+	}
+	url := fmt.Sprintf("%s/v1/agent%s", strings.TrimSuffix(a.profile.ServerURL, "/"), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(api.AgentTokenHeader, a.profile.Token)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		content, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("POST %s failed with status %d, body %s", url, resp.StatusCode, content)
+	}
+	if handle != nil {
+		return handle(resp)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// Connect implements proxy.Gateway, the server authenticates the user and checks the access to the database.
+func (a *Agent) Connect(ctx context.Context, connect *api.ProxyConnect) (*api.ProxySession, error) {
+	session := &api.ProxySession{}
+	if err := a.post(ctx, "/proxy/connect", connect, func(resp *http.Response) error {
+		return json.NewDecoder(resp.Body).Decode(session)
+	}); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Query implements proxy.Gateway, the server checks the statement and runs it on the database.
+func (a *Agent) Query(ctx context.Context, query *api.ProxyQuery) (*api.ProxyQueryResult, error) {
+	result := &api.ProxyQueryResult{}
+	if err := a.post(ctx, "/proxy/query", query, func(resp *http.Response) error {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
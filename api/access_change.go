@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// AccessChangeAction is the action of an access change.
+type AccessChangeAction string
+
+const (
+	// AccessChangeCreateUser creates a MySQL user or a Postgres login role.
+	AccessChangeCreateUser AccessChangeAction = "CREATE_USER"
+	// AccessChangeAlterUser changes the password of the user.
+	AccessChangeAlterUser AccessChangeAction = "ALTER_USER"
+	// AccessChangeDropUser drops the user.
+	AccessChangeDropUser AccessChangeAction = "DROP_USER"
+	// AccessChangeGrant grants the privileges on the database or the table to the user.
+	AccessChangeGrant AccessChangeAction = "GRANT"
+	// AccessChangeRevoke revokes the privileges on the database or the table from the user.
+	AccessChangeRevoke AccessChangeAction = "REVOKE"
+
+	// AccessChangeAllPrivileges is the privilege granting all the privileges.
+	AccessChangeAllPrivileges = "ALL PRIVILEGES"
+	// accessChangeDefaultHost is the default MySQL user host matching any host.
+	accessChangeDefaultHost = "%"
+	// accessChangeMaskedPassword replaces the password in the recorded statements.
+	accessChangeMaskedPassword = "******"
+)
+
+var (
+	// accessChangeMySQLPrivileges is the database and table privileges allowed to grant in MySQL and TiDB.
+	// The global privileges such as SUPER and FILE are not allowed, they need a DBA at the console.
+	accessChangeMySQLPrivileges = map[string]bool{
+		"SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true,
+		"CREATE": true, "DROP": true, "ALTER": true, "INDEX": true, "REFERENCES": true,
+		"CREATE VIEW": true, "SHOW VIEW": true, "TRIGGER": true, "EXECUTE": true,
+		"CREATE ROUTINE": true, "ALTER ROUTINE": true, "EVENT": true, "LOCK TABLES": true,
+		"CREATE TEMPORARY TABLES": true, AccessChangeAllPrivileges: true,
+	}
+	// accessChangePostgresPrivileges is the table privileges allowed to grant in Postgres.
+	accessChangePostgresPrivileges = map[string]bool{
+		"SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true,
+		"TRUNCATE": true, "REFERENCES": true, "TRIGGER": true, AccessChangeAllPrivileges: true,
+	}
+)
+
+// AccessChange is the structured form of a user or grant change, the statement is generated from it.
+type AccessChange struct {
+	Action AccessChangeAction `json:"action"`
+	// UserName is the MySQL user name or the Postgres role name.
+	UserName string `json:"userName"`
+	// Host is the MySQL user host, default to "%". It's not applicable to Postgres.
+	Host string `json:"host"`
+	// Password is only used when creating or altering the user. It's masked in the recorded statement, and it's only
+	// accepted in the issue create context, the issue and the task keep the EncryptedPassword instead.
+	Password string `json:"password,omitempty"`
+	// EncryptedPassword is the Password encrypted with the workspace secret, it's removed from the task once the task is done.
+	EncryptedPassword string `json:"encryptedPassword,omitempty"`
+	// PrivilegeList is the privileges to grant or revoke, e.g. SELECT and INSERT.
+	PrivilegeList []string `json:"privilegeList"`
+	// SchemaName is the schema of the tables in Postgres, default to "public".
+	SchemaName string `json:"schemaName"`
+	// TableName is the table the privileges are on, empty means all the tables in the database, or in the schema in Postgres.
+	TableName string `json:"tableName"`
+	// WithGrantOption allows the user to grant the privileges to others.
+	WithGrantOption bool `json:"withGrantOption"`
+}
+
+// Review returns the error if the access change violates the policy.
+func (ap AccessChangePolicy) Review(engine db.Type, change *AccessChange) error {
+	if ap.DisallowAllPrivileges && change.Action == AccessChangeGrant {
+		for _, privilege := range change.PrivilegeList {
+			if privilege == AccessChangeAllPrivileges {
+				return &common.Error{Code: common.Invalid, Err: fmt.Errorf("granting %s to %q is disallowed by the access change policy", AccessChangeAllPrivileges, change.UserName)}
+			}
+		}
+	}
+	if ap.DisallowWildcardHost && engine != db.Postgres && strings.Contains(change.Host, "%") {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("host %q of user %q is disallowed by the access change policy, specify the host explicitly", change.Host, change.UserName)}
+	}
+	if ap.DisallowGrantOption && change.Action == AccessChangeGrant && change.WithGrantOption {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("granting with the GRANT OPTION to %q is disallowed by the access change policy", change.UserName)}
+	}
+	return nil
+}
+
+// NormalizeAccessChange validates the access change and fills the defaults.
+func NormalizeAccessChange(engine db.Type, change *AccessChange) error {
+	change.UserName = strings.TrimSpace(change.UserName)
+	if change.UserName == "" {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("user name must not be empty")}
+	}
+	if engine == db.Postgres {
+		change.Host = ""
+		if change.SchemaName == "" {
+			change.SchemaName = "public"
+		}
+	} else {
+		change.SchemaName = ""
+		if change.Host == "" {
+			change.Host = accessChangeDefaultHost
+		}
+	}
+
+	switch change.Action {
+	case AccessChangeCreateUser, AccessChangeAlterUser:
+		if change.Password == "" {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("password of user %q must not be empty", change.UserName)}
+		}
+	case AccessChangeDropUser:
+	case AccessChangeGrant, AccessChangeRevoke:
+		allowed := accessChangeMySQLPrivileges
+		if engine == db.Postgres {
+			allowed = accessChangePostgresPrivileges
+		}
+		if len(change.PrivilegeList) == 0 {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("privilege list of user %q must not be empty", change.UserName)}
+		}
+		for i, privilege := range change.PrivilegeList {
+			privilege = strings.ToUpper(strings.Join(strings.Fields(privilege), " "))
+			if privilege == "ALL" {
+				privilege = AccessChangeAllPrivileges
+			}
+			if !allowed[privilege] {
+				return &common.Error{Code: common.Invalid, Err: fmt.Errorf("privilege %q is not allowed for %s", change.PrivilegeList[i], engine)}
+			}
+			change.PrivilegeList[i] = privilege
+		}
+	default:
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid access change action %q", change.Action)}
+	}
+	return nil
+}
+
+// GetAccessChangeStatement returns the statement of the normalized access change on the database.
+// The password is masked if maskPassword is true, so that the statement can be shown and recorded.
+func GetAccessChangeStatement(engine db.Type, databaseName string, change *AccessChange, maskPassword bool) string {
+	password := change.Password
+	if maskPassword {
+		password = accessChangeMaskedPassword
+	}
+	if engine == db.Postgres {
+		return getPostgresAccessChangeStatement(change, password)
+	}
+	return getMySQLAccessChangeStatement(databaseName, change, password)
+}
+
+func getMySQLAccessChangeStatement(databaseName string, change *AccessChange, password string) string {
+	quoteString := func(s string) string {
+		return fmt.Sprintf("'%s'", strings.NewReplacer(`\`, `\\`, "'", "''").Replace(s))
+	}
+	quoteIdentifier := func(s string) string {
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "`", "``"))
+	}
+	user := fmt.Sprintf("%s@%s", quoteString(change.UserName), quoteString(change.Host))
+	switch change.Action {
+	case AccessChangeCreateUser:
+		return fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s;", user, quoteString(password))
+	case AccessChangeAlterUser:
+		return fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s;", user, quoteString(password))
+	case AccessChangeDropUser:
+		return fmt.Sprintf("DROP USER %s;", user)
+	}
+	object := fmt.Sprintf("%s.*", quoteIdentifier(databaseName))
+	if change.TableName != "" {
+		object = fmt.Sprintf("%s.%s", quoteIdentifier(databaseName), quoteIdentifier(change.TableName))
+	}
+	privileges := strings.Join(change.PrivilegeList, ", ")
+	if change.Action == AccessChangeRevoke {
+		return fmt.Sprintf("REVOKE %s ON %s FROM %s;", privileges, object, user)
+	}
+	stmt := fmt.Sprintf("GRANT %s ON %s TO %s", privileges, object, user)
+	if change.WithGrantOption {
+		stmt += " WITH GRANT OPTION"
+	}
+	return stmt + ";"
+}
+
+func getPostgresAccessChangeStatement(change *AccessChange, password string) string {
+	quoteString := func(s string) string {
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+	}
+	quoteIdentifier := func(s string) string {
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+	}
+	role := quoteIdentifier(change.UserName)
+	switch change.Action {
+	case AccessChangeCreateUser:
+		return fmt.Sprintf("CREATE ROLE %s WITH LOGIN PASSWORD %s;", role, quoteString(password))
+	case AccessChangeAlterUser:
+		return fmt.Sprintf("ALTER ROLE %s WITH PASSWORD %s;", role, quoteString(password))
+	case AccessChangeDropUser:
+		return fmt.Sprintf("DROP ROLE %s;", role)
+	}
+	schema := quoteIdentifier(change.SchemaName)
+	object := fmt.Sprintf("ALL TABLES IN SCHEMA %s", schema)
+	if change.TableName != "" {
+		object = fmt.Sprintf("TABLE %s.%s", schema, quoteIdentifier(change.TableName))
+	}
+	privileges := strings.Join(change.PrivilegeList, ", ")
+	if change.Action == AccessChangeRevoke {
+		return fmt.Sprintf("REVOKE %s ON %s FROM %s;", privileges, object, role)
+	}
+	// The role can't access the tables without the usage of the schema.
+	stmt := fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s;\nGRANT %s ON %s TO %s", schema, role, privileges, object, role)
+	if change.WithGrantOption {
+		stmt += " WITH GRANT OPTION"
+	}
+	return stmt + ";"
+}
+
+// AccessChangeHistory is the API message for a recorded access change, it's separate from the schema migration history.
+type AccessChangeHistory struct {
+	ID int `jsonapi:"primary,accessChangeHistory"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	InstanceID int `jsonapi:"attr,instanceId"`
+	DatabaseID int `jsonapi:"attr,databaseId"`
+	// IssueID is 0 if the task doesn't belong to an issue.
+	IssueID int `jsonapi:"attr,issueId"`
+	TaskID  int `jsonapi:"attr,taskId"`
+
+	// Domain specific fields
+	Action   AccessChangeAction `jsonapi:"attr,action"`
+	UserName string             `jsonapi:"attr,userName"`
+	// Statement is the executed statement with the password masked.
+	Statement string `jsonapi:"attr,statement"`
+	// Error is the error executing the statement, empty if it succeeded.
+	Error string `jsonapi:"attr,error"`
+}
+
+// AccessChangeHistoryCreate is the API message for recording an access change.
+type AccessChangeHistoryCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	InstanceID int
+	DatabaseID int
+	IssueID    int
+	TaskID     int
+
+	// Domain specific fields
+	Action    AccessChangeAction
+	UserName  string
+	Statement string
+	Error     string
+}
+
+// AccessChangeHistoryFind is the API message for finding access change histories.
+type AccessChangeHistoryFind struct {
+	ID *int
+
+	// Related fields
+	InstanceID *int
+	DatabaseID *int
+}
+
+func (find *AccessChangeHistoryFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
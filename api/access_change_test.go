@@ -0,0 +1,112 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestNormalizeAccessChange(t *testing.T) {
+	change := &AccessChange{Action: AccessChangeGrant, UserName: " alice ", PrivilegeList: []string{"select", "all", "show  view"}}
+	require.NoError(t, NormalizeAccessChange(db.MySQL, change))
+	require.Equal(t, "alice", change.UserName)
+	require.Equal(t, "%", change.Host)
+	require.Equal(t, []string{"SELECT", AccessChangeAllPrivileges, "SHOW VIEW"}, change.PrivilegeList)
+
+	change = &AccessChange{Action: AccessChangeGrant, UserName: "alice", Host: "10.0.0.1", PrivilegeList: []string{"truncate"}}
+	require.NoError(t, NormalizeAccessChange(db.Postgres, change))
+	require.Equal(t, "", change.Host)
+	require.Equal(t, "public", change.SchemaName)
+	require.Equal(t, []string{"TRUNCATE"}, change.PrivilegeList)
+
+	tests := []struct {
+		engine db.Type
+		change *AccessChange
+	}{
+		{db.MySQL, &AccessChange{Action: AccessChangeCreateUser, UserName: " "}},
+		{db.MySQL, &AccessChange{Action: AccessChangeCreateUser, UserName: "alice"}},
+		{db.MySQL, &AccessChange{Action: AccessChangeGrant, UserName: "alice"}},
+		{db.MySQL, &AccessChange{Action: AccessChangeGrant, UserName: "alice", PrivilegeList: []string{"SUPER"}}},
+		{db.MySQL, &AccessChange{Action: AccessChangeGrant, UserName: "alice", PrivilegeList: []string{"TRUNCATE"}}},
+		{db.Postgres, &AccessChange{Action: AccessChangeGrant, UserName: "alice", PrivilegeList: []string{"CREATE VIEW"}}},
+		{db.Postgres, &AccessChange{Action: "RENAME_USER", UserName: "alice"}},
+	}
+	for _, test := range tests {
+		require.Error(t, NormalizeAccessChange(test.engine, test.change), "%+v", test.change)
+	}
+}
+
+func TestGetAccessChangeStatement(t *testing.T) {
+	tests := []struct {
+		engine       db.Type
+		change       *AccessChange
+		maskPassword bool
+		want         string
+	}{
+		{
+			engine:       db.MySQL,
+			change:       &AccessChange{Action: AccessChangeCreateUser, UserName: "alice", Host: "%", Password: "it's"},
+			maskPassword: false,
+			want:         "CREATE USER 'alice'@'%' IDENTIFIED BY 'it''s';",
+		},
+		{
+			engine:       db.MySQL,
+			change:       &AccessChange{Action: AccessChangeAlterUser, UserName: "alice", Host: "10.%", Password: "secret"},
+			maskPassword: true,
+			want:         "ALTER USER 'alice'@'10.%' IDENTIFIED BY '******';",
+		},
+		{
+			engine: db.MySQL,
+			change: &AccessChange{Action: AccessChangeDropUser, UserName: "alice", Host: "%"},
+			want:   "DROP USER 'alice'@'%';",
+		},
+		{
+			engine: db.MySQL,
+			change: &AccessChange{Action: AccessChangeGrant, UserName: "alice", Host: "%", PrivilegeList: []string{"SELECT", "INSERT"}, WithGrantOption: true},
+			want:   "GRANT SELECT, INSERT ON `shop`.* TO 'alice'@'%' WITH GRANT OPTION;",
+		},
+		{
+			engine: db.TiDB,
+			change: &AccessChange{Action: AccessChangeRevoke, UserName: "alice", Host: "%", PrivilegeList: []string{"UPDATE"}, TableName: "order"},
+			want:   "REVOKE UPDATE ON `shop`.`order` FROM 'alice'@'%';",
+		},
+		{
+			engine:       db.Postgres,
+			change:       &AccessChange{Action: AccessChangeCreateUser, UserName: "alice", Password: "secret"},
+			maskPassword: true,
+			want:         `CREATE ROLE "alice" WITH LOGIN PASSWORD '******';`,
+		},
+		{
+			engine: db.Postgres,
+			change: &AccessChange{Action: AccessChangeAlterUser, UserName: "alice", Password: "secret"},
+			want:   `ALTER ROLE "alice" WITH PASSWORD 'secret';`,
+		},
+		{
+			engine: db.Postgres,
+			change: &AccessChange{Action: AccessChangeGrant, UserName: "alice", SchemaName: "public", PrivilegeList: []string{"SELECT"}},
+			want:   "GRANT USAGE ON SCHEMA \"public\" TO \"alice\";\nGRANT SELECT ON ALL TABLES IN SCHEMA \"public\" TO \"alice\";",
+		},
+		{
+			engine: db.Postgres,
+			change: &AccessChange{Action: AccessChangeRevoke, UserName: "alice", SchemaName: "sales", TableName: "order", PrivilegeList: []string{AccessChangeAllPrivileges}},
+			want:   `REVOKE ALL PRIVILEGES ON TABLE "sales"."order" FROM "alice";`,
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, GetAccessChangeStatement(test.engine, "shop", test.change, test.maskPassword))
+	}
+}
+
+func TestAccessChangePolicyReview(t *testing.T) {
+	grantAll := &AccessChange{Action: AccessChangeGrant, UserName: "alice", Host: "%", PrivilegeList: []string{AccessChangeAllPrivileges}, WithGrantOption: true}
+	require.NoError(t, AccessChangePolicy{}.Review(db.MySQL, grantAll))
+	require.Error(t, AccessChangePolicy{DisallowAllPrivileges: true}.Review(db.MySQL, grantAll))
+	require.Error(t, AccessChangePolicy{DisallowWildcardHost: true}.Review(db.MySQL, grantAll))
+	require.Error(t, AccessChangePolicy{DisallowGrantOption: true}.Review(db.MySQL, grantAll))
+
+	// Revoking is always allowed, and Postgres roles have no host.
+	revokeAll := &AccessChange{Action: AccessChangeRevoke, UserName: "alice", PrivilegeList: []string{AccessChangeAllPrivileges}}
+	require.NoError(t, AccessChangePolicy{DisallowAllPrivileges: true, DisallowWildcardHost: true}.Review(db.Postgres, revokeAll))
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// AccountReportSchedule is the schedule generating the account reports.
+type AccountReportSchedule string
+
+const (
+	// AccountReportScheduleUnset means the account reports are only generated manually.
+	AccountReportScheduleUnset AccountReportSchedule = "UNSET"
+	// AccountReportScheduleDaily generates an account report every day.
+	AccountReportScheduleDaily AccountReportSchedule = "DAILY"
+	// AccountReportScheduleWeekly generates an account report every week.
+	AccountReportScheduleWeekly AccountReportSchedule = "WEEKLY"
+
+	// DefaultAccountUnusedDays is the default number of days without activity before an account is reported unused.
+	DefaultAccountUnusedDays = 90
+)
+
+// Interval returns the interval between the scheduled reports, 0 if the reports aren't scheduled.
+func (s AccountReportSchedule) Interval() time.Duration {
+	switch s {
+	case AccountReportScheduleDaily:
+		return 24 * time.Hour
+	case AccountReportScheduleWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// AccountReportSetting is the value of the workspace account report setting.
+type AccountReportSetting struct {
+	Schedule AccountReportSchedule `json:"schedule"`
+	// UnusedDays is the number of days without activity before an account is reported unused.
+	UnusedDays int `json:"unusedDays"`
+}
+
+// Validate validates the account report setting.
+func (s *AccountReportSetting) Validate() error {
+	switch s.Schedule {
+	case AccountReportScheduleUnset, AccountReportScheduleDaily, AccountReportScheduleWeekly:
+	default:
+		return fmt.Errorf("invalid account report schedule %q", s.Schedule)
+	}
+	if s.UnusedDays <= 0 {
+		return fmt.Errorf("account unused days must be positive, got %d", s.UnusedDays)
+	}
+	return nil
+}
+
+// AccountFlag is a finding about a database account for the access recertification.
+type AccountFlag string
+
+const (
+	// AccountFlagSuperuser flags the accounts with the superuser privilege.
+	AccountFlagSuperuser AccountFlag = "SUPERUSER"
+	// AccountFlagNoPasswordExpiry flags the accounts whose password never expires.
+	AccountFlagNoPasswordExpiry AccountFlag = "NO_PASSWORD_EXPIRY"
+	// AccountFlagUnused flags the accounts without activity for the unused days.
+	AccountFlagUnused AccountFlag = "UNUSED"
+)
+
+// Account is a login account of an instance in the account report.
+type Account struct {
+	InstanceID      int     `json:"instanceId"`
+	InstanceName    string  `json:"instanceName"`
+	EnvironmentName string  `json:"environmentName"`
+	Engine          db.Type `json:"engine"`
+	// Name is the same as the synced instance user, e.g. 'user'@'host' for MySQL.
+	Name      string `json:"name"`
+	Superuser bool   `json:"superuser"`
+	// PasswordExpireTs is 0 if the password never expires.
+	PasswordExpireTs int64 `json:"passwordExpireTs"`
+	// FirstSeenTs is when the account is first found by the reports.
+	FirstSeenTs int64 `json:"firstSeenTs"`
+	// LastActivityTs is when the account is last seen connected by the reports, 0 if never.
+	// The databases don't keep the last login time, so it's a heuristic based on the sessions found while generating the reports.
+	LastActivityTs int64         `json:"lastActivityTs"`
+	FlagList       []AccountFlag `json:"flagList"`
+}
+
+// AccountReportPayload is the content of an account report.
+type AccountReportPayload struct {
+	AccountList []*Account `json:"accountList"`
+	// InstanceErrorList is the instances whose accounts can't be listed, e.g. the instance is unreachable.
+	InstanceErrorList []string `json:"instanceErrorList"`
+}
+
+// AccountReport is the API message for an account report.
+type AccountReport struct {
+	ID int `jsonapi:"primary,accountReport"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Domain specific fields
+	AccountCount int `jsonapi:"attr,accountCount"`
+	FlaggedCount int `jsonapi:"attr,flaggedCount"`
+	// Payload is the JSON encoded AccountReportPayload.
+	Payload string `jsonapi:"attr,payload"`
+}
+
+// AccountReportCreate is the API message for creating an account report.
+type AccountReportCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Domain specific fields
+	AccountCount int
+	FlaggedCount int
+	Payload      string
+}
+
+// AccountReportFind is the API message for finding account reports.
+type AccountReportFind struct {
+	ID *int
+
+	// Limit is the number of the latest reports to return.
+	Limit *int
+}
+
+func (find *AccountReportFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// UpdateAccountActivity carries over the first seen and the last activity time of the account from the previous report,
+// and sets the last activity time to now if the account is connected.
+func UpdateAccountActivity(account *Account, previous *Account, connected bool, now int64) {
+	account.FirstSeenTs = now
+	if previous != nil {
+		account.FirstSeenTs = previous.FirstSeenTs
+		account.LastActivityTs = previous.LastActivityTs
+	}
+	if connected {
+		account.LastActivityTs = now
+	}
+}
+
+// SetAccountFlagList sets the flags of the account.
+// An account is unused if it has no activity in the unused days, counting from when it's first seen.
+func SetAccountFlagList(account *Account, unusedDays int, now int64) {
+	account.FlagList = nil
+	if account.Superuser {
+		account.FlagList = append(account.FlagList, AccountFlagSuperuser)
+	}
+	if account.PasswordExpireTs == 0 {
+		account.FlagList = append(account.FlagList, AccountFlagNoPasswordExpiry)
+	}
+	lastActivityTs := account.LastActivityTs
+	if lastActivityTs == 0 {
+		lastActivityTs = account.FirstSeenTs
+	}
+	if now-lastActivityTs >= int64(unusedDays)*24*60*60 {
+		account.FlagList = append(account.FlagList, AccountFlagUnused)
+	}
+}
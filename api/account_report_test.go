@@ -0,0 +1,62 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountReportSettingValidate(t *testing.T) {
+	require.NoError(t, (&AccountReportSetting{Schedule: AccountReportScheduleWeekly, UnusedDays: 30}).Validate())
+	require.Error(t, (&AccountReportSetting{Schedule: "HOURLY", UnusedDays: 30}).Validate())
+	require.Error(t, (&AccountReportSetting{Schedule: AccountReportScheduleUnset, UnusedDays: 0}).Validate())
+}
+
+func TestUpdateAccountActivity(t *testing.T) {
+	account := &Account{}
+	UpdateAccountActivity(account, nil /* previous */, false /* connected */, 1000)
+	require.Equal(t, int64(1000), account.FirstSeenTs)
+	require.Equal(t, int64(0), account.LastActivityTs)
+
+	previous := &Account{FirstSeenTs: 100, LastActivityTs: 500}
+	account = &Account{}
+	UpdateAccountActivity(account, previous, false /* connected */, 1000)
+	require.Equal(t, int64(100), account.FirstSeenTs)
+	require.Equal(t, int64(500), account.LastActivityTs)
+
+	account = &Account{}
+	UpdateAccountActivity(account, previous, true /* connected */, 1000)
+	require.Equal(t, int64(100), account.FirstSeenTs)
+	require.Equal(t, int64(1000), account.LastActivityTs)
+}
+
+func TestSetAccountFlagList(t *testing.T) {
+	day := int64(24 * 60 * 60)
+	now := 100 * day
+	tests := []struct {
+		account *Account
+		want    []AccountFlag
+	}{
+		{
+			account: &Account{PasswordExpireTs: now + day, FirstSeenTs: now - day, LastActivityTs: now},
+			want:    nil,
+		},
+		{
+			account: &Account{Superuser: true, FirstSeenTs: now - day},
+			want:    []AccountFlag{AccountFlagSuperuser, AccountFlagNoPasswordExpiry},
+		},
+		{
+			// Never seen connected since first seen 30 days ago.
+			account: &Account{PasswordExpireTs: now + day, FirstSeenTs: now - 30*day},
+			want:    []AccountFlag{AccountFlagUnused},
+		},
+		{
+			account: &Account{PasswordExpireTs: now + day, FirstSeenTs: now - 90*day, LastActivityTs: now - 29*day},
+			want:    nil,
+		},
+	}
+	for _, test := range tests {
+		SetAccountFlagList(test.account, 30, now)
+		require.Equal(t, test.want, test.account.FlagList)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/vcs"
 )
 
@@ -21,6 +22,8 @@ const (
 	ActivityIssueFieldUpdate ActivityType = "bb.issue.field.update"
 	// ActivityIssueStatusUpdate is the type for updating issue status.
 	ActivityIssueStatusUpdate ActivityType = "bb.issue.status.update"
+	// ActivityIssueSLARemind is the type for reminding the assignee of an issue approaching the SLA deadline.
+	ActivityIssueSLARemind ActivityType = "bb.issue.sla.remind"
 	// ActivityPipelineTaskStatusUpdate is the type for updating pipeline task status.
 	ActivityPipelineTaskStatusUpdate ActivityType = "bb.pipeline.task.status.update"
 	// ActivityPipelineTaskFileCommit is the type for committing pipeline task file.
@@ -29,6 +32,8 @@ const (
 	ActivityPipelineTaskStatementUpdate ActivityType = "bb.pipeline.task.statement.update"
 	// ActivityPipelineTaskEarliestAllowedTimeUpdate is the type for updating pipeline task the earliest allowed time.
 	ActivityPipelineTaskEarliestAllowedTimeUpdate ActivityType = "bb.pipeline.task.general.earliest-allowed-time.update"
+	// ActivityPipelineChangeSetHalt is the type for halting a change set after a task fails.
+	ActivityPipelineChangeSetHalt ActivityType = "bb.pipeline.change-set.halt"
 
 	// Member related.
 
@@ -40,6 +45,8 @@ const (
 	ActivityMemberActivate ActivityType = "bb.member.activate"
 	// ActivityMemberDeactivate is the type for deactivating members.
 	ActivityMemberDeactivate ActivityType = "bb.member.deactivate"
+	// ActivityMemberErase is the type for erasing the personal data of departed members.
+	ActivityMemberErase ActivityType = "bb.member.erase"
 
 	// Project related.
 
@@ -53,16 +60,27 @@ const (
 	ActivityProjectMemberDelete ActivityType = "bb.project.member.delete"
 	// ActivityProjectMemberRoleUpdate is the type for updating project member roles.
 	ActivityProjectMemberRoleUpdate ActivityType = "bb.project.member.role.update"
+	// ActivityProjectDatabaseRename is the type for detecting a database renamed outside Bytebase.
+	ActivityProjectDatabaseRename ActivityType = "bb.project.database.rename"
 
 	// SQL Editor related.
 
 	// ActivitySQLEditorQuery is the type for executing query.
 	ActivitySQLEditorQuery ActivityType = "bb.sql-editor.query"
+	// ActivitySQLEditorMetadataQuery is the type for executing query against the Bytebase metadata database.
+	ActivitySQLEditorMetadataQuery ActivityType = "bb.sql-editor.metadata.query"
 
 	// Database related.
 
 	// ActivityDatabaseRecoveryPITRDone is the type for performing PITR on the database successfully.
 	ActivityDatabaseRecoveryPITRDone ActivityType = "bb.database.recovery.pitr.done"
+	// ActivityDatabaseSchemaChange is the type for completing a migration on the database.
+	ActivityDatabaseSchemaChange ActivityType = "bb.database.schema.change"
+
+	// Data source related.
+
+	// ActivityDataSourceCredentialRotate is the type for switching a data source to the rotated credential.
+	ActivityDataSourceCredentialRotate ActivityType = "bb.data-source.credential.rotate"
 )
 
 // ActivityLevel is the level of activities.
@@ -110,6 +128,15 @@ type ActivityIssueStatusUpdatePayload struct {
 	IssueName string `json:"issueName"`
 }
 
+// ActivityIssueSLARemindPayload is the API message payloads for reminding the issue SLA.
+type ActivityIssueSLARemindPayload struct {
+	Phase      IssueSLAPhase  `json:"phase"`
+	Status     IssueSLAStatus `json:"status"`
+	DeadlineTs int64          `json:"deadlineTs"`
+	// Used by inbox to display info without paying the join cost
+	IssueName string `json:"issueName"`
+}
+
 // ActivityPipelineTaskStatusUpdatePayload is the API message payloads for updating pipeline task status.
 type ActivityPipelineTaskStatusUpdatePayload struct {
 	TaskID    int        `json:"taskId"`
@@ -150,6 +177,14 @@ type ActivityPipelineTaskEarliestAllowedTimeUpdatePayload struct {
 	TaskName  string `json:"taskName"`
 }
 
+// ActivityPipelineChangeSetHaltPayload is the API message payloads for halting change sets.
+type ActivityPipelineChangeSetHaltPayload struct {
+	ChangeSetID      int                        `json:"changeSetId"`
+	CompensationPlan *ChangeSetCompensationPlan `json:"compensationPlan"`
+	// Used by activity table to display info without paying the join cost
+	IssueName string `json:"issueName"`
+}
+
 // ActivityMemberCreatePayload is the API message payloads for creating members.
 type ActivityMemberCreatePayload struct {
 	PrincipalID    int          `json:"principalId"`
@@ -176,6 +211,15 @@ type ActivityMemberActivateDeactivatePayload struct {
 	Role           Role   `json:"role"`
 }
 
+// ActivityMemberErasePayload is the API message payloads for erasing the personal data of departed members.
+// The payload doesn't contain the name and the email of the erased member.
+type ActivityMemberErasePayload struct {
+	PrincipalID     int   `json:"principalId"`
+	SuccessorID     int   `json:"successorId"`
+	ReassignedCount int64 `json:"reassignedCount"`
+	DeletedCount    int64 `json:"deletedCount"`
+}
+
 // ActivityProjectRepositoryPushPayload is the API message payloads for pushing repositories.
 type ActivityProjectRepositoryPushPayload struct {
 	VCSPushEvent vcs.PushEvent `json:"pushEvent"`
@@ -192,6 +236,15 @@ type ActivityProjectDatabaseTransferPayload struct {
 	DatabaseName string `json:"databaseName,omitempty"`
 }
 
+// ActivityProjectDatabaseRenamePayload is the API message payloads for detecting renamed databases.
+type ActivityProjectDatabaseRenamePayload struct {
+	DatabaseID int    `json:"databaseId"`
+	OldName    string `json:"oldName"`
+	NewName    string `json:"newName"`
+	// Used by activity table to display info without paying the join cost
+	InstanceName string `json:"instanceName"`
+}
+
 // ActivitySQLEditorQueryPayload is the API message payloads for the executed query info.
 type ActivitySQLEditorQueryPayload struct {
 	// Used by activity table to display info without paying the join cost
@@ -203,6 +256,37 @@ type ActivitySQLEditorQueryPayload struct {
 	AdviceList   []advisor.Advice `json:"adviceList"`
 }
 
+// ActivitySQLEditorMetadataQueryPayload is the API message payloads for the query executed against the Bytebase metadata database.
+type ActivitySQLEditorMetadataQueryPayload struct {
+	Statement  string `json:"statement"`
+	DurationNs int64  `json:"durationNs"`
+	RowCount   int    `json:"rowCount"`
+	Error      string `json:"error"`
+}
+
+// ActivityDataSourceCredentialRotatePayload is the API message payloads for rotating data source credentials.
+type ActivityDataSourceCredentialRotatePayload struct {
+	RotationID     int            `json:"rotationId"`
+	DataSourceID   int            `json:"dataSourceId"`
+	DataSourceType DataSourceType `json:"dataSourceType"`
+	OldUsername    string         `json:"oldUsername"`
+	NewUsername    string         `json:"newUsername"`
+	// Used by activity table to display info without paying the join cost
+	InstanceName string `json:"instanceName"`
+}
+
+// ActivityDatabaseSchemaChangePayload is the API message payloads for completing migrations on databases.
+// The statement and the schema diff are not saved, they are in the migration history and the published SchemaChangeEvent.
+type ActivityDatabaseSchemaChangePayload struct {
+	TaskID             int              `json:"taskId"`
+	DatabaseID         int              `json:"databaseId"`
+	MigrationHistoryID int64            `json:"migrationHistoryId"`
+	MigrationType      db.MigrationType `json:"migrationType"`
+	Version            string           `json:"version"`
+	// Used by activity table to display info without paying the join cost
+	DatabaseName string `json:"databaseName"`
+}
+
 // Activity is the API message for an activity.
 type Activity struct {
 	ID int `jsonapi:"primary,activity"`
@@ -7,6 +7,7 @@ type ServerInfo struct {
 	GitCommit      string `json:"gitCommit"`
 	Readonly       bool   `json:"readonly"`
 	Demo           bool   `json:"demo"`
+	Offline        bool   `json:"offline"`
 	DemoName       string `json:"demoName"`
 	Host           string `json:"host"`
 	Port           string `json:"port"`
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// AgentTokenHeader is the HTTP header carrying the agent token.
+const AgentTokenHeader = "X-Bytebase-Agent-Token"
+
+// Agent is the API message for a runner agent.
+// A runner agent is deployed inside a private network and executes the work on behalf of the
+// Bytebase server against the instances the server cannot reach directly.
+type Agent struct {
+	ID int `jsonapi:"primary,agent"`
+
+	// Standard fields
+	RowStatus RowStatus `jsonapi:"attr,rowStatus"`
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+	// Token is only returned to the client upon creation, afterwards only its hash is kept.
+	Token           string `jsonapi:"attr,token,omitempty"`
+	TokenHash       string
+	Version         string `jsonapi:"attr,version"`
+	LastHeartbeatTs int64  `jsonapi:"attr,lastHeartbeatTs"`
+}
+
+// AgentCreate is the API message for creating an agent.
+type AgentCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+	// Token is generated by the server.
+	Token string
+}
+
+// AgentFind is the API message for finding agents.
+type AgentFind struct {
+	ID *int
+
+	// Standard fields
+	RowStatus *RowStatus
+
+	// Domain specific fields
+	Name *string
+	// Token finds the agent by the hash of the token.
+	Token *string
+}
+
+func (find *AgentFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// AgentPatch is the API message for patching an agent.
+type AgentPatch struct {
+	ID int `jsonapi:"primary,agentPatch"`
+
+	// Standard fields
+	RowStatus *string `jsonapi:"attr,rowStatus"`
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name            *string `jsonapi:"attr,name"`
+	Version         *string
+	LastHeartbeatTs *int64
+}
+
+// AgentTaskType is the type of the work executed by an agent.
+type AgentTaskType string
+
+const (
+	// AgentTaskInstanceSync is the agent task type for syncing the instance and database schemas.
+	AgentTaskInstanceSync AgentTaskType = "bb.agent-task.instance.sync"
+	// AgentTaskDatabaseMigrate is the agent task type for executing a migration.
+	AgentTaskDatabaseMigrate AgentTaskType = "bb.agent-task.database.migrate"
+	// AgentTaskDatabaseQuery is the agent task type for executing a read-only query.
+	AgentTaskDatabaseQuery AgentTaskType = "bb.agent-task.database.query"
+)
+
+// AgentTaskStatus is the status of an agent task.
+type AgentTaskStatus string
+
+const (
+	// AgentTaskPending is the agent task status for PENDING.
+	AgentTaskPending AgentTaskStatus = "PENDING"
+	// AgentTaskRunning is the agent task status for RUNNING.
+	AgentTaskRunning AgentTaskStatus = "RUNNING"
+	// AgentTaskDone is the agent task status for DONE.
+	AgentTaskDone AgentTaskStatus = "DONE"
+	// AgentTaskFailed is the agent task status for FAILED.
+	AgentTaskFailed AgentTaskStatus = "FAILED"
+)
+
+// AgentTaskPayload is the payload of an agent task.
+type AgentTaskPayload struct {
+	// Database is the database name, empty for instance level tasks.
+	Database      string            `json:"database,omitempty"`
+	Statement     string            `json:"statement,omitempty"`
+	Limit         int               `json:"limit,omitempty"`
+	MigrationInfo *db.MigrationInfo `json:"migrationInfo,omitempty"`
+	// SessionSetting is the session settings resolved by the session setting policy, which the agent applies before the migration.
+	SessionSetting *db.SessionSetting `json:"sessionSetting,omitempty"`
+}
+
+// AgentTaskSyncResult is the result of an AgentTaskInstanceSync task.
+type AgentTaskSyncResult struct {
+	InstanceMeta *db.InstanceMeta `json:"instanceMeta"`
+	SchemaList   []*db.Schema     `json:"schemaList"`
+	// SchemaVersionMap maps the database name to its latest schema version.
+	SchemaVersionMap map[string]string `json:"schemaVersionMap"`
+	// ErrorList contains the databases failed to sync, the other databases are still synced.
+	ErrorList []string `json:"errorList"`
+}
+
+// AgentTaskMigrateResult is the result of an AgentTaskDatabaseMigrate task.
+type AgentTaskMigrateResult struct {
+	MigrationID int64  `json:"migrationId"`
+	Schema      string `json:"schema"`
+}
+
+// AgentTaskQueryResult is the result of an AgentTaskDatabaseQuery task.
+type AgentTaskQueryResult struct {
+	RowSet []interface{} `json:"rowSet"`
+}
+
+// AgentTaskResult is the result reported by the agent.
+type AgentTaskResult struct {
+	Error   string                  `json:"error,omitempty"`
+	Sync    *AgentTaskSyncResult    `json:"sync,omitempty"`
+	Migrate *AgentTaskMigrateResult `json:"migrate,omitempty"`
+	Query   *AgentTaskQueryResult   `json:"query,omitempty"`
+}
+
+// AgentTask is the API message for a unit of work dispatched to an agent.
+type AgentTask struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	AgentID    int
+	InstanceID int
+
+	// Domain specific fields
+	Type   AgentTaskType
+	Status AgentTaskStatus
+	// Payload is the JSON encoded AgentTaskPayload.
+	Payload string
+	// Result is the JSON encoded AgentTaskResult.
+	Result string
+}
+
+// AgentTaskCreate is the API message for creating an agent task.
+type AgentTaskCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	AgentID    int
+	InstanceID int
+
+	// Domain specific fields
+	Type    AgentTaskType
+	Payload string
+}
+
+// AgentTaskFind is the API message for finding agent tasks.
+type AgentTaskFind struct {
+	ID *int
+
+	// Related fields
+	AgentID    *int
+	InstanceID *int
+
+	// Domain specific fields
+	Type       *AgentTaskType
+	StatusList *[]AgentTaskStatus
+}
+
+func (find *AgentTaskFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// AgentTaskStatusPatch is the API message for patching the status of an agent task.
+type AgentTaskStatusPatch struct {
+	ID int
+
+	// Standard fields
+	UpdaterID int
+
+	// Domain specific fields
+	Status AgentTaskStatus
+	Result *string
+}
+
+// AgentTaskDispatch is the API message sent to the agent when it claims a task.
+// The connection is composed at dispatch time so the credentials are never persisted in the task payload.
+type AgentTaskDispatch struct {
+	ID               int                  `json:"id"`
+	Type             AgentTaskType        `json:"type"`
+	Engine           db.Type              `json:"engine"`
+	ConnectionConfig db.ConnectionConfig  `json:"connectionConfig"`
+	ConnectionCtx    db.ConnectionContext `json:"connectionContext"`
+	Payload          AgentTaskPayload     `json:"payload"`
+}
+
+// AgentHeartbeat is the API message sent by the agent periodically.
+type AgentHeartbeat struct {
+	Version string `json:"version"`
+}
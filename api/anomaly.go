@@ -12,6 +12,12 @@ const (
 	AnomalyInstanceConnection AnomalyType = "bb.anomaly.instance.connection"
 	// AnomalyInstanceMigrationSchema is the anomaly type for schema migrations.
 	AnomalyInstanceMigrationSchema AnomalyType = "bb.anomaly.instance.migration-schema"
+	// AnomalyInstanceParameterDrift is the anomaly type for instance parameters drifting from the environment baseline.
+	AnomalyInstanceParameterDrift AnomalyType = "bb.anomaly.instance.parameter-drift"
+	// AnomalyInstanceCertificateExpiry is the anomaly type for instance TLS certificates expired or about to expire.
+	AnomalyInstanceCertificateExpiry AnomalyType = "bb.anomaly.instance.certificate-expiry"
+	// AnomalyInstanceVersionAdvisory is the anomaly type for instances running the EOL or vulnerable engine versions.
+	AnomalyInstanceVersionAdvisory AnomalyType = "bb.anomaly.instance.version-advisory"
 	// AnomalyDatabaseBackupPolicyViolation is the anomaly type for backup policy violations.
 	AnomalyDatabaseBackupPolicyViolation AnomalyType = "bb.anomaly.database.backup.policy-violation"
 	// AnomalyDatabaseBackupMissing is the anomaly type for missing backups.
@@ -20,6 +26,8 @@ const (
 	AnomalyDatabaseConnection AnomalyType = "bb.anomaly.database.connection"
 	// AnomalyDatabaseSchemaDrift is the anomaly type for database schema drifts.
 	AnomalyDatabaseSchemaDrift AnomalyType = "bb.anomaly.database.schema.drift"
+	// AnomalyDatabaseInvalidObject is the anomaly type for invalid indexes and NOT VALID constraints left by migrations.
+	AnomalyDatabaseInvalidObject AnomalyType = "bb.anomaly.database.invalid-object"
 )
 
 // AnomalySeverity is the severity of anomaly.
@@ -37,9 +45,9 @@ const (
 // AnomalySeverityFromType maps the severity from a anomaly type.
 func AnomalySeverityFromType(anomalyType AnomalyType) AnomalySeverity {
 	switch anomalyType {
-	case AnomalyDatabaseBackupPolicyViolation:
+	case AnomalyDatabaseBackupPolicyViolation, AnomalyInstanceParameterDrift:
 		return AnomalySeverityMedium
-	case AnomalyDatabaseBackupMissing:
+	case AnomalyDatabaseBackupMissing, AnomalyInstanceCertificateExpiry, AnomalyInstanceVersionAdvisory, AnomalyDatabaseInvalidObject:
 		return AnomalySeverityHigh
 	case AnomalyInstanceConnection:
 	case AnomalyInstanceMigrationSchema:
@@ -56,6 +64,26 @@ type AnomalyInstanceConnectionPayload struct {
 	Detail string `json:"detail,omitempty"`
 }
 
+// AnomalyInstanceParameterDriftPayload is the API message for instance parameter drift payloads.
+type AnomalyInstanceParameterDriftPayload struct {
+	EnvironmentID int               `json:"environmentId,omitempty"`
+	DriftList     []*ParameterDrift `json:"driftList,omitempty"`
+}
+
+// AnomalyInstanceCertificateExpiryPayload is the API message for instance certificate expiry payloads.
+type AnomalyInstanceCertificateExpiryPayload struct {
+	Subject  string `json:"subject,omitempty"`
+	Issuer   string `json:"issuer,omitempty"`
+	ExpireTs int64  `json:"expireTs,omitempty"`
+}
+
+// AnomalyInstanceVersionAdvisoryPayload is the API message for instance version advisory payloads.
+type AnomalyInstanceVersionAdvisoryPayload struct {
+	Status             VersionAdvisoryStatus `json:"status,omitempty"`
+	RecommendedVersion string                `json:"recommendedVersion,omitempty"`
+	Detail             string                `json:"detail,omitempty"`
+}
+
 // AnomalyDatabaseBackupPolicyViolationPayload is the API message for backup policy violation payloads.
 type AnomalyDatabaseBackupPolicyViolationPayload struct {
 	EnvironmentID          int                      `json:"environmentId,omitempty"`
@@ -86,6 +114,31 @@ type AnomalyDatabaseSchemaDriftPayload struct {
 	Actual string `json:"actual,omitempty"`
 }
 
+// InvalidObjectType is the type of an invalid database object.
+type InvalidObjectType string
+
+const (
+	// InvalidObjectIndex is the index left INVALID, e.g. by a failed CREATE INDEX CONCURRENTLY.
+	InvalidObjectIndex InvalidObjectType = "INDEX"
+	// InvalidObjectConstraint is the constraint added NOT VALID but not validated yet.
+	InvalidObjectConstraint InvalidObjectType = "CONSTRAINT"
+)
+
+// InvalidObject is the API message for an invalid database object, which isn't enforced or used by the database.
+type InvalidObject struct {
+	Type InvalidObjectType `json:"type"`
+	// Table is in the form of "schema.table".
+	Table string `json:"table"`
+	Name  string `json:"name"`
+	// Definition is the definition of the index or the constraint.
+	Definition string `json:"definition"`
+}
+
+// AnomalyDatabaseInvalidObjectPayload is the API message for invalid object payloads.
+type AnomalyDatabaseInvalidObjectPayload struct {
+	InvalidObjectList []*InvalidObject `json:"invalidObjectList,omitempty"`
+}
+
 // Anomaly is the API message for an anomaly.
 type Anomaly struct {
 	ID int `jsonapi:"primary,anomaly"`
@@ -10,6 +10,7 @@ package api
 import (
 	// dependency gate.
 	_ "github.com/bytebase/bytebase/plugin/advisor"
+	_ "github.com/bytebase/bytebase/plugin/advisor/external"
 	_ "github.com/bytebase/bytebase/plugin/db"
 	_ "github.com/bytebase/bytebase/plugin/metric"
 	_ "github.com/bytebase/bytebase/plugin/parser"
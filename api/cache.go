@@ -26,4 +26,5 @@ const (
 type CacheService interface {
 	FindCache(namespace CacheNamespace, id int, entry interface{}) (bool, error)
 	UpsertCache(namespace CacheNamespace, id int, entry interface{}) error
+	DeleteCache(namespace CacheNamespace, id int)
 }
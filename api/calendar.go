@@ -0,0 +1,47 @@
+package api
+
+// ChangeCalendarEventType is the type of an event in the change calendar.
+type ChangeCalendarEventType string
+
+const (
+	// ChangeCalendarEventScheduled is the event of a task waiting for its earliest allowed time.
+	ChangeCalendarEventScheduled ChangeCalendarEventType = "SCHEDULED"
+	// ChangeCalendarEventExecuted is the event of a task run.
+	ChangeCalendarEventExecuted ChangeCalendarEventType = "EXECUTED"
+)
+
+// ChangeCalendar is the API message for the scheduled and the executed changes in a time range grouped by environment.
+// It's returned in plain JSON since the nested event lists can't be represented by JSON:API attributes well.
+type ChangeCalendar struct {
+	StartTs int64 `json:"startTs"`
+	EndTs   int64 `json:"endTs"`
+	// EnvironmentList is in the same order as the environment list.
+	EnvironmentList []*ChangeCalendarEnvironment `json:"environmentList"`
+}
+
+// ChangeCalendarEnvironment is the API message for the change calendar of an environment.
+type ChangeCalendarEnvironment struct {
+	EnvironmentID   int    `json:"environmentId"`
+	EnvironmentName string `json:"environmentName"`
+	// EventList is in the ascending order of the start time.
+	EventList []*ChangeCalendarEvent `json:"eventList"`
+}
+
+// ChangeCalendarEvent is the API message for an event in the change calendar.
+type ChangeCalendarEvent struct {
+	Type ChangeCalendarEventType `json:"type"`
+	// StartTs is the earliest allowed time of the scheduled task, or the start time of the task run.
+	StartTs int64 `json:"startTs"`
+	// EndTs is the end time of the finished task run, 0 otherwise.
+	EndTs int64 `json:"endTs"`
+	// Status is the task status of the scheduled task, or the task run status of the task run.
+	Status       string   `json:"status"`
+	TaskID       int      `json:"taskId"`
+	TaskName     string   `json:"taskName"`
+	TaskType     TaskType `json:"taskType"`
+	IssueID      int      `json:"issueId"`
+	IssueName    string   `json:"issueName"`
+	ProjectID    int      `json:"projectId"`
+	InstanceName string   `json:"instanceName"`
+	DatabaseName string   `json:"databaseName"`
+}
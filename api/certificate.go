@@ -0,0 +1,27 @@
+package api
+
+import (
+	"fmt"
+)
+
+// DefaultCertificateExpiryAlertDays is the default number of days before the certificate expiry to raise the anomaly.
+const DefaultCertificateExpiryAlertDays = 30
+
+// CertificateExpirySetting is the value of the workspace certificate expiry setting.
+type CertificateExpirySetting struct {
+	// AlertDays is the number of days before the expiry of the instance certificates to raise the anomaly.
+	AlertDays int `json:"alertDays"`
+}
+
+// Validate validates the certificate expiry setting.
+func (s *CertificateExpirySetting) Validate() error {
+	if s.AlertDays <= 0 {
+		return fmt.Errorf("alert days must be positive, got %d", s.AlertDays)
+	}
+	return nil
+}
+
+// IsExpiring returns true if the certificate expiring at expireTs is expired or within the alert days at now.
+func (s *CertificateExpirySetting) IsExpiring(expireTs int64, now int64) bool {
+	return expireTs-now < int64(s.AlertDays)*24*3600
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateExpirySetting(t *testing.T) {
+	setting := &CertificateExpirySetting{AlertDays: 30}
+	require.NoError(t, setting.Validate())
+	require.Error(t, (&CertificateExpirySetting{}).Validate())
+
+	now := int64(1650000000)
+	day := int64(24 * 3600)
+	require.False(t, setting.IsExpiring(now+31*day, now))
+	require.True(t, setting.IsExpiring(now+29*day, now))
+	require.True(t, setting.IsExpiring(now-day, now))
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// ChangeSetStatus is the status of a change set.
+type ChangeSetStatus string
+
+const (
+	// ChangeSetVerifying is the status before all tasks of the change set pass the task checks, no task is executed yet.
+	ChangeSetVerifying ChangeSetStatus = "VERIFYING"
+	// ChangeSetRunning is the status when all tasks of the change set have passed the task checks and the tasks are being executed.
+	ChangeSetRunning ChangeSetStatus = "RUNNING"
+	// ChangeSetHalted is the status when a task of the change set fails after some tasks have been executed.
+	ChangeSetHalted ChangeSetStatus = "HALTED"
+	// ChangeSetDone is the status when all tasks of the change set are done.
+	ChangeSetDone ChangeSetStatus = "DONE"
+)
+
+// ChangeSet is the API message for a change set.
+// A change set is a pipeline whose tasks must be applied to several databases together, e.g. the schemas of two services.
+// None of the tasks executes until all tasks pass the task checks, and the pipeline halts with a compensation plan
+// if a task fails after some others have been executed.
+type ChangeSet struct {
+	ID int `jsonapi:"primary,changeSet"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	PipelineID int `jsonapi:"attr,pipelineId"`
+
+	// Domain specific fields
+	Status  ChangeSetStatus `jsonapi:"attr,status"`
+	Payload string          `jsonapi:"attr,payload"`
+}
+
+// ChangeSetPayload is the payload of a change set.
+type ChangeSetPayload struct {
+	// CompensationPlan is recorded when the change set halts.
+	CompensationPlan *ChangeSetCompensationPlan `json:"compensationPlan,omitempty"`
+}
+
+// ChangeSetCompensationPlan is the plan to compensate the executed tasks of a halted change set.
+type ChangeSetCompensationPlan struct {
+	FailedTaskID       int    `json:"failedTaskId"`
+	FailedTaskName     string `json:"failedTaskName"`
+	FailedDatabaseName string `json:"failedDatabaseName"`
+	// StepList is in the reverse order of the task execution.
+	StepList []*ChangeSetCompensationStep `json:"stepList"`
+}
+
+// ChangeSetCompensationStep is the step to compensate an executed task of a halted change set.
+type ChangeSetCompensationStep struct {
+	TaskID          int    `json:"taskId"`
+	DatabaseID      int    `json:"databaseId"`
+	DatabaseName    string `json:"databaseName"`
+	EnvironmentName string `json:"environmentName"`
+	// Statement is the down statement of the task, it's empty if the task must be compensated manually.
+	Statement string `json:"statement"`
+}
+
+// ChangeSetCreate is the API message for creating a change set.
+type ChangeSetCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	PipelineID int
+}
+
+// ChangeSetFind is the API message for finding change sets.
+type ChangeSetFind struct {
+	ID *int
+
+	// Related fields
+	PipelineID *int
+}
+
+func (find *ChangeSetFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// ChangeSetPatch is the API message for patching a change set.
+type ChangeSetPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Status  *ChangeSetStatus
+	Payload *string
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// SchemaDiffAction is the action of a schema object in the schema diff.
+type SchemaDiffAction string
+
+const (
+	// SchemaDiffActionAdd means the object is added.
+	SchemaDiffActionAdd SchemaDiffAction = "ADD"
+	// SchemaDiffActionDrop means the object is dropped.
+	SchemaDiffActionDrop SchemaDiffAction = "DROP"
+	// SchemaDiffActionModify means the definition of the object is changed.
+	SchemaDiffActionModify SchemaDiffAction = "MODIFY"
+)
+
+// Changelog is the API message for the changelog of a database.
+// It's returned in plain JSON since the nested diff can't be represented by JSON:API attributes well.
+type Changelog struct {
+	DatabaseID   int    `json:"databaseId"`
+	DatabaseName string `json:"databaseName"`
+	// EntryList is in the descending order of the creation time, the same as the migration history.
+	EntryList []*ChangelogEntry `json:"entryList"`
+}
+
+// ChangelogEntry is the API message for a migration history enriched with the schema diff.
+type ChangelogEntry struct {
+	MigrationHistoryID  int                `json:"migrationHistoryId"`
+	Creator             string             `json:"creator"`
+	CreatedTs           int64              `json:"createdTs"`
+	Source              db.MigrationSource `json:"source"`
+	Type                db.MigrationType   `json:"type"`
+	Status              db.MigrationStatus `json:"status"`
+	Version             string             `json:"version"`
+	Description         string             `json:"description"`
+	Statement           string             `json:"statement"`
+	ExecutionDurationNs int64              `json:"executionDurationNs"`
+	IssueID             string             `json:"issueId"`
+	// DownStatement is the statement reverting the change, it's empty if the change can't be reverted.
+	DownStatement string `json:"downStatement"`
+	// SchemaBefore and SchemaAfter are the schema snapshots before and after the change.
+	SchemaBefore string `json:"schemaBefore"`
+	SchemaAfter  string `json:"schemaAfter"`
+	// Diff is nil if the change isn't done, since there is no schema snapshot after the change.
+	Diff *SchemaDiff `json:"diff"`
+}
+
+// SchemaDiff is the API message for the structural diff between two schema snapshots.
+type SchemaDiff struct {
+	ObjectList []*SchemaObjectDiff `json:"objectList"`
+}
+
+// SchemaObjectDiff is the API message for the diff of a top level schema object such as a table, an index or a view.
+type SchemaObjectDiff struct {
+	// Type is the object type such as "TABLE", "INDEX", "VIEW" and "CONSTRAINT".
+	// It's "STATEMENT" for the statements not recognized as objects, and the name is the statement itself.
+	Type   string           `json:"type"`
+	Name   string           `json:"name"`
+	Action SchemaDiffAction `json:"action"`
+	// Before and After are the definition statements, empty if the object doesn't exist.
+	Before string `json:"before"`
+	After  string `json:"after"`
+	// ColumnList is only set for the modified tables.
+	ColumnList []*SchemaColumnDiff `json:"columnList"`
+	// ParentName is the partitioned table or index of the partition, so the partitions are shown under it.
+	// The changes of the partitions inherited from the parent are omitted.
+	ParentName string `json:"parentName,omitempty"`
+}
+
+// SchemaColumnDiff is the API message for the diff of a column in the table.
+type SchemaColumnDiff struct {
+	Name   string           `json:"name"`
+	Action SchemaDiffAction `json:"action"`
+	// Before and After are the column definitions without the column name.
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ChangelogRevertIssueCreate is the API message for creating an issue to revert a change
+// by applying the down statement recorded in the migration history.
+type ChangelogRevertIssueCreate struct {
+	AssigneeID int `jsonapi:"attr,assigneeId"`
+}
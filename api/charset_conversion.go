@@ -0,0 +1,139 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// CharsetConversionMethod is the method converting the character set of a table.
+type CharsetConversionMethod string
+
+const (
+	// CharsetConversionAlter converts the table with ALTER TABLE, which blocks the writes to the table until it's done.
+	CharsetConversionAlter CharsetConversionMethod = "ALTER"
+	// CharsetConversionGhost converts the table with gh-ost, which copies the rows to a ghost table without blocking the writes.
+	CharsetConversionGhost CharsetConversionMethod = "GHOST"
+
+	// CharsetConversionGhostThreshold is the table data size in bytes from which the table is converted with gh-ost.
+	CharsetConversionGhostThreshold = 1 << 30
+
+	// The throughputs are rough estimates, the actual ones depend on the hardware, the row size and the workload.
+	charsetConversionAlterBytesPerSecond = 32 << 20
+	charsetConversionGhostRowsPerSecond  = 10000
+)
+
+// CharsetConversionTablePlan is the API message for the conversion plan of a table.
+type CharsetConversionTablePlan struct {
+	Name string `json:"name"`
+	// Collation is the table collation before the conversion.
+	Collation string                  `json:"collation"`
+	RowCount  int64                   `json:"rowCount"`
+	DataSize  int64                   `json:"dataSize"`
+	Method    CharsetConversionMethod `json:"method"`
+	// EstimatedDurationSeconds is the estimated conversion duration based on the synced table statistics.
+	EstimatedDurationSeconds int64 `json:"estimatedDurationSeconds"`
+}
+
+// CharsetConversionPlan is the API message for the plan converting the character set of a MySQL database.
+type CharsetConversionPlan struct {
+	DatabaseName string `json:"databaseName"`
+	// CharacterSet and Collation are the target character set and collation.
+	CharacterSet string                        `json:"characterSet"`
+	Collation    string                        `json:"collation"`
+	TableList    []*CharsetConversionTablePlan `json:"tableList"`
+	// EstimatedDurationSeconds is the sum of the estimated table conversion durations.
+	EstimatedDurationSeconds int64 `json:"estimatedDurationSeconds"`
+}
+
+// PlanCharsetConversion plans converting the tables of the database to the character set and collation.
+// If tableNameList is empty, all the tables not in the character set are converted.
+// The tableList is the synced tables of the database.
+func PlanCharsetConversion(databaseName, characterSet, collation string, tableList []*Table, tableNameList []string) (*CharsetConversionPlan, error) {
+	if characterSet == "" {
+		characterSet = DefaultCharactorSetName
+	}
+	if collation == "" && characterSet == DefaultCharactorSetName {
+		collation = DefaultCollationName
+	}
+	if characterSet != DefaultCharactorSetName {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("only converting to %q is supported, got %q", DefaultCharactorSetName, characterSet)}
+	}
+	if !strings.HasPrefix(collation, characterSet+"_") {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("collation %q doesn't belong to character set %q", collation, characterSet)}
+	}
+
+	plan := &CharsetConversionPlan{
+		DatabaseName: databaseName,
+		CharacterSet: characterSet,
+		Collation:    collation,
+	}
+	add := func(table *Table) {
+		tablePlan := &CharsetConversionTablePlan{
+			Name:      table.Name,
+			Collation: table.Collation,
+			RowCount:  table.RowCount,
+			DataSize:  table.DataSize,
+			Method:    CharsetConversionAlter,
+		}
+		if table.DataSize >= CharsetConversionGhostThreshold {
+			tablePlan.Method = CharsetConversionGhost
+			tablePlan.EstimatedDurationSeconds = table.RowCount/charsetConversionGhostRowsPerSecond + 1
+		} else {
+			tablePlan.EstimatedDurationSeconds = table.DataSize/charsetConversionAlterBytesPerSecond + 1
+		}
+		plan.TableList = append(plan.TableList, tablePlan)
+		plan.EstimatedDurationSeconds += tablePlan.EstimatedDurationSeconds
+	}
+
+	tableMap := make(map[string]*Table)
+	for _, table := range tableList {
+		// The views have no data to convert.
+		if table.Type == "VIEW" {
+			continue
+		}
+		tableMap[table.Name] = table
+	}
+	if len(tableNameList) > 0 {
+		for _, name := range tableNameList {
+			table, ok := tableMap[name]
+			if !ok {
+				return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("table %q not found in database %q", name, databaseName)}
+			}
+			add(table)
+		}
+		return plan, nil
+	}
+	for _, table := range tableList {
+		if _, ok := tableMap[table.Name]; !ok {
+			continue
+		}
+		// The collation name starts with the character set name, e.g. utf8_general_ci.
+		if strings.HasPrefix(table.Collation, characterSet+"_") {
+			continue
+		}
+		add(table)
+	}
+	return plan, nil
+}
+
+// GetTableStatement returns the statement converting the table.
+func (plan *CharsetConversionPlan) GetTableStatement(table *CharsetConversionTablePlan) string {
+	return fmt.Sprintf("ALTER TABLE `%s` CONVERT TO CHARACTER SET %s COLLATE %s;", table.Name, plan.CharacterSet, plan.Collation)
+}
+
+// GetDatabaseStatement returns the statement changing the database default, so that the new tables use the character set.
+func (plan *CharsetConversionPlan) GetDatabaseStatement() string {
+	return fmt.Sprintf("ALTER DATABASE `%s` CHARACTER SET %s COLLATE %s;", plan.DatabaseName, plan.CharacterSet, plan.Collation)
+}
+
+// GetStatement returns all the statements of the plan, it's recorded in the migration history.
+func (plan *CharsetConversionPlan) GetStatement() string {
+	var statementList []string
+	for _, table := range plan.TableList {
+		statementList = append(statementList, plan.GetTableStatement(table))
+	}
+	statementList = append(statementList, plan.GetDatabaseStatement())
+	return strings.Join(statementList, "\n")
+}
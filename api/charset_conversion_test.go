@@ -0,0 +1,47 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestPlanCharsetConversion(t *testing.T) {
+	tableList := []*Table{
+		{Name: "t1", Type: "BASE TABLE", Collation: "utf8_general_ci", RowCount: 100, DataSize: 16 << 20},
+		{Name: "t2", Type: "BASE TABLE", Collation: "utf8mb4_general_ci", RowCount: 100, DataSize: 16 << 20},
+		{Name: "t3", Type: "BASE TABLE", Collation: "utf8_bin", RowCount: 50000, DataSize: 2 << 30},
+		{Name: "v1", Type: "VIEW"},
+	}
+
+	plan, err := PlanCharsetConversion("db", "", "", tableList, nil)
+	require.NoError(t, err)
+	require.Equal(t, &CharsetConversionPlan{
+		DatabaseName: "db",
+		CharacterSet: "utf8mb4",
+		Collation:    "utf8mb4_general_ci",
+		TableList: []*CharsetConversionTablePlan{
+			{Name: "t1", Collation: "utf8_general_ci", RowCount: 100, DataSize: 16 << 20, Method: CharsetConversionAlter, EstimatedDurationSeconds: 1},
+			{Name: "t3", Collation: "utf8_bin", RowCount: 50000, DataSize: 2 << 30, Method: CharsetConversionGhost, EstimatedDurationSeconds: 6},
+		},
+		EstimatedDurationSeconds: 7,
+	}, plan)
+	require.Equal(t, "ALTER TABLE `t1` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;\n"+
+		"ALTER TABLE `t3` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;\n"+
+		"ALTER DATABASE `db` CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;", plan.GetStatement())
+
+	plan, err = PlanCharsetConversion("db", "utf8mb4", "utf8mb4_unicode_ci", tableList, []string{"t2"})
+	require.NoError(t, err)
+	require.Len(t, plan.TableList, 1)
+	require.Equal(t, "t2", plan.TableList[0].Name)
+	require.Equal(t, "ALTER TABLE `t2` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;", plan.GetTableStatement(plan.TableList[0]))
+
+	_, err = PlanCharsetConversion("db", "", "", tableList, []string{"v1"})
+	require.Equal(t, common.NotFound, common.ErrorCode(err))
+	_, err = PlanCharsetConversion("db", "latin1", "", tableList, nil)
+	require.Equal(t, common.Invalid, common.ErrorCode(err))
+	_, err = PlanCharsetConversion("db", "utf8mb4", "utf8_bin", tableList, nil)
+	require.Equal(t, common.Invalid, common.ErrorCode(err))
+}
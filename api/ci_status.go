@@ -0,0 +1,63 @@
+package api
+
+// CITokenHeader is the HTTP header carrying the token authenticating the CI status API.
+const CITokenHeader = "X-Bytebase-CI-Token"
+
+// CIStatus is the consolidated Bytebase verdict for a migration file, consumed by external CI pipelines.
+type CIStatus string
+
+const (
+	// CIStatusPending means Bytebase hasn't reached a verdict yet, external pipelines should keep waiting.
+	CIStatusPending CIStatus = "PENDING"
+	// CIStatusSuccess means all Bytebase checks have passed.
+	CIStatusSuccess CIStatus = "SUCCESS"
+	// CIStatusFailure means at least one Bytebase check has failed.
+	CIStatusFailure CIStatus = "FAILURE"
+)
+
+func (s CIStatus) level() int {
+	switch s {
+	case CIStatusSuccess:
+		return 2
+	case CIStatusPending:
+		return 1
+	case CIStatusFailure:
+		return 0
+	}
+	return -1
+}
+
+// Merge returns the worse of the two statuses.
+// FAILURE is worse than PENDING, which is worse than SUCCESS.
+func (s CIStatus) Merge(r CIStatus) CIStatus {
+	if r.level() < s.level() {
+		return r
+	}
+	return s
+}
+
+// CITaskStatus is the API message for the CI status of a single task.
+type CITaskStatus struct {
+	IssueID     int        `json:"issueId"`
+	TaskID      int        `json:"taskId"`
+	TaskName    string     `json:"taskName"`
+	TaskStatus  TaskStatus `json:"taskStatus"`
+	Environment string     `json:"environment"`
+	Database    string     `json:"database"`
+	Status      CIStatus   `json:"status"`
+	// DetailList explains why the task is not SUCCESS.
+	DetailList []string `json:"detailList"`
+}
+
+// CIToken is the API message for the generated CI token, which is only returned once.
+type CIToken struct {
+	Token string `json:"token"`
+}
+
+// CIStatusResponse is the API message for the consolidated CI status of a commit.
+type CIStatusResponse struct {
+	CommitID string          `json:"commitId"`
+	FilePath string          `json:"filePath,omitempty"`
+	Status   CIStatus        `json:"status"`
+	TaskList []*CITaskStatus `json:"taskList"`
+}
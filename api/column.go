@@ -27,6 +27,8 @@ type Column struct {
 	CharacterSet string  `json:"characterSet"`
 	Collation    string  `json:"collation"`
 	Comment      string  `json:"comment"`
+	// Description is the logical description if present, otherwise the comment.
+	Description string `json:"description"`
 }
 
 // ColumnCreate is the API message for creating a column.
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// DefaultColumnAddBatchSize is the default number of rows backfilled in a batch.
+const DefaultColumnAddBatchSize = 1000
+
+var sqlNumericLiteralRegexp = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// PlanColumnAdd plans adding a NOT NULL column with a default value to a large table without locking it for long:
+//  1. add the nullable column.
+//  2. set the default value, so the new rows get it while backfilling.
+//  3. backfill the existing rows in batches.
+//  4. enforce NOT NULL, e.g. by adding a NOT VALID check constraint and validating it separately for PostgreSQL.
+//
+// The engine version decides the cheapest statement of each step, it's the synced version of the instance.
+// The default value is a SQL expression, e.g. 0 or 'active'.
+func PlanColumnAdd(dbType db.Type, engineVersion string, tableName, columnName, columnType, defaultValue string, batchSize int) ([]*ColumnChangeStep, error) {
+	if columnName == "" {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("column name must not be empty")}
+	}
+	if columnType == "" {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("column type must not be empty")}
+	}
+	if strings.TrimSpace(defaultValue) == "" {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("default value must not be empty, it's used to backfill the existing rows")}
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultColumnAddBatchSize
+	}
+
+	switch dbType {
+	case db.MySQL, db.MariaDB:
+		return planMySQLColumnAdd(dbType, parseEngineVersion(engineVersion), tableName, columnName, columnType, defaultValue, batchSize), nil
+	case db.Postgres:
+		return planPostgresColumnAdd(parseEngineVersion(engineVersion), tableName, columnName, columnType, defaultValue, batchSize), nil
+	}
+	return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("adding NOT NULL column is not supported for %s", dbType)}
+}
+
+func planMySQLColumnAdd(dbType db.Type, version []int, tableName, columnName, columnType, defaultValue string, batchSize int) []*ColumnChangeStep {
+	quote := func(s string) string {
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "`", "``"))
+	}
+	table, column := quote(tableName), quote(columnName)
+
+	// MySQL 8.0.12 adds the column instantly, while MySQL 5.6 adds it online with rebuilding the table.
+	// MySQL 8.0.13 accepts expressions as the default value only if they're parenthesized.
+	instantVersion, onlineVersion, expressionDefaultVersion := []int{8, 0, 12}, []int{5, 6}, []int{8, 0, 13}
+	if dbType == db.MariaDB {
+		// MariaDB 10.3.2 adds the last column instantly, and MariaDB 10.2.1 accepts the expressions as the default value.
+		instantVersion, onlineVersion, expressionDefaultVersion = []int{10, 3, 2}, []int{10, 0}, []int{10, 2, 1}
+	}
+	addAlgorithm, modifyAlgorithm := "", ""
+	if compareEngineVersion(version, instantVersion) >= 0 {
+		addAlgorithm = ", ALGORITHM=INSTANT"
+	} else if compareEngineVersion(version, onlineVersion) >= 0 {
+		addAlgorithm = ", ALGORITHM=INPLACE, LOCK=NONE"
+	}
+	if compareEngineVersion(version, onlineVersion) >= 0 {
+		modifyAlgorithm = ", ALGORITHM=INPLACE, LOCK=NONE"
+	}
+	columnDefault := defaultValue
+	if compareEngineVersion(version, expressionDefaultVersion) >= 0 && !isSQLLiteral(defaultValue) {
+		columnDefault = fmt.Sprintf("(%s)", defaultValue)
+	}
+
+	return []*ColumnChangeStep{
+		{
+			Name:      fmt.Sprintf("Add nullable column %s", columnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL%s;", table, column, columnType, addAlgorithm),
+		},
+		{
+			Name:      fmt.Sprintf("Set default value of column %s", columnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", table, column, columnDefault),
+		},
+		{
+			Name:      fmt.Sprintf("Backfill column %s", columnName),
+			TaskType:  TaskDatabaseDataBackfill,
+			Statement: fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s IS NULL LIMIT %d;", table, column, defaultValue, column, batchSize),
+		},
+		{
+			// MySQL has no NOT VALID constraints, it validates the rows while rebuilding the table online.
+			Name:      fmt.Sprintf("Set column %s NOT NULL", columnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s NOT NULL DEFAULT %s%s;", table, column, columnType, columnDefault, modifyAlgorithm),
+		},
+	}
+}
+
+func planPostgresColumnAdd(version []int, tableName, columnName, columnType, defaultValue string, batchSize int) []*ColumnChangeStep {
+	quote := func(s string) string {
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+	}
+	schemaName := "public"
+	if i := strings.Index(tableName, "."); i >= 0 {
+		schemaName, tableName = tableName[:i], tableName[i+1:]
+	}
+	table := fmt.Sprintf("%s.%s", quote(schemaName), quote(tableName))
+	column := quote(columnName)
+	constraint := quote(fmt.Sprintf("bb_%s_%s_not_null", tableName, columnName))
+
+	stepList := []*ColumnChangeStep{
+		{
+			// Adding the column with the default value rewrites the table before PostgreSQL 11,
+			// and the volatile default value still does, so the default value is set separately.
+			Name:      fmt.Sprintf("Add nullable column %s", columnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL;", table, column, columnType),
+		},
+		{
+			Name:      fmt.Sprintf("Set default value of column %s", columnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", table, column, defaultValue),
+		},
+		{
+			Name:     fmt.Sprintf("Backfill column %s", columnName),
+			TaskType: TaskDatabaseDataBackfill,
+			// PostgreSQL has no UPDATE ... LIMIT, so the batch is selected by ctid.
+			Statement: fmt.Sprintf("UPDATE %s SET %s = %s WHERE ctid IN (SELECT ctid FROM %s WHERE %s IS NULL LIMIT %d);", table, column, defaultValue, table, column, batchSize),
+		},
+		{
+			// The NOT VALID constraint only checks the new writes, so it takes the lock briefly.
+			Name:      fmt.Sprintf("Add NOT VALID check constraint on column %s", columnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s IS NOT NULL) NOT VALID;", table, constraint, column),
+		},
+		{
+			// Validating the constraint scans the table without blocking the writes.
+			Name:      fmt.Sprintf("Validate check constraint on column %s", columnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s;", table, constraint),
+		},
+	}
+	// PostgreSQL 12 skips the table scan of SET NOT NULL if a valid check constraint proves it,
+	// while the older versions keep the check constraint instead of scanning the table under the exclusive lock.
+	if compareEngineVersion(version, []int{12}) >= 0 {
+		stepList = append(stepList, &ColumnChangeStep{
+			Name:     fmt.Sprintf("Set column %s NOT NULL", columnName),
+			TaskType: TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;\n", table, column) +
+				fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", table, constraint),
+		})
+	}
+	return stepList
+}
+
+// isSQLLiteral returns whether the SQL expression is a string or numeric literal, or a keyword MySQL accepts as the default value
+// without parentheses, e.g. CURRENT_TIMESTAMP.
+func isSQLLiteral(expr string) bool {
+	expr = strings.TrimSpace(expr)
+	switch strings.ToUpper(expr) {
+	case "NULL", "TRUE", "FALSE", "CURRENT_TIMESTAMP":
+		return true
+	}
+	if len(expr) >= 2 && expr[0] == '\'' && expr[len(expr)-1] == '\'' {
+		return !strings.Contains(strings.ReplaceAll(expr[1:len(expr)-1], "''", ""), "'")
+	}
+	return sqlNumericLiteralRegexp.MatchString(expr)
+}
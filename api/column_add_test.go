@@ -0,0 +1,62 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestPlanColumnAdd(t *testing.T) {
+	stepList, err := PlanColumnAdd(db.MySQL, "8.0.28", "user", "status", "varchar(16)", "'active'", 500)
+	require.NoError(t, err)
+	require.Len(t, stepList, 4)
+	require.Equal(t, "ALTER TABLE `user` ADD COLUMN `status` varchar(16) NULL, ALGORITHM=INSTANT;", stepList[0].Statement)
+	require.Equal(t, "ALTER TABLE `user` ALTER COLUMN `status` SET DEFAULT 'active';", stepList[1].Statement)
+	require.Equal(t, TaskDatabaseDataBackfill, stepList[2].TaskType)
+	require.Equal(t, "UPDATE `user` SET `status` = 'active' WHERE `status` IS NULL LIMIT 500;", stepList[2].Statement)
+	require.Equal(t, "ALTER TABLE `user` MODIFY COLUMN `status` varchar(16) NOT NULL DEFAULT 'active', ALGORITHM=INPLACE, LOCK=NONE;", stepList[3].Statement)
+
+	stepList, err = PlanColumnAdd(db.MySQL, "8.0.28", "user", "uid", "varchar(36)", "uuid()", 0)
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE `user` ALTER COLUMN `uid` SET DEFAULT (uuid());", stepList[1].Statement)
+	require.Equal(t, "UPDATE `user` SET `uid` = uuid() WHERE `uid` IS NULL LIMIT 1000;", stepList[2].Statement)
+
+	stepList, err = PlanColumnAdd(db.MySQL, "5.7.36-log", "user", "score", "int", "0", 0)
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE `user` ADD COLUMN `score` int NULL, ALGORITHM=INPLACE, LOCK=NONE;", stepList[0].Statement)
+
+	stepList, err = PlanColumnAdd(db.MariaDB, "10.6.11-MariaDB-1:10.6.11+maria~ubu2004", "user", "uid", "varchar(36)", "uuid()", 0)
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE `user` ADD COLUMN `uid` varchar(36) NULL, ALGORITHM=INSTANT;", stepList[0].Statement)
+	require.Equal(t, "ALTER TABLE `user` ALTER COLUMN `uid` SET DEFAULT (uuid());", stepList[1].Statement)
+	require.Equal(t, "ALTER TABLE `user` MODIFY COLUMN `uid` varchar(36) NOT NULL DEFAULT (uuid()), ALGORITHM=INPLACE, LOCK=NONE;", stepList[3].Statement)
+
+	// MariaDB 10.2 is numbered above MySQL 8.0, but it adds the column online rather than instantly.
+	stepList, err = PlanColumnAdd(db.MariaDB, "10.2.44-MariaDB", "user", "score", "int", "0", 0)
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE `user` ADD COLUMN `score` int NULL, ALGORITHM=INPLACE, LOCK=NONE;", stepList[0].Statement)
+
+	stepList, err = PlanColumnAdd(db.Postgres, "14.2", "s.user", "score", "integer", "0", 0)
+	require.NoError(t, err)
+	require.Len(t, stepList, 6)
+	require.Equal(t, `ALTER TABLE "s"."user" ADD COLUMN "score" integer NULL;`, stepList[0].Statement)
+	require.Equal(t, `ALTER TABLE "s"."user" ALTER COLUMN "score" SET DEFAULT 0;`, stepList[1].Statement)
+	require.Equal(t, `UPDATE "s"."user" SET "score" = 0 WHERE ctid IN (SELECT ctid FROM "s"."user" WHERE "score" IS NULL LIMIT 1000);`, stepList[2].Statement)
+	require.Equal(t, `ALTER TABLE "s"."user" ADD CONSTRAINT "bb_user_score_not_null" CHECK ("score" IS NOT NULL) NOT VALID;`, stepList[3].Statement)
+	require.Equal(t, `ALTER TABLE "s"."user" VALIDATE CONSTRAINT "bb_user_score_not_null";`, stepList[4].Statement)
+	require.Equal(t, `ALTER TABLE "s"."user" ALTER COLUMN "score" SET NOT NULL;`+"\n"+
+		`ALTER TABLE "s"."user" DROP CONSTRAINT "bb_user_score_not_null";`, stepList[5].Statement)
+
+	// PostgreSQL 11 keeps the validated check constraint.
+	stepList, err = PlanColumnAdd(db.Postgres, "11.15", "user", "score", "integer", "0", 0)
+	require.NoError(t, err)
+	require.Len(t, stepList, 5)
+
+	_, err = PlanColumnAdd(db.MySQL, "8.0.28", "user", "score", "int", "", 0)
+	require.Equal(t, common.Invalid, common.ErrorCode(err))
+	_, err = PlanColumnAdd(db.ClickHouse, "22.3", "user", "score", "Int32", "0", 0)
+	require.Equal(t, common.Invalid, common.ErrorCode(err))
+}
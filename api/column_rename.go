@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// DefaultColumnRenameBatchSize is the default number of rows backfilled in a batch.
+const DefaultColumnRenameBatchSize = 1000
+
+// ColumnChangeStep is a step of changing a column online, e.g. renaming a column with the expand/contract pattern.
+type ColumnChangeStep struct {
+	Name     string
+	TaskType TaskType
+	// Statement is the migration statement for the schema update task, or the batch statement for the backfill task.
+	Statement string
+	// WaitSeconds is the minimum waiting period before the step, e.g. for deploying the application using the new column.
+	WaitSeconds int64
+}
+
+// PlanColumnRename plans renaming the column of the table with the expand/contract pattern:
+//  1. add the new column.
+//  2. create the triggers copying the old column to the new column on writes.
+//  3. backfill the new column in batches.
+//  4. swap the trigger direction after the application reads and writes the new column.
+//  5. drop the triggers and the old column.
+func PlanColumnRename(dbType db.Type, tableName string, column *Column, columnType, newColumnName string, batchSize int, waitSeconds int64) ([]*ColumnChangeStep, error) {
+	if newColumnName == "" || newColumnName == column.Name {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("new column name must be different from %q", column.Name)}
+	}
+	if columnType == "" {
+		columnType = column.Type
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultColumnRenameBatchSize
+	}
+	if waitSeconds < 0 {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("waiting period must not be negative, got %d", waitSeconds)}
+	}
+
+	switch dbType {
+	case db.MySQL, db.MariaDB:
+		return planMySQLColumnRename(tableName, column, columnType, newColumnName, batchSize, waitSeconds), nil
+	case db.Postgres:
+		return planPostgresColumnRename(tableName, column, columnType, newColumnName, batchSize, waitSeconds), nil
+	}
+	return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("column rename is not supported for %s", dbType)}
+}
+
+func planMySQLColumnRename(tableName string, column *Column, columnType, newColumnName string, batchSize int, waitSeconds int64) []*ColumnChangeStep {
+	quote := func(s string) string {
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "`", "``"))
+	}
+	table, oldColumn, newColumn := quote(tableName), quote(column.Name), quote(newColumnName)
+	insertTrigger := quote(fmt.Sprintf("bb_%s_%s_insert", tableName, newColumnName))
+	updateTrigger := quote(fmt.Sprintf("bb_%s_%s_update", tableName, newColumnName))
+	createTriggers := func(from, to string) string {
+		return fmt.Sprintf("CREATE TRIGGER %s BEFORE INSERT ON %s FOR EACH ROW SET NEW.%s = NEW.%s;\n", insertTrigger, table, to, from) +
+			fmt.Sprintf("CREATE TRIGGER %s BEFORE UPDATE ON %s FOR EACH ROW SET NEW.%s = NEW.%s;", updateTrigger, table, to, from)
+	}
+	dropTriggers := fmt.Sprintf("DROP TRIGGER IF EXISTS %s;\nDROP TRIGGER IF EXISTS %s;", insertTrigger, updateTrigger)
+
+	swap := dropTriggers + "\n" + createTriggers(newColumn, oldColumn)
+	if !column.Nullable {
+		swap = fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s NOT NULL;\n", table, newColumn, columnType) + swap
+	}
+	return []*ColumnChangeStep{
+		{
+			Name:      fmt.Sprintf("Add column %s", newColumnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL;", table, newColumn, columnType),
+		},
+		{
+			Name:      fmt.Sprintf("Create triggers writing %s to %s", column.Name, newColumnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: createTriggers(oldColumn, newColumn),
+		},
+		{
+			Name:      fmt.Sprintf("Backfill column %s", newColumnName),
+			TaskType:  TaskDatabaseDataBackfill,
+			Statement: fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s IS NULL AND %s IS NOT NULL LIMIT %d;", table, newColumn, oldColumn, newColumn, oldColumn, batchSize),
+		},
+		{
+			Name:        fmt.Sprintf("Swap triggers writing %s to %s", newColumnName, column.Name),
+			TaskType:    TaskDatabaseSchemaUpdate,
+			Statement:   swap,
+			WaitSeconds: waitSeconds,
+		},
+		{
+			Name:        fmt.Sprintf("Drop column %s", column.Name),
+			TaskType:    TaskDatabaseSchemaUpdate,
+			Statement:   dropTriggers + "\n" + fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, oldColumn),
+			WaitSeconds: waitSeconds,
+		},
+	}
+}
+
+func planPostgresColumnRename(tableName string, column *Column, columnType, newColumnName string, batchSize int, waitSeconds int64) []*ColumnChangeStep {
+	quote := func(s string) string {
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+	}
+	schemaName := "public"
+	if i := strings.Index(tableName, "."); i >= 0 {
+		schemaName, tableName = tableName[:i], tableName[i+1:]
+	}
+	table := fmt.Sprintf("%s.%s", quote(schemaName), quote(tableName))
+	oldColumn, newColumn := quote(column.Name), quote(newColumnName)
+	function := fmt.Sprintf("%s.%s", quote(schemaName), quote(fmt.Sprintf("bb_%s_%s", tableName, newColumnName)))
+	trigger := quote(fmt.Sprintf("bb_%s_%s", tableName, newColumnName))
+	createTrigger := func(from, to string) string {
+		return fmt.Sprintf("CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$\nBEGIN\n  NEW.%s := NEW.%s;\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;\n", function, to, from) +
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s;\n", trigger, table) +
+			fmt.Sprintf("CREATE TRIGGER %s BEFORE INSERT OR UPDATE ON %s FOR EACH ROW EXECUTE FUNCTION %s();", trigger, table, function)
+	}
+
+	swap := createTrigger(newColumn, oldColumn)
+	if !column.Nullable {
+		swap = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;\n", table, newColumn) + swap
+	}
+	return []*ColumnChangeStep{
+		{
+			Name:      fmt.Sprintf("Add column %s", newColumnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL;", table, newColumn, columnType),
+		},
+		{
+			Name:      fmt.Sprintf("Create trigger writing %s to %s", column.Name, newColumnName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: createTrigger(oldColumn, newColumn),
+		},
+		{
+			Name:     fmt.Sprintf("Backfill column %s", newColumnName),
+			TaskType: TaskDatabaseDataBackfill,
+			// PostgreSQL has no UPDATE ... LIMIT, so the batch is selected by ctid.
+			Statement: fmt.Sprintf("UPDATE %s SET %s = %s WHERE ctid IN (SELECT ctid FROM %s WHERE %s IS NULL AND %s IS NOT NULL LIMIT %d);", table, newColumn, oldColumn, table, newColumn, oldColumn, batchSize),
+		},
+		{
+			Name:        fmt.Sprintf("Swap trigger writing %s to %s", newColumnName, column.Name),
+			TaskType:    TaskDatabaseSchemaUpdate,
+			Statement:   swap,
+			WaitSeconds: waitSeconds,
+		},
+		{
+			Name:     fmt.Sprintf("Drop column %s", column.Name),
+			TaskType: TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s;\n", trigger, table) +
+				fmt.Sprintf("DROP FUNCTION IF EXISTS %s();\n", function) +
+				fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, oldColumn),
+			WaitSeconds: waitSeconds,
+		},
+	}
+}
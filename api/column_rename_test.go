@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestPlanColumnRename(t *testing.T) {
+	column := &Column{Name: "name", Type: "varchar(255)", Nullable: false}
+
+	stepList, err := PlanColumnRename(db.MySQL, "user", column, "", "full_name", 500, 3600)
+	require.NoError(t, err)
+	require.Len(t, stepList, 5)
+	require.Equal(t, "ALTER TABLE `user` ADD COLUMN `full_name` varchar(255) NULL;", stepList[0].Statement)
+	require.Equal(t, "CREATE TRIGGER `bb_user_full_name_insert` BEFORE INSERT ON `user` FOR EACH ROW SET NEW.`full_name` = NEW.`name`;\n"+
+		"CREATE TRIGGER `bb_user_full_name_update` BEFORE UPDATE ON `user` FOR EACH ROW SET NEW.`full_name` = NEW.`name`;", stepList[1].Statement)
+	require.Equal(t, TaskDatabaseDataBackfill, stepList[2].TaskType)
+	require.Equal(t, "UPDATE `user` SET `full_name` = `name` WHERE `full_name` IS NULL AND `name` IS NOT NULL LIMIT 500;", stepList[2].Statement)
+	require.Equal(t, "ALTER TABLE `user` MODIFY COLUMN `full_name` varchar(255) NOT NULL;\n"+
+		"DROP TRIGGER IF EXISTS `bb_user_full_name_insert`;\nDROP TRIGGER IF EXISTS `bb_user_full_name_update`;\n"+
+		"CREATE TRIGGER `bb_user_full_name_insert` BEFORE INSERT ON `user` FOR EACH ROW SET NEW.`name` = NEW.`full_name`;\n"+
+		"CREATE TRIGGER `bb_user_full_name_update` BEFORE UPDATE ON `user` FOR EACH ROW SET NEW.`name` = NEW.`full_name`;", stepList[3].Statement)
+	require.Equal(t, int64(3600), stepList[3].WaitSeconds)
+	require.Equal(t, "DROP TRIGGER IF EXISTS `bb_user_full_name_insert`;\nDROP TRIGGER IF EXISTS `bb_user_full_name_update`;\n"+
+		"ALTER TABLE `user` DROP COLUMN `name`;", stepList[4].Statement)
+	require.Equal(t, int64(3600), stepList[4].WaitSeconds)
+
+	stepList, err = PlanColumnRename(db.Postgres, "s.user", &Column{Name: "name", Type: "text", Nullable: true}, "", "full_name", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, stepList, 5)
+	require.Equal(t, `ALTER TABLE "s"."user" ADD COLUMN "full_name" text NULL;`, stepList[0].Statement)
+	require.Equal(t, `UPDATE "s"."user" SET "full_name" = "name" WHERE ctid IN (SELECT ctid FROM "s"."user" WHERE "full_name" IS NULL AND "name" IS NOT NULL LIMIT 1000);`, stepList[2].Statement)
+	require.Equal(t, `DROP TRIGGER IF EXISTS "bb_user_full_name" ON "s"."user";`+"\n"+
+		`DROP FUNCTION IF EXISTS "s"."bb_user_full_name"();`+"\n"+
+		`ALTER TABLE "s"."user" DROP COLUMN "name";`, stepList[4].Statement)
+
+	_, err = PlanColumnRename(db.MySQL, "user", column, "", "name", 0, 0)
+	require.Equal(t, common.Invalid, common.ErrorCode(err))
+	_, err = PlanColumnRename(db.ClickHouse, "user", column, "", "full_name", 0, 0)
+	require.Equal(t, common.Invalid, common.ErrorCode(err))
+}
@@ -0,0 +1,41 @@
+package api
+
+// ConnectionCheckType is the type of the connection check.
+type ConnectionCheckType string
+
+const (
+	// ConnectionCheckDNS resolves the host name.
+	ConnectionCheckDNS ConnectionCheckType = "DNS"
+	// ConnectionCheckTCP connects to the host and the port.
+	ConnectionCheckTCP ConnectionCheckType = "TCP"
+	// ConnectionCheckTLS handshakes with the server to inspect the TLS version and the certificate.
+	ConnectionCheckTLS ConnectionCheckType = "TLS"
+	// ConnectionCheckAuth logins with the username and the password.
+	ConnectionCheckAuth ConnectionCheckType = "AUTH"
+	// ConnectionCheckPrivilege probes the privileges of the user.
+	ConnectionCheckPrivilege ConnectionCheckType = "PRIVILEGE"
+)
+
+// ConnectionCheckStatus is the status of the connection check.
+type ConnectionCheckStatus string
+
+const (
+	// ConnectionCheckSuccess is the status of the passed check.
+	ConnectionCheckSuccess ConnectionCheckStatus = "SUCCESS"
+	// ConnectionCheckWarn is the status of the check passed with a concern, e.g. the server doesn't support TLS.
+	ConnectionCheckWarn ConnectionCheckStatus = "WARN"
+	// ConnectionCheckError is the status of the failed check.
+	ConnectionCheckError ConnectionCheckStatus = "ERROR"
+	// ConnectionCheckSkipped is the status of the check not applicable to the engine, or skipped after a prior failure.
+	ConnectionCheckSkipped ConnectionCheckStatus = "SKIPPED"
+)
+
+// ConnectionCheck is the result of a step in the connection test.
+type ConnectionCheck struct {
+	Type   ConnectionCheckType   `json:"type"`
+	Title  string                `json:"title"`
+	Status ConnectionCheckStatus `json:"status"`
+	Detail string                `json:"detail"`
+	// DurationMs is the time the check takes in milliseconds.
+	DurationMs int64 `json:"durationMs"`
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// DashboardTileRefresh is when the result of a dashboard tile is refreshed.
+type DashboardTileRefresh string
+
+const (
+	// DashboardTileRefreshOnView refreshes the result when the tile is viewed and the cached result has expired.
+	DashboardTileRefreshOnView DashboardTileRefresh = "ON_VIEW"
+	// DashboardTileRefreshHourly refreshes the result every hour.
+	DashboardTileRefreshHourly DashboardTileRefresh = "HOURLY"
+	// DashboardTileRefreshDaily refreshes the result every day.
+	DashboardTileRefreshDaily DashboardTileRefresh = "DAILY"
+
+	// DashboardTileOnViewCacheDuration is how long the result of an ON_VIEW tile is served from the cache,
+	// so that a dashboard opened by many viewers doesn't run the same query for each of them.
+	DashboardTileOnViewCacheDuration = 5 * time.Minute
+	// DashboardTileMaxRowCount is the maximum number of rows in the result of a dashboard tile.
+	DashboardTileMaxRowCount = 1000
+)
+
+// Interval returns the interval between the scheduled refreshes, 0 if the tile is refreshed on view.
+func (r DashboardTileRefresh) Interval() time.Duration {
+	switch r {
+	case DashboardTileRefreshHourly:
+		return time.Hour
+	case DashboardTileRefreshDaily:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// DashboardTile is the API message for a dashboard tile.
+// A tile pins a parameterized read-only query against a database of the project, and caches its latest result.
+type DashboardTile struct {
+	ID int `jsonapi:"primary,dashboardTile"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID  int       `jsonapi:"attr,projectId"`
+	DatabaseID int       `jsonapi:"attr,databaseId"`
+	Database   *Database `jsonapi:"relation,database"`
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+	// Statement is the SELECT statement, which may reference the parameters and the project variables as {{NAME}}.
+	// The parameter values are substituted as the quoted string literals, while the project variables are substituted as is.
+	Statement string `jsonapi:"attr,statement"`
+	// Parameters is the JSON encoded map from the parameter name to the default value, the values can be overridden when running the tile.
+	Parameters string               `jsonapi:"attr,parameters"`
+	Refresh    DashboardTileRefresh `jsonapi:"attr,refresh"`
+	// Result is the JSON encoded row set of the latest run with the default parameter values, and ResultError is its error.
+	Result      string `jsonapi:"attr,result"`
+	ResultError string `jsonapi:"attr,resultError"`
+	// RefreshedTs is when the result is refreshed, 0 if never.
+	RefreshedTs int64 `jsonapi:"attr,refreshedTs"`
+}
+
+// IsResultExpired returns whether the cached result should be refreshed before serving it to a viewer.
+// The scheduled tiles are refreshed by the scheduler, so their results only expire if they have never been refreshed.
+func (tile *DashboardTile) IsResultExpired(now time.Time) bool {
+	if tile.RefreshedTs == 0 {
+		return true
+	}
+	if tile.Refresh.Interval() > 0 {
+		return false
+	}
+	return now.Sub(time.Unix(tile.RefreshedTs, 0)) >= DashboardTileOnViewCacheDuration
+}
+
+// IsRefreshDue returns whether the scheduled refresh of the tile is due, it's never due for the tiles refreshed on view.
+func (tile *DashboardTile) IsRefreshDue(now time.Time) bool {
+	interval := tile.Refresh.Interval()
+	return interval > 0 && now.Sub(time.Unix(tile.RefreshedTs, 0)) >= interval
+}
+
+// DashboardTileCreate is the API message for creating a dashboard tile.
+type DashboardTileCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	ProjectID  int
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Name       string               `jsonapi:"attr,name"`
+	Statement  string               `jsonapi:"attr,statement"`
+	Parameters string               `jsonapi:"attr,parameters"`
+	Refresh    DashboardTileRefresh `jsonapi:"attr,refresh"`
+}
+
+// DashboardTileFind is the API message for finding dashboard tiles.
+type DashboardTileFind struct {
+	ID *int
+
+	// Related fields
+	ProjectID *int
+
+	// Domain specific fields
+	// Scheduled finds the tiles refreshed on a schedule rather than on view.
+	Scheduled bool
+}
+
+func (find *DashboardTileFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DashboardTilePatch is the API message for patching a dashboard tile.
+type DashboardTilePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	DatabaseID *int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Name       *string `jsonapi:"attr,name"`
+	Statement  *string `jsonapi:"attr,statement"`
+	Parameters *string `jsonapi:"attr,parameters"`
+	Refresh    *string `jsonapi:"attr,refresh"`
+	// Result, ResultError and RefreshedTs are set by the server after running the tile, and reset when the query changes.
+	Result      *string
+	ResultError *string
+	RefreshedTs *int64
+}
+
+// DashboardTileDelete is the API message for deleting a dashboard tile.
+type DashboardTileDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// DashboardTileRun is the API message for running a dashboard tile.
+type DashboardTileRun struct {
+	// Parameters is the JSON encoded map overriding the default parameter values, the result isn't cached if any value is overridden.
+	Parameters string `jsonapi:"attr,parameters"`
+	// Force refreshes the cached result even if it hasn't expired.
+	Force bool `jsonapi:"attr,force"`
+}
+
+// ValidateDashboardTileRefresh validates the refresh of a dashboard tile.
+func ValidateDashboardTileRefresh(refresh DashboardTileRefresh) error {
+	switch refresh {
+	case DashboardTileRefreshOnView, DashboardTileRefreshHourly, DashboardTileRefreshDaily:
+		return nil
+	}
+	return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid dashboard tile refresh %q", refresh)}
+}
+
+// ParseDashboardTileParameters parses and validates the JSON encoded parameters, the empty string is no parameters.
+// The parameters share the naming rules of the project variables, and they take precedence over the project variables with the same names.
+func ParseDashboardTileParameters(parameters string) (map[string]string, error) {
+	parameterMap := map[string]string{}
+	if parameters == "" {
+		return parameterMap, nil
+	}
+	if err := json.Unmarshal([]byte(parameters), &parameterMap); err != nil {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("parameters must be a JSON object of string values, error: %v", err)}
+	}
+	for name := range parameterMap {
+		if err := ValidateProjectVariableName(name); err != nil {
+			return nil, err
+		}
+	}
+	return parameterMap, nil
+}
+
+// MergeDashboardTileParameters returns the default parameter values overridden by the given values.
+// It returns an error if any overridden parameter isn't defined by the tile, so that a misspelled name doesn't silently fall back to the default.
+func MergeDashboardTileParameters(defaultMap, overrideMap map[string]string) (map[string]string, error) {
+	parameterMap := map[string]string{}
+	for name, value := range defaultMap {
+		parameterMap[name] = value
+	}
+	for name, value := range overrideMap {
+		if _, ok := defaultMap[name]; !ok {
+			return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("parameter %q isn't defined by the dashboard tile", name)}
+		}
+		parameterMap[name] = value
+	}
+	return parameterMap, nil
+}
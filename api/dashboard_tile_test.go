@@ -0,0 +1,75 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardTileIsResultExpired(t *testing.T) {
+	now := time.Date(2022, 6, 8, 10, 0, 0, 0, time.UTC)
+
+	tile := &DashboardTile{Refresh: DashboardTileRefreshOnView}
+	require.True(t, tile.IsResultExpired(now))
+	tile.RefreshedTs = now.Add(-time.Minute).Unix()
+	require.False(t, tile.IsResultExpired(now))
+	tile.RefreshedTs = now.Add(-DashboardTileOnViewCacheDuration).Unix()
+	require.True(t, tile.IsResultExpired(now))
+
+	// The results of the scheduled tiles are served until the next refresh.
+	tile = &DashboardTile{Refresh: DashboardTileRefreshDaily}
+	require.True(t, tile.IsResultExpired(now))
+	tile.RefreshedTs = now.Add(-48 * time.Hour).Unix()
+	require.False(t, tile.IsResultExpired(now))
+}
+
+func TestDashboardTileIsRefreshDue(t *testing.T) {
+	now := time.Date(2022, 6, 8, 10, 0, 0, 0, time.UTC)
+
+	tile := &DashboardTile{Refresh: DashboardTileRefreshOnView}
+	require.False(t, tile.IsRefreshDue(now))
+
+	tile = &DashboardTile{Refresh: DashboardTileRefreshHourly}
+	require.True(t, tile.IsRefreshDue(now))
+	tile.RefreshedTs = now.Add(-30 * time.Minute).Unix()
+	require.False(t, tile.IsRefreshDue(now))
+	tile.RefreshedTs = now.Add(-time.Hour).Unix()
+	require.True(t, tile.IsRefreshDue(now))
+}
+
+func TestParseDashboardTileParameters(t *testing.T) {
+	parameterMap, err := ParseDashboardTileParameters("")
+	require.NoError(t, err)
+	require.Empty(t, parameterMap)
+
+	parameterMap, err = ParseDashboardTileParameters(`{"SINCE": "2022-01-01", "STATUS": "PAID"}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"SINCE": "2022-01-01", "STATUS": "PAID"}, parameterMap)
+
+	_, err = ParseDashboardTileParameters(`{"since": "2022-01-01"}`)
+	require.Error(t, err)
+	_, err = ParseDashboardTileParameters(`{"DATABASE_NAME": "db"}`)
+	require.Error(t, err)
+	_, err = ParseDashboardTileParameters(`{"LIMIT": 10}`)
+	require.Error(t, err)
+}
+
+func TestMergeDashboardTileParameters(t *testing.T) {
+	defaultMap := map[string]string{"SINCE": "2022-01-01", "STATUS": "PAID"}
+
+	parameterMap, err := MergeDashboardTileParameters(defaultMap, map[string]string{"STATUS": "REFUNDED"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"SINCE": "2022-01-01", "STATUS": "REFUNDED"}, parameterMap)
+	// The default values are kept.
+	require.Equal(t, "PAID", defaultMap["STATUS"])
+
+	_, err = MergeDashboardTileParameters(defaultMap, map[string]string{"STATE": "REFUNDED"})
+	require.Error(t, err)
+}
+
+func TestValidateDashboardTileRefresh(t *testing.T) {
+	require.NoError(t, ValidateDashboardTileRefresh(DashboardTileRefreshOnView))
+	require.NoError(t, ValidateDashboardTileRefresh(DashboardTileRefreshDaily))
+	require.Error(t, ValidateDashboardTileRefresh("WEEKLY"))
+}
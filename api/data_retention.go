@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+)
+
+var (
+	// QueryHistoryActivityTypeList are the activity types of the query history.
+	QueryHistoryActivityTypeList = []ActivityType{
+		ActivitySQLEditorQuery,
+	}
+	// AuditLogActivityTypeList are the activity types of the audit log, i.e. the changes of the workspace access
+	// and the access to the sensitive data.
+	AuditLogActivityTypeList = []ActivityType{
+		ActivityMemberCreate,
+		ActivityMemberRoleUpdate,
+		ActivityMemberActivate,
+		ActivityMemberDeactivate,
+		ActivityMemberErase,
+		ActivityProjectMemberCreate,
+		ActivityProjectMemberDelete,
+		ActivityProjectMemberRoleUpdate,
+		ActivitySQLEditorMetadataQuery,
+		ActivityDataSourceCredentialRotate,
+	}
+)
+
+// DataRetentionSetting is the value of the workspace data retention setting.
+// The retention days of each category are the number of days the records are kept before they are purged, 0 means forever.
+// The records created by the members on legal hold are never purged.
+type DataRetentionSetting struct {
+	// ActivityRetentionDays applies to the activities other than the query history and the audit log, e.g. the issue comments.
+	ActivityRetentionDays     int `json:"activityRetentionDays"`
+	QueryHistoryRetentionDays int `json:"queryHistoryRetentionDays"`
+	AuditLogRetentionDays     int `json:"auditLogRetentionDays"`
+}
+
+// Validate validates the data retention setting.
+func (s *DataRetentionSetting) Validate() error {
+	if s.ActivityRetentionDays < 0 {
+		return fmt.Errorf("activity retention days must not be negative, got %d", s.ActivityRetentionDays)
+	}
+	if s.QueryHistoryRetentionDays < 0 {
+		return fmt.Errorf("query history retention days must not be negative, got %d", s.QueryHistoryRetentionDays)
+	}
+	if s.AuditLogRetentionDays < 0 {
+		return fmt.Errorf("audit log retention days must not be negative, got %d", s.AuditLogRetentionDays)
+	}
+	return nil
+}
+
+// GetActivityPurgeList returns the activities to purge at the time, one for each category with the retention days.
+func (s *DataRetentionSetting) GetActivityPurgeList(nowTs int64) []*ActivityPurge {
+	var purgeList []*ActivityPurge
+	if s.ActivityRetentionDays > 0 {
+		var excludeTypeList []ActivityType
+		excludeTypeList = append(excludeTypeList, QueryHistoryActivityTypeList...)
+		excludeTypeList = append(excludeTypeList, AuditLogActivityTypeList...)
+		purgeList = append(purgeList, &ActivityPurge{
+			ExcludeTypeList: excludeTypeList,
+			CreatedBeforeTs: nowTs - int64(s.ActivityRetentionDays)*24*3600,
+		})
+	}
+	if s.QueryHistoryRetentionDays > 0 {
+		purgeList = append(purgeList, &ActivityPurge{
+			TypeList:        QueryHistoryActivityTypeList,
+			CreatedBeforeTs: nowTs - int64(s.QueryHistoryRetentionDays)*24*3600,
+		})
+	}
+	if s.AuditLogRetentionDays > 0 {
+		purgeList = append(purgeList, &ActivityPurge{
+			TypeList:        AuditLogActivityTypeList,
+			CreatedBeforeTs: nowTs - int64(s.AuditLogRetentionDays)*24*3600,
+		})
+	}
+	return purgeList
+}
+
+// ActivityPurge is the API message for purging the activities created before the time.
+// The activities created by the members on legal hold are kept.
+type ActivityPurge struct {
+	// TypeList limits the purge to the activity types, empty means all types.
+	TypeList []ActivityType
+	// ExcludeTypeList excludes the activity types from the purge.
+	ExcludeTypeList []ActivityType
+	CreatedBeforeTs int64
+}
+
+// PrincipalErase is the API message for erasing the personal data of a departed user.
+type PrincipalErase struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	// SuccessorID is the principal taking over the ownership of the user, e.g. the open issues and the project roles.
+	SuccessorID int `jsonapi:"attr,successorId"`
+}
+
+// PrincipalEraseResult is the API message for the result of erasing a user.
+type PrincipalEraseResult struct {
+	ID int `jsonapi:"primary,principalEraseResult"`
+
+	// Domain specific fields
+	SuccessorID int `jsonapi:"attr,successorId"`
+	// ReassignedCount is the number of the ownership references moved to the successor.
+	ReassignedCount int64 `jsonapi:"attr,reassignedCount"`
+	// DeletedCount is the number of the deleted personal records, e.g. the inbox messages and the query history.
+	DeletedCount int64 `jsonapi:"attr,deletedCount"`
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataRetentionSettingValidate(t *testing.T) {
+	require.NoError(t, (&DataRetentionSetting{}).Validate())
+	require.NoError(t, (&DataRetentionSetting{ActivityRetentionDays: 365, QueryHistoryRetentionDays: 30, AuditLogRetentionDays: 730}).Validate())
+	require.Error(t, (&DataRetentionSetting{ActivityRetentionDays: -1}).Validate())
+	require.Error(t, (&DataRetentionSetting{QueryHistoryRetentionDays: -1}).Validate())
+	require.Error(t, (&DataRetentionSetting{AuditLogRetentionDays: -1}).Validate())
+}
+
+func TestGetActivityPurgeList(t *testing.T) {
+	const nowTs = int64(100 * 24 * 3600)
+	const day = int64(24 * 3600)
+
+	require.Empty(t, (&DataRetentionSetting{}).GetActivityPurgeList(nowTs))
+
+	purgeList := (&DataRetentionSetting{QueryHistoryRetentionDays: 7}).GetActivityPurgeList(nowTs)
+	require.Len(t, purgeList, 1)
+	require.Equal(t, QueryHistoryActivityTypeList, purgeList[0].TypeList)
+	require.Empty(t, purgeList[0].ExcludeTypeList)
+	require.Equal(t, nowTs-7*day, purgeList[0].CreatedBeforeTs)
+
+	purgeList = (&DataRetentionSetting{ActivityRetentionDays: 30, AuditLogRetentionDays: 90}).GetActivityPurgeList(nowTs)
+	require.Len(t, purgeList, 2)
+	// The other activities exclude the query history and the audit log, which have their own retention.
+	require.Empty(t, purgeList[0].TypeList)
+	require.Contains(t, purgeList[0].ExcludeTypeList, ActivitySQLEditorQuery)
+	require.Contains(t, purgeList[0].ExcludeTypeList, ActivityMemberRoleUpdate)
+	require.NotContains(t, purgeList[0].ExcludeTypeList, ActivityIssueCommentCreate)
+	require.Equal(t, nowTs-30*day, purgeList[0].CreatedBeforeTs)
+	require.Equal(t, AuditLogActivityTypeList, purgeList[1].TypeList)
+	require.Equal(t, nowTs-90*day, purgeList[1].CreatedBeforeTs)
+}
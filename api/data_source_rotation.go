@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DataSourceRotationStatus is the status of a data source credential rotation.
+type DataSourceRotationStatus string
+
+const (
+	// DataSourceRotationValidated is the status when the staged credential passes the validation and is ready to commit.
+	DataSourceRotationValidated DataSourceRotationStatus = "VALIDATED"
+	// DataSourceRotationFailed is the status when the staged credential fails the validation.
+	DataSourceRotationFailed DataSourceRotationStatus = "FAILED"
+	// DataSourceRotationCommitted is the status when the data source has switched to the staged credential.
+	DataSourceRotationCommitted DataSourceRotationStatus = "COMMITTED"
+	// DataSourceRotationCanceled is the status when the staged credential is discarded.
+	DataSourceRotationCanceled DataSourceRotationStatus = "CANCELED"
+)
+
+// DataSourceRotation is the API message for a data source credential rotation.
+type DataSourceRotation struct {
+	ID int `jsonapi:"primary,dataSourceRotation"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DataSourceID int `jsonapi:"attr,dataSourceId"`
+
+	// Domain specific fields
+	Status   DataSourceRotationStatus `jsonapi:"attr,status"`
+	Username string                   `jsonapi:"attr,username"`
+	// Do not return the password to client
+	Password string
+	SslCa    string
+	SslCert  string
+	SslKey   string
+	// Error is the validation error if Status is FAILED.
+	Error string `jsonapi:"attr,error"`
+}
+
+// DataSourceRotationCreate is the API message for staging a new credential on a data source.
+type DataSourceRotationCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DataSourceID int
+
+	// Domain specific fields
+	Username string `jsonapi:"attr,username"`
+	Password string `jsonapi:"attr,password"`
+	SslCa    string `jsonapi:"attr,sslCa"`
+	SslCert  string `jsonapi:"attr,sslCert"`
+	SslKey   string `jsonapi:"attr,sslKey"`
+	// Status and Error are assigned from the validation result by the server.
+	Status DataSourceRotationStatus
+	Error  string
+}
+
+// DataSourceRotationFind is the API message for finding data source rotations.
+type DataSourceRotationFind struct {
+	ID *int
+
+	// Related fields
+	DataSourceID *int
+
+	// Domain specific fields
+	Status *DataSourceRotationStatus
+}
+
+func (find *DataSourceRotationFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DataSourceRotationStatusPatch is the API message for patching the status of a data source rotation.
+type DataSourceRotationStatusPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Status DataSourceRotationStatus
+}
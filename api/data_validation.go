@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	// DefaultDataValidationChunkSize is the default number of rows checksummed in a chunk.
+	DefaultDataValidationChunkSize = 1000
+	// maxDataValidationChunkSize is the limit of the chunk size, so that a chunk query doesn't scan the table for too long.
+	maxDataValidationChunkSize = 100000
+	// maxDataValidationReportedChunkCount is the number of the mismatched chunks reported per table.
+	maxDataValidationReportedChunkCount = 10
+)
+
+// DataValidationChunkResult is the API message for the validation result of a chunk.
+// The chunk covers the rows whose primary key is in (LowerBound, UpperBound], an empty bound is unbounded.
+type DataValidationChunkResult struct {
+	LowerBound     string `json:"lowerBound"`
+	UpperBound     string `json:"upperBound"`
+	SourceRowCount int64  `json:"sourceRowCount"`
+	TargetRowCount int64  `json:"targetRowCount"`
+	SourceChecksum string `json:"sourceChecksum"`
+	TargetChecksum string `json:"targetChecksum"`
+}
+
+// Match returns true if the row count and the checksum of the chunk are the same in the source and the target.
+func (chunk *DataValidationChunkResult) Match() bool {
+	return chunk.SourceRowCount == chunk.TargetRowCount && chunk.SourceChecksum == chunk.TargetChecksum
+}
+
+// DataValidationTableResult is the API message for the validation result of a table.
+type DataValidationTableResult struct {
+	Name string `json:"name"`
+	// Error is set if the table can't be compared, e.g. the table is missing or the columns are different in the target.
+	Error          string `json:"error,omitempty"`
+	SourceRowCount int64  `json:"sourceRowCount"`
+	TargetRowCount int64  `json:"targetRowCount"`
+	ChunkCount     int    `json:"chunkCount"`
+	// MismatchedChunkList is the chunks whose row count or checksum are different.
+	MismatchedChunkList []*DataValidationChunkResult `json:"mismatchedChunkList"`
+}
+
+// Match returns true if the table is the same in the source and the target.
+func (table *DataValidationTableResult) Match() bool {
+	return table.Error == "" && len(table.MismatchedChunkList) == 0
+}
+
+// ValidateDataValidationChunkSize validates the chunk size and returns the default one if it's 0.
+func ValidateDataValidationChunkSize(chunkSize int) (int, error) {
+	if chunkSize == 0 {
+		return DefaultDataValidationChunkSize, nil
+	}
+	if chunkSize < 0 || chunkSize > maxDataValidationChunkSize {
+		return 0, &common.Error{Code: common.Invalid, Err: fmt.Errorf("chunk size must be in [1, %d]", maxDataValidationChunkSize)}
+	}
+	return chunkSize, nil
+}
+
+// FormatDataValidationResult returns the report of the table validation results and whether all the tables match.
+func FormatDataValidationResult(sourceDatabaseName, targetDatabaseName string, tableResultList []*DataValidationTableResult) (string, bool) {
+	var lines []string
+	var rowCount int64
+	var chunkCount, mismatchedTableCount int
+	for _, table := range tableResultList {
+		rowCount += table.SourceRowCount
+		chunkCount += table.ChunkCount
+		if table.Match() {
+			continue
+		}
+		mismatchedTableCount++
+		if table.Error != "" {
+			lines = append(lines, fmt.Sprintf("- %s: %s", table.Name, table.Error))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %d row(s) in source, %d row(s) in target, %d of %d chunk(s) mismatched",
+			table.Name, table.SourceRowCount, table.TargetRowCount, len(table.MismatchedChunkList), table.ChunkCount))
+		for i, chunk := range table.MismatchedChunkList {
+			if i == maxDataValidationReportedChunkCount {
+				lines = append(lines, fmt.Sprintf("  - ... and %d more", len(table.MismatchedChunkList)-i))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("  - (%s, %s]: %d row(s) in source, %d row(s) in target",
+				formatDataValidationBound(chunk.LowerBound, "-inf"), formatDataValidationBound(chunk.UpperBound, "+inf"), chunk.SourceRowCount, chunk.TargetRowCount))
+		}
+	}
+	if mismatchedTableCount == 0 {
+		return fmt.Sprintf("Validated %d table(s) with %d row(s) in %d chunk(s) between %q and %q, no mismatch found",
+			len(tableResultList), rowCount, chunkCount, sourceDatabaseName, targetDatabaseName), true
+	}
+	header := fmt.Sprintf("%d of %d table(s) mismatched between %q and %q:", mismatchedTableCount, len(tableResultList), sourceDatabaseName, targetDatabaseName)
+	return strings.Join(append([]string{header}, lines...), "\n"), false
+}
+
+func formatDataValidationBound(bound, unbounded string) string {
+	if bound == "" {
+		return unbounded
+	}
+	return bound
+}
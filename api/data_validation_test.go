@@ -0,0 +1,44 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDataValidationChunkSize(t *testing.T) {
+	chunkSize, err := ValidateDataValidationChunkSize(0)
+	require.NoError(t, err)
+	require.Equal(t, DefaultDataValidationChunkSize, chunkSize)
+	chunkSize, err = ValidateDataValidationChunkSize(500)
+	require.NoError(t, err)
+	require.Equal(t, 500, chunkSize)
+	_, err = ValidateDataValidationChunkSize(-1)
+	require.Error(t, err)
+	_, err = ValidateDataValidationChunkSize(maxDataValidationChunkSize + 1)
+	require.Error(t, err)
+}
+
+func TestFormatDataValidationResult(t *testing.T) {
+	detail, match := FormatDataValidationResult("src", "dst", []*DataValidationTableResult{
+		{Name: "t1", SourceRowCount: 1500, TargetRowCount: 1500, ChunkCount: 2},
+		{Name: "t2", SourceRowCount: 10, TargetRowCount: 10, ChunkCount: 1},
+	})
+	require.True(t, match)
+	require.Equal(t, `Validated 2 table(s) with 1510 row(s) in 3 chunk(s) between "src" and "dst", no mismatch found`, detail)
+
+	detail, match = FormatDataValidationResult("src", "dst", []*DataValidationTableResult{
+		{Name: "t1", SourceRowCount: 1500, TargetRowCount: 1499, ChunkCount: 2, MismatchedChunkList: []*DataValidationChunkResult{
+			{LowerBound: "", UpperBound: "1000", SourceRowCount: 1000, TargetRowCount: 1000, SourceChecksum: "1", TargetChecksum: "2"},
+			{LowerBound: "1000", UpperBound: "", SourceRowCount: 500, TargetRowCount: 499, SourceChecksum: "3", TargetChecksum: "4"},
+		}},
+		{Name: "t2", Error: "table not found in target database"},
+		{Name: "t3", SourceRowCount: 10, TargetRowCount: 10, ChunkCount: 1},
+	})
+	require.False(t, match)
+	require.Equal(t, `2 of 3 table(s) mismatched between "src" and "dst":
+- t1: 1500 row(s) in source, 1499 row(s) in target, 2 of 2 chunk(s) mismatched
+  - (-inf, 1000]: 1000 row(s) in source, 1000 row(s) in target
+  - (1000, +inf]: 500 row(s) in source, 499 row(s) in target
+- t2: table not found in target database`, detail)
+}
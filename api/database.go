@@ -30,6 +30,7 @@ type Database struct {
 	ID int `jsonapi:"primary,database"`
 
 	// Standard fields
+	RowStatus RowStatus `jsonapi:"attr,rowStatus"`
 	CreatorID int
 	Creator   *Principal `jsonapi:"relation,creator"`
 	CreatedTs int64      `jsonapi:"attr,createdTs"`
@@ -55,9 +56,13 @@ type Database struct {
 	SchemaVersion        string     `jsonapi:"attr,schemaVersion"`
 	SyncStatus           SyncStatus `jsonapi:"attr,syncStatus"`
 	LastSuccessfulSyncTs int64      `jsonapi:"attr,lastSuccessfulSyncTs"`
+	// NotFoundTs is the time the database was first found missing from the instance, 0 if SyncStatus is OK.
+	NotFoundTs int64 `jsonapi:"attr,notFoundTs"`
 	// Labels is a json-encoded string from a list of DatabaseLabel,
 	// e.g. "[{"key":"bb.location","value":"earth"},{"key":"bb.tenant","value":"bytebase"}]".
 	Labels string `jsonapi:"attr,labels,omitempty"`
+	// TagList is a json-encoded string from a list of tags in addition to the ones inherited from the instance, e.g. "["pci"]".
+	TagList string `jsonapi:"attr,tagList"`
 }
 
 // DatabaseCreate is the API message for creating a database.
@@ -86,6 +91,9 @@ type DatabaseCreate struct {
 type DatabaseFind struct {
 	ID *int
 
+	// Standard fields
+	RowStatus *RowStatus
+
 	// Related fields
 	ProjectID  *int
 	InstanceID *int
@@ -119,9 +127,16 @@ type DatabasePatch struct {
 	// Labels is a json-encoded string from a list of DatabaseLabel,
 	// e.g. "[{"key":"bb.location","value":"earth"},{"key":"bb.tenant","value":"bytebase"}]".
 	Labels *string `jsonapi:"attr,labels"`
+	// TagList is a json-encoded string from a list of tags, e.g. "["pci"]".
+	TagList *string `jsonapi:"attr,tagList"`
 
 	// Domain specific fields
+	// Name is only patched when the database is detected as renamed on the instance.
+	Name                 *string
 	SchemaVersion        *string
 	SyncStatus           *SyncStatus
 	LastSuccessfulSyncTs *int64
+	// RowStatus is ARCHIVED if the NOT_FOUND database is purged, and NORMAL if the database shows up again.
+	RowStatus  *RowStatus
+	NotFoundTs *int64
 }
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+var (
+	// reservedDatabaseNameList are the keywords rejected as database names by all engines.
+	// Most engines accept the quoted keywords, but such databases are error-prone to use in the statements.
+	reservedDatabaseNameList = []string{
+		"all", "and", "as", "by", "column", "create", "database", "default", "delete", "drop", "from", "grant",
+		"group", "index", "insert", "into", "key", "not", "null", "or", "order", "primary", "schema",
+		"select", "table", "to", "union", "update", "user", "where",
+	}
+	// reservedEngineDatabaseNameMap are the system databases and the engine specific keywords rejected as database names.
+	reservedEngineDatabaseNameMap = map[db.Type][]string{
+		db.MySQL:      {"information_schema", "mysql", "performance_schema", "sys", "bytebase"},
+		db.TiDB:       {"information_schema", "mysql", "performance_schema", "metrics_schema", "bytebase"},
+		db.MariaDB:    {"information_schema", "mysql", "performance_schema", "sys", "bytebase"},
+		db.Postgres:   {"postgres", "template0", "template1", "bytebase", "current_user", "session_user"},
+		db.ClickHouse: {"system", "information_schema", "bytebase"},
+		db.Snowflake:  {"snowflake", "snowflake_sample_data", "bytebase"},
+		db.SQLite:     {"main", "temp", "bytebase"},
+		db.MSSQL:      {"master", "model", "msdb", "tempdb", "bytebase"},
+		db.MongoDB:    {"admin", "config", "local", "bytebase"},
+	}
+	// snowflakeDatabaseNameRegexp matches the Snowflake unquoted identifiers.
+	snowflakeDatabaseNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+	// maxDatabaseNameLengthMap is the maximum database name length in bytes.
+	maxDatabaseNameLengthMap = map[db.Type]int{
+		db.MySQL:     64,
+		db.TiDB:      64,
+		db.MariaDB:   64,
+		db.Postgres:  63,
+		db.Snowflake: 255,
+		db.MSSQL:     128,
+		db.MongoDB:   63,
+	}
+)
+
+// ValidateDatabaseNamePattern validates the project database name pattern.
+func ValidateDatabaseNamePattern(pattern string) error {
+	if pattern == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid database name pattern %q, error: %w", pattern, err)}
+	}
+	return nil
+}
+
+// ValidateDatabaseName validates the name of the database to create on the engine.
+// The name is rejected if it's a reserved word, too long, contains the characters not allowed by the engine,
+// or doesn't match the project database name pattern. Empty pattern means no restriction.
+func ValidateDatabaseName(dbType db.Type, name string, pattern string) error {
+	if name == "" {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name must not be empty")}
+	}
+	if strings.TrimSpace(name) != name {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q must not start or end with whitespace", name)}
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q contains invalid character %q", name, r)}
+		}
+	}
+	switch dbType {
+	case db.MySQL, db.TiDB, db.MariaDB:
+		// MySQL maps the database to a directory, so the path separators and the dot are not allowed.
+		if strings.ContainsAny(name, `/\.`) {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q must not contain '/', '\\' or '.' for %s", name, dbType)}
+		}
+	case db.Postgres:
+		// The Postgres driver extracts the database name from the CREATE DATABASE and \connect statements by the quotes and whitespaces.
+		if strings.ContainsAny(name, "\" \t") {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q must not contain double quotes or whitespaces for %s", name, dbType)}
+		}
+	case db.SQLite:
+		// SQLite database is a file named after the database.
+		if strings.ContainsAny(name, `/\`) {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q must not contain '/' or '\\' for %s", name, dbType)}
+		}
+	case db.MSSQL:
+		// The SQL Server driver extracts the database name from the CREATE DATABASE and USE statements by the whitespaces.
+		if strings.ContainsAny(name, "[] \t") {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q must not contain brackets or whitespaces for %s", name, dbType)}
+		}
+	case db.MongoDB:
+		// MongoDB rejects these characters in the database names, and the dot separates the database and the collection in the namespace.
+		if strings.ContainsAny(name, `/\. "$*<>:|?`) {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q must not contain any of '/\\. \"$*<>:|?' for %s", name, dbType)}
+		}
+	case db.Snowflake:
+		// Snowflake database name is created without quoting, so it must be a valid unquoted identifier.
+		if !snowflakeDatabaseNameRegexp.MatchString(name) {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q must start with a letter or underscore and contain only letters, digits, underscores and dollar signs for %s", name, dbType)}
+		}
+	}
+	if maxLength, ok := maxDatabaseNameLengthMap[dbType]; ok && len(name) > maxLength {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q exceeds the maximum length %d for %s", name, maxLength, dbType)}
+	}
+
+	var reservedList []string
+	reservedList = append(reservedList, reservedDatabaseNameList...)
+	reservedList = append(reservedList, reservedEngineDatabaseNameMap[dbType]...)
+	for _, reserved := range reservedList {
+		if strings.EqualFold(name, reserved) {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q is a reserved word for %s", name, dbType)}
+		}
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid database name pattern %q, error: %w", pattern, err)}
+		}
+		if !re.MatchString(name) {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("database name %q doesn't match the project database name pattern %q", name, pattern)}
+		}
+	}
+	return nil
+}
+
+// QuoteIdentifier quotes the identifier for the engine, the quote characters inside the identifier are escaped by doubling.
+func QuoteIdentifier(dbType db.Type, identifier string) string {
+	switch dbType {
+	case db.MySQL, db.TiDB, db.MariaDB, db.ClickHouse:
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(identifier, "`", "``"))
+	case db.MSSQL:
+		return fmt.Sprintf("[%s]", strings.ReplaceAll(identifier, "]", "]]"))
+	default:
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(identifier, `"`, `""`))
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestValidateDatabaseName(t *testing.T) {
+	tests := []struct {
+		dbType  db.Type
+		name    string
+		pattern string
+		wantErr bool
+	}{
+		{db.MySQL, "shop", "", false},
+		{db.MySQL, "shop-prod", `^shop`, false},
+		{db.MySQL, "", "", true},
+		{db.MySQL, " shop", "", true},
+		{db.MySQL, "shop\n", "", true},
+		{db.MySQL, "shop.prod", "", true},
+		{db.MySQL, "shop/prod", "", true},
+		{db.MySQL, "Select", "", true},
+		{db.MySQL, "information_schema", "", true},
+		{db.MySQL, strings.Repeat("s", 64), "", false},
+		{db.MySQL, strings.Repeat("s", 65), "", true},
+		{db.MariaDB, "shop_prod", "", false},
+		{db.MariaDB, "performance_schema", "", true},
+		{db.Postgres, "shop.prod", "", false},
+		{db.Postgres, "template1", "", true},
+		{db.Postgres, `shop"prod`, "", true},
+		{db.Postgres, "shop prod", "", true},
+		{db.Postgres, strings.Repeat("s", 64), "", true},
+		{db.Postgres, "blog", `^shop_[a-z]+$`, true},
+		{db.Postgres, "shop_prod", `^shop_[a-z]+$`, false},
+		{db.ClickHouse, "system", "", true},
+		{db.Snowflake, "SHOP_PROD", "", false},
+		{db.Snowflake, "SHOP-PROD", "", true},
+		{db.SQLite, "main", "", true},
+		{db.SQLite, "../shop", "", true},
+		{db.MSSQL, "shop_prod", "", false},
+		{db.MSSQL, "msdb", "", true},
+		{db.MSSQL, "shop prod", "", true},
+		{db.MSSQL, "shop]prod", "", true},
+		{db.MSSQL, strings.Repeat("s", 129), "", true},
+		{db.MongoDB, "shop_prod", "", false},
+		{db.MongoDB, "local", "", true},
+		{db.MongoDB, "shop.prod", "", true},
+		{db.MongoDB, "shop$prod", "", true},
+		{db.MongoDB, strings.Repeat("s", 64), "", true},
+	}
+
+	for _, test := range tests {
+		err := ValidateDatabaseName(test.dbType, test.name, test.pattern)
+		if test.wantErr {
+			require.Equal(t, common.Invalid, common.ErrorCode(err), "%s %q", test.dbType, test.name)
+		} else {
+			require.NoError(t, err, "%s %q", test.dbType, test.name)
+		}
+	}
+
+	require.NoError(t, ValidateDatabaseNamePattern(`^shop_`))
+	require.Equal(t, common.Invalid, common.ErrorCode(ValidateDatabaseNamePattern(`^shop_(`)))
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	require.Equal(t, "`shop`", QuoteIdentifier(db.MySQL, "shop"))
+	require.Equal(t, "`sh``op`", QuoteIdentifier(db.TiDB, "sh`op"))
+	require.Equal(t, "`shop`", QuoteIdentifier(db.MariaDB, "shop"))
+	require.Equal(t, "`shop`", QuoteIdentifier(db.ClickHouse, "shop"))
+	require.Equal(t, `"shop"`, QuoteIdentifier(db.Postgres, "shop"))
+	require.Equal(t, `"sh""op"`, QuoteIdentifier(db.Postgres, `sh"op`))
+	require.Equal(t, "[sh]]op]", QuoteIdentifier(db.MSSQL, "sh]op"))
+}
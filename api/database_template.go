@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// DatabaseTemplate is the API message for a database template.
+// The template is chosen in the create database issue, and its extensions and statement are applied
+// right after creating the database as part of the baseline migration.
+type DatabaseTemplate struct {
+	ID int `jsonapi:"primary,databaseTemplate"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Domain specific fields
+	Name        string  `jsonapi:"attr,name"`
+	Engine      db.Type `jsonapi:"attr,engine"`
+	Description string  `jsonapi:"attr,description"`
+	Statement   string  `jsonapi:"attr,statement"`
+	// Labels is a json-encoded string from a list of DatabaseLabel.
+	Labels string `jsonapi:"attr,labels"`
+	// ExtensionList is only applicable to Postgres.
+	ExtensionList []string `jsonapi:"attr,extensionList"`
+}
+
+// DatabaseTemplateCreate is the API message for creating a database template.
+type DatabaseTemplateCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	Name          string   `jsonapi:"attr,name"`
+	Engine        db.Type  `jsonapi:"attr,engine"`
+	Description   string   `jsonapi:"attr,description"`
+	Statement     string   `jsonapi:"attr,statement"`
+	Labels        string   `jsonapi:"attr,labels"`
+	ExtensionList []string `jsonapi:"attr,extensionList"`
+}
+
+// DatabaseTemplateFind is the API message for finding database templates.
+type DatabaseTemplateFind struct {
+	ID *int
+
+	// Domain specific fields
+	Name   *string
+	Engine *db.Type
+}
+
+func (find *DatabaseTemplateFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DatabaseTemplatePatch is the API message for patching a database template.
+type DatabaseTemplatePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name        *string `jsonapi:"attr,name"`
+	Description *string `jsonapi:"attr,description"`
+	Statement   *string `jsonapi:"attr,statement"`
+	Labels      *string `jsonapi:"attr,labels"`
+	// ExtensionList is the comma-separated extension list.
+	ExtensionList *string `jsonapi:"attr,extensionList"`
+}
+
+// DatabaseTemplateDelete is the API message for deleting a database template.
+type DatabaseTemplateDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// ValidateDatabaseTemplate validates the engine, the labels and the extensions of the database template.
+func ValidateDatabaseTemplate(engine db.Type, labels string, extensionList []string) error {
+	switch engine {
+	case db.MySQL, db.Postgres, db.TiDB, db.ClickHouse, db.Snowflake, db.SQLite, db.MSSQL, db.MongoDB, db.MariaDB:
+	default:
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid database template engine %q", engine)}
+	}
+	if labels != "" {
+		var labelList []*DatabaseLabel
+		if err := json.Unmarshal([]byte(labels), &labelList); err != nil {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid database template labels %q, error: %w", labels, err)}
+		}
+	}
+	if len(extensionList) > 0 && engine != db.Postgres {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("extensions are only applicable to Postgres, got %s", engine)}
+	}
+	for _, extension := range extensionList {
+		if extension == "" {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("extension name must not be empty")}
+		}
+	}
+	return nil
+}
+
+// GetStatement returns the statement applying the template to a new database, the extensions are created before the DDL.
+func (t *DatabaseTemplate) GetStatement() string {
+	var stmtList []string
+	for _, extension := range t.ExtensionList {
+		stmtList = append(stmtList, fmt.Sprintf(`CREATE EXTENSION IF NOT EXISTS "%s";`, strings.ReplaceAll(extension, `"`, `""`)))
+	}
+	if statement := strings.TrimSpace(t.Statement); statement != "" {
+		stmtList = append(stmtList, statement)
+	}
+	return strings.Join(stmtList, "\n")
+}
+
+// MergeLabels returns the json-encoded labels of the template overridden by the given labels with the same keys.
+func (t *DatabaseTemplate) MergeLabels(labels string) (string, error) {
+	var templateLabelList, labelList []*DatabaseLabel
+	if t.Labels != "" {
+		if err := json.Unmarshal([]byte(t.Labels), &templateLabelList); err != nil {
+			return "", err
+		}
+	}
+	if labels != "" {
+		if err := json.Unmarshal([]byte(labels), &labelList); err != nil {
+			return "", err
+		}
+	}
+	if len(templateLabelList) == 0 {
+		return labels, nil
+	}
+
+	keys := make(map[string]bool)
+	for _, label := range labelList {
+		keys[label.Key] = true
+	}
+	mergedList := labelList
+	for _, label := range templateLabelList {
+		if !keys[label.Key] {
+			mergedList = append(mergedList, label)
+		}
+	}
+	bytes, err := json.Marshal(mergedList)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
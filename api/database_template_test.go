@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestDatabaseTemplate(t *testing.T) {
+	template := &DatabaseTemplate{
+		Engine:        db.Postgres,
+		Statement:     "  CREATE TABLE audit(id INT);\n",
+		Labels:        `[{"key":"bb.location","value":"us-central1"},{"key":"bb.tenant","value":"default"}]`,
+		ExtensionList: []string{"pgcrypto", "uuid-ossp"},
+	}
+	require.Equal(t, `CREATE EXTENSION IF NOT EXISTS "pgcrypto";`+"\n"+
+		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`+"\n"+
+		"CREATE TABLE audit(id INT);", template.GetStatement())
+
+	labels, err := template.MergeLabels(`[{"key":"bb.tenant","value":"acme"}]`)
+	require.NoError(t, err)
+	require.Equal(t, `[{"key":"bb.tenant","value":"acme"},{"key":"bb.location","value":"us-central1"}]`, labels)
+
+	labels, err = (&DatabaseTemplate{}).MergeLabels(`[{"key":"bb.tenant","value":"acme"}]`)
+	require.NoError(t, err)
+	require.Equal(t, `[{"key":"bb.tenant","value":"acme"}]`, labels)
+
+	require.NoError(t, ValidateDatabaseTemplate(db.Postgres, template.Labels, template.ExtensionList))
+	require.Equal(t, common.Invalid, common.ErrorCode(ValidateDatabaseTemplate(db.MySQL, "", []string{"pgcrypto"})))
+	require.Equal(t, common.Invalid, common.ErrorCode(ValidateDatabaseTemplate(db.MySQL, "not json", nil)))
+	require.Equal(t, common.Invalid, common.ErrorCode(ValidateDatabaseTemplate("ORACLE", "", nil)))
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DatabaseAssignmentRule is the API message for a database assignment rule.
+// When the instance sync discovers a new database, the database is assigned to the project of the first
+// matching rule in the ascending ID order, otherwise it's assigned to the default project.
+type DatabaseAssignmentRule struct {
+	ID int `jsonapi:"primary,databaseAssignmentRule"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID int `jsonapi:"attr,projectId"`
+	// InstanceID is the instance the database belongs to, nil matches any instance.
+	InstanceID *int `jsonapi:"attr,instanceId,omitempty"`
+
+	// Domain specific fields
+	// NamePattern is the regular expression the database name must match, empty matches any name.
+	NamePattern string `jsonapi:"attr,namePattern"`
+	// LabelSelector is the JSON encoded LabelSelector. The requirements are matched against the labels
+	// known when the database is discovered, i.e. the environment label "bb.environment" derived from the instance.
+	// Different from the deployment config, an empty selector matches any database.
+	LabelSelector string `jsonapi:"attr,labelSelector"`
+}
+
+// DatabaseAssignmentRuleCreate is the API message for creating a database assignment rule.
+type DatabaseAssignmentRuleCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	ProjectID  int
+	InstanceID *int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	NamePattern   string `jsonapi:"attr,namePattern"`
+	LabelSelector string `jsonapi:"attr,labelSelector"`
+}
+
+// DatabaseAssignmentRuleFind is the API message for finding database assignment rules.
+type DatabaseAssignmentRuleFind struct {
+	ID *int
+
+	// Related fields
+	ProjectID *int
+}
+
+func (find *DatabaseAssignmentRuleFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DatabaseAssignmentRulePatch is the API message for patching a database assignment rule.
+type DatabaseAssignmentRulePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	// InstanceID 0 removes the instance restriction.
+	InstanceID *int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	NamePattern   *string `jsonapi:"attr,namePattern"`
+	LabelSelector *string `jsonapi:"attr,labelSelector"`
+}
+
+// DatabaseAssignmentRuleDelete is the API message for deleting a database assignment rule.
+type DatabaseAssignmentRuleDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DBFunction is the API message for a database function or stored procedure.
+type DBFunction struct {
+	ID int `jsonapi:"primary,dbFunction"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int
+	Database   *Database `jsonapi:"relation,database"`
+
+	// Domain specific fields
+	Name   string `jsonapi:"attr,name"`
+	Schema string `jsonapi:"attr,schema"`
+	// Arguments is the argument signature identifying the function among the overloaded ones.
+	Arguments string `jsonapi:"attr,arguments"`
+	// Type is either FUNCTION or PROCEDURE.
+	Type       string `jsonapi:"attr,type"`
+	Language   string `jsonapi:"attr,language"`
+	Definition string `jsonapi:"attr,definition"`
+}
+
+// DBFunctionCreate is the API message for creating a database function.
+type DBFunctionCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name       string
+	Schema     string
+	Arguments  string
+	Type       string
+	Language   string
+	Definition string
+}
+
+// DBFunctionFind is the API message for finding database functions.
+type DBFunctionFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+}
+
+func (find *DBFunctionFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DBFunctionDelete is the API message for deleting a database function.
+type DBFunctionDelete struct {
+	ID int
+}
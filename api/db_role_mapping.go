@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DatabaseRoleMapping is the API message for mapping a database role to a Bytebase principal.
+// The principal is considered the owner of the database objects owned by the role, e.g. the tables
+// whose tableowner is the role in Postgres.
+type DatabaseRoleMapping struct {
+	ID int `jsonapi:"primary,databaseRoleMapping"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	InstanceID  int `jsonapi:"attr,instanceId"`
+	PrincipalID int
+	Principal   *Principal `jsonapi:"relation,principal"`
+
+	// Domain specific fields
+	RoleName string `jsonapi:"attr,roleName"`
+}
+
+// DatabaseRoleMappingCreate is the API message for creating a database role mapping.
+type DatabaseRoleMappingCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	InstanceID  int
+	PrincipalID int `jsonapi:"attr,principalId"`
+
+	// Domain specific fields
+	RoleName string `jsonapi:"attr,roleName"`
+}
+
+// DatabaseRoleMappingFind is the API message for finding database role mappings.
+type DatabaseRoleMappingFind struct {
+	ID *int
+
+	// Related fields
+	InstanceID  *int
+	PrincipalID *int
+
+	// Domain specific fields
+	RoleName *string
+}
+
+func (find *DatabaseRoleMappingFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// DatabaseRoleMappingPatch is the API message for patching a database role mapping.
+type DatabaseRoleMappingPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	PrincipalID *int `jsonapi:"attr,principalId"`
+}
+
+// DatabaseRoleMappingDelete is the API message for deleting a database role mapping.
+type DatabaseRoleMappingDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
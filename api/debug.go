@@ -9,3 +9,23 @@ type Debug struct {
 type DebugPatch struct {
 	IsDebug bool `jsonapi:"attr,isDebug"`
 }
+
+// LogConfig is the API message for the runtime log config.
+type LogConfig struct {
+	// Level is the global log level, e.g. debug, info, warn and error.
+	Level string `jsonapi:"attr,level"`
+	// SubsystemLevel is the JSON encoded map from the subsystem, i.e. scheduler, sync and vcs, to its level.
+	// The subsystem not in the map follows the global level.
+	SubsystemLevel string `jsonapi:"attr,subsystemLevel"`
+	// Sink is the JSON encoded list of the additional sinks besides the console, e.g. [{"type":"FILE","path":"/var/log/bytebase.log"}].
+	Sink string `jsonapi:"attr,sink"`
+}
+
+// LogConfigPatch is the API message for patching the runtime log config.
+type LogConfigPatch struct {
+	Level *string `jsonapi:"attr,level"`
+	// SubsystemLevel replaces the whole subsystem level map if specified.
+	SubsystemLevel *string `jsonapi:"attr,subsystemLevel"`
+	// Sink replaces the whole sink list if specified.
+	Sink *string `jsonapi:"attr,sink"`
+}
@@ -0,0 +1,46 @@
+package api
+
+// ERDiagram is the API message for the entity-relationship diagram of a database.
+// It's built from the synced metadata and is returned in plain JSON so that external tools can consume it.
+// Nodes are sorted by the table name, and edges are sorted by the source table and the foreign key name.
+type ERDiagram struct {
+	DatabaseID   int             `json:"databaseId"`
+	DatabaseName string          `json:"databaseName"`
+	NodeList     []ERDiagramNode `json:"nodeList"`
+	EdgeList     []ERDiagramEdge `json:"edgeList"`
+}
+
+// ERDiagramNode is the API message for a table in the ER diagram.
+type ERDiagramNode struct {
+	Table      string            `json:"table"`
+	Comment    string            `json:"comment"`
+	ColumnList []ERDiagramColumn `json:"columnList"`
+}
+
+// ERDiagramColumn is the API message for a column of a table in the ER diagram.
+type ERDiagramColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Primary  bool   `json:"primary"`
+}
+
+// ERDiagramEdge is the API message for a foreign key in the ER diagram.
+type ERDiagramEdge struct {
+	Name           string                `json:"name"`
+	FromTable      string                `json:"fromTable"`
+	ToTable        string                `json:"toTable"`
+	ColumnPairList []ERDiagramColumnPair `json:"columnPairList"`
+	// OnDelete and OnUpdate are the referential actions, e.g. CASCADE.
+	OnDelete string `json:"onDelete"`
+	OnUpdate string `json:"onUpdate"`
+	// External is true if the referenced table is not in the database, e.g. a MySQL cross-database foreign key.
+	// There is no node for such table.
+	External bool `json:"external"`
+}
+
+// ERDiagramColumnPair is the API message for a pair of the referencing and the referenced columns.
+type ERDiagramColumnPair struct {
+	FromColumn string `json:"fromColumn"`
+	ToColumn   string `json:"toColumn"`
+}
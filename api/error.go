@@ -0,0 +1,13 @@
+package api
+
+import "github.com/bytebase/bytebase/common"
+
+// ErrorResponse is the API message for the error response.
+type ErrorResponse struct {
+	// Message is the human readable error message.
+	Message string `json:"message"`
+	// Code is the machine readable error code, clients can tell the user errors, e.g. common.Invalid and common.NotFound, from common.Internal.
+	Code common.Code `json:"code"`
+	// RequestID is the ID of the failed request, which is also included in the server logs.
+	RequestID string `json:"requestId"`
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/advisor/external"
+)
+
+// ExternalAdvisorDefaultTimeout is the default timeout of each call to the external policy engine.
+const ExternalAdvisorDefaultTimeout = 10 * time.Second
+
+// ExternalAdvisorSetting is the value of the workspace external advisor setting.
+// The external policy engine is consulted with the parsed statements and their context during the task checks,
+// and its ERROR advices block the tasks like the SQL review, so the organization rules can be codified outside Bytebase.
+type ExternalAdvisorSetting struct {
+	// Engine is the type of the external policy engine, e.g. "OPA".
+	Engine external.Engine `json:"engine"`
+	// URL is the endpoint of the policy, e.g. http://localhost:8181/v1/data/bytebase/advise for OPA, empty disables the external advisor.
+	URL string `json:"url"`
+	// TimeoutSeconds is the timeout of each call, 0 means ExternalAdvisorDefaultTimeout.
+	TimeoutSeconds int `json:"timeoutSeconds"`
+	// FailOpen warns instead of blocking the tasks when the external policy engine fails, e.g. it's unreachable.
+	FailOpen bool `json:"failOpen"`
+}
+
+// Enabled returns whether the external advisor is enabled.
+func (s *ExternalAdvisorSetting) Enabled() bool {
+	return s.URL != ""
+}
+
+// GetTimeout returns the timeout of each call to the external policy engine.
+func (s *ExternalAdvisorSetting) GetTimeout() time.Duration {
+	if s.TimeoutSeconds == 0 {
+		return ExternalAdvisorDefaultTimeout
+	}
+	return time.Duration(s.TimeoutSeconds) * time.Second
+}
+
+// Validate validates the external advisor setting.
+func (s *ExternalAdvisorSetting) Validate() error {
+	if !s.Enabled() {
+		return nil
+	}
+	switch s.Engine {
+	case external.OPA, external.HTTP:
+	default:
+		return fmt.Errorf("invalid external policy engine %q", s.Engine)
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid external policy URL %q", s.URL)
+	}
+	if s.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeout seconds must not be negative, got %d", s.TimeoutSeconds)
+	}
+	return nil
+}
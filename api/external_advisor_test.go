@@ -0,0 +1,25 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor/external"
+)
+
+func TestExternalAdvisorSettingValidate(t *testing.T) {
+	require.NoError(t, (&ExternalAdvisorSetting{}).Validate())
+	require.NoError(t, (&ExternalAdvisorSetting{Engine: external.OPA, URL: "http://localhost:8181/v1/data/bytebase/advise"}).Validate())
+	require.NoError(t, (&ExternalAdvisorSetting{Engine: external.HTTP, URL: "https://policy.example.com/advise", TimeoutSeconds: 30, FailOpen: true}).Validate())
+	require.Error(t, (&ExternalAdvisorSetting{Engine: "REGO", URL: "http://localhost:8181"}).Validate())
+	require.Error(t, (&ExternalAdvisorSetting{Engine: external.OPA, URL: "localhost:8181"}).Validate())
+	require.Error(t, (&ExternalAdvisorSetting{Engine: external.OPA, URL: "ftp://localhost"}).Validate())
+	require.Error(t, (&ExternalAdvisorSetting{Engine: external.OPA, URL: "http://localhost:8181", TimeoutSeconds: -1}).Validate())
+}
+
+func TestExternalAdvisorSettingGetTimeout(t *testing.T) {
+	require.Equal(t, ExternalAdvisorDefaultTimeout, (&ExternalAdvisorSetting{}).GetTimeout())
+	require.Equal(t, 30*time.Second, (&ExternalAdvisorSetting{TimeoutSeconds: 30}).GetTimeout())
+}
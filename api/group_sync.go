@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// GroupSyncSetting is the value of the workspace group sync setting.
+// On each login via a VCS, the project membership of the user is kept in sync with the VCS groups of the user.
+type GroupSyncSetting struct {
+	MappingList []*GroupMapping `json:"mappingList"`
+}
+
+// GroupMapping grants the members of a VCS group a role in a project.
+type GroupMapping struct {
+	// VCSID is the ID of the VCS the user logins via.
+	VCSID int `json:"vcsId"`
+	// Group is the full path of the GitLab group, or the GitHub team in the form of "<org>/<team>".
+	Group     string             `json:"group"`
+	ProjectID int                `json:"projectId"`
+	Role      common.ProjectRole `json:"role"`
+}
+
+// Validate validates the group sync setting.
+func (s *GroupSyncSetting) Validate() error {
+	mappingSet := make(map[string]bool)
+	for _, mapping := range s.MappingList {
+		if mapping.VCSID <= 0 {
+			return fmt.Errorf("invalid VCS ID %d", mapping.VCSID)
+		}
+		if strings.TrimSpace(mapping.Group) == "" {
+			return fmt.Errorf("empty group of VCS %d", mapping.VCSID)
+		}
+		if mapping.ProjectID <= 0 || mapping.ProjectID == DefaultProjectID {
+			return fmt.Errorf("invalid project ID %d of group %q", mapping.ProjectID, mapping.Group)
+		}
+		if mapping.Role != common.ProjectOwner && mapping.Role != common.ProjectDeveloper {
+			return fmt.Errorf("invalid role %q of group %q", mapping.Role, mapping.Group)
+		}
+		key := fmt.Sprintf("%d/%s/%d", mapping.VCSID, strings.ToLower(mapping.Group), mapping.ProjectID)
+		if mappingSet[key] {
+			return fmt.Errorf("duplicate mapping of group %q to project %d", mapping.Group, mapping.ProjectID)
+		}
+		mappingSet[key] = true
+	}
+	return nil
+}
+
+// HasVCS returns true if any group of the VCS is mapped.
+func (s *GroupSyncSetting) HasVCS(vcsID int) bool {
+	for _, mapping := range s.MappingList {
+		if mapping.VCSID == vcsID {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupProjectRole is the project role granted by the groups.
+type GroupProjectRole struct {
+	Role common.ProjectRole
+	// GroupList is the groups granting the role.
+	GroupList []string
+}
+
+// GetGroupProjectRoleMap returns the project roles granted by the groups of the user in the VCS, keyed by the project ID.
+// The groups are matched case-insensitively, and the owner role wins if the groups grant different roles in a project.
+func (s *GroupSyncSetting) GetGroupProjectRoleMap(vcsID int, groupList []string) map[int]*GroupProjectRole {
+	groupSet := make(map[string]bool)
+	for _, group := range groupList {
+		groupSet[strings.ToLower(group)] = true
+	}
+	roleMap := make(map[int]*GroupProjectRole)
+	for _, mapping := range s.MappingList {
+		if mapping.VCSID != vcsID || !groupSet[strings.ToLower(mapping.Group)] {
+			continue
+		}
+		role, ok := roleMap[mapping.ProjectID]
+		if !ok {
+			roleMap[mapping.ProjectID] = &GroupProjectRole{
+				Role:      mapping.Role,
+				GroupList: []string{mapping.Group},
+			}
+			continue
+		}
+		if mapping.Role == common.ProjectOwner && role.Role != common.ProjectOwner {
+			role.Role = common.ProjectOwner
+			role.GroupList = nil
+		}
+		if mapping.Role == role.Role {
+			role.GroupList = append(role.GroupList, mapping.Group)
+		}
+	}
+	return roleMap
+}
+
+// ProjectMemberGroupSyncPayload is the payload of the project members synced from the VCS groups.
+// The members share the BYTEBASE role provider with the ones added by hand, the payload tells them apart.
+type ProjectMemberGroupSyncPayload struct {
+	// VCSID is the ID of the VCS of the groups, 0 means the member is added by hand.
+	VCSID int `json:"vcsId,omitempty"`
+	// GroupList is the groups granting the role.
+	GroupList  []string `json:"groupList,omitempty"`
+	LastSyncTs int64    `json:"lastSyncTs,omitempty"`
+}
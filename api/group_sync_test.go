@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestGroupSyncSettingValidate(t *testing.T) {
+	require.NoError(t, (&GroupSyncSetting{}).Validate())
+	require.NoError(t, (&GroupSyncSetting{MappingList: []*GroupMapping{
+		{VCSID: 1, Group: "acme/dba", ProjectID: 101, Role: common.ProjectOwner},
+		{VCSID: 1, Group: "acme/dev", ProjectID: 101, Role: common.ProjectDeveloper},
+	}}).Validate())
+	require.Error(t, (&GroupSyncSetting{MappingList: []*GroupMapping{
+		{VCSID: 1, Group: "acme/dba", ProjectID: DefaultProjectID, Role: common.ProjectOwner},
+	}}).Validate())
+	require.Error(t, (&GroupSyncSetting{MappingList: []*GroupMapping{
+		{VCSID: 1, Group: "acme/dba", ProjectID: 101, Role: "DBA"},
+	}}).Validate())
+	require.Error(t, (&GroupSyncSetting{MappingList: []*GroupMapping{
+		{VCSID: 1, Group: "acme/dba", ProjectID: 101, Role: common.ProjectOwner},
+		{VCSID: 1, Group: "ACME/dba", ProjectID: 101, Role: common.ProjectDeveloper},
+	}}).Validate())
+}
+
+func TestGetGroupProjectRoleMap(t *testing.T) {
+	setting := &GroupSyncSetting{MappingList: []*GroupMapping{
+		{VCSID: 1, Group: "acme/dev", ProjectID: 101, Role: common.ProjectDeveloper},
+		{VCSID: 1, Group: "acme/dba", ProjectID: 101, Role: common.ProjectOwner},
+		{VCSID: 1, Group: "acme/qa", ProjectID: 101, Role: common.ProjectDeveloper},
+		{VCSID: 1, Group: "acme/dev", ProjectID: 102, Role: common.ProjectDeveloper},
+		{VCSID: 2, Group: "acme/dev", ProjectID: 103, Role: common.ProjectOwner},
+	}}
+	require.Equal(t, map[int]*GroupProjectRole{
+		101: {Role: common.ProjectOwner, GroupList: []string{"acme/dba"}},
+		102: {Role: common.ProjectDeveloper, GroupList: []string{"acme/dev"}},
+	}, setting.GetGroupProjectRoleMap(1, []string{"Acme/Dev", "acme/dba", "acme/qa"}))
+	require.Equal(t, map[int]*GroupProjectRole{}, setting.GetGroupProjectRoleMap(1, []string{"acme/ops"}))
+}
@@ -22,6 +22,8 @@ type Instance struct {
 	// Related fields
 	EnvironmentID int
 	Environment   *Environment `jsonapi:"relation,environment"`
+	// AgentID is the runner agent executing the work against the instance, nil if the Bytebase server connects to the instance directly.
+	AgentID *int `jsonapi:"attr,agentId,omitempty"`
 	// Anomalies are stored in a separate table, but just return here for convenience
 	AnomalyList    []*Anomaly    `jsonapi:"relation,anomalyList"`
 	DataSourceList []*DataSource `jsonapi:"relation,dataSourceList"`
@@ -30,12 +32,17 @@ type Instance struct {
 	Name          string  `jsonapi:"attr,name"`
 	Engine        db.Type `jsonapi:"attr,engine"`
 	EngineVersion string  `jsonapi:"attr,engineVersion"`
-	ExternalLink  string  `jsonapi:"attr,externalLink"`
-	Host          string  `jsonapi:"attr,host"`
-	Port          string  `jsonapi:"attr,port"`
-	Username      string  `jsonapi:"attr,username"`
+	// Dialect is the server speaking the protocol of the engine detected by the instance sync, e.g. Redshift added as Postgres.
+	// It's empty for the engine itself.
+	Dialect      db.Dialect `jsonapi:"attr,dialect"`
+	ExternalLink string     `jsonapi:"attr,externalLink"`
+	Host         string     `jsonapi:"attr,host"`
+	Port         string     `jsonapi:"attr,port"`
+	Username     string     `jsonapi:"attr,username"`
 	// Password is not returned to the client
 	Password string
+	// TagList is a json-encoded string from a list of tags inherited by the databases of the instance, e.g. "["pci","gdpr"]".
+	TagList string `jsonapi:"attr,tagList"`
 }
 
 // InstanceCreate is the API message for creating an instance.
@@ -73,8 +80,10 @@ type InstanceFind struct {
 
 	// Related fields
 	EnvironmentID *int
+	AgentID       *int
 
 	// Domain specific fields
+	Name *string
 	Host *string
 	Port *string
 }
@@ -102,12 +111,26 @@ type InstancePatch struct {
 	ExternalLink  *string `jsonapi:"attr,externalLink"`
 	Host          *string `jsonapi:"attr,host"`
 	Port          *string `jsonapi:"attr,port"`
+	// Engine is detected when syncing the instance, e.g. MariaDB added as MySQL.
+	Engine *db.Type
+	// Dialect is detected when syncing the instance, e.g. Redshift added as Postgres.
+	Dialect *db.Dialect
+	// AgentID assigns the instance to a runner agent, 0 unassigns it.
+	AgentID *int `jsonapi:"attr,agentId"`
+	// TagList is a json-encoded string from a list of tags, e.g. "["pci","gdpr"]".
+	TagList *string `jsonapi:"attr,tagList"`
 	// If true, syncs the schema after patching the instance. The client
 	// may set to false if the target instance contains too many databases
 	// to avoid the request timeout.
 	SyncSchema bool `jsonapi:"attr,syncSchema"`
 }
 
+// GetInstanceCapabilities returns the capabilities of the instance, which are narrowed down by the dialect detected by the instance sync.
+// The features are gated on it rather than the engine alone, e.g. Redshift added as Postgres can't be backed up.
+func GetInstanceCapabilities(instance *Instance) db.Capabilities {
+	return db.GetDialectCapabilities(instance.Engine, instance.Dialect)
+}
+
 // DataSourceFromInstanceWithType gets a typed data source from a instance.
 func DataSourceFromInstanceWithType(instance *Instance, dataSourceType DataSourceType) *DataSource {
 	for _, dataSource := range instance.DataSourceList {
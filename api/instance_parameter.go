@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// InstanceParameter is the API message for a synced instance parameter value.
+// A new value is recorded only when the parameter changes, so the values of a parameter are its history.
+type InstanceParameter struct {
+	ID int `jsonapi:"primary,instanceParameter"`
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	InstanceID int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	Name  string `jsonapi:"attr,name"`
+	Value string `jsonapi:"attr,value"`
+}
+
+// InstanceParameterCreate is the API message for recording an instance parameter value.
+type InstanceParameterCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	Name  string
+	Value string
+}
+
+// InstanceParameterFind is the API message for finding instance parameter values.
+type InstanceParameterFind struct {
+	// Related fields
+	InstanceID *int
+
+	// Domain specific fields
+	Name *string
+	// Latest only returns the latest value of each parameter.
+	Latest bool
+}
+
+func (find *InstanceParameterFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// ParameterDrift is a parameter whose value drifts from the environment baseline.
+type ParameterDrift struct {
+	Name   string `json:"name"`
+	Expect string `json:"expect"`
+	// Actual is empty if the parameter isn't synced from the instance.
+	Actual string `json:"actual"`
+}
+
+// GetParameterDriftList returns the parameters of the instance drifting from the baseline of the engine.
+func GetParameterDriftList(engine db.Type, baseline *ParameterBaselinePolicy, parameterList []*InstanceParameter) []*ParameterDrift {
+	valueMap := make(map[string]string)
+	for _, parameter := range parameterList {
+		valueMap[strings.ToLower(parameter.Name)] = parameter.Value
+	}
+	var driftList []*ParameterDrift
+	for _, expected := range baseline.ParameterList {
+		if expected.Engine != engine {
+			continue
+		}
+		actual, ok := valueMap[strings.ToLower(expected.Name)]
+		if ok && equalParameterValue(expected.Value, actual) {
+			continue
+		}
+		driftList = append(driftList, &ParameterDrift{
+			Name:   expected.Name,
+			Expect: expected.Value,
+			Actual: actual,
+		})
+	}
+	sort.Slice(driftList, func(i, j int) bool {
+		return driftList[i].Name < driftList[j].Name
+	})
+	return driftList
+}
+
+// equalParameterValue compares the values case-insensitively.
+// The comma-separated values such as sql_mode are compared regardless of the order.
+func equalParameterValue(a, b string) bool {
+	split := func(s string) []string {
+		var list []string
+		for _, v := range strings.Split(strings.ToLower(s), ",") {
+			list = append(list, strings.TrimSpace(v))
+		}
+		sort.Strings(list)
+		return list
+	}
+	return strings.Join(split(a), ",") == strings.Join(split(b), ",")
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetParameterDriftList(t *testing.T) {
+	baseline := &ParameterBaselinePolicy{
+		ParameterList: []ParameterBaseline{
+			{Engine: db.MySQL, Name: "sql_mode", Value: "STRICT_TRANS_TABLES,NO_ZERO_DATE"},
+			{Engine: db.MySQL, Name: "innodb_flush_log_at_trx_commit", Value: "1"},
+			{Engine: db.MySQL, Name: "slow_query_log", Value: "on"},
+			{Engine: db.MySQL, Name: "gtid_mode", Value: "ON"},
+			{Engine: db.Postgres, Name: "max_connections", Value: "200"},
+		},
+	}
+	parameterList := []*InstanceParameter{
+		{Name: "sql_mode", Value: "NO_ZERO_DATE,STRICT_TRANS_TABLES"},
+		{Name: "innodb_flush_log_at_trx_commit", Value: "2"},
+		{Name: "slow_query_log", Value: "ON"},
+		{Name: "max_connections", Value: "151"},
+	}
+	require.Equal(t, []*ParameterDrift{
+		{Name: "gtid_mode", Expect: "ON", Actual: ""},
+		{Name: "innodb_flush_log_at_trx_commit", Expect: "1", Actual: "2"},
+	}, GetParameterDriftList(db.MySQL, baseline, parameterList))
+
+	require.Equal(t, []*ParameterDrift{
+		{Name: "max_connections", Expect: "200", Actual: "151"},
+	}, GetParameterDriftList(db.Postgres, baseline, parameterList))
+
+	require.Nil(t, GetParameterDriftList(db.TiDB, baseline, parameterList))
+}
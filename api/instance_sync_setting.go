@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultSchemaSyncIntervalMinutes is the interval of the automatic schema sync of the instances without a sync setting.
+	DefaultSchemaSyncIntervalMinutes = 30
+	// MinSchemaSyncIntervalMinutes is the minimal interval of the automatic schema sync.
+	MinSchemaSyncIntervalMinutes = 5
+	// MaxSchemaSyncIntervalMinutes is the maximal interval of the automatic schema sync, which is a week.
+	MaxSchemaSyncIntervalMinutes = 7 * 24 * 60
+)
+
+// SchemaSyncMode is the mode of the schema sync.
+type SchemaSyncMode string
+
+const (
+	// SchemaSyncDeep syncs the instance metadata and the schema of every database.
+	SchemaSyncDeep SchemaSyncMode = "DEEP"
+	// SchemaSyncFast only syncs the instance metadata and the database list, leaving the schemas as of the last deep sync.
+	SchemaSyncFast SchemaSyncMode = "FAST"
+)
+
+// SyncBlackoutWindow is a weekly time range during which the automatic schema sync doesn't run.
+type SyncBlackoutWindow struct {
+	// DayOfWeek is the day of the week in UTC, 0 is Sunday, -1 means every day.
+	DayOfWeek int `json:"dayOfWeek"`
+	// StartHour and EndHour are the hours in UTC, the window is [StartHour, EndHour).
+	StartHour int `json:"startHour"`
+	EndHour   int `json:"endHour"`
+}
+
+// Contains returns true if the time is in the window.
+func (w *SyncBlackoutWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	if w.DayOfWeek != -1 && int(t.Weekday()) != w.DayOfWeek {
+		return false
+	}
+	return t.Hour() >= w.StartHour && t.Hour() < w.EndHour
+}
+
+// InstanceSyncSetting is the API message for the schema sync schedule of an instance.
+type InstanceSyncSetting struct {
+	ID int `jsonapi:"primary,instanceSyncSetting"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	InstanceID int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	// IntervalMinutes is the interval of the automatic schema sync, 0 disables the automatic sync.
+	IntervalMinutes int `jsonapi:"attr,intervalMinutes"`
+	// BlackoutWindowList is the JSON encoded []*SyncBlackoutWindow.
+	BlackoutWindowList string `jsonapi:"attr,blackoutWindowList"`
+}
+
+// InstanceSyncSettingFind is the API message for finding instance sync settings.
+type InstanceSyncSettingFind struct {
+	// Related fields
+	InstanceID *int
+}
+
+func (find *InstanceSyncSettingFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// InstanceSyncSettingUpsert is the API message for upserting the schema sync schedule of an instance.
+// NOTE: We use PATCH for Upsert, this is inspired by https://google.aip.dev/134#patch-and-put
+type InstanceSyncSettingUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	IntervalMinutes    int    `jsonapi:"attr,intervalMinutes"`
+	BlackoutWindowList string `jsonapi:"attr,blackoutWindowList"`
+}
+
+// ParseSyncBlackoutWindowList parses and validates the JSON encoded blackout windows, empty means no window.
+func ParseSyncBlackoutWindowList(s string) ([]*SyncBlackoutWindow, error) {
+	var windowList []*SyncBlackoutWindow
+	if s == "" {
+		return windowList, nil
+	}
+	if err := json.Unmarshal([]byte(s), &windowList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal blackout window list %q, error: %w", s, err)
+	}
+	for _, window := range windowList {
+		if window.DayOfWeek < -1 || window.DayOfWeek > 6 {
+			return nil, fmt.Errorf("invalid day of week %d, expect -1 for every day or 0 to 6 for Sunday to Saturday", window.DayOfWeek)
+		}
+		if window.StartHour < 0 || window.EndHour > 24 || window.StartHour >= window.EndHour {
+			return nil, fmt.Errorf("invalid hour range [%d, %d), expect 0 <= start hour < end hour <= 24", window.StartHour, window.EndHour)
+		}
+	}
+	return windowList, nil
+}
+
+// ValidateSchemaSyncInterval validates the interval of the automatic schema sync.
+func ValidateSchemaSyncInterval(intervalMinutes int) error {
+	if intervalMinutes == 0 {
+		return nil
+	}
+	if intervalMinutes < MinSchemaSyncIntervalMinutes || intervalMinutes > MaxSchemaSyncIntervalMinutes {
+		return fmt.Errorf("invalid interval %d minutes, expect 0 to disable the automatic sync or %d to %d", intervalMinutes, MinSchemaSyncIntervalMinutes, MaxSchemaSyncIntervalMinutes)
+	}
+	return nil
+}
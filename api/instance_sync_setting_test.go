@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSyncBlackoutWindowList(t *testing.T) {
+	windowList, err := ParseSyncBlackoutWindowList("")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(windowList))
+
+	windowList, err = ParseSyncBlackoutWindowList(`[{"dayOfWeek":-1,"startHour":8,"endHour":18},{"dayOfWeek":6,"startHour":0,"endHour":24}]`)
+	require.NoError(t, err)
+	require.Equal(t, []*SyncBlackoutWindow{
+		{DayOfWeek: -1, StartHour: 8, EndHour: 18},
+		{DayOfWeek: 6, StartHour: 0, EndHour: 24},
+	}, windowList)
+
+	for _, s := range []string{
+		`{}`,
+		`[{"dayOfWeek":7,"startHour":8,"endHour":18}]`,
+		`[{"dayOfWeek":-1,"startHour":18,"endHour":8}]`,
+		`[{"dayOfWeek":-1,"startHour":0,"endHour":25}]`,
+	} {
+		_, err := ParseSyncBlackoutWindowList(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestSyncBlackoutWindowContains(t *testing.T) {
+	// 2022-05-23 is a Monday.
+	monday := time.Date(2022, 5, 23, 9, 30, 0, 0, time.UTC)
+	everyDay := &SyncBlackoutWindow{DayOfWeek: -1, StartHour: 9, EndHour: 10}
+	require.True(t, everyDay.Contains(monday))
+	require.True(t, everyDay.Contains(monday.AddDate(0, 0, 3)))
+	require.False(t, everyDay.Contains(monday.Add(time.Hour)))
+
+	sunday := &SyncBlackoutWindow{DayOfWeek: 0, StartHour: 0, EndHour: 24}
+	require.False(t, sunday.Contains(monday))
+	require.True(t, sunday.Contains(monday.AddDate(0, 0, -1)))
+}
+
+func TestValidateSchemaSyncInterval(t *testing.T) {
+	require.NoError(t, ValidateSchemaSyncInterval(0))
+	require.NoError(t, ValidateSchemaSyncInterval(DefaultSchemaSyncIntervalMinutes))
+	require.Error(t, ValidateSchemaSyncInterval(1))
+	require.Error(t, ValidateSchemaSyncInterval(-30))
+	require.Error(t, ValidateSchemaSyncInterval(MaxSchemaSyncIntervalMinutes+1))
+}
@@ -37,6 +37,22 @@ const (
 	IssueDataSourceRequest IssueType = "bb.issue.data-source.request"
 	// IssueDatabasePITR is the issue type for performing a Point-in-time Recovery.
 	IssueDatabasePITR IssueType = "bb.issue.database.pitr"
+	// IssueDatabaseCharsetConvert is the issue type for converting the character set of MySQL tables.
+	IssueDatabaseCharsetConvert IssueType = "bb.issue.database.charset.convert"
+	// IssueDatabaseColumnRename is the issue type for renaming a column without downtime.
+	IssueDatabaseColumnRename IssueType = "bb.issue.database.column.rename"
+	// IssueDatabaseColumnAdd is the issue type for adding a NOT NULL column with a default value to a large table.
+	IssueDatabaseColumnAdd IssueType = "bb.issue.database.column.add"
+	// IssueDatabaseDrop is the issue type for dropping a database after taking a final backup.
+	IssueDatabaseDrop IssueType = "bb.issue.database.drop"
+	// IssueDatabaseDataValidate is the issue type for comparing the data of a database with its source, e.g. after a restore or a clone.
+	IssueDatabaseDataValidate IssueType = "bb.issue.database.data.validate"
+	// IssueDatabaseAccessChange is the issue type for changing the users and the grants of a database.
+	IssueDatabaseAccessChange IssueType = "bb.issue.database.access.change"
+	// IssueInstanceParameterChange is the issue type for changing the parameters of an instance.
+	IssueInstanceParameterChange IssueType = "bb.issue.instance.parameter.change"
+	// IssueInstanceUpgradeCheck is the issue type for checking the readiness of the Postgres major version upgrade.
+	IssueInstanceUpgradeCheck IssueType = "bb.issue.instance.upgrade.check"
 )
 
 // IssueFieldID is the field ID for an issue.
@@ -115,6 +131,9 @@ type IssueCreate struct {
 
 	// ValidateOnly validates the request and previews the review, but does not actually post it.
 	ValidateOnly bool `jsonapi:"attr,validateOnly"`
+	// IdempotencyKey is the client token identifying the creation request of the creator.
+	// Value is assigned from the Idempotency-Key header passed by the client.
+	IdempotencyKey string
 }
 
 // CreateDatabaseContext is the issue create context for creating a database.
@@ -136,6 +155,15 @@ type CreateDatabaseContext struct {
 	// Labels is a json-encoded string from a list of DatabaseLabel.
 	// See definition in api.Database.
 	Labels string `jsonapi:"attr,labels,omitempty"`
+	// TemplateID is the optional ID of the database template applied right after creating the database.
+	TemplateID int `json:"templateId"`
+	// Tablespace is the default tablespace of the database. This is only applicable to Postgres.
+	Tablespace string `json:"tablespace"`
+	// ConnectionLimit is the maximum number of concurrent connections to the database, 0 means no limit.
+	// This is only applicable to Postgres.
+	ConnectionLimit int `json:"connectionLimit"`
+	// ExtensionList is the extensions created right after creating the database. This is only applicable to Postgres.
+	ExtensionList []string `json:"extensionList"`
 }
 
 // UpdateSchemaDetail is the detail of updating database schema.
@@ -147,8 +175,29 @@ type UpdateSchemaDetail struct {
 	DatabaseName string `json:"databaseName"`
 	// Statement is the statement to update database schema.
 	Statement string `json:"statement"`
+	// DownStatement is the optional statement reverting the Statement, it's recorded in the migration history for the revert issue.
+	DownStatement string `json:"downStatement"`
 	// EarliestAllowedTs the earliest execution time of the change at system local Unix timestamp in seconds.
 	EarliestAllowedTs int64 `jsonapi:"attr,earliestAllowedTs"`
+	// Verification is the optional query verifying the database after the change is applied.
+	Verification *TaskVerification `json:"verification,omitempty"`
+	// MigrationType overrides the MigrationType of the UpdateSchemaContext, only MIGRATE and DATA are allowed.
+	// It allows an issue to both alter the schema and change the data of a database, the details of the same database are executed in the declared order.
+	MigrationType db.MigrationType `json:"migrationType,omitempty"`
+	// SheetID and SheetVersion optionally refer to the reviewed version of a sheet in the project.
+	// If set, the Statement is taken from the sheet version so that it's traceable to the sheet revision.
+	SheetID      int `json:"sheetId,omitempty"`
+	SheetVersion int `json:"sheetVersion,omitempty"`
+	// SessionSetting is the optional session settings applied before executing the Statement.
+	SessionSetting *db.SessionSetting `json:"sessionSetting,omitempty"`
+}
+
+// GetMigrationType returns the migration type of the detail, falling back to the migration type of the context.
+func (d *UpdateSchemaDetail) GetMigrationType(contextMigrationType db.MigrationType) db.MigrationType {
+	if d.MigrationType != "" {
+		return d.MigrationType
+	}
+	return contextMigrationType
 }
 
 // UpdateSchemaContext is the issue create context for updating database schema.
@@ -160,6 +209,10 @@ type UpdateSchemaContext struct {
 	DetailList []*UpdateSchemaDetail `json:"updateSchemaDetailList"`
 	// VCSPushEvent is the event information for VCS push.
 	VCSPushEvent *vcs.PushEvent
+	// ChangeSet is whether the databases must be updated together, see ChangeSet.
+	ChangeSet bool `json:"changeSet"`
+	// FormatStatement is whether to format the statements in the dialect of the database engines on creating the issue.
+	FormatStatement bool `json:"formatStatement"`
 }
 
 // UpdateSchemaGhostDetail is the detail of updating database schema using gh-ost.
@@ -184,6 +237,86 @@ type UpdateSchemaGhostContext struct {
 	VCSPushEvent *vcs.PushEvent
 }
 
+// CharsetConvertContext is the issue create context for converting the character set of MySQL tables.
+type CharsetConvertContext struct {
+	DatabaseID int `json:"databaseId"`
+	// CharacterSet and Collation are the target character set and collation, default to utf8mb4 and utf8mb4_general_ci.
+	CharacterSet string `json:"characterSet"`
+	Collation    string `json:"collation"`
+	// TableList is the tables to convert. If it's empty, all the tables not in the target character set are converted.
+	TableList []string `json:"tableList"`
+}
+
+// ColumnRenameContext is the issue create context for renaming a column without downtime.
+type ColumnRenameContext struct {
+	DatabaseID int `json:"databaseId"`
+	// TableName is the table name, it's in the form of "schema.table" for PostgreSQL.
+	TableName     string `json:"tableName"`
+	OldColumnName string `json:"oldColumnName"`
+	NewColumnName string `json:"newColumnName"`
+	// ColumnType overrides the synced column type, e.g. "varchar(255)" since PostgreSQL only syncs "character varying".
+	ColumnType string `json:"columnType"`
+	// BatchSize is the number of rows backfilled in a batch, default to DefaultColumnRenameBatchSize.
+	BatchSize int `json:"batchSize"`
+	// WaitPeriodSeconds is the waiting period for deploying the application before swapping the columns
+	// and before dropping the old column.
+	WaitPeriodSeconds int64 `json:"waitPeriodSeconds"`
+}
+
+// ColumnAddContext is the issue create context for adding a NOT NULL column with a default value to a large table.
+type ColumnAddContext struct {
+	DatabaseID int `json:"databaseId"`
+	// TableName is the table name, it's in the form of "schema.table" for PostgreSQL.
+	TableName  string `json:"tableName"`
+	ColumnName string `json:"columnName"`
+	ColumnType string `json:"columnType"`
+	// DefaultValue is the SQL expression of the default value, e.g. 0 or 'active'. It's also used to backfill the existing rows.
+	DefaultValue string `json:"defaultValue"`
+	// BatchSize is the number of rows backfilled in a batch, default to DefaultColumnAddBatchSize.
+	BatchSize int `json:"batchSize"`
+}
+
+// DropDatabaseContext is the issue create context for dropping a database.
+type DropDatabaseContext struct {
+	DatabaseID int `json:"databaseId"`
+}
+
+// DataValidateContext is the issue create context for comparing the data of a database with its source.
+type DataValidateContext struct {
+	// DatabaseID is the database to validate, e.g. the restored or cloned database.
+	DatabaseID int `json:"databaseId"`
+	// SourceDatabaseID is the database compared with, it must be of the same engine.
+	SourceDatabaseID int `json:"sourceDatabaseId"`
+	// TableList is the tables to compare, it's in the form of "schema.table" for PostgreSQL.
+	// If it's empty, all the tables in the source database are compared.
+	TableList []string `json:"tableList"`
+	// ChunkSize is the number of rows checksummed in a chunk, default to DefaultDataValidationChunkSize.
+	ChunkSize int `json:"chunkSize"`
+}
+
+// AccessChangeContext is the issue create context for changing the users and the grants.
+type AccessChangeContext struct {
+	// DatabaseID is the database the privileges are on, the users are created on its instance.
+	DatabaseID int             `json:"databaseId"`
+	ChangeList []*AccessChange `json:"changeList"`
+}
+
+// ParameterChangeContext is the issue create context for changing the instance parameters.
+type ParameterChangeContext struct {
+	InstanceID    int                `json:"instanceId"`
+	ParameterList []*ParameterChange `json:"parameterList"`
+}
+
+// UpgradeCheckContext is the issue create context for checking the readiness of the Postgres major version upgrade.
+type UpgradeCheckContext struct {
+	InstanceID int `json:"instanceId"`
+	// TargetVersion is the major version to upgrade to, e.g. "14".
+	// It can be empty if TargetInstanceID is set, then it's the version of the target instance.
+	TargetVersion string `json:"targetVersion"`
+	// TargetInstanceID is the optional instance of the target version to check the extension availability.
+	TargetInstanceID *int `json:"targetInstanceId"`
+}
+
 // PITRContext is the issue create context for performing a PITR in a database.
 type PITRContext struct {
 	DatabaseID int `json:"databaseId"`
@@ -213,7 +346,10 @@ type IssueFind struct {
 	PipelineID *int
 	// Find issue where principalID is either creator, assignee or subscriber
 	PrincipalID *int
-	StatusList  *[]IssueStatus
+	CreatorID   *int
+	// IdempotencyKey finds the issue created by the request with the client token.
+	IdempotencyKey *string
+	StatusList     *[]IssueStatus
 	// If specified, then it will only fetch "Limit" most recently updated issues
 	Limit *int
 }
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// IssueAttachment is the API message for an issue attachment.
+type IssueAttachment struct {
+	ID int `jsonapi:"primary,issueAttachment"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	IssueID int `jsonapi:"attr,issueId"`
+
+	// Domain specific fields
+	Name        string `jsonapi:"attr,name"`
+	ContentType string `jsonapi:"attr,contentType"`
+	Size        int64  `jsonapi:"attr,size"`
+	// BlobKey is the key of the file content in the blob store.
+	BlobKey string
+}
+
+// IssueAttachmentCreate is the API message for creating an issue attachment.
+type IssueAttachmentCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	IssueID int
+
+	// Domain specific fields
+	Name        string
+	ContentType string
+	Size        int64
+	BlobKey     string
+}
+
+// IssueAttachmentFind is the API message for finding issue attachments.
+type IssueAttachmentFind struct {
+	ID *int
+
+	// Related fields
+	IssueID *int
+}
+
+func (find *IssueAttachmentFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// IssueAttachmentDelete is the API message for deleting an issue attachment.
+type IssueAttachmentDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
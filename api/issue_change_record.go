@@ -0,0 +1,155 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// IssueChangeRecord is the API message for the change record of a done issue for the auditors.
+// The record is a snapshot taken when it's first requested, and it's never modified afterwards,
+// so the later changes of the databases and the settings don't alter what the auditors see.
+type IssueChangeRecord struct {
+	ID int `jsonapi:"primary,issueChangeRecord"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	IssueID int `jsonapi:"attr,issueId"`
+
+	// Domain specific fields
+	// Payload is the JSON encoded IssueChangeRecordPayload, it's kept as it's signed.
+	Payload string `jsonapi:"attr,payload"`
+	// Signature is the HMAC of the fields above keyed by the workspace secret.
+	Signature string `jsonapi:"attr,signature"`
+}
+
+// IssueChangeRecordCreate is the API message for creating an issue change record.
+type IssueChangeRecordCreate struct {
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	IssueID int
+
+	// Domain specific fields
+	Payload   string
+	Signature string
+}
+
+// IssueChangeRecordFind is the API message for finding issue change records.
+type IssueChangeRecordFind struct {
+	ID *int
+
+	// Related fields
+	IssueID *int
+}
+
+// IssueChangeRecordPayload is the content of the change record.
+type IssueChangeRecordPayload struct {
+	IssueName   string    `json:"issueName"`
+	IssueType   IssueType `json:"issueType"`
+	ProjectName string    `json:"projectName"`
+	CreatorName string    `json:"creatorName"`
+	CreatedTs   int64     `json:"createdTs"`
+	DoneTs      int64     `json:"doneTs"`
+
+	ApprovalList []*IssueChangeRecordApproval `json:"approvalList"`
+	TaskList     []*IssueChangeRecordTask     `json:"taskList"`
+}
+
+// IssueChangeRecordApproval is the approval of a task in the change record.
+type IssueChangeRecordApproval struct {
+	TaskName      string `json:"taskName"`
+	ApproverName  string `json:"approverName"`
+	ApproverEmail string `json:"approverEmail"`
+	ApprovedTs    int64  `json:"approvedTs"`
+}
+
+// IssueChangeRecordTask is a task of the issue in the change record.
+type IssueChangeRecordTask struct {
+	Name            string     `json:"name"`
+	Type            TaskType   `json:"type"`
+	Status          TaskStatus `json:"status"`
+	EnvironmentName string     `json:"environmentName"`
+	InstanceName    string     `json:"instanceName"`
+	// DatabaseName is empty for the instance-level tasks.
+	DatabaseName string `json:"databaseName"`
+	Statement    string `json:"statement"`
+
+	RunList []*IssueChangeRecordTaskRun `json:"runList"`
+
+	// SchemaVersion and SchemaDiff are from the migration history of the last run, empty if the task records no migration.
+	SchemaVersion string `json:"schemaVersion"`
+	// SchemaDiff is the unified diff from the schema before the migration to the one after.
+	SchemaDiff string `json:"schemaDiff"`
+}
+
+// IssueChangeRecordTaskRun is a run of the task in the change record.
+type IssueChangeRecordTaskRun struct {
+	Status    TaskRunStatus `json:"status"`
+	StartedTs int64         `json:"startedTs"`
+	EndedTs   int64         `json:"endedTs"`
+	// Detail is the result of the run, or the error if it failed.
+	Detail string `json:"detail"`
+}
+
+// IssueChangeRecordVerification is the API message for the result of verifying the signature of the change record.
+type IssueChangeRecordVerification struct {
+	// IssueID is used as the primary key, as the verification is computed on request.
+	IssueID int `jsonapi:"primary,issueChangeRecordVerification"`
+
+	// Domain specific fields
+	RecordID int  `jsonapi:"attr,recordId"`
+	Verified bool `jsonapi:"attr,verified"`
+}
+
+// GetIssueChangeRecordSignature returns the signature of the change record.
+// The fields are encoded as a JSON array so that their boundaries are unambiguous.
+func GetIssueChangeRecordSignature(key []byte, create *IssueChangeRecordCreate) string {
+	// Marshaling the strings and the integers never fails.
+	b, _ := json.Marshal([]interface{}{
+		create.CreatorID,
+		create.CreatedTs,
+		create.IssueID,
+		create.Payload,
+	})
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyIssueChangeRecord returns whether the change record matches its signature.
+func VerifyIssueChangeRecord(key []byte, record *IssueChangeRecord) bool {
+	signature := GetIssueChangeRecordSignature(key, &IssueChangeRecordCreate{
+		CreatorID: record.CreatorID,
+		CreatedTs: record.CreatedTs,
+		IssueID:   record.IssueID,
+		Payload:   record.Payload,
+	})
+	return hmac.Equal([]byte(signature), []byte(record.Signature))
+}
+
+// DiffSchemaDump returns the unified diff from the schema dump before the migration to the one after,
+// it's empty if the schema is unchanged.
+func DiffSchemaDump(before, after string) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitStatementLines(before),
+		B:        splitStatementLines(after),
+		FromFile: "before",
+		ToFile:   "after",
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to diff the schema, error: %w", err)
+	}
+	return diff, nil
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyIssueChangeRecord(t *testing.T) {
+	key := []byte("secret")
+	create := &IssueChangeRecordCreate{
+		CreatorID: 101,
+		CreatedTs: 1654000000,
+		IssueID:   102,
+		Payload:   `{"issueName":"Add column"}`,
+	}
+	record := &IssueChangeRecord{
+		CreatorID: create.CreatorID,
+		CreatedTs: create.CreatedTs,
+		IssueID:   create.IssueID,
+		Payload:   create.Payload,
+		Signature: GetIssueChangeRecordSignature(key, create),
+	}
+	require.True(t, VerifyIssueChangeRecord(key, record))
+	require.False(t, VerifyIssueChangeRecord([]byte("another secret"), record))
+
+	record.Payload = `{"issueName":"Drop column"}`
+	require.False(t, VerifyIssueChangeRecord(key, record))
+}
+
+func TestDiffSchemaDump(t *testing.T) {
+	diff, err := DiffSchemaDump("CREATE TABLE t (\n  id INT\n);\n", "CREATE TABLE t (\n  id INT,\n  name TEXT\n);\n")
+	require.NoError(t, err)
+	require.Equal(t, "--- before\n+++ after\n@@ -1,3 +1,4 @@\n CREATE TABLE t (\n-  id INT\n+  id INT,\n+  name TEXT\n );\n", diff)
+
+	diff, err = DiffSchemaDump("CREATE TABLE t (id INT);\n", "CREATE TABLE t (id INT);\n")
+	require.NoError(t, err)
+	require.Equal(t, "", diff)
+}
@@ -0,0 +1,39 @@
+package api
+
+// IssueDependency is the API message for an issue blocking another issue.
+// The first stage of the blocked issue can't start until the blocking issue is done.
+type IssueDependency struct {
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Domain specific fields
+	BlockingIssueID int `jsonapi:"attr,blockingIssueId"`
+	BlockedIssueID  int `jsonapi:"attr,blockedIssueId"`
+}
+
+// IssueDependencyCreate is the API message for creating an issue dependency.
+type IssueDependencyCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	BlockingIssueID int `jsonapi:"attr,blockingIssueId"`
+	BlockedIssueID  int
+}
+
+// IssueDependencyFind is the API message for finding issue dependencies.
+type IssueDependencyFind struct {
+	// Domain specific fields
+	BlockingIssueID *int
+	BlockedIssueID  *int
+}
+
+// IssueDependencyDelete is the API message for deleting an issue dependency.
+type IssueDependencyDelete struct {
+	// Domain specific fields
+	BlockingIssueID int
+	BlockedIssueID  int
+}
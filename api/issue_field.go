@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// IssueFieldType is the type of an issue custom field.
+type IssueFieldType string
+
+const (
+	// IssueFieldString is the free text field, e.g. the internal change ticket ID.
+	IssueFieldString IssueFieldType = "STRING"
+	// IssueFieldEnum is the field with one of the predefined values, e.g. the risk category.
+	IssueFieldEnum IssueFieldType = "ENUM"
+	// IssueFieldUser is the field referencing a principal, the value is the principal ID.
+	IssueFieldUser IssueFieldType = "USER"
+	// IssueFieldDate is the calendar date field, the value is in the IssueFieldDateFormat.
+	IssueFieldDate IssueFieldType = "DATE"
+
+	// IssueFieldDateFormat is the format of the DATE field value.
+	IssueFieldDateFormat = "2006-01-02"
+
+	maxIssueFieldValueLength = 1024
+)
+
+// IssueField is the API message for an issue custom field defined in a project.
+type IssueField struct {
+	ID int `jsonapi:"primary,issueField"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	Name string         `jsonapi:"attr,name"`
+	Type IssueFieldType `jsonapi:"attr,type"`
+	// EnumValueList is the allowed values of the ENUM field.
+	EnumValueList []string `jsonapi:"attr,enumValueList"`
+}
+
+// IssueFieldCreate is the API message for creating an issue custom field.
+type IssueFieldCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Name          string         `jsonapi:"attr,name"`
+	Type          IssueFieldType `jsonapi:"attr,type"`
+	EnumValueList []string       `jsonapi:"attr,enumValueList"`
+}
+
+// IssueFieldFind is the API message for finding issue custom fields.
+type IssueFieldFind struct {
+	ID *int
+
+	// Related fields
+	ProjectID *int
+}
+
+func (find *IssueFieldFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// IssueFieldPatch is the API message for patching an issue custom field.
+// The type can't be changed since the existing values may not fit the new type.
+type IssueFieldPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name *string `jsonapi:"attr,name"`
+	// EnumValueList is the comma-separated enum value list.
+	EnumValueList *string `jsonapi:"attr,enumValueList"`
+}
+
+// IssueFieldDelete is the API message for deleting an issue custom field, the values of the field are deleted as well.
+type IssueFieldDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// IssueFieldValue is the API message for the value of an issue custom field.
+type IssueFieldValue struct {
+	ID int `jsonapi:"primary,issueFieldValue"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	IssueID int `jsonapi:"attr,issueId"`
+	FieldID int `jsonapi:"attr,fieldId"`
+
+	// Domain specific fields
+	Value string `jsonapi:"attr,value"`
+}
+
+// IssueFieldValueUpsert is the API message for setting the value of an issue custom field.
+type IssueFieldValueUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	IssueID int
+	FieldID int
+
+	// Domain specific fields
+	Value string `jsonapi:"attr,value"`
+}
+
+// IssueFieldValueFind is the API message for finding issue custom field values.
+type IssueFieldValueFind struct {
+	// Related fields
+	IssueID *int
+	FieldID *int
+}
+
+func (find *IssueFieldValueFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// IssueFieldValueDelete is the API message for clearing the value of an issue custom field.
+type IssueFieldValueDelete struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+
+	// Related fields
+	IssueID int
+	FieldID int
+}
+
+// ValidateIssueField validates the name, the type and the enum values of the issue custom field.
+func ValidateIssueField(name string, fieldType IssueFieldType, enumValueList []string) error {
+	if strings.TrimSpace(name) == "" {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("field name must not be empty")}
+	}
+	switch fieldType {
+	case IssueFieldString, IssueFieldUser, IssueFieldDate:
+		if len(enumValueList) > 0 {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("enum values are only applicable to the %s field", IssueFieldEnum)}
+		}
+	case IssueFieldEnum:
+		if len(enumValueList) == 0 {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("the %s field %q must have at least one value", IssueFieldEnum, name)}
+		}
+		valueSet := make(map[string]bool)
+		for _, value := range enumValueList {
+			// The enum values are patched as a comma-separated list.
+			if value == "" || strings.Contains(value, ",") {
+				return &common.Error{Code: common.Invalid, Err: fmt.Errorf("enum value %q must be non-empty and must not contain comma", value)}
+			}
+			if valueSet[value] {
+				return &common.Error{Code: common.Invalid, Err: fmt.Errorf("duplicate enum value %q", value)}
+			}
+			valueSet[value] = true
+		}
+	default:
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid field type %q", fieldType)}
+	}
+	return nil
+}
+
+// ValidateIssueFieldValue validates the value fits the type of the issue custom field.
+// The principal of the USER field is checked by the caller.
+func ValidateIssueFieldValue(field *IssueField, value string) error {
+	if value == "" {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("value of field %q must not be empty", field.Name)}
+	}
+	switch field.Type {
+	case IssueFieldString:
+		if utf8.RuneCountInString(value) > maxIssueFieldValueLength {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("value of field %q must not exceed %d characters", field.Name, maxIssueFieldValueLength)}
+		}
+	case IssueFieldEnum:
+		for _, enumValue := range field.EnumValueList {
+			if value == enumValue {
+				return nil
+			}
+		}
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("value %q of field %q must be one of %s", value, field.Name, strings.Join(field.EnumValueList, ", "))}
+	case IssueFieldUser:
+		if id, err := strconv.Atoi(value); err != nil || id <= 0 {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("value %q of field %q must be a principal ID", value, field.Name)}
+		}
+	case IssueFieldDate:
+		if _, err := time.Parse(IssueFieldDateFormat, value); err != nil {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("value %q of field %q must be a date in the YYYY-MM-DD format", value, field.Name)}
+		}
+	default:
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid field type %q", field.Type)}
+	}
+	return nil
+}
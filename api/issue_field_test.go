@@ -0,0 +1,69 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIssueField(t *testing.T) {
+	tests := []struct {
+		name          string
+		fieldType     IssueFieldType
+		enumValueList []string
+		wantErr       bool
+	}{
+		{name: "Change ticket", fieldType: IssueFieldString},
+		{name: "Risk", fieldType: IssueFieldEnum, enumValueList: []string{"LOW", "MEDIUM", "HIGH"}},
+		{name: "Reviewer", fieldType: IssueFieldUser},
+		{name: "Due date", fieldType: IssueFieldDate},
+		{name: " ", fieldType: IssueFieldString, wantErr: true},
+		{name: "Risk", fieldType: "NUMBER", wantErr: true},
+		{name: "Risk", fieldType: IssueFieldEnum, wantErr: true},
+		{name: "Risk", fieldType: IssueFieldEnum, enumValueList: []string{"LOW", "LOW"}, wantErr: true},
+		{name: "Risk", fieldType: IssueFieldEnum, enumValueList: []string{"LOW,MEDIUM"}, wantErr: true},
+		{name: "Change ticket", fieldType: IssueFieldString, enumValueList: []string{"LOW"}, wantErr: true},
+	}
+	for _, test := range tests {
+		err := ValidateIssueField(test.name, test.fieldType, test.enumValueList)
+		if test.wantErr {
+			require.Error(t, err, "%s %s", test.name, test.fieldType)
+		} else {
+			require.NoError(t, err, "%s %s", test.name, test.fieldType)
+		}
+	}
+}
+
+func TestValidateIssueFieldValue(t *testing.T) {
+	ticket := &IssueField{Name: "Change ticket", Type: IssueFieldString}
+	risk := &IssueField{Name: "Risk", Type: IssueFieldEnum, EnumValueList: []string{"LOW", "HIGH"}}
+	reviewer := &IssueField{Name: "Reviewer", Type: IssueFieldUser}
+	dueDate := &IssueField{Name: "Due date", Type: IssueFieldDate}
+
+	tests := []struct {
+		field   *IssueField
+		value   string
+		wantErr bool
+	}{
+		{field: ticket, value: "CHG-1024"},
+		{field: ticket, value: "", wantErr: true},
+		{field: ticket, value: strings.Repeat("a", 1025), wantErr: true},
+		{field: risk, value: "HIGH"},
+		{field: risk, value: "high", wantErr: true},
+		{field: reviewer, value: "101"},
+		{field: reviewer, value: "0", wantErr: true},
+		{field: reviewer, value: "dba@example.com", wantErr: true},
+		{field: dueDate, value: "2022-05-16"},
+		{field: dueDate, value: "2022-13-01", wantErr: true},
+		{field: dueDate, value: "05/16/2022", wantErr: true},
+	}
+	for _, test := range tests {
+		err := ValidateIssueFieldValue(test.field, test.value)
+		if test.wantErr {
+			require.Error(t, err, "%s %q", test.field.Name, test.value)
+		} else {
+			require.NoError(t, err, "%s %q", test.field.Name, test.value)
+		}
+	}
+}
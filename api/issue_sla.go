@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DefaultIssueSLAReminderThreshold is the default percentage of the SLA elapsed before reminding the assignee.
+const DefaultIssueSLAReminderThreshold = 80
+
+// IssueSLASetting is the API message for the issue SLA setting of a project.
+type IssueSLASetting struct {
+	ID int `jsonapi:"primary,issueSlaSetting"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	// TimeToApprove is the seconds allowed to approve an issue since it's created, 0 means no SLA.
+	TimeToApprove int64 `jsonapi:"attr,timeToApprove"`
+	// TimeToExecute is the seconds allowed to finish an issue since it's approved, 0 means no SLA.
+	TimeToExecute int64 `jsonapi:"attr,timeToExecute"`
+	// ReminderThreshold is the percentage of the SLA elapsed before reminding the assignee.
+	ReminderThreshold int `jsonapi:"attr,reminderThreshold"`
+}
+
+// IssueSLASettingFind is the API message for finding issue SLA settings.
+type IssueSLASettingFind struct {
+	// Related fields
+	ProjectID *int
+}
+
+func (find *IssueSLASettingFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// IssueSLASettingUpsert is the API message for upserting the issue SLA setting of a project.
+// NOTE: We use PATCH for Upsert, this is inspired by https://google.aip.dev/134#patch-and-put
+type IssueSLASettingUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	TimeToApprove     int64 `jsonapi:"attr,timeToApprove"`
+	TimeToExecute     int64 `jsonapi:"attr,timeToExecute"`
+	ReminderThreshold int   `jsonapi:"attr,reminderThreshold"`
+}
+
+// IssueSLAPhase is the phase of an issue measured by the SLA.
+type IssueSLAPhase string
+
+const (
+	// IssueSLAPhaseApprove is the phase from the issue creation to the first task approval.
+	IssueSLAPhaseApprove IssueSLAPhase = "APPROVE"
+	// IssueSLAPhaseExecute is the phase from the first task approval to the issue resolution.
+	IssueSLAPhaseExecute IssueSLAPhase = "EXECUTE"
+)
+
+// IssueSLAStatus is the status of an SLA timer.
+type IssueSLAStatus string
+
+const (
+	// IssueSLAOnTrack is the status of a running timer before the reminder threshold.
+	IssueSLAOnTrack IssueSLAStatus = "ON_TRACK"
+	// IssueSLAAtRisk is the status of a running timer past the reminder threshold.
+	IssueSLAAtRisk IssueSLAStatus = "AT_RISK"
+	// IssueSLAMet is the status of a timer stopped before the deadline.
+	IssueSLAMet IssueSLAStatus = "MET"
+	// IssueSLABreached is the status of a timer past the deadline, whether it's stopped or not.
+	IssueSLABreached IssueSLAStatus = "BREACHED"
+)
+
+// IssueSLATimer is the SLA timer of a phase of an issue.
+type IssueSLATimer struct {
+	Phase      IssueSLAPhase `json:"phase"`
+	StartedTs  int64         `json:"startedTs"`
+	DeadlineTs int64         `json:"deadlineTs"`
+	// StoppedTs is 0 if the phase isn't finished yet.
+	StoppedTs int64          `json:"stoppedTs"`
+	Status    IssueSLAStatus `json:"status"`
+}
+
+// IssueSLA is the API message for the SLA timers of an issue.
+// A timer is nil if the phase has no SLA or the phase hasn't started yet.
+type IssueSLA struct {
+	IssueID      int            `json:"issueId"`
+	IssueName    string         `json:"issueName"`
+	IssueStatus  IssueStatus    `json:"issueStatus"`
+	AssigneeID   int            `json:"assigneeId"`
+	ApproveTimer *IssueSLATimer `json:"approveTimer"`
+	ExecuteTimer *IssueSLATimer `json:"executeTimer"`
+}
+
+// IssueSLAPhaseSummary is the summary of the SLA timers of a phase in the report.
+type IssueSLAPhaseSummary struct {
+	Phase         IssueSLAPhase `json:"phase"`
+	OnTrackCount  int           `json:"onTrackCount"`
+	AtRiskCount   int           `json:"atRiskCount"`
+	MetCount      int           `json:"metCount"`
+	BreachedCount int           `json:"breachedCount"`
+	// AverageSeconds is the average duration of the stopped timers.
+	AverageSeconds int64 `json:"averageSeconds"`
+}
+
+// IssueSLAReport is the API message for the SLA report of the issues created in a time range in a project.
+// It's returned in plain JSON since the nested summaries can't be represented by JSON:API attributes well.
+type IssueSLAReport struct {
+	ProjectID      int                   `json:"projectId"`
+	StartTs        int64                 `json:"startTs"`
+	EndTs          int64                 `json:"endTs"`
+	TimeToApprove  int64                 `json:"timeToApprove"`
+	TimeToExecute  int64                 `json:"timeToExecute"`
+	ApproveSummary *IssueSLAPhaseSummary `json:"approveSummary"`
+	ExecuteSummary *IssueSLAPhaseSummary `json:"executeSummary"`
+	// IssueList excludes the canceled issues.
+	IssueList []*IssueSLA `json:"issueList"`
+}
@@ -26,6 +26,9 @@ const (
 	DBA Role = "DBA"
 	// Developer is the DEVELOPER role.
 	Developer Role = "DEVELOPER"
+	// Auditor is the AUDITOR role.
+	// An auditor can view everything across all projects but can't change anything.
+	Auditor Role = "AUDITOR"
 )
 
 // Member is the API message for a member.
@@ -46,6 +49,8 @@ type Member struct {
 	Role        Role         `jsonapi:"attr,role"`
 	PrincipalID int
 	Principal   *Principal `jsonapi:"relation,principal"`
+	// LegalHold keeps the data of the member from the retention purge and the erasure.
+	LegalHold bool `jsonapi:"attr,legalHold"`
 }
 
 // MemberCreate is the API message for creating a member.
@@ -87,5 +92,6 @@ type MemberPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	Role *string `jsonapi:"attr,role"`
+	Role      *string `jsonapi:"attr,role"`
+	LegalHold *bool   `jsonapi:"attr,legalHold"`
 }
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MemberImportEntry is one member in the bulk member import.
+type MemberImportEntry struct {
+	Email string `json:"email"`
+	// Name defaults to the local part of the email if it's empty.
+	Name string `json:"name"`
+	// Role defaults to DEVELOPER if it's empty.
+	Role Role `json:"role"`
+}
+
+// MemberImportResult is the API message for the result of the bulk member import.
+type MemberImportResult struct {
+	// CreatedCount is the number of the members created along with their users.
+	CreatedCount int `json:"createdCount"`
+	// UpdatedCount is the number of the existing members whose role is changed.
+	UpdatedCount int `json:"updatedCount"`
+	// SkippedCount is the number of the existing members already in the role.
+	SkippedCount int `json:"skippedCount"`
+}
+
+// ParseMemberImportCSV parses the members from the CSV content with the "email", "name" and "role" columns.
+// The header row is required, and only the "email" column is mandatory.
+func ParseMemberImportCSV(content []byte) ([]*MemberImportEntry, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CSV header, error: %w", err)
+	}
+	columnIndex := make(map[string]int)
+	for i, column := range header {
+		columnIndex[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columnIndex["email"]; !ok {
+		return nil, fmt.Errorf("missing the email column in the CSV header")
+	}
+	getColumn := func(record []string, column string) string {
+		i, ok := columnIndex[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entryList []*MemberImportEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CSV content, error: %w", err)
+		}
+		entryList = append(entryList, &MemberImportEntry{
+			Email: getColumn(record, "email"),
+			Name:  getColumn(record, "name"),
+			Role:  Role(strings.ToUpper(getColumn(record, "role"))),
+		})
+	}
+	return entryList, nil
+}
+
+// ParseMemberImportJSON parses the members from the JSON array content.
+func ParseMemberImportJSON(content []byte) ([]*MemberImportEntry, error) {
+	var entryList []*MemberImportEntry
+	if err := json.Unmarshal(content, &entryList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the JSON content, error: %w", err)
+	}
+	for _, entry := range entryList {
+		entry.Email = strings.TrimSpace(entry.Email)
+		entry.Name = strings.TrimSpace(entry.Name)
+		entry.Role = Role(strings.ToUpper(string(entry.Role)))
+	}
+	return entryList, nil
+}
+
+// ValidateMemberImportList validates the members and fills in the default name and role.
+// All the problems are returned at once with the 1-based entry numbers, so the payload can be fixed in one go.
+func ValidateMemberImportList(entryList []*MemberImportEntry) error {
+	if len(entryList) == 0 {
+		return fmt.Errorf("no member to import")
+	}
+	var problemList []string
+	emailSet := make(map[string]bool)
+	for i, entry := range entryList {
+		entry.Email = strings.ToLower(entry.Email)
+		if at := strings.Index(entry.Email, "@"); at <= 0 || at == len(entry.Email)-1 {
+			problemList = append(problemList, fmt.Sprintf("#%d: invalid email %q", i+1, entry.Email))
+			continue
+		}
+		if emailSet[entry.Email] {
+			problemList = append(problemList, fmt.Sprintf("#%d: duplicate email %q", i+1, entry.Email))
+			continue
+		}
+		emailSet[entry.Email] = true
+		if entry.Name == "" {
+			entry.Name = entry.Email[:strings.Index(entry.Email, "@")]
+		}
+		switch entry.Role {
+		case "":
+			entry.Role = Developer
+		case Owner, DBA, Developer, Auditor:
+		default:
+			problemList = append(problemList, fmt.Sprintf("#%d: invalid role %q", i+1, entry.Role))
+		}
+	}
+	if len(problemList) > 0 {
+		return fmt.Errorf("%s", strings.Join(problemList, "; "))
+	}
+	return nil
+}
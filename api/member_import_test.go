@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMemberImportCSV(t *testing.T) {
+	entryList, err := ParseMemberImportCSV([]byte("Email,Role,Name\nalice@example.com,dba,Alice\nbob@example.com,,\n"))
+	require.NoError(t, err)
+	require.Equal(t, []*MemberImportEntry{
+		{Email: "alice@example.com", Name: "Alice", Role: DBA},
+		{Email: "bob@example.com", Name: "", Role: ""},
+	}, entryList)
+
+	_, err = ParseMemberImportCSV([]byte("name,role\nAlice,DBA\n"))
+	require.Error(t, err)
+}
+
+func TestValidateMemberImportList(t *testing.T) {
+	entryList := []*MemberImportEntry{
+		{Email: "Alice@Example.com", Role: DBA},
+		{Email: "bob@example.com", Name: "Bob"},
+	}
+	require.NoError(t, ValidateMemberImportList(entryList))
+	require.Equal(t, []*MemberImportEntry{
+		{Email: "alice@example.com", Name: "alice", Role: DBA},
+		{Email: "bob@example.com", Name: "Bob", Role: Developer},
+	}, entryList)
+
+	err := ValidateMemberImportList([]*MemberImportEntry{
+		{Email: "alice"},
+		{Email: "bob@example.com", Role: "ADMIN"},
+		{Email: "carol@example.com"},
+		{Email: "CAROL@example.com"},
+	})
+	require.Error(t, err)
+	require.Equal(t, `#1: invalid email "alice"; #2: invalid role "ADMIN"; #4: duplicate email "carol@example.com"`, err.Error())
+
+	require.Error(t, ValidateMemberImportList(nil))
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// MigrationHistoryImport is the API message for importing the migration history recorded by another migration tool.
+type MigrationHistoryImport struct {
+	// Source is the migration tool, either FLYWAY or LIQUIBASE.
+	Source db.MigrationSource `jsonapi:"attr,source"`
+}
+
+// MigrationHistoryImportResult is the API message for the result of importing the migration history.
+type MigrationHistoryImportResult struct {
+	// ImportedCount is the number of the migrations recorded in the migration history.
+	ImportedCount int `json:"importedCount"`
+	// SkippedCount is the number of the failed migrations and the migrations imported before.
+	SkippedCount int `json:"skippedCount"`
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// ParameterScope is the MySQL scope of setting a system variable.
+type ParameterScope string
+
+const (
+	// ParameterScopeGlobal sets the global value, it's lost after the restart.
+	// It's used for MySQL 5.7 and TiDB, where TiDB persists the global value in the cluster.
+	ParameterScopeGlobal ParameterScope = "GLOBAL"
+	// ParameterScopePersist sets the global value and persists it to mysqld-auto.cnf, MySQL 8.0 only.
+	ParameterScopePersist ParameterScope = "PERSIST"
+	// ParameterScopePersistOnly only persists the value to mysqld-auto.cnf, it's for the read-only variables taking effect after the restart.
+	ParameterScopePersistOnly ParameterScope = "PERSIST_ONLY"
+)
+
+var parameterNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// ParameterChange is a change of an instance parameter, i.e. ALTER SYSTEM for Postgres and SET GLOBAL for MySQL.
+type ParameterChange struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ValidateParameterChangeList validates the parameter changes.
+func ValidateParameterChangeList(changeList []*ParameterChange) error {
+	if len(changeList) == 0 {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("parameter list must not be empty")}
+	}
+	nameSet := make(map[string]bool)
+	for _, change := range changeList {
+		if !parameterNameRegexp.MatchString(change.Name) {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid parameter name %q", change.Name)}
+		}
+		if nameSet[strings.ToLower(change.Name)] {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("duplicate parameter %q", change.Name)}
+		}
+		nameSet[strings.ToLower(change.Name)] = true
+	}
+	return nil
+}
+
+// GetMySQLParameterScope returns the scope of setting the system variables on the MySQL, MariaDB or TiDB instance of the version.
+// MariaDB has no SET PERSIST, so its changes are global until the restart.
+func GetMySQLParameterScope(engine db.Type, engineVersion string) ParameterScope {
+	if engine == db.MySQL && !strings.HasPrefix(engineVersion, "5.") {
+		return ParameterScopePersist
+	}
+	return ParameterScopeGlobal
+}
+
+// GetParameterChangeStatement returns the statement changing the parameter, the scope is only used for MySQL, MariaDB and TiDB.
+func GetParameterChangeStatement(engine db.Type, scope ParameterScope, change *ParameterChange) string {
+	quoteString := func(s string) string {
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+	}
+	if engine == db.Postgres {
+		return fmt.Sprintf("ALTER SYSTEM SET %s = %s;", change.Name, quoteString(change.Value))
+	}
+	// The numeric system variables don't accept the quoted values.
+	value := change.Value
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		value = quoteString(strings.ReplaceAll(value, `\`, `\\`))
+	}
+	return fmt.Sprintf("SET %s %s = %s;", scope, change.Name, value)
+}
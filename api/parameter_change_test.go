@@ -0,0 +1,48 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestValidateParameterChangeList(t *testing.T) {
+	require.NoError(t, ValidateParameterChangeList([]*ParameterChange{{Name: "max_connections", Value: "200"}, {Name: "auto_explain.log_min_duration", Value: "1s"}}))
+
+	tests := [][]*ParameterChange{
+		nil,
+		{{Name: "", Value: "1"}},
+		{{Name: "max_connections; DROP TABLE t", Value: "1"}},
+		{{Name: "max_connections", Value: "100"}, {Name: "MAX_CONNECTIONS", Value: "200"}},
+	}
+	for _, test := range tests {
+		require.Error(t, ValidateParameterChangeList(test), "%+v", test)
+	}
+}
+
+func TestGetMySQLParameterScope(t *testing.T) {
+	require.Equal(t, ParameterScopeGlobal, GetMySQLParameterScope(db.MySQL, "5.7.36"))
+	require.Equal(t, ParameterScopePersist, GetMySQLParameterScope(db.MySQL, "8.0.28"))
+	require.Equal(t, ParameterScopeGlobal, GetMySQLParameterScope(db.TiDB, "5.7.25-TiDB-v6.0.0"))
+}
+
+func TestGetParameterChangeStatement(t *testing.T) {
+	tests := []struct {
+		engine db.Type
+		scope  ParameterScope
+		change *ParameterChange
+		want   string
+	}{
+		{db.Postgres, "", &ParameterChange{Name: "shared_buffers", Value: "256MB"}, "ALTER SYSTEM SET shared_buffers = '256MB';"},
+		{db.Postgres, "", &ParameterChange{Name: "search_path", Value: `"$user", public`}, `ALTER SYSTEM SET search_path = '"$user", public';`},
+		{db.MySQL, ParameterScopePersist, &ParameterChange{Name: "max_connections", Value: "200"}, "SET PERSIST max_connections = 200;"},
+		{db.MySQL, ParameterScopePersistOnly, &ParameterChange{Name: "innodb_log_file_size", Value: "1073741824"}, "SET PERSIST_ONLY innodb_log_file_size = 1073741824;"},
+		{db.MySQL, ParameterScopeGlobal, &ParameterChange{Name: "sql_mode", Value: "STRICT_TRANS_TABLES,NO_ZERO_DATE"}, "SET GLOBAL sql_mode = 'STRICT_TRANS_TABLES,NO_ZERO_DATE';"},
+		{db.TiDB, ParameterScopeGlobal, &ParameterChange{Name: "init_connect", Value: `SET @a='\'`}, `SET GLOBAL init_connect = 'SET @a=''\\''';`},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, GetParameterChangeStatement(test.engine, test.scope, test.change))
+	}
+}
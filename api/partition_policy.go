@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// PartitionInterval is the range of each partition managed by the partition policy.
+type PartitionInterval string
+
+const (
+	// PartitionIntervalDay is the daily partition, e.g. orders_p20220516.
+	PartitionIntervalDay PartitionInterval = "DAY"
+	// PartitionIntervalWeek is the weekly partition starting on Monday, e.g. orders_p20220516.
+	PartitionIntervalWeek PartitionInterval = "WEEK"
+	// PartitionIntervalMonth is the monthly partition, e.g. orders_p202205.
+	PartitionIntervalMonth PartitionInterval = "MONTH"
+
+	// maxPartitionPrecreateCount is the limit of the pre-create horizon.
+	maxPartitionPrecreateCount = 366
+)
+
+// PartitionPolicy is the API message for a partition policy.
+// The partitions of the time-partitioned table are created ahead of the pre-create horizon and dropped after the retention
+// by the background partition manager. The statements are applied through the schema update issue of the normal task pipeline.
+// The table must be partitioned by RANGE on the range key in Postgres, or by RANGE COLUMNS in MySQL and TiDB.
+type PartitionPolicy struct {
+	ID int `jsonapi:"primary,partitionPolicy"`
+
+	// Standard fields
+	RowStatus RowStatus `jsonapi:"attr,rowStatus"`
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	// SchemaName is the schema of the table, only applicable to Postgres.
+	SchemaName string            `jsonapi:"attr,schemaName"`
+	TableName  string            `jsonapi:"attr,tableName"`
+	RangeKey   string            `jsonapi:"attr,rangeKey"`
+	Interval   PartitionInterval `jsonapi:"attr,interval"`
+	// RetentionCount is the number of the past partitions kept besides the current one, 0 means the partitions are never dropped.
+	RetentionCount int `jsonapi:"attr,retentionCount"`
+	// PrecreateCount is the number of the future partitions created ahead besides the current one.
+	PrecreateCount int `jsonapi:"attr,precreateCount"`
+	// AssigneeID is the assignee of the partition maintenance issue.
+	AssigneeID int   `jsonapi:"attr,assigneeId"`
+	LastRunTs  int64 `jsonapi:"attr,lastRunTs"`
+	// LastIssueID is the ID of the last partition maintenance issue, 0 if none.
+	LastIssueID int `jsonapi:"attr,lastIssueId"`
+}
+
+// PartitionPolicyCreate is the API message for creating a partition policy.
+type PartitionPolicyCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	SchemaName     string            `jsonapi:"attr,schemaName"`
+	TableName      string            `jsonapi:"attr,tableName"`
+	RangeKey       string            `jsonapi:"attr,rangeKey"`
+	Interval       PartitionInterval `jsonapi:"attr,interval"`
+	RetentionCount int               `jsonapi:"attr,retentionCount"`
+	PrecreateCount int               `jsonapi:"attr,precreateCount"`
+	AssigneeID     int               `jsonapi:"attr,assigneeId"`
+}
+
+// PartitionPolicyFind is the API message for finding partition policies.
+type PartitionPolicyFind struct {
+	ID *int
+
+	// Standard fields
+	RowStatus *RowStatus
+
+	// Related fields
+	DatabaseID *int
+}
+
+func (find *PartitionPolicyFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// PartitionPolicyPatch is the API message for patching a partition policy.
+// The table and the interval can't be changed since the existing partitions are named after them.
+type PartitionPolicyPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+	RowStatus *string `jsonapi:"attr,rowStatus"`
+
+	// Domain specific fields
+	RetentionCount *int `jsonapi:"attr,retentionCount"`
+	PrecreateCount *int `jsonapi:"attr,precreateCount"`
+	AssigneeID     *int `jsonapi:"attr,assigneeId"`
+	// LastRunTs and LastIssueID are set by the partition manager after creating the issue.
+	LastRunTs   *int64
+	LastIssueID *int
+}
+
+// PartitionPolicyDelete is the API message for deleting a partition policy, the existing partitions are kept.
+type PartitionPolicyDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// ValidatePartitionPolicy validates the partition policy.
+func ValidatePartitionPolicy(tableName, rangeKey string, interval PartitionInterval, retentionCount, precreateCount int) error {
+	if strings.TrimSpace(tableName) == "" {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("table name must not be empty")}
+	}
+	if strings.TrimSpace(rangeKey) == "" {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("range key must not be empty")}
+	}
+	switch interval {
+	case PartitionIntervalDay, PartitionIntervalWeek, PartitionIntervalMonth:
+	default:
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid partition interval %q", interval)}
+	}
+	if retentionCount < 0 {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("retention count must not be negative")}
+	}
+	if precreateCount < 0 || precreateCount > maxPartitionPrecreateCount {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("pre-create count must be in [0, %d]", maxPartitionPrecreateCount)}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePartitionPolicy(t *testing.T) {
+	tests := []struct {
+		tableName      string
+		rangeKey       string
+		interval       PartitionInterval
+		retentionCount int
+		precreateCount int
+		wantErr        bool
+	}{
+		{tableName: "orders", rangeKey: "created_at", interval: PartitionIntervalMonth, retentionCount: 12, precreateCount: 3},
+		{tableName: "events", rangeKey: "ts", interval: PartitionIntervalDay, retentionCount: 0, precreateCount: 0},
+		{tableName: "", rangeKey: "ts", interval: PartitionIntervalDay, wantErr: true},
+		{tableName: "events", rangeKey: " ", interval: PartitionIntervalDay, wantErr: true},
+		{tableName: "events", rangeKey: "ts", interval: "HOUR", wantErr: true},
+		{tableName: "events", rangeKey: "ts", interval: PartitionIntervalWeek, retentionCount: -1, wantErr: true},
+		{tableName: "events", rangeKey: "ts", interval: PartitionIntervalWeek, precreateCount: 367, wantErr: true},
+	}
+	for _, test := range tests {
+		err := ValidatePartitionPolicy(test.tableName, test.rangeKey, test.interval, test.retentionCount, test.precreateCount)
+		if test.wantErr {
+			require.Error(t, err, "%+v", test)
+		} else {
+			require.NoError(t, err, "%+v", test)
+		}
+	}
+}
@@ -3,8 +3,11 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 // PolicyType is the type or name of a policy.
@@ -19,6 +22,9 @@ type AssigneeGroupValue string
 // BackupPlanPolicySchedule is value for backup plan policy.
 type BackupPlanPolicySchedule string
 
+// DataSourcePolicyValue is value for data source policy.
+type DataSourcePolicyValue string
+
 const (
 	// DefaultPolicyID is the ID of the default policy.
 	DefaultPolicyID int = 0
@@ -29,6 +35,26 @@ const (
 	PolicyTypeBackupPlan PolicyType = "bb.policy.backup-plan"
 	// PolicyTypeSQLReview is the sql review policy type.
 	PolicyTypeSQLReview PolicyType = "bb.policy.sql-review"
+	// PolicyTypeDataSource is the data source policy type.
+	PolicyTypeDataSource PolicyType = "bb.policy.data-source"
+	// PolicyTypeDatabasePurge is the policy type for purging the databases no longer found on the instance.
+	PolicyTypeDatabasePurge PolicyType = "bb.policy.database-purge"
+	// PolicyTypeStatisticsRefresh is the policy type for refreshing the table statistics after the migrations.
+	PolicyTypeStatisticsRefresh PolicyType = "bb.policy.statistics-refresh"
+	// PolicyTypeStageGate is the policy type for the external gates checked before starting the stage tasks.
+	PolicyTypeStageGate PolicyType = "bb.policy.stage-gate"
+	// PolicyTypeReplicationLag is the policy type for the replication lag threshold checked before the cutover tasks.
+	PolicyTypeReplicationLag PolicyType = "bb.policy.replication-lag"
+	// PolicyTypeAccessChange is the policy type for reviewing the user and grant changes.
+	PolicyTypeAccessChange PolicyType = "bb.policy.access-change"
+	// PolicyTypeParameterBaseline is the policy type for the expected instance parameters.
+	PolicyTypeParameterBaseline PolicyType = "bb.policy.parameter-baseline"
+	// PolicyTypeSessionSetting is the policy type for the default and the maximum session settings of the tasks.
+	PolicyTypeSessionSetting PolicyType = "bb.policy.session-setting"
+	// PolicyTypeSQLEditorQuery is the policy type for the guardrails of the SQL editor queries.
+	PolicyTypeSQLEditorQuery PolicyType = "bb.policy.sql-editor-query"
+	// PolicyTypeDataMasking is the policy type for masking the sensitive columns in the query results.
+	PolicyTypeDataMasking PolicyType = "bb.policy.data-masking"
 
 	// PipelineApprovalValueManualNever means the pipeline will automatically be approved without user intervention.
 	PipelineApprovalValueManualNever PipelineApprovalValue = "MANUAL_APPROVAL_NEVER"
@@ -44,14 +70,32 @@ const (
 	BackupPlanPolicyScheduleDaily BackupPlanPolicySchedule = "DAILY"
 	// BackupPlanPolicyScheduleWeekly is WEEKLY backup plan policy value.
 	BackupPlanPolicyScheduleWeekly BackupPlanPolicySchedule = "WEEKLY"
+
+	// DataSourcePolicyValueReadOnlyPreferred means the read-only operations use the read-only data source if any, and fall back to the admin data source.
+	DataSourcePolicyValueReadOnlyPreferred DataSourcePolicyValue = "READ_ONLY_PREFERRED"
+	// DataSourcePolicyValueReadOnlyRequired means the read-only operations are rejected if the instance doesn't have a read-only data source.
+	DataSourcePolicyValueReadOnlyRequired DataSourcePolicyValue = "READ_ONLY_REQUIRED"
+
+	// DefaultReplicationLagMaxSeconds is the default replication lag threshold of the cutover tasks.
+	DefaultReplicationLagMaxSeconds int64 = 10
 )
 
 var (
 	// PolicyTypes is a set of all policy types.
 	PolicyTypes = map[PolicyType]bool{
-		PolicyTypePipelineApproval: true,
-		PolicyTypeBackupPlan:       true,
-		PolicyTypeSQLReview:        true,
+		PolicyTypePipelineApproval:  true,
+		PolicyTypeBackupPlan:        true,
+		PolicyTypeSQLReview:         true,
+		PolicyTypeDataSource:        true,
+		PolicyTypeDatabasePurge:     true,
+		PolicyTypeStatisticsRefresh: true,
+		PolicyTypeStageGate:         true,
+		PolicyTypeReplicationLag:    true,
+		PolicyTypeAccessChange:      true,
+		PolicyTypeParameterBaseline: true,
+		PolicyTypeSessionSetting:    true,
+		PolicyTypeSQLEditorQuery:    true,
+		PolicyTypeDataMasking:       true,
 	}
 )
 
@@ -73,6 +117,8 @@ type Policy struct {
 	Environment   *Environment `jsonapi:"relation,environment"`
 
 	// Domain specific fields
+	// Tag scopes the policy to the databases with the tag in the environment, it's empty for the environment policy.
+	Tag     string     `jsonapi:"attr,tag"`
 	Type    PolicyType `jsonapi:"attr,type"`
 	Payload string     `jsonapi:"attr,payload"`
 }
@@ -85,7 +131,12 @@ type PolicyFind struct {
 	EnvironmentID *int
 
 	// Domain specific fields
-	Type *PolicyType `jsonapi:"attr,type"`
+	// Tag finds the policy of the exact tag.
+	Tag *string
+	// TagList finds the effective policy of the database with the tags, which is the policy of the first tag having one,
+	// and falls back to the environment policy.
+	TagList []string
+	Type    *PolicyType `jsonapi:"attr,type"`
 }
 
 // PolicyUpsert is the message to upsert a policy.
@@ -101,6 +152,7 @@ type PolicyUpsert struct {
 	EnvironmentID int
 
 	// Domain specific fields
+	Tag     string
 	Type    PolicyType
 	Payload *string `jsonapi:"attr,payload"`
 }
@@ -118,6 +170,7 @@ type PolicyDelete struct {
 	// Type is the policy type.
 	// Currently we only support delete operation for "bb.policy.sql-review", need it here for validation and update query.
 	Type PolicyType
+	Tag  string
 }
 
 // PipelineApprovalPolicy is the policy configuration for pipeline approval.
@@ -127,6 +180,19 @@ type PipelineApprovalPolicy struct {
 	// if the approval policy is MANUAL_APPROVAL_ALWAYS, the assignee group is the DBAs by default,
 	//	 and we set the assignee group to the project owners for corresponding issue types.
 	AssigneeGroupList []AssigneeGroup `json:"assigneeGroupList"`
+	// TaskApprovalList overrides the Value for the tasks of the given types,
+	// e.g. the data update tasks require the manual approval while the schema update tasks don't.
+	TaskApprovalList []TaskApproval `json:"taskApprovalList,omitempty"`
+}
+
+// GetValue returns the approval policy value of the task type.
+func (pa *PipelineApprovalPolicy) GetValue(taskType TaskType) PipelineApprovalValue {
+	for _, approval := range pa.TaskApprovalList {
+		if approval.TaskType == taskType {
+			return approval.Value
+		}
+	}
+	return pa.Value
 }
 
 func (pa PipelineApprovalPolicy) String() (string, error) {
@@ -146,6 +212,12 @@ func UnmarshalPipelineApprovalPolicy(payload string) (*PipelineApprovalPolicy, e
 	return &pa, nil
 }
 
+// TaskApproval is the approval policy value of a task type.
+type TaskApproval struct {
+	TaskType TaskType              `json:"taskType"`
+	Value    PipelineApprovalValue `json:"value"`
+}
+
 // AssigneeGroup is the configuration of the assignee group.
 type AssigneeGroup struct {
 	IssueType IssueType          `json:"issueType"`
@@ -184,6 +256,431 @@ func UnmarshalBackupPlanPolicy(payload string) (*BackupPlanPolicy, error) {
 	return &bp, nil
 }
 
+// DataSourcePolicy is the policy configuration for choosing the data source by operation type.
+type DataSourcePolicy struct {
+	Value DataSourcePolicyValue `json:"value"`
+}
+
+func (dp DataSourcePolicy) String() (string, error) {
+	s, err := json.Marshal(dp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalDataSourcePolicy will unmarshal payload to data source policy.
+func UnmarshalDataSourcePolicy(payload string) (*DataSourcePolicy, error) {
+	var dp DataSourcePolicy
+	if err := json.Unmarshal([]byte(payload), &dp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data source policy %q: %q", payload, err)
+	}
+	return &dp, nil
+}
+
+// DatabasePurgePolicy is the policy configuration for purging the databases no longer found on the instance.
+type DatabasePurgePolicy struct {
+	// PurgeAfterDays is the number of days after which a NOT_FOUND database is archived, 0 means never.
+	PurgeAfterDays int `json:"purgeAfterDays"`
+}
+
+func (dp DatabasePurgePolicy) String() (string, error) {
+	s, err := json.Marshal(dp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalDatabasePurgePolicy will unmarshal payload to database purge policy.
+func UnmarshalDatabasePurgePolicy(payload string) (*DatabasePurgePolicy, error) {
+	var dp DatabasePurgePolicy
+	if err := json.Unmarshal([]byte(payload), &dp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal database purge policy %q: %q", payload, err)
+	}
+	return &dp, nil
+}
+
+// StatisticsRefreshPolicy is the policy configuration for refreshing the table statistics after the migrations.
+type StatisticsRefreshPolicy struct {
+	// Enabled runs ANALYZE on the tables touched by the migration after it's applied,
+	// so that the query plans don't degrade right after the rollout.
+	Enabled bool `json:"enabled"`
+}
+
+func (sp StatisticsRefreshPolicy) String() (string, error) {
+	s, err := json.Marshal(sp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalStatisticsRefreshPolicy will unmarshal payload to statistics refresh policy.
+func UnmarshalStatisticsRefreshPolicy(payload string) (*StatisticsRefreshPolicy, error) {
+	var sp StatisticsRefreshPolicy
+	if err := json.Unmarshal([]byte(payload), &sp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal statistics refresh policy %q: %q", payload, err)
+	}
+	return &sp, nil
+}
+
+// StageGatePolicy is the policy configuration for the external gates checked before starting the tasks of the stages in an environment.
+type StageGatePolicy struct {
+	GateList []StageGate `json:"gateList"`
+}
+
+// StageGate is the external gate provider, the stage tasks don't start until all the gates pass.
+type StageGate struct {
+	// Name is the unique name of the gate in the environment, e.g. "QA suite".
+	Name string `json:"name"`
+	// URL is the HTTP callback receiving StageGateRequest and responding StageGateResponse.
+	URL string `json:"url"`
+}
+
+func (sp StageGatePolicy) String() (string, error) {
+	s, err := json.Marshal(sp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalStageGatePolicy will unmarshal payload to stage gate policy.
+func UnmarshalStageGatePolicy(payload string) (*StageGatePolicy, error) {
+	var sp StageGatePolicy
+	if err := json.Unmarshal([]byte(payload), &sp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stage gate policy %q: %q", payload, err)
+	}
+	return &sp, nil
+}
+
+// Validate validates the stage gate policy.
+func (sp StageGatePolicy) Validate() error {
+	nameSet := make(map[string]bool)
+	for _, gate := range sp.GateList {
+		if gate.Name == "" {
+			return fmt.Errorf("stage gate name must not be empty")
+		}
+		if nameSet[gate.Name] {
+			return fmt.Errorf("duplicate stage gate name %q", gate.Name)
+		}
+		nameSet[gate.Name] = true
+		u, err := url.Parse(gate.URL)
+		if err != nil {
+			return fmt.Errorf("invalid URL %q of stage gate %q: %w", gate.URL, gate.Name, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid URL %q of stage gate %q, must be an HTTP or HTTPS URL", gate.URL, gate.Name)
+		}
+	}
+	return nil
+}
+
+// ReplicationLagPolicy is the policy configuration for the replication lag checked before the cutover tasks in an environment,
+// i.e. the gh-ost cutover and the PITR swap. The cutover is blocked until the lag is under the threshold.
+type ReplicationLagPolicy struct {
+	// MaxLagSeconds is the maximum replica lag and the maximum delay of the pending binlog events allowed to cut over.
+	MaxLagSeconds int64 `json:"maxLagSeconds"`
+}
+
+func (rp ReplicationLagPolicy) String() (string, error) {
+	s, err := json.Marshal(rp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalReplicationLagPolicy will unmarshal payload to replication lag policy.
+func UnmarshalReplicationLagPolicy(payload string) (*ReplicationLagPolicy, error) {
+	var rp ReplicationLagPolicy
+	if err := json.Unmarshal([]byte(payload), &rp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal replication lag policy %q: %q", payload, err)
+	}
+	return &rp, nil
+}
+
+// AccessChangePolicy is the policy configuration for reviewing the access changes in an environment.
+type AccessChangePolicy struct {
+	// DisallowAllPrivileges rejects granting ALL PRIVILEGES.
+	DisallowAllPrivileges bool `json:"disallowAllPrivileges"`
+	// DisallowWildcardHost rejects the MySQL users with the host containing "%".
+	DisallowWildcardHost bool `json:"disallowWildcardHost"`
+	// DisallowGrantOption rejects granting with the GRANT OPTION.
+	DisallowGrantOption bool `json:"disallowGrantOption"`
+}
+
+func (ap AccessChangePolicy) String() (string, error) {
+	s, err := json.Marshal(ap)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalAccessChangePolicy will unmarshal payload to access change policy.
+func UnmarshalAccessChangePolicy(payload string) (*AccessChangePolicy, error) {
+	var ap AccessChangePolicy
+	if err := json.Unmarshal([]byte(payload), &ap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal access change policy %q: %q", payload, err)
+	}
+	return &ap, nil
+}
+
+// ParameterBaselinePolicy is the policy configuration for the expected parameters of the instances in an environment.
+// An anomaly is raised if the synced parameters of an instance drift from the baseline.
+type ParameterBaselinePolicy struct {
+	ParameterList []ParameterBaseline `json:"parameterList"`
+}
+
+// ParameterBaseline is the expected value of a parameter of the instances of the engine.
+type ParameterBaseline struct {
+	Engine db.Type `json:"engine"`
+	// Name is the Postgres GUC or the MySQL system variable name, e.g. max_connections.
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (pp ParameterBaselinePolicy) String() (string, error) {
+	s, err := json.Marshal(pp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalParameterBaselinePolicy will unmarshal payload to parameter baseline policy.
+func UnmarshalParameterBaselinePolicy(payload string) (*ParameterBaselinePolicy, error) {
+	var pp ParameterBaselinePolicy
+	if err := json.Unmarshal([]byte(payload), &pp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal parameter baseline policy %q: %q", payload, err)
+	}
+	return &pp, nil
+}
+
+// Validate validates the parameter baseline policy.
+func (pp ParameterBaselinePolicy) Validate() error {
+	keySet := make(map[string]bool)
+	for _, parameter := range pp.ParameterList {
+		if parameter.Engine == "" {
+			return fmt.Errorf("engine of parameter %q must not be empty", parameter.Name)
+		}
+		if parameter.Name == "" {
+			return fmt.Errorf("parameter name must not be empty")
+		}
+		key := fmt.Sprintf("%s/%s", parameter.Engine, strings.ToLower(parameter.Name))
+		if keySet[key] {
+			return fmt.Errorf("duplicate %s parameter %q", parameter.Engine, parameter.Name)
+		}
+		keySet[key] = true
+	}
+	return nil
+}
+
+// SessionSettingPolicy is the policy configuration for the session settings of the tasks executing statements in an environment.
+type SessionSettingPolicy struct {
+	// DefaultList is the default session settings of the engines, which are applied unless the task specifies them.
+	DefaultList []SessionSettingDefault `json:"defaultList"`
+	// MaxLockTimeoutMs caps the lock timeout of the tasks, 0 means no cap.
+	// The tasks without the lock timeout wait for the locks up to the cap.
+	MaxLockTimeoutMs int64 `json:"maxLockTimeoutMs"`
+	// DisallowForeignKeyChecksOff rejects the tasks turning off the MySQL foreign key checks.
+	DisallowForeignKeyChecksOff bool `json:"disallowForeignKeyChecksOff"`
+}
+
+// SessionSettingDefault is the default session settings of the tasks on the instances of the engine.
+type SessionSettingDefault struct {
+	Engine  db.Type           `json:"engine"`
+	Setting db.SessionSetting `json:"setting"`
+}
+
+func (sp SessionSettingPolicy) String() (string, error) {
+	s, err := json.Marshal(sp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalSessionSettingPolicy will unmarshal payload to session setting policy.
+func UnmarshalSessionSettingPolicy(payload string) (*SessionSettingPolicy, error) {
+	var sp SessionSettingPolicy
+	if err := json.Unmarshal([]byte(payload), &sp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session setting policy %q: %q", payload, err)
+	}
+	return &sp, nil
+}
+
+// Validate validates the session setting policy.
+func (sp SessionSettingPolicy) Validate() error {
+	if sp.MaxLockTimeoutMs < 0 {
+		return fmt.Errorf("maximum lock timeout must not be negative, got %d", sp.MaxLockTimeoutMs)
+	}
+	engineSet := make(map[db.Type]bool)
+	for _, d := range sp.DefaultList {
+		if d.Engine == "" {
+			return fmt.Errorf("engine of the default session settings must not be empty")
+		}
+		if engineSet[d.Engine] {
+			return fmt.Errorf("duplicate default session settings for %s", d.Engine)
+		}
+		engineSet[d.Engine] = true
+		if err := d.Setting.Validate(d.Engine); err != nil {
+			return fmt.Errorf("invalid default session settings for %s: %w", d.Engine, err)
+		}
+		if sp.MaxLockTimeoutMs > 0 && d.Setting.LockTimeoutMs > sp.MaxLockTimeoutMs {
+			return fmt.Errorf("default lock timeout %dms for %s exceeds the maximum %dms", d.Setting.LockTimeoutMs, d.Engine, sp.MaxLockTimeoutMs)
+		}
+		if sp.DisallowForeignKeyChecksOff && d.Setting.ForeignKeyChecks != nil && !*d.Setting.ForeignKeyChecks {
+			return fmt.Errorf("default session settings for %s turn off the foreign key checks, which is disallowed", d.Engine)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the session settings of the task on the instance of the engine, with the unset ones taken from the engine defaults.
+// It returns an error if the settings exceed the caps of the policy.
+func (sp SessionSettingPolicy) Resolve(dbType db.Type, setting *db.SessionSetting) (*db.SessionSetting, error) {
+	resolved := db.SessionSetting{}
+	for _, d := range sp.DefaultList {
+		if d.Engine == dbType {
+			resolved = d.Setting
+			break
+		}
+	}
+	if setting != nil {
+		if setting.IsolationLevel != "" {
+			resolved.IsolationLevel = setting.IsolationLevel
+		}
+		if setting.LockTimeoutMs != 0 {
+			resolved.LockTimeoutMs = setting.LockTimeoutMs
+		}
+		if setting.SearchPath != "" {
+			resolved.SearchPath = setting.SearchPath
+		}
+		if setting.SQLMode != nil {
+			resolved.SQLMode = setting.SQLMode
+		}
+		if setting.ForeignKeyChecks != nil {
+			resolved.ForeignKeyChecks = setting.ForeignKeyChecks
+		}
+	}
+
+	if sp.MaxLockTimeoutMs > 0 {
+		if resolved.LockTimeoutMs > sp.MaxLockTimeoutMs {
+			return nil, fmt.Errorf("lock timeout %dms exceeds the maximum %dms of the environment", resolved.LockTimeoutMs, sp.MaxLockTimeoutMs)
+		}
+		if resolved.LockTimeoutMs == 0 {
+			resolved.LockTimeoutMs = sp.MaxLockTimeoutMs
+		}
+	}
+	if sp.DisallowForeignKeyChecksOff && resolved.ForeignKeyChecks != nil && !*resolved.ForeignKeyChecks {
+		return nil, fmt.Errorf("turning off the foreign key checks is disallowed in the environment")
+	}
+	if err := resolved.Validate(dbType); err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// SQLEditorQueryPolicy is the policy configuration for the SQL editor queries in an environment.
+// The limits are enforced by the server for each query, 0 means no limit.
+type SQLEditorQueryPolicy struct {
+	// MaxConcurrentQueryPerUser is the maximum number of the queries a user runs at the same time in the environment.
+	MaxConcurrentQueryPerUser int `json:"maxConcurrentQueryPerUser"`
+	// MaxExecutionSeconds is the maximum execution time of a query, the query is canceled after it.
+	MaxExecutionSeconds int `json:"maxExecutionSeconds"`
+	// AutoLimit is the LIMIT appended to the SELECT statements without one, and it also caps the rows returned.
+	AutoLimit int `json:"autoLimit"`
+}
+
+func (qp SQLEditorQueryPolicy) String() (string, error) {
+	s, err := json.Marshal(qp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalSQLEditorQueryPolicy will unmarshal payload to SQL editor query policy.
+func UnmarshalSQLEditorQueryPolicy(payload string) (*SQLEditorQueryPolicy, error) {
+	var qp SQLEditorQueryPolicy
+	if err := json.Unmarshal([]byte(payload), &qp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SQL editor query policy %q: %q", payload, err)
+	}
+	return &qp, nil
+}
+
+// Validate validates the SQL editor query policy.
+func (qp SQLEditorQueryPolicy) Validate() error {
+	if qp.MaxConcurrentQueryPerUser < 0 {
+		return fmt.Errorf("maximum concurrent queries per user must not be negative, got %d", qp.MaxConcurrentQueryPerUser)
+	}
+	if qp.MaxExecutionSeconds < 0 {
+		return fmt.Errorf("maximum execution seconds must not be negative, got %d", qp.MaxExecutionSeconds)
+	}
+	if qp.AutoLimit < 0 {
+		return fmt.Errorf("auto limit must not be negative, got %d", qp.AutoLimit)
+	}
+	return nil
+}
+
+// DataMaskingPolicyMaskedValue is the value replacing the masked values in the query results.
+const DataMaskingPolicyMaskedValue = "******"
+
+// DataMaskingPolicy is the policy configuration for masking the sensitive columns in the query results of an environment,
+// which applies to the SQL editor, the dashboard tiles and the proxied connections.
+// The columns are matched by the names in the result rather than the table schema, so an aliased column isn't masked.
+// It's a guardrail for the ad-hoc queries, and the truly secret columns should be kept out of reach of the read-only data source.
+type DataMaskingPolicy struct {
+	// ColumnList is the names of the masked columns, matched case-insensitively.
+	ColumnList []string `json:"columnList"`
+}
+
+func (mp DataMaskingPolicy) String() (string, error) {
+	s, err := json.Marshal(mp)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalDataMaskingPolicy will unmarshal payload to data masking policy.
+func UnmarshalDataMaskingPolicy(payload string) (*DataMaskingPolicy, error) {
+	var mp DataMaskingPolicy
+	if err := json.Unmarshal([]byte(payload), &mp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data masking policy %q: %q", payload, err)
+	}
+	return &mp, nil
+}
+
+// Validate validates the data masking policy.
+func (mp DataMaskingPolicy) Validate() error {
+	columnSet := make(map[string]bool)
+	for _, column := range mp.ColumnList {
+		if strings.TrimSpace(column) == "" {
+			return fmt.Errorf("masked column name must not be empty")
+		}
+		key := strings.ToLower(column)
+		if columnSet[key] {
+			return fmt.Errorf("duplicate masked column %q", column)
+		}
+		columnSet[key] = true
+	}
+	return nil
+}
+
+// IsMasked returns whether the column of the query result is masked.
+func (mp DataMaskingPolicy) IsMasked(column string) bool {
+	for _, masked := range mp.ColumnList {
+		if strings.EqualFold(masked, column) {
+			return true
+		}
+	}
+	return false
+}
+
 // UnmarshalSQLReviewPolicy will unmarshal payload to SQL review policy.
 func UnmarshalSQLReviewPolicy(payload string) (*advisor.SQLReviewPolicy, error) {
 	var sr advisor.SQLReviewPolicy
@@ -211,6 +708,19 @@ func ValidatePolicy(pType PolicyType, payload string) error {
 		if pa.Value != PipelineApprovalValueManualNever && pa.Value != PipelineApprovalValueManualAlways {
 			return fmt.Errorf("invalid approval policy value: %q", payload)
 		}
+		taskTypeSet := make(map[TaskType]bool)
+		for _, approval := range pa.TaskApprovalList {
+			if approval.TaskType == "" {
+				return fmt.Errorf("missing task type in approval policy: %q", payload)
+			}
+			if taskTypeSet[approval.TaskType] {
+				return fmt.Errorf("duplicate task type %q in approval policy", approval.TaskType)
+			}
+			taskTypeSet[approval.TaskType] = true
+			if approval.Value != PipelineApprovalValueManualNever && approval.Value != PipelineApprovalValueManualAlways {
+				return fmt.Errorf("invalid approval policy value %q for task type %q", approval.Value, approval.TaskType)
+			}
+		}
 	case PolicyTypeBackupPlan:
 		bp, err := UnmarshalBackupPlanPolicy(payload)
 		if err != nil {
@@ -227,6 +737,78 @@ func ValidatePolicy(pType PolicyType, payload string) error {
 		if err := sr.Validate(); err != nil {
 			return fmt.Errorf("invalid SQL review policy: %w", err)
 		}
+	case PolicyTypeDataSource:
+		dp, err := UnmarshalDataSourcePolicy(payload)
+		if err != nil {
+			return err
+		}
+		if dp.Value != DataSourcePolicyValueReadOnlyPreferred && dp.Value != DataSourcePolicyValueReadOnlyRequired {
+			return fmt.Errorf("invalid data source policy value: %q", payload)
+		}
+	case PolicyTypeDatabasePurge:
+		dp, err := UnmarshalDatabasePurgePolicy(payload)
+		if err != nil {
+			return err
+		}
+		if dp.PurgeAfterDays < 0 {
+			return fmt.Errorf("invalid database purge policy days: %d", dp.PurgeAfterDays)
+		}
+	case PolicyTypeStatisticsRefresh:
+		if _, err := UnmarshalStatisticsRefreshPolicy(payload); err != nil {
+			return err
+		}
+	case PolicyTypeStageGate:
+		sp, err := UnmarshalStageGatePolicy(payload)
+		if err != nil {
+			return err
+		}
+		if err := sp.Validate(); err != nil {
+			return fmt.Errorf("invalid stage gate policy: %w", err)
+		}
+	case PolicyTypeReplicationLag:
+		rp, err := UnmarshalReplicationLagPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if rp.MaxLagSeconds <= 0 {
+			return fmt.Errorf("invalid replication lag policy max lag seconds: %d", rp.MaxLagSeconds)
+		}
+	case PolicyTypeAccessChange:
+		if _, err := UnmarshalAccessChangePolicy(payload); err != nil {
+			return err
+		}
+	case PolicyTypeParameterBaseline:
+		pp, err := UnmarshalParameterBaselinePolicy(payload)
+		if err != nil {
+			return err
+		}
+		if err := pp.Validate(); err != nil {
+			return fmt.Errorf("invalid parameter baseline policy: %w", err)
+		}
+	case PolicyTypeSessionSetting:
+		sp, err := UnmarshalSessionSettingPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if err := sp.Validate(); err != nil {
+			return fmt.Errorf("invalid session setting policy: %w", err)
+		}
+	case PolicyTypeSQLEditorQuery:
+		qp, err := UnmarshalSQLEditorQueryPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if err := qp.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL editor query policy: %w", err)
+		}
+	case PolicyTypeDataMasking:
+		mp, err := UnmarshalDataMaskingPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if err := mp.Validate(); err != nil {
+			return fmt.Errorf("invalid data masking policy: %w", err)
+		}
 	}
 	return nil
 }
@@ -246,6 +828,42 @@ func GetDefaultPolicy(pType PolicyType) (string, error) {
 	case PolicyTypeSQLReview:
 		// TODO(ed): we may need to define the default SQL review policy payload in the PR of policy data migration.
 		return "{}", nil
+	case PolicyTypeDataSource:
+		return DataSourcePolicy{
+			Value: DataSourcePolicyValueReadOnlyPreferred,
+		}.String()
+	case PolicyTypeDatabasePurge:
+		return DatabasePurgePolicy{
+			PurgeAfterDays: 0,
+		}.String()
+	case PolicyTypeStatisticsRefresh:
+		return StatisticsRefreshPolicy{
+			Enabled: false,
+		}.String()
+	case PolicyTypeStageGate:
+		return StageGatePolicy{
+			GateList: []StageGate{},
+		}.String()
+	case PolicyTypeReplicationLag:
+		return ReplicationLagPolicy{
+			MaxLagSeconds: DefaultReplicationLagMaxSeconds,
+		}.String()
+	case PolicyTypeAccessChange:
+		return AccessChangePolicy{}.String()
+	case PolicyTypeParameterBaseline:
+		return ParameterBaselinePolicy{
+			ParameterList: []ParameterBaseline{},
+		}.String()
+	case PolicyTypeSessionSetting:
+		return SessionSettingPolicy{
+			DefaultList: []SessionSettingDefault{},
+		}.String()
+	case PolicyTypeSQLEditorQuery:
+		return SQLEditorQueryPolicy{}.String()
+	case PolicyTypeDataMasking:
+		return DataMaskingPolicy{
+			ColumnList: []string{},
+		}.String()
 	}
 	return "", nil
 }
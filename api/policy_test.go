@@ -0,0 +1,143 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestValidateStageGatePolicy(t *testing.T) {
+	tests := []struct {
+		payload string
+		wantErr bool
+	}{
+		{payload: `{"gateList":[]}`},
+		{payload: `{"gateList":[{"name":"QA suite","url":"https://qa.example.com/gate"},{"name":"Load test","url":"http://10.0.0.1:8080/gate"}]}`},
+		{payload: `{"gateList":[{"name":"","url":"https://qa.example.com/gate"}]}`, wantErr: true},
+		{payload: `{"gateList":[{"name":"QA suite","url":"https://qa.example.com/a"},{"name":"QA suite","url":"https://qa.example.com/b"}]}`, wantErr: true},
+		{payload: `{"gateList":[{"name":"QA suite","url":"ftp://qa.example.com/gate"}]}`, wantErr: true},
+		{payload: `{"gateList":[{"name":"QA suite","url":"qa.example.com/gate"}]}`, wantErr: true},
+		{payload: `{"gateList":`, wantErr: true},
+	}
+	for _, test := range tests {
+		err := ValidatePolicy(PolicyTypeStageGate, test.payload)
+		if test.wantErr {
+			require.Error(t, err, test.payload)
+		} else {
+			require.NoError(t, err, test.payload)
+		}
+	}
+
+	payload, err := GetDefaultPolicy(PolicyTypeStageGate)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeStageGate, payload))
+}
+
+func TestValidateReplicationLagPolicy(t *testing.T) {
+	require.NoError(t, ValidatePolicy(PolicyTypeReplicationLag, `{"maxLagSeconds":30}`))
+	require.Error(t, ValidatePolicy(PolicyTypeReplicationLag, `{"maxLagSeconds":0}`))
+	require.Error(t, ValidatePolicy(PolicyTypeReplicationLag, `{"maxLagSeconds":-1}`))
+	require.Error(t, ValidatePolicy(PolicyTypeReplicationLag, `{"maxLagSeconds":`))
+
+	payload, err := GetDefaultPolicy(PolicyTypeReplicationLag)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeReplicationLag, payload))
+}
+
+func TestValidateParameterBaselinePolicy(t *testing.T) {
+	require.NoError(t, ValidatePolicy(PolicyTypeParameterBaseline, `{"parameterList":[{"engine":"MYSQL","name":"sql_mode","value":"STRICT_TRANS_TABLES"},{"engine":"POSTGRES","name":"max_connections","value":"200"}]}`))
+	require.NoError(t, ValidatePolicy(PolicyTypeParameterBaseline, `{"parameterList":[{"engine":"MYSQL","name":"max_connections","value":"100"},{"engine":"POSTGRES","name":"max_connections","value":"200"}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypeParameterBaseline, `{"parameterList":[{"engine":"MYSQL","name":"max_connections","value":"100"},{"engine":"MYSQL","name":"MAX_CONNECTIONS","value":"200"}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypeParameterBaseline, `{"parameterList":[{"engine":"","name":"max_connections","value":"100"}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypeParameterBaseline, `{"parameterList":[{"engine":"MYSQL","name":"","value":"100"}]}`))
+
+	payload, err := GetDefaultPolicy(PolicyTypeParameterBaseline)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeParameterBaseline, payload))
+}
+
+func TestValidateSessionSettingPolicy(t *testing.T) {
+	require.NoError(t, ValidatePolicy(PolicyTypeSessionSetting, `{"defaultList":[{"engine":"POSTGRES","setting":{"lockTimeoutMs":3000,"searchPath":"app, public"}}],"maxLockTimeoutMs":10000}`))
+	require.Error(t, ValidatePolicy(PolicyTypeSessionSetting, `{"defaultList":[{"engine":"POSTGRES","setting":{"lockTimeoutMs":30000}}],"maxLockTimeoutMs":10000}`))
+	require.Error(t, ValidatePolicy(PolicyTypeSessionSetting, `{"defaultList":[{"engine":"MYSQL","setting":{"searchPath":"public"}}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypeSessionSetting, `{"defaultList":[{"engine":"MYSQL","setting":{}},{"engine":"MYSQL","setting":{}}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypeSessionSetting, `{"maxLockTimeoutMs":-1}`))
+	require.NoError(t, ValidatePolicy(PolicyTypeSessionSetting, `{"defaultList":[{"engine":"MARIADB","setting":{"sqlMode":"STRICT_TRANS_TABLES","foreignKeyChecks":false}}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypeSessionSetting, `{"defaultList":[{"engine":"POSTGRES","setting":{"sqlMode":"STRICT_TRANS_TABLES"}}]}`))
+
+	payload, err := GetDefaultPolicy(PolicyTypeSessionSetting)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeSessionSetting, payload))
+}
+
+func TestValidateSQLEditorQueryPolicy(t *testing.T) {
+	require.NoError(t, ValidatePolicy(PolicyTypeSQLEditorQuery, `{"maxConcurrentQueryPerUser":2,"maxExecutionSeconds":30,"autoLimit":1000}`))
+	require.Error(t, ValidatePolicy(PolicyTypeSQLEditorQuery, `{"maxConcurrentQueryPerUser":-1}`))
+	require.Error(t, ValidatePolicy(PolicyTypeSQLEditorQuery, `{"maxExecutionSeconds":-1}`))
+	require.Error(t, ValidatePolicy(PolicyTypeSQLEditorQuery, `{"autoLimit":-1}`))
+
+	payload, err := GetDefaultPolicy(PolicyTypeSQLEditorQuery)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeSQLEditorQuery, payload))
+}
+
+func TestValidateDataMaskingPolicy(t *testing.T) {
+	require.NoError(t, ValidatePolicy(PolicyTypeDataMasking, `{"columnList":["email","ssn"]}`))
+	require.Error(t, ValidatePolicy(PolicyTypeDataMasking, `{"columnList":[" "]}`))
+	require.Error(t, ValidatePolicy(PolicyTypeDataMasking, `{"columnList":["email","EMAIL"]}`))
+
+	payload, err := GetDefaultPolicy(PolicyTypeDataMasking)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeDataMasking, payload))
+
+	policy := DataMaskingPolicy{ColumnList: []string{"Email"}}
+	require.True(t, policy.IsMasked("email"))
+	require.False(t, policy.IsMasked("email_verified"))
+}
+
+func TestSessionSettingPolicyResolve(t *testing.T) {
+	sqlMode := "STRICT_TRANS_TABLES"
+	off := false
+	policy := SessionSettingPolicy{
+		DefaultList: []SessionSettingDefault{
+			{Engine: db.MySQL, Setting: db.SessionSetting{IsolationLevel: db.IsolationReadCommitted, SQLMode: &sqlMode}},
+		},
+		MaxLockTimeoutMs: 10000,
+	}
+
+	setting, err := policy.Resolve(db.MySQL, &db.SessionSetting{LockTimeoutMs: 5000})
+	require.NoError(t, err)
+	require.Equal(t, &db.SessionSetting{IsolationLevel: db.IsolationReadCommitted, LockTimeoutMs: 5000, SQLMode: &sqlMode}, setting)
+
+	// The tasks without the lock timeout wait up to the cap.
+	setting, err = policy.Resolve(db.Postgres, nil)
+	require.NoError(t, err)
+	require.Equal(t, &db.SessionSetting{LockTimeoutMs: 10000}, setting)
+
+	_, err = policy.Resolve(db.MySQL, &db.SessionSetting{LockTimeoutMs: 20000})
+	require.Error(t, err)
+	_, err = policy.Resolve(db.Postgres, &db.SessionSetting{ForeignKeyChecks: &off})
+	require.Error(t, err)
+
+	policy.DisallowForeignKeyChecksOff = true
+	_, err = policy.Resolve(db.MySQL, &db.SessionSetting{ForeignKeyChecks: &off})
+	require.Error(t, err)
+}
+
+func TestPipelineApprovalPolicyTaskApproval(t *testing.T) {
+	require.NoError(t, ValidatePolicy(PolicyTypePipelineApproval, `{"value":"MANUAL_APPROVAL_NEVER","taskApprovalList":[{"taskType":"bb.task.database.data.update","value":"MANUAL_APPROVAL_ALWAYS"}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypePipelineApproval, `{"value":"MANUAL_APPROVAL_NEVER","taskApprovalList":[{"taskType":"bb.task.database.data.update","value":"SOMETIMES"}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypePipelineApproval, `{"value":"MANUAL_APPROVAL_NEVER","taskApprovalList":[{"taskType":"","value":"MANUAL_APPROVAL_ALWAYS"}]}`))
+	require.Error(t, ValidatePolicy(PolicyTypePipelineApproval, `{"value":"MANUAL_APPROVAL_NEVER","taskApprovalList":[{"taskType":"bb.task.database.data.update","value":"MANUAL_APPROVAL_ALWAYS"},{"taskType":"bb.task.database.data.update","value":"MANUAL_APPROVAL_NEVER"}]}`))
+
+	policy := &PipelineApprovalPolicy{
+		Value: PipelineApprovalValueManualNever,
+		TaskApprovalList: []TaskApproval{
+			{TaskType: TaskDatabaseDataUpdate, Value: PipelineApprovalValueManualAlways},
+		},
+	}
+	require.Equal(t, PipelineApprovalValueManualNever, policy.GetValue(TaskDatabaseSchemaUpdate))
+	require.Equal(t, PipelineApprovalValueManualAlways, policy.GetValue(TaskDatabaseDataUpdate))
+}
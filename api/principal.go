@@ -119,4 +119,6 @@ type PrincipalPatch struct {
 	Name         *string `jsonapi:"attr,name"`
 	Password     *string `jsonapi:"attr,password"`
 	PasswordHash *string
+	// Email is only changed when erasing the user, the users can't change their emails.
+	Email *string
 }
@@ -0,0 +1,37 @@
+package api
+
+// PrivilegeCheckFeature is the feature requiring privileges of the admin data source account.
+type PrivilegeCheckFeature string
+
+const (
+	// PrivilegeCheckSyncSchema is the feature syncing the databases and the schemas from the instance.
+	PrivilegeCheckSyncSchema PrivilegeCheckFeature = "SYNC_SCHEMA"
+	// PrivilegeCheckCreateDatabase is the feature creating databases on the instance.
+	PrivilegeCheckCreateDatabase PrivilegeCheckFeature = "CREATE_DATABASE"
+	// PrivilegeCheckBackup is the feature backing up the databases.
+	PrivilegeCheckBackup PrivilegeCheckFeature = "BACKUP"
+	// PrivilegeCheckPITR is the feature restoring the databases to a point in time from the binlog.
+	PrivilegeCheckPITR PrivilegeCheckFeature = "PITR"
+)
+
+// PrivilegeCheck is the API message for the privilege check of a feature.
+type PrivilegeCheck struct {
+	Feature PrivilegeCheckFeature `json:"feature"`
+	// Status is SUCCESS if the account has all the required privileges, ERROR if any is missing.
+	Status ConnectionCheckStatus `json:"status"`
+	// RequiredList is the privileges the feature requires.
+	RequiredList []string `json:"requiredList"`
+	// MissingList is the required privileges the account doesn't have.
+	MissingList []string `json:"missingList"`
+	Detail      string   `json:"detail"`
+	// Suggestion is the statement granting the missing privileges, empty if nothing is missing.
+	Suggestion string `json:"suggestion"`
+}
+
+// InstancePrivilegeCheck is the API message for the privilege checks of the admin data source account of an instance.
+type InstancePrivilegeCheck struct {
+	InstanceID int `json:"instanceId"`
+	// Username is the account of the admin data source.
+	Username  string            `json:"username"`
+	CheckList []*PrivilegeCheck `json:"checkList"`
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 // DefaultProjectID is the ID for the default project.
@@ -68,6 +69,14 @@ type Project struct {
 	// Empty value means {{DB_NAME}}.
 	DBNameTemplate string              `jsonapi:"attr,dbNameTemplate"`
 	RoleProvider   ProjectRoleProvider `jsonapi:"attr,roleProvider"`
+	// PinnedNote is the note shown on top of the project pages, e.g. the maintenance notice.
+	PinnedNote string `jsonapi:"attr,pinnedNote"`
+	// DBNamePattern is the regular expression the names of the databases created in the project must match.
+	// Empty value means no restriction.
+	DBNamePattern string `jsonapi:"attr,dbNamePattern"`
+	// AllowedEnvironmentList is the JSON encoded IDs of the environments the project can deploy to, e.g. "[101,102]".
+	// Empty list means no restriction.
+	AllowedEnvironmentList string `jsonapi:"attr,allowedEnvironmentList"`
 }
 
 // ProjectCreate is the API message for creating a project.
@@ -92,6 +101,7 @@ type ProjectFind struct {
 	RowStatus *RowStatus
 
 	// Domain specific fields
+	Key *string
 	// If present, will only find project containing PrincipalID as an active member
 	PrincipalID *int
 }
@@ -114,10 +124,44 @@ type ProjectPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	Name         *string              `jsonapi:"attr,name"`
-	Key          *string              `jsonapi:"attr,key"`
-	WorkflowType *ProjectWorkflowType `jsonapi:"attr,workflowType"`
-	RoleProvider *string              `jsonapi:"attr,roleProvider"`
+	Name          *string              `jsonapi:"attr,name"`
+	Key           *string              `jsonapi:"attr,key"`
+	WorkflowType  *ProjectWorkflowType `jsonapi:"attr,workflowType"`
+	RoleProvider  *string              `jsonapi:"attr,roleProvider"`
+	PinnedNote    *string              `jsonapi:"attr,pinnedNote"`
+	DBNamePattern *string              `jsonapi:"attr,dbNamePattern"`
+	// AllowedEnvironmentList is the JSON encoded IDs of the allowed environments, empty list removes the restriction.
+	AllowedEnvironmentList *string `jsonapi:"attr,allowedEnvironmentList"`
+}
+
+// ParseProjectAllowedEnvironmentList parses the JSON encoded IDs of the environments the project can deploy to.
+// Empty string or list means no restriction.
+func ParseProjectAllowedEnvironmentList(s string) ([]int, error) {
+	var environmentIDList []int
+	if s == "" {
+		return environmentIDList, nil
+	}
+	if err := json.Unmarshal([]byte(s), &environmentIDList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal allowed environment list %q, error: %w", s, err)
+	}
+	return environmentIDList, nil
+}
+
+// IsEnvironmentAllowed returns whether the project can deploy to the environment.
+func (project *Project) IsEnvironmentAllowed(environmentID int) (bool, error) {
+	environmentIDList, err := ParseProjectAllowedEnvironmentList(project.AllowedEnvironmentList)
+	if err != nil {
+		return false, err
+	}
+	if len(environmentIDList) == 0 {
+		return true, nil
+	}
+	for _, id := range environmentIDList {
+		if id == environmentID {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 var (
@@ -138,6 +182,11 @@ var (
 		EnvironmentToken:  false,
 		"{{DESCRIPTION}}": false,
 	}
+	// For the migration file formats other than Bytebase, the file path template only matches the directory.
+	migrationDirectoryTemplateTokens = map[string]bool{
+		DBNameToken:      true,
+		EnvironmentToken: false,
+	}
 	schemaPathTemplateTokens = map[string]bool{
 		DBNameToken:      true,
 		EnvironmentToken: false,
@@ -177,6 +226,42 @@ func ValidateRepositoryFilePathTemplate(filePathTemplate string, tenantMode Proj
 	return nil
 }
 
+// ValidateRepositoryFileFormat validates the repository migration file format and the file path template for the format.
+func ValidateRepositoryFileFormat(fileFormat db.MigrationFileFormat, filePathTemplate string, tenantMode ProjectTenantMode) error {
+	switch fileFormat {
+	case db.MigrationFileFormatBytebase:
+		return ValidateRepositoryFilePathTemplate(filePathTemplate, tenantMode)
+	case db.MigrationFileFormatFlyway, db.MigrationFileFormatLiquibase, db.MigrationFileFormatGolangMigrate:
+	default:
+		return fmt.Errorf("unsupported file format %q", fileFormat)
+	}
+
+	tokens, _ := common.ParseTemplateTokens(filePathTemplate)
+	tokenMap := make(map[string]bool)
+	for _, token := range tokens {
+		tokenMap[token] = true
+	}
+	if tenantMode == TenantModeTenant {
+		if _, ok := tokenMap[EnvironmentToken]; ok {
+			return &common.Error{Code: common.Invalid, Err: fmt.Errorf("%q is not allowed in the template for projects in tenant mode", EnvironmentToken)}
+		}
+	}
+
+	for token, required := range migrationDirectoryTemplateTokens {
+		if required {
+			if _, ok := tokenMap[token]; !ok {
+				return fmt.Errorf("missing %s in file path template", token)
+			}
+		}
+	}
+	for token := range tokenMap {
+		if _, ok := migrationDirectoryTemplateTokens[token]; !ok {
+			return fmt.Errorf("token %s is not allowed in file path template for file format %s, the version is derived from the file name", token, fileFormat)
+		}
+	}
+	return nil
+}
+
 // ValidateRepositorySchemaPathTemplate validates the repository schema path template.
 func ValidateRepositorySchemaPathTemplate(schemaPathTemplate string, tenantMode ProjectTenantMode) error {
 	if schemaPathTemplate == "" {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/stretchr/testify/require"
 )
 
@@ -89,6 +90,63 @@ func TestValidateRepositoryFilePathTemplate(t *testing.T) {
 	}
 }
 
+func TestValidateRepositoryFileFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileFormat db.MigrationFileFormat
+		template   string
+		tenantMode ProjectTenantMode
+		errPart    string
+	}{
+		{
+			"Bytebase",
+			db.MigrationFileFormatBytebase,
+			"{{DB_NAME}}_{{TYPE}}.sql",
+			TenantModeDisabled,
+			"missing {{VERSION}}",
+		}, {
+			"Flyway",
+			db.MigrationFileFormatFlyway,
+			"{{ENV_NAME}}/{{DB_NAME}}",
+			TenantModeDisabled,
+			"",
+		}, {
+			"Liquibase with {{VERSION}}",
+			db.MigrationFileFormatLiquibase,
+			"{{DB_NAME}}/{{VERSION}}.xml",
+			TenantModeDisabled,
+			"token {{VERSION}} is not allowed",
+		}, {
+			"golang-migrate missing {{DB_NAME}}",
+			db.MigrationFileFormatGolangMigrate,
+			"migrations",
+			TenantModeDisabled,
+			"missing {{DB_NAME}}",
+		}, {
+			"Tenant mode {{ENV_NAME}}",
+			db.MigrationFileFormatFlyway,
+			"{{ENV_NAME}}/{{DB_NAME}}",
+			TenantModeTenant,
+			"not allowed in the template",
+		}, {
+			"Unknown format",
+			db.MigrationFileFormat("ALEMBIC"),
+			"{{DB_NAME}}",
+			TenantModeDisabled,
+			"unsupported file format",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateRepositoryFileFormat(test.fileFormat, test.template, test.tenantMode)
+		if test.errPart == "" {
+			require.NoError(t, err)
+		} else {
+			require.Contains(t, err.Error(), test.errPart)
+		}
+	}
+}
+
 func TestValidateRepositorySchemaPathTemplate(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -341,3 +399,29 @@ func TestGetBaseDatabaseName(t *testing.T) {
 		require.Equal(t, got, test.want)
 	}
 }
+
+func TestProjectIsEnvironmentAllowed(t *testing.T) {
+	tests := []struct {
+		allowedEnvironmentList string
+		environmentID          int
+		want                   bool
+		wantErr                bool
+	}{
+		{"", 101, true, false},
+		{"[]", 101, true, false},
+		{"[101,102]", 102, true, false},
+		{"[101,102]", 103, false, false},
+		{"[101", 101, false, true},
+	}
+
+	for _, test := range tests {
+		project := &Project{AllowedEnvironmentList: test.allowedEnvironmentList}
+		got, err := project.IsEnvironmentAllowed(test.environmentID)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, got, "allowed environment list %q environment %d", test.allowedEnvironmentList, test.environmentID)
+	}
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	// DatabaseNameVariable is the built-in statement variable resolved to the name of the target database.
+	DatabaseNameVariable = "DATABASE_NAME"
+	// TenantIDVariable is the built-in statement variable resolved to the tenant label value of the target database.
+	TenantIDVariable = "TENANT_ID"
+)
+
+var (
+	// projectVariableNameRegexp matches the variable name, e.g. SCHEMA_PREFIX.
+	projectVariableNameRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	// statementVariableRegexp matches the variable placeholder in the statement, e.g. {{SCHEMA_PREFIX}}.
+	statementVariableRegexp = regexp.MustCompile(`{{([A-Z][A-Z0-9_]*)}}`)
+)
+
+// ProjectVariable is the API message for a project variable.
+// The variables are referenced as {{NAME}} in the migration statements of the project
+// and resolved when the statements are executed against each target database.
+type ProjectVariable struct {
+	ID int `jsonapi:"primary,projectVariable"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	Name  string `jsonapi:"attr,name"`
+	Value string `jsonapi:"attr,value"`
+}
+
+// ProjectVariableCreate is the API message for creating a project variable.
+type ProjectVariableCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Name  string `jsonapi:"attr,name"`
+	Value string `jsonapi:"attr,value"`
+}
+
+// ProjectVariableFind is the API message for finding project variables.
+type ProjectVariableFind struct {
+	ID *int
+
+	// Related fields
+	ProjectID *int
+}
+
+func (find *ProjectVariableFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// ProjectVariablePatch is the API message for patching a project variable.
+type ProjectVariablePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Value *string `jsonapi:"attr,value"`
+}
+
+// ProjectVariableDelete is the API message for deleting a project variable.
+type ProjectVariableDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// ValidateProjectVariableName validates the project variable name is well-formed and doesn't shadow a built-in variable.
+func ValidateProjectVariableName(name string) error {
+	if !projectVariableNameRegexp.MatchString(name) {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("variable name %q must start with an uppercase letter and contain only uppercase letters, digits and underscores", name)}
+	}
+	if name == DatabaseNameVariable || name == TenantIDVariable {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("variable name %q is reserved", name)}
+	}
+	return nil
+}
+
+// GetStatementVariableMap returns the values of the statement variables for the target database,
+// including the built-in variables and the project variables.
+// TENANT_ID is only defined if the database has the tenant label.
+func GetStatementVariableMap(databaseName, labelsJSON string, variableList []*ProjectVariable) (map[string]string, error) {
+	variableMap := map[string]string{}
+	for _, variable := range variableList {
+		variableMap[variable.Name] = variable.Value
+	}
+	variableMap[DatabaseNameVariable] = databaseName
+
+	var labels []*DatabaseLabel
+	if labelsJSON != "" {
+		if err := json.Unmarshal([]byte(labelsJSON), &labels); err != nil {
+			return nil, err
+		}
+	}
+	for _, label := range labels {
+		if label.Key == TenantLabelKey && label.Value != "" {
+			variableMap[TenantIDVariable] = label.Value
+		}
+	}
+	return variableMap, nil
+}
+
+// ResolveStatementVariables replaces the {{NAME}} placeholders in the statement with the variable values.
+// It returns an error listing the undefined variables, so that a misspelled placeholder never reaches the database.
+func ResolveStatementVariables(statement string, variableMap map[string]string) (string, error) {
+	if !strings.Contains(statement, "{{") {
+		return statement, nil
+	}
+	var undefinedList []string
+	undefined := make(map[string]bool)
+	resolved := statementVariableRegexp.ReplaceAllStringFunc(statement, func(placeholder string) string {
+		name := placeholder[2 : len(placeholder)-2]
+		value, ok := variableMap[name]
+		if !ok {
+			if !undefined[name] {
+				undefined[name] = true
+				undefinedList = append(undefinedList, name)
+			}
+			return placeholder
+		}
+		return value
+	})
+	if len(undefinedList) > 0 {
+		return "", &common.Error{Code: common.Invalid, Err: fmt.Errorf("undefined statement variables: %s", strings.Join(undefinedList, ", "))}
+	}
+	return resolved, nil
+}
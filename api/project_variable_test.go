@@ -0,0 +1,66 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveStatementVariables(t *testing.T) {
+	variableMap, err := GetStatementVariableMap(
+		"shop_hangzhou",
+		`[{"key":"bb.environment","value":"Prod"},{"key":"bb.tenant","value":"hangzhou"}]`,
+		[]*ProjectVariable{{Name: "SCHEMA_PREFIX", Value: "app"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"SCHEMA_PREFIX": "app",
+		"DATABASE_NAME": "shop_hangzhou",
+		"TENANT_ID":     "hangzhou",
+	}, variableMap)
+
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{
+			statement: "CREATE TABLE t (id INT);",
+			want:      "CREATE TABLE t (id INT);",
+		},
+		{
+			statement: "CREATE TABLE {{SCHEMA_PREFIX}}_orders_{{TENANT_ID}} (id INT);",
+			want:      "CREATE TABLE app_orders_hangzhou (id INT);",
+		},
+		{
+			statement: "GRANT SELECT ON {{DATABASE_NAME}}.* TO 'reader_{{TENANT_ID}}';",
+			want:      "GRANT SELECT ON shop_hangzhou.* TO 'reader_hangzhou';",
+		},
+		{
+			// Only the uppercase names are placeholders.
+			statement: "INSERT INTO t (tpl) VALUES ('{{ name }}');",
+			want:      "INSERT INTO t (tpl) VALUES ('{{ name }}');",
+		},
+	}
+	for _, test := range tests {
+		got, err := ResolveStatementVariables(test.statement, variableMap)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.want, got, test.statement)
+	}
+
+	_, err = ResolveStatementVariables("CREATE TABLE {{TENANT}}_{{REGION}}_{{TENANT}} (id INT);", variableMap)
+	require.EqualError(t, err, "undefined statement variables: TENANT, REGION")
+
+	// The database without the tenant label doesn't define TENANT_ID.
+	variableMap, err = GetStatementVariableMap("shop", "", nil)
+	require.NoError(t, err)
+	_, err = ResolveStatementVariables("CREATE TABLE orders_{{TENANT_ID}} (id INT);", variableMap)
+	require.Error(t, err)
+}
+
+func TestValidateProjectVariableName(t *testing.T) {
+	require.NoError(t, ValidateProjectVariableName("SCHEMA_PREFIX"))
+	require.NoError(t, ValidateProjectVariableName("V2"))
+	for _, name := range []string{"", "schema_prefix", "2V", "_V", "A-B", "DATABASE_NAME", "TENANT_ID"} {
+		require.Error(t, ValidateProjectVariableName(name), name)
+	}
+}
@@ -2,6 +2,23 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+)
+
+// The project webhook activity filters narrowing down the task status updates to a single kind of change,
+// e.g. posting only the task failures to the on-call channel.
+// They are only used by the project webhooks and never recorded as activities.
+const (
+	// ProjectWebhookActivityTaskApproved is the filter for approving tasks.
+	ProjectWebhookActivityTaskApproved ActivityType = "bb.pipeline.task.status.update.approved"
+	// ProjectWebhookActivityTaskStarted is the filter for starting tasks.
+	ProjectWebhookActivityTaskStarted ActivityType = "bb.pipeline.task.status.update.started"
+	// ProjectWebhookActivityTaskCompleted is the filter for completing tasks.
+	ProjectWebhookActivityTaskCompleted ActivityType = "bb.pipeline.task.status.update.completed"
+	// ProjectWebhookActivityTaskFailed is the filter for failing tasks.
+	ProjectWebhookActivityTaskFailed ActivityType = "bb.pipeline.task.status.update.failed"
+	// ProjectWebhookActivityTaskCanceled is the filter for canceling running tasks.
+	ProjectWebhookActivityTaskCanceled ActivityType = "bb.pipeline.task.status.update.canceled"
 )
 
 // ProjectWebhook is the API message for project webhooks.
@@ -48,8 +65,9 @@ type ProjectWebhookFind struct {
 	ID *int
 
 	// Related fields
-	ProjectID    *int
-	ActivityType *ActivityType
+	ProjectID *int
+	// ActivityTypeList finds the webhooks subscribing to any of the activity types.
+	ActivityTypeList []ActivityType
 }
 
 func (find *ProjectWebhookFind) String() string {
@@ -87,3 +105,33 @@ type ProjectWebhookDelete struct {
 type ProjectWebhookTestResult struct {
 	Error string `jsonapi:"attr,error"`
 }
+
+// GetProjectWebhookActivityTypeList returns the activity types for finding the project webhooks subscribing to the activity,
+// which are the activity type and the filter matching the activity if any.
+func GetProjectWebhookActivityTypeList(activityType ActivityType, payload string) ([]ActivityType, error) {
+	typeList := []ActivityType{activityType}
+	if activityType != ActivityPipelineTaskStatusUpdate {
+		return typeList, nil
+	}
+
+	update := &ActivityPipelineTaskStatusUpdatePayload{}
+	if err := json.Unmarshal([]byte(payload), update); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task status update payload: %w", err)
+	}
+	switch update.NewStatus {
+	case TaskPending:
+		switch update.OldStatus {
+		case TaskPendingApproval:
+			typeList = append(typeList, ProjectWebhookActivityTaskApproved)
+		case TaskRunning:
+			typeList = append(typeList, ProjectWebhookActivityTaskCanceled)
+		}
+	case TaskRunning:
+		typeList = append(typeList, ProjectWebhookActivityTaskStarted)
+	case TaskDone:
+		typeList = append(typeList, ProjectWebhookActivityTaskCompleted)
+	case TaskFailed:
+		typeList = append(typeList, ProjectWebhookActivityTaskFailed)
+	}
+	return typeList, nil
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProjectWebhookActivityTypeList(t *testing.T) {
+	tests := []struct {
+		activityType ActivityType
+		payload      string
+		want         []ActivityType
+	}{
+		{
+			activityType: ActivityIssueCreate,
+			payload:      "",
+			want:         []ActivityType{ActivityIssueCreate},
+		},
+		{
+			activityType: ActivityPipelineTaskStatusUpdate,
+			payload:      `{"taskId":1,"oldStatus":"PENDING_APPROVAL","newStatus":"PENDING"}`,
+			want:         []ActivityType{ActivityPipelineTaskStatusUpdate, ProjectWebhookActivityTaskApproved},
+		},
+		{
+			activityType: ActivityPipelineTaskStatusUpdate,
+			payload:      `{"taskId":1,"oldStatus":"RUNNING","newStatus":"PENDING"}`,
+			want:         []ActivityType{ActivityPipelineTaskStatusUpdate, ProjectWebhookActivityTaskCanceled},
+		},
+		{
+			activityType: ActivityPipelineTaskStatusUpdate,
+			payload:      `{"taskId":1,"oldStatus":"RUNNING","newStatus":"FAILED"}`,
+			want:         []ActivityType{ActivityPipelineTaskStatusUpdate, ProjectWebhookActivityTaskFailed},
+		},
+		{
+			activityType: ActivityPipelineTaskStatusUpdate,
+			payload:      `{"taskId":1,"oldStatus":"FAILED","newStatus":"DONE"}`,
+			want:         []ActivityType{ActivityPipelineTaskStatusUpdate, ProjectWebhookActivityTaskCompleted},
+		},
+	}
+	for _, test := range tests {
+		typeList, err := GetProjectWebhookActivityTypeList(test.activityType, test.payload)
+		require.NoError(t, err)
+		require.Equal(t, test.want, typeList)
+	}
+
+	_, err := GetProjectWebhookActivityTypeList(ActivityPipelineTaskStatusUpdate, "")
+	require.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// ProxyConnect is the API message for connecting a client of the agent proxy to a database.
+// The agent terminates the wire protocol of the client, and the server authenticates the user and checks the access,
+// so that the ad-hoc psql and mysql sessions are governed the same as the SQL editor.
+type ProxyConnect struct {
+	// Email and Password are the Bytebase credentials of the user, sent by the client as the user name and the password.
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	// Database is the database name sent by the client, see ParseProxyDatabase.
+	Database string `json:"database"`
+	// Protocol is the wire protocol of the client, either Postgres or MySQL.
+	Protocol db.Type `json:"protocol"`
+}
+
+// ProxySession is the API message for a proxied session.
+// The connection is rejected if Error is set, e.g. the password is incorrect or the user can't access the database.
+type ProxySession struct {
+	// Token is signed by the server and identifies the user and the database of the session in the following queries.
+	// It expires so that a long-lived connection has to reconnect and pass the checks again.
+	Token         string `json:"token"`
+	InstanceName  string `json:"instanceName"`
+	DatabaseName  string `json:"databaseName"`
+	EngineVersion string `json:"engineVersion"`
+	Error         string `json:"error"`
+}
+
+// ProxyQuery is the API message for running a query in a proxied session.
+type ProxyQuery struct {
+	Token     string `json:"token"`
+	Statement string `json:"statement"`
+}
+
+// ProxyQueryResult is the API message for the result of a proxied query.
+type ProxyQueryResult struct {
+	// Data is the JSON encoded row set, same as the one of the SQL editor.
+	Data string `json:"data"`
+	// Error is the reason the query is rejected or fails, e.g. it isn't read-only or violates the SQL review policy.
+	Error      string           `json:"error"`
+	AdviceList []advisor.Advice `json:"adviceList"`
+}
+
+// ParseProxyDatabase parses the database name sent by the proxy client, which is "instance/database" to pick the
+// database of an instance, or "database" if the database name is unique in the workspace.
+// The instance name is split at the last slash since the instance names are free-form.
+func ParseProxyDatabase(name string) (instanceName string, databaseName string) {
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		return "", name
+	}
+	return name[:i], name[i+1:]
+}
+
+// IsProxyProtocolCompatible returns whether the database of the engine can be accessed via the wire protocol of the proxy.
+func IsProxyProtocolCompatible(protocol, engine db.Type) bool {
+	switch protocol {
+	case db.Postgres:
+		return engine == db.Postgres
+	case db.MySQL:
+		return engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+	}
+	return false
+}
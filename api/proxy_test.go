@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestParseProxyDatabase(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceName string
+		databaseName string
+	}{
+		{name: "employee", instanceName: "", databaseName: "employee"},
+		{name: "Prod Sample Instance/employee", instanceName: "Prod Sample Instance", databaseName: "employee"},
+		{name: "us/east/employee", instanceName: "us/east", databaseName: "employee"},
+	}
+
+	for _, test := range tests {
+		instanceName, databaseName := ParseProxyDatabase(test.name)
+		require.Equal(t, test.instanceName, instanceName, test.name)
+		require.Equal(t, test.databaseName, databaseName, test.name)
+	}
+}
+
+func TestIsProxyProtocolCompatible(t *testing.T) {
+	require.True(t, IsProxyProtocolCompatible(db.Postgres, db.Postgres))
+	require.True(t, IsProxyProtocolCompatible(db.MySQL, db.TiDB))
+	require.False(t, IsProxyProtocolCompatible(db.MySQL, db.Postgres))
+	require.False(t, IsProxyProtocolCompatible(db.Snowflake, db.Snowflake))
+}
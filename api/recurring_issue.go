@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// RecurringIssue is the API message for a recurring issue.
+// An issue is instantiated from the template on the cron schedule, e.g. the monthly partition creation
+// or the weekly cleanup DELETE.
+type RecurringIssue struct {
+	ID int `jsonapi:"primary,recurringIssue"`
+
+	// Standard fields
+	RowStatus RowStatus `jsonapi:"attr,rowStatus"`
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+	// Schedule is the 5-field cron expression evaluated in UTC.
+	Schedule string `jsonapi:"attr,schedule"`
+	// The template of the instantiated issue, the fields have the same meanings as in IssueCreate.
+	Type          IssueType `jsonapi:"attr,type"`
+	Description   string    `jsonapi:"attr,description"`
+	AssigneeID    int       `jsonapi:"attr,assigneeId"`
+	CreateContext string    `jsonapi:"attr,createContext"`
+	// AutoApprove approves the instantiated issue, otherwise the tasks wait for the approval as usual.
+	AutoApprove bool  `jsonapi:"attr,autoApprove"`
+	NextRunTs   int64 `jsonapi:"attr,nextRunTs"`
+	LastRunTs   int64 `jsonapi:"attr,lastRunTs"`
+	// LastIssueID is the ID of the last instantiated issue, 0 if none.
+	LastIssueID int `jsonapi:"attr,lastIssueId"`
+}
+
+// RecurringIssueCreate is the API message for creating a recurring issue.
+type RecurringIssueCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Name          string    `jsonapi:"attr,name"`
+	Schedule      string    `jsonapi:"attr,schedule"`
+	Type          IssueType `jsonapi:"attr,type"`
+	Description   string    `jsonapi:"attr,description"`
+	AssigneeID    int       `jsonapi:"attr,assigneeId"`
+	CreateContext string    `jsonapi:"attr,createContext"`
+	AutoApprove   bool      `jsonapi:"attr,autoApprove"`
+	// NextRunTs is computed from the schedule by the server.
+	NextRunTs int64
+}
+
+// RecurringIssueFind is the API message for finding recurring issues.
+type RecurringIssueFind struct {
+	ID *int
+
+	// Standard fields
+	RowStatus *RowStatus
+
+	// Related fields
+	ProjectID *int
+
+	// Domain specific fields
+	// DueBeforeTs finds the recurring issues whose next run is before or at the timestamp.
+	DueBeforeTs *int64
+}
+
+func (find *RecurringIssueFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// RecurringIssuePatch is the API message for patching a recurring issue.
+type RecurringIssuePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+	RowStatus *string `jsonapi:"attr,rowStatus"`
+
+	// Domain specific fields
+	Name          *string `jsonapi:"attr,name"`
+	Schedule      *string `jsonapi:"attr,schedule"`
+	Description   *string `jsonapi:"attr,description"`
+	AssigneeID    *int    `jsonapi:"attr,assigneeId"`
+	CreateContext *string `jsonapi:"attr,createContext"`
+	AutoApprove   *bool   `jsonapi:"attr,autoApprove"`
+	// NextRunTs is recomputed by the server when the schedule changes or the recurring issue is restored.
+	NextRunTs *int64
+	// LastRunTs and LastIssueID are set by the scheduler after instantiating the issue.
+	LastRunTs   *int64
+	LastIssueID *int
+}
+
+// RecurringIssueDelete is the API message for deleting a recurring issue, the instantiated issues are kept.
+type RecurringIssueDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// ValidateRecurringIssueType validates the recurring issue type, only the SQL changes are applicable.
+func ValidateRecurringIssueType(issueType IssueType) error {
+	switch issueType {
+	case IssueDatabaseSchemaUpdate, IssueDatabaseDataUpdate:
+		return nil
+	}
+	return &common.Error{Code: common.Invalid, Err: fmt.Errorf("recurring issue type must be %s or %s, got %q", IssueDatabaseSchemaUpdate, IssueDatabaseDataUpdate, issueType)}
+}
+
+// GetRecurringIssueNextRunTs validates the cron schedule and returns the timestamp of the first run after now.
+func GetRecurringIssueNextRunTs(schedule string, now time.Time) (int64, error) {
+	cron, err := common.ParseCronSchedule(strings.TrimSpace(schedule))
+	if err != nil {
+		return 0, &common.Error{Code: common.Invalid, Err: err}
+	}
+	next := cron.Next(now.UTC())
+	if next.IsZero() {
+		return 0, &common.Error{Code: common.Invalid, Err: fmt.Errorf("cron schedule %q never runs", schedule)}
+	}
+	return next.Unix(), nil
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRecurringIssueNextRunTs(t *testing.T) {
+	// The schedule is evaluated in UTC regardless of the location of now.
+	now := time.Date(2022, 5, 16, 10, 30, 0, 0, time.FixedZone("UTC+8", 8*60*60))
+
+	ts, err := GetRecurringIssueNextRunTs("0 3 1 * *", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2022, 6, 1, 3, 0, 0, 0, time.UTC).Unix(), ts)
+
+	_, err = GetRecurringIssueNextRunTs("0 3 1 *", now)
+	require.Error(t, err)
+
+	_, err = GetRecurringIssueNextRunTs("0 0 31 4 *", now)
+	require.Error(t, err)
+}
+
+func TestValidateRecurringIssueType(t *testing.T) {
+	require.NoError(t, ValidateRecurringIssueType(IssueDatabaseSchemaUpdate))
+	require.NoError(t, ValidateRecurringIssueType(IssueDatabaseDataUpdate))
+	require.Error(t, ValidateRecurringIssueType(IssueDatabaseCreate))
+}
@@ -2,6 +2,14 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/vcs"
 )
 
 // Repository is the API message for a repository.
@@ -30,11 +38,22 @@ type Repository struct {
 	BaseDirectory string `jsonapi:"attr,baseDirectory"`
 	// The file path template for matching the committed migration script.
 	FilePathTemplate string `jsonapi:"attr,filePathTemplate"`
+	// The naming convention and the content format of the migration files.
+	// For the formats other than Bytebase, the file path template only matches the directory containing the migration files.
+	FileFormat db.MigrationFileFormat `jsonapi:"attr,fileFormat"`
 	// The file path template for storing the latest schema auto-generated by Bytebase after migration.
 	// If empty, then Bytebase won't auto generate it.
 	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
 	// The file path template for matching the sql files for sheet.
-	SheetPathTemplate  string `jsonapi:"attr,sheetPathTemplate"`
+	SheetPathTemplate string `jsonapi:"attr,sheetPathTemplate"`
+	// EnvironmentMapping encapsulates RepositoryEnvironmentMapping in json string format.
+	// If empty, the environment is determined by the file path template.
+	EnvironmentMapping string `jsonapi:"attr,environmentMapping"`
+	// RequireSignedCommit requires the commits of the migration files to be signed and verified by the VCS before creating the issues.
+	RequireSignedCommit bool `jsonapi:"attr,requireSignedCommit"`
+	// TrustedSigningKeys is the comma separated list of the trusted signing keys, which are the GPG long key IDs or fingerprints,
+	// the emails of the X.509 certificates or the SHA256 fingerprints of the SSH keys. If empty, any verified signature is trusted.
+	TrustedSigningKeys string `jsonapi:"attr,trustedSigningKeys"`
 	ExternalID         string `jsonapi:"attr,externalId"`
 	ExternalWebhookID  string
 	WebhookURLHost     string
@@ -57,15 +76,19 @@ type RepositoryCreate struct {
 	ProjectID int
 
 	// Domain specific fields
-	Name               string `jsonapi:"attr,name"`
-	FullPath           string `jsonapi:"attr,fullPath"`
-	WebURL             string `jsonapi:"attr,webUrl"`
-	BranchFilter       string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
-	SheetPathTemplate  string `jsonapi:"attr,sheetPathTemplate"`
-	ExternalID         string `jsonapi:"attr,externalId"`
+	Name                string                 `jsonapi:"attr,name"`
+	FullPath            string                 `jsonapi:"attr,fullPath"`
+	WebURL              string                 `jsonapi:"attr,webUrl"`
+	BranchFilter        string                 `jsonapi:"attr,branchFilter"`
+	BaseDirectory       string                 `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate    string                 `jsonapi:"attr,filePathTemplate"`
+	FileFormat          db.MigrationFileFormat `jsonapi:"attr,fileFormat"`
+	SchemaPathTemplate  string                 `jsonapi:"attr,schemaPathTemplate"`
+	SheetPathTemplate   string                 `jsonapi:"attr,sheetPathTemplate"`
+	EnvironmentMapping  string                 `jsonapi:"attr,environmentMapping"`
+	RequireSignedCommit bool                   `jsonapi:"attr,requireSignedCommit"`
+	TrustedSigningKeys  string                 `jsonapi:"attr,trustedSigningKeys"`
+	ExternalID          string                 `jsonapi:"attr,externalId"`
 	// Token belonged by the user linking the project to the VCS repository. We store this token together
 	// with the refresh token in the new repository record so we can use it to call VCS API on
 	// behalf of that user to perform tasks like webhook CRUD later.
@@ -107,14 +130,18 @@ type RepositoryPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	BranchFilter       *string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      *string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   *string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate *string `jsonapi:"attr,schemaPathTemplate"`
-	SheetPathTemplate  *string `jsonapi:"attr,sheetPathTemplate"`
-	AccessToken        *string
-	ExpiresTs          *int64
-	RefreshToken       *string
+	BranchFilter        *string                 `jsonapi:"attr,branchFilter"`
+	BaseDirectory       *string                 `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate    *string                 `jsonapi:"attr,filePathTemplate"`
+	FileFormat          *db.MigrationFileFormat `jsonapi:"attr,fileFormat"`
+	SchemaPathTemplate  *string                 `jsonapi:"attr,schemaPathTemplate"`
+	SheetPathTemplate   *string                 `jsonapi:"attr,sheetPathTemplate"`
+	EnvironmentMapping  *string                 `jsonapi:"attr,environmentMapping"`
+	RequireSignedCommit *bool                   `jsonapi:"attr,requireSignedCommit"`
+	TrustedSigningKeys  *string                 `jsonapi:"attr,trustedSigningKeys"`
+	AccessToken         *string
+	ExpiresTs           *int64
+	RefreshToken        *string
 }
 
 // RepositoryDelete is the API message for deleting a repository.
@@ -128,3 +155,132 @@ type RepositoryDelete struct {
 	// Value is assigned from the jwt subject field passed by the client.
 	DeleterID int
 }
+
+// RepositoryEnvironmentMapping is the API message for mapping the branches or directories of a repository to the environments,
+// e.g. main to Prod and develop to Staging, so merging between the branches promotes the migrations between the environments.
+type RepositoryEnvironmentMapping struct {
+	// RuleList is matched in order and the first matched rule wins.
+	RuleList []*RepositoryEnvironmentRule `json:"ruleList"`
+}
+
+// RepositoryEnvironmentRule is the API message for a rule of the repository environment mapping.
+// The rule matches the migration file when both the branch and the directory match, the empty one matches all.
+type RepositoryEnvironmentRule struct {
+	// Branch is the branch name, which supports the wildcard such as release/*.
+	// The branch filter of the repository should cover the branch, otherwise GitLab won't deliver its push events.
+	Branch string `json:"branch"`
+	// Directory is relative to the base directory of the repository.
+	Directory string `json:"directory"`
+	// Environment is the name of the environment, which is case insensitive.
+	Environment string `json:"environment"`
+}
+
+// Match returns the environment name of the first rule matching the branch and the file path relative to the base directory,
+// or empty if none of the rules matches.
+func (m *RepositoryEnvironmentMapping) Match(branch, file string) string {
+	for _, rule := range m.RuleList {
+		if rule.Branch != "" {
+			// The pattern has been validated.
+			if ok, _ := path.Match(rule.Branch, branch); !ok {
+				continue
+			}
+		}
+		if rule.Directory != "" && !strings.HasPrefix(file, rule.Directory+"/") {
+			continue
+		}
+		return rule.Environment
+	}
+	return ""
+}
+
+// ValidateAndGetRepositoryEnvironmentMapping validates and returns the repository environment mapping.
+// The empty payload means no mapping.
+func ValidateAndGetRepositoryEnvironmentMapping(payload string, tenantMode ProjectTenantMode) (*RepositoryEnvironmentMapping, error) {
+	mapping := &RepositoryEnvironmentMapping{}
+	if payload == "" {
+		return mapping, nil
+	}
+	if err := json.Unmarshal([]byte(payload), mapping); err != nil {
+		return nil, common.Errorf(common.Invalid, "invalid environment mapping: %v", err)
+	}
+	if len(mapping.RuleList) > 0 && tenantMode == TenantModeTenant {
+		return nil, common.Errorf(common.Invalid, "environment mapping is not allowed for projects in tenant mode")
+	}
+	for i, rule := range mapping.RuleList {
+		rule.Directory = strings.Trim(rule.Directory, "/")
+		if rule.Environment == "" {
+			return nil, common.Errorf(common.Invalid, "environment mapping rule %d must specify the environment", i+1)
+		}
+		if rule.Branch == "" && rule.Directory == "" {
+			return nil, common.Errorf(common.Invalid, "environment mapping rule %d must specify the branch or the directory", i+1)
+		}
+		if _, err := path.Match(rule.Branch, ""); err != nil {
+			return nil, common.Errorf(common.Invalid, "environment mapping rule %d has invalid branch pattern %q", i+1, rule.Branch)
+		}
+	}
+	return mapping, nil
+}
+
+// gpgKeyIDMinLength is the length of the GPG long key ID, the short key IDs are trivial to collide.
+const gpgKeyIDMinLength = 16
+
+// gpgKeyRegexp matches the GPG key IDs and fingerprints, the fingerprints may be grouped by the spaces.
+var gpgKeyRegexp = regexp.MustCompile(`^[0-9A-Fa-f ]+$`)
+
+// ValidateTrustedSigningKeys validates the comma separated trusted signing keys, the GPG keys must be the fingerprints
+// or the long key IDs. The X.509 emails and the SSH fingerprints are told apart from the GPG keys by the "@" and the ":".
+func ValidateTrustedSigningKeys(trustedSigningKeys string) error {
+	if strings.TrimSpace(trustedSigningKeys) == "" {
+		return nil
+	}
+	for _, trustedKey := range strings.Split(trustedSigningKeys, ",") {
+		trustedKey = strings.TrimSpace(trustedKey)
+		if trustedKey == "" {
+			return common.Errorf(common.Invalid, "empty trusted signing key in %q", trustedSigningKeys)
+		}
+		if strings.ContainsAny(trustedKey, "@:") {
+			continue
+		}
+		if !gpgKeyRegexp.MatchString(trustedKey) || len(strings.ReplaceAll(trustedKey, " ", "")) < gpgKeyIDMinLength {
+			return common.Errorf(common.Invalid, "trusted signing key %q must be a GPG fingerprint or a %d-digit GPG key ID, an X.509 email or an SSH SHA256 fingerprint", trustedKey, gpgKeyIDMinLength)
+		}
+	}
+	return nil
+}
+
+// VerifyCommitSignature returns an error if the commit signature isn't verified by the VCS,
+// or isn't signed by one of the comma separated trusted signing keys. If trustedSigningKeys is empty, any verified signature is trusted.
+func VerifyCommitSignature(signature *vcs.CommitSignature, trustedSigningKeys string) error {
+	if !signature.Signed {
+		return fmt.Errorf("commit is not signed")
+	}
+	if !signature.Verified {
+		return fmt.Errorf("%s signature of key %q is not verified by the VCS, status: %s", signature.Type, signature.KeyID, signature.Status)
+	}
+	if strings.TrimSpace(trustedSigningKeys) == "" {
+		return nil
+	}
+	for _, trustedKey := range strings.Split(trustedSigningKeys, ",") {
+		trustedKey = strings.TrimSpace(trustedKey)
+		if trustedKey == "" || signature.KeyID == "" {
+			continue
+		}
+		switch signature.Type {
+		case vcs.SignatureTypeGPG:
+			// The trusted key may be either the long key ID or the fingerprint, whose suffix is the key ID.
+			keyID, trusted := strings.ToUpper(signature.KeyID), strings.ToUpper(strings.ReplaceAll(trustedKey, " ", ""))
+			if len(keyID) >= gpgKeyIDMinLength && strings.HasSuffix(trusted, keyID) {
+				return nil
+			}
+		case vcs.SignatureTypeX509:
+			if strings.EqualFold(trustedKey, signature.KeyID) {
+				return nil
+			}
+		case vcs.SignatureTypeSSH:
+			if trustedKey == signature.KeyID {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%s signature of key %q is not signed by a trusted key", signature.Type, signature.KeyID)
+}
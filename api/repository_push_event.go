@@ -0,0 +1,80 @@
+package api
+
+import (
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+// RepositoryPushEventStatus is the processing status of a file added by a repository push event.
+type RepositoryPushEventStatus string
+
+const (
+	// RepositoryPushEventDone is the status when the issue has been created for the file.
+	RepositoryPushEventDone RepositoryPushEventStatus = "DONE"
+	// RepositoryPushEventIgnored is the status when the file isn't a migration file according to the repository configuration,
+	// e.g. it's not under the base directory.
+	RepositoryPushEventIgnored RepositoryPushEventStatus = "IGNORED"
+	// RepositoryPushEventFailed is the status when the file looks like a migration file but failed to be processed,
+	// e.g. the file name doesn't match the file path template, or the statement can't be parsed.
+	RepositoryPushEventFailed RepositoryPushEventStatus = "FAILED"
+)
+
+// RepositoryPushEvent is the API message for a file added by a push event received from the VCS.
+// Each added file is processed independently, so the failed and ignored ones can be replayed
+// after fixing the repository configuration instead of pushing an artificial commit.
+type RepositoryPushEvent struct {
+	ID int `jsonapi:"primary,repositoryPushEvent"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	File      string                    `jsonapi:"attr,file"`
+	PushEvent vcs.PushEvent             `jsonapi:"attr,pushEvent"`
+	Status    RepositoryPushEventStatus `jsonapi:"attr,status"`
+	// Message is the reason of being ignored or failed, or the created issue.
+	Message string `jsonapi:"attr,message"`
+	// ReplayCount is the number of times the event has been replayed.
+	ReplayCount int `jsonapi:"attr,replayCount"`
+}
+
+// RepositoryPushEventCreate is the API message for creating a repository push event.
+type RepositoryPushEventCreate struct {
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	File      string
+	PushEvent vcs.PushEvent
+	Status    RepositoryPushEventStatus
+	Message   string
+}
+
+// RepositoryPushEventFind is the API message for finding repository push events.
+type RepositoryPushEventFind struct {
+	ID *int
+
+	// Related fields
+	ProjectID *int
+
+	// Domain specific fields
+	Status *RepositoryPushEventStatus
+}
+
+// RepositoryPushEventPatch is the API message for patching the result of replaying a repository push event.
+type RepositoryPushEventPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Status  RepositoryPushEventStatus
+	Message string
+}
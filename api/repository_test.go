@@ -0,0 +1,221 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestValidateAndGetRepositoryEnvironmentMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    string
+		tenantMode ProjectTenantMode
+		want       *RepositoryEnvironmentMapping
+		errPart    string
+	}{
+		{
+			name:       "empty",
+			payload:    "",
+			tenantMode: TenantModeDisabled,
+			want:       &RepositoryEnvironmentMapping{},
+		},
+		{
+			name:       "branchAndDirectory",
+			payload:    `{"ruleList":[{"branch":"main","environment":"Prod"},{"branch":"release/*","directory":"/staging/","environment":"Staging"}]}`,
+			tenantMode: TenantModeDisabled,
+			want: &RepositoryEnvironmentMapping{
+				RuleList: []*RepositoryEnvironmentRule{
+					{Branch: "main", Environment: "Prod"},
+					{Branch: "release/*", Directory: "staging", Environment: "Staging"},
+				},
+			},
+		},
+		{
+			name:       "malformed",
+			payload:    `{"ruleList":`,
+			tenantMode: TenantModeDisabled,
+			errPart:    "invalid environment mapping",
+		},
+		{
+			name:       "tenantMode",
+			payload:    `{"ruleList":[{"branch":"main","environment":"Prod"}]}`,
+			tenantMode: TenantModeTenant,
+			errPart:    "not allowed for projects in tenant mode",
+		},
+		{
+			name:       "missingEnvironment",
+			payload:    `{"ruleList":[{"branch":"main"}]}`,
+			tenantMode: TenantModeDisabled,
+			errPart:    "must specify the environment",
+		},
+		{
+			name:       "missingBranchAndDirectory",
+			payload:    `{"ruleList":[{"directory":"/","environment":"Prod"}]}`,
+			tenantMode: TenantModeDisabled,
+			errPart:    "must specify the branch or the directory",
+		},
+		{
+			name:       "invalidBranchPattern",
+			payload:    `{"ruleList":[{"branch":"release/[","environment":"Prod"}]}`,
+			tenantMode: TenantModeDisabled,
+			errPart:    "invalid branch pattern",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ValidateAndGetRepositoryEnvironmentMapping(test.payload, test.tenantMode)
+			if test.errPart != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errPart)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestRepositoryEnvironmentMappingMatch(t *testing.T) {
+	mapping := &RepositoryEnvironmentMapping{
+		RuleList: []*RepositoryEnvironmentRule{
+			{Branch: "main", Directory: "hotfix", Environment: "Staging"},
+			{Branch: "main", Environment: "Prod"},
+			{Branch: "release/*", Environment: "Staging"},
+			{Directory: "test", Environment: "Test"},
+		},
+	}
+	tests := []struct {
+		branch string
+		file   string
+		want   string
+	}{
+		{"main", "v1__db1__init.sql", "Prod"},
+		{"main", "hotfix/v1__db1__init.sql", "Staging"},
+		{"main", "hotfixes/v1__db1__init.sql", "Prod"},
+		{"release/1.0", "v1__db1__init.sql", "Staging"},
+		{"release/1.0/rc", "v1__db1__init.sql", ""},
+		{"develop", "test/v1__db1__init.sql", "Test"},
+		{"develop", "v1__db1__init.sql", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, mapping.Match(test.branch, test.file), "branch %q file %q", test.branch, test.file)
+	}
+}
+
+func TestVerifyCommitSignature(t *testing.T) {
+	tests := []struct {
+		name               string
+		signature          *vcs.CommitSignature
+		trustedSigningKeys string
+		errPart            string
+	}{
+		{
+			name:      "unsigned",
+			signature: &vcs.CommitSignature{Signed: false},
+			errPart:   "not signed",
+		},
+		{
+			name:      "unverified",
+			signature: &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, KeyID: "8254AAB3FBD54AC9", Status: "unknown_key"},
+			errPart:   "not verified",
+		},
+		{
+			name:      "anyVerified",
+			signature: &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, Verified: true, KeyID: "8254AAB3FBD54AC9"},
+		},
+		{
+			name:               "gpgFingerprint",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, Verified: true, KeyID: "8254AAB3FBD54AC9"},
+			trustedSigningKeys: "0123456789ABCDEF, 5B9A 0F4C 1D22 7E3B 8254 AAB3 FBD5 4AC9",
+		},
+		{
+			name:               "gpgKeyIDCaseInsensitive",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, Verified: true, KeyID: "8254AAB3FBD54AC9"},
+			trustedSigningKeys: "8254aab3fbd54ac9",
+		},
+		{
+			name:               "x509Email",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeX509, Verified: true, KeyID: "dev@example.com"},
+			trustedSigningKeys: "Dev@Example.com",
+		},
+		{
+			name:               "sshFingerprint",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeSSH, Verified: true, KeyID: "SHA256:it8s9S0dQmd/L0a+TqkHlpai8Ip6t9V+Ch5/8yjs1tQ"},
+			trustedSigningKeys: "SHA256:it8s9S0dQmd/L0a+TqkHlpai8Ip6t9V+Ch5/8yjs1tQ",
+		},
+		{
+			name:               "untrusted",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, Verified: true, KeyID: "8254AAB3FBD54AC9"},
+			trustedSigningKeys: "0123456789ABCDEF",
+			errPart:            "not signed by a trusted key",
+		},
+		{
+			// The short key ID saved before the validation is never matched.
+			name:               "gpgShortTrustedKey",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, Verified: true, KeyID: "8254AAB3FBD54AC9"},
+			trustedSigningKeys: "FBD54AC9",
+			errPart:            "not signed by a trusted key",
+		},
+		{
+			name:               "gpgShortSignatureKey",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, Verified: true, KeyID: "FBD54AC9"},
+			trustedSigningKeys: "5B9A 0F4C 1D22 7E3B 8254 AAB3 FBD5 4AC9",
+			errPart:            "not signed by a trusted key",
+		},
+		{
+			name:               "gpgEmptySignatureKey",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, Verified: true},
+			trustedSigningKeys: "5B9A 0F4C 1D22 7E3B 8254 AAB3 FBD5 4AC9",
+			errPart:            "not signed by a trusted key",
+		},
+		{
+			name:               "emptyTrustedKeys",
+			signature:          &vcs.CommitSignature{Signed: true, Type: vcs.SignatureTypeGPG, Verified: true, KeyID: "8254AAB3FBD54AC9"},
+			trustedSigningKeys: " , ",
+			errPart:            "not signed by a trusted key",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := VerifyCommitSignature(test.signature, test.trustedSigningKeys)
+			if test.errPart != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errPart)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateTrustedSigningKeys(t *testing.T) {
+	tests := []struct {
+		trustedSigningKeys string
+		wantErr            bool
+	}{
+		{trustedSigningKeys: ""},
+		{trustedSigningKeys: "8254aab3fbd54ac9"},
+		{trustedSigningKeys: "5B9A 0F4C 1D22 7E3B 8254 AAB3 FBD5 4AC9, dev@example.com, SHA256:it8s9S0dQmd/L0a+TqkHlpai8Ip6t9V+Ch5/8yjs1tQ"},
+		{trustedSigningKeys: "FBD54AC9", wantErr: true},
+		{trustedSigningKeys: "8254AAB3FBD54AC9, 4AC9", wantErr: true},
+		{trustedSigningKeys: "8254AAB3FBD54AC9,,dev@example.com", wantErr: true},
+		{trustedSigningKeys: " , ", wantErr: true},
+		{trustedSigningKeys: "not-a-key-0123456789", wantErr: true},
+	}
+	for _, test := range tests {
+		err := ValidateTrustedSigningKeys(test.trustedSigningKeys)
+		if test.wantErr {
+			require.Equal(t, common.Invalid, common.ErrorCode(err), test.trustedSigningKeys)
+		} else {
+			require.NoError(t, err, test.trustedSigningKeys)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// SchemaChangeEvent is the event published when a migration completes on a database,
+// so the downstream systems such as the ORM caches and the data catalogs can react to the schema change.
+// It's sent to the custom project webhooks subscribing ActivityDatabaseSchemaChange, and to the event sink if configured.
+type SchemaChangeEvent struct {
+	Type      ActivityType `json:"type"`
+	CreatedTs int64        `json:"createdTs"`
+
+	// Related fields
+	ProjectID       int    `json:"projectId"`
+	ProjectName     string `json:"projectName"`
+	EnvironmentName string `json:"environmentName"`
+	InstanceID      int    `json:"instanceId"`
+	InstanceName    string `json:"instanceName"`
+	DatabaseID      int    `json:"databaseId"`
+	DatabaseName    string `json:"databaseName"`
+	// IssueID is 0 if the task doesn't belong to an issue.
+	IssueID int `json:"issueId"`
+	TaskID  int `json:"taskId"`
+
+	// Domain specific fields
+	MigrationHistoryID int64            `json:"migrationHistoryId"`
+	MigrationType      db.MigrationType `json:"migrationType"`
+	Version            string           `json:"version"`
+	Statement          string           `json:"statement"`
+	// Diff is nil if the schema diff can't be computed.
+	Diff *SchemaDiff `json:"diff"`
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// SchemaDescription is the API message for the logical description of a table or a column, a.k.a. the data dictionary.
+// It's maintained in Bytebase so that users don't need the privileges to change the comments in the database,
+// and it takes precedence over the comment synced from the database.
+type SchemaDescription struct {
+	ID int `jsonapi:"primary,schemaDescription"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	TableName string `jsonapi:"attr,tableName"`
+	// ColumnName is empty for the description of the table.
+	ColumnName  string `jsonapi:"attr,columnName"`
+	Description string `jsonapi:"attr,description"`
+}
+
+// SchemaDescriptionUpsert is the API message for upserting a schema description.
+// An empty description removes the existing description.
+type SchemaDescriptionUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	TableName   string `jsonapi:"attr,tableName"`
+	ColumnName  string `jsonapi:"attr,columnName"`
+	Description string `jsonapi:"attr,description"`
+}
+
+// SchemaDescriptionFind is the API message for finding schema descriptions.
+type SchemaDescriptionFind struct {
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	TableName *string
+}
+
+func (find *SchemaDescriptionFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SchemaDescriptionDelete is the API message for deleting a schema description.
+type SchemaDescriptionDelete struct {
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	TableName  string
+	ColumnName string
+}
+
+// SchemaDescriptionIssueCreate is the API message for creating an issue to apply the schema descriptions
+// as the comments in the database.
+type SchemaDescriptionIssueCreate struct {
+	AssigneeID int `jsonapi:"attr,assigneeId"`
+}
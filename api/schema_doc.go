@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// SchemaDocFormat is the format of the exported schema documentation.
+type SchemaDocFormat string
+
+const (
+	// SchemaDocFormatMarkdown is the Markdown format.
+	SchemaDocFormatMarkdown SchemaDocFormat = "MARKDOWN"
+	// SchemaDocFormatHTML is the HTML format.
+	SchemaDocFormatHTML SchemaDocFormat = "HTML"
+	// SchemaDocFormatPDF is the PDF format.
+	SchemaDocFormatPDF SchemaDocFormat = "PDF"
+)
+
+// SchemaDocPublishSchedule is the schedule to publish the schema documentation.
+type SchemaDocPublishSchedule string
+
+const (
+	// SchemaDocPublishScheduleDisabled means the schema documentation is not published.
+	SchemaDocPublishScheduleDisabled SchemaDocPublishSchedule = "DISABLED"
+	// SchemaDocPublishScheduleDaily publishes the schema documentation every day.
+	SchemaDocPublishScheduleDaily SchemaDocPublishSchedule = "DAILY"
+	// SchemaDocPublishScheduleWeekly publishes the schema documentation every week.
+	SchemaDocPublishScheduleWeekly SchemaDocPublishSchedule = "WEEKLY"
+)
+
+// SchemaDocSetting is the API message for the schema documentation setting of a project.
+// The branding fields apply to the documentation of every database in the project.
+type SchemaDocSetting struct {
+	ID int `jsonapi:"primary,schemaDocSetting"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectID int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	// Branding related fields
+	// Title is the title of the documentation, empty means the project name.
+	Title   string `jsonapi:"attr,title"`
+	LogoURL string `jsonapi:"attr,logoUrl"`
+	Footer  string `jsonapi:"attr,footer"`
+	// ThemeColor is the hex color such as "#4f46e5" used in the HTML documentation.
+	ThemeColor string `jsonapi:"attr,themeColor"`
+	// Publish related fields
+	PublishSchedule SchemaDocPublishSchedule `jsonapi:"attr,publishSchedule"`
+	PublishFormat   SchemaDocFormat          `jsonapi:"attr,publishFormat"`
+	// PublishURL is the URL the documentation of each database is POSTed to.
+	PublishURL      string `jsonapi:"attr,publishUrl"`
+	LastPublishedTs int64  `jsonapi:"attr,lastPublishedTs"`
+}
+
+// SchemaDocSettingFind is the API message for finding schema documentation settings.
+type SchemaDocSettingFind struct {
+	// Related fields
+	ProjectID *int
+
+	// Domain specific fields
+	PublishSchedule *SchemaDocPublishSchedule
+}
+
+func (find *SchemaDocSettingFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SchemaDocSettingUpsert is the API message for upserting the schema documentation setting of a project.
+// NOTE: We use PATCH for Upsert, this is inspired by https://google.aip.dev/134#patch-and-put
+type SchemaDocSettingUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Title           string                   `jsonapi:"attr,title"`
+	LogoURL         string                   `jsonapi:"attr,logoUrl"`
+	Footer          string                   `jsonapi:"attr,footer"`
+	ThemeColor      string                   `jsonapi:"attr,themeColor"`
+	PublishSchedule SchemaDocPublishSchedule `jsonapi:"attr,publishSchedule"`
+	PublishFormat   SchemaDocFormat          `jsonapi:"attr,publishFormat"`
+	PublishURL      string                   `jsonapi:"attr,publishUrl"`
+}
+
+// SchemaDocSettingPublish is the API message for recording the publication of the schema documentation.
+type SchemaDocSettingPublish struct {
+	ID int
+
+	// Domain specific fields
+	LastPublishedTs int64
+}
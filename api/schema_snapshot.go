@@ -0,0 +1,59 @@
+package api
+
+// SchemaSnapshotSource is the source capturing the schema snapshot.
+type SchemaSnapshotSource string
+
+const (
+	// SchemaSnapshotSync is the schema snapshot captured by the schema sync.
+	SchemaSnapshotSync SchemaSnapshotSource = "SYNC"
+	// SchemaSnapshotMigration is the schema snapshot captured after applying a migration.
+	SchemaSnapshotMigration SchemaSnapshotSource = "MIGRATION"
+)
+
+// SchemaSnapshot is the API message for the schema dump of a database at a point in time.
+// A new snapshot is recorded only when the schema changes, so the schema as of a time is the latest snapshot before it.
+type SchemaSnapshot struct {
+	ID int `jsonapi:"primary,schemaSnapshot"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Source SchemaSnapshotSource `jsonapi:"attr,source"`
+	// Version is the migration version for the MIGRATION source.
+	Version string `jsonapi:"attr,version"`
+	Schema  string `jsonapi:"attr,schema"`
+}
+
+// SchemaSnapshotCreate is the API message for recording a schema snapshot.
+type SchemaSnapshotCreate struct {
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Source  SchemaSnapshotSource
+	Version string
+	Schema  string
+}
+
+// SchemaSnapshotFind is the API message for finding schema snapshots, the latest snapshot comes first.
+type SchemaSnapshotFind struct {
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	// CreatedTsBefore finds the snapshots created at or before the time, i.e. the schema as of the time.
+	CreatedTsBefore *int64
+	Limit           *int
+}
+
+// SchemaSnapshotDiff is the API message for the schema diff of a database between two points in time.
+type SchemaSnapshotDiff struct {
+	// From and To are the snapshots in effect at the two points in time.
+	From *SchemaSnapshot `json:"from"`
+	To   *SchemaSnapshot `json:"to"`
+	Diff *SchemaDiff     `json:"diff"`
+}
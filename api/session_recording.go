@@ -0,0 +1,172 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// SessionRecordingTag is the tag of the databases whose SQL editor and proxied sessions are recorded.
+// The databases inherit it from their instance, same as the other tags.
+const SessionRecordingTag = "sensitive"
+
+// SessionSource is the source of a recorded session.
+type SessionSource string
+
+const (
+	// SessionSourceSQLEditor is the session of the SQL editor.
+	SessionSourceSQLEditor SessionSource = "SQL_EDITOR"
+	// SessionSourceProxy is the session of a client connected via the agent proxy.
+	SessionSourceProxy SessionSource = "PROXY"
+)
+
+// IsSessionRecorded returns whether the sessions on the database are recorded.
+// The database is nil for the instance-level queries, e.g. the MySQL query without the database name.
+func IsSessionRecorded(instance *Instance, database *Database) bool {
+	for _, tag := range GetPolicyTagList(instance, database) {
+		if tag == SessionRecordingTag {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionRecording is the API message for a recorded statement of a session on a sensitive database.
+// The recordings are immutable and chained by Hash, so that modifying, deleting or reordering them is detected.
+type SessionRecording struct {
+	ID int `jsonapi:"primary,sessionRecording"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	// The recordings outlive the instances and the databases, so their names are kept as well.
+	InstanceID   int    `jsonapi:"attr,instanceId"`
+	InstanceName string `jsonapi:"attr,instanceName"`
+	// DatabaseID is 0 and DatabaseName is empty for the instance-level queries.
+	DatabaseID   int    `jsonapi:"attr,databaseId"`
+	DatabaseName string `jsonapi:"attr,databaseName"`
+
+	// Domain specific fields
+	Source SessionSource `jsonapi:"attr,source"`
+	// SessionID groups the statements of a session, e.g. a psql connection or a SQL editor connection.
+	SessionID string `jsonapi:"attr,sessionId"`
+	Statement string `jsonapi:"attr,statement"`
+	// ColumnNameList and RowCount are the metadata of the result, the values aren't recorded.
+	ColumnNameList []string `jsonapi:"attr,columnNameList"`
+	RowCount       int      `jsonapi:"attr,rowCount"`
+	DurationNs     int64    `jsonapi:"attr,durationNs"`
+	// Error is the reason the statement is rejected or fails, empty if it succeeded.
+	Error string `jsonapi:"attr,error"`
+	// Hash is the HMAC of the hash of the previous recording and the fields above.
+	Hash string `jsonapi:"attr,hash"`
+}
+
+// SessionRecordingCreate is the API message for recording a statement of a session.
+type SessionRecordingCreate struct {
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	InstanceID   int
+	InstanceName string
+	DatabaseID   int
+	DatabaseName string
+
+	// Domain specific fields
+	Source         SessionSource
+	SessionID      string
+	Statement      string
+	ColumnNameList []string
+	RowCount       int
+	DurationNs     int64
+	Error          string
+}
+
+// SessionRecordingFind is the API message for finding session recordings.
+type SessionRecordingFind struct {
+	ID *int
+
+	// Standard fields
+	CreatorID *int
+
+	// Related fields
+	InstanceID *int
+	DatabaseID *int
+
+	// Domain specific fields
+	SessionID *string
+}
+
+// SessionRecordingVerification is the API message for the result of verifying the hash chain of the session recordings.
+type SessionRecordingVerification struct {
+	ID int `jsonapi:"primary,sessionRecordingVerification"`
+
+	// Domain specific fields
+	Count int `jsonapi:"attr,count"`
+	// BrokenRecordingID is the ID of the first recording not matching its hash, 0 if the chain is intact.
+	// A recording is reported if it's modified, or the one before it is deleted or modified.
+	BrokenRecordingID int `jsonapi:"attr,brokenRecordingId"`
+}
+
+// GetSessionRecordingHash returns the hash of the recording chained to the hash of the previous recording.
+// The fields are encoded as a JSON array so that their boundaries are unambiguous.
+func GetSessionRecordingHash(key []byte, prevHash string, create *SessionRecordingCreate) string {
+	columnNameList := create.ColumnNameList
+	if columnNameList == nil {
+		columnNameList = []string{}
+	}
+	// Marshaling the strings, the integers and the string slice never fails.
+	b, _ := json.Marshal([]interface{}{
+		prevHash,
+		create.CreatorID,
+		create.CreatedTs,
+		create.InstanceID,
+		create.InstanceName,
+		create.DatabaseID,
+		create.DatabaseName,
+		create.Source,
+		create.SessionID,
+		create.Statement,
+		columnNameList,
+		create.RowCount,
+		create.DurationNs,
+		create.Error,
+	})
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySessionRecordingChain verifies the hash chain of the recordings in the ascending ID order.
+func VerifySessionRecordingChain(key []byte, recordingList []*SessionRecording) *SessionRecordingVerification {
+	verification := &SessionRecordingVerification{Count: len(recordingList)}
+	prevHash := ""
+	for _, recording := range recordingList {
+		hash := GetSessionRecordingHash(key, prevHash, &SessionRecordingCreate{
+			CreatorID:      recording.CreatorID,
+			CreatedTs:      recording.CreatedTs,
+			InstanceID:     recording.InstanceID,
+			InstanceName:   recording.InstanceName,
+			DatabaseID:     recording.DatabaseID,
+			DatabaseName:   recording.DatabaseName,
+			Source:         recording.Source,
+			SessionID:      recording.SessionID,
+			Statement:      recording.Statement,
+			ColumnNameList: recording.ColumnNameList,
+			RowCount:       recording.RowCount,
+			DurationNs:     recording.DurationNs,
+			Error:          recording.Error,
+		})
+		if !hmac.Equal([]byte(hash), []byte(recording.Hash)) {
+			verification.BrokenRecordingID = recording.ID
+			return verification
+		}
+		prevHash = recording.Hash
+	}
+	return verification
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSessionRecorded(t *testing.T) {
+	require.False(t, IsSessionRecorded(&Instance{TagList: `["pci"]`}, &Database{TagList: `[]`}))
+	require.True(t, IsSessionRecorded(&Instance{TagList: `["sensitive"]`}, nil))
+	require.True(t, IsSessionRecorded(&Instance{TagList: `[]`}, &Database{TagList: `["pci","sensitive"]`}))
+}
+
+func TestVerifySessionRecordingChain(t *testing.T) {
+	key := []byte("secret")
+	var recordingList []*SessionRecording
+	prevHash := ""
+	for i, statement := range []string{"SELECT 1", "SELECT * FROM salary", "DELETE FROM salary"} {
+		create := &SessionRecordingCreate{
+			CreatorID:      101,
+			CreatedTs:      int64(1650000000 + i),
+			InstanceID:     1,
+			InstanceName:   "prod",
+			DatabaseID:     7,
+			DatabaseName:   "hr",
+			Source:         SessionSourceProxy,
+			SessionID:      "session",
+			Statement:      statement,
+			ColumnNameList: []string{"id"},
+			RowCount:       i,
+		}
+		hash := GetSessionRecordingHash(key, prevHash, create)
+		recordingList = append(recordingList, &SessionRecording{
+			ID:             101 + i,
+			CreatorID:      create.CreatorID,
+			CreatedTs:      create.CreatedTs,
+			InstanceID:     create.InstanceID,
+			InstanceName:   create.InstanceName,
+			DatabaseID:     create.DatabaseID,
+			DatabaseName:   create.DatabaseName,
+			Source:         create.Source,
+			SessionID:      create.SessionID,
+			Statement:      create.Statement,
+			ColumnNameList: create.ColumnNameList,
+			RowCount:       create.RowCount,
+			Hash:           hash,
+		})
+		prevHash = hash
+	}
+
+	require.Equal(t, &SessionRecordingVerification{Count: 3}, VerifySessionRecordingChain(key, recordingList))
+	// Another key doesn't verify the chain.
+	require.Equal(t, 101, VerifySessionRecordingChain([]byte("another"), recordingList).BrokenRecordingID)
+
+	// Deleting a recording breaks the next one.
+	deleted := []*SessionRecording{recordingList[0], recordingList[2]}
+	require.Equal(t, 103, VerifySessionRecordingChain(key, deleted).BrokenRecordingID)
+
+	// Modifying a recording breaks it.
+	modified := *recordingList[1]
+	modified.Statement = "SELECT 1"
+	require.Equal(t, 102, VerifySessionRecordingChain(key, []*SessionRecording{recordingList[0], &modified, recordingList[2]}).BrokenRecordingID)
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // SettingName is the name of a setting.
@@ -16,8 +17,79 @@ const (
 	SettingWorkspaceID SettingName = "bb.workspace.id"
 	// SettingEnterpriseLicense is the setting name for enterprise license.
 	SettingEnterpriseLicense SettingName = "bb.enterprise.license"
+	// SettingWorkspaceAnnouncement is the setting name for the workspace announcement banner.
+	SettingWorkspaceAnnouncement SettingName = "bb.workspace.announcement"
+	// SettingWorkspaceMetricReportLevel is the setting name for the granularity of the reported usage metrics.
+	SettingWorkspaceMetricReportLevel SettingName = "bb.workspace.metric-report-level"
+	// SettingWorkspaceAccountReport is the setting name for the schedule of the database account reports.
+	SettingWorkspaceAccountReport SettingName = "bb.workspace.account-report"
+	// SettingWorkspaceCertificateExpiry is the setting name for alerting the expiring instance certificates.
+	SettingWorkspaceCertificateExpiry SettingName = "bb.workspace.certificate-expiry"
+	// SettingWorkspaceTrash is the setting name for the retention of the deleted resources in the trash.
+	SettingWorkspaceTrash SettingName = "bb.workspace.trash"
+	// SettingWorkspaceGroupSync is the setting name for syncing the project membership with the VCS groups on login.
+	SettingWorkspaceGroupSync SettingName = "bb.workspace.group-sync"
+	// SettingWorkspaceDataRetention is the setting name for the retention of the activities, the query history and the audit log.
+	SettingWorkspaceDataRetention SettingName = "bb.workspace.data-retention"
+	// SettingWorkspaceExternalAdvisor is the setting name for the external policy engine consulted during the task checks.
+	SettingWorkspaceExternalAdvisor SettingName = "bb.workspace.external-advisor"
+	// SettingWorkspaceCIToken is the setting name for the SHA-256 hash of the token authenticating the CI status API.
+	// It's empty until the token is generated by the workspace owner.
+	SettingWorkspaceCIToken SettingName = "bb.workspace.ci-token"
 )
 
+// AnnouncementSeverity is the severity of the workspace announcement.
+type AnnouncementSeverity string
+
+const (
+	// AnnouncementInfo is the INFO severity of the announcement.
+	AnnouncementInfo AnnouncementSeverity = "INFO"
+	// AnnouncementWarn is the WARN severity of the announcement.
+	AnnouncementWarn AnnouncementSeverity = "WARN"
+	// AnnouncementCritical is the CRITICAL severity of the announcement.
+	AnnouncementCritical AnnouncementSeverity = "CRITICAL"
+)
+
+// Announcement is the value of the workspace announcement setting, e.g. to warn the users about the maintenance.
+type Announcement struct {
+	// Empty text means no announcement.
+	Text     string               `json:"text"`
+	Severity AnnouncementSeverity `json:"severity"`
+	// StartTs and EndTs bound the time to show the announcement, 0 means unbounded.
+	StartTs int64 `json:"startTs"`
+	EndTs   int64 `json:"endTs"`
+}
+
+// Validate validates the announcement.
+func (a *Announcement) Validate() error {
+	if a.Text == "" {
+		return nil
+	}
+	switch a.Severity {
+	case AnnouncementInfo, AnnouncementWarn, AnnouncementCritical:
+	default:
+		return fmt.Errorf("invalid announcement severity %q", a.Severity)
+	}
+	if a.StartTs != 0 && a.EndTs != 0 && a.StartTs >= a.EndTs {
+		return fmt.Errorf("announcement start time must be before the end time")
+	}
+	return nil
+}
+
+// IsActive returns whether the announcement should be shown at the time.
+func (a *Announcement) IsActive(ts int64) bool {
+	if a.Text == "" {
+		return false
+	}
+	if a.StartTs != 0 && ts < a.StartTs {
+		return false
+	}
+	if a.EndTs != 0 && ts >= a.EndTs {
+		return false
+	}
+	return true
+}
+
 // Setting is the API message for a setting.
 type Setting struct {
 	ID int `jsonapi:"primary,setting"`
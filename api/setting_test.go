@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnouncement(t *testing.T) {
+	tests := []struct {
+		announcement Announcement
+		valid        bool
+		active       bool
+	}{
+		{
+			announcement: Announcement{},
+			valid:        true,
+			active:       false,
+		},
+		{
+			announcement: Announcement{Text: "Maintenance", Severity: AnnouncementWarn},
+			valid:        true,
+			active:       true,
+		},
+		{
+			announcement: Announcement{Text: "Maintenance", Severity: "UNKNOWN"},
+			valid:        false,
+		},
+		{
+			announcement: Announcement{Text: "Maintenance", Severity: AnnouncementInfo, StartTs: 200, EndTs: 100},
+			valid:        false,
+		},
+		{
+			announcement: Announcement{Text: "Maintenance", Severity: AnnouncementCritical, StartTs: 100, EndTs: 200},
+			valid:        true,
+			active:       true,
+		},
+		{
+			announcement: Announcement{Text: "Maintenance", Severity: AnnouncementCritical, StartTs: 200},
+			valid:        true,
+			active:       false,
+		},
+		{
+			announcement: Announcement{Text: "Maintenance", Severity: AnnouncementCritical, EndTs: 150},
+			valid:        true,
+			active:       false,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.announcement.Validate()
+		if !test.valid {
+			require.Error(t, err, test.announcement)
+			continue
+		}
+		require.NoError(t, err, test.announcement)
+		require.Equal(t, test.active, test.announcement.IsActive(150), test.announcement)
+	}
+}
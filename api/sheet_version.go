@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// SheetVersion is the API message for a revision of the sheet statement.
+// A new version is recorded whenever the statement of the sheet changes, and the versions are never modified.
+type SheetVersion struct {
+	ID int `jsonapi:"primary,sheetVersion"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	SheetID int `jsonapi:"attr,sheetId"`
+
+	// Domain specific fields
+	// Version starts from 1 and increases by 1 for each revision of the sheet.
+	Version   int    `jsonapi:"attr,version"`
+	Statement string `jsonapi:"attr,statement"`
+}
+
+// SheetVersionCreate is the API message for creating a sheet version.
+// The version is assigned in order, and no version is created if the statement is the same as the latest version.
+type SheetVersionCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	SheetID int
+
+	// Domain specific fields
+	Statement string
+}
+
+// SheetVersionFind is the API message for finding sheet versions.
+type SheetVersionFind struct {
+	// Related fields
+	SheetID *int
+
+	// Domain specific fields
+	Version *int
+}
+
+// SheetVersionDiff is the API message for the difference between two versions of a sheet.
+type SheetVersionDiff struct {
+	// SheetID is used as the primary key, as the diff is computed on request.
+	SheetID int `jsonapi:"primary,sheetVersionDiff"`
+
+	// Domain specific fields
+	FromVersion int `jsonapi:"attr,fromVersion"`
+	ToVersion   int `jsonapi:"attr,toVersion"`
+	// Diff is in the unified diff format, it's empty if the statements are the same.
+	Diff string `jsonapi:"attr,diff"`
+}
+
+// DiffSheetVersion returns the difference from one version of a sheet to another.
+func DiffSheetVersion(from, to *SheetVersion) (*SheetVersionDiff, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitStatementLines(from.Statement),
+		B:        splitStatementLines(to.Statement),
+		FromFile: fmt.Sprintf("version %d", from.Version),
+		ToFile:   fmt.Sprintf("version %d", to.Version),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff version %d and %d of sheet %d, error: %w", from.Version, to.Version, to.SheetID, err)
+	}
+	return &SheetVersionDiff{
+		SheetID:     to.SheetID,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Diff:        diff,
+	}, nil
+}
+
+// splitStatementLines splits the statement into lines, each line ends with a newline as the unified diff expects.
+func splitStatementLines(statement string) []string {
+	if statement == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(statement, "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSheetVersion(t *testing.T) {
+	from := &SheetVersion{SheetID: 101, Version: 1, Statement: "CREATE TABLE t (id INT);\nCREATE INDEX idx_t_id ON t(id);\n"}
+	to := &SheetVersion{SheetID: 101, Version: 2, Statement: "CREATE TABLE t (id INT, name TEXT);\nCREATE INDEX idx_t_id ON t(id);\n"}
+
+	got, err := DiffSheetVersion(from, to)
+	require.NoError(t, err)
+	require.Equal(t, &SheetVersionDiff{
+		SheetID:     101,
+		FromVersion: 1,
+		ToVersion:   2,
+		Diff: "--- version 1\n" +
+			"+++ version 2\n" +
+			"@@ -1,2 +1,2 @@\n" +
+			"-CREATE TABLE t (id INT);\n" +
+			"+CREATE TABLE t (id INT, name TEXT);\n" +
+			" CREATE INDEX idx_t_id ON t(id);\n",
+	}, got)
+
+	got, err = DiffSheetVersion(from, from)
+	require.NoError(t, err)
+	require.Equal(t, "", got.Diff)
+}
@@ -25,6 +25,11 @@ type ConnectionInfo struct {
 type SQLSyncSchema struct {
 	InstanceID *int `jsonapi:"attr,instanceId"`
 	DatabaseID *int `jsonapi:"attr,databaseId"`
+	// Mode is the mode of the instance sync, empty means DEEP. A database is always synced deeply.
+	Mode SchemaSyncMode `jsonapi:"attr,mode"`
+	// ExactRowCount counts the rows of every table exactly, which may take long on the large tables.
+	// Otherwise the row counts of the large Postgres tables are estimated from the statistics.
+	ExactRowCount bool `jsonapi:"attr,exactRowCount"`
 }
 
 // SQLExecute is the API message for execute SQL.
@@ -39,6 +44,29 @@ type SQLExecute struct {
 	// The maximum row count returned, only applicable to SELECT query.
 	// Not enforced if limit <= 0.
 	Limit int `jsonapi:"attr,limit"`
+	// SessionID identifies the SQL editor session in the session recordings of the databases tagged sensitive.
+	SessionID string `jsonapi:"attr,sessionId"`
+}
+
+// SQLMetadataQuery is the API message for querying the Bytebase metadata database.
+// Only the read-only SELECT / EXPLAIN statement is supported.
+type SQLMetadataQuery struct {
+	Statement string `jsonapi:"attr,statement"`
+	// The maximum row count returned, the server caps it.
+	Limit int `jsonapi:"attr,limit"`
+}
+
+// SQLFormat is the API message for formatting SQL.
+type SQLFormat struct {
+	Engine    db.Type `jsonapi:"attr,engine"`
+	Statement string  `jsonapi:"attr,statement"`
+}
+
+// SQLFormatResult is the API message for the formatted SQL.
+type SQLFormatResult struct {
+	Statement string `jsonapi:"attr,statement"`
+	// Canonical is the statement without comments and with the whitespace normalized, the statements are compared by it.
+	Canonical string `jsonapi:"attr,canonical"`
 }
 
 // SQLResultSet is the API message for SQL results.
@@ -49,6 +77,8 @@ type SQLResultSet struct {
 	Error string `jsonapi:"attr,error"`
 	// A list of SQL check advice.
 	AdviceList []advisor.Advice `jsonapi:"attr,adviceList"`
+	// A list of the connection test steps, only for the connection test.
+	CheckList []ConnectionCheck `jsonapi:"attr,checkList"`
 }
 
 // SQLService is the service for SQL.
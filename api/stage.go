@@ -64,6 +64,41 @@ type StageAllTaskStatusPatch struct {
 	Comment *string    `jsonapi:"attr,comment"`
 }
 
+// StagePromote is the API message for promoting the statements applied in a stage to the next stage.
+type StagePromote struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	// Approve moves the promoted tasks to PENDING, otherwise they wait for the approval in PENDING_APPROVAL.
+	Approve bool `jsonapi:"attr,approve"`
+}
+
+// StageGateRequest is the API message posted to the stage gate provider before starting the stage tasks.
+type StageGateRequest struct {
+	GateName        string `json:"gateName"`
+	IssueID         int    `json:"issueId"`
+	IssueName       string `json:"issueName"`
+	ProjectID       int    `json:"projectId"`
+	PipelineID      int    `json:"pipelineId"`
+	StageID         int    `json:"stageId"`
+	StageName       string `json:"stageName"`
+	EnvironmentID   int    `json:"environmentId"`
+	EnvironmentName string `json:"environmentName"`
+	Link            string `json:"link"`
+}
+
+// StageGateResponse is the API message responded by the stage gate provider.
+type StageGateResponse struct {
+	// Pass allows the stage tasks to start, otherwise the gate is polled again later.
+	Pass bool `json:"pass"`
+	// Detail is the optional message shown in the task check result, e.g. the failed QA cases.
+	Detail string `json:"detail"`
+}
+
 func (find *StageFind) String() string {
 	str, err := json.Marshal(*find)
 	if err != nil {
@@ -21,18 +21,26 @@ type Table struct {
 	Database   *Database `jsonapi:"relation,database"`
 
 	// Domain specific fields
-	Name          string    `jsonapi:"attr,name"`
-	Type          string    `jsonapi:"attr,type"`
-	Engine        string    `jsonapi:"attr,engine"`
-	Collation     string    `jsonapi:"attr,collation"`
-	RowCount      int64     `jsonapi:"attr,rowCount"`
-	DataSize      int64     `jsonapi:"attr,dataSize"`
-	IndexSize     int64     `jsonapi:"attr,indexSize"`
-	DataFree      int64     `jsonapi:"attr,dataFree"`
-	CreateOptions string    `jsonapi:"attr,createOptions"`
-	Comment       string    `jsonapi:"attr,comment"`
-	ColumnList    []*Column `jsonapi:"attr,columnList"`
-	IndexList     []*Index  `jsonapi:"attr,indexList"`
+	Name          string `jsonapi:"attr,name"`
+	Type          string `jsonapi:"attr,type"`
+	Engine        string `jsonapi:"attr,engine"`
+	Collation     string `jsonapi:"attr,collation"`
+	RowCount      int64  `jsonapi:"attr,rowCount"`
+	DataSize      int64  `jsonapi:"attr,dataSize"`
+	IndexSize     int64  `jsonapi:"attr,indexSize"`
+	DataFree      int64  `jsonapi:"attr,dataFree"`
+	CreateOptions string `jsonapi:"attr,createOptions"`
+	Comment       string `jsonapi:"attr,comment"`
+	Owner         string `jsonapi:"attr,owner"`
+	// PartitionKey is only set for the partitioned tables.
+	PartitionKey string `jsonapi:"attr,partitionKey"`
+	// ParentTable and PartitionBound are only set for the partitions, so the partitions are shown under the partitioned table.
+	ParentTable    string    `jsonapi:"attr,parentTable"`
+	PartitionBound string    `jsonapi:"attr,partitionBound"`
+	ColumnList     []*Column `jsonapi:"attr,columnList"`
+	IndexList      []*Index  `jsonapi:"attr,indexList"`
+	// Description is the logical description if present, otherwise the comment.
+	Description string `jsonapi:"attr,description"`
 }
 
 // TableCreate is the API message for creating a table.
@@ -47,16 +55,20 @@ type TableCreate struct {
 	DatabaseID int
 
 	// Domain specific fields
-	Name          string
-	Type          string
-	Engine        string
-	Collation     string
-	RowCount      int64
-	DataSize      int64
-	IndexSize     int64
-	DataFree      int64
-	CreateOptions string
-	Comment       string
+	Name           string
+	Type           string
+	Engine         string
+	Collation      string
+	RowCount       int64
+	DataSize       int64
+	IndexSize      int64
+	DataFree       int64
+	CreateOptions  string
+	Comment        string
+	Owner          string
+	PartitionKey   string
+	ParentTable    string
+	PartitionBound string
 }
 
 // TableFind is the API message for finding tables.
@@ -65,9 +77,11 @@ type TableFind struct {
 
 	// Related fields
 	DatabaseID *int
+	InstanceID *int
 
 	// Domain specific fields
-	Name *string
+	Name  *string
+	Owner *string
 }
 
 func (find *TableFind) String() string {
@@ -86,15 +100,19 @@ type TablePatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	Type          string
-	Engine        string
-	Collation     string
-	RowCount      int64
-	DataSize      int64
-	IndexSize     int64
-	DataFree      int64
-	CreateOptions string
-	Comment       string
+	Type           string
+	Engine         string
+	Collation      string
+	RowCount       int64
+	DataSize       int64
+	IndexSize      int64
+	DataFree       int64
+	CreateOptions  string
+	Comment        string
+	Owner          string
+	PartitionKey   string
+	ParentTable    string
+	PartitionBound string
 }
 
 // TableDelete is the API message for deleting a table.
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// ForeignKey is the API message for a foreign key, one entry per column in the key.
+type ForeignKey struct {
+	ID int `jsonapi:"primary,foreignKey"`
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Related fields
+	DatabaseID int
+	TableID    int
+
+	// Domain specific fields
+	Name             string `json:"name"`
+	Column           string `json:"column"`
+	Position         int    `json:"position"`
+	ReferencedTable  string `json:"referencedTable"`
+	ReferencedColumn string `json:"referencedColumn"`
+	// OnDelete and OnUpdate are the referential actions, e.g. CASCADE, SET NULL or NO ACTION.
+	OnDelete string `json:"onDelete"`
+	OnUpdate string `json:"onUpdate"`
+}
+
+// ForeignKeyCreate is the API message for creating a foreign key.
+type ForeignKeyCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+	TableID    int
+
+	// Domain specific fields
+	Name             string
+	Column           string
+	Position         int
+	ReferencedTable  string
+	ReferencedColumn string
+	OnDelete         string
+	OnUpdate         string
+}
+
+// ForeignKeyFind is the API message for finding foreign keys.
+type ForeignKeyFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+	TableID    *int
+}
+
+func (find *ForeignKeyFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// ForeignKeyDelete is the API message for deleting a foreign key.
+type ForeignKeyDelete struct {
+	ID int
+}
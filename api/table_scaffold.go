@@ -0,0 +1,227 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// TableScaffoldType is the type of a generated statement template of a table.
+type TableScaffoldType string
+
+const (
+	// TableScaffoldColumnAdd adds a NOT NULL column with a default value without locking the table for long.
+	TableScaffoldColumnAdd TableScaffoldType = "COLUMN_ADD"
+	// TableScaffoldArchiveTable creates the archive table with the same columns and indexes as the table.
+	TableScaffoldArchiveTable TableScaffoldType = "ARCHIVE_TABLE"
+	// TableScaffoldArchiveRows moves the matching rows of the table to the archive table.
+	TableScaffoldArchiveRows TableScaffoldType = "ARCHIVE_ROWS"
+	// TableScaffoldAuditTrigger creates the audit table and the triggers recording the row changes of the table into it.
+	TableScaffoldAuditTrigger TableScaffoldType = "AUDIT_TRIGGER"
+)
+
+// The placeholders in the templates, they're chosen so that running the template unchanged does no harm.
+const (
+	scaffoldColumnName    = "new_column"
+	scaffoldColumnType    = "INT"
+	scaffoldColumnDefault = "0"
+	// scaffoldArchiveCondition matches no rows.
+	scaffoldArchiveCondition = "1 = 0"
+)
+
+// TableScaffold is the API message for a generated statement template of a table, the starting point of an issue statement.
+type TableScaffold struct {
+	Type TableScaffoldType `json:"type"`
+	Name string            `json:"name"`
+	// TaskType is the type of the task running the statement.
+	TaskType  TaskType `json:"taskType"`
+	Statement string   `json:"statement"`
+}
+
+// GenerateTableScaffoldList generates the statement templates of the synced table, including the column list.
+// The engine version decides the syntax, it's the synced version of the instance.
+// The templates adding a column are the steps of PlanColumnAdd, which are meant to run in order.
+func GenerateTableScaffoldList(dbType db.Type, engineVersion string, table *Table) ([]*TableScaffold, error) {
+	if len(table.ColumnList) == 0 {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("table %q has no synced columns", table.Name)}
+	}
+	columnList := make([]*Column, len(table.ColumnList))
+	copy(columnList, table.ColumnList)
+	sort.SliceStable(columnList, func(i, j int) bool {
+		return columnList[i].Position < columnList[j].Position
+	})
+
+	stepList, err := PlanColumnAdd(dbType, engineVersion, table.Name, scaffoldColumnName, scaffoldColumnType, scaffoldColumnDefault, DefaultColumnAddBatchSize)
+	if err != nil {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("generating scaffolding is not supported for %s", dbType)}
+	}
+	var scaffoldList []*TableScaffold
+	for _, step := range stepList {
+		scaffoldList = append(scaffoldList, &TableScaffold{
+			Type:      TableScaffoldColumnAdd,
+			Name:      step.Name,
+			TaskType:  step.TaskType,
+			Statement: step.Statement,
+		})
+	}
+
+	switch dbType {
+	case db.MySQL, db.MariaDB:
+		scaffoldList = append(scaffoldList, generateMySQLTableScaffoldList(dbType, parseEngineVersion(engineVersion), table.Name, columnList)...)
+	case db.Postgres:
+		scaffoldList = append(scaffoldList, generatePostgresTableScaffoldList(parseEngineVersion(engineVersion), table.Name, columnList)...)
+	}
+	return scaffoldList, nil
+}
+
+func generateMySQLTableScaffoldList(dbType db.Type, version []int, tableName string, columnList []*Column) []*TableScaffold {
+	quote := func(s string) string {
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "`", "``"))
+	}
+	table := quote(tableName)
+	archiveTable := quote(fmt.Sprintf("%s_archive", tableName))
+	var columnNameList []string
+	for _, column := range columnList {
+		columnNameList = append(columnNameList, quote(column.Name))
+	}
+	columns := strings.Join(columnNameList, ", ")
+
+	scaffoldList := []*TableScaffold{
+		{
+			// CREATE TABLE ... LIKE keeps the indexes but not the foreign keys, so the archived rows don't block deleting the referenced ones.
+			Type:      TableScaffoldArchiveTable,
+			Name:      fmt.Sprintf("Create archive table %s_archive", tableName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("CREATE TABLE %s LIKE %s;", archiveTable, table),
+		},
+		{
+			Type:     TableScaffoldArchiveRows,
+			Name:     fmt.Sprintf("Archive rows of table %s", tableName),
+			TaskType: TaskDatabaseDataUpdate,
+			Statement: fmt.Sprintf("-- Replace the condition %q with the one of the rows to archive.\n", scaffoldArchiveCondition) +
+				fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE %s;\n", archiveTable, columns, columns, table, scaffoldArchiveCondition) +
+				fmt.Sprintf("DELETE FROM %s WHERE %s;", table, scaffoldArchiveCondition),
+		},
+	}
+
+	// The audit rows are JSON objects, which needs MySQL 5.7.8 or MariaDB 10.2.7.
+	jsonVersion := []int{5, 7, 8}
+	if dbType == db.MariaDB {
+		jsonVersion = []int{10, 2, 7}
+	}
+	if compareEngineVersion(version, jsonVersion) < 0 {
+		return scaffoldList
+	}
+	auditTable := quote(fmt.Sprintf("%s_audit", tableName))
+	rowObject := func(row string) string {
+		var argList []string
+		for _, column := range columnList {
+			argList = append(argList, fmt.Sprintf("'%s', %s.%s", strings.ReplaceAll(column.Name, "'", "''"), row, quote(column.Name)))
+		}
+		return fmt.Sprintf("JSON_OBJECT(%s)", strings.Join(argList, ", "))
+	}
+	var statementList []string
+	statementList = append(statementList, fmt.Sprintf(`CREATE TABLE %s (
+  audit_id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  operation VARCHAR(6) NOT NULL,
+  changed_by VARCHAR(288) NOT NULL,
+  changed_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  old_row JSON NULL,
+  new_row JSON NULL
+);`, auditTable))
+	// MySQL has no statement-level variable of the operation, so there is a trigger for each operation.
+	for _, operation := range []string{"INSERT", "UPDATE", "DELETE"} {
+		oldRow, newRow := "NULL", "NULL"
+		if operation != "INSERT" {
+			oldRow = rowObject("OLD")
+		}
+		if operation != "DELETE" {
+			newRow = rowObject("NEW")
+		}
+		trigger := quote(fmt.Sprintf("bb_%s_audit_%s", tableName, strings.ToLower(operation)))
+		statementList = append(statementList, fmt.Sprintf("CREATE TRIGGER %s AFTER %s ON %s FOR EACH ROW\nINSERT INTO %s (operation, changed_by, old_row, new_row) VALUES ('%s', CURRENT_USER(), %s, %s);", trigger, operation, table, auditTable, operation, oldRow, newRow))
+	}
+	return append(scaffoldList, &TableScaffold{
+		Type:      TableScaffoldAuditTrigger,
+		Name:      fmt.Sprintf("Create audit triggers of table %s", tableName),
+		TaskType:  TaskDatabaseSchemaUpdate,
+		Statement: strings.Join(statementList, "\n\n"),
+	})
+}
+
+func generatePostgresTableScaffoldList(version []int, tableName string, columnList []*Column) []*TableScaffold {
+	quote := func(s string) string {
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+	}
+	schemaName := "public"
+	if i := strings.Index(tableName, "."); i >= 0 {
+		schemaName, tableName = tableName[:i], tableName[i+1:]
+	}
+	table := fmt.Sprintf("%s.%s", quote(schemaName), quote(tableName))
+	archiveTable := fmt.Sprintf("%s.%s", quote(schemaName), quote(fmt.Sprintf("%s_archive", tableName)))
+	auditTable := fmt.Sprintf("%s.%s", quote(schemaName), quote(fmt.Sprintf("%s_audit", tableName)))
+	function := fmt.Sprintf("%s.%s", quote(schemaName), quote(fmt.Sprintf("bb_%s_audit", tableName)))
+	trigger := quote(fmt.Sprintf("bb_%s_audit", tableName))
+	var columnNameList []string
+	for _, column := range columnList {
+		columnNameList = append(columnNameList, quote(column.Name))
+	}
+	columns := strings.Join(columnNameList, ", ")
+
+	// EXECUTE PROCEDURE is deprecated by PostgreSQL 11 in favor of EXECUTE FUNCTION.
+	execute := "EXECUTE PROCEDURE"
+	if compareEngineVersion(version, []int{11}) >= 0 {
+		execute = "EXECUTE FUNCTION"
+	}
+
+	return []*TableScaffold{
+		{
+			// INCLUDING ALL keeps the defaults, the constraints and the indexes but not the foreign keys.
+			Type:      TableScaffoldArchiveTable,
+			Name:      fmt.Sprintf("Create archive table %s_archive", tableName),
+			TaskType:  TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING ALL);", archiveTable, table),
+		},
+		{
+			// The rows are moved in a single statement, so no row is deleted without being archived.
+			Type:     TableScaffoldArchiveRows,
+			Name:     fmt.Sprintf("Archive rows of table %s", tableName),
+			TaskType: TaskDatabaseDataUpdate,
+			Statement: fmt.Sprintf("-- Replace the condition %q with the one of the rows to archive.\n", scaffoldArchiveCondition) +
+				fmt.Sprintf("WITH archived AS (DELETE FROM %s WHERE %s RETURNING %s)\n", table, scaffoldArchiveCondition, columns) +
+				fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM archived;", archiveTable, columns, columns),
+		},
+		{
+			// OLD and NEW are only referenced for the operations setting them, since referencing the unset one fails before PostgreSQL 11.
+			Type:     TableScaffoldAuditTrigger,
+			Name:     fmt.Sprintf("Create audit trigger of table %s", tableName),
+			TaskType: TaskDatabaseSchemaUpdate,
+			Statement: fmt.Sprintf(`CREATE TABLE %s (
+  audit_id BIGSERIAL PRIMARY KEY,
+  operation TEXT NOT NULL,
+  changed_by TEXT NOT NULL DEFAULT current_user,
+  changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  old_row JSONB,
+  new_row JSONB
+);
+
+CREATE OR REPLACE FUNCTION %s() RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    INSERT INTO %s (operation, new_row) VALUES (TG_OP, to_jsonb(NEW));
+  ELSIF TG_OP = 'UPDATE' THEN
+    INSERT INTO %s (operation, old_row, new_row) VALUES (TG_OP, to_jsonb(OLD), to_jsonb(NEW));
+  ELSE
+    INSERT INTO %s (operation, old_row) VALUES (TG_OP, to_jsonb(OLD));
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW %s %s();`, auditTable, function, auditTable, auditTable, auditTable, trigger, table, execute, function),
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGenerateTableScaffoldList(t *testing.T) {
+	table := &Table{
+		Name: "orders",
+		ColumnList: []*Column{
+			{Name: "status", Position: 2},
+			{Name: "id", Position: 1},
+		},
+	}
+	getScaffold := func(scaffoldList []*TableScaffold, scaffoldType TableScaffoldType) *TableScaffold {
+		for _, scaffold := range scaffoldList {
+			if scaffold.Type == scaffoldType {
+				return scaffold
+			}
+		}
+		return nil
+	}
+
+	scaffoldList, err := GenerateTableScaffoldList(db.MySQL, "8.0.28", table)
+	require.NoError(t, err)
+	require.Equal(t, TaskDatabaseDataBackfill, scaffoldList[2].TaskType)
+	require.Equal(t, "ALTER TABLE `orders` ADD COLUMN `new_column` INT NULL, ALGORITHM=INSTANT;", scaffoldList[0].Statement)
+	require.Equal(t, "CREATE TABLE `orders_archive` LIKE `orders`;", getScaffold(scaffoldList, TableScaffoldArchiveTable).Statement)
+	require.Equal(t, "-- Replace the condition \"1 = 0\" with the one of the rows to archive.\n"+
+		"INSERT INTO `orders_archive` (`id`, `status`) SELECT `id`, `status` FROM `orders` WHERE 1 = 0;\n"+
+		"DELETE FROM `orders` WHERE 1 = 0;", getScaffold(scaffoldList, TableScaffoldArchiveRows).Statement)
+	require.Contains(t, getScaffold(scaffoldList, TableScaffoldAuditTrigger).Statement,
+		"CREATE TRIGGER `bb_orders_audit_update` AFTER UPDATE ON `orders` FOR EACH ROW\n"+
+			"INSERT INTO `orders_audit` (operation, changed_by, old_row, new_row) VALUES ('UPDATE', CURRENT_USER(), JSON_OBJECT('id', OLD.`id`, 'status', OLD.`status`), JSON_OBJECT('id', NEW.`id`, 'status', NEW.`status`));")
+
+	// MySQL 5.6 has no JSON.
+	scaffoldList, err = GenerateTableScaffoldList(db.MySQL, "5.6.51", table)
+	require.NoError(t, err)
+	require.Nil(t, getScaffold(scaffoldList, TableScaffoldAuditTrigger))
+
+	scaffoldList, err = GenerateTableScaffoldList(db.Postgres, "10.21", &Table{Name: "sales.orders", ColumnList: table.ColumnList})
+	require.NoError(t, err)
+	require.Equal(t, `CREATE TABLE "sales"."orders_archive" (LIKE "sales"."orders" INCLUDING ALL);`, getScaffold(scaffoldList, TableScaffoldArchiveTable).Statement)
+	require.Contains(t, getScaffold(scaffoldList, TableScaffoldArchiveRows).Statement,
+		`WITH archived AS (DELETE FROM "sales"."orders" WHERE 1 = 0 RETURNING "id", "status")`)
+	require.Contains(t, getScaffold(scaffoldList, TableScaffoldAuditTrigger).Statement,
+		`CREATE TRIGGER "bb_orders_audit" AFTER INSERT OR UPDATE OR DELETE ON "sales"."orders" FOR EACH ROW EXECUTE PROCEDURE "sales"."bb_orders_audit"();`)
+
+	_, err = GenerateTableScaffoldList(db.ClickHouse, "22.3", table)
+	require.Equal(t, common.Invalid, common.ErrorCode(err))
+	_, err = GenerateTableScaffoldList(db.MySQL, "8.0.28", &Table{Name: "orders"})
+	require.Equal(t, common.Invalid, common.ErrorCode(err))
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// maxTagLength is the maximum length of a tag.
+const maxTagLength = 63
+
+// tagRegexp matches the tag, e.g. "pci" or "tier-1".
+var tagRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// ValidateTag validates the tag of the instances, the databases and the policies.
+func ValidateTag(tag string) error {
+	if len(tag) > maxTagLength {
+		return fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+	}
+	if !tagRegexp.MatchString(tag) {
+		return fmt.Errorf("invalid tag %q, it must start with a lowercase letter or a digit, and only contain lowercase letters, digits, '_', '-' and '.'", tag)
+	}
+	return nil
+}
+
+// ParseTagList parses the json-encoded tag list, e.g. "["pci","gdpr"]".
+// Empty string means no tags.
+func ParseTagList(s string) ([]string, error) {
+	var tagList []string
+	if s == "" {
+		return tagList, nil
+	}
+	if err := json.Unmarshal([]byte(s), &tagList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tag list %q, error: %w", s, err)
+	}
+	return tagList, nil
+}
+
+// NormalizeTagList validates the json-encoded tag list and returns it without the duplicate tags.
+func NormalizeTagList(s string) (string, error) {
+	tagList, err := ParseTagList(s)
+	if err != nil {
+		return "", err
+	}
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tagList {
+		if err := ValidateTag(tag); err != nil {
+			return "", err
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	b, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// GetPolicyTagList returns the tags selecting the tag policies of the database, which are the tags of the instance
+// followed by the ones of the database. The earlier tag takes precedence if the policies of several tags apply.
+// The database is nil for the operations on the instance.
+// The tag lists are validated when patched, so the malformed ones are ignored.
+func GetPolicyTagList(instance *Instance, database *Database) []string {
+	var tagList []string
+	seen := make(map[string]bool)
+	appendTagList := func(s string) {
+		list, err := ParseTagList(s)
+		if err != nil {
+			return
+		}
+		for _, tag := range list {
+			if !seen[tag] {
+				seen[tag] = true
+				tagList = append(tagList, tag)
+			}
+		}
+	}
+	if instance != nil {
+		appendTagList(instance.TagList)
+	}
+	if database != nil {
+		appendTagList(database.TagList)
+	}
+	return tagList
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTagList(t *testing.T) {
+	tests := []struct {
+		name    string
+		tagList string
+		want    string
+		errPart string
+	}{
+		{
+			name:    "empty",
+			tagList: "",
+			want:    "[]",
+		},
+		{
+			name:    "duplicate",
+			tagList: `["pci","tier-1","pci","v1.2_a"]`,
+			want:    `["pci","tier-1","v1.2_a"]`,
+		},
+		{
+			name:    "malformed",
+			tagList: `["pci"`,
+			errPart: "failed to unmarshal tag list",
+		},
+		{
+			name:    "uppercase",
+			tagList: `["PCI"]`,
+			errPart: "invalid tag",
+		},
+		{
+			name:    "emptyTag",
+			tagList: `[""]`,
+			errPart: "invalid tag",
+		},
+		{
+			name:    "tooLong",
+			tagList: `["` + strings.Repeat("a", maxTagLength+1) + `"]`,
+			errPart: "longer than",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NormalizeTagList(test.tagList)
+			if test.errPart != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errPart)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestGetPolicyTagList(t *testing.T) {
+	instance := &Instance{TagList: `["pci","gdpr"]`}
+	database := &Database{TagList: `["tier-1","pci"]`}
+
+	assert.Equal(t, []string{"pci", "gdpr", "tier-1"}, GetPolicyTagList(instance, database))
+	assert.Equal(t, []string{"pci", "gdpr"}, GetPolicyTagList(instance, nil))
+	assert.Equal(t, []string{"tier-1", "pci"}, GetPolicyTagList(&Instance{}, database))
+	assert.Empty(t, GetPolicyTagList(nil, nil))
+}
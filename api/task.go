@@ -58,6 +58,20 @@ const (
 	TaskDatabasePITRRestore TaskType = "bb.task.database.pitr.restore"
 	// TaskDatabasePITRCutover is the task type for swapping the pitr and original database.
 	TaskDatabasePITRCutover TaskType = "bb.task.database.pitr.cutover"
+	// TaskDatabaseCharsetConvert is the task type for converting the character set of MySQL tables.
+	TaskDatabaseCharsetConvert TaskType = "bb.task.database.charset.convert"
+	// TaskDatabaseDataBackfill is the task type for backfilling data in batches.
+	TaskDatabaseDataBackfill TaskType = "bb.task.database.data.backfill"
+	// TaskDatabaseDrop is the task type for dropping a database after taking a final backup.
+	TaskDatabaseDrop TaskType = "bb.task.database.drop"
+	// TaskDatabaseDataValidate is the task type for comparing the row counts and checksums of the tables with the source database.
+	TaskDatabaseDataValidate TaskType = "bb.task.database.data.validate"
+	// TaskDatabaseAccessChange is the task type for changing the users and the grants.
+	TaskDatabaseAccessChange TaskType = "bb.task.database.access.change"
+	// TaskInstanceParameterChange is the task type for changing the instance parameters.
+	TaskInstanceParameterChange TaskType = "bb.task.instance.parameter.change"
+	// TaskInstanceUpgradeCheck is the task type for checking the readiness of the Postgres major version upgrade.
+	TaskInstanceUpgradeCheck TaskType = "bb.task.instance.upgrade.check"
 )
 
 // These payload types are only used when marshalling to the json format for saving into the database.
@@ -101,14 +115,33 @@ type TaskDatabaseCreatePayload struct {
 	Collation     string `json:"collation,omitempty"`
 	Labels        string `json:"labels,omitempty"`
 	SchemaVersion string `json:"schemaVersion,omitempty"`
+	// TemplateName is the name of the database template whose statement is appended to the Statement.
+	TemplateName string `json:"templateName,omitempty"`
+	// The Postgres options below are already in the Statement, they're recorded for displaying the task.
+	Owner           string   `json:"owner,omitempty"`
+	Tablespace      string   `json:"tablespace,omitempty"`
+	ConnectionLimit int      `json:"connectionLimit,omitempty"`
+	ExtensionList   []string `json:"extensionList,omitempty"`
 }
 
 // TaskDatabaseSchemaUpdatePayload is the task payload for database schema update (DDL).
 type TaskDatabaseSchemaUpdatePayload struct {
 	MigrationType db.MigrationType `json:"migrationType,omitempty"`
 	Statement     string           `json:"statement,omitempty"`
+	DownStatement string           `json:"downStatement,omitempty"`
 	SchemaVersion string           `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent   `json:"pushEvent,omitempty"`
+	// Verification is the optional query verifying the database after the migration.
+	Verification *TaskVerification `json:"verification,omitempty"`
+	// PromotedFromTaskID is the ID of the task in the previous stage whose applied statement is promoted to this task.
+	PromotedFromTaskID int `json:"promotedFromTaskId,omitempty"`
+	// SheetID and SheetVersion refer to the sheet version the statement is taken from.
+	// They are cleared once the statement is changed to something else.
+	SheetID      int `json:"sheetId,omitempty"`
+	SheetVersion int `json:"sheetVersion,omitempty"`
+	// SessionSetting is the optional session settings applied before executing the statement,
+	// the unset ones are taken from the session setting policy of the environment.
+	SessionSetting *db.SessionSetting `json:"sessionSetting,omitempty"`
 }
 
 // TaskDatabaseSchemaUpdateGhostSyncPayload is the task payload for gh-ost syncing ghost table.
@@ -129,8 +162,71 @@ type TaskDatabaseSchemaUpdateGhostCutoverPayload struct {
 // TaskDatabaseDataUpdatePayload is the task payload for database data update (DML).
 type TaskDatabaseDataUpdatePayload struct {
 	Statement     string         `json:"statement,omitempty"`
+	DownStatement string         `json:"downStatement,omitempty"`
 	SchemaVersion string         `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// Verification is the optional query verifying the database after the migration.
+	Verification *TaskVerification `json:"verification,omitempty"`
+	// PromotedFromTaskID is the ID of the task in the previous stage whose applied statement is promoted to this task.
+	PromotedFromTaskID int `json:"promotedFromTaskId,omitempty"`
+	// SheetID and SheetVersion refer to the sheet version the statement is taken from.
+	// They are cleared once the statement is changed to something else.
+	SheetID      int `json:"sheetId,omitempty"`
+	SheetVersion int `json:"sheetVersion,omitempty"`
+	// SessionSetting is the optional session settings applied before executing the statement,
+	// the unset ones are taken from the session setting policy of the environment.
+	SessionSetting *db.SessionSetting `json:"sessionSetting,omitempty"`
+}
+
+// TaskDatabaseCharsetConvertPayload is the task payload for converting the character set of MySQL tables.
+type TaskDatabaseCharsetConvertPayload struct {
+	Plan          *CharsetConversionPlan `json:"plan,omitempty"`
+	SchemaVersion string                 `json:"schemaVersion,omitempty"`
+}
+
+// TaskDatabaseDataBackfillPayload is the task payload for backfilling data in batches.
+type TaskDatabaseDataBackfillPayload struct {
+	// BatchStatement is executed repeatedly until it affects no rows.
+	BatchStatement string `json:"batchStatement,omitempty"`
+	// EstimatedRowCount is the synced row count of the table, it's used as the total of the progress.
+	EstimatedRowCount int64 `json:"estimatedRowCount,omitempty"`
+}
+
+// TaskDatabaseDropPayload is the task payload for dropping a database.
+type TaskDatabaseDropPayload struct {
+	DatabaseName string `json:"databaseName,omitempty"`
+}
+
+// TaskDatabaseDataValidatePayload is the task payload for comparing the data with the source database.
+type TaskDatabaseDataValidatePayload struct {
+	SourceDatabaseID int      `json:"sourceDatabaseId,omitempty"`
+	TableList        []string `json:"tableList,omitempty"`
+	ChunkSize        int      `json:"chunkSize,omitempty"`
+}
+
+// TaskDatabaseAccessChangePayload is the task payload for changing the users and the grants.
+type TaskDatabaseAccessChangePayload struct {
+	// Statement is the generated statements with the passwords masked, it's for displaying the task.
+	Statement  string          `json:"statement,omitempty"`
+	ChangeList []*AccessChange `json:"changeList,omitempty"`
+}
+
+// TaskInstanceParameterChangePayload is the task payload for changing the instance parameters.
+type TaskInstanceParameterChangePayload struct {
+	// Statement is the generated statements, it's for displaying the task.
+	Statement     string             `json:"statement,omitempty"`
+	ParameterList []*ParameterChange `json:"parameterList,omitempty"`
+	// AppliedTs is the time of the instance when the changes are applied, it's 0 before applying.
+	// The task is rerun until the instance restarts after it if PendingRestartList isn't empty.
+	AppliedTs int64 `json:"appliedTs,omitempty"`
+	// PendingRestartList is the parameters taking effect only after the instance restarts.
+	PendingRestartList []string `json:"pendingRestartList,omitempty"`
+}
+
+// TaskInstanceUpgradeCheckPayload is the task payload for checking the readiness of the Postgres major version upgrade.
+type TaskInstanceUpgradeCheckPayload struct {
+	TargetVersion    string `json:"targetVersion,omitempty"`
+	TargetInstanceID int    `json:"targetInstanceId,omitempty"`
 }
 
 // TaskDatabaseBackupPayload is the task payload for database backup.
@@ -235,9 +331,13 @@ type TaskFind struct {
 	// Related fields
 	PipelineID *int
 	StageID    *int
+	DatabaseID *int
 
 	// Domain specific fields
 	StatusList *[]TaskStatus
+	// CommitID and FilePath match the VCS push event recorded in the task payload.
+	CommitID *string
+	FilePath *string
 }
 
 func (find *TaskFind) String() string {
@@ -261,6 +361,8 @@ type TaskPatch struct {
 	Statement         *string `jsonapi:"attr,statement"`
 	Payload           *string
 	EarliestAllowedTs *int64 `jsonapi:"attr,earliestAllowedTs"`
+	// Verification is the JSON encoded TaskVerification, empty removes the verification.
+	Verification *string `jsonapi:"attr,verification"`
 }
 
 // TaskStatusPatch is the API message for patching a task status.
@@ -276,4 +378,6 @@ type TaskStatusPatch struct {
 	Code    *common.Code
 	Comment *string `jsonapi:"attr,comment"`
 	Result  *string
+	// RequestID is the ID of the API request patching the status, it's recorded on the task run started by the patch.
+	RequestID string
 }
@@ -67,6 +67,8 @@ const (
 	TaskCheckDatabaseStatementCompatibility TaskCheckType = "bb.task-check.database.statement.compatibility"
 	// TaskCheckDatabaseStatementAdvise is the task check type for schema system review policy.
 	TaskCheckDatabaseStatementAdvise TaskCheckType = "bb.task-check.database.statement.advise"
+	// TaskCheckDatabaseStatementExternalAdvise is the task check type for the advices of the external policy engine.
+	TaskCheckDatabaseStatementExternalAdvise TaskCheckType = "bb.task-check.database.statement.external-advise"
 	// TaskCheckDatabaseStatementType is the task check type for statement type.
 	TaskCheckDatabaseStatementType TaskCheckType = "bb.task-check.database.statement.type"
 	// TaskCheckDatabaseConnect is the task check type for database connection.
@@ -75,8 +77,20 @@ const (
 	TaskCheckInstanceMigrationSchema TaskCheckType = "bb.task-check.instance.migration-schema"
 	// TaskCheckGhostSync is the task check type for the gh-ost sync task.
 	TaskCheckGhostSync TaskCheckType = "bb.task-check.database.ghost.sync"
+	// TaskCheckDatabaseImpactAnalysis is the task check type for the objects depending on the altered or dropped tables.
+	TaskCheckDatabaseImpactAnalysis TaskCheckType = "bb.task-check.database.impact-analysis"
+	// TaskCheckDatabaseMigrationDuplicate is the task check type for the same migration already applied to the database by another issue.
+	TaskCheckDatabaseMigrationDuplicate TaskCheckType = "bb.task-check.database.migration.duplicate"
+	// TaskCheckDatabaseCreateName is the task check type for the name of the database to create.
+	TaskCheckDatabaseCreateName TaskCheckType = "bb.task-check.database.create.name"
+	// TaskCheckDatabaseDropActivity is the task check type for the recent activity of the database to drop.
+	TaskCheckDatabaseDropActivity TaskCheckType = "bb.task-check.database.drop.activity"
 	// TaskCheckGeneralEarliestAllowedTime is the task check type for earliest allowed time.
 	TaskCheckGeneralEarliestAllowedTime TaskCheckType = "bb.task-check.general.earliest-allowed-time"
+	// TaskCheckGeneralStageGate is the task check type for the external gates of the stage.
+	TaskCheckGeneralStageGate TaskCheckType = "bb.task-check.general.stage-gate"
+	// TaskCheckDatabaseReplicationLag is the task check type for the replication lag before the cutover.
+	TaskCheckDatabaseReplicationLag TaskCheckType = "bb.task-check.database.replication-lag"
 )
 
 // TaskCheckEarliestAllowedTimePayload is the task check payload for earliest allowed time.
@@ -84,6 +98,16 @@ type TaskCheckEarliestAllowedTimePayload struct {
 	EarliestAllowedTs int64 `json:"earliestAllowedTs,omitempty"`
 }
 
+// TaskCheckStageGatePayload is the task check payload for the external gates of the stage.
+type TaskCheckStageGatePayload struct {
+	GateList []StageGate `json:"gateList,omitempty"`
+}
+
+// TaskCheckReplicationLagPayload is the task check payload for the replication lag before the cutover.
+type TaskCheckReplicationLagPayload struct {
+	MaxLagSeconds int64 `json:"maxLagSeconds,omitempty"`
+}
+
 // TaskCheckDatabaseStatementAdvisePayload is the task check payload for database statement advise.
 type TaskCheckDatabaseStatementAdvisePayload struct {
 	Statement string  `json:"statement,omitempty"`
@@ -95,12 +119,48 @@ type TaskCheckDatabaseStatementAdvisePayload struct {
 	PolicyID int `json:"policyID,omitempty"`
 }
 
+// TaskCheckDatabaseStatementExternalAdvisePayload is the task check payload for the advices of the external policy engine.
+type TaskCheckDatabaseStatementExternalAdvisePayload struct {
+	Statement string  `json:"statement,omitempty"`
+	DbType    db.Type `json:"dbType,omitempty"`
+	Charset   string  `json:"charset,omitempty"`
+	Collation string  `json:"collation,omitempty"`
+}
+
 // TaskCheckDatabaseStatementTypePayload is the task check payload for SQL type.
 type TaskCheckDatabaseStatementTypePayload struct {
 	Statement string  `json:"statement,omitempty"`
 	DbType    db.Type `json:"dbType,omitempty"`
 }
 
+// TaskCheckDatabaseImpactAnalysisPayload is the task check payload for impact analysis.
+type TaskCheckDatabaseImpactAnalysisPayload struct {
+	Statement string  `json:"statement,omitempty"`
+	DbType    db.Type `json:"dbType,omitempty"`
+	Charset   string  `json:"charset,omitempty"`
+	Collation string  `json:"collation,omitempty"`
+}
+
+// TaskCheckDatabaseMigrationDuplicatePayload is the task check payload for the duplicate migration.
+type TaskCheckDatabaseMigrationDuplicatePayload struct {
+	Statement string  `json:"statement,omitempty"`
+	DbType    db.Type `json:"dbType,omitempty"`
+}
+
+// TaskCheckDatabaseCreateNamePayload is the task check payload for the name of the database to create.
+type TaskCheckDatabaseCreateNamePayload struct {
+	DatabaseName string  `json:"databaseName,omitempty"`
+	DbType       db.Type `json:"dbType,omitempty"`
+	// Pattern is the database name pattern of the project when the check is scheduled.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// TaskCheckDatabaseDropActivityPayload is the task check payload for the recent activity of the database to drop.
+type TaskCheckDatabaseDropActivityPayload struct {
+	DatabaseName string  `json:"databaseName,omitempty"`
+	DbType       db.Type `json:"dbType,omitempty"`
+}
+
 // Namespace is the namespace for task check result.
 type Namespace string
 
@@ -208,7 +268,7 @@ type TaskCheckRunStatusPatch struct {
 
 // IsSyntaxCheckSupported checks the engine type if syntax check supports it.
 func IsSyntaxCheckSupported(dbType db.Type, _ common.ReleaseMode) bool {
-	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB {
+	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB || dbType == db.MariaDB {
 		advisorDB, err := advisorDB.ConvertToAdvisorDBType(string(dbType))
 		if err != nil {
 			return false
@@ -222,7 +282,7 @@ func IsSyntaxCheckSupported(dbType db.Type, _ common.ReleaseMode) bool {
 
 // IsSQLReviewSupported checks the engine type if SQL review supports it.
 func IsSQLReviewSupported(dbType db.Type, _ common.ReleaseMode) bool {
-	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB {
+	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB || dbType == db.MariaDB {
 		advisorDB, err := advisorDB.ConvertToAdvisorDBType(string(dbType))
 		if err != nil {
 			return false
@@ -27,6 +27,10 @@ type TaskRunResultPayload struct {
 	Detail      string `json:"detail,omitempty"`
 	MigrationID int64  `json:"migrationId,omitempty"`
 	Version     string `json:"version,omitempty"`
+	// Verification is the output of the verification query run after the migration.
+	Verification string `json:"verification,omitempty"`
+	// InvalidObjectList is the invalid indexes and constraints found after the Postgres migration.
+	InvalidObjectList []*InvalidObject `json:"invalidObjectList,omitempty"`
 }
 
 // TaskRun is the API message for a task run.
@@ -52,6 +56,8 @@ type TaskRun struct {
 	Comment string        `jsonapi:"attr,comment"`
 	Result  string        `jsonapi:"attr,result"`
 	Payload string        `jsonapi:"attr,payload"`
+	// RequestID is the ID of the API request starting the task run, it's empty if the task run is started by the scheduler.
+	RequestID string `jsonapi:"attr,requestId"`
 }
 
 // TaskRunCreate is the API message for creating a task run.
@@ -67,6 +73,8 @@ type TaskRunCreate struct {
 	Name    string   `jsonapi:"attr,name"`
 	Type    TaskType `jsonapi:"attr,type"`
 	Payload string   `jsonapi:"attr,payload"`
+	// RequestID is the ID of the API request starting the task run.
+	RequestID string
 }
 
 // TaskRunFind is the API message for finding task runs.
@@ -78,6 +86,9 @@ type TaskRunFind struct {
 
 	// Domain specific fields
 	StatusList *[]TaskRunStatus
+	// CreatedAfterTs and CreatedBeforeTs find the task runs started in the time range, both are inclusive.
+	CreatedAfterTs  *int64
+	CreatedBeforeTs *int64
 }
 
 func (find *TaskRunFind) String() string {
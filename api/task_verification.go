@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// maxVerificationOutputLength is the maximum length of the verification query rows recorded in the task run.
+const maxVerificationOutputLength = 512
+
+// TaskVerification is the verification query run on the database after the migration of the task is applied.
+// The task fails if the assertion doesn't hold.
+type TaskVerification struct {
+	// Query is the SELECT statement, it's executed in a read-only transaction.
+	Query string `json:"query"`
+	// ExpectedRowCount is the number of rows the query must return.
+	// If it's nil, the query must return a single row with a single true value, e.g. SELECT count(*) = 0 FROM t WHERE ...
+	ExpectedRowCount *int `json:"expectedRowCount,omitempty"`
+}
+
+// Validate validates the verification is well-formed.
+func (v *TaskVerification) Validate() error {
+	query := strings.ToUpper(strings.TrimSpace(v.Query))
+	if !strings.HasPrefix(query, "SELECT") && !strings.HasPrefix(query, "WITH") {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("verification query must be a SELECT statement")}
+	}
+	if v.ExpectedRowCount != nil && *v.ExpectedRowCount < 0 {
+		return &common.Error{Code: common.Invalid, Err: fmt.Errorf("expected row count must not be negative, got %d", *v.ExpectedRowCount)}
+	}
+	return nil
+}
+
+// GetQueryLimit returns the row limit of the verification query.
+// It's one more than the expected rows so that the extra rows are detected.
+func (v *TaskVerification) GetQueryLimit() int {
+	if v.ExpectedRowCount != nil {
+		return *v.ExpectedRowCount + 1
+	}
+	return 2
+}
+
+// Check checks the verification query result and returns the output recorded in the task run.
+// The rowSet is the query result of the database driver, i.e. the column names, the column types and the rows.
+// It returns a TaskVerificationFailed error containing the output if the assertion doesn't hold.
+func (v *TaskVerification) Check(rowSet []interface{}) (string, error) {
+	if len(rowSet) != 3 {
+		return "", fmt.Errorf("invalid verification query result with %d parts, expect 3", len(rowSet))
+	}
+	rows, ok := rowSet[2].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid verification query rows of type %T", rowSet[2])
+	}
+	bytes, err := json.Marshal(rows)
+	if err != nil {
+		return "", err
+	}
+	output := string(bytes)
+	if len(output) > maxVerificationOutputLength {
+		output = output[:maxVerificationOutputLength] + "..."
+	}
+	output = fmt.Sprintf("Verification query returned %d row(s): %s", len(rows), output)
+
+	if v.ExpectedRowCount != nil {
+		if len(rows) != *v.ExpectedRowCount {
+			return "", common.Errorf(common.TaskVerificationFailed, "%s, expect %d row(s)", output, *v.ExpectedRowCount)
+		}
+		return output, nil
+	}
+	if len(rows) != 1 {
+		return "", common.Errorf(common.TaskVerificationFailed, "%s, expect a single row", output)
+	}
+	row, ok := rows[0].([]interface{})
+	if !ok || len(row) != 1 {
+		return "", common.Errorf(common.TaskVerificationFailed, "%s, expect a single value", output)
+	}
+	if !isTrueValue(row[0]) {
+		return "", common.Errorf(common.TaskVerificationFailed, "%s, expect true", output)
+	}
+	return output, nil
+}
+
+// isTrueValue returns whether the value read by the database driver is true.
+// MySQL has no boolean type, so the boolean expressions are returned as 1 or "1".
+func isTrueValue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case int64:
+		return v == 1
+	case float64:
+		// The integers are decoded as float64 from the agent query result.
+		return v == 1
+	case string:
+		switch strings.ToLower(v) {
+		case "1", "t", "true":
+			return true
+		}
+	}
+	return false
+}
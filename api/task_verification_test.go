@@ -0,0 +1,92 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestTaskVerificationCheck(t *testing.T) {
+	rowSet := func(rows ...[]interface{}) []interface{} {
+		data := []interface{}{}
+		for _, row := range rows {
+			data = append(data, row)
+		}
+		return []interface{}{[]string{"c"}, []string{"BOOL"}, data}
+	}
+	two := 2
+
+	tests := []struct {
+		name         string
+		verification *TaskVerification
+		rowSet       []interface{}
+		want         string
+		failed       bool
+	}{
+		{
+			name:         "PostgresTrue",
+			verification: &TaskVerification{Query: "SELECT count(*) = 0 FROM t WHERE c IS NULL"},
+			rowSet:       rowSet([]interface{}{true}),
+			want:         "Verification query returned 1 row(s): [[true]]",
+		},
+		{
+			name:         "MySQLTrue",
+			verification: &TaskVerification{Query: "SELECT count(*) = 0 FROM t WHERE c IS NULL"},
+			rowSet:       rowSet([]interface{}{"1"}),
+			want:         `Verification query returned 1 row(s): [["1"]]`,
+		},
+		{
+			name:         "AgentTrue",
+			verification: &TaskVerification{Query: "SELECT 1"},
+			rowSet:       rowSet([]interface{}{float64(1)}),
+			want:         "Verification query returned 1 row(s): [[1]]",
+		},
+		{
+			name:         "False",
+			verification: &TaskVerification{Query: "SELECT false"},
+			rowSet:       rowSet([]interface{}{false}),
+			failed:       true,
+		},
+		{
+			name:         "MultipleValues",
+			verification: &TaskVerification{Query: "SELECT true, true"},
+			rowSet:       rowSet([]interface{}{true, true}),
+			failed:       true,
+		},
+		{
+			name:         "RowCount",
+			verification: &TaskVerification{Query: "SELECT id FROM t", ExpectedRowCount: &two},
+			rowSet:       rowSet([]interface{}{int64(1)}, []interface{}{int64(2)}),
+			want:         "Verification query returned 2 row(s): [[1],[2]]",
+		},
+		{
+			name:         "RowCountMismatch",
+			verification: &TaskVerification{Query: "SELECT id FROM t", ExpectedRowCount: &two},
+			rowSet:       rowSet([]interface{}{int64(1)}),
+			failed:       true,
+		},
+	}
+	for _, test := range tests {
+		output, err := test.verification.Check(test.rowSet)
+		if test.failed {
+			require.Equal(t, common.TaskVerificationFailed, common.ErrorCode(err), test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.want, output, test.name)
+	}
+
+	_, err := (&TaskVerification{Query: "SELECT 1"}).Check([]interface{}{})
+	require.Error(t, err)
+}
+
+func TestTaskVerificationValidate(t *testing.T) {
+	negative := -1
+	require.NoError(t, (&TaskVerification{Query: " select 1"}).Validate())
+	require.NoError(t, (&TaskVerification{Query: "WITH t AS (SELECT 1) SELECT * FROM t"}).Validate())
+	require.Error(t, (&TaskVerification{Query: "DELETE FROM t"}).Validate())
+	require.Error(t, (&TaskVerification{Query: ""}).Validate())
+	require.Error(t, (&TaskVerification{Query: "SELECT 1", ExpectedRowCount: &negative}).Validate())
+}
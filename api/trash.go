@@ -0,0 +1,85 @@
+package api
+
+import (
+	"fmt"
+)
+
+// DefaultTrashRetentionDays is the default number of days the deleted resources are kept in the trash.
+const DefaultTrashRetentionDays = 30
+
+// TrashResourceType is the type of the resource in the trash.
+type TrashResourceType string
+
+const (
+	// TrashProject is the trash resource type for projects.
+	TrashProject TrashResourceType = "PROJECT"
+	// TrashInstance is the trash resource type for instances.
+	TrashInstance TrashResourceType = "INSTANCE"
+	// TrashDatabase is the trash resource type for databases.
+	TrashDatabase TrashResourceType = "DATABASE"
+)
+
+// TrashSetting is the value of the workspace trash setting.
+type TrashSetting struct {
+	// RetentionDays is the number of days the deleted resources can be restored before they are purged.
+	RetentionDays int `json:"retentionDays"`
+}
+
+// Validate validates the trash setting.
+func (s *TrashSetting) Validate() error {
+	if s.RetentionDays <= 0 {
+		return fmt.Errorf("retention days must be positive, got %d", s.RetentionDays)
+	}
+	return nil
+}
+
+// PurgeTs returns the time the resource deleted at deletedTs is purged.
+func (s *TrashSetting) PurgeTs(deletedTs int64) int64 {
+	return deletedTs + int64(s.RetentionDays)*24*3600
+}
+
+// Trash is the API message for a deleted resource in the trash.
+// The resource is archived while it's in the trash, restoring it makes it normal again.
+// Once the retention window passes, the resource is purged: its configuration such as the members and the data sources
+// is deleted, and the resource itself is deleted unless the issue and task history still references it,
+// in which case it stays archived and can no longer be restored.
+type Trash struct {
+	ID int `jsonapi:"primary,trash"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	// CreatedTs is the time the resource was deleted.
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+
+	// Domain specific fields
+	ResourceType TrashResourceType `jsonapi:"attr,resourceType"`
+	ResourceID   int               `jsonapi:"attr,resourceId"`
+	// Name is the name of the resource when it was deleted.
+	Name string `jsonapi:"attr,name"`
+	// PurgeTs is the time the resource is going to be purged, it's derived from the workspace trash setting.
+	PurgeTs int64 `jsonapi:"attr,purgeTs"`
+}
+
+// TrashCreate is the API message for moving a resource to the trash.
+type TrashCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	ResourceType TrashResourceType
+	ResourceID   int
+	Name         string
+}
+
+// TrashFind is the API message for finding the resources in the trash.
+type TrashFind struct {
+	ID *int
+
+	// Domain specific fields
+	ResourceType *TrashResourceType
+	ResourceID   *int
+	// CreatedBeforeTs finds the resources deleted before the time, e.g. the ones to be purged.
+	CreatedBeforeTs *int64
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashSetting(t *testing.T) {
+	setting := &TrashSetting{RetentionDays: 30}
+	require.NoError(t, setting.Validate())
+	require.Error(t, (&TrashSetting{}).Validate())
+	require.Error(t, (&TrashSetting{RetentionDays: -1}).Validate())
+
+	deletedTs := int64(1650000000)
+	require.Equal(t, deletedTs+30*24*3600, setting.PurgeTs(deletedTs))
+}
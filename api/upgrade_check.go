@@ -0,0 +1,266 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	// maxUpgradeCheckReportedObjectCount is the number of the objects reported per check item.
+	maxUpgradeCheckReportedObjectCount = 10
+)
+
+// UpgradeCheckSeverity is the severity of an upgrade check item.
+type UpgradeCheckSeverity string
+
+const (
+	// UpgradeCheckError means the upgrade fails or breaks the data, it must be fixed before the upgrade.
+	UpgradeCheckError UpgradeCheckSeverity = "ERROR"
+	// UpgradeCheckWarning means the item needs to be verified before the upgrade.
+	UpgradeCheckWarning UpgradeCheckSeverity = "WARNING"
+)
+
+// UpgradeCheckItem is the API message for a problem found by the upgrade check.
+type UpgradeCheckItem struct {
+	Severity UpgradeCheckSeverity `json:"severity"`
+	Title    string               `json:"title"`
+	// Database is empty for the instance level items.
+	Database   string   `json:"database"`
+	ObjectList []string `json:"objectList"`
+}
+
+// PostgresUpgradeCheck is a compatibility check of the Postgres major version upgrade, they're the checks of pg_upgrade.
+type PostgresUpgradeCheck struct {
+	Title string
+	// Query returns the name of the objects failing the check in a database.
+	Query string
+}
+
+// postgresUpgradeCheckDefinition is the definition of a check applying to the upgrade across the version.
+type postgresUpgradeCheckDefinition struct {
+	title string
+	// beforeVersionNum is the version number where the check starts to apply, the upgrade from an older version to it or a later version is checked.
+	// 0 means the check applies to all the upgrades.
+	beforeVersionNum int
+	query            string
+}
+
+var postgresUpgradeCheckDefinitionList = []postgresUpgradeCheckDefinition{
+	{
+		title: "Columns using the reg* data types, which reference the system OIDs not preserved by pg_upgrade",
+		query: getPostgresDataTypeUsageQuery([]string{"regcollation", "regconfig", "regdictionary", "regnamespace", "regoper", "regoperator", "regproc", "regprocedure"}),
+	},
+	{
+		title:            `Columns using the data type "unknown", which is no longer allowed in tables`,
+		beforeVersionNum: 100000,
+		query:            getPostgresDataTypeUsageQuery([]string{"unknown"}),
+	},
+	{
+		title:            "Columns using the removed data types abstime, reltime and tinterval",
+		beforeVersionNum: 120000,
+		query:            getPostgresDataTypeUsageQuery([]string{"abstime", "reltime", "tinterval"}),
+	},
+	{
+		title:            `Columns using the data type "sql_identifier", whose on-disk format is changed`,
+		beforeVersionNum: 120000,
+		query:            getPostgresDataTypeUsageQuery([]string{"sql_identifier"}),
+	},
+	{
+		title:            "Tables declared WITH OIDS, which is no longer supported",
+		beforeVersionNum: 120000,
+		query: `
+			SELECT n.nspname || '.' || c.relname
+			FROM pg_catalog.pg_class c
+			JOIN pg_catalog.pg_namespace n ON c.relnamespace = n.oid
+			WHERE c.relhasoids AND n.nspname NOT IN ('pg_catalog')
+			ORDER BY 1`,
+	},
+	{
+		title:            "User-defined postfix operators, which are no longer supported",
+		beforeVersionNum: 140000,
+		query: `
+			SELECT o.oid::pg_catalog.regoperator::text
+			FROM pg_catalog.pg_operator o
+			JOIN pg_catalog.pg_namespace n ON o.oprnamespace = n.oid
+			WHERE o.oprright = 0 AND o.oid >= 16384 AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+			ORDER BY 1`,
+	},
+	{
+		title:            `Columns using the data type "aclitem", whose format is changed`,
+		beforeVersionNum: 160000,
+		query:            getPostgresDataTypeUsageQuery([]string{"aclitem"}),
+	},
+}
+
+// postgresRemovedExtensionMap is the contrib extensions and the version number removing them.
+var postgresRemovedExtensionMap = map[string]int{
+	"tsearch2":          100000,
+	"chkpass":           110000,
+	"timetravel":        120000,
+	"plpythonu":         150000,
+	"plpython2u":        150000,
+	"hstore_plpythonu":  150000,
+	"hstore_plpython2u": 150000,
+	"jsonb_plpythonu":   150000,
+	"jsonb_plpython2u":  150000,
+	"ltree_plpythonu":   150000,
+	"ltree_plpython2u":  150000,
+}
+
+// getPostgresDataTypeUsageQuery returns the query of the columns using the pg_catalog data types,
+// including the use in the domains, the arrays and the composite types like pg_upgrade.
+func getPostgresDataTypeUsageQuery(typeNameList []string) string {
+	var quotedList []string
+	for _, typeName := range typeNameList {
+		quotedList = append(quotedList, fmt.Sprintf("'%s'", typeName))
+	}
+	return `
+		WITH RECURSIVE oids AS (
+			SELECT t.oid
+			FROM pg_catalog.pg_type t
+			JOIN pg_catalog.pg_namespace n ON t.typnamespace = n.oid
+			WHERE n.nspname = 'pg_catalog' AND t.typname IN (` + strings.Join(quotedList, ", ") + `)
+			UNION ALL
+			SELECT * FROM (
+				WITH x AS (SELECT oid FROM oids)
+				SELECT t.oid FROM pg_catalog.pg_type t, x WHERE t.typbasetype = x.oid AND t.typtype = 'd'
+				UNION ALL
+				SELECT t.oid FROM pg_catalog.pg_type t, x WHERE t.typelem = x.oid AND t.typtype = 'b'
+				UNION ALL
+				SELECT t.oid FROM pg_catalog.pg_type t, pg_catalog.pg_class c, pg_catalog.pg_attribute a, x
+				WHERE t.typtype = 'c' AND t.oid = c.reltype AND c.oid = a.attrelid AND NOT a.attisdropped AND a.atttypid = x.oid
+			) foo
+		)
+		SELECT n.nspname || '.' || c.relname || '.' || a.attname
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON c.relnamespace = n.oid
+		JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid
+		WHERE NOT a.attisdropped
+			AND a.atttypid IN (SELECT oid FROM oids)
+			AND c.relkind IN ('r', 'm', 'i')
+			AND n.nspname !~ '^pg_temp_'
+			AND n.nspname !~ '^pg_toast_temp_'
+			AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY 1`
+}
+
+// GetPostgresVersionNum returns the version number of the Postgres major version like server_version_num, e.g. 90600 for "9.6.24" and 140000 for "14.2".
+func GetPostgresVersionNum(version string) (int, error) {
+	numberList := parseEngineVersion(version)
+	if len(numberList) == 0 {
+		return 0, &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid Postgres version %q", version)}
+	}
+	if numberList[0] >= 10 {
+		return numberList[0] * 10000, nil
+	}
+	if len(numberList) < 2 {
+		return 0, &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid Postgres version %q, the major version before 10 has two parts such as 9.6", version)}
+	}
+	return numberList[0]*10000 + numberList[1]*100, nil
+}
+
+// GetPostgresUpgradeCheckList returns the checks of upgrading from the source version number to the target one.
+func GetPostgresUpgradeCheckList(sourceVersionNum, targetVersionNum int) []*PostgresUpgradeCheck {
+	var checkList []*PostgresUpgradeCheck
+	for _, definition := range postgresUpgradeCheckDefinitionList {
+		if definition.beforeVersionNum != 0 && (sourceVersionNum >= definition.beforeVersionNum || targetVersionNum < definition.beforeVersionNum) {
+			continue
+		}
+		checkList = append(checkList, &PostgresUpgradeCheck{
+			Title: definition.title,
+			Query: definition.query,
+		})
+	}
+	return checkList
+}
+
+// GetPostgresExtensionUpgradeCheckItemList returns the check items of the extensions installed in the database.
+// availableSet is the extensions available in the target instance, it's nil if the target instance isn't given.
+func GetPostgresExtensionUpgradeCheckItemList(databaseName string, extensionList []string, targetVersionNum int, availableSet map[string]bool) []*UpgradeCheckItem {
+	var removedList, unavailableList, unverifiedList []string
+	for _, extension := range extensionList {
+		// plpgsql is always installed.
+		if extension == "plpgsql" {
+			continue
+		}
+		if removedVersionNum, ok := postgresRemovedExtensionMap[extension]; ok && targetVersionNum >= removedVersionNum {
+			removedList = append(removedList, extension)
+			continue
+		}
+		if availableSet == nil {
+			unverifiedList = append(unverifiedList, extension)
+			continue
+		}
+		if !availableSet[extension] {
+			unavailableList = append(unavailableList, extension)
+		}
+	}
+
+	var itemList []*UpgradeCheckItem
+	if len(removedList) > 0 {
+		itemList = append(itemList, &UpgradeCheckItem{
+			Severity:   UpgradeCheckError,
+			Title:      "Extensions removed from the target version",
+			Database:   databaseName,
+			ObjectList: removedList,
+		})
+	}
+	if len(unavailableList) > 0 {
+		itemList = append(itemList, &UpgradeCheckItem{
+			Severity:   UpgradeCheckError,
+			Title:      "Extensions not available in the target instance",
+			Database:   databaseName,
+			ObjectList: unavailableList,
+		})
+	}
+	if len(unverifiedList) > 0 {
+		itemList = append(itemList, &UpgradeCheckItem{
+			Severity:   UpgradeCheckWarning,
+			Title:      "Extensions to be installed for the target version",
+			Database:   databaseName,
+			ObjectList: unverifiedList,
+		})
+	}
+	return itemList
+}
+
+// FormatUpgradeCheckReport returns the readiness report of the check items, and whether the instance is ready for the upgrade.
+func FormatUpgradeCheckReport(sourceVersion, targetVersion string, databaseCount int, itemList []*UpgradeCheckItem) (string, bool) {
+	sort.SliceStable(itemList, func(i, j int) bool {
+		return itemList[i].Severity == UpgradeCheckError && itemList[j].Severity != UpgradeCheckError
+	})
+	errorCount, warningCount := 0, 0
+	var lines []string
+	for _, item := range itemList {
+		if item.Severity == UpgradeCheckError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+		objectList := item.ObjectList
+		more := ""
+		if len(objectList) > maxUpgradeCheckReportedObjectCount {
+			more = fmt.Sprintf(" and %d more", len(objectList)-maxUpgradeCheckReportedObjectCount)
+			objectList = objectList[:maxUpgradeCheckReportedObjectCount]
+		}
+		location := ""
+		if item.Database != "" {
+			location = fmt.Sprintf(" in database %q", item.Database)
+		}
+		lines = append(lines, fmt.Sprintf("- [%s] %s%s: %s%s", item.Severity, item.Title, location, strings.Join(objectList, ", "), more))
+	}
+
+	ready := errorCount == 0
+	readiness := "ready"
+	if !ready {
+		readiness = "not ready"
+	}
+	summary := fmt.Sprintf("Upgrading PostgreSQL %s to %s is %s, checked %d database(s) with %d error(s) and %d warning(s).", sourceVersion, targetVersion, readiness, databaseCount, errorCount, warningCount)
+	if len(lines) == 0 {
+		return summary, ready
+	}
+	return summary + "\n" + strings.Join(lines, "\n"), ready
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPostgresVersionNum(t *testing.T) {
+	tests := []struct {
+		version string
+		want    int
+	}{
+		{"9.6.24", 90600},
+		{"9.6", 90600},
+		{"10.21", 100000},
+		{"14", 140000},
+		{"14.2 (Debian 14.2-1.pgdg110+1)", 140000},
+	}
+	for _, test := range tests {
+		got, err := GetPostgresVersionNum(test.version)
+		require.NoError(t, err)
+		require.Equal(t, test.want, got, test.version)
+	}
+	for _, version := range []string{"", "9", "v14"} {
+		_, err := GetPostgresVersionNum(version)
+		require.Error(t, err, version)
+	}
+}
+
+func TestGetPostgresUpgradeCheckList(t *testing.T) {
+	getTitleList := func(checkList []*PostgresUpgradeCheck) []string {
+		var titleList []string
+		for _, check := range checkList {
+			titleList = append(titleList, check.Title)
+		}
+		return titleList
+	}
+
+	// The reg* check applies to all the upgrades.
+	titleList := getTitleList(GetPostgresUpgradeCheckList(130000, 140000))
+	require.Len(t, titleList, 2)
+	require.Contains(t, titleList[0], "reg*")
+	require.Contains(t, titleList[1], "postfix operators")
+
+	titleList = getTitleList(GetPostgresUpgradeCheckList(90600, 120000))
+	require.Len(t, titleList, 5)
+	require.Contains(t, strings.Join(titleList, "\n"), "WITH OIDS")
+
+	require.Len(t, GetPostgresUpgradeCheckList(140000, 150000), 1)
+	require.Len(t, GetPostgresUpgradeCheckList(150000, 160000), 2)
+}
+
+func TestGetPostgresExtensionUpgradeCheckItemList(t *testing.T) {
+	extensionList := []string{"plpgsql", "chkpass", "postgis", "pg_stat_statements"}
+
+	itemList := GetPostgresExtensionUpgradeCheckItemList("db", extensionList, 120000, nil)
+	require.Len(t, itemList, 2)
+	require.Equal(t, UpgradeCheckError, itemList[0].Severity)
+	require.Equal(t, []string{"chkpass"}, itemList[0].ObjectList)
+	require.Equal(t, UpgradeCheckWarning, itemList[1].Severity)
+	require.Equal(t, []string{"postgis", "pg_stat_statements"}, itemList[1].ObjectList)
+
+	itemList = GetPostgresExtensionUpgradeCheckItemList("db", extensionList, 100000, map[string]bool{"chkpass": true, "pg_stat_statements": true})
+	require.Len(t, itemList, 1)
+	require.Equal(t, UpgradeCheckError, itemList[0].Severity)
+	require.Equal(t, []string{"postgis"}, itemList[0].ObjectList)
+
+	require.Empty(t, GetPostgresExtensionUpgradeCheckItemList("db", []string{"plpgsql"}, 140000, nil))
+}
+
+func TestFormatUpgradeCheckReport(t *testing.T) {
+	report, ready := FormatUpgradeCheckReport("13.7", "14", 3, nil)
+	require.True(t, ready)
+	require.Equal(t, "Upgrading PostgreSQL 13.7 to 14 is ready, checked 3 database(s) with 0 error(s) and 0 warning(s).", report)
+
+	var objectList []string
+	for i := 0; i < 12; i++ {
+		objectList = append(objectList, "o")
+	}
+	report, ready = FormatUpgradeCheckReport("11.16", "14", 2, []*UpgradeCheckItem{
+		{Severity: UpgradeCheckWarning, Title: "Extensions to be installed for the target version", Database: "app", ObjectList: []string{"postgis"}},
+		{Severity: UpgradeCheckError, Title: "Columns using reg*", Database: "app", ObjectList: objectList},
+	})
+	require.False(t, ready)
+	require.Equal(t, strings.Join([]string{
+		"Upgrading PostgreSQL 11.16 to 14 is not ready, checked 2 database(s) with 1 error(s) and 1 warning(s).",
+		`- [ERROR] Columns using reg* in database "app": o, o, o, o, o, o, o, o, o, o and 2 more`,
+		`- [WARNING] Extensions to be installed for the target version in database "app": postgis`,
+	}, "\n"), report)
+}
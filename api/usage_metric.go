@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// UsageMetricDateFormat is the format of the usage metric date.
+const UsageMetricDateFormat = "2006-01-02"
+
+// UsageMetricReportLevel is the granularity of the usage metrics reported out of the workspace.
+type UsageMetricReportLevel string
+
+const (
+	// UsageMetricReportNone reports nothing, the usage metrics are only kept in the workspace.
+	UsageMetricReportNone UsageMetricReportLevel = "NONE"
+	// UsageMetricReportAggregate reports the daily total of each metric without the labels.
+	UsageMetricReportAggregate UsageMetricReportLevel = "AGGREGATE"
+	// UsageMetricReportFull reports the daily metrics with the labels.
+	UsageMetricReportFull UsageMetricReportLevel = "FULL"
+)
+
+// Validate validates the usage metric report level.
+func (l UsageMetricReportLevel) Validate() error {
+	switch l {
+	case UsageMetricReportNone, UsageMetricReportAggregate, UsageMetricReportFull:
+		return nil
+	}
+	return fmt.Errorf("invalid usage metric report level %q", l)
+}
+
+// GetUsageMetricDate returns the usage metric date of the time.
+func GetUsageMetricDate(t time.Time) string {
+	return t.UTC().Format(UsageMetricDateFormat)
+}
+
+// UsageMetric is the API message for the usage metric of a day.
+type UsageMetric struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatedTs int64 `json:"createdTs"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Domain specific fields
+	Date     string            `json:"date"`
+	Name     string            `json:"name"`
+	Labels   map[string]string `json:"labels"`
+	Value    int64             `json:"value"`
+	Reported bool              `json:"reported"`
+}
+
+// UsageMetricUpsert is the API message for upserting the usage metric of a day.
+type UsageMetricUpsert struct {
+	Date   string
+	Name   string
+	Labels map[string]string
+	Value  int64
+	// Increment adds the value to the existing value for the events, e.g. the issues created,
+	// otherwise the value replaces the existing value for the gauges, e.g. the instance count.
+	Increment bool
+}
+
+// UsageMetricFind is the API message for finding usage metrics.
+type UsageMetricFind struct {
+	// FromDate and ToDate are inclusive.
+	FromDate *string
+	ToDate   *string
+	Name     *string
+	Reported *bool
+}
+
+func (find *UsageMetricFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// UsageMetricPatch is the API message for patching a usage metric.
+type UsageMetricPatch struct {
+	ID int
+
+	// Domain specific fields
+	Reported *bool
+}
+
+// AggregateUsageMetricList returns the usage metrics to report at the level.
+// The metrics are summed up by the date and the name without the labels at the AGGREGATE level.
+func AggregateUsageMetricList(list []*UsageMetric, level UsageMetricReportLevel) []*UsageMetric {
+	switch level {
+	case UsageMetricReportFull:
+		return list
+	case UsageMetricReportAggregate:
+	default:
+		return nil
+	}
+
+	type key struct {
+		date string
+		name string
+	}
+	sumMap := make(map[key]*UsageMetric)
+	for _, m := range list {
+		k := key{date: m.Date, name: m.Name}
+		if sum, ok := sumMap[k]; ok {
+			sum.Value += m.Value
+			continue
+		}
+		sumMap[k] = &UsageMetric{
+			Date:   m.Date,
+			Name:   m.Name,
+			Labels: map[string]string{},
+			Value:  m.Value,
+		}
+	}
+	var res []*UsageMetric
+	for _, sum := range sumMap {
+		res = append(res, sum)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Date != res[j].Date {
+			return res[i].Date < res[j].Date
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateUsageMetricList(t *testing.T) {
+	list := []*UsageMetric{
+		{Date: "2022-05-12", Name: "bb.task.run", Labels: map[string]string{"type": "bb.task.database.schema.update", "status": "DONE"}, Value: 3},
+		{Date: "2022-05-12", Name: "bb.task.run", Labels: map[string]string{"type": "bb.task.database.schema.update", "status": "FAILED"}, Value: 1},
+		{Date: "2022-05-11", Name: "bb.issue.created", Labels: map[string]string{"type": "bb.issue.database.schema.update"}, Value: 2},
+		{Date: "2022-05-12", Name: "bb.issue.created", Labels: map[string]string{"type": "bb.issue.database.create"}, Value: 5},
+	}
+
+	require.Equal(t, list, AggregateUsageMetricList(list, UsageMetricReportFull))
+	require.Nil(t, AggregateUsageMetricList(list, UsageMetricReportNone))
+	require.Equal(t, []*UsageMetric{
+		{Date: "2022-05-11", Name: "bb.issue.created", Labels: map[string]string{}, Value: 2},
+		{Date: "2022-05-12", Name: "bb.issue.created", Labels: map[string]string{}, Value: 5},
+		{Date: "2022-05-12", Name: "bb.task.run", Labels: map[string]string{}, Value: 4},
+	}, AggregateUsageMetricList(list, UsageMetricReportAggregate))
+
+	require.NoError(t, UsageMetricReportAggregate.Validate())
+	require.Error(t, UsageMetricReportLevel("PARTIAL").Validate())
+}
@@ -24,8 +24,12 @@ type VCS struct {
 	InstanceURL   string   `jsonapi:"attr,instanceUrl"`
 	APIURL        string   `jsonapi:"attr,apiUrl"`
 	ApplicationID string   `jsonapi:"attr,applicationId"`
+	// AuthMode is OAUTH for the OAuth application, or GITHUB_APP for the GitHub App, whose ApplicationID is the app ID and Secret is the private key.
+	AuthMode vcs.AuthMode `jsonapi:"attr,authMode"`
 	// For safety concerns, we will not return the secret, and all relevant logic is dealt in the backend.
 	Secret string
+	// WebhookSecret verifies the webhook events delivered to the GitHub App, it's not returned either.
+	WebhookSecret string
 }
 
 // VCSCreate is the API message for creating a VCS.
@@ -40,8 +44,10 @@ type VCSCreate struct {
 	InstanceURL string   `jsonapi:"attr,instanceUrl"`
 	// APIURL derives from InstanceURL
 	APIURL        string
-	ApplicationID string `jsonapi:"attr,applicationId"`
-	Secret        string `jsonapi:"attr,secret"`
+	ApplicationID string       `jsonapi:"attr,applicationId"`
+	Secret        string       `jsonapi:"attr,secret"`
+	AuthMode      vcs.AuthMode `jsonapi:"attr,authMode"`
+	WebhookSecret string       `jsonapi:"attr,webhookSecret"`
 }
 
 // VCSFind is the API message for finding VCSs.
@@ -69,6 +75,7 @@ type VCSPatch struct {
 	Name          *string `jsonapi:"attr,name"`
 	ApplicationID *string `jsonapi:"attr,applicationId"`
 	Secret        *string `jsonapi:"attr,secret"`
+	WebhookSecret *string `jsonapi:"attr,webhookSecret"`
 }
 
 // VCSDelete is the API message for deleting a VCS.
@@ -88,6 +95,16 @@ type ExternalRepository struct {
 	WebURL   string `jsonapi:"attr,webUrl"`
 }
 
+// ExternalGroup is the API message for external group, e.g. a GitLab group or subgroup.
+type ExternalGroup struct {
+	ID       int64  `jsonapi:"primary,externalGroup"`
+	Name     string `jsonapi:"attr,name"`
+	FullPath string `jsonapi:"attr,fullPath"`
+	// ParentID is the ID of the parent group of a subgroup, it's 0 for the top-level groups.
+	ParentID int64  `jsonapi:"attr,parentId"`
+	WebURL   string `jsonapi:"attr,webUrl"`
+}
+
 // VCSExchangeToken is the API message of exchanging token for a VCS.
 type VCSExchangeToken struct {
 	Code         string   `jsonapi:"attr,code"`
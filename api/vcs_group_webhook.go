@@ -0,0 +1,97 @@
+package api
+
+import (
+	"strings"
+)
+
+// VCSGroupWebhook is the API message for a webhook registered on a VCS group, i.e. a GitLab group.
+// It delivers the push events of all the repositories in the group and its nested subgroups,
+// so the repositories linked under the group don't need their own webhooks.
+type VCSGroupWebhook struct {
+	ID int `jsonapi:"primary,vcsGroupWebhook"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	VCSID int `jsonapi:"attr,vcsId"`
+
+	// Domain specific fields
+	// ExternalGroupID is the group ID from the VCS, e.g. 123.
+	ExternalGroupID string `jsonapi:"attr,externalGroupId"`
+	// GroupFullPath is the full path of the group, e.g. group1/subgroup1.
+	GroupFullPath      string `jsonapi:"attr,groupFullPath"`
+	ExternalWebhookID  string
+	WebhookURLHost     string
+	WebhookEndpointID  string
+	WebhookSecretToken string
+	// These belong to the user registering the webhook, and are only used on the server side to delete the webhook.
+	AccessToken  string
+	ExpiresTs    int64
+	RefreshToken string
+}
+
+// Covers returns whether the repository of the full path is in the group or its subgroups.
+func (webhook *VCSGroupWebhook) Covers(repositoryFullPath string) bool {
+	return strings.HasPrefix(repositoryFullPath, webhook.GroupFullPath+"/")
+}
+
+// VCSGroupWebhookCreate is the API message for creating a VCS group webhook.
+type VCSGroupWebhookCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	VCSID int
+
+	// Domain specific fields
+	ExternalGroupID string `jsonapi:"attr,externalGroupId"`
+	GroupFullPath   string `jsonapi:"attr,groupFullPath"`
+	// Token belonged by the user registering the webhook, who needs to be the owner of the group.
+	AccessToken        string `jsonapi:"attr,accessToken"`
+	ExpiresTs          int64  `jsonapi:"attr,expiresTs"`
+	RefreshToken       string `jsonapi:"attr,refreshToken"`
+	ExternalWebhookID  string
+	WebhookURLHost     string
+	WebhookEndpointID  string
+	WebhookSecretToken string
+}
+
+// VCSGroupWebhookFind is the API message for finding VCS group webhooks.
+type VCSGroupWebhookFind struct {
+	ID *int
+
+	// Related fields
+	VCSID *int
+
+	// Domain specific fields
+	WebhookEndpointID *string
+}
+
+// VCSGroupWebhookPatch is the API message for patching the tokens of a VCS group webhook after refreshing them.
+type VCSGroupWebhookPatch struct {
+	ID int
+
+	// Standard fields
+	UpdaterID int
+
+	// Domain specific fields
+	AccessToken  *string
+	ExpiresTs    *int64
+	RefreshToken *string
+}
+
+// VCSGroupWebhookDelete is the API message for deleting a VCS group webhook.
+type VCSGroupWebhookDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVCSGroupWebhookCovers(t *testing.T) {
+	webhook := &VCSGroupWebhook{GroupFullPath: "acme/backend"}
+	require.True(t, webhook.Covers("acme/backend/api"))
+	require.True(t, webhook.Covers("acme/backend/payment/ledger"))
+	require.False(t, webhook.Covers("acme/backend"))
+	require.False(t, webhook.Covers("acme/backend-legacy/api"))
+	require.False(t, webhook.Covers("acme/frontend/web"))
+}
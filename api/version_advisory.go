@@ -0,0 +1,183 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// VersionAdvisoryStatus is the status of an engine version.
+type VersionAdvisoryStatus string
+
+const (
+	// VersionAdvisorySupported means the version is supported and has no known vulnerability.
+	VersionAdvisorySupported VersionAdvisoryStatus = "SUPPORTED"
+	// VersionAdvisoryVulnerable means the version is older than the minimum patch version of its major version.
+	VersionAdvisoryVulnerable VersionAdvisoryStatus = "VULNERABLE"
+	// VersionAdvisoryEOL means the major version is end of life.
+	VersionAdvisoryEOL VersionAdvisoryStatus = "EOL"
+	// VersionAdvisoryUnknown means the engine or the version isn't in the knowledge table.
+	VersionAdvisoryUnknown VersionAdvisoryStatus = "UNKNOWN"
+)
+
+// engineVersionLifecycle is the lifecycle of a major version of an engine.
+type engineVersionLifecycle struct {
+	engine db.Type
+	// major is the major version, e.g. 14 for Postgres and 8.0 for MySQL.
+	major string
+	// eol is the end of life date in YYYY-MM-DD.
+	eol string
+	// minimumPatch is the oldest version without the known vulnerabilities.
+	minimumPatch string
+}
+
+// engineVersionLifecycleList is the knowledge table of the major versions, ordered by the engine and the ascending version.
+// The versions older than the first major version of an engine are EOL.
+var engineVersionLifecycleList = []engineVersionLifecycle{
+	{engine: db.Postgres, major: "9.6", eol: "2021-11-11", minimumPatch: "9.6.24"},
+	{engine: db.Postgres, major: "10", eol: "2022-11-10", minimumPatch: "10.21"},
+	{engine: db.Postgres, major: "11", eol: "2023-11-09", minimumPatch: "11.16"},
+	{engine: db.Postgres, major: "12", eol: "2024-11-14", minimumPatch: "12.11"},
+	{engine: db.Postgres, major: "13", eol: "2025-11-13", minimumPatch: "13.7"},
+	{engine: db.Postgres, major: "14", eol: "2026-11-12", minimumPatch: "14.4"},
+	{engine: db.MySQL, major: "5.6", eol: "2021-02-01", minimumPatch: "5.6.51"},
+	{engine: db.MySQL, major: "5.7", eol: "2023-10-21", minimumPatch: "5.7.38"},
+	{engine: db.MySQL, major: "8.0", eol: "2026-04-30", minimumPatch: "8.0.29"},
+	{engine: db.MariaDB, major: "10.2", eol: "2022-05-23", minimumPatch: "10.2.44"},
+	{engine: db.MariaDB, major: "10.3", eol: "2023-05-25", minimumPatch: "10.3.35"},
+	{engine: db.MariaDB, major: "10.4", eol: "2024-06-18", minimumPatch: "10.4.25"},
+	{engine: db.MariaDB, major: "10.5", eol: "2025-06-24", minimumPatch: "10.5.16"},
+	{engine: db.MariaDB, major: "10.6", eol: "2026-07-06", minimumPatch: "10.6.8"},
+}
+
+var engineVersionRegexp = regexp.MustCompile(`^\d+(\.\d+)*`)
+
+// VersionAdvisory is the API message for the version advisory of an instance.
+type VersionAdvisory struct {
+	// InstanceID is the primary key, there is one advisory per instance.
+	InstanceID int `jsonapi:"primary,versionAdvisory"`
+
+	// Related fields
+	InstanceName  string `jsonapi:"attr,instanceName"`
+	EnvironmentID int    `jsonapi:"attr,environmentId"`
+
+	// Domain specific fields
+	Engine  db.Type               `jsonapi:"attr,engine"`
+	Version string                `jsonapi:"attr,version"`
+	Status  VersionAdvisoryStatus `jsonapi:"attr,status"`
+	// EOLTs is the end of life time of the major version, 0 if it's unknown.
+	EOLTs int64 `jsonapi:"attr,eolTs"`
+	// RecommendedVersion is the version to upgrade to, empty if no upgrade is needed.
+	RecommendedVersion string `jsonapi:"attr,recommendedVersion"`
+	Detail             string `jsonapi:"attr,detail"`
+}
+
+// GetVersionAdvisory returns the advisory of the engine version at the time.
+func GetVersionAdvisory(engine db.Type, version string, now time.Time) *VersionAdvisory {
+	advisory := &VersionAdvisory{
+		Engine:  engine,
+		Version: version,
+		Status:  VersionAdvisoryUnknown,
+	}
+	var lifecycleList []engineVersionLifecycle
+	for _, lifecycle := range engineVersionLifecycleList {
+		if lifecycle.engine == engine {
+			lifecycleList = append(lifecycleList, lifecycle)
+		}
+	}
+	versionNumber := parseEngineVersion(version)
+	if len(lifecycleList) == 0 || len(versionNumber) == 0 {
+		advisory.Detail = fmt.Sprintf("No lifecycle information for %s %s", engine, version)
+		return advisory
+	}
+
+	// Recommend the minimum patch of the oldest major version still supported.
+	upgrade := "a newer major version"
+	for _, lifecycle := range lifecycleList {
+		if !lifecycle.isEOL(now) {
+			advisory.RecommendedVersion = lifecycle.minimumPatch
+			upgrade = fmt.Sprintf("%s or later", lifecycle.minimumPatch)
+			break
+		}
+	}
+
+	if compareEngineVersion(versionNumber, parseEngineVersion(lifecycleList[0].major)) < 0 {
+		advisory.Status = VersionAdvisoryEOL
+		advisory.Detail = fmt.Sprintf("%s %s is end of life, upgrade to %s", engine, version, upgrade)
+		return advisory
+	}
+	for _, lifecycle := range lifecycleList {
+		major := parseEngineVersion(lifecycle.major)
+		if len(versionNumber) < len(major) || compareEngineVersion(versionNumber[:len(major)], major) != 0 {
+			continue
+		}
+		advisory.EOLTs = lifecycle.eolTime().Unix()
+		if lifecycle.isEOL(now) {
+			advisory.Status = VersionAdvisoryEOL
+			advisory.Detail = fmt.Sprintf("%s %s is end of life since %s, upgrade to %s", engine, lifecycle.major, lifecycle.eol, upgrade)
+			return advisory
+		}
+		if compareEngineVersion(versionNumber, parseEngineVersion(lifecycle.minimumPatch)) < 0 {
+			advisory.Status = VersionAdvisoryVulnerable
+			advisory.RecommendedVersion = lifecycle.minimumPatch
+			advisory.Detail = fmt.Sprintf("%s %s has known vulnerabilities, upgrade to %s or later", engine, version, lifecycle.minimumPatch)
+			return advisory
+		}
+		advisory.Status = VersionAdvisorySupported
+		advisory.RecommendedVersion = ""
+		advisory.Detail = fmt.Sprintf("%s %s is supported until %s", engine, lifecycle.major, lifecycle.eol)
+		return advisory
+	}
+	advisory.RecommendedVersion = ""
+	advisory.Detail = fmt.Sprintf("No lifecycle information for %s %s", engine, version)
+	return advisory
+}
+
+func (l engineVersionLifecycle) eolTime() time.Time {
+	t, err := time.Parse("2006-01-02", l.eol)
+	if err != nil {
+		panic(fmt.Sprintf("invalid EOL date %q of %s %s", l.eol, l.engine, l.major))
+	}
+	return t
+}
+
+func (l engineVersionLifecycle) isEOL(now time.Time) bool {
+	return !now.Before(l.eolTime())
+}
+
+// parseEngineVersion parses the leading numbers of the version, e.g. [5 7 36] for "5.7.36-log".
+func parseEngineVersion(version string) []int {
+	var list []int
+	for _, s := range strings.Split(engineVersionRegexp.FindString(strings.TrimSpace(version)), ".") {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil
+		}
+		list = append(list, v)
+	}
+	return list
+}
+
+// compareEngineVersion compares the versions, the missing parts are considered 0.
+func compareEngineVersion(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
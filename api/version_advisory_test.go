@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetVersionAdvisory(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		engine      db.Type
+		version     string
+		status      VersionAdvisoryStatus
+		recommended string
+	}{
+		{db.Postgres, "14.4", VersionAdvisorySupported, ""},
+		{db.Postgres, "14.2", VersionAdvisoryVulnerable, "14.4"},
+		{db.Postgres, "10.21", VersionAdvisorySupported, ""},
+		{db.Postgres, "9.6.24", VersionAdvisoryEOL, "10.21"},
+		{db.Postgres, "9.5.25", VersionAdvisoryEOL, "10.21"},
+		{db.Postgres, "15.0", VersionAdvisoryUnknown, ""},
+		{db.MySQL, "8.0.28", VersionAdvisoryVulnerable, "8.0.29"},
+		{db.MySQL, "5.7.38-log", VersionAdvisorySupported, ""},
+		{db.MySQL, "5.6.51", VersionAdvisoryEOL, "5.7.38"},
+		{db.MySQL, "5.5.62", VersionAdvisoryEOL, "5.7.38"},
+		{db.MySQL, "", VersionAdvisoryUnknown, ""},
+		{db.MariaDB, "10.6.8-MariaDB-1:10.6.8+maria~focal", VersionAdvisorySupported, ""},
+		{db.MariaDB, "10.5.15-MariaDB-log", VersionAdvisoryVulnerable, "10.5.16"},
+		{db.MariaDB, "10.2.44-MariaDB", VersionAdvisoryEOL, "10.3.35"},
+		{db.TiDB, "5.7.25-TiDB-v6.0.0", VersionAdvisoryUnknown, ""},
+	}
+	for _, test := range tests {
+		advisory := GetVersionAdvisory(test.engine, test.version, now)
+		require.Equal(t, test.status, advisory.Status, "%s %s", test.engine, test.version)
+		require.Equal(t, test.recommended, advisory.RecommendedVersion, "%s %s", test.engine, test.version)
+	}
+
+	// No major version is supported after all of them are end of life.
+	advisory := GetVersionAdvisory(db.MySQL, "8.0.29", time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, VersionAdvisoryEOL, advisory.Status)
+	require.Equal(t, "", advisory.RecommendedVersion)
+}
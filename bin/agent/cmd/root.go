@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/agent"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+
+	// Register clickhouse driver.
+	_ "github.com/bytebase/bytebase/plugin/db/clickhouse"
+	// Register mysql driver.
+	_ "github.com/bytebase/bytebase/plugin/db/mysql"
+	// Register postgres driver.
+	_ "github.com/bytebase/bytebase/plugin/db/pg"
+	// Register snowflake driver.
+	_ "github.com/bytebase/bytebase/plugin/db/snowflake"
+	// Register mongodb driver.
+	_ "github.com/bytebase/bytebase/plugin/db/mongodb"
+	// Register mssql driver.
+	_ "github.com/bytebase/bytebase/plugin/db/mssql"
+	// Register sqlite driver.
+	_ "github.com/bytebase/bytebase/plugin/db/sqlite"
+)
+
+// agentTokenEnv is the environment variable of the agent token, preferred over the --token flag
+// so the token doesn't show up in the process list.
+const agentTokenEnv = "BB_AGENT_TOKEN"
+
+var (
+	flags struct {
+		serverURL   string
+		token       string
+		resourceDir string
+		debug       bool
+		// Proxy flags.
+		pgProxyAddr    string
+		mysqlProxyAddr string
+		proxyTLSCert   string
+		proxyTLSKey    string
+	}
+	rootCmd = &cobra.Command{
+		Use:   "agent",
+		Short: "Bytebase agent executes the Bytebase work against the databases in a private network",
+		Run: func(_ *cobra.Command, _ []string) {
+			start()
+		},
+	}
+)
+
+// Execute executes the root command.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&flags.serverURL, "server-url", "", "the external URL of the Bytebase server, must start with http:// or https://.")
+	rootCmd.PersistentFlags().StringVar(&flags.token, "token", "", fmt.Sprintf("the agent token generated when registering the agent. Can also be set via %s.", agentTokenEnv))
+	rootCmd.PersistentFlags().StringVar(&flags.resourceDir, "resource-dir", os.TempDir(), "the directory to extract the embedded binaries.")
+	rootCmd.PersistentFlags().BoolVar(&flags.debug, "debug", false, "whether to enable debug level logging")
+	rootCmd.PersistentFlags().StringVar(&flags.pgProxyAddr, "pg-proxy-addr", "", "the address to serve the proxied Postgres sessions, e.g. :5432. The proxy is disabled if empty.")
+	rootCmd.PersistentFlags().StringVar(&flags.mysqlProxyAddr, "mysql-proxy-addr", "", "the address to serve the proxied MySQL sessions, e.g. :3306. The proxy is disabled if empty.")
+	rootCmd.PersistentFlags().StringVar(&flags.proxyTLSCert, "proxy-tls-cert", "", "the TLS certificate file of the proxy.")
+	rootCmd.PersistentFlags().StringVar(&flags.proxyTLSKey, "proxy-tls-key", "", "the TLS private key file of the proxy.")
+}
+
+func start() {
+	if flags.debug {
+		log.SetLevel(zap.DebugLevel)
+	}
+	defer log.Sync()
+
+	if !common.HasPrefixes(flags.serverURL, "http://", "https://") {
+		log.Error(fmt.Sprintf("--server-url %q must start with http:// or https://", flags.serverURL))
+		return
+	}
+	token := flags.token
+	if v := os.Getenv(agentTokenEnv); v != "" {
+		token = v
+	}
+	if token == "" {
+		log.Error(fmt.Sprintf("Missing agent token, set it via --token or %s", agentTokenEnv))
+		return
+	}
+
+	var proxyTLSConfig *tls.Config
+	if flags.proxyTLSCert != "" || flags.proxyTLSKey != "" {
+		cert, err := tls.LoadX509KeyPair(flags.proxyTLSCert, flags.proxyTLSKey)
+		if err != nil {
+			log.Error("Failed to load the proxy TLS certificate", zap.Error(err))
+			return
+		}
+		proxyTLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	} else if flags.pgProxyAddr != "" || flags.mysqlProxyAddr != "" {
+		log.Warn("The proxy is served without TLS, the Bytebase passwords are sent in clear text. Set --proxy-tls-cert and --proxy-tls-key to enable TLS.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-c
+		log.Info(fmt.Sprintf("%s received.", sig.String()))
+		cancel()
+	}()
+
+	log.Info(fmt.Sprintf("Bytebase agent %s connecting to %s", version, flags.serverURL))
+	agent.NewAgent(agent.Profile{
+		ServerURL:   flags.serverURL,
+		Token:       token,
+		Version:     version,
+		ResourceDir: flags.resourceDir,
+
+		PostgresProxyAddr: flags.pgProxyAddr,
+		MySQLProxyAddr:    flags.mysqlProxyAddr,
+		ProxyTLSConfig:    proxyTLSConfig,
+	}).Run(ctx)
+}
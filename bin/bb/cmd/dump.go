@@ -55,6 +55,9 @@ func dumpDatabase(ctx context.Context, u *dburl.URL, out io.Writer, schemaOnly b
 	}
 	defer db.Close(ctx)
 
+	if !db.Capabilities().Dump {
+		return fmt.Errorf("dump is not supported for database %q", u.Host)
+	}
 	if _, err := db.Dump(ctx, getDatabase(u), out, schemaOnly); err != nil {
 		return fmt.Errorf("failed to create dump, got error: %w", err)
 	}
@@ -49,6 +49,9 @@ func restoreDatabase(ctx context.Context, u *dburl.URL, file string) error {
 	}
 	defer db.Close(ctx)
 
+	if !db.Capabilities().Restore {
+		return fmt.Errorf("restore is not supported for database %q", u.Host)
+	}
 	if err := db.Restore(ctx, f); err != nil {
 		return fmt.Errorf("failed to restore from database dump %s got error: %w", file, err)
 	}
@@ -2,10 +2,15 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/spf13/cobra"
 )
 
+// resourceDir is the directory to extract the embedded binaries such as pg_dump and mysqlbinlog.
+var resourceDir string
+
 // NewRootCmd creates the root command.
 func NewRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
@@ -16,6 +21,7 @@ func NewRootCmd() *cobra.Command {
 		},
 	}
 
+	rootCmd.PersistentFlags().StringVar(&resourceDir, "resource-dir", os.TempDir(), "Directory to extract the embedded binaries to, the extraction is skipped if the binaries there pass the checksum verification.")
 	rootCmd.AddCommand(newDumpCmd(), newRestoreCmd(), newVersionCmd(), newMigrateCmd())
 
 	return rootCmd
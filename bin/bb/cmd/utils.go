@@ -3,7 +3,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/resources/mysqlutil"
@@ -26,7 +25,6 @@ func getDatabase(u *dburl.URL) string {
 func open(ctx context.Context, u *dburl.URL) (db.Driver, error) {
 	var dbType db.Type
 	var pgInstanceDir string
-	resourceDir := os.TempDir()
 	switch u.Driver {
 	case "mysql":
 		dbType = db.MySQL
@@ -21,6 +21,7 @@ func GetTestProfile(dataDir string, port int) server.Profile {
 		DatastorePort:        datastorePort,
 		PgUser:               "bbtest",
 		DataDir:              dataDir,
+		ResourceDir:          common.GetResourceDir(dataDir),
 		DemoDataDir:          fmt.Sprintf("demo/%s", common.ReleaseModeDev),
 		BackupRunnerInterval: 10 * time.Second,
 		BackupStorageBackend: api.BackupStorageBackendLocal,
@@ -37,6 +38,7 @@ func GetTestProfileWithExternalPg(dataDir string, port int, pgUser string, pgURL
 		BackendPort:          port,
 		PgUser:               pgUser,
 		DataDir:              dataDir,
+		ResourceDir:          common.GetResourceDir(dataDir),
 		DemoDataDir:          fmt.Sprintf("demo/%s", common.ReleaseModeDev),
 		BackupRunnerInterval: 10 * time.Second,
 		BackupStorageBackend: api.BackupStorageBackendLocal,
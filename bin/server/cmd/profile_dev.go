@@ -19,8 +19,16 @@ func activeProfile(dataDir string, backupStorageBackend api.BackupStorageBackend
 		demoName = flags.demoName
 	}
 	demoDataDir := fmt.Sprintf("demo/%s", demoName)
-	// Using flags.port + 1 as our datastore port
-	datastorePort := flags.port + 1
+	// Using flags.port + 1 as our datastore port if not specified
+	datastorePort := flags.datastorePort
+	if datastorePort == 0 {
+		datastorePort = flags.port + 1
+	}
+
+	resourceDir := flags.resourceDir
+	if resourceDir == "" {
+		resourceDir = common.GetResourceDir(dataDir)
+	}
 
 	return server.Profile{
 		Mode:                 common.ReleaseModeDev,
@@ -29,17 +37,27 @@ func activeProfile(dataDir string, backupStorageBackend api.BackupStorageBackend
 		FrontendHost:         flags.frontendHost,
 		FrontendPort:         flags.frontendPort,
 		DatastorePort:        datastorePort,
+		DatastoreLocale:      flags.datastoreLocale,
+		DatastoreChecksums:   flags.datastoreDataChecksums,
 		PgUser:               "bbdev",
 		Readonly:             flags.readonly,
 		Debug:                flags.debug,
 		Demo:                 flags.demo,
 		DataDir:              dataDir,
+		ResourceDir:          resourceDir,
 		DemoDataDir:          demoDataDir,
 		BackupRunnerInterval: 10 * time.Second,
 		BackupStorageBackend: backupStorageBackend,
 		Version:              version,
 		GitCommit:            gitcommit,
 		PgURL:                flags.pgURL,
+		BootstrapFile:        flags.bootstrapFile,
+		EventSinkURL:         flags.eventSinkURL,
+		SampleData:           flags.sampleData,
+		SampleDatabasePort:   datastorePort + 1,
+		BlobStoreURL:         flags.blobStoreURL,
+		AttachmentScanURL:    flags.attachmentScanURL,
+		Offline:              flags.offline,
 		MetricConnectionKey:  "3zcZLeX3ahvlueEJqNyJysGfVAErsjjT",
 	}
 }
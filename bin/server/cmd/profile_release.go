@@ -21,8 +21,16 @@ func activeProfile(dataDir string, backupStorageBackend api.BackupStorageBackend
 		}
 		demoDataDir = fmt.Sprintf("demo/%s", demoName)
 	}
-	// Using flags.port + 1 as our datastore port
-	datastorePort := flags.port + 1
+	// Using flags.port + 1 as our datastore port if not specified
+	datastorePort := flags.datastorePort
+	if datastorePort == 0 {
+		datastorePort = flags.port + 1
+	}
+
+	resourceDir := flags.resourceDir
+	if resourceDir == "" {
+		resourceDir = common.GetResourceDir(dataDir)
+	}
 
 	return server.Profile{
 		Mode:                 common.ReleaseModeProd,
@@ -31,17 +39,27 @@ func activeProfile(dataDir string, backupStorageBackend api.BackupStorageBackend
 		FrontendHost:         flags.frontendHost,
 		FrontendPort:         flags.frontendPort,
 		DatastorePort:        datastorePort,
+		DatastoreLocale:      flags.datastoreLocale,
+		DatastoreChecksums:   flags.datastoreDataChecksums,
 		PgUser:               "bb",
 		Readonly:             flags.readonly,
 		Debug:                flags.debug,
 		Demo:                 flags.demo,
 		DataDir:              dataDir,
+		ResourceDir:          resourceDir,
 		DemoDataDir:          demoDataDir,
 		BackupRunnerInterval: 10 * time.Minute,
 		BackupStorageBackend: backupStorageBackend,
 		Version:              version,
 		GitCommit:            gitcommit,
 		PgURL:                flags.pgURL,
+		BootstrapFile:        flags.bootstrapFile,
+		EventSinkURL:         flags.eventSinkURL,
+		SampleData:           flags.sampleData,
+		SampleDatabasePort:   datastorePort + 1,
+		BlobStoreURL:         flags.blobStoreURL,
+		AttachmentScanURL:    flags.attachmentScanURL,
+		Offline:              flags.offline,
 		MetricConnectionKey:  "so9lLwj5zLjH09sxNabsyVNYSsAHn68F",
 	}
 }
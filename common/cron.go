@@ -0,0 +1,148 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is the parsed standard 5-field cron expression "minute hour day-of-month month day-of-week".
+// Each field supports "*", a value, a range "a-b", a step "*/n" or "a-b/n", and a comma-separated list of them.
+// As in the standard cron, if both day-of-month and day-of-week are restricted, the day matches either of them.
+type CronSchedule struct {
+	minute     []bool
+	hour       []bool
+	dayOfMonth []bool
+	month      []bool
+	dayOfWeek  []bool
+	// anyDayOfMonth and anyDayOfWeek are set when the field is "*".
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFieldList = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	// Both 0 and 7 are Sunday.
+	{name: "day-of-week", min: 0, max: 7},
+}
+
+// ParseCronSchedule parses the standard 5-field cron expression, e.g. "0 3 1 * *" for 03:00 on the first day of every month.
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFieldList) {
+		return nil, fmt.Errorf("cron expression %q must have %d fields, got %d", expr, len(cronFieldList), len(fields))
+	}
+	var valueList [][]bool
+	for i, field := range cronFieldList {
+		values, err := parseCronField(fields[i], field)
+		if err != nil {
+			return nil, err
+		}
+		valueList = append(valueList, values)
+	}
+	schedule := &CronSchedule{
+		minute:        valueList[0],
+		hour:          valueList[1],
+		dayOfMonth:    valueList[2],
+		month:         valueList[3],
+		dayOfWeek:     valueList[4],
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}
+	if schedule.dayOfWeek[7] {
+		schedule.dayOfWeek[0] = true
+	}
+	return schedule, nil
+}
+
+func parseCronField(expr string, field cronField) ([]bool, error) {
+	values := make([]bool, field.max+1)
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step %q in the %s field", part, field.name)
+			}
+			rangeExpr, step = part[:i], n
+		}
+
+		start, end := field.min, field.max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value %q in the %s field", part, field.name)
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid value %q in the %s field", part, field.name)
+				}
+			} else if step > 1 {
+				// "a/n" means from a to the max.
+				end = field.max
+			}
+		}
+		if start < field.min || end > field.max || start > end {
+			return nil, fmt.Errorf("value %q is out of range [%d, %d] in the %s field", part, field.min, field.max, field.name)
+		}
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// Next returns the first time matching the schedule strictly after t, in the location of t.
+// It returns the zero time if nothing matches within 5 years, e.g. "0 0 30 2 *".
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	matchDayOfMonth := s.dayOfMonth[t.Day()]
+	matchDayOfWeek := s.dayOfWeek[int(t.Weekday())]
+	switch {
+	case s.anyDayOfMonth && s.anyDayOfWeek:
+		return true
+	case s.anyDayOfMonth:
+		return matchDayOfWeek
+	case s.anyDayOfWeek:
+		return matchDayOfMonth
+	default:
+		return matchDayOfMonth || matchDayOfWeek
+	}
+}
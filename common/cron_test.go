@@ -0,0 +1,59 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2022-05-16 is a Monday.
+	now := time.Date(2022, 5, 16, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2022, 5, 16, 10, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2022, 5, 16, 10, 45, 0, 0, time.UTC)},
+		{expr: "0 3 * * *", want: time.Date(2022, 5, 17, 3, 0, 0, 0, time.UTC)},
+		// Monthly on the first day.
+		{expr: "0 3 1 * *", want: time.Date(2022, 6, 1, 3, 0, 0, 0, time.UTC)},
+		// Weekly on Sunday, both 0 and 7 are Sunday.
+		{expr: "30 2 * * 0", want: time.Date(2022, 5, 22, 2, 30, 0, 0, time.UTC)},
+		{expr: "30 2 * * 7", want: time.Date(2022, 5, 22, 2, 30, 0, 0, time.UTC)},
+		{expr: "0 9-17/4 * * 1-5", want: time.Date(2022, 5, 16, 13, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1,15 * *", want: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)},
+		// Either the day-of-month or the day-of-week matches.
+		{expr: "0 0 20 * 3", want: time.Date(2022, 5, 18, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+	}
+	for _, test := range tests {
+		schedule, err := ParseCronSchedule(test.expr)
+		require.NoError(t, err, test.expr)
+		assert.Equal(t, test.want, schedule.Next(now), test.expr)
+	}
+}
+
+func TestParseCronScheduleInvalid(t *testing.T) {
+	invalidList := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1- * * * *",
+	}
+	for _, expr := range invalidList {
+		_, err := ParseCronSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
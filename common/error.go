@@ -33,11 +33,30 @@ const (
 	MigrationFailed          Code = 206
 
 	// 301 task error.
-	TaskTimingNotAllowed Code = 301
+	TaskTimingNotAllowed   Code = 301
+	TaskVerificationFailed Code = 302
 
 	// 401 task sql type error.
 	TaskTypeNotDML Code = 401
 	TaskTypeNotDDL Code = 402
+
+	// 501 task impact analysis error.
+	TaskDependentObjectImpacted Code = 501
+
+	// 601 task database name error.
+	TaskDatabaseNameInvalid Code = 601
+
+	// 701 task database drop error.
+	TaskDatabaseActive Code = 701
+
+	// 801 task stage gate error.
+	TaskStageGateNotPassed Code = 801
+
+	// 901 task replication lag error.
+	TaskReplicationLagExceeded Code = 901
+
+	// 1001 task duplicate migration error.
+	TaskMigrationDuplicated Code = 1001
 )
 
 // Int returns the int type of code.
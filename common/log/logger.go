@@ -2,6 +2,7 @@ package log
 
 import (
 	"os"
+	"sync"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -11,18 +12,38 @@ var (
 	// `gl` is the global logger.
 	// Other packages should use public methods like Info/Error to do the logging.
 	// If special logging is required (like log to a separate file for some special operations), we need to add other loggers.
+	// The cores of `gl` accept all levels, the global level and the subsystem levels are checked before logging.
 	gl     *zap.Logger
 	gLevel zap.AtomicLevel
+	// glMu protects `gl` which is rebuilt when the sinks change.
+	glMu sync.RWMutex
 )
 
 // Initializes the global console logger.
 func init() {
 	gLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
-	gl = zap.New(zapcore.NewCore(
-		zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()),
-		zapcore.Lock(os.Stdout),
-		gLevel,
-	))
+	gl = newLogger(nil)
+}
+
+// newLogger creates the logger writing to the console and the additional sinks.
+func newLogger(sinkList []*sink) *zap.Logger {
+	coreList := []zapcore.Core{
+		zapcore.NewCore(
+			zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()),
+			zapcore.Lock(os.Stdout),
+			zap.DebugLevel,
+		),
+	}
+	for _, s := range sinkList {
+		coreList = append(coreList, s.core)
+	}
+	return zap.New(zapcore.NewTee(coreList...))
+}
+
+func logger() *zap.Logger {
+	glMu.RLock()
+	defer glMu.RUnlock()
+	return gl
 }
 
 // SetLevel wraps the zap Level's SetLevel method.
@@ -30,6 +51,11 @@ func SetLevel(level zapcore.Level) {
 	gLevel.SetLevel(level)
 }
 
+// Level returns the global level.
+func Level() zapcore.Level {
+	return gLevel.Level()
+}
+
 // EnabledLevel wraps the zap Level's Enabled method.
 func EnabledLevel(level zapcore.Level) bool {
 	return gLevel.Enabled(level)
@@ -37,28 +63,36 @@ func EnabledLevel(level zapcore.Level) bool {
 
 // Debug wraps the zap Logger's Debug method.
 func Debug(msg string, fields ...zap.Field) {
-	gl.Debug(msg, fields...)
+	if gLevel.Enabled(zap.DebugLevel) {
+		logger().Debug(msg, fields...)
+	}
 }
 
 // Info wraps the zap Logger's Info method.
 func Info(msg string, fields ...zap.Field) {
-	gl.Info(msg, fields...)
+	if gLevel.Enabled(zap.InfoLevel) {
+		logger().Info(msg, fields...)
+	}
 }
 
 // Warn wraps the zap Logger's Warn method.
 func Warn(msg string, fields ...zap.Field) {
-	gl.Warn(msg, fields...)
+	if gLevel.Enabled(zap.WarnLevel) {
+		logger().Warn(msg, fields...)
+	}
 }
 
 // Error wraps the zap Logger's Error method.
 func Error(msg string, fields ...zap.Field) {
-	// Append the stack info in Error logging for better debugging experience.
-	// Note that we should skip one stack frames so that the top frame start at the caller of log.Error.
-	fields = append(fields, zap.StackSkip("stack", 1))
-	gl.Error(msg, fields...)
+	if gLevel.Enabled(zap.ErrorLevel) {
+		// Append the stack info in Error logging for better debugging experience.
+		// Note that we should skip one stack frames so that the top frame start at the caller of log.Error.
+		fields = append(fields, zap.StackSkip("stack", 1))
+		logger().Error(msg, fields...)
+	}
 }
 
 // Sync wraps the zap Logger's Sync method.
 func Sync() {
-	_ = gl.Sync()
+	_ = logger().Sync()
 }
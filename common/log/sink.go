@@ -0,0 +1,227 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkType is the type of the additional log sink.
+type SinkType string
+
+const (
+	// SinkFile writes the JSON encoded logs to a file rotated by size.
+	SinkFile SinkType = "FILE"
+	// SinkSyslog writes the JSON encoded logs to the syslog.
+	SinkSyslog SinkType = "SYSLOG"
+
+	defaultMaxSizeMB  = 100
+	defaultMaxBackups = 5
+	defaultSyslogTag  = "bytebase"
+)
+
+// SinkConfig is the config of an additional log sink besides the console.
+type SinkConfig struct {
+	Type SinkType `json:"type"`
+	// Path is the log file path of the FILE sink.
+	Path string `json:"path,omitempty"`
+	// MaxSizeMB is the size in megabytes the FILE sink rotates at, 100 if not specified.
+	MaxSizeMB int `json:"maxSizeMB,omitempty"`
+	// MaxBackups is the number of the rotated files kept by the FILE sink, 5 if not specified.
+	MaxBackups int `json:"maxBackups,omitempty"`
+	// Network and Address are the remote syslog server of the SYSLOG sink, e.g. "udp" and "localhost:514".
+	// The local syslog server is used if Network is empty.
+	Network string `json:"network,omitempty"`
+	Address string `json:"address,omitempty"`
+	// Tag is the syslog tag of the SYSLOG sink, "bytebase" if not specified.
+	Tag string `json:"tag,omitempty"`
+}
+
+type sink struct {
+	core   zapcore.Core
+	closer io.Closer
+}
+
+var (
+	// sinkMu serializes SetSinks.
+	sinkMu         sync.Mutex
+	sinkList       []*sink
+	sinkConfigList []SinkConfig
+)
+
+// SetSinks replaces the additional sinks of the global logger.
+// The existing sinks are kept if any of the new sinks fails to open.
+func SetSinks(configList []SinkConfig) error {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+
+	var newSinkList []*sink
+	for _, config := range configList {
+		s, err := openSink(config)
+		if err != nil {
+			for _, s := range newSinkList {
+				_ = s.closer.Close()
+			}
+			return err
+		}
+		newSinkList = append(newSinkList, s)
+	}
+
+	glMu.Lock()
+	oldLogger := gl
+	gl = newLogger(newSinkList)
+	glMu.Unlock()
+
+	_ = oldLogger.Sync()
+	for _, s := range sinkList {
+		_ = s.closer.Close()
+	}
+	sinkList = newSinkList
+	sinkConfigList = append([]SinkConfig{}, configList...)
+	return nil
+}
+
+// Sinks returns the config of the additional sinks.
+func Sinks() []SinkConfig {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	return append([]SinkConfig{}, sinkConfigList...)
+}
+
+func openSink(config SinkConfig) (*sink, error) {
+	var w io.WriteCloser
+	switch config.Type {
+	case SinkFile:
+		if config.Path == "" {
+			return nil, fmt.Errorf("path is required for the %s log sink", config.Type)
+		}
+		maxSizeMB := config.MaxSizeMB
+		if maxSizeMB <= 0 {
+			maxSizeMB = defaultMaxSizeMB
+		}
+		maxBackups := config.MaxBackups
+		if maxBackups <= 0 {
+			maxBackups = defaultMaxBackups
+		}
+		f, err := openRotatingFile(config.Path, int64(maxSizeMB)*1024*1024, maxBackups)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file %q, error: %w", config.Path, err)
+		}
+		w = f
+	case SinkSyslog:
+		tag := config.Tag
+		if tag == "" {
+			tag = defaultSyslogTag
+		}
+		writer, err := syslog.Dial(config.Network, config.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to syslog %s %q, error: %w", config.Network, config.Address, err)
+		}
+		w = writer
+	default:
+		return nil, fmt.Errorf("unsupported log sink type %q", config.Type)
+	}
+
+	return &sink{
+		core: zapcore.NewCore(
+			zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+			zapcore.Lock(zapcore.AddSync(w)),
+			zap.DebugLevel,
+		),
+		closer: w,
+	}, nil
+}
+
+// rotatingFile is a file rotated when its size exceeds maxSize.
+// The rotated files are renamed to path.1, path.2, ... with path.1 being the latest.
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", f.path, i)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := os.Rename(src, fmt.Sprintf("%s.%d", f.path, i+1)); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+// Write writes p to the file, the file is rotated first if p would make it exceed maxSize.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync commits the file to the disk.
+func (f *rotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Sync()
+}
+
+// Close closes the file.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log", "bytebase.log")
+	f, err := openRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	tests := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for name, want := range tests {
+		content, err := os.ReadFile(name)
+		require.NoError(t, err)
+		require.Equal(t, want, string(content))
+	}
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFileSinkSubsystemLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bytebase.log")
+	require.NoError(t, SetSinks([]SinkConfig{{Type: SinkFile, Path: path}}))
+	defer func() {
+		require.NoError(t, SetSinks(nil))
+	}()
+
+	debugLevel := zap.DebugLevel
+	require.NoError(t, SetSubsystemLevel(SubsystemSync, &debugLevel))
+	defer func() {
+		require.NoError(t, SetSubsystemLevel(SubsystemSync, nil))
+	}()
+
+	Debug("global debug")
+	Named(SubsystemScheduler).Debug("scheduler debug")
+	Named(SubsystemSync).Debug("sync debug")
+	Sync()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], `"logger":"sync"`)
+	require.Contains(t, lines[0], `"msg":"sync debug"`)
+}
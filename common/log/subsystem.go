@@ -0,0 +1,112 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Subsystem is the component whose log level can be set independently from the global level.
+type Subsystem string
+
+const (
+	// SubsystemScheduler is the task and task check scheduler.
+	SubsystemScheduler Subsystem = "scheduler"
+	// SubsystemSync is the schema syncer.
+	SubsystemSync Subsystem = "sync"
+	// SubsystemVCS is the VCS push event webhook.
+	SubsystemVCS Subsystem = "vcs"
+)
+
+// SubsystemList is the list of the subsystems.
+var SubsystemList = []Subsystem{SubsystemScheduler, SubsystemSync, SubsystemVCS}
+
+var loggers = func() map[Subsystem]*Logger {
+	m := make(map[Subsystem]*Logger)
+	for _, subsystem := range SubsystemList {
+		m[subsystem] = &Logger{subsystem: subsystem}
+	}
+	return m
+}()
+
+// Logger is the logger of a subsystem.
+// It follows the global level unless the subsystem level is set.
+type Logger struct {
+	subsystem Subsystem
+
+	mu sync.RWMutex
+	// level is nil if the subsystem follows the global level.
+	level *zapcore.Level
+}
+
+// Named returns the logger of the subsystem.
+func Named(subsystem Subsystem) *Logger {
+	l, ok := loggers[subsystem]
+	if !ok {
+		panic(fmt.Sprintf("log: unknown subsystem %q", subsystem))
+	}
+	return l
+}
+
+// SetSubsystemLevel sets the level of the subsystem, the subsystem follows the global level again if level is nil.
+func SetSubsystemLevel(subsystem Subsystem, level *zapcore.Level) error {
+	l, ok := loggers[subsystem]
+	if !ok {
+		return fmt.Errorf("unknown log subsystem %q", subsystem)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+	return nil
+}
+
+// SubsystemLevel returns the level of the subsystem, it's nil if the subsystem follows the global level.
+func SubsystemLevel(subsystem Subsystem) *zapcore.Level {
+	l, ok := loggers[subsystem]
+	if !ok {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level
+}
+
+func (l *Logger) enabled(level zapcore.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.level != nil {
+		return l.level.Enabled(level)
+	}
+	return gLevel.Enabled(level)
+}
+
+// Debug logs a message at the debug level on behalf of the subsystem.
+func (l *Logger) Debug(msg string, fields ...zap.Field) {
+	if l.enabled(zap.DebugLevel) {
+		logger().Named(string(l.subsystem)).Debug(msg, fields...)
+	}
+}
+
+// Info logs a message at the info level on behalf of the subsystem.
+func (l *Logger) Info(msg string, fields ...zap.Field) {
+	if l.enabled(zap.InfoLevel) {
+		logger().Named(string(l.subsystem)).Info(msg, fields...)
+	}
+}
+
+// Warn logs a message at the warn level on behalf of the subsystem.
+func (l *Logger) Warn(msg string, fields ...zap.Field) {
+	if l.enabled(zap.WarnLevel) {
+		logger().Named(string(l.subsystem)).Warn(msg, fields...)
+	}
+}
+
+// Error logs a message at the error level on behalf of the subsystem with the stack of the caller.
+func (l *Logger) Error(msg string, fields ...zap.Field) {
+	if l.enabled(zap.ErrorLevel) {
+		fields = append(fields, zap.StackSkip("stack", 1))
+		logger().Named(string(l.subsystem)).Error(msg, fields...)
+	}
+}
@@ -0,0 +1,16 @@
+package common
+
+import "context"
+
+type requestIDContextKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the API request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the API request ID carried by ctx, it's empty if ctx doesn't derive from an API request.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
@@ -1,7 +1,14 @@
 package common
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"math/big"
 	"os"
 	"path"
@@ -42,6 +49,60 @@ func RandomString(n int) (string, error) {
 	return sb.String(), nil
 }
 
+// HashToken returns the hex encoded SHA-256 hash of the token, which is stored in place of the token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchTokenHash returns true if the token matches the hash returned by HashToken, the hashes are compared in constant time.
+func MatchTokenHash(token, tokenHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(tokenHash)) == 1
+}
+
+// EncryptWithSecret encrypts the plaintext with the AES-GCM key derived from the secret, and returns the base64 encoded
+// nonce and ciphertext. It's for keeping the credentials in the payloads exposed via the API until they're used.
+func EncryptWithSecret(plaintext, secret string) (string, error) {
+	gcm, err := newSecretGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+}
+
+// DecryptWithSecret decrypts the ciphertext returned by EncryptWithSecret with the same secret.
+func DecryptWithSecret(ciphertext, secret string) (string, error) {
+	gcm, err := newSecretGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func newSecretGCM(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
 // HasPrefixes returns true if the string s has any of the given prefixes.
 func HasPrefixes(src string, prefixes ...string) bool {
 	for _, prefix := range prefixes {
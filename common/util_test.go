@@ -40,3 +40,31 @@ func TestHasPrefixes(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchTokenHash(t *testing.T) {
+	tokenHash := HashToken("token")
+	assert.Len(t, tokenHash, 64)
+	assert.NotEqual(t, "token", tokenHash)
+	assert.True(t, MatchTokenHash("token", tokenHash))
+	assert.False(t, MatchTokenHash("tokem", tokenHash))
+	assert.False(t, MatchTokenHash("", tokenHash))
+	assert.False(t, MatchTokenHash(tokenHash, tokenHash))
+}
+
+func TestEncryptWithSecret(t *testing.T) {
+	ciphertext, err := EncryptWithSecret("pa55w0rd", "secret")
+	assert.NoError(t, err)
+	assert.NotContains(t, ciphertext, "pa55w0rd")
+	// Each encryption uses a new nonce.
+	another, err := EncryptWithSecret("pa55w0rd", "secret")
+	assert.NoError(t, err)
+	assert.NotEqual(t, ciphertext, another)
+
+	plaintext, err := DecryptWithSecret(ciphertext, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "pa55w0rd", plaintext)
+	_, err = DecryptWithSecret(ciphertext, "another")
+	assert.Error(t, err)
+	_, err = DecryptWithSecret("c2hvcnQ=", "secret")
+	assert.Error(t, err)
+}
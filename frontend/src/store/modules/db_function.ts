@@ -0,0 +1,71 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  Database,
+  DatabaseId,
+  ResourceIdentifier,
+  ResourceObject,
+  unknown,
+  DBFunction,
+  DBFunctionState,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+import { useDatabaseStore } from "./database";
+
+function convert(
+  dbFunction: ResourceObject,
+  includedList: ResourceObject[]
+): DBFunction {
+  const databaseId = (
+    dbFunction.relationships!.database.data as ResourceIdentifier
+  ).id;
+
+  let database: Database = unknown("DATABASE") as Database;
+  const databaseStore = useDatabaseStore();
+  for (const item of includedList || []) {
+    if (item.type == "database" && item.id == databaseId) {
+      database = databaseStore.convert(item, includedList);
+      break;
+    }
+  }
+
+  return {
+    ...(dbFunction.attributes as Omit<
+      DBFunction,
+      "id" | "database" | "creator" | "updater"
+    >),
+    id: parseInt(dbFunction.id),
+    creator: getPrincipalFromIncludedList(
+      dbFunction.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      dbFunction.relationships!.updater.data,
+      includedList
+    ),
+    database,
+  };
+}
+
+export const useDBFunctionStore = defineStore("dbFunction", {
+  state: (): DBFunctionState => ({
+    dbFunctionListByDatabaseId: new Map(),
+  }),
+
+  actions: {
+    getDBFunctionListByDatabaseId(databaseId: DatabaseId): DBFunction[] {
+      return this.dbFunctionListByDatabaseId.get(databaseId) || [];
+    },
+
+    async fetchDBFunctionListByDatabaseId(databaseId: DatabaseId) {
+      const data = (await axios.get(`/api/database/${databaseId}/function`))
+        .data;
+      const dbFunctionList = data.data.map((dbFunction: ResourceObject) => {
+        return convert(dbFunction, data.included);
+      });
+
+      this.dbFunctionListByDatabaseId.set(databaseId, dbFunctionList);
+      return dbFunctionList;
+    },
+  },
+});
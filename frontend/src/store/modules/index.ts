@@ -14,6 +14,9 @@ export * from "./gitlab";
 export * from "./help";
 export * from "./issue";
 export * from "./issueSubscriber";
+export * from "./issueDependency";
+export * from "./issueAttachment";
+export * from "./issueField";
 export * from "./inbox";
 export * from "./instance";
 export * from "./label";
@@ -40,4 +43,5 @@ export * from "./uistate";
 export * from "./vcs";
 export * from "./view";
 export * from "./db_extension";
+export * from "./db_function";
 export * from "./sqlReview";
@@ -13,7 +13,10 @@ import {
   InstanceId,
   InstanceMigration,
   InstancePatch,
+  InstancePrivilegeCheck,
   InstanceState,
+  InstanceSyncSetting,
+  InstanceSyncSettingUpsert,
   INSTANCE_OPERATION_TIMEOUT,
   MigrationHistory,
   MigrationHistoryId,
@@ -159,6 +162,17 @@ function convertMigrationHistory(history: ResourceObject): MigrationHistory {
   };
 }
 
+function convertSyncSetting(setting: ResourceObject): InstanceSyncSetting {
+  return {
+    id: parseInt(setting.id),
+    instanceId: setting.attributes.instanceId as InstanceId,
+    intervalMinutes: setting.attributes.intervalMinutes as number,
+    blackoutWindowList: JSON.parse(
+      (setting.attributes.blackoutWindowList as string) || "[]"
+    ),
+  };
+}
+
 export const useInstanceStore = defineStore("instance", {
   state: (): InstanceState => ({
     instanceById: new Map(),
@@ -373,6 +387,40 @@ export const useInstanceStore = defineStore("instance", {
       });
       return instanceUserList;
     },
+    async fetchSyncSetting(
+      instanceId: InstanceId
+    ): Promise<InstanceSyncSetting> {
+      const data = (
+        await axios.get(`/api/instance/${instanceId}/sync-setting`)
+      ).data.data;
+      return convertSyncSetting(data);
+    },
+    async upsertSyncSetting(
+      instanceId: InstanceId,
+      upsert: InstanceSyncSettingUpsert
+    ): Promise<InstanceSyncSetting> {
+      const data = (
+        await axios.patch(`/api/instance/${instanceId}/sync-setting`, {
+          data: {
+            type: "instanceSyncSettingUpsert",
+            attributes: {
+              intervalMinutes: upsert.intervalMinutes,
+              blackoutWindowList: JSON.stringify(upsert.blackoutWindowList),
+            },
+          },
+        })
+      ).data.data;
+      return convertSyncSetting(data);
+    },
+    async checkPrivilege(
+      instanceId: InstanceId
+    ): Promise<InstancePrivilegeCheck> {
+      return (
+        await axios.get(`/api/instance/${instanceId}/privilege-check`, {
+          timeout: INSTANCE_OPERATION_TIMEOUT,
+        })
+      ).data;
+    },
     async checkMigrationSetup(
       instanceId: InstanceId
     ): Promise<InstanceMigration> {
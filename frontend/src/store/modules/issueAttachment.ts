@@ -0,0 +1,81 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  IssueAttachment,
+  IssueAttachmentId,
+  IssueAttachmentState,
+  IssueId,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  attachment: ResourceObject,
+  includedList: ResourceObject[]
+): IssueAttachment {
+  return {
+    ...(attachment.attributes as Omit<IssueAttachment, "id" | "creator">),
+    id: parseInt(attachment.id),
+    creator: getPrincipalFromIncludedList(
+      attachment.relationships!.creator.data,
+      includedList
+    ),
+  };
+}
+
+export const useIssueAttachmentStore = defineStore("issueAttachment", {
+  state: (): IssueAttachmentState => ({
+    attachmentListByIssue: new Map(),
+  }),
+
+  actions: {
+    getAttachmentListByIssue(issueId: IssueId): IssueAttachment[] {
+      return this.attachmentListByIssue.get(issueId) || [];
+    },
+
+    async fetchAttachmentListByIssue(issueId: IssueId) {
+      const data = (await axios.get(`/api/issue/${issueId}/attachment`)).data;
+      const attachmentList = data.data.map((attachment: ResourceObject) => {
+        return convert(attachment, data.included);
+      });
+      this.attachmentListByIssue.set(issueId, attachmentList);
+      return attachmentList;
+    },
+
+    async uploadAttachment({
+      issueId,
+      file,
+    }: {
+      issueId: IssueId;
+      file: File;
+    }) {
+      const formData = new FormData();
+      formData.append("file", file);
+      const data = (
+        await axios.post(`/api/issue/${issueId}/attachment`, formData)
+      ).data;
+      const attachment = convert(data.data, data.included);
+      this.attachmentListByIssue.set(issueId, [
+        ...this.getAttachmentListByIssue(issueId),
+        attachment,
+      ]);
+      return attachment;
+    },
+
+    async deleteAttachment({
+      issueId,
+      attachmentId,
+    }: {
+      issueId: IssueId;
+      attachmentId: IssueAttachmentId;
+    }) {
+      await axios.delete(`/api/issue/${issueId}/attachment/${attachmentId}`);
+      this.attachmentListByIssue.set(
+        issueId,
+        this.getAttachmentListByIssue(issueId).filter(
+          (attachment) => attachment.id != attachmentId
+        )
+      );
+    },
+  },
+});
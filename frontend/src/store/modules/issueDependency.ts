@@ -0,0 +1,84 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  IssueDependency,
+  IssueDependencyState,
+  IssueId,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convert(
+  dependency: ResourceObject,
+  includedList: ResourceObject[]
+): IssueDependency {
+  return {
+    ...(dependency.attributes as Omit<IssueDependency, "creator">),
+    creator: getPrincipalFromIncludedList(
+      dependency.relationships!.creator.data,
+      includedList
+    ),
+  };
+}
+
+export const useIssueDependencyStore = defineStore("issueDependency", {
+  state: (): IssueDependencyState => ({
+    dependencyListByIssue: new Map(),
+  }),
+
+  actions: {
+    getBlockingListByIssue(issueId: IssueId): IssueDependency[] {
+      return (this.dependencyListByIssue.get(issueId) || []).filter(
+        (dependency) => dependency.blockedIssueId == issueId
+      );
+    },
+
+    getBlockedListByIssue(issueId: IssueId): IssueDependency[] {
+      return (this.dependencyListByIssue.get(issueId) || []).filter(
+        (dependency) => dependency.blockingIssueId == issueId
+      );
+    },
+
+    async fetchDependencyListByIssue(issueId: IssueId) {
+      const data = (await axios.get(`/api/issue/${issueId}/dependency`)).data;
+      const dependencyList = data.data.map((dependency: ResourceObject) => {
+        return convert(dependency, data.included);
+      });
+      this.dependencyListByIssue.set(issueId, dependencyList);
+      return dependencyList;
+    },
+
+    async createDependency({
+      issueId,
+      blockingIssueId,
+    }: {
+      issueId: IssueId;
+      blockingIssueId: IssueId;
+    }) {
+      const data = (
+        await axios.post(`/api/issue/${issueId}/dependency`, {
+          data: {
+            type: "issueDependencyCreate",
+            attributes: {
+              blockingIssueId,
+            },
+          },
+        })
+      ).data;
+      const dependency = convert(data.data, data.included);
+      await this.fetchDependencyListByIssue(issueId);
+      return dependency;
+    },
+
+    async deleteDependency({
+      issueId,
+      blockingIssueId,
+    }: {
+      issueId: IssueId;
+      blockingIssueId: IssueId;
+    }) {
+      await axios.delete(`/api/issue/${issueId}/dependency/${blockingIssueId}`);
+      await this.fetchDependencyListByIssue(issueId);
+    },
+  },
+});
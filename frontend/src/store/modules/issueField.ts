@@ -0,0 +1,198 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  IssueField,
+  IssueFieldCreate,
+  IssueFieldId,
+  IssueFieldPatch,
+  IssueFieldState,
+  IssueFieldValue,
+  IssueId,
+  ProjectId,
+  ResourceObject,
+} from "@/types";
+import { getPrincipalFromIncludedList } from "./principal";
+
+function convertField(
+  field: ResourceObject,
+  includedList: ResourceObject[]
+): IssueField {
+  return {
+    ...(field.attributes as Omit<IssueField, "id" | "creator" | "updater">),
+    id: parseInt(field.id),
+    creator: getPrincipalFromIncludedList(
+      field.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      field.relationships!.updater.data,
+      includedList
+    ),
+  };
+}
+
+function convertValue(
+  value: ResourceObject,
+  includedList: ResourceObject[]
+): IssueFieldValue {
+  return {
+    ...(value.attributes as Omit<
+      IssueFieldValue,
+      "id" | "creator" | "updater"
+    >),
+    id: parseInt(value.id),
+    creator: getPrincipalFromIncludedList(
+      value.relationships!.creator.data,
+      includedList
+    ),
+    updater: getPrincipalFromIncludedList(
+      value.relationships!.updater.data,
+      includedList
+    ),
+  };
+}
+
+export const useIssueFieldStore = defineStore("issueField", {
+  state: (): IssueFieldState => ({
+    fieldListByProject: new Map(),
+    valueListByIssue: new Map(),
+  }),
+
+  actions: {
+    getFieldListByProject(projectId: ProjectId): IssueField[] {
+      return this.fieldListByProject.get(projectId) || [];
+    },
+
+    getValueListByIssue(issueId: IssueId): IssueFieldValue[] {
+      return this.valueListByIssue.get(issueId) || [];
+    },
+
+    async fetchFieldListByProject(projectId: ProjectId) {
+      const data = (await axios.get(`/api/project/${projectId}/issue-field`))
+        .data;
+      const fieldList = data.data.map((field: ResourceObject) => {
+        return convertField(field, data.included);
+      });
+      this.fieldListByProject.set(projectId, fieldList);
+      return fieldList;
+    },
+
+    async createField({
+      projectId,
+      fieldCreate,
+    }: {
+      projectId: ProjectId;
+      fieldCreate: IssueFieldCreate;
+    }) {
+      const data = (
+        await axios.post(`/api/project/${projectId}/issue-field`, {
+          data: {
+            type: "issueFieldCreate",
+            attributes: fieldCreate,
+          },
+        })
+      ).data;
+      const field = convertField(data.data, data.included);
+      this.fieldListByProject.set(projectId, [
+        ...this.getFieldListByProject(projectId),
+        field,
+      ]);
+      return field;
+    },
+
+    async patchField({
+      projectId,
+      fieldId,
+      fieldPatch,
+    }: {
+      projectId: ProjectId;
+      fieldId: IssueFieldId;
+      fieldPatch: IssueFieldPatch;
+    }) {
+      const data = (
+        await axios.patch(`/api/project/${projectId}/issue-field/${fieldId}`, {
+          data: {
+            type: "issueFieldPatch",
+            attributes: fieldPatch,
+          },
+        })
+      ).data;
+      const field = convertField(data.data, data.included);
+      this.fieldListByProject.set(
+        projectId,
+        this.getFieldListByProject(projectId).map((item) =>
+          item.id == field.id ? field : item
+        )
+      );
+      return field;
+    },
+
+    async deleteField({
+      projectId,
+      fieldId,
+    }: {
+      projectId: ProjectId;
+      fieldId: IssueFieldId;
+    }) {
+      await axios.delete(`/api/project/${projectId}/issue-field/${fieldId}`);
+      this.fieldListByProject.set(
+        projectId,
+        this.getFieldListByProject(projectId).filter(
+          (field) => field.id != fieldId
+        )
+      );
+    },
+
+    async fetchValueListByIssue(issueId: IssueId) {
+      const data = (await axios.get(`/api/issue/${issueId}/field-value`)).data;
+      const valueList = data.data.map((value: ResourceObject) => {
+        return convertValue(value, data.included);
+      });
+      this.valueListByIssue.set(issueId, valueList);
+      return valueList;
+    },
+
+    async setValue({
+      issueId,
+      fieldId,
+      value,
+    }: {
+      issueId: IssueId;
+      fieldId: IssueFieldId;
+      value: string;
+    }) {
+      const data = (
+        await axios.patch(`/api/issue/${issueId}/field-value/${fieldId}`, {
+          data: {
+            type: "issueFieldValueUpsert",
+            attributes: { value },
+          },
+        })
+      ).data;
+      const fieldValue = convertValue(data.data, data.included);
+      this.valueListByIssue.set(issueId, [
+        ...this.getValueListByIssue(issueId).filter(
+          (item) => item.fieldId != fieldId
+        ),
+        fieldValue,
+      ]);
+      return fieldValue;
+    },
+
+    async clearValue({
+      issueId,
+      fieldId,
+    }: {
+      issueId: IssueId;
+      fieldId: IssueFieldId;
+    }) {
+      await axios.delete(`/api/issue/${issueId}/field-value/${fieldId}`);
+      this.valueListByIssue.set(
+        issueId,
+        this.getValueListByIssue(issueId).filter(
+          (item) => item.fieldId != fieldId
+        )
+      );
+    },
+  },
+});
@@ -7,6 +7,7 @@ import {
   MemberCreate,
   MemberPatch,
   MemberState,
+  PrincipalEraseResult,
   ResourceObject,
   PrincipalId,
   unknown,
@@ -133,6 +134,30 @@ export const useMemberStore = defineStore("member", {
 
       return updatedMember;
     },
+    async erasePrincipal({
+      principalId,
+      successorId,
+    }: {
+      principalId: PrincipalId;
+      successorId: PrincipalId;
+    }): Promise<PrincipalEraseResult> {
+      const data = (
+        await axios.post(`/api/principal/${principalId}/erase`, {
+          data: {
+            type: "principalErase",
+            attributes: {
+              successorId,
+            },
+          },
+        })
+      ).data;
+      await this.fetchMemberList();
+
+      return {
+        ...(data.data.attributes as Omit<PrincipalEraseResult, "id">),
+        id: parseInt(data.data.id),
+      };
+    },
     async deleteMemberById(id: MemberId) {
       await axios.delete(`/api/member/${id}`);
 
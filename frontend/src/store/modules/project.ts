@@ -51,6 +51,9 @@ function convert(
     tenantMode: attrs.tenantMode,
     dbNameTemplate: attrs.dbNameTemplate,
     roleProvider: attrs.roleProvider,
+    pinnedNote: attrs.pinnedNote,
+    dbNamePattern: attrs.dbNamePattern,
+    allowedEnvironmentList: attrs.allowedEnvironmentList,
   };
 
   const memberList: ProjectMember[] = [];
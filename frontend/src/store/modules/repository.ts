@@ -6,6 +6,8 @@ import {
   Repository,
   RepositoryCreate,
   RepositoryPatch,
+  RepositoryPushEvent,
+  RepositoryPushEventStatus,
   RepositoryState,
   ResourceIdentifier,
   ResourceObject,
@@ -61,6 +63,20 @@ function convert(
   };
 }
 
+function convertPushEvent(
+  pushEvent: ResourceObject,
+  includedList: ResourceObject[]
+): RepositoryPushEvent {
+  return {
+    ...(pushEvent.attributes as Omit<RepositoryPushEvent, "id" | "updater">),
+    id: parseInt(pushEvent.id),
+    updater: getPrincipalFromIncludedList(
+      pushEvent.relationships!.updater.data,
+      includedList
+    ),
+  };
+}
+
 export const useRepositoryStore = defineStore("repository", {
   state: (): RepositoryState => ({
     repositoryListByVCSId: new Map(),
@@ -191,5 +207,28 @@ export const useRepositoryStore = defineStore("repository", {
       // Refetch the project as the project workflow type has been updated to "UI"
       useProjectStore().fetchProjectById(projectId);
     },
+    async fetchPushEventListByProjectId(
+      projectId: ProjectId,
+      status?: RepositoryPushEventStatus
+    ): Promise<RepositoryPushEvent[]> {
+      const query = status ? `?status=${status}` : "";
+      const data = (
+        await axios.get(`/api/project/${projectId}/push-event${query}`)
+      ).data;
+      return data.data.map((pushEvent: ResourceObject) => {
+        return convertPushEvent(pushEvent, data.included);
+      });
+    },
+    async replayPushEvent(
+      projectId: ProjectId,
+      pushEventId: number
+    ): Promise<RepositoryPushEvent> {
+      const data = (
+        await axios.post(
+          `/api/project/${projectId}/push-event/${pushEventId}/replay`
+        )
+      ).data;
+      return convertPushEvent(data.data, data.included);
+    },
   },
 });
@@ -18,6 +18,8 @@ import {
   SheetOrganizerUpsert,
   ProjectId,
   SheetUpsert,
+  SheetVersion,
+  SheetVersionDiff,
 } from "@/types";
 import { getPrincipalFromIncludedList } from "./principal";
 import { useAuthStore } from "./auth";
@@ -314,5 +316,35 @@ export const useSheetStore = defineStore("sheet", {
     async syncSheetFromVCS(projectId: ProjectId) {
       await axios.post(`/api/sheet/project/${projectId}/sync`);
     },
+    async fetchSheetVersionList(sheetId: SheetId): Promise<SheetVersion[]> {
+      const data = (await axios.get(`/api/sheet/${sheetId}/version`)).data;
+      return data.data.map((item: ResourceObject): SheetVersion => {
+        return {
+          ...(item.attributes as Omit<SheetVersion, "id" | "creator">),
+          id: parseInt(item.id),
+          creator: getPrincipalFromIncludedList(
+            item.relationships!.creator.data,
+            data.included
+          ),
+        };
+      });
+    },
+    async fetchSheetVersionDiff(
+      sheetId: SheetId,
+      fromVersion: number,
+      toVersion?: number
+    ): Promise<SheetVersionDiff> {
+      const queryList = [`from=${fromVersion}`];
+      if (toVersion) {
+        queryList.push(`to=${toVersion}`);
+      }
+      const data = (
+        await axios.get(`/api/sheet/${sheetId}/diff?${queryList.join("&")}`)
+      ).data;
+      return {
+        ...(data.data.attributes as Omit<SheetVersionDiff, "sheetId">),
+        sheetId: parseInt(data.data.id),
+      };
+    },
   },
 });
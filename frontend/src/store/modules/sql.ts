@@ -9,6 +9,10 @@ import {
   ResourceObject,
   SQLResultSet,
   Advice,
+  ConnectionCheck,
+  SchemaSyncMode,
+  EngineType,
+  SQLFormatResult,
 } from "@/types";
 import { useDatabaseStore } from "./database";
 import { useInstanceStore } from "./instance";
@@ -18,6 +22,7 @@ function convert(resultSet: ResourceObject): SQLResultSet {
     data: JSON.parse((resultSet.attributes.data as string) || "null"),
     error: resultSet.attributes.error as string,
     adviceList: resultSet.attributes.adviceList as Advice[],
+    checkList: resultSet.attributes.checkList as ConnectionCheck[],
   };
 }
 
@@ -39,7 +44,7 @@ export const useSQLStore = defineStore("sql", {
 
       return convert(res.data);
     },
-    async syncSchema(instanceId: InstanceId) {
+    async syncSchema(instanceId: InstanceId, mode: SchemaSyncMode = "DEEP") {
       const res = (
         await axios.post(
           `/api/sql/sync-schema`,
@@ -48,6 +53,7 @@ export const useSQLStore = defineStore("sql", {
               type: "sqlSyncSchema",
               attributes: {
                 instanceId: instanceId,
+                mode,
               },
             },
           },
@@ -118,5 +124,55 @@ export const useSQLStore = defineStore("sql", {
 
       return resultSet;
     },
+    async queryMetadata(
+      statement: string,
+      limit?: number
+    ): Promise<SQLResultSet> {
+      const res = (
+        await axios.post(
+          `/api/sql/metadata-query`,
+          {
+            data: {
+              type: "sqlMetadataQuery",
+              attributes: {
+                statement,
+                limit,
+              },
+            },
+          },
+          {
+            timeout: INSTANCE_OPERATION_TIMEOUT,
+          }
+        )
+      ).data;
+
+      const resultSet = convert(res.data);
+      if (resultSet.error) {
+        throw new Error(resultSet.error);
+      }
+
+      return resultSet;
+    },
+    async formatStatement(
+      engine: EngineType,
+      statement: string
+    ): Promise<SQLFormatResult> {
+      const res = (
+        await axios.post(`/api/sql/format`, {
+          data: {
+            type: "sqlFormat",
+            attributes: {
+              engine,
+              statement,
+            },
+          },
+        })
+      ).data;
+
+      return {
+        statement: res.data.attributes.statement as string,
+        canonical: res.data.attributes.canonical as string,
+      };
+    },
   },
 });
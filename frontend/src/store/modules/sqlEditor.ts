@@ -1,6 +1,7 @@
 import { defineStore } from "pinia";
 import dayjs from "dayjs";
 import { isEmpty } from "lodash-es";
+import { v1 as uuidv1 } from "uuid";
 import {
   SQLEditorState,
   ConnectionAtom,
@@ -37,6 +38,10 @@ export const getDefaultConnectionContext = () => ({
   option: {},
 });
 
+// The statements on the databases tagged sensitive are recorded by sessions, and a new session starts
+// whenever the connection changes.
+let editorSession = { connection: "", id: "" };
+
 export const useSQLEditorStore = defineStore("sqlEditor", {
   state: (): SQLEditorState => ({
     connectionTree: [],
@@ -124,7 +129,7 @@ export const useSQLEditorStore = defineStore("sqlEditor", {
     /**
      * check the connection whether disconnected
      * 1、If the context is not set the instanceId, return true
-     * 2、If the context is set the instanceId, but not set the databaseId and databaseType is not MYSQL or TIDB, return true
+     * 2、If the context is set the instanceId, but not set the databaseId and databaseType is not MYSQL, MARIADB or TIDB, return true
      * @param state
      * @returns boolean
      */
@@ -134,6 +139,7 @@ export const useSQLEditorStore = defineStore("sqlEditor", {
         ctx.instanceId === UNKNOWN_ID ||
         (ctx.databaseId === UNKNOWN_ID &&
           ctx.databaseType !== "MYSQL" &&
+          ctx.databaseType !== "MARIADB" &&
           ctx.databaseType !== "TIDB")
       );
     },
@@ -165,12 +171,17 @@ export const useSQLEditorStore = defineStore("sqlEditor", {
       this.isFetchingQueryHistory = payload;
     },
     async executeQuery({ statement }: Pick<QueryInfo, "statement">) {
+      const connection = `${this.connectionContext.instanceId}/${this.connectionContext.databaseName}`;
+      if (editorSession.connection !== connection) {
+        editorSession = { connection, id: uuidv1() };
+      }
       const queryResult = await useSQLStore().query({
         instanceId: this.connectionContext.instanceId,
         databaseName: this.connectionContext.databaseName,
         statement: statement,
         // set the limit to 10000 temporarily to avoid the query timeout and page crash
         limit: 10000,
+        sessionId: editorSession.id,
       });
 
       return queryResult;
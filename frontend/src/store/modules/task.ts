@@ -12,6 +12,7 @@ import {
   Stage,
   StageAllTaskStatusPatch,
   StageId,
+  StagePromote,
   Task,
   TaskCheckRun,
   TaskId,
@@ -272,6 +273,28 @@ export const useTaskStore = defineStore("task", {
 
       useIssueStore().fetchIssueById(issue.id);
     },
+    async promoteStage({
+      issue,
+      stage,
+      promote,
+    }: {
+      issue: Issue;
+      stage: Stage;
+      promote: StagePromote;
+    }) {
+      const { pipeline } = stage;
+      await axios.post(
+        `/api/pipeline/${pipeline.id}/stage/${stage.id}/promote`,
+        {
+          data: {
+            type: "stagePromote",
+            attributes: promote,
+          },
+        }
+      );
+
+      useIssueStore().fetchIssueById(issue.id);
+    },
     async patchTask({
       issueId,
       pipelineId,
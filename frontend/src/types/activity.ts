@@ -12,10 +12,12 @@ export type IssueActivityType =
   | "bb.issue.comment.create"
   | "bb.issue.field.update"
   | "bb.issue.status.update"
+  | "bb.issue.sla.remind"
   | "bb.pipeline.task.status.update"
   | "bb.pipeline.task.file.commit"
   | "bb.pipeline.task.statement.update"
-  | "bb.pipeline.task.general.earliest-allowed-time.update";
+  | "bb.pipeline.task.general.earliest-allowed-time.update"
+  | "bb.pipeline.change-set.halt";
 
 export type MemberActivityType =
   | "bb.member.create"
@@ -30,7 +32,9 @@ export type ProjectActivityType =
   | "bb.project.member.delete"
   | "bb.project.member.role.update";
 
-export type DatabaseActivityType = "bb.database.recovery.pitr.done";
+export type DatabaseActivityType =
+  | "bb.database.recovery.pitr.done"
+  | "bb.database.schema.change";
 
 export type ActivityType =
   | IssueActivityType
@@ -48,6 +52,8 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.issue-field-update");
     case "bb.issue.status.update":
       return t("activity.type.issue-status-update");
+    case "bb.issue.sla.remind":
+      return t("activity.type.issue-sla-remind");
     case "bb.pipeline.task.status.update":
       return t("activity.type.pipeline-task-status-update");
     case "bb.pipeline.task.file.commit":
@@ -56,6 +62,8 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.pipeline-task-statement-update");
     case "bb.pipeline.task.general.earliest-allowed-time.update":
       return t("activity.type.pipeline-task-earliest-allowed-time-update");
+    case "bb.pipeline.change-set.halt":
+      return t("activity.type.pipeline-change-set-halt");
     case "bb.member.create":
       return t("activity.type.member-create");
     case "bb.member.role.update":
@@ -76,6 +84,8 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.project-member-role-update");
     case "bb.database.recovery.pitr.done":
       return t("activity.type.database-recovery-pitr-done");
+    case "bb.database.schema.change":
+      return t("activity.type.database-schema-change");
   }
 }
 
@@ -12,15 +12,50 @@ import {
 export type AnomalyType =
   | "bb.anomaly.instance.connection"
   | "bb.anomaly.instance.migration-schema"
+  | "bb.anomaly.instance.parameter-drift"
+  | "bb.anomaly.instance.certificate-expiry"
+  | "bb.anomaly.instance.version-advisory"
   | "bb.anomaly.database.backup.policy-violation"
   | "bb.anomaly.database.backup.missing"
   | "bb.anomaly.database.connection"
-  | "bb.anomaly.database.schema.drift";
+  | "bb.anomaly.database.schema.drift"
+  | "bb.anomaly.database.invalid-object";
 
 export type AnomalyInstanceConnectionPayload = {
   detail: string;
 };
 
+export type ParameterDrift = {
+  name: string;
+  expect: string;
+  // Empty if the parameter isn't synced from the instance.
+  actual: string;
+};
+
+export type AnomalyInstanceParameterDriftPayload = {
+  environmentId: EnvironmentId;
+  driftList: ParameterDrift[];
+};
+
+export type AnomalyInstanceCertificateExpiryPayload = {
+  subject: string;
+  issuer: string;
+  expireTs: number;
+};
+
+export type VersionAdvisoryStatus =
+  | "SUPPORTED"
+  | "VULNERABLE"
+  | "EOL"
+  | "UNKNOWN";
+
+export type AnomalyInstanceVersionAdvisoryPayload = {
+  status: VersionAdvisoryStatus;
+  // Empty if no supported version is known.
+  recommendedVersion: string;
+  detail: string;
+};
+
 export type AnomalyDatabaseBackupPolicyViolationPayload = {
   environmentId: EnvironmentId;
   expectedSchedule: BackupPlanPolicySchedule;
@@ -42,11 +77,29 @@ export type AnomalyDatabaseSchemaDriftPayload = {
   actual: string;
 };
 
+export type InvalidObjectType = "INDEX" | "CONSTRAINT";
+
+export type InvalidObject = {
+  type: InvalidObjectType;
+  // In the form of "schema.table".
+  table: string;
+  name: string;
+  definition: string;
+};
+
+export type AnomalyDatabaseInvalidObjectPayload = {
+  invalidObjectList: InvalidObject[];
+};
+
 export type AnomalyPayload =
+  | AnomalyInstanceParameterDriftPayload
+  | AnomalyInstanceCertificateExpiryPayload
+  | AnomalyInstanceVersionAdvisoryPayload
   | AnomalyDatabaseBackupPolicyViolationPayload
   | AnomalyDatabaseBackupMissingPayload
   | AnomalyDatabaseConnectionPayload
-  | AnomalyDatabaseSchemaDriftPayload;
+  | AnomalyDatabaseSchemaDriftPayload
+  | AnomalyDatabaseInvalidObjectPayload;
 
 export type AnomalySeverity = "MEDIUM" | "HIGH" | "CRITICAL";
 
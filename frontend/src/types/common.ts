@@ -192,6 +192,7 @@ const makeUnknown = (type: ResourceType) => {
     status: "ACTIVE",
     role: "DEVELOPER",
     principal: UNKNOWN_PRINCIPAL,
+    legalHold: false,
   };
 
   const UNKNOWN_ENVIRONMENT: Environment = {
@@ -220,6 +221,9 @@ const makeUnknown = (type: ResourceType) => {
     tenantMode: "DISABLED",
     dbNameTemplate: "",
     roleProvider: "BYTEBASE",
+    pinnedNote: "",
+    dbNamePattern: "",
+    allowedEnvironmentList: "[]",
   };
 
   const UNKNOWN_PROJECT_HOOK: ProjectWebhook = {
@@ -330,6 +334,7 @@ const makeUnknown = (type: ResourceType) => {
     updatedTs: 0,
     rowStatus: "NORMAL",
     environment: UNKNOWN_ENVIRONMENT,
+    tag: "",
     type: "bb.policy.pipeline-approval",
     payload: {
       value: DefaultApprovalPolicy,
@@ -432,6 +437,7 @@ const makeUnknown = (type: ResourceType) => {
     instanceUrl: "",
     apiUrl: "",
     applicationId: "",
+    authMode: "OAUTH",
     secret: "",
   };
 
@@ -449,8 +455,12 @@ const makeUnknown = (type: ResourceType) => {
     baseDirectory: "",
     branchFilter: "",
     filePathTemplate: "",
+    fileFormat: "BYTEBASE",
     schemaPathTemplate: "",
     sheetPathTemplate: "",
+    environmentMapping: "",
+    requireSignedCommit: false,
+    trustedSigningKeys: "",
     externalId: UNKNOWN_ID.toString(),
   };
 
@@ -595,6 +605,7 @@ const makeEmpty = (type: ResourceType) => {
     status: "ACTIVE",
     role: "DEVELOPER",
     principal: EMPTY_PRINCIPAL,
+    legalHold: false,
   };
 
   const EMPTY_ENVIRONMENT: Environment = {
@@ -623,6 +634,9 @@ const makeEmpty = (type: ResourceType) => {
     tenantMode: "DISABLED",
     dbNameTemplate: "",
     roleProvider: "BYTEBASE",
+    pinnedNote: "",
+    dbNamePattern: "",
+    allowedEnvironmentList: "[]",
   };
 
   const EMPTY_PROJECT_HOOK: ProjectWebhook = {
@@ -733,6 +747,7 @@ const makeEmpty = (type: ResourceType) => {
     updatedTs: 0,
     rowStatus: "NORMAL",
     environment: EMPTY_ENVIRONMENT,
+    tag: "",
     type: "bb.policy.pipeline-approval",
     payload: {
       value: DefaultApprovalPolicy,
@@ -835,6 +850,7 @@ const makeEmpty = (type: ResourceType) => {
     instanceUrl: "",
     apiUrl: "",
     applicationId: "",
+    authMode: "OAUTH",
     secret: "",
   };
 
@@ -852,8 +868,12 @@ const makeEmpty = (type: ResourceType) => {
     baseDirectory: "",
     branchFilter: "",
     filePathTemplate: "",
+    fileFormat: "BYTEBASE",
     schemaPathTemplate: "",
     sheetPathTemplate: "",
+    environmentMapping: "",
+    requireSignedCommit: false,
+    trustedSigningKeys: "",
     externalId: EMPTY_ID.toString(),
   };
 
@@ -41,6 +41,8 @@ export type Database = {
   collation: string;
   schemaVersion: string;
   labels: DatabaseLabel[];
+  // JSON encoded tags in addition to the ones inherited from the instance.
+  tagList?: string;
 };
 
 export type DatabaseCreate = {
@@ -70,4 +72,5 @@ export type DatabasePatch = {
   // Related fields
   projectId?: ProjectId;
   labels?: DatabaseLabel[];
+  tagList?: string;
 };
@@ -0,0 +1,27 @@
+import { Database } from "./database";
+import { DBFunctionId } from "./id";
+import { Principal } from "./principal";
+
+export type DBFunctionType = "FUNCTION" | "PROCEDURE";
+
+// DBFunction
+export type DBFunction = {
+  id: DBFunctionId;
+
+  // Related fields
+  database: Database;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Domain specific fields
+  name: string;
+  schema: string;
+  arguments: string;
+  type: DBFunctionType;
+  language: string;
+  definition: string;
+};
@@ -21,6 +21,10 @@ export type ProjectWebhookId = IdType;
 
 export type IssueId = IdType;
 
+export type IssueAttachmentId = IdType;
+
+export type IssueFieldId = IdType;
+
 export type PipelineId = IdType;
 
 export type StageId = IdType;
@@ -51,6 +55,8 @@ export type ViewId = IdType;
 
 export type DBExtensionId = IdType;
 
+export type DBFunctionId = IdType;
+
 export type ColumnId = IdType;
 
 export type TableIndexId = IdType;
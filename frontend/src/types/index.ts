@@ -16,7 +16,10 @@ export * from "./id";
 export * from "./inbox";
 export * from "./instance";
 export * from "./issue";
+export * from "./issueAttachment";
+export * from "./issueField";
 export * from "./issueSubscriber";
+export * from "./issueDependency";
 export * from "./jsonapi";
 export * from "./member";
 export * from "./notification";
@@ -35,6 +38,7 @@ export * from "./tableIndex";
 export * from "./vcs";
 export * from "./view";
 export * from "./db_extension";
+export * from "./db_function";
 export * from "./label";
 export * from "./deployment";
 export * from "./sqlEditor";
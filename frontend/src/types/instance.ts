@@ -1,4 +1,4 @@
-import { Anomaly, DataSource } from ".";
+import { Anomaly, ConnectionCheckStatus, DataSource } from ".";
 import { RowStatus } from "./common";
 import { Environment } from "./environment";
 import { EnvironmentId, InstanceId, MigrationHistoryId } from "./id";
@@ -7,6 +7,9 @@ import { VCSPushEvent } from "./vcs";
 
 export type EngineType =
   | "CLICKHOUSE"
+  | "MARIADB"
+  | "MONGODB"
+  | "MSSQL"
   | "MYSQL"
   | "POSTGRES"
   | "SNOWFLAKE"
@@ -15,8 +18,11 @@ export type EngineType =
 export function defaultCharset(type: EngineType): string {
   switch (type) {
     case "CLICKHOUSE":
+    case "MONGODB":
+    case "MSSQL":
     case "SNOWFLAKE":
       return "";
+    case "MARIADB":
     case "MYSQL":
     case "TIDB":
       return "utf8mb4";
@@ -28,8 +34,11 @@ export function defaultCharset(type: EngineType): string {
 export function defaultCollation(type: EngineType): string {
   switch (type) {
     case "CLICKHOUSE":
+    case "MONGODB":
+    case "MSSQL":
     case "SNOWFLAKE":
       return "";
+    case "MARIADB":
     case "MYSQL":
     case "TIDB":
       return "utf8mb4_general_ci";
@@ -64,6 +73,8 @@ export type Instance = {
   externalLink?: string;
   host: string;
   port?: string;
+  // JSON encoded tags inherited by the databases, e.g. ["pci","gdpr"].
+  tagList?: string;
 };
 
 export type InstanceCreate = {
@@ -95,6 +106,7 @@ export type InstancePatch = {
   externalLink?: string;
   host?: string;
   port?: string;
+  tagList?: string;
   syncSchema?: boolean;
 };
 
@@ -105,7 +117,12 @@ export type InstanceMigration = {
   error: string;
 };
 
-export type MigrationSource = "UI" | "VCS" | "LIBRARY";
+export type MigrationSource =
+  | "UI"
+  | "VCS"
+  | "LIBRARY"
+  | "FLYWAY"
+  | "LIQUIBASE";
 
 export type MigrationType = "BASELINE" | "MIGRATE" | "BRANCH" | "DATA";
 
@@ -135,3 +152,49 @@ export type MigrationHistory = {
   issueId: number;
   payload?: MigrationHistoryPayload;
 };
+
+export type PrivilegeCheckFeature =
+  | "SYNC_SCHEMA"
+  | "CREATE_DATABASE"
+  | "BACKUP"
+  | "PITR";
+
+export type PrivilegeCheck = {
+  feature: PrivilegeCheckFeature;
+  status: ConnectionCheckStatus;
+  requiredList: string[];
+  missingList: string[];
+  detail: string;
+  // The statement granting the missing privileges.
+  suggestion: string;
+};
+
+export type InstancePrivilegeCheck = {
+  instanceId: InstanceId;
+  username: string;
+  checkList: PrivilegeCheck[];
+};
+
+export type SchemaSyncMode = "DEEP" | "FAST";
+
+export type SyncBlackoutWindow = {
+  // 0 is Sunday, -1 means every day, in UTC.
+  dayOfWeek: number;
+  // [startHour, endHour) in UTC.
+  startHour: number;
+  endHour: number;
+};
+
+export type InstanceSyncSetting = {
+  // UNKNOWN_ID means the instance syncs on the default schedule.
+  id: number;
+  instanceId: InstanceId;
+  // 0 disables the automatic schema sync.
+  intervalMinutes: number;
+  blackoutWindowList: SyncBlackoutWindow[];
+};
+
+export type InstanceSyncSettingUpsert = {
+  intervalMinutes: number;
+  blackoutWindowList: SyncBlackoutWindow[];
+};
@@ -5,8 +5,9 @@ import {
   IssueId,
   PrincipalId,
   ProjectId,
+  SheetId,
 } from "./id";
-import { Pipeline, PipelineCreate } from "./pipeline";
+import { Pipeline, PipelineCreate, TaskVerification } from "./pipeline";
 import { Principal } from "./principal";
 import { Project } from "./project";
 import { MigrationType } from "./instance";
@@ -19,13 +20,24 @@ type IssueTypeDatabase =
   | "bb.issue.database.schema.update"
   | "bb.issue.database.data.update"
   | "bb.issue.database.schema.update.ghost"
-  | "bb.issue.database.pitr";
+  | "bb.issue.database.pitr"
+  | "bb.issue.database.charset.convert"
+  | "bb.issue.database.column.rename"
+  | "bb.issue.database.column.add"
+  | "bb.issue.database.drop"
+  | "bb.issue.database.data.validate"
+  | "bb.issue.database.access.change";
+
+type IssueTypeInstance =
+  | "bb.issue.instance.parameter.change"
+  | "bb.issue.instance.upgrade.check";
 
 type IssueTypeDataSource = "bb.issue.data-source.request";
 
 export type IssueType =
   | IssueTypeGeneral
   | IssueTypeDatabase
+  | IssueTypeInstance
   | IssueTypeDataSource;
 
 export type IssueStatus = "OPEN" | "DONE" | "CANCELED";
@@ -41,13 +53,28 @@ export type CreateDatabaseContext = {
   backupId: BackupId;
   backupName: string;
   labels?: string; // JSON encoded
+  // The database template applied right after creating the database.
+  templateId?: number;
+  // The options below are only applicable to PostgreSQL.
+  tablespace?: string;
+  // 0 means no limit.
+  connectionLimit?: number;
+  extensionList?: string[];
 };
 
 export type UpdateSchemaDetail = {
   databaseId: DatabaseId;
   databaseName: string;
   statement: string;
+  // The optional statement reverting the statement, recorded for the revert issue.
+  downStatement?: string;
   earliestAllowedTs: number;
+  verification?: TaskVerification;
+  // Overrides the migration type of the context, allowing both MIGRATE and DATA details of the same database in one issue.
+  migrationType?: MigrationType;
+  // The optional sheet version the statement is taken from.
+  sheetId?: SheetId;
+  sheetVersion?: number;
 };
 
 export type UpdateSchemaGhostDetail = UpdateSchemaDetail & {
@@ -58,6 +85,10 @@ export type UpdateSchemaGhostDetail = UpdateSchemaDetail & {
 export type UpdateSchemaContext = {
   migrationType: MigrationType;
   updateSchemaDetailList: UpdateSchemaDetail[];
+  // Whether the databases must be updated together as a change set.
+  changeSet?: boolean;
+  // Whether to format the statements in the dialect of the database engines.
+  formatStatement?: boolean;
 };
 
 export type UpdateSchemaGhostContext = {
@@ -0,0 +1,18 @@
+import { IssueAttachmentId, IssueId } from "./id";
+import { Principal } from "./principal";
+
+export type IssueAttachment = {
+  id: IssueAttachmentId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+
+  // Related fields
+  issueId: IssueId;
+
+  // Domain specific fields
+  name: string;
+  contentType: string;
+  size: number;
+};
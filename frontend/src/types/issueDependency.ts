@@ -0,0 +1,13 @@
+import { IssueId } from "./id";
+import { Principal } from "./principal";
+
+// The first stage of the blocked issue can't start until the blocking issue is done.
+export type IssueDependency = {
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+
+  // Domain specific fields
+  blockingIssueId: IssueId;
+  blockedIssueId: IssueId;
+};
@@ -0,0 +1,54 @@
+import { IssueFieldId, IssueId, ProjectId } from "./id";
+import { Principal } from "./principal";
+
+export type IssueFieldType = "STRING" | "ENUM" | "USER" | "DATE";
+
+export type IssueField = {
+  id: IssueFieldId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  projectId: ProjectId;
+
+  // Domain specific fields
+  name: string;
+  type: IssueFieldType;
+  enumValueList: string[];
+};
+
+export type IssueFieldCreate = {
+  // Domain specific fields
+  name: string;
+  type: IssueFieldType;
+  enumValueList: string[];
+};
+
+export type IssueFieldPatch = {
+  // Domain specific fields
+  name?: string;
+  // Comma-separated enum value list.
+  enumValueList?: string;
+};
+
+// The value of the USER field is the principal ID, and the DATE field is in the YYYY-MM-DD format.
+export type IssueFieldValue = {
+  id: number;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  issueId: IssueId;
+  fieldId: IssueFieldId;
+
+  // Domain specific fields
+  value: string;
+};
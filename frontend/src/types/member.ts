@@ -4,7 +4,7 @@ import { Principal } from "./principal";
 
 export type MemberStatus = "INVITED" | "ACTIVE";
 
-export type RoleType = "OWNER" | "DBA" | "DEVELOPER";
+export type RoleType = "OWNER" | "DBA" | "DEVELOPER" | "AUDITOR";
 
 export type Member = {
   id: MemberId;
@@ -20,6 +20,8 @@ export type Member = {
   status: MemberStatus;
   role: RoleType;
   principal: Principal;
+  // The data of the member on legal hold is kept from the retention purge and the erasure.
+  legalHold: boolean;
 };
 
 export type MemberCreate = {
@@ -35,4 +37,28 @@ export type MemberPatch = {
 
   // Domain specific fields
   role?: RoleType;
+  legalHold?: boolean;
+};
+
+// The result of erasing the personal data of a departed member.
+export type PrincipalEraseResult = {
+  id: PrincipalId;
+  successorId: PrincipalId;
+  reassignedCount: number;
+  deletedCount: number;
+};
+
+// The member in the bulk member import.
+export type MemberImportEntry = {
+  email: string;
+  // Defaults to the local part of the email if it's empty.
+  name?: string;
+  // Defaults to DEVELOPER if it's empty.
+  role?: RoleType;
+};
+
+export type MemberImportResult = {
+  createdCount: number;
+  updatedCount: number;
+  skippedCount: number;
 };
@@ -42,3 +42,8 @@ export type StageAllTaskStatusPatch = {
 
   updatedTs: number;
 };
+
+export type StagePromote = {
+  // Approve moves the promoted tasks to PENDING.
+  approve: boolean;
+};
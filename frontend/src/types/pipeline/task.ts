@@ -1,10 +1,12 @@
 import { ErrorCode, MigrationHistoryId, TaskCheckRunId } from "..";
+import { InvalidObject } from "../anomaly";
 import { Database } from "../database";
 import {
   BackupId,
   DatabaseId,
   InstanceId,
   ProjectId,
+  SheetId,
   TaskId,
   TaskRunId,
 } from "../id";
@@ -24,7 +26,14 @@ export type TaskType =
   | "bb.task.database.schema.update.ghost.cutover"
   | "bb.task.database.pitr.restore"
   | "bb.task.database.pitr.cutover"
-  | "bb.task.database.pitr.delete";
+  | "bb.task.database.pitr.delete"
+  | "bb.task.database.charset.convert"
+  | "bb.task.database.data.backfill"
+  | "bb.task.database.drop"
+  | "bb.task.database.data.validate"
+  | "bb.task.database.access.change"
+  | "bb.task.instance.parameter.change"
+  | "bb.task.instance.upgrade.check";
 
 export type TaskStatus =
   | "PENDING"
@@ -50,10 +59,39 @@ export type TaskDatabaseCreatePayload = {
   collation: string;
 };
 
+// The verification query run after the migration is applied.
+// Without expectedRowCount, the query must return a single true value.
+export type TaskVerification = {
+  query: string;
+  expectedRowCount?: number;
+};
+
+// The session settings applied before executing the statement.
+// The unset ones are taken from the session setting policy of the environment.
+export type TaskSessionSetting = {
+  isolationLevel?:
+    | "READ UNCOMMITTED"
+    | "READ COMMITTED"
+    | "REPEATABLE READ"
+    | "SERIALIZABLE";
+  lockTimeoutMs?: number;
+  // Postgres only.
+  searchPath?: string;
+  // MySQL only.
+  sqlMode?: string;
+  foreignKeyChecks?: boolean;
+};
+
 export type TaskDatabaseSchemaUpdatePayload = {
   migrationType: MigrationType;
   statement: string;
   pushEvent?: VCSPushEvent;
+  verification?: TaskVerification;
+  promotedFromTaskId?: TaskId;
+  // The sheet version the statement is taken from.
+  sheetId?: SheetId;
+  sheetVersion?: number;
+  sessionSetting?: TaskSessionSetting;
 };
 
 export type TaskDatabaseSchemaUpdateGhostSyncPayload = {
@@ -84,6 +122,12 @@ export type TaskDatabasePITRDeletePayload = {
 export type TaskDatabaseDataUpdatePayload = {
   statement: string;
   pushEvent?: VCSPushEvent;
+  verification?: TaskVerification;
+  promotedFromTaskId?: TaskId;
+  // The sheet version the statement is taken from.
+  sheetId?: SheetId;
+  sheetVersion?: number;
+  sessionSetting?: TaskSessionSetting;
 };
 
 export type TaskDatabaseRestorePayload = {
@@ -167,6 +211,8 @@ export type TaskCreate = {
 export type TaskPatch = {
   statement?: string;
   earliestAllowedTs?: number;
+  // JSON encoded TaskVerification, empty string removes the verification.
+  verification?: string;
 
   updatedTs?: number;
 };
@@ -185,6 +231,9 @@ export type TaskRunResultPayload = {
   detail: string;
   migrationId?: MigrationHistoryId;
   version?: string;
+  verification?: string;
+  // The invalid indexes and constraints found after the Postgres migration.
+  invalidObjectList?: InvalidObject[];
 };
 
 export type TaskRun = {
@@ -204,6 +253,7 @@ export type TaskRun = {
   comment: string;
   result: TaskRunResultPayload;
   payload?: TaskPayload;
+  requestId: string;
 };
 
 export type TaskCheckRunStatus = "RUNNING" | "DONE" | "FAILED" | "CANCELED";
@@ -213,11 +263,17 @@ export type TaskCheckType =
   | "bb.task-check.database.statement.syntax"
   | "bb.task-check.database.statement.compatibility"
   | "bb.task-check.database.statement.advise"
+  | "bb.task-check.database.statement.external-advise"
   | "bb.task-check.database.statement.type"
   | "bb.task-check.database.connect"
   | "bb.task-check.instance.migration-schema"
   | "bb.task-check.general.earliest-allowed-time"
-  | "bb.task-check.database.ghost.sync";
+  | "bb.task-check.general.stage-gate"
+  | "bb.task-check.database.replication-lag"
+  | "bb.task-check.database.ghost.sync"
+  | "bb.task-check.database.create.name"
+  | "bb.task-check.database.drop.activity"
+  | "bb.task-check.database.migration.duplicate";
 
 export type TaskCheckDatabaseStatementAdvisePayload = {
   statement: string;
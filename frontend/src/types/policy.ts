@@ -1,5 +1,6 @@
 import {
   RowStatus,
+  TaskType,
   Environment,
   PolicyId,
   Principal,
@@ -16,8 +17,15 @@ export type PipelineApprovalPolicyValue =
   | "MANUAL_APPROVAL_NEVER"
   | "MANUAL_APPROVAL_ALWAYS";
 
+export type TaskApproval = {
+  taskType: TaskType;
+  value: PipelineApprovalPolicyValue;
+};
+
 export type PipelineApprovalPolicyPayload = {
   value: PipelineApprovalPolicyValue;
+  // Overrides the value for the tasks of the given types.
+  taskApprovalList?: TaskApproval[];
 };
 
 export const DefaultApprovalPolicy: PipelineApprovalPolicyValue =
@@ -60,6 +68,8 @@ export type Policy = {
   environment: Environment;
 
   // Domain specific fields
+  // The tag scoping the policy to the tagged databases, empty for the environment policy.
+  tag: string;
   type: PolicyType;
   payload: PolicyPayload;
 };
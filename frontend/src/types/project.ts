@@ -50,6 +50,10 @@ export type Project = {
   tenantMode: ProjectTenantMode;
   dbNameTemplate: string;
   roleProvider: ProjectRoleProvider;
+  pinnedNote: string;
+  dbNamePattern: string;
+  // JSON encoded environment IDs the project can deploy to, empty list means no restriction.
+  allowedEnvironmentList: string;
 };
 
 export type ProjectCreate = {
@@ -69,6 +73,9 @@ export type ProjectPatch = {
   name?: string;
   key?: string;
   roleProvider?: ProjectRoleProvider;
+  pinnedNote?: string;
+  dbNamePattern?: string;
+  allowedEnvironmentList?: string;
 };
 
 // Project Member
@@ -49,10 +49,19 @@ export const PROJECT_HOOK_TYPE_ITEM_LIST: () => ProjectWebhookTypeItem[] =
     },
   ];
 
+// The webhook activity filters narrowing down the task status updates to a single kind of change.
+export type ProjectWebhookActivityType =
+  | ActivityType
+  | "bb.pipeline.task.status.update.approved"
+  | "bb.pipeline.task.status.update.started"
+  | "bb.pipeline.task.status.update.completed"
+  | "bb.pipeline.task.status.update.failed"
+  | "bb.pipeline.task.status.update.canceled";
+
 type ProjectWebhookActivityItem = {
   title: string;
   label: string;
-  activity: ActivityType;
+  activity: ProjectWebhookActivityType;
 };
 
 export const PROJECT_HOOK_ACTIVITY_ITEM_LIST: () => ProjectWebhookActivityItem[] =
@@ -72,6 +81,31 @@ export const PROJECT_HOOK_ACTIVITY_ITEM_LIST: () => ProjectWebhookActivityItem[]
       label: t("project.webhook.activity-item.issue-task-status-change.label"),
       activity: "bb.pipeline.task.status.update",
     },
+    {
+      title: t("project.webhook.activity-item.task-approved.title"),
+      label: t("project.webhook.activity-item.task-approved.label"),
+      activity: "bb.pipeline.task.status.update.approved",
+    },
+    {
+      title: t("project.webhook.activity-item.task-started.title"),
+      label: t("project.webhook.activity-item.task-started.label"),
+      activity: "bb.pipeline.task.status.update.started",
+    },
+    {
+      title: t("project.webhook.activity-item.task-completed.title"),
+      label: t("project.webhook.activity-item.task-completed.label"),
+      activity: "bb.pipeline.task.status.update.completed",
+    },
+    {
+      title: t("project.webhook.activity-item.task-failed.title"),
+      label: t("project.webhook.activity-item.task-failed.label"),
+      activity: "bb.pipeline.task.status.update.failed",
+    },
+    {
+      title: t("project.webhook.activity-item.task-canceled.title"),
+      label: t("project.webhook.activity-item.task-canceled.label"),
+      activity: "bb.pipeline.task.status.update.canceled",
+    },
     {
       title: t("project.webhook.activity-item.issue-info-change.title"),
       label: t("project.webhook.activity-item.issue-info-change.label"),
@@ -82,6 +116,21 @@ export const PROJECT_HOOK_ACTIVITY_ITEM_LIST: () => ProjectWebhookActivityItem[]
       label: t("project.webhook.activity-item.issue-comment-creation.label"),
       activity: "bb.issue.comment.create",
     },
+    {
+      title: t("project.webhook.activity-item.database-schema-change.title"),
+      label: t("project.webhook.activity-item.database-schema-change.label"),
+      activity: "bb.database.schema.change",
+    },
+    {
+      title: t("project.webhook.activity-item.change-set-halt.title"),
+      label: t("project.webhook.activity-item.change-set-halt.label"),
+      activity: "bb.pipeline.change-set.halt",
+    },
+    {
+      title: t("project.webhook.activity-item.issue-sla-remind.title"),
+      label: t("project.webhook.activity-item.issue-sla-remind.label"),
+      activity: "bb.issue.sla.remind",
+    },
   ];
 
 // Project Member
@@ -101,7 +150,7 @@ export type ProjectWebhook = {
   type: string;
   name: string;
   url: string;
-  activityList: ActivityType[];
+  activityList: ProjectWebhookActivityType[];
 };
 
 export type ProjectWebhookCreate = {
@@ -109,7 +158,7 @@ export type ProjectWebhookCreate = {
   type: string;
   name: string;
   url: string;
-  activityList: ActivityType[];
+  activityList: ProjectWebhookActivityType[];
 };
 
 export type ProjectWebhookPatch = {
@@ -2,7 +2,15 @@ import isEmpty from "lodash-es/isEmpty";
 import { ProjectId, RepositoryId, VCSId } from "./id";
 import { Principal } from "./principal";
 import { Project } from "./project";
-import { VCS } from "./vcs";
+import { VCS, VCSPushEvent } from "./vcs";
+
+// For the formats other than BYTEBASE, filePathTemplate only matches the directory containing the migration files,
+// and the version is derived from the file name by the format convention.
+export type MigrationFileFormat =
+  | "BYTEBASE"
+  | "FLYWAY"
+  | "LIQUIBASE"
+  | "GOLANG_MIGRATE";
 
 export type Repository = {
   id: RepositoryId;
@@ -27,8 +35,15 @@ export type Repository = {
   baseDirectory: string;
   branchFilter: string;
   filePathTemplate: string;
+  fileFormat: MigrationFileFormat;
   schemaPathTemplate: string;
   sheetPathTemplate: string;
+  // JSON serialization of RepositoryEnvironmentMapping, empty if not configured.
+  environmentMapping: string;
+  // Require the commits of the migration files to be signed and verified by the VCS.
+  requireSignedCommit: boolean;
+  // Comma separated trusted signing keys, any verified signature is trusted if empty.
+  trustedSigningKeys: string;
   // e.g. In GitLab, this is the corresponding project id.
   externalId: string;
 };
@@ -44,8 +59,12 @@ export type RepositoryCreate = {
   branchFilter: string;
   baseDirectory: string;
   filePathTemplate: string;
+  fileFormat?: MigrationFileFormat;
   schemaPathTemplate: string;
   sheetPathTemplate: string;
+  environmentMapping?: string;
+  requireSignedCommit?: boolean;
+  trustedSigningKeys?: string;
   externalId: string;
   accessToken: string;
   expiresTs: number;
@@ -56,8 +75,12 @@ export type RepositoryPatch = {
   baseDirectory?: string;
   branchFilter?: string;
   filePathTemplate?: string;
+  fileFormat?: MigrationFileFormat;
   schemaPathTemplate?: string;
   sheetPathTemplate?: string;
+  environmentMapping?: string;
+  requireSignedCommit?: boolean;
+  trustedSigningKeys?: string;
 };
 
 export type RepositoryConfig = {
@@ -68,6 +91,43 @@ export type RepositoryConfig = {
   sheetPathTemplate: string;
 };
 
+// Maps the branches or directories of the repository to the environments,
+// e.g. main to Prod and develop to Staging. The first matched rule wins.
+export type RepositoryEnvironmentRule = {
+  // Supports the wildcard such as release/*.
+  branch: string;
+  // Relative to the base directory.
+  directory: string;
+  environment: string;
+};
+
+export type RepositoryEnvironmentMapping = {
+  ruleList: RepositoryEnvironmentRule[];
+};
+
+export type RepositoryPushEventStatus = "DONE" | "IGNORED" | "FAILED";
+
+// A file added by a push event received from the VCS, the failed and ignored
+// ones can be replayed after fixing the repository configuration.
+export type RepositoryPushEvent = {
+  id: number;
+
+  // Standard fields
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  projectId: ProjectId;
+
+  // Domain specific fields
+  file: string;
+  pushEvent: VCSPushEvent;
+  status: RepositoryPushEventStatus;
+  message: string;
+  replayCount: number;
+};
+
 export type ExternalRepositoryInfo = {
   // e.g. In GitLab, this is the corresponding project id. e.g. 123
   externalId: string;
@@ -19,3 +19,122 @@ export type Setting = {
 };
 
 export const brandingLogoSettingName: SettingName = "bb.branding.logo";
+export const announcementSettingName: SettingName = "bb.workspace.announcement";
+export const metricReportLevelSettingName: SettingName =
+  "bb.workspace.metric-report-level";
+export const accountReportSettingName: SettingName =
+  "bb.workspace.account-report";
+export const certificateExpirySettingName: SettingName =
+  "bb.workspace.certificate-expiry";
+export const trashSettingName: SettingName = "bb.workspace.trash";
+export const groupSyncSettingName: SettingName = "bb.workspace.group-sync";
+export const dataRetentionSettingName: SettingName =
+  "bb.workspace.data-retention";
+export const externalAdvisorSettingName: SettingName =
+  "bb.workspace.external-advisor";
+
+export type AccountReportSchedule = "UNSET" | "DAILY" | "WEEKLY";
+
+// The value of the account report setting in JSON format.
+export type AccountReportSetting = {
+  schedule: AccountReportSchedule;
+  // The number of days without activity before an account is reported unused.
+  unusedDays: number;
+};
+
+// The value of the certificate expiry setting in JSON format.
+export type CertificateExpirySetting = {
+  // The number of days before the instance certificates expire to raise the anomaly.
+  alertDays: number;
+};
+
+// The value of the trash setting in JSON format.
+export type TrashSetting = {
+  // The number of days the deleted resources can be restored before they are purged.
+  retentionDays: number;
+};
+
+// The value of the data retention setting in JSON format.
+// The number of days each category is kept before it's purged, 0 means forever.
+export type DataRetentionSetting = {
+  activityRetentionDays: number;
+  queryHistoryRetentionDays: number;
+  auditLogRetentionDays: number;
+};
+
+export type ExternalAdvisorEngine = "OPA" | "HTTP";
+
+// The value of the external advisor setting in JSON format.
+// The external policy engine is consulted for the advices on the statements during the task checks, empty url disables it.
+export type ExternalAdvisorSetting = {
+  engine: ExternalAdvisorEngine;
+  url: string;
+  // The timeout of each call, 0 means the default 10 seconds.
+  timeoutSeconds: number;
+  // Warn instead of blocking the tasks when the external policy engine fails.
+  failOpen: boolean;
+};
+
+export type TrashResourceType = "PROJECT" | "INSTANCE" | "DATABASE";
+
+// The deleted resource in the trash.
+export type Trash = {
+  id: number;
+
+  // Standard fields
+  creator: Principal;
+  // The time the resource was deleted.
+  createdTs: number;
+
+  // Domain specific fields
+  resourceType: TrashResourceType;
+  resourceId: number;
+  name: string;
+  purgeTs: number;
+};
+
+// The mapping from the VCS groups to the project roles synced on login.
+export type GroupMapping = {
+  vcsId: number;
+  // The GitLab group full path, or the GitHub team in the form of "<org>/<team>".
+  group: string;
+  projectId: number;
+  role: "OWNER" | "DEVELOPER";
+};
+
+// The value of the group sync setting in JSON format.
+export type GroupSyncSetting = {
+  mappingList: GroupMapping[];
+};
+
+// The granularity of the usage metrics reported out of the workspace.
+export type UsageMetricReportLevel = "NONE" | "AGGREGATE" | "FULL";
+
+// The usage metric aggregated daily in the workspace.
+export type UsageMetric = {
+  id: number;
+
+  // Standard fields
+  createdTs: number;
+  updatedTs: number;
+
+  // Domain specific fields
+  // The UTC date in YYYY-MM-DD format.
+  date: string;
+  name: string;
+  labels: Record<string, string>;
+  value: number;
+  reported: boolean;
+};
+
+export type AnnouncementSeverity = "INFO" | "WARN" | "CRITICAL";
+
+// The value of the announcement setting in JSON format.
+export type Announcement = {
+  // Empty text means no announcement.
+  text: string;
+  severity: AnnouncementSeverity;
+  // 0 means unbounded.
+  startTs: number;
+  endTs: number;
+};
@@ -42,6 +42,30 @@ export interface Sheet {
   pinned: boolean;
 }
 
+// SheetVersion is an immutable revision of the sheet statement.
+export interface SheetVersion {
+  id: number;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+
+  // Related fields
+  sheetId: SheetId;
+
+  // Domain fields
+  version: number;
+  statement: string;
+}
+
+export interface SheetVersionDiff {
+  sheetId: SheetId;
+  fromVersion: number;
+  toVersion: number;
+  // In the unified diff format.
+  diff: string;
+}
+
 export interface SheetUpsert {
   id?: SheetId;
   projectId: ProjectId;
@@ -24,12 +24,33 @@ export type QueryInfo = {
   databaseName?: string;
   statement: string;
   limit?: number;
+  sessionId?: string;
 };
 
 export type Advice = TaskCheckResult;
 
+export type ConnectionCheckType = "DNS" | "TCP" | "TLS" | "AUTH" | "PRIVILEGE";
+
+export type ConnectionCheckStatus = "SUCCESS" | "WARN" | "ERROR" | "SKIPPED";
+
+export type ConnectionCheck = {
+  type: ConnectionCheckType;
+  title: string;
+  status: ConnectionCheckStatus;
+  detail: string;
+  durationMs: number;
+};
+
+export type SQLFormatResult = {
+  statement: string;
+  // The statement without comments and with the whitespace normalized.
+  canonical: string;
+};
+
 export type SQLResultSet = {
   data: any[];
   error: string;
   adviceList: Advice[];
+  // Only set by the connection test.
+  checkList?: ConnectionCheck[];
 };
@@ -78,6 +78,7 @@ export type RuleType =
   | "engine.mysql.use-innodb"
   | "table.require-pk"
   | "table.no-foreign-key"
+  | "table.foreign-key-not-valid"
   | "table.drop-naming-convention"
   | "naming.table"
   | "naming.column"
@@ -106,12 +107,21 @@ interface RequiredColumnPayload {
   columnList: string[];
 }
 
+// The foreign key NOT VALID rule payload.
+// Used by the backend.
+interface TableFKNotValidPayload {
+  minRowCount: number;
+}
+
 // The SchemaPolicyRule stores the rule configuration by users.
 // Used by the backend
 export interface SchemaPolicyRule {
   type: RuleType;
   level: RuleLevel;
-  payload?: NamingFormatPayload | RequiredColumnPayload;
+  payload?:
+    | NamingFormatPayload
+    | RequiredColumnPayload
+    | TableFKNotValidPayload;
 }
 
 // The API for SQL review policy in backend.
@@ -355,6 +365,24 @@ export const convertPolicyRuleToRuleTemplate = (
           },
         ],
       };
+    case "table.foreign-key-not-valid":
+      if (!numberComponent) {
+        throw new Error(`Invalid rule ${ruleTemplate.type}`);
+      }
+
+      return {
+        ...res,
+        componentList: [
+          {
+            ...numberComponent,
+            payload: {
+              ...numberComponent.payload,
+              value: (policyRule.payload as TableFKNotValidPayload)
+                .minRowCount,
+            } as NumberPayload,
+          },
+        ],
+      };
     case "column.required": {
       const requiredColumnComponent = ruleTemplate.componentList[0];
       const requiredColumnPayload = {
@@ -447,6 +475,16 @@ export const convertRuleTemplateToPolicyRule = (
           maxLength: numberPayload.value ?? numberPayload.default,
         },
       };
+    case "table.foreign-key-not-valid":
+      if (!numberPayload) {
+        throw new Error(`Invalid rule ${rule.type}`);
+      }
+      return {
+        ...base,
+        payload: {
+          minRowCount: numberPayload.value ?? numberPayload.default,
+        },
+      };
     case "column.required": {
       const stringArrayPayload = rule.componentList[0]
         .payload as StringArrayPayload;
@@ -8,6 +8,7 @@ import {
   QueryHistory,
   View,
   DBExtension,
+  DBFunction,
   Sheet,
 } from ".";
 import { Activity } from "./activity";
@@ -34,6 +35,9 @@ import { Instance, MigrationHistory } from "./instance";
 import { InstanceUser } from "./InstanceUser";
 import { Issue } from "./issue";
 import { IssueSubscriber } from "./issueSubscriber";
+import { IssueAttachment } from "./issueAttachment";
+import { IssueDependency } from "./issueDependency";
+import { IssueField, IssueFieldValue } from "./issueField";
 import { Member } from "./member";
 import { Notification } from "./notification";
 import { PlanType } from "./plan";
@@ -95,6 +99,20 @@ export interface IssueSubscriberState {
   subscriberList: Map<IssueId, IssueSubscriber[]>;
 }
 
+export interface IssueAttachmentState {
+  attachmentListByIssue: Map<IssueId, IssueAttachment[]>;
+}
+
+export interface IssueDependencyState {
+  // Both the dependencies blocking the issue and the ones blocked by the issue.
+  dependencyListByIssue: Map<IssueId, IssueDependency[]>;
+}
+
+export interface IssueFieldState {
+  fieldListByProject: Map<ProjectId, IssueField[]>;
+  valueListByIssue: Map<IssueId, IssueFieldValue[]>;
+}
+
 // eslint-disable-next-line @typescript-eslint/no-empty-interface
 export interface PipelineState {}
 
@@ -155,6 +173,10 @@ export interface DBExtensionState {
   dbExtensionListByDatabaseId: Map<DatabaseId, DBExtension[]>;
 }
 
+export interface DBFunctionState {
+  dbFunctionListByDatabaseId: Map<DatabaseId, DBFunction[]>;
+}
+
 export interface BackupState {
   backupList: Map<DatabaseId, Backup[]>;
 }
@@ -4,7 +4,7 @@ import { TableId } from "./id";
 import { Principal } from "./principal";
 import { TableIndex } from "./tableIndex";
 
-export type TableType = "BASE TABLE" | "VIEW";
+export type TableType = "BASE TABLE" | "SYSTEM VERSIONED" | "VIEW";
 export type TableEngineType = "InnoDB";
 
 // Table
@@ -32,5 +32,10 @@ export type Table = {
   dataFree: number;
   createOptions: string;
   comment: string;
+  // Only set for the partitioned tables.
+  partitionKey: string;
+  // Only set for the partitions, so they're shown under the partitioned table.
+  parentTable: string;
+  partitionBound: string;
   columnList: Column[];
 };
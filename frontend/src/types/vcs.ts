@@ -3,6 +3,10 @@ import { Principal } from "./principal";
 
 export type VCSType = "GITLAB_SELF_HOST" | "GITHUB_COM";
 
+// GITHUB_APP authenticates as a GitHub App, whose applicationId is the app ID
+// and secret is the private key.
+export type VCSAuthMode = "OAUTH" | "GITHUB_APP";
+
 export interface VCSConfig {
   type: VCSType;
   name: string;
@@ -26,6 +30,7 @@ export type VCS = {
   instanceUrl: string;
   apiUrl: string;
   applicationId: string;
+  authMode: VCSAuthMode;
   secret: string;
 };
 
@@ -36,6 +41,10 @@ export type VCSCreate = {
   instanceUrl: string;
   applicationId: string;
   secret: string;
+  authMode?: VCSAuthMode;
+  // Required by the GitHub App to verify the events delivered to
+  // /hook/github/app/{vcsId}.
+  webhookSecret?: string;
 };
 
 export type VCSPatch = {
@@ -43,6 +52,7 @@ export type VCSPatch = {
   name?: string;
   applicationId?: string;
   secret?: string;
+  webhookSecret?: string;
 };
 
 export type VCSTokenCreate = {
@@ -58,6 +68,19 @@ export type VCSFileCommit = {
   url: string;
   authorName: string;
   added: string;
+  // Only set when the repository requires signed commits.
+  signature?: VCSCommitSignature;
+};
+
+export type VCSSignatureType = "GPG" | "X509" | "SSH";
+
+export type VCSCommitSignature = {
+  signed: boolean;
+  type: VCSSignatureType;
+  verified: boolean;
+  // The GPG key ID, the X.509 certificate email or the SSH key fingerprint.
+  keyId: string;
+  status: string;
 };
 
 export type VCSPushEvent = {
@@ -70,6 +93,41 @@ export type VCSPushEvent = {
   fileCommit: VCSFileCommit;
 };
 
+// The GitLab group, the subgroups are nested by the parentId, which is 0 for the top-level group.
+export type ExternalGroup = {
+  id: number;
+  name: string;
+  fullPath: string;
+  parentId: number;
+  webUrl: string;
+};
+
+// The GitLab group webhook delivers the push events of the projects under the group
+// and its subgroups to the /hook/gitlab/group endpoint, the repositories linked
+// under the group don't have the webhook of their own.
+export type VCSGroupWebhook = {
+  id: number;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Domain specific fields
+  vcsId: VCSId;
+  externalGroupId: string;
+  groupFullPath: string;
+};
+
+export type VCSGroupWebhookCreate = {
+  externalGroupId: string;
+  groupFullPath: string;
+  accessToken: string;
+  expiresTs: number;
+  refreshToken: string;
+};
+
 export function isValidVCSApplicationIdOrSecret(
   vcsType: VCSType,
   str: string
@@ -28,6 +28,8 @@ export function roleName(role: RoleType): string {
       return "DBA";
     case "DEVELOPER":
       return "Developer";
+    case "AUDITOR":
+      return "Auditor";
   }
 }
 
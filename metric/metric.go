@@ -27,6 +27,10 @@ const (
 	OpenAPIMetricName metric.Name = "bb.api.call"
 	// SQLAdviseAPIMetricName is the metric name for SQL check API.
 	SQLAdviseAPIMetricName metric.Name = "bb.api.sql.advise"
+	// IssueCreatedMetricName is the metric name for the issues created.
+	IssueCreatedMetricName metric.Name = "bb.issue.created"
+	// TaskRunMetricName is the metric name for the task runs finished.
+	TaskRunMetricName metric.Name = "bb.task.run"
 )
 
 // InstanceCountMetric is the API message for bb.instance.count.
@@ -144,6 +144,9 @@ const (
 
 	// PostgreSQLTableNoFK is an advisor type for PostgreSQL table disallow foreign key.
 	PostgreSQLTableNoFK Type = "bb.plugin.advisor.postgresql.table.no-foreign-key"
+
+	// PostgreSQLTableFKNotValid is an advisor type for PostgreSQL adding foreign key with NOT VALID on large tables.
+	PostgreSQLTableFKNotValid Type = "bb.plugin.advisor.postgresql.table.foreign-key-not-valid"
 )
 
 // Advice is the result of an advisor.
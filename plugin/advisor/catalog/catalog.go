@@ -117,6 +117,27 @@ func (d *Database) HasNoTable() bool {
 	return true
 }
 
+// TableFind is for find table.
+type TableFind struct {
+	SchemaName string
+	TableName  string
+}
+
+// FindTable finds the table.
+func (d *Database) FindTable(find *TableFind) *Table {
+	for _, schema := range d.SchemaList {
+		if schema.Name != find.SchemaName {
+			continue
+		}
+		for _, table := range schema.TableList {
+			if table.Name == find.TableName {
+				return table
+			}
+		}
+	}
+	return nil
+}
+
 // IndexFind is for find index.
 type IndexFind struct {
 	SchemaName string
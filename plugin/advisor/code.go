@@ -53,6 +53,7 @@ const (
 	TableNoPK                         Code = 601
 	TableHasFK                        Code = 602
 	TableDropNamingConventionMismatch Code = 603
+	TableFKRequireNotValid            Code = 604
 
 	// 701 ~ 799 database advisor error code.
 	DatabaseNotEmpty   Code = 701
@@ -60,6 +61,9 @@ const (
 
 	// 801 miss index error code.
 	NotUseIndex Code = 801
+
+	// 901 external policy error code.
+	ExternalPolicyViolation Code = 901
 )
 
 // Int returns the int type of code.
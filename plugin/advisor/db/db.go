@@ -19,9 +19,10 @@ const (
 )
 
 // ConvertToAdvisorDBType will convert db type into advisor db type.
+// MariaDB is reviewed by the MySQL advisors.
 func ConvertToAdvisorDBType(dbType string) (Type, error) {
 	switch strings.ToUpper(dbType) {
-	case string(MySQL):
+	case string(MySQL), "MARIADB":
 		return MySQL, nil
 	case string(Postgres):
 		return Postgres, nil
@@ -0,0 +1,206 @@
+// Package external consults an external policy engine, e.g. Open Policy Agent, for the advices on the statements,
+// so that the organization rules can be codified outside Bytebase.
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	tidbparser "github.com/pingcap/tidb/parser"
+)
+
+// Engine is the type of the external policy engine.
+type Engine string
+
+const (
+	// OPA is the Open Policy Agent. The request is posted as {"input": <Request>} to the data API of the policy,
+	// e.g. http://localhost:8181/v1/data/bytebase/advise, and the Response is read from the "result" field.
+	OPA Engine = "OPA"
+	// HTTP is a plain HTTP policy service. The Request is posted as the body and the Response is read from the body.
+	HTTP Engine = "HTTP"
+)
+
+// Statement is a single statement of the checked statements.
+type Statement struct {
+	Text string `json:"text"`
+	// Type is the type of the parsed statement, e.g. "CreateTableStmt", empty if the statement isn't parsed.
+	Type string `json:"type"`
+}
+
+// RequestContext is the context of the checked statements.
+type RequestContext struct {
+	// Engine is the database engine, e.g. "MYSQL".
+	Engine    string `json:"engine"`
+	Charset   string `json:"charset"`
+	Collation string `json:"collation"`
+	// TaskType is the type of the task running the statements, e.g. "bb.task.database.schema.update".
+	TaskType    string            `json:"taskType"`
+	Environment string            `json:"environment"`
+	Instance    string            `json:"instance"`
+	Database    string            `json:"database"`
+	Labels      map[string]string `json:"labels"`
+	// Tags is the policy tags of the instance and the database, e.g. "pci".
+	Tags      []string `json:"tags"`
+	Project   string   `json:"project"`
+	IssueID   int      `json:"issueId"`
+	IssueName string   `json:"issueName"`
+	// Creator is the email of the issue creator.
+	Creator string `json:"creator"`
+}
+
+// Request is the request to the external policy engine.
+type Request struct {
+	Statement     string         `json:"statement"`
+	StatementList []Statement    `json:"statementList"`
+	Context       RequestContext `json:"context"`
+}
+
+// ResponseAdvice is an advice responded by the external policy engine.
+type ResponseAdvice struct {
+	// Status is "SUCCESS", "WARN" or "ERROR", the ERROR advices block the task.
+	Status  advisor.Status `json:"status"`
+	Title   string         `json:"title"`
+	Content string         `json:"content"`
+}
+
+// Response is the response of the external policy engine, no advice means the statements comply with the policy.
+type Response struct {
+	AdviceList []ResponseAdvice `json:"adviceList"`
+}
+
+// opaRequest and opaResponse wrap the request and the response in the OPA data API.
+type opaRequest struct {
+	Input *Request `json:"input"`
+}
+
+type opaResponse struct {
+	Result *Response `json:"result"`
+}
+
+// ParseStatementList splits the statement into the single statements with the parsed types.
+// The statement is kept as a whole without the type if the engine isn't supported or the statement can't be parsed,
+// the syntax is checked by the syntax advisor rather than the external policy engine.
+func ParseStatementList(dbType advisorDB.Type, statement, charset, collation string) []Statement {
+	switch dbType {
+	case advisorDB.MySQL, advisorDB.TiDB:
+		p := tidbparser.New()
+		p.EnableWindowFunc(true)
+		nodeList, _, err := p.Parse(statement, charset, collation)
+		if err != nil {
+			break
+		}
+		var statementList []Statement
+		for _, node := range nodeList {
+			statementList = append(statementList, Statement{Text: node.Text(), Type: getNodeType(node)})
+		}
+		return statementList
+	case advisorDB.Postgres:
+		nodeList, err := parser.Parse(parser.Postgres, parser.Context{}, statement)
+		if err != nil {
+			break
+		}
+		var statementList []Statement
+		for _, node := range nodeList {
+			statementList = append(statementList, Statement{Text: node.Text(), Type: getNodeType(node)})
+		}
+		return statementList
+	}
+	return []Statement{{Text: statement}}
+}
+
+// getNodeType returns the name of the node type, e.g. "CreateTableStmt" for *ast.CreateTableStmt.
+func getNodeType(node interface{}) string {
+	t := reflect.TypeOf(node)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// Client is the client of the external policy engine.
+type Client struct {
+	client *http.Client
+}
+
+// NewClient creates a client of the external policy engine with the timeout of each check.
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Check posts the request to the external policy engine at the URL and returns the advices.
+// The engine must respond 200, and OPA must define the result of the policy.
+func (c *Client) Check(ctx context.Context, engine Engine, url string, request *Request) ([]advisor.Advice, error) {
+	var body interface{} = request
+	if engine == OPA {
+		body = &opaRequest{Input: request}
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal external policy request, error: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct external policy request %v, error: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to POST external policy %v, error: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	b, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read external policy response %v, error: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to POST external policy %v, status code: %d, response body: %s", url, resp.StatusCode, b)
+	}
+
+	response := &Response{}
+	switch engine {
+	case OPA:
+		opaResp := &opaResponse{}
+		if err := json.Unmarshal(b, opaResp); err != nil {
+			return nil, fmt.Errorf("malformed OPA response %v, response body: %s, error: %w", url, b, err)
+		}
+		// OPA omits the result if the policy is undefined, e.g. the URL has a typo.
+		if opaResp.Result == nil {
+			return nil, fmt.Errorf("OPA policy %v is undefined", url)
+		}
+		response = opaResp.Result
+	case HTTP:
+		if err := json.Unmarshal(b, response); err != nil {
+			return nil, fmt.Errorf("malformed external policy response %v, response body: %s, error: %w", url, b, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported external policy engine %q", engine)
+	}
+
+	var adviceList []advisor.Advice
+	for _, advice := range response.AdviceList {
+		switch advice.Status {
+		case advisor.Success, advisor.Warn, advisor.Error:
+		default:
+			return nil, fmt.Errorf("invalid advice status %q in external policy response %v", advice.Status, url)
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advice.Status,
+			Code:    advisor.ExternalPolicyViolation,
+			Title:   advice.Title,
+			Content: advice.Content,
+		})
+	}
+	return adviceList, nil
+}
@@ -0,0 +1,103 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
+
+	// Register the Postgres parser and the TiDB parser driver.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+func TestParseStatementList(t *testing.T) {
+	tests := []struct {
+		dbType    advisorDB.Type
+		statement string
+		want      []Statement
+	}{
+		{
+			dbType:    advisorDB.MySQL,
+			statement: "CREATE TABLE t(a int);DROP TABLE t;",
+			want: []Statement{
+				{Text: "CREATE TABLE t(a int);", Type: "CreateTableStmt"},
+				{Text: "DROP TABLE t;", Type: "DropTableStmt"},
+			},
+		},
+		{
+			dbType:    advisorDB.Postgres,
+			statement: "CREATE TABLE t(a int);",
+			want: []Statement{
+				{Text: "CREATE TABLE t(a int);", Type: "CreateTableStmt"},
+			},
+		},
+		{
+			dbType:    advisorDB.MySQL,
+			statement: "CREATE TABLLE t(a int);",
+			want: []Statement{
+				{Text: "CREATE TABLLE t(a int);"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, ParseStatementList(test.dbType, test.statement, "", ""), test.statement)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	request := &Request{
+		Statement:     "DROP TABLE t;",
+		StatementList: []Statement{{Text: "DROP TABLE t;", Type: "DropTableStmt"}},
+		Context:       RequestContext{Engine: "MYSQL", Environment: "Prod"},
+	}
+	response := &Response{
+		AdviceList: []ResponseAdvice{
+			{Status: advisor.Error, Title: "No DROP TABLE in Prod", Content: "DROP TABLE t;"},
+		},
+	}
+	want := []advisor.Advice{
+		{Status: advisor.Error, Code: advisor.ExternalPolicyViolation, Title: "No DROP TABLE in Prod", Content: "DROP TABLE t;"},
+	}
+
+	opa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := &opaRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(got))
+		require.Equal(t, request, got.Input)
+		require.NoError(t, json.NewEncoder(w).Encode(&opaResponse{Result: response}))
+	}))
+	defer opa.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := &Request{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(got))
+		require.Equal(t, request, got)
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer plain.Close()
+	undefined := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer undefined.Close()
+
+	client := NewClient(time.Second)
+	ctx := context.Background()
+
+	adviceList, err := client.Check(ctx, OPA, opa.URL, request)
+	require.NoError(t, err)
+	require.Equal(t, want, adviceList)
+
+	adviceList, err = client.Check(ctx, HTTP, plain.URL, request)
+	require.NoError(t, err)
+	require.Equal(t, want, adviceList)
+
+	_, err = client.Check(ctx, OPA, undefined.URL, request)
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*TableFKNotValidAdvisor)(nil)
+	_ ast.Visitor     = (*tableFKNotValidChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLTableFKNotValid, &TableFKNotValidAdvisor{})
+}
+
+// TableFKNotValidAdvisor is the advisor checking the foreign keys on large tables are added with NOT VALID.
+type TableFKNotValidAdvisor struct {
+}
+
+// Check checks the foreign keys on large tables are added with NOT VALID.
+func (*TableFKNotValidAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalTableFKNotValidRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &tableFKNotValidChecker{
+		level:       level,
+		title:       string(ctx.Rule.Type),
+		database:    ctx.Database,
+		minRowCount: payload.MinRowCount,
+	}
+
+	for _, stmt := range stmts {
+		checker.text = stmt.Text()
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type tableFKNotValidChecker struct {
+	adviceList  []advisor.Advice
+	level       advisor.Status
+	title       string
+	database    *catalog.Database
+	minRowCount int64
+	text        string
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *tableFKNotValidChecker) Visit(node ast.Node) ast.Visitor {
+	// The foreign keys in CREATE TABLE are skipped since the new table has no rows to validate.
+	n, ok := node.(*ast.AddConstraintStmt)
+	if !ok || n.Constraint.Type != ast.ConstraintTypeForeign || n.Constraint.SkipValidation {
+		return checker
+	}
+	table := checker.database.FindTable(&catalog.TableFind{
+		SchemaName: normalizeSchemaName(n.Table.Schema),
+		TableName:  n.Table.Name,
+	})
+	if table == nil || table.RowCount < checker.minRowCount {
+		return checker
+	}
+
+	checker.adviceList = append(checker.adviceList, advisor.Advice{
+		Status: checker.level,
+		Code:   advisor.TableFKRequireNotValid,
+		Title:  checker.title,
+		Content: fmt.Sprintf("Adding foreign key on table %q.%q with about %d rows blocks the writes while validating the existing rows, "+
+			"add it with NOT VALID and it will be validated in a separate step, related statement: %q",
+			normalizeSchemaName(n.Table.Schema),
+			n.Table.Name,
+			table.RowCount,
+			checker.text,
+		),
+	})
+	return checker
+}
@@ -0,0 +1,66 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+func TestTableFKNotValid(t *testing.T) {
+	database := &catalog.Database{
+		Name:   "test",
+		DbType: db.Postgres,
+		SchemaList: []*catalog.Schema{
+			{
+				Name: "public",
+				TableList: []*catalog.Table{
+					{Name: "tech_book", RowCount: 2000},
+					{Name: "author", RowCount: 10},
+				},
+			},
+		},
+	}
+	tests := []advisor.TestCase{
+		{
+			Statement: "ALTER TABLE tech_book ADD CONSTRAINT fk_tech_book_author_id_author_id FOREIGN KEY (author_id) REFERENCES author (id)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableFKRequireNotValid,
+					Title:   "table.foreign-key-not-valid",
+					Content: "Adding foreign key on table \"public\".\"tech_book\" with about 2000 rows blocks the writes while validating the existing rows, add it with NOT VALID and it will be validated in a separate step, related statement: \"ALTER TABLE tech_book ADD CONSTRAINT fk_tech_book_author_id_author_id FOREIGN KEY (author_id) REFERENCES author (id)\"",
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book ADD CONSTRAINT fk_tech_book_author_id_author_id FOREIGN KEY (author_id) REFERENCES author (id) NOT VALID",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE author ADD CONSTRAINT fk_author_book_id_tech_book_id FOREIGN KEY (book_id) REFERENCES tech_book (id)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &TableFKNotValidAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleTableFKNotValid,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: `{"minRowCount":1000}`,
+	}, database)
+}
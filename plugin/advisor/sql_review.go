@@ -58,6 +58,8 @@ const (
 	SchemaRuleTableRequirePK SQLReviewRuleType = "table.require-pk"
 	// SchemaRuleTableNoFK require the table disallow the foreign key.
 	SchemaRuleTableNoFK SQLReviewRuleType = "table.no-foreign-key"
+	// SchemaRuleTableFKNotValid require adding the foreign key with NOT VALID on large tables, so that it's validated in a separate step.
+	SchemaRuleTableFKNotValid SQLReviewRuleType = "table.foreign-key-not-valid"
 	// SchemaRuleTableDropNamingConvention require only the table following the naming convention can be deleted.
 	SchemaRuleTableDropNamingConvention SQLReviewRuleType = "table.drop-naming-convention"
 
@@ -87,6 +89,8 @@ const (
 
 	// defaultNameLengthLimit is the default length limit for naming rules.
 	defaultNameLengthLimit = 64
+	// defaultFKNotValidMinRowCount is the default row count from which the table is considered large for adding foreign keys.
+	defaultFKNotValidMinRowCount = 1000000
 )
 
 var (
@@ -157,6 +161,10 @@ func (rule *SQLReviewRule) Validate() error {
 		if _, err := UnmarshalRequiredColumnRulePayload(rule.Payload); err != nil {
 			return err
 		}
+	case SchemaRuleTableFKNotValid:
+		if _, err := UnmarshalTableFKNotValidRulePayload(rule.Payload); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -172,6 +180,11 @@ type RequiredColumnRulePayload struct {
 	ColumnList []string `json:"columnList"`
 }
 
+// TableFKNotValidRulePayload is the payload for the foreign key NOT VALID rule.
+type TableFKNotValidRulePayload struct {
+	MinRowCount int64 `json:"minRowCount"`
+}
+
 // UnamrshalNamingRulePayloadAsRegexp will unmarshal payload to NamingRulePayload and compile it as regular expression.
 func UnamrshalNamingRulePayloadAsRegexp(payload string) (*regexp.Regexp, int, error) {
 	var nr NamingRulePayload
@@ -253,6 +266,24 @@ func UnmarshalRequiredColumnRulePayload(payload string) (*RequiredColumnRulePayl
 	return &rcr, nil
 }
 
+// UnmarshalTableFKNotValidRulePayload will unmarshal payload to TableFKNotValidRulePayload.
+func UnmarshalTableFKNotValidRulePayload(payload string) (*TableFKNotValidRulePayload, error) {
+	var fr TableFKNotValidRulePayload
+	// The payload may be empty for the rules created before the payload is introduced.
+	if payload != "" {
+		if err := json.Unmarshal([]byte(payload), &fr); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal foreign key NOT VALID rule payload %q: %q", payload, err)
+		}
+	}
+	if fr.MinRowCount < 0 {
+		return nil, fmt.Errorf("invalid foreign key NOT VALID rule payload, min row count cannot be negative")
+	}
+	if fr.MinRowCount == 0 {
+		fr.MinRowCount = defaultFKNotValidMinRowCount
+	}
+	return &fr, nil
+}
+
 // SQLReviewCheckContext is the context for SQL review check.
 type SQLReviewCheckContext struct {
 	Charset   string
@@ -409,6 +440,10 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		case db.Postgres:
 			return PostgreSQLTableNoFK, nil
 		}
+	case SchemaRuleTableFKNotValid:
+		if engine == db.Postgres {
+			return PostgreSQLTableFKNotValid, nil
+		}
 	case SchemaRuleTableDropNamingConvention:
 		switch engine {
 		case db.MySQL, db.TiDB:
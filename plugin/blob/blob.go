@@ -0,0 +1,62 @@
+// Package blob stores the file contents such as the issue attachments in a blob store, either the local disk or S3.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ErrNotFound is returned when the blob doesn't exist.
+var ErrNotFound = errors.New("blob: not found")
+
+// Store is the blob store.
+// The key is a slash separated path such as "issue/101/attachment/abc", it must not contain "." or ".." elements.
+type Store interface {
+	// Put writes the blob, overwriting the existing one with the same key.
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	// Get returns the reader of the blob, the caller should close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete deletes the blob, deleting a nonexistent blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Open opens the blob store by the URL.
+//
+// The supported stores are:
+//   - file:///path/to/dir, the blobs are stored as files under the directory.
+//   - s3://bucket/prefix?region=us-east-1[&endpoint=http://localhost:9000], the blobs are stored as objects under the prefix.
+//     The credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and optional AWS_SESSION_TOKEN environment variables.
+//     The endpoint is for the S3 compatible services such as MinIO, which are accessed in the path style.
+func Open(storeURL string) (Store, error) {
+	u, err := url.Parse(storeURL)
+	if err != nil {
+		return nil, fmt.Errorf("blob: invalid store URL, error: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("blob: missing directory in store URL %q", storeURL)
+		}
+		return NewLocalStore(u.Path), nil
+	case "s3":
+		return newS3Store(u)
+	default:
+		return nil, fmt.Errorf("blob: no applicable store for URL scheme: %q", u.Scheme)
+	}
+}
+
+func validateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("blob: empty key")
+	}
+	for _, element := range strings.Split(key, "/") {
+		if element == "" || element == "." || element == ".." {
+			return fmt.Errorf("blob: invalid key %q", key)
+		}
+	}
+	return nil
+}
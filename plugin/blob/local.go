@@ -0,0 +1,85 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var (
+	_ Store = (*LocalStore)(nil)
+)
+
+// LocalStore is the blob store saving the blobs as files under a directory.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a local blob store under the directory.
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+// Put writes the blob to a temporary file first and then renames it, so that the readers never see a partial file.
+func (s *LocalStore) Put(_ context.Context, key string, content []byte, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("blob: failed to create directory for %q, error: %w", key, err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("blob: failed to create temporary file for %q, error: %w", key, err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return fmt.Errorf("blob: failed to write %q, error: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("blob: failed to write %q, error: %w", key, err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("blob: failed to write %q, error: %w", key, err)
+	}
+	return nil
+}
+
+// Get opens the file of the blob.
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("blob: failed to read %q, error: %w", key, err)
+	}
+	return f, nil
+}
+
+// Delete removes the file of the blob.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("blob: failed to delete %q, error: %w", key, err)
+	}
+	return nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
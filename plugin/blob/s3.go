@@ -0,0 +1,230 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Service         = "s3"
+	s3DefaultRegion   = "us-east-1"
+	s3SigningAlgo     = "AWS4-HMAC-SHA256"
+	s3AmzDateFormat   = "20060102T150405Z"
+	s3ScopeDateFormat = "20060102"
+	s3RequestTimeout  = 60 * time.Second
+)
+
+var (
+	_ Store = (*S3Store)(nil)
+)
+
+// S3Store is the blob store saving the blobs as the S3 objects.
+// The requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	// baseURL is the URL of the bucket, either in the virtual hosted style or the path style for the custom endpoint.
+	baseURL      *url.URL
+	prefix       string
+	region       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	client       *http.Client
+}
+
+func newS3Store(u *url.URL) (*S3Store, error) {
+	bucket := u.Host
+	if bucket == "" {
+		return nil, fmt.Errorf("blob: missing bucket in S3 store URL")
+	}
+	region := u.Query().Get("region")
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	var baseURL *url.URL
+	if endpoint := u.Query().Get("endpoint"); endpoint != "" {
+		e, err := url.Parse(endpoint)
+		if err != nil || e.Host == "" {
+			return nil, fmt.Errorf("blob: invalid S3 endpoint %q", endpoint)
+		}
+		baseURL = &url.URL{Scheme: e.Scheme, Host: e.Host, Path: "/" + bucket}
+	} else {
+		baseURL = &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket, region)}
+	}
+
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("blob: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for the S3 store")
+	}
+	return &S3Store{
+		baseURL:      baseURL,
+		prefix:       strings.Trim(u.Path, "/"),
+		region:       region,
+		accessKey:    accessKey,
+		secretKey:    secretKey,
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		client:       &http.Client{Timeout: s3RequestTimeout},
+	}, nil
+}
+
+// Put uploads the object.
+func (s *S3Store) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, content, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(http.MethodPut, key, resp)
+	}
+	return nil
+}
+
+// Get downloads the object.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error(http.MethodGet, key, resp)
+	}
+}
+
+// Delete deletes the object, S3 returns 204 even if the object doesn't exist.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error(http.MethodDelete, key, resp)
+	}
+	return nil
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, content []byte, contentType string) (*http.Response, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	u := *s.baseURL
+	u.Path = path.Join("/", u.Path, s.prefix, key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("blob: failed to construct S3 request for %q, error: %w", key, err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	signV4(req, content, s.accessKey, s.secretKey, s.region, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("blob: failed to %s S3 object %q, error: %w", method, key, err)
+	}
+	return resp, nil
+}
+
+func s3Error(method, key string, resp *http.Response) error {
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("blob: failed to read S3 response of %s %q, error: %w", method, key, err)
+	}
+	return fmt.Errorf("blob: failed to %s S3 object %q, status code: %d, response body: %s", method, key, resp.StatusCode, b)
+}
+
+// signV4 signs the request with AWS Signature Version 4, all the headers set on the request are signed.
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func signV4(req *http.Request, content []byte, accessKey, secretKey, region string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format(s3AmzDateFormat)
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", t.Format(s3ScopeDateFormat), region, s3Service)
+	payloadHash := sha256Hex(content)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapeURIPath(req.URL.Path),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		s3SigningAlgo,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), t.Format(s3ScopeDateFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", s3SigningAlgo, accessKey, scope, signedHeaders, signature))
+}
+
+// escapeURIPath escapes everything except the unreserved characters and the slashes as required by the canonical request.
+func escapeURIPath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(content []byte) string {
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
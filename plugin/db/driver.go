@@ -19,6 +19,12 @@ type Type string
 const (
 	// ClickHouse is the database type for CLICKHOUSE.
 	ClickHouse Type = "CLICKHOUSE"
+	// MariaDB is the database type for MARIADB.
+	MariaDB Type = "MARIADB"
+	// MongoDB is the database type for MONGODB.
+	MongoDB Type = "MONGODB"
+	// MSSQL is the database type for Microsoft SQL Server.
+	MSSQL Type = "MSSQL"
 	// MySQL is the database type for MYSQL.
 	MySQL Type = "MYSQL"
 	// Postgres is the database type for POSTGRES.
@@ -58,6 +64,52 @@ type Extension struct {
 	Description string
 }
 
+// Function is the database function or stored procedure.
+type Function struct {
+	// Name is the name of the function without the schema, which is the same for the overloaded functions.
+	Name   string
+	Schema string
+	// Arguments is the argument signature identifying the function among the overloaded ones, e.g. "a integer, b text".
+	Arguments string
+	// Type is either FUNCTION or PROCEDURE.
+	Type       string
+	Language   string
+	Definition string
+}
+
+// Sequence is the database sequence.
+type Sequence struct {
+	// Name has the same format as the Table name, e.g. "public.user_id_seq".
+	Name      string
+	DataType  string
+	Start     int64
+	Increment int64
+	MinValue  int64
+	MaxValue  int64
+	Cache     int64
+	Cycle     bool
+	// OwnerTable and OwnerColumn are the column owning the sequence, e.g. a serial or identity column.
+	// They are empty for the standalone sequences.
+	OwnerTable  string
+	OwnerColumn string
+}
+
+// Trigger is the table trigger.
+type Trigger struct {
+	Name string
+	// Timing is BEFORE, AFTER or INSTEAD OF.
+	Timing string
+	// EventList is the events firing the trigger, e.g. ["INSERT", "UPDATE"].
+	EventList []string
+	// Level is ROW or STATEMENT.
+	Level string
+	// Function is the function executed by the trigger, e.g. "public.audit".
+	Function string
+	Enabled  bool
+	// Definition is the CREATE TRIGGER statement.
+	Definition string
+}
+
 // Index is the database index.
 type Index struct {
 	Name string
@@ -74,6 +126,21 @@ type Index struct {
 	Comment string
 }
 
+// ForeignKey is the foreign key of a table, one entry per column in the key.
+type ForeignKey struct {
+	Name string
+	// Column is the referencing column.
+	Column   string
+	Position int
+	// ReferencedTable has the same format as the Table name, and it's qualified by the database name
+	// if the referenced table is in another database.
+	ReferencedTable  string
+	ReferencedColumn string
+	// OnDelete and OnUpdate are the referential actions, e.g. CASCADE, SET NULL or NO ACTION.
+	OnDelete string
+	OnUpdate string
+}
+
 // Column the database table column.
 type Column struct {
 	Name     string
@@ -97,7 +164,8 @@ type Table struct {
 	CreatedTs int64
 	// UpdatedTs isn't supported for SQLite.
 	UpdatedTs int64
-	Type      string
+	// Type is SYSTEM VERSIONED for the MariaDB system-versioned tables, which are the base tables keeping the row history.
+	Type string
 	// Engine isn't supported for Postgres, Snowflake, SQLite.
 	Engine string
 	// Collation isn't supported for Postgres, ClickHouse, Snowflake, SQLite.
@@ -112,17 +180,44 @@ type Table struct {
 	// CreateOptions isn't supported for Postgres, ClickHouse, Snowflake, SQLite.
 	CreateOptions string
 	// Comment isn't supported for SQLite.
-	Comment    string
+	Comment string
+	// Owner is only supported for Postgres.
+	Owner      string
 	ColumnList []Column
 	// IndexList isn't supported for ClickHouse, Snowflake.
 	IndexList []Index
+	// ForeignKeyList isn't supported for ClickHouse, Snowflake, SQLite.
+	ForeignKeyList []ForeignKey
+	// TriggerList is only supported for Postgres.
+	TriggerList []Trigger
+	// PartitionKey is the partition key of the partitioned table, e.g. "RANGE (created_ts)".
+	// It's only supported for Postgres.
+	PartitionKey string
+	// ParentTable and PartitionBound are only set for the partitions, e.g. "public.event" and
+	// "FOR VALUES FROM ('2022-01-01') TO ('2022-02-01')". They're only supported for Postgres.
+	ParentTable    string
+	PartitionBound string
+}
+
+// Parameter is an instance configuration parameter, e.g. a Postgres GUC or a MySQL system variable.
+type Parameter struct {
+	Name  string
+	Value string
 }
 
 // InstanceMeta is the metadata for an instance.
 type InstanceMeta struct {
-	Version      string
-	UserList     []User
-	DatabaseList []DatabaseMeta
+	// Engine is the engine detected from the instance if it differs from the one the driver is opened with,
+	// e.g. MariaDB added as a MySQL instance. It's empty otherwise.
+	Engine Type
+	// Dialect is the server speaking the protocol of the engine, e.g. Redshift opened by the Postgres driver.
+	// It's empty for the engine itself.
+	Dialect  Dialect
+	Version  string
+	UserList []User
+	// ParameterList is the key configuration parameters, it isn't supported for ClickHouse, Snowflake, SQLite.
+	ParameterList []Parameter
+	DatabaseList  []DatabaseMeta
 }
 
 // DatabaseMeta is the metadata for a database.
@@ -144,6 +239,10 @@ type Schema struct {
 	TableList     []Table
 	ViewList      []View
 	ExtensionList []Extension
+	// FunctionList isn't supported for the databases other than Postgres.
+	FunctionList []Function
+	// SequenceList is only supported for Postgres and MariaDB.
+	SequenceList []Sequence
 }
 
 var (
@@ -170,6 +269,10 @@ const (
 	VCS MigrationSource = "VCS"
 	// LIBRARY is the migration source type for LIBRARY.
 	LIBRARY MigrationSource = "LIBRARY"
+	// FLYWAY is the migration source type for the history imported from Flyway.
+	FLYWAY MigrationSource = "FLYWAY"
+	// LIQUIBASE is the migration source type for the history imported from Liquibase.
+	LIQUIBASE MigrationSource = "LIQUIBASE"
 )
 
 // MigrationType is the type of a migration.
@@ -207,6 +310,8 @@ const (
 // MigrationInfoPayload is the API message for migration info payload.
 type MigrationInfoPayload struct {
 	VCSPushEvent *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// DownStatement is the optional statement reverting the migration, which is applied by the revert issue.
+	DownStatement string `json:"downStatement,omitempty"`
 }
 
 // MigrationInfo is the API message for migration info.
@@ -382,6 +487,82 @@ type ConnectionContext struct {
 	InstanceName    string
 }
 
+type exactRowCountContextKey struct{}
+
+// WithExactRowCount returns a copy of ctx requesting SyncDBSchema to count the rows of every table exactly,
+// instead of estimating the row counts of the large tables from the statistics.
+func WithExactRowCount(ctx context.Context) context.Context {
+	return context.WithValue(ctx, exactRowCountContextKey{}, true)
+}
+
+// IsExactRowCount returns whether ctx requests the exact row counts.
+func IsExactRowCount(ctx context.Context) bool {
+	exact, _ := ctx.Value(exactRowCountContextKey{}).(bool)
+	return exact
+}
+
+// Capabilities is the features of an engine which the server and the bb CLI gate on, since not every engine supports them.
+type Capabilities struct {
+	// TransactionalDDL is whether the DDL statements run in a transaction, so a failed migration leaves no partial change.
+	TransactionalDDL bool `json:"transactionalDDL"`
+	// Dump is whether the driver dumps the database, which the backups are taken by.
+	Dump bool `json:"dump"`
+	// Restore is whether the driver restores the database from a dump.
+	Restore bool `json:"restore"`
+	// ReadOnlyQuery is whether the driver runs the read-only queries of the SQL editor.
+	ReadOnlyQuery bool `json:"readOnlyQuery"`
+	// OnlineSchemaChange is whether the schema is changed online with gh-ost.
+	OnlineSchemaChange bool `json:"onlineSchemaChange"`
+}
+
+var capabilitiesMap = map[Type]Capabilities{
+	ClickHouse: {Dump: true, Restore: true, ReadOnlyQuery: true},
+	MariaDB:    {Dump: true, Restore: true, ReadOnlyQuery: true},
+	// MongoDB runs the scripts with mongosh, which doesn't return the result set.
+	MongoDB:  {Dump: true, Restore: true},
+	MSSQL:    {TransactionalDDL: true, Dump: true, Restore: true, ReadOnlyQuery: true},
+	MySQL:    {Dump: true, Restore: true, ReadOnlyQuery: true, OnlineSchemaChange: true},
+	Postgres: {TransactionalDDL: true, Dump: true, Restore: true, ReadOnlyQuery: true},
+	// Snowflake commits the transaction implicitly before and after a DDL statement.
+	Snowflake: {Dump: true, Restore: true, ReadOnlyQuery: true},
+	SQLite:    {TransactionalDDL: true, Dump: true, Restore: true, ReadOnlyQuery: true},
+	// gh-ost relies on the MySQL binlog format, which TiDB doesn't provide.
+	TiDB: {Dump: true, Restore: true, ReadOnlyQuery: true},
+}
+
+// Dialect is the server speaking the wire protocol of another engine, which supports fewer features than the engine.
+type Dialect string
+
+const (
+	// DialectRedshift is Amazon Redshift opened by the Postgres driver.
+	DialectRedshift Dialect = "REDSHIFT"
+	// DialectCockroachDB is CockroachDB opened by the Postgres driver.
+	DialectCockroachDB Dialect = "COCKROACHDB"
+)
+
+// GetCapabilities returns the capabilities of the engine. It's the static view by the engine type, GetDialectCapabilities
+// narrows it down by the dialect of the server, e.g. CockroachDB opened by the Postgres driver.
+func GetCapabilities(dbType Type) Capabilities {
+	return capabilitiesMap[dbType]
+}
+
+// GetDialectCapabilities returns the capabilities of the engine narrowed down by the dialect, the empty dialect is the engine itself.
+func GetDialectCapabilities(dbType Type, dialect Dialect) Capabilities {
+	capabilities := GetCapabilities(dbType)
+	switch dialect {
+	case DialectRedshift:
+		// pg_dump fails on the Redshift catalogs.
+		capabilities.Dump = false
+		capabilities.Restore = false
+	case DialectCockroachDB:
+		// CockroachDB runs the schema changes asynchronously after the commit, and pg_dump fails on its catalogs.
+		capabilities.TransactionalDDL = false
+		capabilities.Dump = false
+		capabilities.Restore = false
+	}
+	return capabilities
+}
+
 // Driver is the interface for database driver.
 type Driver interface {
 	// General execution
@@ -424,6 +605,10 @@ type Driver interface {
 	Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error)
 	// Restore the database from src, which is a full backup.
 	Restore(ctx context.Context, src io.Reader) error
+
+	// Capabilities returns the features the engine supports, the callers disable the unsupported ones instead of failing midway.
+	// It takes the dialect into account once the dialect is detected, e.g. by SyncInstance.
+	Capabilities() Capabilities
 }
 
 // Register makes a database driver available by the provided type.
@@ -239,3 +239,29 @@ func TestParseMigrationInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCapabilities(t *testing.T) {
+	require.True(t, GetCapabilities(MySQL).OnlineSchemaChange)
+	require.False(t, GetCapabilities(TiDB).OnlineSchemaChange)
+	require.False(t, GetCapabilities(MySQL).TransactionalDDL)
+	require.True(t, GetCapabilities(Postgres).TransactionalDDL)
+	require.False(t, GetCapabilities(MongoDB).ReadOnlyQuery)
+	// The unknown engine supports nothing.
+	require.Equal(t, Capabilities{}, GetCapabilities(Type("UNKNOWN")))
+}
+
+func TestGetDialectCapabilities(t *testing.T) {
+	require.Equal(t, GetCapabilities(Postgres), GetDialectCapabilities(Postgres, ""))
+
+	redshift := GetDialectCapabilities(Postgres, DialectRedshift)
+	require.True(t, redshift.TransactionalDDL)
+	require.False(t, redshift.Dump)
+	require.False(t, redshift.Restore)
+	require.True(t, redshift.ReadOnlyQuery)
+
+	cockroach := GetDialectCapabilities(Postgres, DialectCockroachDB)
+	require.False(t, cockroach.TransactionalDDL)
+	require.False(t, cockroach.Dump)
+	require.False(t, cockroach.Restore)
+	require.True(t, cockroach.ReadOnlyQuery)
+}
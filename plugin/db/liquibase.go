@@ -0,0 +1,263 @@
+package db
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// liquibaseChangeSet is a change set in the Liquibase changelog.
+// Only the raw SQL changes are supported, since translating the structured changes such as "createTable" depends on the database type.
+type liquibaseChangeSet struct {
+	id           string
+	author       string
+	comment      string
+	sqlList      []string
+	rollbackList []string
+}
+
+type liquibaseXMLChangelog struct {
+	XMLName        xml.Name                `xml:"databaseChangeLog"`
+	ChangeSetList  []liquibaseXMLChangeSet `xml:"changeSet"`
+	IncludeList    []xml.Name              `xml:"include"`
+	IncludeAllList []xml.Name              `xml:"includeAll"`
+}
+
+type liquibaseXMLChangeSet struct {
+	ID         string               `xml:"id,attr"`
+	Author     string               `xml:"author,attr"`
+	Comment    string               `xml:"comment"`
+	ChangeList []liquibaseXMLChange `xml:",any"`
+}
+
+type liquibaseXMLChange struct {
+	XMLName xml.Name
+	Text    string `xml:",chardata"`
+	// ChangeList is the nested changes, e.g. the "sql" changes in the "rollback".
+	ChangeList []liquibaseXMLChange `xml:",any"`
+}
+
+// liquibaseIgnoredElements are the change set elements not changing the schema.
+var liquibaseIgnoredElements = map[string]bool{
+	"comment":       true,
+	"preConditions": true,
+	"validCheckSum": true,
+	"tagDatabase":   true,
+}
+
+// parseLiquibaseChangelog parses the change sets from the Liquibase XML or YAML changelog.
+func parseLiquibaseChangelog(filePath string, content string) ([]*liquibaseChangeSet, error) {
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".xml":
+		return parseLiquibaseXMLChangelog(content)
+	case ".yaml", ".yml":
+		return parseLiquibaseYAMLChangelog(content)
+	}
+	return nil, fmt.Errorf("file %q is not a Liquibase XML or YAML changelog", filePath)
+}
+
+func parseLiquibaseXMLChangelog(content string) ([]*liquibaseChangeSet, error) {
+	var changelog liquibaseXMLChangelog
+	if err := xml.Unmarshal([]byte(content), &changelog); err != nil {
+		return nil, fmt.Errorf("invalid Liquibase XML changelog, error: %w", err)
+	}
+	if len(changelog.IncludeList) > 0 || len(changelog.IncludeAllList) > 0 {
+		return nil, fmt.Errorf("the Liquibase master changelog including other changelogs is not a migration")
+	}
+
+	var changeSetList []*liquibaseChangeSet
+	for _, xmlChangeSet := range changelog.ChangeSetList {
+		changeSet := &liquibaseChangeSet{
+			id:      xmlChangeSet.ID,
+			author:  xmlChangeSet.Author,
+			comment: strings.TrimSpace(xmlChangeSet.Comment),
+		}
+		for _, change := range xmlChangeSet.ChangeList {
+			if liquibaseIgnoredElements[change.XMLName.Local] {
+				continue
+			}
+			if change.XMLName.Local == "rollback" {
+				// Both "<rollback>SQL</rollback>" and "<rollback><sql>SQL</sql></rollback>" are accepted.
+				if sql := strings.TrimSpace(change.Text); sql != "" {
+					changeSet.rollbackList = append(changeSet.rollbackList, sql)
+				}
+				for _, rollbackChange := range change.ChangeList {
+					if rollbackChange.XMLName.Local != "sql" {
+						return nil, fmt.Errorf("change set %q of the Liquibase changelog contains unsupported rollback change %q, only \"sql\" is supported", changeSet.id, rollbackChange.XMLName.Local)
+					}
+					changeSet.rollbackList = append(changeSet.rollbackList, rollbackChange.Text)
+				}
+				continue
+			}
+			if change.XMLName.Local != "sql" {
+				return nil, fmt.Errorf("change set %q of the Liquibase changelog contains unsupported change %q, only \"sql\" is supported", changeSet.id, change.XMLName.Local)
+			}
+			changeSet.sqlList = append(changeSet.sqlList, change.Text)
+		}
+		changeSetList = append(changeSetList, changeSet)
+	}
+	return changeSetList, nil
+}
+
+func parseLiquibaseYAMLChangelog(content string) ([]*liquibaseChangeSet, error) {
+	var changelog struct {
+		DatabaseChangeLog []map[string]interface{} `yaml:"databaseChangeLog"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &changelog); err != nil {
+		return nil, fmt.Errorf("invalid Liquibase YAML changelog, error: %w", err)
+	}
+
+	var changeSetList []*liquibaseChangeSet
+	for _, item := range changelog.DatabaseChangeLog {
+		if _, ok := item["include"]; ok {
+			return nil, fmt.Errorf("the Liquibase master changelog including other changelogs is not a migration")
+		}
+		if _, ok := item["includeAll"]; ok {
+			return nil, fmt.Errorf("the Liquibase master changelog including other changelogs is not a migration")
+		}
+		yamlChangeSet, ok := item["changeSet"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		changeSet := &liquibaseChangeSet{
+			id:      yamlString(yamlChangeSet["id"]),
+			author:  yamlString(yamlChangeSet["author"]),
+			comment: strings.TrimSpace(yamlString(yamlChangeSet["comment"])),
+		}
+		changeList, _ := yamlChangeSet["changes"].([]interface{})
+		sqlList, err := yamlSQLChangeList(changeSet.id, changeList)
+		if err != nil {
+			return nil, err
+		}
+		changeSet.sqlList = sqlList
+		switch rollback := yamlChangeSet["rollback"].(type) {
+		case nil:
+		case string:
+			changeSet.rollbackList = []string{rollback}
+		case map[string]interface{}:
+			rollbackList, err := yamlSQLChangeList(changeSet.id, []interface{}{rollback})
+			if err != nil {
+				return nil, err
+			}
+			changeSet.rollbackList = rollbackList
+		case []interface{}:
+			rollbackList, err := yamlSQLChangeList(changeSet.id, rollback)
+			if err != nil {
+				return nil, err
+			}
+			changeSet.rollbackList = rollbackList
+		default:
+			return nil, fmt.Errorf("change set %q of the Liquibase changelog contains malformed rollback", changeSet.id)
+		}
+		changeSetList = append(changeSetList, changeSet)
+	}
+	return changeSetList, nil
+}
+
+// yamlSQLChangeList returns the SQL of the changes, both "sql: {sql: ...}" and the shorthand "sql: ..." are accepted.
+func yamlSQLChangeList(changeSetID string, changeList []interface{}) ([]string, error) {
+	var sqlList []string
+	for _, change := range changeList {
+		changeMap, ok := change.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("change set %q of the Liquibase changelog contains malformed change", changeSetID)
+		}
+		for changeType, value := range changeMap {
+			if changeType != "sql" {
+				return nil, fmt.Errorf("change set %q of the Liquibase changelog contains unsupported change %q, only \"sql\" is supported", changeSetID, changeType)
+			}
+			if valueMap, ok := value.(map[string]interface{}); ok {
+				value = valueMap["sql"]
+			}
+			sqlList = append(sqlList, yamlString(value))
+		}
+	}
+	return sqlList, nil
+}
+
+// parseLiquibaseFormattedSQLRollback parses the change sets with the "--rollback" comments from the Liquibase formatted SQL changelog.
+func parseLiquibaseFormattedSQLRollback(content string) []*liquibaseChangeSet {
+	var changeSetList []*liquibaseChangeSet
+	var changeSet *liquibaseChangeSet
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "--") {
+			continue
+		}
+		directive := strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		if strings.HasPrefix(directive, "changeset ") {
+			changeSet = &liquibaseChangeSet{}
+			fields := strings.Fields(strings.TrimPrefix(directive, "changeset "))
+			if len(fields) > 0 {
+				// The change set is declared as "--changeset author:id".
+				authorID := strings.SplitN(fields[0], ":", 2)
+				changeSet.author = authorID[0]
+				if len(authorID) == 2 {
+					changeSet.id = authorID[1]
+				}
+			}
+			changeSetList = append(changeSetList, changeSet)
+			continue
+		}
+		if changeSet != nil && strings.HasPrefix(directive, "rollback ") {
+			changeSet.rollbackList = append(changeSet.rollbackList, strings.TrimPrefix(directive, "rollback "))
+		}
+	}
+	return changeSetList
+}
+
+// formatLiquibaseChangeSetList formats the change sets into the statement with the change set comments like the Liquibase formatted SQL.
+func formatLiquibaseChangeSetList(changeSetList []*liquibaseChangeSet) (string, error) {
+	var buf strings.Builder
+	for _, changeSet := range changeSetList {
+		writeLiquibaseChangeSet(&buf, changeSet, changeSet.sqlList)
+	}
+	if buf.Len() == 0 {
+		return "", fmt.Errorf("the Liquibase changelog contains no SQL change")
+	}
+	return buf.String(), nil
+}
+
+// formatLiquibaseRollbackList formats the rollbacks of the change sets in the reverse order, which is how Liquibase rolls back the changelog.
+// It returns empty if no change set has the rollback.
+func formatLiquibaseRollbackList(changeSetList []*liquibaseChangeSet) string {
+	var buf strings.Builder
+	for i := len(changeSetList) - 1; i >= 0; i-- {
+		writeLiquibaseChangeSet(&buf, changeSetList[i], changeSetList[i].rollbackList)
+	}
+	return buf.String()
+}
+
+func writeLiquibaseChangeSet(buf *strings.Builder, changeSet *liquibaseChangeSet, sqlList []string) {
+	if len(sqlList) == 0 {
+		return
+	}
+	if buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	fmt.Fprintf(buf, "-- changeset %s:%s\n", changeSet.author, changeSet.id)
+	if changeSet.comment != "" {
+		fmt.Fprintf(buf, "-- comment: %s\n", strings.Join(strings.Fields(changeSet.comment), " "))
+	}
+	for _, sql := range sqlList {
+		sql = strings.TrimSpace(sql)
+		if sql == "" {
+			continue
+		}
+		buf.WriteString(sql)
+		if !strings.HasSuffix(sql, ";") {
+			buf.WriteString(";")
+		}
+		buf.WriteString("\n")
+	}
+}
+
+func yamlString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
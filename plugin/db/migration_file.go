@@ -0,0 +1,213 @@
+package db
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// MigrationFileFormat is the naming convention and the content format of the migration files in the repository.
+type MigrationFileFormat string
+
+const (
+	// MigrationFileFormatBytebase is the Bytebase format, the file path template declares where the version, the type and the description are.
+	MigrationFileFormatBytebase MigrationFileFormat = "BYTEBASE"
+	// MigrationFileFormatFlyway is the Flyway format, e.g. V1_1__add_column.sql and B1__baseline.sql.
+	MigrationFileFormatFlyway MigrationFileFormat = "FLYWAY"
+	// MigrationFileFormatLiquibase is the Liquibase format, the changelog files in XML, YAML or formatted SQL.
+	MigrationFileFormatLiquibase MigrationFileFormat = "LIQUIBASE"
+	// MigrationFileFormatGolangMigrate is the golang-migrate format, e.g. 000001_add_column.up.sql paired with 000001_add_column.down.sql.
+	MigrationFileFormatGolangMigrate MigrationFileFormat = "GOLANG_MIGRATE"
+)
+
+var (
+	flywayFileNameRegexp        = regexp.MustCompile(`^([VBU])([0-9][0-9._]*)__(.+)\.sql$`)
+	flywayRepeatableRegexp      = regexp.MustCompile(`^R__(.+)\.sql$`)
+	golangMigrateFileNameRegexp = regexp.MustCompile(`^([0-9]+)_(.+)\.(up|down)\.sql$`)
+	liquibaseFileNameRegexp     = regexp.MustCompile(`^(.+)\.(xml|yaml|yml|sql)$`)
+)
+
+// ParseMigrationFile derives the MigrationInfo from the file path in the format.
+// For the Bytebase format, the file path is matched against the file path template, see ParseMigrationInfo.
+// For the other formats, the file path template only matches the directory containing the migration files,
+// e.g. "{{ENV_NAME}}/{{DB_NAME}}", and the version, the type and the description are derived from the file name by the format convention.
+func ParseMigrationFile(format MigrationFileFormat, filePath string, filePathTemplate string) (*MigrationInfo, error) {
+	if format == "" || format == MigrationFileFormatBytebase {
+		return ParseMigrationInfo(filePath, filePathTemplate)
+	}
+
+	dir, fileName := path.Split(filePath)
+	mi, err := parseMigrationDirectory(strings.TrimSuffix(dir, "/"), filePathTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("file path %q does not match file path template %q, error: %w", filePath, filePathTemplate, err)
+	}
+
+	switch format {
+	case MigrationFileFormatFlyway:
+		if flywayRepeatableRegexp.MatchString(fileName) {
+			return nil, fmt.Errorf("file path %q is a Flyway repeatable migration, which is not supported", filePath)
+		}
+		matches := flywayFileNameRegexp.FindStringSubmatch(fileName)
+		if matches == nil {
+			return nil, fmt.Errorf("file path %q does not follow the Flyway naming convention, e.g. V1__description.sql", filePath)
+		}
+		switch matches[1] {
+		case "V":
+			mi.Type = Migrate
+		case "B":
+			mi.Type = Baseline
+		case "U":
+			return nil, fmt.Errorf("file path %q is a Flyway undo migration, which is not applied directly", filePath)
+		}
+		// Flyway allows "_" as the version separator, e.g. V1_1__description.sql is version 1.1.
+		mi.Version = normalizeNumericVersion(strings.ReplaceAll(matches[2], "_", "."))
+		mi.Description = formatMigrationDescription(matches[3])
+	case MigrationFileFormatGolangMigrate:
+		matches := golangMigrateFileNameRegexp.FindStringSubmatch(fileName)
+		if matches == nil {
+			return nil, fmt.Errorf("file path %q does not follow the golang-migrate naming convention, e.g. 1_description.up.sql", filePath)
+		}
+		if matches[3] == "down" {
+			return nil, fmt.Errorf("file path %q is a golang-migrate down migration, which is not applied directly", filePath)
+		}
+		mi.Type = Migrate
+		mi.Version = normalizeNumericVersion(matches[1])
+		mi.Description = formatMigrationDescription(matches[2])
+	case MigrationFileFormatLiquibase:
+		matches := liquibaseFileNameRegexp.FindStringSubmatch(fileName)
+		if matches == nil {
+			return nil, fmt.Errorf("file path %q is not a Liquibase changelog in XML, YAML or formatted SQL", filePath)
+		}
+		// Liquibase applies the changelogs included by "includeAll" in the alphabetical order of the file names,
+		// which is the same as the version order.
+		mi.Type = Migrate
+		mi.Version = matches[1]
+		mi.Description = fmt.Sprintf("Apply Liquibase changelog %s", fileName)
+	default:
+		return nil, fmt.Errorf("unsupported migration file format %q", format)
+	}
+	return mi, nil
+}
+
+// ParseMigrationStatement returns the SQL statement to apply from the content of the migration file.
+// Only the Liquibase XML and YAML changelogs need to be converted, the other files are SQL already.
+func ParseMigrationStatement(format MigrationFileFormat, filePath string, content string) (string, error) {
+	if format != MigrationFileFormatLiquibase {
+		return content, nil
+	}
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".xml", ".yaml", ".yml":
+		changeSetList, err := parseLiquibaseChangelog(filePath, content)
+		if err != nil {
+			return "", err
+		}
+		return formatLiquibaseChangeSetList(changeSetList)
+	}
+	return content, nil
+}
+
+// ParseMigrationDownStatement returns the down statement reverting the migration declared in the content of the migration file.
+// Only the Liquibase changelogs declare the rollback in the migration file, the down statements of the other formats are
+// in the separate files, see MigrationDownFilePath. It returns empty if the migration file has no down statement.
+func ParseMigrationDownStatement(format MigrationFileFormat, filePath string, content string) (string, error) {
+	if format != MigrationFileFormatLiquibase {
+		return "", nil
+	}
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".xml", ".yaml", ".yml":
+		changeSetList, err := parseLiquibaseChangelog(filePath, content)
+		if err != nil {
+			return "", err
+		}
+		return formatLiquibaseRollbackList(changeSetList), nil
+	}
+	return formatLiquibaseRollbackList(parseLiquibaseFormattedSQLRollback(content)), nil
+}
+
+// IsMigrationDownFile returns whether the file is a down migration file paired with a migration file,
+// e.g. the Flyway undo migration U1__add_column.sql and the golang-migrate 1_add_column.down.sql.
+// The down migration files are not applied directly, but read as the down statement of the paired migration file.
+func IsMigrationDownFile(format MigrationFileFormat, filePath string) bool {
+	fileName := path.Base(filePath)
+	switch format {
+	case MigrationFileFormatFlyway:
+		matches := flywayFileNameRegexp.FindStringSubmatch(fileName)
+		return matches != nil && matches[1] == "U"
+	case MigrationFileFormatGolangMigrate:
+		matches := golangMigrateFileNameRegexp.FindStringSubmatch(fileName)
+		return matches != nil && matches[3] == "down"
+	}
+	return false
+}
+
+// MigrationDownFilePath returns the path of the down migration file paired with the migration file,
+// e.g. U1__add_column.sql for the Flyway versioned migration V1__add_column.sql.
+// It returns empty if the format doesn't pair the migration with a down migration file.
+func MigrationDownFilePath(format MigrationFileFormat, filePath string) string {
+	dir, fileName := path.Split(filePath)
+	switch format {
+	case MigrationFileFormatFlyway:
+		if matches := flywayFileNameRegexp.FindStringSubmatch(fileName); matches != nil && matches[1] == "V" {
+			return dir + "U" + strings.TrimPrefix(fileName, "V")
+		}
+	case MigrationFileFormatGolangMigrate:
+		if matches := golangMigrateFileNameRegexp.FindStringSubmatch(fileName); matches != nil && matches[3] == "up" {
+			return dir + strings.TrimSuffix(fileName, ".up.sql") + ".down.sql"
+		}
+	}
+	return ""
+}
+
+// parseMigrationDirectory matches the directory against the directory template containing {{DB_NAME}} and optionally {{ENV_NAME}}.
+func parseMigrationDirectory(dir string, dirTemplate string) (*MigrationInfo, error) {
+	placeholderList := []string{
+		"ENV_NAME",
+		"DB_NAME",
+	}
+	dirRegex := regexp.QuoteMeta(strings.TrimSuffix(dirTemplate, "/"))
+	for _, placeholder := range placeholderList {
+		dirRegex = strings.ReplaceAll(dirRegex, regexp.QuoteMeta(fmt.Sprintf("{{%s}}", placeholder)), fmt.Sprintf("(?P<%s>[a-zA-Z0-9+-=_#?!$. ]+)", placeholder))
+	}
+	myRegex, err := regexp.Compile("^" + dirRegex + "$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid file path template: %q", dirTemplate)
+	}
+	matchList := myRegex.FindStringSubmatch(dir)
+	if matchList == nil {
+		return nil, fmt.Errorf("directory %q does not match %q", dir, dirTemplate)
+	}
+
+	mi := &MigrationInfo{
+		Source: VCS,
+	}
+	if index := myRegex.SubexpIndex("ENV_NAME"); index >= 0 {
+		mi.Environment = matchList[index]
+	}
+	if index := myRegex.SubexpIndex("DB_NAME"); index >= 0 {
+		mi.Namespace = matchList[index]
+		mi.Database = matchList[index]
+	}
+	if mi.Namespace == "" {
+		return nil, fmt.Errorf("directory %q does not contain {{DB_NAME}}", dir)
+	}
+	return mi, nil
+}
+
+// normalizeNumericVersion left pads the numeric parts of the version with zeros,
+// so the versions are compared in the numeric order, e.g. "1.10" becomes "0001.0010" which is after "0001.0009".
+func normalizeNumericVersion(version string) string {
+	partList := strings.Split(version, ".")
+	for i, part := range partList {
+		if part != "" && len(part) < 4 && strings.Trim(part, "0123456789") == "" {
+			partList[i] = strings.Repeat("0", 4-len(part)) + part
+		}
+	}
+	return strings.Join(partList, ".")
+}
+
+// formatMigrationDescription replaces "_" with space and capitalizes the first letter.
+func formatMigrationDescription(description string) string {
+	description = strings.ReplaceAll(description, "_", " ")
+	return strings.ToUpper(description[:1]) + description[1:]
+}
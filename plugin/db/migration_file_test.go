@@ -0,0 +1,249 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMigrationFile(t *testing.T) {
+	type test struct {
+		format           MigrationFileFormat
+		filePath         string
+		filePathTemplate string
+		want             MigrationInfo
+		wantErr          string
+	}
+
+	tests := []test{
+		{
+			format:           MigrationFileFormatFlyway,
+			filePath:         "bytebase/dev/db1/V1_10__add_column.sql",
+			filePathTemplate: "bytebase/{{ENV_NAME}}/{{DB_NAME}}",
+			want: MigrationInfo{
+				Version:     "0001.0010",
+				Namespace:   "db1",
+				Database:    "db1",
+				Environment: "dev",
+				Source:      VCS,
+				Type:        Migrate,
+				Description: "Add column",
+			},
+		},
+		{
+			format:           MigrationFileFormatFlyway,
+			filePath:         "db1/B2__init.sql",
+			filePathTemplate: "{{DB_NAME}}",
+			want: MigrationInfo{
+				Version:     "0002",
+				Namespace:   "db1",
+				Database:    "db1",
+				Source:      VCS,
+				Type:        Baseline,
+				Description: "Init",
+			},
+		},
+		{
+			format:           MigrationFileFormatFlyway,
+			filePath:         "db1/U2__init.sql",
+			filePathTemplate: "{{DB_NAME}}",
+			wantErr:          "undo migration",
+		},
+		{
+			format:           MigrationFileFormatFlyway,
+			filePath:         "db1/R__views.sql",
+			filePathTemplate: "{{DB_NAME}}",
+			wantErr:          "repeatable migration",
+		},
+		{
+			format:           MigrationFileFormatGolangMigrate,
+			filePath:         "migrations/db1/20220501120000_create_users.up.sql",
+			filePathTemplate: "migrations/{{DB_NAME}}",
+			want: MigrationInfo{
+				Version:     "20220501120000",
+				Namespace:   "db1",
+				Database:    "db1",
+				Source:      VCS,
+				Type:        Migrate,
+				Description: "Create users",
+			},
+		},
+		{
+			format:           MigrationFileFormatGolangMigrate,
+			filePath:         "migrations/db1/20220501120000_create_users.down.sql",
+			filePathTemplate: "migrations/{{DB_NAME}}",
+			wantErr:          "down migration",
+		},
+		{
+			format:           MigrationFileFormatLiquibase,
+			filePath:         "db1/changelog-001-users.xml",
+			filePathTemplate: "{{DB_NAME}}",
+			want: MigrationInfo{
+				Version:     "changelog-001-users",
+				Namespace:   "db1",
+				Database:    "db1",
+				Source:      VCS,
+				Type:        Migrate,
+				Description: "Apply Liquibase changelog changelog-001-users.xml",
+			},
+		},
+		{
+			format:           MigrationFileFormatLiquibase,
+			filePath:         "other/db1/changelog-001-users.xml",
+			filePathTemplate: "{{ENV_NAME}}",
+			wantErr:          "does not match file path template",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.filePath, func(t *testing.T) {
+			mi, err := ParseMigrationFile(tc.format, tc.filePath, tc.filePathTemplate)
+			if tc.wantErr != "" {
+				got := fmt.Sprintf("%v", err)
+				require.Contains(t, got, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, *mi)
+		})
+	}
+}
+
+func TestParseMigrationStatement(t *testing.T) {
+	xmlChangelog := `<?xml version="1.0" encoding="UTF-8"?>
+<databaseChangeLog xmlns="http://www.liquibase.org/xml/ns/dbchangelog">
+  <changeSet id="1" author="alice">
+    <comment>Create the users table</comment>
+    <sql><![CDATA[CREATE TABLE users (id INT, name TEXT)]]></sql>
+    <rollback>DROP TABLE users</rollback>
+  </changeSet>
+  <changeSet id="2" author="bob">
+    <sql>ALTER TABLE users ADD email TEXT;</sql>
+  </changeSet>
+</databaseChangeLog>`
+	yamlChangelog := `databaseChangeLog:
+  - changeSet:
+      id: 1
+      author: alice
+      comment: Create the users table
+      changes:
+        - sql:
+            sql: CREATE TABLE users (id INT, name TEXT)
+  - changeSet:
+      id: 2
+      author: bob
+      changes:
+        - sql: ALTER TABLE users ADD email TEXT;
+`
+	want := "-- changeset alice:1\n" +
+		"-- comment: Create the users table\n" +
+		"CREATE TABLE users (id INT, name TEXT);\n" +
+		"\n" +
+		"-- changeset bob:2\n" +
+		"ALTER TABLE users ADD email TEXT;\n"
+
+	statement, err := ParseMigrationStatement(MigrationFileFormatLiquibase, "db1/changelog.xml", xmlChangelog)
+	require.NoError(t, err)
+	require.Equal(t, want, statement)
+
+	statement, err = ParseMigrationStatement(MigrationFileFormatLiquibase, "db1/changelog.yaml", yamlChangelog)
+	require.NoError(t, err)
+	require.Equal(t, want, statement)
+
+	_, err = ParseMigrationStatement(MigrationFileFormatLiquibase, "db1/changelog.xml", `<databaseChangeLog>
+  <changeSet id="1" author="alice"><createTable tableName="users"/></changeSet>
+</databaseChangeLog>`)
+	require.ErrorContains(t, err, `unsupported change "createTable"`)
+
+	_, err = ParseMigrationStatement(MigrationFileFormatLiquibase, "db1/master.xml", `<databaseChangeLog><includeAll path="changes/"/></databaseChangeLog>`)
+	require.ErrorContains(t, err, "master changelog")
+
+	statement, err = ParseMigrationStatement(MigrationFileFormatFlyway, "db1/V1__init.sql", "CREATE TABLE t (id INT);")
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE t (id INT);", statement)
+}
+
+func TestParseMigrationDownStatement(t *testing.T) {
+	xmlChangelog := `<databaseChangeLog>
+  <changeSet id="1" author="alice">
+    <sql>CREATE TABLE users (id INT)</sql>
+    <rollback>DROP TABLE users</rollback>
+  </changeSet>
+  <changeSet id="2" author="bob">
+    <sql>ALTER TABLE users ADD email TEXT</sql>
+    <rollback><sql>ALTER TABLE users DROP COLUMN email</sql></rollback>
+  </changeSet>
+</databaseChangeLog>`
+	yamlChangelog := `databaseChangeLog:
+  - changeSet:
+      id: 1
+      author: alice
+      changes:
+        - sql: CREATE TABLE users (id INT)
+      rollback: DROP TABLE users
+  - changeSet:
+      id: 2
+      author: bob
+      changes:
+        - sql: ALTER TABLE users ADD email TEXT
+      rollback:
+        - sql:
+            sql: ALTER TABLE users DROP COLUMN email
+`
+	sqlChangelog := `--liquibase formatted sql
+
+--changeset alice:1
+CREATE TABLE users (id INT);
+--rollback DROP TABLE users;
+
+--changeset bob:2
+ALTER TABLE users ADD email TEXT;
+--rollback ALTER TABLE users DROP COLUMN email;
+`
+	want := "-- changeset bob:2\n" +
+		"ALTER TABLE users DROP COLUMN email;\n" +
+		"\n" +
+		"-- changeset alice:1\n" +
+		"DROP TABLE users;\n"
+
+	for filePath, content := range map[string]string{
+		"db1/changelog.xml":  xmlChangelog,
+		"db1/changelog.yaml": yamlChangelog,
+		"db1/changelog.sql":  sqlChangelog,
+	} {
+		statement, err := ParseMigrationDownStatement(MigrationFileFormatLiquibase, filePath, content)
+		require.NoError(t, err, filePath)
+		require.Equal(t, want, statement, filePath)
+	}
+
+	statement, err := ParseMigrationDownStatement(MigrationFileFormatLiquibase, "db1/changelog.xml", `<databaseChangeLog>
+  <changeSet id="1" author="alice"><sql>CREATE TABLE users (id INT)</sql></changeSet>
+</databaseChangeLog>`)
+	require.NoError(t, err)
+	require.Equal(t, "", statement)
+
+	statement, err = ParseMigrationDownStatement(MigrationFileFormatFlyway, "db1/V1__init.sql", "CREATE TABLE t (id INT);")
+	require.NoError(t, err)
+	require.Equal(t, "", statement)
+}
+
+func TestMigrationDownFile(t *testing.T) {
+	tests := []struct {
+		format       MigrationFileFormat
+		filePath     string
+		downFilePath string
+		isDownFile   bool
+	}{
+		{MigrationFileFormatFlyway, "db1/V1_1__add_column.sql", "db1/U1_1__add_column.sql", false},
+		{MigrationFileFormatFlyway, "db1/U1_1__add_column.sql", "", true},
+		{MigrationFileFormatFlyway, "db1/B1__baseline.sql", "", false},
+		{MigrationFileFormatGolangMigrate, "db1/000001_add_column.up.sql", "db1/000001_add_column.down.sql", false},
+		{MigrationFileFormatGolangMigrate, "db1/000001_add_column.down.sql", "", true},
+		{MigrationFileFormatLiquibase, "db1/changelog.xml", "", false},
+		{MigrationFileFormatBytebase, "db1/V1__db1__add_column.sql", "", false},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.downFilePath, MigrationDownFilePath(tc.format, tc.filePath), tc.filePath)
+		require.Equal(t, tc.isDownFile, IsMigrationDownFile(tc.format, tc.filePath), tc.filePath)
+	}
+}
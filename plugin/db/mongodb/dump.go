@@ -0,0 +1,194 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// Dump dumps the database as a mongosh script, if database is empty, then dump all databases.
+// The script of a single database runs on the current database of mongosh, so that it can be restored to another database.
+func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error) {
+	if database != "" {
+		return "", driver.dumpDatabase(ctx, out, database, schemaOnly)
+	}
+
+	databaseNames, err := driver.getDatabaseNames(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range databaseNames {
+		if name == db.BytebaseDatabase {
+			continue
+		}
+		if _, err := io.WriteString(out, fmt.Sprintf("db = db.getSiblingDB(%s);\n", quoteString(name))); err != nil {
+			return "", err
+		}
+		if err := driver.dumpDatabase(ctx, out, name, schemaOnly); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// Restore restores a database by running the dumped script on the current database.
+func (driver *Driver) Restore(ctx context.Context, src io.Reader) error {
+	script, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	return driver.execute(ctx, driver.databaseName, string(script))
+}
+
+func (driver *Driver) dumpDatabase(ctx context.Context, out io.Writer, databaseName string, schemaOnly bool) error {
+	database := driver.client.Database(databaseName)
+	specList, err := getCollectionSpecList(ctx, database)
+	if err != nil {
+		return err
+	}
+
+	// The collections are created before the views since the views depend on them.
+	for _, spec := range specList {
+		if spec.Type == "view" {
+			continue
+		}
+		options, err := toEJSON(spec.Options)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(out, fmt.Sprintf("db.createCollection(%s, %s);\n", quoteString(spec.Name), options)); err != nil {
+			return err
+		}
+
+		var indexList []bson.D
+		cursor, err := database.Collection(spec.Name).Indexes().List(ctx)
+		if err != nil {
+			return err
+		}
+		if err := cursor.All(ctx, &indexList); err != nil {
+			return err
+		}
+		for _, index := range indexList {
+			var name string
+			var key interface{}
+			var indexOptions bson.D
+			for _, e := range index {
+				switch e.Key {
+				case "key":
+					key = e.Value
+				case "v", "ns":
+				default:
+					if e.Key == "name" {
+						name, _ = e.Value.(string)
+					}
+					indexOptions = append(indexOptions, e)
+				}
+			}
+			if name == idIndexName {
+				continue
+			}
+			// The keys only have the numbers and the strings, so they're written as is.
+			keyJSON, err := toRelaxedJSON(key)
+			if err != nil {
+				return err
+			}
+			options, err := toEJSON(indexOptions)
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(out, fmt.Sprintf("db.getCollection(%s).createIndex(%s, %s);\n", quoteString(spec.Name), keyJSON, options)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, spec := range specList {
+		if spec.Type != "view" {
+			continue
+		}
+		var view struct {
+			ViewOn   string `bson:"viewOn"`
+			Pipeline bson.A `bson:"pipeline"`
+		}
+		if err := bson.Unmarshal(spec.Options, &view); err != nil {
+			return err
+		}
+		pipeline, err := toEJSON(view.Pipeline)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(out, fmt.Sprintf("db.createView(%s, %s, %s);\n", quoteString(spec.Name), quoteString(view.ViewOn), pipeline)); err != nil {
+			return err
+		}
+	}
+
+	if schemaOnly {
+		return nil
+	}
+	for _, spec := range specList {
+		if spec.Type == "view" {
+			continue
+		}
+		if err := dumpCollectionData(ctx, out, database.Collection(spec.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpCollectionData dumps the documents of the collection as the insertOne calls.
+// The documents are in the canonical Extended JSON to keep the BSON types, e.g. Int64 and ObjectId.
+func dumpCollectionData(ctx context.Context, out io.Writer, collection *mongo.Collection) error {
+	cursor, err := collection.Find(ctx, bson.D{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		document, err := bson.MarshalExtJSON(cursor.Current, true /* canonical */, false /* escapeHTML */)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(out, fmt.Sprintf("db.getCollection(%s).insertOne(EJSON.deserialize(%s));\n", quoteString(collection.Name()), document)); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// toEJSON returns the JavaScript expression deserializing the value from the relaxed Extended JSON, e.g. the options with a date.
+func toEJSON(value interface{}) (string, error) {
+	s, err := toRelaxedJSON(value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("EJSON.deserialize(%s)", s), nil
+}
+
+// toRelaxedJSON returns the value in the relaxed Extended JSON.
+func toRelaxedJSON(value interface{}) (string, error) {
+	// MarshalExtJSON only accepts the documents, so the value is wrapped and then unwrapped.
+	b, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: value}}, false /* canonical */, false /* escapeHTML */)
+	if err != nil {
+		return "", err
+	}
+	var wrapper struct {
+		V json.RawMessage `json:"v"`
+	}
+	if err := json.Unmarshal(b, &wrapper); err != nil {
+		return "", err
+	}
+	return string(wrapper.V), nil
+}
+
+// quoteString quotes the string as a JavaScript string literal.
+func quoteString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,338 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// migrationHistoryCollection is the collection of the migration history in the bytebase database.
+const migrationHistoryCollection = "migration_history"
+
+// migrationHistory is the document of the migration history, the fields are the same as the migration_history table of the SQL databases.
+type migrationHistory struct {
+	ID                  int64              `bson:"_id"`
+	CreatedBy           string             `bson:"created_by"`
+	CreatedTs           int64              `bson:"created_ts"`
+	UpdatedBy           string             `bson:"updated_by"`
+	UpdatedTs           int64              `bson:"updated_ts"`
+	ReleaseVersion      string             `bson:"release_version"`
+	Namespace           string             `bson:"namespace"`
+	Sequence            int                `bson:"sequence"`
+	Source              db.MigrationSource `bson:"source"`
+	Type                db.MigrationType   `bson:"type"`
+	Status              db.MigrationStatus `bson:"status"`
+	Version             string             `bson:"version"`
+	Description         string             `bson:"description"`
+	Statement           string             `bson:"statement"`
+	Schema              string             `bson:"schema"`
+	SchemaPrev          string             `bson:"schema_prev"`
+	ExecutionDurationNs int64              `bson:"execution_duration_ns"`
+	IssueID             string             `bson:"issue_id"`
+	Payload             string             `bson:"payload"`
+}
+
+// NeedsSetupMigration returns whether it needs to setup migration.
+func (driver *Driver) NeedsSetupMigration(ctx context.Context) (bool, error) {
+	names, err := driver.client.Database(db.BytebaseDatabase).ListCollectionNames(ctx, bson.D{{Key: "name", Value: migrationHistoryCollection}})
+	if err != nil {
+		return false, err
+	}
+	return len(names) == 0, nil
+}
+
+// SetupMigrationIfNeeded sets up migration if needed.
+// The database and the collection are created implicitly, so it only creates the indexes.
+func (driver *Driver) SetupMigrationIfNeeded(ctx context.Context) error {
+	setup, err := driver.NeedsSetupMigration(ctx)
+	if err != nil {
+		return err
+	}
+	if !setup {
+		return nil
+	}
+
+	log.Info("Bytebase migration schema not found, creating schema...",
+		zap.String("environment", driver.connectionCtx.EnvironmentName),
+		zap.String("database", driver.connectionCtx.InstanceName),
+	)
+	// The index names are the same as the SQL databases, so that util.FormatError recognizes the violations.
+	if _, err := driver.historyCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "namespace", Value: 1}, {Key: "sequence", Value: 1}},
+			Options: options.Index().SetName("bytebase_idx_unique_migration_history_namespace_sequence").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace", Value: 1}, {Key: "version", Value: 1}},
+			Options: options.Index().SetName("bytebase_idx_unique_migration_history_namespace_version").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace", Value: 1}, {Key: "source", Value: 1}, {Key: "type", Value: 1}},
+			Options: options.Index().SetName("bytebase_idx_migration_history_namespace_source_type"),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace", Value: 1}, {Key: "created_ts", Value: 1}},
+			Options: options.Index().SetName("bytebase_idx_migration_history_namespace_created"),
+		},
+	}); err != nil {
+		log.Error("Failed to initialize migration schema.",
+			zap.Error(err),
+			zap.String("environment", driver.connectionCtx.EnvironmentName),
+			zap.String("database", driver.connectionCtx.InstanceName),
+		)
+		return err
+	}
+	log.Info("Successfully created migration schema.",
+		zap.String("environment", driver.connectionCtx.EnvironmentName),
+		zap.String("database", driver.connectionCtx.InstanceName),
+	)
+	return nil
+}
+
+// ExecuteMigration will execute the migration.
+// It follows the same phases as util.ExecuteMigration, which records the migration history in a SQL transaction.
+func (driver *Driver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo, statement string) (migrationHistoryID int64, updatedSchema string, resErr error) {
+	var prevSchemaBuf bytes.Buffer
+	// Don't record schema if the database hasn't exist yet.
+	if !m.CreateDatabase {
+		if _, err := driver.Dump(ctx, m.Database, &prevSchemaBuf, true /* schemaOnly */); err != nil {
+			return -1, "", err
+		}
+	}
+
+	// Phase 1 - Pre-check before executing migration
+	// Phase 2 - Record migration history as PENDING
+	insertedID, err := driver.beginMigration(ctx, m, prevSchemaBuf.String(), statement)
+	if err != nil {
+		if common.ErrorCode(err) == common.MigrationAlreadyApplied {
+			return insertedID, prevSchemaBuf.String(), nil
+		}
+		return -1, "", err
+	}
+
+	startedNs := time.Now().UnixNano()
+	defer func() {
+		if err := driver.endMigration(ctx, startedNs, insertedID, updatedSchema, resErr == nil /* isDone */); err != nil {
+			log.Error("Failed to update migration history record",
+				zap.Error(err),
+				zap.Int64("migration_id", migrationHistoryID),
+			)
+		}
+	}()
+
+	// Phase 3 - Executing migration
+	// Branch migration type always has empty statement.
+	// Baseline migration type could has non-empty statement but will not execute, except for CreateDatabase.
+	if statement != "" && (m.Type != db.Baseline || m.CreateDatabase) {
+		// The statement of CreateDatabase switches to the new database by itself.
+		database := m.Database
+		if m.CreateDatabase {
+			database = ""
+		}
+		if err := driver.execute(ctx, database, statement); err != nil {
+			return -1, "", err
+		}
+	}
+
+	// Phase 4 - Dump the schema after migration
+	var afterSchemaBuf bytes.Buffer
+	if _, err := driver.Dump(ctx, m.Database, &afterSchemaBuf, true /* schemaOnly */); err != nil {
+		return -1, "", err
+	}
+	return insertedID, afterSchemaBuf.String(), nil
+}
+
+// beginMigration checks before executing migration and inserts a migration history record with pending status.
+func (driver *Driver) beginMigration(ctx context.Context, m *db.MigrationInfo, prevSchema, statement string) (int64, error) {
+	storedVersion, err := util.ToStoredVersion(m.UseSemanticVersion, m.Version, m.SemanticVersionSuffix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert to stored version, error %w", err)
+	}
+	// Check if the same migration version has already been applied.
+	if list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
+		Database: &m.Namespace,
+		Version:  &m.Version,
+	}); err != nil {
+		return -1, fmt.Errorf("check duplicate version error: %q", err)
+	} else if len(list) > 0 {
+		switch list[0].Status {
+		case db.Done:
+			return int64(list[0].ID),
+				common.Errorf(common.MigrationAlreadyApplied, "database %q has already applied version %s", m.Database, m.Version)
+		case db.Pending:
+			// For force migration, we will ignore the existing migration history and continue to migration.
+			if m.Force {
+				return int64(list[0].ID), nil
+			}
+			return -1, common.Errorf(common.MigrationPending, "database %q version %s migration is already in progress", m.Database, m.Version)
+		case db.Failed:
+			if m.Force {
+				return int64(list[0].ID), nil
+			}
+			return -1, common.Errorf(common.MigrationFailed, "database %q version %s migration has failed, please check your database to make sure things are fine and then start a new migration using a new version ", m.Database, m.Version)
+		}
+	}
+
+	largestSequence, err := driver.findLargestSequence(ctx, m.Namespace, false /* baseline */)
+	if err != nil {
+		return -1, err
+	}
+	// Check if there is any higher version already been applied since the last baseline or branch.
+	largestBaselineSequence, err := driver.findLargestSequence(ctx, m.Namespace, true /* baseline */)
+	if err != nil {
+		return -1, err
+	}
+	var largest migrationHistory
+	if err := driver.historyCollection().FindOne(ctx,
+		bson.D{{Key: "namespace", Value: m.Namespace}, {Key: "sequence", Value: bson.D{{Key: "$gte", Value: largestBaselineSequence}}}},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}),
+	).Decode(&largest); err != nil && err != mongo.ErrNoDocuments {
+		return -1, err
+	} else if err == nil && largest.Version >= m.Version {
+		return -1, common.Errorf(common.MigrationOutOfOrder, "database %q has already applied version %s which >= %s", m.Database, largest.Version, m.Version)
+	}
+
+	// Phase 2 - Record migration history as PENDING.
+	// The ID is the next of the largest one, and the unique index on _id rejects the concurrent insertion.
+	var last migrationHistory
+	if err := driver.historyCollection().FindOne(ctx, bson.D{}, options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})).Decode(&last); err != nil && err != mongo.ErrNoDocuments {
+		return -1, err
+	}
+	now := time.Now().Unix()
+	history := &migrationHistory{
+		ID:             last.ID + 1,
+		CreatedBy:      m.Creator,
+		CreatedTs:      now,
+		UpdatedBy:      m.Creator,
+		UpdatedTs:      now,
+		ReleaseVersion: m.ReleaseVersion,
+		Namespace:      m.Namespace,
+		Sequence:       largestSequence + 1,
+		Source:         m.Source,
+		Type:           m.Type,
+		Status:         db.Pending,
+		Version:        storedVersion,
+		Description:    m.Description,
+		Statement:      statement,
+		Schema:         prevSchema,
+		SchemaPrev:     prevSchema,
+		IssueID:        m.IssueID,
+		Payload:        m.Payload,
+	}
+	if _, err := driver.historyCollection().InsertOne(ctx, history); err != nil {
+		return -1, util.FormatError(err)
+	}
+	return history.ID, nil
+}
+
+// endMigration updates the migration history record to DONE or FAILED depending on migration is done or not.
+func (driver *Driver) endMigration(ctx context.Context, startedNs int64, migrationHistoryID int64, updatedSchema string, isDone bool) error {
+	set := bson.D{
+		{Key: "status", Value: db.Failed},
+		{Key: "execution_duration_ns", Value: time.Now().UnixNano() - startedNs},
+		{Key: "updated_ts", Value: time.Now().Unix()},
+	}
+	if isDone {
+		set[0].Value = db.Done
+		set = append(set, bson.E{Key: "schema", Value: updatedSchema})
+	}
+	_, err := driver.historyCollection().UpdateByID(ctx, migrationHistoryID, bson.D{{Key: "$set", Value: set}})
+	return err
+}
+
+// findLargestSequence returns the largest sequence number, or 0 if we haven't applied any migration for this namespace.
+func (driver *Driver) findLargestSequence(ctx context.Context, namespace string, baseline bool) (int, error) {
+	filter := bson.D{{Key: "namespace", Value: namespace}}
+	if baseline {
+		filter = append(filter, bson.E{Key: "type", Value: bson.D{{Key: "$in", Value: bson.A{db.Baseline, db.Branch}}}})
+	}
+	var history migrationHistory
+	if err := driver.historyCollection().FindOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})).Decode(&history); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return -1, err
+	}
+	return history.Sequence, nil
+}
+
+// FindMigrationHistoryList finds the migration history.
+func (driver *Driver) FindMigrationHistoryList(ctx context.Context, find *db.MigrationHistoryFind) ([]*db.MigrationHistory, error) {
+	filter := bson.D{}
+	if v := find.ID; v != nil {
+		filter = append(filter, bson.E{Key: "_id", Value: int64(*v)})
+	}
+	if v := find.Database; v != nil {
+		filter = append(filter, bson.E{Key: "namespace", Value: *v})
+	}
+	if v := find.Version; v != nil {
+		storedVersion, err := util.ToStoredVersion(false, *v, "")
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, bson.E{Key: "version", Value: storedVersion})
+	}
+	if v := find.Source; v != nil {
+		filter = append(filter, bson.E{Key: "source", Value: *v})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_ts", Value: -1}, {Key: "_id", Value: -1}})
+	if v := find.Limit; v != nil {
+		opts.SetLimit(int64(*v))
+	}
+
+	cursor, err := driver.historyCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var historyList []migrationHistory
+	if err := cursor.All(ctx, &historyList); err != nil {
+		return nil, err
+	}
+
+	var migrationHistoryList []*db.MigrationHistory
+	for _, history := range historyList {
+		useSemanticVersion, version, semanticVersionSuffix, err := util.FromStoredVersion(history.Version)
+		if err != nil {
+			return nil, err
+		}
+		migrationHistoryList = append(migrationHistoryList, &db.MigrationHistory{
+			ID:                    int(history.ID),
+			Creator:               history.CreatedBy,
+			CreatedTs:             history.CreatedTs,
+			Updater:               history.UpdatedBy,
+			UpdatedTs:             history.UpdatedTs,
+			ReleaseVersion:        history.ReleaseVersion,
+			Namespace:             history.Namespace,
+			Sequence:              history.Sequence,
+			Source:                history.Source,
+			Type:                  history.Type,
+			Status:                history.Status,
+			Version:               version,
+			Description:           history.Description,
+			Statement:             history.Statement,
+			Schema:                history.Schema,
+			SchemaPrev:            history.SchemaPrev,
+			ExecutionDurationNs:   history.ExecutionDurationNs,
+			IssueID:               history.IssueID,
+			Payload:               history.Payload,
+			UseSemanticVersion:    useSemanticVersion,
+			SemanticVersionSuffix: semanticVersionSuffix,
+		})
+	}
+	return migrationHistoryList, nil
+}
+
+func (driver *Driver) historyCollection() *mongo.Collection {
+	return driver.client.Database(db.BytebaseDatabase).Collection(migrationHistoryCollection)
+}
@@ -0,0 +1,191 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// authDatabase is the database the users managed by Bytebase are created in.
+const authDatabase = "admin"
+
+var (
+	systemDatabases = map[string]bool{
+		"admin":  true,
+		"config": true,
+		"local":  true,
+	}
+
+	_ db.Driver = (*Driver)(nil)
+)
+
+func init() {
+	db.Register(db.MongoDB, newDriver)
+}
+
+// Driver is the MongoDB driver.
+type Driver struct {
+	connectionCtx db.ConnectionContext
+	// uri is the connection string without the database, the credentials are included.
+	uri       url.URL
+	tlsConfig db.TLSConfig
+
+	client       *mongo.Client
+	databaseName string
+}
+
+func newDriver(db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens a MongoDB driver.
+func (driver *Driver) Open(ctx context.Context, _ db.Type, config db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+	port := config.Port
+	if port == "" {
+		port = "27017"
+	}
+	driver.uri = url.URL{
+		Scheme: "mongodb",
+		Host:   net.JoinHostPort(config.Host, port),
+		// The connection string requires the slash before the options.
+		Path: "/",
+	}
+	if config.Username != "" {
+		driver.uri.User = url.UserPassword(config.Username, config.Password)
+		driver.uri.RawQuery = url.Values{"authSource": []string{authDatabase}}.Encode()
+	}
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, fmt.Errorf("sql: tls config error: %v", err)
+	}
+	opts := options.Client().ApplyURI(driver.uri.String()).SetAppName("bytebase")
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+	log.Debug("Opening MongoDB driver",
+		zap.String("host", config.Host),
+		zap.String("port", port),
+		zap.String("environment", connCtx.EnvironmentName),
+		zap.String("database", connCtx.InstanceName),
+	)
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	driver.connectionCtx = connCtx
+	driver.tlsConfig = config.TLSConfig
+	driver.client = client
+	driver.databaseName = config.Database
+	return driver, nil
+}
+
+// Close closes the driver.
+func (driver *Driver) Close(ctx context.Context) error {
+	return driver.client.Disconnect(ctx)
+}
+
+// Ping pings the database.
+func (driver *Driver) Ping(ctx context.Context) error {
+	return driver.client.Ping(ctx, nil)
+}
+
+// GetDBConnection gets a database connection.
+func (*Driver) GetDBConnection(context.Context, string) (*sql.DB, error) {
+	return nil, fmt.Errorf("MongoDB doesn't support SQL connection")
+}
+
+// Execute executes the JavaScript change script with mongosh on the current database.
+func (driver *Driver) Execute(ctx context.Context, statement string) error {
+	return driver.execute(ctx, driver.databaseName, statement)
+}
+
+// Query queries a SQL statement.
+func (*Driver) Query(context.Context, string, int) ([]interface{}, error) {
+	return nil, fmt.Errorf("MongoDB doesn't support SQL query, please query the data with mongosh")
+}
+
+// Capabilities returns the features the engine supports.
+func (*Driver) Capabilities() db.Capabilities {
+	return db.GetCapabilities(db.MongoDB)
+}
+
+// execute runs the script with mongosh on the database, the empty database is the default database "test".
+// The script is passed as a file since it may exceed the size limit of the command line argument, e.g. the restore script.
+func (driver *Driver) execute(ctx context.Context, database, script string) error {
+	mongosh, err := exec.LookPath("mongosh")
+	if err != nil {
+		return fmt.Errorf("mongosh is required to execute the MongoDB script, please install it in the PATH of the Bytebase server, error: %v", err)
+	}
+	dir, err := os.MkdirTemp("", "bytebase-mongosh-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	args, err := driver.getMongoshArgs(dir, database, script)
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, mongosh, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to execute the script with mongosh, error: %v, output: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// getMongoshArgs writes the script and the files it needs to the dir, and returns the mongosh arguments running the script on the database.
+// The command line is visible to the other users of the host, so the connection string has no credentials. Instead, the credentials
+// are in the authentication script readable only by the owner, which mongosh runs before the script in the same session.
+func (driver *Driver) getMongoshArgs(dir, database, script string) ([]string, error) {
+	uri := driver.uri
+	uri.User = nil
+	uri.RawQuery = ""
+	uri.Path = "/" + database
+	args := []string{uri.String(), "--quiet"}
+	if driver.tlsConfig.SslCA != "" {
+		caPath := filepath.Join(dir, "ca.pem")
+		if err := os.WriteFile(caPath, []byte(driver.tlsConfig.SslCA), 0600); err != nil {
+			return nil, err
+		}
+		args = append(args, "--tls", "--tlsCAFile", caPath)
+		if driver.tlsConfig.SslCert != "" {
+			// mongosh takes the client certificate and the key in a single file.
+			certPath := filepath.Join(dir, "cert.pem")
+			if err := os.WriteFile(certPath, []byte(driver.tlsConfig.SslCert+"\n"+driver.tlsConfig.SslKey), 0600); err != nil {
+				return nil, err
+			}
+			args = append(args, "--tlsCertificateKeyFile", certPath)
+		}
+	}
+	if user := driver.uri.User; user != nil {
+		password, _ := user.Password()
+		authPath := filepath.Join(dir, "auth.js")
+		auth := fmt.Sprintf("db.getSiblingDB(%s).auth(%s, %s);\n", quoteString(authDatabase), quoteString(user.Username()), quoteString(password))
+		if err := os.WriteFile(authPath, []byte(auth), 0600); err != nil {
+			return nil, err
+		}
+		args = append(args, authPath)
+	}
+	scriptPath := filepath.Join(dir, "script.js")
+	if err := os.WriteFile(scriptPath, []byte(script), 0600); err != nil {
+		return nil, err
+	}
+	return append(args, scriptPath), nil
+}
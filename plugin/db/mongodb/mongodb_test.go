@@ -0,0 +1,72 @@
+package mongodb
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGetIndexType(t *testing.T) {
+	require.Equal(t, "ascending", getIndexType(int32(1)))
+	require.Equal(t, "descending", getIndexType(int64(-1)))
+	require.Equal(t, "descending", getIndexType(-1.0))
+	require.Equal(t, "text", getIndexType("text"))
+	require.Equal(t, "2dsphere", getIndexType("2dsphere"))
+	require.Equal(t, "", getIndexType(true))
+}
+
+func TestToEJSON(t *testing.T) {
+	key, err := toRelaxedJSON(bson.D{{Key: "a", Value: int32(1)}, {Key: "b", Value: int32(-1)}})
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1,"b":-1}`, key)
+
+	options, err := toEJSON(bson.D{{Key: "expireAt", Value: time.Date(2022, 6, 7, 0, 0, 0, 0, time.UTC)}})
+	require.NoError(t, err)
+	require.Equal(t, `EJSON.deserialize({"expireAt":{"$date":"2022-06-07T00:00:00Z"}})`, options)
+
+	pipeline, err := toEJSON(bson.A{bson.D{{Key: "$match", Value: bson.D{{Key: "status", Value: "A"}}}}})
+	require.NoError(t, err)
+	require.Equal(t, `EJSON.deserialize([{"$match":{"status":"A"}}])`, pipeline)
+}
+
+func TestQuoteString(t *testing.T) {
+	require.Equal(t, `"orders"`, quoteString("orders"))
+	require.Equal(t, `"a\"b\\c"`, quoteString(`a"b\c`))
+}
+
+func TestGetMongoshArgs(t *testing.T) {
+	driver := &Driver{uri: url.URL{
+		Scheme:   "mongodb",
+		Host:     "localhost:27017",
+		Path:     "/",
+		User:     url.UserPassword("bytebase", `pa55"w0rd`),
+		RawQuery: "authSource=admin",
+	}}
+	dir := t.TempDir()
+	args, err := driver.getMongoshArgs(dir, "orders", "db.orders.find();")
+	require.NoError(t, err)
+	require.Equal(t, []string{"mongodb://localhost:27017/orders", "--quiet", filepath.Join(dir, "auth.js"), filepath.Join(dir, "script.js")}, args)
+	for _, arg := range args {
+		require.NotContains(t, arg, "pa55")
+	}
+
+	// The credentials are only readable by the owner.
+	info, err := os.Stat(filepath.Join(dir, "auth.js"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	auth, err := os.ReadFile(filepath.Join(dir, "auth.js"))
+	require.NoError(t, err)
+	require.Equal(t, `db.getSiblingDB("admin").auth("bytebase", "pa55\"w0rd");`+"\n", string(auth))
+
+	// No authentication script without the credentials.
+	driver.uri.User, driver.uri.RawQuery = nil, ""
+	args, err = driver.getMongoshArgs(t.TempDir(), "", "db.orders.find();")
+	require.NoError(t, err)
+	require.Len(t, args, 3)
+	require.Equal(t, "mongodb://localhost:27017/", args[0])
+}
@@ -0,0 +1,226 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// idIndexName is the name of the index on the _id field, which exists in every collection.
+const idIndexName = "_id_"
+
+// indexDocument is the index definition returned by listIndexes.
+type indexDocument struct {
+	Name   string `bson:"name"`
+	Key    bson.D `bson:"key"`
+	Unique bool   `bson:"unique"`
+	Hidden bool   `bson:"hidden"`
+}
+
+// SyncInstance syncs the instance.
+func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error) {
+	var buildInfo struct {
+		Version string `bson:"version"`
+	}
+	if err := driver.client.Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo); err != nil {
+		return nil, err
+	}
+
+	userList, err := driver.getUserList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	databaseNames, err := driver.getDatabaseNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var databaseList []db.DatabaseMeta
+	for _, name := range databaseNames {
+		if name == db.BytebaseDatabase {
+			continue
+		}
+		databaseList = append(databaseList, db.DatabaseMeta{Name: name})
+	}
+
+	return &db.InstanceMeta{
+		Version:      buildInfo.Version,
+		UserList:     userList,
+		DatabaseList: databaseList,
+	}, nil
+}
+
+// SyncDBSchema syncs a single database schema.
+// The collections are synced as the tables, and the views as the views. The collections don't have the columns.
+func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*db.Schema, error) {
+	database := driver.client.Database(databaseName)
+	specList, err := getCollectionSpecList(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := db.Schema{
+		Name: databaseName,
+	}
+	for _, spec := range specList {
+		if spec.Type == "view" {
+			definition, err := bson.MarshalExtJSON(spec.Options, false /* canonical */, false /* escapeHTML */)
+			if err != nil {
+				return nil, err
+			}
+			schema.ViewList = append(schema.ViewList, db.View{
+				Name:       spec.Name,
+				Definition: string(definition),
+			})
+			continue
+		}
+
+		collection := database.Collection(spec.Name)
+		table := db.Table{
+			Name: spec.Name,
+			// The type is either COLLECTION or TIMESERIES.
+			Type: strings.ToUpper(spec.Type),
+		}
+		if db.IsExactRowCount(ctx) {
+			table.RowCount, err = collection.CountDocuments(ctx, bson.D{})
+		} else {
+			table.RowCount, err = collection.EstimatedDocumentCount(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		var stats struct {
+			Size           int64 `bson:"size"`
+			TotalIndexSize int64 `bson:"totalIndexSize"`
+		}
+		if err := database.RunCommand(ctx, bson.D{{Key: "collStats", Value: spec.Name}}).Decode(&stats); err != nil {
+			return nil, err
+		}
+		table.DataSize, table.IndexSize = stats.Size, stats.TotalIndexSize
+		// The options are the ones of createCollection, e.g. capped and validator.
+		if elements, err := spec.Options.Elements(); err == nil && len(elements) > 0 {
+			options, err := bson.MarshalExtJSON(spec.Options, false /* canonical */, false /* escapeHTML */)
+			if err != nil {
+				return nil, err
+			}
+			table.CreateOptions = string(options)
+		}
+
+		var indexList []indexDocument
+		cursor, err := collection.Indexes().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := cursor.All(ctx, &indexList); err != nil {
+			return nil, err
+		}
+		for _, index := range indexList {
+			for i, key := range index.Key {
+				table.IndexList = append(table.IndexList, db.Index{
+					Name:       index.Name,
+					Expression: key.Key,
+					Position:   i + 1,
+					Type:       getIndexType(key.Value),
+					Unique:     index.Unique || index.Name == idIndexName,
+					Primary:    index.Name == idIndexName,
+					Visible:    !index.Hidden,
+				})
+			}
+		}
+		schema.TableList = append(schema.TableList, table)
+	}
+	return &schema, nil
+}
+
+// getDatabaseNames returns the names of the user databases, including the bytebase database.
+func (driver *Driver) getDatabaseNames(ctx context.Context) ([]string, error) {
+	names, err := driver.client.ListDatabaseNames(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var databaseNames []string
+	for _, name := range names {
+		if systemDatabases[name] {
+			continue
+		}
+		databaseNames = append(databaseNames, name)
+	}
+	sort.Strings(databaseNames)
+	return databaseNames, nil
+}
+
+// getUserList returns the users of all databases, the user name is qualified by the authentication database, e.g. "admin.root".
+// The grant is the JSON encoded roles of the user.
+func (driver *Driver) getUserList(ctx context.Context) ([]db.User, error) {
+	var usersInfo struct {
+		Users []struct {
+			User  string `bson:"user"`
+			DB    string `bson:"db"`
+			Roles bson.A `bson:"roles"`
+		} `bson:"users"`
+	}
+	if err := driver.client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "usersInfo", Value: bson.D{{Key: "forAllDBs", Value: true}}},
+	}).Decode(&usersInfo); err != nil {
+		return nil, err
+	}
+
+	var userList []db.User
+	for _, user := range usersInfo.Users {
+		roles, err := bson.MarshalExtJSON(bson.D{{Key: "roles", Value: user.Roles}}, false /* canonical */, false /* escapeHTML */)
+		if err != nil {
+			return nil, err
+		}
+		userList = append(userList, db.User{
+			Name:  fmt.Sprintf("%s.%s", user.DB, user.User),
+			Grant: string(roles),
+		})
+	}
+	return userList, nil
+}
+
+// getCollectionSpecList returns the collections and the views of the database sorted by name, the system collections are excluded.
+func getCollectionSpecList(ctx context.Context, database *mongo.Database) ([]*mongo.CollectionSpecification, error) {
+	specList, err := database.ListCollectionSpecifications(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var result []*mongo.CollectionSpecification
+	for _, spec := range specList {
+		if strings.HasPrefix(spec.Name, "system.") {
+			continue
+		}
+		result = append(result, spec)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// getIndexType returns the index type of the key value, e.g. "ascending" for 1 and "text" for "text".
+func getIndexType(value interface{}) string {
+	var direction float64
+	switch v := value.(type) {
+	case string:
+		return v
+	case int32:
+		direction = float64(v)
+	case int64:
+		direction = float64(v)
+	case float64:
+		direction = v
+	default:
+		return ""
+	}
+	if direction < 0 {
+		return "descending"
+	}
+	return "ascending"
+}
@@ -0,0 +1,408 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// Dump and restore.
+const (
+	databaseHeaderFmt = "" +
+		"--\n" +
+		"-- Microsoft SQL Server database structure for %s\n" +
+		"--\n"
+)
+
+// Dump dumps the database, the statements are separated into the batches by GO, so the dump is restored by Restore or sqlcmd.
+// The SQL Server has no statement showing the DDL of a table, so the tables are generated from the catalog views,
+// while the views, functions, procedures and triggers are dumped with their definitions.
+func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error) {
+	var dumpableDbNames []string
+	if database != "" {
+		dumpableDbNames = []string{database}
+	} else {
+		databases, err := driver.getDatabases(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get databases: %s", err)
+		}
+		for _, database := range databases {
+			if systemDatabases[database.Name] || database.Name == db.BytebaseDatabase {
+				continue
+			}
+			dumpableDbNames = append(dumpableDbNames, database.Name)
+		}
+	}
+
+	for _, dbName := range dumpableDbNames {
+		// The database is created and used only if dumping all databases.
+		if database == "" {
+			header := fmt.Sprintf(databaseHeaderFmt, dbName)
+			if _, err := io.WriteString(out, fmt.Sprintf("%sCREATE DATABASE %s;\nGO\nUSE %s;\nGO\n", header, quoteIdentifier(dbName), quoteIdentifier(dbName))); err != nil {
+				return "", err
+			}
+		}
+		if err := driver.dumpOneDatabase(ctx, dbName, out, schemaOnly); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+func (driver *Driver) dumpOneDatabase(ctx context.Context, database string, out io.Writer, schemaOnly bool) error {
+	sqldb, err := driver.GetDBConnection(ctx, database)
+	if err != nil {
+		return err
+	}
+	// Snapshot isolation isn't enabled by default, so the dump reads the committed data as the other engines without locking the tables for long.
+	txn, err := sqldb.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	schemaList, err := getSchemas(ctx, txn)
+	if err != nil {
+		return err
+	}
+	for _, schema := range schemaList {
+		if _, err := io.WriteString(out, fmt.Sprintf("CREATE SCHEMA %s;\nGO\n", quoteIdentifier(schema))); err != nil {
+			return err
+		}
+	}
+
+	tableList, err := getTables(ctx, txn)
+	if err != nil {
+		return err
+	}
+	identityMap, err := getIdentityColumns(ctx, txn)
+	if err != nil {
+		return err
+	}
+	for _, table := range tableList {
+		identity, hasIdentity := identityMap[table.Name]
+		if _, err := io.WriteString(out, getTableStatement(table, identity)); err != nil {
+			return err
+		}
+		if !schemaOnly {
+			if err := exportTableData(ctx, txn, table.Name, hasIdentity, out); err != nil {
+				return err
+			}
+		}
+	}
+	// The foreign keys are added after all tables are created and filled.
+	for _, table := range tableList {
+		if _, err := io.WriteString(out, getForeignKeyStatement(table)); err != nil {
+			return err
+		}
+	}
+
+	definitionList, err := getModuleDefinitions(ctx, txn)
+	if err != nil {
+		return err
+	}
+	for _, definition := range definitionList {
+		if _, err := io.WriteString(out, fmt.Sprintf("%s\nGO\n", strings.TrimSpace(definition))); err != nil {
+			return err
+		}
+	}
+
+	return txn.Commit()
+}
+
+// getSchemas gets the user schemas of the current database, the default dbo schema exists in every database.
+func getSchemas(ctx context.Context, txn *sql.Tx) ([]string, error) {
+	query := `
+		SELECT name FROM sys.schemas
+		WHERE schema_id > 4 AND schema_id < 16384
+		ORDER BY name`
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemaList []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemaList = append(schemaList, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return schemaList, nil
+}
+
+// identityColumn is the identity column of a table, a table has at most one.
+type identityColumn struct {
+	name      string
+	seed      string
+	increment string
+}
+
+// getIdentityColumns gets the identity columns of the tables in the current database, keyed by schemaName.tableName.
+func getIdentityColumns(ctx context.Context, txn *sql.Tx) (map[string]identityColumn, error) {
+	query := `
+		SELECT
+			s.name,
+			t.name,
+			c.name,
+			CAST(c.seed_value AS NVARCHAR(64)),
+			CAST(c.increment_value AS NVARCHAR(64))
+		FROM sys.identity_columns c
+		JOIN sys.tables t ON t.object_id = c.object_id
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		WHERE t.is_ms_shipped = 0`
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identityMap := make(map[string]identityColumn)
+	for rows.Next() {
+		var schemaName, tableName string
+		var identity identityColumn
+		if err := rows.Scan(&schemaName, &tableName, &identity.name, &identity.seed, &identity.increment); err != nil {
+			return nil, err
+		}
+		identityMap[fmt.Sprintf("%s.%s", schemaName, tableName)] = identity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return identityMap, nil
+}
+
+// getModuleDefinitions gets the definitions of the views, functions, procedures and triggers in the creation order,
+// so the objects are created after the ones they depend on in most cases.
+func getModuleDefinitions(ctx context.Context, txn *sql.Tx) ([]string, error) {
+	query := `
+		SELECT m.definition
+		FROM sys.sql_modules m
+		JOIN sys.objects o ON o.object_id = m.object_id
+		WHERE o.is_ms_shipped = 0 AND m.definition IS NOT NULL
+		ORDER BY o.create_date, o.object_id`
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var definitionList []string
+	for rows.Next() {
+		var definition string
+		if err := rows.Scan(&definition); err != nil {
+			return nil, err
+		}
+		definitionList = append(definitionList, definition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return definitionList, nil
+}
+
+// getTableStatement generates the CREATE TABLE statement with the primary key, and the CREATE INDEX statements of the other indexes.
+func getTableStatement(table db.Table, identity identityColumn) string {
+	tableName := quoteTableName(table.Name)
+	var lines []string
+	for _, column := range table.ColumnList {
+		line := fmt.Sprintf("%s %s", quoteIdentifier(column.Name), column.Type)
+		if column.Name == identity.name {
+			line = fmt.Sprintf("%s IDENTITY(%s, %s)", line, identity.seed, identity.increment)
+		}
+		if column.Collation != "" {
+			line = fmt.Sprintf("%s COLLATE %s", line, column.Collation)
+		}
+		if column.Nullable {
+			line += " NULL"
+		} else {
+			line += " NOT NULL"
+		}
+		if column.Default != nil {
+			line = fmt.Sprintf("%s DEFAULT %s", line, *column.Default)
+		}
+		lines = append(lines, "    "+line)
+	}
+
+	indexMap, indexNameList := groupIndexes(table.IndexList)
+	var indexStatements []string
+	for _, name := range indexNameList {
+		indexList := indexMap[name]
+		var columnList []string
+		for _, index := range indexList {
+			columnList = append(columnList, quoteIdentifier(index.Expression))
+		}
+		first := indexList[0]
+		if first.Primary {
+			lines = append(lines, fmt.Sprintf("    CONSTRAINT %s PRIMARY KEY %s (%s)", quoteIdentifier(name), first.Type, strings.Join(columnList, ", ")))
+			continue
+		}
+		unique := ""
+		if first.Unique {
+			unique = "UNIQUE "
+		}
+		indexStatements = append(indexStatements, fmt.Sprintf("CREATE %s%s INDEX %s ON %s (%s);\n", unique, first.Type, quoteIdentifier(name), tableName, strings.Join(columnList, ", ")))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n);\n%sGO\n", tableName, strings.Join(lines, ",\n"), strings.Join(indexStatements, ""))
+}
+
+// getForeignKeyStatement generates the ALTER TABLE statements adding the foreign keys of the table.
+func getForeignKeyStatement(table db.Table) string {
+	var foreignKeyNameList []string
+	foreignKeyMap := make(map[string][]db.ForeignKey)
+	for _, foreignKey := range table.ForeignKeyList {
+		if _, ok := foreignKeyMap[foreignKey.Name]; !ok {
+			foreignKeyNameList = append(foreignKeyNameList, foreignKey.Name)
+		}
+		foreignKeyMap[foreignKey.Name] = append(foreignKeyMap[foreignKey.Name], foreignKey)
+	}
+
+	var buf strings.Builder
+	for _, name := range foreignKeyNameList {
+		foreignKeyList := foreignKeyMap[name]
+		var columnList, referencedColumnList []string
+		for _, foreignKey := range foreignKeyList {
+			columnList = append(columnList, quoteIdentifier(foreignKey.Column))
+			referencedColumnList = append(referencedColumnList, quoteIdentifier(foreignKey.ReferencedColumn))
+		}
+		first := foreignKeyList[0]
+		fmt.Fprintf(&buf, "ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s) ON DELETE %s ON UPDATE %s;\nGO\n",
+			quoteTableName(table.Name), quoteIdentifier(name), strings.Join(columnList, ", "),
+			quoteTableName(first.ReferencedTable), strings.Join(referencedColumnList, ", "), first.OnDelete, first.OnUpdate)
+	}
+	return buf.String()
+}
+
+// groupIndexes groups the index entries by the index name in the order of their first appearance.
+func groupIndexes(indexList []db.Index) (map[string][]db.Index, []string) {
+	var nameList []string
+	indexMap := make(map[string][]db.Index)
+	for _, index := range indexList {
+		if _, ok := indexMap[index.Name]; !ok {
+			nameList = append(nameList, index.Name)
+		}
+		indexMap[index.Name] = append(indexMap[index.Name], index)
+	}
+	return indexMap, nameList
+}
+
+// exportTableData generates the INSERT statements of the table rows.
+func exportTableData(ctx context.Context, txn *sql.Tx, tableName string, hasIdentity bool, out io.Writer) error {
+	query := fmt.Sprintf("SELECT * FROM %s", quoteTableName(tableName))
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	if len(columnTypes) == 0 {
+		return nil
+	}
+	var columnNameList []string
+	for _, columnType := range columnTypes {
+		columnNameList = append(columnNameList, quoteIdentifier(columnType.Name()))
+	}
+
+	if hasIdentity {
+		if _, err := io.WriteString(out, fmt.Sprintf("SET IDENTITY_INSERT %s ON;\n", quoteTableName(tableName))); err != nil {
+			return err
+		}
+	}
+	values := make([]interface{}, len(columnTypes))
+	refs := make([]interface{}, len(columnTypes))
+	for i := range values {
+		refs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(refs...); err != nil {
+			return err
+		}
+		tokens := make([]string, len(columnTypes))
+		for i, v := range values {
+			tokens[i] = formatValue(v, columnTypes[i].DatabaseTypeName())
+		}
+		stmt := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);\n", quoteTableName(tableName), strings.Join(columnNameList, ", "), strings.Join(tokens, ", "))
+		if _, err := io.WriteString(out, stmt); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if hasIdentity {
+		if _, err := io.WriteString(out, fmt.Sprintf("SET IDENTITY_INSERT %s OFF;\n", quoteTableName(tableName))); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(out, "GO\n")
+	return err
+}
+
+// formatValue formats the value scanned by the SQL Server driver as a T-SQL literal.
+func formatValue(v interface{}, databaseTypeName string) string {
+	switch value := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if value {
+			return "1"
+		}
+		return "0"
+	case int64, float64:
+		return fmt.Sprintf("%v", value)
+	case string:
+		return fmt.Sprintf("N'%s'", strings.ReplaceAll(value, "'", "''"))
+	case []byte:
+		switch databaseTypeName {
+		// The driver returns the exact numerics as the decimal strings.
+		case "DECIMAL", "MONEY", "SMALLMONEY":
+			return string(value)
+		}
+		return fmt.Sprintf("0x%X", value)
+	case time.Time:
+		switch databaseTypeName {
+		case "DATE":
+			return fmt.Sprintf("'%s'", value.Format("2006-01-02"))
+		case "TIME":
+			return fmt.Sprintf("'%s'", value.Format("15:04:05.9999999"))
+		case "DATETIME", "SMALLDATETIME":
+			return fmt.Sprintf("'%s'", value.Format("2006-01-02T15:04:05.999"))
+		case "DATETIMEOFFSET":
+			return fmt.Sprintf("'%s'", value.Format("2006-01-02T15:04:05.9999999Z07:00"))
+		}
+		return fmt.Sprintf("'%s'", value.Format("2006-01-02T15:04:05.9999999"))
+	}
+	return fmt.Sprintf("N'%s'", strings.ReplaceAll(fmt.Sprintf("%v", v), "'", "''"))
+}
+
+// quoteTableName quotes the table name in the format of schemaName.tableName, e.g. [dbo].[user].
+func quoteTableName(name string) string {
+	if i := strings.Index(name, "."); i >= 0 {
+		return fmt.Sprintf("%s.%s", quoteIdentifier(name[:i]), quoteIdentifier(name[i+1:]))
+	}
+	return quoteIdentifier(name)
+}
+
+// Restore restores a database, the batches separated by GO are executed one by one.
+func (driver *Driver) Restore(ctx context.Context, sc io.Reader) error {
+	statement, err := io.ReadAll(sc)
+	if err != nil {
+		return err
+	}
+	return driver.Execute(ctx, string(statement))
+}
@@ -0,0 +1,303 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	// embed will embeds the migration schema.
+	_ "embed"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+var (
+	//go:embed mssql_migration_schema.sql
+	migrationSchema string
+
+	_ util.MigrationExecutor = (*Driver)(nil)
+)
+
+// NeedsSetupMigration returns whether it needs to setup migration.
+func (driver *Driver) NeedsSetupMigration(ctx context.Context) (bool, error) {
+	exist, err := driver.hasBytebaseDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		return true, nil
+	}
+	sqldb, err := driver.GetDBConnection(ctx, db.BytebaseDatabase)
+	if err != nil {
+		return false, err
+	}
+
+	const query = `
+		SELECT
+		    1
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = 'dbo' AND TABLE_NAME = 'migration_history'
+	`
+	return util.NeedsSetupMigrationSchema(ctx, sqldb, query)
+}
+
+// SetupMigrationIfNeeded sets up migration if needed.
+func (driver *Driver) SetupMigrationIfNeeded(ctx context.Context) error {
+	setup, err := driver.NeedsSetupMigration(ctx)
+	if err != nil {
+		return err
+	}
+	if !setup {
+		return nil
+	}
+
+	log.Info("Bytebase migration schema not found, creating schema...",
+		zap.String("environment", driver.connectionCtx.EnvironmentName),
+		zap.String("database", driver.connectionCtx.InstanceName),
+	)
+	exist, err := driver.hasBytebaseDatabase(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find database \"bytebase\", error: %v", err)
+	}
+	if !exist {
+		createBytebaseDatabaseStmt := fmt.Sprintf("CREATE DATABASE %s", quoteIdentifier(db.BytebaseDatabase))
+		if _, err := driver.db.ExecContext(ctx, createBytebaseDatabaseStmt); err != nil {
+			log.Error("Failed to create database \"bytebase\".",
+				zap.Error(err),
+				zap.String("environment", driver.connectionCtx.EnvironmentName),
+				zap.String("database", driver.connectionCtx.InstanceName),
+			)
+			return util.FormatErrorWithQuery(err, createBytebaseDatabaseStmt)
+		}
+	}
+	sqldb, err := driver.GetDBConnection(ctx, db.BytebaseDatabase)
+	if err != nil {
+		return fmt.Errorf("failed to switch to database \"bytebase\", error: %v", err)
+	}
+	if _, err := sqldb.ExecContext(ctx, migrationSchema); err != nil {
+		log.Error("Failed to initialize migration schema.",
+			zap.Error(err),
+			zap.String("environment", driver.connectionCtx.EnvironmentName),
+			zap.String("database", driver.connectionCtx.InstanceName),
+		)
+		return util.FormatErrorWithQuery(err, migrationSchema)
+	}
+	log.Info("Successfully created migration schema.",
+		zap.String("environment", driver.connectionCtx.EnvironmentName),
+		zap.String("database", driver.connectionCtx.InstanceName),
+	)
+	return nil
+}
+
+// FindLargestVersionSinceBaseline will find the largest version since last baseline or branch.
+func (driver Driver) FindLargestVersionSinceBaseline(ctx context.Context, tx *sql.Tx, namespace string) (*string, error) {
+	largestBaselineSequence, err := driver.FindLargestSequence(ctx, tx, namespace, true /* baseline */)
+	if err != nil {
+		return nil, err
+	}
+	const getLargestVersionSinceLastBaselineQuery = `
+		SELECT MAX(version) FROM migration_history
+		WHERE namespace = @p1 AND sequence >= @p2
+	`
+	var version sql.NullString
+	if err := tx.QueryRowContext(ctx, getLargestVersionSinceLastBaselineQuery,
+		namespace, largestBaselineSequence,
+	).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, util.FormatErrorWithQuery(err, getLargestVersionSinceLastBaselineQuery)
+	}
+	if version.Valid {
+		return &version.String, nil
+	}
+	return nil, nil
+}
+
+// FindLargestSequence will return the largest sequence number.
+func (Driver) FindLargestSequence(ctx context.Context, tx *sql.Tx, namespace string, baseline bool) (int, error) {
+	findLargestSequenceQuery := `
+		SELECT MAX(sequence) FROM migration_history
+		WHERE namespace = @p1`
+	if baseline {
+		findLargestSequenceQuery = fmt.Sprintf("%s AND (type = '%s' OR type = '%s')", findLargestSequenceQuery, db.Baseline, db.Branch)
+	}
+	var sequence sql.NullInt64
+	if err := tx.QueryRowContext(ctx, findLargestSequenceQuery,
+		namespace,
+	).Scan(&sequence); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return -1, util.FormatErrorWithQuery(err, findLargestSequenceQuery)
+	}
+	if sequence.Valid {
+		return int(sequence.Int64), nil
+	}
+	// Returns 0 if we haven't applied any migration for this namespace.
+	return 0, nil
+}
+
+// InsertPendingHistory will insert the migration record with pending status and return the inserted ID.
+func (Driver) InsertPendingHistory(ctx context.Context, tx *sql.Tx, sequence int, prevSchema string, m *db.MigrationInfo, storedVersion, statement string) (int64, error) {
+	const insertHistoryQuery = `
+	INSERT INTO migration_history (
+		created_by,
+		created_ts,
+		updated_by,
+		updated_ts,
+		release_version,
+		namespace,
+		sequence,
+		source,
+		type,
+		status,
+		version,
+		description,
+		statement,
+		[schema],
+		schema_prev,
+		execution_duration_ns,
+		issue_id,
+		payload
+	)
+	OUTPUT INSERTED.id
+	VALUES (@p1, DATEDIFF(SECOND, '1970-01-01', SYSUTCDATETIME()), @p2, DATEDIFF(SECOND, '1970-01-01', SYSUTCDATETIME()), @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, @p12, @p13, 0, @p14, @p15)
+	`
+	var insertedID int64
+	if err := tx.QueryRowContext(ctx, insertHistoryQuery,
+		m.Creator,
+		m.Creator,
+		m.ReleaseVersion,
+		m.Namespace,
+		sequence,
+		m.Source,
+		m.Type,
+		db.Pending,
+		storedVersion,
+		m.Description,
+		statement,
+		prevSchema,
+		prevSchema,
+		m.IssueID,
+		m.Payload,
+	).Scan(&insertedID); err != nil {
+		return 0, util.FormatErrorWithQuery(err, insertHistoryQuery)
+	}
+	return insertedID, nil
+}
+
+// UpdateHistoryAsDone will update the migration record as done.
+func (Driver) UpdateHistoryAsDone(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, updatedSchema string, insertedID int64) error {
+	const updateHistoryAsDoneQuery = `
+	UPDATE
+		migration_history
+	SET
+		status = @p1,
+		execution_duration_ns = @p2,
+		[schema] = @p3
+	WHERE id = @p4
+	`
+	_, err := tx.ExecContext(ctx, updateHistoryAsDoneQuery, db.Done, migrationDurationNs, updatedSchema, insertedID)
+	return err
+}
+
+// UpdateHistoryAsFailed will update the migration record as failed.
+func (Driver) UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, insertedID int64) error {
+	const updateHistoryAsFailedQuery = `
+	UPDATE
+		migration_history
+	SET
+		status = @p1,
+		execution_duration_ns = @p2
+	WHERE id = @p3
+	`
+	_, err := tx.ExecContext(ctx, updateHistoryAsFailedQuery, db.Failed, migrationDurationNs, insertedID)
+	return err
+}
+
+// ExecuteMigration will execute the migration.
+func (driver *Driver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo, statement string) (int64, string, error) {
+	return util.ExecuteMigration(ctx, driver, m, statement, db.BytebaseDatabase)
+}
+
+// FindMigrationHistoryList finds the migration history.
+func (driver *Driver) FindMigrationHistoryList(ctx context.Context, find *db.MigrationHistoryFind) ([]*db.MigrationHistory, error) {
+	top := ""
+	if v := find.Limit; v != nil {
+		top = fmt.Sprintf("TOP (%d) ", *v)
+	}
+	baseQuery := `
+	SELECT ` + top + `
+		id,
+		created_by,
+		created_ts,
+		updated_by,
+		updated_ts,
+		release_version,
+		namespace,
+		sequence,
+		source,
+		type,
+		status,
+		version,
+		description,
+		statement,
+		[schema],
+		schema_prev,
+		execution_duration_ns,
+		issue_id,
+		payload
+		FROM migration_history `
+	paramNames, params := []string{}, []interface{}{}
+	if v := find.ID; v != nil {
+		paramNames, params = append(paramNames, "id"), append(params, *v)
+	}
+	if v := find.Database; v != nil {
+		paramNames, params = append(paramNames, "namespace"), append(params, *v)
+	}
+	if v := find.Version; v != nil {
+		storedVersion, err := util.ToStoredVersion(false, *v, "")
+		if err != nil {
+			return nil, err
+		}
+		paramNames, params = append(paramNames, "version"), append(params, storedVersion)
+	}
+	if v := find.Source; v != nil {
+		paramNames, params = append(paramNames, "source"), append(params, *v)
+	}
+	var query = baseQuery +
+		formatParamNameInAtSignPosition(paramNames) +
+		`ORDER BY created_ts DESC`
+	return util.FindMigrationHistoryList(ctx, query, params, driver, db.BytebaseDatabase)
+}
+
+// formatParamNameInAtSignPosition formats the param names in the positions of the SQL Server driver, e.g. @p1.
+func formatParamNameInAtSignPosition(paramNames []string) string {
+	if len(paramNames) == 0 {
+		return ""
+	}
+	var parts []string
+	for i, param := range paramNames {
+		parts = append(parts, fmt.Sprintf("%s = @p%d", param, i+1))
+	}
+	return fmt.Sprintf("WHERE %s ", strings.Join(parts, " AND "))
+}
+
+func (driver *Driver) hasBytebaseDatabase(ctx context.Context) (bool, error) {
+	databases, err := driver.getDatabases(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, database := range databases {
+		if database.Name == db.BytebaseDatabase {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,269 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	mssqldb "github.com/denisenkom/go-mssqldb"
+	"github.com/denisenkom/go-mssqldb/batch"
+	"github.com/denisenkom/go-mssqldb/msdsn"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+var (
+	systemDatabases = map[string]bool{
+		"master": true,
+		"model":  true,
+		"msdb":   true,
+		"tempdb": true,
+	}
+	// batchSeparator separates the batches of the T-SQL script, e.g. CREATE VIEW must be the first statement in a batch.
+	batchSeparator = "GO"
+
+	_ db.Driver = (*Driver)(nil)
+)
+
+func init() {
+	db.Register(db.MSSQL, newDriver)
+}
+
+// Driver is the Microsoft SQL Server driver.
+type Driver struct {
+	connectionCtx db.ConnectionContext
+	config        msdsn.Config
+
+	db           *sql.DB
+	databaseName string
+}
+
+func newDriver(db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens a Microsoft SQL Server driver.
+func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+	port := uint64(1433)
+	if config.Port != "" {
+		p, err := strconv.ParseUint(config.Port, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", config.Port)
+		}
+		port = p
+	}
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, fmt.Errorf("sql: tls config error: %v", err)
+	}
+	driver.config = msdsn.Config{
+		Host:     config.Host,
+		Port:     port,
+		User:     config.Username,
+		Password: config.Password,
+		AppName:  "bytebase",
+	}
+	if tlsConfig != nil {
+		driver.config.Encryption = msdsn.EncryptionRequired
+		driver.config.TLSConfig = tlsConfig
+	}
+	driver.connectionCtx = connCtx
+	log.Debug("Opening Microsoft SQL Server driver",
+		zap.String("host", config.Host),
+		zap.Uint64("port", port),
+		zap.String("environment", connCtx.EnvironmentName),
+		zap.String("database", connCtx.InstanceName),
+	)
+	if err := driver.switchDatabase(config.Database); err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// Close closes the driver.
+func (driver *Driver) Close(context.Context) error {
+	return driver.db.Close()
+}
+
+// Ping pings the database.
+func (driver *Driver) Ping(ctx context.Context) error {
+	return driver.db.PingContext(ctx)
+}
+
+// GetDBConnection gets a database connection.
+func (driver *Driver) GetDBConnection(_ context.Context, database string) (*sql.DB, error) {
+	if database != driver.databaseName {
+		if err := driver.switchDatabase(database); err != nil {
+			return nil, err
+		}
+	}
+	return driver.db, nil
+}
+
+// switchDatabase reopens the connection pool on the database, the empty database is the default database of the login.
+func (driver *Driver) switchDatabase(database string) error {
+	if driver.db != nil {
+		if err := driver.db.Close(); err != nil {
+			return err
+		}
+	}
+	config := driver.config
+	config.Database = database
+	driver.db = sql.OpenDB(mssqldb.NewConnectorConfig(config))
+	driver.databaseName = database
+	return nil
+}
+
+// getVersion gets the version of the SQL Server, e.g. 15.0.2000.5.
+func (driver *Driver) getVersion(ctx context.Context) (string, error) {
+	query := "SELECT CAST(SERVERPROPERTY('ProductVersion') AS NVARCHAR(128))"
+	var version string
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return "", common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return "", util.FormatErrorWithQuery(err, query)
+	}
+	return version, nil
+}
+
+// Execute executes the T-SQL script, the batches separated by GO are executed one by one in a transaction.
+// CREATE DATABASE can't run in a transaction, so it's executed separately and switches to the new database.
+func (driver *Driver) Execute(ctx context.Context, statement string) error {
+	var batchList []string
+	for _, b := range splitBatches(statement) {
+		if databaseName, ok := getDatabaseInCreateDatabaseStatement(b); ok {
+			if err := driver.executeBatchList(ctx, batchList); err != nil {
+				return err
+			}
+			batchList = nil
+			if _, err := driver.db.ExecContext(ctx, b); err != nil {
+				return err
+			}
+			if _, err := driver.GetDBConnection(ctx, databaseName); err != nil {
+				return err
+			}
+			continue
+		}
+		if databaseName, ok := getDatabaseInUseStatement(b); ok {
+			// USE only changes the database of the connection it runs on, so the connection pool is switched instead.
+			if err := driver.executeBatchList(ctx, batchList); err != nil {
+				return err
+			}
+			batchList = nil
+			if _, err := driver.GetDBConnection(ctx, databaseName); err != nil {
+				return err
+			}
+			continue
+		}
+		batchList = append(batchList, b)
+	}
+	return driver.executeBatchList(ctx, batchList)
+}
+
+func (driver *Driver) executeBatchList(ctx context.Context, batchList []string) error {
+	if len(batchList) == 0 {
+		return nil
+	}
+	setting := db.SessionSettingFromContext(ctx)
+	if setting == nil {
+		setting = &db.SessionSetting{}
+	}
+	tx, err := driver.db.BeginTx(ctx, setting.GetTxOptions())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if setting.LockTimeoutMs > 0 {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCK_TIMEOUT %d", setting.LockTimeoutMs)); err != nil {
+			return err
+		}
+	}
+	for _, b := range batchList {
+		if _, err := tx.ExecContext(ctx, b); err != nil {
+			return util.FormatErrorWithQuery(err, b)
+		}
+	}
+	return tx.Commit()
+}
+
+// Query queries a SQL statement.
+func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
+	// SQL Server rejects the read-only transactions, so the transaction is always rolled back instead.
+	tx, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return util.QueryTx(ctx, tx, statement, limit)
+}
+
+// Capabilities returns the features the engine supports.
+func (*Driver) Capabilities() db.Capabilities {
+	return db.GetCapabilities(db.MSSQL)
+}
+
+// splitBatches splits the T-SQL script into the batches separated by GO, the empty batches are skipped.
+func splitBatches(statement string) []string {
+	var batchList []string
+	for _, b := range batch.Split(statement, batchSeparator) {
+		if strings.TrimSpace(b) == "" {
+			continue
+		}
+		batchList = append(batchList, b)
+	}
+	return batchList
+}
+
+// getDatabaseInCreateDatabaseStatement returns the database name if the batch is a single CREATE DATABASE statement,
+// the options following the database name such as COLLATE are allowed.
+func getDatabaseInCreateDatabaseStatement(b string) (string, bool) {
+	return getDatabaseInStatement(b, "CREATE DATABASE", true /* allowOptions */)
+}
+
+// getDatabaseInUseStatement returns the database name if the batch is a single USE statement.
+func getDatabaseInUseStatement(b string) (string, bool) {
+	return getDatabaseInStatement(b, "USE", false /* allowOptions */)
+}
+
+func getDatabaseInStatement(b string, prefix string, allowOptions bool) (string, bool) {
+	stmt := strings.TrimRight(strings.TrimSpace(b), ";")
+	if strings.Contains(stmt, ";") {
+		return "", false
+	}
+	fields := strings.Fields(stmt)
+	prefixFields := strings.Fields(prefix)
+	if len(fields) < len(prefixFields)+1 || (!allowOptions && len(fields) > len(prefixFields)+1) {
+		return "", false
+	}
+	for i, field := range prefixFields {
+		if !strings.EqualFold(fields[i], field) {
+			return "", false
+		}
+	}
+	return unquoteIdentifier(fields[len(prefixFields)]), true
+}
+
+// quoteIdentifier quotes the identifier with brackets, e.g. [dbo].
+func quoteIdentifier(s string) string {
+	return fmt.Sprintf("[%s]", strings.ReplaceAll(s, "]", "]]"))
+}
+
+// unquoteIdentifier removes the brackets or the double quotes around the identifier.
+func unquoteIdentifier(s string) string {
+	if len(s) >= 2 && s[0] == '[' && s[len(s)-1] == ']' {
+		return strings.ReplaceAll(s[1:len(s)-1], "]]", "]")
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strings.ReplaceAll(s[1:len(s)-1], `""`, `"`)
+	}
+	return s
+}
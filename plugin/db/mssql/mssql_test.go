@@ -0,0 +1,68 @@
+package mssql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitBatches(t *testing.T) {
+	statement := "CREATE TABLE t(id INT);\nGO\n\nGO\nCREATE VIEW v AS SELECT id FROM t;\nGO\n"
+	got := splitBatches(statement)
+	require.Len(t, got, 2)
+	require.Contains(t, got[0], "CREATE TABLE t")
+	require.Contains(t, got[1], "CREATE VIEW v")
+}
+
+func TestGetDatabaseInStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		create    bool
+		want      string
+		wantOK    bool
+	}{
+		{"CREATE DATABASE [shop];", true, "shop", true},
+		{"create database shop COLLATE Latin1_General_CI_AS", true, "shop", true},
+		{`CREATE DATABASE "sh""op"`, true, `sh"op`, true},
+		{"CREATE DATABASE;", true, "", false},
+		{"CREATE DATABASE shop; CREATE TABLE t(id INT);", true, "", false},
+		{"CREATE TABLE shop(id INT);", true, "", false},
+		{"USE [sh]]op];", false, "sh]op", true},
+		{"USE shop COLLATE x;", false, "", false},
+		{"USE;", false, "", false},
+	}
+
+	for _, test := range tests {
+		var got string
+		var ok bool
+		if test.create {
+			got, ok = getDatabaseInCreateDatabaseStatement(test.statement)
+		} else {
+			got, ok = getDatabaseInUseStatement(test.statement)
+		}
+		require.Equal(t, test.wantOK, ok, test.statement)
+		require.Equal(t, test.want, got, test.statement)
+	}
+}
+
+func TestFormatColumnType(t *testing.T) {
+	require.Equal(t, "int", formatColumnType("int", 4, 10, 0))
+	require.Equal(t, "varchar(64)", formatColumnType("varchar", 64, 0, 0))
+	require.Equal(t, "nvarchar(64)", formatColumnType("nvarchar", 128, 0, 0))
+	require.Equal(t, "nvarchar(max)", formatColumnType("nvarchar", -1, 0, 0))
+	require.Equal(t, "decimal(10, 2)", formatColumnType("decimal", 9, 10, 2))
+	require.Equal(t, "datetime2(7)", formatColumnType("datetime2", 8, 27, 7))
+}
+
+func TestFormatValue(t *testing.T) {
+	ts := time.Date(2022, 6, 5, 10, 30, 0, 0, time.UTC)
+	require.Equal(t, "NULL", formatValue(nil, "INT"))
+	require.Equal(t, "1", formatValue(true, "BIT"))
+	require.Equal(t, "42", formatValue(int64(42), "INT"))
+	require.Equal(t, "N'it''s'", formatValue("it's", "NVARCHAR"))
+	require.Equal(t, "12.50", formatValue([]byte("12.50"), "DECIMAL"))
+	require.Equal(t, "0x0AFF", formatValue([]byte{0x0a, 0xff}, "VARBINARY"))
+	require.Equal(t, "'2022-06-05'", formatValue(ts, "DATE"))
+	require.Equal(t, "'2022-06-05T10:30:00'", formatValue(ts, "DATETIME2"))
+}
@@ -0,0 +1,486 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+var (
+	// systemSchemas are the schemas created in every database, excluding the default dbo schema.
+	systemSchemas = map[string]bool{
+		"sys":                true,
+		"INFORMATION_SCHEMA": true,
+		"guest":              true,
+	}
+)
+
+// SyncInstance syncs the instance.
+func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error) {
+	version, err := driver.getVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userList, err := driver.getUserList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := driver.getDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var databaseList []db.DatabaseMeta
+	for _, database := range databases {
+		if systemDatabases[database.Name] || database.Name == db.BytebaseDatabase {
+			continue
+		}
+		databaseList = append(databaseList, database)
+	}
+
+	return &db.InstanceMeta{
+		Version:      version,
+		UserList:     userList,
+		DatabaseList: databaseList,
+	}, nil
+}
+
+// SyncDBSchema syncs a single database schema.
+func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*db.Schema, error) {
+	databases, err := driver.getDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var schema *db.Schema
+	for _, database := range databases {
+		if database.Name == databaseName {
+			schema = &db.Schema{
+				Name:      database.Name,
+				Collation: database.Collation,
+			}
+			break
+		}
+	}
+	if schema == nil {
+		return nil, common.Errorf(common.NotFound, "database %q not found", databaseName)
+	}
+
+	sqldb, err := driver.GetDBConnection(ctx, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	txn, err := sqldb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	tableList, err := getTables(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	viewList, err := getViews(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	schema.TableList, schema.ViewList = tableList, viewList
+	return schema, nil
+}
+
+// getDatabases gets the databases of the instance with their collations.
+func (driver *Driver) getDatabases(ctx context.Context) ([]db.DatabaseMeta, error) {
+	query := "SELECT name, ISNULL(collation_name, '') FROM sys.databases ORDER BY name"
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var databaseList []db.DatabaseMeta
+	for rows.Next() {
+		var database db.DatabaseMeta
+		if err := rows.Scan(&database.Name, &database.Collation); err != nil {
+			return nil, err
+		}
+		databaseList = append(databaseList, database)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return databaseList, nil
+}
+
+// getUserList gets the logins with their server roles, e.g. sa with sysadmin.
+func (driver *Driver) getUserList(ctx context.Context) ([]db.User, error) {
+	query := `
+		SELECT
+			p.name,
+			ISNULL(r.name, '')
+		FROM sys.server_principals p
+		LEFT JOIN sys.server_role_members m ON m.member_principal_id = p.principal_id
+		LEFT JOIN sys.server_principals r ON r.principal_id = m.role_principal_id
+		WHERE p.type IN ('S', 'U', 'G') AND p.name NOT LIKE '##%'
+		ORDER BY p.name, r.name`
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var nameList []string
+	grants := make(map[string][]string)
+	for rows.Next() {
+		var name, role string
+		if err := rows.Scan(&name, &role); err != nil {
+			return nil, err
+		}
+		if _, ok := grants[name]; !ok {
+			nameList = append(nameList, name)
+			grants[name] = nil
+		}
+		if role != "" {
+			grants[name] = append(grants[name], role)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+
+	var userList []db.User
+	for _, name := range nameList {
+		userList = append(userList, db.User{
+			Name:  name,
+			Grant: strings.Join(grants[name], ", "),
+		})
+	}
+	return userList, nil
+}
+
+// getTables gets the tables of the current database with their columns, indexes and foreign keys.
+func getTables(ctx context.Context, txn *sql.Tx) ([]db.Table, error) {
+	columnMap, err := getColumns(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	indexMap, err := getIndexes(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	foreignKeyMap, err := getForeignKeys(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	// The row count and the sizes are summed over the partitions of the heap or the clustered index, and the other indexes respectively.
+	query := `
+		SELECT
+			s.name,
+			t.name,
+			DATEDIFF(SECOND, '1970-01-01', t.create_date),
+			DATEDIFF(SECOND, '1970-01-01', t.modify_date),
+			ISNULL((SELECT SUM(p.rows) FROM sys.partitions p WHERE p.object_id = t.object_id AND p.index_id IN (0, 1)), 0),
+			ISNULL((SELECT SUM(ps.used_page_count) FROM sys.dm_db_partition_stats ps WHERE ps.object_id = t.object_id AND ps.index_id IN (0, 1)), 0) * 8192,
+			ISNULL((SELECT SUM(ps.used_page_count) FROM sys.dm_db_partition_stats ps WHERE ps.object_id = t.object_id AND ps.index_id > 1), 0) * 8192,
+			ISNULL(CAST(ep.value AS NVARCHAR(MAX)), '')
+		FROM sys.tables t
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		LEFT JOIN sys.extended_properties ep ON ep.major_id = t.object_id AND ep.minor_id = 0 AND ep.class = 1 AND ep.name = 'MS_Description'
+		WHERE t.is_ms_shipped = 0
+		ORDER BY s.name, t.name`
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var tableList []db.Table
+	for rows.Next() {
+		var schemaName, tableName string
+		var table db.Table
+		if err := rows.Scan(
+			&schemaName,
+			&tableName,
+			&table.CreatedTs,
+			&table.UpdatedTs,
+			&table.RowCount,
+			&table.DataSize,
+			&table.IndexSize,
+			&table.Comment,
+		); err != nil {
+			return nil, err
+		}
+		if systemSchemas[schemaName] {
+			continue
+		}
+		table.Name = fmt.Sprintf("%s.%s", schemaName, tableName)
+		table.Type = "BASE TABLE"
+		table.ColumnList = columnMap[table.Name]
+		table.IndexList = indexMap[table.Name]
+		table.ForeignKeyList = foreignKeyMap[table.Name]
+		tableList = append(tableList, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return tableList, nil
+}
+
+// getColumns gets the columns of the tables in the current database, keyed by schemaName.tableName.
+func getColumns(ctx context.Context, txn *sql.Tx) (map[string][]db.Column, error) {
+	query := `
+		SELECT
+			s.name,
+			t.name,
+			c.name,
+			c.column_id,
+			dc.definition,
+			c.is_nullable,
+			ty.name,
+			c.max_length,
+			c.precision,
+			c.scale,
+			ISNULL(c.collation_name, ''),
+			ISNULL(CAST(ep.value AS NVARCHAR(MAX)), '')
+		FROM sys.columns c
+		JOIN sys.tables t ON t.object_id = c.object_id
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+		LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+		LEFT JOIN sys.extended_properties ep ON ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.class = 1 AND ep.name = 'MS_Description'
+		WHERE t.is_ms_shipped = 0
+		ORDER BY s.name, t.name, c.column_id`
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	columnMap := make(map[string][]db.Column)
+	for rows.Next() {
+		var schemaName, tableName, typeName string
+		var defaultStr sql.NullString
+		var maxLength, precision, scale int
+		var column db.Column
+		if err := rows.Scan(
+			&schemaName,
+			&tableName,
+			&column.Name,
+			&column.Position,
+			&defaultStr,
+			&column.Nullable,
+			&typeName,
+			&maxLength,
+			&precision,
+			&scale,
+			&column.Collation,
+			&column.Comment,
+		); err != nil {
+			return nil, err
+		}
+		if defaultStr.Valid {
+			column.Default = &defaultStr.String
+		}
+		column.Type = formatColumnType(typeName, maxLength, precision, scale)
+		key := fmt.Sprintf("%s.%s", schemaName, tableName)
+		columnMap[key] = append(columnMap[key], column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return columnMap, nil
+}
+
+// getIndexes gets the indexes of the tables in the current database, keyed by schemaName.tableName.
+// The primary key and the unique constraints are backed by the indexes, so they're synced as the indexes.
+func getIndexes(ctx context.Context, txn *sql.Tx) (map[string][]db.Index, error) {
+	query := `
+		SELECT
+			s.name,
+			t.name,
+			i.name,
+			c.name,
+			ic.key_ordinal,
+			i.type_desc,
+			i.is_unique,
+			i.is_primary_key,
+			i.is_disabled
+		FROM sys.indexes i
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		JOIN sys.tables t ON t.object_id = i.object_id
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		WHERE t.is_ms_shipped = 0 AND i.name IS NOT NULL AND ic.key_ordinal > 0
+		ORDER BY s.name, t.name, i.name, ic.key_ordinal`
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	indexMap := make(map[string][]db.Index)
+	for rows.Next() {
+		var schemaName, tableName string
+		var disabled bool
+		var index db.Index
+		if err := rows.Scan(
+			&schemaName,
+			&tableName,
+			&index.Name,
+			&index.Expression,
+			&index.Position,
+			&index.Type,
+			&index.Unique,
+			&index.Primary,
+			&disabled,
+		); err != nil {
+			return nil, err
+		}
+		index.Visible = !disabled
+		key := fmt.Sprintf("%s.%s", schemaName, tableName)
+		indexMap[key] = append(indexMap[key], index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return indexMap, nil
+}
+
+// getForeignKeys gets the foreign keys of the tables in the current database, keyed by schemaName.tableName.
+func getForeignKeys(ctx context.Context, txn *sql.Tx) (map[string][]db.ForeignKey, error) {
+	query := `
+		SELECT
+			s.name,
+			t.name,
+			fk.name,
+			c.name,
+			fkc.constraint_column_id,
+			rs.name,
+			rt.name,
+			rc.name,
+			fk.delete_referential_action_desc,
+			fk.update_referential_action_desc
+		FROM sys.foreign_keys fk
+		JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		JOIN sys.tables t ON t.object_id = fk.parent_object_id
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		JOIN sys.columns c ON c.object_id = fkc.parent_object_id AND c.column_id = fkc.parent_column_id
+		JOIN sys.tables rt ON rt.object_id = fk.referenced_object_id
+		JOIN sys.schemas rs ON rs.schema_id = rt.schema_id
+		JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE t.is_ms_shipped = 0
+		ORDER BY s.name, t.name, fk.name, fkc.constraint_column_id`
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	foreignKeyMap := make(map[string][]db.ForeignKey)
+	for rows.Next() {
+		var schemaName, tableName, referencedSchemaName, referencedTableName string
+		var foreignKey db.ForeignKey
+		if err := rows.Scan(
+			&schemaName,
+			&tableName,
+			&foreignKey.Name,
+			&foreignKey.Column,
+			&foreignKey.Position,
+			&referencedSchemaName,
+			&referencedTableName,
+			&foreignKey.ReferencedColumn,
+			&foreignKey.OnDelete,
+			&foreignKey.OnUpdate,
+		); err != nil {
+			return nil, err
+		}
+		foreignKey.ReferencedTable = fmt.Sprintf("%s.%s", referencedSchemaName, referencedTableName)
+		// SQL Server describes the actions with underscores, e.g. NO_ACTION and SET_NULL.
+		foreignKey.OnDelete = strings.ReplaceAll(foreignKey.OnDelete, "_", " ")
+		foreignKey.OnUpdate = strings.ReplaceAll(foreignKey.OnUpdate, "_", " ")
+		key := fmt.Sprintf("%s.%s", schemaName, tableName)
+		foreignKeyMap[key] = append(foreignKeyMap[key], foreignKey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return foreignKeyMap, nil
+}
+
+// getViews gets the views of the current database.
+func getViews(ctx context.Context, txn *sql.Tx) ([]db.View, error) {
+	query := `
+		SELECT
+			s.name,
+			v.name,
+			DATEDIFF(SECOND, '1970-01-01', v.create_date),
+			DATEDIFF(SECOND, '1970-01-01', v.modify_date),
+			ISNULL(m.definition, ''),
+			ISNULL(CAST(ep.value AS NVARCHAR(MAX)), '')
+		FROM sys.views v
+		JOIN sys.schemas s ON s.schema_id = v.schema_id
+		LEFT JOIN sys.sql_modules m ON m.object_id = v.object_id
+		LEFT JOIN sys.extended_properties ep ON ep.major_id = v.object_id AND ep.minor_id = 0 AND ep.class = 1 AND ep.name = 'MS_Description'
+		WHERE v.is_ms_shipped = 0
+		ORDER BY s.name, v.name`
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var viewList []db.View
+	for rows.Next() {
+		var schemaName, viewName string
+		var view db.View
+		if err := rows.Scan(
+			&schemaName,
+			&viewName,
+			&view.CreatedTs,
+			&view.UpdatedTs,
+			&view.Definition,
+			&view.Comment,
+		); err != nil {
+			return nil, err
+		}
+		if systemSchemas[schemaName] {
+			continue
+		}
+		view.Name = fmt.Sprintf("%s.%s", schemaName, viewName)
+		viewList = append(viewList, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return viewList, nil
+}
+
+// formatColumnType formats the column type with its length, precision or scale, e.g. nvarchar(64), decimal(10, 2) and varchar(max).
+// The max length of the sys.columns is in bytes, so it's halved for the Unicode types.
+func formatColumnType(typeName string, maxLength, precision, scale int) string {
+	switch strings.ToLower(typeName) {
+	case "char", "varchar", "binary", "varbinary":
+		if maxLength == -1 {
+			return fmt.Sprintf("%s(max)", typeName)
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength)
+	case "nchar", "nvarchar":
+		if maxLength == -1 {
+			return fmt.Sprintf("%s(max)", typeName)
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength/2)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d, %d)", typeName, precision, scale)
+	case "datetime2", "datetimeoffset", "time":
+		return fmt.Sprintf("%s(%d)", typeName, scale)
+	}
+	return typeName
+}
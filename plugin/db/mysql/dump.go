@@ -9,6 +9,7 @@ import (
 	"io"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
 	"github.com/bytebase/bytebase/resources/mysqlutil"
 )
@@ -112,8 +114,8 @@ func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer,
 	}
 
 	options := sql.TxOptions{}
-	// TiDB does not support readonly, so we only set for MySQL.
-	if driver.dbType == "MYSQL" {
+	// TiDB does not support readonly, so we only set for MySQL and MariaDB.
+	if driver.dbType == db.MySQL || driver.dbType == db.MariaDB {
 		options.ReadOnly = true
 	}
 	// If `schemaOnly` is false, now we are still holding the tables' exclusive locks.
@@ -157,7 +159,7 @@ func FlushTablesWithReadLock(ctx context.Context, conn *sql.Conn, database strin
 
 	var tableNames []string
 	for _, table := range tables {
-		if table.TableType != baseTableType {
+		if table.TableType != baseTableType && table.TableType != systemVersionedTableType {
 			continue
 		}
 		tableNames = append(tableNames, fmt.Sprintf("`%s`", table.Name))
@@ -228,16 +230,33 @@ func dumpTxn(ctx context.Context, txn *sql.Tx, database string, out io.Writer, s
 			return fmt.Errorf("failed to get tables of database %q, error: %w", dbName, err)
 		}
 		for _, tbl := range tables {
-			if schemaOnly && tbl.TableType == baseTableType {
+			isTable := tbl.TableType == baseTableType || tbl.TableType == systemVersionedTableType
+			if schemaOnly && isTable {
 				tbl.Statement = excludeSchemaAutoIncrementValue(tbl.Statement)
 			}
 			if _, err := io.WriteString(out, fmt.Sprintf("%s\n", tbl.Statement)); err != nil {
 				return err
 			}
-			if !schemaOnly && tbl.TableType == baseTableType {
-				// Include db prefix if dumping multiple databases.
-				includeDbPrefix := len(dumpableDbNames) > 1
-				if err := exportTableData(txn, dbName, tbl.Name, includeDbPrefix, out); err != nil {
+			if schemaOnly {
+				continue
+			}
+			// Include db prefix if dumping multiple databases.
+			includeDbPrefix := len(dumpableDbNames) > 1
+			switch tbl.TableType {
+			case baseTableType:
+				if err := exportTableData(txn, dbName, tbl.Name, nil /* columnList */, includeDbPrefix, out); err != nil {
+					return err
+				}
+			case systemVersionedTableType:
+				columnList, err := getSystemVersionedColumnList(txn, dbName, tbl.Name)
+				if err != nil {
+					return fmt.Errorf("failed to get columns of table %q, error: %w", tbl.Name, err)
+				}
+				if err := exportTableData(txn, dbName, tbl.Name, columnList, includeDbPrefix, out); err != nil {
+					return err
+				}
+			case sequenceTableType:
+				if err := exportSequenceValue(txn, dbName, tbl.Name, includeDbPrefix, out); err != nil {
 					return err
 				}
 			}
@@ -379,6 +398,10 @@ func getTablesImpl(txn *sql.Tx, dbName string) ([]*TableSchema, error) {
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	// The MariaDB sequences go first, since the tables may use them in the default values.
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].TableType == sequenceTableType && tables[j].TableType != sequenceTableType
+	})
 	for _, tbl := range tables {
 		stmt, err := getTableStmt(txn, dbName, tbl.Name, tbl.TableType)
 		if err != nil {
@@ -392,7 +415,7 @@ func getTablesImpl(txn *sql.Tx, dbName string) ([]*TableSchema, error) {
 // getTableStmt gets the create statement of a table.
 func getTableStmt(txn *sql.Tx, dbName, tblName, tblType string) (string, error) {
 	switch tblType {
-	case baseTableType:
+	case baseTableType, systemVersionedTableType:
 		query := fmt.Sprintf("SHOW CREATE TABLE `%s`.`%s`;", dbName, tblName)
 		var stmt, unused string
 		if err := txn.QueryRow(query).Scan(&unused, &stmt); err != nil {
@@ -413,14 +436,25 @@ func getTableStmt(txn *sql.Tx, dbName, tblName, tblType string) (string, error)
 			return "", err
 		}
 		return fmt.Sprintf(viewStmtFmt, tblName, createStmt), nil
+	case sequenceTableType:
+		return getSequenceStmt(txn, dbName, tblName)
 	default:
 		return "", fmt.Errorf("unrecognized table type %q for database %q table %q", tblType, dbName, tblName)
 	}
 }
 
-// exportTableData gets the data of a table.
-func exportTableData(txn *sql.Tx, dbName, tblName string, includeDbPrefix bool, out io.Writer) error {
-	query := fmt.Sprintf("SELECT * FROM `%s`.`%s`;", dbName, tblName)
+// exportTableData gets the data of a table, columnList is the columns to export, or nil for all the columns.
+func exportTableData(txn *sql.Tx, dbName, tblName string, columnList []string, includeDbPrefix bool, out io.Writer) error {
+	selectList, insertColumns := "*", ""
+	if columnList != nil {
+		var quotedList []string
+		for _, column := range columnList {
+			quotedList = append(quotedList, fmt.Sprintf("`%s`", column))
+		}
+		selectList = strings.Join(quotedList, ", ")
+		insertColumns = fmt.Sprintf(" (%s)", selectList)
+	}
+	query := fmt.Sprintf("SELECT %s FROM `%s`.`%s`;", selectList, dbName, tblName)
 	rows, err := txn.Query(query)
 	if err != nil {
 		return err
@@ -458,7 +492,7 @@ func exportTableData(txn *sql.Tx, dbName, tblName string, includeDbPrefix bool,
 		if includeDbPrefix {
 			dbPrefix = fmt.Sprintf("`%s`.", dbName)
 		}
-		stmt := fmt.Sprintf("INSERT INTO %s`%s` VALUES (%s);\n", dbPrefix, tblName, strings.Join(tokens, ", "))
+		stmt := fmt.Sprintf("INSERT INTO %s`%s`%s VALUES (%s);\n", dbPrefix, tblName, insertColumns, strings.Join(tokens, ", "))
 		if _, err := io.WriteString(out, stmt); err != nil {
 			return err
 		}
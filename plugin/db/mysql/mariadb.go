@@ -0,0 +1,132 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+const (
+	sequenceStmtFmt = "" +
+		"--\n" +
+		"-- Sequence structure for `%s`\n" +
+		"--\n" +
+		"%s;\n"
+	// setSequenceValueFmt restores the next value of the sequence, the same as mariadb-dump.
+	setSequenceValueFmt = "SELECT SETVAL(%s`%s`, %d, 0);\n\n"
+)
+
+// isMariaDB returns whether the server is MariaDB.
+func (driver *Driver) isMariaDB(ctx context.Context) (bool, error) {
+	if driver.dbType == db.MariaDB {
+		return true, nil
+	}
+	if !driver.dialectDetected {
+		version, err := driver.getVersion(ctx)
+		if err != nil {
+			return false, err
+		}
+		driver.mariaDB = isMariaDBVersion(version)
+		driver.dialectDetected = true
+	}
+	return driver.mariaDB, nil
+}
+
+// isMariaDBVersion returns whether the result of VERSION() is reported by MariaDB, e.g. "10.6.11-MariaDB-1:10.6.11+maria~ubu2004".
+func isMariaDBVersion(version string) bool {
+	return strings.Contains(strings.ToLower(version), "mariadb")
+}
+
+// getMariaDBSequenceList returns the sequences of the database. MariaDB has no catalog of the sequence options before 11.5,
+// so they're read from each sequence, which is a table of a single row.
+func getMariaDBSequenceList(ctx context.Context, sqldb *sql.DB, databaseName string, nameList []string) ([]db.Sequence, error) {
+	var sequenceList []db.Sequence
+	for _, name := range nameList {
+		query := fmt.Sprintf("SELECT start_value, minimum_value, maximum_value, increment, cache_size, cycle_option FROM `%s`.`%s`", databaseName, name)
+		sequence := db.Sequence{
+			Name: name,
+			// The sequences are BIGINT unless they're created with AS since 11.5, which isn't in the sequence table.
+			DataType: "bigint",
+		}
+		if err := sqldb.QueryRowContext(ctx, query).Scan(
+			&sequence.Start,
+			&sequence.MinValue,
+			&sequence.MaxValue,
+			&sequence.Increment,
+			&sequence.Cache,
+			&sequence.Cycle,
+		); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+			}
+			return nil, util.FormatErrorWithQuery(err, query)
+		}
+		sequenceList = append(sequenceList, sequence)
+	}
+	return sequenceList, nil
+}
+
+// getSequenceStmt gets the create statement of a MariaDB sequence.
+func getSequenceStmt(txn *sql.Tx, dbName, sequenceName string) (string, error) {
+	query := fmt.Sprintf("SHOW CREATE SEQUENCE `%s`.`%s`;", dbName, sequenceName)
+	var stmt, unused string
+	if err := txn.QueryRow(query).Scan(&unused, &stmt); err != nil {
+		if err == sql.ErrNoRows {
+			return "", common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return "", err
+	}
+	return fmt.Sprintf(sequenceStmtFmt, sequenceName, stmt), nil
+}
+
+// exportSequenceValue writes the statement restoring the next value of a MariaDB sequence.
+func exportSequenceValue(txn *sql.Tx, dbName, sequenceName string, includeDbPrefix bool, out io.Writer) error {
+	query := fmt.Sprintf("SELECT next_not_cached_value FROM `%s`.`%s`;", dbName, sequenceName)
+	var nextValue int64
+	if err := txn.QueryRow(query).Scan(&nextValue); err != nil {
+		if err == sql.ErrNoRows {
+			return common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return err
+	}
+	dbPrefix := ""
+	if includeDbPrefix {
+		dbPrefix = fmt.Sprintf("`%s`.", dbName)
+	}
+	_, err := io.WriteString(out, fmt.Sprintf(setSequenceValueFmt, dbPrefix, sequenceName, nextValue))
+	return err
+}
+
+// getSystemVersionedColumnList returns the columns of a MariaDB system-versioned table except the row start and end columns,
+// which are generated by the server and can't be inserted. The history rows aren't dumped, the same as mariadb-dump by default.
+func getSystemVersionedColumnList(txn *sql.Tx, dbName, tableName string) ([]string, error) {
+	query := `
+		SELECT COLUMN_NAME
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND EXTRA NOT LIKE '%ROW START%' AND EXTRA NOT LIKE '%ROW END%' AND EXTRA NOT LIKE '%INVISIBLE%'
+		ORDER BY ORDINAL_POSITION`
+	rows, err := txn.Query(query, dbName, tableName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var columnList []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columnList = append(columnList, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return columnList, nil
+}
@@ -17,6 +17,9 @@ import (
 var (
 	baseTableType = "BASE TABLE"
 	viewTableType = "VIEW"
+	// The table types only MariaDB has.
+	systemVersionedTableType = "SYSTEM VERSIONED"
+	sequenceTableType        = "SEQUENCE"
 
 	_ db.Driver = (*Driver)(nil)
 )
@@ -24,6 +27,7 @@ var (
 func init() {
 	db.Register(db.MySQL, newDriver)
 	db.Register(db.TiDB, newDriver)
+	db.Register(db.MariaDB, newDriver)
 }
 
 // Driver is the MySQL driver.
@@ -36,6 +40,11 @@ type Driver struct {
 	db            *sql.DB
 
 	replayBinlogCounter *common.CountingReader
+
+	// dialectDetected is set once the dialect is detected, and mariaDB is set if the server is MariaDB.
+	// MariaDB may be added as a MySQL instance, so it's detected from the server rather than the database type.
+	dialectDetected bool
+	mariaDB         bool
 }
 
 func newDriver(dc db.DriverConfig) db.Driver {
@@ -150,8 +159,35 @@ func (driver *Driver) getVersion(ctx context.Context) (string, error) {
 }
 
 // Execute executes a SQL statement.
+// The session settings carried by ctx are applied to the connection executing the statement, and reset afterwards.
 func (driver *Driver) Execute(ctx context.Context, statement string) error {
-	tx, err := driver.db.BeginTx(ctx, nil)
+	var txOptions *sql.TxOptions
+	var setList, resetList []string
+	if setting := db.SessionSettingFromContext(ctx); setting != nil {
+		txOptions = setting.GetTxOptions()
+		setList, resetList = getSessionSettingStatements(setting)
+	}
+
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(resetList) > 0 {
+		// The session variables would otherwise leak to the later use of the pooled connection.
+		defer func() {
+			if _, err := conn.ExecContext(context.Background(), strings.Join(resetList, "\n")); err != nil {
+				log.Warn("Failed to reset the session settings", zap.Error(err))
+			}
+		}()
+	}
+	if len(setList) > 0 {
+		if _, err := conn.ExecContext(ctx, strings.Join(setList, "\n")); err != nil {
+			return err
+		}
+	}
+
+	tx, err := conn.BeginTx(ctx, txOptions)
 	if err != nil {
 		return err
 	}
@@ -168,7 +204,42 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 	return err
 }
 
+// getSessionSettingStatements returns the statements setting the session variables, and the ones resetting them to the global values.
+// The isolation level is set by the transaction options instead.
+func getSessionSettingStatements(setting *db.SessionSetting) ([]string, []string) {
+	var setList, resetList []string
+	set := func(name, value string) {
+		setList = append(setList, fmt.Sprintf("SET SESSION %s = %s;", name, value))
+		resetList = append(resetList, fmt.Sprintf("SET SESSION %s = DEFAULT;", name))
+	}
+	if setting.LockTimeoutMs > 0 {
+		// Both timeouts are in seconds, so the lock timeout is rounded up.
+		seconds := (setting.LockTimeoutMs + 999) / 1000
+		set("lock_wait_timeout", fmt.Sprintf("%d", seconds))
+		set("innodb_lock_wait_timeout", fmt.Sprintf("%d", seconds))
+	}
+	if setting.SQLMode != nil {
+		set("sql_mode", fmt.Sprintf("'%s'", *setting.SQLMode))
+	}
+	if setting.ForeignKeyChecks != nil {
+		value := "0"
+		if *setting.ForeignKeyChecks {
+			value = "1"
+		}
+		set("foreign_key_checks", value)
+	}
+	return setList, resetList
+}
+
 // Query queries a SQL statement.
 func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
 	return util.Query(ctx, driver.db, statement, limit)
 }
+
+// Capabilities returns the features the engine supports, MariaDB is told apart once detected even if it's added as a MySQL instance.
+func (driver *Driver) Capabilities() db.Capabilities {
+	if driver.mariaDB {
+		return db.GetCapabilities(db.MariaDB)
+	}
+	return db.GetCapabilities(driver.dbType)
+}
@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsMariaDBVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"10.6.11-MariaDB-1:10.6.11+maria~ubu2004", true},
+		{"10.3.36-MariaDB-log", true},
+		{"8.0.28", false},
+		{"5.7.38-log", false},
+		{"5.7.25-TiDB-v6.1.0", false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, isMariaDBVersion(test.version), test.version)
+	}
+}
@@ -20,6 +20,28 @@ var (
 		"performance_schema": true,
 		"sys":                true,
 	}
+	// syncedParameterList is the key system variables synced from the instance.
+	// Some of them aren't available in TiDB, and they are skipped.
+	syncedParameterList = []string{
+		"max_connections",
+		"sql_mode",
+		"innodb_flush_log_at_trx_commit",
+		"sync_binlog",
+		"innodb_buffer_pool_size",
+		"binlog_format",
+		"log_bin",
+		"gtid_mode",
+		"transaction_isolation",
+		"character_set_server",
+		"collation_server",
+		"time_zone",
+		"lower_case_table_names",
+		"max_allowed_packet",
+		"long_query_time",
+		"slow_query_log",
+		"read_only",
+		"default_password_lifetime",
+	}
 )
 
 // SyncInstance syncs the instance.
@@ -35,6 +57,20 @@ func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error
 		return nil, err
 	}
 
+	parameterList, err := driver.getParameterList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mariaDB, err := driver.isMariaDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var engine db.Type
+	if mariaDB && driver.dbType != db.MariaDB {
+		engine = db.MariaDB
+	}
+
 	excludedDatabaseList := []string{
 		// Skip our internal "bytebase" database
 		"'bytebase'",
@@ -76,9 +112,11 @@ func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error
 	}
 
 	return &db.InstanceMeta{
-		Version:      version,
-		UserList:     userList,
-		DatabaseList: databaseList,
+		Engine:        engine,
+		Version:       version,
+		UserList:      userList,
+		ParameterList: parameterList,
+		DatabaseList:  databaseList,
 	}, nil
 }
 
@@ -173,6 +211,65 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 		return nil, util.FormatErrorWithQuery(err, indexQuery)
 	}
 
+	// Query foreign key info
+	foreignKeyWhere := fmt.Sprintf("LOWER(k.TABLE_SCHEMA) = '%s' AND k.REFERENCED_TABLE_NAME IS NOT NULL", strings.ToLower(databaseName))
+	foreignKeyQuery := `
+			SELECT
+				k.TABLE_SCHEMA,
+				k.TABLE_NAME,
+				k.CONSTRAINT_NAME,
+				k.COLUMN_NAME,
+				k.ORDINAL_POSITION,
+				k.REFERENCED_TABLE_SCHEMA,
+				k.REFERENCED_TABLE_NAME,
+				k.REFERENCED_COLUMN_NAME,
+				IFNULL(r.DELETE_RULE, 'NO ACTION'),
+				IFNULL(r.UPDATE_RULE, 'NO ACTION')
+			FROM information_schema.KEY_COLUMN_USAGE AS k
+			LEFT JOIN information_schema.REFERENTIAL_CONSTRAINTS AS r
+				ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+			WHERE ` + foreignKeyWhere
+	foreignKeyRows, err := driver.db.QueryContext(ctx, foreignKeyQuery)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, foreignKeyQuery)
+	}
+	defer foreignKeyRows.Close()
+
+	// dbName/tableName -> foreignKeyList map
+	foreignKeyMap := make(map[string][]db.ForeignKey)
+	for foreignKeyRows.Next() {
+		var dbName string
+		var tableName string
+		var referencedDBName string
+		var referencedTableName string
+		var foreignKey db.ForeignKey
+		if err := foreignKeyRows.Scan(
+			&dbName,
+			&tableName,
+			&foreignKey.Name,
+			&foreignKey.Column,
+			&foreignKey.Position,
+			&referencedDBName,
+			&referencedTableName,
+			&foreignKey.ReferencedColumn,
+			&foreignKey.OnDelete,
+			&foreignKey.OnUpdate,
+		); err != nil {
+			return nil, err
+		}
+
+		foreignKey.ReferencedTable = referencedTableName
+		if referencedDBName != dbName {
+			foreignKey.ReferencedTable = fmt.Sprintf("%s.%s", referencedDBName, referencedTableName)
+		}
+
+		key := fmt.Sprintf("%s/%s", dbName, tableName)
+		foreignKeyMap[key] = append(foreignKeyMap[key], foreignKey)
+	}
+	if err := foreignKeyRows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, foreignKeyQuery)
+	}
+
 	// Query column info
 	columnWhere := fmt.Sprintf("LOWER(TABLE_SCHEMA) = '%s'", strings.ToLower(databaseName))
 	columnQuery := `
@@ -267,6 +364,8 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 	}
 	// dbName/viewName -> ViewInfo
 	viewInfoMap := make(map[string]ViewInfo)
+	// dbName -> sequenceNameList map, the sequences are only found in MariaDB.
+	sequenceNameMap := make(map[string][]string)
 	for tableRows.Next() {
 		var dbName string
 		// Workaround TiDB bug https://github.com/pingcap/tidb/issues/27970
@@ -291,7 +390,7 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 		}
 
 		switch table.Type {
-		case baseTableType:
+		case baseTableType, systemVersionedTableType:
 			if tableCollation.Valid {
 				table.Collation = tableCollation.String
 			}
@@ -299,6 +398,7 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 			key := fmt.Sprintf("%s/%s", dbName, table.Name)
 			table.ColumnList = columnMap[key]
 			table.IndexList = indexMap[key]
+			table.ForeignKeyList = foreignKeyMap[key]
 
 			if tableList, ok := tableMap[dbName]; ok {
 				tableMap[dbName] = append(tableList, table)
@@ -311,6 +411,8 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 				updatedTs: table.UpdatedTs,
 				comment:   table.Comment,
 			}
+		case sequenceTableType:
+			sequenceNameMap[dbName] = append(sequenceNameMap[dbName], table.Name)
 		}
 	}
 	if err := tableRows.Err(); err != nil {
@@ -381,10 +483,38 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 	}
 	schema.TableList = tableMap[schema.Name]
 	schema.ViewList = viewMap[schema.Name]
+	if nameList := sequenceNameMap[schema.Name]; len(nameList) > 0 {
+		if schema.SequenceList, err = getMariaDBSequenceList(ctx, driver.db, schema.Name, nameList); err != nil {
+			return nil, err
+		}
+	}
 
 	return &schema, err
 }
 
+// getParameterList returns the synced global system variables.
+func (driver *Driver) getParameterList(ctx context.Context) ([]db.Parameter, error) {
+	query := "SHOW GLOBAL VARIABLES WHERE Variable_name IN ('" + strings.Join(syncedParameterList, "', '") + "')"
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var parameterList []db.Parameter
+	for rows.Next() {
+		var parameter db.Parameter
+		if err := rows.Scan(&parameter.Name, &parameter.Value); err != nil {
+			return nil, err
+		}
+		parameterList = append(parameterList, parameter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return parameterList, nil
+}
+
 func (driver *Driver) getUserList(ctx context.Context) ([]db.User, error) {
 	// Query user info
 	userQuery := `
@@ -0,0 +1,325 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// cockroachSetLocalMinVersion is the earliest CockroachDB version supporting SET LOCAL and lock_timeout.
+	cockroachSetLocalMinVersion = "21.2"
+	// cockroachRowStatisticsMinVersion is the earliest CockroachDB version estimating the row counts in crdb_internal.table_row_statistics.
+	cockroachRowStatisticsMinVersion = "21.1"
+)
+
+var (
+	// cockroachVersionRegexp matches the CockroachDB version in the result of version(),
+	// e.g. "CockroachDB CCL v22.1.0 (x86_64-pc-linux-gnu, built 2022/05/23 16:27:47, go1.17.6)".
+	cockroachVersionRegexp = regexp.MustCompile(`CockroachDB \w+ v(\d+\.\d+\.\d+\S*)`)
+	// cockroachSystemDatabases is the databases for the internal use of CockroachDB.
+	cockroachSystemDatabases = map[string]bool{
+		"system": true,
+	}
+	// cockroachSystemSchemaList is the schemas of the catalogs and the virtual tables of CockroachDB.
+	cockroachSystemSchemaList = []string{"pg_catalog", "information_schema", "crdb_internal", "pg_extension"}
+)
+
+// getCockroachVersion returns the CockroachDB version in the result of version(), or empty if the server isn't CockroachDB.
+func getCockroachVersion(version string) string {
+	matches := cockroachVersionRegexp.FindStringSubmatch(version)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// cockroachVersionAtLeast returns whether the CockroachDB version, e.g. "22.1.0", is the same as or later than the minimum version, e.g. "21.2".
+func cockroachVersionAtLeast(version, minVersion string) bool {
+	var major, minor, minMajor, minMinor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err != nil {
+		return false
+	}
+	if _, err := fmt.Sscanf(minVersion, "%d.%d", &minMajor, &minMinor); err != nil {
+		return false
+	}
+	return major > minMajor || (major == minMajor && minor >= minMinor)
+}
+
+// isCockroachDB returns whether the server is CockroachDB.
+func (driver *Driver) isCockroachDB(ctx context.Context) (bool, error) {
+	if err := driver.detectDialect(ctx); err != nil {
+		return false, err
+	}
+	return driver.cockroachVersion != "", nil
+}
+
+// syncCockroachSchema syncs the tables, the indexes and the views of a CockroachDB database.
+// CockroachDB doesn't support the triggers, the partitions in the Postgres sense and the extensions, and the serial columns
+// default to unique_rowid() rather than the sequences, so they're left empty.
+func syncCockroachSchema(ctx context.Context, txn *sql.Tx, schema *db.Schema, version string) error {
+	commentMap, err := getCockroachRelationComments(txn)
+	if err != nil {
+		return fmt.Errorf("failed to get comments: %s", err)
+	}
+
+	indicesMap := make(map[string][]*indexSchema)
+	indices, err := getCockroachIndices(txn)
+	if err != nil {
+		return fmt.Errorf("failed to get indices: %s", err)
+	}
+	for _, idx := range indices {
+		key := fmt.Sprintf("%s.%s", idx.schemaName, idx.tableName)
+		indicesMap[key] = append(indicesMap[key], idx)
+	}
+
+	tables, err := getCockroachTables(txn, version, db.IsExactRowCount(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get tables: %s", err)
+	}
+	for _, tbl := range tables {
+		dbTable := db.Table{
+			Name:     fmt.Sprintf("%s.%s", tbl.schemaName, tbl.name),
+			Type:     "BASE TABLE",
+			Owner:    tbl.tableowner,
+			RowCount: tbl.rowCount,
+		}
+		dbTable.Comment = commentMap[dbTable.Name]
+		for _, col := range tbl.columns {
+			dbTable.ColumnList = append(dbTable.ColumnList, db.Column{
+				Name:      col.columnName,
+				Position:  col.ordinalPosition,
+				Default:   &col.columnDefault,
+				Type:      col.dataType,
+				Nullable:  col.isNullable,
+				Collation: col.collationName,
+				Comment:   col.comment,
+			})
+		}
+		for _, idx := range indicesMap[dbTable.Name] {
+			for i, colExp := range idx.columnExpressions {
+				dbTable.IndexList = append(dbTable.IndexList, db.Index{
+					Name:       idx.name,
+					Expression: colExp,
+					Position:   i + 1,
+					Type:       idx.methodType,
+					Unique:     idx.unique,
+					Primary:    idx.primary,
+					Comment:    idx.comment,
+				})
+			}
+		}
+		for _, constraint := range tbl.constraints {
+			if constraint.constraintType != "f" {
+				continue
+			}
+			foreignKeyList, err := parseForeignKey(constraint)
+			if err != nil {
+				return fmt.Errorf("failed to parse foreign key %q of table %q: %s", constraint.name, dbTable.Name, err)
+			}
+			dbTable.ForeignKeyList = append(dbTable.ForeignKeyList, foreignKeyList...)
+		}
+		schema.TableList = append(schema.TableList, dbTable)
+	}
+
+	views, err := listViews(txn, cockroachSystemSchemaList...)
+	if err != nil {
+		return fmt.Errorf("failed to get views: %s", err)
+	}
+	for _, view := range views {
+		name := fmt.Sprintf("%s.%s", view.schemaName, view.name)
+		schema.ViewList = append(schema.ViewList, db.View{
+			Name:       name,
+			CreatedTs:  time.Now().Unix(),
+			Definition: view.definition,
+			Comment:    commentMap[name],
+		})
+	}
+	return nil
+}
+
+// getCockroachTables gets all tables of a CockroachDB database.
+// CockroachDB doesn't report the table sizes in the catalogs, so the row counts are estimated from the table statistics
+// unless exactRowCount is set. The rows are counted exactly on the versions without the table statistics.
+func getCockroachTables(txn *sql.Tx, version string, exactRowCount bool) ([]*tableSchema, error) {
+	constraints, err := getTableConstraints(txn)
+	if err != nil {
+		return nil, fmt.Errorf("getTableConstraints() got error: %v", err)
+	}
+
+	var tables []*tableSchema
+	query := "" +
+		"SELECT schemaname, tablename, tableowner FROM pg_catalog.pg_tables " +
+		"WHERE schemaname NOT IN ('" + strings.Join(cockroachSystemSchemaList, "', '") + "');"
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tbl tableSchema
+		if err := rows.Scan(&tbl.schemaName, &tbl.name, &tbl.tableowner); err != nil {
+			return nil, err
+		}
+		tables = append(tables, &tbl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var estimatedRowCountMap map[string]int64
+	if cockroachVersionAtLeast(version, cockroachRowStatisticsMinVersion) {
+		if estimatedRowCountMap, err = getCockroachEstimatedRowCounts(txn); err != nil {
+			return nil, fmt.Errorf("getCockroachEstimatedRowCounts() got error: %v", err)
+		}
+	} else {
+		exactRowCount = true
+	}
+	for _, tbl := range tables {
+		key := fmt.Sprintf("%s.%s", tbl.schemaName, tbl.name)
+		if exactRowCount {
+			if err := getTableRowCount(txn, tbl); err != nil {
+				return nil, fmt.Errorf("getTableRowCount(%q, %q) got error %v", tbl.schemaName, tbl.name, err)
+			}
+		} else {
+			tbl.rowCount = estimatedRowCountMap[key]
+		}
+		columns, err := getCockroachTableColumns(txn, tbl.schemaName, tbl.name)
+		if err != nil {
+			return nil, fmt.Errorf("getCockroachTableColumns(%q, %q) got error %v", tbl.schemaName, tbl.name, err)
+		}
+		tbl.columns = columns
+		tbl.constraints = constraints[key]
+	}
+	return tables, nil
+}
+
+// getCockroachEstimatedRowCounts gets the row counts estimated from the table statistics, keyed by the table name qualified by the schema name.
+// The table ID of CockroachDB is the OID of the table in pg_class.
+func getCockroachEstimatedRowCounts(txn *sql.Tx) (map[string]int64, error) {
+	query := "" +
+		"SELECT n.nspname, c.relname, COALESCE(s.estimated_row_count, 0) " +
+		"FROM crdb_internal.table_row_statistics s " +
+		"JOIN pg_catalog.pg_class c ON c.oid = s.table_id " +
+		"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace;"
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rowCountMap := make(map[string]int64)
+	for rows.Next() {
+		var schemaName, tableName string
+		var rowCount int64
+		if err := rows.Scan(&schemaName, &tableName, &rowCount); err != nil {
+			return nil, err
+		}
+		rowCountMap[fmt.Sprintf("%s.%s", schemaName, tableName)] = rowCount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rowCountMap, nil
+}
+
+// getCockroachTableColumns gets the columns of a CockroachDB table.
+// The hidden columns, e.g. the rowid added to the tables without the primary key, are excluded, and the comments are joined
+// from pg_description by the column ID, which differs from the ordinal position once a column is dropped.
+func getCockroachTableColumns(txn *sql.Tx, schemaName, tableName string) ([]*columnSchema, error) {
+	query := `
+	SELECT
+		cols.column_name,
+		cols.data_type,
+		cols.ordinal_position,
+		cols.character_maximum_length,
+		cols.column_default,
+		cols.is_nullable,
+		cols.collation_name,
+		cols.udt_schema,
+		cols.udt_name,
+		d.description
+	FROM information_schema.columns AS cols
+	JOIN pg_catalog.pg_namespace n ON n.nspname = cols.table_schema
+	JOIN pg_catalog.pg_class c ON c.relnamespace = n.oid AND c.relname = cols.table_name
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attname = cols.column_name
+	LEFT JOIN pg_catalog.pg_description d ON d.objoid = c.oid AND d.objsubid = a.attnum
+	WHERE cols.table_schema = $1 AND cols.table_name = $2 AND cols.is_hidden = 'NO'
+	ORDER BY cols.ordinal_position;`
+	rows, err := txn.Query(query, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableColumns(rows)
+}
+
+// getCockroachIndices gets all indices of a CockroachDB database.
+// The index names are only unique in the table rather than the schema, so the comments are joined by the index OID.
+func getCockroachIndices(txn *sql.Tx) ([]*indexSchema, error) {
+	query := "" +
+		"SELECT n.nspname, t.relname, i.relname, pg_get_indexdef(x.indexrelid), x.indisprimary, COALESCE(d.description, '') " +
+		"FROM pg_catalog.pg_index x " +
+		"JOIN pg_catalog.pg_class t ON t.oid = x.indrelid " +
+		"JOIN pg_catalog.pg_class i ON i.oid = x.indexrelid " +
+		"JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace " +
+		"LEFT JOIN pg_catalog.pg_description d ON d.objoid = x.indexrelid AND d.objsubid = 0 " +
+		"WHERE n.nspname NOT IN ('" + strings.Join(cockroachSystemSchemaList, "', '") + "');"
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indices []*indexSchema
+	for rows.Next() {
+		var idx indexSchema
+		if err := rows.Scan(&idx.schemaName, &idx.tableName, &idx.name, &idx.statement, &idx.primary, &idx.comment); err != nil {
+			return nil, err
+		}
+		if err := parseIndexStatement(&idx); err != nil {
+			return nil, err
+		}
+		indices = append(indices, &idx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return indices, nil
+}
+
+// getCockroachRelationComments gets the comments of the tables and the views, keyed by the name qualified by the schema name.
+// CockroachDB doesn't support obj_description, so the comments are read from pg_description.
+func getCockroachRelationComments(txn *sql.Tx) (map[string]string, error) {
+	query := "" +
+		"SELECT n.nspname, c.relname, d.description " +
+		"FROM pg_catalog.pg_description d " +
+		"JOIN pg_catalog.pg_class c ON c.oid = d.objoid " +
+		"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace " +
+		"WHERE d.objsubid = 0 AND c.relkind IN ('r', 'v');"
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	commentMap := make(map[string]string)
+	for rows.Next() {
+		var schemaName, name, comment string
+		if err := rows.Scan(&schemaName, &name, &comment); err != nil {
+			return nil, err
+		}
+		commentMap[fmt.Sprintf("%s.%s", schemaName, name)] = comment
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return commentMap, nil
+}
@@ -164,6 +164,7 @@ func (Driver) FindLargestSequence(ctx context.Context, tx *sql.Tx, namespace str
 
 // InsertPendingHistory will insert the migration record with pending status and return the inserted ID.
 func (Driver) InsertPendingHistory(ctx context.Context, tx *sql.Tx, sequence int, prevSchema string, m *db.MigrationInfo, storedVersion, statement string) (int64, error) {
+	// The epoch is cast explicitly since CockroachDB doesn't cast the decimal to the BIGINT column on assignment.
 	const insertHistoryQuery = `
 	INSERT INTO migration_history (
 		created_by,
@@ -185,7 +186,7 @@ func (Driver) InsertPendingHistory(ctx context.Context, tx *sql.Tx, sequence int
 		issue_id,
 		payload
 	)
-	VALUES ($1, EXTRACT(epoch from NOW()), $2, EXTRACT(epoch from NOW()), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, 0, $14, $15)
+	VALUES ($1, EXTRACT(epoch from NOW())::BIGINT, $2, EXTRACT(epoch from NOW())::BIGINT, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, 0, $14, $15)
 	RETURNING id
 	`
 	var insertedID int64
@@ -45,6 +45,12 @@ type Driver struct {
 
 	// strictDatabase should be used only if the user gives only a database instead of a whole instance to access.
 	strictDatabase string
+
+	// dialectDetected is set once the dialect is detected. redshiftVersion is set if the server is Amazon Redshift,
+	// and cockroachVersion is set if the server is CockroachDB.
+	dialectDetected  bool
+	redshiftVersion  string
+	cockroachVersion string
 }
 
 func newDriver(config db.DriverConfig) db.Driver {
@@ -178,8 +184,20 @@ func (driver *Driver) GetDBConnection(_ context.Context, database string) (*sql.
 
 // getDatabases gets all databases of an instance.
 func (driver *Driver) getDatabases(ctx context.Context) ([]*pgDatabaseSchema, error) {
+	if err := driver.detectDialect(ctx); err != nil {
+		return nil, err
+	}
+	query := "SELECT datname, pg_encoding_to_char(encoding), datcollate FROM pg_database;"
+	switch {
+	case driver.redshiftVersion != "":
+		// Redshift doesn't support the collations.
+		query = "SELECT datname, pg_encoding_to_char(encoding), '' FROM pg_database;"
+	case driver.cockroachVersion != "":
+		// CockroachDB only supports the UTF8 encoding.
+		query = "SELECT datname, 'UTF8', datcollate FROM pg_database;"
+	}
 	var dbs []*pgDatabaseSchema
-	rows, err := driver.db.QueryContext(ctx, "SELECT datname, pg_encoding_to_char(encoding), datcollate FROM pg_database;")
+	rows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -198,8 +216,39 @@ func (driver *Driver) getDatabases(ctx context.Context) ([]*pgDatabaseSchema, er
 	return dbs, nil
 }
 
-// getVersion gets the version of Postgres server.
+// detectDialect detects whether the server is Amazon Redshift or CockroachDB, which speak the Postgres wire protocol
+// but differ in the catalogs. Redshift lacks most of the catalogs and the functions added since Postgres 8.0,
+// e.g. pg_indexes_size and regnamespace, and CockroachDB lacks the size functions, e.g. pg_table_size, and obj_description.
+// It's detected once since all databases of an instance have the same dialect.
+func (driver *Driver) detectDialect(ctx context.Context) error {
+	if driver.dialectDetected {
+		return nil
+	}
+	query := "SELECT version()"
+	var version string
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return util.FormatErrorWithQuery(err, query)
+	}
+	driver.redshiftVersion = getRedshiftVersion(version)
+	driver.cockroachVersion = getCockroachVersion(version)
+	driver.dialectDetected = true
+	return nil
+}
+
+// getVersion gets the version of Postgres server, or the Redshift or CockroachDB version if the server is either of them.
 func (driver *Driver) getVersion(ctx context.Context) (string, error) {
+	if err := driver.detectDialect(ctx); err != nil {
+		return "", err
+	}
+	if driver.redshiftVersion != "" {
+		return driver.redshiftVersion, nil
+	}
+	if driver.cockroachVersion != "" {
+		return driver.cockroachVersion, nil
+	}
 	query := "SHOW server_version"
 	var version string
 	if err := driver.db.QueryRowContext(ctx, query).Scan(&version); err != nil {
@@ -217,6 +266,12 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 	if err != nil {
 		return err
 	}
+	// CockroachDB makes the creator the owner of the created objects and doesn't switch roles within a transaction,
+	// so the statements run as the connecting user.
+	cockroach, err := driver.isCockroachDB(ctx)
+	if err != nil {
+		return err
+	}
 
 	statements, err := parser.SplitMultiSQL(parser.Postgres, statement)
 	if err != nil {
@@ -266,7 +321,7 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 			if owner, err = driver.getCurrentDatabaseOwner(); err != nil {
 				return err
 			}
-		} else if isSuperuserStatement(stmt) {
+		} else if isSuperuserStatement(stmt) && !cockroach {
 			// Use superuser privilege to run privileged statements.
 			remainingStmts = append(remainingStmts, "SET LOCAL ROLE NONE;")
 			remainingStmts = append(remainingStmts, stmt)
@@ -280,15 +335,34 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 		return nil
 	}
 
-	tx, err := driver.db.BeginTx(ctx, nil)
+	setting := db.SessionSettingFromContext(ctx)
+	if setting == nil {
+		setting = &db.SessionSetting{}
+	}
+	tx, err := driver.db.BeginTx(ctx, setting.GetTxOptions())
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
 	// Set the current transaction role to the database owner so that the owner of created database will be the same as the database owner.
-	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL ROLE %s", owner)); err != nil {
-		return err
+	if !cockroach {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL ROLE %s", owner)); err != nil {
+			return err
+		}
+	} else if (setting.LockTimeoutMs > 0 || setting.SearchPath != "") && !cockroachVersionAtLeast(driver.cockroachVersion, cockroachSetLocalMinVersion) {
+		return fmt.Errorf("CockroachDB %s doesn't support the transaction scoped session settings, %s or later is required", driver.cockroachVersion, cockroachSetLocalMinVersion)
+	}
+	// The session settings are set locally, so they end with the transaction.
+	if setting.LockTimeoutMs > 0 {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL lock_timeout = %d", setting.LockTimeoutMs)); err != nil {
+			return err
+		}
+	}
+	if setting.SearchPath != "" {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL search_path TO %s", setting.SearchPath)); err != nil {
+			return err
+		}
 	}
 
 	if _, err := tx.ExecContext(ctx, strings.Join(remainingStmts, "\n")); err != nil {
@@ -355,6 +429,22 @@ func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([
 	return util.Query(ctx, driver.db, statement, limit)
 }
 
+// Capabilities returns the features the engine supports, which are narrowed down for Redshift and CockroachDB once the dialect is detected.
+func (driver *Driver) Capabilities() db.Capabilities {
+	return db.GetDialectCapabilities(db.Postgres, driver.dialect())
+}
+
+// dialect returns the dialect detected by detectDialect, it's empty for Postgres or if the dialect isn't detected yet.
+func (driver *Driver) dialect() db.Dialect {
+	switch {
+	case driver.redshiftVersion != "":
+		return db.DialectRedshift
+	case driver.cockroachVersion != "":
+		return db.DialectCockroachDB
+	}
+	return ""
+}
+
 func (driver *Driver) switchDatabase(dbName string) error {
 	if driver.db != nil {
 		if err := driver.db.Close(); err != nil {
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestGetDatabaseInCreateDatabaseStatement(t *testing.T) {
@@ -49,3 +51,178 @@ func TestGetDatabaseInCreateDatabaseStatement(t *testing.T) {
 		require.Equal(t, test.want, got)
 	}
 }
+
+func TestDecodeTriggerType(t *testing.T) {
+	tests := []struct {
+		tgtype     int
+		wantTiming string
+		wantEvents []string
+		wantLevel  string
+	}{
+		// BEFORE INSERT OR UPDATE ... FOR EACH ROW.
+		{tgtype: 23, wantTiming: "BEFORE", wantEvents: []string{"INSERT", "UPDATE"}, wantLevel: "ROW"},
+		// AFTER DELETE ... FOR EACH STATEMENT.
+		{tgtype: 8, wantTiming: "AFTER", wantEvents: []string{"DELETE"}, wantLevel: "STATEMENT"},
+		// INSTEAD OF INSERT ... FOR EACH ROW on a view.
+		{tgtype: 69, wantTiming: "INSTEAD OF", wantEvents: []string{"INSERT"}, wantLevel: "ROW"},
+		// BEFORE TRUNCATE ... FOR EACH STATEMENT.
+		{tgtype: 34, wantTiming: "BEFORE", wantEvents: []string{"TRUNCATE"}, wantLevel: "STATEMENT"},
+	}
+
+	for _, test := range tests {
+		timing, events, level := decodeTriggerType(test.tgtype)
+		require.Equal(t, test.wantTiming, timing, "tgtype %d", test.tgtype)
+		require.Equal(t, test.wantEvents, events, "tgtype %d", test.tgtype)
+		require.Equal(t, test.wantLevel, level, "tgtype %d", test.tgtype)
+	}
+}
+
+func TestSumPartitionRowCount(t *testing.T) {
+	tableList := []db.Table{
+		{Name: "public.event", PartitionKey: "RANGE (ts)"},
+		{Name: "public.event_2022", PartitionKey: "LIST (kind)", ParentTable: "public.event"},
+		{Name: "public.event_2022_a", RowCount: 10, ParentTable: "public.event_2022"},
+		{Name: "public.event_2022_b", RowCount: 20, ParentTable: "public.event_2022"},
+		{Name: "public.event_2023", RowCount: 5, ParentTable: "public.event"},
+		{Name: "public.user", RowCount: 3},
+	}
+	sumPartitionRowCount(tableList)
+
+	var rowCountList []int64
+	for _, table := range tableList {
+		rowCountList = append(rowCountList, table.RowCount)
+	}
+	require.Equal(t, []int64{35, 30, 10, 20, 5, 3}, rowCountList)
+}
+
+func TestParseForeignKey(t *testing.T) {
+	tests := []struct {
+		constraint *tableConstraint
+		want       []db.ForeignKey
+		wantErr    bool
+	}{
+		{
+			constraint: &tableConstraint{
+				name:            "fk_author",
+				constraint:      "FOREIGN KEY (author_id) REFERENCES author(id)",
+				referencedTable: "public.author",
+			},
+			want: []db.ForeignKey{
+				{Name: "fk_author", Column: "author_id", Position: 1, ReferencedTable: "public.author", ReferencedColumn: "id", OnDelete: "NO ACTION", OnUpdate: "NO ACTION"},
+			},
+		},
+		{
+			constraint: &tableConstraint{
+				name:            "fk_tenant",
+				constraint:      `FOREIGN KEY (tenant_id, "Owner ""ID""") REFERENCES account.tenant(id, owner_id) MATCH FULL ON UPDATE CASCADE ON DELETE SET NULL NOT VALID`,
+				referencedTable: "account.tenant",
+			},
+			want: []db.ForeignKey{
+				{Name: "fk_tenant", Column: "tenant_id", Position: 1, ReferencedTable: "account.tenant", ReferencedColumn: "id", OnDelete: "SET NULL", OnUpdate: "CASCADE"},
+				{Name: "fk_tenant", Column: `Owner "ID"`, Position: 2, ReferencedTable: "account.tenant", ReferencedColumn: "owner_id", OnDelete: "SET NULL", OnUpdate: "CASCADE"},
+			},
+		},
+		{
+			constraint: &tableConstraint{
+				name:       "fk_mismatch",
+				constraint: "FOREIGN KEY (a, b) REFERENCES t(a)",
+			},
+			wantErr: true,
+		},
+		{
+			constraint: &tableConstraint{
+				name:       "chk",
+				constraint: "CHECK ((a > 0))",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := parseForeignKey(test.constraint)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, got)
+	}
+}
+
+func TestGetRedshiftVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{
+			"PostgreSQL 8.0.2 on i686-pc-linux-gnu, compiled by GCC gcc (GCC) 3.4.2 20041017 (Red Hat 3.4.2-6.fc3), Redshift 1.0.38698",
+			"1.0.38698",
+		},
+		{
+			"PostgreSQL 14.5 on x86_64-pc-linux-gnu, compiled by gcc (GCC) 7.3.1 20180712 (Red Hat 7.3.1-12), 64-bit",
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, getRedshiftVersion(test.version))
+	}
+}
+
+func TestGetCockroachVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{
+			"CockroachDB CCL v22.1.0 (x86_64-pc-linux-gnu, built 2022/05/23 16:27:47, go1.17.6)",
+			"22.1.0",
+		},
+		{
+			"CockroachDB OSS v21.2.0-beta.1 (x86_64-apple-darwin19, built 2021/09/13 17:40:01, go1.16.6)",
+			"21.2.0-beta.1",
+		},
+		{
+			"PostgreSQL 14.5 on x86_64-pc-linux-gnu, compiled by gcc (GCC) 7.3.1 20180712 (Red Hat 7.3.1-12), 64-bit",
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, getCockroachVersion(test.version))
+	}
+}
+
+func TestCockroachVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version    string
+		minVersion string
+		want       bool
+	}{
+		{"22.1.0", "21.2", true},
+		{"21.2.3", "21.2", true},
+		{"21.1.19", "21.2", false},
+		{"20.2.0", "21.1", false},
+		{"", "21.1", false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, cockroachVersionAtLeast(test.version, test.minVersion), test.version)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	require.Equal(t, db.GetCapabilities(db.Postgres), (&Driver{}).Capabilities())
+
+	capabilities := (&Driver{dialectDetected: true, redshiftVersion: "1.0.38698"}).Capabilities()
+	require.True(t, capabilities.TransactionalDDL)
+	require.False(t, capabilities.Dump)
+	require.False(t, capabilities.Restore)
+
+	require.Equal(t, db.DialectRedshift, (&Driver{dialectDetected: true, redshiftVersion: "1.0.38698"}).dialect())
+
+	capabilities = (&Driver{dialectDetected: true, cockroachVersion: "22.1.0"}).Capabilities()
+	require.False(t, capabilities.TransactionalDDL)
+	require.False(t, capabilities.Dump)
+	require.True(t, capabilities.ReadOnlyQuery)
+}
@@ -0,0 +1,169 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// redshiftVersionRegexp matches the Redshift version in the result of version(),
+// e.g. "PostgreSQL 8.0.2 on i686-pc-linux-gnu, compiled by GCC gcc (GCC) 3.4.2 20041017 (Red Hat 3.4.2-6.fc3), Redshift 1.0.38698".
+var redshiftVersionRegexp = regexp.MustCompile(`Redshift (\d+(?:\.\d+)*)`)
+
+// redshiftBlockSizeByte is the size of the blocks in which Redshift reports the table size.
+const redshiftBlockSizeByte = 1 << 20
+
+// getRedshiftVersion returns the Redshift version in the result of version(), or empty if the server isn't Redshift.
+func getRedshiftVersion(version string) string {
+	matches := redshiftVersionRegexp.FindStringSubmatch(version)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// isRedshift returns whether the server is Amazon Redshift.
+func (driver *Driver) isRedshift(ctx context.Context) (bool, error) {
+	if err := driver.detectDialect(ctx); err != nil {
+		return false, err
+	}
+	return driver.redshiftVersion != "", nil
+}
+
+// syncRedshiftSchema syncs the tables and the views of a Redshift database.
+// Redshift doesn't have the indexes, the triggers, the partitions, the extensions and the sequences, so they're left empty.
+func syncRedshiftSchema(ctx context.Context, txn *sql.Tx, schema *db.Schema) error {
+	tables, err := getRedshiftTables(txn, db.IsExactRowCount(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get tables: %s", err)
+	}
+	for _, tbl := range tables {
+		dbTable := db.Table{
+			Name:     fmt.Sprintf("%s.%s", tbl.schemaName, tbl.name),
+			Type:     "BASE TABLE",
+			Comment:  tbl.comment,
+			Owner:    tbl.tableowner,
+			RowCount: tbl.rowCount,
+			DataSize: tbl.tableSizeByte,
+		}
+		for _, col := range tbl.columns {
+			dbTable.ColumnList = append(dbTable.ColumnList, db.Column{
+				Name:      col.columnName,
+				Position:  col.ordinalPosition,
+				Default:   &col.columnDefault,
+				Type:      col.dataType,
+				Nullable:  col.isNullable,
+				Collation: col.collationName,
+				Comment:   col.comment,
+			})
+		}
+		// The foreign keys are informational in Redshift, but they're synced since the query planner relies on them.
+		for _, constraint := range tbl.constraints {
+			if constraint.constraintType != "f" {
+				continue
+			}
+			foreignKeyList, err := parseForeignKey(constraint)
+			if err != nil {
+				return fmt.Errorf("failed to parse foreign key %q of table %q: %s", constraint.name, dbTable.Name, err)
+			}
+			dbTable.ForeignKeyList = append(dbTable.ForeignKeyList, foreignKeyList...)
+		}
+		schema.TableList = append(schema.TableList, dbTable)
+	}
+
+	views, err := getViews(txn)
+	if err != nil {
+		return fmt.Errorf("failed to get views: %s", err)
+	}
+	for _, view := range views {
+		schema.ViewList = append(schema.ViewList, db.View{
+			Name:       fmt.Sprintf("%s.%s", view.schemaName, view.name),
+			CreatedTs:  time.Now().Unix(),
+			Definition: view.definition,
+			Comment:    view.comment,
+		})
+	}
+	return nil
+}
+
+// getRedshiftTables gets all tables of a Redshift database.
+// Redshift rejects the queries joining the catalogs on the leader node, e.g. pg_tables, with the system views on the compute nodes,
+// e.g. svv_table_info, so the sizes and the row counts are queried separately. svv_table_info only lists the non-empty tables.
+func getRedshiftTables(txn *sql.Tx, exactRowCount bool) ([]*tableSchema, error) {
+	constraints, err := getTableConstraints(txn)
+	if err != nil {
+		return nil, fmt.Errorf("getTableConstraints() got error: %v", err)
+	}
+
+	var tables []*tableSchema
+	query := "" +
+		"SELECT schemaname, tablename, tableowner FROM pg_catalog.pg_tables " +
+		"WHERE schemaname NOT IN ('pg_catalog', 'information_schema', 'pg_internal');"
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tbl tableSchema
+		if err := rows.Scan(&tbl.schemaName, &tbl.name, &tbl.tableowner); err != nil {
+			return nil, err
+		}
+		tables = append(tables, &tbl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	infoMap, err := getRedshiftTableInfo(txn)
+	if err != nil {
+		return nil, fmt.Errorf("getRedshiftTableInfo() got error: %v", err)
+	}
+	for _, tbl := range tables {
+		key := fmt.Sprintf("%s.%s", tbl.schemaName, tbl.name)
+		if info, ok := infoMap[key]; ok {
+			tbl.tableSizeByte = info.tableSizeByte
+			tbl.estimatedRowCount = info.estimatedRowCount
+		}
+		if err := getTable(txn, tbl, exactRowCount || tbl.tableSizeByte <= exactRowCountMaxTableSizeByte); err != nil {
+			return nil, fmt.Errorf("getTable(%q, %q) got error %v", tbl.schemaName, tbl.name, err)
+		}
+		columns, err := getTableColumns(txn, tbl.schemaName, tbl.name)
+		if err != nil {
+			return nil, fmt.Errorf("getTableColumns(%q, %q) got error %v", tbl.schemaName, tbl.name, err)
+		}
+		tbl.columns = columns
+		tbl.constraints = constraints[key]
+	}
+	return tables, nil
+}
+
+// getRedshiftTableInfo gets the sizes and the row counts of the non-empty tables from svv_table_info, keyed by the table name qualified by the schema name.
+func getRedshiftTableInfo(txn *sql.Tx) (map[string]*tableSchema, error) {
+	query := `SELECT "schema", "table", COALESCE(size, 0), COALESCE(tbl_rows, 0)::bigint FROM svv_table_info;`
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	infoMap := make(map[string]*tableSchema)
+	for rows.Next() {
+		var info tableSchema
+		var sizeBlock int64
+		if err := rows.Scan(&info.schemaName, &info.name, &sizeBlock, &info.estimatedRowCount); err != nil {
+			return nil, err
+		}
+		info.tableSizeByte = sizeBlock * redshiftBlockSizeByte
+		infoMap[fmt.Sprintf("%s.%s", info.schemaName, info.name)] = &info
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return infoMap, nil
+}
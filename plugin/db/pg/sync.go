@@ -13,6 +13,10 @@ import (
 	"github.com/bytebase/bytebase/plugin/db/util"
 )
 
+// exactRowCountMaxTableSizeByte is the maximum size of the tables whose rows are counted exactly by default,
+// counting the rows of the larger tables takes long and holds the transaction open, so they're estimated from the statistics.
+const exactRowCountMaxTableSizeByte = 1 << 30
+
 var (
 	excludedDatabaseList = map[string]bool{
 		// Skip our internal "bytebase" database
@@ -24,7 +28,34 @@ var (
 		// gcp
 		"cloudsql":      true,
 		"cloudsqladmin": true,
+		// aws redshift
+		"padb_harvest": true,
+	}
+	// syncedParameterList is the key configuration parameters synced from the instance.
+	syncedParameterList = []string{
+		"shared_buffers",
+		"max_connections",
+		"work_mem",
+		"maintenance_work_mem",
+		"effective_cache_size",
+		"wal_level",
+		"max_wal_size",
+		"max_wal_senders",
+		"max_replication_slots",
+		"synchronous_commit",
+		"fsync",
+		"full_page_writes",
+		"statement_timeout",
+		"idle_in_transaction_session_timeout",
+		"log_min_duration_statement",
+		"ssl",
+		"TimeZone",
 	}
+	// foreignKeyDefRegexp matches the foreign key definition returned by pg_get_constraintdef,
+	// e.g. "FOREIGN KEY (author_id) REFERENCES author(id) ON UPDATE CASCADE ON DELETE SET NULL".
+	foreignKeyDefRegexp = regexp.MustCompile(`^FOREIGN KEY \((.+?)\) REFERENCES .+?\((.+?)\)(.*)$`)
+	// foreignKeyActionRegexp matches the referential actions of the foreign key definition.
+	foreignKeyActionRegexp = regexp.MustCompile(`ON (UPDATE|DELETE) (CASCADE|RESTRICT|NO ACTION|SET NULL|SET DEFAULT)`)
 )
 
 // pgDatabaseSchema describes a pg database schema.
@@ -36,18 +67,29 @@ type pgDatabaseSchema struct {
 
 // tableSchema describes the schema of a pg table.
 type tableSchema struct {
-	schemaName    string
-	name          string
-	tableowner    string
-	comment       string
-	rowCount      int64
-	tableSizeByte int64
+	schemaName string
+	name       string
+	tableowner string
+	comment    string
+	rowCount   int64
+	// estimatedRowCount is the row count estimated by the last VACUUM or ANALYZE, it's negative if the table has never been analyzed.
+	estimatedRowCount int64
+	tableSizeByte     int64
+	// partitioned is set for the partitioned tables, whose rows are stored in the partitions.
+	partitioned   bool
 	indexSizeByte int64
 
 	columns     []*columnSchema
 	constraints []*tableConstraint
 }
 
+// partitionSchema describes the partitioning of a pg table, it's either a partitioned table, a partition or both for the sub-partitioned partition.
+type partitionSchema struct {
+	partitionKey   string
+	parentTable    string
+	partitionBound string
+}
+
 // columnSchema describes the schema of a pg table column.
 type columnSchema struct {
 	columnName             string
@@ -66,6 +108,10 @@ type tableConstraint struct {
 	schemaName string
 	tableName  string
 	constraint string
+	// constraintType is the pg_constraint.contype, e.g. "f" for the foreign key.
+	constraintType string
+	// referencedTable is the table referenced by the foreign key, qualified by the schema name.
+	referencedTable string
 }
 
 // viewSchema describes the schema of a pg view.
@@ -76,6 +122,22 @@ type viewSchema struct {
 	comment    string
 }
 
+// sequenceSchema describes the schema of a pg sequence.
+type sequenceSchema struct {
+	schemaName string
+	name       string
+	dataType   string
+	startValue int64
+	increment  int64
+	minValue   int64
+	maxValue   int64
+	cacheSize  int64
+	cycle      bool
+	// ownerTable and ownerColumn are empty for the standalone sequences.
+	ownerTable  string
+	ownerColumn string
+}
+
 // indexSchema describes the schema of a pg index.
 type indexSchema struct {
 	schemaName string
@@ -103,6 +165,18 @@ func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error
 		return nil, err
 	}
 
+	redshift, err := driver.isRedshift(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Redshift doesn't have most of the synced parameters.
+	var parameterList []db.Parameter
+	if !redshift {
+		if parameterList, err = driver.getParameterList(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	// Skip all system databases
 	for k := range systemDatabases {
 		excludedDatabaseList[k] = true
@@ -112,12 +186,19 @@ func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get databases: %s", err)
 	}
+	cockroach, err := driver.isCockroachDB(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var databaseList []db.DatabaseMeta
 	for _, database := range databases {
 		dbName := database.name
 		if _, ok := excludedDatabaseList[dbName]; ok {
 			continue
 		}
+		if _, ok := cockroachSystemDatabases[dbName]; ok && cockroach {
+			continue
+		}
 
 		databaseList = append(
 			databaseList,
@@ -130,9 +211,11 @@ func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error
 	}
 
 	return &db.InstanceMeta{
-		Version:      version,
-		UserList:     userList,
-		DatabaseList: databaseList,
+		Dialect:       driver.dialect(),
+		Version:       version,
+		UserList:      userList,
+		ParameterList: parameterList,
+		DatabaseList:  databaseList,
 	}, nil
 }
 
@@ -170,6 +253,33 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 	}
 	defer txn.Rollback()
 
+	redshift, err := driver.isRedshift(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if redshift {
+		if err := syncRedshiftSchema(ctx, txn, &schema); err != nil {
+			return nil, fmt.Errorf("failed to sync Redshift database %q: %s", databaseName, err)
+		}
+		if err := txn.Commit(); err != nil {
+			return nil, err
+		}
+		return &schema, nil
+	}
+	cockroach, err := driver.isCockroachDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cockroach {
+		if err := syncCockroachSchema(ctx, txn, &schema, driver.cockroachVersion); err != nil {
+			return nil, fmt.Errorf("failed to sync CockroachDB database %q: %s", databaseName, err)
+		}
+		if err := txn.Commit(); err != nil {
+			return nil, err
+		}
+		return &schema, nil
+	}
+
 	// Index statements.
 	indicesMap := make(map[string][]*indexSchema)
 	indices, err := getIndices(txn)
@@ -181,8 +291,20 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 		indicesMap[key] = append(indicesMap[key], idx)
 	}
 
+	// Trigger statements.
+	triggersMap, err := getTriggers(txn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get triggers from database %q: %s", databaseName, err)
+	}
+
+	// Partitions.
+	partitionsMap, err := getPartitions(txn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions from database %q: %s", databaseName, err)
+	}
+
 	// Table statements.
-	tables, err := getPgTables(txn)
+	tables, err := getPgTables(txn, db.IsExactRowCount(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tables from database %q: %s", databaseName, err)
 	}
@@ -191,6 +313,7 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 		dbTable.Name = fmt.Sprintf("%s.%s", tbl.schemaName, tbl.name)
 		dbTable.Type = "BASE TABLE"
 		dbTable.Comment = tbl.comment
+		dbTable.Owner = tbl.tableowner
 		dbTable.RowCount = tbl.rowCount
 		dbTable.DataSize = tbl.tableSizeByte
 		dbTable.IndexSize = tbl.indexSizeByte
@@ -219,9 +342,26 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 				dbTable.IndexList = append(dbTable.IndexList, dbIndex)
 			}
 		}
+		for _, constraint := range tbl.constraints {
+			if constraint.constraintType != "f" {
+				continue
+			}
+			foreignKeyList, err := parseForeignKey(constraint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse foreign key %q of table %q: %s", constraint.name, dbTable.Name, err)
+			}
+			dbTable.ForeignKeyList = append(dbTable.ForeignKeyList, foreignKeyList...)
+		}
+		dbTable.TriggerList = triggersMap[dbTable.Name]
+		if partition, ok := partitionsMap[dbTable.Name]; ok {
+			dbTable.PartitionKey = partition.partitionKey
+			dbTable.ParentTable = partition.parentTable
+			dbTable.PartitionBound = partition.partitionBound
+		}
 
 		schema.TableList = append(schema.TableList, dbTable)
 	}
+	sumPartitionRowCount(schema.TableList)
 	// View statements.
 	views, err := getViews(txn)
 	if err != nil {
@@ -244,6 +384,33 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*d
 	}
 	schema.ExtensionList = extensions
 
+	// Functions and stored procedures.
+	functions, err := getFunctions(txn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get functions from database %q: %s", databaseName, err)
+	}
+	schema.FunctionList = functions
+
+	// Sequences.
+	sequences, err := getSequences(txn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sequences from database %q: %s", databaseName, err)
+	}
+	for _, seq := range sequences {
+		schema.SequenceList = append(schema.SequenceList, db.Sequence{
+			Name:        fmt.Sprintf("%s.%s", seq.schemaName, seq.name),
+			DataType:    seq.dataType,
+			Start:       seq.startValue,
+			Increment:   seq.increment,
+			MinValue:    seq.minValue,
+			MaxValue:    seq.maxValue,
+			Cache:       seq.cacheSize,
+			Cycle:       seq.cycle,
+			OwnerTable:  seq.ownerTable,
+			OwnerColumn: seq.ownerColumn,
+		})
+	}
+
 	if err := txn.Commit(); err != nil {
 		return nil, err
 	}
@@ -296,8 +463,36 @@ func (driver *Driver) getUserList(ctx context.Context) ([]db.User, error) {
 	return userList, nil
 }
 
+// getParameterList returns the synced parameters in the same form as SHOW, e.g. "128MB".
+func (driver *Driver) getParameterList(ctx context.Context) ([]db.Parameter, error) {
+	query := `
+		SELECT name, current_setting(name)
+		FROM pg_catalog.pg_settings
+		WHERE name IN ('` + strings.Join(syncedParameterList, "', '") + `')
+		ORDER BY name`
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var parameterList []db.Parameter
+	for rows.Next() {
+		var parameter db.Parameter
+		if err := rows.Scan(&parameter.Name, &parameter.Value); err != nil {
+			return nil, err
+		}
+		parameterList = append(parameterList, parameter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return parameterList, nil
+}
+
 // getTables gets all tables of a database.
-func getPgTables(txn *sql.Tx) ([]*tableSchema, error) {
+// The row counts of the tables larger than exactRowCountMaxTableSizeByte are estimated unless exactRowCount is set.
+func getPgTables(txn *sql.Tx, exactRowCount bool) ([]*tableSchema, error) {
 	constraints, err := getTableConstraints(txn)
 	if err != nil {
 		return nil, fmt.Errorf("getTableConstraints() got error: %v", err)
@@ -305,7 +500,7 @@ func getPgTables(txn *sql.Tx) ([]*tableSchema, error) {
 
 	var tables []*tableSchema
 	query := "" +
-		"SELECT tbl.schemaname, tbl.tablename, tbl.tableowner, pg_table_size(c.oid), pg_indexes_size(c.oid) " +
+		"SELECT tbl.schemaname, tbl.tablename, tbl.tableowner, pg_table_size(c.oid), pg_indexes_size(c.oid), c.reltuples::bigint, c.relkind = 'p' " +
 		"FROM pg_catalog.pg_tables tbl, pg_catalog.pg_class c " +
 		"WHERE schemaname NOT IN ('pg_catalog', 'information_schema') AND tbl.schemaname=c.relnamespace::regnamespace::text AND tbl.tablename = c.relname;"
 	rows, err := txn.Query(query)
@@ -317,8 +512,9 @@ func getPgTables(txn *sql.Tx) ([]*tableSchema, error) {
 	for rows.Next() {
 		var tbl tableSchema
 		var schemaname, tablename, tableowner string
-		var tableSizeByte, indexSizeByte int64
-		if err := rows.Scan(&schemaname, &tablename, &tableowner, &tableSizeByte, &indexSizeByte); err != nil {
+		var tableSizeByte, indexSizeByte, estimatedRowCount int64
+		var partitioned bool
+		if err := rows.Scan(&schemaname, &tablename, &tableowner, &tableSizeByte, &indexSizeByte, &estimatedRowCount, &partitioned); err != nil {
 			return nil, err
 		}
 		tbl.schemaName = schemaname
@@ -326,6 +522,8 @@ func getPgTables(txn *sql.Tx) ([]*tableSchema, error) {
 		tbl.tableowner = tableowner
 		tbl.tableSizeByte = tableSizeByte
 		tbl.indexSizeByte = indexSizeByte
+		tbl.estimatedRowCount = estimatedRowCount
+		tbl.partitioned = partitioned
 
 		tables = append(tables, &tbl)
 	}
@@ -334,7 +532,7 @@ func getPgTables(txn *sql.Tx) ([]*tableSchema, error) {
 	}
 
 	for _, tbl := range tables {
-		if err := getTable(txn, tbl); err != nil {
+		if err := getTable(txn, tbl, exactRowCount || tbl.tableSizeByte <= exactRowCountMaxTableSizeByte); err != nil {
 			return nil, fmt.Errorf("getTable(%q, %q) got error %v", tbl.schemaName, tbl.name, err)
 		}
 		columns, err := getTableColumns(txn, tbl.schemaName, tbl.name)
@@ -349,21 +547,16 @@ func getPgTables(txn *sql.Tx) ([]*tableSchema, error) {
 	return tables, nil
 }
 
-func getTable(txn *sql.Tx, tbl *tableSchema) error {
-	countQuery := fmt.Sprintf(`SELECT COUNT(1) FROM "%s"."%s";`, tbl.schemaName, tbl.name)
-	rows, err := txn.Query(countQuery)
-	if err != nil {
-		return err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		if err := rows.Scan(&tbl.rowCount); err != nil {
+func getTable(txn *sql.Tx, tbl *tableSchema, exactRowCount bool) error {
+	switch {
+	case tbl.partitioned:
+		// The row count of the partitioned table is summed from the partitions.
+	case exactRowCount:
+		if err := getTableRowCount(txn, tbl); err != nil {
 			return err
 		}
-	}
-	if err := rows.Err(); err != nil {
-		return err
+	case tbl.estimatedRowCount > 0:
+		tbl.rowCount = tbl.estimatedRowCount
 	}
 
 	commentQuery := fmt.Sprintf(`SELECT obj_description('"%s"."%s"'::regclass);`, tbl.schemaName, tbl.name)
@@ -383,6 +576,44 @@ func getTable(txn *sql.Tx, tbl *tableSchema) error {
 	return crows.Err()
 }
 
+// sumPartitionRowCount sets the row count of the partitioned tables to the total of their partitions, including the sub-partitions.
+func sumPartitionRowCount(tableList []db.Table) {
+	indexMap := make(map[string]int)
+	for i, table := range tableList {
+		indexMap[table.Name] = i
+	}
+	for _, table := range tableList {
+		if table.PartitionKey != "" {
+			continue
+		}
+		for parent := table.ParentTable; parent != ""; {
+			i, ok := indexMap[parent]
+			if !ok {
+				break
+			}
+			tableList[i].RowCount += table.RowCount
+			parent = tableList[i].ParentTable
+		}
+	}
+}
+
+// getTableRowCount counts the rows of a table exactly, which scans the whole table.
+func getTableRowCount(txn *sql.Tx, tbl *tableSchema) error {
+	countQuery := fmt.Sprintf(`SELECT COUNT(1) FROM "%s"."%s";`, tbl.schemaName, tbl.name)
+	rows, err := txn.Query(countQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&tbl.rowCount); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // getTableColumns gets the columns of a table.
 func getTableColumns(txn *sql.Tx, schemaName, tableName string) ([]*columnSchema, error) {
 	query := `
@@ -397,14 +628,20 @@ func getTableColumns(txn *sql.Tx, schemaName, tableName string) ([]*columnSchema
 		cols.udt_schema,
 		cols.udt_name,
 		pg_catalog.col_description(c.oid, cols.ordinal_position::int) as column_comment
-	FROM INFORMATION_SCHEMA.COLUMNS AS cols, pg_catalog.pg_class c
-	WHERE table_schema=$1 AND table_name=$2 AND cols.table_schema=c.relnamespace::regnamespace::text AND cols.table_name=c.relname;`
+	FROM INFORMATION_SCHEMA.COLUMNS AS cols, pg_catalog.pg_class c, pg_catalog.pg_namespace n
+	WHERE table_schema=$1 AND table_name=$2 AND cols.table_schema=n.nspname AND cols.table_name=c.relname AND c.relnamespace=n.oid;`
 	rows, err := txn.Query(query, schemaName, tableName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return scanTableColumns(rows)
+}
+
+// scanTableColumns scans the columns from the rows of name, data type, ordinal position, character maximum length,
+// default, nullability, collation, UDT schema, UDT name and comment.
+func scanTableColumns(rows *sql.Rows) ([]*columnSchema, error) {
 	var columns []*columnSchema
 	for rows.Next() {
 		var columnName, dataType, isNullable string
@@ -444,9 +681,11 @@ func getTableColumns(txn *sql.Tx, schemaName, tableName string) ([]*columnSchema
 // getTableConstraints gets all table constraints of a database.
 func getTableConstraints(txn *sql.Tx) (map[string][]*tableConstraint, error) {
 	query := "" +
-		"SELECT n.nspname, conrelid::regclass, conname, pg_get_constraintdef(c.oid) " +
+		"SELECT n.nspname, conrelid::regclass, conname, pg_get_constraintdef(c.oid), c.contype, COALESCE(rn.nspname || '.' || rt.relname, '') " +
 		"FROM pg_constraint c " +
 		"JOIN pg_namespace n ON n.oid = c.connamespace " +
+		"LEFT JOIN pg_class rt ON rt.oid = c.confrelid " +
+		"LEFT JOIN pg_namespace rn ON rn.oid = rt.relnamespace " +
 		"WHERE n.nspname NOT IN ('pg_catalog', 'information_schema');"
 	ret := make(map[string][]*tableConstraint)
 	rows, err := txn.Query(query)
@@ -457,7 +696,7 @@ func getTableConstraints(txn *sql.Tx) (map[string][]*tableConstraint, error) {
 
 	for rows.Next() {
 		var constraint tableConstraint
-		if err := rows.Scan(&constraint.schemaName, &constraint.tableName, &constraint.name, &constraint.constraint); err != nil {
+		if err := rows.Scan(&constraint.schemaName, &constraint.tableName, &constraint.name, &constraint.constraint, &constraint.constraintType, &constraint.referencedTable); err != nil {
 			return nil, err
 		}
 		if strings.Contains(constraint.tableName, ".") {
@@ -472,11 +711,88 @@ func getTableConstraints(txn *sql.Tx) (map[string][]*tableConstraint, error) {
 	return ret, nil
 }
 
+// parseForeignKey parses the foreign key constraint into one entry per column in the key.
+// The referencing and the referenced columns are parsed from the definition, while the referenced table is from the catalog,
+// because the definition only qualifies it by the schema name if the schema isn't in the search path.
+func parseForeignKey(constraint *tableConstraint) ([]db.ForeignKey, error) {
+	matches := foreignKeyDefRegexp.FindStringSubmatch(constraint.constraint)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid foreign key definition %q", constraint.constraint)
+	}
+	columnList := splitIdentifierList(matches[1])
+	referencedColumnList := splitIdentifierList(matches[2])
+	if len(columnList) != len(referencedColumnList) {
+		return nil, fmt.Errorf("foreign key definition %q has %d referencing columns but %d referenced columns", constraint.constraint, len(columnList), len(referencedColumnList))
+	}
+	// The referential actions are omitted from the definition if they're NO ACTION.
+	onDelete, onUpdate := "NO ACTION", "NO ACTION"
+	for _, action := range foreignKeyActionRegexp.FindAllStringSubmatch(matches[3], -1) {
+		if action[1] == "DELETE" {
+			onDelete = action[2]
+		} else {
+			onUpdate = action[2]
+		}
+	}
+
+	var foreignKeyList []db.ForeignKey
+	for i, column := range columnList {
+		foreignKeyList = append(foreignKeyList, db.ForeignKey{
+			Name:             constraint.name,
+			Column:           column,
+			Position:         i + 1,
+			ReferencedTable:  constraint.referencedTable,
+			ReferencedColumn: referencedColumnList[i],
+			OnDelete:         onDelete,
+			OnUpdate:         onUpdate,
+		})
+	}
+	return foreignKeyList, nil
+}
+
+// splitIdentifierList splits the comma-separated identifiers, e.g. `id, "Tenant ID"`, and unquotes the quoted ones.
+func splitIdentifierList(s string) []string {
+	var identifierList []string
+	var identifier strings.Builder
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' && quoted && i+1 < len(s) && s[i+1] == '"':
+			// The escaped double quote in the quoted identifier.
+			identifier.WriteByte('"')
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			identifierList = append(identifierList, identifier.String())
+			identifier.Reset()
+		case c == ' ' && !quoted:
+		default:
+			identifier.WriteByte(c)
+		}
+	}
+	return append(identifierList, identifier.String())
+}
+
 // getViews gets all views of a database.
 func getViews(txn *sql.Tx) ([]*viewSchema, error) {
+	views, err := listViews(txn, "pg_catalog", "information_schema")
+	if err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		if err = getView(txn, view); err != nil {
+			return nil, fmt.Errorf("getPgView(%q, %q) got error %v", view.schemaName, view.name, err)
+		}
+	}
+	return views, nil
+}
+
+// listViews lists the views of a database without the comments, excluding the views in the system schemas.
+func listViews(txn *sql.Tx, systemSchemaList ...string) ([]*viewSchema, error) {
 	query := "" +
 		"SELECT schemaname, viewname, definition FROM pg_catalog.pg_views " +
-		"WHERE schemaname NOT IN ('pg_catalog', 'information_schema');"
+		"WHERE schemaname NOT IN ('" + strings.Join(systemSchemaList, "', '") + "');"
 	var views []*viewSchema
 	rows, err := txn.Query(query)
 	if err != nil {
@@ -501,12 +817,6 @@ func getViews(txn *sql.Tx) ([]*viewSchema, error) {
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-
-	for _, view := range views {
-		if err = getView(txn, view); err != nil {
-			return nil, fmt.Errorf("getPgView(%q, %q) got error %v", view.schemaName, view.name, err)
-		}
-	}
 	return views, nil
 }
 
@@ -558,6 +868,229 @@ func getExtensions(txn *sql.Tx) ([]db.Extension, error) {
 	return extensions, nil
 }
 
+// getFunctions gets all user-defined functions and stored procedures of a database.
+// The aggregate and window functions, as well as the ones created by the extensions, are excluded.
+func getFunctions(txn *sql.Tx) ([]db.Function, error) {
+	versionNum, err := getServerVersionNum(txn)
+	if err != nil {
+		return nil, err
+	}
+	// The stored procedures and pg_proc.prokind are introduced in Postgres 11.
+	kindExpr, kindFilter := "p.prokind", "p.prokind IN ('f', 'p')"
+	if versionNum < 110000 {
+		kindExpr, kindFilter = "'f'", "NOT p.proisagg AND NOT p.proiswindow"
+	}
+	query := "" +
+		"SELECT n.nspname, p.proname, pg_catalog.pg_get_function_identity_arguments(p.oid), " + kindExpr + ", l.lanname, pg_catalog.pg_get_functiondef(p.oid) " +
+		"FROM pg_catalog.pg_proc p " +
+		"JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace " +
+		"JOIN pg_catalog.pg_language l ON l.oid = p.prolang " +
+		"WHERE n.nspname NOT IN ('pg_catalog', 'information_schema') AND " + kindFilter + " " +
+		"AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_depend d WHERE d.classid = 'pg_catalog.pg_proc'::pg_catalog.regclass AND d.objid = p.oid AND d.deptype = 'e') " +
+		"ORDER BY n.nspname, p.proname, 3;"
+
+	var functions []db.Function
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f db.Function
+		var kind string
+		if err := rows.Scan(&f.Schema, &f.Name, &f.Arguments, &kind, &f.Language, &f.Definition); err != nil {
+			return nil, err
+		}
+		f.Type = "FUNCTION"
+		if kind == "p" {
+			f.Type = "PROCEDURE"
+		}
+		functions = append(functions, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return functions, nil
+}
+
+// getSequences gets all sequences of a database, including the ones owned by the serial and identity columns.
+func getSequences(txn *sql.Tx) ([]*sequenceSchema, error) {
+	versionNum, err := getServerVersionNum(txn)
+	if err != nil {
+		return nil, err
+	}
+	// The pg_sequences view is introduced in Postgres 10, the sequence parameters are not in the catalog before that.
+	if versionNum < 100000 {
+		return nil, nil
+	}
+	query := "" +
+		"SELECT s.schemaname, s.sequencename, s.data_type::text, s.start_value, s.increment_by, s.min_value, s.max_value, s.cache_size, s.cycle, " +
+		"COALESCE(tn.nspname || '.' || t.relname, ''), COALESCE(a.attname, '') " +
+		"FROM pg_catalog.pg_sequences s " +
+		"JOIN pg_catalog.pg_namespace n ON n.nspname = s.schemaname " +
+		"JOIN pg_catalog.pg_class c ON c.relnamespace = n.oid AND c.relname = s.sequencename AND c.relkind = 'S' " +
+		// The sequence is owned by a column with the auto dependency for serial columns and the internal dependency for identity columns.
+		"LEFT JOIN pg_catalog.pg_depend d ON d.classid = 'pg_catalog.pg_class'::pg_catalog.regclass AND d.objid = c.oid " +
+		"AND d.refclassid = 'pg_catalog.pg_class'::pg_catalog.regclass AND d.refobjsubid > 0 AND d.deptype IN ('a', 'i') " +
+		"LEFT JOIN pg_catalog.pg_class t ON t.oid = d.refobjid " +
+		"LEFT JOIN pg_catalog.pg_namespace tn ON tn.oid = t.relnamespace " +
+		"LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid " +
+		"WHERE s.schemaname NOT IN ('pg_catalog', 'information_schema') " +
+		"ORDER BY s.schemaname, s.sequencename;"
+
+	var sequences []*sequenceSchema
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var seq sequenceSchema
+		if err := rows.Scan(&seq.schemaName, &seq.name, &seq.dataType, &seq.startValue, &seq.increment, &seq.minValue, &seq.maxValue, &seq.cacheSize, &seq.cycle, &seq.ownerTable, &seq.ownerColumn); err != nil {
+			return nil, err
+		}
+		sequences = append(sequences, &seq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sequences, nil
+}
+
+// getTriggers gets the user-defined triggers of a database, keyed by the table name in the form of "schema.table".
+// The internal triggers, e.g. the ones enforcing the foreign keys, are excluded.
+func getTriggers(txn *sql.Tx) (map[string][]db.Trigger, error) {
+	query := "" +
+		"SELECT n.nspname, c.relname, t.tgname, t.tgtype, t.tgenabled, pn.nspname || '.' || p.proname, pg_catalog.pg_get_triggerdef(t.oid) " +
+		"FROM pg_catalog.pg_trigger t " +
+		"JOIN pg_catalog.pg_class c ON c.oid = t.tgrelid " +
+		"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace " +
+		"JOIN pg_catalog.pg_proc p ON p.oid = t.tgfoid " +
+		"JOIN pg_catalog.pg_namespace pn ON pn.oid = p.pronamespace " +
+		"WHERE NOT t.tgisinternal AND n.nspname NOT IN ('pg_catalog', 'information_schema') " +
+		"ORDER BY n.nspname, c.relname, t.tgname;"
+
+	triggersMap := make(map[string][]db.Trigger)
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, tableName, enabled string
+		var tgtype int
+		var trigger db.Trigger
+		if err := rows.Scan(&schemaName, &tableName, &trigger.Name, &tgtype, &enabled, &trigger.Function, &trigger.Definition); err != nil {
+			return nil, err
+		}
+		trigger.Timing, trigger.EventList, trigger.Level = decodeTriggerType(tgtype)
+		// The trigger is disabled by "D", and fires in the origin, replica or both session replication roles by "O", "R" and "A".
+		trigger.Enabled = enabled != "D"
+		key := fmt.Sprintf("%s.%s", schemaName, tableName)
+		triggersMap[key] = append(triggersMap[key], trigger)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return triggersMap, nil
+}
+
+// decodeTriggerType decodes the timing, the events and the level from pg_trigger.tgtype,
+// whose bits are defined by TRIGGER_TYPE_* in the Postgres source include/catalog/pg_trigger.h.
+func decodeTriggerType(tgtype int) (string, []string, string) {
+	const (
+		triggerTypeRow      = 1 << 0
+		triggerTypeBefore   = 1 << 1
+		triggerTypeInsert   = 1 << 2
+		triggerTypeDelete   = 1 << 3
+		triggerTypeUpdate   = 1 << 4
+		triggerTypeTruncate = 1 << 5
+		triggerTypeInstead  = 1 << 6
+	)
+	timing := "AFTER"
+	if tgtype&triggerTypeBefore != 0 {
+		timing = "BEFORE"
+	} else if tgtype&triggerTypeInstead != 0 {
+		timing = "INSTEAD OF"
+	}
+	var events []string
+	if tgtype&triggerTypeInsert != 0 {
+		events = append(events, "INSERT")
+	}
+	if tgtype&triggerTypeUpdate != 0 {
+		events = append(events, "UPDATE")
+	}
+	if tgtype&triggerTypeDelete != 0 {
+		events = append(events, "DELETE")
+	}
+	if tgtype&triggerTypeTruncate != 0 {
+		events = append(events, "TRUNCATE")
+	}
+	level := "STATEMENT"
+	if tgtype&triggerTypeRow != 0 {
+		level = "ROW"
+	}
+	return timing, events, level
+}
+
+// getPartitions gets the partitioned tables and the partitions of a database, keyed by the table name in the form of "schema.table".
+func getPartitions(txn *sql.Tx) (map[string]*partitionSchema, error) {
+	versionNum, err := getServerVersionNum(txn)
+	if err != nil {
+		return nil, err
+	}
+	// The declarative partitioning is introduced in Postgres 10.
+	if versionNum < 100000 {
+		return nil, nil
+	}
+	query := "" +
+		"SELECT n.nspname, c.relname, COALESCE(pg_catalog.pg_get_partkeydef(c.oid), ''), " +
+		"COALESCE(pn.nspname || '.' || p.relname, ''), COALESCE(pg_catalog.pg_get_expr(c.relpartbound, c.oid), '') " +
+		"FROM pg_catalog.pg_class c " +
+		"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace " +
+		"LEFT JOIN pg_catalog.pg_inherits i ON i.inhrelid = c.oid AND c.relispartition " +
+		"LEFT JOIN pg_catalog.pg_class p ON p.oid = i.inhparent " +
+		"LEFT JOIN pg_catalog.pg_namespace pn ON pn.oid = p.relnamespace " +
+		"WHERE (c.relkind = 'p' OR c.relispartition) AND c.relkind IN ('r', 'p') AND n.nspname NOT IN ('pg_catalog', 'information_schema') " +
+		"ORDER BY n.nspname, c.relname;"
+
+	partitionsMap := make(map[string]*partitionSchema)
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, tableName string
+		var partition partitionSchema
+		if err := rows.Scan(&schemaName, &tableName, &partition.partitionKey, &partition.parentTable, &partition.partitionBound); err != nil {
+			return nil, err
+		}
+		partitionsMap[fmt.Sprintf("%s.%s", schemaName, tableName)] = &partition
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return partitionsMap, nil
+}
+
+// getServerVersionNum gets the server version in the number format, e.g. 140005 for 14.5.
+func getServerVersionNum(txn *sql.Tx) (int, error) {
+	var versionNum int
+	if err := txn.QueryRow("SELECT current_setting('server_version_num')::integer;").Scan(&versionNum); err != nil {
+		return 0, err
+	}
+	return versionNum, nil
+}
+
 // getIndices gets all indices of a database.
 func getIndices(txn *sql.Tx) ([]*indexSchema, error) {
 	query := "" +
@@ -576,10 +1109,7 @@ func getIndices(txn *sql.Tx) ([]*indexSchema, error) {
 		if err := rows.Scan(&idx.schemaName, &idx.tableName, &idx.name, &idx.statement); err != nil {
 			return nil, err
 		}
-		idx.unique = strings.Contains(idx.statement, " UNIQUE INDEX ")
-		idx.methodType = getIndexMethodType(idx.statement)
-		idx.columnExpressions, err = getIndexColumnExpressions(idx.statement)
-		if err != nil {
+		if err := parseIndexStatement(&idx); err != nil {
 			return nil, err
 		}
 		indices = append(indices, &idx)
@@ -601,6 +1131,18 @@ func getIndices(txn *sql.Tx) ([]*indexSchema, error) {
 	return indices, nil
 }
 
+// parseIndexStatement sets the uniqueness, the method type and the column expressions of the index from its statement.
+func parseIndexStatement(idx *indexSchema) error {
+	columnExpressions, err := getIndexColumnExpressions(idx.statement)
+	if err != nil {
+		return err
+	}
+	idx.unique = strings.Contains(idx.statement, " UNIQUE INDEX ")
+	idx.methodType = getIndexMethodType(idx.statement)
+	idx.columnExpressions = columnExpressions
+	return nil
+}
+
 func getPrimary(txn *sql.Tx, idx *indexSchema) error {
 	isPrimaryQuery := `
 		SELECT count(*)
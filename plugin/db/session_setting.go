@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// IsolationLevel is the transaction isolation level.
+type IsolationLevel string
+
+const (
+	// IsolationReadUncommitted is the READ UNCOMMITTED isolation level.
+	IsolationReadUncommitted IsolationLevel = "READ UNCOMMITTED"
+	// IsolationReadCommitted is the READ COMMITTED isolation level.
+	IsolationReadCommitted IsolationLevel = "READ COMMITTED"
+	// IsolationRepeatableRead is the REPEATABLE READ isolation level.
+	IsolationRepeatableRead IsolationLevel = "REPEATABLE READ"
+	// IsolationSerializable is the SERIALIZABLE isolation level.
+	IsolationSerializable IsolationLevel = "SERIALIZABLE"
+)
+
+var (
+	// searchPathRegexp matches the comma-separated schema names of the Postgres search_path, e.g. "app, public" or "\"$user\", public".
+	searchPathRegexp = regexp.MustCompile(`^\s*("[^"]+"|[A-Za-z_$][A-Za-z0-9_$]*)(\s*,\s*("[^"]+"|[A-Za-z_$][A-Za-z0-9_$]*))*\s*$`)
+	// sqlModeRegexp matches the comma-separated MySQL SQL modes, e.g. "STRICT_TRANS_TABLES,NO_ZERO_DATE". It can be empty.
+	sqlModeRegexp = regexp.MustCompile(`^[A-Za-z_,]*$`)
+)
+
+// SessionSetting is the session settings applied before executing the statements of a task.
+// The zero values are left as the database defaults.
+type SessionSetting struct {
+	IsolationLevel IsolationLevel `json:"isolationLevel,omitempty"`
+	// LockTimeoutMs is the maximum time waiting for a lock in milliseconds,
+	// which is lock_timeout for Postgres, and lock_wait_timeout and innodb_lock_wait_timeout in seconds for MySQL.
+	LockTimeoutMs int64 `json:"lockTimeoutMs,omitempty"`
+	// SearchPath is only supported for Postgres.
+	SearchPath string `json:"searchPath,omitempty"`
+	// SQLMode is only supported for MySQL and its variants, the empty string clears the SQL modes.
+	SQLMode *string `json:"sqlMode,omitempty"`
+	// ForeignKeyChecks is only supported for MySQL and its variants.
+	ForeignKeyChecks *bool `json:"foreignKeyChecks,omitempty"`
+}
+
+// Validate validates the session settings are supported by the engine.
+func (s *SessionSetting) Validate(dbType Type) error {
+	switch s.IsolationLevel {
+	case "", IsolationReadUncommitted, IsolationReadCommitted, IsolationRepeatableRead, IsolationSerializable:
+	default:
+		return fmt.Errorf("invalid isolation level %q", s.IsolationLevel)
+	}
+	if s.LockTimeoutMs < 0 {
+		return fmt.Errorf("lock timeout must not be negative, got %d", s.LockTimeoutMs)
+	}
+	if s.SearchPath != "" {
+		if dbType != Postgres {
+			return fmt.Errorf("search path is not supported for %s", dbType)
+		}
+		if !searchPathRegexp.MatchString(s.SearchPath) {
+			return fmt.Errorf("invalid search path %q", s.SearchPath)
+		}
+	}
+	if s.SQLMode != nil {
+		if !isMySQLVariant(dbType) {
+			return fmt.Errorf("SQL mode is not supported for %s", dbType)
+		}
+		if !sqlModeRegexp.MatchString(*s.SQLMode) {
+			return fmt.Errorf("invalid SQL mode %q", *s.SQLMode)
+		}
+	}
+	if s.ForeignKeyChecks != nil && !isMySQLVariant(dbType) {
+		return fmt.Errorf("foreign key checks are not supported for %s", dbType)
+	}
+	return nil
+}
+
+// isMySQLVariant returns whether the engine is MySQL or speaks the MySQL session variables, which the MySQL driver sets.
+func isMySQLVariant(dbType Type) bool {
+	return dbType == MySQL || dbType == TiDB || dbType == MariaDB
+}
+
+// GetTxOptions returns the options of the transaction executing the statements, it's nil if the isolation level isn't set.
+func (s *SessionSetting) GetTxOptions() *sql.TxOptions {
+	var isolation sql.IsolationLevel
+	switch s.IsolationLevel {
+	case IsolationReadUncommitted:
+		isolation = sql.LevelReadUncommitted
+	case IsolationReadCommitted:
+		isolation = sql.LevelReadCommitted
+	case IsolationRepeatableRead:
+		isolation = sql.LevelRepeatableRead
+	case IsolationSerializable:
+		isolation = sql.LevelSerializable
+	default:
+		return nil
+	}
+	return &sql.TxOptions{Isolation: isolation}
+}
+
+type sessionSettingContextKey struct{}
+
+// WithSessionSetting returns a copy of ctx carrying the session settings, which are applied by Driver.Execute.
+func WithSessionSetting(ctx context.Context, setting *SessionSetting) context.Context {
+	return context.WithValue(ctx, sessionSettingContextKey{}, setting)
+}
+
+// SessionSettingFromContext returns the session settings carried by ctx, it's nil if there is none.
+func SessionSettingFromContext(ctx context.Context) *SessionSetting {
+	setting, _ := ctx.Value(sessionSettingContextKey{}).(*SessionSetting)
+	return setting
+}
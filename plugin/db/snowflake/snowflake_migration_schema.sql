@@ -20,7 +20,7 @@ CREATE TABLE bytebase.public.migration_history (
     -- Used to detect out of order migration together with 'namespace' and 'version' column.
     sequence BIGINT NOT NULL,
     -- We call it source because maybe we could load history from other migration tool.
-    -- Current allowed values are UI, VCS, LIBRARY.
+    -- Current allowed values are UI, VCS, LIBRARY, FLYWAY, LIQUIBASE.
     source TEXT NOT NULL,
     -- Current allowed values are BASELINE, MIGRATE, BRANCH, DATA.
     type TEXT NOT NULL,
@@ -172,3 +172,8 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
 	return util.Query(ctx, driver.db, statement, limit)
 }
+
+// Capabilities returns the features the engine supports.
+func (*Driver) Capabilities() db.Capabilities {
+	return db.GetCapabilities(db.SQLite)
+}
@@ -0,0 +1,198 @@
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	certificateProbeTimeout = 10 * time.Second
+
+	// postgresSSLRequestCode is the code of the Postgres SSLRequest message.
+	postgresSSLRequestCode = 80877103
+
+	// The MySQL capability flags of the SSL request.
+	mysqlClientLongPassword     = 0x00000001
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientSecureConnection = 0x00008000
+	// mysqlCharsetUTF8 is the utf8_general_ci collation ID.
+	mysqlCharsetUTF8 = 33
+)
+
+// GetDefaultPort returns the default port of the engine, or empty if the engine doesn't listen on a port.
+func GetDefaultPort(engine db.Type) string {
+	switch engine {
+	case db.Postgres:
+		return "5432"
+	case db.MySQL, db.MariaDB:
+		return "3306"
+	case db.TiDB:
+		return "4000"
+	case db.ClickHouse:
+		return "9000"
+	}
+	return ""
+}
+
+// IsTLSProbeSupported returns true if the TLS of the database server can be probed for the engine and the host.
+func IsTLSProbeSupported(engine db.Type, host string) bool {
+	if strings.HasPrefix(host, "/") {
+		return false
+	}
+	return engine == db.Postgres || engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+}
+
+// GetServerCertificate returns the TLS certificate presented by the database server.
+// It returns nil if the server doesn't support TLS, or the engine or the Unix socket isn't supported.
+// The certificate isn't verified, because it's for monitoring the expiry, not for trusting the server.
+func GetServerCertificate(ctx context.Context, engine db.Type, host, port string) (*x509.Certificate, error) {
+	state, err := GetServerTLSState(ctx, engine, host, port)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	certList := state.PeerCertificates
+	if len(certList) == 0 {
+		return nil, fmt.Errorf("server presents no certificate")
+	}
+	return certList[0], nil
+}
+
+// GetServerTLSState returns the state of the TLS handshake with the database server.
+// It returns nil if the server doesn't support TLS, or the engine or the Unix socket isn't supported.
+// The certificate isn't verified.
+func GetServerTLSState(ctx context.Context, engine db.Type, host, port string) (*tls.ConnectionState, error) {
+	if !IsTLSProbeSupported(engine, host) {
+		return nil, nil
+	}
+	if port == "" {
+		port = GetDefaultPort(engine)
+	}
+
+	dialer := &net.Dialer{Timeout: certificateProbeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(certificateProbeTimeout)); err != nil {
+		return nil, err
+	}
+
+	var tlsSupported bool
+	if engine == db.Postgres {
+		tlsSupported, err = startPostgresTLS(conn)
+	} else {
+		tlsSupported, err = startMySQLTLS(conn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !tlsSupported {
+		return nil, nil
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed TLS handshake, error: %w", err)
+	}
+	state := tlsConn.ConnectionState()
+	return &state, nil
+}
+
+// startPostgresTLS sends the SSLRequest message, and returns true if the server accepts it.
+func startPostgresTLS(conn net.Conn) (bool, error) {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return false, err
+	}
+	response := make([]byte, 1)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return false, err
+	}
+	switch response[0] {
+	case 'S':
+		return true, nil
+	case 'N':
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected response %q to SSLRequest", response[0])
+}
+
+// startMySQLTLS reads the initial handshake, and sends the SSL request if the server supports it.
+func startMySQLTLS(conn net.Conn) (bool, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return false, err
+	}
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	handshake := make([]byte, length)
+	if _, err := io.ReadFull(conn, handshake); err != nil {
+		return false, err
+	}
+	capability, err := getMySQLHandshakeCapability(handshake)
+	if err != nil {
+		return false, err
+	}
+	if capability&mysqlClientSSL == 0 {
+		return false, nil
+	}
+	if _, err := conn.Write(getMySQLSSLRequest(header[3] + 1)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// getMySQLHandshakeCapability returns the lower capability flags of the initial handshake packet.
+func getMySQLHandshakeCapability(handshake []byte) (uint16, error) {
+	if len(handshake) == 0 {
+		return 0, fmt.Errorf("empty handshake packet")
+	}
+	// The server may reject the connection with an error packet, e.g. the host isn't allowed.
+	if handshake[0] == 0xff {
+		if len(handshake) > 3 {
+			return 0, fmt.Errorf("server rejects the connection: %s", string(handshake[3:]))
+		}
+		return 0, fmt.Errorf("server rejects the connection")
+	}
+	if handshake[0] != 10 {
+		return 0, fmt.Errorf("unsupported protocol version %d", handshake[0])
+	}
+	// Skip the null-terminated server version.
+	pos := 1
+	for pos < len(handshake) && handshake[pos] != 0 {
+		pos++
+	}
+	// Skip the null terminator, the connection ID, the first part of the auth plugin data, and the filler.
+	pos += 1 + 4 + 8 + 1
+	if pos+2 > len(handshake) {
+		return 0, fmt.Errorf("malformed handshake packet")
+	}
+	return binary.LittleEndian.Uint16(handshake[pos : pos+2]), nil
+}
+
+// getMySQLSSLRequest returns the SSL request packet with the sequence ID.
+func getMySQLSSLRequest(sequenceID byte) []byte {
+	packet := make([]byte, 4+32)
+	packet[0] = 32
+	packet[3] = sequenceID
+	binary.LittleEndian.PutUint32(packet[4:8], mysqlClientLongPassword|mysqlClientProtocol41|mysqlClientSSL|mysqlClientSecureConnection)
+	// The max packet size is 16MB, and the rest is filled with zero.
+	binary.LittleEndian.PutUint32(packet[8:12], 1<<24)
+	packet[12] = mysqlCharsetUTF8
+	return packet
+}
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func newTestCertificate(t *testing.T, notAfter time.Time) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "db.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startTestServer serves a single connection with the handler, and returns the port.
+func startTestServer(t *testing.T, handler func(conn net.Conn)) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handler(conn)
+	}()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return port
+}
+
+func TestGetServerCertificatePostgres(t *testing.T) {
+	notAfter := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second)
+	cert := newTestCertificate(t, notAfter)
+	port := startTestServer(t, func(conn net.Conn) {
+		request := make([]byte, 8)
+		if _, err := io.ReadFull(conn, request); err != nil || binary.BigEndian.Uint32(request[4:]) != postgresSSLRequestCode {
+			return
+		}
+		if _, err := conn.Write([]byte{'S'}); err != nil {
+			return
+		}
+		_ = tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}}).Handshake()
+	})
+
+	got, err := GetServerCertificate(context.Background(), db.Postgres, "127.0.0.1", port)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "db.example.com", got.Subject.CommonName)
+	require.True(t, notAfter.Equal(got.NotAfter))
+
+	port = startTestServer(t, func(conn net.Conn) {
+		request := make([]byte, 8)
+		if _, err := io.ReadFull(conn, request); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte{'N'})
+	})
+	got, err = GetServerCertificate(context.Background(), db.Postgres, "127.0.0.1", port)
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestGetServerCertificateMySQL(t *testing.T) {
+	cert := newTestCertificate(t, time.Now().Add(24*time.Hour))
+	// The handshake is protocol 10, server version, connection ID, auth plugin data, filler, and the capability flags.
+	handshake := []byte{10}
+	handshake = append(handshake, []byte("8.0.28\x00")...)
+	handshake = append(handshake, 1, 0, 0, 0)
+	handshake = append(handshake, make([]byte, 8+1)...)
+	handshake = append(handshake, 0x00, 0x0a)
+	port := startTestServer(t, func(conn net.Conn) {
+		packet := append([]byte{byte(len(handshake)), 0, 0, 0}, handshake...)
+		if _, err := conn.Write(packet); err != nil {
+			return
+		}
+		request := make([]byte, 36)
+		if _, err := io.ReadFull(conn, request); err != nil || request[3] != 1 {
+			return
+		}
+		_ = tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}}).Handshake()
+	})
+
+	got, err := GetServerCertificate(context.Background(), db.MySQL, "127.0.0.1", port)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "db.example.com", got.Subject.CommonName)
+}
+
+func TestGetMySQLHandshakeCapability(t *testing.T) {
+	_, err := getMySQLHandshakeCapability([]byte("\xff\x6a\x04Host is not allowed"))
+	require.Error(t, err)
+	_, err = getMySQLHandshakeCapability([]byte{10, '5', 0})
+	require.Error(t, err)
+}
@@ -308,6 +308,12 @@ func Query(ctx context.Context, sqldb *sql.DB, statement string, limit int) ([]i
 	}
 	defer tx.Rollback()
 
+	return QueryTx(ctx, tx, statement, limit)
+}
+
+// QueryTx executes the query in the transaction, which is rolled back by the caller.
+// It's used by the engines rejecting the ReadOnly flag of the transaction, e.g. Microsoft SQL Server.
+func QueryTx(ctx context.Context, tx *sql.Tx, statement string, limit int) ([]interface{}, error) {
 	rows, err := tx.QueryContext(ctx, statement)
 	if err != nil {
 		return nil, FormatErrorWithQuery(err, statement)
@@ -445,7 +451,7 @@ func FindMigrationHistoryList(ctx context.Context, findMigrationHistoryListQuery
 			return nil, err
 		}
 
-		useSemanticVersion, version, semanticVersionSuffix, err := fromStoredVersion(storedVersion)
+		useSemanticVersion, version, semanticVersionSuffix, err := FromStoredVersion(storedVersion)
 		if err != nil {
 			return nil, err
 		}
@@ -499,8 +505,8 @@ func ToStoredVersion(useSemanticVersion bool, version, semanticVersionSuffix str
 	return fmt.Sprintf("%04s.%04s.%04s-%s", major, minor, patch, semanticVersionSuffix), nil
 }
 
-// fromStoredVersion converts stored version to semantic or non-semantic version.
-func fromStoredVersion(storedVersion string) (bool, string, string, error) {
+// FromStoredVersion converts stored version to semantic or non-semantic version.
+func FromStoredVersion(storedVersion string) (bool, string, string, error) {
 	if strings.HasPrefix(storedVersion, NonSemanticPrefix) {
 		return false, strings.TrimPrefix(storedVersion, NonSemanticPrefix), "", nil
 	}
@@ -0,0 +1,68 @@
+// Package eventbus publishes events to an external event sink such as an HTTP endpoint or a NATS server.
+package eventbus
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var (
+	publisherMu sync.RWMutex
+	publishers  = make(map[string]Publisher)
+	timeout     = 3 * time.Second
+)
+
+// Publisher is the event publisher for a sink URL scheme.
+type Publisher interface {
+	publish(sinkURL *url.URL, event []byte) error
+}
+
+// register makes a publisher available by the sink URL scheme.
+// If register is called twice with the same scheme or if publisher is nil,
+// it panics.
+func register(scheme string, p Publisher) {
+	publisherMu.Lock()
+	defer publisherMu.Unlock()
+	if p == nil {
+		panic("eventbus: Register publisher is nil")
+	}
+	if _, dup := publishers[scheme]; dup {
+		panic("eventbus: Register called twice for scheme " + scheme)
+	}
+	publishers[scheme] = p
+}
+
+// Validate checks the sink URL is supported.
+func Validate(sinkURL string) error {
+	_, _, err := parse(sinkURL)
+	return err
+}
+
+// Publish publishes the JSON encoded event to the sink.
+//
+// The supported sinks are:
+//   - http(s)://host/path, the event is POSTed as the request body. Kafka topics can be reached via a REST proxy.
+//   - nats://[user:password@]host:port/subject, the event is published to the subject.
+func Publish(sinkURL string, event []byte) error {
+	u, p, err := parse(sinkURL)
+	if err != nil {
+		return err
+	}
+	return p.publish(u, event)
+}
+
+func parse(sinkURL string) (*url.URL, Publisher, error) {
+	u, err := url.Parse(sinkURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("eventbus: invalid sink URL, error: %w", err)
+	}
+	publisherMu.RLock()
+	p, ok := publishers[u.Scheme]
+	publisherMu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("eventbus: no applicable publisher for sink URL scheme: %q", u.Scheme)
+	}
+	return u, p, nil
+}
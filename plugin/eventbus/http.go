@@ -0,0 +1,44 @@
+package eventbus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+func init() {
+	register("http", &HTTPPublisher{})
+	register("https", &HTTPPublisher{})
+}
+
+// HTTPPublisher is the publisher POSTing the event to an HTTP endpoint.
+type HTTPPublisher struct {
+}
+
+func (*HTTPPublisher) publish(sinkURL *url.URL, event []byte) error {
+	req, err := http.NewRequest("POST", sinkURL.String(), bytes.NewBuffer(event))
+	if err != nil {
+		return fmt.Errorf("failed to construct event POST request %v (%w)", sinkURL.Redacted(), err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{
+		Timeout: timeout,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to POST event %v (%w)", sinkURL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read POST event response %v (%w)", sinkURL.Redacted(), err)
+		}
+		return fmt.Errorf("failed to POST event %v, status code: %d, response body: %s", sinkURL.Redacted(), resp.StatusCode, b)
+	}
+	return nil
+}
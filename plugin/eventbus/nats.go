@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultNATSPort = "4222"
+
+func init() {
+	register("nats", &NATSPublisher{})
+}
+
+// NATSPublisher is the publisher publishing the event to a NATS subject.
+// It speaks the NATS client protocol (https://docs.nats.io/reference/reference-protocols/nats-protocol) over plain TCP,
+// and opens a connection per event since the schema change events are infrequent.
+type NATSPublisher struct {
+}
+
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+}
+
+func (*NATSPublisher) publish(sinkURL *url.URL, event []byte) error {
+	subject := strings.Trim(sinkURL.Path, "/")
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("invalid NATS subject %q in sink URL %v", subject, sinkURL.Redacted())
+	}
+	host := sinkURL.Host
+	if sinkURL.Port() == "" {
+		host = net.JoinHostPort(sinkURL.Hostname(), defaultNATSPort)
+	}
+
+	conn, err := net.DialTimeout("tcp", host, timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect NATS server %v (%w)", sinkURL.Redacted(), err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(conn)
+
+	// The server greets with the INFO message once connected.
+	info, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read NATS server INFO %v (%w)", sinkURL.Redacted(), err)
+	}
+	if !strings.HasPrefix(info, "INFO ") {
+		return fmt.Errorf("unexpected NATS server greeting %q", strings.TrimSpace(info))
+	}
+
+	connect := natsConnect{Name: "bytebase"}
+	if sinkURL.User != nil {
+		connect.User = sinkURL.User.Username()
+		connect.Pass, _ = sinkURL.User.Password()
+	}
+	connectJSON, err := json.Marshal(connect)
+	if err != nil {
+		return err
+	}
+
+	// The PING after PUB makes the server reply the PONG after processing the PUB, or the -ERR if anything goes wrong.
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "CONNECT %s\r\n", connectJSON)
+	fmt.Fprintf(&buf, "PUB %s %d\r\n", subject, len(event))
+	buf.Write(event)
+	buf.WriteString("\r\nPING\r\n")
+	if _, err := conn.Write([]byte(buf.String())); err != nil {
+		return fmt.Errorf("failed to publish event to NATS server %v (%w)", sinkURL.Redacted(), err)
+	}
+
+	for {
+		reply, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read NATS server reply %v (%w)", sinkURL.Redacted(), err)
+		}
+		reply = strings.TrimSpace(reply)
+		switch {
+		case reply == "PONG":
+			return nil
+		case strings.HasPrefix(reply, "-ERR"):
+			return fmt.Errorf("NATS server %v replied error: %s", sinkURL.Redacted(), reply)
+		}
+		// Ignore the other replies such as +OK and the PING from the server.
+	}
+}
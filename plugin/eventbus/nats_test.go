@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// serveNATS serves a single NATS client connection and returns the subject and the payload published.
+func serveNATS(t *testing.T, listener net.Listener, reply string, result chan<- []string) {
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	_, err = conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+	require.NoError(t, err)
+
+	connect, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	pub, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	fields := strings.Fields(pub)
+	size, err := strconv.Atoi(fields[2])
+	require.NoError(t, err)
+	payload := make([]byte, size+2)
+	_, err = io.ReadFull(reader, payload)
+	require.NoError(t, err)
+	ping, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "PING\r\n", ping)
+
+	_, err = conn.Write([]byte(reply))
+	require.NoError(t, err)
+	result <- []string{strings.TrimSpace(connect), fields[1], string(payload[:size])}
+}
+
+func TestPublishNATS(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	result := make(chan []string, 1)
+	go serveNATS(t, listener, "PONG\r\n", result)
+	err = Publish("nats://alice:secret@"+listener.Addr().String()+"/bytebase.schema", []byte(`{"version":"v1"}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`CONNECT {"verbose":false,"pedantic":false,"name":"bytebase","user":"alice","pass":"secret"}`,
+		"bytebase.schema",
+		`{"version":"v1"}`,
+	}, <-result)
+
+	go serveNATS(t, listener, "-ERR 'Permissions Violation'\r\n", result)
+	err = Publish("nats://"+listener.Addr().String()+"/bytebase.schema", []byte(`{}`))
+	require.Error(t, err)
+	<-result
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate("nats://localhost:4222/bytebase.schema"))
+	require.NoError(t, Validate("https://example.com/events"))
+	require.Error(t, Validate("kafka://localhost:9092/topic"))
+}
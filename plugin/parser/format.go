@@ -0,0 +1,234 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type formatTokenType int
+
+const (
+	formatTokenText formatTokenType = iota
+	formatTokenBlank
+	formatTokenComment
+	formatTokenDelimiter
+)
+
+type formatToken struct {
+	tp   formatTokenType
+	text string
+}
+
+// FormatStatement formats the statement in the dialect of the engine.
+// Each statement is put on its own line with the whitespace collapsed, the comments are kept on their own lines.
+// The string literals, quoted identifiers and comments are kept as they are.
+func FormatStatement(engineType EngineType, statement string) (string, error) {
+	tokenList, err := scanFormatTokenList(engineType, statement)
+	if err != nil {
+		return "", err
+	}
+	if engineType == MySQL || engineType == TiDB {
+		// The statements after DELIMITER are terminated by the custom delimiter, which can't be split by semicolons.
+		for _, token := range tokenList {
+			if token.tp == formatTokenText && strings.EqualFold(token.text, "DELIMITER") {
+				return "", fmt.Errorf("formatting the statement with DELIMITER is not supported")
+			}
+		}
+	}
+
+	var lineList []string
+	var line strings.Builder
+	flush := func() {
+		if line.Len() > 0 {
+			lineList = append(lineList, line.String())
+			line.Reset()
+		}
+	}
+	prev, separated, inStatement := "", false, false
+	for _, token := range tokenList {
+		switch token.tp {
+		case formatTokenBlank:
+			separated = true
+		case formatTokenComment:
+			flush()
+			lineList = append(lineList, strings.TrimRightFunc(token.text, unicode.IsSpace))
+			prev, separated = "", false
+		case formatTokenDelimiter:
+			// Skips the empty statements.
+			if inStatement {
+				line.WriteString(token.text)
+				flush()
+			}
+			prev, separated, inStatement = "", false, false
+		case formatTokenText:
+			if line.Len() > 0 && separated && prev != "(" && !isClosingPunctuation(token.text) {
+				line.WriteByte(' ')
+			}
+			line.WriteString(token.text)
+			prev, separated, inStatement = token.text, false, true
+		}
+	}
+	flush()
+	return strings.Join(lineList, "\n"), nil
+}
+
+// CanonicalizeStatement returns the canonical form of the statement in the dialect of the engine.
+// The comments are removed and the whitespace is normalized, so the statements only differing in
+// comments and whitespace have the same canonical form and can be compared or hashed.
+func CanonicalizeStatement(engineType EngineType, statement string) (string, error) {
+	tokenList, err := scanFormatTokenList(engineType, statement)
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	prev, separated := "", false
+	for _, token := range tokenList {
+		switch token.tp {
+		case formatTokenBlank, formatTokenComment:
+			separated = true
+		case formatTokenDelimiter:
+			// Skips the empty statements.
+			if prev != "" && prev != token.text {
+				buf.WriteString(token.text)
+				prev = token.text
+			}
+			separated = false
+		case formatTokenText:
+			if prev != "" && separated && prev != "(" && prev != "," && prev != ";" && token.text != "(" && !isClosingPunctuation(token.text) {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(token.text)
+			prev, separated = token.text, false
+		}
+	}
+	// The delimiter of the last statement is optional.
+	return strings.TrimSuffix(buf.String(), ";"), nil
+}
+
+func isClosingPunctuation(text string) bool {
+	return text == ")" || text == ","
+}
+
+// scanFormatTokenList scans the statement into the tokens for formatting.
+// We mainly considered:
+//   - comments
+//     - style /* comments */
+//     - style -- comments
+//     - style # comments, MySQL only
+//   - string
+//     - style 'string'
+//     - style "string", MySQL only
+//     - style $$ string $$, PostgreSQL only
+//   - identifier
+//     - style `identifier`, MySQL only
+//     - style "identifier", PostgreSQL only
+func scanFormatTokenList(engineType EngineType, statement string) ([]formatToken, error) {
+	isMySQL := engineType == MySQL || engineType == TiDB
+	if !isMySQL && engineType != Postgres {
+		return nil, fmt.Errorf("engine type is not supported: %s", engineType)
+	}
+
+	t := newTokenizer(statement)
+	var tokenList []formatToken
+	for t.char(0) != eofRune {
+		startPos := t.pos()
+		tp := formatTokenText
+		switch {
+		case isBlank(t.char(0)):
+			t.skipBlank()
+			tp = formatTokenBlank
+		case t.char(0) == '/' && t.char(1) == '*':
+			if err := t.scanComment(); err != nil {
+				return nil, err
+			}
+			tp = formatTokenComment
+		case t.char(0) == '-' && t.char(1) == '-' && (!isMySQL || isBlank(t.char(2)) || t.char(2) == eofRune):
+			// MySQL requires the -- comment to be followed by a whitespace, otherwise it's the minus operators.
+			if err := t.scanComment(); err != nil {
+				return nil, err
+			}
+			tp = formatTokenComment
+		case isMySQL && t.char(0) == '#':
+			for t.char(0) != '\n' && t.char(0) != eofRune {
+				t.skip(1)
+			}
+			tp = formatTokenComment
+		case t.char(0) == '\'':
+			if err := t.scanString('\''); err != nil {
+				return nil, err
+			}
+		case isMySQL && t.char(0) == '"':
+			if err := t.scanString('"'); err != nil {
+				return nil, err
+			}
+		case isMySQL && t.char(0) == '`':
+			if err := t.scanIdentifier('`'); err != nil {
+				return nil, err
+			}
+		case !isMySQL && t.char(0) == '"':
+			if err := t.scanIdentifier('"'); err != nil {
+				return nil, err
+			}
+		case !isMySQL && t.isDollarQuoteStart():
+			if err := t.scanDoubleDollarQuotedString(); err != nil {
+				return nil, err
+			}
+		case t.char(0) == ';':
+			t.skip(1)
+			tp = formatTokenDelimiter
+		case t.char(0) == '(' || t.char(0) == ')' || t.char(0) == ',':
+			t.skip(1)
+		default:
+			t.skip(1)
+			for !t.isFormatTokenBoundary(isMySQL) {
+				t.skip(1)
+			}
+		}
+		tokenList = append(tokenList, formatToken{tp: tp, text: t.getString(startPos, t.pos()-startPos)})
+	}
+	return tokenList, nil
+}
+
+// isDollarQuoteStart returns true if the cursor is at the opening $$ or $tag$ of a dollar quoted string.
+// The positional parameters such as $1 aren't dollar quoted strings.
+func (t *tokenizer) isDollarQuoteStart() bool {
+	if t.char(0) != '$' {
+		return false
+	}
+	if unicode.IsDigit(t.char(1)) {
+		return false
+	}
+	for i := uint(1); ; i++ {
+		r := t.char(i)
+		if r == '$' {
+			return true
+		}
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+}
+
+// isFormatTokenBoundary returns true if the cursor is at the start of another token.
+// The dollar sign inside a word isn't a boundary because it's part of the identifier, e.g. foo$bar.
+func (t *tokenizer) isFormatTokenBoundary(isMySQL bool) bool {
+	switch r := t.char(0); {
+	case r == eofRune, isBlank(r):
+		return true
+	case r == ';', r == '(', r == ')', r == ',', r == '\'':
+		return true
+	case r == '/' && t.char(1) == '*', r == '-' && t.char(1) == '-':
+		return true
+	case isMySQL && (r == '"' || r == '`' || r == '#'):
+		return true
+	case !isMySQL && r == '"':
+		return true
+	}
+	return false
+}
+
+func isBlank(r rune) bool {
+	return r == ' ' || r == '\n' || r == '\r' || r == '\t'
+}
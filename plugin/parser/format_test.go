@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatStatement(t *testing.T) {
+	tests := []struct {
+		engineType EngineType
+		statement  string
+		want       string
+		wantErr    bool
+	}{
+		{
+			engineType: MySQL,
+			statement:  "CREATE TABLE t (\n\tid INT ,\n\tname VARCHAR(255)  DEFAULT 'a  b'\n);\n\n;ALTER TABLE `t  1`   ADD c INT",
+			want:       "CREATE TABLE t (id INT, name VARCHAR(255) DEFAULT 'a  b');\nALTER TABLE `t  1` ADD c INT",
+		},
+		{
+			engineType: MySQL,
+			statement:  "-- Add column\nALTER TABLE t ADD c INT; # trailing\nUPDATE t SET c = c--1;",
+			want:       "-- Add column\nALTER TABLE t ADD c INT;\n# trailing\nUPDATE t SET c = c--1;",
+		},
+		{
+			engineType: TiDB,
+			statement:  `INSERT INTO t VALUES ("a;b",   'it\'s');`,
+			want:       `INSERT INTO t VALUES ("a;b", 'it\'s');`,
+		},
+		{
+			engineType: Postgres,
+			statement:  "CREATE FUNCTION f() RETURNS INT AS $body$\n  SELECT 1;\n$body$ LANGUAGE SQL;\nSELECT   \"a  b\" FROM t WHERE id = $1 /* by id */;",
+			want:       "CREATE FUNCTION f() RETURNS INT AS $body$\n  SELECT 1;\n$body$ LANGUAGE SQL;\nSELECT \"a  b\" FROM t WHERE id = $1\n/* by id */\n;",
+		},
+		{
+			engineType: MySQL,
+			statement:  "DELIMITER ;;\nCREATE TRIGGER t1 BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.c = 1; END;;\nDELIMITER ;",
+			wantErr:    true,
+		},
+		{
+			engineType: MySQL,
+			statement:  "SELECT 'unterminated",
+			wantErr:    true,
+		},
+		{
+			engineType: EngineType("ORACLE"),
+			statement:  "SELECT 1",
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := FormatStatement(test.engineType, test.statement)
+		if test.wantErr {
+			require.Error(t, err, test.statement)
+			continue
+		}
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.want, got, test.statement)
+
+		// Formatting is idempotent.
+		again, err := FormatStatement(test.engineType, got)
+		require.NoError(t, err)
+		require.Equal(t, got, again)
+	}
+}
+
+func TestCanonicalizeStatement(t *testing.T) {
+	tests := []struct {
+		engineType EngineType
+		a          string
+		b          string
+		equal      bool
+	}{
+		{
+			engineType: MySQL,
+			a:          "ALTER TABLE t ADD c INT;",
+			b:          "-- Add column\nalter  TABLE t\n\tADD c INT",
+			equal:      false,
+		},
+		{
+			engineType: MySQL,
+			a:          "CREATE TABLE t (id INT, name VARCHAR(255));\nALTER TABLE t ADD c INT;",
+			b:          "/* table */ CREATE TABLE t(\n  id INT,\n  name VARCHAR (255)\n) ;;\n\nALTER TABLE t ADD c INT # column\n",
+			equal:      true,
+		},
+		{
+			engineType: MySQL,
+			a:          "UPDATE t SET c = 'a b';",
+			b:          "UPDATE t SET c = 'a  b';",
+			equal:      false,
+		},
+		{
+			engineType: Postgres,
+			a:          `SELECT "Name" FROM t;`,
+			b:          "SELECT\n  \"Name\"\nFROM t -- all rows",
+			equal:      true,
+		},
+		{
+			engineType: Postgres,
+			a:          `SELECT "a b" FROM t`,
+			b:          `SELECT "a  b" FROM t`,
+			equal:      false,
+		},
+	}
+
+	for _, test := range tests {
+		a, err := CanonicalizeStatement(test.engineType, test.a)
+		require.NoError(t, err)
+		b, err := CanonicalizeStatement(test.engineType, test.b)
+		require.NoError(t, err)
+		require.Equal(t, test.equal, a == b, "%q vs %q", a, b)
+	}
+}
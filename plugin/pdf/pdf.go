@@ -0,0 +1,180 @@
+// Package pdf is a minimal PDF writer for text documents.
+// It only supports the standard Helvetica fonts with the WinAnsiEncoding, so we don't need to embed any font files.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	// A4 page size in points.
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 50
+	// lineSpacing is the ratio of the line height to the font size.
+	lineSpacing = 1.4
+	// averageCharWidth is the approximate Helvetica character width relative to the font size, used to wrap lines.
+	averageCharWidth = 0.5
+)
+
+type line struct {
+	text   string
+	size   float64
+	bold   bool
+	indent float64
+	y      float64
+}
+
+// Document is a PDF document consisting of text lines flowing from top to bottom over A4 pages.
+type Document struct {
+	pageList [][]line
+	// y is the baseline of the next line on the last page.
+	y float64
+}
+
+// NewDocument creates a new document.
+func NewDocument() *Document {
+	return &Document{}
+}
+
+// AddText adds the text with the font size, the long text is wrapped and the text with newlines is split into multiple lines.
+func (d *Document) AddText(text string, size float64, bold bool) {
+	d.AddIndentedText(text, size, bold, 0)
+}
+
+// AddIndentedText is the same as AddText except that the lines are indented by the points.
+func (d *Document) AddIndentedText(text string, size float64, bold bool, indent float64) {
+	maxChars := int((pageWidth - 2*margin - indent) / (size * averageCharWidth))
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, s := range wrap(paragraph, maxChars) {
+			d.addLine(line{text: s, size: size, bold: bold, indent: indent})
+		}
+	}
+}
+
+// AddSpace adds the vertical space in points.
+func (d *Document) AddSpace(space float64) {
+	if len(d.pageList) == 0 {
+		d.newPage()
+	}
+	d.y -= space
+}
+
+func (d *Document) addLine(l line) {
+	height := l.size * lineSpacing
+	if len(d.pageList) == 0 || d.y-height < margin {
+		d.newPage()
+	}
+	d.y -= height
+	l.y = d.y
+	last := len(d.pageList) - 1
+	d.pageList[last] = append(d.pageList[last], l)
+}
+
+func (d *Document) newPage() {
+	d.pageList = append(d.pageList, nil)
+	d.y = pageHeight - margin
+}
+
+// Bytes renders the document in the PDF format.
+func (d *Document) Bytes() []byte {
+	pageList := d.pageList
+	if len(pageList) == 0 {
+		pageList = [][]line{nil}
+	}
+
+	// Object 1 is the catalog, 2 is the page tree, 3 and 4 are the fonts.
+	// Each page takes two objects, the page and its content stream.
+	var objectList []string
+	var kids []string
+	for i := range pageList {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	objectList = append(objectList,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pageList)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range pageList {
+		var content bytes.Buffer
+		for _, l := range page {
+			font := "F1"
+			if l.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, l.size, margin+l.indent, l.y, escape(l.text))
+		}
+		objectList = append(objectList,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	var offsetList []int
+	for i, object := range objectList {
+		offsetList = append(offsetList, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objectList)+1)
+	for _, offset := range offsetList {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objectList)+1, xrefOffset)
+	return buf.Bytes()
+}
+
+// wrap wraps the text into lines with at most maxChars characters, breaking at spaces if possible.
+func wrap(text string, maxChars int) []string {
+	runes := []rune(text)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return []string{text}
+	}
+	var lineList []string
+	for len(runes) > maxChars {
+		end := maxChars
+		for i := maxChars; i > 0; i-- {
+			if runes[i] == ' ' {
+				end = i
+				break
+			}
+		}
+		lineList = append(lineList, string(runes[:end]))
+		runes = runes[end:]
+		for len(runes) > 0 && runes[0] == ' ' {
+			runes = runes[1:]
+		}
+	}
+	if len(runes) > 0 {
+		lineList = append(lineList, string(runes))
+	}
+	return lineList
+}
+
+// escape escapes the text as a PDF literal string.
+// The characters out of the Latin-1 range can't be represented by the standard fonts and are replaced by "?".
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteRune(' ')
+		case r > 0xff:
+			b.WriteRune('?')
+		case r > 0x7e:
+			// Write the Latin-1 character as a single byte in the octal form, WinAnsiEncoding agrees with Latin-1 for most of them.
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{
+			text:     "short",
+			maxChars: 10,
+			want:     []string{"short"},
+		},
+		{
+			text:     "the quick brown fox",
+			maxChars: 10,
+			want:     []string{"the quick", "brown fox"},
+		},
+		{
+			text:     "abcdefghijkl",
+			maxChars: 5,
+			want:     []string{"abcde", "fghij", "kl"},
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, wrap(test.text, test.maxChars), test.text)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	require.Equal(t, `f\(x\) \\ caf\351 ?`, escape("f(x) \\ café 中"))
+}
+
+func TestDocumentPagination(t *testing.T) {
+	d := NewDocument()
+	for i := 0; i < 100; i++ {
+		d.AddText("line", 12, false)
+	}
+	// Each page holds (842 - 2 * 50) / (12 * 1.4) = 44 lines.
+	require.Len(t, d.pageList, 3)
+
+	content := string(d.Bytes())
+	require.True(t, strings.HasPrefix(content, "%PDF-1.4\n"))
+	require.Contains(t, content, "/Count 3")
+	require.True(t, strings.HasSuffix(content, "%%EOF\n"))
+}
@@ -0,0 +1,269 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+const (
+	// appJWTDuration is how long the JWT authenticating as the GitHub App is
+	// valid, GitHub rejects the JWT expiring more than 10 minutes in the future.
+	appJWTDuration = 9 * time.Minute
+	// appJWTClockDrift is subtracted from the issued time of the JWT to protect
+	// against the clock drift between Bytebase and GitHub.
+	appJWTClockDrift = time.Minute
+)
+
+// appRepositoryPermissions is the permissions requested for the installation
+// token of a repository, which are just enough to read the migration files,
+// list the collaborators and write back the latest schema.
+var appRepositoryPermissions = map[string]string{
+	"contents": "write",
+	"metadata": "read",
+}
+
+// Installation represents a GitHub API response for an app installation.
+type Installation struct {
+	ID      int64 `json:"id"`
+	Account struct {
+		Login string `json:"login"`
+	} `json:"account"`
+}
+
+// InstallationTokenCreate represents a GitHub API request for creating an
+// installation access token.
+type InstallationTokenCreate struct {
+	// Repositories is the list of repository names the token is scoped to, the
+	// token can access all repositories of the installation if empty.
+	Repositories []string `json:"repositories,omitempty"`
+	// Permissions is the permissions granted to the token, which can't exceed
+	// the permissions of the installation.
+	Permissions map[string]string `json:"permissions,omitempty"`
+}
+
+// InstallationToken represents a GitHub API response for an installation
+// access token.
+type InstallationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// InstallationRepositoryList represents a GitHub API response for the
+// repositories accessible to an installation.
+type InstallationRepositoryList struct {
+	TotalCount   int          `json:"total_count"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// AppClient calls the GitHub API as a GitHub App. Unlike the OAuth flow, it
+// authenticates with a short-lived installation token minted from the private
+// key of the app, so there is no refresh token to maintain.
+//
+// Docs: https://docs.github.com/en/developers/apps/building-github-apps/authenticating-with-github-apps
+type AppClient struct {
+	client     *http.Client
+	appID      string
+	privateKey *rsa.PrivateKey
+}
+
+// NewAppClient creates a GitHub App client with the app ID and the PEM encoded
+// private key of the app.
+func NewAppClient(client *http.Client, appID, privateKey string) (*AppClient, error) {
+	if appID == "" {
+		return nil, errors.New("GitHub App ID is required")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse GitHub App private key")
+	}
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &AppClient{
+		client:     client,
+		appID:      appID,
+		privateKey: key,
+	}, nil
+}
+
+// generateJWT generates the JWT authenticating as the GitHub App.
+func (c *AppClient) generateJWT(now time.Time) (string, error) {
+	claims := &jwt.RegisteredClaims{
+		Issuer:    c.appID,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-appJWTClockDrift)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(appJWTDuration)),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(c.privateKey)
+	if err != nil {
+		return "", errors.Wrap(err, "sign GitHub App JWT")
+	}
+	return token, nil
+}
+
+// request makes a HTTP request to the given URL using the token, which is
+// either the JWT of the app or an installation token.
+func (c *AppClient) request(ctx context.Context, method, url, token string, body io.Reader) (code int, respBody string, err error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "construct %s %s", method, url)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "%s %s", method, url)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "read response body with status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(b), nil
+}
+
+// CreateInstallationToken creates an installation token which is only allowed
+// to access the given repository with the minimal permissions. The token
+// expires in an hour.
+//
+// Docs: https://docs.github.com/en/rest/apps/apps#create-an-installation-access-token-for-an-app
+func (c *AppClient) CreateInstallationToken(ctx context.Context, instanceURL, repositoryFullName string) (*vcs.OAuthToken, error) {
+	appJWT, err := c.generateJWT(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := (&Provider{}).APIURL(instanceURL)
+	url := fmt.Sprintf("%s/repos/%s/installation", apiURL, repositoryFullName)
+	code, body, err := c.request(ctx, http.MethodGet, url, appJWT, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "GET")
+	}
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "the GitHub App is not installed on repository %s", repositoryFullName)
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to fetch installation from URL %s, status code: %d, body: %s", url, code, body)
+	}
+	var installation Installation
+	if err := json.Unmarshal([]byte(body), &installation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal installation")
+	}
+
+	// The repositories of the token are the names without the owner.
+	name := repositoryFullName[strings.LastIndex(repositoryFullName, "/")+1:]
+	token, err := c.createInstallationToken(ctx, apiURL, appJWT, installation.ID, InstallationTokenCreate{
+		Repositories: []string{name},
+		Permissions:  appRepositoryPermissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &vcs.OAuthToken{
+		AccessToken: token.Token,
+		ExpiresTs:   token.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (c *AppClient) createInstallationToken(ctx context.Context, apiURL, appJWT string, installationID int64, tokenCreate InstallationTokenCreate) (*InstallationToken, error) {
+	payload, err := json.Marshal(tokenCreate)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal installation token create")
+	}
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", apiURL, installationID)
+	code, body, err := c.request(ctx, http.MethodPost, url, appJWT, bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "POST")
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("failed to create installation token through URL %s, status code: %d, body: %s", url, code, body)
+	}
+	token := &InstallationToken{}
+	if err := json.Unmarshal([]byte(body), token); err != nil {
+		return nil, errors.Wrap(err, "unmarshal installation token")
+	}
+	return token, nil
+}
+
+// FetchAllRepositoryList fetches all repositories the GitHub App is installed
+// on, across all installations of the app.
+//
+// Docs: https://docs.github.com/en/rest/apps/installations#list-repositories-accessible-to-the-app-installation
+func (c *AppClient) FetchAllRepositoryList(ctx context.Context, instanceURL string) ([]*vcs.Repository, error) {
+	appJWT, err := c.generateJWT(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := (&Provider{}).APIURL(instanceURL)
+	var installationList []Installation
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/app/installations?page=%d&per_page=%d", apiURL, page, apiPageSize)
+		code, body, err := c.request(ctx, http.MethodGet, url, appJWT, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "GET")
+		}
+		if code >= 300 {
+			return nil, fmt.Errorf("failed to fetch installation list from URL %s, status code: %d, body: %s", url, code, body)
+		}
+		var installations []Installation
+		if err := json.Unmarshal([]byte(body), &installations); err != nil {
+			return nil, errors.Wrap(err, "unmarshal installation list")
+		}
+		installationList = append(installationList, installations...)
+		if len(installations) < apiPageSize {
+			break
+		}
+	}
+
+	var allRepos []*vcs.Repository
+	for _, installation := range installationList {
+		token, err := c.createInstallationToken(ctx, apiURL, appJWT, installation.ID, InstallationTokenCreate{
+			Permissions: map[string]string{"metadata": "read"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for page := 1; ; page++ {
+			url := fmt.Sprintf("%s/installation/repositories?page=%d&per_page=%d", apiURL, page, apiPageSize)
+			code, body, err := c.request(ctx, http.MethodGet, url, token.Token, nil)
+			if err != nil {
+				return nil, errors.Wrap(err, "GET")
+			}
+			if code >= 300 {
+				return nil, fmt.Errorf("failed to fetch installation repository list from URL %s, status code: %d, body: %s", url, code, body)
+			}
+			var repoList InstallationRepositoryList
+			if err := json.Unmarshal([]byte(body), &repoList); err != nil {
+				return nil, errors.Wrap(err, "unmarshal installation repository list")
+			}
+			for _, r := range repoList.Repositories {
+				allRepos = append(allRepos,
+					&vcs.Repository{
+						ID:       r.ID,
+						Name:     r.Name,
+						FullPath: r.FullName,
+						WebURL:   r.HTMLURL,
+					},
+				)
+			}
+			if len(repoList.Repositories) < apiPageSize {
+				break
+			}
+		}
+	}
+	return allRepos, nil
+}
@@ -3,7 +3,9 @@ package github
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -305,11 +307,234 @@ func (p *Provider) FetchCommitByID(ctx context.Context, oauthCtx common.OauthCon
 	}, nil
 }
 
+// commitSignatureQuery is the GraphQL query for the signature of a commit, the
+// REST API only tells whether the signature is verified but not the signing key.
+const commitSignatureQuery = `query($owner: String!, $name: String!, $oid: GitObjectID!) {
+  repository(owner: $owner, name: $name) {
+    object(oid: $oid) {
+      ... on Commit {
+        signature {
+          __typename
+          isValid
+          state
+          ... on GpgSignature { keyId }
+          ... on SmimeSignature { certificateSubject { emailAddress } }
+          ... on SshSignature { keyFingerprint }
+        }
+      }
+    }
+  }
+}`
+
+// CommitSignature represents a GitHub GraphQL API response for the signature
+// of a commit.
+type CommitSignature struct {
+	TypeName           string `json:"__typename"`
+	IsValid            bool   `json:"isValid"`
+	State              string `json:"state"`
+	KeyID              string `json:"keyId"`
+	CertificateSubject *struct {
+		EmailAddress string `json:"emailAddress"`
+	} `json:"certificateSubject"`
+	KeyFingerprint string `json:"keyFingerprint"`
+}
+
+// commitSignatureResponse represents a GitHub GraphQL API response for the
+// commitSignatureQuery.
+type commitSignatureResponse struct {
+	Data struct {
+		Repository *struct {
+			Object *struct {
+				Signature *CommitSignature `json:"signature"`
+			} `json:"object"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLURL returns the GraphQL API URL of GitHub.
+func graphQLURL(instanceURL string) string {
+	if instanceURL == githubComURL {
+		return "https://api.github.com/graphql"
+	}
+	return fmt.Sprintf("%s/api/graphql", instanceURL)
+}
+
+// FetchCommitSignature fetches the signature of the commit verified by GitHub.
+//
+// Docs: https://docs.github.com/en/graphql/reference/interfaces#gitsignature
+func (p *Provider) FetchCommitSignature(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string) (*vcs.CommitSignature, error) {
+	parts := strings.SplitN(repositoryID, "/", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("invalid repository full name %q", repositoryID)
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"query": commitSignatureQuery,
+		"variables": map[string]string{
+			"owner": parts[0],
+			"name":  parts[1],
+			"oid":   commitID,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal query")
+	}
+
+	url := graphQLURL(instanceURL)
+	code, body, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(payload),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST %s", url)
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("failed to fetch commit signature from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	var resp commitSignatureResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to fetch commit signature from URL %s, error: %s", url, resp.Errors[0].Message)
+	}
+	if resp.Data.Repository == nil || resp.Data.Repository.Object == nil {
+		return nil, common.Errorf(common.NotFound, "commit %s not found in repository %s", commitID, repositoryID)
+	}
+
+	signature := resp.Data.Repository.Object.Signature
+	if signature == nil {
+		return &vcs.CommitSignature{Signed: false}, nil
+	}
+	result := &vcs.CommitSignature{
+		Signed:   true,
+		Verified: signature.IsValid && signature.State == "VALID",
+		Status:   strings.ToLower(signature.State),
+	}
+	switch signature.TypeName {
+	case "GpgSignature":
+		result.Type = vcs.SignatureTypeGPG
+		result.KeyID = signature.KeyID
+	case "SmimeSignature":
+		result.Type = vcs.SignatureTypeX509
+		if signature.CertificateSubject != nil {
+			result.KeyID = signature.CertificateSubject.EmailAddress
+		}
+	case "SshSignature":
+		result.Type = vcs.SignatureTypeSSH
+		result.KeyID = sshKeyFingerprint(signature.KeyFingerprint)
+	default:
+		return nil, fmt.Errorf("unsupported commit signature type %q", signature.TypeName)
+	}
+	return result, nil
+}
+
+// sshKeyFingerprint converts the hex-encoded SHA256 fingerprint of the SSH key
+// to the format printed by ssh-keygen -l, e.g. "SHA256:<base64>".
+func sshKeyFingerprint(fingerprint string) string {
+	if strings.HasPrefix(fingerprint, "SHA256:") {
+		return fingerprint
+	}
+	sum, err := hex.DecodeString(fingerprint)
+	if err != nil || len(sum) != sha256.Size {
+		return fingerprint
+	}
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum)
+}
+
 // FetchUserInfo fetches user info of given user ID.
 func (p *Provider) FetchUserInfo(ctx context.Context, oauthCtx common.OauthContext, instanceURL, username string) (*vcs.UserInfo, error) {
 	return p.fetchUserInfoImpl(ctx, oauthCtx, instanceURL, fmt.Sprintf("users/%s", username))
 }
 
+// Team represents a GitHub API response for a team.
+type Team struct {
+	Slug         string `json:"slug"`
+	Organization struct {
+		Login string `json:"login"`
+	} `json:"organization"`
+}
+
+// FetchUserGroupList fetches the teams of the authenticated user in the form
+// of "<org>/<team>". It requires the read:org scope of the OAuth token.
+//
+// Docs: https://docs.github.com/en/rest/teams/teams#list-teams-for-the-authenticated-user
+func (p *Provider) FetchUserGroupList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) ([]string, error) {
+	var groupList []string
+	page := 1
+	for {
+		teams, hasNextPage, err := p.fetchPaginatedUserTeamList(ctx, oauthCtx, instanceURL, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetch paginated list")
+		}
+		for _, team := range teams {
+			groupList = append(groupList, fmt.Sprintf("%s/%s", team.Organization.Login, team.Slug))
+		}
+
+		if !hasNextPage {
+			break
+		}
+		page++
+	}
+	return groupList, nil
+}
+
+// fetchPaginatedUserTeamList fetches the teams of the authenticated user in
+// given page. It return the paginated results along with a boolean indicating
+// whether the next page exists.
+func (p *Provider) fetchPaginatedUserTeamList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, page int) (teams []Team, hasNextPage bool, err error) {
+	url := fmt.Sprintf("%s/user/teams?page=%d&per_page=%d", p.APIURL(instanceURL), page, apiPageSize)
+	code, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, false, common.Errorf(common.NotFound, "failed to fetch team list from URL %s", url)
+	} else if code >= 300 {
+		return nil, false,
+			fmt.Errorf("failed to fetch team list from URL %s, status code: %d, body: %s",
+				url,
+				code,
+				body,
+			)
+	}
+
+	if err := json.Unmarshal([]byte(body), &teams); err != nil {
+		return nil, false, errors.Wrap(err, "unmarshal body")
+	}
+	return teams, len(teams) >= apiPageSize, nil
+}
+
 func getRoleAndMappedRole(roleName string) (githubRole RepositoryRole, bytebaseRole common.ProjectRole) {
 	// Please refer to https://docs.github.com/en/organizations/managing-access-to-your-organizations-repositories/repository-roles-for-an-organization#repository-roles-for-organizations
 	// for the detailed role descriptions of GitHub.
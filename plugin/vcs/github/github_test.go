@@ -93,6 +93,51 @@ func TestProvider_FetchUserInfo(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestProvider_FetchUserGroupList(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/user/teams", r.URL.Path)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							// Example response taken from https://docs.github.com/en/rest/teams/teams#list-teams-for-the-authenticated-user
+							Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "id": 1,
+    "node_id": "MDQ6VGVhbTE=",
+    "url": "https://api.github.com/teams/1",
+    "html_url": "https://github.com/orgs/github/teams/justice-league",
+    "name": "Justice League",
+    "slug": "justice-league",
+    "description": "A great team.",
+    "privacy": "closed",
+    "permission": "admin",
+    "parent": null,
+    "organization": {
+      "login": "github",
+      "id": 1,
+      "node_id": "MDEyOk9yZ2FuaXphdGlvbjE=",
+      "url": "https://api.github.com/orgs/github"
+    }
+  }
+]
+`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchUserGroupList(ctx, common.OauthContext{}, githubComURL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github/justice-league"}, got)
+}
+
 func TestProvider_FetchRepositoryActiveMemberList(t *testing.T) {
 	t.Run("missing public email", func(t *testing.T) {
 		p := newProvider(
@@ -391,6 +436,81 @@ func TestProvider_FetchCommitByID(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestProvider_FetchCommitSignature(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *vcs.CommitSignature
+	}{
+		{
+			name: "GPG",
+			body: `{"data": {"repository": {"object": {"signature": {"__typename": "GpgSignature", "isValid": true, "state": "VALID", "keyId": "4AEE18F83AFDEB23"}}}}}`,
+			want: &vcs.CommitSignature{
+				Signed:   true,
+				Type:     vcs.SignatureTypeGPG,
+				Verified: true,
+				KeyID:    "4AEE18F83AFDEB23",
+				Status:   "valid",
+			},
+		},
+		{
+			name: "X509",
+			body: `{"data": {"repository": {"object": {"signature": {"__typename": "SmimeSignature", "isValid": false, "state": "UNKNOWN_SIG_TYPE", "certificateSubject": {"emailAddress": "octocat@github.com"}}}}}}`,
+			want: &vcs.CommitSignature{
+				Signed:   true,
+				Type:     vcs.SignatureTypeX509,
+				Verified: false,
+				KeyID:    "octocat@github.com",
+				Status:   "unknown_sig_type",
+			},
+		},
+		{
+			name: "SSH",
+			body: `{"data": {"repository": {"object": {"signature": {"__typename": "SshSignature", "isValid": true, "state": "VALID", "keyFingerprint": "8adf2cf52d1d42677f2f46be4ea9079696a2f08a7ab7d57e0a1e7ff328ecd6d4"}}}}}`,
+			want: &vcs.CommitSignature{
+				Signed:   true,
+				Type:     vcs.SignatureTypeSSH,
+				Verified: true,
+				KeyID:    "SHA256:it8s9S0dQmd/L0a+TqkHlpai8Ip6t9V+Ch5/8yjs1tQ",
+				Status:   "valid",
+			},
+		},
+		{
+			name: "unsigned",
+			body: `{"data": {"repository": {"object": {"signature": null}}}}`,
+			want: &vcs.CommitSignature{Signed: false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newProvider(
+				vcs.ProviderConfig{
+					Client: &http.Client{
+						Transport: &common.MockRoundTripper{
+							MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+								assert.Equal(t, "/graphql", r.URL.Path)
+								body, err := io.ReadAll(r.Body)
+								require.NoError(t, err)
+								assert.Contains(t, string(body), `"variables":{"name":"Hello-World","oid":"7638417db6d59f3c431d3e1f261cc637155684cd","owner":"octocat"}`)
+								return &http.Response{
+									StatusCode: http.StatusOK,
+									Body:       io.NopCloser(strings.NewReader(test.body)),
+								}, nil
+							},
+						},
+					},
+				},
+			)
+
+			ctx := context.Background()
+			got, err := p.FetchCommitSignature(ctx, common.OauthContext{}, githubComURL, "octocat/Hello-World", "7638417db6d59f3c431d3e1f261cc637155684cd")
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
 func TestProvider_ExchangeOAuthToken(t *testing.T) {
 	p := newProvider(
 		vcs.ProviderConfig{
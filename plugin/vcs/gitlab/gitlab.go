@@ -3,6 +3,7 @@ package gitlab
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -424,11 +425,172 @@ func (p *Provider) FetchCommitByID(ctx context.Context, oauthCtx common.OauthCon
 	}, nil
 }
 
+// CommitSignature represents a GitLab API response for the signature of a commit.
+type CommitSignature struct {
+	SignatureType      string `json:"signature_type"`
+	VerificationStatus string `json:"verification_status"`
+	// GPGKeyPrimaryKeyID is set for the PGP signature.
+	GPGKeyPrimaryKeyID string `json:"gpg_key_primary_keyid"`
+	// X509Certificate is set for the X.509 signature.
+	X509Certificate *struct {
+		Email string `json:"email"`
+	} `json:"x509_certificate"`
+	// Key is set for the SSH signature.
+	Key *struct {
+		Key string `json:"key"`
+	} `json:"key"`
+}
+
+// FetchCommitSignature fetches the signature of the commit verified by GitLab.
+//
+// Docs: https://docs.gitlab.com/ee/api/commits.html#get-gpg-signature-of-a-commit
+func (p *Provider) FetchCommitSignature(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string) (*vcs.CommitSignature, error) {
+	url := fmt.Sprintf("%s/projects/%s/repository/commits/%s/signature", p.APIURL(instanceURL), repositoryID, commitID)
+	code, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "GET")
+	}
+	// GitLab responds 404 for both the unsigned commit and the nonexistent commit.
+	if code == http.StatusNotFound {
+		return &vcs.CommitSignature{Signed: false}, nil
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to fetch commit signature from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	signature := &CommitSignature{}
+	if err := json.Unmarshal([]byte(body), signature); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+
+	result := &vcs.CommitSignature{
+		Signed:   true,
+		Verified: signature.VerificationStatus == "verified",
+		Status:   signature.VerificationStatus,
+	}
+	switch signature.SignatureType {
+	case "PGP":
+		result.Type = vcs.SignatureTypeGPG
+		result.KeyID = signature.GPGKeyPrimaryKeyID
+	case "X509":
+		result.Type = vcs.SignatureTypeX509
+		if signature.X509Certificate != nil {
+			result.KeyID = signature.X509Certificate.Email
+		}
+	case "SSH":
+		result.Type = vcs.SignatureTypeSSH
+		if signature.Key != nil {
+			result.KeyID = sshKeyFingerprint(signature.Key.Key)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported commit signature type %q", signature.SignatureType)
+	}
+	return result, nil
+}
+
+// sshKeyFingerprint returns the SHA256 fingerprint of the SSH public key in the authorized_keys format,
+// e.g. "ssh-ed25519 AAAA... comment", the same as the one printed by ssh-keygen -l.
+func sshKeyFingerprint(publicKey string) string {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return ""
+	}
+	key, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(key)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
 // FetchUserInfo fetches user info of given user ID.
 func (p *Provider) FetchUserInfo(ctx context.Context, oauthCtx common.OauthContext, instanceURL, userID string) (*vcs.UserInfo, error) {
 	return p.fetchUserInfoImpl(ctx, oauthCtx, instanceURL, fmt.Sprintf("users/%s", userID))
 }
 
+// FetchUserGroupList fetches the full paths of the groups the authenticated user is a member of.
+//
+// Docs: https://docs.gitlab.com/ee/api/groups.html#list-groups
+func (p *Provider) FetchUserGroupList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) ([]string, error) {
+	var groupList []string
+	page := 1
+	for {
+		// The minimal access level 10 (Guest) limits the groups to the ones the user is a member of,
+		// otherwise all the visible groups are returned.
+		groups, hasNextPage, err := p.fetchPaginatedGroupList(ctx, oauthCtx, instanceURL, 10 /* Guest */, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetch paginated list")
+		}
+		for _, group := range groups {
+			groupList = append(groupList, group.FullPath)
+		}
+
+		if !hasNextPage {
+			break
+		}
+		page++
+	}
+	return groupList, nil
+}
+
+// fetchPaginatedGroupList fetches the groups where the authenticated user has
+// at least the minimal access level in given page. It return the paginated
+// results along with a boolean indicating whether the next page exists.
+func (p *Provider) fetchPaginatedGroupList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, minAccessLevel, page int) (groups []Group, hasNextPage bool, err error) {
+	url := fmt.Sprintf("%s/groups?min_access_level=%d&page=%d&per_page=%d", p.APIURL(instanceURL), minAccessLevel, page, apiPageSize)
+	code, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, false, common.Errorf(common.NotFound, "failed to fetch group list from URL %s", url)
+	} else if code >= 300 {
+		return nil, false,
+			fmt.Errorf("failed to fetch group list from URL %s, status code: %d, body: %s",
+				url,
+				code,
+				body,
+			)
+	}
+
+	if err := json.Unmarshal([]byte(body), &groups); err != nil {
+		return nil, false, errors.Wrap(err, "unmarshal")
+	}
+	return groups, len(groups) >= apiPageSize, nil
+}
+
 func getRoleAndMappedRole(accessLevel int32) (gitLabRole ProjectRole, bytebaseRole common.ProjectRole) {
 	// Please refer to https://docs.gitlab.com/ee/api/members.html for the detailed role descriptions of GitLab.
 	switch accessLevel {
@@ -68,6 +68,54 @@ func TestProvider_FetchUserInfo(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestProvider_FetchUserGroupList(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/groups", r.URL.Path)
+						assert.Equal(t, "10", r.URL.Query().Get("min_access_level"))
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							// Example response taken from https://docs.gitlab.com/ee/api/groups.html#list-groups
+							Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "id": 1,
+    "name": "Foobar Group",
+    "path": "foo-bar",
+    "description": "An interesting group",
+    "visibility": "public",
+    "full_name": "Foobar Group",
+    "full_path": "foo-bar",
+    "parent_id": null
+  },
+  {
+    "id": 2,
+    "name": "DBA",
+    "path": "dba",
+    "description": "",
+    "visibility": "private",
+    "full_name": "Foobar Group / DBA",
+    "full_path": "foo-bar/dba",
+    "parent_id": 1
+  }
+]
+`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchUserGroupList(ctx, common.OauthContext{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo-bar", "foo-bar/dba"}, got)
+}
+
 func TestProvider_FetchRepositoryActiveMemberList(t *testing.T) {
 	t.Run("missing public email", func(t *testing.T) {
 		p := newProvider(
@@ -324,6 +372,115 @@ func TestProvider_FetchCommitByID(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestProvider_FetchCommitSignature(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+		body string
+		want *vcs.CommitSignature
+	}{
+		{
+			name: "PGP",
+			code: http.StatusOK,
+			// Example response taken from https://docs.gitlab.com/ee/api/commits.html#get-gpg-signature-of-a-commit
+			body: `
+{
+  "signature_type": "PGP",
+  "verification_status": "verified",
+  "gpg_key_id": 1,
+  "gpg_key_primary_keyid": "8254AAB3FBD54AC9",
+  "gpg_key_user_name": "John Doe",
+  "gpg_key_user_email": "johndoe@example.com",
+  "gpg_key_subkey_id": null,
+  "commit_source": "gitaly"
+}`,
+			want: &vcs.CommitSignature{
+				Signed:   true,
+				Type:     vcs.SignatureTypeGPG,
+				Verified: true,
+				KeyID:    "8254AAB3FBD54AC9",
+				Status:   "verified",
+			},
+		},
+		{
+			name: "X509",
+			code: http.StatusOK,
+			body: `
+{
+  "signature_type": "X509",
+  "verification_status": "unverified",
+  "x509_certificate": {
+    "id": 1,
+    "subject": "CN=gitlab@example.org,OU=Example,O=World",
+    "email": "gitlab@example.org",
+    "certificate_status": "good"
+  },
+  "commit_source": "gitaly"
+}`,
+			want: &vcs.CommitSignature{
+				Signed:   true,
+				Type:     vcs.SignatureTypeX509,
+				Verified: false,
+				KeyID:    "gitlab@example.org",
+				Status:   "unverified",
+			},
+		},
+		{
+			name: "SSH",
+			code: http.StatusOK,
+			body: `
+{
+  "signature_type": "SSH",
+  "verification_status": "verified",
+  "key": {
+    "id": 11,
+    "title": "Key",
+    "key": "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMWQt5bTlpUrMZvAOTXUeWYDp6Mm7SMoZ3gre7ItBXMP test"
+  },
+  "commit_source": "gitaly"
+}`,
+			want: &vcs.CommitSignature{
+				Signed:   true,
+				Type:     vcs.SignatureTypeSSH,
+				Verified: true,
+				KeyID:    "SHA256:it8s9S0dQmd/L0a+TqkHlpai8Ip6t9V+Ch5/8yjs1tQ",
+				Status:   "verified",
+			},
+		},
+		{
+			name: "unsigned",
+			code: http.StatusNotFound,
+			body: `{"message": "404 Signature Not Found"}`,
+			want: &vcs.CommitSignature{Signed: false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newProvider(
+				vcs.ProviderConfig{
+					Client: &http.Client{
+						Transport: &common.MockRoundTripper{
+							MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+								assert.Equal(t, "/api/v4/projects/5/repository/commits/6104942438c14ec7bd21c6cd5bd995272b3faff6/signature", r.URL.Path)
+								return &http.Response{
+									StatusCode: test.code,
+									Body:       io.NopCloser(strings.NewReader(test.body)),
+								}, nil
+							},
+						},
+					},
+				},
+			)
+
+			ctx := context.Background()
+			got, err := p.FetchCommitSignature(ctx, common.OauthContext{}, "", "5", "6104942438c14ec7bd21c6cd5bd995272b3faff6")
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
 func TestProvider_ExchangeOAuthToken(t *testing.T) {
 	p := newProvider(
 		vcs.ProviderConfig{
@@ -0,0 +1,207 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/internal/oauth"
+)
+
+// Group is the API message for a GitLab group.
+type Group struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	FullPath string `json:"full_path"`
+	// ParentID is the ID of the parent group of a subgroup, it's 0 for the top-level groups.
+	ParentID int64  `json:"parent_id"`
+	WebURL   string `json:"web_url"`
+}
+
+// FetchGroupList fetches the groups and subgroups where the authenticated user
+// has a maintainer role, which is required to link the repositories in them.
+// The subgroups are returned along with their parent groups, and are told apart
+// by the ParentID.
+//
+// Docs: https://docs.gitlab.com/ee/api/groups.html#list-groups
+func (p *Provider) FetchGroupList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) ([]*Group, error) {
+	var groupList []*Group
+	page := 1
+	for {
+		groups, hasNextPage, err := p.fetchPaginatedGroupList(ctx, oauthCtx, instanceURL, 40 /* Maintainer */, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetch paginated list")
+		}
+		for i := range groups {
+			groupList = append(groupList, &groups[i])
+		}
+
+		if !hasNextPage {
+			break
+		}
+		page++
+	}
+	return groupList, nil
+}
+
+// FetchGroupRepositoryList fetches the repositories in the group and all its
+// nested subgroups where the authenticated user has a maintainer role.
+//
+// Docs: https://docs.gitlab.com/ee/api/groups.html#list-a-groups-projects
+func (p *Provider) FetchGroupRepositoryList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, groupID string) ([]*vcs.Repository, error) {
+	var allRepos []*vcs.Repository
+	page := 1
+	for {
+		repos, hasNextPage, err := p.fetchPaginatedGroupRepositoryList(ctx, oauthCtx, instanceURL, groupID, page)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetch paginated list")
+		}
+		for _, r := range repos {
+			allRepos = append(allRepos,
+				&vcs.Repository{
+					ID:       r.ID,
+					Name:     r.Name,
+					FullPath: r.PathWithNamespace,
+					WebURL:   r.WebURL,
+				},
+			)
+		}
+
+		if !hasNextPage {
+			break
+		}
+		page++
+	}
+	return allRepos, nil
+}
+
+// fetchPaginatedGroupRepositoryList fetches the repositories in the group and
+// its subgroups in given page. It return the paginated results along with a
+// boolean indicating whether the next page exists.
+func (p *Provider) fetchPaginatedGroupRepositoryList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, groupID string, page int) (repos []gitLabRepository, hasNextPage bool, err error) {
+	url := fmt.Sprintf("%s/groups/%s/projects?include_subgroups=true&simple=true&min_access_level=40&page=%d&per_page=%d", p.APIURL(instanceURL), url.PathEscape(groupID), page, apiPageSize)
+	code, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, false, common.Errorf(common.NotFound, "failed to fetch group repository list from URL %s", url)
+	} else if code >= 300 {
+		return nil, false,
+			fmt.Errorf("failed to fetch group repository list from URL %s, status code: %d, body: %s",
+				url,
+				code,
+				body,
+			)
+	}
+
+	if err := json.Unmarshal([]byte(body), &repos); err != nil {
+		return nil, false, errors.Wrap(err, "unmarshal")
+	}
+	return repos, len(repos) >= apiPageSize, nil
+}
+
+// CreateGroupWebhook creates a webhook in the group with given payload, which
+// is the same as the one of the project webhook. The group webhook receives the
+// events of all the repositories in the group and its subgroups, and requires
+// the owner role of the group and GitLab Premium. Returns the created webhook
+// ID on success.
+//
+// Docs: https://docs.gitlab.com/ee/api/groups.html#add-group-hook
+func (p *Provider) CreateGroupWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, groupID string, payload []byte) (string, error) {
+	url := fmt.Sprintf("%s/groups/%s/hooks", p.APIURL(instanceURL), url.PathEscape(groupID))
+	code, body, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(payload),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to create group webhook through URL %s", url)
+	} else if code >= 300 {
+		return "", fmt.Errorf("failed to create group webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var webhookInfo WebhookInfo
+	if err = json.Unmarshal([]byte(body), &webhookInfo); err != nil {
+		return "", errors.Wrap(err, "unmarshal body")
+	}
+	return strconv.Itoa(webhookInfo.ID), nil
+}
+
+// DeleteGroupWebhook deletes the webhook from the group.
+//
+// Docs: https://docs.gitlab.com/ee/api/groups.html#delete-group-hook
+func (p *Provider) DeleteGroupWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, groupID, webhookID string) error {
+	url := fmt.Sprintf("%s/groups/%s/hooks/%s", p.APIURL(instanceURL), url.PathEscape(groupID), webhookID)
+	code, body, err := oauth.Delete(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "DELETE %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil // It is OK if the webhook has already gone
+	} else if code >= 300 {
+		return fmt.Errorf("failed to delete group webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
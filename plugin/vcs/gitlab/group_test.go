@@ -0,0 +1,135 @@
+package gitlab
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestProvider_FetchGroupList(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/groups", r.URL.Path)
+						assert.Equal(t, "40", r.URL.Query().Get("min_access_level"))
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							// Example response taken from https://docs.gitlab.com/ee/api/groups.html#list-groups
+							Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "id": 1,
+    "name": "Foobar Group",
+    "path": "foo-bar",
+    "full_path": "foo-bar",
+    "web_url": "http://localhost:3000/groups/foo-bar",
+    "parent_id": null
+  },
+  {
+    "id": 2,
+    "name": "Backend",
+    "path": "backend",
+    "full_path": "foo-bar/backend",
+    "web_url": "http://localhost:3000/groups/foo-bar/backend",
+    "parent_id": 1
+  }
+]
+`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.(*Provider).FetchGroupList(ctx, common.OauthContext{}, "")
+	require.NoError(t, err)
+
+	want := []*Group{
+		{ID: 1, Name: "Foobar Group", FullPath: "foo-bar", WebURL: "http://localhost:3000/groups/foo-bar"},
+		{ID: 2, Name: "Backend", FullPath: "foo-bar/backend", ParentID: 1, WebURL: "http://localhost:3000/groups/foo-bar/backend"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchGroupRepositoryList(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "/api/v4/groups/1/projects", r.URL.Path)
+						assert.Equal(t, "true", r.URL.Query().Get("include_subgroups"))
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							// Example response taken from https://docs.gitlab.com/ee/api/groups.html#list-a-groups-projects
+							Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "id": 9,
+    "description": "foo",
+    "default_branch": "master",
+    "name": "Html5 Boilerplate",
+    "name_with_namespace": "Experimental / Html5 Boilerplate",
+    "path": "html5-boilerplate",
+    "path_with_namespace": "h5bp/experimental/html5-boilerplate",
+    "web_url": "http://example.com/h5bp/experimental/html5-boilerplate"
+  }
+]
+`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.(*Provider).FetchGroupRepositoryList(ctx, common.OauthContext{}, "", "1")
+	require.NoError(t, err)
+
+	want := []*vcs.Repository{
+		{
+			ID:       9,
+			Name:     "Html5 Boilerplate",
+			FullPath: "h5bp/experimental/html5-boilerplate",
+			WebURL:   "http://example.com/h5bp/experimental/html5-boilerplate",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreateGroupWebhook(t *testing.T) {
+	p := newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, http.MethodPost, r.Method)
+						assert.Equal(t, "/api/v4/groups/1/hooks", r.URL.Path)
+						return &http.Response{
+							StatusCode: http.StatusCreated,
+							Body:       io.NopCloser(strings.NewReader(`{"id": 7, "url": "http://example.com/hook", "group_id": 1, "push_events": true}`)),
+						}, nil
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.(*Provider).CreateGroupWebhook(ctx, common.OauthContext{}, "", "1", []byte("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, "7", got)
+}
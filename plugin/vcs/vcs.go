@@ -19,6 +19,16 @@ const (
 	GitHubCom Type = "GITHUB_COM"
 )
 
+// AuthMode is the way Bytebase authenticates against a VCS.
+type AuthMode string
+
+const (
+	// AuthModeOAuth calls the VCS API on behalf of the user linking the repository through the OAuth application.
+	AuthModeOAuth AuthMode = "OAUTH"
+	// AuthModeGitHubApp calls the GitHub API as a GitHub App installation with short-lived installation tokens.
+	AuthModeGitHubApp AuthMode = "GITHUB_APP"
+)
+
 // OAuthToken is the API message for OAuthToken.
 type OAuthToken struct {
 	AccessToken  string `json:"access_token" `
@@ -51,6 +61,33 @@ type FileCommit struct {
 	AuthorName  string `json:"authorName"`
 	AuthorEmail string `json:"authorEmail"`
 	Added       string `json:"added"`
+	// Signature is the verified signature of the commit, which is only set when the repository requires signed commits.
+	Signature *CommitSignature `json:"signature,omitempty"`
+}
+
+// SignatureType is the type of a commit signature.
+type SignatureType string
+
+const (
+	// SignatureTypeGPG is the signature type for the GPG signature.
+	SignatureTypeGPG SignatureType = "GPG"
+	// SignatureTypeX509 is the signature type for the X.509 signature, e.g. the Sigstore signature made by gitsign.
+	SignatureTypeX509 SignatureType = "X509"
+	// SignatureTypeSSH is the signature type for the SSH signature.
+	SignatureTypeSSH SignatureType = "SSH"
+)
+
+// CommitSignature is the signature of a commit verified by the VCS.
+type CommitSignature struct {
+	// Signed is false if the commit isn't signed, and the other fields are empty.
+	Signed bool          `json:"signed"`
+	Type   SignatureType `json:"type"`
+	// Verified is true if the VCS has verified the signature against the key of the signer.
+	Verified bool `json:"verified"`
+	// KeyID identifies the signing key, which is the GPG key ID, the email of the X.509 certificate or the SHA256 fingerprint of the SSH key.
+	KeyID string `json:"keyId"`
+	// Status is the verification status reported by the VCS, e.g. unknown_key.
+	Status string `json:"status"`
 }
 
 // FileCommitCreate is the payload for committing a new file.
@@ -146,12 +183,24 @@ type Provider interface {
 	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
 	// commitID: the commit ID
 	FetchCommitByID(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string) (*Commit, error)
+	// Fetch the signature of the commit verified by the VCS
+	//
+	// oauthCtx: OAuth context to fetch the commit signature
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// commitID: the commit ID
+	FetchCommitSignature(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string) (*CommitSignature, error)
 	// Fetch the user info of the given userID
 	//
 	// oauthCtx: OAuth context to write the file content
 	// instanceURL: VCS instance URL
 	// user: the ID or username of the desired user
 	FetchUserInfo(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, user string) (*UserInfo, error)
+	// Fetch the groups of the user in the OAuth context, e.g. the GitLab groups or the GitHub teams
+	//
+	// oauthCtx: OAuth context of the user
+	// instanceURL: VCS instance URL
+	FetchUserGroupList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) ([]string, error)
 	// Fetch all active members of a given repository
 	//
 	// oauthCtx: OAuth context to write the file content
@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -41,6 +42,16 @@ type Issue struct {
 	Status      string `json:"status"`
 	Type        string `json:"type"`
 	Description string `json:"description"`
+	// Fields is the custom fields set on the issue.
+	Fields []IssueField `json:"fields,omitempty"`
+}
+
+// IssueField object of issue custom field.
+type IssueField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Value is the email of the principal for the USER field.
+	Value string `json:"value"`
 }
 
 // Project object of project.
@@ -63,6 +74,8 @@ type Context struct {
 	CreatedTs    int64
 	Issue        *Issue
 	Project      *Project
+	// SchemaChange is the JSON encoded schema change event, only set for the schema change activity.
+	SchemaChange json.RawMessage
 }
 
 // Receiver is the webhook receiver.
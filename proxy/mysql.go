@@ -0,0 +1,314 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/packet"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// mysqlClearPasswordPlugin is the authentication plugin sending the password in clear text,
+	// the mysql client needs --enable-cleartext-plugin to use it.
+	mysqlClearPasswordPlugin = "mysql_clear_password"
+	// mysqlServerCapability is the capabilities of the proxy, CLIENT_SSL is added if TLS is configured.
+	mysqlServerCapability = mysql.CLIENT_LONG_PASSWORD | mysql.CLIENT_LONG_FLAG | mysql.CLIENT_CONNECT_WITH_DB |
+		mysql.CLIENT_PROTOCOL_41 | mysql.CLIENT_TRANSACTIONS | mysql.CLIENT_SECURE_CONNECTION | mysql.CLIENT_PLUGIN_AUTH |
+		mysql.CLIENT_PLUGIN_AUTH_LENENC_CLIENT_DATA
+	// mysqlSSLRequestLength is the length of the SSL request, which is the beginning of the handshake response.
+	mysqlSSLRequestLength = 32
+)
+
+// mysqlHandshakeResponse is the handshake response of a MySQL client.
+type mysqlHandshakeResponse struct {
+	capability uint32
+	user       string
+	authData   []byte
+	database   string
+	plugin     string
+}
+
+// serveMySQL serves a MySQL client.
+func (p *Proxy) serveMySQL(ctx context.Context, conn net.Conn) error {
+	// The connection isn't buffered if it may be upgraded to TLS, otherwise the buffer would swallow the TLS handshake.
+	c := packet.NewConn(conn)
+	capability := uint32(mysqlServerCapability)
+	if p.profile.TLSConfig != nil {
+		c = packet.NewTLSConn(conn)
+		capability |= mysql.CLIENT_SSL
+	}
+
+	salt, err := mysql.RandomBuf(20)
+	if err != nil {
+		return err
+	}
+	if err := c.WritePacket(p.composeMySQLHandshake(capability, salt)); err != nil {
+		return err
+	}
+	data, err := c.ReadPacket()
+	if err != nil {
+		return err
+	}
+	resp, err := parseMySQLHandshakeResponse(data)
+	if err != nil {
+		return err
+	}
+	secure := false
+	if len(data) == mysqlSSLRequestLength && resp.capability&mysql.CLIENT_SSL != 0 && p.profile.TLSConfig != nil {
+		tlsConn := tls.Server(conn, p.profile.TLSConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return err
+		}
+		sequence := c.Sequence
+		c = packet.NewTLSConn(tlsConn)
+		c.Sequence = sequence
+		secure = true
+		if data, err = c.ReadPacket(); err != nil {
+			return err
+		}
+		if resp, err = parseMySQLHandshakeResponse(data); err != nil {
+			return err
+		}
+	}
+	if p.profile.TLSConfig != nil && !secure {
+		return writeMySQLError(c, mysql.ER_ACCESS_DENIED_ERROR, "SSL connection is required by the Bytebase proxy")
+	}
+
+	password := resp.authData
+	if resp.plugin != mysqlClearPasswordPlugin {
+		// Switch to the clear text password, since the password can't be checked against the scramble of the other plugins.
+		switchRequest := []byte{0, 0, 0, 0, mysql.EOF_HEADER}
+		switchRequest = append(switchRequest, mysqlClearPasswordPlugin...)
+		switchRequest = append(switchRequest, 0)
+		if err := c.WritePacket(switchRequest); err != nil {
+			return err
+		}
+		if password, err = c.ReadPacket(); err != nil {
+			return err
+		}
+	}
+	connect := &api.ProxyConnect{
+		Email:    resp.user,
+		Password: string(bytes.TrimRight(password, "\x00")),
+		Database: resp.database,
+		Protocol: db.MySQL,
+	}
+	session, err := p.gateway.Connect(ctx, connect)
+	if err != nil {
+		if writeErr := writeMySQLError(c, mysql.ER_UNKNOWN_ERROR, fmt.Sprintf("failed to connect to Bytebase, error: %v", err)); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+	if session.Error != "" {
+		return writeMySQLError(c, mysql.ER_ACCESS_DENIED_ERROR, session.Error)
+	}
+	if err := writeMySQLOK(c); err != nil {
+		return err
+	}
+
+	for {
+		c.ResetSequence()
+		data, err := c.ReadPacket()
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("empty command packet")
+		}
+		switch data[0] {
+		case mysql.COM_QUIT:
+			return nil
+		case mysql.COM_PING:
+			if err := writeMySQLOK(c); err != nil {
+				return err
+			}
+		case mysql.COM_INIT_DB:
+			// Switching the database is connecting to another database, which is checked again.
+			connect.Database = string(data[1:])
+			newSession, err := p.gateway.Connect(ctx, connect)
+			if err != nil {
+				if err := writeMySQLError(c, mysql.ER_UNKNOWN_ERROR, fmt.Sprintf("failed to connect to Bytebase, error: %v", err)); err != nil {
+					return err
+				}
+				continue
+			}
+			if newSession.Error != "" {
+				if err := writeMySQLError(c, mysql.ER_DBACCESS_DENIED_ERROR, newSession.Error); err != nil {
+					return err
+				}
+				continue
+			}
+			session = newSession
+			if err := writeMySQLOK(c); err != nil {
+				return err
+			}
+		case mysql.COM_QUERY:
+			if err := p.runMySQLQuery(ctx, c, session, string(data[1:])); err != nil {
+				return err
+			}
+		default:
+			if err := writeMySQLError(c, mysql.ER_UNKNOWN_COM_ERROR, fmt.Sprintf("command %d isn't supported by the Bytebase proxy", data[0])); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// composeMySQLHandshake composes the initial handshake packet, see
+// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase_packets_protocol_handshake_v10.html.
+func (p *Proxy) composeMySQLHandshake(capability uint32, salt []byte) []byte {
+	connectionID := p.nextConnectionID()
+	data := make([]byte, 4, 128)
+	data = append(data, 10)
+	data = append(data, fmt.Sprintf("8.0.0-bytebase-proxy-%s", p.profile.Version)...)
+	data = append(data, 0)
+	data = append(data, byte(connectionID), byte(connectionID>>8), byte(connectionID>>16), byte(connectionID>>24))
+	data = append(data, salt[:8]...)
+	data = append(data, 0)
+	data = append(data, byte(capability), byte(capability>>8))
+	data = append(data, mysql.DEFAULT_COLLATION_ID)
+	data = append(data, byte(mysql.SERVER_STATUS_AUTOCOMMIT), byte(mysql.SERVER_STATUS_AUTOCOMMIT>>8))
+	data = append(data, byte(capability>>16), byte(capability>>24))
+	data = append(data, byte(len(salt)+1))
+	data = append(data, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+	data = append(data, salt[8:]...)
+	data = append(data, 0)
+	data = append(data, mysqlClearPasswordPlugin...)
+	data = append(data, 0)
+	return data
+}
+
+// parseMySQLHandshakeResponse parses the handshake response of the protocol 4.1, see
+// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase_packets_protocol_handshake_response.html.
+// Only the capabilities are parsed from the SSL request.
+func parseMySQLHandshakeResponse(data []byte) (*mysqlHandshakeResponse, error) {
+	if len(data) < mysqlSSLRequestLength {
+		return nil, fmt.Errorf("malformed handshake response of length %d", len(data))
+	}
+	resp := &mysqlHandshakeResponse{capability: binary.LittleEndian.Uint32(data)}
+	if resp.capability&mysql.CLIENT_PROTOCOL_41 == 0 {
+		return nil, fmt.Errorf("only the clients of the protocol 4.1 are supported")
+	}
+	if len(data) == mysqlSSLRequestLength {
+		return resp, nil
+	}
+
+	pos := mysqlSSLRequestLength
+	readNullTerminated := func() string {
+		i := bytes.IndexByte(data[pos:], 0)
+		if i < 0 {
+			// The last field may omit the terminator.
+			s := string(data[pos:])
+			pos = len(data)
+			return s
+		}
+		s := string(data[pos : pos+i])
+		pos += i + 1
+		return s
+	}
+	resp.user = readNullTerminated()
+	if pos >= len(data) {
+		return nil, fmt.Errorf("malformed handshake response, missing auth data")
+	}
+
+	var authLength int
+	switch {
+	case resp.capability&mysql.CLIENT_PLUGIN_AUTH_LENENC_CLIENT_DATA != 0:
+		n, _, l := mysql.LengthEncodedInt(data[pos:])
+		pos += l
+		authLength = int(n)
+	case resp.capability&mysql.CLIENT_SECURE_CONNECTION != 0:
+		authLength = int(data[pos])
+		pos++
+	default:
+		authLength = bytes.IndexByte(data[pos:], 0) + 1
+	}
+	if authLength < 0 || pos+authLength > len(data) {
+		return nil, fmt.Errorf("malformed handshake response, auth data of length %d overflows", authLength)
+	}
+	resp.authData = data[pos : pos+authLength]
+	pos += authLength
+
+	if resp.capability&mysql.CLIENT_CONNECT_WITH_DB != 0 && pos < len(data) {
+		resp.database = readNullTerminated()
+	}
+	if resp.capability&mysql.CLIENT_PLUGIN_AUTH != 0 && pos < len(data) {
+		resp.plugin = readNullTerminated()
+	}
+	return resp, nil
+}
+
+// runMySQLQuery runs the query and writes the result, the query errors are written to the client rather than returned.
+func (p *Proxy) runMySQLQuery(ctx context.Context, c *packet.Conn, session *api.ProxySession, statement string) error {
+	result, err := p.gateway.Query(ctx, &api.ProxyQuery{Token: session.Token, Statement: statement})
+	if err != nil {
+		return writeMySQLError(c, mysql.ER_UNKNOWN_ERROR, fmt.Sprintf("failed to run the query via Bytebase, error: %v", err))
+	}
+	if result.Error != "" {
+		return writeMySQLError(c, mysql.ER_SPECIFIC_ACCESS_DENIED_ERROR, result.Error)
+	}
+	rs, err := parseRowSet(result.Data)
+	if err != nil {
+		return writeMySQLError(c, mysql.ER_UNKNOWN_ERROR, err.Error())
+	}
+
+	// The text result set, see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_query_response_text_resultset.html.
+	data := make([]byte, 4, 1024)
+	data = append(data, mysql.PutLengthEncodedInt(uint64(len(rs.columnNames)))...)
+	if err := c.WritePacket(data); err != nil {
+		return err
+	}
+	for _, name := range rs.columnNames {
+		field := &mysql.Field{Name: []byte(name), Charset: uint16(mysql.DEFAULT_COLLATION_ID), Type: mysql.MYSQL_TYPE_VAR_STRING}
+		data = append(data[:4], field.Dump()...)
+		if err := c.WritePacket(data); err != nil {
+			return err
+		}
+	}
+	if err := writeMySQLEOF(c); err != nil {
+		return err
+	}
+	for _, row := range rs.rows {
+		data = data[:4]
+		for _, value := range row {
+			if b := formatValue(value, "1", "0"); b != nil {
+				data = append(data, mysql.PutLengthEncodedString(b)...)
+			} else {
+				data = append(data, 0xfb)
+			}
+		}
+		if err := c.WritePacket(data); err != nil {
+			return err
+		}
+	}
+	return writeMySQLEOF(c)
+}
+
+func writeMySQLOK(c *packet.Conn) error {
+	data := []byte{0, 0, 0, 0, mysql.OK_HEADER, 0, 0}
+	data = append(data, byte(mysql.SERVER_STATUS_AUTOCOMMIT), byte(mysql.SERVER_STATUS_AUTOCOMMIT>>8), 0, 0)
+	return c.WritePacket(data)
+}
+
+func writeMySQLEOF(c *packet.Conn) error {
+	data := []byte{0, 0, 0, 0, mysql.EOF_HEADER, 0, 0}
+	data = append(data, byte(mysql.SERVER_STATUS_AUTOCOMMIT), byte(mysql.SERVER_STATUS_AUTOCOMMIT>>8))
+	return c.WritePacket(data)
+}
+
+func writeMySQLError(c *packet.Conn, code uint16, message string) error {
+	e := mysql.NewError(code, message)
+	data := []byte{0, 0, 0, 0, mysql.ERR_HEADER, byte(e.Code), byte(e.Code >> 8), '#'}
+	data = append(data, e.State...)
+	data = append(data, e.Message...)
+	return c.WritePacket(data)
+}
@@ -0,0 +1,208 @@
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgproto3/v2"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// pgTextOID is the OID of the text type, all columns are sent as text since the row set doesn't keep the types.
+	pgTextOID = 25
+
+	// The SQLSTATE codes sent to the clients.
+	pgInvalidAuthorization = "28000"
+	pgFeatureNotSupported  = "0A000"
+	pgAccessRuleViolation  = "42000"
+	pgInternalError        = "XX000"
+	pgWarning              = "01000"
+)
+
+// servePostgres serves a Postgres client.
+func (p *Proxy) servePostgres(ctx context.Context, conn net.Conn) error {
+	backend := pgproto3.NewBackend(pgproto3.NewChunkReader(conn), conn)
+	secure := false
+	var startup *pgproto3.StartupMessage
+	for startup == nil {
+		msg, err := backend.ReceiveStartupMessage()
+		if err != nil {
+			return err
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.SSLRequest:
+			if p.profile.TLSConfig == nil {
+				if _, err := conn.Write([]byte{'N'}); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := conn.Write([]byte{'S'}); err != nil {
+				return err
+			}
+			tlsConn := tls.Server(conn, p.profile.TLSConfig)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				return err
+			}
+			conn, secure = tlsConn, true
+			backend = pgproto3.NewBackend(pgproto3.NewChunkReader(conn), conn)
+		case *pgproto3.GSSEncRequest:
+			if _, err := conn.Write([]byte{'N'}); err != nil {
+				return err
+			}
+		case *pgproto3.StartupMessage:
+			startup = msg
+		default:
+			// The queries run on the Bytebase server, so there is nothing to cancel here.
+			return fmt.Errorf("unsupported startup message %T", msg)
+		}
+	}
+	if p.profile.TLSConfig != nil && !secure {
+		return sendPostgresError(backend, "FATAL", pgInvalidAuthorization, "SSL connection is required by the Bytebase proxy")
+	}
+
+	if err := backend.Send(&pgproto3.AuthenticationCleartextPassword{}); err != nil {
+		return err
+	}
+	if err := backend.SetAuthType(pgproto3.AuthTypeCleartextPassword); err != nil {
+		return err
+	}
+	msg, err := backend.Receive()
+	if err != nil {
+		return err
+	}
+	password, ok := msg.(*pgproto3.PasswordMessage)
+	if !ok {
+		return fmt.Errorf("expect password message but got %T", msg)
+	}
+	session, err := p.gateway.Connect(ctx, &api.ProxyConnect{
+		Email:    startup.Parameters["user"],
+		Password: password.Password,
+		Database: startup.Parameters["database"],
+		Protocol: db.Postgres,
+	})
+	if err != nil {
+		if sendErr := sendPostgresError(backend, "FATAL", pgInternalError, fmt.Sprintf("failed to connect to Bytebase, error: %v", err)); sendErr != nil {
+			return sendErr
+		}
+		return err
+	}
+	if session.Error != "" {
+		return sendPostgresError(backend, "FATAL", pgInvalidAuthorization, session.Error)
+	}
+
+	if err := backend.Send(&pgproto3.AuthenticationOk{}); err != nil {
+		return err
+	}
+	for _, parameter := range []pgproto3.ParameterStatus{
+		{Name: "server_version", Value: session.EngineVersion},
+		{Name: "server_encoding", Value: "UTF8"},
+		{Name: "client_encoding", Value: "UTF8"},
+		{Name: "DateStyle", Value: "ISO, MDY"},
+		{Name: "integer_datetimes", Value: "on"},
+		{Name: "standard_conforming_strings", Value: "on"},
+	} {
+		parameter := parameter
+		if err := backend.Send(&parameter); err != nil {
+			return err
+		}
+	}
+	if err := backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'}); err != nil {
+		return err
+	}
+
+	// The extended query protocol isn't supported since the statements are checked as a whole before running.
+	// Its error is sent once and the following messages are discarded until Sync, same as a Postgres server.
+	discarding := false
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return err
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			if err := p.runPostgresQuery(ctx, backend, session, msg.String); err != nil {
+				return err
+			}
+			if err := backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'}); err != nil {
+				return err
+			}
+		case *pgproto3.Sync:
+			discarding = false
+			if err := backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'}); err != nil {
+				return err
+			}
+		case *pgproto3.Flush:
+		case *pgproto3.Terminate:
+			return nil
+		default:
+			if discarding {
+				continue
+			}
+			discarding = true
+			if err := sendPostgresError(backend, "ERROR", pgFeatureNotSupported, "the extended query protocol isn't supported by the Bytebase proxy, please use the simple query protocol"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// runPostgresQuery runs the query and sends the result, the query errors are sent to the client rather than returned.
+func (p *Proxy) runPostgresQuery(ctx context.Context, backend *pgproto3.Backend, session *api.ProxySession, statement string) error {
+	if strings.TrimSpace(strings.TrimRight(strings.TrimSpace(statement), ";")) == "" {
+		return backend.Send(&pgproto3.EmptyQueryResponse{})
+	}
+	result, err := p.gateway.Query(ctx, &api.ProxyQuery{Token: session.Token, Statement: statement})
+	if err != nil {
+		return sendPostgresError(backend, "ERROR", pgInternalError, fmt.Sprintf("failed to run the query via Bytebase, error: %v", err))
+	}
+	for _, advice := range result.AdviceList {
+		if advice.Status != advisor.Warn && advice.Status != advisor.Error {
+			continue
+		}
+		if err := backend.Send(&pgproto3.NoticeResponse{Severity: "WARNING", Code: pgWarning, Message: fmt.Sprintf("%s: %s", advice.Title, advice.Content)}); err != nil {
+			return err
+		}
+	}
+	if result.Error != "" {
+		return sendPostgresError(backend, "ERROR", pgAccessRuleViolation, result.Error)
+	}
+	rs, err := parseRowSet(result.Data)
+	if err != nil {
+		return sendPostgresError(backend, "ERROR", pgInternalError, err.Error())
+	}
+
+	rowDescription := &pgproto3.RowDescription{}
+	for _, name := range rs.columnNames {
+		rowDescription.Fields = append(rowDescription.Fields, pgproto3.FieldDescription{
+			Name:         []byte(name),
+			DataTypeOID:  pgTextOID,
+			DataTypeSize: -1,
+			TypeModifier: -1,
+		})
+	}
+	if err := backend.Send(rowDescription); err != nil {
+		return err
+	}
+	for _, row := range rs.rows {
+		dataRow := &pgproto3.DataRow{}
+		for _, value := range row {
+			dataRow.Values = append(dataRow.Values, formatValue(value, "t", "f"))
+		}
+		if err := backend.Send(dataRow); err != nil {
+			return err
+		}
+	}
+	return backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(fmt.Sprintf("SELECT %d", len(rs.rows)))})
+}
+
+func sendPostgresError(backend *pgproto3.Backend, severity, code, message string) error {
+	return backend.Send(&pgproto3.ErrorResponse{Severity: severity, Code: code, Message: message})
+}
@@ -0,0 +1,189 @@
+// Package proxy is the wire protocol proxy run by the agent for the ad-hoc access with psql, mysql and the other clients.
+// It terminates the Postgres and the MySQL wire connections, and the Bytebase server authenticates the user with the
+// Bytebase credentials, checks the access to the database and the policies, and runs each query on the database.
+// So the proxied sessions are governed and audited the same as the SQL editor, and the clients never see the
+// credentials of the database.
+//
+// The clients send the password in clear text, since the server checks it against the password hash, so the proxy
+// should be served over TLS. Only the simple query protocol is supported, and each statement must be read-only.
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+// Gateway is the Bytebase server governing the proxied sessions.
+type Gateway interface {
+	// Connect authenticates the user and checks the access to the database.
+	Connect(ctx context.Context, connect *api.ProxyConnect) (*api.ProxySession, error)
+	// Query runs the query in the session.
+	Query(ctx context.Context, query *api.ProxyQuery) (*api.ProxyQueryResult, error)
+}
+
+// Profile is the configuration of the proxy.
+type Profile struct {
+	// PostgresAddr is the address to listen for the Postgres clients, e.g. ":5432", empty to disable.
+	PostgresAddr string
+	// MySQLAddr is the address to listen for the MySQL clients, e.g. ":3306", empty to disable.
+	MySQLAddr string
+	// TLSConfig is the TLS configuration of the proxy. The clients must connect over TLS if it's set.
+	TLSConfig *tls.Config
+	// Version is the version of the proxy, reported to the MySQL clients.
+	Version string
+}
+
+// Proxy is the wire protocol proxy.
+type Proxy struct {
+	profile Profile
+	gateway Gateway
+	// connectionID is the last ID of the connections, reported to the MySQL clients.
+	connectionID uint32
+}
+
+// NewProxy creates a proxy.
+func NewProxy(profile Profile, gateway Gateway) *Proxy {
+	return &Proxy{
+		profile: profile,
+		gateway: gateway,
+	}
+}
+
+// Run serves the clients until ctx is canceled, the open connections are closed then.
+func (p *Proxy) Run(ctx context.Context) error {
+	type server struct {
+		name  string
+		addr  string
+		serve func(ctx context.Context, conn net.Conn) error
+	}
+	var serverList []server
+	if p.profile.PostgresAddr != "" {
+		serverList = append(serverList, server{name: "Postgres", addr: p.profile.PostgresAddr, serve: p.servePostgres})
+	}
+	if p.profile.MySQLAddr != "" {
+		serverList = append(serverList, server{name: "MySQL", addr: p.profile.MySQLAddr, serve: p.serveMySQL})
+	}
+
+	var listenerList []net.Listener
+	for _, server := range serverList {
+		listener, err := net.Listen("tcp", server.addr)
+		if err != nil {
+			for _, l := range listenerList {
+				l.Close()
+			}
+			return fmt.Errorf("failed to listen on %s for the %s clients, error: %w", server.addr, server.name, err)
+		}
+		listenerList = append(listenerList, listener)
+	}
+
+	var wg sync.WaitGroup
+	for i, server := range serverList {
+		listener := listenerList[i]
+		log.Info(fmt.Sprintf("Proxy is serving the %s clients on %s", server.name, listener.Addr()))
+		wg.Add(1)
+		go func(name string, serve func(ctx context.Context, conn net.Conn) error) {
+			defer wg.Done()
+			p.accept(ctx, &wg, listener, name, serve)
+		}(server.name, server.serve)
+	}
+	<-ctx.Done()
+	for _, listener := range listenerList {
+		listener.Close()
+	}
+	wg.Wait()
+	return nil
+}
+
+// accept serves each connection of the listener in its own goroutine until the listener is closed.
+func (p *Proxy) accept(ctx context.Context, wg *sync.WaitGroup, listener net.Listener, name string, serve func(ctx context.Context, conn net.Conn) error) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Error("Failed to accept proxy connection", zap.String("protocol", name), zap.Error(err))
+			}
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			connCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			// Close the connection on shutdown to unblock the read.
+			go func() {
+				<-connCtx.Done()
+				conn.Close()
+			}()
+			if err := serve(connCtx, conn); err != nil && connCtx.Err() == nil {
+				log.Debug("Proxy connection closed", zap.String("protocol", name), zap.String("client", conn.RemoteAddr().String()), zap.Error(err))
+			}
+		}()
+	}
+}
+
+// nextConnectionID returns the ID of a new connection.
+func (p *Proxy) nextConnectionID() uint32 {
+	return atomic.AddUint32(&p.connectionID, 1)
+}
+
+// rowSet is the decoded row set of a query result.
+type rowSet struct {
+	columnNames []string
+	rows        [][]interface{}
+}
+
+// parseRowSet decodes the JSON encoded row set, which is [columnNames, columnTypeNames, rows].
+// The numbers are decoded as json.Number to keep the precision of the large integers.
+func parseRowSet(data string) (*rowSet, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		return nil, fmt.Errorf("malformed row set, error: %w", err)
+	}
+	if len(raw) < 3 {
+		return nil, fmt.Errorf("malformed row set, expect 3 parts but got %d", len(raw))
+	}
+	rs := &rowSet{}
+	if err := json.Unmarshal(raw[0], &rs.columnNames); err != nil {
+		return nil, fmt.Errorf("malformed column names, error: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw[2]))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rs.rows); err != nil {
+		return nil, fmt.Errorf("malformed rows, error: %w", err)
+	}
+	return rs, nil
+}
+
+// formatValue formats the value in the row set as the text sent to the client, it returns nil for NULL.
+// The booleans are formatted as the given texts since the protocols differ.
+func formatValue(value interface{}, trueText, falseText string) []byte {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []byte(v)
+	case json.Number:
+		return []byte(v.String())
+	case bool:
+		if v {
+			return []byte(trueText)
+		}
+		return []byte(falseText)
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return []byte(fmt.Sprint(value))
+	}
+	return b
+}
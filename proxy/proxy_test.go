@@ -0,0 +1,170 @@
+package proxy
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// fakeGateway accepts alice with the password "secret" on the database "prod/employee".
+type fakeGateway struct{}
+
+func (*fakeGateway) Connect(_ context.Context, connect *api.ProxyConnect) (*api.ProxySession, error) {
+	if connect.Email != "alice@example.com" || connect.Password != "secret" {
+		return &api.ProxySession{Error: "incorrect email or password"}, nil
+	}
+	if connect.Database != "prod/employee" {
+		return &api.ProxySession{Error: fmt.Sprintf("database %q not found", connect.Database)}, nil
+	}
+	return &api.ProxySession{Token: "token", InstanceName: "prod", DatabaseName: "employee", EngineVersion: "14.2"}, nil
+}
+
+func (*fakeGateway) Query(_ context.Context, query *api.ProxyQuery) (*api.ProxyQueryResult, error) {
+	if query.Token != "token" {
+		return &api.ProxyQueryResult{Error: "invalid proxy session"}, nil
+	}
+	if query.Statement != "SELECT id, email, active FROM users" {
+		return &api.ProxyQueryResult{Error: "only a single SELECT or EXPLAIN statement is allowed"}, nil
+	}
+	return &api.ProxyQueryResult{
+		Data: `[["id","email","active"],["INT","TEXT","BOOL"],[[9007199254740993,"******",true],[2,null,false]]]`,
+	}, nil
+}
+
+// startProxy starts the proxy on the free ports and returns the Postgres and the MySQL addresses.
+func startProxy(t *testing.T) (string, string) {
+	var addrList []string
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addrList = append(addrList, listener.Addr().String())
+		require.NoError(t, listener.Close())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- NewProxy(Profile{PostgresAddr: addrList[0], MySQLAddr: addrList[1], Version: "test"}, &fakeGateway{}).Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	// Wait for the listeners.
+	for _, addr := range addrList {
+		require.Eventually(t, func() bool {
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				return false
+			}
+			conn.Close()
+			return true
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	return addrList[0], addrList[1]
+}
+
+func TestPostgresProxy(t *testing.T) {
+	pgAddr, _ := startProxy(t)
+	host, port, err := net.SplitHostPort(pgAddr)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	connect := func(password string) (*pgx.Conn, error) {
+		config, err := pgx.ParseConfig(fmt.Sprintf("host=%s port=%s user=alice@example.com password=%s dbname=prod/employee sslmode=disable", host, port, password))
+		require.NoError(t, err)
+		config.PreferSimpleProtocol = true
+		return pgx.ConnectConfig(ctx, config)
+	}
+
+	_, err = connect("wrong")
+	require.ErrorContains(t, err, "incorrect email or password")
+
+	conn, err := connect("secret")
+	require.NoError(t, err)
+	defer conn.Close(ctx)
+
+	rows, err := conn.Query(ctx, "SELECT id, email, active FROM users")
+	require.NoError(t, err)
+	var got [][]interface{}
+	for rows.Next() {
+		var id string
+		var email *string
+		var active string
+		require.NoError(t, rows.Scan(&id, &email, &active))
+		got = append(got, []interface{}{id, email, active})
+	}
+	require.NoError(t, rows.Err())
+	masked := "******"
+	require.Equal(t, [][]interface{}{
+		{"9007199254740993", &masked, "t"},
+		{"2", (*string)(nil), "f"},
+	}, got)
+
+	// The rejected statement doesn't break the session.
+	_, err = conn.Exec(ctx, "DELETE FROM users")
+	require.ErrorContains(t, err, "only a single SELECT or EXPLAIN statement is allowed")
+	var id string
+	require.NoError(t, conn.QueryRow(ctx, "SELECT id, email, active FROM users").Scan(&id, nil, nil))
+	require.Equal(t, "9007199254740993", id)
+}
+
+func TestMySQLProxy(t *testing.T) {
+	_, mysqlAddr := startProxy(t)
+	open := func(password, database string) *sql.DB {
+		config := mysql.NewConfig()
+		config.User = "alice@example.com"
+		config.Passwd = password
+		config.Net = "tcp"
+		config.Addr = mysqlAddr
+		config.DBName = database
+		config.AllowCleartextPasswords = true
+		// The database name has a slash, so the connector is used rather than the DSN.
+		connector, err := mysql.NewConnector(config)
+		require.NoError(t, err)
+		return sql.OpenDB(connector)
+	}
+
+	db := open("wrong", "prod/employee")
+	require.ErrorContains(t, db.Ping(), "incorrect email or password")
+	db.Close()
+
+	db = open("secret", "prod/employee")
+	defer db.Close()
+	rows, err := db.Query("SELECT id, email, active FROM users")
+	require.NoError(t, err)
+	var got [][]interface{}
+	for rows.Next() {
+		var id string
+		var email sql.NullString
+		var active string
+		require.NoError(t, rows.Scan(&id, &email, &active))
+		got = append(got, []interface{}{id, email, active})
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, [][]interface{}{
+		{"9007199254740993", sql.NullString{String: "******", Valid: true}, "1"},
+		{"2", sql.NullString{}, "0"},
+	}, got)
+
+	_, err = db.Exec("DELETE FROM users")
+	require.ErrorContains(t, err, "only a single SELECT or EXPLAIN statement is allowed")
+}
+
+func TestParseMySQLHandshakeResponse(t *testing.T) {
+	_, err := parseMySQLHandshakeResponse([]byte{1, 2, 3})
+	require.Error(t, err)
+
+	// The handshake response without CLIENT_PROTOCOL_41.
+	_, err = parseMySQLHandshakeResponse(make([]byte, mysqlSSLRequestLength))
+	require.Error(t, err)
+}
@@ -89,45 +89,22 @@ func Install(resourceDir string) error {
 
 	mysqlutilDir := path.Join(resourceDir, version)
 
-	if _, err := os.Stat(mysqlutilDir); err != nil {
-		if !os.IsNotExist(err) {
-			return fmt.Errorf("failed to check binary directory path %q, error: %w", mysqlutilDir, err)
-		}
-		// Install if not exist yet
-		if err := installImpl(resourceDir, mysqlutilDir, tarName, version); err != nil {
-			return fmt.Errorf("cannot install mysqlutil, error: %w", err)
-		}
+	// Skip the extraction if a valid installation exists, e.g. the resource directory is read-only in the container.
+	installed, err := utils.IsInstalled(mysqlutilDir, resourceChecksum)
+	if err != nil {
+		return err
 	}
-
-	checks := []string{
-		// TODO(zp): remove this line in the next few months
-		// We change Dockerfile's base image from alpine to debian slim in v1.2.1,
-		// and add libncurses.so.5 and libtinfo.so.5 to make mysqlbinlog run successfully.
-		// So we need to reinstall mysqlutil if the users upgrade from an older version that missing these shared libraries.
-		path.Join(mysqlutilDir, "lib", "private", "libncurses.so.5"),
-		path.Join(mysqlutilDir, "lib", "private", "libtinfo.so.5"),
-
-		// We embed mysqldump in version v1.2.3.
-		path.Join(mysqlutilDir, "bin", "mysqldump"),
+	if installed {
+		return nil
 	}
-
-	for _, fp := range checks {
-		if _, err := os.Stat(fp); err != nil {
-			if !os.IsNotExist(err) {
-				return fmt.Errorf("failed to check libncurses library path %q, error: %w", fp, err)
-			}
-			// Remove mysqlutil of old version and reinstall it
-			if err := os.RemoveAll(mysqlutilDir); err != nil {
-				return fmt.Errorf("failed to remove the old version mysqlutil binary directory %q, error: %w", mysqlutilDir, err)
-			}
-			// Install the current version
-			if err := installImpl(resourceDir, mysqlutilDir, tarName, version); err != nil {
-				return fmt.Errorf("cannot install mysqlutil, error: %w", err)
-			}
-			break
-		}
+	// Remove the missing or mutated installation and reinstall it.
+	// The installation of an older version may miss the files, e.g. libncurses.so.5 and libtinfo.so.5 added in v1.2.1 and mysqldump in v1.2.3.
+	if err := os.RemoveAll(mysqlutilDir); err != nil {
+		return fmt.Errorf("failed to remove the old version mysqlutil binary directory %q, error: %w", mysqlutilDir, err)
+	}
+	if err := installImpl(resourceDir, mysqlutilDir, tarName, version); err != nil {
+		return fmt.Errorf("cannot install mysqlutil, error: %w", err)
 	}
-
 	return nil
 }
 
@@ -147,6 +124,9 @@ func installImpl(resourceDir, mysqlutilDir, tarName, version string) error {
 	if err := utils.ExtractTarGz(f, tmpDir); err != nil {
 		return fmt.Errorf("failed to extract tar.gz file, error: %w", err)
 	}
+	if err := utils.VerifyChecksum(tmpDir, resourceChecksum); err != nil {
+		return fmt.Errorf("failed to verify the extracted mysqlutil binaries, error: %w", err)
+	}
 
 	if err := os.Rename(tmpDir, mysqlutilDir); err != nil {
 		return fmt.Errorf("failed to rename mysqlutil binaries directory from %q to %q, error: %w", tmpDir, mysqlutilDir, err)
@@ -35,8 +35,7 @@ func TestRunBinary(t *testing.T) {
 	})
 }
 
-// TODO(zp): remove this test when remove the related block in mysqlutil.go.
-// TestReinstallOnLinuxAmd64 tests is it possible to reinstall mysqlutil on linux amd64.
+// TestReinstallOnLinuxAmd64 tests is it possible to reinstall mysqlutil on linux amd64 if the files are missing.
 func TestReinstallOnLinuxAmd64(t *testing.T) {
 	t.Parallel()
 
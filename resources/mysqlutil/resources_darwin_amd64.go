@@ -4,3 +4,6 @@ import "embed"
 
 //go:embed mysqlutil-8.0.28-macos11-x86_64.tar.gz
 var resources embed.FS
+
+// resourceChecksum is the checksum of the extracted embedded resource, see utils.DirectoryChecksum.
+const resourceChecksum = "0ce686f36c4db1f44cd8666855861e9b7fc2f0a818dbd0766ba903824a24205a"
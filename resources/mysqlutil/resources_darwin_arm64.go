@@ -4,3 +4,6 @@ import "embed"
 
 //go:embed mysqlutil-8.0.28-macos11-arm64.tar.gz
 var resources embed.FS
+
+// resourceChecksum is the checksum of the extracted embedded resource, see utils.DirectoryChecksum.
+const resourceChecksum = "f604db65f109f90e5ae31c530b987c4162f0554793066776f7fdc036b0cb1085"
@@ -4,3 +4,6 @@ import "embed"
 
 //go:embed mysqlutil-8.0.28-linux-glibc2.17-x86_64.tar.gz
 var resources embed.FS
+
+// resourceChecksum is the checksum of the extracted embedded resource, see utils.DirectoryChecksum.
+const resourceChecksum = "b4501d29ab63baa9145962fea27fdbe6022fa3810d52c2b42a2eb605a31ae41f"
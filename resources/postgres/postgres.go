@@ -91,8 +91,22 @@ func (i *Instance) Stop(stdout, stderr io.Writer) error {
 	return p.Run()
 }
 
+// InitDBOption is the option to initialize the postgres data directory.
+// It only takes effect when the data directory is initialized for the first time.
+type InitDBOption struct {
+	// Locale is the locale of the database cluster, defaults to en_US.UTF-8.
+	Locale string
+	// DataChecksums enables the checksums on the data pages to detect the corruption by the I/O system.
+	DataChecksums bool
+}
+
 // Install returns the postgres binary depending on the OS.
 func Install(resourceDir, pgDataDir, pgUser string) (*Instance, error) {
+	return InstallWithOption(resourceDir, pgDataDir, pgUser, InitDBOption{})
+}
+
+// InstallWithOption returns the postgres binary depending on the OS, and initializes the data directory with the option.
+func InstallWithOption(resourceDir, pgDataDir, pgUser string, option InitDBOption) (*Instance, error) {
 	var tarName string
 	switch runtime.GOOS {
 	case "darwin":
@@ -105,26 +119,16 @@ func Install(resourceDir, pgDataDir, pgUser string) (*Instance, error) {
 	version := strings.TrimRight(tarName, ".txz")
 	pgBinDir := path.Join(resourceDir, version)
 
-	// TODO(d): remove this when pg_dump is populated to all users.
-	_, err := os.Stat(path.Join(pgBinDir, "bin", "pg_dump"))
-	pgDumpNotExist := false
+	// Skip the extraction if a valid installation exists, e.g. the resource directory is read-only in the container.
+	installed, err := utils.IsInstalled(pgBinDir, resourceChecksum)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to check pg_dump %q, error: %w", pgBinDir, err)
-		}
-		pgDumpNotExist = true
+		return nil, err
 	}
-	if pgDumpNotExist {
+	if !installed {
+		// Remove the missing or mutated installation, e.g. the one of an older version without pg_dump.
 		if err := os.RemoveAll(pgBinDir); err != nil {
 			return nil, fmt.Errorf("failed to remove binary directory path %q, error: %w", pgBinDir, err)
 		}
-	}
-
-	if _, err := os.Stat(pgBinDir); err != nil {
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to check binary directory path %q, error: %w", pgBinDir, err)
-		}
-		// Install if not exist yet.
 		// The ordering below made Postgres installation atomic.
 		tmpDir := path.Join(resourceDir, fmt.Sprintf("tmp-%s", version))
 		if err := os.RemoveAll(tmpDir); err != nil {
@@ -140,6 +144,9 @@ func Install(resourceDir, pgDataDir, pgUser string) (*Instance, error) {
 		if err := utils.ExtractTarXz(f, tmpDir); err != nil {
 			return nil, fmt.Errorf("failed to extract txz file, error: %w", err)
 		}
+		if err := utils.VerifyChecksum(tmpDir, resourceChecksum); err != nil {
+			return nil, fmt.Errorf("failed to verify the extracted postgres binaries, error: %w", err)
+		}
 
 		if err := os.Rename(tmpDir, pgBinDir); err != nil {
 			return nil, fmt.Errorf("failed to rename postgres binary directory from %q to %q, error: %w", tmpDir, pgBinDir, err)
@@ -148,7 +155,7 @@ func Install(resourceDir, pgDataDir, pgUser string) (*Instance, error) {
 
 	// We will initialize Postgres only when pgDataDir is set.
 	if pgDataDir != "" {
-		if err := initDB(pgBinDir, pgDataDir, pgUser); err != nil {
+		if err := initDB(pgBinDir, pgDataDir, pgUser, option); err != nil {
 			return nil, err
 		}
 	}
@@ -160,7 +167,7 @@ func Install(resourceDir, pgDataDir, pgUser string) (*Instance, error) {
 }
 
 // initDB inits a postgres database if not yet.
-func initDB(pgBinDir, pgDataDir, pgUser string) error {
+func initDB(pgBinDir, pgDataDir, pgUser string, option InitDBOption) error {
 	versionPath := filepath.Join(pgDataDir, "PG_VERSION")
 	_, err := os.Stat(versionPath)
 	if err != nil && !os.IsNotExist(err) {
@@ -184,15 +191,23 @@ func initDB(pgBinDir, pgDataDir, pgUser string) error {
 		return fmt.Errorf("failed to make postgres data directory %q, error: %w", pgDataDir, err)
 	}
 
+	locale := option.Locale
+	if locale == "" {
+		locale = "en_US.UTF-8"
+	}
 	args := []string{
 		"-U", pgUser,
 		"-D", pgDataDir,
+		fmt.Sprintf("--locale=%s", locale),
+	}
+	if option.DataChecksums {
+		args = append(args, "--data-checksums")
 	}
 	initDBBinary := filepath.Join(pgBinDir, "bin", "initdb")
 	p := exec.Command(initDBBinary, args...)
 	p.Env = append(os.Environ(),
-		"LC_ALL=en_US.UTF-8",
-		"LC_CTYPE=en_US.UTF-8",
+		fmt.Sprintf("LC_ALL=%s", locale),
+		fmt.Sprintf("LC_CTYPE=%s", locale),
 	)
 	p.Stderr = os.Stderr
 	p.Stdout = os.Stdout
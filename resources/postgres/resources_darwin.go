@@ -4,3 +4,6 @@ import "embed"
 
 //go:embed postgres-darwin-x86_64.txz
 var resources embed.FS
+
+// resourceChecksum is the checksum of the extracted embedded resource, see utils.DirectoryChecksum.
+const resourceChecksum = "6f7b4bf248cea7e6cab7dc9d3ccfcb659a119823ea35fb45aed8a49f102b6901"
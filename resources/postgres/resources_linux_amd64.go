@@ -4,3 +4,6 @@ import "embed"
 
 //go:embed postgres-linux-x86_64.txz
 var resources embed.FS
+
+// resourceChecksum is the checksum of the extracted embedded resource, see utils.DirectoryChecksum.
+const resourceChecksum = "a0fd886b9b01f5174d59e40542ed10da869aae8fd1a3934c571c053639a2babd"
@@ -4,3 +4,6 @@ import "embed"
 
 //go:embed postgres-linux-arm_64.txz
 var resources embed.FS
+
+// resourceChecksum is the checksum of the extracted embedded resource, see utils.DirectoryChecksum.
+const resourceChecksum = "68549f0ed4eae7822b8fff1d5513895dbe5409db25eba6aece23fd86ab9e9b65"
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DirectoryChecksum returns the checksum of the files under the directory.
+// It's the SHA-256 of the sorted lines, each line is the relative path followed by the SHA-256 of a regular file, or by "->" and the target of a symbolic link.
+// The directories are not included, so the checksum of an extracted tarball can be computed from the tarball in advance.
+func DirectoryChecksum(dir string) (string, error) {
+	var lines []string
+	if err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, fmt.Sprintf("%s -> %s", rel, target))
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("unexpected file type of %q", p)
+		}
+		sum, err := fileChecksum(p)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", rel, sum))
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	sort.Strings(lines)
+	h := sha256.New()
+	for _, line := range lines {
+		if _, err := io.WriteString(h, line+"\n"); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// IsInstalled returns true if the directory exists and its checksum matches, so the extraction can be skipped.
+func IsInstalled(dir, checksum string) (bool, error) {
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check binary directory path %q, error: %w", dir, err)
+	}
+	got, err := DirectoryChecksum(dir)
+	if err != nil {
+		return false, fmt.Errorf("failed to compute the checksum of binary directory path %q, error: %w", dir, err)
+	}
+	return got == checksum, nil
+}
+
+// VerifyChecksum returns an error if the checksum of the directory doesn't match.
+func VerifyChecksum(dir, checksum string) error {
+	got, err := DirectoryChecksum(dir)
+	if err != nil {
+		return fmt.Errorf("failed to compute the checksum of directory %q, error: %w", dir, err)
+	}
+	if got != checksum {
+		return fmt.Errorf("checksum mismatch of directory %q, expected %s but got %s", dir, checksum, got)
+	}
+	return nil
+}
+
+func fileChecksum(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
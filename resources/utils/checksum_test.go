@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryChecksum(t *testing.T) {
+	a := require.New(t)
+	dir := t.TempDir()
+	a.NoError(os.MkdirAll(filepath.Join(dir, "bin"), 0755))
+	a.NoError(os.WriteFile(filepath.Join(dir, "bin", "a"), []byte("a"), 0755))
+	a.NoError(os.Symlink("a", filepath.Join(dir, "bin", "b")))
+
+	// The SHA-256 of "bin/a ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb\nbin/b -> a\n".
+	checksum, err := DirectoryChecksum(dir)
+	a.NoError(err)
+	a.Equal("e3d2e44c6eb039fe29c80fd54b38655465942110b55d5e9822148614f8ef4021", checksum)
+	installed, err := IsInstalled(dir, checksum)
+	a.NoError(err)
+	a.True(installed)
+	a.NoError(VerifyChecksum(dir, checksum))
+
+	a.NoError(os.WriteFile(filepath.Join(dir, "bin", "a"), []byte("b"), 0755))
+	installed, err = IsInstalled(dir, checksum)
+	a.NoError(err)
+	a.False(installed)
+	a.Error(VerifyChecksum(dir, checksum))
+
+	installed, err = IsInstalled(filepath.Join(dir, "missing"), checksum)
+	a.NoError(err)
+	a.False(installed)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerAccessChangeRoutes(g *echo.Group) {
+	g.GET("/instance/:instanceID/access-change-history", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		instanceID, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		find := &api.AccessChangeHistoryFind{
+			InstanceID: &instanceID,
+		}
+		if databaseIDStr := c.QueryParams().Get("databaseId"); databaseIDStr != "" {
+			databaseID, err := strconv.Atoi(databaseIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter databaseId is not a number: %s", databaseIDStr)).SetInternal(err)
+			}
+			find.DatabaseID = &databaseID
+		}
+		historyList, err := s.store.FindAccessChangeHistory(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch access change history list for instance ID: %d", instanceID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, historyList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal access change history list response for instance ID: %d", instanceID)).SetInternal(err)
+		}
+		return nil
+	})
+}
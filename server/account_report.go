@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerAccountReportRoutes(g *echo.Group) {
+	g.POST("/account-report", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		report, err := s.generateAccountReport(ctx, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate account report").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, report); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal account report response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/account-report", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		reportList, err := s.store.FindAccountReport(ctx, &api.AccountReportFind{})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch account report list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, reportList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal account report list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/account-report/:reportID", func(c echo.Context) error {
+		report, err := s.getAccountReportFromParam(c)
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, report); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal account report response: %v", report.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// The export is for the access recertification out of Bytebase, e.g. in a spreadsheet.
+	g.GET("/account-report/:reportID/export", func(c echo.Context) error {
+		report, err := s.getAccountReportFromParam(c)
+		if err != nil {
+			return err
+		}
+		payload := &api.AccountReportPayload{}
+		if err := json.Unmarshal([]byte(report.Payload), payload); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal account report: %v", report.ID)).SetInternal(err)
+		}
+		content, err := getAccountReportCSV(payload)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to export account report: %v", report.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("account-report-%d.csv", report.ID)))
+		return c.Blob(http.StatusOK, "text/csv; charset=utf-8", content)
+	})
+}
+
+func (s *Server) getAccountReportFromParam(c echo.Context) (*api.AccountReport, error) {
+	id, err := strconv.Atoi(c.Param("reportID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Report ID is not a number: %s", c.Param("reportID"))).SetInternal(err)
+	}
+	report, err := s.store.GetAccountReportByID(c.Request().Context(), id)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch account report ID: %v", id)).SetInternal(err)
+	}
+	if report == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Account report not found with ID %d", id))
+	}
+	return report, nil
+}
+
+// getAccountReportCSV returns the accounts of the report in CSV, the time is in RFC 3339 UTC.
+func getAccountReportCSV(payload *api.AccountReportPayload) ([]byte, error) {
+	formatTs := func(ts int64, zero string) string {
+		if ts == 0 {
+			return zero
+		}
+		return time.Unix(ts, 0).UTC().Format(time.RFC3339)
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"Instance", "Environment", "Engine", "Account", "Superuser", "Password Expire Time", "First Seen Time", "Last Activity Time", "Flags"}); err != nil {
+		return nil, err
+	}
+	for _, account := range payload.AccountList {
+		var flagList []string
+		for _, flag := range account.FlagList {
+			flagList = append(flagList, string(flag))
+		}
+		if err := w.Write([]string{
+			account.InstanceName,
+			account.EnvironmentName,
+			string(account.Engine),
+			account.Name,
+			strconv.FormatBool(account.Superuser),
+			formatTs(account.PasswordExpireTs, "never"),
+			formatTs(account.FirstSeenTs, ""),
+			formatTs(account.LastActivityTs, "never"),
+			strings.Join(flagList, " "),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,314 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// The reports are at most daily, so the hourly check generates them in time.
+	accountReportRunnerInterval = time.Duration(1) * time.Hour
+)
+
+// NewAccountReportRunner creates an account report runner.
+func NewAccountReportRunner(server *Server) *AccountReportRunner {
+	return &AccountReportRunner{
+		server: server,
+	}
+}
+
+// AccountReportRunner generates the database account reports on the schedule of the workspace account report setting.
+type AccountReportRunner struct {
+	server *Server
+}
+
+// Run will run the account report runner.
+func (r *AccountReportRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(accountReportRunnerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Account report runner started and will run every %v", accountReportRunnerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						log.Error("Account report runner PANIC RECOVER", zap.Error(err))
+					}
+				}()
+				r.run(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (r *AccountReportRunner) run(ctx context.Context, now time.Time) {
+	setting, err := r.server.getAccountReportSetting(ctx)
+	if err != nil {
+		log.Error("Failed to get account report setting", zap.Error(err))
+		return
+	}
+	interval := setting.Schedule.Interval()
+	if interval == 0 {
+		return
+	}
+	latest, err := r.server.store.GetLatestAccountReport(ctx)
+	if err != nil {
+		log.Error("Failed to get the latest account report", zap.Error(err))
+		return
+	}
+	if latest != nil && now.Sub(time.Unix(latest.CreatedTs, 0)) < interval {
+		return
+	}
+	report, err := r.server.generateAccountReport(ctx, api.SystemBotID)
+	if err != nil {
+		log.Error("Failed to generate account report", zap.Error(err))
+		return
+	}
+	log.Debug("Generated account report", zap.Int("id", report.ID), zap.Int("account_count", report.AccountCount), zap.Int("flagged_count", report.FlaggedCount))
+}
+
+// getAccountReportSetting returns the workspace account report setting, or the default one if it's not set.
+func (s *Server) getAccountReportSetting(ctx context.Context) (*api.AccountReportSetting, error) {
+	name := api.SettingWorkspaceAccountReport
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+	setting := &api.AccountReportSetting{
+		Schedule:   api.AccountReportScheduleUnset,
+		UnusedDays: api.DefaultAccountUnusedDays,
+	}
+	if len(settingList) == 0 {
+		return setting, nil
+	}
+	if err := json.Unmarshal([]byte(settingList[0].Value), setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal account report setting %q, error: %w", settingList[0].Value, err)
+	}
+	return setting, nil
+}
+
+// generateAccountReport lists the login accounts of all the instances and stores the report.
+// The activity of the accounts is carried over from the latest report, so the unused accounts are found across the reports.
+func (s *Server) generateAccountReport(ctx context.Context, creatorID int) (*api.AccountReport, error) {
+	setting, err := s.getAccountReportSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	previousAccountMap := make(map[string]*api.Account)
+	latest, err := s.store.GetLatestAccountReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		previous := &api.AccountReportPayload{}
+		if err := json.Unmarshal([]byte(latest.Payload), previous); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal account report %d, error: %w", latest.ID, err)
+		}
+		for _, account := range previous.AccountList {
+			previousAccountMap[getAccountKey(account)] = account
+		}
+	}
+
+	rowStatus := api.Normal
+	instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{RowStatus: &rowStatus})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find instance list, error: %w", err)
+	}
+
+	now := time.Now().Unix()
+	payload := &api.AccountReportPayload{
+		AccountList:       []*api.Account{},
+		InstanceErrorList: []string{},
+	}
+	flaggedCount := 0
+	for _, instance := range instanceList {
+		if !isAccountReportSupported(instance.Engine) {
+			payload.InstanceErrorList = append(payload.InstanceErrorList, fmt.Sprintf("%s: account report is not supported for %s", instance.Name, instance.Engine))
+			continue
+		}
+		accountList, connectedSet, err := s.getInstanceAccountList(ctx, instance)
+		if err != nil {
+			payload.InstanceErrorList = append(payload.InstanceErrorList, fmt.Sprintf("%s: %s", instance.Name, err.Error()))
+			continue
+		}
+		for _, account := range accountList {
+			account.InstanceID = instance.ID
+			account.InstanceName = instance.Name
+			account.EnvironmentName = instance.Environment.Name
+			account.Engine = instance.Engine
+			api.UpdateAccountActivity(account, previousAccountMap[getAccountKey(account)], connectedSet[account.Name], now)
+			api.SetAccountFlagList(account, setting.UnusedDays, now)
+			if len(account.FlagList) > 0 {
+				flaggedCount++
+			}
+			payload.AccountList = append(payload.AccountList, account)
+		}
+	}
+
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal account report payload, error: %w", err)
+	}
+	return s.store.CreateAccountReport(ctx, &api.AccountReportCreate{
+		CreatorID:    creatorID,
+		AccountCount: len(payload.AccountList),
+		FlaggedCount: flaggedCount,
+		Payload:      string(bytes),
+	})
+}
+
+// isAccountReportSupported returns true if the engine supports the account report.
+func isAccountReportSupported(engine db.Type) bool {
+	return engine == db.Postgres || engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+}
+
+func getAccountKey(account *api.Account) string {
+	return fmt.Sprintf("%d/%s", account.InstanceID, account.Name)
+}
+
+// getInstanceAccountList returns the login accounts of the instance, and the names of the accounts connected now.
+func (s *Server) getInstanceAccountList(ctx context.Context, instance *api.Instance) ([]*api.Account, map[string]bool, error) {
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if instance.Engine == db.Postgres {
+		return getPostgresAccountList(ctx, sqldb)
+	}
+	return getMySQLAccountList(ctx, sqldb, instance.Engine)
+}
+
+func getPostgresAccountList(ctx context.Context, sqldb *sql.DB) ([]*api.Account, map[string]bool, error) {
+	rows, err := sqldb.QueryContext(ctx, `
+		SELECT
+			rolname,
+			rolsuper,
+			CASE WHEN rolvaliduntil IS NULL OR rolvaliduntil = 'infinity' THEN 0 ELSE EXTRACT(EPOCH FROM rolvaliduntil)::BIGINT END
+		FROM pg_catalog.pg_roles
+		WHERE rolcanlogin
+		ORDER BY rolname`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var accountList []*api.Account
+	for rows.Next() {
+		account := &api.Account{}
+		if err := rows.Scan(&account.Name, &account.Superuser, &account.PasswordExpireTs); err != nil {
+			return nil, nil, err
+		}
+		accountList = append(accountList, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// The session of the report itself isn't the activity of the admin account.
+	connectedSet, err := getConnectedUserSet(ctx, sqldb, "SELECT DISTINCT usename FROM pg_catalog.pg_stat_activity WHERE usename IS NOT NULL AND pid <> pg_backend_pid()")
+	if err != nil {
+		return nil, nil, err
+	}
+	return accountList, connectedSet, nil
+}
+
+func getMySQLAccountList(ctx context.Context, sqldb *sql.DB, engine db.Type) ([]*api.Account, map[string]bool, error) {
+	// TiDB doesn't support the password expiration, and MariaDB doesn't keep the password change time in mysql.user,
+	// so their passwords are reported as never expiring.
+	query := `
+		SELECT user, host, Super_priv = 'Y', 0
+		FROM mysql.user
+		WHERE user NOT LIKE 'mysql.%'
+		ORDER BY user, host`
+	if engine == db.MySQL {
+		// The NULL password_lifetime means the global default_password_lifetime, and 0 means never expires.
+		query = `
+			SELECT
+				user,
+				host,
+				Super_priv = 'Y',
+				CASE
+					WHEN password_expired = 'Y' THEN 1
+					WHEN IFNULL(password_lifetime, @@default_password_lifetime) = 0 THEN 0
+					ELSE UNIX_TIMESTAMP(password_last_changed) + IFNULL(password_lifetime, @@default_password_lifetime) * 86400
+				END
+			FROM mysql.user
+			WHERE user NOT LIKE 'mysql.%'
+			ORDER BY user, host`
+	}
+	rows, err := sqldb.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var accountList []*api.Account
+	// The sessions only tell the user name, so all the hosts of the user are considered connected.
+	userAccountMap := make(map[string][]*api.Account)
+	for rows.Next() {
+		var user, host string
+		account := &api.Account{}
+		if err := rows.Scan(&user, &host, &account.Superuser, &account.PasswordExpireTs); err != nil {
+			return nil, nil, err
+		}
+		// Use the same name as the synced instance user.
+		account.Name = fmt.Sprintf("'%s'@'%s'", user, host)
+		accountList = append(accountList, account)
+		userAccountMap[user] = append(userAccountMap[user], account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	connectedUserSet, err := getConnectedUserSet(ctx, sqldb, "SELECT DISTINCT USER FROM information_schema.PROCESSLIST WHERE ID <> CONNECTION_ID()")
+	if err != nil {
+		return nil, nil, err
+	}
+	connectedSet := make(map[string]bool)
+	for user := range connectedUserSet {
+		for _, account := range userAccountMap[user] {
+			connectedSet[account.Name] = true
+		}
+	}
+	return accountList, connectedSet, nil
+}
+
+func getConnectedUserSet(ctx context.Context, sqldb *sql.DB, query string) (map[string]bool, error) {
+	rows, err := sqldb.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	connectedSet := make(map[string]bool)
+	for rows.Next() {
+		var user string
+		if err := rows.Scan(&user); err != nil {
+			return nil, err
+		}
+		connectedSet[strings.TrimSpace(user)] = true
+	}
+	return connectedSet, rows.Err()
+}
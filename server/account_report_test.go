@@ -0,0 +1,41 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetAccountReportCSV(t *testing.T) {
+	content, err := getAccountReportCSV(&api.AccountReportPayload{
+		AccountList: []*api.Account{
+			{
+				InstanceName:    "prod-mysql",
+				EnvironmentName: "Prod",
+				Engine:          db.MySQL,
+				Name:            "'app'@'%'",
+				FirstSeenTs:     1652745600,
+				LastActivityTs:  1652832000,
+				FlagList:        []api.AccountFlag{api.AccountFlagNoPasswordExpiry},
+			},
+			{
+				InstanceName:     "prod-pg",
+				EnvironmentName:  "Prod",
+				Engine:           db.Postgres,
+				Name:             "postgres",
+				Superuser:        true,
+				PasswordExpireTs: 1655424000,
+				FirstSeenTs:      1652745600,
+				FlagList:         []api.AccountFlag{api.AccountFlagSuperuser, api.AccountFlagUnused},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `Instance,Environment,Engine,Account,Superuser,Password Expire Time,First Seen Time,Last Activity Time,Flags
+prod-mysql,Prod,MYSQL,'app'@'%',false,never,2022-05-17T00:00:00Z,2022-05-18T00:00:00Z,NO_PASSWORD_EXPIRY
+prod-pg,Prod,POSTGRES,postgres,true,2022-06-17T00:00:00Z,2022-05-17T00:00:00Z,never,SUPERUSER UNUSED
+`, string(content))
+}
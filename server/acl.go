@@ -183,6 +183,23 @@ func isUpdatingSelf(ctx context.Context, c echo.Context, s *Server, curPrincipal
 
 			return inbox.ReceiverID == curPrincipalID, nil
 		}
+	} else if strings.HasPrefix(c.Path(), "/api/issue/:issueID/attachment") {
+		if attachmentIDStr := c.Param("attachmentID"); attachmentIDStr != "" {
+			attachmentID, err := strconv.Atoi(attachmentIDStr)
+			if err != nil {
+				return false, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Attachment ID is not a number: %s", attachmentIDStr)).SetInternal(err)
+			}
+
+			attachment, err := s.store.GetIssueAttachmentByID(ctx, attachmentID)
+			if err != nil {
+				return false, echo.NewHTTPError(http.StatusInternalServerError, defaultErrMsg).SetInternal(err)
+			}
+			if attachment == nil {
+				return false, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Attachment ID not found: %d", attachmentID))
+			}
+
+			return attachment.CreatorID == curPrincipalID, nil
+		}
 	} else if strings.HasPrefix(c.Path(), "/api/sheet") {
 		if idStr := c.Param("id"); idStr != "" {
 			id, err := strconv.Atoi(idStr)
@@ -25,6 +25,8 @@ type ActivityManager struct {
 // ActivityMeta is the activity metadata.
 type ActivityMeta struct {
 	issue *api.Issue
+	// schemaChange is only set for the schema change activity, and sent to the webhooks along with the activity.
+	schemaChange *api.SchemaChangeEvent
 }
 
 // NewActivityManager creates an activity manager.
@@ -55,9 +57,13 @@ func (m *ActivityManager) CreateActivity(ctx context.Context, create *api.Activi
 		}
 	}
 
+	activityTypeList, err := api.GetProjectWebhookActivityTypeList(create.Type, create.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook activity types for activity %v, error: %w", create.Type, err)
+	}
 	hookFind := &api.ProjectWebhookFind{
-		ProjectID:    &meta.issue.ProjectID,
-		ActivityType: &create.Type,
+		ProjectID:        &meta.issue.ProjectID,
+		ActivityTypeList: activityTypeList,
 	}
 	webhookList, err := m.s.store.FindProjectWebhook(ctx, hookFind)
 	if err != nil {
@@ -246,6 +252,38 @@ func (m *ActivityManager) getWebhookContext(ctx context.Context, activity *api.A
 			level = webhook.WebhookError
 			title = "Task failed - " + task.Name
 		}
+	case api.ActivityIssueSLARemind:
+		level = webhook.WebhookWarn
+		title = "SLA reminder - " + meta.issue.Name
+	case api.ActivityPipelineChangeSetHalt:
+		level = webhook.WebhookError
+		title = "Change set halted - " + meta.issue.Name
+	case api.ActivityDatabaseSchemaChange:
+		level = webhook.WebhookSuccess
+		title = "Schema changed - " + meta.issue.Name
+		if meta.schemaChange != nil {
+			title = "Schema changed - " + meta.schemaChange.DatabaseName
+		}
+	}
+
+	var schemaChange []byte
+	if meta.schemaChange != nil {
+		b, err := json.Marshal(meta.schemaChange)
+		if err != nil {
+			log.Warn("Failed to post webhook event after changing the schema, failed to marshal schema change event",
+				zap.String("issue_name", meta.issue.Name),
+				zap.Error(err))
+			return webhookCtx, err
+		}
+		schemaChange = b
+	}
+
+	fieldList, err := m.getWebhookIssueFieldList(ctx, meta.issue)
+	if err != nil {
+		log.Warn("Failed to post webhook event, failed to find issue custom fields",
+			zap.String("issue_name", meta.issue.Name),
+			zap.Error(err))
+		return webhookCtx, err
 	}
 
 	webhookCtx = webhook.Context{
@@ -258,6 +296,7 @@ func (m *ActivityManager) getWebhookContext(ctx context.Context, activity *api.A
 			Status:      string(meta.issue.Status),
 			Type:        string(meta.issue.Type),
 			Description: meta.issue.Description,
+			Fields:      fieldList,
 		},
 		Project: &webhook.Project{
 			ID:   meta.issue.ProjectID,
@@ -268,10 +307,58 @@ func (m *ActivityManager) getWebhookContext(ctx context.Context, activity *api.A
 		CreatorID:    updater.ID,
 		CreatorName:  updater.Name,
 		CreatorEmail: updater.Email,
+		SchemaChange: schemaChange,
 	}
 	return webhookCtx, nil
 }
 
+// getWebhookIssueFieldList returns the custom fields set on the issue, the USER field value is resolved to the principal email.
+func (m *ActivityManager) getWebhookIssueFieldList(ctx context.Context, issue *api.Issue) ([]webhook.IssueField, error) {
+	valueList, err := m.store.FindIssueFieldValue(ctx, &api.IssueFieldValueFind{IssueID: &issue.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(valueList) == 0 {
+		return nil, nil
+	}
+	fieldList, err := m.store.FindIssueField(ctx, &api.IssueFieldFind{ProjectID: &issue.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	fieldMap := make(map[int]*api.IssueField)
+	for _, field := range fieldList {
+		fieldMap[field.ID] = field
+	}
+
+	var webhookFieldList []webhook.IssueField
+	for _, value := range valueList {
+		field, ok := fieldMap[value.FieldID]
+		if !ok {
+			continue
+		}
+		webhookField := webhook.IssueField{
+			Name:  field.Name,
+			Type:  string(field.Type),
+			Value: value.Value,
+		}
+		if field.Type == api.IssueFieldUser {
+			principalID, err := strconv.Atoi(value.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid principal ID %q of issue field %q, error: %w", value.Value, field.Name, err)
+			}
+			principal, err := m.store.GetPrincipalByID(ctx, principalID)
+			if err != nil {
+				return nil, err
+			}
+			if principal != nil {
+				webhookField.Value = principal.Email
+			}
+		}
+		webhookFieldList = append(webhookFieldList, webhookField)
+	}
+	return webhookFieldList, nil
+}
+
 func shouldPostInbox(activity *api.Activity, createType api.ActivityType) (bool, error) {
 	switch createType {
 	case api.ActivityIssueCreate:
@@ -286,6 +373,8 @@ func shouldPostInbox(activity *api.Activity, createType api.ActivityType) (bool,
 		return true, nil
 	case api.ActivityPipelineTaskEarliestAllowedTimeUpdate:
 		return true, nil
+	case api.ActivityPipelineChangeSetHalt:
+		return true, nil
 	case api.ActivityPipelineTaskStatusUpdate:
 		update := new(api.ActivityPipelineTaskStatusUpdatePayload)
 		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
@@ -21,6 +21,7 @@ func (s *Server) registerActuatorRoutes(g *echo.Group) {
 			GitCommit: s.profile.GitCommit,
 			Readonly:  s.profile.Readonly,
 			Demo:      s.profile.Demo,
+			Offline:   s.profile.Offline,
 			Host:      s.profile.BackendHost,
 			Port:      strconv.Itoa(s.profile.BackendPort),
 		}
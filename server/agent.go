@@ -0,0 +1,453 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	agentContextKey = "agent"
+	// agentTokenLength is the length of the generated agent token.
+	agentTokenLength = 32
+	// agentTaskPollInterval is the interval to check whether the agent has reported the result.
+	agentTaskPollInterval = time.Second
+	// agentQueryTimeout is the maximum duration to wait for the agent to return the query result.
+	agentQueryTimeout = 60 * time.Second
+	// agentOfflineThreshold is the duration without heartbeat after which the agent is considered offline.
+	agentOfflineThreshold = 3 * time.Minute
+)
+
+func (s *Server) registerAgentRoutes(g *echo.Group) {
+	g.POST("/agent", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		agentCreate := &api.AgentCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, agentCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create agent request").SetInternal(err)
+		}
+		if agentCreate.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing agent name")
+		}
+		agentCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		token, err := common.RandomString(agentTokenLength)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate agent token").SetInternal(err)
+		}
+		agentCreate.Token = token
+
+		agent, err := s.store.CreateAgent(ctx, agentCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Agent name already exists: %s", agentCreate.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create agent").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, agent); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create agent response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/agent", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		agentFind := &api.AgentFind{}
+		if rowStatusStr := c.QueryParam("rowstatus"); rowStatusStr != "" {
+			rowStatus := api.RowStatus(rowStatusStr)
+			agentFind.RowStatus = &rowStatus
+		}
+		agentList, err := s.store.FindAgent(ctx, agentFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch agent list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, agentList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal agent list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/agent/:agentID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("agentID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("agentID"))).SetInternal(err)
+		}
+
+		agentPatch := &api.AgentPatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, agentPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch agent request").SetInternal(err)
+		}
+		// Only the agent itself can report its version and heartbeat.
+		agentPatch.Version = nil
+		agentPatch.LastHeartbeatTs = nil
+
+		agent, err := s.store.PatchAgent(ctx, agentPatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Agent ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch agent ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, agent); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal agent ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// registerAgentProtocolRoutes registers the routes called by the runner agents.
+// The agents always initiate the connection, so the server never needs to reach into the private network.
+func (s *Server) registerAgentProtocolRoutes(g *echo.Group) {
+	g.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return agentTokenMiddleware(s, next)
+	})
+
+	g.POST("/heartbeat", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		agent := c.Get(agentContextKey).(*api.Agent)
+		heartbeat := &api.AgentHeartbeat{}
+		if err := json.NewDecoder(c.Request().Body).Decode(heartbeat); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed agent heartbeat request").SetInternal(err)
+		}
+		ts := time.Now().Unix()
+		if _, err := s.store.PatchAgent(ctx, &api.AgentPatch{
+			ID:              agent.ID,
+			UpdaterID:       api.SystemBotID,
+			Version:         &heartbeat.Version,
+			LastHeartbeatTs: &ts,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to record heartbeat for agent %q", agent.Name)).SetInternal(err)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	g.POST("/task/claim", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		agent := c.Get(agentContextKey).(*api.Agent)
+		agentTask, err := s.store.ClaimAgentTask(ctx, agent.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to claim task for agent %q", agent.Name)).SetInternal(err)
+		}
+		if agentTask == nil {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		dispatch, err := s.composeAgentTaskDispatch(ctx, agentTask)
+		if err != nil {
+			// The agent can't do anything about it, so fail the task rather than the request.
+			s.failAgentTask(ctx, agentTask.ID, err)
+			return c.NoContent(http.StatusNoContent)
+		}
+		return c.JSON(http.StatusOK, dispatch)
+	})
+
+	g.POST("/task/:taskID/result", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		agent := c.Get(agentContextKey).(*api.Agent)
+		id, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		result := &api.AgentTaskResult{}
+		if err := json.NewDecoder(c.Request().Body).Decode(result); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed agent task result").SetInternal(err)
+		}
+
+		agentTaskList, err := s.store.FindAgentTask(ctx, &api.AgentTaskFind{ID: &id, AgentID: &agent.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find agent task ID: %v", id)).SetInternal(err)
+		}
+		if len(agentTaskList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Agent task ID not found: %d", id))
+		}
+		agentTask := agentTaskList[0]
+		if agentTask.Status != api.AgentTaskRunning {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Agent task %d is %s instead of %s", id, agentTask.Status, api.AgentTaskRunning))
+		}
+
+		if result.Error == "" && result.Sync != nil {
+			instance, err := s.store.GetInstanceByID(ctx, agentTask.InstanceID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", agentTask.InstanceID)).SetInternal(err)
+			}
+			if instance != nil {
+				if err := s.applyAgentSyncResult(ctx, instance, result.Sync); err != nil {
+					result.Error = err.Error()
+				}
+			}
+		}
+
+		bytes, err := json.Marshal(result)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal agent task result").SetInternal(err)
+		}
+		resultStr := string(bytes)
+		status := api.AgentTaskDone
+		if result.Error != "" {
+			status = api.AgentTaskFailed
+		}
+		if _, err := s.store.PatchAgentTaskStatus(ctx, &api.AgentTaskStatusPatch{
+			ID:        id,
+			UpdaterID: api.SystemBotID,
+			Status:    status,
+			Result:    &resultStr,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update agent task ID: %v", id)).SetInternal(err)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+}
+
+// agentTokenMiddleware authenticates the agent by the token, the archived agents are revoked.
+func agentTokenMiddleware(s *Server, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		token := c.Request().Header.Get(api.AgentTokenHeader)
+		if token == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Missing agent token")
+		}
+		rowStatus := api.Normal
+		agent, err := s.store.GetAgent(ctx, &api.AgentFind{
+			RowStatus: &rowStatus,
+			Token:     &token,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to authenticate agent").SetInternal(err)
+		}
+		// The agent is found by the hash of the token, which is compared again in constant time.
+		if agent == nil || !common.MatchTokenHash(token, agent.TokenHash) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid agent token")
+		}
+		c.Set(agentContextKey, agent)
+		return next(c)
+	}
+}
+
+// composeAgentTaskDispatch composes the work sent to the agent.
+func (s *Server) composeAgentTaskDispatch(ctx context.Context, agentTask *api.AgentTask) (*api.AgentTaskDispatch, error) {
+	instance, err := s.store.GetInstanceByID(ctx, agentTask.InstanceID)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("instance ID not found: %d", agentTask.InstanceID)
+	}
+	dispatch := &api.AgentTaskDispatch{
+		ID:     agentTask.ID,
+		Type:   agentTask.Type,
+		Engine: instance.Engine,
+		ConnectionCtx: db.ConnectionContext{
+			EnvironmentName: instance.Environment.Name,
+			InstanceName:    instance.Name,
+		},
+	}
+	if err := json.Unmarshal([]byte(agentTask.Payload), &dispatch.Payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal agent task payload, error: %w", err)
+	}
+
+	// Only the migration needs the admin data source.
+	dataSourceType := api.RO
+	if agentTask.Type == api.AgentTaskDatabaseMigrate || agentTask.Type == api.AgentTaskInstanceSync {
+		// Sync also sets up the migration schema on the instance.
+		dataSourceType = api.Admin
+	}
+	dataSource := api.DataSourceFromInstanceWithType(instance, dataSourceType)
+	if dataSource == nil {
+		dataSource = api.DataSourceFromInstanceWithType(instance, api.Admin)
+	}
+	if dataSource == nil {
+		return nil, common.Errorf(common.Internal, "data source not found for instance %d", instance.ID)
+	}
+	dispatch.ConnectionConfig = db.ConnectionConfig{
+		Username: dataSource.Username,
+		Password: dataSource.Password,
+		Host:     instance.Host,
+		Port:     instance.Port,
+		Database: dispatch.Payload.Database,
+		TLSConfig: db.TLSConfig{
+			SslCA:   dataSource.SslCa,
+			SslCert: dataSource.SslCert,
+			SslKey:  dataSource.SslKey,
+		},
+		ReadOnly: agentTask.Type == api.AgentTaskDatabaseQuery,
+	}
+	return dispatch, nil
+}
+
+func (s *Server) failAgentTask(ctx context.Context, agentTaskID int, taskErr error) {
+	bytes, err := json.Marshal(&api.AgentTaskResult{Error: taskErr.Error()})
+	if err != nil {
+		log.Error("Failed to marshal agent task result", zap.Int("id", agentTaskID), zap.Error(err))
+		return
+	}
+	result := string(bytes)
+	if _, err := s.store.PatchAgentTaskStatus(ctx, &api.AgentTaskStatusPatch{
+		ID:        agentTaskID,
+		UpdaterID: api.SystemBotID,
+		Status:    api.AgentTaskFailed,
+		Result:    &result,
+	}); err != nil {
+		log.Error("Failed to mark agent task as failed", zap.Int("id", agentTaskID), zap.Error(err))
+	}
+}
+
+// enqueueAgentSync asks the agent of the instance to sync the instance, unless there is already one in progress.
+func (s *Server) enqueueAgentSync(ctx context.Context, instance *api.Instance) error {
+	taskType := api.AgentTaskInstanceSync
+	agentTaskList, err := s.store.FindAgentTask(ctx, &api.AgentTaskFind{
+		InstanceID: &instance.ID,
+		Type:       &taskType,
+		StatusList: &[]api.AgentTaskStatus{api.AgentTaskPending, api.AgentTaskRunning},
+	})
+	if err != nil {
+		return err
+	}
+	if len(agentTaskList) > 0 {
+		return nil
+	}
+	_, err = s.createAgentTask(ctx, instance, taskType, &api.AgentTaskPayload{})
+	return err
+}
+
+func (s *Server) createAgentTask(ctx context.Context, instance *api.Instance, taskType api.AgentTaskType, payload *api.AgentTaskPayload) (*api.AgentTask, error) {
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal agent task payload, error: %w", err)
+	}
+	return s.store.CreateAgentTask(ctx, &api.AgentTaskCreate{
+		CreatorID:  api.SystemBotID,
+		AgentID:    *instance.AgentID,
+		InstanceID: instance.ID,
+		Type:       taskType,
+		Payload:    string(bytes),
+	})
+}
+
+// runAgentTask dispatches the work to the agent of the instance and waits for the result.
+func (s *Server) runAgentTask(ctx context.Context, instance *api.Instance, taskType api.AgentTaskType, payload *api.AgentTaskPayload) (*api.AgentTaskResult, error) {
+	agentTask, err := s.createAgentTask(ctx, instance, taskType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(agentTaskPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			agentTaskList, err := s.store.FindAgentTask(ctx, &api.AgentTaskFind{ID: &agentTask.ID})
+			if err != nil {
+				return nil, err
+			}
+			if len(agentTaskList) == 0 {
+				return nil, fmt.Errorf("agent task ID not found: %d", agentTask.ID)
+			}
+			agentTask = agentTaskList[0]
+			if agentTask.Status != api.AgentTaskDone && agentTask.Status != api.AgentTaskFailed {
+				continue
+			}
+			result := &api.AgentTaskResult{}
+			if err := json.Unmarshal([]byte(agentTask.Result), result); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal agent task result, error: %w", err)
+			}
+			if agentTask.Status == api.AgentTaskFailed {
+				return nil, fmt.Errorf("agent task %d failed: %s", agentTask.ID, result.Error)
+			}
+			return result, nil
+		case <-ctx.Done():
+			if agentTask.Status == api.AgentTaskPending {
+				// Nobody is waiting for the result anymore.
+				s.failAgentTask(context.Background(), agentTask.ID, ctx.Err())
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// applyAgentSyncResult stores the instance and database schemas synced by the agent.
+func (s *Server) applyAgentSyncResult(ctx context.Context, instance *api.Instance, result *api.AgentTaskSyncResult) error {
+	if result.InstanceMeta == nil {
+		return fmt.Errorf("missing instance metadata in the sync result")
+	}
+	getSchema := func(databaseName string) (*db.Schema, error) {
+		for _, schema := range result.SchemaList {
+			if schema.Name == databaseName {
+				return schema, nil
+			}
+		}
+		return nil, fmt.Errorf("schema of database %q is not synced by the agent", databaseName)
+	}
+	if _, err := s.applyInstanceMeta(ctx, instance, result.InstanceMeta, getSchema); err != nil {
+		return err
+	}
+
+	errorList := result.ErrorList
+	for _, schema := range result.SchemaList {
+		databaseName := schema.Name
+		matchedDb, err := s.store.GetDatabase(ctx, &api.DatabaseFind{
+			InstanceID: &instance.ID,
+			Name:       &databaseName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to sync database for instance: %s. Failed to find database list. Error %w", instance.Name, err)
+		}
+		if err := s.applyDatabaseSchema(ctx, instance, matchedDb, schema, result.SchemaVersionMap[databaseName]); err != nil {
+			errorList = append(errorList, err.Error())
+		}
+	}
+	if len(errorList) > 0 {
+		return fmt.Errorf("sync database schema errors, %v", errorList)
+	}
+	return nil
+}
+
+// checkAgentAlive is the task check for the instances behind an agent.
+func (s *Server) checkAgentAlive(ctx context.Context, agentID int) ([]api.TaskCheckResult, error) {
+	agent, err := s.store.GetAgent(ctx, &api.AgentFind{ID: &agentID})
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if agent == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "agent ID not found %v", agentID)
+	}
+	if agent.RowStatus != api.Normal || time.Since(time.Unix(agent.LastHeartbeatTs, 0)) > agentOfflineThreshold {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.DbConnectionFailure.Int(),
+				Title:     fmt.Sprintf("Agent %q is offline", agent.Name),
+				Content:   fmt.Sprintf("No heartbeat from agent %q since %s", agent.Name, time.Unix(agent.LastHeartbeatTs, 0).Format(time.RFC3339)),
+			},
+		}, nil
+	}
+	return []api.TaskCheckResult{
+		{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   fmt.Sprintf("Agent %q is online", agent.Name),
+		},
+	}, nil
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestAgentTokenMiddleware(t *testing.T) {
+	s := newTestServer(t)
+	e := echo.New()
+	apiGroup := e.Group("/api")
+	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(getPrincipalIDContextKey(), api.SystemBotID)
+			return next(c)
+		}
+	})
+	s.registerAgentRoutes(apiGroup)
+	s.registerAgentProtocolRoutes(e.Group("/v1/agent"))
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set(api.AgentTokenHeader, token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Registration returns the token only once.
+	rec := do(http.MethodPost, "/api/agent", "", `{"data":{"type":"agentCreate","attributes":{"name":"runner"}}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	agent := &api.Agent{}
+	require.NoError(t, jsonapi.UnmarshalPayload(rec.Body, agent))
+	require.Len(t, agent.Token, agentTokenLength)
+
+	rec = do(http.MethodGet, "/api/agent", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), agent.Token)
+	require.NotContains(t, rec.Body.String(), "token")
+
+	// Heartbeat.
+	rec = do(http.MethodPost, "/v1/agent/heartbeat", agent.Token, `{"version":"1.2.0"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(http.MethodGet, "/api/agent", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var agentList struct {
+		Data []struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &agentList))
+	require.Len(t, agentList.Data, 1)
+	require.Equal(t, "1.2.0", agentList.Data[0].Attributes["version"])
+	require.NotZero(t, agentList.Data[0].Attributes["lastHeartbeatTs"])
+
+	for _, token := range []string{"", "invalid-token", strings.ToUpper(agent.Token)} {
+		rec = do(http.MethodPost, "/v1/agent/heartbeat", token, `{"version":"1.2.0"}`)
+		require.Equal(t, http.StatusUnauthorized, rec.Code, token)
+	}
+
+	// Revocation.
+	rec = do(http.MethodPatch, fmt.Sprintf("/api/agent/%d", agent.ID), "", fmt.Sprintf(`{"data":{"type":"agentPatch","id":"%d","attributes":{"rowStatus":"ARCHIVED"}}}`, agent.ID))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(http.MethodPost, "/v1/agent/heartbeat", agent.Token, `{"version":"1.2.0"}`)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestExecuteMigrationAgentSessionSetting(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	agent, err := s.store.CreateAgent(ctx, &api.AgentCreate{CreatorID: api.SystemBotID, Name: "runner", Token: "token"})
+	require.NoError(t, err)
+	instance, err := s.store.PatchInstance(ctx, &api.InstancePatch{ID: 6005, UpdaterID: api.SystemBotID, AgentID: &agent.ID})
+	require.NoError(t, err)
+	task := &api.Task{Instance: instance, InstanceID: instance.ID, Database: &api.Database{Name: "app"}}
+
+	// Nobody claims the agent task, so the migration times out after the task is dispatched.
+	setting := &db.SessionSetting{IsolationLevel: db.IsolationSerializable, LockTimeoutMs: 3000}
+	timeoutCtx, cancel := context.WithTimeout(db.WithSessionSetting(ctx, setting), 100*time.Millisecond)
+	defer cancel()
+	_, _, err = executeMigration(timeoutCtx, s, task, "CREATE TABLE t (id INT)", &db.MigrationInfo{Type: db.Migrate})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	agentTaskList, err := s.store.FindAgentTask(ctx, &api.AgentTaskFind{AgentID: &agent.ID})
+	require.NoError(t, err)
+	require.Len(t, agentTaskList, 1)
+	payload := &api.AgentTaskPayload{}
+	require.NoError(t, json.Unmarshal([]byte(agentTaskList[0].Payload), payload))
+	require.Equal(t, setting, payload.SessionSetting)
+}
@@ -66,7 +66,7 @@ func (s *AnomalyScanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 				backupPlanPolicyMap := make(map[int]*api.BackupPlanPolicy)
 				for _, env := range envList {
-					policy, err := s.server.store.GetBackupPlanPolicyByEnvID(ctx, env.ID)
+					policy, err := s.server.store.GetBackupPlanPolicyByEnvID(ctx, env.ID, nil)
 					if err != nil {
 						log.Error("Failed to retrieve backup policy",
 							zap.String("environment", env.Name),
@@ -223,6 +223,110 @@ func (s *AnomalyScanner) checkInstanceAnomaly(ctx context.Context, instance *api
 			}
 		}
 	}
+
+	s.checkInstanceParameterDrift(ctx, instance)
+	s.checkInstanceVersionAdvisory(ctx, instance)
+}
+
+// checkInstanceVersionAdvisory checks the engine version synced from the instance against the version knowledge table.
+func (s *AnomalyScanner) checkInstanceVersionAdvisory(ctx context.Context, instance *api.Instance) {
+	advisory := api.GetVersionAdvisory(instance.Engine, instance.EngineVersion, time.Now())
+	if advisory.Status != api.VersionAdvisoryEOL && advisory.Status != api.VersionAdvisoryVulnerable {
+		err := s.server.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			InstanceID: &instance.ID,
+			Type:       api.AnomalyInstanceVersionAdvisory,
+		})
+		if err != nil && common.ErrorCode(err) != common.NotFound {
+			log.Error("Failed to close anomaly",
+				zap.String("instance", instance.Name),
+				zap.String("type", string(api.AnomalyInstanceVersionAdvisory)),
+				zap.Error(err))
+		}
+		return
+	}
+
+	payload, err := json.Marshal(api.AnomalyInstanceVersionAdvisoryPayload{
+		Status:             advisory.Status,
+		RecommendedVersion: advisory.RecommendedVersion,
+		Detail:             advisory.Detail,
+	})
+	if err != nil {
+		log.Error("Failed to marshal anomaly payload",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(api.AnomalyInstanceVersionAdvisory)),
+			zap.Error(err))
+		return
+	}
+	if _, err := s.server.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		Type:       api.AnomalyInstanceVersionAdvisory,
+		Payload:    string(payload),
+	}); err != nil {
+		log.Error("Failed to create anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(api.AnomalyInstanceVersionAdvisory)),
+			zap.Error(err))
+	}
+}
+
+// checkInstanceParameterDrift compares the parameters recorded by the last sync with the environment baseline.
+func (s *AnomalyScanner) checkInstanceParameterDrift(ctx context.Context, instance *api.Instance) {
+	baseline, err := s.server.store.GetParameterBaselinePolicy(ctx, instance.EnvironmentID, api.GetPolicyTagList(instance, nil))
+	if err != nil {
+		log.Error("Failed to get parameter baseline policy",
+			zap.String("instance", instance.Name),
+			zap.Error(err))
+		return
+	}
+	parameterList, err := s.server.store.FindInstanceParameter(ctx, &api.InstanceParameterFind{
+		InstanceID: &instance.ID,
+		Latest:     true,
+	})
+	if err != nil {
+		log.Error("Failed to find instance parameters",
+			zap.String("instance", instance.Name),
+			zap.Error(err))
+		return
+	}
+
+	driftList := api.GetParameterDriftList(instance.Engine, baseline, parameterList)
+	if len(driftList) == 0 {
+		err := s.server.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			InstanceID: &instance.ID,
+			Type:       api.AnomalyInstanceParameterDrift,
+		})
+		if err != nil && common.ErrorCode(err) != common.NotFound {
+			log.Error("Failed to close anomaly",
+				zap.String("instance", instance.Name),
+				zap.String("type", string(api.AnomalyInstanceParameterDrift)),
+				zap.Error(err))
+		}
+		return
+	}
+
+	payload, err := json.Marshal(api.AnomalyInstanceParameterDriftPayload{
+		EnvironmentID: instance.EnvironmentID,
+		DriftList:     driftList,
+	})
+	if err != nil {
+		log.Error("Failed to marshal anomaly payload",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(api.AnomalyInstanceParameterDrift)),
+			zap.Error(err))
+		return
+	}
+	if _, err := s.server.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		Type:       api.AnomalyInstanceParameterDrift,
+		Payload:    string(payload),
+	}); err != nil {
+		log.Error("Failed to create anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(api.AnomalyInstanceParameterDrift)),
+			zap.Error(err))
+	}
 }
 
 func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api.Instance, database *api.Database) {
@@ -270,6 +374,8 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 			zap.Error(err))
 	}
 
+	s.checkDatabaseInvalidObject(ctx, instance, database, driver)
+
 	// Check schema drift
 	if s.server.feature(api.FeatureSchemaDrift) {
 		setup, err := driver.NeedsSetupMigration(ctx)
@@ -316,7 +422,7 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 			goto SchemaDriftEnd
 		}
 		if len(list) > 0 {
-			if list[0].Schema != schemaBuf.String() {
+			if !isStatementEqual(instance.Engine, list[0].Schema, schemaBuf.String()) {
 				anomalyPayload := api.AnomalyDatabaseSchemaDriftPayload{
 					Version: list[0].Version,
 					Expect:  list[0].Schema,
@@ -362,6 +468,50 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 SchemaDriftEnd:
 }
 
+// checkDatabaseInvalidObject checks the invalid indexes and constraints of the Postgres database again if they've been found after a migration,
+// so the anomaly is updated as they're fixed and archived once all of them are resolved.
+func (s *AnomalyScanner) checkDatabaseInvalidObject(ctx context.Context, instance *api.Instance, database *api.Database, driver db.Driver) {
+	if instance.Engine != db.Postgres {
+		return
+	}
+	anomalyType := api.AnomalyDatabaseInvalidObject
+	rowStatus := api.Normal
+	anomalyList, err := s.server.store.FindAnomaly(ctx, &api.AnomalyFind{
+		RowStatus:  &rowStatus,
+		DatabaseID: &database.ID,
+		Type:       &anomalyType,
+	})
+	if err != nil {
+		log.Error("Failed to find anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.String("type", string(api.AnomalyDatabaseInvalidObject)),
+			zap.Error(err))
+		return
+	}
+	if len(anomalyList) == 0 {
+		return
+	}
+
+	sqldb, err := driver.GetDBConnection(ctx, database.Name)
+	if err != nil {
+		log.Error("Failed to get database connection",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.Error(err))
+		return
+	}
+	invalidObjectList, err := getPgInvalidObjectList(ctx, sqldb)
+	if err != nil {
+		log.Error("Failed to get invalid indexes and constraints",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.Error(err))
+		return
+	}
+	s.server.syncInvalidObjectAnomaly(ctx, instance, database, invalidObjectList)
+}
+
 func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.Instance, database *api.Database, policyMap map[int]*api.BackupPlanPolicy) {
 	schedule := api.BackupPlanPolicyScheduleUnset
 	backupSetting, err := s.server.store.GetBackupSettingByDatabaseID(ctx, database.ID)
@@ -381,22 +531,35 @@ func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.I
 		}
 	}
 
+	// The environment policies are prefetched, while the tagged databases may have their own.
+	backupPlanPolicy := policyMap[instance.EnvironmentID]
+	if tagList := api.GetPolicyTagList(instance, database); len(tagList) > 0 {
+		backupPlanPolicy, err = s.server.store.GetBackupPlanPolicyByEnvID(ctx, instance.EnvironmentID, tagList)
+		if err != nil {
+			log.Error("Failed to retrieve backup policy",
+				zap.String("instance", instance.Name),
+				zap.String("database", database.Name),
+				zap.Error(err))
+			return
+		}
+	}
+
 	// Check backup policy violation
 	{
 		var backupPolicyAnomalyPayload *api.AnomalyDatabaseBackupPolicyViolationPayload
-		if policyMap[instance.EnvironmentID].Schedule != api.BackupPlanPolicyScheduleUnset {
-			if policyMap[instance.EnvironmentID].Schedule == api.BackupPlanPolicyScheduleDaily &&
+		if backupPlanPolicy.Schedule != api.BackupPlanPolicyScheduleUnset {
+			if backupPlanPolicy.Schedule == api.BackupPlanPolicyScheduleDaily &&
 				schedule != api.BackupPlanPolicyScheduleDaily {
 				backupPolicyAnomalyPayload = &api.AnomalyDatabaseBackupPolicyViolationPayload{
 					EnvironmentID:          instance.EnvironmentID,
-					ExpectedBackupSchedule: policyMap[instance.EnvironmentID].Schedule,
+					ExpectedBackupSchedule: backupPlanPolicy.Schedule,
 					ActualBackupSchedule:   schedule,
 				}
-			} else if policyMap[instance.EnvironmentID].Schedule == api.BackupPlanPolicyScheduleWeekly &&
+			} else if backupPlanPolicy.Schedule == api.BackupPlanPolicyScheduleWeekly &&
 				schedule == api.BackupPlanPolicyScheduleUnset {
 				backupPolicyAnomalyPayload = &api.AnomalyDatabaseBackupPolicyViolationPayload{
 					EnvironmentID:          instance.EnvironmentID,
-					ExpectedBackupSchedule: policyMap[instance.EnvironmentID].Schedule,
+					ExpectedBackupSchedule: backupPlanPolicy.Schedule,
 					ActualBackupSchedule:   schedule,
 				}
 			}
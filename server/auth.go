@@ -48,6 +48,9 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 	g.POST("/auth/login/:auth_provider", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		var user *api.Principal
+		// The VCS and the OAuth context of the user logged in via the VCS, for syncing the project membership with the groups.
+		var loginVCS *api.VCS
+		var loginOAuthCtx common.OauthContext
 
 		authProvider := api.PrincipalAuthProvider(c.Param("auth_provider"))
 		switch authProvider {
@@ -86,6 +89,9 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 				if vcsFound == nil {
 					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("vcs do not exist, name: %v, ID: %v", login.Name, login.Name)).SetInternal(err)
 				}
+				if err := s.checkVCSReachable(vcsFound.Type); err != nil {
+					return err
+				}
 
 				// We need to attach the RedirectURL in the get token process of OAuth, and the
 				// RedirectURL needs to be consistent with the RedirectURL in the get code
@@ -128,6 +134,13 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 					return echo.NewHTTPError(http.StatusInternalServerError, "Fail to fetch user info from VCS").SetInternal(err)
 				}
 
+				loginVCS = vcsFound
+				loginOAuthCtx = common.OauthContext{
+					ClientID:     vcsFound.ApplicationID,
+					ClientSecret: vcsFound.Secret,
+					AccessToken:  oauthToken.AccessToken,
+				}
+
 				// We only allow active user to login
 				if userInfo.State != vcs.StateActive {
 					return echo.NewHTTPError(http.StatusUnauthorized, "Fail to login via VCS, user is Archived")
@@ -183,6 +196,10 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusUnauthorized, "This user has been deactivated by the admin")
 		}
 
+		if loginVCS != nil {
+			s.syncLoginGroupProjectMember(ctx, user, loginVCS, loginOAuthCtx)
+		}
+
 		// If password is correct, generate tokens and set cookies.
 		if err := GenerateTokensAndSetCookies(c, user, s.profile.Mode, s.secret); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate access token").SetInternal(err)
@@ -309,38 +309,13 @@ func (r *BackupRunner) startAutoBackups(ctx context.Context, runningTasks map[in
 }
 
 func (s *Server) scheduleBackupTask(ctx context.Context, database *api.Database, backupName string, backupType api.BackupType, creatorID int) (*api.Backup, error) {
-	// Store the migration history version if exists.
-	driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, database.Name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get admin database driver, error: %w", err)
-	}
-	defer driver.Close(ctx)
-
-	migrationHistoryVersion, err := getLatestSchemaVersion(ctx, driver, database.Name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get migration history for database %q, error: %w", database.Name, err)
-	}
-	path := getBackupRelativeFilePath(database.ID, backupName)
-	if err := createBackupDirectory(s.profile.DataDir, database.ID); err != nil {
-		return nil, fmt.Errorf("failed to create backup directory, error: %w", err)
-	}
-	backupCreate := &api.BackupCreate{
-		CreatorID:               creatorID,
-		DatabaseID:              database.ID,
-		Name:                    backupName,
-		StorageBackend:          s.profile.BackupStorageBackend,
-		Type:                    backupType,
-		Path:                    path,
-		MigrationHistoryVersion: migrationHistoryVersion,
-	}
-
-	backupNew, err := s.store.CreateBackup(ctx, backupCreate)
+	backupNew, err := s.createBackup(ctx, database, backupName, backupType, creatorID)
 	if err != nil {
 		if common.ErrorCode(err) == common.Conflict {
 			log.Debug("Backup already exists for the database", zap.String("backup", backupName), zap.String("database", database.Name))
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to create backup %q, error: %w", backupName, err)
+		return nil, err
 	}
 
 	payload := api.TaskDatabaseBackupPayload{
@@ -385,3 +360,37 @@ func (s *Server) scheduleBackupTask(ctx context.Context, database *api.Database,
 	}
 	return backupNew, nil
 }
+
+// createBackup creates the backup record in the PENDING_CREATE status, the caller takes the backup afterwards.
+func (s *Server) createBackup(ctx context.Context, database *api.Database, backupName string, backupType api.BackupType, creatorID int) (*api.Backup, error) {
+	// Store the migration history version if exists.
+	driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, database.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin database driver, error: %w", err)
+	}
+	defer driver.Close(ctx)
+
+	migrationHistoryVersion, err := getLatestSchemaVersion(ctx, driver, database.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration history for database %q, error: %w", database.Name, err)
+	}
+	path := getBackupRelativeFilePath(database.ID, backupName)
+	if err := createBackupDirectory(s.profile.DataDir, database.ID); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory, error: %w", err)
+	}
+	backupCreate := &api.BackupCreate{
+		CreatorID:               creatorID,
+		DatabaseID:              database.ID,
+		Name:                    backupName,
+		StorageBackend:          s.profile.BackupStorageBackend,
+		Type:                    backupType,
+		Path:                    path,
+		MigrationHistoryVersion: migrationHistoryVersion,
+	}
+
+	backupNew, err := s.store.CreateBackup(ctx, backupCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup %q, error: %w", backupName, err)
+	}
+	return backupNew, nil
+}
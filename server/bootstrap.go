@@ -0,0 +1,392 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// bootstrapConfig is the declarative seed config applied on server start.
+// Every resource is identified by its natural key (environment name, instance name, project key and member email),
+// and only missing resources are created, so applying the same file multiple times is a no-op.
+// Example:
+//
+//	environments:
+//	  - name: Prod
+//	instances:
+//	  - name: prod-mysql
+//	    environment: Prod
+//	    engine: MYSQL
+//	    host: mysql.prod.svc
+//	    port: "3306"
+//	    username: bytebase
+//	    password:
+//	      env: PROD_MYSQL_PASSWORD
+//	members:
+//	  - email: dba@example.com
+//	    name: DBA
+//	    role: DBA
+//	projects:
+//	  - key: SHOP
+//	    name: Shop
+//	    members:
+//	      - email: dba@example.com
+//	        role: OWNER
+type bootstrapConfig struct {
+	Environments []bootstrapEnvironment `yaml:"environments"`
+	Instances    []bootstrapInstance    `yaml:"instances"`
+	Members      []bootstrapMember      `yaml:"members"`
+	Projects     []bootstrapProject     `yaml:"projects"`
+}
+
+// bootstrapSecret references a secret value so that credentials don't need to be put in the config file.
+// Kubernetes secrets can be mounted either as environment variables or files.
+type bootstrapSecret struct {
+	// Env is the name of the environment variable holding the secret.
+	Env string `yaml:"env"`
+	// File is the path of the file holding the secret.
+	File string `yaml:"file"`
+}
+
+type bootstrapEnvironment struct {
+	Name string `yaml:"name"`
+}
+
+type bootstrapInstance struct {
+	Name         string           `yaml:"name"`
+	Environment  string           `yaml:"environment"`
+	Engine       db.Type          `yaml:"engine"`
+	ExternalLink string           `yaml:"externalLink"`
+	Host         string           `yaml:"host"`
+	Port         string           `yaml:"port"`
+	Username     string           `yaml:"username"`
+	Password     *bootstrapSecret `yaml:"password"`
+}
+
+type bootstrapMember struct {
+	Email    string           `yaml:"email"`
+	Name     string           `yaml:"name"`
+	Role     api.Role         `yaml:"role"`
+	Password *bootstrapSecret `yaml:"password"`
+}
+
+type bootstrapProjectMember struct {
+	Email string             `yaml:"email"`
+	Role  common.ProjectRole `yaml:"role"`
+}
+
+type bootstrapProject struct {
+	Key     string                   `yaml:"key"`
+	Name    string                   `yaml:"name"`
+	Members []bootstrapProjectMember `yaml:"members"`
+}
+
+// resolve reads the referenced secret. A nil secret resolves to the empty string.
+func (secret *bootstrapSecret) resolve() (string, error) {
+	if secret == nil {
+		return "", nil
+	}
+	if secret.Env != "" && secret.File != "" {
+		return "", fmt.Errorf("secret should specify either env or file, but not both")
+	}
+	if secret.Env != "" {
+		value, ok := os.LookupEnv(secret.Env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %q is not set", secret.Env)
+		}
+		return value, nil
+	}
+	if secret.File != "" {
+		buf, err := os.ReadFile(secret.File)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file %q, error: %w", secret.File, err)
+		}
+		// Secret files usually end with a newline.
+		return strings.TrimRight(string(buf), "\r\n"), nil
+	}
+	return "", nil
+}
+
+// parseBootstrapConfig parses and validates the bootstrap config.
+func parseBootstrapConfig(buf []byte) (*bootstrapConfig, error) {
+	conf := &bootstrapConfig{}
+	if err := yaml.Unmarshal(buf, conf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bootstrap config, error: %w", err)
+	}
+
+	for _, environment := range conf.Environments {
+		if environment.Name == "" {
+			return nil, fmt.Errorf("environment name is required")
+		}
+	}
+	for _, instance := range conf.Instances {
+		if instance.Name == "" {
+			return nil, fmt.Errorf("instance name is required")
+		}
+		if instance.Environment == "" {
+			return nil, fmt.Errorf("instance %q must specify the environment", instance.Name)
+		}
+		switch instance.Engine {
+		case db.ClickHouse, db.MariaDB, db.MongoDB, db.MSSQL, db.MySQL, db.Postgres, db.Snowflake, db.SQLite, db.TiDB:
+		default:
+			return nil, fmt.Errorf("instance %q has unsupported engine %q", instance.Name, instance.Engine)
+		}
+	}
+	for _, member := range conf.Members {
+		if member.Email == "" {
+			return nil, fmt.Errorf("member email is required")
+		}
+		switch member.Role {
+		case api.Owner, api.DBA, api.Developer, api.Auditor:
+		default:
+			return nil, fmt.Errorf("member %q has invalid role %q", member.Email, member.Role)
+		}
+	}
+	for _, project := range conf.Projects {
+		if project.Key == "" || project.Name == "" {
+			return nil, fmt.Errorf("project key and name are required")
+		}
+		for _, projectMember := range project.Members {
+			switch projectMember.Role {
+			case common.ProjectOwner, common.ProjectDeveloper:
+			default:
+				return nil, fmt.Errorf("project %q member %q has invalid role %q", project.Key, projectMember.Email, projectMember.Role)
+			}
+		}
+	}
+	return conf, nil
+}
+
+// applyBootstrapConfig creates the resources declared in the bootstrap file if they don't exist yet.
+func (s *Server) applyBootstrapConfig(ctx context.Context, path string) error {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read bootstrap file %q, error: %w", path, err)
+	}
+	conf, err := parseBootstrapConfig(buf)
+	if err != nil {
+		return err
+	}
+
+	environmentMap := make(map[string]*api.Environment)
+	for _, environment := range conf.Environments {
+		created, err := s.bootstrapEnvironment(ctx, environment)
+		if err != nil {
+			return err
+		}
+		environmentMap[environment.Name] = created
+	}
+	for _, instance := range conf.Instances {
+		environment, ok := environmentMap[instance.Environment]
+		if !ok {
+			environmentList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{Name: &instance.Environment})
+			if err != nil {
+				return err
+			}
+			if len(environmentList) != 1 {
+				return fmt.Errorf("instance %q references unknown environment %q", instance.Name, instance.Environment)
+			}
+			environment = environmentList[0]
+		}
+		if err := s.bootstrapInstance(ctx, environment, instance); err != nil {
+			return err
+		}
+	}
+	for _, member := range conf.Members {
+		if err := s.bootstrapMember(ctx, member); err != nil {
+			return err
+		}
+	}
+	for _, project := range conf.Projects {
+		if err := s.bootstrapProject(ctx, project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) bootstrapEnvironment(ctx context.Context, environment bootstrapEnvironment) (*api.Environment, error) {
+	environmentList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{Name: &environment.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find environment %q, error: %w", environment.Name, err)
+	}
+	if len(environmentList) > 0 {
+		return environmentList[0], nil
+	}
+	created, err := s.store.CreateEnvironment(ctx, &api.EnvironmentCreate{
+		CreatorID: api.SystemBotID,
+		Name:      environment.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create environment %q, error: %w", environment.Name, err)
+	}
+	log.Info("Bootstrapped environment", zap.String("name", environment.Name))
+	return created, nil
+}
+
+func (s *Server) bootstrapInstance(ctx context.Context, environment *api.Environment, instance bootstrapInstance) error {
+	instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{Name: &instance.Name})
+	if err != nil {
+		return fmt.Errorf("failed to find instance %q, error: %w", instance.Name, err)
+	}
+	if len(instanceList) > 0 {
+		return nil
+	}
+	if err := s.instanceCountGuard(ctx); err != nil {
+		return fmt.Errorf("failed to bootstrap instance %q, error: %w", instance.Name, err)
+	}
+	if err := s.disallowBytebaseStore(instance.Engine, instance.Host, instance.Port); err != nil {
+		return err
+	}
+	password, err := instance.Password.resolve()
+	if err != nil {
+		return fmt.Errorf("failed to resolve password for instance %q, error: %w", instance.Name, err)
+	}
+	created, err := s.store.CreateInstance(ctx, &api.InstanceCreate{
+		CreatorID:     api.SystemBotID,
+		EnvironmentID: environment.ID,
+		Name:          instance.Name,
+		Engine:        instance.Engine,
+		ExternalLink:  instance.ExternalLink,
+		Host:          instance.Host,
+		Port:          instance.Port,
+		Username:      instance.Username,
+		Password:      password,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create instance %q, error: %w", instance.Name, err)
+	}
+	log.Info("Bootstrapped instance", zap.String("name", instance.Name))
+
+	// Same as adding the instance from the UI, it's OK if the instance is not reachable yet.
+	// The schema syncer will pick it up once the connection is available.
+	driver, err := s.getAdminDatabaseDriver(ctx, created, "" /* databaseName */)
+	if err != nil {
+		log.Warn("Failed to connect bootstrapped instance", zap.String("name", instance.Name), zap.Error(err))
+		return nil
+	}
+	defer driver.Close(ctx)
+	if err := driver.SetupMigrationIfNeeded(ctx); err != nil {
+		log.Warn("Failed to setup migration schema on bootstrapped instance",
+			zap.String("name", instance.Name),
+			zap.Error(err))
+	}
+	return nil
+}
+
+func (s *Server) bootstrapMember(ctx context.Context, member bootstrapMember) error {
+	principal, err := s.store.GetPrincipalByEmail(ctx, member.Email)
+	if err != nil {
+		return fmt.Errorf("failed to find principal %q, error: %w", member.Email, err)
+	}
+	if principal == nil {
+		password, err := member.Password.resolve()
+		if err != nil {
+			return fmt.Errorf("failed to resolve password for member %q, error: %w", member.Email, err)
+		}
+		if password == "" {
+			// The member is expected to reset the password or login via OAuth.
+			if password, err = common.RandomString(secretLength); err != nil {
+				return err
+			}
+		}
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("failed to generate password hash, error: %w", err)
+		}
+		name := member.Name
+		if name == "" {
+			name = strings.Split(member.Email, "@")[0]
+		}
+		principal, err = s.store.CreatePrincipal(ctx, &api.PrincipalCreate{
+			CreatorID:    api.SystemBotID,
+			Type:         api.EndUser,
+			Name:         name,
+			Email:        member.Email,
+			PasswordHash: string(passwordHash),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create principal %q, error: %w", member.Email, err)
+		}
+	}
+
+	existing, err := s.store.GetMemberByPrincipalID(ctx, principal.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find member %q, error: %w", member.Email, err)
+	}
+	if existing != nil {
+		return nil
+	}
+	if _, err := s.store.CreateMember(ctx, &api.MemberCreate{
+		CreatorID:   api.SystemBotID,
+		Status:      api.Active,
+		Role:        member.Role,
+		PrincipalID: principal.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to create member %q, error: %w", member.Email, err)
+	}
+	log.Info("Bootstrapped member", zap.String("email", member.Email), zap.String("role", string(member.Role)))
+	return nil
+}
+
+func (s *Server) bootstrapProject(ctx context.Context, project bootstrapProject) error {
+	projectList, err := s.store.FindProject(ctx, &api.ProjectFind{Key: &project.Key})
+	if err != nil {
+		return fmt.Errorf("failed to find project %q, error: %w", project.Key, err)
+	}
+	var target *api.Project
+	if len(projectList) > 0 {
+		target = projectList[0]
+	} else {
+		target, err = s.store.CreateProject(ctx, &api.ProjectCreate{
+			CreatorID:  api.SystemBotID,
+			Name:       project.Name,
+			Key:        project.Key,
+			TenantMode: api.TenantModeDisabled,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create project %q, error: %w", project.Key, err)
+		}
+		log.Info("Bootstrapped project", zap.String("key", project.Key))
+	}
+
+	for _, projectMember := range project.Members {
+		principal, err := s.store.GetPrincipalByEmail(ctx, projectMember.Email)
+		if err != nil {
+			return fmt.Errorf("failed to find principal %q, error: %w", projectMember.Email, err)
+		}
+		if principal == nil {
+			return fmt.Errorf("project %q references unknown member %q", project.Key, projectMember.Email)
+		}
+		memberList, err := s.store.FindProjectMember(ctx, &api.ProjectMemberFind{
+			ProjectID:   &target.ID,
+			PrincipalID: &principal.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find project %q member %q, error: %w", project.Key, projectMember.Email, err)
+		}
+		if len(memberList) > 0 {
+			continue
+		}
+		if _, err := s.store.CreateProjectMember(ctx, &api.ProjectMemberCreate{
+			CreatorID:    api.SystemBotID,
+			ProjectID:    target.ID,
+			Role:         projectMember.Role,
+			PrincipalID:  principal.ID,
+			RoleProvider: api.ProjectRoleProviderBytebase,
+		}); err != nil {
+			return fmt.Errorf("failed to add member %q to project %q, error: %w", projectMember.Email, project.Key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBootstrapConfig(t *testing.T) {
+	conf, err := parseBootstrapConfig([]byte(`
+environments:
+  - name: Prod
+instances:
+  - name: prod-mysql
+    environment: Prod
+    engine: MYSQL
+    host: mysql.prod.svc
+    port: "3306"
+    username: bytebase
+    password:
+      env: PROD_MYSQL_PASSWORD
+members:
+  - email: dba@example.com
+    role: DBA
+projects:
+  - key: SHOP
+    name: Shop
+    members:
+      - email: dba@example.com
+        role: OWNER
+`))
+	require.NoError(t, err)
+	require.Equal(t, "Prod", conf.Environments[0].Name)
+	require.Equal(t, db.MySQL, conf.Instances[0].Engine)
+	require.Equal(t, "PROD_MYSQL_PASSWORD", conf.Instances[0].Password.Env)
+	require.Equal(t, api.DBA, conf.Members[0].Role)
+	require.Equal(t, common.ProjectOwner, conf.Projects[0].Members[0].Role)
+
+	invalidList := []string{
+		"environments:\n  - name: \"\"\n",
+		"instances:\n  - name: foo\n    environment: Prod\n    engine: ORACLE\n",
+		"instances:\n  - name: foo\n    engine: MYSQL\n",
+		"members:\n  - email: foo@example.com\n    role: ADMIN\n",
+		"projects:\n  - key: SHOP\n",
+		"projects:\n  - key: SHOP\n    name: Shop\n    members:\n      - email: foo@example.com\n        role: DBA\n",
+	}
+	for _, invalid := range invalidList {
+		_, err := parseBootstrapConfig([]byte(invalid))
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestBootstrapSecretResolve(t *testing.T) {
+	var secret *bootstrapSecret
+	value, err := secret.resolve()
+	require.NoError(t, err)
+	require.Equal(t, "", value)
+
+	t.Setenv("BB_BOOTSTRAP_TEST_SECRET", "from-env")
+	value, err = (&bootstrapSecret{Env: "BB_BOOTSTRAP_TEST_SECRET"}).resolve()
+	require.NoError(t, err)
+	require.Equal(t, "from-env", value)
+
+	_, err = (&bootstrapSecret{Env: "BB_BOOTSTRAP_TEST_SECRET_NOT_EXIST"}).resolve()
+	require.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("from-file\n"), 0600))
+	value, err = (&bootstrapSecret{File: file}).resolve()
+	require.NoError(t, err)
+	require.Equal(t, "from-file", value)
+
+	_, err = (&bootstrapSecret{Env: "BB_BOOTSTRAP_TEST_SECRET", File: file}).resolve()
+	require.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+const (
+	// The default time range of the change calendar is the week before and after now.
+	changeCalendarDefaultRange = time.Duration(7*24) * time.Hour
+)
+
+func (s *Server) registerCalendarRoutes(g *echo.Group) {
+	// The change calendar shows the changes scheduled by the earliest allowed time and the task runs in the time range,
+	// so the operators can coordinate the changes with the other operations and the freeze windows.
+	g.GET("/calendar", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		now := time.Now().Unix()
+		startTs := now - int64(changeCalendarDefaultRange.Seconds())
+		endTs := now + int64(changeCalendarDefaultRange.Seconds())
+		if startStr := c.QueryParam("start"); startStr != "" {
+			ts, err := strconv.ParseInt(startStr, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter start is not a number: %s", startStr)).SetInternal(err)
+			}
+			startTs = ts
+		}
+		if endStr := c.QueryParam("end"); endStr != "" {
+			ts, err := strconv.ParseInt(endStr, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter end is not a number: %s", endStr)).SetInternal(err)
+			}
+			endTs = ts
+		}
+		if startTs > endTs {
+			return echo.NewHTTPError(http.StatusBadRequest, "Query parameter start must not be after end")
+		}
+
+		envFind := &api.EnvironmentFind{}
+		if envIDStr := c.QueryParam("environment"); envIDStr != "" {
+			envID, err := strconv.Atoi(envIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter environment is not a number: %s", envIDStr)).SetInternal(err)
+			}
+			envFind.ID = &envID
+		}
+		envList, err := s.store.FindEnvironment(ctx, envFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch environment list").SetInternal(err)
+		}
+
+		calendar, err := s.getChangeCalendar(ctx, startTs, endTs, envList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build change calendar").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, calendar)
+	})
+}
+
+func (s *Server) getChangeCalendar(ctx context.Context, startTs int64, endTs int64, envList []*api.Environment) (*api.ChangeCalendar, error) {
+	var eventList []*changeCalendarTaskEvent
+
+	// The scheduled changes are the tasks not started yet with the earliest allowed time in the range.
+	statusList := []api.TaskStatus{api.TaskPendingApproval, api.TaskPending}
+	taskList, err := s.store.FindTask(ctx, &api.TaskFind{StatusList: &statusList}, true /* returnOnErr */)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending task list, error: %w", err)
+	}
+	for _, task := range taskList {
+		if task.EarliestAllowedTs < startTs || task.EarliestAllowedTs > endTs {
+			continue
+		}
+		eventList = append(eventList, &changeCalendarTaskEvent{
+			task: task,
+			event: &api.ChangeCalendarEvent{
+				Type:    api.ChangeCalendarEventScheduled,
+				StartTs: task.EarliestAllowedTs,
+				Status:  string(task.Status),
+			},
+		})
+	}
+
+	// The executed changes are the task runs started in the range.
+	taskRunList, err := s.store.FindTaskRun(ctx, &api.TaskRunFind{
+		CreatedAfterTs:  &startTs,
+		CreatedBeforeTs: &endTs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task run list, error: %w", err)
+	}
+	taskMap := make(map[int]*api.Task)
+	for _, taskRun := range taskRunList {
+		task, ok := taskMap[taskRun.TaskID]
+		if !ok {
+			task, err = s.store.GetTaskByID(ctx, taskRun.TaskID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get task ID %d, error: %w", taskRun.TaskID, err)
+			}
+			taskMap[taskRun.TaskID] = task
+		}
+		if task == nil {
+			continue
+		}
+		event := &api.ChangeCalendarEvent{
+			Type:    api.ChangeCalendarEventExecuted,
+			StartTs: taskRun.CreatedTs,
+			Status:  string(taskRun.Status),
+		}
+		if taskRun.Status != api.TaskRunRunning {
+			event.EndTs = taskRun.UpdatedTs
+		}
+		eventList = append(eventList, &changeCalendarTaskEvent{task: task, event: event})
+	}
+
+	issueMap := make(map[int]*api.Issue)
+	for _, e := range eventList {
+		issue, ok := issueMap[e.task.PipelineID]
+		if !ok {
+			issue, err = s.store.GetIssueByPipelineID(ctx, e.task.PipelineID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get issue by pipeline ID %d, error: %w", e.task.PipelineID, err)
+			}
+			issueMap[e.task.PipelineID] = issue
+		}
+		e.issue = issue
+	}
+
+	return buildChangeCalendar(startTs, endTs, envList, eventList), nil
+}
+
+// changeCalendarTaskEvent is a calendar event with the task and the issue to fill the event details.
+type changeCalendarTaskEvent struct {
+	event *api.ChangeCalendarEvent
+	task  *api.Task
+	// issue is nil if the task doesn't belong to an issue.
+	issue *api.Issue
+}
+
+// buildChangeCalendar groups the events by the environment of the task instance.
+// The events in the environments not in the environment list are dropped.
+func buildChangeCalendar(startTs int64, endTs int64, envList []*api.Environment, eventList []*changeCalendarTaskEvent) *api.ChangeCalendar {
+	calendar := &api.ChangeCalendar{
+		StartTs:         startTs,
+		EndTs:           endTs,
+		EnvironmentList: []*api.ChangeCalendarEnvironment{},
+	}
+	envMap := make(map[int]*api.ChangeCalendarEnvironment)
+	for _, env := range envList {
+		calendarEnv := &api.ChangeCalendarEnvironment{
+			EnvironmentID:   env.ID,
+			EnvironmentName: env.Name,
+			EventList:       []*api.ChangeCalendarEvent{},
+		}
+		envMap[env.ID] = calendarEnv
+		calendar.EnvironmentList = append(calendar.EnvironmentList, calendarEnv)
+	}
+
+	for _, e := range eventList {
+		if e.task.Instance == nil {
+			continue
+		}
+		calendarEnv, ok := envMap[e.task.Instance.EnvironmentID]
+		if !ok {
+			continue
+		}
+		event := e.event
+		event.TaskID = e.task.ID
+		event.TaskName = e.task.Name
+		event.TaskType = e.task.Type
+		event.InstanceName = e.task.Instance.Name
+		if e.task.Database != nil {
+			event.DatabaseName = e.task.Database.Name
+		}
+		if e.issue != nil {
+			event.IssueID = e.issue.ID
+			event.IssueName = e.issue.Name
+			event.ProjectID = e.issue.ProjectID
+		}
+		calendarEnv.EventList = append(calendarEnv.EventList, event)
+	}
+
+	for _, calendarEnv := range calendar.EnvironmentList {
+		sort.SliceStable(calendarEnv.EventList, func(i, j int) bool {
+			return calendarEnv.EventList[i].StartTs < calendarEnv.EventList[j].StartTs
+		})
+	}
+	return calendar
+}
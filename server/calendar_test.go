@@ -0,0 +1,69 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildChangeCalendar(t *testing.T) {
+	envList := []*api.Environment{
+		{ID: 1, Name: "Test"},
+		{ID: 2, Name: "Prod"},
+	}
+	newTask := func(id int, envID int, databaseName string) *api.Task {
+		return &api.Task{
+			ID:       id,
+			Name:     "Update " + databaseName,
+			Type:     api.TaskDatabaseSchemaUpdate,
+			Instance: &api.Instance{Name: "mysql", EnvironmentID: envID},
+			Database: &api.Database{Name: databaseName},
+		}
+	}
+	issue := &api.Issue{ID: 10, Name: "Add column", ProjectID: 3}
+	eventList := []*changeCalendarTaskEvent{
+		{
+			event: &api.ChangeCalendarEvent{Type: api.ChangeCalendarEventScheduled, StartTs: 300, Status: string(api.TaskPending)},
+			task:  newTask(2, 2, "db"),
+			issue: issue,
+		},
+		{
+			event: &api.ChangeCalendarEvent{Type: api.ChangeCalendarEventExecuted, StartTs: 100, EndTs: 110, Status: string(api.TaskRunDone)},
+			task:  newTask(1, 1, "db"),
+			issue: issue,
+		},
+		{
+			event: &api.ChangeCalendarEvent{Type: api.ChangeCalendarEventExecuted, StartTs: 200, Status: string(api.TaskRunRunning)},
+			task:  newTask(3, 2, "other"),
+		},
+		{
+			// The environment isn't in the environment list.
+			event: &api.ChangeCalendarEvent{Type: api.ChangeCalendarEventExecuted, StartTs: 150, Status: string(api.TaskRunDone)},
+			task:  newTask(4, 3, "db"),
+		},
+	}
+
+	calendar := buildChangeCalendar(0, 1000, envList, eventList)
+	require.Equal(t, &api.ChangeCalendar{
+		StartTs: 0,
+		EndTs:   1000,
+		EnvironmentList: []*api.ChangeCalendarEnvironment{
+			{
+				EnvironmentID:   1,
+				EnvironmentName: "Test",
+				EventList: []*api.ChangeCalendarEvent{
+					{Type: api.ChangeCalendarEventExecuted, StartTs: 100, EndTs: 110, Status: "DONE", TaskID: 1, TaskName: "Update db", TaskType: api.TaskDatabaseSchemaUpdate, IssueID: 10, IssueName: "Add column", ProjectID: 3, InstanceName: "mysql", DatabaseName: "db"},
+				},
+			},
+			{
+				EnvironmentID:   2,
+				EnvironmentName: "Prod",
+				EventList: []*api.ChangeCalendarEvent{
+					{Type: api.ChangeCalendarEventExecuted, StartTs: 200, Status: "RUNNING", TaskID: 3, TaskName: "Update other", TaskType: api.TaskDatabaseSchemaUpdate, InstanceName: "mysql", DatabaseName: "other"},
+					{Type: api.ChangeCalendarEventScheduled, StartTs: 300, Status: "PENDING", TaskID: 2, TaskName: "Update db", TaskType: api.TaskDatabaseSchemaUpdate, IssueID: 10, IssueName: "Add column", ProjectID: 3, InstanceName: "mysql", DatabaseName: "db"},
+				},
+			},
+		},
+	}, calendar)
+}
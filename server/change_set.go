@@ -0,0 +1,261 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerChangeSetRoutes(g *echo.Group) {
+	g.GET("/issue/:id/change-set", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		issue, err := s.store.GetIssueByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", id)).SetInternal(err)
+		}
+		if issue == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", id))
+		}
+		changeSet, err := s.store.GetChangeSetByPipelineID(ctx, issue.PipelineID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch change set for issue ID: %v", id)).SetInternal(err)
+		}
+		if changeSet == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID %d is not a change set", id))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, changeSet); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal change set response for issue ID: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// createChangeSetIfNeeded records the pipeline as a change set if the issue create context asks the databases to be updated together.
+func (s *Server) createChangeSetIfNeeded(ctx context.Context, issueCreate *api.IssueCreate, pipelineID int, creatorID int) error {
+	if issueCreate.Type != api.IssueDatabaseSchemaUpdate && issueCreate.Type != api.IssueDatabaseDataUpdate {
+		return nil
+	}
+	c := api.UpdateSchemaContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return err
+	}
+	if !c.ChangeSet {
+		return nil
+	}
+	if _, err := s.store.CreateChangeSet(ctx, &api.ChangeSetCreate{
+		CreatorID:  creatorID,
+		PipelineID: pipelineID,
+	}); err != nil {
+		return fmt.Errorf("failed to create change set for pipeline ID %d, error: %w", pipelineID, err)
+	}
+	return nil
+}
+
+// verifyChangeSetIfNeeded returns whether the tasks of the pipeline can be executed.
+// For a change set, no task executes until the required checks of all tasks pass, including the tasks in the later stages.
+func (s *Server) verifyChangeSetIfNeeded(ctx context.Context, pipeline *api.Pipeline) (bool, error) {
+	changeSet, err := s.store.GetChangeSetByPipelineID(ctx, pipeline.ID)
+	if err != nil {
+		return false, err
+	}
+	if changeSet == nil || changeSet.Status != api.ChangeSetVerifying {
+		return true, nil
+	}
+
+	for _, stage := range pipeline.StageList {
+		for _, task := range stage.TaskList {
+			if _, err := s.TaskCheckScheduler.ScheduleCheckIfNeeded(ctx, task, api.SystemBotID, true /* skipIfAlreadyTerminated */); err != nil {
+				return false, err
+			}
+			pass, err := s.TaskScheduler.passAllCheck(ctx, task, api.TaskCheckStatusWarn)
+			if err != nil {
+				return false, err
+			}
+			if !pass {
+				return false, nil
+			}
+		}
+	}
+
+	status := api.ChangeSetRunning
+	if _, err := s.store.PatchChangeSet(ctx, &api.ChangeSetPatch{
+		ID:        changeSet.ID,
+		UpdaterID: api.SystemBotID,
+		Status:    &status,
+	}); err != nil {
+		return false, fmt.Errorf("failed to mark change set ID %d as running, error: %w", changeSet.ID, err)
+	}
+	return true, nil
+}
+
+// updateChangeSetAfterTaskStatusChange updates the change set of the pipeline after the task status changes.
+// A failed task halts the change set and alerts with the compensation plan, and retrying the failed task resumes the change set.
+func (s *Server) updateChangeSetAfterTaskStatusChange(ctx context.Context, task *api.Task, issue *api.Issue, updaterID int) error {
+	changeSet, err := s.store.GetChangeSetByPipelineID(ctx, task.PipelineID)
+	if err != nil {
+		return err
+	}
+	if changeSet == nil {
+		return nil
+	}
+
+	switch {
+	case task.Status == api.TaskFailed && changeSet.Status == api.ChangeSetRunning:
+		return s.haltChangeSet(ctx, changeSet, task, issue)
+	case task.Status == api.TaskRunning && changeSet.Status == api.ChangeSetHalted:
+		// Keep the compensation plan in the payload for the record.
+		status := api.ChangeSetRunning
+		if _, err := s.store.PatchChangeSet(ctx, &api.ChangeSetPatch{
+			ID:        changeSet.ID,
+			UpdaterID: updaterID,
+			Status:    &status,
+		}); err != nil {
+			return fmt.Errorf("failed to resume change set ID %d, error: %w", changeSet.ID, err)
+		}
+	case task.Status == api.TaskDone && changeSet.Status == api.ChangeSetRunning:
+		pipeline, err := s.store.GetPipelineByID(ctx, task.PipelineID)
+		if err != nil {
+			return err
+		}
+		if pipeline == nil {
+			return fmt.Errorf("pipeline not found for ID %v", task.PipelineID)
+		}
+		for _, stage := range pipeline.StageList {
+			for _, t := range stage.TaskList {
+				if t.Status != api.TaskDone {
+					return nil
+				}
+			}
+		}
+		status := api.ChangeSetDone
+		if _, err := s.store.PatchChangeSet(ctx, &api.ChangeSetPatch{
+			ID:        changeSet.ID,
+			UpdaterID: updaterID,
+			Status:    &status,
+		}); err != nil {
+			return fmt.Errorf("failed to mark change set ID %d as done, error: %w", changeSet.ID, err)
+		}
+	}
+	return nil
+}
+
+// haltChangeSet records the compensation plan in the change set and alerts the issue subscribers and the project webhooks.
+// The pipeline itself halts at the failed task as usual.
+func (s *Server) haltChangeSet(ctx context.Context, changeSet *api.ChangeSet, failedTask *api.Task, issue *api.Issue) error {
+	pipeline, err := s.store.GetPipelineByID(ctx, failedTask.PipelineID)
+	if err != nil {
+		return err
+	}
+	if pipeline == nil {
+		return fmt.Errorf("pipeline not found for ID %v", failedTask.PipelineID)
+	}
+	plan, err := buildChangeSetCompensationPlan(pipeline, failedTask)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(api.ChangeSetPayload{CompensationPlan: plan})
+	if err != nil {
+		return fmt.Errorf("failed to marshal change set payload, error: %w", err)
+	}
+	payloadStr := string(payload)
+	status := api.ChangeSetHalted
+	if _, err := s.store.PatchChangeSet(ctx, &api.ChangeSetPatch{
+		ID:        changeSet.ID,
+		UpdaterID: api.SystemBotID,
+		Status:    &status,
+		Payload:   &payloadStr,
+	}); err != nil {
+		return fmt.Errorf("failed to halt change set ID %d, error: %w", changeSet.ID, err)
+	}
+
+	issueName := ""
+	containerID := pipeline.ID
+	if issue != nil {
+		issueName = issue.Name
+		containerID = issue.ID
+	}
+	activityPayload, err := json.Marshal(api.ActivityPipelineChangeSetHaltPayload{
+		ChangeSetID:      changeSet.ID,
+		CompensationPlan: plan,
+		IssueName:        issueName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity after halting change set ID %d, error: %w", changeSet.ID, err)
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: containerID,
+		Type:        api.ActivityPipelineChangeSetHalt,
+		Level:       api.ActivityError,
+		Payload:     string(activityPayload),
+		Comment:     fmt.Sprintf("Halted the change set after task %q failed, %d executed task(s) need to be compensated.", failedTask.Name, len(plan.StepList)),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{issue: issue}); err != nil {
+		return fmt.Errorf("failed to create activity after halting change set ID %d, error: %w", changeSet.ID, err)
+	}
+	return nil
+}
+
+// buildChangeSetCompensationPlan builds the plan to compensate the done tasks of the pipeline in the reverse order.
+// The tasks are executed one by one in the pipeline order, so the reverse pipeline order is the reverse execution order.
+func buildChangeSetCompensationPlan(pipeline *api.Pipeline, failedTask *api.Task) (*api.ChangeSetCompensationPlan, error) {
+	plan := &api.ChangeSetCompensationPlan{
+		FailedTaskID:   failedTask.ID,
+		FailedTaskName: failedTask.Name,
+		StepList:       []*api.ChangeSetCompensationStep{},
+	}
+	if failedTask.Database != nil {
+		plan.FailedDatabaseName = failedTask.Database.Name
+	}
+
+	for i := len(pipeline.StageList) - 1; i >= 0; i-- {
+		taskList := pipeline.StageList[i].TaskList
+		for j := len(taskList) - 1; j >= 0; j-- {
+			task := taskList[j]
+			if task.Status != api.TaskDone {
+				continue
+			}
+			step := &api.ChangeSetCompensationStep{
+				TaskID: task.ID,
+			}
+			if task.Database != nil {
+				step.DatabaseID = task.Database.ID
+				step.DatabaseName = task.Database.Name
+			}
+			if task.Instance != nil && task.Instance.Environment != nil {
+				step.EnvironmentName = task.Instance.Environment.Name
+			}
+			switch task.Type {
+			case api.TaskDatabaseSchemaUpdate:
+				payload := &api.TaskDatabaseSchemaUpdatePayload{}
+				if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+					return nil, fmt.Errorf("invalid database schema update payload of task ID %d, error: %w", task.ID, err)
+				}
+				step.Statement = payload.DownStatement
+			case api.TaskDatabaseDataUpdate:
+				payload := &api.TaskDatabaseDataUpdatePayload{}
+				if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+					return nil, fmt.Errorf("invalid database data update payload of task ID %d, error: %w", task.ID, err)
+				}
+				step.Statement = payload.DownStatement
+			}
+			plan.StepList = append(plan.StepList, step)
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildChangeSetCompensationPlan(t *testing.T) {
+	staging := &api.Environment{Name: "Staging"}
+	prod := &api.Environment{Name: "Prod"}
+	newTask := func(id int, status api.TaskStatus, taskType api.TaskType, databaseName string, environment *api.Environment, payload string) *api.Task {
+		return &api.Task{
+			ID:       id,
+			Name:     "Update " + databaseName,
+			Status:   status,
+			Type:     taskType,
+			Payload:  payload,
+			Database: &api.Database{ID: id * 10, Name: databaseName},
+			Instance: &api.Instance{Environment: environment},
+		}
+	}
+	failedTask := newTask(4, api.TaskFailed, api.TaskDatabaseSchemaUpdate, "billing", prod, `{"statement":"ALTER TABLE invoice ADD c INT"}`)
+	pipeline := &api.Pipeline{
+		StageList: []*api.Stage{
+			{
+				TaskList: []*api.Task{
+					newTask(1, api.TaskDone, api.TaskDatabaseSchemaUpdate, "orders", staging, `{"statement":"ALTER TABLE t ADD c INT","downStatement":"ALTER TABLE t DROP COLUMN c"}`),
+					newTask(2, api.TaskDone, api.TaskDatabaseDataUpdate, "billing", staging, `{"statement":"UPDATE t SET c = 1"}`),
+				},
+			},
+			{
+				TaskList: []*api.Task{
+					newTask(3, api.TaskDone, api.TaskDatabaseSchemaUpdate, "orders", prod, `{"statement":"ALTER TABLE t ADD c INT","downStatement":"ALTER TABLE t DROP COLUMN c"}`),
+					failedTask,
+					newTask(5, api.TaskPendingApproval, api.TaskDatabaseSchemaUpdate, "users", prod, `{"statement":"ALTER TABLE u ADD c INT"}`),
+				},
+			},
+		},
+	}
+
+	plan, err := buildChangeSetCompensationPlan(pipeline, failedTask)
+	require.NoError(t, err)
+	require.Equal(t, &api.ChangeSetCompensationPlan{
+		FailedTaskID:       4,
+		FailedTaskName:     "Update billing",
+		FailedDatabaseName: "billing",
+		StepList: []*api.ChangeSetCompensationStep{
+			{TaskID: 3, DatabaseID: 30, DatabaseName: "orders", EnvironmentName: "Prod", Statement: "ALTER TABLE t DROP COLUMN c"},
+			{TaskID: 2, DatabaseID: 20, DatabaseName: "billing", EnvironmentName: "Staging"},
+			{TaskID: 1, DatabaseID: 10, DatabaseName: "orders", EnvironmentName: "Staging", Statement: "ALTER TABLE t DROP COLUMN c"},
+		},
+	}, plan)
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func (s *Server) registerChangelogRoutes(g *echo.Group) {
+	g.GET("/database/:id/changelog", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		find := &db.MigrationHistoryFind{Database: &database.Name}
+		if versionStr := c.QueryParam("version"); versionStr != "" {
+			find.Version = &versionStr
+		}
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit query parameter is not a number: %s", limitStr)).SetInternal(err)
+			}
+			find.Limit = &limit
+		}
+
+		driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, "" /* databaseName */)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch migration history for database %q", database.Name)).SetInternal(err)
+		}
+		defer driver.Close(ctx)
+		list, err := driver.FindMigrationHistoryList(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch migration history list").SetInternal(err)
+		}
+
+		changelog, err := buildChangelog(database, list)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to build changelog for database %q", database.Name)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, changelog)
+	})
+
+	g.POST("/database/:id/changelog/:historyID/revert", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		historyID, err := strconv.Atoi(c.Param("historyID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("History ID is not a number: %s", c.Param("historyID"))).SetInternal(err)
+		}
+
+		revertCreate := &api.ChangelogRevertIssueCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, revertCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create revert issue request").SetInternal(err)
+		}
+		if revertCreate.AssigneeID == api.UnknownID {
+			revertCreate.AssigneeID = api.SystemBotID
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, "" /* databaseName */)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch migration history for database %q", database.Name)).SetInternal(err)
+		}
+		defer driver.Close(ctx)
+		list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{ID: &historyID, Database: &database.Name})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch migration history list").SetInternal(err)
+		}
+		if len(list) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Migration history ID %d not found for database %q", historyID, database.Name))
+		}
+		history := list[0]
+
+		if history.Status != db.Done {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Migration version %s of database %q isn't done and can't be reverted", history.Version, database.Name))
+		}
+		issueType := api.IssueDatabaseSchemaUpdate
+		switch history.Type {
+		case db.Migrate:
+		case db.Data:
+			issueType = api.IssueDatabaseDataUpdate
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Migration version %s of database %q is %s type and can't be reverted", history.Version, database.Name, history.Type))
+		}
+		downStatement, err := getMigrationDownStatement(history)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to parse migration history ID %d", historyID)).SetInternal(err)
+		}
+		if downStatement == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Migration version %s of database %q has no down statement", history.Version, database.Name))
+		}
+
+		// The revert issue goes through the same approval flow as the other issues.
+		// Its down statement is the original statement, so the revert can be reverted as well.
+		createContext, err := json.Marshal(&api.UpdateSchemaContext{
+			MigrationType: history.Type,
+			DetailList: []*api.UpdateSchemaDetail{
+				{
+					DatabaseID:    database.ID,
+					Statement:     downStatement,
+					DownStatement: history.Statement,
+				},
+			},
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal update schema context").SetInternal(err)
+		}
+		issue, err := s.createIssue(ctx, &api.IssueCreate{
+			ProjectID:     database.ProjectID,
+			Name:          fmt.Sprintf("Revert migration version %s of database %q", history.Version, database.Name),
+			Type:          issueType,
+			Description:   fmt.Sprintf("Apply the down statement of migration version %s: %s", history.Version, history.Description),
+			AssigneeID:    revertCreate.AssigneeID,
+			CreateContext: string(createContext),
+		}, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, issue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal create revert issue response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getMigrationDownStatement returns the down statement recorded in the migration history payload.
+func getMigrationDownStatement(history *db.MigrationHistory) (string, error) {
+	if history.Payload == "" {
+		return "", nil
+	}
+	payload := &db.MigrationInfoPayload{}
+	if err := json.Unmarshal([]byte(history.Payload), payload); err != nil {
+		return "", fmt.Errorf("invalid migration history payload, error: %w", err)
+	}
+	return payload.DownStatement, nil
+}
+
+// buildChangelog builds the changelog from the migration history list of the database.
+// The diff is only computed for the done changes, since the schema snapshot after the change is only recorded when the change succeeds.
+func buildChangelog(database *api.Database, historyList []*db.MigrationHistory) (*api.Changelog, error) {
+	changelog := &api.Changelog{
+		DatabaseID:   database.ID,
+		DatabaseName: database.Name,
+		EntryList:    []*api.ChangelogEntry{},
+	}
+	for _, history := range historyList {
+		entry := &api.ChangelogEntry{
+			MigrationHistoryID:  history.ID,
+			Creator:             history.Creator,
+			CreatedTs:           history.CreatedTs,
+			Source:              history.Source,
+			Type:                history.Type,
+			Status:              history.Status,
+			Version:             history.Version,
+			Description:         history.Description,
+			Statement:           history.Statement,
+			ExecutionDurationNs: history.ExecutionDurationNs,
+			IssueID:             history.IssueID,
+			SchemaBefore:        history.SchemaPrev,
+			SchemaAfter:         history.Schema,
+		}
+		downStatement, err := getMigrationDownStatement(history)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get down statement for migration history ID %d, error: %w", history.ID, err)
+		}
+		entry.DownStatement = downStatement
+		if history.Status == db.Done {
+			diff, err := diffSchema(database.Instance.Engine, history.SchemaPrev, history.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to diff schema for migration history ID %d, error: %w", history.ID, err)
+			}
+			entry.Diff = diff
+		}
+		changelog.EntryList = append(changelog.EntryList, entry)
+	}
+	return changelog, nil
+}
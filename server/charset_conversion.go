@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func (s *Server) registerCharsetConversionRoutes(g *echo.Group) {
+	g.GET("/database/:id/charset-conversion-plan", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		var tableNameList []string
+		if v := c.QueryParam("table"); v != "" {
+			tableNameList = strings.Split(v, ",")
+		}
+		plan, err := s.getCharsetConversionPlan(ctx, database, c.QueryParam("characterSet"), c.QueryParam("collation"), tableNameList)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, plan)
+	})
+}
+
+// getCharsetConversionPlan plans the charset conversion of the database based on the synced table statistics.
+func (s *Server) getCharsetConversionPlan(ctx context.Context, database *api.Database, characterSet, collation string, tableNameList []string) (*api.CharsetConversionPlan, error) {
+	// Only MySQL is supported since the large tables are converted with gh-ost.
+	if database.Instance.Engine != db.MySQL {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Charset conversion is not supported for %s database %q", database.Instance.Engine, database.Name))
+	}
+	tableList, err := s.store.FindTable(ctx, &api.TableFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table list for database id: %d", database.ID)).SetInternal(err)
+	}
+	plan, err := api.PlanCharsetConversion(database.Name, characterSet, collation, tableList, tableNameList)
+	if err != nil {
+		switch common.ErrorCode(err) {
+		case common.Invalid:
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case common.NotFound:
+			return nil, echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to plan charset conversion").SetInternal(err)
+	}
+	return plan, nil
+}
@@ -34,6 +34,10 @@ type Profile struct {
 	FrontendPort int
 	// DatastorePort is the binding port for database instance for storing Bytebase data.
 	DatastorePort int
+	// DatastoreLocale is the locale of the embedded Postgres, it only takes effect when the data directory is initialized.
+	DatastoreLocale string
+	// DatastoreChecksums enables the data checksums of the embedded Postgres, it only takes effect when the data directory is initialized.
+	DatastoreChecksums bool
 	// PgUser is the user we use to connect to bytebase's Postgres database.
 	// The name of the database storing metadata is the same as pgUser.
 	PgUser string
@@ -44,6 +48,8 @@ type Profile struct {
 	Readonly bool
 	// DataDir is the directory stores the data including Bytebase's own database, backups, etc.
 	DataDir string
+	// ResourceDir is the directory where the embedded binaries such as Postgres and mysqlutil are extracted.
+	ResourceDir string
 	// Debug decides the log level
 	Debug bool
 	// Demo decides that whether load demo data.
@@ -62,6 +68,22 @@ type Profile struct {
 	PgURL string
 	// MetricConnectionKey is the connection key for metric.
 	MetricConnectionKey string
+	// BootstrapFile is the optional path of the declarative seed config applied on startup.
+	BootstrapFile string
+	// EventSinkURL is the optional URL of the sink receiving the schema change events, e.g. nats://localhost:4222/bytebase.schema.change.
+	EventSinkURL string
+	// SampleData decides whether to generate the sample instances, project and issues on startup.
+	SampleData bool
+	// SampleDatabasePort is the binding port of the first sample instance, the following sample instances bind the next ports.
+	SampleDatabasePort int
+	// BlobStoreURL is the optional URL of the blob store saving the issue attachments, e.g. s3://bucket/prefix?region=us-east-1.
+	// The attachments are saved under the data directory if not specified.
+	BlobStoreURL string
+	// AttachmentScanURL is the optional HTTP endpoint scanning the uploaded issue attachments for viruses.
+	AttachmentScanURL string
+	// Offline disables the outbound calls to the Bytebase and third-party services such as the usage metrics reporting and the VCS OAuth.
+	// The outbound calls to the endpoints configured explicitly, e.g. the event sink and the project webhooks, are kept.
+	Offline bool
 }
 
 func (prof *Profile) useEmbedDB() bool {
@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+const (
+	connectionCheckTimeout = 10 * time.Second
+)
+
+// checkConnection tests the connection step by step: resolving the host, connecting to the port, handshaking TLS,
+// logging in and probing the privileges, so that the failure can be told apart from each other.
+// The steps depending on a failed step are skipped.
+func checkConnection(ctx context.Context, engine db.Type, connCfg db.ConnectionConfig) []api.ConnectionCheck {
+	var checkList []api.ConnectionCheck
+	addCheck := func(checkType api.ConnectionCheckType, title string, startTime time.Time, status api.ConnectionCheckStatus, detail string) {
+		checkList = append(checkList, api.ConnectionCheck{
+			Type:       checkType,
+			Title:      title,
+			Status:     status,
+			Detail:     detail,
+			DurationMs: time.Since(startTime).Milliseconds(),
+		})
+	}
+
+	// The network checks are only applicable to the engines connecting to a host and a port.
+	port := connCfg.Port
+	if port == "" {
+		port = util.GetDefaultPort(engine)
+	}
+	networkApplicable := port != "" && connCfg.Host != "" && !strings.HasPrefix(connCfg.Host, "/")
+	reachable := true
+	if networkApplicable {
+		startTime := time.Now()
+		addressList, status, detail := checkDNS(ctx, connCfg.Host)
+		addCheck(api.ConnectionCheckDNS, "Resolve host", startTime, status, detail)
+		if status == api.ConnectionCheckError {
+			reachable = false
+		}
+
+		if reachable {
+			startTime = time.Now()
+			status, detail = checkTCP(ctx, connCfg.Host, port, addressList)
+			addCheck(api.ConnectionCheckTCP, "Connect to port", startTime, status, detail)
+			if status == api.ConnectionCheckError {
+				reachable = false
+			}
+		} else {
+			addCheck(api.ConnectionCheckTCP, "Connect to port", time.Now(), api.ConnectionCheckSkipped, "Skipped because the host can't be resolved.")
+		}
+
+		startTime = time.Now()
+		if !reachable {
+			addCheck(api.ConnectionCheckTLS, "Handshake TLS", startTime, api.ConnectionCheckSkipped, "Skipped because the server isn't reachable.")
+		} else if !util.IsTLSProbeSupported(engine, connCfg.Host) {
+			addCheck(api.ConnectionCheckTLS, "Handshake TLS", startTime, api.ConnectionCheckSkipped, fmt.Sprintf("TLS inspection isn't supported for %s.", engine))
+		} else {
+			status, detail = checkTLS(ctx, engine, connCfg.Host, port)
+			addCheck(api.ConnectionCheckTLS, "Handshake TLS", startTime, status, detail)
+		}
+	}
+
+	readTitle, createTitle := "Read information_schema", "Create database"
+	if !reachable {
+		addCheck(api.ConnectionCheckAuth, "Login", time.Now(), api.ConnectionCheckSkipped, "Skipped because the server isn't reachable.")
+		addCheck(api.ConnectionCheckPrivilege, readTitle, time.Now(), api.ConnectionCheckSkipped, "Skipped because the server isn't reachable.")
+		addCheck(api.ConnectionCheckPrivilege, createTitle, time.Now(), api.ConnectionCheckSkipped, "Skipped because the server isn't reachable.")
+		return checkList
+	}
+
+	startTime := time.Now()
+	driver, err := db.Open(ctx, engine, db.DriverConfig{}, connCfg, db.ConnectionContext{})
+	if err == nil {
+		defer driver.Close(ctx)
+		err = driver.Ping(ctx)
+	}
+	if err != nil {
+		addCheck(api.ConnectionCheckAuth, "Login", startTime, api.ConnectionCheckError, fmt.Sprintf("Failed to login as %q: %s", connCfg.Username, err.Error()))
+		addCheck(api.ConnectionCheckPrivilege, readTitle, time.Now(), api.ConnectionCheckSkipped, "Skipped because the login fails.")
+		addCheck(api.ConnectionCheckPrivilege, createTitle, time.Now(), api.ConnectionCheckSkipped, "Skipped because the login fails.")
+		return checkList
+	}
+	addCheck(api.ConnectionCheckAuth, "Login", startTime, api.ConnectionCheckSuccess, fmt.Sprintf("Logged in as %q.", connCfg.Username))
+
+	if engine != db.Postgres && engine != db.MySQL && engine != db.TiDB && engine != db.MariaDB {
+		detail := fmt.Sprintf("Privilege probe isn't supported for %s.", engine)
+		addCheck(api.ConnectionCheckPrivilege, readTitle, time.Now(), api.ConnectionCheckSkipped, detail)
+		addCheck(api.ConnectionCheckPrivilege, createTitle, time.Now(), api.ConnectionCheckSkipped, detail)
+		return checkList
+	}
+	database := ""
+	if engine == db.Postgres {
+		// Postgres always connects to a specific database.
+		database = "postgres"
+	}
+	sqldb, err := driver.GetDBConnection(ctx, database)
+	if err != nil {
+		detail := fmt.Sprintf("Failed to get the connection: %s", err.Error())
+		addCheck(api.ConnectionCheckPrivilege, readTitle, time.Now(), api.ConnectionCheckError, detail)
+		addCheck(api.ConnectionCheckPrivilege, createTitle, time.Now(), api.ConnectionCheckError, detail)
+		return checkList
+	}
+
+	startTime = time.Now()
+	query := "SELECT COUNT(*) FROM information_schema.SCHEMATA"
+	if engine == db.Postgres {
+		query = "SELECT COUNT(*) FROM information_schema.tables"
+	}
+	var count int
+	if err := sqldb.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		addCheck(api.ConnectionCheckPrivilege, readTitle, startTime, api.ConnectionCheckError, fmt.Sprintf("Failed to read information_schema: %s", err.Error()))
+	} else {
+		addCheck(api.ConnectionCheckPrivilege, readTitle, startTime, api.ConnectionCheckSuccess, fmt.Sprintf("%d rows are visible in information_schema.", count))
+	}
+
+	startTime = time.Now()
+	canCreate, err := canCreateDatabase(ctx, engine, sqldb)
+	switch {
+	case err != nil:
+		addCheck(api.ConnectionCheckPrivilege, createTitle, startTime, api.ConnectionCheckError, fmt.Sprintf("Failed to probe the privilege: %s", err.Error()))
+	case canCreate:
+		addCheck(api.ConnectionCheckPrivilege, createTitle, startTime, api.ConnectionCheckSuccess, "The user can create databases.")
+	default:
+		addCheck(api.ConnectionCheckPrivilege, createTitle, startTime, api.ConnectionCheckWarn, "The user can't create databases, creating databases in Bytebase will fail.")
+	}
+	return checkList
+}
+
+// checkDNS resolves the host, the IP address is returned as is.
+func checkDNS(ctx context.Context, host string) ([]string, api.ConnectionCheckStatus, string) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, api.ConnectionCheckSuccess, fmt.Sprintf("%s is an IP address.", host)
+	}
+	ctx, cancel := context.WithTimeout(ctx, connectionCheckTimeout)
+	defer cancel()
+	addressList, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, api.ConnectionCheckError, fmt.Sprintf("Host %q is not found.", host)
+		}
+		return nil, api.ConnectionCheckError, fmt.Sprintf("Failed to resolve host %q: %s", host, err.Error())
+	}
+	return addressList, api.ConnectionCheckSuccess, fmt.Sprintf("%s resolves to %s.", host, strings.Join(addressList, ", "))
+}
+
+// checkTCP connects to the port of the resolved addresses until one succeeds.
+func checkTCP(ctx context.Context, host, port string, addressList []string) (api.ConnectionCheckStatus, string) {
+	dialer := &net.Dialer{Timeout: connectionCheckTimeout}
+	var problemList []string
+	for _, address := range addressList {
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, port))
+		if err != nil {
+			problemList = append(problemList, fmt.Sprintf("%s: %s", net.JoinHostPort(address, port), getTCPProblem(err)))
+			continue
+		}
+		conn.Close()
+		return api.ConnectionCheckSuccess, fmt.Sprintf("Connected to %s.", net.JoinHostPort(address, port))
+	}
+	return api.ConnectionCheckError, fmt.Sprintf("Failed to connect to %s: %s.", net.JoinHostPort(host, port), strings.Join(problemList, "; "))
+}
+
+// getTCPProblem tells the common causes of the connection failure.
+func getTCPProblem(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connection refused, check the port and whether the server is listening on it"
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return "host unreachable, check the network route"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timed out, check the firewall and the security group"
+	}
+	return err.Error()
+}
+
+// checkTLS handshakes TLS with the server to report the TLS version, the cipher suite and the certificate.
+// The server not supporting TLS is a warning, because the connection still works in plain text.
+func checkTLS(ctx context.Context, engine db.Type, host, port string) (api.ConnectionCheckStatus, string) {
+	state, err := util.GetServerTLSState(ctx, engine, host, port)
+	if err != nil {
+		return api.ConnectionCheckError, err.Error()
+	}
+	if state == nil {
+		return api.ConnectionCheckWarn, "The server doesn't support TLS, the traffic is in plain text."
+	}
+	detail := fmt.Sprintf("%s with %s.", tlsVersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		detail += fmt.Sprintf(" Certificate %q is issued by %q and expires at %s.", cert.Subject.String(), cert.Issuer.String(), cert.NotAfter.UTC().Format(time.RFC3339))
+		if time.Now().After(cert.NotAfter) {
+			return api.ConnectionCheckWarn, detail + " The certificate has expired."
+		}
+	}
+	return api.ConnectionCheckSuccess, detail
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("TLS 0x%04x", version)
+}
+
+// canCreateDatabase returns true if the current user can create databases.
+func canCreateDatabase(ctx context.Context, engine db.Type, sqldb *sql.DB) (bool, error) {
+	switch engine {
+	case db.Postgres:
+		var super, createDB bool
+		if err := sqldb.QueryRowContext(ctx, "SELECT rolsuper, rolcreatedb FROM pg_roles WHERE rolname = current_user").Scan(&super, &createDB); err != nil {
+			return false, err
+		}
+		return super || createDB, nil
+	case db.MySQL, db.TiDB, db.MariaDB:
+		rows, err := sqldb.QueryContext(ctx, "SHOW GRANTS")
+		if err != nil {
+			return false, err
+		}
+		defer rows.Close()
+		canCreate := false
+		for rows.Next() {
+			var grant string
+			if err := rows.Scan(&grant); err != nil {
+				return false, err
+			}
+			if hasMySQLGlobalCreatePrivilege(grant) {
+				canCreate = true
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return false, err
+		}
+		return canCreate, nil
+	}
+	return false, fmt.Errorf("unsupported engine %s", engine)
+}
+
+// hasMySQLGlobalCreatePrivilege returns true if the SHOW GRANTS statement grants the CREATE privilege globally,
+// e.g. "GRANT ALL PRIVILEGES ON *.* TO `user`@`%`".
+func hasMySQLGlobalCreatePrivilege(grant string) bool {
+	grant = strings.ToUpper(strings.TrimSpace(grant))
+	if !strings.HasPrefix(grant, "GRANT ") {
+		return false
+	}
+	onIndex := strings.Index(grant, " ON ")
+	if onIndex == -1 || !strings.HasPrefix(grant[onIndex+len(" ON "):], "*.* ") {
+		return false
+	}
+	for _, privilege := range strings.Split(grant[len("GRANT "):onIndex], ",") {
+		privilege = strings.TrimSpace(privilege)
+		if privilege == "CREATE" || privilege == "ALL" || privilege == "ALL PRIVILEGES" {
+			return true
+		}
+	}
+	return false
+}
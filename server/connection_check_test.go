@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestHasMySQLGlobalCreatePrivilege(t *testing.T) {
+	tests := []struct {
+		grant string
+		want  bool
+	}{
+		{
+			grant: "GRANT USAGE ON *.* TO `ro`@`%`",
+			want:  false,
+		},
+		{
+			grant: "GRANT ALL PRIVILEGES ON *.* TO `root`@`localhost` WITH GRANT OPTION",
+			want:  true,
+		},
+		{
+			grant: "GRANT SELECT, CREATE, ALTER ON *.* TO `bytebase`@`%`",
+			want:  true,
+		},
+		{
+			grant: "GRANT CREATE ON `shop`.* TO `dev`@`%`",
+			want:  false,
+		},
+		{
+			grant: "GRANT CREATE VIEW, CREATE ROUTINE ON *.* TO `dev`@`%`",
+			want:  false,
+		},
+		{
+			grant: "GRANT `admin`@`%` TO `dev`@`%`",
+			want:  false,
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, hasMySQLGlobalCreatePrivilege(test.grant), test.grant)
+	}
+}
+
+func TestCheckTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	addressList, status, _ := checkDNS(ctx, "127.0.0.1")
+	require.Equal(t, api.ConnectionCheckSuccess, status)
+	require.Equal(t, []string{"127.0.0.1"}, addressList)
+
+	status, _ = checkTCP(ctx, "127.0.0.1", port, addressList)
+	require.Equal(t, api.ConnectionCheckSuccess, status)
+
+	require.NoError(t, listener.Close())
+	status, detail := checkTCP(ctx, "127.0.0.1", port, addressList)
+	require.Equal(t, api.ConnectionCheckError, status)
+	require.Contains(t, detail, "connection refused")
+}
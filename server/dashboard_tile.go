@@ -0,0 +1,407 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func (s *Server) registerDashboardTileRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/dashboard-tile", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		if err := s.checkDashboardAccess(ctx, c, projectID, false /* manage */); err != nil {
+			return err
+		}
+
+		tileList, err := s.store.FindDashboardTile(ctx, &api.DashboardTileFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch dashboard tile list for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, tileList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal dashboard tile list response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/project/:projectID/dashboard-tile", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		if err := s.checkDashboardAccess(ctx, c, projectID, true /* manage */); err != nil {
+			return err
+		}
+
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+		}
+		if project.RowStatus == api.Archived {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %q is archived", project.Name))
+		}
+
+		tileCreate := &api.DashboardTileCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, tileCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create dashboard tile request").SetInternal(err)
+		}
+		tileCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		tileCreate.ProjectID = projectID
+
+		if tileCreate.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create dashboard tile request, missing name")
+		}
+		if err := s.validateDashboardTile(ctx, projectID, tileCreate.DatabaseID, tileCreate.Statement, tileCreate.Parameters, tileCreate.Refresh); err != nil {
+			return err
+		}
+
+		tile, err := s.store.CreateDashboardTile(ctx, tileCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Dashboard tile %q already exists in project %q", tileCreate.Name, project.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create dashboard tile").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, tile); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create dashboard tile response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/dashboard-tile/:tileID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		tile, err := s.getDashboardTileFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+		if err := s.checkDashboardAccess(ctx, c, tile.ProjectID, true /* manage */); err != nil {
+			return err
+		}
+
+		tilePatch := &api.DashboardTilePatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, tilePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch dashboard tile request").SetInternal(err)
+		}
+		tilePatch.ID = tile.ID
+		tilePatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+
+		if v := tilePatch.Name; v != nil && *v == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch dashboard tile request, empty name")
+		}
+		databaseID, statement, parameters, refresh := tile.DatabaseID, tile.Statement, tile.Parameters, tile.Refresh
+		if v := tilePatch.DatabaseID; v != nil {
+			databaseID = *v
+		}
+		if v := tilePatch.Statement; v != nil {
+			statement = *v
+		}
+		if v := tilePatch.Parameters; v != nil {
+			parameters = *v
+		}
+		if v := tilePatch.Refresh; v != nil {
+			refresh = api.DashboardTileRefresh(*v)
+		}
+		if err := s.validateDashboardTile(ctx, tile.ProjectID, databaseID, statement, parameters, refresh); err != nil {
+			return err
+		}
+		// The cached result is stale once the query changes.
+		if databaseID != tile.DatabaseID || statement != tile.Statement || parameters != tile.Parameters {
+			empty, never := "", int64(0)
+			tilePatch.Result, tilePatch.ResultError, tilePatch.RefreshedTs = &empty, &empty, &never
+		}
+
+		tile, err = s.store.PatchDashboardTile(ctx, tilePatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, "Dashboard tile name already exists in the project")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch dashboard tile ID: %v", tilePatch.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, tile); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch dashboard tile response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/project/:projectID/dashboard-tile/:tileID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		tile, err := s.getDashboardTileFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+		if err := s.checkDashboardAccess(ctx, c, tile.ProjectID, true /* manage */); err != nil {
+			return err
+		}
+
+		tileDelete := &api.DashboardTileDelete{
+			ID:        tile.ID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteDashboardTile(ctx, tileDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete dashboard tile ID: %v", tile.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+
+	// Run returns the tile with the cached result, which is refreshed first if it has expired or the refresh is forced.
+	// If any parameter value is overridden, the query runs with the values and the result is returned without being cached.
+	g.POST("/project/:projectID/dashboard-tile/:tileID/run", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		tile, err := s.getDashboardTileFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+		if err := s.checkDashboardAccess(ctx, c, tile.ProjectID, false /* manage */); err != nil {
+			return err
+		}
+
+		tileRun := &api.DashboardTileRun{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, tileRun); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed run dashboard tile request").SetInternal(err)
+		}
+		overrideMap, err := api.ParseDashboardTileParameters(tileRun.Parameters)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		defaultMap, err := api.ParseDashboardTileParameters(tile.Parameters)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to parse the parameters of dashboard tile ID: %v", tile.ID)).SetInternal(err)
+		}
+
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		now := time.Now()
+		if len(overrideMap) > 0 {
+			parameterMap, err := api.MergeDashboardTileParameters(defaultMap, overrideMap)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			result, queryErr, err := s.runDashboardTile(ctx, tile, parameterMap, principalID)
+			if err != nil {
+				return err
+			}
+			tile.Result, tile.ResultError, tile.RefreshedTs = result, "", now.Unix()
+			if queryErr != nil {
+				tile.ResultError = queryErr.Error()
+			}
+		} else if tileRun.Force || tile.IsResultExpired(now) {
+			if tile, err = s.refreshDashboardTile(ctx, tile, defaultMap, principalID); err != nil {
+				return err
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, tile); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal run dashboard tile response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getDashboardTileFromParam returns the tile specified by the ":projectID" and ":tileID" params.
+// The returned error is an echo HTTP error.
+func (s *Server) getDashboardTileFromParam(ctx context.Context, c echo.Context) (*api.DashboardTile, error) {
+	projectID, err := strconv.Atoi(c.Param("projectID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+	}
+	tileID, err := strconv.Atoi(c.Param("tileID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Dashboard tile ID is not a number: %s", c.Param("tileID"))).SetInternal(err)
+	}
+	tile, err := s.store.GetDashboardTileByID(ctx, tileID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch dashboard tile ID: %v", tileID)).SetInternal(err)
+	}
+	if tile == nil || tile.ProjectID != projectID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Dashboard tile not found by ID %d and project ID %d", tileID, projectID))
+	}
+	return tile, nil
+}
+
+// checkDashboardAccess checks whether the current principal can view or manage the dashboard of the project.
+// The workspace owners and DBAs manage all dashboards and the auditors view them, while the project members view
+// the dashboard of their projects and only the project owners manage it. The auditors only view the cached results,
+// as running the tiles isn't granted to them by the ACL policy. The returned error is an echo HTTP error.
+func (s *Server) checkDashboardAccess(ctx context.Context, c echo.Context, projectID int, manage bool) error {
+	role := c.Get(getRoleContextKey()).(api.Role)
+	if role == api.Owner || role == api.DBA || (role == api.Auditor && !manage) {
+		return nil
+	}
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	member, err := s.store.GetProjectMember(ctx, &api.ProjectMemberFind{ProjectID: &projectID, PrincipalID: &principalID})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the project member of project ID: %v", projectID)).SetInternal(err)
+	}
+	if member == nil {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the project members can access the dashboard of the project")
+	}
+	if manage && member.Role != string(common.ProjectOwner) {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the project owners can manage the dashboard of the project")
+	}
+	return nil
+}
+
+// validateDashboardTile validates the tile to create or patch. The returned error is an echo HTTP error.
+func (s *Server) validateDashboardTile(ctx context.Context, projectID, databaseID int, statement, parameters string, refresh api.DashboardTileRefresh) error {
+	if !validateSQLSelectStatement(statement) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Dashboard tile only supports a single SELECT statement")
+	}
+	if _, err := api.ParseDashboardTileParameters(parameters); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if err := api.ValidateDashboardTileRefresh(refresh); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &databaseID})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", databaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database ID not found: %d", databaseID))
+	}
+	if database.ProjectID != projectID {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q doesn't belong to the project", database.Name))
+	}
+	return nil
+}
+
+// refreshDashboardTile runs the tile with the default parameter values and caches the result.
+// The returned error is an echo HTTP error.
+func (s *Server) refreshDashboardTile(ctx context.Context, tile *api.DashboardTile, parameterMap map[string]string, principalID int) (*api.DashboardTile, error) {
+	result, queryErr, err := s.runDashboardTile(ctx, tile, parameterMap, principalID)
+	if err != nil {
+		return nil, err
+	}
+	resultError := ""
+	if queryErr != nil {
+		resultError = queryErr.Error()
+	}
+	refreshedTs := time.Now().Unix()
+	// The refresh doesn't change the updater of the tile.
+	refreshedTile, err := s.store.PatchDashboardTile(ctx, &api.DashboardTilePatch{
+		ID:          tile.ID,
+		UpdaterID:   tile.UpdaterID,
+		Result:      &result,
+		ResultError: &resultError,
+		RefreshedTs: &refreshedTs,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to cache the result of dashboard tile ID: %v", tile.ID)).SetInternal(err)
+	}
+	return refreshedTile, nil
+}
+
+// runDashboardTile runs the query of the tile with the parameter values through the SQL editor execution path,
+// which applies the read-only data source policy and the SQL editor query policy of the database, and records the query activity.
+// The failure of the query, e.g. an undefined variable or a timeout, is returned as queryErr to be shown on the tile,
+// while the returned error is an echo HTTP error. The concurrent queries of the principal are limited unless it's the system bot.
+func (s *Server) runDashboardTile(ctx context.Context, tile *api.DashboardTile, parameterMap map[string]string, principalID int) (result string, queryErr error, err error) {
+	database := tile.Database
+	if database == nil {
+		return "", fmt.Errorf("database ID %d not found", tile.DatabaseID), nil
+	}
+	if database.ProjectID != tile.ProjectID {
+		return "", fmt.Errorf("database %q has been transferred out of the project", database.Name), nil
+	}
+	instance := database.Instance
+
+	variableList, err := s.store.FindProjectVariable(ctx, &api.ProjectVariableFind{ProjectID: &tile.ProjectID})
+	if err != nil {
+		return "", nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch variable list for project ID: %d", tile.ProjectID)).SetInternal(err)
+	}
+	variableMap, err := api.GetStatementVariableMap(database.Name, database.Labels, variableList)
+	if err != nil {
+		return "", nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the statement variables of database %q", database.Name)).SetInternal(err)
+	}
+	statement, err := resolveDashboardTileStatement(instance.Engine, tile.Statement, variableMap, parameterMap)
+	if err != nil {
+		return "", err, nil
+	}
+	// The parameter values may turn the statement into something else than a single SELECT.
+	if !validateSQLSelectStatement(statement) {
+		return "", fmt.Errorf("dashboard tile only supports a single SELECT statement"), nil
+	}
+	if err := s.checkReadOnlyDataSourcePolicy(ctx, instance); err != nil {
+		if common.ErrorCode(err) == common.Invalid {
+			return "", err, nil
+		}
+		return "", nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check data source policy").SetInternal(err)
+	}
+	queryPolicy, err := s.getSQLEditorQueryPolicy(ctx, instance, database.Name)
+	if err != nil {
+		return "", nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch SQL editor query policy").SetInternal(err)
+	}
+	limit := api.DashboardTileMaxRowCount
+	if queryPolicy.AutoLimit > 0 {
+		statement = injectSQLEditorQueryLimit(instance.Engine, statement, queryPolicy.AutoLimit)
+		if queryPolicy.AutoLimit < limit {
+			limit = queryPolicy.AutoLimit
+		}
+	}
+
+	if principalID != api.SystemBotID {
+		if !s.acquireSQLEditorQuery(principalID, instance.EnvironmentID, queryPolicy.MaxConcurrentQueryPerUser) {
+			return "", nil, echo.NewHTTPError(http.StatusTooManyRequests, fmt.Sprintf("You already have %d running queries in environment %q, please retry after they finish", queryPolicy.MaxConcurrentQueryPerUser, instance.Environment.Name))
+		}
+		defer s.releaseSQLEditorQuery(principalID, instance.EnvironmentID)
+	}
+
+	start := time.Now().UnixNano()
+	bytes, queryErr := s.runSQLEditorQuery(ctx, instance, database.Name, statement, limit, queryPolicy)
+	level, errMessage := api.ActivityInfo, ""
+	if queryErr != nil {
+		level, errMessage = api.ActivityError, queryErr.Error()
+	}
+	if err := s.createSQLEditorQueryActivity(ctx, principalID, level, instance.ID, api.ActivitySQLEditorQueryPayload{
+		Statement:    statement,
+		DurationNs:   time.Now().UnixNano() - start,
+		InstanceName: instance.Name,
+		DatabaseName: database.Name,
+		Error:        errMessage,
+	}); err != nil {
+		return "", nil, err
+	}
+	if queryErr != nil {
+		return "", queryErr, nil
+	}
+	return string(bytes), nil, nil
+}
+
+// resolveDashboardTileStatement resolves the placeholders of the project variables and the parameters in the statement.
+// The parameter values can be overridden by any viewer, so they're substituted as the quoted string literals of the engine
+// rather than the raw SQL, e.g. WHERE id = {{ID}} becomes WHERE id = '1 UNION SELECT ...'.
+func resolveDashboardTileStatement(engine db.Type, statement string, variableMap, parameterMap map[string]string) (string, error) {
+	resolvedMap := make(map[string]string)
+	for name, value := range variableMap {
+		resolvedMap[name] = value
+	}
+	for name, value := range parameterMap {
+		resolvedMap[name] = quoteSQLString(engine, value)
+	}
+	return api.ResolveStatementVariables(statement, resolvedMap)
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	dashboardTileRefresherInterval = time.Duration(1) * time.Minute
+)
+
+// NewDashboardTileRefresher creates a dashboard tile refresher.
+func NewDashboardTileRefresher(server *Server) *DashboardTileRefresher {
+	return &DashboardTileRefresher{
+		server: server,
+	}
+}
+
+// DashboardTileRefresher refreshes the cached results of the dashboard tiles on their schedules.
+type DashboardTileRefresher struct {
+	server *Server
+}
+
+// Run will run the dashboard tile refresher.
+func (r *DashboardTileRefresher) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(dashboardTileRefresherInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Dashboard tile refresher started and will run every %v", dashboardTileRefresherInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						log.Error("Dashboard tile refresher PANIC RECOVER", zap.Error(err))
+					}
+				}()
+				r.refresh(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+// refresh refreshes the due tiles one by one as the system bot, so that the scheduled queries don't pile up on the databases.
+func (r *DashboardTileRefresher) refresh(ctx context.Context, now time.Time) {
+	tileList, err := r.server.store.FindDashboardTile(ctx, &api.DashboardTileFind{Scheduled: true})
+	if err != nil {
+		log.Error("Failed to find scheduled dashboard tile list", zap.Error(err))
+		return
+	}
+	for _, tile := range tileList {
+		if !tile.IsRefreshDue(now) {
+			continue
+		}
+		parameterMap, err := api.ParseDashboardTileParameters(tile.Parameters)
+		if err != nil {
+			log.Error("Failed to parse the parameters of dashboard tile",
+				zap.Int("dashboard_tile_id", tile.ID),
+				zap.Error(err))
+			continue
+		}
+		if _, err := r.server.refreshDashboardTile(ctx, tile, parameterMap, api.SystemBotID); err != nil {
+			log.Error("Failed to refresh dashboard tile",
+				zap.Int("dashboard_tile_id", tile.ID),
+				zap.String("dashboard_tile_name", tile.Name),
+				zap.Error(err))
+		}
+	}
+}
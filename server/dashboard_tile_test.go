@@ -0,0 +1,63 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestResolveDashboardTileStatement(t *testing.T) {
+	variableMap := map[string]string{
+		"TABLE": "orders",
+		"ID":    "0",
+	}
+	tests := []struct {
+		engine       db.Type
+		statement    string
+		parameterMap map[string]string
+		want         string
+	}{
+		{
+			engine:       db.Postgres,
+			statement:    "SELECT * FROM {{TABLE}} WHERE id = {{ID}}",
+			parameterMap: map[string]string{"ID": "1"},
+			want:         "SELECT * FROM orders WHERE id = '1'",
+		},
+		{
+			engine:       db.Postgres,
+			statement:    "SELECT * FROM {{TABLE}} WHERE id = {{ID}}",
+			parameterMap: map[string]string{"ID": "1 UNION SELECT password FROM data_source"},
+			want:         "SELECT * FROM orders WHERE id = '1 UNION SELECT password FROM data_source'",
+		},
+		{
+			engine:       db.Postgres,
+			statement:    "SELECT * FROM {{TABLE}} WHERE id = {{ID}}",
+			parameterMap: map[string]string{"ID": "1' OR '1'='1"},
+			want:         "SELECT * FROM orders WHERE id = '1'' OR ''1''=''1'",
+		},
+		{
+			// The backslash can't escape the closing quote in MySQL.
+			engine:       db.MySQL,
+			statement:    "SELECT * FROM {{TABLE}} WHERE id = {{ID}}",
+			parameterMap: map[string]string{"ID": `\' UNION SELECT authentication_string FROM mysql.user -- `},
+			want:         `SELECT * FROM orders WHERE id = '\\'' UNION SELECT authentication_string FROM mysql.user -- '`,
+		},
+		{
+			// The project variables aren't quoted.
+			engine:       db.Postgres,
+			statement:    "SELECT * FROM {{TABLE}} WHERE id = {{ID}}",
+			parameterMap: map[string]string{},
+			want:         "SELECT * FROM orders WHERE id = 0",
+		},
+	}
+	for _, test := range tests {
+		got, err := resolveDashboardTileStatement(test.engine, test.statement, variableMap, test.parameterMap)
+		require.NoError(t, err)
+		require.Equal(t, test.want, got)
+	}
+
+	_, err := resolveDashboardTileStatement(db.Postgres, "SELECT * FROM {{TABLE}} WHERE id = {{MISSING}}", variableMap, nil)
+	require.Error(t, err)
+}
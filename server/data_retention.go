@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerDataRetentionRoutes(g *echo.Group) {
+	// Erasing a departed user moves the ownership to the successor and anonymizes the personal data, it can't be undone.
+	g.POST("/principal/:principalID/erase", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+		erase := &api.PrincipalErase{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, erase); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed erase principal request").SetInternal(err)
+		}
+		if erase.ID == api.SystemBotID {
+			return echo.NewHTTPError(http.StatusBadRequest, "The system bot cannot be erased")
+		}
+		if erase.SuccessorID == erase.ID {
+			return echo.NewHTTPError(http.StatusBadRequest, "The successor must be another user")
+		}
+		successor, err := s.store.GetMemberByPrincipalID(ctx, erase.SuccessorID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find member with principal ID: %d", erase.SuccessorID)).SetInternal(err)
+		}
+		if successor == nil || successor.RowStatus != api.Normal {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The successor ID %d must be an active member", erase.SuccessorID))
+		}
+		member, err := s.store.GetMemberByPrincipalID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find member with principal ID: %d", id)).SetInternal(err)
+		}
+		if member == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Member not found with principal ID: %d", id))
+		}
+
+		result, err := s.store.ErasePrincipal(ctx, erase)
+		if err != nil {
+			switch common.ErrorCode(err) {
+			case common.NotFound:
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			case common.Invalid:
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to erase principal ID: %d", id)).SetInternal(err)
+		}
+
+		bytes, err := json.Marshal(api.ActivityMemberErasePayload{
+			PrincipalID:     id,
+			SuccessorID:     result.SuccessorID,
+			ReassignedCount: result.ReassignedCount,
+			DeletedCount:    result.DeletedCount,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+			CreatorID:   erase.UpdaterID,
+			ContainerID: member.ID,
+			Type:        api.ActivityMemberErase,
+			Level:       api.ActivityInfo,
+			Payload:     string(bytes),
+		}, &ActivityMeta{}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after erasing member: %d", member.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, result); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal erase principal response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getDataRetentionSetting returns the workspace data retention setting, or the one keeping everything if it's not set.
+func (s *Server) getDataRetentionSetting(ctx context.Context) (*api.DataRetentionSetting, error) {
+	name := api.SettingWorkspaceDataRetention
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+	setting := &api.DataRetentionSetting{}
+	if len(settingList) == 0 {
+		return setting, nil
+	}
+	if err := json.Unmarshal([]byte(settingList[0].Value), setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data retention setting %q, error: %w", settingList[0].Value, err)
+	}
+	return setting, nil
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	dataRetentionPurgerInterval = time.Duration(1) * time.Hour
+)
+
+// NewDataRetentionPurger creates a data retention purger.
+func NewDataRetentionPurger(server *Server) *DataRetentionPurger {
+	return &DataRetentionPurger{
+		server: server,
+	}
+}
+
+// DataRetentionPurger purges the activities, the query history and the audit log after the retention days of the workspace data retention setting.
+type DataRetentionPurger struct {
+	server *Server
+}
+
+// Run will run the data retention purger.
+func (p *DataRetentionPurger) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(dataRetentionPurgerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Data retention purger started and will run every %v", dataRetentionPurgerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						log.Error("Data retention purger PANIC RECOVER", zap.Error(err))
+					}
+				}()
+				p.purge(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (p *DataRetentionPurger) purge(ctx context.Context, now time.Time) {
+	setting, err := p.server.getDataRetentionSetting(ctx)
+	if err != nil {
+		log.Error("Failed to get data retention setting", zap.Error(err))
+		return
+	}
+	for _, purge := range setting.GetActivityPurgeList(now.Unix()) {
+		deletedCount, err := p.server.store.PurgeActivity(ctx, purge)
+		if err != nil {
+			log.Error("Failed to purge activities",
+				zap.Any("typeList", purge.TypeList),
+				zap.Int64("createdBeforeTs", purge.CreatedBeforeTs),
+				zap.Error(err),
+			)
+			continue
+		}
+		if deletedCount > 0 {
+			log.Info("Purged activities",
+				zap.Any("typeList", purge.TypeList),
+				zap.Int64("createdBeforeTs", purge.CreatedBeforeTs),
+				zap.Int64("count", deletedCount),
+			)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// mysqlWritePrivilegeList is the list of MySQL privileges allowing to change the data or the schema.
+var mysqlWritePrivilegeList = []string{
+	"ALL",
+	"ALL PRIVILEGES",
+	"ALTER",
+	"ALTER ROUTINE",
+	"CREATE",
+	"CREATE ROUTINE",
+	"CREATE TABLESPACE",
+	"CREATE TEMPORARY TABLES",
+	"CREATE USER",
+	"CREATE VIEW",
+	"DELETE",
+	"DROP",
+	"EVENT",
+	"FILE",
+	"GRANT OPTION",
+	"INDEX",
+	"INSERT",
+	"LOCK TABLES",
+	"SUPER",
+	"TRIGGER",
+	"UPDATE",
+}
+
+// validateReadOnlyDataSource makes sure the read-only data source uses a separate account from the admin data source,
+// and probes the account doesn't hold any write privilege.
+func (s *Server) validateReadOnlyDataSource(ctx context.Context, instance *api.Instance, connCfg db.ConnectionConfig) error {
+	adminDataSource := api.DataSourceFromInstanceWithType(instance, api.Admin)
+	if adminDataSource != nil && adminDataSource.Username == connCfg.Username {
+		return common.Errorf(common.Invalid, "the read-only data source must use a different account from the admin data source %q", adminDataSource.Username)
+	}
+
+	// The server can't reach the instance behind an agent.
+	if instance.AgentID != nil {
+		return nil
+	}
+	if instance.Engine != db.MySQL && instance.Engine != db.TiDB && instance.Engine != db.MariaDB && instance.Engine != db.Postgres {
+		return nil
+	}
+
+	driver, err := getDatabaseDriver(
+		ctx,
+		instance.Engine,
+		db.DriverConfig{},
+		connCfg,
+		db.ConnectionContext{
+			EnvironmentName: instance.Environment.Name,
+			InstanceName:    instance.Name,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	database := connCfg.Database
+	if database == "" && instance.Engine == db.Postgres {
+		// Postgres always connects to a specific database.
+		database = "postgres"
+	}
+	sqldb, err := driver.GetDBConnection(ctx, database)
+	if err != nil {
+		return err
+	}
+	privilegeList, err := probeWritePrivilege(ctx, instance.Engine, sqldb)
+	if err != nil {
+		return fmt.Errorf("failed to probe the privileges of %q, error: %w", connCfg.Username, err)
+	}
+	if len(privilegeList) > 0 {
+		return common.Errorf(common.Invalid, "the read-only data source account %q has write privileges: %s", connCfg.Username, strings.Join(privilegeList, ", "))
+	}
+	return nil
+}
+
+// probeWritePrivilege returns the write privileges held by the current account.
+func probeWritePrivilege(ctx context.Context, engine db.Type, sqldb *sql.DB) ([]string, error) {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB:
+		rows, err := sqldb.QueryContext(ctx, "SHOW GRANTS")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var privilegeList []string
+		for rows.Next() {
+			var grant string
+			if err := rows.Scan(&grant); err != nil {
+				return nil, err
+			}
+			privilegeList = append(privilegeList, getMySQLWritePrivilegeList(grant)...)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return privilegeList, nil
+	case db.Postgres:
+		var privilegeList []string
+		var super, createDB, createRole bool
+		if err := sqldb.QueryRowContext(ctx, "SELECT rolsuper, rolcreatedb, rolcreaterole FROM pg_roles WHERE rolname = current_user").Scan(&super, &createDB, &createRole); err != nil {
+			return nil, err
+		}
+		if super {
+			privilegeList = append(privilegeList, "SUPERUSER")
+		}
+		if createDB {
+			privilegeList = append(privilegeList, "CREATEDB")
+		}
+		if createRole {
+			privilegeList = append(privilegeList, "CREATEROLE")
+		}
+
+		// Table privileges can only be inspected in the connected database.
+		rows, err := sqldb.QueryContext(ctx, `
+			SELECT DISTINCT privilege_type FROM information_schema.role_table_grants
+			WHERE grantee = current_user AND privilege_type IN ('INSERT', 'UPDATE', 'DELETE', 'TRUNCATE')
+			UNION
+			SELECT 'OWNER' FROM pg_tables
+			WHERE tableowner = current_user AND schemaname NOT IN ('pg_catalog', 'information_schema')
+		`)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var privilege string
+			if err := rows.Scan(&privilege); err != nil {
+				return nil, err
+			}
+			privilegeList = append(privilegeList, privilege)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return privilegeList, nil
+	}
+	return nil, nil
+}
+
+// getMySQLWritePrivilegeList returns the write privileges in a SHOW GRANTS statement,
+// e.g. "GRANT SELECT, INSERT ON `db`.* TO `user`@`%`" returns ["INSERT"].
+func getMySQLWritePrivilegeList(grant string) []string {
+	grant = strings.ToUpper(strings.TrimSpace(grant))
+	if !strings.HasPrefix(grant, "GRANT ") {
+		return nil
+	}
+	onIndex := strings.Index(grant, " ON ")
+	if onIndex == -1 {
+		// Role grant such as "GRANT `role`@`%` TO `user`@`%`".
+		return nil
+	}
+
+	var privilegeList []string
+	for _, privilege := range strings.Split(grant[len("GRANT "):onIndex], ",") {
+		// Strip the column list such as "UPDATE (`c1`)".
+		if i := strings.Index(privilege, "("); i != -1 {
+			privilege = privilege[:i]
+		}
+		privilege = strings.TrimSpace(privilege)
+		for _, write := range mysqlWritePrivilegeList {
+			if privilege == write {
+				privilegeList = append(privilegeList, privilege)
+				break
+			}
+		}
+	}
+	if strings.HasSuffix(grant, " WITH GRANT OPTION") {
+		privilegeList = append(privilegeList, "GRANT OPTION")
+	}
+	return privilegeList
+}
+
+// checkReadOnlyDataSourcePolicy checks the read-only operation on the instance is allowed by the data source policy of its environment.
+func (s *Server) checkReadOnlyDataSourcePolicy(ctx context.Context, instance *api.Instance) error {
+	policy, err := s.store.GetDataSourcePolicy(ctx, instance.EnvironmentID, api.GetPolicyTagList(instance, nil))
+	if err != nil {
+		return err
+	}
+	if policy.Value == api.DataSourcePolicyValueReadOnlyRequired && api.DataSourceFromInstanceWithType(instance, api.RO) == nil {
+		return common.Errorf(common.Invalid, "environment %q requires a read-only data source, but instance %q doesn't have one", instance.Environment.Name, instance.Name)
+	}
+	return nil
+}
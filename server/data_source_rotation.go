@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// registerDataSourceRotationRoutes registers the routes to rotate the data source credentials without downtime.
+// A rotation stages the new credential and validates it against the instance first. Committing the rotation
+// switches the data source to the new credential in a single transaction. Since the executors open the
+// connection from the data source on each run, the following runs pick up the new credential while the
+// in-flight runs finish with the old one, so the old credential should be revoked after the commit.
+func (s *Server) registerDataSourceRotationRoutes(g *echo.Group) {
+	g.POST("/database/:id/data-source/:dataSourceID/rotation", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		database, dataSource, err := s.getDataSourceFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		rotationCreate := &api.DataSourceRotationCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, rotationCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create data source rotation request").SetInternal(err)
+		}
+		if rotationCreate.Username == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Username is required for the rotated credential")
+		}
+		rotationCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		rotationCreate.DataSourceID = dataSource.ID
+
+		rotationCreate.Status = api.DataSourceRotationValidated
+		if err := s.validateRotatedCredential(ctx, database.Instance, dataSource, rotationCreate); err != nil {
+			if common.ErrorCode(err) == common.NotImplemented {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			rotationCreate.Status = api.DataSourceRotationFailed
+			rotationCreate.Error = err.Error()
+		}
+
+		rotation, err := s.store.CreateDataSourceRotation(ctx, rotationCreate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create data source rotation").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rotation); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create data source rotation response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/data-source/:dataSourceID/rotation", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		_, dataSource, err := s.getDataSourceFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		rotationList, err := s.store.FindDataSourceRotation(ctx, &api.DataSourceRotationFind{DataSourceID: &dataSource.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch rotation list for data source %d", dataSource.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rotationList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal data source rotation list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/database/:id/data-source/:dataSourceID/rotation/:rotationID/commit", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		database, dataSource, rotation, err := s.getDataSourceRotationFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+		if rotation.Status != api.DataSourceRotationValidated {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only %s rotation can be committed, the rotation is %s", api.DataSourceRotationValidated, rotation.Status))
+		}
+
+		updaterID := c.Get(getPrincipalIDContextKey()).(int)
+		committed, err := s.store.CommitDataSourceRotation(ctx, rotation, updaterID)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to commit data source rotation %d", rotation.ID)).SetInternal(err)
+		}
+		rotation = committed
+
+		payload, err := json.Marshal(api.ActivityDataSourceCredentialRotatePayload{
+			RotationID:     rotation.ID,
+			DataSourceID:   dataSource.ID,
+			DataSourceType: dataSource.Type,
+			OldUsername:    dataSource.Username,
+			NewUsername:    rotation.Username,
+			InstanceName:   database.Instance.Name,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		}
+		activityCreate := &api.ActivityCreate{
+			CreatorID:   updaterID,
+			ContainerID: database.InstanceID,
+			Type:        api.ActivityDataSourceCredentialRotate,
+			Level:       api.ActivityInfo,
+			Comment:     fmt.Sprintf("Rotated the credential of %s of instance %q.", api.DataSourceNameFromType(dataSource.Type), database.Instance.Name),
+			Payload:     string(payload),
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+			// The credential has been switched, so we only log the error.
+			log.Error("Failed to create activity after rotating the data source credential",
+				zap.Int("data_source_id", dataSource.ID),
+				zap.Int("rotation_id", rotation.ID),
+				zap.Error(err))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rotation); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal commit data source rotation response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/database/:id/data-source/:dataSourceID/rotation/:rotationID/cancel", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		_, _, rotation, err := s.getDataSourceRotationFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+		if rotation.Status != api.DataSourceRotationValidated && rotation.Status != api.DataSourceRotationFailed {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The rotation is already %s", rotation.Status))
+		}
+
+		rotation, err = s.store.PatchDataSourceRotationStatus(ctx, &api.DataSourceRotationStatusPatch{
+			ID:        rotation.ID,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+			Status:    api.DataSourceRotationCanceled,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to cancel data source rotation").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rotation); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal cancel data source rotation response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getDataSourceFromParam returns the database and the data source specified by the ":id" and ":dataSourceID" params.
+// The returned error is an echo HTTP error.
+func (s *Server) getDataSourceFromParam(ctx context.Context, c echo.Context) (*api.Database, *api.DataSource, error) {
+	databaseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database ID is not a number: %s", c.Param("id"))).SetInternal(err)
+	}
+	dataSourceID, err := strconv.Atoi(c.Param("dataSourceID"))
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Data source ID is not a number: %s", c.Param("dataSourceID"))).SetInternal(err)
+	}
+
+	// Because data source could use a wildcard database "*" as its database,
+	// so we need to include wildcard databases when check if relevant database exists.
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &databaseID, IncludeAllDatabase: true})
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", databaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", databaseID))
+	}
+
+	dataSource, err := s.store.GetDataSource(ctx, &api.DataSourceFind{ID: &dataSourceID, DatabaseID: &databaseID})
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch data source by ID %d", dataSourceID)).SetInternal(err)
+	}
+	if dataSource == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("data source not found by ID %d and database ID %d", dataSourceID, databaseID))
+	}
+	return database, dataSource, nil
+}
+
+// getDataSourceRotationFromParam is similar to getDataSourceFromParam and additionally returns the rotation specified by the ":rotationID" param.
+func (s *Server) getDataSourceRotationFromParam(ctx context.Context, c echo.Context) (*api.Database, *api.DataSource, *api.DataSourceRotation, error) {
+	database, dataSource, err := s.getDataSourceFromParam(ctx, c)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rotationID, err := strconv.Atoi(c.Param("rotationID"))
+	if err != nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Rotation ID is not a number: %s", c.Param("rotationID"))).SetInternal(err)
+	}
+	rotation, err := s.store.GetDataSourceRotation(ctx, &api.DataSourceRotationFind{ID: &rotationID, DataSourceID: &dataSource.ID})
+	if err != nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch data source rotation by ID %d", rotationID)).SetInternal(err)
+	}
+	if rotation == nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Rotation not found by ID %d and data source ID %d", rotationID, dataSource.ID))
+	}
+	return database, dataSource, rotation, nil
+}
+
+// validateRotatedCredential connects to the instance with the staged credential.
+// The read-only data source additionally requires the new account doesn't hold any write privilege.
+func (s *Server) validateRotatedCredential(ctx context.Context, instance *api.Instance, dataSource *api.DataSource, create *api.DataSourceRotationCreate) error {
+	// The staged credential can't be validated if the server can't reach the instance.
+	if instance.AgentID != nil {
+		return common.Errorf(common.NotImplemented, "rotating the credential of the instance %q behind a runner agent is not supported", instance.Name)
+	}
+
+	connCfg := db.ConnectionConfig{
+		Username: create.Username,
+		Password: create.Password,
+		Host:     instance.Host,
+		Port:     instance.Port,
+		TLSConfig: db.TLSConfig{
+			SslCA:   create.SslCa,
+			SslCert: create.SslCert,
+			SslKey:  create.SslKey,
+		},
+	}
+	driver, err := getDatabaseDriver(
+		ctx,
+		instance.Engine,
+		db.DriverConfig{},
+		connCfg,
+		db.ConnectionContext{
+			EnvironmentName: instance.Environment.Name,
+			InstanceName:    instance.Name,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+	if err := driver.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping instance %q with user %q, error: %w", instance.Name, create.Username, err)
+	}
+
+	if dataSource.Type == api.RO {
+		return s.validateReadOnlyDataSource(ctx, instance, connCfg)
+	}
+	return nil
+}
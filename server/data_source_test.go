@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMySQLWritePrivilegeList(t *testing.T) {
+	tests := []struct {
+		grant string
+		want  []string
+	}{
+		{
+			grant: "GRANT USAGE ON *.* TO `ro`@`%`",
+			want:  nil,
+		},
+		{
+			grant: "GRANT SELECT, SHOW VIEW ON `shop`.* TO `ro`@`%`",
+			want:  nil,
+		},
+		{
+			grant: "GRANT SELECT, INSERT, UPDATE (`name`) ON `shop`.* TO `ro`@`%`",
+			want:  []string{"INSERT", "UPDATE"},
+		},
+		{
+			grant: "GRANT ALL PRIVILEGES ON *.* TO `root`@`localhost` WITH GRANT OPTION",
+			want:  []string{"ALL PRIVILEGES", "GRANT OPTION"},
+		},
+		{
+			grant: "GRANT `app_reader`@`%` TO `ro`@`%`",
+			want:  nil,
+		},
+		{
+			grant: "grant create temporary tables on `shop`.* to `ro`@`%`",
+			want:  []string{"CREATE TEMPORARY TABLES"},
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, getMySQLWritePrivilegeList(test.grant), test.grant)
+	}
+}
@@ -47,6 +47,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if project.TenantMode == api.TenantModeTenant && !s.feature(api.FeatureMultiTenancy) {
 			return echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
 		}
+		if err := s.validateProjectEnvironment(ctx, project, instance.EnvironmentID); err != nil {
+			return err
+		}
 		// Pre-validate database labels.
 		if databaseCreate.Labels != nil && *databaseCreate.Labels != "" {
 			if err := s.setDatabaseLabels(ctx, *databaseCreate.Labels, &api.Database{Name: databaseCreate.Name, Instance: instance} /* dummy database */, project, databaseCreate.CreatorID, true /* validateOnly */); err != nil {
@@ -94,6 +97,12 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			syncStatus := api.SyncStatus(syncStatusStr)
 			databaseFind.SyncStatus = &syncStatus
 		}
+		// The purged databases are excluded unless requested explicitly.
+		rowStatus := api.Normal
+		if rowStatusStr := c.QueryParam("rowStatus"); rowStatusStr != "" {
+			rowStatus = api.RowStatus(rowStatusStr)
+		}
+		databaseFind.RowStatus = &rowStatus
 		projectIDStr := c.QueryParams().Get("project")
 		if projectIDStr != "" {
 			projectID, err := strconv.Atoi(projectIDStr)
@@ -114,7 +123,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		// project where the caller is a member of.
 		// Looking from the UI perspective:
 		// - The database list left sidebar will only return databases related to the caller regardless of the caller's role.
-		// - The database list on the instance page will return all databases if the caller is Owner or DBA, but will only return
+		// - The database list on the instance page will return all databases if the caller is Owner, DBA or Auditor, but will only return
 		//   related databases if the caller is Developer.
 		if projectIDStr == "" && (databaseFind.InstanceID == nil || role == api.Developer) {
 			principalID := c.Get(getPrincipalIDContextKey()).(int)
@@ -188,6 +197,14 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
 		}
 
+		if v := dbPatch.TagList; v != nil {
+			tagList, err := api.NormalizeTagList(*v)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid tag list: %v", err)).SetInternal(err)
+			}
+			dbPatch.TagList = &tagList
+		}
+
 		targetProject := database.Project
 		if dbPatch.ProjectID != nil && *dbPatch.ProjectID != database.ProjectID {
 			// Before updating the database projectID, we first need to check if there are still bound sheets.
@@ -207,6 +224,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", *dbPatch.ProjectID))
 			}
 			targetProject = toProject
+			if err := s.validateProjectEnvironment(ctx, toProject, database.Instance.EnvironmentID); err != nil {
+				return err
+			}
 
 			if toProject.TenantMode == api.TenantModeTenant {
 				if !s.feature(api.FeatureMultiTenancy) {
@@ -240,7 +260,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 					if _, err := driver.Dump(ctx, database.Name, &schemaBuf, true /* schemaOnly */); err != nil {
 						return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get database schema for database %q", database.Name)).SetInternal(err)
 					}
-					if peerSchema != schemaBuf.String() {
+					if !isStatementEqual(database.Instance.Engine, peerSchema, schemaBuf.String()) {
 						return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The schema for database %q does not match the peer database schema in the target tenant mode project %q", database.Name, toProject.Name))
 					}
 				}
@@ -371,6 +391,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			}
 			table.IndexList = indexList
 		}
+		if err := s.applySchemaDescription(ctx, id, tableList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema description list for database id: %d", id)).SetInternal(err)
+		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, tableList); err != nil {
@@ -427,6 +450,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch index list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
 		}
 		table.IndexList = indexList
+		if err := s.applySchemaDescription(ctx, id, []*api.Table{table}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema description list for database id: %d", id)).SetInternal(err)
+		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, table); err != nil {
@@ -479,6 +505,28 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.GET("/database/:id/function", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		dbFunctionFind := &api.DBFunctionFind{
+			DatabaseID: &id,
+		}
+		dbFunctionList, err := s.store.FindDBFunction(ctx, dbFunctionFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch dbFunction list for database ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dbFunctionList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal fetch dbFunction list response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.POST("/database/:id/backup", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		id, err := strconv.Atoi(c.Param("id"))
@@ -500,6 +548,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if database == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
 		}
+		if !api.GetInstanceCapabilities(database.Instance).Dump {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Backup is not supported for database %q of engine %s", database.Name, database.Instance.Engine))
+		}
 
 		backup, err := s.scheduleBackupTask(ctx, database, backupCreate.Name, backupCreate.Type, c.Get(getPrincipalIDContextKey()).(int))
 		if err != nil {
@@ -559,14 +610,17 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		}
 		backupSettingUpsert.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
 
-		db, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
 		}
-		if db == nil {
+		if database == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
 		}
-		backupSettingUpsert.EnvironmentID = db.Instance.Environment.ID
+		if backupSettingUpsert.Enabled && !api.GetInstanceCapabilities(database.Instance).Dump {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Backup is not supported for database %q of engine %s", database.Name, database.Instance.Engine))
+		}
+		backupSettingUpsert.EnvironmentID = database.Instance.Environment.ID
 
 		backupSetting, err := s.store.UpsertBackupSetting(ctx, backupSettingUpsert)
 		if err != nil {
@@ -680,6 +734,25 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		dataSourceCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
 		dataSourceCreate.DatabaseID = databaseID
 
+		if api.DataSourceFromInstanceWithType(database.Instance, dataSourceCreate.Type) != nil {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Instance %q already has a %s data source", database.Instance.Name, dataSourceCreate.Type))
+		}
+		if dataSourceCreate.Type == api.RO {
+			if err := s.validateReadOnlyDataSource(ctx, database.Instance, db.ConnectionConfig{
+				Username: dataSourceCreate.Username,
+				Password: dataSourceCreate.Password,
+				Host:     database.Instance.Host,
+				Port:     database.Instance.Port,
+				TLSConfig: db.TLSConfig{
+					SslCA:   dataSourceCreate.SslCa,
+					SslCert: dataSourceCreate.SslCert,
+					SslKey:  dataSourceCreate.SslKey,
+				},
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid read-only data source: %s", err.Error())).SetInternal(err)
+			}
+		}
+
 		dataSource, err := s.store.CreateDataSource(ctx, dataSourceCreate)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create data source").SetInternal(err)
@@ -752,6 +825,39 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			dataSourcePatch.Password = &password
 		}
 
+		credentialChanged := dataSourcePatch.Username != nil || dataSourcePatch.Password != nil || dataSourcePatch.SslCa != nil || dataSourcePatch.SslCert != nil || dataSourcePatch.SslKey != nil
+		if dataSourceOld.Type == api.RO && credentialChanged {
+			connCfg := db.ConnectionConfig{
+				Username: dataSourceOld.Username,
+				Password: dataSourceOld.Password,
+				Host:     database.Instance.Host,
+				Port:     database.Instance.Port,
+				TLSConfig: db.TLSConfig{
+					SslCA:   dataSourceOld.SslCa,
+					SslCert: dataSourceOld.SslCert,
+					SslKey:  dataSourceOld.SslKey,
+				},
+			}
+			if v := dataSourcePatch.Username; v != nil {
+				connCfg.Username = *v
+			}
+			if v := dataSourcePatch.Password; v != nil {
+				connCfg.Password = *v
+			}
+			if v := dataSourcePatch.SslCa; v != nil {
+				connCfg.TLSConfig.SslCA = *v
+			}
+			if v := dataSourcePatch.SslCert; v != nil {
+				connCfg.TLSConfig.SslCert = *v
+			}
+			if v := dataSourcePatch.SslKey; v != nil {
+				connCfg.TLSConfig.SslKey = *v
+			}
+			if err := s.validateReadOnlyDataSource(ctx, database.Instance, connCfg); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid read-only data source: %s", err.Error())).SetInternal(err)
+			}
+		}
+
 		dataSourceNew, err := s.store.PatchDataSource(ctx, dataSourcePatch)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update data source with ID %d", dataSourceID)).SetInternal(err)
@@ -838,7 +944,7 @@ func (s *Server) getAdminDatabaseDriver(ctx context.Context, instance *api.Insta
 		instance.Engine,
 		db.DriverConfig{
 			PgInstanceDir: s.pgInstanceDir,
-			ResourceDir:   common.GetResourceDir(s.profile.DataDir),
+			ResourceDir:   s.profile.ResourceDir,
 		},
 		connCfg,
 		db.ConnectionContext{
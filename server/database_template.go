@@ -0,0 +1,139 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func (s *Server) registerDatabaseTemplateRoutes(g *echo.Group) {
+	g.GET("/database-template", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find := &api.DatabaseTemplateFind{}
+		if engineStr := c.QueryParam("engine"); engineStr != "" {
+			engine := db.Type(engineStr)
+			find.Engine = &engine
+		}
+		templateList, err := s.store.FindDatabaseTemplate(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch database template list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, templateList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal database template list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/database-template", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		templateCreate := &api.DatabaseTemplateCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, templateCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create database template request").SetInternal(err)
+		}
+		templateCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+
+		if templateCreate.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Database template name must not be empty")
+		}
+		if err := api.ValidateDatabaseTemplate(templateCreate.Engine, templateCreate.Labels, templateCreate.ExtensionList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		template, err := s.store.CreateDatabaseTemplate(ctx, templateCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Database template %q already exists", templateCreate.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create database template").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, template); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create database template response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/database-template/:templateID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("templateID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Template ID is not a number: %s", c.Param("templateID"))).SetInternal(err)
+		}
+		template, err := s.store.GetDatabaseTemplateByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database template ID: %v", id)).SetInternal(err)
+		}
+		if template == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database template not found with ID %d", id))
+		}
+
+		templatePatch := &api.DatabaseTemplatePatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, templatePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch database template request").SetInternal(err)
+		}
+		templatePatch.ID = id
+		templatePatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+
+		if v := templatePatch.Name; v != nil && *v == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Database template name must not be empty")
+		}
+		labels, extensionList := template.Labels, template.ExtensionList
+		if v := templatePatch.Labels; v != nil {
+			labels = *v
+		}
+		if v := templatePatch.ExtensionList; v != nil {
+			extensionList = nil
+			if *v != "" {
+				extensionList = strings.Split(*v, ",")
+			}
+		}
+		if err := api.ValidateDatabaseTemplate(template.Engine, labels, extensionList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		template, err = s.store.PatchDatabaseTemplate(ctx, templatePatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Database template %q already exists", *templatePatch.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch database template ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, template); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch database template response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/database-template/:templateID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("templateID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Template ID is not a number: %s", c.Param("templateID"))).SetInternal(err)
+		}
+
+		templateDelete := &api.DatabaseTemplateDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteDatabaseTemplate(ctx, templateDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete database template ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
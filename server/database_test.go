@@ -1,10 +1,20 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/bytebase/bytebase/api"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestValidateDatabaseLabelList(t *testing.T) {
@@ -151,3 +161,47 @@ func TestValidateDatabaseLabelList(t *testing.T) {
 		}
 	}
 }
+
+func TestDatabaseCapabilitiesRedshift(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	e := echo.New()
+	apiGroup := e.Group("/api")
+	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(getPrincipalIDContextKey(), api.SystemBotID)
+			return next(c)
+		}
+	})
+	s.registerDatabaseRoutes(apiGroup)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The database is on the demo Postgres instance 6005, which is detected as Redshift by the instance sync.
+	database := newTestPgDatabase(ctx, t, s, "redshift_db")
+	dialect := db.DialectRedshift
+	instance, err := s.store.PatchInstance(ctx, &api.InstancePatch{ID: 6005, UpdaterID: api.SystemBotID, Dialect: &dialect})
+	require.NoError(t, err)
+	require.Equal(t, db.DialectRedshift, instance.Dialect)
+	require.False(t, api.GetInstanceCapabilities(instance).Dump)
+	require.True(t, api.GetInstanceCapabilities(instance).ReadOnlyQuery)
+
+	rec := do(http.MethodPost, fmt.Sprintf("/api/database/%d/backup", database.ID), fmt.Sprintf(`{"data":{"type":"backupCreate","attributes":{"databaseId":%d,"name":"backup","type":"MANUAL"}}}`, database.ID))
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	require.Contains(t, rec.Body.String(), "Backup is not supported")
+	rec = do(http.MethodPatch, fmt.Sprintf("/api/database/%d/backup-setting", database.ID), fmt.Sprintf(`{"data":{"type":"backupSettingUpsert","attributes":{"databaseId":%d,"enabled":true}}}`, database.ID))
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	require.Contains(t, rec.Body.String(), "Backup is not supported")
+
+	createContext, err := json.Marshal(api.CreateDatabaseContext{InstanceID: 6005, DatabaseName: "restored", CharacterSet: "UTF8", Owner: "root", BackupID: 1})
+	require.NoError(t, err)
+	_, err = s.getPipelineCreateForDatabaseCreate(ctx, &api.IssueCreate{ProjectID: api.DefaultProjectID, CreateContext: string(createContext)})
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok, err)
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+	require.Contains(t, httpErr.Message, "Restoring from a backup is not supported")
+}
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerDatabaseAssignmentRuleRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/db-assignment-rule", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		ruleList, err := s.store.FindDatabaseAssignmentRule(ctx, &api.DatabaseAssignmentRuleFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database assignment rule list for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, ruleList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database assignment rule list response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/project/:projectID/db-assignment-rule", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+		}
+		if project.RowStatus == api.Archived {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %q is archived", project.Name))
+		}
+
+		ruleCreate := &api.DatabaseAssignmentRuleCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, ruleCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create database assignment rule request").SetInternal(err)
+		}
+		ruleCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		ruleCreate.ProjectID = projectID
+
+		if err := s.validateDatabaseAssignmentRule(ctx, ruleCreate.InstanceID, ruleCreate.NamePattern, ruleCreate.LabelSelector); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid database assignment rule: %s", err.Error())).SetInternal(err)
+		}
+
+		rule, err := s.store.CreateDatabaseAssignmentRule(ctx, ruleCreate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create database assignment rule").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rule); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create database assignment rule response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/db-assignment-rule/:ruleID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		rule, err := s.getDatabaseAssignmentRuleFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		rulePatch := &api.DatabaseAssignmentRulePatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, rulePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch database assignment rule request").SetInternal(err)
+		}
+		rulePatch.ID = rule.ID
+		rulePatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+
+		// Validate the rule after applying the patch.
+		instanceID, namePattern, labelSelector := rule.InstanceID, rule.NamePattern, rule.LabelSelector
+		if v := rulePatch.InstanceID; v != nil {
+			instanceID = v
+			if *v == 0 {
+				instanceID = nil
+			}
+		}
+		if v := rulePatch.NamePattern; v != nil {
+			namePattern = *v
+		}
+		if v := rulePatch.LabelSelector; v != nil {
+			labelSelector = *v
+		}
+		if err := s.validateDatabaseAssignmentRule(ctx, instanceID, namePattern, labelSelector); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid database assignment rule: %s", err.Error())).SetInternal(err)
+		}
+
+		rule, err = s.store.PatchDatabaseAssignmentRule(ctx, rulePatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch database assignment rule ID: %v", rulePatch.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rule); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch database assignment rule response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/project/:projectID/db-assignment-rule/:ruleID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		rule, err := s.getDatabaseAssignmentRuleFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		ruleDelete := &api.DatabaseAssignmentRuleDelete{
+			ID:        rule.ID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteDatabaseAssignmentRule(ctx, ruleDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete database assignment rule ID: %v", rule.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// getDatabaseAssignmentRuleFromParam returns the rule specified by the ":projectID" and ":ruleID" params.
+// The returned error is an echo HTTP error.
+func (s *Server) getDatabaseAssignmentRuleFromParam(ctx context.Context, c echo.Context) (*api.DatabaseAssignmentRule, error) {
+	projectID, err := strconv.Atoi(c.Param("projectID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+	}
+	ruleID, err := strconv.Atoi(c.Param("ruleID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Rule ID is not a number: %s", c.Param("ruleID"))).SetInternal(err)
+	}
+	rule, err := s.store.GetDatabaseAssignmentRuleByID(ctx, ruleID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database assignment rule ID: %v", ruleID)).SetInternal(err)
+	}
+	if rule == nil || rule.ProjectID != projectID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database assignment rule not found by ID %d and project ID %d", ruleID, projectID))
+	}
+	return rule, nil
+}
+
+// validateDatabaseAssignmentRule validates the rule has at least one criterion and all the criteria are well-formed.
+func (s *Server) validateDatabaseAssignmentRule(ctx context.Context, instanceID *int, namePattern, labelSelector string) error {
+	selector, err := unmarshalDatabaseAssignmentLabelSelector(labelSelector)
+	if err != nil {
+		return err
+	}
+	if instanceID == nil && namePattern == "" && len(selector.MatchExpressions) == 0 {
+		return fmt.Errorf("at least one of the instance, the name pattern and the label selector is required")
+	}
+	if instanceID != nil {
+		instance, err := s.store.GetInstanceByID(ctx, *instanceID)
+		if err != nil {
+			return err
+		}
+		if instance == nil {
+			return common.Errorf(common.NotFound, "instance ID not found: %d", *instanceID)
+		}
+	}
+	if _, err := regexp.Compile(namePattern); err != nil {
+		return fmt.Errorf("invalid name pattern %q, error: %w", namePattern, err)
+	}
+	for _, expression := range selector.MatchExpressions {
+		switch expression.Operator {
+		case api.InOperatorType:
+			if len(expression.Values) == 0 {
+				return fmt.Errorf("label selector %q with operator %q requires values", expression.Key, expression.Operator)
+			}
+		case api.ExistsOperatorType:
+		default:
+			return fmt.Errorf("label selector %q has unsupported operator %q", expression.Key, expression.Operator)
+		}
+	}
+	return nil
+}
+
+func unmarshalDatabaseAssignmentLabelSelector(labelSelector string) (*api.LabelSelector, error) {
+	selector := &api.LabelSelector{}
+	if labelSelector == "" {
+		return selector, nil
+	}
+	if err := json.Unmarshal([]byte(labelSelector), selector); err != nil {
+		return nil, fmt.Errorf("invalid label selector %q, error: %w", labelSelector, err)
+	}
+	return selector, nil
+}
+
+// isDatabaseAssignmentRuleMatched returns whether the newly discovered database matches the rule.
+func isDatabaseAssignmentRuleMatched(rule *api.DatabaseAssignmentRule, instance *api.Instance, databaseName string) (bool, error) {
+	if rule.InstanceID != nil && *rule.InstanceID != instance.ID {
+		return false, nil
+	}
+	if rule.NamePattern != "" {
+		matched, err := regexp.MatchString(rule.NamePattern, databaseName)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	selector, err := unmarshalDatabaseAssignmentLabelSelector(rule.LabelSelector)
+	if err != nil {
+		return false, err
+	}
+	if len(selector.MatchExpressions) == 0 {
+		return true, nil
+	}
+	labels := map[string]string{
+		api.EnvironmentKeyName: instance.Environment.Name,
+	}
+	return isMatchExpressions(labels, selector.MatchExpressions), nil
+}
+
+// getAssignedProjectID returns the project of the first matching rule for the newly discovered database,
+// or the default project if no rule matches.
+func getAssignedProjectID(ruleList []*api.DatabaseAssignmentRule, instance *api.Instance, databaseName string) int {
+	for _, rule := range ruleList {
+		matched, err := isDatabaseAssignmentRuleMatched(rule, instance, databaseName)
+		if err != nil {
+			// The rule is validated upon creation, so we just skip the malformed rule.
+			continue
+		}
+		if matched {
+			return rule.ProjectID
+		}
+	}
+	return api.DefaultProjectID
+}
+
+// findActiveDatabaseAssignmentRuleList returns the rules of all the projects not archived.
+func (s *Server) findActiveDatabaseAssignmentRuleList(ctx context.Context) ([]*api.DatabaseAssignmentRule, error) {
+	ruleList, err := s.store.FindDatabaseAssignmentRule(ctx, &api.DatabaseAssignmentRuleFind{})
+	if err != nil {
+		return nil, err
+	}
+	var activeRuleList []*api.DatabaseAssignmentRule
+	for _, rule := range ruleList {
+		project, err := s.store.GetProjectByID(ctx, rule.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if project == nil || project.RowStatus == api.Archived {
+			continue
+		}
+		activeRuleList = append(activeRuleList, rule)
+	}
+	return activeRuleList, nil
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAssignedProjectID(t *testing.T) {
+	instanceID := 101
+	otherInstanceID := 102
+	ruleList := []*api.DatabaseAssignmentRule{
+		{
+			ProjectID:   1001,
+			InstanceID:  &otherInstanceID,
+			NamePattern: "^tenant_",
+		},
+		{
+			ProjectID:     1002,
+			NamePattern:   "^tenant_",
+			LabelSelector: `{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["Prod"]}]}`,
+		},
+		{
+			ProjectID:   1003,
+			InstanceID:  &instanceID,
+			NamePattern: "^tenant_",
+		},
+		{
+			ProjectID:   1004,
+			NamePattern: "_report$",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		instance     *api.Instance
+		databaseName string
+		want         int
+	}{
+		{
+			name:         "instance",
+			instance:     &api.Instance{ID: otherInstanceID, Environment: &api.Environment{Name: "Test"}},
+			databaseName: "tenant_a",
+			want:         1001,
+		},
+		{
+			name:         "label",
+			instance:     &api.Instance{ID: instanceID, Environment: &api.Environment{Name: "Prod"}},
+			databaseName: "tenant_a",
+			want:         1002,
+		},
+		{
+			name:         "firstMatch",
+			instance:     &api.Instance{ID: instanceID, Environment: &api.Environment{Name: "Test"}},
+			databaseName: "tenant_a",
+			want:         1003,
+		},
+		{
+			name:         "namePattern",
+			instance:     &api.Instance{ID: otherInstanceID, Environment: &api.Environment{Name: "Prod"}},
+			databaseName: "sales_report",
+			want:         1004,
+		},
+		{
+			name:         "default",
+			instance:     &api.Instance{ID: otherInstanceID, Environment: &api.Environment{Name: "Prod"}},
+			databaseName: "employee",
+			want:         api.DefaultProjectID,
+		},
+	}
+
+	for _, test := range tests {
+		got := getAssignedProjectID(ruleList, test.instance, test.databaseName)
+		assert.Equal(t, test.want, got, test.name)
+	}
+}
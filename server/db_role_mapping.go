@@ -0,0 +1,311 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// identifierRegexp matches the identifiers in a SQL statement.
+var identifierRegexp = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_$]*`)
+
+func (s *Server) registerDatabaseRoleMappingRoutes(g *echo.Group) {
+	g.GET("/instance/:instanceID/role-mapping", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		instanceID, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		mappingList, err := s.store.FindDatabaseRoleMapping(ctx, &api.DatabaseRoleMappingFind{InstanceID: &instanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database role mapping list for instance ID: %d", instanceID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, mappingList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database role mapping list response: %v", instanceID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/instance/:instanceID/role-mapping", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		instanceID, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+		instance, err := s.store.GetInstanceByID(ctx, instanceID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", instanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", instanceID))
+		}
+
+		mappingCreate := &api.DatabaseRoleMappingCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, mappingCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create database role mapping request").SetInternal(err)
+		}
+		mappingCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		mappingCreate.InstanceID = instanceID
+		mappingCreate.RoleName = strings.TrimSpace(mappingCreate.RoleName)
+		if mappingCreate.RoleName == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid database role mapping: role name is required")
+		}
+		if err := s.validateRoleMappingPrincipal(ctx, mappingCreate.PrincipalID); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid database role mapping: %s", err.Error())).SetInternal(err)
+		}
+
+		mapping, err := s.store.CreateDatabaseRoleMapping(ctx, mappingCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Role %q has already been mapped in instance %q", mappingCreate.RoleName, instance.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create database role mapping").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, mapping); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create database role mapping response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/instance/:instanceID/role-mapping/:mappingID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		mapping, err := s.getDatabaseRoleMappingFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		mappingPatch := &api.DatabaseRoleMappingPatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, mappingPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch database role mapping request").SetInternal(err)
+		}
+		mappingPatch.ID = mapping.ID
+		mappingPatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+		if v := mappingPatch.PrincipalID; v != nil {
+			if err := s.validateRoleMappingPrincipal(ctx, *v); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid database role mapping: %s", err.Error())).SetInternal(err)
+			}
+		}
+
+		mapping, err = s.store.PatchDatabaseRoleMapping(ctx, mappingPatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch database role mapping ID: %v", mappingPatch.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, mapping); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch database role mapping response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/instance/:instanceID/role-mapping/:mappingID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		mapping, err := s.getDatabaseRoleMappingFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		mappingDelete := &api.DatabaseRoleMappingDelete{
+			ID:        mapping.ID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteDatabaseRoleMapping(ctx, mappingDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete database role mapping ID: %v", mapping.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+
+	// The owned objects of a principal are the tables owned by the database roles mapped to the principal.
+	g.GET("/principal/:principalID/owned-object", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+
+		mappingList, err := s.store.FindDatabaseRoleMapping(ctx, &api.DatabaseRoleMappingFind{PrincipalID: &principalID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database role mapping list for principal ID: %d", principalID)).SetInternal(err)
+		}
+		tableList := []*api.Table{}
+		for _, mapping := range mappingList {
+			list, err := s.store.FindTable(ctx, &api.TableFind{
+				InstanceID: &mapping.InstanceID,
+				Owner:      &mapping.RoleName,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch tables owned by role %q in instance ID: %d", mapping.RoleName, mapping.InstanceID)).SetInternal(err)
+			}
+			tableList = append(tableList, list...)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, tableList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal owned object list response: %v", principalID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getDatabaseRoleMappingFromParam returns the mapping specified by the ":instanceID" and ":mappingID" params.
+// The returned error is an echo HTTP error.
+func (s *Server) getDatabaseRoleMappingFromParam(ctx context.Context, c echo.Context) (*api.DatabaseRoleMapping, error) {
+	instanceID, err := strconv.Atoi(c.Param("instanceID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+	}
+	mappingID, err := strconv.Atoi(c.Param("mappingID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Mapping ID is not a number: %s", c.Param("mappingID"))).SetInternal(err)
+	}
+	mapping, err := s.store.GetDatabaseRoleMappingByID(ctx, mappingID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database role mapping ID: %v", mappingID)).SetInternal(err)
+	}
+	if mapping == nil || mapping.InstanceID != instanceID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database role mapping not found by ID %d and instance ID %d", mappingID, instanceID))
+	}
+	return mapping, nil
+}
+
+// validateRoleMappingPrincipal validates the principal is an end user.
+func (s *Server) validateRoleMappingPrincipal(ctx context.Context, principalID int) error {
+	principal, err := s.store.GetPrincipalByID(ctx, principalID)
+	if err != nil {
+		return err
+	}
+	if principal == nil {
+		return common.Errorf(common.NotFound, "principal ID not found: %d", principalID)
+	}
+	if principal.Type != api.EndUser {
+		return fmt.Errorf("principal %q is not an end user", principal.Name)
+	}
+	return nil
+}
+
+// getOwnerReviewerIDList returns the principals owning the tables referenced by the statements of the pipeline.
+// The owners are the default reviewers of the issue.
+func (s *Server) getOwnerReviewerIDList(ctx context.Context, pipeline *api.Pipeline) ([]int, error) {
+	// Cache the role mappings by the instance ID since the tasks of a pipeline usually target a few instances.
+	roleMappingMap := make(map[int]map[string]int)
+	var reviewerIDList []int
+	for _, stage := range pipeline.StageList {
+		for _, task := range stage.TaskList {
+			if task.DatabaseID == nil {
+				continue
+			}
+			statement, err := getTaskStatement(task)
+			if err != nil {
+				return nil, err
+			}
+			if statement == "" {
+				continue
+			}
+
+			principalIDMap, ok := roleMappingMap[task.InstanceID]
+			if !ok {
+				mappingList, err := s.store.FindDatabaseRoleMapping(ctx, &api.DatabaseRoleMappingFind{InstanceID: &task.InstanceID})
+				if err != nil {
+					return nil, err
+				}
+				principalIDMap = make(map[string]int)
+				for _, mapping := range mappingList {
+					principalIDMap[mapping.RoleName] = mapping.PrincipalID
+				}
+				roleMappingMap[task.InstanceID] = principalIDMap
+			}
+			if len(principalIDMap) == 0 {
+				continue
+			}
+
+			tableList, err := s.store.FindTable(ctx, &api.TableFind{DatabaseID: task.DatabaseID})
+			if err != nil {
+				return nil, err
+			}
+			for _, table := range getReferencedTableList(statement, tableList) {
+				principalID, ok := principalIDMap[table.Owner]
+				if !ok {
+					continue
+				}
+				reviewerIDList = appendUniqueID(reviewerIDList, principalID)
+			}
+		}
+	}
+	return reviewerIDList, nil
+}
+
+// getTaskStatement returns the statement of the tasks changing the database, otherwise returns empty.
+func getTaskStatement(task *api.Task) (string, error) {
+	switch task.Type {
+	case api.TaskDatabaseSchemaUpdate:
+		payload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return "", fmt.Errorf("invalid database schema update payload: %w", err)
+		}
+		return payload.Statement, nil
+	case api.TaskDatabaseDataUpdate:
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return "", fmt.Errorf("invalid database data update payload: %w", err)
+		}
+		return payload.Statement, nil
+	case api.TaskDatabaseSchemaUpdateGhostSync:
+		payload := &api.TaskDatabaseSchemaUpdateGhostSyncPayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return "", fmt.Errorf("invalid gh-ost sync payload: %w", err)
+		}
+		return payload.Statement, nil
+	}
+	return "", nil
+}
+
+// getReferencedTableList returns the owned tables whose name appears as an identifier in the statement.
+// The table name may be qualified by the schema such as "public.t", in which case the unqualified name is matched.
+// It's a best-effort match without parsing the statement, so that it works for any engine and any statement.
+func getReferencedTableList(statement string, tableList []*api.Table) []*api.Table {
+	identifierMap := make(map[string]bool)
+	for _, identifier := range identifierRegexp.FindAllString(statement, -1) {
+		identifierMap[strings.ToLower(identifier)] = true
+	}
+	var referencedList []*api.Table
+	for _, table := range tableList {
+		if table.Owner == "" {
+			continue
+		}
+		name := table.Name
+		if i := strings.LastIndex(name, "."); i != -1 {
+			name = name[i+1:]
+		}
+		if identifierMap[strings.ToLower(name)] {
+			referencedList = append(referencedList, table)
+		}
+	}
+	return referencedList
+}
+
+func appendUniqueID(idList []int, id int) []int {
+	for _, v := range idList {
+		if v == id {
+			return idList
+		}
+	}
+	return append(idList, id)
+}
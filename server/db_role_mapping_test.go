@@ -0,0 +1,49 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReferencedTableList(t *testing.T) {
+	tableList := []*api.Table{
+		{Name: "public.orders", Owner: "sales"},
+		{Name: "public.users", Owner: "account"},
+		{Name: "public.user_tags", Owner: "account"},
+		{Name: "public.audit", Owner: ""},
+	}
+
+	tests := []struct {
+		statement string
+		want      []string
+	}{
+		{
+			statement: "ALTER TABLE orders ADD COLUMN note TEXT;",
+			want:      []string{"public.orders"},
+		},
+		{
+			statement: `UPDATE public."Users" SET name = 'a' WHERE id IN (SELECT user_id FROM orders);`,
+			want:      []string{"public.orders", "public.users"},
+		},
+		{
+			// The table names only appear as a part of other identifiers.
+			statement: "CREATE TABLE orders_archive (user_tags_id INT);",
+			want:      nil,
+		},
+		{
+			// The table without the owner is ignored.
+			statement: "DELETE FROM audit;",
+			want:      nil,
+		},
+	}
+
+	for _, test := range tests {
+		var nameList []string
+		for _, table := range getReferencedTableList(test.statement, tableList) {
+			nameList = append(nameList, table.Name)
+		}
+		assert.Equal(t, test.want, nameList, test.statement)
+	}
+}
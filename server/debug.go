@@ -1,6 +1,8 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/bytebase/bytebase/api"
@@ -8,6 +10,7 @@ import (
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func (s *Server) registerDebugRoutes(g *echo.Group) {
@@ -29,6 +32,110 @@ func (s *Server) registerDebugRoutes(g *echo.Group) {
 
 		return currentDebugState(c)
 	})
+
+	g.GET("/debug/log", currentLogConfig)
+
+	// Changes the log level and sinks at runtime, the changes are not persisted and are reset on restart.
+	g.PATCH("/debug/log", func(c echo.Context) error {
+		patch := &api.LogConfigPatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, patch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch log config request").SetInternal(err)
+		}
+
+		// Validate the whole patch before applying anything.
+		var level zapcore.Level
+		if patch.Level != nil {
+			if err := level.UnmarshalText([]byte(*patch.Level)); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid log level %q", *patch.Level)).SetInternal(err)
+			}
+		}
+		var subsystemLevel map[log.Subsystem]zapcore.Level
+		if patch.SubsystemLevel != nil {
+			levelText := make(map[log.Subsystem]string)
+			if err := json.Unmarshal([]byte(*patch.SubsystemLevel), &levelText); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed subsystem log level").SetInternal(err)
+			}
+			subsystemLevel = make(map[log.Subsystem]zapcore.Level)
+			for subsystem, text := range levelText {
+				if !isLogSubsystem(subsystem) {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown log subsystem %q, should be one of %v", subsystem, log.SubsystemList))
+				}
+				var l zapcore.Level
+				if err := l.UnmarshalText([]byte(text)); err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid log level %q for subsystem %q", text, subsystem)).SetInternal(err)
+				}
+				subsystemLevel[subsystem] = l
+			}
+		}
+		var sinkList []log.SinkConfig
+		if patch.Sink != nil {
+			if err := json.Unmarshal([]byte(*patch.Sink), &sinkList); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed log sink").SetInternal(err)
+			}
+			// The sinks are opened first as it's the only step that may fail.
+			if err := log.SetSinks(sinkList); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to set log sink: %v", err)).SetInternal(err)
+			}
+		}
+
+		if patch.Level != nil {
+			log.SetLevel(level)
+			s.e.Debug = level == zap.DebugLevel
+		}
+		if patch.SubsystemLevel != nil {
+			for _, subsystem := range log.SubsystemList {
+				var l *zapcore.Level
+				if v, ok := subsystemLevel[subsystem]; ok {
+					l = &v
+				}
+				if err := log.SetSubsystemLevel(subsystem, l); err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set log level for subsystem %q", subsystem)).SetInternal(err)
+				}
+			}
+		}
+
+		return currentLogConfig(c)
+	})
+}
+
+func isLogSubsystem(subsystem log.Subsystem) bool {
+	for _, s := range log.SubsystemList {
+		if s == subsystem {
+			return true
+		}
+	}
+	return false
+}
+
+func currentLogConfig(c echo.Context) error {
+	subsystemLevel := make(map[log.Subsystem]string)
+	for _, subsystem := range log.SubsystemList {
+		if l := log.SubsystemLevel(subsystem); l != nil {
+			subsystemLevel[subsystem] = l.String()
+		}
+	}
+	subsystemLevelJSON, err := json.Marshal(subsystemLevel)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal subsystem log level").SetInternal(err)
+	}
+	sinkList := log.Sinks()
+	if sinkList == nil {
+		sinkList = []log.SinkConfig{}
+	}
+	sinkJSON, err := json.Marshal(sinkList)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal log sink").SetInternal(err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	if err := jsonapi.MarshalPayload(c.Response().Writer, &api.LogConfig{
+		Level:          log.Level().String(),
+		SubsystemLevel: string(subsystemLevelJSON),
+		Sink:           string(sinkJSON),
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal log config response").SetInternal(err)
+	}
+	return nil
 }
 
 func currentDebugState(c echo.Context) error {
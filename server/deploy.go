@@ -76,6 +76,10 @@ func getDatabaseMatrixFromDeploymentSchedule(schedule *api.DeploymentSchedule, b
 		var matchedDatabaseList []int
 		// Loop over databaseList instead of idToLabels to get determinant results.
 		for _, database := range databaseList {
+			// Skip the databases no longer found on the instance.
+			if database.SyncStatus == api.NotFound || database.RowStatus == api.Archived {
+				continue
+			}
 			labels := idToLabels[database.ID]
 			// The tenant database should match the database name.
 			name, err := formatDatabaseName(baseDatabaseName, dbNameTemplate, labels)
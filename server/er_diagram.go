@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerERDiagramRoutes(g *echo.Group) {
+	g.GET("/database/:id/er-diagram", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		tableList, foreignKeyList, err := s.findTableListWithDetail(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table list for database id: %d", id)).SetInternal(err)
+		}
+
+		return c.JSON(http.StatusOK, buildERDiagram(database, tableList, foreignKeyList))
+	})
+}
+
+// findTableListWithDetail finds the tables of the database with their columns, indices and descriptions, and the foreign keys of the database.
+func (s *Server) findTableListWithDetail(ctx context.Context, databaseID int) ([]*api.Table, []*api.ForeignKey, error) {
+	tableList, err := s.store.FindTable(ctx, &api.TableFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, nil, err
+	}
+	columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, nil, err
+	}
+	indexList, err := s.store.FindIndex(ctx, &api.IndexFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, nil, err
+	}
+	foreignKeyList, err := s.store.FindForeignKey(ctx, &api.ForeignKeyFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, table := range tableList {
+		for _, column := range columnList {
+			if column.TableID == table.ID {
+				table.ColumnList = append(table.ColumnList, column)
+			}
+		}
+		for _, index := range indexList {
+			if index.TableID == table.ID {
+				table.IndexList = append(table.IndexList, index)
+			}
+		}
+	}
+	if err := s.applySchemaDescription(ctx, databaseID, tableList); err != nil {
+		return nil, nil, err
+	}
+	return tableList, foreignKeyList, nil
+}
+
+// buildERDiagram builds the ER diagram from the tables with their columns and indices, and the foreign keys of the database.
+func buildERDiagram(database *api.Database, tableList []*api.Table, foreignKeyList []*api.ForeignKey) *api.ERDiagram {
+	diagram := &api.ERDiagram{
+		DatabaseID:   database.ID,
+		DatabaseName: database.Name,
+		// Always return the empty list instead of null for a database without tables.
+		NodeList: []api.ERDiagramNode{},
+		EdgeList: []api.ERDiagramEdge{},
+	}
+
+	tableNameMap := make(map[int]string)
+	tableNameSet := make(map[string]bool)
+	for _, table := range tableList {
+		tableNameMap[table.ID] = table.Name
+		tableNameSet[table.Name] = true
+
+		primaryColumnMap := make(map[string]bool)
+		for _, index := range table.IndexList {
+			if index.Primary {
+				primaryColumnMap[index.Expression] = true
+			}
+		}
+		node := api.ERDiagramNode{
+			Table:      table.Name,
+			Comment:    getDescription(table.Description, table.Comment),
+			ColumnList: []api.ERDiagramColumn{},
+		}
+		for _, column := range table.ColumnList {
+			node.ColumnList = append(node.ColumnList, api.ERDiagramColumn{
+				Name:     column.Name,
+				Type:     column.Type,
+				Nullable: column.Nullable,
+				Primary:  primaryColumnMap[column.Name],
+			})
+		}
+		diagram.NodeList = append(diagram.NodeList, node)
+	}
+	sort.Slice(diagram.NodeList, func(i, j int) bool {
+		return diagram.NodeList[i].Table < diagram.NodeList[j].Table
+	})
+
+	// The foreign key list is sorted by the table and the position, so the columns of a foreign key are adjacent.
+	edgeMap := make(map[string]int)
+	for _, foreignKey := range foreignKeyList {
+		fromTable, ok := tableNameMap[foreignKey.TableID]
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s", fromTable, foreignKey.Name)
+		i, ok := edgeMap[key]
+		if !ok {
+			diagram.EdgeList = append(diagram.EdgeList, api.ERDiagramEdge{
+				Name:      foreignKey.Name,
+				FromTable: fromTable,
+				ToTable:   foreignKey.ReferencedTable,
+				OnDelete:  foreignKey.OnDelete,
+				OnUpdate:  foreignKey.OnUpdate,
+				External:  !tableNameSet[foreignKey.ReferencedTable],
+			})
+			i = len(diagram.EdgeList) - 1
+			edgeMap[key] = i
+		}
+		diagram.EdgeList[i].ColumnPairList = append(diagram.EdgeList[i].ColumnPairList, api.ERDiagramColumnPair{
+			FromColumn: foreignKey.Column,
+			ToColumn:   foreignKey.ReferencedColumn,
+		})
+	}
+	sort.SliceStable(diagram.EdgeList, func(i, j int) bool {
+		if diagram.EdgeList[i].FromTable != diagram.EdgeList[j].FromTable {
+			return diagram.EdgeList[i].FromTable < diagram.EdgeList[j].FromTable
+		}
+		return diagram.EdgeList[i].Name < diagram.EdgeList[j].Name
+	})
+
+	return diagram
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildERDiagram(t *testing.T) {
+	database := &api.Database{ID: 101, Name: "shop"}
+	tableList := []*api.Table{
+		{
+			ID:   2,
+			Name: "orders",
+			ColumnList: []*api.Column{
+				{Name: "id", Type: "int"},
+				{Name: "user_id", Type: "int"},
+				{Name: "tenant_id", Type: "int", Nullable: true},
+			},
+			IndexList: []*api.Index{
+				{Name: "PRIMARY", Expression: "id", Primary: true},
+			},
+		},
+		{
+			ID:      1,
+			Name:    "users",
+			Comment: "the users",
+			ColumnList: []*api.Column{
+				{Name: "id", Type: "int"},
+			},
+			IndexList: []*api.Index{
+				{Name: "PRIMARY", Expression: "id", Primary: true},
+			},
+		},
+	}
+	foreignKeyList := []*api.ForeignKey{
+		{TableID: 2, Name: "fk_user", Column: "user_id", Position: 1, ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "CASCADE", OnUpdate: "NO ACTION"},
+		{TableID: 2, Name: "fk_tenant", Column: "tenant_id", Position: 1, ReferencedTable: "account.tenant", ReferencedColumn: "id"},
+		{TableID: 2, Name: "fk_tenant", Column: "user_id", Position: 2, ReferencedTable: "account.tenant", ReferencedColumn: "owner_id"},
+	}
+
+	want := &api.ERDiagram{
+		DatabaseID:   101,
+		DatabaseName: "shop",
+		NodeList: []api.ERDiagramNode{
+			{
+				Table: "orders",
+				ColumnList: []api.ERDiagramColumn{
+					{Name: "id", Type: "int", Primary: true},
+					{Name: "user_id", Type: "int"},
+					{Name: "tenant_id", Type: "int", Nullable: true},
+				},
+			},
+			{
+				Table:   "users",
+				Comment: "the users",
+				ColumnList: []api.ERDiagramColumn{
+					{Name: "id", Type: "int", Primary: true},
+				},
+			},
+		},
+		EdgeList: []api.ERDiagramEdge{
+			{
+				Name:      "fk_tenant",
+				FromTable: "orders",
+				ToTable:   "account.tenant",
+				ColumnPairList: []api.ERDiagramColumnPair{
+					{FromColumn: "tenant_id", ToColumn: "id"},
+					{FromColumn: "user_id", ToColumn: "owner_id"},
+				},
+				External: true,
+			},
+			{
+				Name:      "fk_user",
+				FromTable: "orders",
+				ToTable:   "users",
+				ColumnPairList: []api.ERDiagramColumnPair{
+					{FromColumn: "user_id", ToColumn: "id"},
+				},
+				OnDelete: "CASCADE",
+				OnUpdate: "NO ACTION",
+			},
+		},
+	}
+	require.Equal(t, want, buildERDiagram(database, tableList, foreignKeyList))
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+// validateForeignKeys validates the foreign keys added with NOT VALID by the statement on Postgres.
+// Adding the foreign key with NOT VALID only takes a brief lock, and VALIDATE CONSTRAINT scans the existing rows
+// without blocking the writes, so the two steps together don't block the writes for long on large tables.
+// It returns the number of the validated foreign keys.
+func validateForeignKeys(ctx context.Context, server *Server, task *api.Task, statement string) (int, error) {
+	instance := task.Instance
+	if instance.Engine != db.Postgres {
+		return 0, nil
+	}
+	// The agent only runs the migrations and the read-only queries.
+	if instance.AgentID != nil {
+		log.Debug("Skip validating foreign keys for the instance managed by the agent", zap.String("instance", instance.Name))
+		return 0, nil
+	}
+
+	validationList, err := getForeignKeyValidationList(statement)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the statement for the foreign keys, error: %w", err)
+	}
+	if len(validationList) == 0 {
+		return 0, nil
+	}
+
+	databaseName := task.Database.Name
+	driver, err := server.getAdminDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return 0, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, databaseName)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, validation := range validationList {
+		// Each constraint is validated in its own transaction, so that the validated ones are kept if a later one fails.
+		if _, err := sqldb.ExecContext(ctx, validation); err != nil {
+			return count, fmt.Errorf("failed to run %q, error: %w", validation, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// getForeignKeyValidationList returns the VALIDATE CONSTRAINT statements for the foreign keys added with NOT VALID by the statement.
+// The foreign keys without names are skipped since their generated names are unknown.
+func getForeignKeyValidationList(statement string) ([]string, error) {
+	nodeList, err := parser.Parse(parser.Postgres, parser.Context{}, statement)
+	if err != nil {
+		return nil, err
+	}
+	quote := func(s string) string {
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+	}
+
+	var validationList []string
+	for _, node := range nodeList {
+		alter, ok := node.(*ast.AlterTableStmt)
+		if !ok {
+			continue
+		}
+		for _, item := range alter.AlterItemList {
+			add, ok := item.(*ast.AddConstraintStmt)
+			if !ok || add.Constraint.Type != ast.ConstraintTypeForeign || !add.Constraint.SkipValidation || add.Constraint.Name == "" {
+				continue
+			}
+			table := quote(add.Table.Name)
+			if add.Table.Schema != "" {
+				table = fmt.Sprintf("%s.%s", quote(add.Table.Schema), table)
+			}
+			validationList = append(validationList, fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s", table, quote(add.Constraint.Name)))
+		}
+	}
+	return validationList, nil
+}
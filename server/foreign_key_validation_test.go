@@ -0,0 +1,23 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	// Register the parser.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
+)
+
+func TestGetForeignKeyValidationList(t *testing.T) {
+	statement := `ALTER TABLE t1 ADD CONSTRAINT fk_t1_a FOREIGN KEY (a) REFERENCES t2 (id) NOT VALID;
+ALTER TABLE s.t3 ADD CONSTRAINT "fk_t3_""b" FOREIGN KEY (b) REFERENCES t2 (id) NOT VALID;
+ALTER TABLE t4 ADD CONSTRAINT fk_t4_c FOREIGN KEY (c) REFERENCES t2 (id);
+ALTER TABLE t5 ADD FOREIGN KEY (d) REFERENCES t2 (id) NOT VALID;`
+	validationList, err := getForeignKeyValidationList(statement)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`ALTER TABLE "t1" VALIDATE CONSTRAINT "fk_t1_a"`,
+		`ALTER TABLE "s"."t3" VALIDATE CONSTRAINT "fk_t3_""b"`,
+	}, validationList)
+}
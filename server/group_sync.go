@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+)
+
+// getGroupSyncSetting returns the workspace group sync setting, or the default one if it's not set.
+func (s *Server) getGroupSyncSetting(ctx context.Context) (*api.GroupSyncSetting, error) {
+	name := api.SettingWorkspaceGroupSync
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+	setting := &api.GroupSyncSetting{}
+	if len(settingList) == 0 {
+		return setting, nil
+	}
+	if err := json.Unmarshal([]byte(settingList[0].Value), setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal group sync setting %q, error: %w", settingList[0].Value, err)
+	}
+	return setting, nil
+}
+
+// syncGroupProjectMember keeps the project membership of the user in sync with the groups of the user in the VCS.
+// Only the members synced from the groups of the same VCS are changed, the members added by hand are left as is.
+func (s *Server) syncGroupProjectMember(ctx context.Context, setting *api.GroupSyncSetting, user *api.Principal, vcsID int, groupList []string) error {
+	roleMap := setting.GetGroupProjectRoleMap(vcsID, groupList)
+	lastSyncTs := time.Now().Unix()
+
+	roleProvider := api.ProjectRoleProviderBytebase
+	projectMemberList, err := s.store.FindProjectMember(ctx, &api.ProjectMemberFind{
+		PrincipalID:  &user.ID,
+		RoleProvider: &roleProvider,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find project members of user %d, error: %w", user.ID, err)
+	}
+	for _, projectMember := range projectMemberList {
+		payload := &api.ProjectMemberGroupSyncPayload{}
+		if err := json.Unmarshal([]byte(projectMember.Payload), payload); err != nil {
+			return fmt.Errorf("failed to unmarshal project member %d payload %q, error: %w", projectMember.ID, projectMember.Payload, err)
+		}
+		groupRole, ok := roleMap[projectMember.ProjectID]
+		delete(roleMap, projectMember.ProjectID)
+		if payload.VCSID != vcsID {
+			continue
+		}
+
+		if !ok {
+			if err := s.store.DeleteProjectMember(ctx, &api.ProjectMemberDelete{
+				ID:        projectMember.ID,
+				DeleterID: api.SystemBotID,
+			}); err != nil {
+				return fmt.Errorf("failed to delete project member %d, error: %w", projectMember.ID, err)
+			}
+			s.createGroupSyncActivity(ctx, projectMember.ProjectID, api.ActivityProjectMemberDelete,
+				fmt.Sprintf("Revoked %s from %s (%s). Because the user no longer belongs to the groups: %s.",
+					projectMember.Role, user.Name, user.Email, strings.Join(payload.GroupList, ", ")))
+			continue
+		}
+
+		bytes, err := json.Marshal(&api.ProjectMemberGroupSyncPayload{
+			VCSID:      vcsID,
+			GroupList:  groupRole.GroupList,
+			LastSyncTs: lastSyncTs,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal project member payload, error: %w", err)
+		}
+		role := string(groupRole.Role)
+		payloadStr := string(bytes)
+		if _, err := s.store.PatchProjectMember(ctx, &api.ProjectMemberPatch{
+			ID:        projectMember.ID,
+			UpdaterID: api.SystemBotID,
+			Role:      &role,
+			Payload:   &payloadStr,
+		}); err != nil {
+			return fmt.Errorf("failed to patch project member %d, error: %w", projectMember.ID, err)
+		}
+		if projectMember.Role != role {
+			s.createGroupSyncActivity(ctx, projectMember.ProjectID, api.ActivityProjectMemberRoleUpdate,
+				fmt.Sprintf("Changed %s (%s) from %s to %s (synced from the groups: %s).",
+					user.Name, user.Email, projectMember.Role, role, strings.Join(groupRole.GroupList, ", ")))
+		}
+	}
+
+	for projectID, groupRole := range roleMap {
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return fmt.Errorf("failed to find project %d, error: %w", projectID, err)
+		}
+		// The mappings to the deleted or archived projects are ignored.
+		if project == nil || project.RowStatus != api.Normal {
+			continue
+		}
+		bytes, err := json.Marshal(&api.ProjectMemberGroupSyncPayload{
+			VCSID:      vcsID,
+			GroupList:  groupRole.GroupList,
+			LastSyncTs: lastSyncTs,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal project member payload, error: %w", err)
+		}
+		if _, err := s.store.CreateProjectMember(ctx, &api.ProjectMemberCreate{
+			CreatorID:    api.SystemBotID,
+			ProjectID:    projectID,
+			Role:         groupRole.Role,
+			PrincipalID:  user.ID,
+			RoleProvider: api.ProjectRoleProviderBytebase,
+			Payload:      string(bytes),
+		}); err != nil {
+			return fmt.Errorf("failed to create project member of user %d in project %d, error: %w", user.ID, projectID, err)
+		}
+		s.createGroupSyncActivity(ctx, projectID, api.ActivityProjectMemberCreate,
+			fmt.Sprintf("Granted %s to %s (%s) (synced from the groups: %s).",
+				groupRole.Role, user.Name, user.Email, strings.Join(groupRole.GroupList, ", ")))
+	}
+	return nil
+}
+
+// syncLoginGroupProjectMember syncs the project membership of the user logged in via the VCS if any group of the VCS is mapped.
+// The login goes on if the sync fails, the project membership stays as of the last sync until the next login.
+func (s *Server) syncLoginGroupProjectMember(ctx context.Context, user *api.Principal, vcs *api.VCS, oauthCtx common.OauthContext) {
+	setting, err := s.getGroupSyncSetting(ctx)
+	if err != nil {
+		log.Error("Failed to get group sync setting", zap.Error(err))
+		return
+	}
+	if !setting.HasVCS(vcs.ID) {
+		return
+	}
+	groupList, err := vcsPlugin.Get(vcs.Type, vcsPlugin.ProviderConfig{}).FetchUserGroupList(ctx, oauthCtx, vcs.InstanceURL)
+	if err != nil {
+		log.Error("Failed to fetch user groups from VCS",
+			zap.String("email", user.Email),
+			zap.String("vcs", vcs.Name),
+			zap.Error(err))
+		return
+	}
+	if err := s.syncGroupProjectMember(ctx, setting, user, vcs.ID, groupList); err != nil {
+		log.Error("Failed to sync project members from VCS groups",
+			zap.String("email", user.Email),
+			zap.String("vcs", vcs.Name),
+			zap.Error(err))
+	}
+}
+
+func (s *Server) createGroupSyncActivity(ctx context.Context, projectID int, activityType api.ActivityType, comment string) {
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: projectID,
+		Type:        activityType,
+		Level:       api.ActivityInfo,
+		Comment:     comment,
+	}, &ActivityMeta{}); err != nil {
+		log.Warn("Failed to create project activity after syncing member from groups",
+			zap.Int("project_id", projectID),
+			zap.String("type", string(activityType)),
+			zap.Error(err))
+	}
+}
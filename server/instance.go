@@ -145,8 +145,26 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 
+		if v := instancePatch.AgentID; v != nil && *v != 0 {
+			agent, err := s.store.GetAgent(ctx, &api.AgentFind{ID: v})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get agent ID: %v", *v)).SetInternal(err)
+			}
+			if agent == nil || agent.RowStatus != api.Normal {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Agent ID not found: %d", *v))
+			}
+		}
+
+		if v := instancePatch.TagList; v != nil {
+			tagList, err := api.NormalizeTagList(*v)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid tag list: %v", err)).SetInternal(err)
+			}
+			instancePatch.TagList = &tagList
+		}
+
 		var instancePatched *api.Instance
-		if instancePatch.RowStatus != nil || instancePatch.Name != nil || instancePatch.ExternalLink != nil || instancePatch.Host != nil || instancePatch.Port != nil {
+		if instancePatch.RowStatus != nil || instancePatch.Name != nil || instancePatch.ExternalLink != nil || instancePatch.Host != nil || instancePatch.Port != nil || instancePatch.AgentID != nil || instancePatch.TagList != nil {
 			// Users can switch instance status from ARCHIVED to NORMAL.
 			// So we need to check the current instance count with NORMAL status for quota limitation.
 			if instancePatch.RowStatus != nil && *instancePatch.RowStatus == string(api.Normal) {
@@ -182,7 +200,8 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 		}
 
 		// Try immediately setup the migration schema, sync the engine version and schema after updating any connection related info.
-		if instancePatch.Host != nil || instancePatch.Port != nil {
+		// The instances behind a runner agent are unreachable from the server, they are set up and synced by the agent instead.
+		if (instancePatch.Host != nil || instancePatch.Port != nil) && instancePatched.AgentID == nil {
 			db, err := s.getAdminDatabaseDriver(ctx, instancePatched, "" /* databaseName */)
 			if err == nil {
 				defer db.Close(ctx)
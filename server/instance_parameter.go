@@ -0,0 +1,63 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerInstanceParameterRoutes(g *echo.Group) {
+	// Returns the latest synced value of each parameter.
+	g.GET("/instance/:instanceID/parameter", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		instanceID, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		parameterList, err := s.store.FindInstanceParameter(ctx, &api.InstanceParameterFind{
+			InstanceID: &instanceID,
+			Latest:     true,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch parameter list for instance ID: %d", instanceID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, parameterList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal parameter list response for instance ID: %d", instanceID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Returns the value changes of the parameters, optionally filtered by the parameter name.
+	g.GET("/instance/:instanceID/parameter/history", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		instanceID, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		find := &api.InstanceParameterFind{
+			InstanceID: &instanceID,
+		}
+		if name := c.QueryParams().Get("name"); name != "" {
+			find.Name = &name
+		}
+		parameterList, err := s.store.FindInstanceParameter(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch parameter history for instance ID: %d", instanceID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, parameterList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal parameter history response for instance ID: %d", instanceID)).SetInternal(err)
+		}
+		return nil
+	})
+}
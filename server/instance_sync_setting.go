@@ -0,0 +1,85 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerInstanceSyncSettingRoutes(g *echo.Group) {
+	g.GET("/instance/:instanceID/sync-setting", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		instanceID, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		setting, err := s.store.GetInstanceSyncSettingByInstanceID(ctx, instanceID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get sync setting for instance ID: %d", instanceID)).SetInternal(err)
+		}
+		if setting == nil {
+			// Returns the setting with UNKNOWN_ID to indicate the instance has no setting and syncs on the default schedule.
+			setting = &api.InstanceSyncSetting{
+				ID:                 api.UnknownID,
+				InstanceID:         instanceID,
+				IntervalMinutes:    api.DefaultSchemaSyncIntervalMinutes,
+				BlackoutWindowList: "[]",
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal get instance sync setting response: %v", instanceID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/instance/:instanceID/sync-setting", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		instanceID, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		instance, err := s.store.GetInstanceByID(ctx, instanceID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", instanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", instanceID))
+		}
+
+		upsert := &api.InstanceSyncSettingUpsert{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed set instance sync setting request").SetInternal(err)
+		}
+		upsert.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+		upsert.InstanceID = instanceID
+		if upsert.BlackoutWindowList == "" {
+			upsert.BlackoutWindowList = "[]"
+		}
+		if err := api.ValidateSchemaSyncInterval(upsert.IntervalMinutes); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if _, err := api.ParseSyncBlackoutWindowList(upsert.BlackoutWindowList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		setting, err := s.store.UpsertInstanceSyncSetting(ctx, upsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set sync setting for instance ID: %d", instanceID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal set instance sync setting response").SetInternal(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// checkInvalidObjects finds the invalid indexes and the NOT VALID constraints in the Postgres database after the migration,
+// e.g. the index left by a failed CREATE INDEX CONCURRENTLY, which isn't used by the queries but still slows down the writes.
+// The invalid object anomaly of the database is updated with the result, so it's tracked until resolved.
+func checkInvalidObjects(ctx context.Context, server *Server, task *api.Task) ([]*api.InvalidObject, error) {
+	instance := task.Instance
+	if instance.Engine != db.Postgres {
+		return nil, nil
+	}
+	// The agent only runs the migrations and the read-only queries.
+	if instance.AgentID != nil {
+		log.Debug("Skip checking invalid objects for the instance managed by the agent", zap.String("instance", instance.Name))
+		return nil, nil
+	}
+
+	databaseName := task.Database.Name
+	driver, err := server.getAdminDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	invalidObjectList, err := getPgInvalidObjectList(ctx, sqldb)
+	if err != nil {
+		return nil, err
+	}
+	server.syncInvalidObjectAnomaly(ctx, instance, task.Database, invalidObjectList)
+	return invalidObjectList, nil
+}
+
+// syncInvalidObjectAnomaly creates or updates the invalid object anomaly of the database, or archives it if there is no invalid object.
+func (s *Server) syncInvalidObjectAnomaly(ctx context.Context, instance *api.Instance, database *api.Database, invalidObjectList []*api.InvalidObject) {
+	if len(invalidObjectList) == 0 {
+		err := s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			DatabaseID: &database.ID,
+			Type:       api.AnomalyDatabaseInvalidObject,
+		})
+		if err != nil && common.ErrorCode(err) != common.NotFound {
+			log.Error("Failed to close anomaly",
+				zap.String("instance", instance.Name),
+				zap.String("database", database.Name),
+				zap.String("type", string(api.AnomalyDatabaseInvalidObject)),
+				zap.Error(err))
+		}
+		return
+	}
+
+	payload, err := json.Marshal(api.AnomalyDatabaseInvalidObjectPayload{
+		InvalidObjectList: invalidObjectList,
+	})
+	if err != nil {
+		log.Error("Failed to marshal anomaly payload",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.String("type", string(api.AnomalyDatabaseInvalidObject)),
+			zap.Error(err))
+		return
+	}
+	if _, err := s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		DatabaseID: &database.ID,
+		Type:       api.AnomalyDatabaseInvalidObject,
+		Payload:    string(payload),
+	}); err != nil {
+		log.Error("Failed to create anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.String("type", string(api.AnomalyDatabaseInvalidObject)),
+			zap.Error(err))
+	}
+}
+
+// getPgInvalidObjectList gets the invalid indexes and the NOT VALID constraints of the Postgres database.
+func getPgInvalidObjectList(ctx context.Context, sqldb *sql.DB) ([]*api.InvalidObject, error) {
+	query := `
+		SELECT 'INDEX', n.nspname || '.' || t.relname, c.relname, pg_catalog.pg_get_indexdef(i.indexrelid)
+		FROM pg_catalog.pg_index i
+		JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid
+		JOIN pg_catalog.pg_class t ON t.oid = i.indrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+		WHERE NOT i.indisvalid AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		UNION ALL
+		SELECT 'CONSTRAINT', n.nspname || '.' || t.relname, con.conname, pg_catalog.pg_get_constraintdef(con.oid)
+		FROM pg_catalog.pg_constraint con
+		JOIN pg_catalog.pg_class t ON t.oid = con.conrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+		WHERE NOT con.convalidated AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY 1, 2, 3`
+	rows, err := sqldb.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invalidObjectList []*api.InvalidObject
+	for rows.Next() {
+		var object api.InvalidObject
+		if err := rows.Scan(&object.Type, &object.Table, &object.Name, &object.Definition); err != nil {
+			return nil, err
+		}
+		invalidObjectList = append(invalidObjectList, &object)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return invalidObjectList, nil
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestCheckInvalidObjects(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	database := newTestPgDatabase(ctx, t, s, "invalid_object")
+	instance := database.Instance
+	task := &api.Task{
+		InstanceID: instance.ID,
+		Instance:   instance,
+		DatabaseID: &database.ID,
+		Database:   database,
+	}
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, database.Name)
+	require.NoError(t, err)
+	defer driver.Close(ctx)
+	sqlDB, err := driver.GetDBConnection(ctx, database.Name)
+	require.NoError(t, err)
+	exec := func(statement string) {
+		_, err := sqlDB.ExecContext(ctx, statement)
+		require.NoError(t, err)
+	}
+	findAnomalyList := func(rowStatus api.RowStatus) []*api.Anomaly {
+		anomalyType := api.AnomalyDatabaseInvalidObject
+		anomalyList, err := s.store.FindAnomaly(ctx, &api.AnomalyFind{
+			RowStatus:  &rowStatus,
+			DatabaseID: &database.ID,
+			Type:       &anomalyType,
+		})
+		require.NoError(t, err)
+		return anomalyList
+	}
+	requireAnomalyPayload := func(anomaly *api.Anomaly, want []*api.InvalidObject) {
+		payload := &api.AnomalyDatabaseInvalidObjectPayload{}
+		require.NoError(t, json.Unmarshal([]byte(anomaly.Payload), payload))
+		require.Equal(t, want, payload.InvalidObjectList)
+	}
+
+	exec("CREATE TABLE account (id INTEGER, email TEXT, balance INTEGER)")
+	exec("INSERT INTO account VALUES (1, 'a@example.com', -1), (2, 'a@example.com', 1)")
+
+	// No anomaly is created without the invalid object.
+	invalidObjectList, err := checkInvalidObjects(ctx, s, task)
+	require.NoError(t, err)
+	require.Empty(t, invalidObjectList)
+	require.Empty(t, findAnomalyList(api.Normal))
+
+	// The failed CREATE INDEX CONCURRENTLY leaves the INVALID index behind.
+	_, err = sqlDB.ExecContext(ctx, "CREATE UNIQUE INDEX CONCURRENTLY idx_account_email ON account (email)")
+	require.Error(t, err)
+	invalidIndex := &api.InvalidObject{
+		Type:       api.InvalidObjectIndex,
+		Table:      "public.account",
+		Name:       "idx_account_email",
+		Definition: "CREATE UNIQUE INDEX idx_account_email ON public.account USING btree (email)",
+	}
+	invalidObjectList, err = checkInvalidObjects(ctx, s, task)
+	require.NoError(t, err)
+	require.Equal(t, []*api.InvalidObject{invalidIndex}, invalidObjectList)
+	anomalyList := findAnomalyList(api.Normal)
+	require.Len(t, anomalyList, 1)
+	requireAnomalyPayload(anomalyList[0], []*api.InvalidObject{invalidIndex})
+	anomalyID := anomalyList[0].ID
+
+	// The active anomaly is updated with the NOT VALID constraint added later.
+	exec("ALTER TABLE account ADD CONSTRAINT account_balance_check CHECK (balance >= 0) NOT VALID")
+	invalidConstraint := &api.InvalidObject{
+		Type:       api.InvalidObjectConstraint,
+		Table:      "public.account",
+		Name:       "account_balance_check",
+		Definition: "CHECK ((balance >= 0)) NOT VALID",
+	}
+	invalidObjectList, err = checkInvalidObjects(ctx, s, task)
+	require.NoError(t, err)
+	require.Equal(t, []*api.InvalidObject{invalidConstraint, invalidIndex}, invalidObjectList)
+	anomalyList = findAnomalyList(api.Normal)
+	require.Len(t, anomalyList, 1)
+	require.Equal(t, anomalyID, anomalyList[0].ID)
+	requireAnomalyPayload(anomalyList[0], []*api.InvalidObject{invalidConstraint, invalidIndex})
+
+	// The anomaly is resolved once the invalid objects are fixed.
+	exec("DROP INDEX idx_account_email")
+	exec("UPDATE account SET balance = 0 WHERE balance < 0")
+	exec("ALTER TABLE account VALIDATE CONSTRAINT account_balance_check")
+	invalidObjectList, err = checkInvalidObjects(ctx, s, task)
+	require.NoError(t, err)
+	require.Empty(t, invalidObjectList)
+	require.Empty(t, findAnomalyList(api.Normal))
+	require.Len(t, findAnomalyList(api.Archived), 1)
+
+	// Checking again without the active anomaly is a no-op.
+	_, err = checkInvalidObjects(ctx, s, task)
+	require.NoError(t, err)
+	require.Empty(t, findAnomalyList(api.Normal))
+	require.Len(t, findAnomalyList(api.Archived), 1)
+
+	// The other engines are skipped.
+	mysqlInstance := *instance
+	mysqlInstance.Engine = db.MySQL
+	invalidObjectList, err = checkInvalidObjects(ctx, s, &api.Task{Instance: &mysqlInstance, Database: database})
+	require.NoError(t, err)
+	require.Nil(t, invalidObjectList)
+}
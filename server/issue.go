@@ -9,16 +9,29 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	metricAPI "github.com/bytebase/bytebase/metric"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/metric"
+	"github.com/bytebase/bytebase/plugin/parser"
 	"github.com/bytebase/bytebase/plugin/vcs"
 )
 
+const (
+	// idempotencyKeyHeader is the header of the client token for retrying the issue creation safely.
+	// The retried request with the same key returns the issue created by the first request instead of creating a new one.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader is set in the response of the retried request.
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
 func (s *Server) registerIssueRoutes(g *echo.Group) {
 	g.POST("/issue", func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -26,9 +39,40 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, issueCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create issue request").SetInternal(err)
 		}
+		creatorID := c.Get(getPrincipalIDContextKey()).(int)
+
+		if idempotencyKey := c.Request().Header.Get(idempotencyKeyHeader); idempotencyKey != "" && !issueCreate.ValidateOnly {
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s header exceeds %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
+			}
+			// The pipeline is created before the issue, so the concurrent requests with the same key are rejected
+			// instead of relying on the unique index, which would leave the pipeline of the losing request behind.
+			inFlightKey := fmt.Sprintf("%d/%s", creatorID, idempotencyKey)
+			if _, ok := s.issueIdempotencyKeyInFlight.LoadOrStore(inFlightKey, true); ok {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Another request with %s %q is in progress", idempotencyKeyHeader, idempotencyKey))
+			}
+			defer s.issueIdempotencyKeyInFlight.Delete(inFlightKey)
+
+			issue, err := s.store.GetIssueByIdempotencyKey(ctx, creatorID, idempotencyKey)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue with %s %q", idempotencyKeyHeader, idempotencyKey)).SetInternal(err)
+			}
+			if issue != nil {
+				c.Response().Header().Set(idempotentReplayedHeader, "true")
+				c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+				if err := jsonapi.MarshalPayload(c.Response().Writer, issue); err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create issue response").SetInternal(err)
+				}
+				return nil
+			}
+			issueCreate.IdempotencyKey = idempotencyKey
+		}
 
-		issue, err := s.createIssue(ctx, issueCreate, c.Get(getPrincipalIDContextKey()).(int))
+		issue, err := s.createIssue(ctx, issueCreate, creatorID)
 		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, "Failed to create issue").SetInternal(err)
+			}
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue").SetInternal(err)
 		}
 
@@ -298,8 +342,32 @@ func (s *Server) createIssue(ctx context.Context, issueCreate *api.IssueCreate,
 	if err != nil {
 		return nil, err
 	}
+	if s.MetricReporter != nil {
+		s.MetricReporter.report(&metric.Metric{
+			Name:  metricAPI.IssueCreatedMetricName,
+			Value: 1,
+			Labels: map[string]string{
+				"type": string(issue.Type),
+			},
+		})
+	}
+	if err := s.createChangeSetIfNeeded(ctx, issueCreate, pipeline.ID, creatorID); err != nil {
+		return nil, fmt.Errorf("failed to create change set after creating the issue: %v. Error %w", issue.Name, err)
+	}
+	// The owners of the tables touched by the issue are subscribed as the default reviewers.
+	reviewerIDList, err := s.getOwnerReviewerIDList(ctx, issue.Pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the owner reviewers after creating the issue: %v. Error %w", issue.Name, err)
+	}
+	subscriberIDList := issueCreate.SubscriberIDList
+	for _, reviewerID := range reviewerIDList {
+		if reviewerID == creatorID || reviewerID == issue.AssigneeID {
+			continue
+		}
+		subscriberIDList = appendUniqueID(subscriberIDList, reviewerID)
+	}
 	// Create issue subscribers.
-	for _, subscriberID := range issueCreate.SubscriberIDList {
+	for _, subscriberID := range subscriberIDList {
 		subscriberCreate := &api.IssueSubscriberCreate{
 			IssueID:      issue.ID,
 			SubscriberID: subscriberID,
@@ -344,12 +412,23 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 		return nil, err
 	}
 
+	project, err := s.store.GetProjectByID(ctx, issueCreate.ProjectID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find project with ID %d", issueCreate.ProjectID)).SetInternal(err)
+	}
+	if project == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID not found: %d", issueCreate.ProjectID))
+	}
+
 	// Return an error if the issue has no task to be executed
 	hasTask := false
 	for _, stage := range pipelineCreate.StageList {
-		if len(stage.TaskList) > 0 {
-			hasTask = true
-			break
+		if len(stage.TaskList) == 0 {
+			continue
+		}
+		hasTask = true
+		if err := s.validateProjectEnvironment(ctx, project, stage.EnvironmentID); err != nil {
+			return nil, err
 		}
 	}
 	if !hasTask {
@@ -414,6 +493,22 @@ func (s *Server) getPipelineCreate(ctx context.Context, issueCreate *api.IssueCr
 		return s.getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx, issueCreate)
 	case api.IssueDatabaseSchemaUpdateGhost:
 		return s.getPipelineCreateForDatabaseSchemaUpdateGhost(ctx, issueCreate)
+	case api.IssueDatabaseCharsetConvert:
+		return s.getPipelineCreateForDatabaseCharsetConvert(ctx, issueCreate)
+	case api.IssueDatabaseColumnRename:
+		return s.getPipelineCreateForDatabaseColumnRename(ctx, issueCreate)
+	case api.IssueDatabaseColumnAdd:
+		return s.getPipelineCreateForDatabaseColumnAdd(ctx, issueCreate)
+	case api.IssueDatabaseDrop:
+		return s.getPipelineCreateForDatabaseDrop(ctx, issueCreate)
+	case api.IssueDatabaseDataValidate:
+		return s.getPipelineCreateForDatabaseDataValidate(ctx, issueCreate)
+	case api.IssueDatabaseAccessChange:
+		return s.getPipelineCreateForDatabaseAccessChange(ctx, issueCreate)
+	case api.IssueInstanceParameterChange:
+		return s.getPipelineCreateForInstanceParameterChange(ctx, issueCreate)
+	case api.IssueInstanceUpgradeCheck:
+		return s.getPipelineCreateForInstanceUpgradeCheck(ctx, issueCreate)
 	default:
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid issue type %q", issueCreate.Type))
 	}
@@ -449,6 +544,29 @@ func (s *Server) getPipelineCreateForDatabaseCreate(ctx context.Context, issueCr
 	if err := checkCharacterSetCollationOwner(instance.Engine, c.CharacterSet, c.Collation, c.Owner); err != nil {
 		return nil, err
 	}
+	if err := checkPostgresDatabaseOptions(instance.Engine, c.Tablespace, c.ConnectionLimit, c.ExtensionList); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// The labels of the template are set on the database unless the issue sets the same keys.
+	var template *api.DatabaseTemplate
+	if c.TemplateID != 0 {
+		template, err = s.store.GetDatabaseTemplateByID(ctx, c.TemplateID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database template with ID %d", c.TemplateID)).SetInternal(err)
+		}
+		if template == nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database template not found with ID %d", c.TemplateID))
+		}
+		if template.Engine != instance.Engine {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database template %q is for %s, but the instance is %s", template.Name, template.Engine, instance.Engine))
+		}
+		labels, err := template.MergeLabels(c.Labels)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to merge the labels of database template %q", template.Name)).SetInternal(err)
+		}
+		c.Labels = labels
+	}
 
 	if instance.Engine == db.Snowflake {
 		// Snowflake needs to use upper case of DatabaseName.
@@ -481,13 +599,26 @@ func (s *Server) getPipelineCreateForDatabaseCreate(ctx context.Context, issueCr
 	if schemaVersion == "" {
 		schemaVersion = common.DefaultMigrationVersion()
 	}
+	if template != nil {
+		if schema != "" {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database template %q can't be applied since the schema is copied from the peer tenant databases in project %q", template.Name, project.Name))
+		}
+		schema = template.GetStatement()
+	}
 
 	payload := api.TaskDatabaseCreatePayload{
-		ProjectID:     issueCreate.ProjectID,
-		CharacterSet:  c.CharacterSet,
-		Collation:     c.Collation,
-		Labels:        c.Labels,
-		SchemaVersion: schemaVersion,
+		ProjectID:       issueCreate.ProjectID,
+		CharacterSet:    c.CharacterSet,
+		Collation:       c.Collation,
+		Labels:          c.Labels,
+		SchemaVersion:   schemaVersion,
+		Owner:           c.Owner,
+		Tablespace:      c.Tablespace,
+		ConnectionLimit: c.ConnectionLimit,
+		ExtensionList:   c.ExtensionList,
+	}
+	if template != nil {
+		payload.TemplateName = template.Name
 	}
 	payload.DatabaseName, payload.Statement = getDatabaseNameAndStatement(instance.Engine, c, schema)
 	bytes, err := json.Marshal(payload)
@@ -496,6 +627,9 @@ func (s *Server) getPipelineCreateForDatabaseCreate(ctx context.Context, issueCr
 	}
 
 	if c.BackupID != 0 {
+		if !api.GetInstanceCapabilities(instance).Restore {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Restoring from a backup is not supported for instance %q of engine %s", instance.Name, instance.Engine))
+		}
 		backup, err := s.store.GetBackupByID(ctx, c.BackupID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find backup %v", c.BackupID)
@@ -612,6 +746,11 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 			}
 		}
 	}
+	for _, detail := range c.DetailList {
+		if err := s.setUpdateSchemaDetailSheetStatement(ctx, issueCreate, detail); err != nil {
+			return nil, err
+		}
+	}
 	create := &api.PipelineCreate{}
 	switch c.MigrationType {
 	case db.Baseline:
@@ -623,6 +762,19 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 	default:
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid migration type %q", c.MigrationType))
 	}
+	mixed := false
+	for _, d := range c.DetailList {
+		if d.MigrationType == "" || d.MigrationType == c.MigrationType {
+			continue
+		}
+		if c.MigrationType == db.Baseline || (d.MigrationType != db.Migrate && d.MigrationType != db.Data) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid migration type %q of the update schema detail, only %q and %q can be mixed", d.MigrationType, db.Migrate, db.Data))
+		}
+		mixed = true
+	}
+	if mixed {
+		create.Name = "Update database schema and data pipeline"
+	}
 	project, err := s.store.GetProjectByID(ctx, issueCreate.ProjectID)
 	if err != nil {
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project with ID %d", issueCreate.ProjectID)).SetInternal(err)
@@ -654,7 +806,7 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 				return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", d.DatabaseID))
 			}
 
-			taskCreate, err := getUpdateTask(database, c.MigrationType, c.VCSPushEvent, d, schemaVersion)
+			taskCreate, err := getUpdateTask(database, d.GetMigrationType(c.MigrationType), c.VCSPushEvent, d, schemaVersion, c.FormatStatement)
 			if err != nil {
 				return nil, err
 			}
@@ -687,7 +839,7 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 					environmentSet[database.Instance.Environment.Name] = true
 					environmentID = database.Instance.EnvironmentID
 
-					taskCreate, err := getUpdateTask(database, c.MigrationType, c.VCSPushEvent, d, schemaVersion)
+					taskCreate, err := getUpdateTask(database, d.GetMigrationType(c.MigrationType), c.VCSPushEvent, d, schemaVersion, c.FormatStatement)
 					if err != nil {
 						return nil, err
 					}
@@ -721,8 +873,13 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 			order int
 		}
 		envToDatabaseMap := make(map[envKey][]api.TaskCreate)
+		// The details of the same database become the sub-tasks executed in the declared order.
+		envToTaskIndexDAGMap := make(map[envKey][]api.TaskIndexDAG)
+		databaseToLastTaskIndex := make(map[int]int)
+		databaseToTaskCount := make(map[int]int)
 		for _, d := range c.DetailList {
-			if c.MigrationType == db.Migrate && d.Statement == "" {
+			migrationType := d.GetMigrationType(c.MigrationType)
+			if migrationType == db.Migrate && d.Statement == "" {
 				return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, sql statement missing")
 			}
 			database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &d.DatabaseID})
@@ -733,13 +890,24 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 				return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", d.DatabaseID))
 			}
 
-			taskCreate, err := getUpdateTask(database, c.MigrationType, c.VCSPushEvent, d, schemaVersion)
+			key := envKey{name: database.Instance.Environment.Name, id: database.Instance.Environment.ID, order: database.Instance.Environment.Order}
+			lastIndex, hasPrevious := databaseToLastTaskIndex[database.ID]
+			version := schemaVersion
+			if hasPrevious {
+				version = getSubTaskSchemaVersion(schemaVersion, databaseToTaskCount[database.ID])
+			}
+			taskCreate, err := getUpdateTask(database, migrationType, c.VCSPushEvent, d, version, c.FormatStatement)
 			if err != nil {
 				return nil, err
 			}
 
-			key := envKey{name: database.Instance.Environment.Name, id: database.Instance.Environment.ID, order: database.Instance.Environment.Order}
+			index := len(envToDatabaseMap[key])
 			envToDatabaseMap[key] = append(envToDatabaseMap[key], *taskCreate)
+			if hasPrevious {
+				envToTaskIndexDAGMap[key] = append(envToTaskIndexDAGMap[key], api.TaskIndexDAG{FromIndex: lastIndex, ToIndex: index})
+			}
+			databaseToLastTaskIndex[database.ID] = index
+			databaseToTaskCount[database.ID]++
 		}
 		// Sort and group by environments.
 		var envKeys []envKey
@@ -751,12 +919,26 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 		})
 		for _, env := range envKeys {
 			create.StageList = append(create.StageList, api.StageCreate{
-				Name:          env.name,
-				EnvironmentID: env.id,
-				TaskList:      envToDatabaseMap[env],
+				Name:             env.name,
+				EnvironmentID:    env.id,
+				TaskList:         envToDatabaseMap[env],
+				TaskIndexDAGList: envToTaskIndexDAGMap[env],
 			})
 		}
 	}
+	if c.ChangeSet {
+		databaseSet := make(map[int]bool)
+		for _, stage := range create.StageList {
+			for _, task := range stage.TaskList {
+				if task.DatabaseID != nil {
+					databaseSet[*task.DatabaseID] = true
+				}
+			}
+		}
+		if len(databaseSet) < 2 {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "A change set should update at least two databases")
+		}
+	}
 	return create, nil
 }
 
@@ -799,6 +981,9 @@ func (s *Server) getPipelineCreateForDatabaseSchemaUpdateGhost(ctx context.Conte
 		if database == nil {
 			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("database ID not found: %d", detail.DatabaseID))
 		}
+		if !api.GetInstanceCapabilities(database.Instance).OnlineSchemaChange {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("online schema change is not supported for database %q of engine %s", database.Name, database.Instance.Engine))
+		}
 
 		taskCreateList, taskIndexDAGList, err := createGhostTaskList(database, c.VCSPushEvent, detail, schemaVersion)
 		if err != nil {
@@ -815,7 +1000,584 @@ func (s *Server) getPipelineCreateForDatabaseSchemaUpdateGhost(ctx context.Conte
 	return create, nil
 }
 
-func getUpdateTask(database *api.Database, migrationType db.MigrationType, vcsPushEvent *vcs.PushEvent, d *api.UpdateSchemaDetail, schemaVersion string) (*api.TaskCreate, error) {
+func (s *Server) getPipelineCreateForDatabaseCharsetConvert(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.CharsetConvertContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	plan, err := s.getCharsetConversionPlan(ctx, database, c.CharacterSet, c.Collation, c.TableList)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range plan.TableList {
+		if table.Method == api.CharsetConversionGhost && !s.feature(api.FeatureGhost) {
+			return nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Table %q is too large to convert without gh-ost, %s", table.Name, api.FeatureGhost.AccessErrorMessage()))
+		}
+	}
+
+	payload := api.TaskDatabaseCharsetConvertPayload{
+		Plan:          plan,
+		SchemaVersion: common.DefaultMigrationVersion(),
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database charset convert payload: %v", err))
+	}
+
+	return &api.PipelineCreate{
+		Name: "Convert database character set pipeline",
+		StageList: []api.StageCreate{
+			{
+				Name:          database.Instance.Environment.Name,
+				EnvironmentID: database.Instance.Environment.ID,
+				TaskList: []api.TaskCreate{
+					{
+						Name:          fmt.Sprintf("Convert %q to %s", database.Name, plan.CharacterSet),
+						InstanceID:    database.InstanceID,
+						DatabaseID:    &database.ID,
+						Status:        api.TaskPendingApproval,
+						Type:          api.TaskDatabaseCharsetConvert,
+						Statement:     plan.GetStatement(),
+						MigrationType: db.Migrate,
+						Payload:       string(bytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *Server) getPipelineCreateForDatabaseColumnRename(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.ColumnRenameContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+	if c.WaitPeriodSeconds != 0 && !s.feature(api.FeatureTaskScheduleTime) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, api.FeatureTaskScheduleTime.AccessErrorMessage())
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	table, err := s.store.GetTable(ctx, &api.TableFind{DatabaseID: &database.ID, Name: &c.TableName})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table %q", c.TableName)).SetInternal(err)
+	}
+	if table == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Table %q not found in database %q", c.TableName, database.Name))
+	}
+	columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &database.ID, TableID: &table.ID, Name: &c.OldColumnName})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch column %q", c.OldColumnName)).SetInternal(err)
+	}
+	if len(columnList) == 0 {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Column %q not found in table %q", c.OldColumnName, c.TableName))
+	}
+
+	stepList, err := api.PlanColumnRename(database.Instance.Engine, table.Name, columnList[0], c.ColumnType, c.NewColumnName, c.BatchSize, c.WaitPeriodSeconds)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	taskCreateList, taskIndexDAGList, err := createColumnChangeTaskList(database, table, stepList)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.PipelineCreate{
+		Name: fmt.Sprintf("Rename column %q to %q pipeline", c.OldColumnName, c.NewColumnName),
+		StageList: []api.StageCreate{
+			{
+				Name:             fmt.Sprintf("%s %s", database.Instance.Environment.Name, database.Name),
+				EnvironmentID:    database.Instance.Environment.ID,
+				TaskList:         taskCreateList,
+				TaskIndexDAGList: taskIndexDAGList,
+			},
+		},
+	}, nil
+}
+
+func (s *Server) getPipelineCreateForDatabaseColumnAdd(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.ColumnAddContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	table, err := s.store.GetTable(ctx, &api.TableFind{DatabaseID: &database.ID, Name: &c.TableName})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table %q", c.TableName)).SetInternal(err)
+	}
+	if table == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Table %q not found in database %q", c.TableName, database.Name))
+	}
+	columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &database.ID, TableID: &table.ID, Name: &c.ColumnName})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch column %q", c.ColumnName)).SetInternal(err)
+	}
+	if len(columnList) > 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Column %q already exists in table %q", c.ColumnName, c.TableName))
+	}
+
+	stepList, err := api.PlanColumnAdd(database.Instance.Engine, database.Instance.EngineVersion, table.Name, c.ColumnName, c.ColumnType, c.DefaultValue, c.BatchSize)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	taskCreateList, taskIndexDAGList, err := createColumnChangeTaskList(database, table, stepList)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.PipelineCreate{
+		Name: fmt.Sprintf("Add NOT NULL column %q pipeline", c.ColumnName),
+		StageList: []api.StageCreate{
+			{
+				Name:             fmt.Sprintf("%s %s", database.Instance.Environment.Name, database.Name),
+				EnvironmentID:    database.Instance.Environment.ID,
+				TaskList:         taskCreateList,
+				TaskIndexDAGList: taskIndexDAGList,
+			},
+		},
+	}, nil
+}
+
+// createColumnChangeTaskList creates the chained tasks of the column change steps on the table.
+func createColumnChangeTaskList(database *api.Database, table *api.Table, stepList []*api.ColumnChangeStep) ([]api.TaskCreate, []api.TaskIndexDAG, error) {
+	// The steps are chained, and each step gets its own schema version since they're recorded separately in the migration history.
+	// The waiting periods are accumulated from now, as the earliest allowed time is absolute.
+	schemaVersion := common.DefaultMigrationVersion()
+	earliestAllowedTs := int64(0)
+	var taskCreateList []api.TaskCreate
+	var taskIndexDAGList []api.TaskIndexDAG
+	for i, step := range stepList {
+		if step.WaitSeconds > 0 {
+			if earliestAllowedTs == 0 {
+				earliestAllowedTs = time.Now().Unix()
+			}
+			earliestAllowedTs += step.WaitSeconds
+		}
+		var taskCreate *api.TaskCreate
+		switch step.TaskType {
+		case api.TaskDatabaseDataBackfill:
+			bytes, err := json.Marshal(api.TaskDatabaseDataBackfillPayload{
+				BatchStatement:    step.Statement,
+				EstimatedRowCount: table.RowCount,
+			})
+			if err != nil {
+				return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database data backfill payload: %v", err))
+			}
+			taskCreate = &api.TaskCreate{
+				InstanceID:        database.Instance.ID,
+				DatabaseID:        &database.ID,
+				Status:            api.TaskPendingApproval,
+				Type:              api.TaskDatabaseDataBackfill,
+				Statement:         step.Statement,
+				EarliestAllowedTs: earliestAllowedTs,
+				Payload:           string(bytes),
+			}
+		default:
+			detail := &api.UpdateSchemaDetail{
+				Statement:         step.Statement,
+				EarliestAllowedTs: earliestAllowedTs,
+			}
+			var err error
+			if taskCreate, err = getUpdateTask(database, db.Migrate, nil /* vcsPushEvent */, detail, fmt.Sprintf("%s-%d", schemaVersion, i+1), false /* formatStatement */); err != nil {
+				return nil, nil, err
+			}
+		}
+		taskCreate.Name = fmt.Sprintf("Step %d: %s", i+1, step.Name)
+		taskCreateList = append(taskCreateList, *taskCreate)
+		if i > 0 {
+			taskIndexDAGList = append(taskIndexDAGList, api.TaskIndexDAG{FromIndex: i - 1, ToIndex: i})
+		}
+	}
+	return taskCreateList, taskIndexDAGList, nil
+}
+
+func (s *Server) getPipelineCreateForDatabaseDrop(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.DropDatabaseContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	if database.ProjectID != issueCreate.ProjectID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q doesn't belong to project ID %d", database.Name, issueCreate.ProjectID))
+	}
+	if database.SyncStatus != api.OK {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q is not found on the instance", database.Name))
+	}
+	switch database.Instance.Engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.Postgres:
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Dropping database is not supported for %s", database.Instance.Engine))
+	}
+
+	payload := api.TaskDatabaseDropPayload{
+		DatabaseName: database.Name,
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database drop payload: %v", err))
+	}
+
+	return &api.PipelineCreate{
+		Name: "Drop database pipeline",
+		StageList: []api.StageCreate{
+			{
+				Name:          database.Instance.Environment.Name,
+				EnvironmentID: database.Instance.Environment.ID,
+				TaskList: []api.TaskCreate{
+					{
+						Name:       fmt.Sprintf("Drop database %q", database.Name),
+						InstanceID: database.InstanceID,
+						DatabaseID: &database.ID,
+						// The task always requires the approval from the Owner or DBA regardless of the approval policy.
+						Status:    api.TaskPendingApproval,
+						Type:      api.TaskDatabaseDrop,
+						Statement: getDropDatabaseStatement(database.Instance.Engine, database.Name),
+						Payload:   string(bytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *Server) getPipelineCreateForDatabaseDataValidate(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.DataValidateContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+	chunkSize, err := api.ValidateDataValidationChunkSize(c.ChunkSize)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if c.DatabaseID == c.SourceDatabaseID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "The database can't be validated against itself")
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	if database.ProjectID != issueCreate.ProjectID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q doesn't belong to project ID %d", database.Name, issueCreate.ProjectID))
+	}
+	sourceDatabase, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.SourceDatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch source database ID: %v", c.SourceDatabaseID)).SetInternal(err)
+	}
+	if sourceDatabase == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Source database ID not found: %d", c.SourceDatabaseID))
+	}
+	if !isDataValidationSupported(database.Instance.Engine) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Data validation is not supported for %s", database.Instance.Engine))
+	}
+	if sourceDatabase.Instance.Engine != database.Instance.Engine {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Source database %q is %s but database %q is %s", sourceDatabase.Name, sourceDatabase.Instance.Engine, database.Name, database.Instance.Engine))
+	}
+
+	payload := api.TaskDatabaseDataValidatePayload{
+		SourceDatabaseID: sourceDatabase.ID,
+		TableList:        c.TableList,
+		ChunkSize:        chunkSize,
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database data validate payload: %v", err))
+	}
+
+	return &api.PipelineCreate{
+		Name: "Validate database data pipeline",
+		StageList: []api.StageCreate{
+			{
+				Name:          database.Instance.Environment.Name,
+				EnvironmentID: database.Instance.Environment.ID,
+				TaskList: []api.TaskCreate{
+					{
+						Name:       fmt.Sprintf("Validate %q data against %q", database.Name, sourceDatabase.Name),
+						InstanceID: database.InstanceID,
+						DatabaseID: &database.ID,
+						Status:     api.TaskPendingApproval,
+						Type:       api.TaskDatabaseDataValidate,
+						Payload:    string(bytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *Server) getPipelineCreateForDatabaseAccessChange(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.AccessChangeContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+	if len(c.ChangeList) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Access change list must not be empty")
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	if database.ProjectID != issueCreate.ProjectID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q doesn't belong to project ID %d", database.Name, issueCreate.ProjectID))
+	}
+	engine := database.Instance.Engine
+	if !isAccessChangeSupported(engine) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Access change is not supported for %s", engine))
+	}
+	policy, err := s.store.GetAccessChangePolicy(ctx, database.Instance.EnvironmentID, api.GetPolicyTagList(database.Instance, database))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the access change policy of environment %q", database.Instance.Environment.Name)).SetInternal(err)
+	}
+	var statementList []string
+	for _, change := range c.ChangeList {
+		if err := api.NormalizeAccessChange(engine, change); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if err := policy.Review(engine, change); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		statementList = append(statementList, api.GetAccessChangeStatement(engine, database.Name, change, true /* maskPassword */))
+		// The task payload and the issue create context are returned by the API, so the task only keeps the encrypted password.
+		if change.Password != "" {
+			encryptedPassword, err := common.EncryptWithSecret(change.Password, s.secret)
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to encrypt the password of %q", change.UserName)).SetInternal(err)
+			}
+			change.Password, change.EncryptedPassword = "", encryptedPassword
+		}
+	}
+
+	payload := api.TaskDatabaseAccessChangePayload{
+		Statement:  strings.Join(statementList, "\n"),
+		ChangeList: c.ChangeList,
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database access change payload: %v", err))
+	}
+	for _, change := range c.ChangeList {
+		change.EncryptedPassword = ""
+	}
+	createContext, err := json.Marshal(c)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal database access change context: %v", err))
+	}
+	issueCreate.CreateContext = string(createContext)
+
+	return &api.PipelineCreate{
+		Name: "Change database access pipeline",
+		StageList: []api.StageCreate{
+			{
+				Name:          database.Instance.Environment.Name,
+				EnvironmentID: database.Instance.Environment.ID,
+				TaskList: []api.TaskCreate{
+					{
+						Name:       fmt.Sprintf("Change %q access", database.Name),
+						InstanceID: database.InstanceID,
+						DatabaseID: &database.ID,
+						Status:     api.TaskPendingApproval,
+						Type:       api.TaskDatabaseAccessChange,
+						Payload:    string(bytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *Server) getPipelineCreateForInstanceParameterChange(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.ParameterChangeContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+	if err := api.ValidateParameterChangeList(c.ParameterList); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	instance, err := s.store.GetInstanceByID(ctx, c.InstanceID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", c.InstanceID)).SetInternal(err)
+	}
+	if instance == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", c.InstanceID))
+	}
+	if !isParameterChangeSupported(instance.Engine) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parameter change is not supported for %s", instance.Engine))
+	}
+	scope := api.GetMySQLParameterScope(instance.Engine, instance.EngineVersion)
+	var statementList []string
+	for _, change := range c.ParameterList {
+		statementList = append(statementList, api.GetParameterChangeStatement(instance.Engine, scope, change))
+	}
+
+	payload := api.TaskInstanceParameterChangePayload{
+		Statement:     strings.Join(statementList, "\n"),
+		ParameterList: c.ParameterList,
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal instance parameter change payload: %v", err))
+	}
+
+	return &api.PipelineCreate{
+		Name: "Change instance parameter pipeline",
+		StageList: []api.StageCreate{
+			{
+				Name:          instance.Environment.Name,
+				EnvironmentID: instance.Environment.ID,
+				TaskList: []api.TaskCreate{
+					{
+						Name:       fmt.Sprintf("Change %q parameters", instance.Name),
+						InstanceID: instance.ID,
+						Status:     api.TaskPendingApproval,
+						Type:       api.TaskInstanceParameterChange,
+						Payload:    string(bytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *Server) getPipelineCreateForInstanceUpgradeCheck(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.UpgradeCheckContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+
+	instance, err := s.store.GetInstanceByID(ctx, c.InstanceID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", c.InstanceID)).SetInternal(err)
+	}
+	if instance == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", c.InstanceID))
+	}
+	if instance.Engine != db.Postgres {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Upgrade check is only supported for PostgreSQL, but instance %q is %s", instance.Name, instance.Engine))
+	}
+
+	payload := api.TaskInstanceUpgradeCheckPayload{
+		TargetVersion: c.TargetVersion,
+	}
+	if c.TargetInstanceID != nil {
+		targetInstance, err := s.store.GetInstanceByID(ctx, *c.TargetInstanceID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch target instance ID: %v", *c.TargetInstanceID)).SetInternal(err)
+		}
+		if targetInstance == nil {
+			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Target instance ID not found: %d", *c.TargetInstanceID))
+		}
+		if targetInstance.Engine != db.Postgres {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Target instance %q is %s, not PostgreSQL", targetInstance.Name, targetInstance.Engine))
+		}
+		if payload.TargetVersion == "" {
+			payload.TargetVersion = targetInstance.EngineVersion
+		}
+		payload.TargetInstanceID = targetInstance.ID
+	}
+	targetVersionNum, err := api.GetPostgresVersionNum(payload.TargetVersion)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if sourceVersionNum, err := api.GetPostgresVersionNum(instance.EngineVersion); err == nil && sourceVersionNum >= targetVersionNum {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance %q of PostgreSQL %s is not older than the target version %s", instance.Name, instance.EngineVersion, payload.TargetVersion))
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal instance upgrade check payload: %v", err))
+	}
+
+	return &api.PipelineCreate{
+		Name: "Check instance upgrade pipeline",
+		StageList: []api.StageCreate{
+			{
+				Name:          instance.Environment.Name,
+				EnvironmentID: instance.Environment.ID,
+				TaskList: []api.TaskCreate{
+					{
+						Name:       fmt.Sprintf("Check %q upgrade to PostgreSQL %s", instance.Name, payload.TargetVersion),
+						InstanceID: instance.ID,
+						Status:     api.TaskPendingApproval,
+						Type:       api.TaskInstanceUpgradeCheck,
+						Payload:    string(bytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// setUpdateSchemaDetailSheetStatement takes the statement of the detail from the sheet version it refers to.
+// The sheet must belong to the project of the issue, and the statement, if provided, must be the same as the sheet version.
+func (s *Server) setUpdateSchemaDetailSheetStatement(ctx context.Context, issueCreate *api.IssueCreate, d *api.UpdateSchemaDetail) error {
+	if d.SheetID == 0 {
+		if d.SheetVersion != 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Sheet version is specified without the sheet")
+		}
+		return nil
+	}
+	if d.SheetVersion <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Must specify the version of sheet %d", d.SheetID))
+	}
+	sheet, err := s.store.GetSheet(ctx, &api.SheetFind{ID: &d.SheetID}, issueCreate.CreatorID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sheet ID: %d", d.SheetID)).SetInternal(err)
+	}
+	if sheet == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Sheet ID not found: %d", d.SheetID))
+	}
+	if sheet.ProjectID != issueCreate.ProjectID {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Sheet %d doesn't belong to project %d", d.SheetID, issueCreate.ProjectID))
+	}
+	sheetVersion, err := s.store.GetSheetVersion(ctx, d.SheetID, d.SheetVersion)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch version %d of sheet ID: %d", d.SheetVersion, d.SheetID)).SetInternal(err)
+	}
+	if sheetVersion == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Version %d not found for sheet ID: %d", d.SheetVersion, d.SheetID))
+	}
+	if d.Statement != "" && d.Statement != sheetVersion.Statement {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Statement doesn't match version %d of sheet %q", d.SheetVersion, sheet.Name))
+	}
+	d.Statement = sheetVersion.Statement
+	return nil
+}
+
+func getUpdateTask(database *api.Database, migrationType db.MigrationType, vcsPushEvent *vcs.PushEvent, d *api.UpdateSchemaDetail, schemaVersion string, formatStatement bool) (*api.TaskCreate, error) {
 	taskName := fmt.Sprintf("Establish %q baseline", database.Name)
 	switch migrationType {
 	case db.Migrate:
@@ -823,13 +1585,37 @@ func getUpdateTask(database *api.Database, migrationType db.MigrationType, vcsPu
 	case db.Data:
 		taskName = fmt.Sprintf("Update %q data", database.Name)
 	}
+	statement := d.Statement
+	// The statements of the engines without parser support are kept as they are.
+	if engineType, ok := getParserEngineType(database.Instance.Engine); formatStatement && ok {
+		formatted, err := parser.FormatStatement(engineType, statement)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to format the statement for database %q: %v", database.Name, err))
+		}
+		statement = formatted
+	}
 	payload := api.TaskDatabaseSchemaUpdatePayload{}
 	payload.MigrationType = migrationType
-	payload.Statement = d.Statement
+	payload.Statement = statement
+	payload.DownStatement = d.DownStatement
 	payload.SchemaVersion = schemaVersion
+	payload.SheetID = d.SheetID
+	payload.SheetVersion = d.SheetVersion
 	if vcsPushEvent != nil {
 		payload.VCSPushEvent = vcsPushEvent
 	}
+	if d.Verification != nil {
+		if err := d.Verification.Validate(); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid verification for database %q: %v", database.Name, err))
+		}
+		payload.Verification = d.Verification
+	}
+	if d.SessionSetting != nil {
+		if err := d.SessionSetting.Validate(database.Instance.Engine); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid session settings for database %q: %v", database.Name, err))
+		}
+		payload.SessionSetting = d.SessionSetting
+	}
 	bytes, err := json.Marshal(payload)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to marshal database schema update payload: %v", err)
@@ -849,13 +1635,19 @@ func getUpdateTask(database *api.Database, migrationType db.MigrationType, vcsPu
 		DatabaseID:        &database.ID,
 		Status:            api.TaskPendingApproval,
 		Type:              taskType,
-		Statement:         d.Statement,
+		Statement:         statement,
 		EarliestAllowedTs: d.EarliestAllowedTs,
 		MigrationType:     migrationType,
 		Payload:           string(bytes),
 	}, nil
 }
 
+// getSubTaskSchemaVersion returns the schema version of the sub-task following the first task of the same database in an issue.
+// The migration versions of a database must be unique and increasing, so the sub-tasks are suffixed with the zero-padded sequence.
+func getSubTaskSchemaVersion(schemaVersion string, sequence int) string {
+	return fmt.Sprintf("%s.%04d", schemaVersion, sequence)
+}
+
 // creates PITR TaskCreate list and dependency.
 func createPITRTaskList(database *api.Database, projectID int, targetTs int64) ([]api.TaskCreate, []api.TaskIndexDAG, error) {
 	var taskCreateList []api.TaskCreate
@@ -969,6 +1761,19 @@ func checkCharacterSetCollationOwner(dbType db.Type, characterSet, collation, ow
 		if collation != "" {
 			return fmt.Errorf("Snowflake does not support collation, but got %s", collation)
 		}
+	case db.MSSQL:
+		// SQL Server uses the server collation if the database collation isn't specified.
+		if characterSet != "" {
+			return fmt.Errorf("SQL Server does not support character set, but got %s", characterSet)
+		}
+	case db.MongoDB:
+		// MongoDB supports collation at the collection level only.
+		if characterSet != "" {
+			return fmt.Errorf("MongoDB does not support character set, but got %s", characterSet)
+		}
+		if collation != "" {
+			return fmt.Errorf("MongoDB does not support collation, but got %s", collation)
+		}
 	case db.Postgres:
 		if owner == "" {
 			return fmt.Errorf("database owner is required for PostgreSQL")
@@ -989,6 +1794,25 @@ func checkCharacterSetCollationOwner(dbType db.Type, characterSet, collation, ow
 	return nil
 }
 
+// checkPostgresDatabaseOptions checks the tablespace, the connection limit and the extensions are only set for Postgres.
+func checkPostgresDatabaseOptions(dbType db.Type, tablespace string, connectionLimit int, extensionList []string) error {
+	if dbType != db.Postgres {
+		if tablespace != "" || connectionLimit != 0 || len(extensionList) > 0 {
+			return fmt.Errorf("tablespace, connection limit and extensions are only applicable to PostgreSQL, but got %s", dbType)
+		}
+		return nil
+	}
+	if connectionLimit < 0 {
+		return fmt.Errorf("connection limit must not be negative, got %d", connectionLimit)
+	}
+	for _, extension := range extensionList {
+		if extension == "" {
+			return fmt.Errorf("extension name must not be empty")
+		}
+	}
+	return nil
+}
+
 func getDatabaseNameAndStatement(dbType db.Type, createDatabaseContext api.CreateDatabaseContext, schema string) (string, string) {
 	databaseName := createDatabaseContext.DatabaseName
 	// Snowflake needs to use upper case of DatabaseName.
@@ -998,17 +1822,23 @@ func getDatabaseNameAndStatement(dbType db.Type, createDatabaseContext api.Creat
 
 	var stmt string
 	switch dbType {
-	case db.MySQL, db.TiDB:
-		stmt = fmt.Sprintf("CREATE DATABASE `%s` CHARACTER SET %s COLLATE %s;", databaseName, createDatabaseContext.CharacterSet, createDatabaseContext.Collation)
+	case db.MySQL, db.TiDB, db.MariaDB:
+		stmt = fmt.Sprintf("CREATE DATABASE %s CHARACTER SET %s COLLATE %s;", api.QuoteIdentifier(dbType, databaseName), createDatabaseContext.CharacterSet, createDatabaseContext.Collation)
 		if schema != "" {
-			stmt = fmt.Sprintf("%s\nUSE `%s`;\n%s", stmt, databaseName, schema)
+			stmt = fmt.Sprintf("%s\nUSE %s;\n%s", stmt, api.QuoteIdentifier(dbType, databaseName), schema)
 		}
 	case db.Postgres:
-		if createDatabaseContext.Collation == "" {
-			stmt = fmt.Sprintf("CREATE DATABASE \"%s\" ENCODING %q;", databaseName, createDatabaseContext.CharacterSet)
-		} else {
-			stmt = fmt.Sprintf("CREATE DATABASE \"%s\" ENCODING %q LC_COLLATE %q;", databaseName, createDatabaseContext.CharacterSet, createDatabaseContext.Collation)
+		stmt = fmt.Sprintf("CREATE DATABASE %s ENCODING %q", api.QuoteIdentifier(dbType, databaseName), createDatabaseContext.CharacterSet)
+		if createDatabaseContext.Collation != "" {
+			stmt = fmt.Sprintf("%s LC_COLLATE %q", stmt, createDatabaseContext.Collation)
+		}
+		if createDatabaseContext.Tablespace != "" {
+			stmt = fmt.Sprintf("%s TABLESPACE %s", stmt, api.QuoteIdentifier(dbType, createDatabaseContext.Tablespace))
 		}
+		if createDatabaseContext.ConnectionLimit > 0 {
+			stmt = fmt.Sprintf("%s CONNECTION LIMIT %d", stmt, createDatabaseContext.ConnectionLimit)
+		}
+		stmt += ";"
 		// Set the database owner.
 		// We didn't use CREATE DATABASE WITH OWNER because RDS requires the current role to be a member of the database owner.
 		// However, people can still use ALTER DATABASE to change the owner afterwards.
@@ -1017,18 +1847,26 @@ func getDatabaseNameAndStatement(dbType db.Type, createDatabaseContext api.Creat
 		// ERROR:  must be member of role "hello"
 		//
 		// For tenant project, the schema for the newly created database will belong to the same owner.
-		stmt = fmt.Sprintf("%s\nALTER DATABASE \"%s\" OWNER TO %s;\n", stmt, databaseName, createDatabaseContext.Owner)
+		stmt = fmt.Sprintf("%s\nALTER DATABASE %s OWNER TO %s;\n", stmt, api.QuoteIdentifier(dbType, databaseName), createDatabaseContext.Owner)
+		// The extensions are created in the new database before the schema, since the schema may depend on them.
+		var bootstrapList []string
+		for _, extension := range createDatabaseContext.ExtensionList {
+			bootstrapList = append(bootstrapList, fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s;", api.QuoteIdentifier(dbType, extension)))
+		}
 		if schema != "" {
-			stmt = fmt.Sprintf("%s\n\\connect \"%s\";\n%s", stmt, databaseName, schema)
+			bootstrapList = append(bootstrapList, schema)
+		}
+		if len(bootstrapList) > 0 {
+			stmt = fmt.Sprintf("%s\n\\connect %s;\n%s", stmt, api.QuoteIdentifier(dbType, databaseName), strings.Join(bootstrapList, "\n"))
 		}
 	case db.ClickHouse:
 		clusterPart := ""
 		if createDatabaseContext.Cluster != "" {
-			clusterPart = fmt.Sprintf(" ON CLUSTER `%s`", createDatabaseContext.Cluster)
+			clusterPart = fmt.Sprintf(" ON CLUSTER %s", api.QuoteIdentifier(dbType, createDatabaseContext.Cluster))
 		}
-		stmt = fmt.Sprintf("CREATE DATABASE `%s`%s;", databaseName, clusterPart)
+		stmt = fmt.Sprintf("CREATE DATABASE %s%s;", api.QuoteIdentifier(dbType, databaseName), clusterPart)
 		if schema != "" {
-			stmt = fmt.Sprintf("%s\nUSE `%s`;\n%s", stmt, databaseName, schema)
+			stmt = fmt.Sprintf("%s\nUSE %s;\n%s", stmt, api.QuoteIdentifier(dbType, databaseName), schema)
 		}
 	case db.Snowflake:
 		databaseName = strings.ToUpper(databaseName)
@@ -1036,6 +1874,23 @@ func getDatabaseNameAndStatement(dbType db.Type, createDatabaseContext api.Creat
 		if schema != "" {
 			stmt = fmt.Sprintf("%s\nUSE DATABASE %s;\n%s", stmt, databaseName, schema)
 		}
+	case db.MSSQL:
+		stmt = fmt.Sprintf("CREATE DATABASE %s", api.QuoteIdentifier(dbType, databaseName))
+		if createDatabaseContext.Collation != "" {
+			stmt = fmt.Sprintf("%s COLLATE %s", stmt, createDatabaseContext.Collation)
+		}
+		stmt += ";"
+		// CREATE DATABASE must be in its own batch, and so is USE since the driver switches the connection on it.
+		if schema != "" {
+			stmt = fmt.Sprintf("%s\nGO\nUSE %s;\nGO\n%s", stmt, api.QuoteIdentifier(dbType, databaseName), schema)
+		}
+	case db.MongoDB:
+		// MongoDB creates the database implicitly on the first collection, so the statement only switches to the database.
+		// The database stays empty until the schema or a later change script creates a collection.
+		stmt = fmt.Sprintf("db = db.getSiblingDB(%s);", api.QuoteIdentifier(dbType, databaseName))
+		if schema != "" {
+			stmt = fmt.Sprintf("%s\n%s", stmt, schema)
+		}
 	case db.SQLite:
 		// This is a fake CREATE DATABASE and USE statement since a single SQLite file represents a database. Engine driver will recognize it and establish a connection to create the sqlite file representing the database.
 		stmt = fmt.Sprintf("CREATE DATABASE '%s';", databaseName)
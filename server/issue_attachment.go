@@ -0,0 +1,241 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/blob"
+)
+
+const (
+	// maxAttachmentSize is the size limit of an issue attachment.
+	maxAttachmentSize = 10 * 1024 * 1024
+	// maxAttachmentCount is the limit of the attachments of an issue.
+	maxAttachmentCount = 20
+	// attachmentScanTimeout is the timeout of scanning an attachment.
+	attachmentScanTimeout = 30 * time.Second
+)
+
+// errAttachmentRejected is returned when the scan endpoint rejects the attachment.
+var errAttachmentRejected = errors.New("attachment rejected by the scan")
+
+func (s *Server) registerIssueAttachmentRoutes(g *echo.Group) {
+	// The attachment is uploaded as the "file" field of the multipart form.
+	g.POST("/issue/:issueID/attachment", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issue, err := s.getAttachmentIssue(ctx, c.Param("issueID"))
+		if err != nil {
+			return err
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing file in upload attachment request").SetInternal(err)
+		}
+		if fileHeader.Size > maxAttachmentSize {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Attachment %q exceeds the size limit of %d bytes", fileHeader.Filename, maxAttachmentSize))
+		}
+		attachmentList, err := s.store.FindIssueAttachment(ctx, &api.IssueAttachmentFind{IssueID: &issue.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch attachment list for issue %d", issue.ID)).SetInternal(err)
+		}
+		if len(attachmentList) >= maxAttachmentCount {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue %d already has %d attachments", issue.ID, maxAttachmentCount))
+		}
+
+		f, err := fileHeader.Open()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to open attachment %q", fileHeader.Filename)).SetInternal(err)
+		}
+		defer f.Close()
+		content, err := io.ReadAll(io.LimitReader(f, maxAttachmentSize+1))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to read attachment %q", fileHeader.Filename)).SetInternal(err)
+		}
+		if len(content) > maxAttachmentSize {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Attachment %q exceeds the size limit of %d bytes", fileHeader.Filename, maxAttachmentSize))
+		}
+
+		if s.profile.AttachmentScanURL != "" {
+			if err := scanAttachment(ctx, s.profile.AttachmentScanURL, fileHeader.Filename, content); err != nil {
+				if errors.Is(err, errAttachmentRejected) {
+					return echo.NewHTTPError(http.StatusUnprocessableEntity, fmt.Sprintf("Attachment %q is rejected by the virus scan", fileHeader.Filename)).SetInternal(err)
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to scan attachment %q", fileHeader.Filename)).SetInternal(err)
+			}
+		}
+
+		contentType := fileHeader.Header.Get(echo.HeaderContentType)
+		if contentType == "" {
+			contentType = http.DetectContentType(content)
+		}
+		blobKey := fmt.Sprintf("issue/%d/attachment/%s", issue.ID, uuid.New().String())
+		if err := s.blobStore.Put(ctx, blobKey, content, contentType); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to save attachment %q", fileHeader.Filename)).SetInternal(err)
+		}
+
+		attachment, err := s.store.CreateIssueAttachment(ctx, &api.IssueAttachmentCreate{
+			CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
+			IssueID:     issue.ID,
+			Name:        fileHeader.Filename,
+			ContentType: contentType,
+			Size:        int64(len(content)),
+			BlobKey:     blobKey,
+		})
+		if err != nil {
+			if err := s.blobStore.Delete(ctx, blobKey); err != nil {
+				log.Warn("Failed to delete the blob of the attachment failed to create", zap.String("blob_key", blobKey), zap.Error(err))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create attachment %q for issue %d", fileHeader.Filename, issue.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, attachment); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create issue attachment response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/issue/:issueID/attachment", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issue, err := s.getAttachmentIssue(ctx, c.Param("issueID"))
+		if err != nil {
+			return err
+		}
+
+		attachmentList, err := s.store.FindIssueAttachment(ctx, &api.IssueAttachmentFind{IssueID: &issue.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch attachment list for issue %d", issue.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, attachmentList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal attachment list response for issue %d", issue.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Downloads the attachment content.
+	g.GET("/issue/:issueID/attachment/:attachmentID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		attachment, err := s.getIssueAttachment(ctx, c.Param("issueID"), c.Param("attachmentID"))
+		if err != nil {
+			return err
+		}
+
+		r, err := s.blobStore.Get(ctx, attachment.BlobKey)
+		if err != nil {
+			if errors.Is(err, blob.ErrNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Content of attachment %q not found", attachment.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to read attachment %q", attachment.Name)).SetInternal(err)
+		}
+		defer r.Close()
+
+		// Always download the attachment instead of rendering it, so that an uploaded HTML page can't run in our origin.
+		c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+		c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+		return c.Stream(http.StatusOK, attachment.ContentType, r)
+	})
+
+	g.DELETE("/issue/:issueID/attachment/:attachmentID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		attachment, err := s.getIssueAttachment(ctx, c.Param("issueID"), c.Param("attachmentID"))
+		if err != nil {
+			return err
+		}
+
+		if err := s.store.DeleteIssueAttachment(ctx, &api.IssueAttachmentDelete{
+			ID:        attachment.ID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete attachment %q", attachment.Name)).SetInternal(err)
+		}
+		// The orphan blob is harmless, so we only emit a warning.
+		if err := s.blobStore.Delete(ctx, attachment.BlobKey); err != nil {
+			log.Warn("Failed to delete the blob of the deleted attachment", zap.String("blob_key", attachment.BlobKey), zap.Error(err))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+func (s *Server) getAttachmentIssue(ctx context.Context, issueIDStr string) (*api.Issue, error) {
+	issueID, err := strconv.Atoi(issueIDStr)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", issueIDStr)).SetInternal(err)
+	}
+	issue, err := s.store.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", issueID)).SetInternal(err)
+	}
+	if issue == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", issueID))
+	}
+	return issue, nil
+}
+
+func (s *Server) getIssueAttachment(ctx context.Context, issueIDStr, attachmentIDStr string) (*api.IssueAttachment, error) {
+	issue, err := s.getAttachmentIssue(ctx, issueIDStr)
+	if err != nil {
+		return nil, err
+	}
+	attachmentID, err := strconv.Atoi(attachmentIDStr)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Attachment ID is not a number: %s", attachmentIDStr)).SetInternal(err)
+	}
+	attachment, err := s.store.GetIssueAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch attachment ID: %v", attachmentID)).SetInternal(err)
+	}
+	if attachment == nil || attachment.IssueID != issue.ID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Attachment ID not found in issue %d: %d", issue.ID, attachmentID))
+	}
+	return attachment, nil
+}
+
+// scanAttachment POSTs the attachment content to the scan endpoint, e.g. a REST wrapper of ClamAV.
+// The attachment is clean if the endpoint responds with a 2xx status code, and rejected on a 4xx status code.
+func scanAttachment(ctx context.Context, scanURL, name string, content []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, attachmentScanTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scanURL, bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to construct attachment scan request, error: %w", err)
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEOctetStream)
+	req.Header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to POST attachment to the scan endpoint, error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return fmt.Errorf("failed to read the scan response, error: %w", err)
+	}
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		return fmt.Errorf("%w, status code: %d, response body: %s", errAttachmentRejected, resp.StatusCode, b)
+	}
+	return fmt.Errorf("failed to scan attachment, status code: %d, response body: %s", resp.StatusCode, b)
+}
@@ -0,0 +1,447 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/pdf"
+)
+
+func (s *Server) registerIssueChangeRecordRoutes(g *echo.Group) {
+	// The change record is taken on the first request, and the later requests render the same record.
+	g.GET("/issue/:issueID/change-record", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		format := api.SchemaDocFormatHTML
+		if v := c.QueryParam("format"); v != "" {
+			format = api.SchemaDocFormat(strings.ToUpper(v))
+		}
+		if format != api.SchemaDocFormatHTML && format != api.SchemaDocFormatPDF {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported change record format %q, expect HTML or PDF", format))
+		}
+		contentType, extension, err := getSchemaDocContentType(format)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		record, err := s.store.GetIssueChangeRecordByIssueID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch change record of issue ID: %v", id)).SetInternal(err)
+		}
+		if record == nil {
+			issue, err := s.store.GetIssueByID(ctx, id)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", id)).SetInternal(err)
+			}
+			if issue == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", id))
+			}
+			if issue.Status != api.IssueDone {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue %q is %s, the change record is only available for the done issue", issue.Name, issue.Status))
+			}
+			if record, err = s.createIssueChangeRecord(ctx, issue, c.Get(getPrincipalIDContextKey()).(int)); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create change record of issue %q", issue.Name)).SetInternal(err)
+			}
+		}
+
+		content, err := renderIssueChangeRecord(record, format)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to render change record of issue ID: %v", id)).SetInternal(err)
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("issue-%d-change-record%s", id, extension)))
+		return c.Blob(http.StatusOK, contentType, content)
+	})
+
+	g.GET("/issue/:issueID/change-record/verify", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		record, err := s.store.GetIssueChangeRecordByIssueID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch change record of issue ID: %v", id)).SetInternal(err)
+		}
+		if record == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Change record not found for issue ID: %d", id))
+		}
+		verification := &api.IssueChangeRecordVerification{
+			IssueID:  id,
+			RecordID: record.ID,
+			Verified: api.VerifyIssueChangeRecord([]byte(s.secret), record),
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, verification); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal change record verification response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// createIssueChangeRecord takes the change record of the done issue and signs it by the workspace secret.
+// The schema before and after each migration is read from the migration history on the instance, so the instance must be reachable.
+func (s *Server) createIssueChangeRecord(ctx context.Context, issue *api.Issue, principalID int) (*api.IssueChangeRecord, error) {
+	activityList, err := s.store.FindActivity(ctx, &api.ActivityFind{ContainerID: &issue.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find activity list for issue ID %d, error: %w", issue.ID, err)
+	}
+	historyMap := make(map[int]*db.MigrationHistory)
+	if issue.Pipeline != nil {
+		for _, stage := range issue.Pipeline.StageList {
+			for _, task := range stage.TaskList {
+				history, err := s.findTaskMigrationHistory(ctx, task)
+				if err != nil {
+					return nil, err
+				}
+				if history != nil {
+					historyMap[task.ID] = history
+				}
+			}
+		}
+	}
+
+	payload, err := buildIssueChangeRecordPayload(issue, activityList, historyMap)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal change record of issue ID %d, error: %w", issue.ID, err)
+	}
+	record, err := s.store.CreateIssueChangeRecord(ctx, &api.IssueChangeRecordCreate{
+		CreatorID: principalID,
+		CreatedTs: time.Now().Unix(),
+		IssueID:   issue.ID,
+		Payload:   string(bytes),
+	}, []byte(s.secret))
+	if err != nil {
+		// The concurrent request has taken the record first, which is the one to keep.
+		if common.ErrorCode(err) == common.Conflict {
+			return s.store.GetIssueChangeRecordByIssueID(ctx, issue.ID)
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// findTaskMigrationHistory returns the migration history recorded by the last done run of the task, nil if there is none.
+func (s *Server) findTaskMigrationHistory(ctx context.Context, task *api.Task) (*db.MigrationHistory, error) {
+	if task.Database == nil {
+		return nil, nil
+	}
+	var result *api.TaskRunResultPayload
+	for _, taskRun := range task.TaskRunList {
+		if taskRun.Status != api.TaskRunDone {
+			continue
+		}
+		result = &api.TaskRunResultPayload{}
+		if err := json.Unmarshal([]byte(taskRun.Result), result); err != nil {
+			return nil, fmt.Errorf("invalid result of task run ID %d, error: %w", taskRun.ID, err)
+		}
+	}
+	if result == nil || result.MigrationID == 0 {
+		return nil, nil
+	}
+
+	driver, err := s.getAdminDatabaseDriver(ctx, task.Instance, "" /* databaseName */)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect instance %q, error: %w", task.Instance.Name, err)
+	}
+	defer driver.Close(ctx)
+	historyID := int(result.MigrationID)
+	list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{ID: &historyID, Database: &task.Database.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find migration history ID %d of database %q, error: %w", historyID, task.Database.Name, err)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// buildIssueChangeRecordPayload builds the change record of the issue, the historyMap is the migration history of the tasks by the task ID.
+func buildIssueChangeRecordPayload(issue *api.Issue, activityList []*api.Activity, historyMap map[int]*db.MigrationHistory) (*api.IssueChangeRecordPayload, error) {
+	_, doneTs, err := getIssueSLAMilestones(issue, activityList)
+	if err != nil {
+		return nil, err
+	}
+	payload := &api.IssueChangeRecordPayload{
+		IssueName:    issue.Name,
+		IssueType:    issue.Type,
+		CreatedTs:    issue.CreatedTs,
+		DoneTs:       doneTs,
+		ApprovalList: []*api.IssueChangeRecordApproval{},
+		TaskList:     []*api.IssueChangeRecordTask{},
+	}
+	if issue.Project != nil {
+		payload.ProjectName = issue.Project.Name
+	}
+	if issue.Creator != nil {
+		payload.CreatorName = issue.Creator.Name
+	}
+
+	for _, activity := range activityList {
+		if activity.Type != api.ActivityPipelineTaskStatusUpdate {
+			continue
+		}
+		activityPayload := &api.ActivityPipelineTaskStatusUpdatePayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), activityPayload); err != nil {
+			return nil, fmt.Errorf("invalid payload of activity ID %d, error: %w", activity.ID, err)
+		}
+		if activityPayload.OldStatus != api.TaskPendingApproval || activityPayload.NewStatus != api.TaskPending {
+			continue
+		}
+		approval := &api.IssueChangeRecordApproval{
+			TaskName:   activityPayload.TaskName,
+			ApprovedTs: activity.CreatedTs,
+		}
+		if activity.Creator != nil {
+			approval.ApproverName = activity.Creator.Name
+			approval.ApproverEmail = activity.Creator.Email
+		}
+		payload.ApprovalList = append(payload.ApprovalList, approval)
+	}
+
+	if issue.Pipeline == nil {
+		return payload, nil
+	}
+	for _, stage := range issue.Pipeline.StageList {
+		for _, task := range stage.TaskList {
+			statement, err := getTaskStatement(task)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get statement of task ID %d, error: %w", task.ID, err)
+			}
+			recordTask := &api.IssueChangeRecordTask{
+				Name:      task.Name,
+				Type:      task.Type,
+				Status:    task.Status,
+				Statement: statement,
+				RunList:   []*api.IssueChangeRecordTaskRun{},
+			}
+			if stage.Environment != nil {
+				recordTask.EnvironmentName = stage.Environment.Name
+			}
+			if task.Instance != nil {
+				recordTask.InstanceName = task.Instance.Name
+			}
+			if task.Database != nil {
+				recordTask.DatabaseName = task.Database.Name
+			}
+			for _, taskRun := range task.TaskRunList {
+				recordRun := &api.IssueChangeRecordTaskRun{
+					Status:    taskRun.Status,
+					StartedTs: taskRun.CreatedTs,
+					EndedTs:   taskRun.UpdatedTs,
+					Detail:    taskRun.Comment,
+				}
+				result := &api.TaskRunResultPayload{}
+				if taskRun.Result != "" && json.Unmarshal([]byte(taskRun.Result), result) == nil && result.Detail != "" {
+					recordRun.Detail = result.Detail
+				}
+				recordTask.RunList = append(recordTask.RunList, recordRun)
+			}
+			if history, ok := historyMap[task.ID]; ok {
+				diff, err := api.DiffSchemaDump(history.SchemaPrev, history.Schema)
+				if err != nil {
+					return nil, fmt.Errorf("failed to diff schema of task ID %d, error: %w", task.ID, err)
+				}
+				recordTask.SchemaVersion = history.Version
+				recordTask.SchemaDiff = diff
+			}
+			payload.TaskList = append(payload.TaskList, recordTask)
+		}
+	}
+	return payload, nil
+}
+
+// issueChangeRecordDoc is the content of the rendered change record.
+type issueChangeRecordDoc struct {
+	IssueID   int
+	RecordID  int
+	CreatedTs int64
+	Creator   string
+	Signature string
+	*api.IssueChangeRecordPayload
+}
+
+func renderIssueChangeRecord(record *api.IssueChangeRecord, format api.SchemaDocFormat) ([]byte, error) {
+	payload := &api.IssueChangeRecordPayload{}
+	if err := json.Unmarshal([]byte(record.Payload), payload); err != nil {
+		return nil, fmt.Errorf("invalid payload of change record ID %d, error: %w", record.ID, err)
+	}
+	doc := &issueChangeRecordDoc{
+		IssueID:                  record.IssueID,
+		RecordID:                 record.ID,
+		CreatedTs:                record.CreatedTs,
+		Signature:                record.Signature,
+		IssueChangeRecordPayload: payload,
+	}
+	if record.Creator != nil {
+		doc.Creator = record.Creator.Name
+	}
+
+	switch format {
+	case api.SchemaDocFormatHTML:
+		var buf bytes.Buffer
+		if err := issueChangeRecordHTMLTemplate.Execute(&buf, doc); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case api.SchemaDocFormatPDF:
+		return renderIssueChangeRecordPDF(doc), nil
+	}
+	return nil, fmt.Errorf("unsupported change record format %q", format)
+}
+
+// formatChangeRecordTs formats the timestamp in UTC, so the record reads the same wherever it's rendered.
+func formatChangeRecordTs(ts int64) string {
+	if ts == 0 {
+		return "-"
+	}
+	return time.Unix(ts, 0).UTC().Format(time.RFC3339)
+}
+
+var issueChangeRecordHTMLTemplate = template.Must(template.New("issueChangeRecord").Funcs(template.FuncMap{
+	"ts": formatChangeRecordTs,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Change record of issue #{{.IssueID}} {{.IssueName}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2937; }
+h1, h2, h3 { color: #4f46e5; }
+table { border-collapse: collapse; margin-bottom: 1rem; }
+th, td { border: 1px solid #d1d5db; padding: 0.25rem 0.5rem; text-align: left; vertical-align: top; }
+th { background-color: #4f46e5; color: #ffffff; }
+pre { background-color: #f3f4f6; padding: 0.5rem; white-space: pre-wrap; }
+footer { margin-top: 2rem; color: #6b7280; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>Change record of issue #{{.IssueID}}</h1>
+<table>
+<tr><th>Issue</th><td>{{.IssueName}}</td></tr>
+<tr><th>Type</th><td>{{.IssueType}}</td></tr>
+<tr><th>Project</th><td>{{.ProjectName}}</td></tr>
+<tr><th>Creator</th><td>{{.CreatorName}}</td></tr>
+<tr><th>Created at</th><td>{{ts .CreatedTs}}</td></tr>
+<tr><th>Done at</th><td>{{ts .DoneTs}}</td></tr>
+</table>
+<h2>Approvals</h2>
+{{if .ApprovalList}}<table>
+<tr><th>Task</th><th>Approver</th><th>Approved at</th></tr>
+{{range .ApprovalList}}<tr><td>{{.TaskName}}</td><td>{{.ApproverName}} &lt;{{.ApproverEmail}}&gt;</td><td>{{ts .ApprovedTs}}</td></tr>
+{{end}}</table>{{else}}<p>No approval was required.</p>{{end}}
+<h2>Tasks</h2>
+{{range .TaskList}}
+<h3>{{.Name}}</h3>
+<table>
+<tr><th>Type</th><td>{{.Type}}</td></tr>
+<tr><th>Status</th><td>{{.Status}}</td></tr>
+<tr><th>Environment</th><td>{{.EnvironmentName}}</td></tr>
+<tr><th>Instance</th><td>{{.InstanceName}}</td></tr>
+{{if .DatabaseName}}<tr><th>Database</th><td>{{.DatabaseName}}</td></tr>{{end}}
+{{if .SchemaVersion}}<tr><th>Schema version</th><td>{{.SchemaVersion}}</td></tr>{{end}}
+</table>
+{{if .Statement}}<h4>Statement</h4>
+<pre>{{.Statement}}</pre>{{end}}
+{{if .RunList}}<h4>Runs</h4>
+<table>
+<tr><th>Status</th><th>Started at</th><th>Ended at</th><th>Detail</th></tr>
+{{range .RunList}}<tr><td>{{.Status}}</td><td>{{ts .StartedTs}}</td><td>{{ts .EndedTs}}</td><td>{{.Detail}}</td></tr>
+{{end}}</table>{{end}}
+{{if .SchemaVersion}}<h4>Schema diff</h4>
+{{if .SchemaDiff}}<pre>{{.SchemaDiff}}</pre>{{else}}<p>The schema is unchanged.</p>{{end}}{{end}}
+{{end}}
+<footer>
+<p>Record #{{.RecordID}} taken at {{ts .CreatedTs}} by {{.Creator}}.</p>
+<p>Signature (HMAC-SHA256): {{.Signature}}</p>
+</footer>
+</body>
+</html>
+`))
+
+// renderIssueChangeRecordPDF renders the text-only PDF.
+func renderIssueChangeRecordPDF(doc *issueChangeRecordDoc) []byte {
+	d := pdf.NewDocument()
+	d.AddText(fmt.Sprintf("Change record of issue #%d", doc.IssueID), 20, true)
+	d.AddSpace(4)
+	for _, field := range [][2]string{
+		{"Issue", doc.IssueName},
+		{"Type", string(doc.IssueType)},
+		{"Project", doc.ProjectName},
+		{"Creator", doc.CreatorName},
+		{"Created at", formatChangeRecordTs(doc.CreatedTs)},
+		{"Done at", formatChangeRecordTs(doc.DoneTs)},
+	} {
+		d.AddText(fmt.Sprintf("%s: %s", field[0], field[1]), 10, false)
+	}
+
+	d.AddSpace(12)
+	d.AddText("Approvals", 16, true)
+	if len(doc.ApprovalList) == 0 {
+		d.AddText("No approval was required.", 10, false)
+	}
+	for _, approval := range doc.ApprovalList {
+		d.AddText(fmt.Sprintf("%s approved by %s <%s> at %s", approval.TaskName, approval.ApproverName, approval.ApproverEmail, formatChangeRecordTs(approval.ApprovedTs)), 10, false)
+	}
+
+	d.AddSpace(12)
+	d.AddText("Tasks", 16, true)
+	for _, task := range doc.TaskList {
+		d.AddSpace(8)
+		d.AddText(task.Name, 13, true)
+		location := fmt.Sprintf("%s / %s", task.EnvironmentName, task.InstanceName)
+		if task.DatabaseName != "" {
+			location += fmt.Sprintf(" / %s", task.DatabaseName)
+		}
+		d.AddText(fmt.Sprintf("%s, %s, on %s", task.Type, task.Status, location), 10, false)
+		if task.Statement != "" {
+			d.AddText("Statement", 10, true)
+			d.AddIndentedText(task.Statement, 9, false, 12)
+		}
+		if len(task.RunList) > 0 {
+			d.AddText("Runs", 10, true)
+			for _, run := range task.RunList {
+				text := fmt.Sprintf("%s  %s - %s", run.Status, formatChangeRecordTs(run.StartedTs), formatChangeRecordTs(run.EndedTs))
+				if run.Detail != "" {
+					text += fmt.Sprintf("  %s", run.Detail)
+				}
+				d.AddIndentedText(text, 9, false, 12)
+			}
+		}
+		if task.SchemaVersion != "" {
+			d.AddText(fmt.Sprintf("Schema diff of version %s", task.SchemaVersion), 10, true)
+			if task.SchemaDiff == "" {
+				d.AddIndentedText("The schema is unchanged.", 9, false, 12)
+			} else {
+				d.AddIndentedText(task.SchemaDiff, 9, false, 12)
+			}
+		}
+	}
+
+	d.AddSpace(12)
+	d.AddText(fmt.Sprintf("Record #%d taken at %s by %s.", doc.RecordID, formatChangeRecordTs(doc.CreatedTs), doc.Creator), 9, false)
+	d.AddText(fmt.Sprintf("Signature (HMAC-SHA256): %s", doc.Signature), 9, false)
+	return d.Bytes()
+}
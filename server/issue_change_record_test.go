@@ -0,0 +1,116 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIssueChangeRecordPayload(t *testing.T) {
+	databaseID := 201
+	issue := &api.Issue{
+		Name:      "Add column",
+		Type:      api.IssueDatabaseSchemaUpdate,
+		Status:    api.IssueDone,
+		CreatedTs: 1000,
+		UpdatedTs: 9000,
+		Project:   &api.Project{Name: "Shop"},
+		Creator:   &api.Principal{Name: "Alice"},
+		Pipeline: &api.Pipeline{
+			StageList: []*api.Stage{
+				{
+					Environment: &api.Environment{Name: "Prod"},
+					TaskList: []*api.Task{
+						{
+							ID:         301,
+							Name:       "Update shop",
+							Type:       api.TaskDatabaseSchemaUpdate,
+							Status:     api.TaskDone,
+							Instance:   &api.Instance{Name: "mysql-prod"},
+							DatabaseID: &databaseID,
+							Database:   &api.Database{ID: databaseID, Name: "shop"},
+							Payload:    `{"statement":"ALTER TABLE t ADD COLUMN name TEXT;"}`,
+							TaskRunList: []*api.TaskRun{
+								{Status: api.TaskRunFailed, CreatedTs: 2000, UpdatedTs: 2100, Comment: "lock wait timeout"},
+								{Status: api.TaskRunDone, CreatedTs: 3000, UpdatedTs: 3100, Result: `{"detail":"Applied migration","migrationId":5}`},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	activityList := []*api.Activity{
+		{
+			Type:      api.ActivityPipelineTaskStatusUpdate,
+			CreatedTs: 1500,
+			Creator:   &api.Principal{Name: "Bob", Email: "bob@example.com"},
+			Payload:   `{"taskId":301,"oldStatus":"PENDING_APPROVAL","newStatus":"PENDING","taskName":"Update shop"}`,
+		},
+		{
+			Type:      api.ActivityPipelineTaskStatusUpdate,
+			CreatedTs: 2000,
+			Payload:   `{"taskId":301,"oldStatus":"PENDING","newStatus":"RUNNING","taskName":"Update shop"}`,
+		},
+		{
+			Type:      api.ActivityIssueStatusUpdate,
+			CreatedTs: 4000,
+			Payload:   `{"oldStatus":"OPEN","newStatus":"DONE"}`,
+		},
+	}
+	historyMap := map[int]*db.MigrationHistory{
+		301: {
+			Version:    "0002",
+			SchemaPrev: "CREATE TABLE t (\n  id INT\n);\n",
+			Schema:     "CREATE TABLE t (\n  id INT,\n  name TEXT\n);\n",
+		},
+	}
+
+	payload, err := buildIssueChangeRecordPayload(issue, activityList, historyMap)
+	require.NoError(t, err)
+	require.Equal(t, "Shop", payload.ProjectName)
+	require.Equal(t, "Alice", payload.CreatorName)
+	require.Equal(t, int64(4000), payload.DoneTs)
+	require.Equal(t, []*api.IssueChangeRecordApproval{
+		{TaskName: "Update shop", ApproverName: "Bob", ApproverEmail: "bob@example.com", ApprovedTs: 1500},
+	}, payload.ApprovalList)
+
+	require.Len(t, payload.TaskList, 1)
+	task := payload.TaskList[0]
+	require.Equal(t, "Prod", task.EnvironmentName)
+	require.Equal(t, "mysql-prod", task.InstanceName)
+	require.Equal(t, "shop", task.DatabaseName)
+	require.Equal(t, "ALTER TABLE t ADD COLUMN name TEXT;", task.Statement)
+	require.Equal(t, []*api.IssueChangeRecordTaskRun{
+		{Status: api.TaskRunFailed, StartedTs: 2000, EndedTs: 2100, Detail: "lock wait timeout"},
+		{Status: api.TaskRunDone, StartedTs: 3000, EndedTs: 3100, Detail: "Applied migration"},
+	}, task.RunList)
+	require.Equal(t, "0002", task.SchemaVersion)
+	require.Contains(t, task.SchemaDiff, "+  name TEXT\n")
+}
+
+func TestRenderIssueChangeRecordHTML(t *testing.T) {
+	record := &api.IssueChangeRecord{
+		ID:        101,
+		CreatedTs: 1654041600,
+		IssueID:   102,
+		Creator:   &api.Principal{Name: "Carol"},
+		Payload:   `{"issueName":"<script>alert(1)</script>","approvalList":[],"taskList":[{"name":"Update shop","statement":"SELECT 1;","runList":[],"schemaVersion":"0002","schemaDiff":""}]}`,
+		Signature: "abc123",
+	}
+	content, err := renderIssueChangeRecord(record, api.SchemaDocFormatHTML)
+	require.NoError(t, err)
+	html := string(content)
+	require.Contains(t, html, "&lt;script&gt;alert(1)&lt;/script&gt;")
+	require.NotContains(t, html, "<script>")
+	require.Contains(t, html, "No approval was required.")
+	require.Contains(t, html, "The schema is unchanged.")
+	require.Contains(t, html, "Record #101 taken at 2022-06-01T00:00:00Z by Carol.")
+	require.Contains(t, html, "Signature (HMAC-SHA256): abc123")
+
+	content, err = renderIssueChangeRecord(record, api.SchemaDocFormatPDF)
+	require.NoError(t, err)
+	require.True(t, len(content) > 0 && string(content[:5]) == "%PDF-")
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerIssueDependencyRoutes(g *echo.Group) {
+	// Declares the issue is blocked by another issue.
+	g.POST("/issue/:issueID/dependency", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issueID, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		dependencyCreate := &api.IssueDependencyCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, dependencyCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create issue dependency request").SetInternal(err)
+		}
+		dependencyCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		dependencyCreate.BlockedIssueID = issueID
+		if dependencyCreate.BlockingIssueID == issueID {
+			return echo.NewHTTPError(http.StatusBadRequest, "An issue can't block itself")
+		}
+
+		issue, err := s.store.GetIssueByID(ctx, issueID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", issueID)).SetInternal(err)
+		}
+		if issue == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", issueID))
+		}
+		if issue.Status != api.IssueOpen {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue %d is %s, only the open issue can be blocked", issueID, issue.Status))
+		}
+		blockingIssue, err := s.store.GetIssueByID(ctx, dependencyCreate.BlockingIssueID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", dependencyCreate.BlockingIssueID)).SetInternal(err)
+		}
+		if blockingIssue == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Blocking issue ID not found: %d", dependencyCreate.BlockingIssueID))
+		}
+		if blockingIssue.Status == api.IssueCanceled {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue %d is canceled and would block issue %d forever", blockingIssue.ID, issueID))
+		}
+
+		// The blocking issue mustn't be blocked by the issue directly or indirectly, otherwise neither can start.
+		cycle, err := isIssueBlockedBy(dependencyCreate.BlockingIssueID, issueID, func(blockedIssueID int) ([]int, error) {
+			return s.getBlockingIssueIDList(ctx, blockedIssueID)
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check the dependencies of issue %d", dependencyCreate.BlockingIssueID)).SetInternal(err)
+		}
+		if cycle {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue %d is already blocked by issue %d, the dependency would form a cycle", dependencyCreate.BlockingIssueID, issueID))
+		}
+
+		dependency, err := s.store.CreateIssueDependency(ctx, dependencyCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Issue %d is already blocked by issue %d", issueID, dependencyCreate.BlockingIssueID))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to block issue %d by issue %d", issueID, dependencyCreate.BlockingIssueID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dependency); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create issue dependency response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Returns both the issues blocking the issue and the issues blocked by the issue.
+	g.GET("/issue/:issueID/dependency", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issueID, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		blockedByList, err := s.store.FindIssueDependency(ctx, &api.IssueDependencyFind{BlockedIssueID: &issueID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the issues blocking issue %d", issueID)).SetInternal(err)
+		}
+		blockingList, err := s.store.FindIssueDependency(ctx, &api.IssueDependencyFind{BlockingIssueID: &issueID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the issues blocked by issue %d", issueID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, append(blockedByList, blockingList...)); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue dependency list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/issue/:issueID/dependency/:blockingIssueID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issueID, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+		blockingIssueID, err := strconv.Atoi(c.Param("blockingIssueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Blocking issue ID is not a number: %s", c.Param("blockingIssueID"))).SetInternal(err)
+		}
+
+		if err := s.store.DeleteIssueDependency(ctx, &api.IssueDependencyDelete{
+			BlockingIssueID: blockingIssueID,
+			BlockedIssueID:  issueID,
+		}); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unblock issue %d from issue %d", issueID, blockingIssueID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// getBlockingIssueIDList returns the IDs of the issues blocking the issue.
+func (s *Server) getBlockingIssueIDList(ctx context.Context, blockedIssueID int) ([]int, error) {
+	dependencyList, err := s.store.FindIssueDependency(ctx, &api.IssueDependencyFind{BlockedIssueID: &blockedIssueID})
+	if err != nil {
+		return nil, err
+	}
+	var idList []int
+	for _, dependency := range dependencyList {
+		idList = append(idList, dependency.BlockingIssueID)
+	}
+	return idList, nil
+}
+
+// isIssueBlockedBy returns true if the issue is blocked by the other issue directly or indirectly.
+func isIssueBlockedBy(issueID, otherIssueID int, getBlockingIssueIDList func(blockedIssueID int) ([]int, error)) (bool, error) {
+	visited := map[int]bool{issueID: true}
+	queue := []int{issueID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		blockingIDList, err := getBlockingIssueIDList(id)
+		if err != nil {
+			return false, err
+		}
+		for _, blockingID := range blockingIDList {
+			if blockingID == otherIssueID {
+				return true, nil
+			}
+			if !visited[blockingID] {
+				visited[blockingID] = true
+				queue = append(queue, blockingID)
+			}
+		}
+	}
+	return false, nil
+}
+
+// getUnfinishedBlockingIssueList returns the issues blocking the issue which are not done yet.
+func (s *Server) getUnfinishedBlockingIssueList(ctx context.Context, issueID int) ([]*api.Issue, error) {
+	blockingIDList, err := s.getBlockingIssueIDList(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	var issueList []*api.Issue
+	for _, id := range blockingIDList {
+		issue, err := s.store.GetIssueByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil && issue.Status != api.IssueDone {
+			issueList = append(issueList, issue)
+		}
+	}
+	return issueList, nil
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsIssueBlockedBy(t *testing.T) {
+	// 103 is blocked by 102, which is blocked by 101. 104 is blocked by 101.
+	blockingMap := map[int][]int{
+		102: {101},
+		103: {102},
+		104: {101},
+	}
+	getBlockingIssueIDList := func(blockedIssueID int) ([]int, error) {
+		return blockingMap[blockedIssueID], nil
+	}
+
+	tests := []struct {
+		issueID      int
+		otherIssueID int
+		want         bool
+	}{
+		{issueID: 102, otherIssueID: 101, want: true},
+		{issueID: 103, otherIssueID: 101, want: true},
+		{issueID: 101, otherIssueID: 103, want: false},
+		{issueID: 103, otherIssueID: 104, want: false},
+		{issueID: 105, otherIssueID: 101, want: false},
+	}
+	for _, test := range tests {
+		blocked, err := isIssueBlockedBy(test.issueID, test.otherIssueID, getBlockingIssueIDList)
+		require.NoError(t, err)
+		require.Equal(t, test.want, blocked, "%d blocked by %d", test.issueID, test.otherIssueID)
+	}
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerIssueFieldRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/issue-field", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		fieldList, err := s.store.FindIssueField(ctx, &api.IssueFieldFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue field list for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, fieldList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal issue field list response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/project/:projectID/issue-field", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+		}
+		if project.RowStatus == api.Archived {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %q is archived", project.Name))
+		}
+
+		fieldCreate := &api.IssueFieldCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, fieldCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create issue field request").SetInternal(err)
+		}
+		fieldCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		fieldCreate.ProjectID = projectID
+		fieldCreate.Name = strings.TrimSpace(fieldCreate.Name)
+
+		if err := api.ValidateIssueField(fieldCreate.Name, fieldCreate.Type, fieldCreate.EnumValueList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		field, err := s.store.CreateIssueField(ctx, fieldCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Issue field %q already exists in project %q", fieldCreate.Name, project.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue field").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, field); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create issue field response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/issue-field/:fieldID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		field, err := s.getIssueFieldFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		fieldPatch := &api.IssueFieldPatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, fieldPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch issue field request").SetInternal(err)
+		}
+		fieldPatch.ID = field.ID
+		fieldPatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+
+		name := field.Name
+		if v := fieldPatch.Name; v != nil {
+			name = strings.TrimSpace(*v)
+			fieldPatch.Name = &name
+		}
+		enumValueList := field.EnumValueList
+		if v := fieldPatch.EnumValueList; v != nil {
+			enumValueList = nil
+			if *v != "" {
+				enumValueList = strings.Split(*v, ",")
+			}
+		}
+		if err := api.ValidateIssueField(name, field.Type, enumValueList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		field, err = s.store.PatchIssueField(ctx, fieldPatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Issue field %q already exists in the project", name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch issue field ID: %v", fieldPatch.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, field); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch issue field response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/project/:projectID/issue-field/:fieldID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		field, err := s.getIssueFieldFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		fieldDelete := &api.IssueFieldDelete{
+			ID:        field.ID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteIssueField(ctx, fieldDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete issue field ID: %v", field.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+
+	g.GET("/issue/:issueID/field-value", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issue, err := s.getFieldValueIssue(ctx, c.Param("issueID"))
+		if err != nil {
+			return err
+		}
+
+		valueList, err := s.store.FindIssueFieldValue(ctx, &api.IssueFieldValueFind{IssueID: &issue.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch field value list for issue ID: %d", issue.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, valueList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal issue field value list response: %v", issue.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/issue/:issueID/field-value/:fieldID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issue, field, err := s.getIssueAndFieldFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		valueUpsert := &api.IssueFieldValueUpsert{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, valueUpsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed set issue field value request").SetInternal(err)
+		}
+		valueUpsert.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+		valueUpsert.IssueID = issue.ID
+		valueUpsert.FieldID = field.ID
+
+		if err := api.ValidateIssueFieldValue(field, valueUpsert.Value); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if field.Type == api.IssueFieldUser {
+			principalID, _ := strconv.Atoi(valueUpsert.Value)
+			principal, err := s.store.GetPrincipalByID(ctx, principalID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch principal ID: %v", principalID)).SetInternal(err)
+			}
+			if principal == nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Principal ID not found for field %q: %d", field.Name, principalID))
+			}
+		}
+
+		value, err := s.store.UpsertIssueFieldValue(ctx, valueUpsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set value of field %q for issue ID: %v", field.Name, issue.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, value); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal set issue field value response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/issue/:issueID/field-value/:fieldID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		issue, field, err := s.getIssueAndFieldFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		valueDelete := &api.IssueFieldValueDelete{
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+			IssueID:   issue.ID,
+			FieldID:   field.ID,
+		}
+		if err := s.store.DeleteIssueFieldValue(ctx, valueDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to clear value of field %q for issue ID: %v", field.Name, issue.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// getIssueFieldFromParam returns the issue field specified by the ":projectID" and ":fieldID" params.
+// The returned error is an echo HTTP error.
+func (s *Server) getIssueFieldFromParam(ctx context.Context, c echo.Context) (*api.IssueField, error) {
+	projectID, err := strconv.Atoi(c.Param("projectID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+	}
+	fieldID, err := strconv.Atoi(c.Param("fieldID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Field ID is not a number: %s", c.Param("fieldID"))).SetInternal(err)
+	}
+	field, err := s.store.GetIssueFieldByID(ctx, fieldID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue field ID: %v", fieldID)).SetInternal(err)
+	}
+	if field == nil || field.ProjectID != projectID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue field not found by ID %d and project ID %d", fieldID, projectID))
+	}
+	return field, nil
+}
+
+// getIssueAndFieldFromParam returns the issue and the field specified by the ":issueID" and ":fieldID" params.
+// The field must be defined in the project of the issue. The returned error is an echo HTTP error.
+func (s *Server) getIssueAndFieldFromParam(ctx context.Context, c echo.Context) (*api.Issue, *api.IssueField, error) {
+	issue, err := s.getFieldValueIssue(ctx, c.Param("issueID"))
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldID, err := strconv.Atoi(c.Param("fieldID"))
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Field ID is not a number: %s", c.Param("fieldID"))).SetInternal(err)
+	}
+	field, err := s.store.GetIssueFieldByID(ctx, fieldID)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue field ID: %v", fieldID)).SetInternal(err)
+	}
+	if field == nil || field.ProjectID != issue.ProjectID {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue field not found by ID %d in the project of issue %d", fieldID, issue.ID))
+	}
+	return issue, field, nil
+}
+
+func (s *Server) getFieldValueIssue(ctx context.Context, issueIDStr string) (*api.Issue, error) {
+	issueID, err := strconv.Atoi(issueIDStr)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", issueIDStr)).SetInternal(err)
+	}
+	issue, err := s.store.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", issueID)).SetInternal(err)
+	}
+	if issue == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", issueID))
+	}
+	return issue, nil
+}
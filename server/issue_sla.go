@@ -0,0 +1,291 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+const (
+	// The default time range of the SLA report.
+	issueSLAReportDefaultRange = time.Duration(30*24) * time.Hour
+)
+
+func (s *Server) registerIssueSLARoutes(g *echo.Group) {
+	g.GET("/project/:projectID/issue-sla-setting", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		setting, err := s.store.GetIssueSLASettingByProjectID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get issue SLA setting for project ID: %d", projectID)).SetInternal(err)
+		}
+		if setting == nil {
+			// Returns the setting with UNKNOWN_ID to indicate the project has no setting.
+			setting = &api.IssueSLASetting{
+				ID:                api.UnknownID,
+				ProjectID:         projectID,
+				ReminderThreshold: api.DefaultIssueSLAReminderThreshold,
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal get issue SLA setting response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/issue-sla-setting", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find project with ID %d", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+		}
+
+		upsert := &api.IssueSLASettingUpsert{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed set issue SLA setting request").SetInternal(err)
+		}
+		upsert.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+		upsert.ProjectID = projectID
+		if upsert.ReminderThreshold == 0 {
+			upsert.ReminderThreshold = api.DefaultIssueSLAReminderThreshold
+		}
+		if upsert.TimeToApprove < 0 || upsert.TimeToExecute < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "The time to approve and the time to execute must not be negative")
+		}
+		if upsert.ReminderThreshold < 0 || upsert.ReminderThreshold > 100 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid reminder threshold %d, expect the percentage between 1 and 100", upsert.ReminderThreshold))
+		}
+
+		setting, err := s.store.UpsertIssueSLASetting(ctx, upsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set issue SLA setting for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal set issue SLA setting response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/project/:projectID/issue-sla-report", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		now := time.Now()
+		endTs := now.Unix()
+		if endStr := c.QueryParam("end"); endStr != "" {
+			if endTs, err = strconv.ParseInt(endStr, 10, 64); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter end is not a number: %s", endStr)).SetInternal(err)
+			}
+		}
+		startTs := endTs - int64(issueSLAReportDefaultRange.Seconds())
+		if startStr := c.QueryParam("start"); startStr != "" {
+			if startTs, err = strconv.ParseInt(startStr, 10, 64); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter start is not a number: %s", startStr)).SetInternal(err)
+			}
+		}
+		if startTs > endTs {
+			return echo.NewHTTPError(http.StatusBadRequest, "Query parameter start must not be after end")
+		}
+
+		setting, err := s.store.GetIssueSLASettingByProjectID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get issue SLA setting for project ID: %d", projectID)).SetInternal(err)
+		}
+		if setting == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID %d has no issue SLA setting", projectID))
+		}
+
+		issueList, err := s.store.FindIssue(ctx, &api.IssueFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue list for project ID: %d", projectID)).SetInternal(err)
+		}
+		var slaList []*api.IssueSLA
+		for _, issue := range issueList {
+			if issue.Status == api.IssueCanceled || issue.CreatedTs < startTs || issue.CreatedTs > endTs {
+				continue
+			}
+			sla, err := s.getIssueSLA(ctx, issue, setting, now)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compute SLA for issue %q", issue.Name)).SetInternal(err)
+			}
+			slaList = append(slaList, sla)
+		}
+
+		return c.JSON(http.StatusOK, buildIssueSLAReport(setting, startTs, endTs, slaList))
+	})
+}
+
+// getIssueSLA computes the SLA timers of the issue from its activities.
+func (s *Server) getIssueSLA(ctx context.Context, issue *api.Issue, setting *api.IssueSLASetting, now time.Time) (*api.IssueSLA, error) {
+	activityList, err := s.store.FindActivity(ctx, &api.ActivityFind{ContainerID: &issue.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find activity list for issue ID %d, error: %w", issue.ID, err)
+	}
+	approvedTs, doneTs, err := getIssueSLAMilestones(issue, activityList)
+	if err != nil {
+		return nil, err
+	}
+	return computeIssueSLA(issue, setting, approvedTs, doneTs, now), nil
+}
+
+// getIssueSLAMilestones returns when the issue was approved and when it was resolved, 0 means not yet.
+// The issue is approved when its first task is approved. If the first task requires no approval, the issue is
+// approved once it's created.
+func getIssueSLAMilestones(issue *api.Issue, activityList []*api.Activity) (int64, int64, error) {
+	var approvedTs, doneTs int64
+	for _, activity := range activityList {
+		switch activity.Type {
+		case api.ActivityPipelineTaskStatusUpdate:
+			payload := &api.ActivityPipelineTaskStatusUpdatePayload{}
+			if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+				return 0, 0, fmt.Errorf("invalid payload of activity ID %d, error: %w", activity.ID, err)
+			}
+			if payload.OldStatus == api.TaskPendingApproval && payload.NewStatus == api.TaskPending {
+				if approvedTs == 0 || activity.CreatedTs < approvedTs {
+					approvedTs = activity.CreatedTs
+				}
+			}
+		case api.ActivityIssueStatusUpdate:
+			payload := &api.ActivityIssueStatusUpdatePayload{}
+			if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+				return 0, 0, fmt.Errorf("invalid payload of activity ID %d, error: %w", activity.ID, err)
+			}
+			// The issue may be reopened, so the last resolution counts.
+			if payload.NewStatus == api.IssueDone && activity.CreatedTs > doneTs {
+				doneTs = activity.CreatedTs
+			}
+		}
+	}
+
+	if approvedTs == 0 && issue.Pipeline != nil && len(issue.Pipeline.StageList) > 0 && len(issue.Pipeline.StageList[0].TaskList) > 0 {
+		if issue.Pipeline.StageList[0].TaskList[0].Status != api.TaskPendingApproval {
+			approvedTs = issue.CreatedTs
+		}
+	}
+	if issue.Status != api.IssueDone {
+		doneTs = 0
+	} else if doneTs == 0 {
+		doneTs = issue.UpdatedTs
+	}
+	if approvedTs == 0 && doneTs != 0 {
+		// The issue is resolved without the approval, e.g. resolved manually.
+		approvedTs = doneTs
+	}
+	return approvedTs, doneTs, nil
+}
+
+// computeIssueSLA computes the SLA timers of the issue.
+// The approve timer runs from the issue creation to the approval, and the execute timer runs from the approval to the resolution.
+func computeIssueSLA(issue *api.Issue, setting *api.IssueSLASetting, approvedTs int64, doneTs int64, now time.Time) *api.IssueSLA {
+	sla := &api.IssueSLA{
+		IssueID:     issue.ID,
+		IssueName:   issue.Name,
+		IssueStatus: issue.Status,
+		AssigneeID:  issue.AssigneeID,
+	}
+	if setting.TimeToApprove > 0 {
+		sla.ApproveTimer = getIssueSLATimer(api.IssueSLAPhaseApprove, issue.CreatedTs, setting.TimeToApprove, approvedTs, setting.ReminderThreshold, now)
+	}
+	if setting.TimeToExecute > 0 && approvedTs > 0 {
+		sla.ExecuteTimer = getIssueSLATimer(api.IssueSLAPhaseExecute, approvedTs, setting.TimeToExecute, doneTs, setting.ReminderThreshold, now)
+	}
+	return sla
+}
+
+func getIssueSLATimer(phase api.IssueSLAPhase, startedTs int64, duration int64, stoppedTs int64, reminderThreshold int, now time.Time) *api.IssueSLATimer {
+	timer := &api.IssueSLATimer{
+		Phase:      phase,
+		StartedTs:  startedTs,
+		DeadlineTs: startedTs + duration,
+		StoppedTs:  stoppedTs,
+	}
+	switch {
+	case stoppedTs > 0 && stoppedTs <= timer.DeadlineTs:
+		timer.Status = api.IssueSLAMet
+	case stoppedTs > 0 || now.Unix() > timer.DeadlineTs:
+		timer.Status = api.IssueSLABreached
+	case (now.Unix()-startedTs)*100 >= duration*int64(reminderThreshold):
+		timer.Status = api.IssueSLAAtRisk
+	default:
+		timer.Status = api.IssueSLAOnTrack
+	}
+	return timer
+}
+
+func buildIssueSLAReport(setting *api.IssueSLASetting, startTs int64, endTs int64, slaList []*api.IssueSLA) *api.IssueSLAReport {
+	report := &api.IssueSLAReport{
+		ProjectID:      setting.ProjectID,
+		StartTs:        startTs,
+		EndTs:          endTs,
+		TimeToApprove:  setting.TimeToApprove,
+		TimeToExecute:  setting.TimeToExecute,
+		ApproveSummary: &api.IssueSLAPhaseSummary{Phase: api.IssueSLAPhaseApprove},
+		ExecuteSummary: &api.IssueSLAPhaseSummary{Phase: api.IssueSLAPhaseExecute},
+		IssueList:      []*api.IssueSLA{},
+	}
+	var approveTotal, approveCount, executeTotal, executeCount int64
+	for _, sla := range slaList {
+		report.IssueList = append(report.IssueList, sla)
+		if timer := sla.ApproveTimer; timer != nil {
+			addIssueSLATimerToSummary(report.ApproveSummary, timer)
+			if timer.StoppedTs > 0 {
+				approveTotal += timer.StoppedTs - timer.StartedTs
+				approveCount++
+			}
+		}
+		if timer := sla.ExecuteTimer; timer != nil {
+			addIssueSLATimerToSummary(report.ExecuteSummary, timer)
+			if timer.StoppedTs > 0 {
+				executeTotal += timer.StoppedTs - timer.StartedTs
+				executeCount++
+			}
+		}
+	}
+	if approveCount > 0 {
+		report.ApproveSummary.AverageSeconds = approveTotal / approveCount
+	}
+	if executeCount > 0 {
+		report.ExecuteSummary.AverageSeconds = executeTotal / executeCount
+	}
+	return report
+}
+
+func addIssueSLATimerToSummary(summary *api.IssueSLAPhaseSummary, timer *api.IssueSLATimer) {
+	switch timer.Status {
+	case api.IssueSLAOnTrack:
+		summary.OnTrackCount++
+	case api.IssueSLAAtRisk:
+		summary.AtRiskCount++
+	case api.IssueSLAMet:
+		summary.MetCount++
+	case api.IssueSLABreached:
+		summary.BreachedCount++
+	}
+}
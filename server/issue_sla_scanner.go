@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// The chosen interval is a balance between the reminder delay and background load.
+	issueSLAScanInterval = time.Duration(5) * time.Minute
+)
+
+// NewIssueSLAScanner creates an issue SLA scanner.
+func NewIssueSLAScanner(server *Server) *IssueSLAScanner {
+	return &IssueSLAScanner{
+		server: server,
+	}
+}
+
+// IssueSLAScanner reminds the assignees of the open issues approaching the SLA deadline.
+type IssueSLAScanner struct {
+	server *Server
+}
+
+// Run will run the issue SLA scanner.
+func (s *IssueSLAScanner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(issueSLAScanInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Issue SLA scanner started and will run every %v", issueSLAScanInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						log.Error("Issue SLA scanner PANIC RECOVER", zap.Error(err))
+					}
+				}()
+				s.scan(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (s *IssueSLAScanner) scan(ctx context.Context, now time.Time) {
+	settingList, err := s.server.store.FindIssueSLASetting(ctx, &api.IssueSLASettingFind{})
+	if err != nil {
+		log.Error("Failed to find issue SLA setting list", zap.Error(err))
+		return
+	}
+	for _, setting := range settingList {
+		if setting.TimeToApprove == 0 && setting.TimeToExecute == 0 {
+			continue
+		}
+		statusList := []api.IssueStatus{api.IssueOpen}
+		issueList, err := s.server.store.FindIssue(ctx, &api.IssueFind{
+			ProjectID:  &setting.ProjectID,
+			StatusList: &statusList,
+		})
+		if err != nil {
+			log.Error("Failed to find open issue list", zap.Int("project", setting.ProjectID), zap.Error(err))
+			continue
+		}
+		for _, issue := range issueList {
+			if err := s.remindIfNeeded(ctx, issue, setting, now); err != nil {
+				log.Error("Failed to remind issue SLA",
+					zap.Int("issue_id", issue.ID),
+					zap.String("issue_name", issue.Name),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+// remindIfNeeded reminds the assignee once per phase when the running timer of the phase is at risk or breached.
+func (s *IssueSLAScanner) remindIfNeeded(ctx context.Context, issue *api.Issue, setting *api.IssueSLASetting, now time.Time) error {
+	activityList, err := s.server.store.FindActivity(ctx, &api.ActivityFind{ContainerID: &issue.ID})
+	if err != nil {
+		return fmt.Errorf("failed to find activity list, error: %w", err)
+	}
+	approvedTs, doneTs, err := getIssueSLAMilestones(issue, activityList)
+	if err != nil {
+		return err
+	}
+	sla := computeIssueSLA(issue, setting, approvedTs, doneTs, now)
+
+	remindedPhases := make(map[api.IssueSLAPhase]bool)
+	for _, activity := range activityList {
+		if activity.Type != api.ActivityIssueSLARemind {
+			continue
+		}
+		payload := &api.ActivityIssueSLARemindPayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+			return fmt.Errorf("invalid payload of activity ID %d, error: %w", activity.ID, err)
+		}
+		remindedPhases[payload.Phase] = true
+	}
+
+	for _, timer := range []*api.IssueSLATimer{sla.ApproveTimer, sla.ExecuteTimer} {
+		if timer == nil || timer.StoppedTs > 0 || remindedPhases[timer.Phase] {
+			continue
+		}
+		if timer.Status != api.IssueSLAAtRisk && timer.Status != api.IssueSLABreached {
+			continue
+		}
+		if err := s.remind(ctx, issue, timer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *IssueSLAScanner) remind(ctx context.Context, issue *api.Issue, timer *api.IssueSLATimer) error {
+	payload, err := json.Marshal(api.ActivityIssueSLARemindPayload{
+		Phase:      timer.Phase,
+		Status:     timer.Status,
+		DeadlineTs: timer.DeadlineTs,
+		IssueName:  issue.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity payload, error: %w", err)
+	}
+	comment := fmt.Sprintf("The %s SLA is due at %s.", issueSLAPhaseName(timer.Phase), time.Unix(timer.DeadlineTs, 0).UTC().Format(time.RFC3339))
+	if timer.Status == api.IssueSLABreached {
+		comment = fmt.Sprintf("The %s SLA was breached at %s.", issueSLAPhaseName(timer.Phase), time.Unix(timer.DeadlineTs, 0).UTC().Format(time.RFC3339))
+	}
+	activity, err := s.server.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.ID,
+		Type:        api.ActivityIssueSLARemind,
+		Level:       api.ActivityWarn,
+		Comment:     comment,
+		Payload:     string(payload),
+	}, &ActivityMeta{issue: issue})
+	if err != nil {
+		return fmt.Errorf("failed to create activity, error: %w", err)
+	}
+
+	// Only the assignee is reminded since it's the assignee who should act.
+	if issue.AssigneeID == api.SystemBotID {
+		return nil
+	}
+	if _, err := s.server.store.CreateInbox(ctx, &api.InboxCreate{
+		ReceiverID: issue.AssigneeID,
+		ActivityID: activity.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to post activity to assignee inbox: %d, error: %w", issue.AssigneeID, err)
+	}
+	return nil
+}
+
+func issueSLAPhaseName(phase api.IssueSLAPhase) string {
+	switch phase {
+	case api.IssueSLAPhaseApprove:
+		return "time to approve"
+	case api.IssueSLAPhaseExecute:
+		return "time to execute"
+	}
+	return string(phase)
+}
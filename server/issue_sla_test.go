@@ -0,0 +1,129 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIssueSLAMilestones(t *testing.T) {
+	newIssue := func(status api.IssueStatus, firstTaskStatus api.TaskStatus) *api.Issue {
+		return &api.Issue{
+			Status:    status,
+			CreatedTs: 1000,
+			UpdatedTs: 9000,
+			Pipeline: &api.Pipeline{
+				StageList: []*api.Stage{
+					{TaskList: []*api.Task{{Status: firstTaskStatus}}},
+				},
+			},
+		}
+	}
+	approve := &api.Activity{
+		Type:      api.ActivityPipelineTaskStatusUpdate,
+		CreatedTs: 2000,
+		Payload:   `{"oldStatus":"PENDING_APPROVAL","newStatus":"PENDING"}`,
+	}
+	run := &api.Activity{
+		Type:      api.ActivityPipelineTaskStatusUpdate,
+		CreatedTs: 2500,
+		Payload:   `{"oldStatus":"PENDING","newStatus":"RUNNING"}`,
+	}
+	resolve := &api.Activity{
+		Type:      api.ActivityIssueStatusUpdate,
+		CreatedTs: 3000,
+		Payload:   `{"oldStatus":"OPEN","newStatus":"DONE"}`,
+	}
+
+	tests := []struct {
+		name         string
+		issue        *api.Issue
+		activityList []*api.Activity
+		approvedTs   int64
+		doneTs       int64
+	}{
+		{
+			name:         "pending approval",
+			issue:        newIssue(api.IssueOpen, api.TaskPendingApproval),
+			activityList: nil,
+			approvedTs:   0,
+			doneTs:       0,
+		},
+		{
+			name:         "approved",
+			issue:        newIssue(api.IssueOpen, api.TaskRunning),
+			activityList: []*api.Activity{approve, run},
+			approvedTs:   2000,
+			doneTs:       0,
+		},
+		{
+			name:         "no approval required",
+			issue:        newIssue(api.IssueOpen, api.TaskPending),
+			activityList: nil,
+			approvedTs:   1000,
+			doneTs:       0,
+		},
+		{
+			name:         "resolved",
+			issue:        newIssue(api.IssueDone, api.TaskDone),
+			activityList: []*api.Activity{approve, run, resolve},
+			approvedTs:   2000,
+			doneTs:       3000,
+		},
+		{
+			name:         "reopened",
+			issue:        newIssue(api.IssueOpen, api.TaskDone),
+			activityList: []*api.Activity{approve, run, resolve},
+			approvedTs:   2000,
+			doneTs:       0,
+		},
+	}
+
+	for _, test := range tests {
+		approvedTs, doneTs, err := getIssueSLAMilestones(test.issue, test.activityList)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.approvedTs, approvedTs, test.name)
+		require.Equal(t, test.doneTs, doneTs, test.name)
+	}
+}
+
+func TestComputeIssueSLA(t *testing.T) {
+	setting := &api.IssueSLASetting{
+		TimeToApprove:     100,
+		TimeToExecute:     1000,
+		ReminderThreshold: 80,
+	}
+	issue := &api.Issue{ID: 1, CreatedTs: 1000}
+
+	// Pending approval, 50% of the time to approve elapsed.
+	sla := computeIssueSLA(issue, setting, 0, 0, time.Unix(1050, 0))
+	require.Equal(t, &api.IssueSLATimer{Phase: api.IssueSLAPhaseApprove, StartedTs: 1000, DeadlineTs: 1100, Status: api.IssueSLAOnTrack}, sla.ApproveTimer)
+	require.Nil(t, sla.ExecuteTimer)
+
+	// Pending approval, 80% of the time to approve elapsed.
+	sla = computeIssueSLA(issue, setting, 0, 0, time.Unix(1080, 0))
+	require.Equal(t, api.IssueSLAAtRisk, sla.ApproveTimer.Status)
+
+	// Approved late, executing.
+	sla = computeIssueSLA(issue, setting, 1200, 0, time.Unix(1300, 0))
+	require.Equal(t, &api.IssueSLATimer{Phase: api.IssueSLAPhaseApprove, StartedTs: 1000, DeadlineTs: 1100, StoppedTs: 1200, Status: api.IssueSLABreached}, sla.ApproveTimer)
+	require.Equal(t, &api.IssueSLATimer{Phase: api.IssueSLAPhaseExecute, StartedTs: 1200, DeadlineTs: 2200, Status: api.IssueSLAOnTrack}, sla.ExecuteTimer)
+
+	// Resolved in time.
+	sla = computeIssueSLA(issue, setting, 1050, 2000, time.Unix(5000, 0))
+	require.Equal(t, api.IssueSLAMet, sla.ApproveTimer.Status)
+	require.Equal(t, api.IssueSLAMet, sla.ExecuteTimer.Status)
+
+	// Executing past the deadline.
+	sla = computeIssueSLA(issue, setting, 1050, 0, time.Unix(5000, 0))
+	require.Equal(t, api.IssueSLABreached, sla.ExecuteTimer.Status)
+
+	report := buildIssueSLAReport(setting, 0, 5000, []*api.IssueSLA{
+		computeIssueSLA(issue, setting, 1050, 2000, time.Unix(5000, 0)),
+		computeIssueSLA(issue, setting, 1200, 0, time.Unix(5000, 0)),
+	})
+	require.Equal(t, &api.IssueSLAPhaseSummary{Phase: api.IssueSLAPhaseApprove, MetCount: 1, BreachedCount: 1, AverageSeconds: 125}, report.ApproveSummary)
+	require.Equal(t, &api.IssueSLAPhaseSummary{Phase: api.IssueSLAPhaseExecute, MetCount: 1, BreachedCount: 1, AverageSeconds: 950}, report.ExecuteSummary)
+}
@@ -114,6 +114,37 @@ func TestCheckCharacterSetCollationOwner(t *testing.T) {
 			expectError: false,
 		},
 
+		/* SQL Server */
+		// With character set
+		{
+			dbType:       db.MSSQL,
+			characterSet: "utf8mb4",
+			expectError:  true,
+		},
+		// Without collation
+		{
+			dbType:      db.MSSQL,
+			expectError: false,
+		},
+		// Normal
+		{
+			dbType:      db.MSSQL,
+			collation:   "Latin1_General_CI_AS",
+			expectError: false,
+		},
+		/* MongoDB */
+		// With collation
+		{
+			dbType:      db.MongoDB,
+			collation:   "en_US",
+			expectError: true,
+		},
+		// Normal
+		{
+			dbType:      db.MongoDB,
+			expectError: false,
+		},
+
 		/* PostgreSQL */
 		// Without owner
 		{
@@ -165,3 +196,62 @@ func TestCheckCharacterSetCollationOwner(t *testing.T) {
 		}
 	}
 }
+
+func TestGetDatabaseNameAndStatementPostgresOptions(t *testing.T) {
+	c := api.CreateDatabaseContext{
+		DatabaseName:    "hello",
+		CharacterSet:    "UTF8",
+		Owner:           "bytebase",
+		Tablespace:      "fast",
+		ConnectionLimit: 20,
+		ExtensionList:   []string{"pgcrypto", "postgis"},
+	}
+	databaseName, stmt := getDatabaseNameAndStatement(db.Postgres, c, "CREATE TABLE t(id INT);")
+	require.Equal(t, "hello", databaseName)
+	require.Equal(t, "CREATE DATABASE \"hello\" ENCODING \"UTF8\" TABLESPACE \"fast\" CONNECTION LIMIT 20;\n"+
+		"ALTER DATABASE \"hello\" OWNER TO bytebase;\n\n"+
+		"\\connect \"hello\";\n"+
+		"CREATE EXTENSION IF NOT EXISTS \"pgcrypto\";\n"+
+		"CREATE EXTENSION IF NOT EXISTS \"postgis\";\n"+
+		"CREATE TABLE t(id INT);", stmt)
+
+	_, stmt = getDatabaseNameAndStatement(db.Postgres, api.CreateDatabaseContext{DatabaseName: "hello", CharacterSet: "UTF8", Owner: "bytebase"}, "")
+	require.Equal(t, "CREATE DATABASE \"hello\" ENCODING \"UTF8\";\nALTER DATABASE \"hello\" OWNER TO bytebase;\n", stmt)
+
+	require.NoError(t, checkPostgresDatabaseOptions(db.Postgres, "fast", 20, []string{"pgcrypto"}))
+	require.Error(t, checkPostgresDatabaseOptions(db.Postgres, "", -1, nil))
+	require.Error(t, checkPostgresDatabaseOptions(db.MySQL, "fast", 0, nil))
+	require.Error(t, checkPostgresDatabaseOptions(db.MySQL, "", 0, []string{"pgcrypto"}))
+}
+
+func TestGetDatabaseNameAndStatementMSSQL(t *testing.T) {
+	c := api.CreateDatabaseContext{
+		DatabaseName: "hello",
+		Collation:    "Latin1_General_CI_AS",
+	}
+	databaseName, stmt := getDatabaseNameAndStatement(db.MSSQL, c, "CREATE TABLE t(id INT);")
+	require.Equal(t, "hello", databaseName)
+	require.Equal(t, "CREATE DATABASE [hello] COLLATE Latin1_General_CI_AS;\nGO\nUSE [hello];\nGO\nCREATE TABLE t(id INT);", stmt)
+
+	_, stmt = getDatabaseNameAndStatement(db.MSSQL, api.CreateDatabaseContext{DatabaseName: "hello"}, "")
+	require.Equal(t, "CREATE DATABASE [hello];", stmt)
+}
+
+func TestGetDatabaseNameAndStatementMongoDB(t *testing.T) {
+	databaseName, stmt := getDatabaseNameAndStatement(db.MongoDB, api.CreateDatabaseContext{DatabaseName: "hello"}, `db.createCollection("t");`)
+	require.Equal(t, "hello", databaseName)
+	require.Equal(t, "db = db.getSiblingDB(\"hello\");\ndb.createCollection(\"t\");", stmt)
+}
+
+func TestGetSubTaskSchemaVersion(t *testing.T) {
+	schemaVersion := "20220525103000"
+	previous := schemaVersion
+	for sequence := 1; sequence <= 12; sequence++ {
+		version := getSubTaskSchemaVersion(schemaVersion, sequence)
+		require.Greater(t, version, previous)
+		// The sub-tasks should precede the issues created later.
+		require.Less(t, version, "20220525103001")
+		previous = version
+	}
+	require.Equal(t, "20220525103000.0002", getSubTaskSchemaVersion(schemaVersion, 2))
+}
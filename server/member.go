@@ -1,13 +1,17 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
@@ -71,6 +75,46 @@ func (s *Server) registerMemberRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// The members are imported from the CSV content with the text/csv content type, or the JSON array content otherwise.
+	// The users are created along with the members, and the existing members are changed to the imported roles.
+	g.POST("/member/import", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		content, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read import member request").SetInternal(err)
+		}
+		var entryList []*api.MemberImportEntry
+		if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "text/csv") {
+			entryList, err = api.ParseMemberImportCSV(content)
+		} else {
+			entryList, err = api.ParseMemberImportJSON(content)
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed import member request").SetInternal(err)
+		}
+		if err := api.ValidateMemberImportList(entryList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid members: %s", err.Error()))
+		}
+
+		result := &api.MemberImportResult{}
+		creatorID := c.Get(getPrincipalIDContextKey()).(int)
+		for _, entry := range entryList {
+			changed, created, httpErr := s.importMember(ctx, entry, creatorID)
+			if httpErr != nil {
+				return httpErr
+			}
+			switch {
+			case created:
+				result.CreatedCount++
+			case changed:
+				result.UpdatedCount++
+			default:
+				result.SkippedCount++
+			}
+		}
+		return c.JSON(http.StatusOK, result)
+	})
+
 	g.GET("/member", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		memberFind := &api.MemberFind{}
@@ -184,3 +228,105 @@ func (s *Server) registerMemberRoutes(g *echo.Group) {
 		return nil
 	})
 }
+
+// importMember creates the user and the member of the imported member, or changes the role of the existing member.
+func (s *Server) importMember(ctx context.Context, entry *api.MemberImportEntry, creatorID int) (changed bool, created bool, httpErr *echo.HTTPError) {
+	user, err := s.store.GetPrincipalByEmail(ctx, entry.Email)
+	if err != nil {
+		return false, false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find user %s", entry.Email)).SetInternal(err)
+	}
+	if user == nil {
+		// The imported users have no chance to set their password, we use a random one so that
+		// they login via the VCS or have the password reset by the admin.
+		password, err := common.RandomString(20)
+		if err != nil {
+			return false, false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate random password").SetInternal(err)
+		}
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return false, false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate password hash").SetInternal(err)
+		}
+		user, err = s.store.CreatePrincipal(ctx, &api.PrincipalCreate{
+			CreatorID:    creatorID,
+			Type:         api.EndUser,
+			Name:         entry.Name,
+			Email:        entry.Email,
+			PasswordHash: string(passwordHash),
+		})
+		if err != nil {
+			return false, false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create user %s", entry.Email)).SetInternal(err)
+		}
+		created = true
+	} else if user.Type != api.EndUser {
+		return false, false, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s is not an end user", entry.Email))
+	}
+
+	member, err := s.store.GetMemberByPrincipalID(ctx, user.ID)
+	if err != nil {
+		return false, false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find member %s", entry.Email)).SetInternal(err)
+	}
+	if member == nil {
+		member, err = s.store.CreateMember(ctx, &api.MemberCreate{
+			CreatorID:   creatorID,
+			Status:      api.Active,
+			Role:        entry.Role,
+			PrincipalID: user.ID,
+		})
+		if err != nil {
+			return false, false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create member %s", entry.Email)).SetInternal(err)
+		}
+		bytes, err := json.Marshal(api.ActivityMemberCreatePayload{
+			PrincipalID:    member.PrincipalID,
+			PrincipalName:  user.Name,
+			PrincipalEmail: user.Email,
+			MemberStatus:   member.Status,
+			Role:           member.Role,
+		})
+		if err != nil {
+			return false, false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+			CreatorID:   creatorID,
+			ContainerID: member.ID,
+			Type:        api.ActivityMemberCreate,
+			Level:       api.ActivityInfo,
+			Payload:     string(bytes),
+		}, &ActivityMeta{}); err != nil {
+			return false, false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after creating member: %d", member.ID)).SetInternal(err)
+		}
+		return true, created, nil
+	}
+	if member.Role == entry.Role {
+		return false, created, nil
+	}
+
+	role := string(entry.Role)
+	updatedMember, err := s.store.PatchMember(ctx, &api.MemberPatch{
+		ID:        member.ID,
+		UpdaterID: creatorID,
+		Role:      &role,
+	})
+	if err != nil {
+		return false, false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to change the role of member %s", entry.Email)).SetInternal(err)
+	}
+	bytes, err := json.Marshal(api.ActivityMemberRoleUpdatePayload{
+		PrincipalID:    updatedMember.PrincipalID,
+		PrincipalName:  user.Name,
+		PrincipalEmail: user.Email,
+		OldRole:        member.Role,
+		NewRole:        updatedMember.Role,
+	})
+	if err != nil {
+		return false, false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   creatorID,
+		ContainerID: updatedMember.ID,
+		Type:        api.ActivityMemberRoleUpdate,
+		Level:       api.ActivityInfo,
+		Payload:     string(bytes),
+	}, &ActivityMeta{}); err != nil {
+		return false, false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after changing member role: %d", updatedMember.ID)).SetInternal(err)
+	}
+	return true, created, nil
+}
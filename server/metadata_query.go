@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// maxMetadataQueryLimit is the maximum row count returned by the metadata query.
+	maxMetadataQueryLimit = 1000
+	// metadataQueryTimeout is the timeout of the metadata query, so it doesn't hold the connections of the server for long.
+	metadataQueryTimeout = 30 * time.Second
+)
+
+func (s *Server) registerMetadataQueryRoutes(g *echo.Group) {
+	// The metadata query is only allowed for the owner by the ACL policy, and each query is recorded in the activity.
+	g.POST("/sql/metadata-query", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		query := &api.SQLMetadataQuery{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, query); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql metadata query request").SetInternal(err)
+		}
+		if !validateSQLSelectStatement(query.Statement) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql metadata query request, only support SELECT sql statement")
+		}
+		if query.Limit <= 0 || query.Limit > maxMetadataQueryLimit {
+			query.Limit = maxMetadataQueryLimit
+		}
+
+		start := time.Now().UnixNano()
+		result, queryErr := func() ([]interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, metadataQueryTimeout)
+			defer cancel()
+			return s.store.QueryMetadata(ctx, query.Statement, query.Limit)
+		}()
+
+		payload := api.ActivitySQLEditorMetadataQueryPayload{
+			Statement:  query.Statement,
+			DurationNs: time.Now().UnixNano() - start,
+		}
+		level := api.ActivityInfo
+		resultSet := &api.SQLResultSet{}
+		if queryErr != nil {
+			level = api.ActivityError
+			payload.Error = queryErr.Error()
+			resultSet.Error = queryErr.Error()
+		} else {
+			if len(result) == 3 {
+				if data, ok := result[2].([]interface{}); ok {
+					payload.RowCount = len(data)
+				}
+			}
+			bytes, err := json.Marshal(result)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql metadata query result").SetInternal(err)
+			}
+			resultSet.Data = string(bytes)
+		}
+		// The rejected queries are recorded as well.
+		if err := s.createSQLEditorMetadataQueryActivity(ctx, c, level, payload); err != nil {
+			return err
+		}
+		if common.ErrorCode(queryErr) == common.Invalid {
+			return echo.NewHTTPError(http.StatusBadRequest, queryErr.Error())
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql metadata query result set response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// createSQLEditorMetadataQueryActivity records the metadata query in the activity of the principal executing it.
+func (s *Server) createSQLEditorMetadataQueryActivity(ctx context.Context, c echo.Context, level api.ActivityLevel, payload api.ActivitySQLEditorMetadataQueryPayload) error {
+	activityBytes, err := json.Marshal(payload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   principalID,
+		Type:        api.ActivitySQLEditorMetadataQuery,
+		ContainerID: principalID,
+		Level:       level,
+		Comment:     fmt.Sprintf("Executed `%q` in the Bytebase metadata database.", payload.Statement),
+		Payload:     string(activityBytes),
+	}
+	if _, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		log.Warn("Failed to create activity after executing metadata query",
+			zap.String("statement", payload.Statement),
+			zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+	}
+	return nil
+}
@@ -36,14 +36,19 @@ type MetricReporter struct {
 	// Version is the bytebase's version
 	version     string
 	workspaceID string
-	reporter    metric.Reporter
-	collectors  map[string]metric.Collector
-	store       *store.Store
+	// reporter sends the usage metrics out of the workspace, it's nil if the reporting is disabled.
+	reporter   metric.Reporter
+	collectors map[string]metric.Collector
+	store      *store.Store
 }
 
 // NewMetricReporter creates a new metric scheduler.
-func NewMetricReporter(server *Server, workspaceID string) *MetricReporter {
-	r := segment.NewReporter(server.profile.MetricConnectionKey, workspaceID)
+// The usage metrics are always aggregated in the usage_metric table, and are reported daily only if reportEnabled is true.
+func NewMetricReporter(server *Server, workspaceID string, reportEnabled bool) *MetricReporter {
+	var r metric.Reporter
+	if reportEnabled {
+		r = segment.NewReporter(server.profile.MetricConnectionKey, workspaceID)
+	}
 
 	return &MetricReporter{
 		subscription: &server.subscription,
@@ -78,8 +83,7 @@ func (m *MetricReporter) Run(ctx context.Context, wg *sync.WaitGroup) {
 				}()
 
 				ctx := context.Background()
-				// identify will be triggered in every schedule loop so that we can update the latest workspace profile like subscription plan.
-				m.identify(ctx)
+				now := time.Now()
 				for name, collector := range m.collectors {
 					log.Debug("Run metric collector", zap.String("collector", name))
 
@@ -93,10 +97,13 @@ func (m *MetricReporter) Run(ctx context.Context, wg *sync.WaitGroup) {
 						continue
 					}
 
+					// The collected metrics are gauges, so the latest value of the day is kept.
 					for _, metric := range metricList {
-						m.report(metric)
+						m.record(ctx, now, metric, false /* increment */)
 					}
 				}
+
+				m.reportUsageMetric(ctx, now)
 			}()
 		case <-ctx.Done(): // if cancel() execute
 			return
@@ -106,7 +113,9 @@ func (m *MetricReporter) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 // Close will close the metric reporter.
 func (m *MetricReporter) Close() {
-	m.reporter.Close()
+	if m.reporter != nil {
+		m.reporter.Close()
+	}
 }
 
 // Register will register a metric collector.
@@ -149,12 +158,99 @@ func (m *MetricReporter) identify(ctx context.Context) {
 	}
 }
 
+// report records the event metric, e.g. an issue is created, by adding it to the usage metric of the day.
 func (m *MetricReporter) report(metric *metric.Metric) {
-	if err := m.reporter.Report(metric); err != nil {
+	m.record(context.Background(), time.Now(), metric, true /* increment */)
+}
+
+func (m *MetricReporter) record(ctx context.Context, now time.Time, metric *metric.Metric, increment bool) {
+	if _, err := m.store.UpsertUsageMetric(ctx, &api.UsageMetricUpsert{
+		Date:      api.GetUsageMetricDate(now),
+		Name:      string(metric.Name),
+		Labels:    metric.Labels,
+		Value:     int64(metric.Value),
+		Increment: increment,
+	}); err != nil {
 		log.Error(
-			"Failed to report metric",
+			"Failed to record metric",
 			zap.String("metric", string(metric.Name)),
 			zap.Error(err),
 		)
 	}
 }
+
+// reportUsageMetric reports the usage metrics of the past days that haven't been reported at the configured granularity.
+// The metrics of the current day are still changing, so they are reported on the next day.
+func (m *MetricReporter) reportUsageMetric(ctx context.Context, now time.Time) {
+	if m.reporter == nil {
+		return
+	}
+	level, err := m.getReportLevel(ctx)
+	if err != nil {
+		log.Error("Failed to get usage metric report level", zap.Error(err))
+		return
+	}
+
+	// identify will be triggered in every schedule loop so that we can update the latest workspace profile like subscription plan.
+	if level != api.UsageMetricReportNone {
+		m.identify(ctx)
+	}
+
+	yesterday := api.GetUsageMetricDate(now.AddDate(0, 0, -1))
+	reported := false
+	usageMetricList, err := m.store.FindUsageMetric(ctx, &api.UsageMetricFind{
+		ToDate:   &yesterday,
+		Reported: &reported,
+	})
+	if err != nil {
+		log.Error("Failed to find usage metrics to report", zap.Error(err))
+		return
+	}
+	if len(usageMetricList) == 0 {
+		return
+	}
+
+	for _, usageMetric := range api.AggregateUsageMetricList(usageMetricList, level) {
+		labels := map[string]string{"date": usageMetric.Date}
+		for k, v := range usageMetric.Labels {
+			labels[k] = v
+		}
+		if err := m.reporter.Report(&metric.Metric{
+			Name:   metric.Name(usageMetric.Name),
+			Value:  int(usageMetric.Value),
+			Labels: labels,
+		}); err != nil {
+			// Keep the metrics unreported so that they are reported in the next loop.
+			log.Error(
+				"Failed to report metric",
+				zap.String("metric", usageMetric.Name),
+				zap.Error(err),
+			)
+			return
+		}
+	}
+
+	// The metrics are marked as reported even if the level is NONE, so they won't be reported after opting in again.
+	reported = true
+	for _, usageMetric := range usageMetricList {
+		if _, err := m.store.PatchUsageMetric(ctx, &api.UsageMetricPatch{
+			ID:       usageMetric.ID,
+			Reported: &reported,
+		}); err != nil {
+			log.Error("Failed to mark usage metric as reported", zap.Int("id", usageMetric.ID), zap.Error(err))
+			return
+		}
+	}
+}
+
+func (m *MetricReporter) getReportLevel(ctx context.Context) (api.UsageMetricReportLevel, error) {
+	name := api.SettingWorkspaceMetricReportLevel
+	settingList, err := m.store.FindSetting(ctx, &api.SettingFind{Name: &name})
+	if err != nil {
+		return "", err
+	}
+	if len(settingList) == 0 {
+		return api.UsageMetricReportFull, nil
+	}
+	return api.UsageMetricReportLevel(settingList[0].Value), nil
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	flywayHistoryQuery = `
+		SELECT installed_rank, version, description, type, script, installed_by, installed_on, success
+		FROM flyway_schema_history
+		ORDER BY installed_rank`
+	liquibaseHistoryQuery = `
+		SELECT ID, AUTHOR, FILENAME, DATEEXECUTED, ORDEREXECUTED, EXECTYPE, DESCRIPTION, COMMENTS
+		FROM DATABASECHANGELOG
+		ORDER BY ORDEREXECUTED`
+)
+
+// importedMigration is a migration recorded by another migration tool.
+type importedMigration struct {
+	// rank is the execution order of the migration.
+	rank int
+	// version is the version recorded by the tool, e.g. the Flyway version or the Liquibase changeset ID.
+	version     string
+	description string
+	script      string
+	installedBy string
+	installedOn string
+	success     bool
+	baseline    bool
+}
+
+func (s *Server) registerMigrationImportRoutes(g *echo.Group) {
+	// The migrations are imported as recorded-only entries, nothing is executed on the database.
+	g.POST("/database/:id/migration-history/import", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		historyImport := &api.MigrationHistoryImport{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, historyImport); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed import migration history request").SetInternal(err)
+		}
+		var query string
+		switch historyImport.Source {
+		case db.FLYWAY:
+			query = flywayHistoryQuery
+		case db.LIQUIBASE:
+			query = liquibaseHistoryQuery
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported migration history source %q", historyImport.Source))
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		driver, err := s.getAdminDatabaseDriver(ctx, database.Instance, "" /* databaseName */)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to connect instance %q", database.Instance.Name)).SetInternal(err)
+		}
+		defer driver.Close(ctx)
+		setup, err := driver.NeedsSetupMigration(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check migration setup for instance %q", database.Instance.Name)).SetInternal(err)
+		}
+		if setup {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Missing migration schema for instance %q", database.Instance.Name))
+		}
+
+		sqldb, err := driver.GetDBConnection(ctx, database.Name)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to connect database %q", database.Name)).SetInternal(err)
+		}
+		migrationList, err := readImportedMigrationList(ctx, sqldb, historyImport.Source, query)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to read %s history from database %q", historyImport.Source, database.Name)).SetInternal(err)
+		}
+
+		result := &api.MigrationHistoryImportResult{}
+		for _, migration := range migrationList {
+			// The failed migrations don't change the schema, so they are not part of the history.
+			if !migration.success {
+				result.SkippedCount++
+				continue
+			}
+			mi, err := getImportedMigrationInfo(database, historyImport.Source, migration)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid %s migration %q", historyImport.Source, migration.version)).SetInternal(err)
+			}
+			mi.ReleaseVersion = s.profile.Version
+			list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{Database: &mi.Namespace, Version: &mi.Version})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch migration history list").SetInternal(err)
+			}
+			if len(list) > 0 {
+				result.SkippedCount++
+				continue
+			}
+			if _, _, err := driver.ExecuteMigration(ctx, mi, "" /* statement */); err != nil {
+				if common.ErrorCode(err) == common.MigrationOutOfOrder {
+					return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Database %q has migration history newer than the %s migration %q", database.Name, historyImport.Source, migration.version)).SetInternal(err)
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to record %s migration %q", historyImport.Source, migration.version)).SetInternal(err)
+			}
+			result.ImportedCount++
+		}
+
+		return c.JSON(http.StatusOK, result)
+	})
+}
+
+// readImportedMigrationList reads the migration history table of the migration tool in the execution order.
+func readImportedMigrationList(ctx context.Context, sqldb *sql.DB, source db.MigrationSource, query string) ([]*importedMigration, error) {
+	rows, err := sqldb.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var migrationList []*importedMigration
+	for rows.Next() {
+		migration := &importedMigration{}
+		switch source {
+		case db.FLYWAY:
+			var version sql.NullString
+			var migrationType string
+			if err := rows.Scan(
+				&migration.rank,
+				&version,
+				&migration.description,
+				&migrationType,
+				&migration.script,
+				&migration.installedBy,
+				&migration.installedOn,
+				&migration.success,
+			); err != nil {
+				return nil, err
+			}
+			// The repeatable migrations don't have a version.
+			migration.version = "R"
+			if version.Valid {
+				migration.version = version.String
+			}
+			migration.baseline = strings.Contains(migrationType, "BASELINE")
+		case db.LIQUIBASE:
+			var execType string
+			var description, comments sql.NullString
+			if err := rows.Scan(
+				&migration.version,
+				&migration.installedBy,
+				&migration.script,
+				&migration.installedOn,
+				&migration.rank,
+				&execType,
+				&description,
+				&comments,
+			); err != nil {
+				return nil, err
+			}
+			migration.description = description.String
+			if comments.String != "" {
+				migration.description = comments.String
+			}
+			// MARK_RAN changesets are not executed but marked as applied by the user.
+			migration.success = execType == "EXECUTED" || execType == "RERAN" || execType == "MARK_RAN"
+		}
+		migrationList = append(migrationList, migration)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return migrationList, nil
+}
+
+// getImportedMigrationInfo converts the imported migration to the recorded-only migration info.
+// The version starts with the execution time so that the imported migrations are ordered before the later ones
+// recorded by Bytebase, and ends with the rank to be unique.
+func getImportedMigrationInfo(database *api.Database, source db.MigrationSource, migration *importedMigration) (*db.MigrationInfo, error) {
+	installedTs, err := parseImportedTimestamp(migration.installedOn)
+	if err != nil {
+		return nil, err
+	}
+	mi := &db.MigrationInfo{
+		Version:     fmt.Sprintf("%s-%s-%06d", installedTs.UTC().Format("20060102150405"), strings.ToLower(string(source)), migration.rank),
+		Namespace:   database.Name,
+		Database:    database.Name,
+		Environment: database.Instance.Environment.Name,
+		Source:      source,
+		Type:        db.Migrate,
+		Description: fmt.Sprintf("[%s %s] %s (%s)", source, migration.version, migration.description, migration.script),
+		Creator:     migration.installedBy,
+	}
+	if migration.baseline {
+		mi.Type = db.Baseline
+	}
+	return mi, nil
+}
+
+// parseImportedTimestamp parses the execution time read from the migration history table.
+// The format depends on the database driver, e.g. the MySQL driver returns the DATETIME as is,
+// while the Postgres driver returns the TIMESTAMP in RFC 3339.
+func parseImportedTimestamp(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid execution time %q", value)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportedTimestamp(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{value: "2022-05-01T10:00:00Z", want: 1651399200},
+		{value: "2022-05-01T10:00:00.123456Z", want: 1651399200},
+		{value: "2022-05-01T18:00:00+08:00", want: 1651399200},
+		{value: "2022-05-01 10:00:00", want: 1651399200},
+		{value: "2022-05-01 10:00:00.5", want: 1651399200},
+	}
+	for _, test := range tests {
+		ts, err := parseImportedTimestamp(test.value)
+		require.NoError(t, err, test.value)
+		require.Equal(t, test.want, ts.Unix(), test.value)
+	}
+
+	_, err := parseImportedTimestamp("yesterday")
+	require.Error(t, err)
+}
+
+func TestGetImportedMigrationInfo(t *testing.T) {
+	database := &api.Database{
+		Name: "shop",
+		Instance: &api.Instance{
+			Environment: &api.Environment{Name: "Prod"},
+		},
+	}
+
+	mi, err := getImportedMigrationInfo(database, db.FLYWAY, &importedMigration{
+		rank:        2,
+		version:     "1.1",
+		description: "create users",
+		script:      "V1.1__create_users.sql",
+		installedBy: "bob",
+		installedOn: "2022-05-01 10:00:00",
+		success:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &db.MigrationInfo{
+		Version:     "20220501100000-flyway-000002",
+		Namespace:   "shop",
+		Database:    "shop",
+		Environment: "Prod",
+		Source:      db.FLYWAY,
+		Type:        db.Migrate,
+		Description: "[FLYWAY 1.1] create users (V1.1__create_users.sql)",
+		Creator:     "bob",
+	}, mi)
+
+	mi, err = getImportedMigrationInfo(database, db.FLYWAY, &importedMigration{
+		rank:        1,
+		version:     "1",
+		description: "<< Flyway Baseline >>",
+		script:      "<< Flyway Baseline >>",
+		installedOn: "2022-04-30T10:00:00Z",
+		success:     true,
+		baseline:    true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.Baseline, mi.Type)
+	require.Equal(t, "20220430100000-flyway-000001", mi.Version)
+
+	_, err = getImportedMigrationInfo(database, db.LIQUIBASE, &importedMigration{rank: 1, installedOn: ""})
+	require.Error(t, err)
+}
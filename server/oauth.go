@@ -67,6 +67,10 @@ func (s *Server) registerOAuthRoutes(g *echo.Group) {
 			}
 		}
 
+		if err := s.checkVCSReachable(vcsType); err != nil {
+			return err
+		}
+
 		// We need to attach the RedirectURL in the get token process of oauth,
 		// and the RedirectURL needs to be consistent with the RedirectURL in the get code process.
 		// The frontend get it through window.location.origin in the get code process,
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+)
+
+// checkVCSReachable rejects the VCS providers hosted outside the network when running in offline mode,
+// so that the users get an explicit error instead of a connection timeout.
+// The self-hosted GitLab is considered inside the network and is always allowed.
+func (s *Server) checkVCSReachable(vcsType vcsPlugin.Type) error {
+	if s.profile.Offline && vcsType == vcsPlugin.GitHubCom {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, fmt.Sprintf("VCS type %s is not available in offline mode", vcsType))
+	}
+	return nil
+}
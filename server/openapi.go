@@ -40,6 +40,11 @@ type sqlCheckRequestBody struct {
 
 func (s *Server) registerOpenAPIRoutes(g *echo.Group) {
 	g.POST("/sql/advise", s.sqlCheckController)
+	ciGroup := g.Group("/ci")
+	ciGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return ciTokenMiddleware(s, next)
+	})
+	ciGroup.GET("/status", s.ciStatusController)
 }
 
 // sqlCheckController godoc
@@ -116,6 +121,7 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		"utf8mb4",
 		"utf8mb4_general_ci",
 		envList[0].ID,
+		nil, /* tagList */
 		request.Statement,
 		catalog,
 	)
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// ciStatusController godoc
+// @Summary  Get the consolidated Bytebase check status of a commit.
+// @Description  External CI pipelines can poll this endpoint to gate the deployment until Bytebase checks pass.
+// @Accept  */*
+// @Tags  CI
+// @Produce  json
+// @Param  X-Bytebase-CI-Token  header  string  true   "The CI token generated by the workspace owner."
+// @Param  commit               query   string  true   "The commit ID which adds the migration file."
+// @Param  file                 query   string  false  "The migration file path in the repository."
+// @Success  200  {object}  api.CIStatusResponse
+// @Failure  400  {object}  echo.HTTPError
+// @Failure  401  {object}  echo.HTTPError
+// @Failure  404  {object}  echo.HTTPError
+// @Failure  500  {object}  echo.HTTPError
+// @Router  /ci/status  [get].
+func (s *Server) ciStatusController(c echo.Context) error {
+	ctx := c.Request().Context()
+	commitID := c.QueryParam("commit")
+	if commitID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required commit")
+	}
+	taskFind := &api.TaskFind{
+		CommitID: &commitID,
+	}
+	filePath := c.QueryParam("file")
+	if filePath != "" {
+		taskFind.FilePath = &filePath
+	}
+
+	taskList, err := s.store.FindTask(ctx, taskFind, true)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find task for commit %s", commitID)).SetInternal(err)
+	}
+	if len(taskList) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Cannot find any task created from commit %s", commitID))
+	}
+
+	response := &api.CIStatusResponse{
+		CommitID: commitID,
+		FilePath: filePath,
+		Status:   api.CIStatusSuccess,
+	}
+	pipelineMap := make(map[int]*api.Pipeline)
+	for _, task := range taskList {
+		pipeline, ok := pipelineMap[task.PipelineID]
+		if !ok {
+			pipeline, err = s.store.GetPipelineByID(ctx, task.PipelineID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch pipeline %d", task.PipelineID)).SetInternal(err)
+			}
+			if pipeline == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Pipeline not found with ID %d", task.PipelineID))
+			}
+			pipelineMap[task.PipelineID] = pipeline
+		}
+		taskStatus, err := s.getCITaskStatus(ctx, pipeline, task)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compute CI status for task %d", task.ID)).SetInternal(err)
+		}
+		response.Status = response.Status.Merge(taskStatus.Status)
+		response.TaskList = append(response.TaskList, taskStatus)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// getCITaskStatus consolidates the task status, the task check results and the completion of earlier stages.
+func (s *Server) getCITaskStatus(ctx context.Context, pipeline *api.Pipeline, task *api.Task) (*api.CITaskStatus, error) {
+	taskStatus := &api.CITaskStatus{
+		TaskID:     task.ID,
+		TaskName:   task.Name,
+		TaskStatus: task.Status,
+		Status:     api.CIStatusSuccess,
+		DetailList: []string{},
+	}
+	if task.Instance != nil && task.Instance.Environment != nil {
+		taskStatus.Environment = task.Instance.Environment.Name
+	}
+	if task.Database != nil {
+		taskStatus.Database = task.Database.Name
+	}
+	issue, err := s.store.GetIssueByPipelineID(ctx, pipeline.ID)
+	if err != nil {
+		return nil, err
+	}
+	if issue != nil {
+		taskStatus.IssueID = issue.ID
+	}
+
+	switch task.Status {
+	case api.TaskDone:
+		return taskStatus, nil
+	case api.TaskFailed, api.TaskCanceled:
+		taskStatus.Status = api.CIStatusFailure
+		taskStatus.DetailList = append(taskStatus.DetailList, fmt.Sprintf("Task is %s", task.Status))
+		return taskStatus, nil
+	}
+
+	// The task can only run after all tasks in the earlier stages are done.
+	for _, stage := range pipeline.StageList {
+		if stage.ID == task.StageID {
+			break
+		}
+		for _, stageTask := range stage.TaskList {
+			if stageTask.Status != api.TaskDone {
+				taskStatus.Status = taskStatus.Status.Merge(api.CIStatusPending)
+				taskStatus.DetailList = append(taskStatus.DetailList, fmt.Sprintf("Waiting for stage %q to complete", stage.Name))
+				break
+			}
+		}
+	}
+
+	checkStatus, detailList, err := getCITaskCheckStatus(task.TaskCheckRunList)
+	if err != nil {
+		return nil, err
+	}
+	taskStatus.Status = taskStatus.Status.Merge(checkStatus)
+	taskStatus.DetailList = append(taskStatus.DetailList, detailList...)
+
+	if taskStatus.Status == api.CIStatusSuccess {
+		// All checks pass but the task hasn't been rolled out yet.
+		taskStatus.Status = api.CIStatusPending
+		taskStatus.DetailList = append(taskStatus.DetailList, fmt.Sprintf("Task is %s", task.Status))
+	}
+	return taskStatus, nil
+}
+
+// getCITaskCheckStatus consolidates the latest run of each task check type.
+func getCITaskCheckStatus(taskCheckRunList []*api.TaskCheckRun) (api.CIStatus, []string, error) {
+	latestRunMap := make(map[api.TaskCheckType]*api.TaskCheckRun)
+	var typeList []api.TaskCheckType
+	for _, run := range taskCheckRunList {
+		latest, ok := latestRunMap[run.Type]
+		if !ok {
+			typeList = append(typeList, run.Type)
+		}
+		if !ok || run.UpdatedTs > latest.UpdatedTs || (run.UpdatedTs == latest.UpdatedTs && run.ID > latest.ID) {
+			latestRunMap[run.Type] = run
+		}
+	}
+
+	status := api.CIStatusSuccess
+	var detailList []string
+	for _, checkType := range typeList {
+		run := latestRunMap[checkType]
+		switch run.Status {
+		case api.TaskCheckRunRunning:
+			status = status.Merge(api.CIStatusPending)
+			detailList = append(detailList, fmt.Sprintf("Check %s is running", checkType))
+		case api.TaskCheckRunFailed, api.TaskCheckRunCanceled:
+			status = status.Merge(api.CIStatusFailure)
+			detailList = append(detailList, fmt.Sprintf("Check %s is %s", checkType, run.Status))
+		case api.TaskCheckRunDone:
+			checkResult := &api.TaskCheckRunResultPayload{}
+			if err := json.Unmarshal([]byte(run.Result), checkResult); err != nil {
+				return "", nil, fmt.Errorf("failed to unmarshal task check run %d result, error: %w", run.ID, err)
+			}
+			for _, result := range checkResult.ResultList {
+				if result.Status == api.TaskCheckStatusError {
+					status = status.Merge(api.CIStatusFailure)
+					detailList = append(detailList, fmt.Sprintf("Check %s: %s", checkType, result.Title))
+				}
+			}
+		}
+	}
+	return status, detailList, nil
+}
+
+// ciTokenMiddleware authenticates the CI status API by the CI token generated by the workspace owner.
+func ciTokenMiddleware(s *Server, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		settingName := api.SettingWorkspaceCIToken
+		settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to authenticate CI token").SetInternal(err)
+		}
+		tokenHash := ""
+		if len(settingList) > 0 {
+			tokenHash = settingList[0].Value
+		}
+		if err := validateCIToken(c.Request().Header.Get(api.CITokenHeader), tokenHash); err != nil {
+			return err
+		}
+		return next(c)
+	}
+}
+
+// validateCIToken validates the token against the hash of the CI token. The returned error is an echo HTTP error.
+func validateCIToken(token, tokenHash string) error {
+	if tokenHash == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "CI token isn't generated, the workspace owner can generate it in the settings")
+	}
+	if token == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing CI token")
+	}
+	if !common.MatchTokenHash(token, tokenHash) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid CI token")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCITaskCheckStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		runList []*api.TaskCheckRun
+		want    api.CIStatus
+	}{
+		{
+			name:    "no check",
+			runList: nil,
+			want:    api.CIStatusSuccess,
+		},
+		{
+			name: "warning passes",
+			runList: []*api.TaskCheckRun{
+				{ID: 1, Type: api.TaskCheckDatabaseConnect, Status: api.TaskCheckRunDone, Result: `{"resultList":[{"status":"SUCCESS"}]}`},
+				{ID: 2, Type: api.TaskCheckDatabaseStatementAdvise, Status: api.TaskCheckRunDone, Result: `{"resultList":[{"status":"WARN"}]}`},
+			},
+			want: api.CIStatusSuccess,
+		},
+		{
+			name: "running check",
+			runList: []*api.TaskCheckRun{
+				{ID: 1, Type: api.TaskCheckDatabaseConnect, Status: api.TaskCheckRunDone, Result: `{"resultList":[{"status":"SUCCESS"}]}`},
+				{ID: 2, Type: api.TaskCheckDatabaseStatementAdvise, Status: api.TaskCheckRunRunning},
+			},
+			want: api.CIStatusPending,
+		},
+		{
+			name: "error wins over running",
+			runList: []*api.TaskCheckRun{
+				{ID: 1, Type: api.TaskCheckDatabaseConnect, Status: api.TaskCheckRunRunning},
+				{ID: 2, Type: api.TaskCheckDatabaseStatementAdvise, Status: api.TaskCheckRunDone, Result: `{"resultList":[{"status":"ERROR","title":"bad"}]}`},
+			},
+			want: api.CIStatusFailure,
+		},
+		{
+			name: "only latest run counts",
+			runList: []*api.TaskCheckRun{
+				{ID: 1, UpdatedTs: 1, Type: api.TaskCheckDatabaseStatementAdvise, Status: api.TaskCheckRunDone, Result: `{"resultList":[{"status":"ERROR"}]}`},
+				{ID: 2, UpdatedTs: 2, Type: api.TaskCheckDatabaseStatementAdvise, Status: api.TaskCheckRunDone, Result: `{"resultList":[{"status":"SUCCESS"}]}`},
+			},
+			want: api.CIStatusSuccess,
+		},
+	}
+
+	for _, test := range tests {
+		got, _, err := getCITaskCheckStatus(test.runList)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.want, got, test.name)
+	}
+}
+
+func TestValidateCIToken(t *testing.T) {
+	tokenHash := common.HashToken("ci-token")
+	tests := []struct {
+		name      string
+		token     string
+		tokenHash string
+		wantErr   bool
+	}{
+		{
+			name:      "valid token",
+			token:     "ci-token",
+			tokenHash: tokenHash,
+			wantErr:   false,
+		},
+		{
+			name:      "missing token",
+			token:     "",
+			tokenHash: tokenHash,
+			wantErr:   true,
+		},
+		{
+			name:      "invalid token",
+			token:     "other-token",
+			tokenHash: tokenHash,
+			wantErr:   true,
+		},
+		{
+			name:      "token not generated",
+			token:     "",
+			tokenHash: "",
+			wantErr:   true,
+		},
+		{
+			// The hash itself isn't the token.
+			name:      "hash as token",
+			token:     tokenHash,
+			tokenHash: tokenHash,
+			wantErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateCIToken(test.token, test.tokenHash)
+			if !test.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok)
+			require.Equal(t, http.StatusUnauthorized, httpErr.Code)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package server
 
 import (
 	"strconv"
-	"time"
 
 	metricAPI "github.com/bytebase/bytebase/metric"
 	"github.com/bytebase/bytebase/plugin/metric"
@@ -11,20 +10,17 @@ import (
 
 func openAPIMetricMiddleware(s *Server, next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) (err error) {
-		start := time.Now()
-
 		defer func() {
-			duration := time.Since(start)
 			requestMethod := c.Request().Method
 			requestPath := c.Path()
 			responseCode := c.Response().Status
 
 			if s.MetricReporter != nil {
+				// The latency isn't labeled since the calls are aggregated daily by the labels.
 				s.MetricReporter.report(&metric.Metric{
 					Name:  metricAPI.OpenAPIMetricName,
 					Value: 1,
 					Labels: map[string]string{
-						"latency_ns":     strconv.FormatInt(duration.Nanoseconds(), 10),
 						"request_method": requestMethod,
 						"request_path":   requestPath,
 						"response_code":  strconv.Itoa(responseCode),
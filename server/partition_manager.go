@@ -0,0 +1,403 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// The smallest partition interval is a day, so the hourly check creates the partitions in time.
+	partitionManagerInterval = time.Duration(1) * time.Hour
+)
+
+// isPartitionManagementSupported returns true if the engine supports the managed partitions.
+func isPartitionManagementSupported(engine db.Type) bool {
+	return engine == db.Postgres || engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+}
+
+// NewPartitionManager creates a partition manager.
+func NewPartitionManager(server *Server) *PartitionManager {
+	return &PartitionManager{
+		server: server,
+	}
+}
+
+// PartitionManager creates the partitions ahead of the pre-create horizon and drops the partitions after the retention
+// for the tables with the partition policy. The statements are applied through the schema update issue, so that
+// they go through the approval, the task checks and the migration history as the other schema changes.
+type PartitionManager struct {
+	server *Server
+}
+
+// Run will run the partition manager.
+func (m *PartitionManager) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(partitionManagerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Partition manager started and will run every %v", partitionManagerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						log.Error("Partition manager PANIC RECOVER", zap.Error(err))
+					}
+				}()
+				m.manage(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (m *PartitionManager) manage(ctx context.Context, now time.Time) {
+	rowStatus := api.Normal
+	policyList, err := m.server.store.FindPartitionPolicy(ctx, &api.PartitionPolicyFind{RowStatus: &rowStatus})
+	if err != nil {
+		log.Error("Failed to find partition policy list", zap.Error(err))
+		return
+	}
+	for _, policy := range policyList {
+		if err := m.managePartition(ctx, policy, now); err != nil {
+			log.Error("Failed to manage partitions",
+				zap.Int("partition_policy_id", policy.ID),
+				zap.String("table", policy.TableName),
+				zap.Error(err))
+		}
+	}
+}
+
+func (m *PartitionManager) managePartition(ctx context.Context, policy *api.PartitionPolicy, now time.Time) error {
+	database, err := m.server.store.GetDatabase(ctx, &api.DatabaseFind{ID: &policy.DatabaseID})
+	if err != nil {
+		return fmt.Errorf("failed to find database ID %d, error: %w", policy.DatabaseID, err)
+	}
+	if database == nil {
+		return fmt.Errorf("database ID not found %d", policy.DatabaseID)
+	}
+	instance := database.Instance
+	if !isPartitionManagementSupported(instance.Engine) {
+		return fmt.Errorf("partition management is not supported for %s", instance.Engine)
+	}
+	// The agent only runs the migrations and the read-only queries, so the partitions can't be listed.
+	if instance.AgentID != nil {
+		log.Debug("Skip managing partitions for the instance managed by the agent", zap.String("instance", instance.Name))
+		return nil
+	}
+
+	// Wait for the last issue, otherwise the same partitions would be created again.
+	if policy.LastIssueID > 0 {
+		lastIssue, err := m.server.store.GetIssueByID(ctx, policy.LastIssueID)
+		if err != nil {
+			return fmt.Errorf("failed to find the last issue ID %d, error: %w", policy.LastIssueID, err)
+		}
+		if lastIssue != nil && lastIssue.Status == api.IssueOpen {
+			return nil
+		}
+	}
+
+	driver, err := m.server.getAdminDatabaseDriver(ctx, instance, database.Name)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, database.Name)
+	if err != nil {
+		return err
+	}
+	keyDef, nameList, err := getTablePartitionList(ctx, sqldb, instance.Engine, database.Name, policy)
+	if err != nil {
+		return err
+	}
+	if !isRangePartitionKey(instance.Engine, keyDef, policy.RangeKey) {
+		return fmt.Errorf("table %q is not partitioned by range on %q, got %q", policy.TableName, policy.RangeKey, keyDef)
+	}
+
+	lastRunTs := now.Unix()
+	policyPatch := &api.PartitionPolicyPatch{
+		ID:        policy.ID,
+		UpdaterID: api.SystemBotID,
+		LastRunTs: &lastRunTs,
+	}
+	createList, dropList := getPartitionChange(instance.Engine, policy, nameList, now)
+	if len(createList) > 0 || len(dropList) > 0 {
+		createContext, err := json.Marshal(&api.UpdateSchemaContext{
+			MigrationType: db.Migrate,
+			DetailList: []*api.UpdateSchemaDetail{
+				{
+					DatabaseID: database.ID,
+					Statement:  getPartitionStatement(instance.Engine, policy, createList, dropList),
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal update schema context, error: %w", err)
+		}
+		issue, err := m.server.createIssue(ctx, &api.IssueCreate{
+			ProjectID:     database.ProjectID,
+			Name:          fmt.Sprintf("Maintain partitions of table %q in database %q", policy.TableName, database.Name),
+			Type:          api.IssueDatabaseSchemaUpdate,
+			Description:   fmt.Sprintf("Create %d and drop %d partitions by the %s partition policy on %q.", len(createList), len(dropList), strings.ToLower(string(policy.Interval)), policy.RangeKey),
+			AssigneeID:    policy.AssigneeID,
+			CreateContext: string(createContext),
+		}, api.SystemBotID)
+		if err != nil {
+			return fmt.Errorf("failed to create partition maintenance issue, error: %w", err)
+		}
+		policyPatch.LastIssueID = &issue.ID
+	}
+	if _, err := m.server.store.PatchPartitionPolicy(ctx, policyPatch); err != nil {
+		return fmt.Errorf("failed to record the last run, error: %w", err)
+	}
+	return nil
+}
+
+// partitionRange is the range [start, end) of a managed partition.
+type partitionRange struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+// getTablePartitionList returns the partition key definition and the partition names of the table in the ascending order.
+func getTablePartitionList(ctx context.Context, sqldb *sql.DB, engine db.Type, databaseName string, policy *api.PartitionPolicy) (string, []string, error) {
+	var keyDef string
+	var nameList []string
+	if engine == db.Postgres {
+		schemaName := getPartitionSchemaName(policy)
+		if err := sqldb.QueryRowContext(ctx, `
+			SELECT COALESCE(pg_get_partkeydef(c.oid), '')
+			FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relname = $2`,
+			schemaName, policy.TableName,
+		).Scan(&keyDef); err != nil {
+			if err == sql.ErrNoRows {
+				return "", nil, fmt.Errorf("table %q not found in schema %q", policy.TableName, schemaName)
+			}
+			return "", nil, err
+		}
+		rows, err := sqldb.QueryContext(ctx, `
+			SELECT c.relname
+			FROM pg_inherits i
+				JOIN pg_class c ON c.oid = i.inhrelid
+				JOIN pg_class p ON p.oid = i.inhparent
+				JOIN pg_namespace n ON n.oid = p.relnamespace
+			WHERE n.nspname = $1 AND p.relname = $2
+			ORDER BY c.relname`,
+			schemaName, policy.TableName,
+		)
+		if err != nil {
+			return "", nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return "", nil, err
+			}
+			nameList = append(nameList, name)
+		}
+		return keyDef, nameList, rows.Err()
+	}
+
+	rows, err := sqldb.QueryContext(ctx, `
+		SELECT PARTITION_NAME, PARTITION_METHOD, PARTITION_EXPRESSION
+		FROM information_schema.PARTITIONS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL
+		ORDER BY PARTITION_ORDINAL_POSITION`,
+		databaseName, policy.TableName,
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, method, expression string
+		if err := rows.Scan(&name, &method, &expression); err != nil {
+			return "", nil, err
+		}
+		keyDef = fmt.Sprintf("%s (%s)", method, expression)
+		nameList = append(nameList, name)
+	}
+	return keyDef, nameList, rows.Err()
+}
+
+// isRangePartitionKey returns true if the partition key definition is the range on the key,
+// i.e. "RANGE (key)" in Postgres and "RANGE COLUMNS (key)" in MySQL.
+func isRangePartitionKey(engine db.Type, keyDef, rangeKey string) bool {
+	normalize := func(s string) string {
+		s = strings.NewReplacer("`", "", `"`, "", " ", "").Replace(s)
+		return strings.ToLower(s)
+	}
+	want := fmt.Sprintf("RANGE COLUMNS (%s)", rangeKey)
+	if engine == db.Postgres {
+		want = fmt.Sprintf("RANGE (%s)", rangeKey)
+	}
+	return normalize(keyDef) == normalize(want)
+}
+
+// getPartitionChange returns the partitions to create from the current one to the pre-create horizon,
+// and the managed partitions to drop after the retention. The partitions not named by the policy are left alone.
+func getPartitionChange(engine db.Type, policy *api.PartitionPolicy, nameList []string, now time.Time) ([]partitionRange, []string) {
+	current := getPartitionStart(policy.Interval, now)
+
+	existing := make(map[string]bool)
+	// MySQL only adds the partitions after the last one, the gaps require reorganizing the partitions.
+	var lastEnd time.Time
+	for _, name := range nameList {
+		existing[name] = true
+		if start, ok := parsePartitionName(engine, policy, name); ok {
+			if end := addPartitionInterval(policy.Interval, start, 1); end.After(lastEnd) {
+				lastEnd = end
+			}
+		}
+	}
+
+	var createList []partitionRange
+	for i := 0; i <= policy.PrecreateCount; i++ {
+		start := addPartitionInterval(policy.Interval, current, i)
+		name := getPartitionName(engine, policy, start)
+		if existing[name] {
+			continue
+		}
+		if engine != db.Postgres && start.Before(lastEnd) {
+			continue
+		}
+		createList = append(createList, partitionRange{
+			name:  name,
+			start: start,
+			end:   addPartitionInterval(policy.Interval, start, 1),
+		})
+	}
+
+	var dropList []string
+	if policy.RetentionCount > 0 {
+		cutoff := addPartitionInterval(policy.Interval, current, -policy.RetentionCount)
+		for _, name := range nameList {
+			if start, ok := parsePartitionName(engine, policy, name); ok && start.Before(cutoff) {
+				dropList = append(dropList, name)
+			}
+		}
+	}
+	return createList, dropList
+}
+
+// getPartitionStatement returns the statement creating and dropping the partitions.
+func getPartitionStatement(engine db.Type, policy *api.PartitionPolicy, createList []partitionRange, dropList []string) string {
+	var stmtList []string
+	if engine == db.Postgres {
+		quote := func(s string) string {
+			return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+		}
+		schemaName := getPartitionSchemaName(policy)
+		for _, partition := range createList {
+			stmtList = append(stmtList, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s PARTITION OF %s.%s FOR VALUES FROM ('%s') TO ('%s');",
+				quote(schemaName), quote(partition.name), quote(schemaName), quote(policy.TableName),
+				partition.start.Format("2006-01-02"), partition.end.Format("2006-01-02")))
+		}
+		for _, name := range dropList {
+			stmtList = append(stmtList, fmt.Sprintf("DROP TABLE IF EXISTS %s.%s;", quote(schemaName), quote(name)))
+		}
+		return strings.Join(stmtList, "\n")
+	}
+
+	quote := func(s string) string {
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "`", "``"))
+	}
+	if len(createList) > 0 {
+		var partitionList []string
+		for _, partition := range createList {
+			partitionList = append(partitionList, fmt.Sprintf("PARTITION %s VALUES LESS THAN ('%s')", quote(partition.name), partition.end.Format("2006-01-02")))
+		}
+		stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s ADD PARTITION (%s);", quote(policy.TableName), strings.Join(partitionList, ", ")))
+	}
+	if len(dropList) > 0 {
+		var nameList []string
+		for _, name := range dropList {
+			nameList = append(nameList, quote(name))
+		}
+		stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s;", quote(policy.TableName), strings.Join(nameList, ", ")))
+	}
+	return strings.Join(stmtList, "\n")
+}
+
+func getPartitionSchemaName(policy *api.PartitionPolicy) string {
+	if policy.SchemaName == "" {
+		return "public"
+	}
+	return policy.SchemaName
+}
+
+// getPartitionStart returns the start of the partition containing t in UTC.
+func getPartitionStart(interval api.PartitionInterval, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case api.PartitionIntervalWeek:
+		// The week starts on Monday.
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case api.PartitionIntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func addPartitionInterval(interval api.PartitionInterval, start time.Time, n int) time.Time {
+	switch interval {
+	case api.PartitionIntervalWeek:
+		return start.AddDate(0, 0, 7*n)
+	case api.PartitionIntervalMonth:
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, n)
+}
+
+func getPartitionNameLayout(interval api.PartitionInterval) string {
+	if interval == api.PartitionIntervalMonth {
+		return "200601"
+	}
+	return "20060102"
+}
+
+// getPartitionName returns the name of the partition starting at start, e.g. orders_p20220516 in Postgres where
+// the partitions are tables, and p20220516 in MySQL where the partition names are scoped in the table.
+func getPartitionName(engine db.Type, policy *api.PartitionPolicy, start time.Time) string {
+	name := "p" + start.Format(getPartitionNameLayout(policy.Interval))
+	if engine == db.Postgres {
+		return fmt.Sprintf("%s_%s", policy.TableName, name)
+	}
+	return name
+}
+
+// parsePartitionName returns the start of the partition if the partition is named by the policy.
+func parsePartitionName(engine db.Type, policy *api.PartitionPolicy, name string) (time.Time, bool) {
+	prefix := "p"
+	if engine == db.Postgres {
+		prefix = policy.TableName + "_p"
+	}
+	if !strings.HasPrefix(name, prefix) {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(getPartitionNameLayout(policy.Interval), strings.TrimPrefix(name, prefix))
+	if err != nil || !start.Equal(getPartitionStart(policy.Interval, start)) {
+		return time.Time{}, false
+	}
+	return start, true
+}
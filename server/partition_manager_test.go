@@ -0,0 +1,96 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPartitionStart(t *testing.T) {
+	// 2022-05-18 is a Wednesday.
+	now := time.Date(2022, 5, 18, 13, 45, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2022, 5, 18, 0, 0, 0, 0, time.UTC), getPartitionStart(api.PartitionIntervalDay, now))
+	require.Equal(t, time.Date(2022, 5, 16, 0, 0, 0, 0, time.UTC), getPartitionStart(api.PartitionIntervalWeek, now))
+	require.Equal(t, time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC), getPartitionStart(api.PartitionIntervalMonth, now))
+	// Sunday belongs to the week starting on the previous Monday.
+	require.Equal(t, time.Date(2022, 5, 16, 0, 0, 0, 0, time.UTC), getPartitionStart(api.PartitionIntervalWeek, time.Date(2022, 5, 22, 23, 0, 0, 0, time.UTC)))
+}
+
+func TestParsePartitionName(t *testing.T) {
+	policy := &api.PartitionPolicy{TableName: "orders", Interval: api.PartitionIntervalWeek}
+	start, ok := parsePartitionName(db.Postgres, policy, "orders_p20220516")
+	require.True(t, ok)
+	require.Equal(t, time.Date(2022, 5, 16, 0, 0, 0, 0, time.UTC), start)
+	// Not a Monday.
+	_, ok = parsePartitionName(db.Postgres, policy, "orders_p20220517")
+	require.False(t, ok)
+	_, ok = parsePartitionName(db.Postgres, policy, "orders_default")
+	require.False(t, ok)
+	_, ok = parsePartitionName(db.MySQL, policy, "pmax")
+	require.False(t, ok)
+}
+
+func TestGetPartitionChange(t *testing.T) {
+	now := time.Date(2022, 5, 18, 13, 45, 0, 0, time.UTC)
+
+	t.Run("postgres", func(t *testing.T) {
+		policy := &api.PartitionPolicy{
+			SchemaName:     "public",
+			TableName:      "orders",
+			RangeKey:       "created_at",
+			Interval:       api.PartitionIntervalDay,
+			RetentionCount: 2,
+			PrecreateCount: 2,
+		}
+		createList, dropList := getPartitionChange(db.Postgres, policy, []string{
+			"orders_default",
+			"orders_p20220515",
+			"orders_p20220516",
+			"orders_p20220518",
+		}, now)
+		require.Equal(t, []partitionRange{
+			{name: "orders_p20220519", start: time.Date(2022, 5, 19, 0, 0, 0, 0, time.UTC), end: time.Date(2022, 5, 20, 0, 0, 0, 0, time.UTC)},
+			{name: "orders_p20220520", start: time.Date(2022, 5, 20, 0, 0, 0, 0, time.UTC), end: time.Date(2022, 5, 21, 0, 0, 0, 0, time.UTC)},
+		}, createList)
+		require.Equal(t, []string{"orders_p20220515"}, dropList)
+		require.Equal(t, `CREATE TABLE IF NOT EXISTS "public"."orders_p20220519" PARTITION OF "public"."orders" FOR VALUES FROM ('2022-05-19') TO ('2022-05-20');
+CREATE TABLE IF NOT EXISTS "public"."orders_p20220520" PARTITION OF "public"."orders" FOR VALUES FROM ('2022-05-20') TO ('2022-05-21');
+DROP TABLE IF EXISTS "public"."orders_p20220515";`, getPartitionStatement(db.Postgres, policy, createList, dropList))
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		policy := &api.PartitionPolicy{
+			TableName:      "orders",
+			RangeKey:       "created_at",
+			Interval:       api.PartitionIntervalMonth,
+			RetentionCount: 0,
+			PrecreateCount: 2,
+		}
+		createList, dropList := getPartitionChange(db.MySQL, policy, []string{"p202203", "p202205"}, now)
+		require.Equal(t, []partitionRange{
+			{name: "p202206", start: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), end: time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)},
+			{name: "p202207", start: time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC), end: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)},
+		}, createList)
+		require.Empty(t, dropList)
+		require.Equal(t, "ALTER TABLE `orders` ADD PARTITION (PARTITION `p202206` VALUES LESS THAN ('2022-07-01'), PARTITION `p202207` VALUES LESS THAN ('2022-08-01'));",
+			getPartitionStatement(db.MySQL, policy, createList, dropList))
+
+		// The partitions before the last one can't be added in MySQL.
+		policy.RetentionCount = 1
+		createList, dropList = getPartitionChange(db.MySQL, policy, []string{"p202203", "p202206"}, now)
+		require.Equal(t, []string{"p202207"}, []string{createList[0].name})
+		require.Len(t, createList, 1)
+		require.Equal(t, []string{"p202203"}, dropList)
+	})
+}
+
+func TestIsRangePartitionKey(t *testing.T) {
+	require.True(t, isRangePartitionKey(db.Postgres, "RANGE (created_at)", "created_at"))
+	require.True(t, isRangePartitionKey(db.Postgres, `RANGE ("Created_At")`, "created_at"))
+	require.False(t, isRangePartitionKey(db.Postgres, "LIST (region)", "created_at"))
+	require.True(t, isRangePartitionKey(db.MySQL, "RANGE COLUMNS (`created_at`)", "created_at"))
+	require.False(t, isRangePartitionKey(db.MySQL, "RANGE (to_days(`created_at`))", "created_at"))
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func (s *Server) registerPartitionPolicyRoutes(g *echo.Group) {
+	g.GET("/database/:id/partition-policy", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		policyList, err := s.store.FindPartitionPolicy(ctx, &api.PartitionPolicyFind{DatabaseID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch partition policy list for database ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, policyList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal partition policy list response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/database/:id/partition-policy", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		if !isPartitionManagementSupported(database.Instance.Engine) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Partition management is not supported for %s", database.Instance.Engine))
+		}
+
+		policyCreate := &api.PartitionPolicyCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, policyCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create partition policy request").SetInternal(err)
+		}
+		policyCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		policyCreate.DatabaseID = id
+		policyCreate.SchemaName = strings.TrimSpace(policyCreate.SchemaName)
+		policyCreate.TableName = strings.TrimSpace(policyCreate.TableName)
+		policyCreate.RangeKey = strings.TrimSpace(policyCreate.RangeKey)
+		if database.Instance.Engine == db.Postgres {
+			if policyCreate.SchemaName == "" {
+				policyCreate.SchemaName = "public"
+			}
+		} else {
+			// The table is in the database itself in MySQL and TiDB.
+			policyCreate.SchemaName = ""
+		}
+		if err := api.ValidatePartitionPolicy(policyCreate.TableName, policyCreate.RangeKey, policyCreate.Interval, policyCreate.RetentionCount, policyCreate.PrecreateCount); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if err := s.validateAssigneeRoleByID(ctx, policyCreate.AssigneeID); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid partition maintenance assignee: %v", err)).SetInternal(err)
+		}
+
+		policy, err := s.store.CreatePartitionPolicy(ctx, policyCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Partition policy already exists for table %q in database %q", policyCreate.TableName, database.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create partition policy").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, policy); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create partition policy response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/database/:id/partition-policy/:policyID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		policy, err := s.getPartitionPolicyFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		policyPatch := &api.PartitionPolicyPatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, policyPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch partition policy request").SetInternal(err)
+		}
+		policyPatch.ID = policy.ID
+		policyPatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+		// The partition manager owns the last run.
+		policyPatch.LastRunTs = nil
+		policyPatch.LastIssueID = nil
+
+		retentionCount, precreateCount := policy.RetentionCount, policy.PrecreateCount
+		if v := policyPatch.RetentionCount; v != nil {
+			retentionCount = *v
+		}
+		if v := policyPatch.PrecreateCount; v != nil {
+			precreateCount = *v
+		}
+		if err := api.ValidatePartitionPolicy(policy.TableName, policy.RangeKey, policy.Interval, retentionCount, precreateCount); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if v := policyPatch.AssigneeID; v != nil {
+			if err := s.validateAssigneeRoleByID(ctx, *v); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid partition maintenance assignee: %v", err)).SetInternal(err)
+			}
+		}
+
+		policy, err = s.store.PatchPartitionPolicy(ctx, policyPatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch partition policy ID: %v", policyPatch.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, policy); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch partition policy response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/database/:id/partition-policy/:policyID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		policy, err := s.getPartitionPolicyFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		policyDelete := &api.PartitionPolicyDelete{
+			ID:        policy.ID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeletePartitionPolicy(ctx, policyDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete partition policy ID: %v", policy.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// getPartitionPolicyFromParam returns the partition policy specified by the ":id" and ":policyID" params.
+// The returned error is an echo HTTP error.
+func (s *Server) getPartitionPolicyFromParam(ctx context.Context, c echo.Context) (*api.PartitionPolicy, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+	}
+	policyID, err := strconv.Atoi(c.Param("policyID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Partition policy ID is not a number: %s", c.Param("policyID"))).SetInternal(err)
+	}
+	policy, err := s.store.GetPartitionPolicyByID(ctx, policyID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch partition policy ID: %v", policyID)).SetInternal(err)
+	}
+	if policy == nil || policy.DatabaseID != id {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Partition policy not found by ID %d and database ID %d", policyID, id))
+	}
+	return policy, nil
+}
@@ -24,11 +24,13 @@ func (s *Server) ScheduleNextTaskIfNeeded(ctx context.Context, pipeline *api.Pip
 					return nil, err
 				}
 
-				policy, err := s.store.GetPipelineApprovalPolicy(ctx, task.Instance.EnvironmentID)
+				policy, err := s.store.GetPipelineApprovalPolicy(ctx, task.Instance.EnvironmentID, api.GetPolicyTagList(task.Instance, task.Database))
 				if err != nil {
 					return nil, fmt.Errorf("failed to get approval policy for environment ID %d, error: %w", task.Instance.EnvironmentID, err)
 				}
-				if policy.Value == api.PipelineApprovalValueManualNever {
+				// Each task follows the approval policy of its own type, so the schema and data update tasks of an issue can be approved differently.
+				// Dropping database always requires the manual approval.
+				if policy.GetValue(task.Type) == api.PipelineApprovalValueManualNever && task.Type != api.TaskDatabaseDrop {
 					// transit into Pending for ManualNever (auto-approval) tasks if all required task checks passed.
 					ok, err := s.TaskScheduler.canAutoApprove(ctx, task)
 					if err != nil {
@@ -51,6 +53,13 @@ func (s *Server) ScheduleNextTaskIfNeeded(ctx context.Context, pipeline *api.Pip
 				if _, err := s.TaskCheckScheduler.ScheduleCheckIfNeeded(ctx, task, api.SystemBotID, skipIfAlreadyTerminated); err != nil {
 					return nil, err
 				}
+				verified, err := s.verifyChangeSetIfNeeded(ctx, pipeline)
+				if err != nil {
+					return nil, err
+				}
+				if !verified {
+					return task, nil
+				}
 				updatedTask, err := s.TaskScheduler.ScheduleIfNeeded(ctx, task)
 				if err != nil {
 					return nil, err
@@ -63,6 +63,7 @@ func (s *Server) registerPolicyRoutes(g *echo.Group) {
 		}
 
 		policyUpsert.EnvironmentID = environmentID
+		policyUpsert.Tag = c.QueryParam("tag")
 		policyUpsert.Type = pType
 		policyUpsert.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
 
@@ -95,6 +96,7 @@ func (s *Server) registerPolicyRoutes(g *echo.Group) {
 			EnvironmentID: environmentID,
 			DeleterID:     c.Get(getPrincipalIDContextKey()).(int),
 			Type:          api.PolicyType(c.QueryParam("type")),
+			Tag:           c.QueryParam("tag"),
 		}
 
 		ctx := c.Request().Context()
@@ -123,6 +125,9 @@ func (s *Server) registerPolicyRoutes(g *echo.Group) {
 		}
 		policyFind.Type = &pType
 		policyFind.EnvironmentID = &environmentID
+		// The tag policy is found by the exact tag, and the environment policy by the empty tag.
+		tag := c.QueryParam("tag")
+		policyFind.Tag = &tag
 
 		policy, err := s.store.GetPolicy(ctx, policyFind)
 		if err != nil {
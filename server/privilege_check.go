@@ -0,0 +1,296 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// featurePrivilege is the privileges a feature requires.
+type featurePrivilege struct {
+	feature       api.PrivilegeCheckFeature
+	privilegeList []string
+}
+
+// mysqlFeaturePrivilegeList is the global privileges each feature requires on MySQL.
+var mysqlFeaturePrivilegeList = []featurePrivilege{
+	{
+		feature:       api.PrivilegeCheckSyncSchema,
+		privilegeList: []string{"SELECT", "SHOW DATABASES", "SHOW VIEW"},
+	},
+	{
+		feature:       api.PrivilegeCheckCreateDatabase,
+		privilegeList: []string{"CREATE"},
+	},
+	{
+		// The dump locks the tables by FLUSH TABLES WITH READ LOCK, and dumps the triggers and the events along with the tables.
+		feature:       api.PrivilegeCheckBackup,
+		privilegeList: []string{"SELECT", "SHOW VIEW", "LOCK TABLES", "RELOAD", "TRIGGER", "EVENT"},
+	},
+	{
+		// The binlog position is read by SHOW MASTER STATUS, and the binlog files are downloaded by mysqlbinlog --read-from-remote-server.
+		feature:       api.PrivilegeCheckPITR,
+		privilegeList: []string{"RELOAD", "REPLICATION CLIENT", "REPLICATION SLAVE"},
+	},
+}
+
+// tidbFeaturePrivilegeList is the global privileges each feature requires on TiDB, which doesn't support PITR.
+var tidbFeaturePrivilegeList = mysqlFeaturePrivilegeList[:3]
+
+// mariadbFeaturePrivilegeList is the global privileges each feature requires on MariaDB,
+// whose binlog can't be replayed by the bundled MySQL mysqlbinlog for PITR.
+var mariadbFeaturePrivilegeList = mysqlFeaturePrivilegeList[:3]
+
+// pgSyncSchemaViewList is the pg_catalog views read by the schema sync.
+var pgSyncSchemaViewList = []string{
+	"pg_catalog.pg_database",
+	"pg_catalog.pg_user",
+	"pg_catalog.pg_settings",
+	"pg_catalog.pg_tables",
+	"pg_catalog.pg_views",
+	"pg_catalog.pg_indexes",
+	"pg_catalog.pg_extension",
+	"pg_catalog.pg_sequences",
+}
+
+func (s *Server) registerPrivilegeCheckRoutes(g *echo.Group) {
+	// Checks the admin data source account has the privileges each feature requires.
+	g.GET("/instance/:instanceID/privilege-check", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		instance, err := s.store.GetInstanceByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", id)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", id))
+		}
+		// The server can't reach the instance behind an agent.
+		if instance.AgentID != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance %q is managed by an agent, the privileges can't be checked from the server", instance.Name))
+		}
+		if instance.Engine != db.MySQL && instance.Engine != db.TiDB && instance.Engine != db.MariaDB && instance.Engine != db.Postgres {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Privilege check doesn't support %s", instance.Engine))
+		}
+
+		result, err := s.checkInstancePrivilege(ctx, instance)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check the privileges of instance %q", instance.Name)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, result)
+	})
+}
+
+// checkInstancePrivilege checks the privileges of the admin data source account for each feature.
+func (s *Server) checkInstancePrivilege(ctx context.Context, instance *api.Instance) (*api.InstancePrivilegeCheck, error) {
+	adminDataSource := api.DataSourceFromInstanceWithType(instance, api.Admin)
+	if adminDataSource == nil {
+		return nil, common.Errorf(common.Internal, "admin data source not found for instance %d", instance.ID)
+	}
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+
+	database := ""
+	if instance.Engine == db.Postgres {
+		// Postgres always connects to a specific database.
+		database = "postgres"
+	}
+	sqldb, err := driver.GetDBConnection(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkList []*api.PrivilegeCheck
+	switch instance.Engine {
+	case db.MySQL, db.TiDB, db.MariaDB:
+		checkList, err = checkMySQLPrivilege(ctx, instance.Engine, sqldb)
+	case db.Postgres:
+		checkList, err = checkPostgresPrivilege(ctx, sqldb, adminDataSource.Username)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe the privileges of %q, error: %w", adminDataSource.Username, err)
+	}
+	return &api.InstancePrivilegeCheck{
+		InstanceID: instance.ID,
+		Username:   adminDataSource.Username,
+		CheckList:  checkList,
+	}, nil
+}
+
+func checkMySQLPrivilege(ctx context.Context, engine db.Type, sqldb *sql.DB) ([]*api.PrivilegeCheck, error) {
+	// CURRENT_USER() returns the account matched by the server such as "bytebase@%", which is the one to grant.
+	var currentUser string
+	if err := sqldb.QueryRowContext(ctx, "SELECT CURRENT_USER()").Scan(&currentUser); err != nil {
+		return nil, err
+	}
+	rows, err := sqldb.QueryContext(ctx, "SHOW GRANTS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var grantList []string
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return nil, err
+		}
+		grantList = append(grantList, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	featureList := mysqlFeaturePrivilegeList
+	switch engine {
+	case db.TiDB:
+		featureList = tidbFeaturePrivilegeList
+	case db.MariaDB:
+		featureList = mariadbFeaturePrivilegeList
+	}
+	return buildMySQLPrivilegeCheckList(featureList, getMySQLGlobalPrivilegeSet(grantList), currentUser), nil
+}
+
+// buildMySQLPrivilegeCheckList checks the global privileges against the ones each feature requires.
+func buildMySQLPrivilegeCheckList(featureList []featurePrivilege, privilegeSet map[string]bool, currentUser string) []*api.PrivilegeCheck {
+	var checkList []*api.PrivilegeCheck
+	for _, feature := range featureList {
+		check := &api.PrivilegeCheck{
+			Feature:      feature.feature,
+			Status:       api.ConnectionCheckSuccess,
+			RequiredList: feature.privilegeList,
+			MissingList:  []string{},
+			Detail:       fmt.Sprintf("The account has the global privileges: %s.", strings.Join(feature.privilegeList, ", ")),
+		}
+		if !privilegeSet["ALL"] {
+			for _, privilege := range feature.privilegeList {
+				if !privilegeSet[privilege] {
+					check.MissingList = append(check.MissingList, privilege)
+				}
+			}
+		}
+		if len(check.MissingList) > 0 {
+			check.Status = api.ConnectionCheckError
+			check.Detail = fmt.Sprintf("The account misses the global privileges: %s.", strings.Join(check.MissingList, ", "))
+			check.Suggestion = fmt.Sprintf("GRANT %s ON *.* TO %s;", strings.Join(check.MissingList, ", "), quoteMySQLAccount(currentUser))
+		}
+		checkList = append(checkList, check)
+	}
+	return checkList
+}
+
+// getMySQLGlobalPrivilegeSet returns the privileges granted on *.* in the SHOW GRANTS statements.
+// "ALL PRIVILEGES" is returned as "ALL".
+func getMySQLGlobalPrivilegeSet(grantList []string) map[string]bool {
+	privilegeSet := make(map[string]bool)
+	for _, grant := range grantList {
+		grant = strings.ToUpper(strings.TrimSpace(grant))
+		if !strings.HasPrefix(grant, "GRANT ") {
+			continue
+		}
+		onIndex := strings.Index(grant, " ON ")
+		if onIndex == -1 || !strings.HasPrefix(grant[onIndex+len(" ON "):], "*.* ") {
+			continue
+		}
+		for _, privilege := range strings.Split(grant[len("GRANT "):onIndex], ",") {
+			privilege = strings.TrimSpace(privilege)
+			if privilege == "ALL PRIVILEGES" {
+				privilege = "ALL"
+			}
+			privilegeSet[privilege] = true
+		}
+	}
+	return privilegeSet
+}
+
+// quoteMySQLAccount quotes the account such as "bytebase@%" to "'bytebase'@'%'".
+func quoteMySQLAccount(account string) string {
+	i := strings.LastIndex(account, "@")
+	if i == -1 {
+		return fmt.Sprintf("'%s'", account)
+	}
+	return fmt.Sprintf("'%s'@'%s'", account[:i], account[i+1:])
+}
+
+func checkPostgresPrivilege(ctx context.Context, sqldb *sql.DB, username string) ([]*api.PrivilegeCheck, error) {
+	var super, createDB bool
+	if err := sqldb.QueryRowContext(ctx, "SELECT rolsuper, rolcreatedb FROM pg_roles WHERE rolname = current_user").Scan(&super, &createDB); err != nil {
+		return nil, err
+	}
+
+	syncCheck := &api.PrivilegeCheck{
+		Feature:      api.PrivilegeCheckSyncSchema,
+		Status:       api.ConnectionCheckSuccess,
+		RequiredList: []string{"USAGE ON SCHEMA pg_catalog"},
+		MissingList:  []string{},
+		Detail:       "The account can read the pg_catalog views used by the schema sync.",
+	}
+	var usage bool
+	if err := sqldb.QueryRowContext(ctx, "SELECT has_schema_privilege('pg_catalog', 'USAGE')").Scan(&usage); err != nil {
+		return nil, err
+	}
+	if !usage {
+		syncCheck.MissingList = append(syncCheck.MissingList, "USAGE ON SCHEMA pg_catalog")
+	}
+	var missingViewList []string
+	for _, view := range pgSyncSchemaViewList {
+		syncCheck.RequiredList = append(syncCheck.RequiredList, fmt.Sprintf("SELECT ON %s", view))
+		var selectable bool
+		if err := sqldb.QueryRowContext(ctx, "SELECT has_table_privilege($1, 'SELECT')", view).Scan(&selectable); err != nil {
+			return nil, err
+		}
+		if !selectable {
+			missingViewList = append(missingViewList, view)
+			syncCheck.MissingList = append(syncCheck.MissingList, fmt.Sprintf("SELECT ON %s", view))
+		}
+	}
+	if len(syncCheck.MissingList) > 0 {
+		syncCheck.Status = api.ConnectionCheckError
+		syncCheck.Detail = fmt.Sprintf("The account misses the privileges: %s.", strings.Join(syncCheck.MissingList, ", "))
+		var statementList []string
+		if !usage {
+			statementList = append(statementList, fmt.Sprintf("GRANT USAGE ON SCHEMA pg_catalog TO %s;", quotePostgresIdentifier(username)))
+		}
+		if len(missingViewList) > 0 {
+			statementList = append(statementList, fmt.Sprintf("GRANT SELECT ON %s TO %s;", strings.Join(missingViewList, ", "), quotePostgresIdentifier(username)))
+		}
+		syncCheck.Suggestion = strings.Join(statementList, "\n")
+	}
+
+	createDatabaseCheck := &api.PrivilegeCheck{
+		Feature:      api.PrivilegeCheckCreateDatabase,
+		Status:       api.ConnectionCheckSuccess,
+		RequiredList: []string{"CREATEDB"},
+		MissingList:  []string{},
+		Detail:       "The account has the CREATEDB attribute.",
+	}
+	if super {
+		createDatabaseCheck.Detail = "The account is a superuser."
+	} else if !createDB {
+		createDatabaseCheck.Status = api.ConnectionCheckError
+		createDatabaseCheck.MissingList = []string{"CREATEDB"}
+		createDatabaseCheck.Detail = "The account doesn't have the CREATEDB attribute."
+		createDatabaseCheck.Suggestion = fmt.Sprintf("ALTER ROLE %s CREATEDB;", quotePostgresIdentifier(username))
+	}
+
+	return []*api.PrivilegeCheck{syncCheck, createDatabaseCheck}, nil
+}
+
+func quotePostgresIdentifier(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
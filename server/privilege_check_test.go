@@ -0,0 +1,63 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetMySQLGlobalPrivilegeSet(t *testing.T) {
+	tests := []struct {
+		grantList []string
+		want      map[string]bool
+	}{
+		{
+			grantList: []string{"GRANT USAGE ON *.* TO `ro`@`%`"},
+			want:      map[string]bool{"USAGE": true},
+		},
+		{
+			grantList: []string{"GRANT ALL PRIVILEGES ON *.* TO `root`@`localhost` WITH GRANT OPTION"},
+			want:      map[string]bool{"ALL": true},
+		},
+		{
+			grantList: []string{
+				"GRANT SELECT, RELOAD, REPLICATION CLIENT ON *.* TO `bytebase`@`%`",
+				"GRANT ALL PRIVILEGES ON `shop`.* TO `bytebase`@`%`",
+				"GRANT `admin`@`%` TO `bytebase`@`%`",
+			},
+			want: map[string]bool{"SELECT": true, "RELOAD": true, "REPLICATION CLIENT": true},
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, getMySQLGlobalPrivilegeSet(test.grantList), test.grantList)
+	}
+}
+
+func TestBuildMySQLPrivilegeCheckList(t *testing.T) {
+	featureList := []featurePrivilege{
+		{
+			feature:       api.PrivilegeCheckCreateDatabase,
+			privilegeList: []string{"CREATE"},
+		},
+		{
+			feature:       api.PrivilegeCheckPITR,
+			privilegeList: []string{"RELOAD", "REPLICATION CLIENT", "REPLICATION SLAVE"},
+		},
+	}
+
+	checkList := buildMySQLPrivilegeCheckList(featureList, map[string]bool{"CREATE": true, "RELOAD": true}, "bytebase@%")
+	require.Equal(t, 2, len(checkList))
+	require.Equal(t, api.ConnectionCheckSuccess, checkList[0].Status)
+	require.Equal(t, []string{}, checkList[0].MissingList)
+	require.Equal(t, "", checkList[0].Suggestion)
+	require.Equal(t, api.ConnectionCheckError, checkList[1].Status)
+	require.Equal(t, []string{"REPLICATION CLIENT", "REPLICATION SLAVE"}, checkList[1].MissingList)
+	require.Equal(t, "GRANT REPLICATION CLIENT, REPLICATION SLAVE ON *.* TO 'bytebase'@'%';", checkList[1].Suggestion)
+
+	checkList = buildMySQLPrivilegeCheckList(featureList, map[string]bool{"ALL": true}, "root@localhost")
+	for _, check := range checkList {
+		require.Equal(t, api.ConnectionCheckSuccess, check.Status)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/google/uuid"
@@ -16,6 +17,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
 	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
 	"github.com/bytebase/bytebase/plugin/vcs/github"
 	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
@@ -78,10 +80,10 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			projectFind.PrincipalID = &userID
 		}
 
-		// Only Owner and DBA can fetch all projects from all users.
+		// Only Owner, DBA and Auditor can fetch all projects from all users.
 		if projectFind.PrincipalID == nil {
 			role := c.Get(getRoleContextKey()).(api.Role)
-			if role != api.Owner && role != api.DBA {
+			if role != api.Owner && role != api.DBA && role != api.Auditor {
 				return echo.NewHTTPError(http.StatusForbidden, "Not allowed to fetch all project list")
 			}
 		}
@@ -157,6 +159,22 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch project request").SetInternal(err)
 		}
 
+		if v := projectPatch.DBNamePattern; v != nil {
+			if err := api.ValidateDatabaseNamePattern(*v); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+		}
+		if v := projectPatch.AllowedEnvironmentList; v != nil {
+			allowedEnvironmentList, err := s.validateProjectAllowedEnvironmentList(ctx, id, *v)
+			if err != nil {
+				if common.ErrorCode(err) == common.Invalid {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid allowed environment list: %s", err.Error()))
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate allowed environment list").SetInternal(err)
+			}
+			projectPatch.AllowedEnvironmentList = &allowedEnvironmentList
+		}
+
 		// Ensure the project has no database before it's archived.
 		if v := projectPatch.RowStatus; v != nil && *v == string(api.Archived) {
 			databases, err := s.store.FindDatabase(ctx, &api.DatabaseFind{ProjectID: &id})
@@ -206,7 +224,10 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project not found with ID %d", projectID))
 		}
 
-		if err := api.ValidateRepositoryFilePathTemplate(repositoryCreate.FilePathTemplate, project.TenantMode); err != nil {
+		if repositoryCreate.FileFormat == "" {
+			repositoryCreate.FileFormat = db.MigrationFileFormatBytebase
+		}
+		if err := api.ValidateRepositoryFileFormat(repositoryCreate.FileFormat, repositoryCreate.FilePathTemplate, project.TenantMode); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
 		}
 
@@ -214,6 +235,19 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
 		}
 
+		if err := api.ValidateTrustedSigningKeys(repositoryCreate.TrustedSigningKeys); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
+		}
+
+		environmentMapping, err := s.validateRepositoryEnvironmentMapping(ctx, repositoryCreate.EnvironmentMapping, project.TenantMode)
+		if err != nil {
+			if common.ErrorCode(err) == common.Invalid {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate environment mapping").SetInternal(err)
+		}
+		repositoryCreate.EnvironmentMapping = environmentMapping
+
 		vcs, err := s.store.GetVCSByID(ctx, repositoryCreate.VCSID)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find VCS for creating repository: %d", repositoryCreate.VCSID)).SetInternal(err)
@@ -230,58 +264,88 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		}
 		repositoryCreate.WebhookSecretToken = secretToken
 
-		// Create a new webhook and retrieve the created webhook ID
-		var webhookCreatePayload []byte
-		switch vcs.Type {
-		case vcsPlugin.GitLabSelfHost:
-			webhookCreate := gitlab.WebhookCreate{
-				URL:                    fmt.Sprintf("%s:%d/%s/%s", s.profile.BackendHost, s.profile.BackendPort, gitlabWebhookPath, repositoryCreate.WebhookEndpointID),
-				SecretToken:            repositoryCreate.WebhookSecretToken,
-				PushEvents:             true,
-				PushEventsBranchFilter: repositoryCreate.BranchFilter,
-				EnableSSLVerification:  false, // TODO(tianzhou): This is set to false, be lax to not enable_ssl_verification
+		var groupWebhook *api.VCSGroupWebhook
+		if vcs.Type == vcsPlugin.GitLabSelfHost {
+			groupWebhook, err = s.findCoveringGroupWebhook(ctx, vcs.ID, repositoryCreate.FullPath)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find group webhook for repository %s", repositoryCreate.FullPath)).SetInternal(err)
 			}
-			webhookCreatePayload, err = json.Marshal(webhookCreate)
+		}
+
+		if vcs.AuthMode == vcsPlugin.AuthModeGitHubApp {
+			// The GitHub App calls the API with its own installation token instead of the token of the user linking the repository,
+			// and the push events are delivered to the webhook of the app, so there is no webhook to create for the repository.
+			token, err := createGitHubAppInstallationToken(ctx, vcs, repositoryCreate.ExternalID)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal request body for creating webhook for project ID: %d", repositoryCreate.ProjectID)).SetInternal(err)
+				if common.ErrorCode(err) == common.NotFound {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("GitHub App %q is not installed on repository %s", vcs.Name, repositoryCreate.ExternalID))
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create GitHub App installation token for project ID: %v", repositoryCreate.ProjectID)).SetInternal(err)
 			}
-		case vcsPlugin.GitHubCom:
-			webhookHost := s.profile.BackendHost
-			if s.profile.BackendHost == "http://localhost" {
-				webhookHost = fmt.Sprintf("%s:%d", s.profile.BackendHost, s.profile.BackendPort)
+			repositoryCreate.AccessToken = token.AccessToken
+			repositoryCreate.ExpiresTs = token.ExpiresTs
+			repositoryCreate.RefreshToken = token.RefreshToken
+		} else if groupWebhook != nil {
+			// The push events of the repository under the group with the webhook are delivered to the group webhook,
+			// creating another webhook for the repository would process them twice.
+			vcsLog.Debug("Linked repository through the group webhook",
+				zap.String("repository", repositoryCreate.FullPath),
+				zap.String("group", groupWebhook.GroupFullPath),
+			)
+		} else {
+			// Create a new webhook and retrieve the created webhook ID
+			var webhookCreatePayload []byte
+			switch vcs.Type {
+			case vcsPlugin.GitLabSelfHost:
+				webhookCreate := gitlab.WebhookCreate{
+					URL:                    fmt.Sprintf("%s:%d/%s/%s", s.profile.BackendHost, s.profile.BackendPort, gitlabWebhookPath, repositoryCreate.WebhookEndpointID),
+					SecretToken:            repositoryCreate.WebhookSecretToken,
+					PushEvents:             true,
+					PushEventsBranchFilter: repositoryCreate.BranchFilter,
+					EnableSSLVerification:  false, // TODO(tianzhou): This is set to false, be lax to not enable_ssl_verification
+				}
+				webhookCreatePayload, err = json.Marshal(webhookCreate)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal request body for creating webhook for project ID: %d", repositoryCreate.ProjectID)).SetInternal(err)
+				}
+			case vcsPlugin.GitHubCom:
+				webhookHost := s.profile.BackendHost
+				if s.profile.BackendHost == "http://localhost" {
+					webhookHost = fmt.Sprintf("%s:%d", s.profile.BackendHost, s.profile.BackendPort)
+				}
+				webhookPost := github.WebhookCreateOrUpdate{
+					Config: github.WebhookConfig{
+						URL:         fmt.Sprintf("%s/%s/%s", webhookHost, githubWebhookPath, repositoryCreate.WebhookEndpointID),
+						ContentType: "json",
+						Secret:      repositoryCreate.WebhookSecretToken,
+						InsecureSSL: 1, // TODO: Allow user to specify this value through api.RepositoryCreate
+					},
+					Events: []string{"push"},
+				}
+				webhookCreatePayload, err = json.Marshal(webhookPost)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal request body for creating webhook for project ID: %d", repositoryCreate.ProjectID)).SetInternal(err)
+				}
 			}
-			webhookPost := github.WebhookCreateOrUpdate{
-				Config: github.WebhookConfig{
-					URL:         fmt.Sprintf("%s/%s/%s", webhookHost, githubWebhookPath, repositoryCreate.WebhookEndpointID),
-					ContentType: "json",
-					Secret:      repositoryCreate.WebhookSecretToken,
-					InsecureSSL: 1, // TODO: Allow user to specify this value through api.RepositoryCreate
+
+			webhookID, err := vcsPlugin.Get(vcs.Type, vcsPlugin.ProviderConfig{}).CreateWebhook(
+				ctx,
+				common.OauthContext{
+					AccessToken: repositoryCreate.AccessToken,
+					// We use refreshTokenNoop() because the repository isn't created yet.
+					Refresher: refreshTokenNoop(),
 				},
-				Events: []string{"push"},
-			}
-			webhookCreatePayload, err = json.Marshal(webhookPost)
+				vcs.InstanceURL,
+				repositoryCreate.ExternalID,
+				webhookCreatePayload,
+			)
+
 			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal request body for creating webhook for project ID: %d", repositoryCreate.ProjectID)).SetInternal(err)
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create webhook for project ID: %v", repositoryCreate.ProjectID)).SetInternal(err)
 			}
+			repositoryCreate.ExternalWebhookID = webhookID
 		}
 
-		webhookID, err := vcsPlugin.Get(vcs.Type, vcsPlugin.ProviderConfig{}).CreateWebhook(
-			ctx,
-			common.OauthContext{
-				AccessToken: repositoryCreate.AccessToken,
-				// We use refreshTokenNoop() because the repository isn't created yet.
-				Refresher: refreshTokenNoop(),
-			},
-			vcs.InstanceURL,
-			repositoryCreate.ExternalID,
-			webhookCreatePayload,
-		)
-
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create webhook for project ID: %v", repositoryCreate.ProjectID)).SetInternal(err)
-		}
-		repositoryCreate.ExternalWebhookID = webhookID
-
 		// Remove enclosing /
 		repositoryCreate.BaseDirectory = strings.Trim(repositoryCreate.BaseDirectory, "/")
 		repository, err := s.store.CreateRepository(ctx, repositoryCreate)
@@ -350,16 +414,27 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project not found with ID %d", projectID))
 		}
 
-		if repoPatch.FilePathTemplate != nil {
-			if err := api.ValidateRepositoryFilePathTemplate(*repoPatch.FilePathTemplate, project.TenantMode); err != nil {
+		if repoPatch.SchemaPathTemplate != nil {
+			if err := api.ValidateRepositorySchemaPathTemplate(*repoPatch.SchemaPathTemplate, project.TenantMode); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
+			}
+		}
+
+		if repoPatch.TrustedSigningKeys != nil {
+			if err := api.ValidateTrustedSigningKeys(*repoPatch.TrustedSigningKeys); err != nil {
 				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed patch linked repository request: %s", err.Error()))
 			}
 		}
 
-		if repoPatch.SchemaPathTemplate != nil {
-			if err := api.ValidateRepositorySchemaPathTemplate(*repoPatch.SchemaPathTemplate, project.TenantMode); err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
+		if repoPatch.EnvironmentMapping != nil {
+			environmentMapping, err := s.validateRepositoryEnvironmentMapping(ctx, *repoPatch.EnvironmentMapping, project.TenantMode)
+			if err != nil {
+				if common.ErrorCode(err) == common.Invalid {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed patch linked repository request: %s", err.Error()))
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate environment mapping").SetInternal(err)
 			}
+			repoPatch.EnvironmentMapping = &environmentMapping
 		}
 
 		// Remove enclosing /
@@ -384,13 +459,27 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		}
 
 		repo := repoList[0]
+		if repoPatch.FilePathTemplate != nil || repoPatch.FileFormat != nil {
+			// Validate the file path template against the file format, either of which may be unchanged.
+			filePathTemplate, fileFormat := repo.FilePathTemplate, repo.FileFormat
+			if repoPatch.FilePathTemplate != nil {
+				filePathTemplate = *repoPatch.FilePathTemplate
+			}
+			if repoPatch.FileFormat != nil {
+				fileFormat = *repoPatch.FileFormat
+			}
+			if err := api.ValidateRepositoryFileFormat(fileFormat, filePathTemplate, project.TenantMode); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed patch linked repository request: %s", err.Error()))
+			}
+		}
 		repoPatch.ID = repo.ID
 		updatedRepo, err := s.store.PatchRepository(ctx, repoPatch)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update repository for project ID: %d", projectID)).SetInternal(err)
 		}
 
-		if repoPatch.BranchFilter != nil {
+		// The repository linked through the GitHub App or the GitLab group webhook has no webhook, its push events of all branches are delivered to the webhook of the app or the group.
+		if repoPatch.BranchFilter != nil && repo.ExternalWebhookID != "" {
 			vcs, err := s.store.GetVCSByID(ctx, repo.VCSID)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update repository for project ID: %d", projectID)).SetInternal(err)
@@ -496,27 +585,30 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete repository for project ID: %d", projectID)).SetInternal(err)
 		}
 
-		// Delete the webhook after we successfully delete the repository.
-		// This is because in case the webhook deletion fails, we can still have a cleanup process to cleanup the orphaned webhook.
-		// If we delete it before we delete the repository, then if the repository deletion fails, we will have a broken repository with no webhook.
-		err = vcsPlugin.Get(vcs.Type, vcsPlugin.ProviderConfig{}).DeleteWebhook(
-			ctx,
-			// Need to get ApplicationID, Secret from vcs instead of repository.vcs since the latter is not composed.
-			common.OauthContext{
-				ClientID:     vcs.ApplicationID,
-				ClientSecret: vcs.Secret,
-				AccessToken:  repo.AccessToken,
-				RefreshToken: repo.RefreshToken,
-				Refresher:    s.refreshToken(ctx, repo.ID),
-			},
-			vcs.InstanceURL,
-			repo.ExternalID,
-			repo.ExternalWebhookID,
-		)
+		// The repository linked through the GitHub App or the GitLab group webhook has no webhook to delete.
+		if repo.ExternalWebhookID != "" {
+			// Delete the webhook after we successfully delete the repository.
+			// This is because in case the webhook deletion fails, we can still have a cleanup process to cleanup the orphaned webhook.
+			// If we delete it before we delete the repository, then if the repository deletion fails, we will have a broken repository with no webhook.
+			err = vcsPlugin.Get(vcs.Type, vcsPlugin.ProviderConfig{}).DeleteWebhook(
+				ctx,
+				// Need to get ApplicationID, Secret from vcs instead of repository.vcs since the latter is not composed.
+				common.OauthContext{
+					ClientID:     vcs.ApplicationID,
+					ClientSecret: vcs.Secret,
+					AccessToken:  repo.AccessToken,
+					RefreshToken: repo.RefreshToken,
+					Refresher:    s.refreshToken(ctx, repo.ID),
+				},
+				vcs.InstanceURL,
+				repo.ExternalID,
+				repo.ExternalWebhookID,
+			)
 
-		if err != nil {
-			// Despite the error here, we have deleted the repository in the database, we still return success.
-			log.Error("Failed to delete webhook for project", zap.Int("project", projectID), zap.Int("repo", repo.ID), zap.Error(err))
+			if err != nil {
+				// Despite the error here, we have deleted the repository in the database, we still return success.
+				log.Error("Failed to delete webhook for project", zap.Int("project", projectID), zap.Int("repo", repo.ID), zap.Error(err))
+			}
 		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
@@ -613,3 +705,142 @@ func refreshTokenNoop() common.TokenRefresher {
 		return nil
 	}
 }
+
+// validateRepositoryEnvironmentMapping validates the environment mapping of the repository against the existing environments,
+// and returns the normalized payload to be stored.
+func (s *Server) validateRepositoryEnvironmentMapping(ctx context.Context, payload string, tenantMode api.ProjectTenantMode) (string, error) {
+	mapping, err := api.ValidateAndGetRepositoryEnvironmentMapping(payload, tenantMode)
+	if err != nil {
+		return "", err
+	}
+	if len(mapping.RuleList) == 0 {
+		return "", nil
+	}
+
+	rowStatus := api.Normal
+	environmentList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{RowStatus: &rowStatus})
+	if err != nil {
+		return "", err
+	}
+	for _, rule := range mapping.RuleList {
+		found := false
+		for _, environment := range environmentList {
+			// Environment name comparison is case insensitive, the same as the one in the file path template.
+			if strings.EqualFold(environment.Name, rule.Environment) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", common.Errorf(common.Invalid, "environment %q in the environment mapping not found", rule.Environment)
+		}
+	}
+
+	bytes, err := json.Marshal(mapping)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// validateProjectAllowedEnvironmentList validates the environments the project can deploy to against the existing environments
+// and the databases of the project, and returns the normalized payload to be stored.
+func (s *Server) validateProjectAllowedEnvironmentList(ctx context.Context, projectID int, payload string) (string, error) {
+	environmentIDList, err := api.ParseProjectAllowedEnvironmentList(payload)
+	if err != nil {
+		return "", common.Errorf(common.Invalid, "%v", err)
+	}
+	if len(environmentIDList) == 0 {
+		return "[]", nil
+	}
+	// The databases synced from the instances are put in the default project, whatever environment they're in.
+	if projectID == api.DefaultProjectID {
+		return "", common.Errorf(common.Invalid, "the environments of the default project can not be restricted")
+	}
+
+	allowedSet := make(map[int]bool)
+	var normalizedList []int
+	for _, environmentID := range environmentIDList {
+		if allowedSet[environmentID] {
+			continue
+		}
+		environment, err := s.store.GetEnvironmentByID(ctx, environmentID)
+		if err != nil {
+			return "", err
+		}
+		if environment == nil {
+			return "", common.Errorf(common.Invalid, "environment ID %d not found", environmentID)
+		}
+		allowedSet[environmentID] = true
+		normalizedList = append(normalizedList, environmentID)
+	}
+	databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{ProjectID: &projectID})
+	if err != nil {
+		return "", err
+	}
+	for _, database := range databaseList {
+		if !allowedSet[database.Instance.EnvironmentID] {
+			return "", common.Errorf(common.Invalid, "database %q of the project is in the environment %q, transfer it out first", database.Name, database.Instance.Environment.Name)
+		}
+	}
+
+	bytes, err := json.Marshal(normalizedList)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// validateProjectEnvironment returns an error if the project isn't allowed to deploy to the environment.
+func (s *Server) validateProjectEnvironment(ctx context.Context, project *api.Project, environmentID int) error {
+	allowed, err := project.IsEnvironmentAllowed(environmentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the allowed environments of project %q", project.Name)).SetInternal(err)
+	}
+	if allowed {
+		return nil
+	}
+	environment, err := s.store.GetEnvironmentByID(ctx, environmentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find environment with ID %d", environmentID)).SetInternal(err)
+	}
+	environmentName := strconv.Itoa(environmentID)
+	if environment != nil {
+		environmentName = environment.Name
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %q is not allowed to deploy to environment %q", project.Name, environmentName))
+}
+
+// gitHubAppTokenRefreshWindow is how long before the expiry we create a new installation token,
+// which leaves enough time for the VCS calls made with the current one.
+const gitHubAppTokenRefreshWindow = 10 * time.Minute
+
+// refreshGitHubAppToken creates a new installation token for the repository linked through the GitHub App if the stored one
+// is about to expire, and stores it to both the repository record and the given repository.
+// The tokens of the other repositories are refreshed by the VCS plugin on demand.
+func (s *Server) refreshGitHubAppToken(ctx context.Context, repo *api.Repository) error {
+	if repo.VCS == nil || repo.VCS.AuthMode != vcsPlugin.AuthModeGitHubApp {
+		return nil
+	}
+	if time.Until(time.Unix(repo.ExpiresTs, 0)) > gitHubAppTokenRefreshWindow {
+		return nil
+	}
+	token, err := createGitHubAppInstallationToken(ctx, repo.VCS, repo.ExternalID)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub App installation token for repository %s, error: %w", repo.ExternalID, err)
+	}
+	if err := s.refreshToken(ctx, repo.ID)(token.AccessToken, token.RefreshToken, token.ExpiresTs); err != nil {
+		return fmt.Errorf("failed to store GitHub App installation token for repository %s, error: %w", repo.ExternalID, err)
+	}
+	repo.AccessToken, repo.RefreshToken, repo.ExpiresTs = token.AccessToken, token.RefreshToken, token.ExpiresTs
+	return nil
+}
+
+// createGitHubAppInstallationToken creates an installation token of the GitHub App which only has access to the repository.
+func createGitHubAppInstallationToken(ctx context.Context, vcs *api.VCS, repositoryFullName string) (*vcsPlugin.OAuthToken, error) {
+	appClient, err := github.NewAppClient(nil, vcs.ApplicationID, vcs.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return appClient.CreateInstallationToken(ctx, vcs.InstanceURL, repositoryFullName)
+}
@@ -0,0 +1,524 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// projectConfig is the project configuration as code, it's exported from a project and imported to create
+// the same project on another Bytebase instance.
+// Resources are referenced by their natural keys (member email and instance name) instead of IDs, since IDs differ between instances.
+// The review policies are bound to the environments rather than the project, so they are not part of the project configuration.
+// Example:
+//
+//	name: Shop
+//	key: SHOP
+//	tenantMode: TENANT
+//	dbNameTemplate: "{{DB_NAME}}_{{TENANT}}"
+//	dbNamePattern: ^shop_[a-z0-9_]+$
+//	members:
+//	  - email: dba@example.com
+//	    role: OWNER
+//	deployments:
+//	  - name: Staging
+//	    selector:
+//	      - key: bb.environment
+//	        operator: In
+//	        values: [Staging]
+//	webhooks:
+//	  - type: bb.plugin.webhook.slack
+//	    name: Shop alerts
+//	    url: https://hooks.slack.com/services/XXX
+//	    activities: [bb.issue.create]
+//	assignmentRules:
+//	  - instance: prod-mysql
+//	    namePattern: ^shop_
+//	issueSLA:
+//	  timeToApprove: 86400
+//	  timeToExecute: 259200
+//	  reminderThreshold: 80
+//	issueFields:
+//	  - name: Change ticket
+//	    type: STRING
+//	  - name: Risk
+//	    type: ENUM
+//	    enumValues: [LOW, MEDIUM, HIGH]
+type projectConfig struct {
+	Name           string                   `yaml:"name"`
+	Key            string                   `yaml:"key"`
+	TenantMode     api.ProjectTenantMode    `yaml:"tenantMode"`
+	DBNameTemplate string                   `yaml:"dbNameTemplate,omitempty"`
+	PinnedNote     string                   `yaml:"pinnedNote,omitempty"`
+	DBNamePattern  string                   `yaml:"dbNamePattern,omitempty"`
+	Members        []bootstrapProjectMember `yaml:"members,omitempty"`
+	// Deployments is the deployment config of the tenant mode project.
+	Deployments     []projectConfigDeployment     `yaml:"deployments,omitempty"`
+	Webhooks        []projectConfigWebhook        `yaml:"webhooks,omitempty"`
+	AssignmentRules []projectConfigAssignmentRule `yaml:"assignmentRules,omitempty"`
+	IssueSLA        *projectConfigIssueSLA        `yaml:"issueSLA,omitempty"`
+	IssueFields     []projectConfigIssueField     `yaml:"issueFields,omitempty"`
+}
+
+type projectConfigLabelRequirement struct {
+	Key      string           `yaml:"key"`
+	Operator api.OperatorType `yaml:"operator"`
+	Values   []string         `yaml:"values,omitempty"`
+}
+
+type projectConfigDeployment struct {
+	Name     string                          `yaml:"name"`
+	Selector []projectConfigLabelRequirement `yaml:"selector"`
+}
+
+type projectConfigWebhook struct {
+	Type       string   `yaml:"type"`
+	Name       string   `yaml:"name"`
+	URL        string   `yaml:"url"`
+	Activities []string `yaml:"activities"`
+}
+
+type projectConfigAssignmentRule struct {
+	// Instance is the instance name, empty matches any instance.
+	Instance    string                          `yaml:"instance,omitempty"`
+	NamePattern string                          `yaml:"namePattern,omitempty"`
+	Selector    []projectConfigLabelRequirement `yaml:"selector,omitempty"`
+}
+
+type projectConfigIssueSLA struct {
+	TimeToApprove     int64 `yaml:"timeToApprove"`
+	TimeToExecute     int64 `yaml:"timeToExecute"`
+	ReminderThreshold int   `yaml:"reminderThreshold"`
+}
+
+type projectConfigIssueField struct {
+	Name       string             `yaml:"name"`
+	Type       api.IssueFieldType `yaml:"type"`
+	EnumValues []string           `yaml:"enumValues,omitempty"`
+}
+
+func (s *Server) registerProjectConfigRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/config", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project not found with ID %d", projectID))
+		}
+
+		conf, err := s.getProjectConfig(ctx, project)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to export project %q configuration", project.Key)).SetInternal(err)
+		}
+		buf, err := yaml.Marshal(conf)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal project configuration").SetInternal(err)
+		}
+		return c.Blob(http.StatusOK, "application/x-yaml", buf)
+	})
+
+	// Importing the configuration creates a new project, so that the configuration is applied as a whole
+	// instead of merged into an existing project.
+	g.POST("/project/config", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		buf, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read project configuration").SetInternal(err)
+		}
+		conf, err := parseProjectConfig(buf)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid project configuration: %s", err.Error())).SetInternal(err)
+		}
+		if conf.TenantMode == api.TenantModeTenant && !s.feature(api.FeatureMultiTenancy) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+		}
+
+		project, err := s.applyProjectConfig(ctx, conf, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Project already exists: %s", conf.Key)).SetInternal(err)
+			}
+			if common.ErrorCode(err) == common.Invalid {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to import project %q configuration", conf.Key)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, project); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal import project configuration response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getProjectConfig collects the configuration of the project.
+func (s *Server) getProjectConfig(ctx context.Context, project *api.Project) (*projectConfig, error) {
+	conf := &projectConfig{
+		Name:           project.Name,
+		Key:            project.Key,
+		TenantMode:     project.TenantMode,
+		DBNameTemplate: project.DBNameTemplate,
+		PinnedNote:     project.PinnedNote,
+		DBNamePattern:  project.DBNamePattern,
+	}
+	for _, member := range project.ProjectMemberList {
+		// The members synced from the VCS are synced again on the target instance.
+		if member.RoleProvider != api.ProjectRoleProviderBytebase || member.Principal == nil {
+			continue
+		}
+		conf.Members = append(conf.Members, bootstrapProjectMember{
+			Email: member.Principal.Email,
+			Role:  common.ProjectRole(member.Role),
+		})
+	}
+
+	deploymentConfig, err := s.store.GetDeploymentConfigByProjectID(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deployment config, error: %w", err)
+	}
+	if deploymentConfig != nil {
+		schedule, err := api.ValidateAndGetDeploymentSchedule(deploymentConfig.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deployment config, error: %w", err)
+		}
+		for _, deployment := range schedule.Deployments {
+			conf.Deployments = append(conf.Deployments, projectConfigDeployment{
+				Name:     deployment.Name,
+				Selector: toProjectConfigLabelRequirementList(deployment.Spec.Selector),
+			})
+		}
+	}
+
+	webhookList, err := s.store.FindProjectWebhook(ctx, &api.ProjectWebhookFind{ProjectID: &project.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook list, error: %w", err)
+	}
+	for _, webhook := range webhookList {
+		conf.Webhooks = append(conf.Webhooks, projectConfigWebhook{
+			Type:       webhook.Type,
+			Name:       webhook.Name,
+			URL:        webhook.URL,
+			Activities: webhook.ActivityList,
+		})
+	}
+
+	ruleList, err := s.store.FindDatabaseAssignmentRule(ctx, &api.DatabaseAssignmentRuleFind{ProjectID: &project.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find database assignment rule list, error: %w", err)
+	}
+	for _, rule := range ruleList {
+		confRule := projectConfigAssignmentRule{NamePattern: rule.NamePattern}
+		if rule.InstanceID != nil {
+			instance, err := s.store.GetInstanceByID(ctx, *rule.InstanceID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find instance ID %d, error: %w", *rule.InstanceID, err)
+			}
+			if instance == nil {
+				return nil, fmt.Errorf("instance ID not found: %d", *rule.InstanceID)
+			}
+			confRule.Instance = instance.Name
+		}
+		if rule.LabelSelector != "" {
+			selector := &api.LabelSelector{}
+			if err := json.Unmarshal([]byte(rule.LabelSelector), selector); err != nil {
+				return nil, fmt.Errorf("invalid label selector of database assignment rule ID %d, error: %w", rule.ID, err)
+			}
+			confRule.Selector = toProjectConfigLabelRequirementList(selector)
+		}
+		conf.AssignmentRules = append(conf.AssignmentRules, confRule)
+	}
+
+	setting, err := s.store.GetIssueSLASettingByProjectID(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find issue SLA setting, error: %w", err)
+	}
+	if setting != nil {
+		conf.IssueSLA = &projectConfigIssueSLA{
+			TimeToApprove:     setting.TimeToApprove,
+			TimeToExecute:     setting.TimeToExecute,
+			ReminderThreshold: setting.ReminderThreshold,
+		}
+	}
+
+	fieldList, err := s.store.FindIssueField(ctx, &api.IssueFieldFind{ProjectID: &project.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find issue field list, error: %w", err)
+	}
+	for _, field := range fieldList {
+		conf.IssueFields = append(conf.IssueFields, projectConfigIssueField{
+			Name:       field.Name,
+			Type:       field.Type,
+			EnumValues: field.EnumValueList,
+		})
+	}
+	return conf, nil
+}
+
+// parseProjectConfig parses and validates the project configuration.
+func parseProjectConfig(buf []byte) (*projectConfig, error) {
+	conf := &projectConfig{}
+	if err := yaml.Unmarshal(buf, conf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project configuration, error: %w", err)
+	}
+
+	if conf.Key == "" || conf.Name == "" {
+		return nil, fmt.Errorf("project key and name are required")
+	}
+	switch conf.TenantMode {
+	case "":
+		conf.TenantMode = api.TenantModeDisabled
+	case api.TenantModeDisabled, api.TenantModeTenant:
+	default:
+		return nil, fmt.Errorf("invalid tenant mode %q", conf.TenantMode)
+	}
+	if err := api.ValidateProjectDBNameTemplate(conf.DBNameTemplate); err != nil {
+		return nil, err
+	}
+	if err := api.ValidateDatabaseNamePattern(conf.DBNamePattern); err != nil {
+		return nil, err
+	}
+	if conf.TenantMode != api.TenantModeTenant && conf.DBNameTemplate != "" {
+		return nil, fmt.Errorf("database name template can only be set for tenant mode project")
+	}
+	if conf.TenantMode != api.TenantModeTenant && len(conf.Deployments) > 0 {
+		return nil, fmt.Errorf("deployments can only be set for tenant mode project")
+	}
+	for _, member := range conf.Members {
+		if member.Email == "" {
+			return nil, fmt.Errorf("member email is required")
+		}
+		switch member.Role {
+		case common.ProjectOwner, common.ProjectDeveloper:
+		default:
+			return nil, fmt.Errorf("member %q has invalid role %q", member.Email, member.Role)
+		}
+	}
+	if _, err := api.ValidateAndGetDeploymentSchedule(conf.getDeploymentSchedulePayload()); err != nil {
+		return nil, fmt.Errorf("invalid deployments, error: %w", err)
+	}
+	for _, webhook := range conf.Webhooks {
+		if webhook.Type == "" || webhook.Name == "" || webhook.URL == "" {
+			return nil, fmt.Errorf("webhook type, name and url are required")
+		}
+	}
+	if conf.IssueSLA != nil {
+		if conf.IssueSLA.TimeToApprove < 0 || conf.IssueSLA.TimeToExecute < 0 {
+			return nil, fmt.Errorf("issue SLA must not be negative")
+		}
+		if conf.IssueSLA.ReminderThreshold <= 0 || conf.IssueSLA.ReminderThreshold > 100 {
+			return nil, fmt.Errorf("issue SLA reminder threshold must be in (0, 100]")
+		}
+	}
+	fieldNameSet := make(map[string]bool)
+	for _, field := range conf.IssueFields {
+		if err := api.ValidateIssueField(field.Name, field.Type, field.EnumValues); err != nil {
+			return nil, err
+		}
+		if fieldNameSet[field.Name] {
+			return nil, fmt.Errorf("duplicate issue field %q", field.Name)
+		}
+		fieldNameSet[field.Name] = true
+	}
+	return conf, nil
+}
+
+// applyProjectConfig creates the project with the configuration.
+// The references are resolved before creating anything, so an invalid configuration doesn't leave a partial project.
+func (s *Server) applyProjectConfig(ctx context.Context, conf *projectConfig, creatorID int) (*api.Project, error) {
+	memberCreateList := []*api.ProjectMemberCreate{
+		{
+			CreatorID:    creatorID,
+			Role:         common.ProjectOwner,
+			PrincipalID:  creatorID,
+			RoleProvider: api.ProjectRoleProviderBytebase,
+		},
+	}
+	for _, member := range conf.Members {
+		principal, err := s.store.GetPrincipalByEmail(ctx, member.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find principal %q, error: %w", member.Email, err)
+		}
+		if principal == nil {
+			return nil, common.Errorf(common.Invalid, "member %q not found", member.Email)
+		}
+		// The importer is added as the project owner unless the configuration says otherwise.
+		if principal.ID == creatorID {
+			memberCreateList[0].Role = member.Role
+			continue
+		}
+		memberCreateList = append(memberCreateList, &api.ProjectMemberCreate{
+			CreatorID:    creatorID,
+			Role:         member.Role,
+			PrincipalID:  principal.ID,
+			RoleProvider: api.ProjectRoleProviderBytebase,
+		})
+	}
+	var ruleCreateList []*api.DatabaseAssignmentRuleCreate
+	for _, rule := range conf.AssignmentRules {
+		ruleCreate := &api.DatabaseAssignmentRuleCreate{
+			CreatorID:   creatorID,
+			NamePattern: rule.NamePattern,
+		}
+		if rule.Instance != "" {
+			instanceName := rule.Instance
+			instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{Name: &instanceName})
+			if err != nil {
+				return nil, fmt.Errorf("failed to find instance %q, error: %w", rule.Instance, err)
+			}
+			if len(instanceList) != 1 {
+				return nil, common.Errorf(common.Invalid, "database assignment rule references unknown instance %q", rule.Instance)
+			}
+			ruleCreate.InstanceID = &instanceList[0].ID
+		}
+		if len(rule.Selector) > 0 {
+			selector, err := json.Marshal(toLabelSelector(rule.Selector))
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal label selector, error: %w", err)
+			}
+			ruleCreate.LabelSelector = string(selector)
+		}
+		ruleCreateList = append(ruleCreateList, ruleCreate)
+	}
+
+	project, err := s.store.CreateProject(ctx, &api.ProjectCreate{
+		CreatorID:      creatorID,
+		Name:           conf.Name,
+		Key:            conf.Key,
+		TenantMode:     conf.TenantMode,
+		DBNameTemplate: conf.DBNameTemplate,
+		RoleProvider:   api.ProjectRoleProviderBytebase,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if conf.PinnedNote != "" || conf.DBNamePattern != "" {
+		if project, err = s.store.PatchProject(ctx, &api.ProjectPatch{
+			ID:            project.ID,
+			UpdaterID:     creatorID,
+			PinnedNote:    &conf.PinnedNote,
+			DBNamePattern: &conf.DBNamePattern,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to set pinned note and database name pattern, error: %w", err)
+		}
+	}
+	for _, memberCreate := range memberCreateList {
+		memberCreate.ProjectID = project.ID
+		if _, err := s.store.CreateProjectMember(ctx, memberCreate); err != nil {
+			return nil, fmt.Errorf("failed to add project member ID %d, error: %w", memberCreate.PrincipalID, err)
+		}
+	}
+	if len(conf.Deployments) > 0 {
+		if _, err := s.store.UpsertDeploymentConfig(ctx, &api.DeploymentConfigUpsert{
+			UpdaterID: creatorID,
+			ProjectID: project.ID,
+			Payload:   conf.getDeploymentSchedulePayload(),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to set deployment config, error: %w", err)
+		}
+	}
+	for _, webhook := range conf.Webhooks {
+		if _, err := s.store.CreateProjectWebhook(ctx, &api.ProjectWebhookCreate{
+			CreatorID:    creatorID,
+			ProjectID:    project.ID,
+			Type:         webhook.Type,
+			Name:         webhook.Name,
+			URL:          webhook.URL,
+			ActivityList: webhook.Activities,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create webhook %q, error: %w", webhook.Name, err)
+		}
+	}
+	for _, ruleCreate := range ruleCreateList {
+		ruleCreate.ProjectID = project.ID
+		if _, err := s.store.CreateDatabaseAssignmentRule(ctx, ruleCreate); err != nil {
+			return nil, fmt.Errorf("failed to create database assignment rule, error: %w", err)
+		}
+	}
+	if conf.IssueSLA != nil {
+		if _, err := s.store.UpsertIssueSLASetting(ctx, &api.IssueSLASettingUpsert{
+			UpdaterID:         creatorID,
+			ProjectID:         project.ID,
+			TimeToApprove:     conf.IssueSLA.TimeToApprove,
+			TimeToExecute:     conf.IssueSLA.TimeToExecute,
+			ReminderThreshold: conf.IssueSLA.ReminderThreshold,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to set issue SLA, error: %w", err)
+		}
+	}
+	for _, field := range conf.IssueFields {
+		if _, err := s.store.CreateIssueField(ctx, &api.IssueFieldCreate{
+			CreatorID:     creatorID,
+			ProjectID:     project.ID,
+			Name:          field.Name,
+			Type:          field.Type,
+			EnumValueList: field.EnumValues,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create issue field %q, error: %w", field.Name, err)
+		}
+	}
+
+	// Returns the project with the members.
+	return s.store.GetProjectByID(ctx, project.ID)
+}
+
+// getDeploymentSchedulePayload returns the deployment config payload, i.e. the JSON encoded DeploymentSchedule.
+func (conf *projectConfig) getDeploymentSchedulePayload() string {
+	schedule := &api.DeploymentSchedule{Deployments: []*api.Deployment{}}
+	for _, deployment := range conf.Deployments {
+		schedule.Deployments = append(schedule.Deployments, &api.Deployment{
+			Name: deployment.Name,
+			Spec: &api.DeploymentSpec{Selector: toLabelSelector(deployment.Selector)},
+		})
+	}
+	// The schedule only consists of strings, so it can always be marshaled.
+	buf, _ := json.Marshal(schedule)
+	return string(buf)
+}
+
+func toProjectConfigLabelRequirementList(selector *api.LabelSelector) []projectConfigLabelRequirement {
+	var requirementList []projectConfigLabelRequirement
+	if selector == nil {
+		return requirementList
+	}
+	for _, requirement := range selector.MatchExpressions {
+		requirementList = append(requirementList, projectConfigLabelRequirement{
+			Key:      requirement.Key,
+			Operator: requirement.Operator,
+			Values:   requirement.Values,
+		})
+	}
+	return requirementList
+}
+
+func toLabelSelector(requirementList []projectConfigLabelRequirement) *api.LabelSelector {
+	selector := &api.LabelSelector{MatchExpressions: []*api.LabelSelectorRequirement{}}
+	for _, requirement := range requirementList {
+		values := requirement.Values
+		if values == nil {
+			values = []string{}
+		}
+		selector.MatchExpressions = append(selector.MatchExpressions, &api.LabelSelectorRequirement{
+			Key:      requirement.Key,
+			Operator: requirement.Operator,
+			Values:   values,
+		})
+	}
+	return selector
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestParseProjectConfig(t *testing.T) {
+	conf, err := parseProjectConfig([]byte(`
+name: Shop
+key: SHOP
+tenantMode: TENANT
+dbNameTemplate: "{{DB_NAME}}_{{TENANT}}"
+members:
+  - email: dba@example.com
+    role: OWNER
+deployments:
+  - name: Staging
+    selector:
+      - key: bb.environment
+        operator: In
+        values: [Staging]
+      - key: bb.tenant
+        operator: Exists
+webhooks:
+  - type: bb.plugin.webhook.slack
+    name: Shop alerts
+    url: https://hooks.slack.com/services/XXX
+    activities: [bb.issue.create]
+assignmentRules:
+  - namePattern: ^shop_
+issueSLA:
+  timeToApprove: 86400
+  timeToExecute: 259200
+  reminderThreshold: 80
+issueFields:
+  - name: Change ticket
+    type: STRING
+  - name: Risk
+    type: ENUM
+    enumValues: [LOW, MEDIUM, HIGH]
+`))
+	require.NoError(t, err)
+	require.Equal(t, api.TenantModeTenant, conf.TenantMode)
+	require.Equal(t, []bootstrapProjectMember{{Email: "dba@example.com", Role: common.ProjectOwner}}, conf.Members)
+	require.Equal(t,
+		`{"deployments":[{"name":"Staging","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["Staging"]},{"key":"bb.tenant","operator":"Exists","values":[]}]}}}]}`,
+		conf.getDeploymentSchedulePayload())
+
+	// The exported configuration can be imported as is.
+	buf, err := yaml.Marshal(conf)
+	require.NoError(t, err)
+	reparsed, err := parseProjectConfig(buf)
+	require.NoError(t, err)
+	require.Equal(t, conf, reparsed)
+
+	conf, err = parseProjectConfig([]byte("name: Shop\nkey: SHOP\n"))
+	require.NoError(t, err)
+	require.Equal(t, api.TenantModeDisabled, conf.TenantMode)
+
+	invalidList := []string{
+		"name: Shop\n",
+		"name: Shop\nkey: SHOP\ntenantMode: MULTI\n",
+		"name: Shop\nkey: SHOP\ndeployments:\n  - name: Prod\n    selector:\n      - key: bb.environment\n        operator: In\n        values: [Prod]\n",
+		"name: Shop\nkey: SHOP\ntenantMode: TENANT\ndeployments:\n  - name: Prod\n    selector:\n      - key: bb.tenant\n        operator: Exists\n",
+		"name: Shop\nkey: SHOP\nmembers:\n  - email: dba@example.com\n    role: DBA\n",
+		"name: Shop\nkey: SHOP\nwebhooks:\n  - type: bb.plugin.webhook.slack\n    name: alerts\n",
+		"name: Shop\nkey: SHOP\nissueSLA:\n  timeToApprove: 60\n",
+		"name: Shop\nkey: SHOP\nissueFields:\n  - name: Risk\n    type: ENUM\n",
+		"name: Shop\nkey: SHOP\nissueFields:\n  - name: Risk\n    type: STRING\n  - name: Risk\n    type: DATE\n",
+	}
+	for _, invalid := range invalidList {
+		_, err := parseProjectConfig([]byte(invalid))
+		require.Error(t, err, invalid)
+	}
+}
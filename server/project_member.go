@@ -49,6 +49,9 @@ func (s *Server) registerProjectMemberRoutes(g *echo.Group) {
 		if vcs == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS not found with ID: %d", repo.VCSID))
 		}
+		if err := s.refreshGitHubAppToken(ctx, repo); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to refresh token for repository: %d", repo.ID)).SetInternal(err)
+		}
 		vcsProjectMemberList, err := vcsPlugin.Get(vcs.Type, vcsPlugin.ProviderConfig{}).FetchRepositoryActiveMemberList(ctx,
 			common.OauthContext{
 				ClientID:     vcs.ApplicationID,
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerProjectVariableRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/variable", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		variableList, err := s.store.FindProjectVariable(ctx, &api.ProjectVariableFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch variable list for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, variableList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal project variable list response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/project/:projectID/variable", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+		}
+		if project.RowStatus == api.Archived {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %q is archived", project.Name))
+		}
+
+		variableCreate := &api.ProjectVariableCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, variableCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create project variable request").SetInternal(err)
+		}
+		variableCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		variableCreate.ProjectID = projectID
+
+		if err := api.ValidateProjectVariableName(variableCreate.Name); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		variable, err := s.store.CreateProjectVariable(ctx, variableCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Variable %q already exists in project %q", variableCreate.Name, project.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create project variable").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, variable); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create project variable response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/variable/:variableID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		variable, err := s.getProjectVariableFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		variablePatch := &api.ProjectVariablePatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, variablePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch project variable request").SetInternal(err)
+		}
+		variablePatch.ID = variable.ID
+		variablePatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+
+		variable, err = s.store.PatchProjectVariable(ctx, variablePatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch project variable ID: %v", variablePatch.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, variable); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch project variable response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/project/:projectID/variable/:variableID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		variable, err := s.getProjectVariableFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		variableDelete := &api.ProjectVariableDelete{
+			ID:        variable.ID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteProjectVariable(ctx, variableDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete project variable ID: %v", variable.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// getProjectVariableFromParam returns the variable specified by the ":projectID" and ":variableID" params.
+// The returned error is an echo HTTP error.
+func (s *Server) getProjectVariableFromParam(ctx context.Context, c echo.Context) (*api.ProjectVariable, error) {
+	projectID, err := strconv.Atoi(c.Param("projectID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+	}
+	variableID, err := strconv.Atoi(c.Param("variableID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Variable ID is not a number: %s", c.Param("variableID"))).SetInternal(err)
+	}
+	variable, err := s.store.GetProjectVariableByID(ctx, variableID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project variable ID: %v", variableID)).SetInternal(err)
+	}
+	if variable == nil || variable.ProjectID != projectID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project variable not found by ID %d and project ID %d", variableID, projectID))
+	}
+	return variable, nil
+}
@@ -0,0 +1,419 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	// proxySessionAudienceFmt is the audience of the proxy session token, which differs from the one of the access token
+	// so that neither token is accepted as the other.
+	proxySessionAudienceFmt = "bb.proxy.session.%s"
+	// proxySessionDuration is how long a proxied session lasts before the client has to reconnect.
+	proxySessionDuration = 8 * time.Hour
+	// proxyLoginFailureWindow is the window counting the failed proxy logins, the logins are throttled until the window ends
+	// once either the agent or the email reaches the maximum failures.
+	proxyLoginFailureWindow = 15 * time.Minute
+	// proxyLoginMaxFailurePerEmail is the maximum failed proxy logins of an email in the window, across all agents.
+	proxyLoginMaxFailurePerEmail = 5
+	// proxyLoginMaxFailurePerAgent is the maximum failed proxy logins via an agent in the window, across all emails.
+	proxyLoginMaxFailurePerAgent = 20
+)
+
+// proxyLoginFailure is the failed proxy logins of an agent or an email in the window starting at the first failure.
+type proxyLoginFailure struct {
+	count int
+	since time.Time
+}
+
+// proxySessionClaims is the claims of the proxy session token, the subject is the principal ID and the ID identifies
+// the session in the session recordings.
+type proxySessionClaims struct {
+	DatabaseID int `json:"databaseId"`
+	jwt.RegisteredClaims
+}
+
+// registerProxyProtocolRoutes registers the routes called by the proxy of the runner agents.
+// They're registered in the group of the agent protocol and authenticated by the agent token, while the user of each
+// session is authenticated by the Bytebase credentials and checked for the access to the database on every query.
+// The rejections are returned in the Error field since they're shown to the proxy client rather than the agent.
+func (s *Server) registerProxyProtocolRoutes(g *echo.Group) {
+	g.POST("/proxy/connect", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		connect := &api.ProxyConnect{}
+		if err := json.NewDecoder(c.Request().Body).Decode(connect); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed proxy connect request").SetInternal(err)
+		}
+
+		agent := c.Get(agentContextKey).(*api.Agent)
+		session, err := s.connectProxySession(ctx, agent, connect)
+		if err != nil {
+			if common.ErrorCode(err) == common.Internal {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to connect proxy session").SetInternal(err)
+			}
+			session = &api.ProxySession{Error: err.Error()}
+		}
+		return c.JSON(http.StatusOK, session)
+	})
+
+	g.POST("/proxy/query", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		query := &api.ProxyQuery{}
+		if err := json.NewDecoder(c.Request().Body).Decode(query); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed proxy query request").SetInternal(err)
+		}
+
+		agent := c.Get(agentContextKey).(*api.Agent)
+		result, err := s.runProxyQuery(ctx, agent, query)
+		if err != nil {
+			if common.ErrorCode(err) == common.Internal {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to run proxy query").SetInternal(err)
+			}
+			result = &api.ProxyQueryResult{Error: err.Error()}
+		}
+		return c.JSON(http.StatusOK, result)
+	})
+}
+
+// connectProxySession authenticates the user and checks the access to the database served by the agent, and returns the signed session.
+// The rejections are returned as the errors with the codes other than common.Internal.
+func (s *Server) connectProxySession(ctx context.Context, agent *api.Agent, connect *api.ProxyConnect) (*api.ProxySession, error) {
+	// The failed logins are throttled before checking the password, so that neither a compromised agent nor the clients of
+	// an agent can guess the passwords.
+	if s.isProxyLoginThrottled(agent.ID, connect.Email) {
+		return nil, common.Errorf(common.NotAuthorized, "too many failed logins, please retry after %v", proxyLoginFailureWindow)
+	}
+	principal, err := s.store.GetPrincipalByEmail(ctx, connect.Email)
+	if err != nil {
+		return nil, err
+	}
+	// The same message for the unknown user and the incorrect password, so that the proxy doesn't reveal the users.
+	if principal == nil || principal.Type != api.EndUser {
+		s.recordProxyLoginFailure(agent.ID, connect.Email)
+		return nil, common.Errorf(common.NotAuthorized, "incorrect email or password")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(principal.PasswordHash), []byte(connect.Password)); err != nil {
+		s.recordProxyLoginFailure(agent.ID, connect.Email)
+		return nil, common.Errorf(common.NotAuthorized, "incorrect email or password")
+	}
+
+	database, err := s.findProxyDatabase(ctx, connect.Database)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProxyAgent(agent, database); err != nil {
+		return nil, err
+	}
+	if !api.IsProxyProtocolCompatible(connect.Protocol, database.Instance.Engine) {
+		return nil, common.Errorf(common.Invalid, "database %q is a %s database, which can't be accessed via the %s protocol", connect.Database, database.Instance.Engine, connect.Protocol)
+	}
+	if err := s.checkProxyAccess(ctx, principal, database); err != nil {
+		return nil, err
+	}
+
+	token, err := s.generateProxySessionToken(principal.ID, database.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate proxy session token, error: %w", err)
+	}
+	return &api.ProxySession{
+		Token:         token,
+		InstanceName:  database.Instance.Name,
+		DatabaseName:  database.Name,
+		EngineVersion: database.Instance.EngineVersion,
+	}, nil
+}
+
+// findProxyDatabase finds the database by the name sent by the proxy client.
+func (s *Server) findProxyDatabase(ctx context.Context, name string) (*api.Database, error) {
+	instanceName, databaseName := api.ParseProxyDatabase(name)
+	if databaseName == "" {
+		return nil, common.Errorf(common.Invalid, "missing database name, connect to \"instance/database\" or \"database\"")
+	}
+	find := &api.DatabaseFind{Name: &databaseName}
+	if instanceName != "" {
+		rowStatus := api.Normal
+		instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{RowStatus: &rowStatus, Name: &instanceName})
+		if err != nil {
+			return nil, err
+		}
+		if len(instanceList) == 0 {
+			return nil, common.Errorf(common.NotFound, "instance %q not found", instanceName)
+		}
+		if len(instanceList) > 1 {
+			return nil, common.Errorf(common.Invalid, "there are multiple instances named %q", instanceName)
+		}
+		find.InstanceID = &instanceList[0].ID
+	}
+
+	databaseList, err := s.store.FindDatabase(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(databaseList) == 0 {
+		return nil, common.Errorf(common.NotFound, "database %q not found", name)
+	}
+	if len(databaseList) > 1 {
+		return nil, common.Errorf(common.Invalid, "there are multiple databases named %q, connect to \"instance/%s\" instead", databaseName, databaseName)
+	}
+	return databaseList[0], nil
+}
+
+// checkProxyAgent checks whether the database is on an instance served by the agent, an agent only proxies the sessions
+// to its own instances.
+func checkProxyAgent(agent *api.Agent, database *api.Database) error {
+	if database.Instance.AgentID == nil || *database.Instance.AgentID != agent.ID {
+		return common.Errorf(common.NotAuthorized, "database %q isn't served by agent %q", database.Name, agent.Name)
+	}
+	return nil
+}
+
+// isProxyLoginThrottled returns true if either the agent or the email has reached the maximum failed proxy logins in the window.
+func (s *Server) isProxyLoginThrottled(agentID int, email string) bool {
+	s.proxyLoginFailureMu.Lock()
+	defer s.proxyLoginFailureMu.Unlock()
+
+	for key, max := range map[string]int{
+		fmt.Sprintf("agent/%d", agentID): proxyLoginMaxFailurePerAgent,
+		fmt.Sprintf("email/%s", email):   proxyLoginMaxFailurePerEmail,
+	} {
+		failure, ok := s.proxyLoginFailureMap[key]
+		if !ok {
+			continue
+		}
+		if time.Since(failure.since) >= proxyLoginFailureWindow {
+			delete(s.proxyLoginFailureMap, key)
+			continue
+		}
+		if failure.count >= max {
+			return true
+		}
+	}
+	return false
+}
+
+// recordProxyLoginFailure counts a failed proxy login of the email via the agent.
+func (s *Server) recordProxyLoginFailure(agentID int, email string) {
+	s.proxyLoginFailureMu.Lock()
+	defer s.proxyLoginFailureMu.Unlock()
+
+	if s.proxyLoginFailureMap == nil {
+		s.proxyLoginFailureMap = make(map[string]*proxyLoginFailure)
+	}
+	for _, key := range []string{fmt.Sprintf("agent/%d", agentID), fmt.Sprintf("email/%s", email)} {
+		failure, ok := s.proxyLoginFailureMap[key]
+		if !ok || time.Since(failure.since) >= proxyLoginFailureWindow {
+			failure = &proxyLoginFailure{since: time.Now()}
+			s.proxyLoginFailureMap[key] = failure
+		}
+		failure.count++
+	}
+}
+
+// checkProxyAccess checks whether the user can access the database via the proxy.
+// The workspace owners and DBAs access all databases, while the other users only access the databases of their projects.
+// It's checked on every query, so that deactivating the user or removing the user from the project takes effect immediately.
+func (s *Server) checkProxyAccess(ctx context.Context, principal *api.Principal, database *api.Database) error {
+	member, err := s.store.GetMemberByPrincipalID(ctx, principal.ID)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.RowStatus == api.Archived {
+		return common.Errorf(common.NotAuthorized, "user %q has been deactivated", principal.Email)
+	}
+	if member.Role == api.Owner || member.Role == api.DBA {
+		return nil
+	}
+	projectMember, err := s.store.GetProjectMember(ctx, &api.ProjectMemberFind{ProjectID: &database.ProjectID, PrincipalID: &principal.ID})
+	if err != nil {
+		return err
+	}
+	if projectMember == nil {
+		return common.Errorf(common.NotAuthorized, "user %q isn't a member of project %q of database %q", principal.Email, database.Project.Name, database.Name)
+	}
+	return nil
+}
+
+// runProxyQuery runs the query of a proxied session with the same checks as the SQL editor, i.e. the statement must be a
+// single SELECT or EXPLAIN statement compliant with the SQL review policy, and the data source, the SQL editor query and
+// the data masking policies apply. The rejections are returned as the errors with the codes other than common.Internal.
+//
+// The session is only accepted from the agent serving the database, and the statements on the databases tagged sensitive
+// are recorded, including the rejected ones.
+func (s *Server) runProxyQuery(ctx context.Context, agent *api.Agent, query *api.ProxyQuery) (result *api.ProxyQueryResult, err error) {
+	principalID, databaseID, sessionID, err := s.parseProxySessionToken(query.Token)
+	if err != nil {
+		return nil, common.Errorf(common.NotAuthorized, "invalid proxy session, please reconnect, error: %v", err)
+	}
+	principal, err := s.store.GetPrincipalByID(ctx, principalID)
+	if err != nil {
+		return nil, err
+	}
+	if principal == nil {
+		return nil, common.Errorf(common.NotAuthorized, "user ID %d not found", principalID)
+	}
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &databaseID})
+	if err != nil {
+		return nil, err
+	}
+	if database == nil {
+		return nil, common.Errorf(common.NotFound, "database ID %d not found", databaseID)
+	}
+	// The instance may have been reassigned to another agent since the session was connected.
+	if err := checkProxyAgent(agent, database); err != nil {
+		return nil, err
+	}
+	if err := s.checkProxyAccess(ctx, principal, database); err != nil {
+		return nil, err
+	}
+	instance := database.Instance
+	if api.IsSessionRecorded(instance, database) {
+		start := time.Now()
+		defer func() {
+			data, errMessage := "", ""
+			if err != nil {
+				// The internal errors are recorded as well since the statement may have run.
+				errMessage = err.Error()
+			} else {
+				data, errMessage = result.Data, result.Error
+			}
+			if recordErr := s.recordSessionStatement(ctx, principal.ID, instance, database, api.SessionSourceProxy, sessionID, query.Statement, start, data, errMessage); recordErr != nil {
+				result, err = nil, recordErr
+			}
+		}()
+	}
+
+	if !validateSQLSelectStatement(query.Statement) {
+		return nil, common.Errorf(common.Invalid, "only a single SELECT or EXPLAIN statement is allowed via the Bytebase proxy, please create an issue to change the database")
+	}
+	if err := s.checkReadOnlyDataSourcePolicy(ctx, instance); err != nil {
+		return nil, err
+	}
+	queryPolicy, err := s.getSQLEditorQueryPolicy(ctx, instance, database.Name)
+	if err != nil {
+		return nil, err
+	}
+	statement, limit := query.Statement, 0
+	if queryPolicy.AutoLimit > 0 {
+		statement, limit = injectSQLEditorQueryLimit(instance.Engine, statement, queryPolicy.AutoLimit), queryPolicy.AutoLimit
+	}
+
+	result = &api.ProxyQueryResult{}
+	if s.feature(api.FeatureSQLReviewPolicy) && api.IsSQLReviewSupported(instance.Engine, s.profile.Mode) {
+		dbType, err := advisorDB.ConvertToAdvisorDBType(string(instance.Engine))
+		if err != nil {
+			return nil, err
+		}
+		adviceLevel, adviceList, err := s.sqlCheck(
+			ctx,
+			dbType,
+			database.CharacterSet,
+			database.Collation,
+			instance.EnvironmentID,
+			api.GetPolicyTagList(instance, database),
+			statement,
+			store.NewCatalog(&database.ID, s.store, instance.Engine),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check SQL review policy, error: %w", err)
+		}
+		result.AdviceList = adviceList
+		if adviceLevel == advisor.Error {
+			if err := s.createSQLEditorQueryActivity(ctx, principal.ID, api.ActivityError, instance.ID, api.ActivitySQLEditorQueryPayload{
+				Statement:    statement,
+				InstanceName: instance.Name,
+				DatabaseName: database.Name,
+				AdviceList:   adviceList,
+			}); err != nil {
+				return nil, err
+			}
+			result.Error = "the statement violates the SQL review policy"
+			return result, nil
+		}
+	}
+
+	if !s.acquireSQLEditorQuery(principal.ID, instance.EnvironmentID, queryPolicy.MaxConcurrentQueryPerUser) {
+		return nil, common.Errorf(common.Conflict, "you already have %d running queries in environment %q, please retry after they finish", queryPolicy.MaxConcurrentQueryPerUser, instance.Environment.Name)
+	}
+	defer s.releaseSQLEditorQuery(principal.ID, instance.EnvironmentID)
+
+	start := time.Now().UnixNano()
+	bytes, queryErr := s.runSQLEditorQuery(ctx, instance, database.Name, statement, limit, queryPolicy)
+	level, errMessage := api.ActivityInfo, ""
+	if queryErr != nil {
+		level, errMessage = api.ActivityError, queryErr.Error()
+	}
+	if err := s.createSQLEditorQueryActivity(ctx, principal.ID, level, instance.ID, api.ActivitySQLEditorQueryPayload{
+		Statement:    statement,
+		DurationNs:   time.Now().UnixNano() - start,
+		InstanceName: instance.Name,
+		DatabaseName: database.Name,
+		Error:        errMessage,
+		AdviceList:   result.AdviceList,
+	}); err != nil {
+		return nil, err
+	}
+	if queryErr != nil {
+		result.Error = queryErr.Error()
+		return result, nil
+	}
+	result.Data = string(bytes)
+	return result, nil
+}
+
+// generateProxySessionToken generates the token of a proxied session of the user on the database.
+func (s *Server) generateProxySessionToken(principalID, databaseID int) (string, error) {
+	claims := &proxySessionClaims{
+		DatabaseID: databaseID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{fmt.Sprintf(proxySessionAudienceFmt, s.profile.Mode)},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(proxySessionDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    issuer,
+			Subject:   strconv.Itoa(principalID),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString([]byte(s.secret))
+}
+
+// parseProxySessionToken validates the token of a proxied session, and returns the principal ID, the database ID and
+// the session ID.
+func (s *Server) parseProxySessionToken(token string) (principalID int, databaseID int, sessionID string, err error) {
+	claims := &proxySessionClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Name {
+			return nil, fmt.Errorf("unexpected proxy session token signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
+		}
+		if kid, ok := t.Header["kid"].(string); ok && kid == keyID {
+			return []byte(s.secret), nil
+		}
+		return nil, fmt.Errorf("unexpected proxy session token kid=%v", t.Header["kid"])
+	}); err != nil {
+		return 0, 0, "", err
+	}
+	if !audienceContains(claims.Audience, fmt.Sprintf(proxySessionAudienceFmt, s.profile.Mode)) {
+		return 0, 0, "", fmt.Errorf("audience mismatch, got %q", claims.Audience)
+	}
+	principalID, err = strconv.Atoi(claims.Subject)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("malformed principal ID %q", claims.Subject)
+	}
+	return principalID, claims.DatabaseID, claims.ID, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestProxySessionToken(t *testing.T) {
+	s := &Server{secret: "secret", profile: Profile{Mode: common.ReleaseModeDev}}
+	token, err := s.generateProxySessionToken(101, 7)
+	require.NoError(t, err)
+
+	principalID, databaseID, sessionID, err := s.parseProxySessionToken(token)
+	require.NoError(t, err)
+	require.Equal(t, 101, principalID)
+	require.Equal(t, 7, databaseID)
+	require.NotEmpty(t, sessionID)
+
+	// Each token is a session of its own.
+	another, err := s.generateProxySessionToken(101, 7)
+	require.NoError(t, err)
+	_, _, anotherSessionID, err := s.parseProxySessionToken(another)
+	require.NoError(t, err)
+	require.NotEqual(t, sessionID, anotherSessionID)
+
+	// The token is rejected by another workspace or another release mode.
+	_, _, _, err = (&Server{secret: "another", profile: Profile{Mode: common.ReleaseModeDev}}).parseProxySessionToken(token)
+	require.Error(t, err)
+	_, _, _, err = (&Server{secret: "secret", profile: Profile{Mode: common.ReleaseModeProd}}).parseProxySessionToken(token)
+	require.Error(t, err)
+
+	// The access token isn't a proxy session token.
+	accessToken, err := generateAccessToken(&api.Principal{ID: 101}, common.ReleaseModeDev, "secret")
+	require.NoError(t, err)
+	_, _, _, err = s.parseProxySessionToken(accessToken)
+	require.Error(t, err)
+}
+
+func TestProxySessionAgent(t *testing.T) {
+	s := newTestServer(t)
+	s.secret = "secret"
+	s.profile.Mode = common.ReleaseModeDev
+	ctx := context.Background()
+
+	database := newTestPgDatabase(ctx, t, s, "proxy_db")
+	agentA, err := s.store.CreateAgent(ctx, &api.AgentCreate{CreatorID: api.SystemBotID, Name: "runner-a", Token: "token-a"})
+	require.NoError(t, err)
+	agentB, err := s.store.CreateAgent(ctx, &api.AgentCreate{CreatorID: api.SystemBotID, Name: "runner-b", Token: "token-b"})
+	require.NoError(t, err)
+	assignInstance := func(agentID int) {
+		_, err := s.store.PatchInstance(ctx, &api.InstancePatch{ID: database.Instance.ID, UpdaterID: api.SystemBotID, AgentID: &agentID})
+		require.NoError(t, err)
+	}
+	assignInstance(agentA.ID)
+
+	// The demo owner's password is 1024.
+	connect := &api.ProxyConnect{Email: "demo@example.com", Password: "1024", Protocol: db.Postgres, Database: database.Name}
+	_, err = s.connectProxySession(ctx, agentB, connect)
+	require.Equal(t, common.NotAuthorized, common.ErrorCode(err))
+	session, err := s.connectProxySession(ctx, agentA, connect)
+	require.NoError(t, err)
+	require.NotEmpty(t, session.Token)
+
+	// The session is rejected by the other agent, and by the agent itself once the instance is reassigned.
+	query := &api.ProxyQuery{Token: session.Token, Statement: "SELECT 1"}
+	_, err = s.runProxyQuery(ctx, agentB, query)
+	require.Equal(t, common.NotAuthorized, common.ErrorCode(err))
+	assignInstance(agentB.ID)
+	_, err = s.runProxyQuery(ctx, agentA, query)
+	require.Equal(t, common.NotAuthorized, common.ErrorCode(err))
+	assignInstance(0)
+	_, err = s.runProxyQuery(ctx, agentB, query)
+	require.Equal(t, common.NotAuthorized, common.ErrorCode(err))
+
+	// Once throttled, even the correct password is rejected.
+	assignInstance(agentA.ID)
+	wrong := *connect
+	wrong.Password = "wrong"
+	for i := 0; i < proxyLoginMaxFailurePerEmail; i++ {
+		_, err = s.connectProxySession(ctx, agentB, &wrong)
+		require.ErrorContains(t, err, "incorrect email or password")
+	}
+	_, err = s.connectProxySession(ctx, agentA, connect)
+	require.ErrorContains(t, err, "too many failed logins")
+}
+
+func TestProxyLoginThrottle(t *testing.T) {
+	s := &Server{}
+
+	// The email is throttled across the agents.
+	for i := 0; i < proxyLoginMaxFailurePerEmail; i++ {
+		require.False(t, s.isProxyLoginThrottled(1, "a@example.com"))
+		s.recordProxyLoginFailure(1, "a@example.com")
+	}
+	require.True(t, s.isProxyLoginThrottled(1, "a@example.com"))
+	require.True(t, s.isProxyLoginThrottled(2, "a@example.com"))
+
+	// The agent is throttled across the emails.
+	for i := 0; i < proxyLoginMaxFailurePerAgent; i++ {
+		require.False(t, s.isProxyLoginThrottled(3, fmt.Sprintf("%d@example.com", i)))
+		s.recordProxyLoginFailure(3, fmt.Sprintf("%d@example.com", i))
+	}
+	require.True(t, s.isProxyLoginThrottled(3, "b@example.com"))
+	require.False(t, s.isProxyLoginThrottled(4, "b@example.com"))
+
+	// The failures expire with the window.
+	for _, failure := range s.proxyLoginFailureMap {
+		failure.since = failure.since.Add(-proxyLoginFailureWindow)
+	}
+	require.False(t, s.isProxyLoginThrottled(1, "a@example.com"))
+	require.False(t, s.isProxyLoginThrottled(3, "b@example.com"))
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerRecurringIssueRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/recurring-issue", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		recurringIssueList, err := s.store.FindRecurringIssue(ctx, &api.RecurringIssueFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch recurring issue list for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, recurringIssueList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal recurring issue list response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/project/:projectID/recurring-issue", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+		}
+		if project.RowStatus == api.Archived {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %q is archived", project.Name))
+		}
+
+		recurringIssueCreate := &api.RecurringIssueCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, recurringIssueCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create recurring issue request").SetInternal(err)
+		}
+		recurringIssueCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		recurringIssueCreate.ProjectID = projectID
+		recurringIssueCreate.Name = strings.TrimSpace(recurringIssueCreate.Name)
+		if recurringIssueCreate.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Recurring issue name must not be empty")
+		}
+		if err := api.ValidateRecurringIssueType(recurringIssueCreate.Type); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		nextRunTs, err := api.GetRecurringIssueNextRunTs(recurringIssueCreate.Schedule, time.Now())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		recurringIssueCreate.NextRunTs = nextRunTs
+
+		if err := s.validateRecurringIssueTemplate(ctx, &api.RecurringIssue{
+			CreatorID:     recurringIssueCreate.CreatorID,
+			ProjectID:     recurringIssueCreate.ProjectID,
+			Name:          recurringIssueCreate.Name,
+			Type:          recurringIssueCreate.Type,
+			Description:   recurringIssueCreate.Description,
+			AssigneeID:    recurringIssueCreate.AssigneeID,
+			CreateContext: recurringIssueCreate.CreateContext,
+		}); err != nil {
+			return err
+		}
+
+		recurringIssue, err := s.store.CreateRecurringIssue(ctx, recurringIssueCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Recurring issue %q already exists in project %q", recurringIssueCreate.Name, project.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create recurring issue").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, recurringIssue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create recurring issue response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/recurring-issue/:recurringIssueID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		recurringIssue, err := s.getRecurringIssueFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		recurringIssuePatch := &api.RecurringIssuePatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, recurringIssuePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch recurring issue request").SetInternal(err)
+		}
+		recurringIssuePatch.ID = recurringIssue.ID
+		recurringIssuePatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+
+		// Validate the template patched onto the existing one.
+		template := *recurringIssue
+		if v := recurringIssuePatch.Name; v != nil {
+			name := strings.TrimSpace(*v)
+			if name == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, "Recurring issue name must not be empty")
+			}
+			recurringIssuePatch.Name = &name
+			template.Name = name
+		}
+		if v := recurringIssuePatch.Description; v != nil {
+			template.Description = *v
+		}
+		if v := recurringIssuePatch.AssigneeID; v != nil {
+			template.AssigneeID = *v
+		}
+		if v := recurringIssuePatch.CreateContext; v != nil {
+			template.CreateContext = *v
+		}
+		if recurringIssuePatch.AssigneeID != nil || recurringIssuePatch.CreateContext != nil {
+			if err := s.validateRecurringIssueTemplate(ctx, &template); err != nil {
+				return err
+			}
+		}
+
+		// The missed runs are skipped when the recurring issue is restored, and the next run is recomputed from now.
+		restored := recurringIssuePatch.RowStatus != nil && api.RowStatus(*recurringIssuePatch.RowStatus) == api.Normal && recurringIssue.RowStatus == api.Archived
+		if recurringIssuePatch.Schedule != nil || restored {
+			schedule := recurringIssue.Schedule
+			if v := recurringIssuePatch.Schedule; v != nil {
+				schedule = strings.TrimSpace(*v)
+				recurringIssuePatch.Schedule = &schedule
+			}
+			nextRunTs, err := api.GetRecurringIssueNextRunTs(schedule, time.Now())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			recurringIssuePatch.NextRunTs = &nextRunTs
+		}
+
+		recurringIssue, err = s.store.PatchRecurringIssue(ctx, recurringIssuePatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Recurring issue %q already exists in the project", template.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch recurring issue ID: %v", recurringIssuePatch.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, recurringIssue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch recurring issue response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/project/:projectID/recurring-issue/:recurringIssueID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		recurringIssue, err := s.getRecurringIssueFromParam(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		recurringIssueDelete := &api.RecurringIssueDelete{
+			ID:        recurringIssue.ID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteRecurringIssue(ctx, recurringIssueDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete recurring issue ID: %v", recurringIssue.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// getRecurringIssueFromParam returns the recurring issue specified by the ":projectID" and ":recurringIssueID" params.
+// The returned error is an echo HTTP error.
+func (s *Server) getRecurringIssueFromParam(ctx context.Context, c echo.Context) (*api.RecurringIssue, error) {
+	projectID, err := strconv.Atoi(c.Param("projectID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+	}
+	recurringIssueID, err := strconv.Atoi(c.Param("recurringIssueID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Recurring issue ID is not a number: %s", c.Param("recurringIssueID"))).SetInternal(err)
+	}
+	recurringIssue, err := s.store.GetRecurringIssueByID(ctx, recurringIssueID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch recurring issue ID: %v", recurringIssueID)).SetInternal(err)
+	}
+	if recurringIssue == nil || recurringIssue.ProjectID != projectID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Recurring issue not found by ID %d and project ID %d", recurringIssueID, projectID))
+	}
+	return recurringIssue, nil
+}
+
+// validateRecurringIssueTemplate validates the issue could be instantiated from the template by creating it in the validate only mode.
+// The returned error is an echo HTTP error.
+func (s *Server) validateRecurringIssueTemplate(ctx context.Context, recurringIssue *api.RecurringIssue) error {
+	issueCreate := getRecurringIssueCreate(recurringIssue, time.Now())
+	issueCreate.ValidateOnly = true
+	if _, err := s.createIssue(ctx, issueCreate, recurringIssue.CreatorID); err != nil {
+		if httpErr, ok := err.(*echo.HTTPError); ok {
+			return httpErr
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid recurring issue template: %v", err)).SetInternal(err)
+	}
+	return nil
+}
+
+// getRecurringIssueCreate returns the issue instantiated from the recurring issue template for the run at runTime.
+func getRecurringIssueCreate(recurringIssue *api.RecurringIssue, runTime time.Time) *api.IssueCreate {
+	return &api.IssueCreate{
+		ProjectID:     recurringIssue.ProjectID,
+		Name:          fmt.Sprintf("%s - %s", recurringIssue.Name, runTime.UTC().Format("2006-01-02 15:04")),
+		Type:          recurringIssue.Type,
+		Description:   recurringIssue.Description,
+		AssigneeID:    recurringIssue.AssigneeID,
+		CreateContext: recurringIssue.CreateContext,
+	}
+}
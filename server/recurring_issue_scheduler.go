@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// The cron schedule has the minute granularity.
+	recurringIssueSchedulerInterval = time.Duration(1) * time.Minute
+)
+
+// NewRecurringIssueScheduler creates a recurring issue scheduler.
+func NewRecurringIssueScheduler(server *Server) *RecurringIssueScheduler {
+	return &RecurringIssueScheduler{
+		server: server,
+	}
+}
+
+// RecurringIssueScheduler instantiates the issues from the recurring issue templates on the cron schedule.
+type RecurringIssueScheduler struct {
+	server *Server
+}
+
+// Run will run the recurring issue scheduler.
+func (s *RecurringIssueScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(recurringIssueSchedulerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Recurring issue scheduler started and will run every %v", recurringIssueSchedulerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						log.Error("Recurring issue scheduler PANIC RECOVER", zap.Error(err))
+					}
+				}()
+				s.schedule(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (s *RecurringIssueScheduler) schedule(ctx context.Context, now time.Time) {
+	rowStatus := api.Normal
+	nowTs := now.Unix()
+	recurringIssueList, err := s.server.store.FindRecurringIssue(ctx, &api.RecurringIssueFind{
+		RowStatus:   &rowStatus,
+		DueBeforeTs: &nowTs,
+	})
+	if err != nil {
+		log.Error("Failed to find due recurring issue list", zap.Error(err))
+		return
+	}
+	for _, recurringIssue := range recurringIssueList {
+		if err := s.run(ctx, recurringIssue, now); err != nil {
+			log.Error("Failed to run recurring issue",
+				zap.Int("recurring_issue_id", recurringIssue.ID),
+				zap.String("recurring_issue_name", recurringIssue.Name),
+				zap.Error(err))
+		}
+	}
+}
+
+// run instantiates the issue of the due recurring issue.
+// The next run is advanced before creating the issue, so that a failed run is not retried every minute,
+// and the runs missed during the downtime are collapsed into one.
+func (s *RecurringIssueScheduler) run(ctx context.Context, recurringIssue *api.RecurringIssue, now time.Time) error {
+	runTime := time.Unix(recurringIssue.NextRunTs, 0)
+	nextRunTs, err := api.GetRecurringIssueNextRunTs(recurringIssue.Schedule, now)
+	if err != nil {
+		return err
+	}
+	if _, err := s.server.store.PatchRecurringIssue(ctx, &api.RecurringIssuePatch{
+		ID:        recurringIssue.ID,
+		UpdaterID: api.SystemBotID,
+		NextRunTs: &nextRunTs,
+	}); err != nil {
+		return fmt.Errorf("failed to advance the next run, error: %w", err)
+	}
+
+	project, err := s.server.store.GetProjectByID(ctx, recurringIssue.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to find project ID %d, error: %w", recurringIssue.ProjectID, err)
+	}
+	if project == nil || project.RowStatus == api.Archived {
+		log.Debug("Skip the recurring issue of the archived project",
+			zap.Int("recurring_issue_id", recurringIssue.ID),
+			zap.Int("project_id", recurringIssue.ProjectID))
+		return nil
+	}
+
+	// The run is skipped while the last issue is still open, e.g. waiting for the approval, so that the issues don't pile up.
+	if recurringIssue.LastIssueID > 0 {
+		lastIssue, err := s.server.store.GetIssueByID(ctx, recurringIssue.LastIssueID)
+		if err != nil {
+			return fmt.Errorf("failed to find the last issue ID %d, error: %w", recurringIssue.LastIssueID, err)
+		}
+		if lastIssue != nil && lastIssue.Status == api.IssueOpen {
+			log.Debug("Skip the recurring issue since the last issue is still open",
+				zap.Int("recurring_issue_id", recurringIssue.ID),
+				zap.Int("last_issue_id", lastIssue.ID))
+			return nil
+		}
+	}
+
+	issue, err := s.server.createIssue(ctx, getRecurringIssueCreate(recurringIssue, runTime), recurringIssue.CreatorID)
+	if err != nil {
+		return fmt.Errorf("failed to create issue, error: %w", err)
+	}
+	lastRunTs := now.Unix()
+	if _, err := s.server.store.PatchRecurringIssue(ctx, &api.RecurringIssuePatch{
+		ID:          recurringIssue.ID,
+		UpdaterID:   api.SystemBotID,
+		LastRunTs:   &lastRunTs,
+		LastIssueID: &issue.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to record the last run, error: %w", err)
+	}
+
+	if recurringIssue.AutoApprove {
+		for _, stage := range issue.Pipeline.StageList {
+			for _, task := range stage.TaskList {
+				if task.Status != api.TaskPendingApproval {
+					continue
+				}
+				if _, err := s.server.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
+					ID:        task.ID,
+					UpdaterID: recurringIssue.CreatorID,
+					Status:    api.TaskPending,
+				}); err != nil {
+					return fmt.Errorf("failed to approve task %q of issue ID %d, error: %w", task.Name, issue.ID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerRepositoryPushEventRoutes(g *echo.Group) {
+	// Lists the files added by the push events of the project, e.g. ?status=FAILED for the failed ones.
+	g.GET("/project/:projectID/push-event", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		find := &api.RepositoryPushEventFind{ProjectID: &projectID}
+		if statusStr := c.QueryParams().Get("status"); statusStr != "" {
+			status := api.RepositoryPushEventStatus(statusStr)
+			switch status {
+			case api.RepositoryPushEventDone, api.RepositoryPushEventIgnored, api.RepositoryPushEventFailed:
+			default:
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid push event status: %s", statusStr))
+			}
+			find.Status = &status
+		}
+		eventList, err := s.store.FindRepositoryPushEvent(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch push event list for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, eventList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal push event list response for project ID: %d", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Processes the file of the push event again with the current repository configuration,
+	// so the missed migration doesn't require pushing an artificial commit.
+	g.POST("/project/:projectID/push-event/:eventID/replay", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		eventID, err := strconv.Atoi(c.Param("eventID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Push event ID is not a number: %s", c.Param("eventID"))).SetInternal(err)
+		}
+
+		// Claim the event before checking its status, so the concurrent replays of the same event are rejected
+		// instead of creating the issue twice.
+		if _, ok := s.pushEventReplayInFlight.LoadOrStore(eventID, true); ok {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Push event %d is being replayed", eventID))
+		}
+		defer s.pushEventReplayInFlight.Delete(eventID)
+
+		event, err := s.store.GetRepositoryPushEventByID(ctx, eventID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch push event ID: %d", eventID)).SetInternal(err)
+		}
+		if event == nil || event.ProjectID != projectID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Push event not found with ID %d in project %d", eventID, projectID))
+		}
+		if event.Status == api.RepositoryPushEventDone {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Push event %d has already created the issue, replaying it would create a duplicate one", eventID))
+		}
+
+		repo, err := s.store.GetRepository(ctx, &api.RepositoryFind{ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch repository for project ID: %d", projectID)).SetInternal(err)
+		}
+		if repo == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %d isn't linked to a repository", projectID))
+		}
+		if repo.VCS.Type != event.PushEvent.VCSType {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Push event %d is from %s, but project %d is linked to a %s repository now", eventID, event.PushEvent.VCSType, projectID, repo.VCS.Type))
+		}
+
+		// Replays with the current base directory, which may have been fixed since the push.
+		pushEvent := event.PushEvent
+		pushEvent.BaseDirectory = repo.BaseDirectory
+		status, message, replayErr := s.createIssueFromPushEvent(ctx, repo, pushEvent, event.File, repo.WebhookEndpointID)
+		if replayErr != nil {
+			status, message = api.RepositoryPushEventFailed, replayErr.Error()
+		}
+		updatedEvent, err := s.store.PatchRepositoryPushEvent(ctx, &api.RepositoryPushEventPatch{
+			ID:        eventID,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+			Status:    status,
+			Message:   message,
+		})
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Push event %d has already created the issue", eventID)).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update push event ID: %d", eventID)).SetInternal(err)
+		}
+		if replayErr != nil {
+			return replayErr
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, updatedEvent); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal replay push event response: %d", eventID)).SetInternal(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestReplayRepositoryPushEvent(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	e := echo.New()
+	apiGroup := e.Group("/api")
+	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(getPrincipalIDContextKey(), api.SystemBotID)
+			return next(c)
+		}
+	})
+	s.registerRepositoryPushEventRoutes(apiGroup)
+
+	// The demo project 3003 is linked to the repository with the base directory "bytebase".
+	event, err := s.store.CreateRepositoryPushEvent(ctx, &api.RepositoryPushEventCreate{
+		ProjectID: 3003,
+		File:      "migration/prod/blog__202206010000__migrate__add_post.sql",
+		PushEvent: vcs.PushEvent{VCSType: vcs.GitLabSelfHost, BaseDirectory: "migration"},
+		Status:    api.RepositoryPushEventFailed,
+		Message:   "database not found",
+	})
+	require.NoError(t, err)
+	replay := func(eventID int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/project/3003/push-event/%d/replay", eventID), nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	requireEvent := func(status api.RepositoryPushEventStatus, replayCount int) {
+		found, err := s.store.GetRepositoryPushEventByID(ctx, event.ID)
+		require.NoError(t, err)
+		require.Equal(t, status, found.Status)
+		require.Equal(t, replayCount, found.ReplayCount)
+	}
+
+	// The replay uses the current base directory of the repository.
+	rec := replay(event.ID)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	replayed := &api.RepositoryPushEvent{}
+	require.NoError(t, jsonapi.UnmarshalPayload(rec.Body, replayed))
+	require.Equal(t, api.RepositoryPushEventIgnored, replayed.Status)
+	require.Equal(t, `not under base directory "bytebase"`, replayed.Message)
+	requireEvent(api.RepositoryPushEventIgnored, 1)
+
+	// The concurrent replay of the same event is rejected.
+	s.pushEventReplayInFlight.Store(event.ID, true)
+	rec = replay(event.ID)
+	require.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	s.pushEventReplayInFlight.Delete(event.ID)
+	requireEvent(api.RepositoryPushEventIgnored, 1)
+
+	// The event which has created the issue isn't processed again.
+	_, err = s.store.PatchRepositoryPushEvent(ctx, &api.RepositoryPushEventPatch{
+		ID:        event.ID,
+		UpdaterID: api.SystemBotID,
+		Status:    api.RepositoryPushEventDone,
+		Message:   "created issue 101",
+	})
+	require.NoError(t, err)
+	rec = replay(event.ID)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	requireEvent(api.RepositoryPushEventDone, 2)
+
+	rec = replay(event.ID + 1)
+	require.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+// requestIDPattern is the pattern of the request ID accepted from the client, other IDs are replaced with a generated one.
+var requestIDPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// requestIDMiddleware assigns each request an ID, which is returned in the X-Request-ID response header
+// and carried by the request context for the logs and the task runs.
+// The X-Request-ID request header is reused if supplied so that the ID can be traced from the upstream proxy.
+func requestIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.New().String()
+			c.Request().Header.Set(echo.HeaderXRequestID, requestID)
+		}
+		c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+		c.SetRequest(c.Request().WithContext(common.WithRequestID(c.Request().Context(), requestID)))
+		return next(c)
+	}
+}
+
+// httpErrorHandler responds the error with the error code and the request ID besides the message.
+// The error code is taken from the *common.Error wrapped by the error, or derived from the HTTP status code otherwise.
+// The HTTP status code of an internal server error is corrected if the wrapped *common.Error tells it's a user error.
+func httpErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	message := "Internal error."
+	code := common.Internal
+	var appErr *common.Error
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Code
+		message = fmt.Sprintf("%v", httpErr.Message)
+		code = httpStatusErrorCode(status)
+		if httpErr.Internal != nil && errors.As(httpErr.Internal, &appErr) && appErr.Code != common.Internal {
+			code = appErr.Code
+			if status == http.StatusInternalServerError {
+				status = errorCodeHTTPStatus(code)
+			}
+		}
+	} else if errors.As(err, &appErr) {
+		code = appErr.Code
+		status = errorCodeHTTPStatus(code)
+		message = common.ErrorMessage(err)
+	}
+
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+	if status >= http.StatusInternalServerError {
+		log.Error("Failed to serve the request",
+			zap.String("request_id", requestID),
+			zap.String("method", c.Request().Method),
+			zap.String("uri", c.Request().RequestURI),
+			zap.Int("status", status),
+			zap.Error(err),
+		)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, &api.ErrorResponse{
+			Message:   message,
+			Code:      code,
+			RequestID: requestID,
+		})
+	}
+	if err != nil {
+		log.Error("Failed to send the error response", zap.String("request_id", requestID), zap.Error(err))
+	}
+}
+
+// httpStatusErrorCode returns the error code of the HTTP status code.
+func httpStatusErrorCode(status int) common.Code {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return common.NotAuthorized
+	case http.StatusNotFound:
+		return common.NotFound
+	case http.StatusConflict:
+		return common.Conflict
+	case http.StatusNotImplemented:
+		return common.NotImplemented
+	}
+	if status < http.StatusInternalServerError {
+		return common.Invalid
+	}
+	return common.Internal
+}
+
+// errorCodeHTTPStatus returns the HTTP status code of the error code.
+func errorCodeHTTPStatus(code common.Code) int {
+	switch code {
+	case common.Ok:
+		return http.StatusOK
+	case common.NotAuthorized:
+		return http.StatusForbidden
+	case common.Invalid:
+		return http.StatusBadRequest
+	case common.NotFound:
+		return http.StatusNotFound
+	case common.Conflict:
+		return http.StatusConflict
+	case common.NotImplemented:
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   common.Code
+		wantMsg    string
+	}{
+		{
+			name:       "bad request",
+			err:        echo.NewHTTPError(http.StatusBadRequest, "Malformed request"),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   common.Invalid,
+			wantMsg:    "Malformed request",
+		},
+		{
+			name:       "internal error wrapping a conflict",
+			err:        echo.NewHTTPError(http.StatusInternalServerError, "Failed to update task").SetInternal(&common.Error{Code: common.Conflict, Err: errors.New("task is already running")}),
+			wantStatus: http.StatusConflict,
+			wantCode:   common.Conflict,
+			wantMsg:    "Failed to update task",
+		},
+		{
+			name:       "internal error",
+			err:        echo.NewHTTPError(http.StatusInternalServerError, "Failed to update task").SetInternal(errors.New("connection refused")),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   common.Internal,
+			wantMsg:    "Failed to update task",
+		},
+		{
+			name:       "application error",
+			err:        &common.Error{Code: common.NotFound, Err: errors.New("task not found")},
+			wantStatus: http.StatusNotFound,
+			wantCode:   common.NotFound,
+			wantMsg:    "task not found",
+		},
+		{
+			name:       "plain error",
+			err:        errors.New("disk full"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   common.Internal,
+			wantMsg:    "Internal error.",
+		},
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = httpErrorHandler
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/task", nil)
+			req.Header.Set(echo.HeaderXRequestID, "req-1")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			err := requestIDMiddleware(func(echo.Context) error {
+				return test.err
+			})(c)
+			e.HTTPErrorHandler(err, c)
+
+			require.Equal(t, test.wantStatus, rec.Code)
+			require.Equal(t, "req-1", rec.Header().Get(echo.HeaderXRequestID))
+			var resp api.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.Equal(t, api.ErrorResponse{Message: test.wantMsg, Code: test.wantCode, RequestID: "req-1"}, resp)
+		})
+	}
+}
+
+func TestRequestIDMiddlewareGeneratesID(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/task", nil)
+	req.Header.Set(echo.HeaderXRequestID, "invalid id with spaces")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var requestID string
+	err := requestIDMiddleware(func(c echo.Context) error {
+		requestID = common.RequestIDFromContext(c.Request().Context())
+		return nil
+	})(c)
+	require.NoError(t, err)
+	require.NotEqual(t, "invalid id with spaces", requestID)
+	require.Equal(t, requestID, rec.Header().Get(echo.HeaderXRequestID))
+}
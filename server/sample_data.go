@@ -0,0 +1,395 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/resources/postgres"
+)
+
+const (
+	// sampleProjectKey identifies the sample project, the generator is a no-op for the resources already created.
+	sampleProjectKey  = "SAMPLE"
+	sampleProjectName = "Sample Project"
+	// sampleDatabaseName is the database created on every sample instance.
+	sampleDatabaseName = "employee"
+)
+
+// sampleEnvironmentList is the environments of the sample instances, one sample instance is created for each of them.
+// The N-th sample instance listens on the sample database port + N.
+var sampleEnvironmentList = []string{"Test", "Prod"}
+
+// sampleMigration is a migration applied to the sample database to fake the migration history.
+type sampleMigration struct {
+	version     string
+	migrateType db.MigrationType
+	description string
+	statement   string
+}
+
+var sampleMigrationList = []sampleMigration{
+	{
+		version:     "20220501000000",
+		migrateType: db.Migrate,
+		description: "Create employee tables",
+		statement: `CREATE TABLE department (
+	dept_no TEXT PRIMARY KEY,
+	dept_name TEXT NOT NULL UNIQUE
+);
+CREATE TABLE employee (
+	emp_no INTEGER PRIMARY KEY,
+	birth_date DATE NOT NULL,
+	first_name TEXT NOT NULL,
+	last_name TEXT NOT NULL,
+	gender TEXT NOT NULL CHECK (gender IN ('M', 'F')),
+	hire_date DATE NOT NULL
+);
+CREATE TABLE dept_emp (
+	emp_no INTEGER NOT NULL REFERENCES employee (emp_no) ON DELETE CASCADE,
+	dept_no TEXT NOT NULL REFERENCES department (dept_no) ON DELETE CASCADE,
+	from_date DATE NOT NULL,
+	to_date DATE NOT NULL,
+	PRIMARY KEY (emp_no, dept_no)
+);`,
+	},
+	{
+		version:     "20220502000000",
+		migrateType: db.Migrate,
+		description: "Create salary table",
+		statement: `CREATE TABLE salary (
+	emp_no INTEGER NOT NULL REFERENCES employee (emp_no) ON DELETE CASCADE,
+	amount INTEGER NOT NULL,
+	from_date DATE NOT NULL,
+	to_date DATE NOT NULL,
+	PRIMARY KEY (emp_no, from_date)
+);`,
+	},
+	{
+		version:     "20220503000000",
+		migrateType: db.Data,
+		description: "Seed departments and employees",
+		statement: `INSERT INTO department (dept_no, dept_name) VALUES
+	('d001', 'Marketing'),
+	('d002', 'Finance'),
+	('d003', 'Human Resources'),
+	('d004', 'Production'),
+	('d005', 'Development');
+INSERT INTO employee (emp_no, birth_date, first_name, last_name, gender, hire_date) VALUES
+	(10001, '1953-09-02', 'Georgi', 'Facello', 'M', '1986-06-26'),
+	(10002, '1964-06-02', 'Bezalel', 'Simmel', 'F', '1985-11-21'),
+	(10003, '1959-12-03', 'Parto', 'Bamford', 'M', '1986-08-28'),
+	(10004, '1954-05-01', 'Chirstian', 'Koblick', 'M', '1986-12-01'),
+	(10005, '1955-01-21', 'Kyoichi', 'Maliniak', 'M', '1989-09-12');
+INSERT INTO dept_emp (emp_no, dept_no, from_date, to_date) VALUES
+	(10001, 'd005', '1986-06-26', '9999-01-01'),
+	(10002, 'd001', '1985-11-21', '9999-01-01'),
+	(10003, 'd004', '1986-08-28', '9999-01-01'),
+	(10004, 'd004', '1986-12-01', '9999-01-01'),
+	(10005, 'd003', '1989-09-12', '9999-01-01');
+INSERT INTO salary (emp_no, amount, from_date, to_date) VALUES
+	(10001, 88958, '2002-06-22', '9999-01-01'),
+	(10002, 72527, '2001-08-02', '9999-01-01'),
+	(10003, 43311, '2001-12-01', '9999-01-01'),
+	(10004, 74057, '2001-11-27', '9999-01-01'),
+	(10005, 94692, '2001-09-09', '9999-01-01');`,
+	},
+}
+
+// sampleIssue is an issue created in the sample project.
+type sampleIssue struct {
+	name        string
+	description string
+	statement   string
+	// environmentList is the environments of the databases to change.
+	environmentList []string
+	// cancel is whether to cancel the issue after creation.
+	cancel bool
+}
+
+// The sample issues end up in different states.
+// The change to the Test environment is rolled out automatically, so the first issue is done in a moment,
+// while the second one waits for the approval of the change to the Prod environment.
+var sampleIssueList = []sampleIssue{
+	{
+		name:            "Add index on employee hire date",
+		description:     "Speed up the headcount report grouping by the hire date.",
+		statement:       "CREATE INDEX idx_employee_hire_date ON employee (hire_date);",
+		environmentList: []string{"Test"},
+	},
+	{
+		name:            "Add department budget",
+		description:     "Track the yearly budget of each department.",
+		statement:       "ALTER TABLE department ADD COLUMN budget INTEGER NOT NULL DEFAULT 0;",
+		environmentList: []string{"Test", "Prod"},
+	},
+	{
+		name:            "Drop salary table",
+		description:     "Salaries are moved to the payroll system.",
+		statement:       "DROP TABLE salary;",
+		environmentList: []string{"Prod"},
+		cancel:          true,
+	},
+}
+
+func (s *Server) registerSampleDataRoutes(g *echo.Group) {
+	// The sample data lets the evaluators explore the features without wiring real databases.
+	g.POST("/sample-data", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if s.profile.Readonly {
+			return echo.NewHTTPError(http.StatusMethodNotAllowed, "Sample data cannot be generated in readonly mode")
+		}
+		project, err := s.generateSampleData(ctx, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate sample data").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, project); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sample project response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// generateSampleData provisions the sample instances, the sample project with the databases and the issues.
+// Only the missing resources are created, so it's safe to generate the sample data multiple times.
+// The issues are created on behalf of the owner, and skipped if ownerID is api.UnknownID,
+// e.g. the server is started with the sample data before anyone signs up.
+func (s *Server) generateSampleData(ctx context.Context, ownerID int) (*api.Project, error) {
+	if err := s.startSampleInstances(); err != nil {
+		return nil, err
+	}
+
+	if err := s.bootstrapProject(ctx, bootstrapProject{Key: sampleProjectKey, Name: sampleProjectName}); err != nil {
+		return nil, err
+	}
+	projectKey := sampleProjectKey
+	projectList, err := s.store.FindProject(ctx, &api.ProjectFind{Key: &projectKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sample project, error: %w", err)
+	}
+	if len(projectList) == 0 {
+		return nil, fmt.Errorf("sample project %q not found", sampleProjectKey)
+	}
+	project := projectList[0]
+
+	databaseMap := make(map[string]*api.Database)
+	for i, environmentName := range sampleEnvironmentList {
+		environment, err := s.bootstrapEnvironment(ctx, bootstrapEnvironment{Name: environmentName})
+		if err != nil {
+			return nil, err
+		}
+		instanceName := fmt.Sprintf("Sample %s", environmentName)
+		if err := s.bootstrapInstance(ctx, environment, bootstrapInstance{
+			Name:        instanceName,
+			Environment: environmentName,
+			Engine:      db.Postgres,
+			Host:        common.GetPostgresSocketDir(),
+			Port:        fmt.Sprintf("%d", s.profile.SampleDatabasePort+i),
+			Username:    s.profile.PgUser,
+		}); err != nil {
+			return nil, err
+		}
+		instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{Name: &instanceName})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find instance %q, error: %w", instanceName, err)
+		}
+		if len(instanceList) == 0 {
+			return nil, fmt.Errorf("instance %q not found", instanceName)
+		}
+		database, err := s.generateSampleDatabase(ctx, instanceList[0], project)
+		if err != nil {
+			return nil, err
+		}
+		databaseMap[environmentName] = database
+	}
+
+	if ownerID == api.UnknownID {
+		log.Info("Skipped generating the sample issues since there is no owner yet")
+		return project, nil
+	}
+	issueList, err := s.store.FindIssue(ctx, &api.IssueFind{ProjectID: &project.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sample issue list, error: %w", err)
+	}
+	if len(issueList) > 0 {
+		return project, nil
+	}
+	for _, issue := range sampleIssueList {
+		if err := s.generateSampleIssue(ctx, project, databaseMap, issue, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	log.Info("Generated sample data", zap.String("project", project.Key))
+	return project, nil
+}
+
+// generateSampleDatabase creates the sample database with the fake migration history on the sample instance.
+func (s *Server) generateSampleDatabase(ctx context.Context, instance *api.Instance, project *api.Project) (*api.Database, error) {
+	databaseName := sampleDatabaseName
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{InstanceID: &instance.ID, Name: &databaseName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find database %q on instance %q, error: %w", databaseName, instance.Name, err)
+	}
+	if database != nil {
+		return database, nil
+	}
+
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	if _, _, err := driver.ExecuteMigration(ctx, &db.MigrationInfo{
+		ReleaseVersion: s.profile.Version,
+		Version:        "20220430000000",
+		Namespace:      databaseName,
+		Database:       databaseName,
+		Environment:    instance.Environment.Name,
+		Source:         db.UI,
+		Type:           db.Baseline,
+		Description:    "Create database",
+		CreateDatabase: true,
+		Force:          true,
+	}, fmt.Sprintf("CREATE DATABASE %q", databaseName)); err != nil {
+		return nil, fmt.Errorf("failed to create database %q on instance %q, error: %w", databaseName, instance.Name, err)
+	}
+
+	databaseDriver, err := s.getAdminDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer databaseDriver.Close(ctx)
+	for _, migration := range sampleMigrationList {
+		if _, _, err := databaseDriver.ExecuteMigration(ctx, &db.MigrationInfo{
+			ReleaseVersion: s.profile.Version,
+			Version:        migration.version,
+			Namespace:      databaseName,
+			Database:       databaseName,
+			Environment:    instance.Environment.Name,
+			Source:         db.UI,
+			Type:           migration.migrateType,
+			Description:    migration.description,
+		}, migration.statement); err != nil {
+			return nil, fmt.Errorf("failed to apply migration %q to database %q on instance %q, error: %w", migration.version, databaseName, instance.Name, err)
+		}
+	}
+
+	database, err = s.store.CreateDatabase(ctx, &api.DatabaseCreate{
+		CreatorID:     api.SystemBotID,
+		ProjectID:     project.ID,
+		InstanceID:    instance.ID,
+		EnvironmentID: instance.EnvironmentID,
+		Name:          databaseName,
+		CharacterSet:  "UTF8",
+		Collation:     "en_US.UTF-8",
+		SchemaVersion: sampleMigrationList[len(sampleMigrationList)-1].version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database %q on instance %q, error: %w", databaseName, instance.Name, err)
+	}
+	// Same as creating the database from the issue, the schema syncer will catch up if the sync fails.
+	if err := s.syncDatabaseSchema(ctx, instance, databaseName); err != nil {
+		log.Warn("Failed to sync sample database schema",
+			zap.String("instance", instance.Name),
+			zap.String("database", databaseName),
+			zap.Error(err))
+	}
+	return database, nil
+}
+
+func (s *Server) generateSampleIssue(ctx context.Context, project *api.Project, databaseMap map[string]*api.Database, issue sampleIssue, ownerID int) error {
+	var detailList []*api.UpdateSchemaDetail
+	for _, environmentName := range issue.environmentList {
+		database, ok := databaseMap[environmentName]
+		if !ok {
+			return fmt.Errorf("sample database in environment %q not found", environmentName)
+		}
+		detailList = append(detailList, &api.UpdateSchemaDetail{
+			DatabaseID: database.ID,
+			Statement:  issue.statement,
+		})
+	}
+	createContext, err := json.Marshal(&api.UpdateSchemaContext{
+		MigrationType: db.Migrate,
+		DetailList:    detailList,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal sample issue create context, error: %w", err)
+	}
+	created, err := s.createIssue(ctx, &api.IssueCreate{
+		ProjectID:     project.ID,
+		Name:          issue.name,
+		Type:          api.IssueDatabaseSchemaUpdate,
+		Description:   issue.description,
+		AssigneeID:    ownerID,
+		CreateContext: string(createContext),
+	}, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to create sample issue %q, error: %w", issue.name, err)
+	}
+	if issue.cancel {
+		if _, err := s.changeIssueStatus(ctx, created, api.IssueCanceled, ownerID, "Canceled in favor of the payroll system migration."); err != nil {
+			return fmt.Errorf("failed to cancel sample issue %q, error: %w", issue.name, err)
+		}
+	}
+	return nil
+}
+
+// startSampleInstances starts the sample Postgres servers from the embedded Postgres binary if not started yet.
+func (s *Server) startSampleInstances() error {
+	s.samplePgMu.Lock()
+	defer s.samplePgMu.Unlock()
+	if len(s.samplePgInstanceList) > 0 {
+		return nil
+	}
+
+	resourceDir := s.profile.ResourceDir
+	for i, environmentName := range sampleEnvironmentList {
+		pgInstance, err := postgres.Install(resourceDir, getSampleDataDir(s.profile.DataDir, environmentName), s.profile.PgUser)
+		if err != nil {
+			return fmt.Errorf("failed to install sample instance for environment %q, error: %w", environmentName, err)
+		}
+		if err := pgInstance.Start(s.profile.SampleDatabasePort+i, os.Stderr, os.Stderr); err != nil {
+			return fmt.Errorf("failed to start sample instance for environment %q, error: %w", environmentName, err)
+		}
+		s.samplePgInstanceList = append(s.samplePgInstanceList, pgInstance)
+	}
+	return nil
+}
+
+// stopSampleInstances stops the started sample Postgres servers.
+func (s *Server) stopSampleInstances() {
+	s.samplePgMu.Lock()
+	defer s.samplePgMu.Unlock()
+	for _, pgInstance := range s.samplePgInstanceList {
+		if err := pgInstance.Stop(os.Stdout, os.Stderr); err != nil {
+			log.Error("Failed to stop sample instance", zap.Error(err))
+		}
+	}
+	s.samplePgInstanceList = nil
+}
+
+// hasSampleData returns whether the sample data has been generated before, in which case
+// the sample instances should be started with the server.
+func hasSampleData(dataDir string) bool {
+	_, err := os.Stat(path.Join(dataDir, "pgdata-sample"))
+	return err == nil
+}
+
+func getSampleDataDir(dataDir string, environmentName string) string {
+	return path.Join(dataDir, "pgdata-sample", strings.ToLower(environmentName))
+}
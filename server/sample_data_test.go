@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
+)
+
+// testLicenseService loads the license allowing more instances than the demo data has.
+type testLicenseService struct{}
+
+func (*testLicenseService) StoreLicense(_ *enterpriseAPI.SubscriptionPatch) error {
+	return nil
+}
+
+func (*testLicenseService) LoadLicense() (*enterpriseAPI.License, error) {
+	return &enterpriseAPI.License{InstanceCount: 100, Plan: api.TEAM}, nil
+}
+
+func TestGenerateSampleDataTwice(t *testing.T) {
+	s := newTestServer(t)
+	s.LicenseService = &testLicenseService{}
+	s.profile.ResourceDir = t.TempDir()
+	s.profile.DataDir = t.TempDir()
+	s.profile.PgUser = "root"
+	s.profile.SampleDatabasePort = testPgPort + 10
+	t.Cleanup(s.stopSampleInstances)
+	ctx := context.Background()
+	// The migrations dump the schema with the pg_dump of the embedded Postgres, which is installed along with the sample instances.
+	require.NoError(t, s.startSampleInstances())
+	s.pgInstanceDir = s.samplePgInstanceList[0].BaseDir
+
+	// The issues are skipped before anyone signs up, so only the instances, the project and the databases are provisioned.
+	project, err := s.generateSampleData(ctx, api.UnknownID)
+	require.NoError(t, err)
+	require.Equal(t, sampleProjectKey, project.Key)
+	databaseList := findSampleDatabaseList(ctx, t, s, project.ID)
+	require.Len(t, databaseList, len(sampleEnvironmentList))
+	for _, database := range databaseList {
+		require.Equal(t, sampleDatabaseName, database.Name)
+		require.Equal(t, sampleMigrationList[len(sampleMigrationList)-1].version, database.SchemaVersion)
+	}
+
+	// Generating again is a no-op for the provisioned resources, even after the sample instances are restarted.
+	s.stopSampleInstances()
+	again, err := s.generateSampleData(ctx, api.UnknownID)
+	require.NoError(t, err)
+	require.Equal(t, project.ID, again.ID)
+	againList := findSampleDatabaseList(ctx, t, s, project.ID)
+	require.Len(t, againList, len(databaseList))
+	for i, database := range againList {
+		require.Equal(t, databaseList[i].ID, database.ID)
+	}
+	for _, environmentName := range sampleEnvironmentList {
+		instanceName := "Sample " + environmentName
+		instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{Name: &instanceName})
+		require.NoError(t, err)
+		require.Len(t, instanceList, 1)
+	}
+	projectKey := sampleProjectKey
+	projectList, err := s.store.FindProject(ctx, &api.ProjectFind{Key: &projectKey})
+	require.NoError(t, err)
+	require.Len(t, projectList, 1)
+}
+
+func findSampleDatabaseList(ctx context.Context, t *testing.T, s *Server, projectID int) []*api.Database {
+	databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{ProjectID: &projectID})
+	require.NoError(t, err)
+	return databaseList
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/eventbus"
+)
+
+// publishSchemaChangeEvent publishes the schema change event after the migration completes.
+// The event is sent to the project webhooks subscribing the schema change activity, and to the event sink if configured.
+// It never fails the migration, the errors are only logged.
+func (s *Server) publishSchemaChangeEvent(ctx context.Context, task *api.Task, issue *api.Issue, project *api.Project, mi *db.MigrationInfo, migrationID int64) {
+	event, err := s.buildSchemaChangeEvent(ctx, task, issue, project, mi, migrationID)
+	if err != nil {
+		log.Warn("Failed to build schema change event",
+			zap.Int("task_id", task.ID),
+			zap.String("database", task.Database.Name),
+			zap.Error(err))
+		return
+	}
+
+	payload, err := json.Marshal(api.ActivityDatabaseSchemaChangePayload{
+		TaskID:             task.ID,
+		DatabaseID:         event.DatabaseID,
+		MigrationHistoryID: migrationID,
+		MigrationType:      mi.Type,
+		Version:            mi.Version,
+		DatabaseName:       event.DatabaseName,
+	})
+	if err != nil {
+		log.Error("Failed to marshal schema change activity", zap.Int("task_id", task.ID), zap.Error(err))
+		return
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   task.UpdaterID,
+		ContainerID: project.ID,
+		Type:        api.ActivityDatabaseSchemaChange,
+		Level:       api.ActivityInfo,
+		Payload:     string(payload),
+		Comment:     fmt.Sprintf("Applied migration version %s to database %q.", mi.Version, event.DatabaseName),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{issue: issue, schemaChange: event}); err != nil {
+		log.Error("Failed to create schema change activity", zap.Int("task_id", task.ID), zap.Error(err))
+	}
+
+	if s.profile.EventSinkURL == "" {
+		return
+	}
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Error("Failed to marshal schema change event", zap.Int("task_id", task.ID), zap.Error(err))
+		return
+	}
+	// Call the event sink in Go routine to avoid blocking the task executor.
+	go func() {
+		if err := eventbus.Publish(s.profile.EventSinkURL, eventJSON); err != nil {
+			// The event sink might be unavailable which is out of our code control, so we just emit a warning
+			log.Warn("Failed to publish schema change event to the event sink",
+				zap.String("database", event.DatabaseName),
+				zap.String("version", event.Version),
+				zap.Error(err))
+		}
+	}()
+}
+
+// buildSchemaChangeEvent builds the schema change event with the statement and the schema diff from the migration history.
+func (s *Server) buildSchemaChangeEvent(ctx context.Context, task *api.Task, issue *api.Issue, project *api.Project, mi *db.MigrationInfo, migrationID int64) (*api.SchemaChangeEvent, error) {
+	event := &api.SchemaChangeEvent{
+		Type:               api.ActivityDatabaseSchemaChange,
+		CreatedTs:          time.Now().Unix(),
+		ProjectID:          project.ID,
+		ProjectName:        project.Name,
+		InstanceID:         task.Instance.ID,
+		InstanceName:       task.Instance.Name,
+		DatabaseID:         task.Database.ID,
+		DatabaseName:       task.Database.Name,
+		TaskID:             task.ID,
+		MigrationHistoryID: migrationID,
+		MigrationType:      mi.Type,
+		Version:            mi.Version,
+	}
+	if task.Instance.Environment != nil {
+		event.EnvironmentName = task.Instance.Environment.Name
+	}
+	if issue != nil {
+		event.IssueID = issue.ID
+	}
+
+	driver, err := s.getAdminDatabaseDriver(ctx, task.Instance, "" /* databaseName */)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	id := int(migrationID)
+	historyList, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{ID: &id, Database: &mi.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	if len(historyList) == 0 {
+		return nil, fmt.Errorf("migration history ID %d not found for database %q", migrationID, mi.Namespace)
+	}
+	history := historyList[0]
+	event.Statement = history.Statement
+
+	diff, err := diffSchema(task.Instance.Engine, history.SchemaPrev, history.Schema)
+	if err != nil {
+		log.Warn("Failed to diff schema for schema change event",
+			zap.String("database", task.Database.Name),
+			zap.Int64("migration_history_id", migrationID),
+			zap.Error(err))
+	} else {
+		event.Diff = diff
+	}
+	return event, nil
+}
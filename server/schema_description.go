@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func (s *Server) registerSchemaDescriptionRoutes(g *echo.Group) {
+	g.GET("/database/:id/schema-description", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		descriptionList, err := s.store.FindSchemaDescription(ctx, &api.SchemaDescriptionFind{DatabaseID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema description list for database id: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, descriptionList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema description list response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/database/:id/schema-description", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		upsert := &api.SchemaDescriptionUpsert{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed set schema description request").SetInternal(err)
+		}
+		upsert.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+		upsert.DatabaseID = id
+
+		// The description can only be set on the synced tables and columns.
+		table, err := s.store.GetTable(ctx, &api.TableFind{DatabaseID: &id, Name: &upsert.TableName})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table for database id: %d, table name: %s", id, upsert.TableName)).SetInternal(err)
+		}
+		if table == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("table %q not found from database %v", upsert.TableName, id))
+		}
+		if upsert.ColumnName != "" {
+			columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &id, TableID: &table.ID})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch column list for database id: %d, table name: %s", id, upsert.TableName)).SetInternal(err)
+			}
+			found := false
+			for _, column := range columnList {
+				if column.Name == upsert.ColumnName {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("column %q not found from table %q", upsert.ColumnName, upsert.TableName))
+			}
+		}
+
+		if strings.TrimSpace(upsert.Description) == "" {
+			if err := s.store.DeleteSchemaDescription(ctx, &api.SchemaDescriptionDelete{
+				DatabaseID: id,
+				TableName:  upsert.TableName,
+				ColumnName: upsert.ColumnName,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete schema description for database id: %d, table name: %s", id, upsert.TableName)).SetInternal(err)
+			}
+			c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+			c.Response().WriteHeader(http.StatusOK)
+			return nil
+		}
+
+		description, err := s.store.UpsertSchemaDescription(ctx, upsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set schema description for database id: %d, table name: %s", id, upsert.TableName)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, description); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal set schema description response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Create an issue to write the schema descriptions differing from the comments back to the database as the comments.
+	g.POST("/database/:id/schema-description/issue", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		issueCreate := &api.SchemaDescriptionIssueCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, issueCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create schema description issue request").SetInternal(err)
+		}
+		if issueCreate.AssigneeID == api.UnknownID {
+			issueCreate.AssigneeID = api.SystemBotID
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		if database.Project.TenantMode == api.TenantModeTenant {
+			return echo.NewHTTPError(http.StatusBadRequest, "Schema descriptions can't be applied to a database in the tenant mode project")
+		}
+
+		statement, err := s.getSchemaDescriptionStatement(ctx, database)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if statement == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The schema descriptions of database %q are the same as the comments", database.Name))
+		}
+
+		createContext, err := json.Marshal(&api.UpdateSchemaContext{
+			MigrationType: db.Migrate,
+			DetailList: []*api.UpdateSchemaDetail{
+				{
+					DatabaseID: database.ID,
+					Statement:  statement,
+				},
+			},
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal update schema context").SetInternal(err)
+		}
+		issue, err := s.createIssue(ctx, &api.IssueCreate{
+			ProjectID:     database.ProjectID,
+			Name:          fmt.Sprintf("Apply schema descriptions to database %q", database.Name),
+			Type:          api.IssueDatabaseSchemaUpdate,
+			Description:   "Write the schema descriptions maintained in Bytebase back to the database as the comments.",
+			AssigneeID:    issueCreate.AssigneeID,
+			CreateContext: string(createContext),
+		}, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, issue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal create schema description issue response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// applySchemaDescription sets the descriptions of the tables and their columns in the list.
+func (s *Server) applySchemaDescription(ctx context.Context, databaseID int, tableList []*api.Table) error {
+	descriptionList, err := s.store.FindSchemaDescription(ctx, &api.SchemaDescriptionFind{DatabaseID: &databaseID})
+	if err != nil {
+		return err
+	}
+	mergeSchemaDescription(tableList, descriptionList)
+	return nil
+}
+
+// mergeSchemaDescription sets the description of the tables and the columns to the logical description if present,
+// otherwise the native comment.
+func mergeSchemaDescription(tableList []*api.Table, descriptionList []*api.SchemaDescription) {
+	descriptionMap := make(map[string]string)
+	for _, description := range descriptionList {
+		descriptionMap[getSchemaDescriptionKey(description.TableName, description.ColumnName)] = description.Description
+	}
+	for _, table := range tableList {
+		table.Description = table.Comment
+		if v, ok := descriptionMap[getSchemaDescriptionKey(table.Name, "")]; ok {
+			table.Description = v
+		}
+		for _, column := range table.ColumnList {
+			column.Description = column.Comment
+			if v, ok := descriptionMap[getSchemaDescriptionKey(table.Name, column.Name)]; ok {
+				column.Description = v
+			}
+		}
+	}
+}
+
+// getDescription returns the description if present, otherwise the comment.
+// The description is empty if the table or the column isn't merged with the schema descriptions.
+func getDescription(description, comment string) string {
+	if description != "" {
+		return description
+	}
+	return comment
+}
+
+func getSchemaDescriptionKey(tableName, columnName string) string {
+	return fmt.Sprintf("%s/%s", tableName, columnName)
+}
+
+func (s *Server) getSchemaDescriptionStatement(ctx context.Context, database *api.Database) (string, error) {
+	tableList, err := s.store.FindTable(ctx, &api.TableFind{DatabaseID: &database.ID})
+	if err != nil {
+		return "", err
+	}
+	for _, table := range tableList {
+		columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &database.ID, TableID: &table.ID})
+		if err != nil {
+			return "", err
+		}
+		table.ColumnList = columnList
+	}
+	descriptionList, err := s.store.FindSchemaDescription(ctx, &api.SchemaDescriptionFind{DatabaseID: &database.ID})
+	if err != nil {
+		return "", err
+	}
+	return generateSchemaDescriptionStatement(database.Instance.Engine, tableList, descriptionList)
+}
+
+// generateSchemaDescriptionStatement generates the statement setting the comments to the descriptions
+// that differ from the comments.
+func generateSchemaDescriptionStatement(dbType db.Type, tableList []*api.Table, descriptionList []*api.SchemaDescription) (string, error) {
+	if dbType != db.Postgres && dbType != db.MySQL && dbType != db.TiDB && dbType != db.MariaDB {
+		return "", fmt.Errorf("applying schema descriptions is not supported for %s", dbType)
+	}
+
+	tableMap := make(map[string]*api.Table)
+	for _, table := range tableList {
+		tableMap[table.Name] = table
+	}
+	var statementList []string
+	hasStatement := false
+	for _, description := range descriptionList {
+		table, ok := tableMap[description.TableName]
+		if !ok {
+			continue
+		}
+		if description.ColumnName == "" {
+			if table.Comment == description.Description {
+				continue
+			}
+			switch dbType {
+			case db.Postgres:
+				statementList = append(statementList, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", quotePostgresTableName(table.Name), quoteSQLString(dbType, description.Description)))
+			default:
+				statementList = append(statementList, fmt.Sprintf("ALTER TABLE `%s` COMMENT = %s;", table.Name, quoteSQLString(dbType, description.Description)))
+			}
+			hasStatement = true
+			continue
+		}
+
+		for _, column := range table.ColumnList {
+			if column.Name != description.ColumnName || column.Comment == description.Description {
+				continue
+			}
+			switch dbType {
+			case db.Postgres:
+				statementList = append(statementList, fmt.Sprintf("COMMENT ON COLUMN %s.\"%s\" IS %s;", quotePostgresTableName(table.Name), column.Name, quoteSQLString(dbType, description.Description)))
+				hasStatement = true
+			default:
+				// MySQL can only change the column comment with the full column definition, and the synced metadata
+				// doesn't contain all of it, e.g. AUTO_INCREMENT and ON UPDATE, so we leave it to the user.
+				statementList = append(statementList, fmt.Sprintf("-- Skipped the comment of column `%s`.`%s`, please change it with the full column definition.", table.Name, column.Name))
+			}
+		}
+	}
+	if !hasStatement {
+		return "", nil
+	}
+	return strings.Join(statementList, "\n"), nil
+}
+
+// quotePostgresTableName quotes the table name in the format of "schema.table".
+func quotePostgresTableName(name string) string {
+	if i := strings.Index(name, "."); i >= 0 {
+		return fmt.Sprintf("\"%s\".\"%s\"", name[:i], name[i+1:])
+	}
+	return fmt.Sprintf("\"%s\"", name)
+}
+
+// quoteSQLString quotes the string as a string literal of the engine.
+func quoteSQLString(dbType db.Type, s string) string {
+	switch dbType {
+	case db.MySQL, db.TiDB, db.MariaDB, db.ClickHouse, db.Snowflake:
+		// The backslash is the escape character in the string literals of these engines by default.
+		s = strings.ReplaceAll(s, "\\", "\\\\")
+	}
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSchemaDescription(t *testing.T) {
+	tableList := []*api.Table{
+		{
+			Name:    "public.orders",
+			Comment: "native",
+			ColumnList: []*api.Column{
+				{Name: "id", Comment: "id comment"},
+				{Name: "amount"},
+			},
+		},
+	}
+	descriptionList := []*api.SchemaDescription{
+		{TableName: "public.orders", ColumnName: "amount", Description: "in cents"},
+		{TableName: "public.users", Description: "stale"},
+	}
+
+	mergeSchemaDescription(tableList, descriptionList)
+	require.Equal(t, "native", tableList[0].Description)
+	require.Equal(t, "id comment", tableList[0].ColumnList[0].Description)
+	require.Equal(t, "in cents", tableList[0].ColumnList[1].Description)
+}
+
+func TestGenerateSchemaDescriptionStatement(t *testing.T) {
+	tests := []struct {
+		dbType          db.Type
+		tableList       []*api.Table
+		descriptionList []*api.SchemaDescription
+		want            string
+	}{
+		{
+			dbType: db.Postgres,
+			tableList: []*api.Table{
+				{
+					Name:    "public.orders",
+					Comment: "same",
+					ColumnList: []*api.Column{
+						{Name: "amount"},
+					},
+				},
+			},
+			descriptionList: []*api.SchemaDescription{
+				{TableName: "public.orders", Description: "same"},
+				{TableName: "public.orders", ColumnName: "amount", Description: "customer's amount"},
+				{TableName: "public.users", Description: "dropped table"},
+			},
+			want: `COMMENT ON COLUMN "public"."orders"."amount" IS 'customer''s amount';`,
+		},
+		{
+			dbType: db.MySQL,
+			tableList: []*api.Table{
+				{
+					Name: "orders",
+					ColumnList: []*api.Column{
+						{Name: "amount"},
+					},
+				},
+			},
+			descriptionList: []*api.SchemaDescription{
+				{TableName: "orders", Description: `a\b`},
+				{TableName: "orders", ColumnName: "amount", Description: "in cents"},
+			},
+			want: "ALTER TABLE `orders` COMMENT = 'a\\\\b';\n-- Skipped the comment of column `orders`.`amount`, please change it with the full column definition.",
+		},
+		{
+			// The skipped column comment alone isn't worth an issue.
+			dbType: db.MySQL,
+			tableList: []*api.Table{
+				{
+					Name: "orders",
+					ColumnList: []*api.Column{
+						{Name: "amount"},
+					},
+				},
+			},
+			descriptionList: []*api.SchemaDescription{
+				{TableName: "orders", ColumnName: "amount", Description: "in cents"},
+			},
+			want: "",
+		},
+	}
+
+	for _, test := range tests {
+		statement, err := generateSchemaDescriptionStatement(test.dbType, test.tableList, test.descriptionList)
+		require.NoError(t, err)
+		require.Equal(t, test.want, statement)
+	}
+
+	_, err := generateSchemaDescriptionStatement(db.ClickHouse, nil, nil)
+	require.Error(t, err)
+}
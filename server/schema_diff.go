@@ -0,0 +1,411 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+const (
+	schemaObjectTypeConstraint = "CONSTRAINT"
+	schemaObjectTypeStatement  = "STATEMENT"
+	// schemaObjectTypePartition is the object attaching a table or an index partition to its parent, named by the partition.
+	schemaObjectTypePartition = "PARTITION"
+)
+
+var (
+	// createObjectRegexp matches the type and the name of the object created by the statement, e.g.
+	// "CREATE TABLE `t` (", "CREATE UNIQUE INDEX idx ON", "CREATE DEFINER=`root`@`%` PROCEDURE `p`(".
+	createObjectRegexp = regexp.MustCompile("(?i)^CREATE\\s+(?:[^\\n]*?\\s)?(TABLE|VIEW|INDEX|SEQUENCE|FUNCTION|PROCEDURE|TRIGGER|EVENT|TYPE|EXTENSION|SCHEMA)\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?(?:CONCURRENTLY\\s+)?((?:\"[^\"]+\"|`[^`]+`|[^\\s(\"`])+)")
+	// addConstraintRegexp matches the table and the constraint name of the pg_dump constraint statement, e.g.
+	// "ALTER TABLE ONLY public.t ADD CONSTRAINT t_pkey PRIMARY KEY (id)".
+	addConstraintRegexp = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:ONLY\s+)?(\S+)\s+ADD\s+CONSTRAINT\s+(\S+)`)
+	// attachPartitionRegexp matches the type, the parent and the partition of the pg_dump statement attaching the partition, e.g.
+	// "ALTER TABLE ONLY public.t ATTACH PARTITION public.t_2022 FOR VALUES ..." and "ALTER INDEX public.t_idx ATTACH PARTITION public.t_2022_idx".
+	attachPartitionRegexp = regexp.MustCompile(`(?i)^ALTER\s+(TABLE|INDEX)\s+(?:ONLY\s+)?(\S+)\s+ATTACH\s+PARTITION\s+([^\s;]+)`)
+	// partitionOfRegexp matches the parent of the partition created by the pg_dump before Postgres 12, e.g.
+	// "CREATE TABLE public.t_2022 PARTITION OF public.t FOR VALUES ...".
+	partitionOfRegexp = regexp.MustCompile(`(?i)^CREATE\s+TABLE\s+\S+\s+PARTITION\s+OF\s+(\S+)`)
+	// tableConstraintKeywords are the leading keywords of the non-column definitions in the CREATE TABLE statement.
+	tableConstraintKeywords = map[string]bool{
+		"PRIMARY":    true,
+		"KEY":        true,
+		"UNIQUE":     true,
+		"CONSTRAINT": true,
+		"INDEX":      true,
+		"FOREIGN":    true,
+		"CHECK":      true,
+		"FULLTEXT":   true,
+		"SPATIAL":    true,
+		"EXCLUDE":    true,
+		"LIKE":       true,
+	}
+)
+
+// schemaObject is a top level object defined by a statement in the schema dump.
+type schemaObject struct {
+	typ       string
+	name      string
+	statement string
+	// parent is the partitioned table or index of the partition.
+	parent string
+}
+
+func (o *schemaObject) key() string {
+	return fmt.Sprintf("%s/%s", o.typ, o.name)
+}
+
+// diffSchema computes the structural diff from the before schema dump to the after one.
+// The objects are in the order they appear in the after schema, followed by the dropped objects.
+func diffSchema(dbType db.Type, before, after string) (*api.SchemaDiff, error) {
+	beforeList, err := parseSchemaObjectList(dbType, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the schema before the change, error: %w", err)
+	}
+	afterList, err := parseSchemaObjectList(dbType, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the schema after the change, error: %w", err)
+	}
+
+	beforeMap := make(map[string]*schemaObject)
+	for _, object := range beforeList {
+		beforeMap[object.key()] = object
+	}
+	afterMap := make(map[string]*schemaObject)
+	for _, object := range afterList {
+		afterMap[object.key()] = object
+	}
+
+	diff := &api.SchemaDiff{ObjectList: []*api.SchemaObjectDiff{}}
+	for _, object := range afterList {
+		beforeObject, ok := beforeMap[object.key()]
+		if !ok {
+			diff.ObjectList = append(diff.ObjectList, &api.SchemaObjectDiff{
+				Type:       object.typ,
+				Name:       object.name,
+				Action:     api.SchemaDiffActionAdd,
+				After:      object.statement,
+				ParentName: object.parent,
+			})
+			continue
+		}
+		if normalizeStatement(beforeObject.statement) == normalizeStatement(object.statement) {
+			continue
+		}
+		objectDiff := &api.SchemaObjectDiff{
+			Type:       object.typ,
+			Name:       object.name,
+			Action:     api.SchemaDiffActionModify,
+			Before:     beforeObject.statement,
+			After:      object.statement,
+			ParentName: object.parent,
+		}
+		if strings.EqualFold(object.typ, "TABLE") {
+			objectDiff.ColumnList = diffTableColumn(beforeObject.statement, object.statement)
+		}
+		diff.ObjectList = append(diff.ObjectList, objectDiff)
+	}
+	for _, object := range beforeList {
+		if _, ok := afterMap[object.key()]; !ok {
+			diff.ObjectList = append(diff.ObjectList, &api.SchemaObjectDiff{
+				Type:       object.typ,
+				Name:       object.name,
+				Action:     api.SchemaDiffActionDrop,
+				Before:     object.statement,
+				ParentName: object.parent,
+			})
+		}
+	}
+	diff.ObjectList = omitInheritedPartitionDiff(diff.ObjectList)
+	return diff, nil
+}
+
+// omitInheritedPartitionDiff omits the changes of the partitions inherited from the parent, e.g. the column added to the partitioned table
+// is added to every partition, so the migration against the partitioned table doesn't show up on every partition.
+func omitInheritedPartitionDiff(objectList []*api.SchemaObjectDiff) []*api.SchemaObjectDiff {
+	objectMap := make(map[string]*api.SchemaObjectDiff)
+	for _, object := range objectList {
+		objectMap[fmt.Sprintf("%s/%s", object.Type, object.Name)] = object
+	}
+
+	var result []*api.SchemaObjectDiff
+	for _, object := range objectList {
+		if object.ParentName != "" && isInheritedPartitionDiff(object, objectMap) {
+			continue
+		}
+		result = append(result, object)
+	}
+	return result
+}
+
+// isInheritedPartitionDiff returns whether the change of the partition follows the change of its parent.
+func isInheritedPartitionDiff(object *api.SchemaObjectDiff, objectMap map[string]*api.SchemaObjectDiff) bool {
+	switch object.Type {
+	case "TABLE":
+		// The partitions are created explicitly, but dropped and altered with the partitioned table.
+		parent, ok := objectMap[fmt.Sprintf("TABLE/%s", object.ParentName)]
+		if !ok || parent.Action != object.Action {
+			return false
+		}
+		return object.Action == api.SchemaDiffActionDrop ||
+			(object.Action == api.SchemaDiffActionModify && isColumnDiffEqual(object.ColumnList, parent.ColumnList))
+	case "INDEX":
+		// The index created on the partitioned table is created and attached on every partition.
+		parent, ok := objectMap[fmt.Sprintf("INDEX/%s", object.ParentName)]
+		return ok && parent.Action == object.Action
+	case schemaObjectTypePartition:
+		if parent, ok := objectMap[fmt.Sprintf("INDEX/%s", object.ParentName)]; ok && parent.Action == object.Action {
+			return true
+		}
+		parent, ok := objectMap[fmt.Sprintf("TABLE/%s", object.ParentName)]
+		return ok && parent.Action == api.SchemaDiffActionDrop && object.Action == api.SchemaDiffActionDrop
+	}
+	return false
+}
+
+func isColumnDiffEqual(a, b []*api.SchemaColumnDiff) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name ||
+			a[i].Action != b[i].Action ||
+			normalizeStatement(a[i].Before) != normalizeStatement(b[i].Before) ||
+			normalizeStatement(a[i].After) != normalizeStatement(b[i].After) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSchemaObjectList parses the schema dump into the object list.
+func parseSchemaObjectList(dbType db.Type, schema string) ([]*schemaObject, error) {
+	statementList, err := splitSchemaDump(dbType, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var objectList []*schemaObject
+	keySet := make(map[string]bool)
+	// parentMap maps the key of the partition to its parent.
+	parentMap := make(map[string]string)
+	for _, statement := range statementList {
+		statement = trimStatementComment(statement)
+		if statement == "" {
+			continue
+		}
+		object := &schemaObject{
+			typ:       schemaObjectTypeStatement,
+			name:      normalizeStatement(statement),
+			statement: statement,
+		}
+		if matches := createObjectRegexp.FindStringSubmatch(statement); matches != nil {
+			object.typ = strings.ToUpper(matches[1])
+			object.name = matches[2]
+			if matches := partitionOfRegexp.FindStringSubmatch(statement); matches != nil {
+				object.parent = matches[1]
+			}
+		} else if matches := addConstraintRegexp.FindStringSubmatch(statement); matches != nil {
+			object.typ = schemaObjectTypeConstraint
+			object.name = fmt.Sprintf("%s.%s", matches[1], matches[2])
+		} else if matches := attachPartitionRegexp.FindStringSubmatch(statement); matches != nil {
+			object.typ = schemaObjectTypePartition
+			object.name = matches[3]
+			object.parent = matches[2]
+			if strings.EqualFold(matches[1], "INDEX") {
+				// The pg_dump names the created index without the schema, while it qualifies the attached one.
+				object.name, object.parent = unqualifyName(object.name), unqualifyName(object.parent)
+			}
+			parentMap[fmt.Sprintf("%s/%s", strings.ToUpper(matches[1]), object.name)] = object.parent
+		}
+		// Disambiguate the objects with the same name, e.g. the overloaded functions and the repeated statements.
+		name := object.name
+		for i := 2; keySet[object.key()]; i++ {
+			object.name = fmt.Sprintf("%s#%d", name, i)
+		}
+		keySet[object.key()] = true
+		objectList = append(objectList, object)
+	}
+	// The partition is attached after it's created.
+	for _, object := range objectList {
+		if parent, ok := parentMap[object.key()]; ok {
+			object.parent = parent
+		}
+	}
+	return objectList, nil
+}
+
+// unqualifyName returns the name without the schema, e.g. "t_idx" for "public.t_idx".
+func unqualifyName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// splitSchemaDump splits the schema dump into statements.
+func splitSchemaDump(dbType db.Type, schema string) ([]string, error) {
+	if dbType == db.Postgres {
+		return parser.SplitMultiSQL(parser.Postgres, schema)
+	}
+
+	// The other dumps end each statement at the end of a line, and the routines and triggers are wrapped by the DELIMITER commands.
+	var statementList []string
+	var buf strings.Builder
+	delimiter := ";"
+	for _, line := range strings.Split(schema, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(strings.ToUpper(trimmed), "DELIMITER ") {
+			delimiter = strings.TrimSpace(trimmed[len("DELIMITER "):])
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+		if strings.HasSuffix(trimmed, delimiter) {
+			statement := strings.TrimSpace(buf.String())
+			statement = strings.TrimSpace(strings.TrimSuffix(statement, delimiter))
+			statementList = append(statementList, statement+";")
+			buf.Reset()
+		}
+	}
+	if statement := strings.TrimSpace(buf.String()); statement != "" {
+		statementList = append(statementList, statement)
+	}
+	return statementList, nil
+}
+
+// trimStatementComment trims the spaces and the leading "--" comment lines of the statement.
+func trimStatementComment(statement string) string {
+	statement = strings.TrimSpace(statement)
+	for strings.HasPrefix(statement, "--") {
+		i := strings.Index(statement, "\n")
+		if i < 0 {
+			return ""
+		}
+		statement = strings.TrimSpace(statement[i+1:])
+	}
+	return statement
+}
+
+// normalizeStatement collapses the whitespaces, so the diff ignores the formatting changes.
+func normalizeStatement(statement string) string {
+	return strings.Join(strings.Fields(statement), " ")
+}
+
+// diffTableColumn computes the column diff between two CREATE TABLE statements.
+func diffTableColumn(before, after string) []*api.SchemaColumnDiff {
+	beforeNameList, beforeMap := parseTableColumn(before)
+	afterNameList, afterMap := parseTableColumn(after)
+
+	var columnList []*api.SchemaColumnDiff
+	for _, name := range afterNameList {
+		beforeDefinition, ok := beforeMap[name]
+		switch {
+		case !ok:
+			columnList = append(columnList, &api.SchemaColumnDiff{
+				Name:   name,
+				Action: api.SchemaDiffActionAdd,
+				After:  afterMap[name],
+			})
+		case normalizeStatement(beforeDefinition) != normalizeStatement(afterMap[name]):
+			columnList = append(columnList, &api.SchemaColumnDiff{
+				Name:   name,
+				Action: api.SchemaDiffActionModify,
+				Before: beforeDefinition,
+				After:  afterMap[name],
+			})
+		}
+	}
+	for _, name := range beforeNameList {
+		if _, ok := afterMap[name]; !ok {
+			columnList = append(columnList, &api.SchemaColumnDiff{
+				Name:   name,
+				Action: api.SchemaDiffActionDrop,
+				Before: beforeMap[name],
+			})
+		}
+	}
+	return columnList
+}
+
+// parseTableColumn parses the column names and the definitions from the CREATE TABLE statement.
+func parseTableColumn(statement string) ([]string, map[string]string) {
+	definitionMap := make(map[string]string)
+	var nameList []string
+	for _, item := range splitTableDefinition(statement) {
+		name, definition := splitColumnName(item)
+		if name == "" {
+			continue
+		}
+		if _, ok := definitionMap[name]; ok {
+			continue
+		}
+		nameList = append(nameList, name)
+		definitionMap[name] = definition
+	}
+	return nameList, definitionMap
+}
+
+// splitTableDefinition splits the parenthesized definition list of the CREATE TABLE statement by the top level commas.
+func splitTableDefinition(statement string) []string {
+	start := strings.Index(statement, "(")
+	if start < 0 {
+		return nil
+	}
+	var itemList []string
+	var quote rune
+	depth := 0
+	itemStart := start + 1
+	for i, r := range statement {
+		if i <= start {
+			continue
+		}
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch r {
+		case '\'', '"', '`':
+			quote = r
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return append(itemList, strings.TrimSpace(statement[itemStart:i]))
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				itemList = append(itemList, strings.TrimSpace(statement[itemStart:i]))
+				itemStart = i + 1
+			}
+		}
+	}
+	return itemList
+}
+
+// splitColumnName splits the column definition into the unquoted column name and the rest of the definition.
+// It returns the empty name for the table constraints.
+func splitColumnName(item string) (string, string) {
+	if item == "" {
+		return "", ""
+	}
+	if quote := item[0]; quote == '"' || quote == '`' {
+		end := strings.IndexByte(item[1:], quote)
+		if end < 0 {
+			return "", ""
+		}
+		return item[1 : end+1], strings.TrimSpace(item[end+2:])
+	}
+	fields := strings.Fields(item)
+	if tableConstraintKeywords[strings.ToUpper(fields[0])] {
+		return "", ""
+	}
+	return fields[0], strings.TrimSpace(strings.TrimPrefix(item, fields[0]))
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchemaMySQL(t *testing.T) {
+	before := "SET character_set_client  = utf8;\n" +
+		"--\n" +
+		"-- Table structure for `users`\n" +
+		"--\n" +
+		"CREATE TABLE `users` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `name` varchar(64) DEFAULT 'a,b',\n" +
+		"  `age` int,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB;\n" +
+		"CREATE TABLE `legacy` (\n" +
+		"  `id` int\n" +
+		");\n" +
+		"DELIMITER ;;\n" +
+		"CREATE DEFINER=`root`@`%` PROCEDURE `p`()\n" +
+		"BEGIN\n" +
+		"  SELECT 1;\n" +
+		"END ;;\n" +
+		"DELIMITER ;\n"
+	after := "SET character_set_client  = utf8;\n" +
+		"CREATE TABLE `users` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `name` varchar(128) DEFAULT 'a,b',\n" +
+		"  `email` varchar(255),\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB;\n" +
+		"CREATE TABLE `orders` (\n" +
+		"  `id` int\n" +
+		");\n" +
+		"DELIMITER ;;\n" +
+		"CREATE DEFINER=`root`@`%` PROCEDURE `p`()\n" +
+		"BEGIN\n" +
+		"    SELECT 1;\n" +
+		"END ;;\n" +
+		"DELIMITER ;\n"
+
+	diff, err := diffSchema(db.MySQL, before, after)
+	require.NoError(t, err)
+	require.Equal(t, []*api.SchemaObjectDiff{
+		{
+			Type:   "TABLE",
+			Name:   "`users`",
+			Action: api.SchemaDiffActionModify,
+			Before: "CREATE TABLE `users` (\n  `id` int NOT NULL,\n  `name` varchar(64) DEFAULT 'a,b',\n  `age` int,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;",
+			After:  "CREATE TABLE `users` (\n  `id` int NOT NULL,\n  `name` varchar(128) DEFAULT 'a,b',\n  `email` varchar(255),\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;",
+			ColumnList: []*api.SchemaColumnDiff{
+				{Name: "name", Action: api.SchemaDiffActionModify, Before: "varchar(64) DEFAULT 'a,b'", After: "varchar(128) DEFAULT 'a,b'"},
+				{Name: "email", Action: api.SchemaDiffActionAdd, After: "varchar(255)"},
+				{Name: "age", Action: api.SchemaDiffActionDrop, Before: "int"},
+			},
+		},
+		{
+			Type:   "TABLE",
+			Name:   "`orders`",
+			Action: api.SchemaDiffActionAdd,
+			After:  "CREATE TABLE `orders` (\n  `id` int\n);",
+		},
+		{
+			Type:   "TABLE",
+			Name:   "`legacy`",
+			Action: api.SchemaDiffActionDrop,
+			Before: "CREATE TABLE `legacy` (\n  `id` int\n);",
+		},
+	}, diff.ObjectList)
+}
+
+func TestDiffSchemaPostgres(t *testing.T) {
+	before := "CREATE TABLE public.t (\n    id integer NOT NULL\n);\n" +
+		"ALTER TABLE ONLY public.t ADD CONSTRAINT t_pkey PRIMARY KEY (id);\n"
+	after := "CREATE TABLE public.t (\n    id integer NOT NULL,\n    \"Name\" text\n);\n" +
+		"CREATE INDEX idx_name ON public.t USING btree (\"Name\");\n"
+
+	diff, err := diffSchema(db.Postgres, before, after)
+	require.NoError(t, err)
+	require.Len(t, diff.ObjectList, 3)
+
+	require.Equal(t, "public.t", diff.ObjectList[0].Name)
+	require.Equal(t, api.SchemaDiffActionModify, diff.ObjectList[0].Action)
+	require.Equal(t, []*api.SchemaColumnDiff{
+		{Name: "Name", Action: api.SchemaDiffActionAdd, After: "text"},
+	}, diff.ObjectList[0].ColumnList)
+
+	require.Equal(t, "INDEX", diff.ObjectList[1].Type)
+	require.Equal(t, "idx_name", diff.ObjectList[1].Name)
+	require.Equal(t, api.SchemaDiffActionAdd, diff.ObjectList[1].Action)
+
+	require.Equal(t, "CONSTRAINT", diff.ObjectList[2].Type)
+	require.Equal(t, "public.t.t_pkey", diff.ObjectList[2].Name)
+	require.Equal(t, api.SchemaDiffActionDrop, diff.ObjectList[2].Action)
+}
+
+func TestDiffSchemaPostgresPartition(t *testing.T) {
+	before := "CREATE TABLE public.event (\n    id integer NOT NULL,\n    ts date NOT NULL\n)\nPARTITION BY RANGE (ts);\n" +
+		"CREATE TABLE public.event_2022 (\n    id integer NOT NULL,\n    ts date NOT NULL\n);\n" +
+		"ALTER TABLE ONLY public.event ATTACH PARTITION public.event_2022 FOR VALUES FROM ('2022-01-01') TO ('2023-01-01');\n"
+	after := "CREATE TABLE public.event (\n    id integer NOT NULL,\n    ts date NOT NULL,\n    name text\n)\nPARTITION BY RANGE (ts);\n" +
+		"CREATE TABLE public.event_2022 (\n    id integer NOT NULL,\n    ts date NOT NULL,\n    name text\n);\n" +
+		"CREATE TABLE public.event_2023 (\n    id integer NOT NULL,\n    ts date NOT NULL,\n    name text\n);\n" +
+		"ALTER TABLE ONLY public.event ATTACH PARTITION public.event_2022 FOR VALUES FROM ('2022-01-01') TO ('2023-01-01');\n" +
+		"ALTER TABLE ONLY public.event ATTACH PARTITION public.event_2023 FOR VALUES FROM ('2023-01-01') TO ('2024-01-01');\n" +
+		"CREATE INDEX event_ts_idx ON ONLY public.event USING btree (ts);\n" +
+		"CREATE INDEX event_2022_ts_idx ON public.event_2022 USING btree (ts);\n" +
+		"CREATE INDEX event_2023_ts_idx ON public.event_2023 USING btree (ts);\n" +
+		"ALTER INDEX public.event_ts_idx ATTACH PARTITION public.event_2022_ts_idx;\n" +
+		"ALTER INDEX public.event_ts_idx ATTACH PARTITION public.event_2023_ts_idx;\n"
+
+	diff, err := diffSchema(db.Postgres, before, after)
+	require.NoError(t, err)
+
+	var nameList []string
+	for _, object := range diff.ObjectList {
+		nameList = append(nameList, fmt.Sprintf("%s %s %s %s", object.Action, object.Type, object.Name, object.ParentName))
+	}
+	// The column and the index added to the partitioned table are omitted on the partitions.
+	require.Equal(t, []string{
+		"MODIFY TABLE public.event ",
+		"ADD TABLE public.event_2023 public.event",
+		"ADD PARTITION public.event_2023 public.event",
+		"ADD INDEX event_ts_idx ",
+	}, nameList)
+}
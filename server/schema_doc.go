@@ -0,0 +1,469 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/pdf"
+)
+
+const (
+	defaultSchemaDocThemeColor = "#4f46e5"
+)
+
+var (
+	themeColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// schemaDoc is the content of the schema documentation of a database.
+type schemaDoc struct {
+	// Branding
+	Title      string
+	LogoURL    string
+	Footer     string
+	ThemeColor string
+
+	DatabaseName string
+	GeneratedAt  string
+	TableList    []*schemaDocTable
+}
+
+type schemaDocTable struct {
+	Name             string
+	Comment          string
+	ColumnList       []*api.Column
+	IndexList        []*schemaDocIndex
+	RelationshipList []api.ERDiagramEdge
+}
+
+type schemaDocIndex struct {
+	Name       string
+	ColumnList []string
+	Unique     bool
+	Primary    bool
+}
+
+func (s *Server) registerSchemaDocRoutes(g *echo.Group) {
+	g.GET("/database/:id/schema-doc", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		format := api.SchemaDocFormatMarkdown
+		if v := c.QueryParam("format"); v != "" {
+			format = api.SchemaDocFormat(strings.ToUpper(v))
+		}
+		contentType, extension, err := getSchemaDocContentType(format)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		content, err := s.generateSchemaDoc(ctx, database, format)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to generate schema documentation for database %q", database.Name)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", database.Name+extension))
+		return c.Blob(http.StatusOK, contentType, content)
+	})
+
+	g.GET("/project/:projectID/schema-doc-setting", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		setting, err := s.store.GetSchemaDocSettingByProjectID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get schema doc setting for project ID: %d", projectID)).SetInternal(err)
+		}
+		if setting == nil {
+			// Returns the setting with UNKNOWN_ID to indicate the project has no setting.
+			setting = &api.SchemaDocSetting{
+				ID:              api.UnknownID,
+				ProjectID:       projectID,
+				PublishSchedule: api.SchemaDocPublishScheduleDisabled,
+				PublishFormat:   api.SchemaDocFormatMarkdown,
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal get schema doc setting response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/schema-doc-setting", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		project, err := s.store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find project with ID %d", projectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+		}
+
+		upsert := &api.SchemaDocSettingUpsert{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed set schema doc setting request").SetInternal(err)
+		}
+		upsert.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+		upsert.ProjectID = projectID
+		if upsert.PublishSchedule == "" {
+			upsert.PublishSchedule = api.SchemaDocPublishScheduleDisabled
+		}
+		if upsert.PublishFormat == "" {
+			upsert.PublishFormat = api.SchemaDocFormatMarkdown
+		}
+		if err := validateSchemaDocSetting(upsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		setting, err := s.store.UpsertSchemaDocSetting(ctx, upsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set schema doc setting for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal set schema doc setting response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+func validateSchemaDocSetting(upsert *api.SchemaDocSettingUpsert) error {
+	if upsert.ThemeColor != "" && !themeColorRegexp.MatchString(upsert.ThemeColor) {
+		return fmt.Errorf("invalid theme color %q, expect the hex color such as %q", upsert.ThemeColor, defaultSchemaDocThemeColor)
+	}
+	if upsert.LogoURL != "" && !strings.HasPrefix(upsert.LogoURL, "https://") && !strings.HasPrefix(upsert.LogoURL, "http://") {
+		return fmt.Errorf("invalid logo URL %q, expect the http or https URL", upsert.LogoURL)
+	}
+	if _, _, err := getSchemaDocContentType(upsert.PublishFormat); err != nil {
+		return err
+	}
+	switch upsert.PublishSchedule {
+	case api.SchemaDocPublishScheduleDisabled:
+	case api.SchemaDocPublishScheduleDaily, api.SchemaDocPublishScheduleWeekly:
+		if !strings.HasPrefix(upsert.PublishURL, "https://") && !strings.HasPrefix(upsert.PublishURL, "http://") {
+			return fmt.Errorf("invalid publish URL %q, expect the http or https URL", upsert.PublishURL)
+		}
+	default:
+		return fmt.Errorf("invalid publish schedule %q", upsert.PublishSchedule)
+	}
+	return nil
+}
+
+// getSchemaDocContentType returns the content type and the file extension of the format.
+func getSchemaDocContentType(format api.SchemaDocFormat) (string, string, error) {
+	switch format {
+	case api.SchemaDocFormatMarkdown:
+		return "text/markdown; charset=UTF-8", ".md", nil
+	case api.SchemaDocFormatHTML:
+		return echo.MIMETextHTMLCharsetUTF8, ".html", nil
+	case api.SchemaDocFormatPDF:
+		return "application/pdf", ".pdf", nil
+	}
+	return "", "", fmt.Errorf("unsupported schema doc format %q", format)
+}
+
+// generateSchemaDoc renders the schema documentation of the database from the synced metadata with the branding of its project.
+func (s *Server) generateSchemaDoc(ctx context.Context, database *api.Database, format api.SchemaDocFormat) ([]byte, error) {
+	setting, err := s.store.GetSchemaDocSettingByProjectID(ctx, database.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema doc setting for project ID %d, error: %w", database.ProjectID, err)
+	}
+	tableList, foreignKeyList, err := s.findTableListWithDetail(ctx, database.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find table list for database ID %d, error: %w", database.ID, err)
+	}
+
+	doc := buildSchemaDoc(database, tableList, foreignKeyList, setting)
+	doc.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	return renderSchemaDoc(doc, format)
+}
+
+// buildSchemaDoc builds the schema documentation, the setting may be nil if the project has no setting.
+func buildSchemaDoc(database *api.Database, tableList []*api.Table, foreignKeyList []*api.ForeignKey, setting *api.SchemaDocSetting) *schemaDoc {
+	doc := &schemaDoc{
+		ThemeColor:   defaultSchemaDocThemeColor,
+		DatabaseName: database.Name,
+	}
+	if database.Project != nil {
+		doc.Title = database.Project.Name
+	}
+	if setting != nil {
+		if setting.Title != "" {
+			doc.Title = setting.Title
+		}
+		if setting.ThemeColor != "" {
+			doc.ThemeColor = setting.ThemeColor
+		}
+		doc.LogoURL = setting.LogoURL
+		doc.Footer = setting.Footer
+	}
+
+	relationshipMap := make(map[string][]api.ERDiagramEdge)
+	for _, edge := range buildERDiagram(database, tableList, foreignKeyList).EdgeList {
+		relationshipMap[edge.FromTable] = append(relationshipMap[edge.FromTable], edge)
+	}
+	for _, table := range tableList {
+		docTable := &schemaDocTable{
+			Name:             table.Name,
+			Comment:          getDescription(table.Description, table.Comment),
+			ColumnList:       table.ColumnList,
+			RelationshipList: relationshipMap[table.Name],
+		}
+		// The index list is sorted by the name and the position, so the expressions of an index are adjacent.
+		for _, index := range table.IndexList {
+			if n := len(docTable.IndexList); n > 0 && docTable.IndexList[n-1].Name == index.Name {
+				docTable.IndexList[n-1].ColumnList = append(docTable.IndexList[n-1].ColumnList, index.Expression)
+				continue
+			}
+			docTable.IndexList = append(docTable.IndexList, &schemaDocIndex{
+				Name:       index.Name,
+				ColumnList: []string{index.Expression},
+				Unique:     index.Unique,
+				Primary:    index.Primary,
+			})
+		}
+		doc.TableList = append(doc.TableList, docTable)
+	}
+	sort.Slice(doc.TableList, func(i, j int) bool {
+		return doc.TableList[i].Name < doc.TableList[j].Name
+	})
+	return doc
+}
+
+func renderSchemaDoc(doc *schemaDoc, format api.SchemaDocFormat) ([]byte, error) {
+	switch format {
+	case api.SchemaDocFormatMarkdown:
+		return []byte(renderSchemaDocMarkdown(doc)), nil
+	case api.SchemaDocFormatHTML:
+		return renderSchemaDocHTML(doc)
+	case api.SchemaDocFormatPDF:
+		return renderSchemaDocPDF(doc), nil
+	}
+	return nil, fmt.Errorf("unsupported schema doc format %q", format)
+}
+
+func renderSchemaDocMarkdown(doc *schemaDoc) string {
+	var b strings.Builder
+	if doc.LogoURL != "" {
+		fmt.Fprintf(&b, "![logo](%s)\n\n", doc.LogoURL)
+	}
+	if doc.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(doc.Title))
+	}
+	fmt.Fprintf(&b, "## Database %s\n\n", escapeMarkdown(doc.DatabaseName))
+	if doc.GeneratedAt != "" {
+		fmt.Fprintf(&b, "Generated at %s.\n\n", doc.GeneratedAt)
+	}
+
+	for _, table := range doc.TableList {
+		fmt.Fprintf(&b, "### %s\n\n", escapeMarkdown(table.Name))
+		if table.Comment != "" {
+			fmt.Fprintf(&b, "%s\n\n", escapeMarkdown(table.Comment))
+		}
+
+		b.WriteString("| Column | Type | Nullable | Default | Comment |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, column := range table.ColumnList {
+			defaultValue := ""
+			if column.Default != nil {
+				defaultValue = *column.Default
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				escapeMarkdown(column.Name),
+				escapeMarkdown(column.Type),
+				strconv.FormatBool(column.Nullable),
+				escapeMarkdown(defaultValue),
+				escapeMarkdown(getDescription(column.Description, column.Comment)),
+			)
+		}
+		b.WriteString("\n")
+
+		if len(table.IndexList) > 0 {
+			b.WriteString("| Index | Columns | Unique | Primary |\n")
+			b.WriteString("| --- | --- | --- | --- |\n")
+			for _, index := range table.IndexList {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+					escapeMarkdown(index.Name),
+					escapeMarkdown(strings.Join(index.ColumnList, ", ")),
+					strconv.FormatBool(index.Unique),
+					strconv.FormatBool(index.Primary),
+				)
+			}
+			b.WriteString("\n")
+		}
+
+		for _, relationship := range table.RelationshipList {
+			fmt.Fprintf(&b, "- %s\n", escapeMarkdown(formatRelationship(relationship)))
+		}
+		if len(table.RelationshipList) > 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	if doc.Footer != "" {
+		fmt.Fprintf(&b, "---\n\n%s\n", escapeMarkdown(doc.Footer))
+	}
+	return b.String()
+}
+
+// escapeMarkdown escapes the text to be used in the Markdown table cells and headings.
+func escapeMarkdown(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// formatRelationship formats the foreign key such as "fk_user: (user_id) -> users (id)".
+func formatRelationship(edge api.ERDiagramEdge) string {
+	var fromColumnList, toColumnList []string
+	for _, pair := range edge.ColumnPairList {
+		fromColumnList = append(fromColumnList, pair.FromColumn)
+		toColumnList = append(toColumnList, pair.ToColumn)
+	}
+	return fmt.Sprintf("%s: (%s) -> %s (%s)", edge.Name, strings.Join(fromColumnList, ", "), edge.ToTable, strings.Join(toColumnList, ", "))
+}
+
+var schemaDocHTMLTemplate = template.Must(template.New("schemaDoc").Funcs(template.FuncMap{
+	"description":        getDescription,
+	"formatRelationship": formatRelationship,
+	"join":               strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{if .Title}}{{.Title}} - {{end}}{{.DatabaseName}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2937; }
+h1, h2, h3 { color: {{.ThemeColor}}; }
+table { border-collapse: collapse; margin-bottom: 1rem; }
+th, td { border: 1px solid #d1d5db; padding: 0.25rem 0.5rem; text-align: left; }
+th { background-color: {{.ThemeColor}}; color: #ffffff; }
+footer { margin-top: 2rem; color: #6b7280; }
+</style>
+</head>
+<body>
+{{if .LogoURL}}<img src="{{.LogoURL}}" alt="logo" height="48">{{end}}
+{{if .Title}}<h1>{{.Title}}</h1>{{end}}
+<h2>Database {{.DatabaseName}}</h2>
+{{if .GeneratedAt}}<p>Generated at {{.GeneratedAt}}.</p>{{end}}
+{{range .TableList}}
+<h3 id="{{.Name}}">{{.Name}}</h3>
+{{if .Comment}}<p>{{.Comment}}</p>{{end}}
+<table>
+<tr><th>Column</th><th>Type</th><th>Nullable</th><th>Default</th><th>Comment</th></tr>
+{{range .ColumnList}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Nullable}}</td><td>{{if .Default}}{{.Default}}{{end}}</td><td>{{description .Description .Comment}}</td></tr>
+{{end}}</table>
+{{if .IndexList}}<table>
+<tr><th>Index</th><th>Columns</th><th>Unique</th><th>Primary</th></tr>
+{{range .IndexList}}<tr><td>{{.Name}}</td><td>{{join .ColumnList ", "}}</td><td>{{.Unique}}</td><td>{{.Primary}}</td></tr>
+{{end}}</table>{{end}}
+{{if .RelationshipList}}<ul>
+{{range .RelationshipList}}<li>{{formatRelationship .}}</li>
+{{end}}</ul>{{end}}
+{{end}}
+{{if .Footer}}<footer>{{.Footer}}</footer>{{end}}
+</body>
+</html>
+`))
+
+func renderSchemaDocHTML(doc *schemaDoc) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := schemaDocHTMLTemplate.Execute(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderSchemaDocPDF renders the text-only PDF, the logo and the theme color are not supported.
+func renderSchemaDocPDF(doc *schemaDoc) []byte {
+	d := pdf.NewDocument()
+	if doc.Title != "" {
+		d.AddText(doc.Title, 20, true)
+		d.AddSpace(4)
+	}
+	d.AddText(fmt.Sprintf("Database %s", doc.DatabaseName), 16, true)
+	if doc.GeneratedAt != "" {
+		d.AddText(fmt.Sprintf("Generated at %s.", doc.GeneratedAt), 9, false)
+	}
+
+	for _, table := range doc.TableList {
+		d.AddSpace(12)
+		d.AddText(table.Name, 13, true)
+		if table.Comment != "" {
+			d.AddText(table.Comment, 10, false)
+		}
+		d.AddSpace(4)
+		d.AddText("Columns", 10, true)
+		for _, column := range table.ColumnList {
+			text := fmt.Sprintf("%s  %s", column.Name, column.Type)
+			if !column.Nullable {
+				text += "  NOT NULL"
+			}
+			if column.Default != nil {
+				text += fmt.Sprintf("  DEFAULT %s", *column.Default)
+			}
+			if comment := getDescription(column.Description, column.Comment); comment != "" {
+				text += fmt.Sprintf("  -- %s", comment)
+			}
+			d.AddIndentedText(text, 9, false, 12)
+		}
+		if len(table.IndexList) > 0 {
+			d.AddText("Indexes", 10, true)
+			for _, index := range table.IndexList {
+				text := fmt.Sprintf("%s (%s)", index.Name, strings.Join(index.ColumnList, ", "))
+				if index.Primary {
+					text += "  PRIMARY"
+				} else if index.Unique {
+					text += "  UNIQUE"
+				}
+				d.AddIndentedText(text, 9, false, 12)
+			}
+		}
+		if len(table.RelationshipList) > 0 {
+			d.AddText("Relationships", 10, true)
+			for _, relationship := range table.RelationshipList {
+				d.AddIndentedText(formatRelationship(relationship), 9, false, 12)
+			}
+		}
+	}
+
+	if doc.Footer != "" {
+		d.AddSpace(12)
+		d.AddText(doc.Footer, 9, false)
+	}
+	return d.Bytes()
+}
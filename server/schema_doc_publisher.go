@@ -0,0 +1,152 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	schemaDocPublishInterval = time.Duration(1) * time.Hour
+	schemaDocPublishTimeout  = time.Duration(30) * time.Second
+)
+
+// NewSchemaDocPublisher creates a schema documentation publisher.
+func NewSchemaDocPublisher(server *Server) *SchemaDocPublisher {
+	return &SchemaDocPublisher{
+		server: server,
+		client: &http.Client{Timeout: schemaDocPublishTimeout},
+	}
+}
+
+// SchemaDocPublisher publishes the schema documentation of the databases in the projects by the publish schedule.
+type SchemaDocPublisher struct {
+	server *Server
+	client *http.Client
+}
+
+// Run will run the schema documentation publisher.
+func (p *SchemaDocPublisher) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(schemaDocPublishInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Schema doc publisher started and will run every %v", schemaDocPublishInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						log.Error("Schema doc publisher PANIC RECOVER", zap.Error(err))
+					}
+				}()
+				p.publish(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (p *SchemaDocPublisher) publish(ctx context.Context, now time.Time) {
+	for _, schedule := range []api.SchemaDocPublishSchedule{api.SchemaDocPublishScheduleDaily, api.SchemaDocPublishScheduleWeekly} {
+		schedule := schedule
+		settingList, err := p.server.store.FindSchemaDocSetting(ctx, &api.SchemaDocSettingFind{PublishSchedule: &schedule})
+		if err != nil {
+			log.Error("Failed to find schema doc setting list", zap.String("schedule", string(schedule)), zap.Error(err))
+			continue
+		}
+		for _, setting := range settingList {
+			if !isSchemaDocPublishDue(setting, now) {
+				continue
+			}
+			if err := p.publishProject(ctx, setting); err != nil {
+				log.Error("Failed to publish schema doc",
+					zap.Int("project", setting.ProjectID),
+					zap.String("url", setting.PublishURL),
+					zap.Error(err))
+				continue
+			}
+			if err := p.server.store.PublishSchemaDocSetting(ctx, &api.SchemaDocSettingPublish{
+				ID:              setting.ID,
+				LastPublishedTs: now.Unix(),
+			}); err != nil {
+				log.Error("Failed to record schema doc publication", zap.Int("project", setting.ProjectID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// isSchemaDocPublishDue returns true if the period of the publish schedule has passed since the last publication.
+func isSchemaDocPublishDue(setting *api.SchemaDocSetting, now time.Time) bool {
+	var period time.Duration
+	switch setting.PublishSchedule {
+	case api.SchemaDocPublishScheduleDaily:
+		period = 24 * time.Hour
+	case api.SchemaDocPublishScheduleWeekly:
+		period = 7 * 24 * time.Hour
+	default:
+		return false
+	}
+	// Allow a publish interval of tolerance, otherwise the publication time drifts by the interval every period.
+	return now.Sub(time.Unix(setting.LastPublishedTs, 0)) >= period-schemaDocPublishInterval
+}
+
+// publishProject POSTs the schema documentation of each database in the project to the publish URL.
+// The database name is passed in the "database" query parameter.
+func (p *SchemaDocPublisher) publishProject(ctx context.Context, setting *api.SchemaDocSetting) error {
+	contentType, _, err := getSchemaDocContentType(setting.PublishFormat)
+	if err != nil {
+		return err
+	}
+	publishURL, err := url.Parse(setting.PublishURL)
+	if err != nil {
+		return fmt.Errorf("invalid publish URL %q, error: %w", setting.PublishURL, err)
+	}
+
+	rowStatus := api.Normal
+	databaseList, err := p.server.store.FindDatabase(ctx, &api.DatabaseFind{
+		ProjectID: &setting.ProjectID,
+		RowStatus: &rowStatus,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find database list for project ID %d, error: %w", setting.ProjectID, err)
+	}
+	for _, database := range databaseList {
+		content, err := p.server.generateSchemaDoc(ctx, database, setting.PublishFormat)
+		if err != nil {
+			return fmt.Errorf("failed to generate schema doc for database %q, error: %w", database.Name, err)
+		}
+
+		query := publishURL.Query()
+		query.Set("database", database.Name)
+		publishURL.RawQuery = query.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, publishURL.String(), bytes.NewReader(content))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", contentType)
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to POST schema doc for database %q, error: %w", database.Name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("failed to POST schema doc for database %q, status %s", database.Name, resp.Status)
+		}
+	}
+	return nil
+}
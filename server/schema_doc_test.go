@@ -0,0 +1,122 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSchemaDocMarkdown(t *testing.T) {
+	defaultValue := "0"
+	database := &api.Database{ID: 101, Name: "shop", Project: &api.Project{Name: "Shop"}}
+	tableList := []*api.Table{
+		{
+			ID:      2,
+			Name:    "orders",
+			Comment: "the orders",
+			ColumnList: []*api.Column{
+				{Name: "id", Type: "int"},
+				{Name: "user_id", Type: "int", Default: &defaultValue, Comment: "a|b"},
+			},
+			IndexList: []*api.Index{
+				{Name: "PRIMARY", Expression: "id", Unique: true, Primary: true},
+				{Name: "idx_user", Expression: "user_id", Position: 1},
+				{Name: "idx_user", Expression: "id", Position: 2},
+			},
+		},
+		{
+			ID:   1,
+			Name: "users",
+			ColumnList: []*api.Column{
+				{Name: "id", Type: "int"},
+			},
+		},
+	}
+	foreignKeyList := []*api.ForeignKey{
+		{TableID: 2, Name: "fk_user", Column: "user_id", Position: 1, ReferencedTable: "users", ReferencedColumn: "id"},
+	}
+	setting := &api.SchemaDocSetting{Footer: "Internal use only"}
+
+	want := `# Shop
+
+## Database shop
+
+### orders
+
+the orders
+
+| Column | Type | Nullable | Default | Comment |
+| --- | --- | --- | --- | --- |
+| id | int | false |  |  |
+| user_id | int | false | 0 | a\|b |
+
+| Index | Columns | Unique | Primary |
+| --- | --- | --- | --- |
+| PRIMARY | id | true | true |
+| idx_user | user_id, id | false | false |
+
+- fk_user: (user_id) -> users (id)
+
+### users
+
+| Column | Type | Nullable | Default | Comment |
+| --- | --- | --- | --- | --- |
+| id | int | false |  |  |
+
+---
+
+Internal use only
+`
+	doc := buildSchemaDoc(database, tableList, foreignKeyList, setting)
+	require.Equal(t, defaultSchemaDocThemeColor, doc.ThemeColor)
+	require.Equal(t, want, renderSchemaDocMarkdown(doc))
+
+	content, err := renderSchemaDocHTML(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "<li>fk_user: (user_id) -&gt; users (id)</li>")
+}
+
+func TestIsSchemaDocPublishDue(t *testing.T) {
+	now := time.Unix(1650000000, 0)
+	tests := []struct {
+		schedule        api.SchemaDocPublishSchedule
+		lastPublishedTs int64
+		want            bool
+	}{
+		{
+			schedule:        api.SchemaDocPublishScheduleDaily,
+			lastPublishedTs: 0,
+			want:            true,
+		},
+		{
+			schedule:        api.SchemaDocPublishScheduleDaily,
+			lastPublishedTs: now.Add(-23 * time.Hour).Unix(),
+			want:            true,
+		},
+		{
+			schedule:        api.SchemaDocPublishScheduleDaily,
+			lastPublishedTs: now.Add(-22 * time.Hour).Unix(),
+			want:            false,
+		},
+		{
+			schedule:        api.SchemaDocPublishScheduleWeekly,
+			lastPublishedTs: now.Add(-24 * time.Hour).Unix(),
+			want:            false,
+		},
+		{
+			schedule:        api.SchemaDocPublishScheduleDisabled,
+			lastPublishedTs: 0,
+			want:            false,
+		},
+	}
+
+	for _, test := range tests {
+		setting := &api.SchemaDocSetting{
+			PublishSchedule: test.schedule,
+			LastPublishedTs: test.lastPublishedTs,
+		}
+		require.Equal(t, test.want, isSchemaDocPublishDue(setting, now), "%s %d", test.schedule, test.lastPublishedTs)
+	}
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func (s *Server) registerSchemaSnapshotRoutes(g *echo.Group) {
+	// Lists the schema snapshots of the database without the schema, e.g. ?limit=20, the latest snapshot comes first.
+	g.GET("/database/:id/schema-snapshot", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		find := &api.SchemaSnapshotFind{DatabaseID: &id}
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit query parameter is not a number: %s", limitStr)).SetInternal(err)
+			}
+			find.Limit = &limit
+		}
+		snapshotList, err := s.store.FindSchemaSnapshot(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot list for database ID: %d", id)).SetInternal(err)
+		}
+		// The schema can be large, it's fetched by the time of the snapshot.
+		for _, snapshot := range snapshotList {
+			snapshot.Schema = ""
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, snapshotList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema snapshot list response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Gets the schema of the database as of the time, e.g. ?ts=1656547200. The current schema is returned if ts is omitted.
+	g.GET("/database/:id/schema-snapshot/as-of", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		ts, err := getSchemaSnapshotTs(c.QueryParam("ts"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		snapshot, err := s.store.GetSchemaSnapshotAsOf(ctx, id, ts)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot for database ID: %d", id)).SetInternal(err)
+		}
+		if snapshot == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("No schema snapshot of database %d is captured as of %s", id, time.Unix(ts, 0).UTC().Format(time.RFC3339)))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, snapshot); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema snapshot response for database ID: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Diffs the schema of the database between two points in time, e.g. ?from=1656547200&to=1656633600.
+	// The current schema is used if to is omitted.
+	g.GET("/database/:id/schema-snapshot/diff", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		if c.QueryParam("from") == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing from query parameter")
+		}
+		fromTs, err := getSchemaSnapshotTs(c.QueryParam("from"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		toTs, err := getSchemaSnapshotTs(c.QueryParam("to"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if fromTs > toTs {
+			return echo.NewHTTPError(http.StatusBadRequest, "The from time must be before the to time")
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		from, err := s.store.GetSchemaSnapshotAsOf(ctx, id, fromTs)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot for database ID: %d", id)).SetInternal(err)
+		}
+		to, err := s.store.GetSchemaSnapshotAsOf(ctx, id, toTs)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot for database ID: %d", id)).SetInternal(err)
+		}
+		if to == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("No schema snapshot of database %q is captured as of %s", database.Name, time.Unix(toTs, 0).UTC().Format(time.RFC3339)))
+		}
+
+		// The database didn't have a snapshot at the from time, all objects are added since then.
+		fromSchema := ""
+		if from != nil {
+			fromSchema = from.Schema
+		}
+		diff, err := diffSchema(database.Instance.Engine, fromSchema, to.Schema)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to diff schema snapshots for database %q", database.Name)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.SchemaSnapshotDiff{
+			From: from,
+			To:   to,
+			Diff: diff,
+		})
+	})
+}
+
+// getSchemaSnapshotTs parses the Unix timestamp in seconds of the query parameter, which is now if empty.
+func getSchemaSnapshotTs(tsStr string) (int64, error) {
+	if tsStr == "" {
+		return time.Now().Unix(), nil
+	}
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("timestamp is not a number: %s", tsStr)
+	}
+	return ts, nil
+}
+
+// captureSchemaSnapshot dumps the schema of the synced database and records it as a snapshot.
+// It never fails the sync, the errors are only logged.
+func (s *Server) captureSchemaSnapshot(ctx context.Context, instance *api.Instance, driver db.Driver, databaseName string) {
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{
+		InstanceID: &instance.ID,
+		Name:       &databaseName,
+	})
+	if err != nil {
+		log.Error("Failed to find database for schema snapshot",
+			zap.String("instance", instance.Name),
+			zap.String("database", databaseName),
+			zap.Error(err))
+		return
+	}
+	if database == nil {
+		return
+	}
+	var schemaBuf bytes.Buffer
+	if _, err := driver.Dump(ctx, databaseName, &schemaBuf, true /* schemaOnly */); err != nil {
+		log.Warn("Failed to dump schema for schema snapshot",
+			zap.String("instance", instance.Name),
+			zap.String("database", databaseName),
+			zap.Error(err))
+		return
+	}
+	s.recordSchemaSnapshot(ctx, instance, database.ID, api.SchemaSnapshotSync, "" /* version */, schemaBuf.String())
+}
+
+// recordSchemaSnapshot records the schema of the database if it changes since the latest snapshot.
+// The errors are only logged.
+func (s *Server) recordSchemaSnapshot(ctx context.Context, instance *api.Instance, databaseID int, source api.SchemaSnapshotSource, version string, schema string) {
+	latest, err := s.store.GetSchemaSnapshotAsOf(ctx, databaseID, time.Now().Unix())
+	if err != nil {
+		log.Error("Failed to find the latest schema snapshot",
+			zap.Int("database_id", databaseID),
+			zap.Error(err))
+		return
+	}
+	if latest != nil && isStatementEqual(instance.Engine, latest.Schema, schema) {
+		return
+	}
+	if _, err := s.store.CreateSchemaSnapshot(ctx, &api.SchemaSnapshotCreate{
+		DatabaseID: databaseID,
+		Source:     source,
+		Version:    version,
+		Schema:     schema,
+	}); err != nil {
+		log.Error("Failed to create schema snapshot",
+			zap.Int("database_id", databaseID),
+			zap.String("source", string(source)),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestSchemaSnapshotAsOf(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	e := echo.New()
+	s.registerSchemaSnapshotRoutes(e.Group("/api"))
+
+	// The demo database 7004 is the blog database on the MySQL instance.
+	databaseID := 7004
+	usersTable := "CREATE TABLE `users` (\n  `id` int NOT NULL\n);"
+	ordersTable := "CREATE TABLE `orders` (\n  `id` int NOT NULL\n);"
+	first, err := s.store.CreateSchemaSnapshot(ctx, &api.SchemaSnapshotCreate{
+		DatabaseID: databaseID,
+		Source:     api.SchemaSnapshotSync,
+		Schema:     usersTable + "\n",
+	})
+	require.NoError(t, err)
+	// The snapshots are captured in different seconds.
+	time.Sleep(1500 * time.Millisecond)
+	second, err := s.store.CreateSchemaSnapshot(ctx, &api.SchemaSnapshotCreate{
+		DatabaseID: databaseID,
+		Source:     api.SchemaSnapshotMigration,
+		Version:    "20220601000000",
+		Schema:     usersTable + "\n" + ordersTable + "\n",
+	})
+	require.NoError(t, err)
+	t1, t2 := first.CreatedTs, second.CreatedTs
+	require.Less(t, t1, t2)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/database/%d/schema-snapshot/%s", databaseID, path), nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("as of", func(t *testing.T) {
+		tests := []struct {
+			ts   int64
+			want *api.SchemaSnapshot
+		}{
+			// Before the first snapshot.
+			{ts: t1 - 1, want: nil},
+			// Exactly at the snapshots.
+			{ts: t1, want: first},
+			{ts: t2, want: second},
+			// Between the two snapshots.
+			{ts: t2 - 1, want: first},
+		}
+		for _, test := range tests {
+			rec := get(fmt.Sprintf("as-of?ts=%d", test.ts))
+			if test.want == nil {
+				require.Equal(t, http.StatusNotFound, rec.Code, test.ts)
+				continue
+			}
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			snapshot := &api.SchemaSnapshot{}
+			require.NoError(t, jsonapi.UnmarshalPayload(rec.Body, snapshot))
+			require.Equal(t, test.want.ID, snapshot.ID, test.ts)
+			require.Equal(t, test.want.Schema, snapshot.Schema, test.ts)
+		}
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		tests := []struct {
+			name string
+			from int64
+			to   int64
+			// wantFrom and wantTo are the expected snapshot IDs, 0 for no snapshot.
+			wantFrom int
+			wantTo   int
+			wantDiff []*api.SchemaObjectDiff
+		}{
+			{
+				name:     "from before the first snapshot",
+				from:     t1 - 1,
+				to:       t1,
+				wantTo:   first.ID,
+				wantDiff: []*api.SchemaObjectDiff{{Type: "TABLE", Name: "`users`", Action: api.SchemaDiffActionAdd, After: usersTable}},
+			},
+			{
+				name:     "exactly at the two snapshots",
+				from:     t1,
+				to:       t2,
+				wantFrom: first.ID,
+				wantTo:   second.ID,
+				wantDiff: []*api.SchemaObjectDiff{{Type: "TABLE", Name: "`orders`", Action: api.SchemaDiffActionAdd, After: ordersTable}},
+			},
+			{
+				name:     "between the two snapshots",
+				from:     t1,
+				to:       t2 - 1,
+				wantFrom: first.ID,
+				wantTo:   first.ID,
+			},
+			{
+				name:     "across the second snapshot",
+				from:     t2 - 1,
+				to:       t2 + 1,
+				wantFrom: first.ID,
+				wantTo:   second.ID,
+				wantDiff: []*api.SchemaObjectDiff{{Type: "TABLE", Name: "`orders`", Action: api.SchemaDiffActionAdd, After: ordersTable}},
+			},
+		}
+		for _, test := range tests {
+			rec := get(fmt.Sprintf("diff?from=%d&to=%d", test.from, test.to))
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var diff api.SchemaSnapshotDiff
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &diff))
+			if test.wantFrom == 0 {
+				require.Nil(t, diff.From, test.name)
+			} else {
+				require.Equal(t, test.wantFrom, diff.From.ID, test.name)
+			}
+			require.Equal(t, test.wantTo, diff.To.ID, test.name)
+			if len(test.wantDiff) == 0 {
+				require.Empty(t, diff.Diff.ObjectList, test.name)
+			} else {
+				require.Equal(t, test.wantDiff, diff.Diff.ObjectList, test.name)
+			}
+		}
+
+		// No snapshot is captured as of the to time.
+		rec := get(fmt.Sprintf("diff?from=0&to=%d", t1-1))
+		require.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+		rec = get(fmt.Sprintf("diff?from=%d&to=%d", t2, t1))
+		require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	})
+}
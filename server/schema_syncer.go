@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,9 +13,16 @@ import (
 )
 
 const (
-	schemaSyncInterval = time.Duration(30) * time.Minute
+	// schemaSyncInterval is the interval of the automatic schema sync of the instances without a sync setting,
+	// and the interval to purge the not found databases.
+	schemaSyncInterval = time.Duration(api.DefaultSchemaSyncIntervalMinutes) * time.Minute
+	// schemaSyncCheckInterval is how often the syncer checks which instances are due for the sync.
+	schemaSyncCheckInterval = time.Minute
 )
 
+// syncLog logs on behalf of the sync subsystem whose level can be changed at runtime.
+var syncLog = log.Named(log.SubsystemSync)
+
 // NewSchemaSyncer creates a schema syncer.
 func NewSchemaSyncer(server *Server) *SchemaSyncer {
 	return &SchemaSyncer{
@@ -29,16 +37,20 @@ type SchemaSyncer struct {
 
 // Run will run the schema syncer once.
 func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
-	ticker := time.NewTicker(schemaSyncInterval)
+	ticker := time.NewTicker(schemaSyncCheckInterval)
 	defer ticker.Stop()
 	defer wg.Done()
-	log.Debug(fmt.Sprintf("Schema syncer started and will run every %v", schemaSyncInterval))
+	syncLog.Debug(fmt.Sprintf("Schema syncer started and will check the instances due for the sync every %v", schemaSyncCheckInterval))
 	runningTasks := make(map[int]bool)
 	mu := sync.RWMutex{}
+	// The instances not synced since the syncer started are treated as synced at the start,
+	// so they are synced after their intervals as before the per-instance schedule is introduced.
+	startTime := time.Now()
+	lastSyncTimeMap := make(map[int]time.Time)
+	lastPurgeTime := startTime
 	for {
 		select {
 		case <-ticker.C:
-			log.Debug("New schema syncer round started...")
 			func() {
 				defer func() {
 					if r := recover(); r != nil {
@@ -46,11 +58,12 @@ func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 						if !ok {
 							err = fmt.Errorf("%v", r)
 						}
-						log.Error("Schema syncer PANIC RECOVER", zap.Error(err))
+						syncLog.Error("Schema syncer PANIC RECOVER", zap.Error(err))
 					}
 				}()
 
 				ctx := context.Background()
+				now := time.Now()
 
 				rowStatus := api.Normal
 				instanceFind := &api.InstanceFind{
@@ -58,11 +71,28 @@ func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 				}
 				instanceList, err := s.server.store.FindInstance(ctx, instanceFind)
 				if err != nil {
-					log.Error("Failed to retrieve instances", zap.Error(err))
+					syncLog.Error("Failed to retrieve instances", zap.Error(err))
+					return
+				}
+				scheduleMap, err := s.getScheduleMap(ctx)
+				if err != nil {
+					syncLog.Error("Failed to retrieve instance sync settings", zap.Error(err))
 					return
 				}
 
 				for _, instance := range instanceList {
+					schedule, ok := scheduleMap[instance.ID]
+					if !ok {
+						schedule = &schemaSyncSchedule{interval: schemaSyncInterval}
+					}
+					lastSyncTime, ok := lastSyncTimeMap[instance.ID]
+					if !ok {
+						lastSyncTime = startTime
+					}
+					if !schedule.isDue(lastSyncTime, now) {
+						continue
+					}
+
 					mu.Lock()
 					if _, ok := runningTasks[instance.ID]; ok {
 						mu.Unlock()
@@ -70,25 +100,129 @@ func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 					}
 					runningTasks[instance.ID] = true
 					mu.Unlock()
+					lastSyncTimeMap[instance.ID] = now
 
 					go func(instance *api.Instance) {
-						log.Debug("Sync instance schema", zap.String("instance", instance.Name))
+						syncLog.Debug("Sync instance schema", zap.String("instance", instance.Name))
 						defer func() {
 							mu.Lock()
 							delete(runningTasks, instance.ID)
 							mu.Unlock()
 						}()
 						if err := s.server.syncEngineVersionAndSchema(ctx, instance); err != nil {
-							log.Debug("Failed to sync instance",
+							syncLog.Debug("Failed to sync instance",
 								zap.Int("id", instance.ID),
 								zap.String("name", instance.Name),
 								zap.String("error", err.Error()))
 						}
 					}(instance)
 				}
+
+				if now.Sub(lastPurgeTime) >= schemaSyncInterval {
+					lastPurgeTime = now
+					s.purgeNotFoundDatabase(ctx)
+				}
 			}()
 		case <-ctx.Done(): // if cancel() execute
 			return
 		}
 	}
 }
+
+// schemaSyncSchedule is the automatic schema sync schedule of an instance.
+type schemaSyncSchedule struct {
+	// interval is the interval of the sync, 0 disables the automatic sync.
+	interval           time.Duration
+	blackoutWindowList []*api.SyncBlackoutWindow
+}
+
+// isDue returns true if the sync is due at now since the last sync, and now is out of the blackout windows.
+func (s *schemaSyncSchedule) isDue(lastSyncTime, now time.Time) bool {
+	if s.interval == 0 || now.Sub(lastSyncTime) < s.interval {
+		return false
+	}
+	for _, window := range s.blackoutWindowList {
+		if window.Contains(now) {
+			return false
+		}
+	}
+	return true
+}
+
+// getScheduleMap returns the schedules of the instances with a sync setting, keyed by the instance ID.
+func (s *SchemaSyncer) getScheduleMap(ctx context.Context) (map[int]*schemaSyncSchedule, error) {
+	settingList, err := s.server.store.FindInstanceSyncSetting(ctx, &api.InstanceSyncSettingFind{})
+	if err != nil {
+		return nil, err
+	}
+	scheduleMap := make(map[int]*schemaSyncSchedule)
+	for _, setting := range settingList {
+		windowList, err := api.ParseSyncBlackoutWindowList(setting.BlackoutWindowList)
+		if err != nil {
+			// The setting is validated on update, so this only happens if the row is changed by hand.
+			syncLog.Error("Invalid blackout windows of instance sync setting, ignore the windows",
+				zap.Int("instance_id", setting.InstanceID),
+				zap.Error(err))
+		}
+		scheduleMap[setting.InstanceID] = &schemaSyncSchedule{
+			interval:           time.Duration(setting.IntervalMinutes) * time.Minute,
+			blackoutWindowList: windowList,
+		}
+	}
+	return scheduleMap, nil
+}
+
+// purgeNotFoundDatabase archives the databases which have been missing from the instance
+// longer than the database purge policy of the environment allows.
+func (s *SchemaSyncer) purgeNotFoundDatabase(ctx context.Context) {
+	syncStatus := api.NotFound
+	rowStatus := api.Normal
+	databaseList, err := s.server.store.FindDatabase(ctx, &api.DatabaseFind{
+		SyncStatus: &syncStatus,
+		RowStatus:  &rowStatus,
+	})
+	if err != nil {
+		syncLog.Error("Failed to retrieve not found databases", zap.Error(err))
+		return
+	}
+
+	// The policies are cached by the environment and the tags.
+	policyMap := make(map[string]*api.DatabasePurgePolicy)
+	for _, database := range databaseList {
+		environmentID := database.Instance.EnvironmentID
+		tagList := api.GetPolicyTagList(database.Instance, database)
+		key := fmt.Sprintf("%d/%s", environmentID, strings.Join(tagList, ","))
+		policy, ok := policyMap[key]
+		if !ok {
+			policy, err = s.server.store.GetDatabasePurgePolicy(ctx, environmentID, tagList)
+			if err != nil {
+				syncLog.Error("Failed to get database purge policy", zap.Int("environment_id", environmentID), zap.Error(err))
+				continue
+			}
+			policyMap[key] = policy
+		}
+		if policy.PurgeAfterDays == 0 || database.NotFoundTs == 0 {
+			continue
+		}
+		if time.Since(time.Unix(database.NotFoundTs, 0)) < time.Duration(policy.PurgeAfterDays)*24*time.Hour {
+			continue
+		}
+
+		archived := api.Archived
+		if _, err := s.server.store.PatchDatabase(ctx, &api.DatabasePatch{
+			ID:        database.ID,
+			UpdaterID: api.SystemBotID,
+			RowStatus: &archived,
+		}); err != nil {
+			syncLog.Error("Failed to purge not found database",
+				zap.Int("id", database.ID),
+				zap.String("name", database.Name),
+				zap.Error(err))
+			continue
+		}
+		syncLog.Info("Purged not found database",
+			zap.Int("id", database.ID),
+			zap.String("name", database.Name),
+			zap.String("instance", database.Instance.Name))
+	}
+}
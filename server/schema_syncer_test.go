@@ -0,0 +1,24 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestSchemaSyncScheduleIsDue(t *testing.T) {
+	lastSyncTime := time.Date(2022, 5, 23, 8, 0, 0, 0, time.UTC)
+	schedule := &schemaSyncSchedule{interval: time.Hour}
+	require.False(t, schedule.isDue(lastSyncTime, lastSyncTime.Add(30*time.Minute)))
+	require.True(t, schedule.isDue(lastSyncTime, lastSyncTime.Add(time.Hour)))
+
+	schedule.blackoutWindowList = []*api.SyncBlackoutWindow{{DayOfWeek: -1, StartHour: 9, EndHour: 18}}
+	require.False(t, schedule.isDue(lastSyncTime, lastSyncTime.Add(2*time.Hour)))
+	require.True(t, schedule.isDue(lastSyncTime, lastSyncTime.Add(10*time.Hour)))
+
+	disabled := &schemaSyncSchedule{}
+	require.False(t, disabled.isDue(lastSyncTime, lastSyncTime.Add(24*time.Hour)))
+}
@@ -2,8 +2,10 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 	"runtime"
 	"strings"
 	"sync"
@@ -30,6 +32,8 @@ import (
 	enterpriseService "github.com/bytebase/bytebase/enterprise/service"
 	"github.com/bytebase/bytebase/metric"
 	metricCollector "github.com/bytebase/bytebase/metric/collector"
+	"github.com/bytebase/bytebase/plugin/blob"
+	"github.com/bytebase/bytebase/plugin/eventbus"
 	"github.com/bytebase/bytebase/resources/mysqlutil"
 	"github.com/bytebase/bytebase/resources/postgres"
 	"github.com/bytebase/bytebase/store"
@@ -41,13 +45,21 @@ const openAPIPrefix = "/v1"
 // Server is the Bytebase server.
 type Server struct {
 	// Asynchronous runners.
-	TaskScheduler      *TaskScheduler
-	TaskCheckScheduler *TaskCheckScheduler
-	MetricReporter     *MetricReporter
-	SchemaSyncer       *SchemaSyncer
-	BackupRunner       *BackupRunner
-	AnomalyScanner     *AnomalyScanner
-	runnerWG           sync.WaitGroup
+	TaskScheduler           *TaskScheduler
+	TaskCheckScheduler      *TaskCheckScheduler
+	MetricReporter          *MetricReporter
+	SchemaSyncer            *SchemaSyncer
+	BackupRunner            *BackupRunner
+	SchemaDocPublisher      *SchemaDocPublisher
+	AnomalyScanner          *AnomalyScanner
+	IssueSLAScanner         *IssueSLAScanner
+	RecurringIssueScheduler *RecurringIssueScheduler
+	DashboardTileRefresher  *DashboardTileRefresher
+	PartitionManager        *PartitionManager
+	AccountReportRunner     *AccountReportRunner
+	TrashPurger             *TrashPurger
+	DataRetentionPurger     *DataRetentionPurger
+	runnerWG                sync.WaitGroup
 
 	ActivityManager *ActivityManager
 
@@ -62,6 +74,27 @@ type Server struct {
 	startedTs     int64
 	secret        string
 
+	// samplePgInstanceList is the started sample Postgres servers, see generateSampleData.
+	samplePgInstanceList []*postgres.Instance
+	samplePgMu           sync.Mutex
+
+	// blobStore saves the issue attachments.
+	blobStore blob.Store
+
+	// issueIdempotencyKeyInFlight is the idempotency keys of the issue creation requests being processed.
+	issueIdempotencyKeyInFlight sync.Map // map[creatorID/idempotencyKey]bool
+
+	// pushEventReplayInFlight is the repository push events being replayed.
+	pushEventReplayInFlight sync.Map // map[eventID]bool
+
+	// sqlEditorQueryCount is the number of the running SQL editor queries of the users in the environments.
+	sqlEditorQueryCount   map[string]int // map[principalID/environmentID]count
+	sqlEditorQueryCountMu sync.Mutex
+
+	// proxyLoginFailureMap is the failed proxy logins of the agents and the emails, see isProxyLoginThrottled.
+	proxyLoginFailureMap map[string]*proxyLoginFailure // map[agent/agentID or email/email]failure
+	proxyLoginFailureMu  sync.Mutex
+
 	// boot specifies that whether the server boot correctly
 	cancel context.CancelFunc
 }
@@ -78,6 +111,9 @@ var casbinDBAPolicy string
 //go:embed acl_casbin_policy_developer.csv
 var casbinDeveloperPolicy string
 
+//go:embed acl_casbin_policy_auditor.csv
+var casbinAuditorPolicy string
+
 // Use following cmd to generate swagger doc
 // swag init -g ./server.go -d ./server --output docs/openapi --parseDependency
 
@@ -116,8 +152,29 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	log.Info(fmt.Sprintf("debug=%t", prof.Debug))
 	log.Info(fmt.Sprintf("dataDir=%s", prof.DataDir))
 	log.Info(fmt.Sprintf("backupStorageBackend=%s", prof.BackupStorageBackend))
+	log.Info(fmt.Sprintf("bootstrapFile=%s", prof.BootstrapFile))
+	log.Info(fmt.Sprintf("eventSink=%t", prof.EventSinkURL != ""))
+	log.Info(fmt.Sprintf("sampleData=%t", prof.SampleData))
+	log.Info(fmt.Sprintf("blobStore=%t", prof.BlobStoreURL != ""))
+	log.Info(fmt.Sprintf("attachmentScan=%t", prof.AttachmentScanURL != ""))
+	log.Info(fmt.Sprintf("offline=%t", prof.Offline))
 	log.Info("-----Config END-------")
 
+	if prof.EventSinkURL != "" {
+		if err := eventbus.Validate(prof.EventSinkURL); err != nil {
+			return nil, fmt.Errorf("invalid event sink, error: %w", err)
+		}
+	}
+	if prof.BlobStoreURL != "" {
+		blobStore, err := blob.Open(prof.BlobStoreURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob store, error: %w", err)
+		}
+		s.blobStore = blobStore
+	} else {
+		s.blobStore = blob.NewLocalStore(path.Join(prof.DataDir, "blob"))
+	}
+
 	serverStarted := false
 	defer func() {
 		if !serverStarted {
@@ -127,7 +184,7 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 
 	var err error
 
-	resourceDir := common.GetResourceDir(prof.DataDir)
+	resourceDir := prof.ResourceDir
 	// Install mysqlutil
 	if err := mysqlutil.Install(resourceDir); err != nil {
 		return nil, fmt.Errorf("cannot install mysqlbinlog binary, error: %w", err)
@@ -141,12 +198,18 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	log.Info("-----Embedded Postgres Config BEGIN-----")
 	log.Info(fmt.Sprintf("resourceDir=%s", resourceDir))
 	log.Info(fmt.Sprintf("pgdataDir=%s", pgDataDir))
+	log.Info(fmt.Sprintf("datastorePort=%d", prof.DatastorePort))
+	log.Info(fmt.Sprintf("datastoreLocale=%s", prof.DatastoreLocale))
+	log.Info(fmt.Sprintf("datastoreChecksums=%t", prof.DatastoreChecksums))
 	log.Info("-----Embedded Postgres Config END-----")
 	log.Info("Preparing embedded PostgreSQL instance...")
 	// Installs the Postgres binary and creates the 'activeProfile.pgUser' user/database
 	// to store Bytebase's own metadata.
 	log.Info(fmt.Sprintf("Installing Postgres OS %q Arch %q", runtime.GOOS, runtime.GOARCH))
-	pgInstance, err := postgres.Install(resourceDir, pgDataDir, prof.PgUser)
+	pgInstance, err := postgres.InstallWithOption(resourceDir, pgDataDir, prof.PgUser, postgres.InitDBOption{
+		Locale:        prof.DatastoreLocale,
+		DataChecksums: prof.DatastoreChecksums,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -187,6 +250,7 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	e := echo.New()
 	e.Debug = prof.Debug
 	e.HideBanner = true
+	e.HTTPErrorHandler = httpErrorHandler
 	e.HidePort = true
 
 	// Disallow to be embedded in an iFrame.
@@ -221,6 +285,18 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 
 		taskScheduler.Register(api.TaskDatabasePITRCutover, NewPITRCutoverTaskExecutor)
 
+		taskScheduler.Register(api.TaskDatabaseCharsetConvert, NewCharsetConvertTaskExecutor)
+
+		taskScheduler.Register(api.TaskDatabaseDataBackfill, NewDataBackfillTaskExecutor)
+
+		taskScheduler.Register(api.TaskDatabaseDrop, NewDatabaseDropTaskExecutor)
+
+		taskScheduler.Register(api.TaskDatabaseDataValidate, NewDataValidateTaskExecutor)
+
+		taskScheduler.Register(api.TaskDatabaseAccessChange, NewAccessChangeTaskExecutor)
+		taskScheduler.Register(api.TaskInstanceParameterChange, NewParameterChangeTaskExecutor)
+		taskScheduler.Register(api.TaskInstanceUpgradeCheck, NewUpgradeCheckTaskExecutor)
+
 		s.TaskScheduler = taskScheduler
 
 		// Task check scheduler
@@ -233,9 +309,18 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		statementCompositeExecutor := NewTaskCheckStatementAdvisorCompositeExecutor()
 		taskCheckScheduler.Register(api.TaskCheckDatabaseStatementAdvise, statementCompositeExecutor)
 
+		statementExternalExecutor := NewTaskCheckStatementExternalAdvisorExecutor()
+		taskCheckScheduler.Register(api.TaskCheckDatabaseStatementExternalAdvise, statementExternalExecutor)
+
 		statementTypeExecutor := NewTaskCheckStatementTypeExecutor()
 		taskCheckScheduler.Register(api.TaskCheckDatabaseStatementType, statementTypeExecutor)
 
+		impactAnalysisExecutor := NewTaskCheckImpactAnalysisExecutor()
+		taskCheckScheduler.Register(api.TaskCheckDatabaseImpactAnalysis, impactAnalysisExecutor)
+
+		migrationDuplicateExecutor := NewTaskCheckMigrationDuplicateExecutor()
+		taskCheckScheduler.Register(api.TaskCheckDatabaseMigrationDuplicate, migrationDuplicateExecutor)
+
 		databaseConnectExecutor := NewTaskCheckDatabaseConnectExecutor()
 		taskCheckScheduler.Register(api.TaskCheckDatabaseConnect, databaseConnectExecutor)
 
@@ -248,6 +333,18 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		timingExecutor := NewTaskCheckTimingExecutor()
 		taskCheckScheduler.Register(api.TaskCheckGeneralEarliestAllowedTime, timingExecutor)
 
+		stageGateExecutor := NewTaskCheckStageGateExecutor()
+		taskCheckScheduler.Register(api.TaskCheckGeneralStageGate, stageGateExecutor)
+
+		replicationLagExecutor := NewTaskCheckReplicationLagExecutor()
+		taskCheckScheduler.Register(api.TaskCheckDatabaseReplicationLag, replicationLagExecutor)
+
+		databaseNameExecutor := NewTaskCheckDatabaseNameExecutor()
+		taskCheckScheduler.Register(api.TaskCheckDatabaseCreateName, databaseNameExecutor)
+
+		databaseActivityExecutor := NewTaskCheckDatabaseActivityExecutor()
+		taskCheckScheduler.Register(api.TaskCheckDatabaseDropActivity, databaseActivityExecutor)
+
 		s.TaskCheckScheduler = taskCheckScheduler
 
 		// Schema syncer
@@ -256,20 +353,45 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		// Backup runner
 		s.BackupRunner = NewBackupRunner(s, prof.BackupRunnerInterval)
 
+		// Schema doc publisher
+		s.SchemaDocPublisher = NewSchemaDocPublisher(s)
+
 		// Anomaly scanner
 		s.AnomalyScanner = NewAnomalyScanner(s)
 
+		// Issue SLA scanner
+		s.IssueSLAScanner = NewIssueSLAScanner(s)
+
+		// Recurring issue scheduler
+		s.RecurringIssueScheduler = NewRecurringIssueScheduler(s)
+
+		// Dashboard tile refresher
+		s.DashboardTileRefresher = NewDashboardTileRefresher(s)
+
+		// Partition manager
+		s.PartitionManager = NewPartitionManager(s)
+
+		// Account report runner
+		s.AccountReportRunner = NewAccountReportRunner(s)
+
+		// Trash purger
+		s.TrashPurger = NewTrashPurger(s)
+
+		// Data retention purger
+		s.DataRetentionPurger = NewDataRetentionPurger(s)
+
 		// Metric reporter
 		s.initMetricReporter(config.workspaceID)
 	}
 
 	// Middleware
+	e.Use(requestIDMiddleware)
 	if prof.Mode == common.ReleaseModeDev || prof.Debug {
 		e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
 			Skipper: func(c echo.Context) bool {
 				return !common.HasPrefixes(c.Path(), "/api", "/hook")
 			},
-			Format: `{"time":"${time_rfc3339}",` +
+			Format: `{"time":"${time_rfc3339}","id":"${id}",` +
 				`"method":"${method}","uri":"${uri}",` +
 				`"status":${status},"error":"${error}"}` + "\n",
 		}))
@@ -294,7 +416,7 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	sa := scas.NewAdapter(strings.Join([]string{casbinOwnerPolicy, casbinDBAPolicy, casbinDeveloperPolicy}, "\n"))
+	sa := scas.NewAdapter(strings.Join([]string{casbinOwnerPolicy, casbinDBAPolicy, casbinDeveloperPolicy, casbinAuditorPolicy}, "\n"))
 	ce, err := casbin.NewEnforcer(m, sa)
 	if err != nil {
 		return nil, err
@@ -312,24 +434,66 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	s.registerPolicyRoutes(apiGroup)
 	s.registerProjectRoutes(apiGroup)
 	s.registerProjectWebhookRoutes(apiGroup)
+	s.registerRepositoryPushEventRoutes(apiGroup)
+	s.registerDatabaseAssignmentRuleRoutes(apiGroup)
+	s.registerProjectVariableRoutes(apiGroup)
 	s.registerProjectMemberRoutes(apiGroup)
 	s.registerEnvironmentRoutes(apiGroup)
 	s.registerInstanceRoutes(apiGroup)
+	s.registerDatabaseRoleMappingRoutes(apiGroup)
 	s.registerDatabaseRoutes(apiGroup)
+	s.registerERDiagramRoutes(apiGroup)
+	s.registerSchemaDocRoutes(apiGroup)
+	s.registerCharsetConversionRoutes(apiGroup)
+	s.registerTableScaffoldRoutes(apiGroup)
+	s.registerUsageMetricRoutes(apiGroup)
+	s.registerDatabaseTemplateRoutes(apiGroup)
+	s.registerSchemaDescriptionRoutes(apiGroup)
+	s.registerChangelogRoutes(apiGroup)
+	s.registerSchemaSnapshotRoutes(apiGroup)
+	s.registerChangeSetRoutes(apiGroup)
+	s.registerIssueSLARoutes(apiGroup)
+	s.registerCalendarRoutes(apiGroup)
+	s.registerSampleDataRoutes(apiGroup)
+	s.registerMigrationImportRoutes(apiGroup)
+	s.registerProjectConfigRoutes(apiGroup)
+	s.registerIssueAttachmentRoutes(apiGroup)
+	s.registerIssueFieldRoutes(apiGroup)
+	s.registerRecurringIssueRoutes(apiGroup)
+	s.registerDashboardTileRoutes(apiGroup)
+	s.registerPartitionPolicyRoutes(apiGroup)
+	s.registerAccessChangeRoutes(apiGroup)
+	s.registerAccountReportRoutes(apiGroup)
+	s.registerSessionRecordingRoutes(apiGroup)
+	s.registerInstanceParameterRoutes(apiGroup)
+	s.registerInstanceSyncSettingRoutes(apiGroup)
+	s.registerVersionAdvisoryRoutes(apiGroup)
+	s.registerPrivilegeCheckRoutes(apiGroup)
+	s.registerTrashRoutes(apiGroup)
+	s.registerDataRetentionRoutes(apiGroup)
+	s.registerDataSourceRotationRoutes(apiGroup)
 	s.registerIssueRoutes(apiGroup)
+	s.registerIssueChangeRecordRoutes(apiGroup)
 	s.registerIssueSubscriberRoutes(apiGroup)
+	s.registerIssueDependencyRoutes(apiGroup)
 	s.registerTaskRoutes(apiGroup)
 	s.registerStageRoutes(apiGroup)
 	s.registerActivityRoutes(apiGroup)
 	s.registerInboxRoutes(apiGroup)
 	s.registerBookmarkRoutes(apiGroup)
 	s.registerSQLRoutes(apiGroup)
+	s.registerMetadataQueryRoutes(apiGroup)
 	s.registerVCSRoutes(apiGroup)
+	s.registerVCSGroupWebhookRoutes(apiGroup)
 	s.registerLabelRoutes(apiGroup)
 	s.registerSubscriptionRoutes(apiGroup)
 	s.registerSheetRoutes(apiGroup)
 	s.registerSheetOrganizerRoutes(apiGroup)
+	s.registerAgentRoutes(apiGroup)
 	s.registerOpenAPIRoutes(openAPIGroup)
+	agentGroup := openAPIGroup.Group("/agent")
+	s.registerAgentProtocolRoutes(agentGroup)
+	s.registerProxyProtocolRoutes(agentGroup)
 
 	// Register healthz endpoint.
 	e.GET("/healthz", func(c echo.Context) error {
@@ -349,6 +513,33 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 
 	s.initSubscription()
 
+	if prof.BootstrapFile != "" && !prof.Readonly {
+		if err := s.applyBootstrapConfig(ctx, prof.BootstrapFile); err != nil {
+			return nil, fmt.Errorf("failed to apply bootstrap config, error: %w", err)
+		}
+	}
+
+	if prof.SampleData && !prof.Readonly {
+		// The issues are created on behalf of the first owner if anyone has signed up.
+		ownerID := api.UnknownID
+		role := api.Owner
+		memberList, err := s.store.FindMember(ctx, &api.MemberFind{Role: &role})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find owner list, error: %w", err)
+		}
+		if len(memberList) > 0 {
+			ownerID = memberList[0].PrincipalID
+		}
+		if _, err := s.generateSampleData(ctx, ownerID); err != nil {
+			return nil, fmt.Errorf("failed to generate sample data, error: %w", err)
+		}
+	} else if hasSampleData(prof.DataDir) {
+		// Keep the sample instances generated before reachable.
+		if err := s.startSampleInstances(); err != nil {
+			return nil, err
+		}
+	}
+
 	serverStarted = true
 	return s, nil
 }
@@ -359,20 +550,19 @@ func (s *Server) initSubscription() {
 }
 
 // initMetricReporter will initial the metric scheduler.
+// The usage metrics are always aggregated locally for the workspace owners, and are reported only in the prod mode and not offline.
 func (s *Server) initMetricReporter(workspaceID string) {
-	enabled := s.profile.Mode == common.ReleaseModeProd && !s.profile.Demo
-	if enabled {
-		metricReporter := NewMetricReporter(s, workspaceID)
-		metricReporter.Register(metric.InstanceCountMetricName, metricCollector.NewInstanceCountCollector(s.store))
-		metricReporter.Register(metric.IssueCountMetricName, metricCollector.NewIssueCountCollector(s.store))
-		metricReporter.Register(metric.ProjectCountMetricName, metricCollector.NewProjectCountCollector(s.store))
-		metricReporter.Register(metric.PolicyCountMetricName, metricCollector.NewPolicyCountCollector(s.store))
-		metricReporter.Register(metric.TaskCountMetricName, metricCollector.NewTaskCountCollector(s.store))
-		metricReporter.Register(metric.DatabaseCountMetricName, metricCollector.NewDatabaseCountCollector(s.store))
-		metricReporter.Register(metric.SheetCountMetricName, metricCollector.NewSheetCountCollector(s.store))
-		metricReporter.Register(metric.MemberCountMetricName, metricCollector.NewMemberCountCollector(s.store))
-		s.MetricReporter = metricReporter
-	}
+	reportEnabled := s.profile.Mode == common.ReleaseModeProd && !s.profile.Demo && !s.profile.Offline
+	metricReporter := NewMetricReporter(s, workspaceID, reportEnabled)
+	metricReporter.Register(metric.InstanceCountMetricName, metricCollector.NewInstanceCountCollector(s.store))
+	metricReporter.Register(metric.IssueCountMetricName, metricCollector.NewIssueCountCollector(s.store))
+	metricReporter.Register(metric.ProjectCountMetricName, metricCollector.NewProjectCountCollector(s.store))
+	metricReporter.Register(metric.PolicyCountMetricName, metricCollector.NewPolicyCountCollector(s.store))
+	metricReporter.Register(metric.TaskCountMetricName, metricCollector.NewTaskCountCollector(s.store))
+	metricReporter.Register(metric.DatabaseCountMetricName, metricCollector.NewDatabaseCountCollector(s.store))
+	metricReporter.Register(metric.SheetCountMetricName, metricCollector.NewSheetCountCollector(s.store))
+	metricReporter.Register(metric.MemberCountMetricName, metricCollector.NewMemberCountCollector(s.store))
+	s.MetricReporter = metricReporter
 }
 
 func getInitSetting(ctx context.Context, store *store.Store) (*config, error) {
@@ -387,6 +577,119 @@ func getInitSetting(ctx context.Context, store *store.Store) (*config, error) {
 		return nil, err
 	}
 
+	// initial announcement
+	if _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceAnnouncement,
+		Value:       "{}",
+		Description: "The workspace announcement banner in JSON format.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial usage metric report level
+	if _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceMetricReportLevel,
+		Value:       string(api.UsageMetricReportFull),
+		Description: "The granularity of the reported usage metrics, NONE, AGGREGATE or FULL.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial account report schedule
+	accountReportSetting, err := json.Marshal(&api.AccountReportSetting{
+		Schedule:   api.AccountReportScheduleUnset,
+		UnusedDays: api.DefaultAccountUnusedDays,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceAccountReport,
+		Value:       string(accountReportSetting),
+		Description: "The schedule of the database account reports in JSON format.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial certificate expiry alert
+	certificateExpirySetting, err := json.Marshal(&api.CertificateExpirySetting{
+		AlertDays: api.DefaultCertificateExpiryAlertDays,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceCertificateExpiry,
+		Value:       string(certificateExpirySetting),
+		Description: "The number of days before the instance certificates expire to raise the anomaly in JSON format.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial trash retention
+	trashSetting, err := json.Marshal(&api.TrashSetting{
+		RetentionDays: api.DefaultTrashRetentionDays,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceTrash,
+		Value:       string(trashSetting),
+		Description: "The number of days the deleted projects, instances and databases can be restored before they are purged in JSON format.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial data retention keeping everything
+	dataRetentionSetting, err := json.Marshal(&api.DataRetentionSetting{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceDataRetention,
+		Value:       string(dataRetentionSetting),
+		Description: "The number of days the activities, the query history and the audit log are kept before they are purged in JSON format, 0 means forever.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial group sync without any mapping
+	groupSyncSetting, err := json.Marshal(&api.GroupSyncSetting{
+		MappingList: []*api.GroupMapping{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceGroupSync,
+		Value:       string(groupSyncSetting),
+		Description: "The mapping from the VCS groups to the project roles synced on login in JSON format.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial external advisor disabled
+	externalAdvisorSetting, err := json.Marshal(&api.ExternalAdvisorSetting{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceExternalAdvisor,
+		Value:       string(externalAdvisorSetting),
+		Description: "The external policy engine, e.g. Open Policy Agent, consulted for the advices on the statements during the task checks in JSON format.",
+	}); err != nil {
+		return nil, err
+	}
+
 	conf := &config{}
 
 	// initial JWT token
@@ -427,6 +730,16 @@ func getInitSetting(ctx context.Context, store *store.Store) (*config, error) {
 		return nil, err
 	}
 
+	// initial CI token, which is generated by the workspace owner.
+	if _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingWorkspaceCIToken,
+		Value:       "",
+		Description: "SHA-256 hash of the token authenticating the CI status API",
+	}); err != nil {
+		return nil, err
+	}
+
 	return conf, nil
 }
 
@@ -445,7 +758,23 @@ func (s *Server) Run(ctx context.Context) error {
 		s.runnerWG.Add(1)
 		go s.BackupRunner.Run(ctx, &s.runnerWG)
 		s.runnerWG.Add(1)
+		go s.SchemaDocPublisher.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
 		go s.AnomalyScanner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.IssueSLAScanner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.RecurringIssueScheduler.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.DashboardTileRefresher.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.PartitionManager.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.AccountReportRunner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.TrashPurger.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.DataRetentionPurger.Run(ctx, &s.runnerWG)
 
 		if s.MetricReporter != nil {
 			s.runnerWG.Add(1)
@@ -494,6 +823,9 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// Shutdown sample postgres servers if started.
+	s.stopSampleInstances()
+
 	// Shutdown postgres server if embed.
 	if s.metaDB != nil {
 		s.metaDB.Close()
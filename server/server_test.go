@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	dbdriver "github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/resources/postgres"
+	"github.com/bytebase/bytebase/store"
+)
+
+const testPgPort = 6001
+
+// newTestServer returns the server backed by the store on a new Postgres instance migrated to the dev schema with the dev demo data.
+// Only the store is set up, and the instance is stopped when the test finishes.
+func newTestServer(t *testing.T) *Server {
+	pgInstance, stopInstance := postgres.SetupTestInstance(t, testPgPort)
+	t.Cleanup(stopInstance)
+
+	connCfg := dbdriver.ConnectionConfig{
+		Username: "root",
+		Password: "",
+		Host:     common.GetPostgresSocketDir(),
+		Port:     fmt.Sprintf("%d", testPgPort),
+	}
+	db := store.NewDB(connCfg, pgInstance.BaseDir, "demo/dev", false /* readonly */, "server-version", common.ReleaseModeDev)
+	require.NoError(t, db.Open(context.Background()))
+	s := store.New(db, NewCacheService())
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+	return &Server{store: s}
+}
+
+// newTestPgDatabase creates the database on the Postgres instance of the test server, which doubles as the demo Postgres instance.
+// The returned database is composed with the instance connecting to the test server's Postgres.
+func newTestPgDatabase(ctx context.Context, t *testing.T, s *Server, databaseName string) *api.Database {
+	instance, err := s.store.GetInstanceByID(ctx, 6005)
+	require.NoError(t, err)
+	instance.Host = common.GetPostgresSocketDir()
+	instance.Port = fmt.Sprintf("%d", testPgPort)
+	instance.DataSourceList = []*api.DataSource{{Type: api.Admin, Username: "root"}}
+
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	require.NoError(t, err)
+	defer driver.Close(ctx)
+	sqlDB, err := driver.GetDBConnection(ctx, "postgres")
+	require.NoError(t, err)
+	_, err = sqlDB.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %q", databaseName))
+	require.NoError(t, err)
+
+	database, err := s.store.CreateDatabase(ctx, &api.DatabaseCreate{
+		CreatorID:     api.SystemBotID,
+		ProjectID:     api.DefaultProjectID,
+		InstanceID:    instance.ID,
+		EnvironmentID: instance.EnvironmentID,
+		Name:          databaseName,
+		CharacterSet:  "UTF8",
+		Collation:     "en_US.UTF-8",
+	})
+	require.NoError(t, err)
+	database.Instance = instance
+	return database
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerSessionRecordingRoutes(g *echo.Group) {
+	g.GET("/session-recording", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find := &api.SessionRecordingFind{}
+		for name, field := range map[string]**int{
+			"creatorId":  &find.CreatorID,
+			"instanceId": &find.InstanceID,
+			"databaseId": &find.DatabaseID,
+		} {
+			valueStr := c.QueryParams().Get(name)
+			if valueStr == "" {
+				continue
+			}
+			value, err := strconv.Atoi(valueStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter %s is not a number: %s", name, valueStr)).SetInternal(err)
+			}
+			*field = &value
+		}
+		if sessionID := c.QueryParams().Get("sessionId"); sessionID != "" {
+			find.SessionID = &sessionID
+		}
+		recordingList, err := s.store.FindSessionRecording(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch session recording list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, recordingList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal session recording list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/session-recording/verify", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		// The whole chain is verified since deleting a recording is detected by the one after it.
+		recordingList, err := s.store.FindSessionRecording(ctx, &api.SessionRecordingFind{})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch session recording list").SetInternal(err)
+		}
+		verification := api.VerifySessionRecordingChain([]byte(s.secret), recordingList)
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, verification); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal session recording verification response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// recordSessionStatement records the statement run in a session on the database tagged sensitive, along with the metadata
+// of its result. The callers don't return the result if it fails, so that no result leaves unrecorded.
+// The database is nil for the instance-level queries.
+func (s *Server) recordSessionStatement(ctx context.Context, principalID int, instance *api.Instance, database *api.Database, source api.SessionSource, sessionID, statement string, start time.Time, data, errMessage string) error {
+	create := &api.SessionRecordingCreate{
+		CreatorID:    principalID,
+		CreatedTs:    time.Now().Unix(),
+		InstanceID:   instance.ID,
+		InstanceName: instance.Name,
+		Source:       source,
+		SessionID:    sessionID,
+		Statement:    statement,
+		DurationNs:   time.Since(start).Nanoseconds(),
+		Error:        errMessage,
+	}
+	if database != nil {
+		create.DatabaseID = database.ID
+		create.DatabaseName = database.Name
+	}
+	if data != "" {
+		columnNameList, rowCount, err := getRowSetMetadata(data)
+		if err != nil {
+			return err
+		}
+		create.ColumnNameList = columnNameList
+		create.RowCount = rowCount
+	}
+	if _, err := s.store.CreateSessionRecording(ctx, create, []byte(s.secret)); err != nil {
+		return fmt.Errorf("failed to record the statement of session %q, error: %w", sessionID, err)
+	}
+	return nil
+}
+
+// getRowSetMetadata returns the column names and the row count of the JSON encoded row set of the SQL editor query,
+// which is [columnNames, columnTypeNames, rows].
+func getRowSetMetadata(data string) ([]string, int, error) {
+	var rowSet []json.RawMessage
+	if err := json.Unmarshal([]byte(data), &rowSet); err != nil {
+		return nil, 0, fmt.Errorf("malformed row set, error: %w", err)
+	}
+	if len(rowSet) < 3 {
+		return nil, 0, fmt.Errorf("malformed row set, expect 3 parts but got %d", len(rowSet))
+	}
+	var columnNameList []string
+	if err := json.Unmarshal(rowSet[0], &columnNameList); err != nil {
+		return nil, 0, fmt.Errorf("malformed column names, error: %w", err)
+	}
+	var rows []json.RawMessage
+	if err := json.Unmarshal(rowSet[2], &rows); err != nil {
+		return nil, 0, fmt.Errorf("malformed rows, error: %w", err)
+	}
+	return columnNameList, len(rows), nil
+}
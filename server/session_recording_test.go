@@ -0,0 +1,17 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRowSetMetadata(t *testing.T) {
+	columnNameList, rowCount, err := getRowSetMetadata(`[["id","email"],["INT","TEXT"],[[1,"a@example.com"],[2,null]]]`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "email"}, columnNameList)
+	require.Equal(t, 2, rowCount)
+
+	_, _, err = getRowSetMetadata(`[["id"]]`)
+	require.Error(t, err)
+}
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -11,10 +13,21 @@ import (
 	"github.com/bytebase/bytebase/common"
 )
 
+// ciTokenLength is the length of the generated CI token.
+const ciTokenLength = 32
+
 var (
 	// Some settings contain secret info so we only return settings that are needed by the client.
 	whitelistSettings = []api.SettingName{
 		api.SettingBrandingLogo,
+		api.SettingWorkspaceAnnouncement,
+		api.SettingWorkspaceMetricReportLevel,
+		api.SettingWorkspaceAccountReport,
+		api.SettingWorkspaceCertificateExpiry,
+		api.SettingWorkspaceTrash,
+		api.SettingWorkspaceGroupSync,
+		api.SettingWorkspaceDataRetention,
+		api.SettingWorkspaceExternalAdvisor,
 	}
 )
 
@@ -59,6 +72,103 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed update setting request").SetInternal(err)
 		}
 
+		// The CI token is only generated by the server, so that the token itself is never stored.
+		if settingPatch.Name == api.SettingWorkspaceCIToken {
+			return echo.NewHTTPError(http.StatusBadRequest, "CI token can't be updated, generate a new one instead")
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceAnnouncement {
+			announcement := &api.Announcement{}
+			if err := json.Unmarshal([]byte(settingPatch.Value), announcement); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed announcement setting value").SetInternal(err)
+			}
+			if err := announcement.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid announcement: %s", err.Error()))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceMetricReportLevel {
+			if err := api.UsageMetricReportLevel(settingPatch.Value).Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceAccountReport {
+			accountReportSetting := &api.AccountReportSetting{}
+			if err := json.Unmarshal([]byte(settingPatch.Value), accountReportSetting); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed account report setting value").SetInternal(err)
+			}
+			if err := accountReportSetting.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid account report setting: %s", err.Error()))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceCertificateExpiry {
+			certificateExpirySetting := &api.CertificateExpirySetting{}
+			if err := json.Unmarshal([]byte(settingPatch.Value), certificateExpirySetting); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed certificate expiry setting value").SetInternal(err)
+			}
+			if err := certificateExpirySetting.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid certificate expiry setting: %s", err.Error()))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceTrash {
+			trashSetting := &api.TrashSetting{}
+			if err := json.Unmarshal([]byte(settingPatch.Value), trashSetting); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed trash setting value").SetInternal(err)
+			}
+			if err := trashSetting.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid trash setting: %s", err.Error()))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceDataRetention {
+			dataRetentionSetting := &api.DataRetentionSetting{}
+			if err := json.Unmarshal([]byte(settingPatch.Value), dataRetentionSetting); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed data retention setting value").SetInternal(err)
+			}
+			if err := dataRetentionSetting.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid data retention setting: %s", err.Error()))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceExternalAdvisor {
+			externalAdvisorSetting := &api.ExternalAdvisorSetting{}
+			if err := json.Unmarshal([]byte(settingPatch.Value), externalAdvisorSetting); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed external advisor setting value").SetInternal(err)
+			}
+			if err := externalAdvisorSetting.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid external advisor setting: %s", err.Error()))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceGroupSync {
+			groupSyncSetting := &api.GroupSyncSetting{}
+			if err := json.Unmarshal([]byte(settingPatch.Value), groupSyncSetting); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed group sync setting value").SetInternal(err)
+			}
+			if err := groupSyncSetting.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid group sync setting: %s", err.Error()))
+			}
+			for _, mapping := range groupSyncSetting.MappingList {
+				vcs, err := s.store.GetVCSByID(ctx, mapping.VCSID)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch VCS ID: %v", mapping.VCSID)).SetInternal(err)
+				}
+				if vcs == nil {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("VCS not found with ID %d", mapping.VCSID))
+				}
+				project, err := s.store.GetProjectByID(ctx, mapping.ProjectID)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", mapping.ProjectID)).SetInternal(err)
+				}
+				if project == nil {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project not found with ID %d", mapping.ProjectID))
+				}
+			}
+		}
+
 		setting, err := s.store.PatchSetting(ctx, settingPatch)
 		if err != nil {
 			if common.ErrorCode(err) == common.NotFound {
@@ -73,4 +183,43 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	// The CI token is returned only once, and only its hash is stored. Generating a new token revokes the previous one.
+	g.POST("/setting/ci-token", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		token, err := common.RandomString(ciTokenLength)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate CI token").SetInternal(err)
+		}
+		if _, err := s.store.PatchSetting(ctx, &api.SettingPatch{
+			Name:      api.SettingWorkspaceCIToken,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+			Value:     common.HashToken(token),
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update CI token").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.CIToken{Token: token})
+	})
+
+	// The announcement is returned only when it should be shown, so the client shows the banner as is.
+	// It returns null if there is no active announcement.
+	g.GET("/announcement", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		name := api.SettingWorkspaceAnnouncement
+		settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &name})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch announcement setting").SetInternal(err)
+		}
+		if len(settingList) == 0 {
+			return c.JSON(http.StatusOK, nil)
+		}
+		announcement := &api.Announcement{}
+		if err := json.Unmarshal([]byte(settingList[0].Value), announcement); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmarshal announcement setting").SetInternal(err)
+		}
+		if !announcement.IsActive(time.Now().Unix()) {
+			return c.JSON(http.StatusOK, nil)
+		}
+		return c.JSON(http.StatusOK, announcement)
+	})
 }
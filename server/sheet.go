@@ -103,6 +103,9 @@ func (s *Server) registerSheetRoutes(g *echo.Group) {
 		if vcs == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS not found by ID: %d", repo.VCSID))
 		}
+		if err := s.refreshGitHubAppToken(ctx, repo); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to refresh token for repository: %d", repo.ID)).SetInternal(err)
+		}
 
 		basePath := filepath.Dir(repo.SheetPathTemplate)
 		// TODO(Steven): The repo.branchFilter could be `test/*` which cannot be the ref value.
@@ -391,6 +394,105 @@ func (s *Server) registerSheetRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// Lists the versions of the sheet, the latest version comes first.
+	g.GET("/sheet/:id/version", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		sheetVersionList, err := s.store.FindSheetVersion(ctx, &api.SheetVersionFind{SheetID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch version list of sheet ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, sheetVersionList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal sheet version list response: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/sheet/:id/version/:version", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Version is not a number: %s", c.Param("version"))).SetInternal(err)
+		}
+
+		sheetVersion, err := s.store.GetSheetVersion(ctx, id, version)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch version %d of sheet ID: %d", version, id)).SetInternal(err)
+		}
+		if sheetVersion == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Version %d not found for sheet ID: %d", version, id))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, sheetVersion); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal sheet version response: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Diffs two versions of the sheet, e.g. ?from=1&to=3. The latest version is used if "to" is omitted.
+	g.GET("/sheet/:id/diff", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		fromVersion, err := strconv.Atoi(c.QueryParam("from"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter from is not a number: %s", c.QueryParam("from"))).SetInternal(err)
+		}
+
+		sheetVersionList, err := s.store.FindSheetVersion(ctx, &api.SheetVersionFind{SheetID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch version list of sheet ID: %d", id)).SetInternal(err)
+		}
+		if len(sheetVersionList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Sheet ID not found: %d", id))
+		}
+		toVersion := sheetVersionList[0].Version
+		if toStr := c.QueryParam("to"); toStr != "" {
+			if toVersion, err = strconv.Atoi(toStr); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter to is not a number: %s", toStr)).SetInternal(err)
+			}
+		}
+		var from, to *api.SheetVersion
+		for _, sheetVersion := range sheetVersionList {
+			if sheetVersion.Version == fromVersion {
+				from = sheetVersion
+			}
+			if sheetVersion.Version == toVersion {
+				to = sheetVersion
+			}
+		}
+		if from == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Version %d not found for sheet ID: %d", fromVersion, id))
+		}
+		if to == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Version %d not found for sheet ID: %d", toVersion, id))
+		}
+
+		diff, err := api.DiffSheetVersion(from, to)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to diff sheet ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, diff); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal sheet diff response: %d", id)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.PATCH("/sheet/:id", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		currentPrincipalID := c.Get(getPrincipalIDContextKey()).(int)
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/jsonapi"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 
@@ -71,31 +72,22 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusBadRequest, "TLS/SSL suite must all be set or not be set")
 			}
 		}
-		db, err := db.Open(
-			ctx,
-			connectionInfo.Engine,
-			db.DriverConfig{},
-			db.ConnectionConfig{
-				Username:  connectionInfo.Username,
-				Password:  password,
-				Host:      connectionInfo.Host,
-				Port:      connectionInfo.Port,
-				TLSConfig: tlsConfig,
-			},
-			db.ConnectionContext{},
-		)
+		checkList := checkConnection(ctx, connectionInfo.Engine, db.ConnectionConfig{
+			Username:  connectionInfo.Username,
+			Password:  password,
+			Host:      connectionInfo.Host,
+			Port:      connectionInfo.Port,
+			TLSConfig: tlsConfig,
+		})
 
-		resultSet := &api.SQLResultSet{}
-		if err != nil {
-			hostPort := connectionInfo.Host
-			if connectionInfo.Port != "" {
-				hostPort += ":" + connectionInfo.Port
-			}
-			resultSet.Error = fmt.Errorf("failed to connect %q for user %q, %w", hostPort, connectionInfo.Username, err).Error()
-		} else {
-			defer db.Close(ctx)
-			if err := db.Ping(ctx); err != nil {
-				resultSet.Error = err.Error()
+		// The error of the first failed check is kept for the clients reading the error only.
+		resultSet := &api.SQLResultSet{
+			CheckList: checkList,
+		}
+		for _, check := range checkList {
+			if check.Status == api.ConnectionCheckError {
+				resultSet.Error = check.Detail
+				break
 			}
 		}
 
@@ -115,6 +107,18 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		if (sync.InstanceID == nil) == (sync.DatabaseID == nil) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Either InstanceID or DatabaseID should be set.")
 		}
+		switch sync.Mode {
+		case "", api.SchemaSyncDeep:
+		case api.SchemaSyncFast:
+			if sync.InstanceID == nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "FAST sync mode only applies to the instance.")
+			}
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid sync mode: %s", sync.Mode))
+		}
+		if sync.ExactRowCount {
+			ctx = db.WithExactRowCount(ctx)
+		}
 
 		var resultSet api.SQLResultSet
 		if sync.InstanceID != nil {
@@ -125,7 +129,11 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			if instance == nil {
 				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", *sync.InstanceID))
 			}
-			if err := s.syncEngineVersionAndSchema(ctx, instance); err != nil {
+			syncInstance := s.syncEngineVersionAndSchema
+			if sync.Mode == api.SchemaSyncFast {
+				syncInstance = s.syncEngineVersionAndDatabaseList
+			}
+			if err := syncInstance(ctx, instance); err != nil {
 				resultSet.Error = err.Error()
 			}
 		}
@@ -176,6 +184,40 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		if instance == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
 		}
+		if !api.GetInstanceCapabilities(instance).ReadOnlyQuery {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("SQL editor query is not supported for instance %q of engine %s", instance.Name, instance.Engine))
+		}
+		if err := s.checkReadOnlyDataSourcePolicy(ctx, instance); err != nil {
+			if common.ErrorCode(err) == common.Invalid {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check data source policy").SetInternal(err)
+		}
+		// The database is nil for the instance-level query, e.g. the MySQL query without the database name.
+		var database *api.Database
+		if exec.DatabaseName != "" {
+			database, err = s.store.GetDatabase(ctx, &api.DatabaseFind{InstanceID: &instance.ID, Name: &exec.DatabaseName})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database `%s` for instance ID: %d", exec.DatabaseName, instance.ID)).SetInternal(err)
+			}
+		}
+		recorded := api.IsSessionRecorded(instance, database)
+		if recorded && exec.SessionID == "" {
+			// Each statement is a session of its own if the client doesn't send the session ID.
+			exec.SessionID = uuid.NewString()
+		}
+		// The statement sent by the user is recorded rather than the one with the injected limit.
+		sentStatement, sentTime := exec.Statement, time.Now()
+		queryPolicy, err := s.getSQLEditorQueryPolicy(ctx, instance, exec.DatabaseName)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch SQL editor query policy").SetInternal(err)
+		}
+		if queryPolicy.AutoLimit > 0 {
+			exec.Statement = injectSQLEditorQueryLimit(instance.Engine, exec.Statement, queryPolicy.AutoLimit)
+			if exec.Limit <= 0 || exec.Limit > queryPolicy.AutoLimit {
+				exec.Limit = queryPolicy.AutoLimit
+			}
+		}
 
 		adviceLevel := advisor.Success
 		adviceList := []advisor.Advice{}
@@ -208,6 +250,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				db.CharacterSet,
 				db.Collation,
 				instance.EnvironmentID,
+				api.GetPolicyTagList(instance, db),
 				exec.Statement,
 				store.NewCatalog(&db.ID, s.store, instance.Engine),
 			)
@@ -216,7 +259,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			}
 
 			if adviceLevel == advisor.Error {
-				if err := s.createSQLEditorQueryActivity(ctx, c, api.ActivityError, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
+				if err := s.createSQLEditorQueryActivity(ctx, c.Get(getPrincipalIDContextKey()).(int), api.ActivityError, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
 					Statement:    exec.Statement,
 					DurationNs:   0,
 					InstanceName: instance.Name,
@@ -226,6 +269,11 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				}); err != nil {
 					return err
 				}
+				if recorded {
+					if err := s.recordSessionStatement(ctx, c.Get(getPrincipalIDContextKey()).(int), instance, database, api.SessionSourceSQLEditor, exec.SessionID, sentStatement, sentTime, "", "the statement violates the SQL review policy"); err != nil {
+						return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record session statement").SetInternal(err)
+					}
+				}
 
 				resultSet := &api.SQLResultSet{
 					AdviceList: adviceList,
@@ -239,22 +287,14 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			}
 		}
 
-		start := time.Now().UnixNano()
-
-		bytes, queryErr := func() ([]byte, error) {
-			driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName)
-			if err != nil {
-				return nil, err
-			}
-			defer driver.Close(ctx)
-
-			rowSet, err := driver.Query(ctx, exec.Statement, exec.Limit)
-			if err != nil {
-				return nil, err
-			}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if !s.acquireSQLEditorQuery(principalID, instance.EnvironmentID, queryPolicy.MaxConcurrentQueryPerUser) {
+			return echo.NewHTTPError(http.StatusTooManyRequests, fmt.Sprintf("You already have %d running queries in environment %q, please retry after they finish", queryPolicy.MaxConcurrentQueryPerUser, instance.Environment.Name))
+		}
+		defer s.releaseSQLEditorQuery(principalID, instance.EnvironmentID)
 
-			return json.Marshal(rowSet)
-		}()
+		start := time.Now().UnixNano()
+		bytes, queryErr := s.runSQLEditorQuery(ctx, instance, exec.DatabaseName, exec.Statement, exec.Limit, queryPolicy)
 
 		if instance.Engine == db.Postgres {
 			stmts, err := parser.Parse(parser.Postgres, parser.Context{}, exec.Statement)
@@ -294,7 +334,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			level = api.ActivityError
 			errMessage = queryErr.Error()
 		}
-		if err := s.createSQLEditorQueryActivity(ctx, c, level, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
+		if err := s.createSQLEditorQueryActivity(ctx, principalID, level, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
 			Statement:    exec.Statement,
 			DurationNs:   time.Now().UnixNano() - start,
 			InstanceName: instance.Name,
@@ -304,6 +344,15 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}); err != nil {
 			return err
 		}
+		if recorded {
+			data := ""
+			if queryErr == nil {
+				data = string(bytes)
+			}
+			if err := s.recordSessionStatement(ctx, principalID, instance, database, api.SessionSourceSQLEditor, exec.SessionID, sentStatement, sentTime, data, errMessage); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record session statement").SetInternal(err)
+			}
+		}
 
 		resultSet := &api.SQLResultSet{AdviceList: adviceList}
 		if queryErr == nil {
@@ -336,9 +385,43 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	g.POST("/sql/format", func(c echo.Context) error {
+		format := &api.SQLFormat{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, format); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql format request").SetInternal(err)
+		}
+
+		engineType, ok := getParserEngineType(format.Engine)
+		if !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Formatting the statement of %s is not supported", format.Engine))
+		}
+		statement, err := parser.FormatStatement(engineType, format.Statement)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to format the statement: %v", err))
+		}
+		canonical, err := parser.CanonicalizeStatement(engineType, format.Statement)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to canonicalize the statement: %v", err))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.SQLFormatResult{
+			Statement: statement,
+			Canonical: canonical,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql format response").SetInternal(err)
+		}
+		return nil
+	})
 }
 
 func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.Instance) error {
+	if instance.AgentID != nil {
+		// The instance is unreachable from the server, its agent will report the schema back.
+		return s.enqueueAgentSync(ctx, instance)
+	}
+
 	driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, "")
 	if err != nil {
 		return err
@@ -349,6 +432,7 @@ func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.I
 	if err != nil {
 		return err
 	}
+	s.syncInstanceCertificate(ctx, instance)
 
 	var errorList []string
 	for _, databaseName := range databaseList {
@@ -364,6 +448,27 @@ func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.I
 	return nil
 }
 
+// syncEngineVersionAndDatabaseList syncs the instance metadata and the database list without the schema of each database,
+// which is much faster than syncEngineVersionAndSchema on the instance with many databases.
+func (s *Server) syncEngineVersionAndDatabaseList(ctx context.Context, instance *api.Instance) error {
+	if instance.AgentID != nil {
+		// The agent always syncs deeply.
+		return s.enqueueAgentSync(ctx, instance)
+	}
+
+	driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, "")
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	if _, err := s.syncInstanceSchema(ctx, instance, driver); err != nil {
+		return err
+	}
+	s.syncInstanceCertificate(ctx, instance)
+	return nil
+}
+
 // syncInstanceSchema syncs the instance and all database metadata first without diving into the deep structure of each database.
 func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance, driver db.Driver) ([]string, error) {
 	// Sync instance metadata.
@@ -372,6 +477,118 @@ func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 		return nil, err
 	}
 
+	return s.applyInstanceMeta(ctx, instance, instanceMeta, func(databaseName string) (*db.Schema, error) {
+		return driver.SyncDBSchema(ctx, databaseName)
+	})
+}
+
+// recordInstanceParameterList records the synced parameters whose values change since the last sync.
+func (s *Server) recordInstanceParameterList(ctx context.Context, instance *api.Instance, parameterList []db.Parameter) error {
+	latestList, err := s.store.FindInstanceParameter(ctx, &api.InstanceParameterFind{
+		InstanceID: &instance.ID,
+		Latest:     true,
+	})
+	if err != nil {
+		return err
+	}
+	latestMap := make(map[string]string)
+	for _, parameter := range latestList {
+		latestMap[parameter.Name] = parameter.Value
+	}
+	for _, parameter := range parameterList {
+		if value, ok := latestMap[parameter.Name]; ok && value == parameter.Value {
+			continue
+		}
+		if _, err := s.store.CreateInstanceParameter(ctx, &api.InstanceParameterCreate{
+			CreatorID:  api.SystemBotID,
+			InstanceID: instance.ID,
+			Name:       parameter.Name,
+			Value:      parameter.Value,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyInstanceMeta stores the synced instance metadata and returns the database names in the instance.
+// getSchema returns the schema of a newly found database, which is used to detect the database renamed outside Bytebase.
+// syncInstanceCertificate captures the TLS certificate presented by the instance,
+// and raises the anomaly if it's expired or about to expire within the days of the workspace setting.
+// The failures are only logged, because the instance may not support TLS at all.
+func (s *Server) syncInstanceCertificate(ctx context.Context, instance *api.Instance) {
+	cert, err := util.GetServerCertificate(ctx, instance.Engine, instance.Host, instance.Port)
+	if err != nil {
+		log.Debug("Failed to get the instance certificate",
+			zap.String("instance", instance.Name),
+			zap.Error(err))
+		return
+	}
+	setting, err := s.getCertificateExpirySetting(ctx)
+	if err != nil {
+		log.Error("Failed to get certificate expiry setting", zap.Error(err))
+		return
+	}
+
+	if cert == nil || !setting.IsExpiring(cert.NotAfter.Unix(), time.Now().Unix()) {
+		err := s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			InstanceID: &instance.ID,
+			Type:       api.AnomalyInstanceCertificateExpiry,
+		})
+		if err != nil && common.ErrorCode(err) != common.NotFound {
+			log.Error("Failed to close anomaly",
+				zap.String("instance", instance.Name),
+				zap.String("type", string(api.AnomalyInstanceCertificateExpiry)),
+				zap.Error(err))
+		}
+		return
+	}
+
+	payload, err := json.Marshal(api.AnomalyInstanceCertificateExpiryPayload{
+		Subject:  cert.Subject.String(),
+		Issuer:   cert.Issuer.String(),
+		ExpireTs: cert.NotAfter.Unix(),
+	})
+	if err != nil {
+		log.Error("Failed to marshal anomaly payload",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(api.AnomalyInstanceCertificateExpiry)),
+			zap.Error(err))
+		return
+	}
+	if _, err := s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		Type:       api.AnomalyInstanceCertificateExpiry,
+		Payload:    string(payload),
+	}); err != nil {
+		log.Error("Failed to create anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(api.AnomalyInstanceCertificateExpiry)),
+			zap.Error(err))
+	}
+}
+
+// getCertificateExpirySetting returns the workspace certificate expiry setting, or the default one if it's not set.
+func (s *Server) getCertificateExpirySetting(ctx context.Context) (*api.CertificateExpirySetting, error) {
+	name := api.SettingWorkspaceCertificateExpiry
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+	setting := &api.CertificateExpirySetting{
+		AlertDays: api.DefaultCertificateExpiryAlertDays,
+	}
+	if len(settingList) == 0 {
+		return setting, nil
+	}
+	if err := json.Unmarshal([]byte(settingList[0].Value), setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal certificate expiry setting %q, error: %w", settingList[0].Value, err)
+	}
+	return setting, nil
+}
+
+func (s *Server) applyInstanceMeta(ctx context.Context, instance *api.Instance, instanceMeta *db.InstanceMeta, getSchema func(databaseName string) (*db.Schema, error)) ([]string, error) {
 	// Underlying version may change due to upgrade, however it's a rare event, so we only update if it actually differs
 	// to avoid changing the updated_ts.
 	if instanceMeta.Version != instance.EngineVersion {
@@ -385,6 +602,32 @@ func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 		}
 		instance.EngineVersion = instanceMeta.Version
 	}
+	// The dialect gates the features along with the engine, e.g. Redshift added as Postgres can't be backed up.
+	if instanceMeta.Dialect != instance.Dialect {
+		if _, err := s.store.PatchInstance(ctx, &api.InstancePatch{
+			ID:        instance.ID,
+			UpdaterID: api.SystemBotID,
+			Dialect:   &instanceMeta.Dialect,
+		}); err != nil {
+			return nil, err
+		}
+		instance.Dialect = instanceMeta.Dialect
+	}
+	// The engine detected by the driver takes over, e.g. MariaDB added as MySQL, so the engine specific features apply to it.
+	if instanceMeta.Engine != "" && instanceMeta.Engine != instance.Engine {
+		if _, err := s.store.PatchInstance(ctx, &api.InstancePatch{
+			ID:        instance.ID,
+			UpdaterID: api.SystemBotID,
+			Engine:    &instanceMeta.Engine,
+		}); err != nil {
+			return nil, err
+		}
+		log.Info("Detected the instance engine",
+			zap.String("instance", instance.Name),
+			zap.String("from", string(instance.Engine)),
+			zap.String("to", string(instanceMeta.Engine)))
+		instance.Engine = instanceMeta.Engine
+	}
 
 	instanceUserList, err := s.store.FindInstanceUserByInstanceID(ctx, instance.ID)
 	if err != nil {
@@ -426,9 +669,15 @@ func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 		}
 	}
 
+	if err := s.recordInstanceParameterList(ctx, instance, instanceMeta.ParameterList); err != nil {
+		return nil, fmt.Errorf("failed to sync parameters for instance: %s. Error %w", instance.Name, err)
+	}
+
 	// Compare the stored db info with the just synced db schema.
 	// Case 1: If item appears in both stored db info and the synced db metadata, then it's a no-op. We rely on syncDatabaseSchema() later to sync its details.
 	// Case 2: If item only appears in the synced schema and not in the stored db, then we CREATE the database record in the stored db.
+	//         Unless it has the same structure as exactly one vanished database, then the database is renamed and we RENAME the record
+	//         to keep its history and associations.
 	// Case 3: Conversely, if item only appears in the stored db, but not in the synced schema, then we MARK the record as NOT_FOUND.
 	//   	   We don't delete the entry because:
 	//   	   1. This entry has already been associated with other entities, we can't simply delete it.
@@ -440,6 +689,10 @@ func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 	if err != nil {
 		return nil, fmt.Errorf("failed to sync database for instance: %s. Failed to find database list. Error %w", instance.Name, err)
 	}
+	var assignmentRuleList []*api.DatabaseAssignmentRule
+	assignmentRuleLoaded := false
+	// vanishedHashMap caches the structure hash of the stored databases not in the synced db metadata.
+	var vanishedHashMap map[*api.Database]string
 	for _, databaseMetadata := range instanceMeta.DatabaseList {
 		databaseName := databaseMetadata.Name
 
@@ -456,9 +709,29 @@ func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 			continue
 		}
 		// Case 2, only appear in the synced db schema.
+		if vanishedHashMap == nil {
+			vanishedHashMap, err = s.getVanishedDatabaseStructureHashMap(ctx, dbList, instanceMeta)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sync database for instance: %s. Failed to get vanished database structure. Error %w", instance.Name, err)
+			}
+		}
+		renamed, err := s.renameDatabaseIfMatched(ctx, instance, vanishedHashMap, databaseName, getSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sync database for instance: %s. Failed to rename database: %s. Error %w", instance.Name, databaseName, err)
+		}
+		if renamed {
+			continue
+		}
+		if !assignmentRuleLoaded {
+			assignmentRuleList, err = s.findActiveDatabaseAssignmentRuleList(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sync database for instance: %s. Failed to find database assignment rule list. Error %w", instance.Name, err)
+			}
+			assignmentRuleLoaded = true
+		}
 		databaseCreate := &api.DatabaseCreate{
 			CreatorID:     api.SystemBotID,
-			ProjectID:     api.DefaultProjectID,
+			ProjectID:     getAssignedProjectID(assignmentRuleList, instance, databaseName),
 			InstanceID:    instance.ID,
 			EnvironmentID: instance.EnvironmentID,
 			Name:          databaseName,
@@ -492,6 +765,10 @@ func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 				LastSuccessfulSyncTs: &ts,
 				// SchemaVersion will not be over-written.
 			}
+			// Only record the time the database is first found missing, so that it can be purged by the policy.
+			if db.SyncStatus != api.NotFound {
+				databasePatch.NotFoundTs = &ts
+			}
 			database, err := s.store.PatchDatabase(ctx, databasePatch)
 			if err != nil {
 				if common.ErrorCode(err) == common.NotFound {
@@ -510,7 +787,116 @@ func (s *Server) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 	return databaseList, nil
 }
 
+// getVanishedDatabaseStructureHashMap returns the structure hash of the stored databases not in the synced db metadata.
+func (s *Server) getVanishedDatabaseStructureHashMap(ctx context.Context, dbList []*api.Database, instanceMeta *db.InstanceMeta) (map[*api.Database]string, error) {
+	vanishedHashMap := make(map[*api.Database]string)
+	for _, database := range dbList {
+		found := false
+		for _, databaseMetadata := range instanceMeta.DatabaseList {
+			if database.Name == databaseMetadata.Name {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		hash, err := s.store.GetDatabaseStructureHash(ctx, database.ID)
+		if err != nil {
+			return nil, err
+		}
+		vanishedHashMap[database] = hash
+	}
+	return vanishedHashMap, nil
+}
+
+// renameDatabaseIfMatched renames the vanished database to databaseName if it's the only one having the same structure as the new database.
+// The renamed database is removed from vanishedHashMap, and its name is updated in place so it won't be marked as NOT_FOUND.
+func (s *Server) renameDatabaseIfMatched(ctx context.Context, instance *api.Instance, vanishedHashMap map[*api.Database]string, databaseName string, getSchema func(databaseName string) (*db.Schema, error)) (bool, error) {
+	if len(vanishedHashMap) == 0 {
+		return false, nil
+	}
+	schema, err := getSchema(databaseName)
+	if err != nil {
+		// Fall back to treat it as a new database.
+		log.Warn("Failed to get the schema for rename detection",
+			zap.String("instance", instance.Name),
+			zap.String("database", databaseName),
+			zap.Error(err))
+		return false, nil
+	}
+	hash := store.GetSchemaStructureHash(schema)
+	if hash == "" {
+		return false, nil
+	}
+	var matchedDb *api.Database
+	for database, vanishedHash := range vanishedHashMap {
+		if vanishedHash != hash {
+			continue
+		}
+		if matchedDb != nil {
+			// Ambiguous, more than one vanished database has the same structure.
+			return false, nil
+		}
+		matchedDb = database
+	}
+	if matchedDb == nil {
+		return false, nil
+	}
+
+	oldName := matchedDb.Name
+	syncStatus := api.OK
+	rowStatus := api.Normal
+	notFoundTs := int64(0)
+	database, err := s.store.PatchDatabase(ctx, &api.DatabasePatch{
+		ID:         matchedDb.ID,
+		UpdaterID:  api.SystemBotID,
+		Name:       &databaseName,
+		SyncStatus: &syncStatus,
+		RowStatus:  &rowStatus,
+		NotFoundTs: &notFoundTs,
+	})
+	if err != nil {
+		return false, err
+	}
+	delete(vanishedHashMap, matchedDb)
+	matchedDb.Name = databaseName
+	log.Info("Detected renamed database",
+		zap.String("instance", instance.Name),
+		zap.String("oldName", oldName),
+		zap.String("newName", databaseName))
+
+	bytes, err := json.Marshal(api.ActivityProjectDatabaseRenamePayload{
+		DatabaseID:   database.ID,
+		OldName:      oldName,
+		NewName:      databaseName,
+		InstanceName: instance.Name,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to construct activity payload, error: %w", err)
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: database.ProjectID,
+		Type:        api.ActivityProjectDatabaseRename,
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("Renamed database %q to %q in instance %q outside Bytebase.", oldName, databaseName, instance.Name),
+		Payload:     string(bytes),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		log.Warn("Failed to create activity for the renamed database",
+			zap.String("instance", instance.Name),
+			zap.String("database", databaseName),
+			zap.Error(err))
+	}
+	return true, nil
+}
+
 func (s *Server) syncDatabaseSchema(ctx context.Context, instance *api.Instance, databaseName string) error {
+	if instance.AgentID != nil {
+		return s.enqueueAgentSync(ctx, instance)
+	}
+
 	driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, "")
 	if err != nil {
 		return err
@@ -539,6 +925,15 @@ func (s *Server) syncDatabaseSchema(ctx context.Context, instance *api.Instance,
 		return err
 	}
 
+	if err := s.applyDatabaseSchema(ctx, instance, matchedDb, schema, schemaVersion); err != nil {
+		return err
+	}
+	s.captureSchemaSnapshot(ctx, instance, driver, databaseName)
+	return nil
+}
+
+// applyDatabaseSchema stores the synced database schema, matchedDb is nil if the database hasn't been recorded yet.
+func (s *Server) applyDatabaseSchema(ctx context.Context, instance *api.Instance, matchedDb *api.Database, schema *db.Schema, schemaVersion string) error {
 	var database *api.Database
 	if matchedDb != nil {
 		syncStatus := api.OK
@@ -548,7 +943,17 @@ func (s *Server) syncDatabaseSchema(ctx context.Context, instance *api.Instance,
 			UpdaterID:            api.SystemBotID,
 			SyncStatus:           &syncStatus,
 			LastSuccessfulSyncTs: &ts,
-			SchemaVersion:        &schemaVersion,
+		}
+		// Keep the schema version if the migration history is missing, e.g. the database is renamed outside Bytebase.
+		if schemaVersion != "" {
+			databasePatch.SchemaVersion = &schemaVersion
+		}
+		// The database shows up again, restore it if it has been purged.
+		if matchedDb.SyncStatus == api.NotFound || matchedDb.RowStatus == api.Archived {
+			rowStatus := api.Normal
+			notFoundTs := int64(0)
+			databasePatch.RowStatus = &rowStatus
+			databasePatch.NotFoundTs = &notFoundTs
 		}
 		dbPatched, err := s.store.PatchDatabase(ctx, databasePatch)
 		if err != nil {
@@ -559,9 +964,13 @@ func (s *Server) syncDatabaseSchema(ctx context.Context, instance *api.Instance,
 		}
 		database = dbPatched
 	} else {
+		assignmentRuleList, err := s.findActiveDatabaseAssignmentRuleList(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to sync database for instance: %s. Failed to find database assignment rule list. Error %w", instance.Name, err)
+		}
 		databaseCreate := &api.DatabaseCreate{
 			CreatorID:     api.SystemBotID,
-			ProjectID:     api.DefaultProjectID,
+			ProjectID:     getAssignedProjectID(assignmentRuleList, instance, schema.Name),
 			InstanceID:    instance.ID,
 			EnvironmentID: instance.EnvironmentID,
 			Name:          schema.Name,
@@ -584,7 +993,10 @@ func (s *Server) syncDatabaseSchema(ctx context.Context, instance *api.Instance,
 	if err := syncViewSchema(ctx, s.store, database, schema); err != nil {
 		return err
 	}
-	return syncDBExtensionSchema(ctx, s.store, database, schema)
+	if err := syncDBExtensionSchema(ctx, s.store, database, schema); err != nil {
+		return err
+	}
+	return syncDBFunctionSchema(ctx, s.store, database, schema)
 }
 
 func syncTableSchema(ctx context.Context, store *store.Store, database *api.Database, schema *db.Schema) error {
@@ -599,6 +1011,10 @@ func syncDBExtensionSchema(ctx context.Context, store *store.Store, database *ap
 	return store.SetDBExtensionList(ctx, schema, database.ID)
 }
 
+func syncDBFunctionSchema(ctx context.Context, store *store.Store, database *api.Database, schema *db.Schema) error {
+	return store.SetDBFunctionList(ctx, schema, database.ID)
+}
+
 func getLatestSchemaVersion(ctx context.Context, driver db.Driver, databaseName string) (string, error) {
 	// TODO(d): support semantic versioning.
 	limit := 1
@@ -616,6 +1032,73 @@ func getLatestSchemaVersion(ctx context.Context, driver db.Driver, databaseName
 	return schemaVersion, nil
 }
 
+// getSQLEditorQueryPolicy returns the SQL editor query policy of the database, the tags of the database apply if it exists.
+func (s *Server) getSQLEditorQueryPolicy(ctx context.Context, instance *api.Instance, databaseName string) (*api.SQLEditorQueryPolicy, error) {
+	var database *api.Database
+	if databaseName != "" {
+		var err error
+		database, err = s.store.GetDatabase(ctx, &api.DatabaseFind{InstanceID: &instance.ID, Name: &databaseName})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.store.GetSQLEditorQueryPolicy(ctx, instance.EnvironmentID, api.GetPolicyTagList(instance, database))
+}
+
+// acquireSQLEditorQuery counts a running query of the user in the environment, it returns false if the user already runs
+// the maximum number of queries. The maximum 0 means no limit.
+func (s *Server) acquireSQLEditorQuery(principalID, environmentID, max int) bool {
+	s.sqlEditorQueryCountMu.Lock()
+	defer s.sqlEditorQueryCountMu.Unlock()
+
+	if s.sqlEditorQueryCount == nil {
+		s.sqlEditorQueryCount = make(map[string]int)
+	}
+	key := fmt.Sprintf("%d/%d", principalID, environmentID)
+	if max > 0 && s.sqlEditorQueryCount[key] >= max {
+		return false
+	}
+	s.sqlEditorQueryCount[key]++
+	return true
+}
+
+// releaseSQLEditorQuery uncounts a finished query of the user in the environment.
+func (s *Server) releaseSQLEditorQuery(principalID, environmentID int) {
+	s.sqlEditorQueryCountMu.Lock()
+	defer s.sqlEditorQueryCountMu.Unlock()
+
+	key := fmt.Sprintf("%d/%d", principalID, environmentID)
+	s.sqlEditorQueryCount[key]--
+	if s.sqlEditorQueryCount[key] <= 0 {
+		delete(s.sqlEditorQueryCount, key)
+	}
+}
+
+var (
+	selectStatementRegexp = regexp.MustCompile(`(?is)^SELECT\s`)
+	// limitClauseRegexp matches the clauses limiting the rows, including the ones in the subqueries.
+	limitClauseRegexp = regexp.MustCompile(`(?i)\b(LIMIT|TOP|FETCH\s+(FIRST|NEXT))\b`)
+	// lockingClauseRegexp matches the locking clauses, which must follow the LIMIT clause.
+	lockingClauseRegexp = regexp.MustCompile(`(?i)\bFOR\s+(UPDATE|SHARE|NO\s+KEY\s+UPDATE|KEY\s+SHARE)\b|\bLOCK\s+IN\s+SHARE\s+MODE\b`)
+)
+
+// injectSQLEditorQueryLimit appends the LIMIT clause to the SELECT statement without one, so that the database stops scanning
+// after the rows returned to the SQL editor. The statements of the engines without the LIMIT clause, the EXPLAIN statements,
+// and the statements locking the rows are returned as is.
+func injectSQLEditorQueryLimit(engine db.Type, statement string, limit int) string {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.Postgres, db.ClickHouse, db.Snowflake, db.SQLite:
+	default:
+		return statement
+	}
+	stmt := strings.TrimRight(strings.TrimSpace(statement), "; \t\r\n")
+	if !selectStatementRegexp.MatchString(stmt) || limitClauseRegexp.MatchString(stmt) || lockingClauseRegexp.MatchString(stmt) {
+		return statement
+	}
+	// The clause is on a new line in case the statement ends with a line comment.
+	return fmt.Sprintf("%s\nLIMIT %d;", stmt, limit)
+}
+
 func validateSQLSelectStatement(sqlStatement string) bool {
 	// Check if the query has only one statement.
 	count := 0
@@ -641,7 +1124,123 @@ func validateSQLSelectStatement(sqlStatement string) bool {
 	return false
 }
 
-func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Context, level api.ActivityLevel, containerID int, payload api.ActivitySQLEditorQueryPayload) error {
+// runSQLEditorQuery runs the read-only query on the database and returns the JSON encoded row set, in which the columns
+// of the data masking policy are masked.
+// The query is canceled after the maximum execution time of the SQL editor query policy.
+func (s *Server) runSQLEditorQuery(ctx context.Context, instance *api.Instance, databaseName, statement string, limit int, queryPolicy *api.SQLEditorQueryPolicy) ([]byte, error) {
+	queryCtx, cancelQuery := context.WithCancel(ctx)
+	if queryPolicy.MaxExecutionSeconds > 0 {
+		queryCtx, cancelQuery = context.WithTimeout(ctx, time.Duration(queryPolicy.MaxExecutionSeconds)*time.Second)
+	}
+	defer cancelQuery()
+	bytes, err := func() ([]byte, error) {
+		ctx := queryCtx
+		if instance.AgentID != nil {
+			ctx, cancel := context.WithTimeout(ctx, agentQueryTimeout)
+			defer cancel()
+			result, err := s.runAgentTask(ctx, instance, api.AgentTaskDatabaseQuery, &api.AgentTaskPayload{
+				Database:  databaseName,
+				Statement: statement,
+				Limit:     limit,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if result.Query == nil {
+				return nil, fmt.Errorf("missing query result from the agent")
+			}
+			return json.Marshal(result.Query.RowSet)
+		}
+
+		driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, databaseName)
+		if err != nil {
+			return nil, err
+		}
+		defer driver.Close(ctx)
+
+		rowSet, err := driver.Query(ctx, statement, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(rowSet)
+	}()
+	if err != nil && queryCtx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("query exceeded the maximum execution time of %d seconds in environment %q", queryPolicy.MaxExecutionSeconds, instance.Environment.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	maskingPolicy, err := s.getDataMaskingPolicy(ctx, instance, databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data masking policy, error: %w", err)
+	}
+	return maskRowSet(bytes, maskingPolicy)
+}
+
+// getDataMaskingPolicy returns the data masking policy of the database, the tags of the database apply if it exists.
+func (s *Server) getDataMaskingPolicy(ctx context.Context, instance *api.Instance, databaseName string) (*api.DataMaskingPolicy, error) {
+	var database *api.Database
+	if databaseName != "" {
+		var err error
+		database, err = s.store.GetDatabase(ctx, &api.DatabaseFind{InstanceID: &instance.ID, Name: &databaseName})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.store.GetDataMaskingPolicy(ctx, instance.EnvironmentID, api.GetPolicyTagList(instance, database))
+}
+
+// maskRowSet replaces the non-NULL values of the masked columns in the JSON encoded row set, which is
+// [columnNames, columnTypeNames, rows] as returned by the drivers. The row set is returned as is if no column is masked.
+func maskRowSet(data []byte, policy *api.DataMaskingPolicy) ([]byte, error) {
+	if len(policy.ColumnList) == 0 {
+		return data, nil
+	}
+	var rowSet []json.RawMessage
+	if err := json.Unmarshal(data, &rowSet); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the row set, error: %w", err)
+	}
+	if len(rowSet) < 3 {
+		return data, nil
+	}
+	var columnNames []string
+	if err := json.Unmarshal(rowSet[0], &columnNames); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the column names, error: %w", err)
+	}
+	var maskedIndexes []int
+	for i, name := range columnNames {
+		if policy.IsMasked(name) {
+			maskedIndexes = append(maskedIndexes, i)
+		}
+	}
+	if len(maskedIndexes) == 0 {
+		return data, nil
+	}
+
+	var rows [][]json.RawMessage
+	if err := json.Unmarshal(rowSet[2], &rows); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the rows, error: %w", err)
+	}
+	masked, err := json.Marshal(api.DataMaskingPolicyMaskedValue)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for _, i := range maskedIndexes {
+			if i < len(row) && string(row[i]) != "null" {
+				row[i] = masked
+			}
+		}
+	}
+	if rowSet[2], err = json.Marshal(rows); err != nil {
+		return nil, err
+	}
+	return json.Marshal(rowSet)
+}
+
+func (s *Server) createSQLEditorQueryActivity(ctx context.Context, principalID int, level api.ActivityLevel, containerID int, payload api.ActivitySQLEditorQueryPayload) error {
 	activityBytes, err := json.Marshal(payload)
 	if err != nil {
 		log.Warn("Failed to marshal activity after executing sql statement",
@@ -653,7 +1252,7 @@ func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Contex
 	}
 
 	activityCreate := &api.ActivityCreate{
-		CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
+		CreatorID:   principalID,
 		Type:        api.ActivitySQLEditorQuery,
 		ContainerID: containerID,
 		Level:       level,
@@ -679,11 +1278,12 @@ func (s *Server) sqlCheck(
 	dbCharacterSet string,
 	dbCollation string,
 	environmentID int,
+	tagList []string,
 	statement string,
 	catalog catalog.Catalog,
 ) (advisor.Status, []advisor.Advice, error) {
 	var adviceList []advisor.Advice
-	policy, err := s.store.GetNormalSQLReviewPolicy(ctx, &api.PolicyFind{EnvironmentID: &environmentID})
+	policy, err := s.store.GetNormalSQLReviewPolicy(ctx, &api.PolicyFind{EnvironmentID: &environmentID, TagList: tagList})
 	if err != nil {
 		if e, ok := err.(*common.Error); ok && e.Code == common.NotFound {
 			adviceList = []advisor.Advice{
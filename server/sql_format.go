@@ -0,0 +1,40 @@
+package server
+
+import (
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+// getParserEngineType returns the parser engine type of the database engine, false if the engine has no parser support.
+func getParserEngineType(engine db.Type) (parser.EngineType, bool) {
+	switch engine {
+	case db.MySQL, db.MariaDB:
+		return parser.MySQL, true
+	case db.TiDB:
+		return parser.TiDB, true
+	case db.Postgres:
+		return parser.Postgres, true
+	}
+	return "", false
+}
+
+// isStatementEqual returns true if the statements only differ in comments and whitespace.
+// The statements of the engines without parser support, or failing to be tokenized, are compared as they are.
+func isStatementEqual(engine db.Type, a, b string) bool {
+	if a == b {
+		return true
+	}
+	engineType, ok := getParserEngineType(engine)
+	if !ok {
+		return false
+	}
+	canonicalA, err := parser.CanonicalizeStatement(engineType, a)
+	if err != nil {
+		return false
+	}
+	canonicalB, err := parser.CanonicalizeStatement(engineType, b)
+	if err != nil {
+		return false
+	}
+	return canonicalA == canonicalB
+}
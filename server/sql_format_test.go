@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestIsStatementEqual(t *testing.T) {
+	tests := []struct {
+		engine db.Type
+		a      string
+		b      string
+		want   bool
+	}{
+		{engine: db.MySQL, a: "ALTER TABLE t ADD c INT;", b: "-- Add column\nALTER TABLE t\n  ADD c INT", want: true},
+		{engine: db.TiDB, a: "ALTER TABLE t ADD c INT;", b: "ALTER TABLE t ADD d INT;", want: false},
+		{engine: db.Postgres, a: "CREATE INDEX idx ON t (c);", b: "CREATE INDEX idx ON t(c) /* index */;", want: true},
+		// The statements failing to be tokenized are compared as they are.
+		{engine: db.Postgres, a: "SELECT 'a", b: "SELECT  'a", want: false},
+		// The statements of the engines without parser support are compared as they are.
+		{engine: db.Snowflake, a: "SELECT 1", b: "SELECT  1", want: false},
+		{engine: db.Snowflake, a: "SELECT 1", b: "SELECT 1", want: true},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, isStatementEqual(test.engine, test.a, test.b), "%q vs %q", test.a, test.b)
+	}
+}
@@ -2,6 +2,11 @@ package server
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestValidateSQLSelectStatement(t *testing.T) {
@@ -94,3 +99,51 @@ func TestValidateSQLSelectStatement(t *testing.T) {
 		}
 	}
 }
+
+func TestInjectSQLEditorQueryLimit(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		want      string
+	}{
+		{db.MySQL, "SELECT * FROM t", "SELECT * FROM t\nLIMIT 100;"},
+		{db.Postgres, "  select * from t;  ", "select * from t\nLIMIT 100;"},
+		{db.MySQL, "SELECT * FROM t -- all rows", "SELECT * FROM t -- all rows\nLIMIT 100;"},
+		{db.MySQL, "SELECT * FROM t LIMIT 10", "SELECT * FROM t LIMIT 10"},
+		{db.Postgres, "SELECT * FROM t FETCH FIRST 10 ROWS ONLY", "SELECT * FROM t FETCH FIRST 10 ROWS ONLY"},
+		{db.Postgres, "SELECT * FROM t FOR UPDATE", "SELECT * FROM t FOR UPDATE"},
+		{db.MySQL, "EXPLAIN SELECT * FROM t", "EXPLAIN SELECT * FROM t"},
+		{db.MSSQL, "SELECT * FROM t", "SELECT * FROM t"},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, injectSQLEditorQueryLimit(test.engine, test.statement, 100), test.statement)
+	}
+}
+
+func TestAcquireSQLEditorQuery(t *testing.T) {
+	s := &Server{}
+	require.True(t, s.acquireSQLEditorQuery(101, 1, 2))
+	require.True(t, s.acquireSQLEditorQuery(101, 1, 2))
+	require.False(t, s.acquireSQLEditorQuery(101, 1, 2))
+	// The limit is per user and per environment.
+	require.True(t, s.acquireSQLEditorQuery(102, 1, 2))
+	require.True(t, s.acquireSQLEditorQuery(101, 2, 2))
+
+	s.releaseSQLEditorQuery(101, 1)
+	require.True(t, s.acquireSQLEditorQuery(101, 1, 2))
+	// No limit.
+	require.True(t, s.acquireSQLEditorQuery(101, 1, 0))
+}
+
+func TestMaskRowSet(t *testing.T) {
+	data := `[["id","Email"],["INT","TEXT"],[[1,"alice@example.com"],[2,null]]]`
+
+	got, err := maskRowSet([]byte(data), &api.DataMaskingPolicy{ColumnList: []string{"email"}})
+	require.NoError(t, err)
+	require.JSONEq(t, `[["id","Email"],["INT","TEXT"],[[1,"******"],[2,null]]]`, string(got))
+
+	got, err = maskRowSet([]byte(data), &api.DataMaskingPolicy{ColumnList: []string{"ssn"}})
+	require.NoError(t, err)
+	require.Equal(t, data, string(got))
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func (s *Server) registerStageRoutes(g *echo.Group) {
@@ -64,4 +67,376 @@ func (s *Server) registerStageRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	// This function promotes the statements applied in the stage to the next stage, so that the automation can
+	// promote the changes between environments after the external smoke tests pass.
+	g.POST("/pipeline/:pipelineID/stage/:stageID/promote", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		stageID, err := strconv.Atoi(c.Param("stageID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Stage ID is not a number: %s", c.Param("stageID"))).SetInternal(err)
+		}
+		pipelineID, err := strconv.Atoi(c.Param("pipelineID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Pipeline ID is not a number: %s", c.Param("pipelineID"))).SetInternal(err)
+		}
+
+		currentPrincipalID := c.Get(getPrincipalIDContextKey()).(int)
+		stagePromote := &api.StagePromote{
+			ID:        stageID,
+			UpdaterID: currentPrincipalID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, stagePromote); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed promote stage request").SetInternal(err)
+		}
+
+		if err := s.validateIssueAssignee(ctx, currentPrincipalID, pipelineID); err != nil {
+			return err
+		}
+		issue, err := s.store.GetIssueByPipelineID(ctx, pipelineID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find issue").SetInternal(err)
+		}
+		if issue.Status != api.IssueOpen {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Can not promote the stage of issue %q in %s status", issue.Name, issue.Status))
+		}
+		pipeline, err := s.store.GetPipelineByID(ctx, pipelineID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch pipeline ID: %v", pipelineID)).SetInternal(err)
+		}
+		if pipeline == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Pipeline ID not found: %d", pipelineID))
+		}
+
+		nextStage, httpErr := s.promoteStage(ctx, issue, pipeline, stagePromote)
+		if httpErr != nil {
+			return httpErr
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, nextStage); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal promote stage response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// taskPromotion promotes the statement applied by a done task of the stage to the task of the next stage.
+type taskPromotion struct {
+	sourceTask    *api.Task
+	migrationType db.MigrationType
+	// detail is the statement applied by the source task.
+	detail *api.UpdateSchemaDetail
+	// targetTask is nil if the next stage doesn't exist yet.
+	targetTask *api.Task
+}
+
+// promoteStage promotes the statements applied in the stage to the next stage and returns the next stage.
+// The tasks of the next stage take the applied statements, and the next stage is created in the next environment if it doesn't exist.
+func (s *Server) promoteStage(ctx context.Context, issue *api.Issue, pipeline *api.Pipeline, stagePromote *api.StagePromote) (*api.Stage, *echo.HTTPError) {
+	index := -1
+	for i, stage := range pipeline.StageList {
+		if stage.ID == stagePromote.ID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Stage ID %d not found in pipeline ID %d", stagePromote.ID, pipeline.ID))
+	}
+	stage := pipeline.StageList[index]
+
+	var nextStageID int
+	if index+1 < len(pipeline.StageList) {
+		nextStage := pipeline.StageList[index+1]
+		nextStageID = nextStage.ID
+		promotionList, err := matchTaskPromotionList(stage.TaskList, nextStage.TaskList)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to promote stage %q: %v", stage.Name, err))
+		}
+		for _, promotion := range promotionList {
+			if httpErr := s.promoteTask(ctx, issue, promotion, stagePromote.UpdaterID); httpErr != nil {
+				return nil, httpErr
+			}
+		}
+	} else {
+		nextStage, httpErr := s.createPromotedStage(ctx, issue, stage, stagePromote.UpdaterID)
+		if httpErr != nil {
+			return nil, httpErr
+		}
+		nextStageID = nextStage.ID
+	}
+
+	if stagePromote.Approve {
+		nextStage, httpErr := s.getStageByID(ctx, nextStageID)
+		if httpErr != nil {
+			return nil, httpErr
+		}
+		for _, task := range nextStage.TaskList {
+			if task.Status != api.TaskPendingApproval {
+				continue
+			}
+			if _, err := s.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
+				ID:        task.ID,
+				UpdaterID: stagePromote.UpdaterID,
+				Status:    api.TaskPending,
+			}); err != nil {
+				if common.ErrorCode(err) == common.Invalid {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessage(err))
+				}
+				return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to approve task %q", task.Name)).SetInternal(err)
+			}
+		}
+	}
+	return s.getStageByID(ctx, nextStageID)
+}
+
+func (s *Server) getStageByID(ctx context.Context, id int) (*api.Stage, *echo.HTTPError) {
+	stages, err := s.store.FindStage(ctx, &api.StageFind{ID: &id})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch stage ID: %v", id)).SetInternal(err)
+	}
+	if len(stages) == 0 {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Stage ID not found: %d", id))
+	}
+	return stages[0], nil
+}
+
+// promoteTask updates the task of the next stage with the statement applied by the source task and records the provenance.
+func (s *Server) promoteTask(ctx context.Context, issue *api.Issue, promotion *taskPromotion, updaterID int) *echo.HTTPError {
+	task := *promotion.targetTask
+	payload, err := getPromotedTaskPayload(task.Type, task.Payload, promotion)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to promote task %q", task.Name)).SetInternal(err)
+	}
+	// patchTask updates the statement on top of the payload, so the provenance is saved together with the statement.
+	task.Payload = payload
+	if _, httpErr := s.patchTask(ctx, &task, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: updaterID,
+		Statement: &promotion.detail.Statement,
+	}, issue); httpErr != nil {
+		return httpErr
+	}
+	return nil
+}
+
+// createPromotedStage creates the stage for the next environment with the statements applied in the stage.
+// The databases in the next environment are matched by the database names in the stage.
+func (s *Server) createPromotedStage(ctx context.Context, issue *api.Issue, stage *api.Stage, creatorID int) (*api.Stage, *echo.HTTPError) {
+	promotionList, err := matchTaskPromotionList(stage.TaskList, nil /* targetTaskList */)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to promote stage %q: %v", stage.Name, err))
+	}
+
+	rowStatus := api.Normal
+	environmentList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{RowStatus: &rowStatus})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to find environments").SetInternal(err)
+	}
+	nextEnvironment := getNextEnvironment(environmentList, stage.Environment)
+	if nextEnvironment == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("There is no environment after %q to promote stage %q to", stage.Environment.Name, stage.Name))
+	}
+
+	databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{ProjectID: &issue.ProjectID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find databases in project ID: %v", issue.ProjectID)).SetInternal(err)
+	}
+	databaseMap := make(map[string]*api.Database)
+	for _, database := range databaseList {
+		if database.Instance.EnvironmentID != nextEnvironment.ID {
+			continue
+		}
+		if _, ok := databaseMap[database.Name]; ok {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("There are multiple databases named %q in environment %q", database.Name, nextEnvironment.Name))
+		}
+		databaseMap[database.Name] = database
+	}
+
+	var taskCreateList []api.TaskCreate
+	for _, promotion := range promotionList {
+		database, ok := databaseMap[promotion.sourceTask.Database.Name]
+		if !ok {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q not found in environment %q", promotion.sourceTask.Database.Name, nextEnvironment.Name))
+		}
+		taskCreate, err := getUpdateTask(database, promotion.migrationType, nil /* vcsPushEvent */, promotion.detail, common.DefaultMigrationVersion(), false /* formatStatement */)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create task for database %q", database.Name)).SetInternal(err)
+		}
+		// getUpdateTask uses the schema update payload for both schema and data update tasks.
+		payload, err := getPromotedTaskPayload(api.TaskDatabaseSchemaUpdate, taskCreate.Payload, promotion)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create task for database %q", database.Name)).SetInternal(err)
+		}
+		taskCreate.Payload = payload
+		taskCreateList = append(taskCreateList, *taskCreate)
+	}
+
+	createdStage, err := s.store.CreateStage(ctx, &api.StageCreate{
+		CreatorID:     creatorID,
+		PipelineID:    issue.PipelineID,
+		EnvironmentID: nextEnvironment.ID,
+		Name:          nextEnvironment.Name,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create stage").SetInternal(err)
+	}
+	for _, taskCreate := range taskCreateList {
+		taskCreate.CreatorID = creatorID
+		taskCreate.PipelineID = issue.PipelineID
+		taskCreate.StageID = createdStage.ID
+		if _, err := s.store.CreateTask(ctx, &taskCreate); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create task %q", taskCreate.Name)).SetInternal(err)
+		}
+	}
+	return createdStage, nil
+}
+
+// getNextEnvironment returns the environment right after the given environment in order, nil if it's the last one.
+func getNextEnvironment(environmentList []*api.Environment, environment *api.Environment) *api.Environment {
+	var next *api.Environment
+	for _, env := range environmentList {
+		if env.Order <= environment.Order {
+			continue
+		}
+		if next == nil || env.Order < next.Order {
+			next = env
+		}
+	}
+	return next
+}
+
+// getTaskPromotion returns the statement applied by the task to promote.
+func getTaskPromotion(task *api.Task) (*taskPromotion, error) {
+	if task.Status != api.TaskDone {
+		return nil, fmt.Errorf("task %q is %s, only the stage with all tasks done can be promoted", task.Name, task.Status)
+	}
+	if task.Database == nil {
+		return nil, fmt.Errorf("task %q doesn't have a database", task.Name)
+	}
+	promotion := &taskPromotion{sourceTask: task}
+	switch task.Type {
+	case api.TaskDatabaseSchemaUpdate:
+		payload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return nil, fmt.Errorf("invalid database schema update payload of task %q: %w", task.Name, err)
+		}
+		promotion.migrationType = payload.MigrationType
+		promotion.detail = &api.UpdateSchemaDetail{
+			Statement:      payload.Statement,
+			DownStatement:  payload.DownStatement,
+			Verification:   payload.Verification,
+			SheetID:        payload.SheetID,
+			SheetVersion:   payload.SheetVersion,
+			SessionSetting: payload.SessionSetting,
+		}
+	case api.TaskDatabaseDataUpdate:
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return nil, fmt.Errorf("invalid database data update payload of task %q: %w", task.Name, err)
+		}
+		promotion.migrationType = db.Data
+		promotion.detail = &api.UpdateSchemaDetail{
+			Statement:      payload.Statement,
+			DownStatement:  payload.DownStatement,
+			Verification:   payload.Verification,
+			SheetID:        payload.SheetID,
+			SheetVersion:   payload.SheetVersion,
+			SessionSetting: payload.SessionSetting,
+		}
+	default:
+		return nil, fmt.Errorf("task %q of type %s can not be promoted", task.Name, task.Type)
+	}
+	return promotion, nil
+}
+
+// matchTaskPromotionList matches the done tasks of the stage to the tasks of the next stage by the database names.
+// If both stages have a single task, the tasks are matched regardless of the database names.
+// The target tasks are left empty if the target task list is empty.
+func matchTaskPromotionList(sourceTaskList []*api.Task, targetTaskList []*api.Task) ([]*taskPromotion, error) {
+	if len(sourceTaskList) == 0 {
+		return nil, fmt.Errorf("there is no task to promote")
+	}
+	var promotionList []*taskPromotion
+	promotionMap := make(map[string]*taskPromotion)
+	for _, task := range sourceTaskList {
+		promotion, err := getTaskPromotion(task)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := promotionMap[task.Database.Name]; ok {
+			return nil, fmt.Errorf("there are multiple tasks updating database %q", task.Database.Name)
+		}
+		promotionMap[task.Database.Name] = promotion
+		promotionList = append(promotionList, promotion)
+	}
+	if len(targetTaskList) == 0 {
+		return promotionList, nil
+	}
+
+	var matchedList []*taskPromotion
+	for _, task := range targetTaskList {
+		var promotion *taskPromotion
+		if len(sourceTaskList) == 1 && len(targetTaskList) == 1 {
+			promotion = promotionList[0]
+		} else {
+			if task.Database == nil {
+				return nil, fmt.Errorf("task %q doesn't have a database", task.Name)
+			}
+			p, ok := promotionMap[task.Database.Name]
+			if !ok {
+				return nil, fmt.Errorf("no task updating database %q to promote to task %q", task.Database.Name, task.Name)
+			}
+			promotion = p
+		}
+		if task.Type != promotion.sourceTask.Type {
+			return nil, fmt.Errorf("task %q of type %s can not be promoted to task %q of type %s", promotion.sourceTask.Name, promotion.sourceTask.Type, task.Name, task.Type)
+		}
+		if task.Status != api.TaskPendingApproval {
+			return nil, fmt.Errorf("task %q is %s, only the task pending approval can be promoted to", task.Name, task.Status)
+		}
+		matchedList = append(matchedList, &taskPromotion{
+			sourceTask:    promotion.sourceTask,
+			migrationType: promotion.migrationType,
+			detail:        promotion.detail,
+			targetTask:    task,
+		})
+	}
+	return matchedList, nil
+}
+
+// getPromotedTaskPayload returns the task payload recording the source task and the down statement of the promotion.
+// The statement is updated separately so that the statement update goes through the task check.
+func getPromotedTaskPayload(taskType api.TaskType, payloadStr string, promotion *taskPromotion) (string, error) {
+	var bytes []byte
+	var err error
+	switch taskType {
+	case api.TaskDatabaseSchemaUpdate:
+		payload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(payloadStr), payload); err != nil {
+			return "", fmt.Errorf("invalid database schema update payload: %w", err)
+		}
+		payload.DownStatement = promotion.detail.DownStatement
+		payload.PromotedFromTaskID = promotion.sourceTask.ID
+		payload.SheetID = promotion.detail.SheetID
+		payload.SheetVersion = promotion.detail.SheetVersion
+		bytes, err = json.Marshal(payload)
+	case api.TaskDatabaseDataUpdate:
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(payloadStr), payload); err != nil {
+			return "", fmt.Errorf("invalid database data update payload: %w", err)
+		}
+		payload.DownStatement = promotion.detail.DownStatement
+		payload.PromotedFromTaskID = promotion.sourceTask.ID
+		payload.SheetID = promotion.detail.SheetID
+		payload.SheetVersion = promotion.detail.SheetVersion
+		bytes, err = json.Marshal(payload)
+	default:
+		return "", fmt.Errorf("task type %s can not be promoted", taskType)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
 }
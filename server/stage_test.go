@@ -0,0 +1,152 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchTaskPromotionList(t *testing.T) {
+	newTask := func(id int, status api.TaskStatus, taskType api.TaskType, databaseName string, payload string) *api.Task {
+		return &api.Task{
+			ID:       id,
+			Name:     "Update " + databaseName,
+			Status:   status,
+			Type:     taskType,
+			Payload:  payload,
+			Database: &api.Database{ID: id * 10, Name: databaseName},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		sourceTaskList []*api.Task
+		targetTaskList []*api.Task
+		// want is the pairs of the source and target task IDs, the target task ID is 0 if there is no target task.
+		want    [][2]int
+		wantErr bool
+	}{
+		{
+			name: "match by database name",
+			sourceTaskList: []*api.Task{
+				newTask(1, api.TaskDone, api.TaskDatabaseSchemaUpdate, "orders", `{"migrationType":"MIGRATE","statement":"ALTER TABLE t ADD c INT"}`),
+				newTask(2, api.TaskDone, api.TaskDatabaseSchemaUpdate, "billing", `{"migrationType":"MIGRATE","statement":"ALTER TABLE b ADD c INT"}`),
+			},
+			targetTaskList: []*api.Task{
+				newTask(3, api.TaskPendingApproval, api.TaskDatabaseSchemaUpdate, "billing", `{"migrationType":"MIGRATE","statement":"ALTER TABLE b ADD d INT"}`),
+				newTask(4, api.TaskPendingApproval, api.TaskDatabaseSchemaUpdate, "orders", `{"migrationType":"MIGRATE"}`),
+			},
+			want: [][2]int{{2, 3}, {1, 4}},
+		},
+		{
+			name: "single task regardless of database name",
+			sourceTaskList: []*api.Task{
+				newTask(1, api.TaskDone, api.TaskDatabaseDataUpdate, "orders_staging", `{"statement":"UPDATE t SET c = 1"}`),
+			},
+			targetTaskList: []*api.Task{
+				newTask(2, api.TaskPendingApproval, api.TaskDatabaseDataUpdate, "orders_prod", `{"statement":"UPDATE t SET c = 2"}`),
+			},
+			want: [][2]int{{1, 2}},
+		},
+		{
+			name: "next stage not created",
+			sourceTaskList: []*api.Task{
+				newTask(1, api.TaskDone, api.TaskDatabaseSchemaUpdate, "orders", `{"migrationType":"MIGRATE","statement":"ALTER TABLE t ADD c INT"}`),
+			},
+			want: [][2]int{{1, 0}},
+		},
+		{
+			name: "source task not done",
+			sourceTaskList: []*api.Task{
+				newTask(1, api.TaskRunning, api.TaskDatabaseSchemaUpdate, "orders", `{"migrationType":"MIGRATE","statement":"ALTER TABLE t ADD c INT"}`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported task type",
+			sourceTaskList: []*api.Task{
+				newTask(1, api.TaskDone, api.TaskDatabaseCreate, "orders", `{"statement":"CREATE DATABASE orders"}`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "target task without source task",
+			sourceTaskList: []*api.Task{
+				newTask(1, api.TaskDone, api.TaskDatabaseSchemaUpdate, "orders", `{"migrationType":"MIGRATE","statement":"ALTER TABLE t ADD c INT"}`),
+				newTask(2, api.TaskDone, api.TaskDatabaseSchemaUpdate, "billing", `{"migrationType":"MIGRATE","statement":"ALTER TABLE b ADD c INT"}`),
+			},
+			targetTaskList: []*api.Task{
+				newTask(3, api.TaskPendingApproval, api.TaskDatabaseSchemaUpdate, "users", `{"migrationType":"MIGRATE"}`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "target task already approved",
+			sourceTaskList: []*api.Task{
+				newTask(1, api.TaskDone, api.TaskDatabaseSchemaUpdate, "orders", `{"migrationType":"MIGRATE","statement":"ALTER TABLE t ADD c INT"}`),
+			},
+			targetTaskList: []*api.Task{
+				newTask(2, api.TaskPending, api.TaskDatabaseSchemaUpdate, "orders", `{"migrationType":"MIGRATE"}`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "different task types",
+			sourceTaskList: []*api.Task{
+				newTask(1, api.TaskDone, api.TaskDatabaseSchemaUpdate, "orders", `{"migrationType":"MIGRATE","statement":"ALTER TABLE t ADD c INT"}`),
+			},
+			targetTaskList: []*api.Task{
+				newTask(2, api.TaskPendingApproval, api.TaskDatabaseDataUpdate, "orders", `{}`),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		promotionList, err := matchTaskPromotionList(test.sourceTaskList, test.targetTaskList)
+		if test.wantErr {
+			require.Error(t, err, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		var got [][2]int
+		for _, promotion := range promotionList {
+			targetTaskID := 0
+			if promotion.targetTask != nil {
+				targetTaskID = promotion.targetTask.ID
+			}
+			got = append(got, [2]int{promotion.sourceTask.ID, targetTaskID})
+		}
+		require.Equal(t, test.want, got, test.name)
+	}
+}
+
+func TestGetPromotedTaskPayload(t *testing.T) {
+	promotion := &taskPromotion{
+		sourceTask:    &api.Task{ID: 7},
+		migrationType: db.Migrate,
+		detail: &api.UpdateSchemaDetail{
+			Statement:     "ALTER TABLE t ADD c INT",
+			DownStatement: "ALTER TABLE t DROP COLUMN c",
+		},
+	}
+	payload, err := getPromotedTaskPayload(api.TaskDatabaseSchemaUpdate, `{"migrationType":"MIGRATE","statement":"ALTER TABLE t ADD d INT","schemaVersion":"20220101"}`, promotion)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"migrationType":"MIGRATE","statement":"ALTER TABLE t ADD d INT","downStatement":"ALTER TABLE t DROP COLUMN c","schemaVersion":"20220101","promotedFromTaskId":7}`, payload)
+
+	_, err = getPromotedTaskPayload(api.TaskDatabaseCreate, `{}`, promotion)
+	require.Error(t, err)
+}
+
+func TestGetNextEnvironment(t *testing.T) {
+	dev := &api.Environment{ID: 1, Name: "Dev", Order: 0}
+	staging := &api.Environment{ID: 2, Name: "Staging", Order: 1}
+	prod := &api.Environment{ID: 3, Name: "Prod", Order: 3}
+	environmentList := []*api.Environment{prod, dev, staging}
+
+	require.Equal(t, staging, getNextEnvironment(environmentList, dev))
+	require.Equal(t, prod, getNextEnvironment(environmentList, staging))
+	require.Nil(t, getNextEnvironment(environmentList, prod))
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+// isStatisticsRefreshSupported returns true if the engine supports refreshing the table statistics.
+func isStatisticsRefreshSupported(engine db.Type) bool {
+	return engine == db.Postgres || engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+}
+
+// refreshStatistics runs ANALYZE on the tables touched by the statement if the statistics refresh policy
+// of the environment is enabled. It returns the number of the analyzed tables.
+func refreshStatistics(ctx context.Context, server *Server, task *api.Task, statement string) (int, error) {
+	instance := task.Instance
+	if !isStatisticsRefreshSupported(instance.Engine) {
+		return 0, nil
+	}
+	policy, err := server.store.GetStatisticsRefreshPolicy(ctx, instance.EnvironmentID, api.GetPolicyTagList(instance, task.Database))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get statistics refresh policy for environment ID %d, error: %w", instance.EnvironmentID, err)
+	}
+	if !policy.Enabled {
+		return 0, nil
+	}
+	// The agent only runs the migrations and the read-only queries.
+	if instance.AgentID != nil {
+		log.Debug("Skip refreshing statistics for the instance managed by the agent", zap.String("instance", instance.Name))
+		return 0, nil
+	}
+
+	databaseName := task.Database.Name
+	tableList, err := getStatisticsRefreshTableList(instance.Engine, statement, databaseName, task.Database.CharacterSet, task.Database.Collation)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the statement for the touched tables, error: %w", err)
+	}
+	if len(tableList) == 0 {
+		return 0, nil
+	}
+
+	driver, err := server.getAdminDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return 0, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, databaseName)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, table := range tableList {
+		if _, err := sqldb.ExecContext(ctx, getAnalyzeTableStatement(instance.Engine, table)); err != nil {
+			return count, fmt.Errorf("failed to analyze table %q, error: %w", table.String(), err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// getAnalyzeTableStatement returns the statement refreshing the statistics of the table.
+func getAnalyzeTableStatement(engine db.Type, table impactedTable) string {
+	if engine == db.Postgres {
+		quote := func(s string) string {
+			return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+		}
+		return fmt.Sprintf("ANALYZE %s.%s", quote(table.schema), quote(table.name))
+	}
+	quote := func(s string) string {
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "`", "``"))
+	}
+	return fmt.Sprintf("ANALYZE TABLE %s.%s", quote(table.schema), quote(table.name))
+}
+
+// getStatisticsRefreshTableList returns the tables created, altered, indexed or having data changed by the statement.
+// The renamed tables are returned with their new names.
+func getStatisticsRefreshTableList(dbType db.Type, statement, databaseName, charset, collation string) ([]impactedTable, error) {
+	var tableList []impactedTable
+	add := func(table impactedTable) {
+		for _, t := range tableList {
+			if t == table {
+				return
+			}
+		}
+		tableList = append(tableList, table)
+	}
+
+	switch dbType {
+	case db.Postgres:
+		nodeList, err := parser.Parse(parser.Postgres, parser.Context{}, statement)
+		if err != nil {
+			return nil, err
+		}
+		pgTable := func(table *ast.TableDef) impactedTable {
+			schema := table.Schema
+			if schema == "" {
+				schema = "public"
+			}
+			return impactedTable{schema: schema, name: table.Name}
+		}
+		for _, node := range nodeList {
+			switch node := node.(type) {
+			case *ast.CreateTableStmt:
+				add(pgTable(node.Name))
+			case *ast.AlterTableStmt:
+				// The views have no statistics.
+				if node.Table.Type == ast.TableTypeView {
+					continue
+				}
+				table := pgTable(node.Table)
+				for _, item := range node.AlterItemList {
+					if rename, ok := item.(*ast.RenameTableStmt); ok {
+						table.name = rename.NewName
+					}
+				}
+				add(table)
+			case *ast.CreateIndexStmt:
+				add(pgTable(node.Index.Table))
+			case *ast.InsertStmt:
+				add(pgTable(node.Table))
+			case *ast.UpdateStmt:
+				add(pgTable(node.Table))
+			case *ast.DeleteStmt:
+				add(pgTable(node.Table))
+			case *ast.CopyStmt:
+				add(pgTable(node.Table))
+			}
+		}
+	case db.MySQL, db.TiDB, db.MariaDB:
+		p := tidbparser.New()
+		p.EnableWindowFunc(true)
+		nodeList, _, err := p.Parse(statement, charset, collation)
+		if err != nil {
+			return nil, err
+		}
+		mysqlTable := func(table *tidbast.TableName) impactedTable {
+			schema := table.Schema.O
+			if schema == "" {
+				schema = databaseName
+			}
+			return impactedTable{schema: schema, name: table.Name.O}
+		}
+		// addTableRefs adds the tables of the DML statements, the tables only read by the joins are added as well.
+		var addTableRefs func(node tidbast.ResultSetNode)
+		addTableRefs = func(node tidbast.ResultSetNode) {
+			switch node := node.(type) {
+			case *tidbast.Join:
+				if node == nil {
+					return
+				}
+				addTableRefs(node.Left)
+				addTableRefs(node.Right)
+			case *tidbast.TableSource:
+				if table, ok := node.Source.(*tidbast.TableName); ok {
+					add(mysqlTable(table))
+				}
+			}
+		}
+		for _, node := range nodeList {
+			switch node := node.(type) {
+			case *tidbast.CreateTableStmt:
+				add(mysqlTable(node.Table))
+			case *tidbast.AlterTableStmt:
+				table := node.Table
+				for _, spec := range node.Specs {
+					if spec.Tp == tidbast.AlterTableRenameTable {
+						table = spec.NewTable
+					}
+				}
+				add(mysqlTable(table))
+			case *tidbast.RenameTableStmt:
+				for _, t := range node.TableToTables {
+					add(mysqlTable(t.NewTable))
+				}
+			case *tidbast.CreateIndexStmt:
+				add(mysqlTable(node.Table))
+			case *tidbast.InsertStmt:
+				if node.Table != nil {
+					addTableRefs(node.Table.TableRefs)
+				}
+			case *tidbast.UpdateStmt:
+				if node.TableRefs != nil {
+					addTableRefs(node.TableRefs.TableRefs)
+				}
+			case *tidbast.DeleteStmt:
+				if node.TableRefs != nil {
+					addTableRefs(node.TableRefs.TableRefs)
+				}
+			}
+		}
+	}
+	return tableList, nil
+}
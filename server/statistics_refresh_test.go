@@ -0,0 +1,58 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+
+	// Register the parsers.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+func TestGetStatisticsRefreshTableList(t *testing.T) {
+	tests := []struct {
+		dbType    db.Type
+		statement string
+		want      []impactedTable
+	}{
+		{
+			dbType:    db.MySQL,
+			statement: "CREATE TABLE t1 (a INT); CREATE INDEX idx_a ON t1 (a); ALTER TABLE other.t2 ADD COLUMN c INT; RENAME TABLE t3 TO t4; DROP TABLE t5;",
+			want: []impactedTable{
+				{schema: "db", name: "t1"},
+				{schema: "other", name: "t2"},
+				{schema: "db", name: "t4"},
+			},
+		},
+		{
+			dbType:    db.MySQL,
+			statement: "INSERT INTO t1 VALUES (1); UPDATE t2 JOIN t3 ON t2.id = t3.id SET t2.a = 1; DELETE FROM t1 WHERE a = 1; SELECT * FROM t6;",
+			want: []impactedTable{
+				{schema: "db", name: "t1"},
+				{schema: "db", name: "t2"},
+				{schema: "db", name: "t3"},
+			},
+		},
+		{
+			dbType:    db.Postgres,
+			statement: "CREATE TABLE t1 (a INT); CREATE INDEX idx_a ON s.t2 (a); ALTER TABLE t3 RENAME TO t4; INSERT INTO t1 VALUES (1); DROP TABLE t5;",
+			want: []impactedTable{
+				{schema: "public", name: "t1"},
+				{schema: "s", name: "t2"},
+				{schema: "public", name: "t4"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tableList, err := getStatisticsRefreshTableList(test.dbType, test.statement, "db", "", "")
+		require.NoError(t, err)
+		require.Equal(t, test.want, tableList, test.statement)
+	}
+
+	require.Equal(t, `ANALYZE "public"."t""1"`, getAnalyzeTableStatement(db.Postgres, impactedTable{schema: "public", name: `t"1`}))
+	require.Equal(t, "ANALYZE TABLE `db`.`t``1`", getAnalyzeTableStatement(db.MySQL, impactedTable{schema: "db", name: "t`1"}))
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerTableScaffoldRoutes(g *echo.Group) {
+	// Returns the statement templates of the synced table, e.g. adding a column, archiving the rows and auditing the row changes.
+	g.GET("/database/:id/table/:tableName/scaffold", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		tableName := c.Param("tableName")
+		table, err := s.store.GetTable(ctx, &api.TableFind{DatabaseID: &id, Name: &tableName})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table for database id: %d, table name: %s", id, tableName)).SetInternal(err)
+		}
+		if table == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("table %q not found from database %v", tableName, id))
+		}
+		columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &id, TableID: &table.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch column list for database id: %d, table name: %s", id, tableName)).SetInternal(err)
+		}
+		table.ColumnList = columnList
+
+		scaffoldList, err := api.GenerateTableScaffoldList(database.Instance.Engine, database.Instance.EngineVersion, table)
+		if err != nil {
+			if common.ErrorCode(err) == common.Invalid {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to generate scaffolding for table %q", tableName)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, scaffoldList)
+	})
+}
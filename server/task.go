@@ -198,6 +198,11 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		if err := s.validateIssueAssignee(ctx, currentPrincipalID, task.PipelineID); err != nil {
 			return err
 		}
+		if task.Type == api.TaskDatabaseDrop && task.Status == api.TaskPendingApproval && taskStatusPatch.Status == api.TaskPending {
+			if err := s.validateDatabaseDropApprover(ctx, currentPrincipalID); err != nil {
+				return err
+			}
+		}
 
 		taskPatched, err := s.patchTaskStatus(ctx, task, taskStatusPatch)
 		if err != nil {
@@ -243,6 +248,22 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 	})
 }
 
+// unlinkChangedSheetVersion clears the sheet version the task statement is taken from,
+// if the statement is changed to something other than the sheet version, e.g. edited in the issue.
+func (s *Server) unlinkChangedSheetVersion(ctx context.Context, sheetID *int, sheetVersion *int, oldStatement string, newStatement string) *echo.HTTPError {
+	if *sheetID == 0 || oldStatement == newStatement {
+		return nil
+	}
+	version, err := s.store.GetSheetVersion(ctx, *sheetID, *sheetVersion)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch version %d of sheet ID: %d", *sheetVersion, *sheetID)).SetInternal(err)
+	}
+	if version == nil || version.Statement != newStatement {
+		*sheetID, *sheetVersion = 0, 0
+	}
+	return nil
+}
+
 func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.TaskPatch, issue *api.Issue) (*api.Task, *echo.HTTPError) {
 	oldStatement := ""
 	newStatement := ""
@@ -260,6 +281,9 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 			}
 			oldStatement = payload.Statement
 			payload.Statement = *taskPatch.Statement
+			if httpErr := s.unlinkChangedSheetVersion(ctx, &payload.SheetID, &payload.SheetVersion, oldStatement, payload.Statement); httpErr != nil {
+				return nil, httpErr
+			}
 			// We should update the schema version if we've updated the SQL, otherwise we will
 			// get migration history version conflict if the previous task has been attempted.
 			payload.SchemaVersion = common.DefaultMigrationVersion()
@@ -276,6 +300,9 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 			}
 			oldStatement = payload.Statement
 			payload.Statement = *taskPatch.Statement
+			if httpErr := s.unlinkChangedSheetVersion(ctx, &payload.SheetID, &payload.SheetVersion, oldStatement, payload.Statement); httpErr != nil {
+				return nil, httpErr
+			}
 			// We should update the schema version if we've updated the SQL, otherwise we will
 			// get migration history version conflict if the previous task has been attempted.
 			payload.SchemaVersion = common.DefaultMigrationVersion()
@@ -320,6 +347,53 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 		}
 	}
 
+	if taskPatch.Verification != nil {
+		if httpErr := s.canUpdateTaskStatement(ctx, task); httpErr != nil {
+			return nil, httpErr
+		}
+		var verification *api.TaskVerification
+		if *taskPatch.Verification != "" {
+			verification = &api.TaskVerification{}
+			if err := json.Unmarshal([]byte(*taskPatch.Verification), verification); err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformed task verification").SetInternal(err)
+			}
+			if err := verification.Validate(); err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+		}
+		// The payload may have been updated with the statement above.
+		taskPayload := task.Payload
+		if taskPatch.Payload != nil {
+			taskPayload = *taskPatch.Payload
+		}
+
+		var bytes []byte
+		var err error
+		switch task.Type {
+		case api.TaskDatabaseSchemaUpdate:
+			payload := &api.TaskDatabaseSchemaUpdatePayload{}
+			if err := json.Unmarshal([]byte(taskPayload), payload); err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformed database schema update payload").SetInternal(err)
+			}
+			payload.Verification = verification
+			bytes, err = json.Marshal(payload)
+		case api.TaskDatabaseDataUpdate:
+			payload := &api.TaskDatabaseDataUpdatePayload{}
+			if err := json.Unmarshal([]byte(taskPayload), payload); err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformed database data update payload").SetInternal(err)
+			}
+			payload.Verification = verification
+			bytes, err = json.Marshal(payload)
+		default:
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %q of type %s doesn't support verification", task.Name, task.Type))
+		}
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct updated task payload").SetInternal(err)
+		}
+		payloadStr := string(bytes)
+		taskPatch.Payload = &payloadStr
+	}
+
 	taskPatched, err := s.store.PatchTask(ctx, taskPatch)
 	if err != nil {
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update task \"%v\"", task.Name)).SetInternal(err)
@@ -327,7 +401,8 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 
 	// create an activity and trigger task check for statement update
 	if taskPatched.Type == api.TaskDatabaseSchemaUpdate || taskPatched.Type == api.TaskDatabaseDataUpdate || taskPatched.Type == api.TaskDatabaseSchemaUpdateGhostSync {
-		if oldStatement != newStatement {
+		// The whitespace and comment changes don't need another review.
+		if !isStatementEqual(task.Instance.Engine, oldStatement, newStatement) {
 			if issue == nil {
 				err := fmt.Errorf("issue not found with pipeline ID %v", task.PipelineID)
 				return nil, echo.NewHTTPError(http.StatusNotFound, err).SetInternal(err)
@@ -506,6 +581,9 @@ func (s *Server) validateIssueAssignee(ctx context.Context, currentPrincipalID,
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find principal").SetInternal(err)
 		}
+		if currentPrincipal == nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Principal ID not found: %d", currentPrincipalID))
+		}
 		if currentPrincipal.Role != api.Owner && currentPrincipal.Role != api.DBA {
 			return echo.NewHTTPError(http.StatusUnauthorized, "Only allow Owner/DBA system account to update task status")
 		}
@@ -515,10 +593,27 @@ func (s *Server) validateIssueAssignee(ctx context.Context, currentPrincipalID,
 	return nil
 }
 
+// validateDatabaseDropApprover validates the principal approving the database drop task.
+// Dropping database requires the approval from the Owner or DBA even if the assignee is a developer.
+func (s *Server) validateDatabaseDropApprover(ctx context.Context, principalID int) error {
+	principal, err := s.store.GetPrincipalByID(ctx, principalID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find principal").SetInternal(err)
+	}
+	if principal == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Principal ID not found: %d", principalID))
+	}
+	if principal.Role != api.Owner && principal.Role != api.DBA {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Only allow Owner/DBA to approve dropping database")
+	}
+	return nil
+}
+
 func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatusPatch *api.TaskStatusPatch) (_ *api.Task, err error) {
 	defer func() {
 		if err != nil {
 			log.Error("Failed to change task status.",
+				zap.String("request_id", common.RequestIDFromContext(ctx)),
 				zap.Int("id", task.ID),
 				zap.String("name", task.Name),
 				zap.String("old_status", string(task.Status)),
@@ -527,6 +622,10 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 		}
 	}()
 
+	if taskStatusPatch.RequestID == "" {
+		taskStatusPatch.RequestID = common.RequestIDFromContext(ctx)
+	}
+
 	if !isTaskStatusTransitionAllowed(task.Status, taskStatusPatch.Status) {
 		return nil, &common.Error{
 			Code: common.Invalid,
@@ -610,6 +709,14 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 		}
 	}
 
+	if err := s.updateChangeSetAfterTaskStatusChange(ctx, taskPatched, issue, taskStatusPatch.UpdaterID); err != nil {
+		log.Error("Failed to update change set after changing the task status",
+			zap.Int("task_id", task.ID),
+			zap.String("task_name", task.Name),
+			zap.Error(err),
+		)
+	}
+
 	// If this is the last task in the pipeline and just completed, and the assignee is system bot:
 	// Case 1: If the task is associated with an issue, then we mark the issue (including the pipeline) as DONE.
 	// Case 2: If the task is NOT associated with an issue, then we mark the pipeline as DONE.
@@ -647,7 +754,7 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 }
 
 func (s *Server) triggerDatabaseStatementAdviseTask(ctx context.Context, statement string, task *api.Task) error {
-	policyID, err := s.store.GetSQLReviewPolicyIDByEnvID(ctx, task.Instance.EnvironmentID)
+	policyID, err := s.store.GetSQLReviewPolicyIDByEnvID(ctx, task.Instance.EnvironmentID, api.GetPolicyTagList(task.Instance, task.Database))
 
 	if err != nil {
 		// It's OK if we failed to find the SQL review policy, just emit an error log
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// databaseDropInactivePeriod is the period without writes required before dropping a database.
+const databaseDropInactivePeriod = 7 * 24 * time.Hour
+
+// NewTaskCheckDatabaseActivityExecutor creates a task check database activity executor.
+func NewTaskCheckDatabaseActivityExecutor() TaskCheckExecutor {
+	return &TaskCheckDatabaseActivityExecutor{}
+}
+
+// TaskCheckDatabaseActivityExecutor is the task check executor verifying the database to drop has no active connections
+// and no recent writes.
+type TaskCheckDatabaseActivityExecutor struct {
+}
+
+// databaseActivity is the recent activity of a database on the instance.
+type databaseActivity struct {
+	connectionCount int
+	// lastWriteTs is the last write time in unix seconds, 0 if unknown.
+	lastWriteTs int64
+}
+
+// Run will run the task check database activity executor once.
+func (*TaskCheckDatabaseActivityExecutor) Run(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	task, err := server.store.GetTaskByID(ctx, taskCheckRun.TaskID)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if task == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "task ID not found %v", taskCheckRun.TaskID)
+	}
+
+	payload := &api.TaskCheckDatabaseDropActivityPayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return nil, common.Errorf(common.Invalid, "invalid check database activity payload: %w", err)
+	}
+
+	activity, err := getDatabaseActivity(ctx, server, task.Instance, payload.DatabaseName)
+	if err != nil {
+		//nolint:nilerr
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.DbConnectionFailure.Int(),
+				Title:     fmt.Sprintf("Failed to check the activity of database %q", payload.DatabaseName),
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	if activity.connectionCount > 0 {
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusError,
+			Namespace: api.BBNamespace,
+			Code:      common.TaskDatabaseActive.Int(),
+			Title:     "Database has active connections",
+			Content:   fmt.Sprintf("Database %q has %d active connection(s), stop the applications using it before dropping.", payload.DatabaseName, activity.connectionCount),
+		})
+	}
+	if activity.lastWriteTs > 0 && time.Since(time.Unix(activity.lastWriteTs, 0)) < databaseDropInactivePeriod {
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusError,
+			Namespace: api.BBNamespace,
+			Code:      common.TaskDatabaseActive.Int(),
+			Title:     "Database has recent writes",
+			Content: fmt.Sprintf("Database %q was written at %s, it must have no writes for %d days before dropping.",
+				payload.DatabaseName, time.Unix(activity.lastWriteTs, 0).UTC().Format(dataFormat), int(databaseDropInactivePeriod.Hours()/24)),
+		})
+	}
+	if len(result) > 0 {
+		return result, nil
+	}
+
+	return []api.TaskCheckResult{
+		{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   fmt.Sprintf("Database %q has no active connections and no recent writes", payload.DatabaseName),
+		},
+	}, nil
+}
+
+// getDatabaseActivity gets the active connections and the last write time of the database.
+func getDatabaseActivity(ctx context.Context, server *Server, instance *api.Instance, databaseName string) (*databaseActivity, error) {
+	activity := &databaseActivity{}
+	switch instance.Engine {
+	case db.MySQL, db.TiDB, db.MariaDB:
+		driver, err := server.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+		if err != nil {
+			return nil, err
+		}
+		defer driver.Close(ctx)
+		sqlDB, err := driver.GetDBConnection(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := sqlDB.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM information_schema.PROCESSLIST WHERE DB = ? AND ID <> CONNECTION_ID()",
+			databaseName,
+		).Scan(&activity.connectionCount); err != nil {
+			return nil, err
+		}
+		// UPDATE_TIME is the last time the table data file was updated.
+		var lastWriteTs sql.NullInt64
+		if err := sqlDB.QueryRowContext(ctx,
+			"SELECT CAST(UNIX_TIMESTAMP(MAX(UPDATE_TIME)) AS SIGNED) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?",
+			databaseName,
+		).Scan(&lastWriteTs); err != nil {
+			return nil, err
+		}
+		activity.lastWriteTs = lastWriteTs.Int64
+	case db.Postgres:
+		driver, err := server.getAdminDatabaseDriver(ctx, instance, databaseName)
+		if err != nil {
+			return nil, err
+		}
+		defer driver.Close(ctx)
+
+		// Postgres doesn't track the last write time, autovacuum and autoanalyze run after the rows are modified,
+		// so the last time they ran on the tables approximates the last write time.
+		sqlDB, err := driver.GetDBConnection(ctx, databaseName)
+		if err != nil {
+			return nil, err
+		}
+		var lastWriteTs sql.NullInt64
+		if err := sqlDB.QueryRowContext(ctx,
+			"SELECT CAST(EXTRACT(EPOCH FROM MAX(GREATEST(last_autovacuum, last_autoanalyze))) AS BIGINT) FROM pg_stat_user_tables",
+		).Scan(&lastWriteTs); err != nil {
+			return nil, err
+		}
+		activity.lastWriteTs = lastWriteTs.Int64
+
+		// Count the connections from another database so that the connections of the driver itself are excluded.
+		sqlDB, err = driver.GetDBConnection(ctx, "postgres")
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlDB.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM pg_stat_activity WHERE datname = $1",
+			databaseName,
+		).Scan(&activity.connectionCount); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("checking database activity is not supported for %s", instance.Engine)
+	}
+	return activity, nil
+}
@@ -43,6 +43,11 @@ func (*TaskCheckDatabaseConnectExecutor) Run(ctx context.Context, server *Server
 		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "database ID not found %v", task.DatabaseID)
 	}
 
+	if database.Instance.AgentID != nil {
+		// The server can't reach the instance, check the agent is alive instead.
+		return server.checkAgentAlive(ctx, *database.Instance.AgentID)
+	}
+
 	driver, err := server.getAdminDatabaseDriver(ctx, database.Instance, database.Name)
 	if err != nil {
 		return []api.TaskCheckResult{
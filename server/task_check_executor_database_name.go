@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// NewTaskCheckDatabaseNameExecutor creates a task check database name executor.
+func NewTaskCheckDatabaseNameExecutor() TaskCheckExecutor {
+	return &TaskCheckDatabaseNameExecutor{}
+}
+
+// TaskCheckDatabaseNameExecutor is the task check executor validating the name of the database to create,
+// so the invalid names are reported before running the task instead of failing the CREATE DATABASE statement.
+type TaskCheckDatabaseNameExecutor struct {
+}
+
+// Run will run the task check database name executor once.
+func (*TaskCheckDatabaseNameExecutor) Run(_ context.Context, _ *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	payload := &api.TaskCheckDatabaseCreateNamePayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Invalid, "invalid check database name payload: %w", err)
+	}
+
+	if err := api.ValidateDatabaseName(payload.DbType, payload.DatabaseName, payload.Pattern); err != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskDatabaseNameInvalid.Int(),
+				Title:     fmt.Sprintf("Invalid database name %q", payload.DatabaseName),
+				Content:   common.ErrorMessage(err),
+			},
+		}, nil
+	}
+
+	return []api.TaskCheckResult{
+		{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   fmt.Sprintf("Database name %q is valid", payload.DatabaseName),
+		},
+	}, nil
+}
@@ -0,0 +1,346 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+// NewTaskCheckImpactAnalysisExecutor creates a task check impact analysis executor.
+func NewTaskCheckImpactAnalysisExecutor() TaskCheckExecutor {
+	return &TaskCheckImpactAnalysisExecutor{}
+}
+
+// TaskCheckImpactAnalysisExecutor is the task check impact analysis executor.
+// It lists the views, routines and foreign keys depending on the tables altered or dropped by the statement.
+type TaskCheckImpactAnalysisExecutor struct {
+}
+
+// impactedTable is a table altered or dropped by the statement.
+type impactedTable struct {
+	// schema is the schema for Postgres, and the database for MySQL.
+	schema string
+	name   string
+}
+
+func (t impactedTable) String() string {
+	return fmt.Sprintf("%s.%s", t.schema, t.name)
+}
+
+// Run will run the task check impact analysis executor once.
+func (*TaskCheckImpactAnalysisExecutor) Run(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	task, err := server.store.GetTaskByID(ctx, taskCheckRun.TaskID)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if task == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "task ID not found %v", taskCheckRun.TaskID)
+	}
+
+	payload := &api.TaskCheckDatabaseImpactAnalysisPayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return nil, common.Errorf(common.Invalid, "invalid check impact analysis payload: %w", err)
+	}
+
+	database, err := server.store.GetDatabase(ctx, &api.DatabaseFind{ID: task.DatabaseID})
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if database == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "database ID not found %v", task.DatabaseID)
+	}
+
+	tableList, err := getImpactedTableList(payload.DbType, payload.Statement, database.Name, payload.Charset, payload.Collation)
+	if err != nil {
+		//nolint:nilerr
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.AdvisorNamespace,
+				Code:      advisor.StatementSyntaxError.Int(),
+				Title:     "Syntax error",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	if len(tableList) > 0 {
+		driver, err := server.getAdminDatabaseDriver(ctx, database.Instance, database.Name)
+		if err != nil {
+			return []api.TaskCheckResult{
+				{
+					Status:    api.TaskCheckStatusError,
+					Namespace: api.BBNamespace,
+					Code:      common.DbConnectionFailure.Int(),
+					Title:     fmt.Sprintf("Failed to connect %q", database.Name),
+					Content:   err.Error(),
+				},
+			}, nil
+		}
+		defer driver.Close(ctx)
+
+		sqldb, err := driver.GetDBConnection(ctx, database.Name)
+		if err != nil {
+			return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+		}
+		for _, table := range tableList {
+			objectList, err := findDependentObjectList(ctx, payload.DbType, sqldb, table)
+			if err != nil {
+				return []api.TaskCheckResult{}, common.Errorf(common.DbExecutionError, "failed to find the objects depending on %q, error: %w", table.String(), err)
+			}
+			if len(objectList) == 0 {
+				continue
+			}
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusWarn,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskDependentObjectImpacted.Int(),
+				Title:     fmt.Sprintf("Objects depending on %q may break", table.String()),
+				Content:   fmt.Sprintf("%q is altered or dropped, check the dependent objects: %s", table.String(), strings.Join(objectList, ", ")),
+			})
+		}
+	}
+
+	if len(result) == 0 {
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   "",
+		})
+	}
+
+	return result, nil
+}
+
+// isImpactAnalysisSupported returns true if the dependency catalogs of the engine are supported.
+func isImpactAnalysisSupported(engine db.Type) bool {
+	return engine == db.Postgres || engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+}
+
+// getImpactedTableList returns the tables dropped, renamed, or having columns dropped, renamed or changed by the statement.
+func getImpactedTableList(dbType db.Type, statement, databaseName, charset, collation string) ([]impactedTable, error) {
+	var tableList []impactedTable
+	add := func(table impactedTable) {
+		for _, t := range tableList {
+			if t == table {
+				return
+			}
+		}
+		tableList = append(tableList, table)
+	}
+
+	switch dbType {
+	case db.Postgres:
+		nodeList, err := parser.Parse(parser.Postgres, parser.Context{}, statement)
+		if err != nil {
+			return nil, err
+		}
+		pgTable := func(table *ast.TableDef) impactedTable {
+			schema := table.Schema
+			if schema == "" {
+				schema = "public"
+			}
+			return impactedTable{schema: schema, name: table.Name}
+		}
+		for _, node := range nodeList {
+			switch node := node.(type) {
+			case *ast.DropTableStmt:
+				for _, table := range node.TableList {
+					add(pgTable(table))
+				}
+			case *ast.AlterTableStmt:
+				for _, item := range node.AlterItemList {
+					switch item.(type) {
+					case *ast.DropColumnStmt, *ast.AlterColumnTypeStmt, *ast.RenameColumnStmt, *ast.RenameTableStmt:
+						add(pgTable(node.Table))
+					}
+				}
+			}
+		}
+	case db.MySQL, db.TiDB, db.MariaDB:
+		p := tidbparser.New()
+		p.EnableWindowFunc(true)
+		nodeList, _, err := p.Parse(statement, charset, collation)
+		if err != nil {
+			return nil, err
+		}
+		mysqlTable := func(table *tidbast.TableName) impactedTable {
+			schema := table.Schema.O
+			if schema == "" {
+				schema = databaseName
+			}
+			return impactedTable{schema: schema, name: table.Name.O}
+		}
+		for _, node := range nodeList {
+			switch node := node.(type) {
+			case *tidbast.DropTableStmt:
+				for _, table := range node.Tables {
+					add(mysqlTable(table))
+				}
+			case *tidbast.RenameTableStmt:
+				for _, t := range node.TableToTables {
+					add(mysqlTable(t.OldTable))
+				}
+			case *tidbast.AlterTableStmt:
+				for _, spec := range node.Specs {
+					switch spec.Tp {
+					case tidbast.AlterTableDropColumn, tidbast.AlterTableModifyColumn, tidbast.AlterTableChangeColumn, tidbast.AlterTableRenameColumn, tidbast.AlterTableRenameTable:
+						add(mysqlTable(node.Table))
+					}
+				}
+			}
+		}
+	}
+	return tableList, nil
+}
+
+// findDependentObjectList returns the description of the views, routines and foreign keys depending on the table.
+// The routines are found by the table name in the routine body, since the routine dependencies aren't tracked by the catalog.
+func findDependentObjectList(ctx context.Context, dbType db.Type, sqldb *sql.DB, table impactedTable) ([]string, error) {
+	var objectList []string
+	query := func(query string, format func(values []sql.NullString) string, args ...interface{}) error {
+		rows, err := sqldb.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		columnList, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			values := make([]sql.NullString, len(columnList))
+			dest := make([]interface{}, len(columnList))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+			if object := format(values); object != "" {
+				objectList = append(objectList, object)
+			}
+		}
+		return rows.Err()
+	}
+
+	switch dbType {
+	case db.Postgres:
+		if err := query(`
+			SELECT DISTINCT vn.nspname, v.relname
+			FROM pg_depend d
+			JOIN pg_rewrite r ON r.oid = d.objid
+			JOIN pg_class v ON v.oid = r.ev_class
+			JOIN pg_namespace vn ON vn.oid = v.relnamespace
+			JOIN pg_class t ON t.oid = d.refobjid
+			JOIN pg_namespace tn ON tn.oid = t.relnamespace
+			WHERE d.classid = 'pg_rewrite'::regclass AND d.refclassid = 'pg_class'::regclass
+				AND tn.nspname = $1 AND t.relname = $2 AND v.oid <> t.oid`,
+			func(values []sql.NullString) string {
+				return fmt.Sprintf("view %s.%s", values[0].String, values[1].String)
+			},
+			table.schema, table.name,
+		); err != nil {
+			return nil, err
+		}
+		if err := query(`
+			SELECT c.conname, rn.nspname, r.relname
+			FROM pg_constraint c
+			JOIN pg_class r ON r.oid = c.conrelid
+			JOIN pg_namespace rn ON rn.oid = r.relnamespace
+			JOIN pg_class t ON t.oid = c.confrelid
+			JOIN pg_namespace tn ON tn.oid = t.relnamespace
+			WHERE c.contype = 'f' AND tn.nspname = $1 AND t.relname = $2 AND c.conrelid <> c.confrelid`,
+			func(values []sql.NullString) string {
+				return fmt.Sprintf("foreign key %s on %s.%s", values[0].String, values[1].String, values[2].String)
+			},
+			table.schema, table.name,
+		); err != nil {
+			return nil, err
+		}
+		if err := query(`
+			SELECT n.nspname, p.proname, p.prosrc
+			FROM pg_proc p
+			JOIN pg_namespace n ON n.oid = p.pronamespace
+			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema') AND p.prosrc ILIKE $1`,
+			func(values []sql.NullString) string {
+				if !hasIdentifier(values[2].String, table.name) {
+					return ""
+				}
+				return fmt.Sprintf("function %s.%s", values[0].String, values[1].String)
+			},
+			"%"+escapeLikePattern(table.name)+"%",
+		); err != nil {
+			return nil, err
+		}
+	case db.MySQL, db.TiDB, db.MariaDB:
+		if err := query(`
+			SELECT TABLE_SCHEMA, TABLE_NAME
+			FROM information_schema.VIEWS
+			WHERE VIEW_DEFINITION LIKE ?`,
+			func(values []sql.NullString) string {
+				return fmt.Sprintf("view %s.%s", values[0].String, values[1].String)
+			},
+			fmt.Sprintf("%%`%s`.`%s`%%", escapeLikePattern(table.schema), escapeLikePattern(table.name)),
+		); err != nil {
+			return nil, err
+		}
+		if err := query(`
+			SELECT DISTINCT CONSTRAINT_NAME, TABLE_SCHEMA, TABLE_NAME
+			FROM information_schema.KEY_COLUMN_USAGE
+			WHERE REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME = ?
+				AND NOT (TABLE_SCHEMA = REFERENCED_TABLE_SCHEMA AND TABLE_NAME = REFERENCED_TABLE_NAME)`,
+			func(values []sql.NullString) string {
+				return fmt.Sprintf("foreign key %s on %s.%s", values[0].String, values[1].String, values[2].String)
+			},
+			table.schema, table.name,
+		); err != nil {
+			return nil, err
+		}
+		if err := query(`
+			SELECT ROUTINE_TYPE, ROUTINE_SCHEMA, ROUTINE_NAME, ROUTINE_DEFINITION
+			FROM information_schema.ROUTINES
+			WHERE ROUTINE_SCHEMA = ? AND ROUTINE_DEFINITION LIKE ?`,
+			func(values []sql.NullString) string {
+				if !hasIdentifier(values[3].String, table.name) {
+					return ""
+				}
+				return fmt.Sprintf("%s %s.%s", strings.ToLower(values[0].String), values[1].String, values[2].String)
+			},
+			table.schema, "%"+escapeLikePattern(table.name)+"%",
+		); err != nil {
+			return nil, err
+		}
+	}
+	return objectList, nil
+}
+
+// hasIdentifier returns true if name appears as an identifier in the text, case-insensitively.
+func hasIdentifier(text, name string) bool {
+	name = strings.ToLower(name)
+	for _, identifier := range identifierRegexp.FindAllString(text, -1) {
+		if strings.ToLower(identifier) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeLikePattern escapes the wildcards of the LIKE pattern.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
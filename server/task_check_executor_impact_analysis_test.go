@@ -0,0 +1,57 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+
+	// Register the parsers.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+func TestGetImpactedTableList(t *testing.T) {
+	tests := []struct {
+		dbType    db.Type
+		statement string
+		want      []impactedTable
+	}{
+		{
+			dbType:    db.MySQL,
+			statement: "ALTER TABLE t1 ADD COLUMN c INT; ALTER TABLE t1 DROP COLUMN a; DROP TABLE other.t2; RENAME TABLE t3 TO t4; DROP TABLE t1;",
+			want: []impactedTable{
+				{schema: "db", name: "t1"},
+				{schema: "other", name: "t2"},
+				{schema: "db", name: "t3"},
+			},
+		},
+		{
+			dbType:    db.MySQL,
+			statement: "CREATE TABLE t1 (a INT); INSERT INTO t1 VALUES (1);",
+			want:      nil,
+		},
+		{
+			dbType:    db.Postgres,
+			statement: "ALTER TABLE t1 ADD COLUMN c INT; ALTER TABLE s.t1 ALTER COLUMN a TYPE TEXT; DROP TABLE t2; ALTER TABLE t3 RENAME TO t4;",
+			want: []impactedTable{
+				{schema: "s", name: "t1"},
+				{schema: "public", name: "t2"},
+				{schema: "public", name: "t3"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tableList, err := getImpactedTableList(test.dbType, test.statement, "db", "", "")
+		require.NoError(t, err)
+		require.Equal(t, test.want, tableList, test.statement)
+	}
+}
+
+func TestHasIdentifier(t *testing.T) {
+	require.True(t, hasIdentifier("SELECT * FROM Orders WHERE id = 1", "orders"))
+	require.True(t, hasIdentifier(`UPDATE public."orders" SET a = 1`, "orders"))
+	require.False(t, hasIdentifier("SELECT * FROM orders_archive", "orders"))
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+// NewTaskCheckMigrationDuplicateExecutor creates a task check migration duplicate executor.
+func NewTaskCheckMigrationDuplicateExecutor() TaskCheckExecutor {
+	return &TaskCheckMigrationDuplicateExecutor{}
+}
+
+// TaskCheckMigrationDuplicateExecutor is the task check migration duplicate executor.
+// It warns if the same statement has already been applied to the database by another issue,
+// e.g. the migration file is pushed to the VCS again.
+type TaskCheckMigrationDuplicateExecutor struct {
+}
+
+// migrationSimilarity is how similar two migration statements are.
+type migrationSimilarity int
+
+const (
+	migrationDifferent migrationSimilarity = iota
+	// migrationNearIdentical means the statements only differ in letter case, comments and whitespace.
+	migrationNearIdentical
+	// migrationIdentical means the statements only differ in comments and whitespace.
+	migrationIdentical
+)
+
+// Run will run the task check migration duplicate executor once.
+func (*TaskCheckMigrationDuplicateExecutor) Run(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	task, err := server.store.GetTaskByID(ctx, taskCheckRun.TaskID)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if task == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "task ID not found %v", taskCheckRun.TaskID)
+	}
+	if task.DatabaseID == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "task %q doesn't change a database", task.Name)
+	}
+
+	payload := &api.TaskCheckDatabaseMigrationDuplicatePayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return nil, common.Errorf(common.Invalid, "invalid check migration duplicate payload: %w", err)
+	}
+
+	statusList := []api.TaskStatus{api.TaskDone}
+	appliedTaskList, err := server.store.FindTask(ctx, &api.TaskFind{
+		DatabaseID: task.DatabaseID,
+		StatusList: &statusList,
+	}, true /* returnOnErr */)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	// Warns once for each issue.
+	checkedPipelineSet := map[int]bool{task.PipelineID: true}
+	for _, appliedTask := range appliedTaskList {
+		if checkedPipelineSet[appliedTask.PipelineID] {
+			continue
+		}
+		statement, err := getTaskStatement(appliedTask)
+		if err != nil {
+			return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+		}
+		similarity := getMigrationSimilarity(payload.DbType, payload.Statement, statement)
+		if similarity == migrationDifferent {
+			continue
+		}
+		checkedPipelineSet[appliedTask.PipelineID] = true
+
+		issue, err := server.store.GetIssueByPipelineID(ctx, appliedTask.PipelineID)
+		if err != nil {
+			return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+		}
+		appliedBy := fmt.Sprintf("task %q", appliedTask.Name)
+		if issue != nil {
+			appliedBy = fmt.Sprintf("task %q of issue #%d %q", appliedTask.Name, issue.ID, issue.Name)
+		}
+		title := "Identical migration already applied"
+		content := fmt.Sprintf("The same statement has been applied to the database by %s, make sure the migration isn't submitted twice.", appliedBy)
+		if similarity == migrationNearIdentical {
+			title = "Near-identical migration already applied"
+			content = fmt.Sprintf("A statement only differing in letter case has been applied to the database by %s, make sure the migration isn't submitted twice.", appliedBy)
+		}
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.TaskMigrationDuplicated.Int(),
+			Title:     title,
+			Content:   content,
+		})
+	}
+
+	if len(result) == 0 {
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   "",
+		})
+	}
+
+	return result, nil
+}
+
+// getMigrationSimilarity returns how similar the statements are, the empty statements are never duplicate.
+func getMigrationSimilarity(engine db.Type, statement, other string) migrationSimilarity {
+	a, b := canonicalizeMigrationStatement(engine, statement), canonicalizeMigrationStatement(engine, other)
+	switch {
+	case a == "" || b == "":
+		return migrationDifferent
+	case a == b:
+		return migrationIdentical
+	case strings.EqualFold(a, b):
+		return migrationNearIdentical
+	}
+	return migrationDifferent
+}
+
+// canonicalizeMigrationStatement returns the canonical statement, the whitespace is collapsed for the engines without parser support.
+func canonicalizeMigrationStatement(engine db.Type, statement string) string {
+	if engineType, ok := getParserEngineType(engine); ok {
+		if canonical, err := parser.CanonicalizeStatement(engineType, statement); err == nil {
+			return canonical
+		}
+	}
+	return strings.Join(strings.Fields(statement), " ")
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetMigrationSimilarity(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		other     string
+		want      migrationSimilarity
+	}{
+		{engine: db.MySQL, statement: "ALTER TABLE t ADD c INT;", other: "ALTER TABLE t ADD c INT;", want: migrationIdentical},
+		{engine: db.MySQL, statement: "ALTER TABLE t ADD c INT;", other: "-- Re-pushed\nALTER TABLE t\n  ADD c INT", want: migrationIdentical},
+		{engine: db.MySQL, statement: "ALTER TABLE t ADD c INT;", other: "alter table t add c int;", want: migrationNearIdentical},
+		{engine: db.MySQL, statement: "ALTER TABLE t ADD c INT;", other: "ALTER TABLE t ADD d INT;", want: migrationDifferent},
+		{engine: db.Postgres, statement: `CREATE INDEX idx ON t (c);`, other: `CREATE INDEX idx ON t(c) /* index */`, want: migrationIdentical},
+		{engine: db.Snowflake, statement: "ALTER TABLE t ADD c INT", other: "ALTER TABLE t\n\tADD c INT", want: migrationIdentical},
+		{engine: db.MySQL, statement: "", other: "-- comment only", want: migrationDifferent},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, getMigrationSimilarity(test.engine, test.statement, test.other), "%q vs %q", test.statement, test.other)
+	}
+}
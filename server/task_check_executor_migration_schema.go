@@ -40,6 +40,11 @@ func (*TaskCheckMigrationSchemaExecutor) Run(ctx context.Context, server *Server
 		return []api.TaskCheckResult{}, err
 	}
 
+	if instance.AgentID != nil {
+		// The agent sets up the migration schema before executing the migration.
+		return server.checkAgentAlive(ctx, *instance.AgentID)
+	}
+
 	driver, err := server.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
 	if err != nil {
 		return []api.TaskCheckResult{}, err
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// replicationLagPollInterval is the interval to measure the replication lag again before the cutover.
+const replicationLagPollInterval = time.Duration(30) * time.Second
+
+// NewTaskCheckReplicationLagExecutor creates a task check replication lag executor.
+func NewTaskCheckReplicationLagExecutor() TaskCheckExecutor {
+	return &TaskCheckReplicationLagExecutor{}
+}
+
+// TaskCheckReplicationLagExecutor is the task check executor measuring the replication lag before the cutover tasks.
+// It measures the lag of the replicas, and the delay of the binlog events pending to apply to the gh-ost ghost table.
+type TaskCheckReplicationLagExecutor struct {
+}
+
+// replicationLag is a measured lag.
+type replicationLag struct {
+	// name is the replica or the binlog stream the lag is measured on.
+	name string
+	// seconds is the lag in seconds, -1 if the replication is stopped.
+	seconds float64
+}
+
+// Run will run the task check replication lag executor once.
+func (*TaskCheckReplicationLagExecutor) Run(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	payload := &api.TaskCheckReplicationLagPayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Invalid, "invalid check replication lag payload: %w", err)
+	}
+	task, err := server.store.GetTaskByID(ctx, taskCheckRun.TaskID)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if task == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "task ID not found %v", taskCheckRun.TaskID)
+	}
+
+	lagList, err := getInstanceReplicationLag(ctx, server, task.Instance)
+	if err != nil {
+		//nolint:nilerr
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.DbConnectionFailure.Int(),
+				Title:     fmt.Sprintf("Failed to measure the replication lag of instance %q", task.Instance.Name),
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	if task.Type == api.TaskDatabaseSchemaUpdateGhostCutover {
+		lag, err := getGhostBinlogLag(ctx, server, task)
+		if err != nil {
+			//nolint:nilerr
+			return []api.TaskCheckResult{
+				{
+					Status:    api.TaskCheckStatusError,
+					Namespace: api.BBNamespace,
+					Code:      common.Internal.Int(),
+					Title:     "Failed to measure the gh-ost binlog lag",
+					Content:   err.Error(),
+				},
+			}, nil
+		}
+		lagList = append(lagList, lag)
+	}
+
+	return getReplicationLagCheckResult(lagList, payload.MaxLagSeconds), nil
+}
+
+// getReplicationLagCheckResult returns the check results of the lags against the threshold.
+func getReplicationLagCheckResult(lagList []replicationLag, maxLagSeconds int64) []api.TaskCheckResult {
+	var result []api.TaskCheckResult
+	var okList []string
+	for _, lag := range lagList {
+		switch {
+		case lag.seconds < 0:
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskReplicationLagExceeded.Int(),
+				Title:     "Replication is stopped",
+				Content:   fmt.Sprintf("The replication of %s is stopped, the cutover waits until it catches up.", lag.name),
+			})
+		case lag.seconds > float64(maxLagSeconds):
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskReplicationLagExceeded.Int(),
+				Title:     "Replication lag is too high",
+				Content:   fmt.Sprintf("%s lags %.1fs behind, the cutover waits until the lag is under %ds.", lag.name, lag.seconds, maxLagSeconds),
+			})
+		default:
+			okList = append(okList, fmt.Sprintf("%s (%.1fs)", lag.name, lag.seconds))
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	content := "No replica found"
+	if len(okList) > 0 {
+		content = fmt.Sprintf("The lag is under %ds: %s", maxLagSeconds, strings.Join(okList, ", "))
+	}
+	return []api.TaskCheckResult{
+		{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   content,
+		},
+	}
+}
+
+// getInstanceReplicationLag returns the replica lags measured on the instance.
+// For Postgres, the lags of the standbys are reported by the primary, and the replay delay is reported by a standby.
+// For MySQL, only the lag of the instance itself is known if it's a replica.
+func getInstanceReplicationLag(ctx context.Context, server *Server, instance *api.Instance) ([]replicationLag, error) {
+	driver, err := server.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	switch instance.Engine {
+	case db.Postgres:
+		return getPostgresReplicationLag(ctx, sqldb)
+	case db.MySQL, db.MariaDB:
+		return getMySQLReplicationLag(ctx, sqldb)
+	}
+	// TiDB replicates with TiCDC out of the instance, so there is no lag to measure on the instance.
+	return nil, nil
+}
+
+func getPostgresReplicationLag(ctx context.Context, sqldb *sql.DB) ([]replicationLag, error) {
+	var inRecovery bool
+	if err := sqldb.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return nil, err
+	}
+	if inRecovery {
+		// The replay delay is 0 if nothing is replayed yet, e.g. the primary has no writes.
+		var seconds float64
+		if err := sqldb.QueryRowContext(ctx, `
+			SELECT CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+				ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END`,
+		).Scan(&seconds); err != nil {
+			return nil, err
+		}
+		return []replicationLag{{name: "the instance", seconds: seconds}}, nil
+	}
+
+	rows, err := sqldb.QueryContext(ctx, `
+		SELECT COALESCE(application_name, ''), COALESCE(client_addr::TEXT, ''), COALESCE(EXTRACT(EPOCH FROM replay_lag), 0)
+		FROM pg_stat_replication
+		ORDER BY application_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var lagList []replicationLag
+	for rows.Next() {
+		var name, addr string
+		var seconds float64
+		if err := rows.Scan(&name, &addr, &seconds); err != nil {
+			return nil, err
+		}
+		lagList = append(lagList, replicationLag{
+			name:    fmt.Sprintf("replica %q", strings.TrimSpace(fmt.Sprintf("%s %s", name, addr))),
+			seconds: seconds,
+		})
+	}
+	return lagList, rows.Err()
+}
+
+func getMySQLReplicationLag(ctx context.Context, sqldb *sql.DB) ([]replicationLag, error) {
+	rows, err := sqldb.QueryContext(ctx, "SHOW SLAVE STATUS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var lagList []replicationLag
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]sql.NullString)
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		lag := replicationLag{
+			name:    fmt.Sprintf("the instance replicating from %q", row["Master_Host"].String),
+			seconds: -1,
+		}
+		// Seconds_Behind_Master is NULL if the replication is stopped.
+		if v := row["Seconds_Behind_Master"]; v.Valid {
+			if _, err := fmt.Sscanf(v.String, "%g", &lag.seconds); err != nil {
+				return nil, fmt.Errorf("invalid Seconds_Behind_Master %q: %w", v.String, err)
+			}
+		}
+		lagList = append(lagList, lag)
+	}
+	return lagList, rows.Err()
+}
+
+// getGhostBinlogLag returns the delay of the binlog events pending to apply to the ghost table of the gh-ost cutover task,
+// it's measured by the heartbeat gh-ost writes to the changelog table.
+func getGhostBinlogLag(ctx context.Context, server *Server, task *api.Task) (replicationLag, error) {
+	taskDAG, err := server.store.GetTaskDAGByToTaskID(ctx, task.ID)
+	if err != nil {
+		return replicationLag{}, fmt.Errorf("failed to get the gh-ost sync task, error: %w", err)
+	}
+	value, ok := server.TaskScheduler.sharedTaskState.Load(taskDAG.FromTaskID)
+	if !ok {
+		return replicationLag{}, fmt.Errorf("gh-ost sync is not running, rerun the sync task")
+	}
+	sharedGhost := value.(sharedGhostState)
+	return replicationLag{
+		name:    "the gh-ost binlog stream",
+		seconds: sharedGhost.migrationContext.TimeSinceLastHeartbeatOnChangelog().Seconds(),
+	}, nil
+}
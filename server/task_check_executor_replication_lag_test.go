@@ -0,0 +1,37 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReplicationLagCheckResult(t *testing.T) {
+	result := getReplicationLagCheckResult(nil, 10)
+	require.Len(t, result, 1)
+	require.Equal(t, api.TaskCheckStatusSuccess, result[0].Status)
+	require.Equal(t, "No replica found", result[0].Content)
+
+	result = getReplicationLagCheckResult([]replicationLag{
+		{name: `replica "r1"`, seconds: 2},
+		{name: "the gh-ost binlog stream", seconds: 0.5},
+	}, 10)
+	require.Len(t, result, 1)
+	require.Equal(t, api.TaskCheckStatusSuccess, result[0].Status)
+	require.Equal(t, `The lag is under 10s: replica "r1" (2.0s), the gh-ost binlog stream (0.5s)`, result[0].Content)
+
+	result = getReplicationLagCheckResult([]replicationLag{
+		{name: `replica "r1"`, seconds: 2},
+		{name: `replica "r2"`, seconds: 12.5},
+		{name: `the instance replicating from "10.0.0.1"`, seconds: -1},
+	}, 10)
+	require.Len(t, result, 2)
+	for _, r := range result {
+		require.Equal(t, api.TaskCheckStatusError, r.Status)
+		require.Equal(t, common.TaskReplicationLagExceeded.Int(), r.Code)
+	}
+	require.Equal(t, `replica "r2" lags 12.5s behind, the cutover waits until the lag is under 10s.`, result[0].Content)
+	require.Equal(t, `The replication of the instance replicating from "10.0.0.1" is stopped, the cutover waits until it catches up.`, result[1].Content)
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	// stageGatePollInterval is the interval to poll the stage gates again after they don't pass.
+	stageGatePollInterval = time.Duration(1) * time.Minute
+	stageGateTimeout      = time.Duration(10) * time.Second
+)
+
+// NewTaskCheckStageGateExecutor creates a task check stage gate executor.
+func NewTaskCheckStageGateExecutor() TaskCheckExecutor {
+	return &TaskCheckStageGateExecutor{
+		client: &http.Client{Timeout: stageGateTimeout},
+	}
+}
+
+// TaskCheckStageGateExecutor is the task check executor calling the external gate providers of the stage.
+// The gates are only checked before the stage starts, the later tasks of a started stage pass the check directly.
+type TaskCheckStageGateExecutor struct {
+	client *http.Client
+}
+
+// Run will run the task check stage gate executor once.
+func (exec *TaskCheckStageGateExecutor) Run(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	payload := &api.TaskCheckStageGatePayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Invalid, "invalid check stage gate payload: %w", err)
+	}
+
+	task, err := server.store.GetTaskByID(ctx, taskCheckRun.TaskID)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if task == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "task ID not found %v", taskCheckRun.TaskID)
+	}
+	stageList, err := server.store.FindStage(ctx, &api.StageFind{ID: &task.StageID})
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if len(stageList) == 0 {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "stage ID not found %v", task.StageID)
+	}
+	stage := stageList[0]
+
+	if isStageStarted(stage) {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.BBNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "OK",
+				Content:   fmt.Sprintf("Stage %q has already started", stage.Name),
+			},
+		}, nil
+	}
+
+	issue, err := server.store.GetIssueByPipelineID(ctx, task.PipelineID)
+	if err != nil {
+		return []api.TaskCheckResult{}, common.WithError(common.Internal, err)
+	}
+	if issue == nil {
+		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "issue not found by pipeline ID %v", task.PipelineID)
+	}
+
+	for _, gate := range payload.GateList {
+		resp, err := exec.callStageGate(ctx, gate, &api.StageGateRequest{
+			GateName:        gate.Name,
+			IssueID:         issue.ID,
+			IssueName:       issue.Name,
+			ProjectID:       issue.ProjectID,
+			PipelineID:      task.PipelineID,
+			StageID:         stage.ID,
+			StageName:       stage.Name,
+			EnvironmentID:   stage.EnvironmentID,
+			EnvironmentName: stage.Environment.Name,
+			Link:            fmt.Sprintf("%s:%d/issue/%s", server.profile.FrontendHost, server.profile.FrontendPort, api.IssueSlug(issue)),
+		})
+		if err != nil {
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskStageGateNotPassed.Int(),
+				Title:     fmt.Sprintf("Failed to call stage gate %q", gate.Name),
+				Content:   err.Error(),
+			})
+			continue
+		}
+		if !resp.Pass {
+			content := fmt.Sprintf("Stage gate %q hasn't passed yet, it will be checked again in %v", gate.Name, stageGatePollInterval)
+			if resp.Detail != "" {
+				content = fmt.Sprintf("%s: %s", content, resp.Detail)
+			}
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusWarn,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskStageGateNotPassed.Int(),
+				Title:     fmt.Sprintf("Waiting for stage gate %q", gate.Name),
+				Content:   content,
+			})
+			continue
+		}
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   fmt.Sprintf("Stage gate %q passed", gate.Name),
+		})
+	}
+	if len(result) == 0 {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.BBNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "OK",
+				Content:   "No stage gate is configured",
+			},
+		}, nil
+	}
+	return result, nil
+}
+
+// callStageGate posts the request to the stage gate provider, the provider must respond 200 with StageGateResponse.
+func (exec *TaskCheckStageGateExecutor) callStageGate(ctx context.Context, gate api.StageGate, request *api.StageGateRequest) (*api.StageGateResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stage gate request, error: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", gate.URL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct stage gate request %v, error: %w", gate.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := exec.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to POST stage gate %v, error: %w", gate.URL, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stage gate response %v, error: %w", gate.URL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to POST stage gate %v, status code: %d, response body: %s", gate.URL, resp.StatusCode, b)
+	}
+	gateResp := &api.StageGateResponse{}
+	if err := json.Unmarshal(b, gateResp); err != nil {
+		return nil, fmt.Errorf("malformed stage gate response %v, response body: %s, error: %w", gate.URL, b, err)
+	}
+	return gateResp, nil
+}
+
+// isStageStarted returns whether any task of the stage has started.
+func isStageStarted(stage *api.Stage) bool {
+	for _, task := range stage.TaskList {
+		switch task.Status {
+		case api.TaskRunning, api.TaskDone, api.TaskFailed:
+			return true
+		}
+	}
+	return false
+}
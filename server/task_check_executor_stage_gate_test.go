@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallStageGate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &api.StageGateRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/pass":
+			fmt.Fprintf(w, `{"pass":true,"detail":"%s passed in %s"}`, request.GateName, request.EnvironmentName)
+		case "/fail":
+			fmt.Fprint(w, `{"pass":false,"detail":"2 QA cases failed"}`)
+		case "/malformed":
+			fmt.Fprint(w, `pass`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	exec := NewTaskCheckStageGateExecutor().(*TaskCheckStageGateExecutor)
+	request := &api.StageGateRequest{GateName: "QA suite", EnvironmentName: "Staging"}
+
+	resp, err := exec.callStageGate(context.Background(), api.StageGate{Name: "QA suite", URL: ts.URL + "/pass"}, request)
+	require.NoError(t, err)
+	require.Equal(t, &api.StageGateResponse{Pass: true, Detail: "QA suite passed in Staging"}, resp)
+
+	resp, err = exec.callStageGate(context.Background(), api.StageGate{Name: "QA suite", URL: ts.URL + "/fail"}, request)
+	require.NoError(t, err)
+	require.Equal(t, &api.StageGateResponse{Pass: false, Detail: "2 QA cases failed"}, resp)
+
+	_, err = exec.callStageGate(context.Background(), api.StageGate{Name: "QA suite", URL: ts.URL + "/malformed"}, request)
+	require.Error(t, err)
+
+	_, err = exec.callStageGate(context.Background(), api.StageGate{Name: "QA suite", URL: ts.URL + "/unknown"}, request)
+	require.Error(t, err)
+}
+
+func TestIsStageStarted(t *testing.T) {
+	stage := &api.Stage{
+		TaskList: []*api.Task{
+			{Status: api.TaskPending},
+			{Status: api.TaskPendingApproval},
+		},
+	}
+	require.False(t, isStageStarted(stage))
+
+	stage.TaskList[0].Status = api.TaskDone
+	require.True(t, isStageStarted(stage))
+}
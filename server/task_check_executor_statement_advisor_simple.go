@@ -33,7 +33,7 @@ func (*TaskCheckStatementAdvisorSimpleExecutor) Run(_ context.Context, _ *Server
 		advisorType = advisor.Fake
 	case api.TaskCheckDatabaseStatementSyntax:
 		switch payload.DbType {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB:
 			advisorType = advisor.MySQLSyntax
 		case db.Postgres:
 			advisorType = advisor.PostgreSQLSyntax
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/advisor/external"
+)
+
+// NewTaskCheckStatementExternalAdvisorExecutor creates a task check statement external advisor executor.
+func NewTaskCheckStatementExternalAdvisorExecutor() TaskCheckExecutor {
+	return &TaskCheckStatementExternalAdvisorExecutor{}
+}
+
+// TaskCheckStatementExternalAdvisorExecutor is the task check executor consulting the external policy engine of the workspace.
+type TaskCheckStatementExternalAdvisorExecutor struct {
+}
+
+// Run will run the task check statement external advisor executor once.
+func (*TaskCheckStatementExternalAdvisorExecutor) Run(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun) (result []api.TaskCheckResult, err error) {
+	payload := &api.TaskCheckDatabaseStatementExternalAdvisePayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return nil, common.Errorf(common.Invalid, "invalid check statement external advise payload: %w", err)
+	}
+
+	setting, err := server.getExternalAdvisorSetting(ctx)
+	if err != nil {
+		return nil, common.Errorf(common.Internal, "failed to get external advisor setting: %w", err)
+	}
+	if !setting.Enabled() {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.BBNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "OK",
+				Content:   "External advisor is disabled",
+			},
+		}, nil
+	}
+
+	request, err := server.composeExternalAdvisorRequest(ctx, taskCheckRun.TaskID, payload)
+	if err != nil {
+		return nil, err
+	}
+	adviceList, err := external.NewClient(setting.GetTimeout()).Check(ctx, setting.Engine, setting.URL, request)
+	if err != nil {
+		// The failure of the external policy engine blocks the task unless the workspace chooses to fail open.
+		status := api.TaskCheckStatusError
+		if setting.FailOpen {
+			status = api.TaskCheckStatusWarn
+		}
+		return []api.TaskCheckResult{
+			{
+				Status:    status,
+				Namespace: api.AdvisorNamespace,
+				Code:      advisor.Internal.Int(),
+				Title:     "Failed to consult the external advisor",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	result = []api.TaskCheckResult{}
+	for _, advice := range adviceList {
+		status := api.TaskCheckStatusSuccess
+		switch advice.Status {
+		case advisor.Success:
+			continue
+		case advisor.Warn:
+			status = api.TaskCheckStatusWarn
+		case advisor.Error:
+			status = api.TaskCheckStatusError
+		}
+
+		result = append(result, api.TaskCheckResult{
+			Status:    status,
+			Namespace: api.AdvisorNamespace,
+			Code:      advice.Code.Int(),
+			Title:     advice.Title,
+			Content:   advice.Content,
+		})
+	}
+
+	if len(result) == 0 {
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   "",
+		})
+	}
+
+	return result, nil
+}
+
+// composeExternalAdvisorRequest composes the request to the external policy engine with the parsed statements and the context of the task.
+func (s *Server) composeExternalAdvisorRequest(ctx context.Context, taskID int, payload *api.TaskCheckDatabaseStatementExternalAdvisePayload) (*external.Request, error) {
+	task, err := s.store.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, common.Errorf(common.Internal, "failed to get task by id: %w", err)
+	}
+	if task == nil {
+		return nil, common.Errorf(common.Internal, "task ID not found %v", taskID)
+	}
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: task.DatabaseID})
+	if err != nil {
+		return nil, common.Errorf(common.Internal, "failed to get database: %w", err)
+	}
+	if database == nil {
+		return nil, common.Errorf(common.Internal, "database ID not found %v", task.DatabaseID)
+	}
+	issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
+	if err != nil {
+		return nil, common.Errorf(common.Internal, "failed to get issue: %w", err)
+	}
+	if issue == nil {
+		return nil, common.Errorf(common.Internal, "issue not found by pipeline ID %v", task.PipelineID)
+	}
+
+	labelMap := make(map[string]string)
+	if database.Labels != "" {
+		var labelList []*api.DatabaseLabel
+		if err := json.Unmarshal([]byte(database.Labels), &labelList); err != nil {
+			return nil, common.Errorf(common.Internal, "failed to unmarshal labels of database %q: %w", database.Name, err)
+		}
+		for _, label := range labelList {
+			labelMap[label.Key] = label.Value
+		}
+	}
+	creator := ""
+	if issue.Creator != nil {
+		creator = issue.Creator.Email
+	}
+
+	return &external.Request{
+		Statement:     payload.Statement,
+		StatementList: external.ParseStatementList(advisorDB.Type(payload.DbType), payload.Statement, payload.Charset, payload.Collation),
+		Context: external.RequestContext{
+			Engine:      string(payload.DbType),
+			Charset:     payload.Charset,
+			Collation:   payload.Collation,
+			TaskType:    string(task.Type),
+			Environment: database.Instance.Environment.Name,
+			Instance:    database.Instance.Name,
+			Database:    database.Name,
+			Labels:      labelMap,
+			Tags:        api.GetPolicyTagList(database.Instance, database),
+			Project:     database.Project.Name,
+			IssueID:     issue.ID,
+			IssueName:   issue.Name,
+			Creator:     creator,
+		},
+	}, nil
+}
+
+// getExternalAdvisorSetting returns the workspace external advisor setting, or the disabled one if it's not set.
+func (s *Server) getExternalAdvisorSetting(ctx context.Context) (*api.ExternalAdvisorSetting, error) {
+	name := api.SettingWorkspaceExternalAdvisor
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+	setting := &api.ExternalAdvisorSetting{}
+	if len(settingList) == 0 {
+		return setting, nil
+	}
+	if err := json.Unmarshal([]byte(settingList[0].Value), setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal external advisor setting %q, error: %w", settingList[0].Value, err)
+	}
+	return setting, nil
+}
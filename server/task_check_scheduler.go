@@ -9,7 +9,6 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
-	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
 	"go.uber.org/zap"
 )
@@ -34,7 +33,7 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 	ticker := time.NewTicker(taskSchedulerInterval)
 	defer ticker.Stop()
 	defer wg.Done()
-	log.Debug(fmt.Sprintf("Task check scheduler started and will run every %v", taskSchedulerInterval))
+	schedulerLog.Debug(fmt.Sprintf("Task check scheduler started and will run every %v", taskSchedulerInterval))
 	runningTaskChecks := make(map[int]bool)
 	mu := sync.RWMutex{}
 	for {
@@ -47,7 +46,7 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 						if !ok {
 							err = fmt.Errorf("%v", r)
 						}
-						log.Error("Task check scheduler PANIC RECOVER", zap.Error(err))
+						schedulerLog.Error("Task check scheduler PANIC RECOVER", zap.Error(err))
 					}
 				}()
 
@@ -60,13 +59,13 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 				}
 				taskCheckRunList, err := s.server.store.FindTaskCheckRun(ctx, taskCheckRunFind)
 				if err != nil {
-					log.Error("Failed to retrieve running tasks", zap.Error(err))
+					schedulerLog.Error("Failed to retrieve running tasks", zap.Error(err))
 					return
 				}
 				for _, taskCheckRun := range taskCheckRunList {
 					executor, ok := s.executors[taskCheckRun.Type]
 					if !ok {
-						log.Error("Skip running task check run with unknown type",
+						schedulerLog.Error("Skip running task check run with unknown type",
 							zap.Int("id", taskCheckRun.ID),
 							zap.Int("task_id", taskCheckRun.TaskID),
 							zap.String("type", string(taskCheckRun.Type)),
@@ -95,7 +94,7 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								ResultList: checkResultList,
 							})
 							if err != nil {
-								log.Error("Failed to marshal task check run result",
+								schedulerLog.Error("Failed to marshal task check run result",
 									zap.Int("id", taskCheckRun.ID),
 									zap.Int("task_id", taskCheckRun.TaskID),
 									zap.String("type", string(taskCheckRun.Type)),
@@ -113,7 +112,7 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							}
 							_, err = s.server.store.PatchTaskCheckRunStatus(ctx, taskCheckRunStatusPatch)
 							if err != nil {
-								log.Error("Failed to mark task check run as DONE",
+								schedulerLog.Error("Failed to mark task check run as DONE",
 									zap.Int("id", taskCheckRun.ID),
 									zap.Int("task_id", taskCheckRun.TaskID),
 									zap.String("type", string(taskCheckRun.Type)),
@@ -121,7 +120,7 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								)
 							}
 						} else {
-							log.Warn("Failed to run task check",
+							schedulerLog.Warn("Failed to run task check",
 								zap.Int("id", taskCheckRun.ID),
 								zap.Int("task_id", taskCheckRun.TaskID),
 								zap.String("type", string(taskCheckRun.Type)),
@@ -131,7 +130,7 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								Detail: err.Error(),
 							})
 							if marshalErr != nil {
-								log.Error("Failed to marshal task check run result",
+								schedulerLog.Error("Failed to marshal task check run result",
 									zap.Int("id", taskCheckRun.ID),
 									zap.Int("task_id", taskCheckRun.TaskID),
 									zap.String("type", string(taskCheckRun.Type)),
@@ -149,7 +148,7 @@ func (s *TaskCheckScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							}
 							_, err = s.server.store.PatchTaskCheckRunStatus(ctx, taskCheckRunStatusPatch)
 							if err != nil {
-								log.Error("Failed to mark task check run as FAILED",
+								schedulerLog.Error("Failed to mark task check run as FAILED",
 									zap.Int("id", taskCheckRun.ID),
 									zap.Int("task_id", taskCheckRun.TaskID),
 									zap.String("type", string(taskCheckRun.Type)),
@@ -218,6 +217,48 @@ func (s *TaskCheckScheduler) shouldScheduleTimingTaskCheck(ctx context.Context,
 	return false, nil
 }
 
+// Returns true if we meet either of the following conditions for the polling task check, e.g. the stage gates and the replication lag:
+//   1. No task check of the type has run before (so we are about to kick off the check for the first time)
+//   2. The latest check didn't pass and has finished for pollInterval, so we need to poll again.
+//   3. The latest check passed but has finished for pollInterval, and repollPassed is true because the checked state may change.
+func (s *TaskCheckScheduler) shouldSchedulePollingTaskCheck(ctx context.Context, task *api.Task, taskCheckType api.TaskCheckType, pollInterval time.Duration, forceSchedule, repollPassed bool) (bool, error) {
+	statusList := []api.TaskCheckRunStatus{api.TaskCheckRunDone, api.TaskCheckRunFailed, api.TaskCheckRunRunning}
+	taskCheckRunFind := &api.TaskCheckRunFind{
+		TaskID:     &task.ID,
+		Type:       &taskCheckType,
+		StatusList: &statusList,
+		Latest:     true,
+	}
+	taskCheckRunList, err := s.server.store.FindTaskCheckRun(ctx, taskCheckRunFind)
+	if err != nil {
+		return false, err
+	}
+	if len(taskCheckRunList) == 0 || forceSchedule {
+		return true, nil
+	}
+
+	taskCheckRun := taskCheckRunList[0]
+	if taskCheckRun.Status == api.TaskCheckRunRunning {
+		return false, nil
+	}
+	if taskCheckRun.Status == api.TaskCheckRunDone && !repollPassed {
+		checkResult := &api.TaskCheckRunResultPayload{}
+		if err := json.Unmarshal([]byte(taskCheckRun.Result), checkResult); err != nil {
+			return false, err
+		}
+		passed := true
+		for _, result := range checkResult.ResultList {
+			if result.Status != api.TaskCheckStatusSuccess {
+				passed = false
+			}
+		}
+		if passed {
+			return false, nil
+		}
+	}
+	return time.Since(time.Unix(taskCheckRun.UpdatedTs, 0)) >= pollInterval, nil
+}
+
 // ScheduleCheckIfNeeded schedules a check if needed.
 func (s *TaskCheckScheduler) ScheduleCheckIfNeeded(ctx context.Context, task *api.Task, creatorID int, skipIfAlreadyTerminated bool) (*api.Task, error) {
 	// the following block is for timing task check
@@ -248,6 +289,155 @@ func (s *TaskCheckScheduler) ScheduleCheckIfNeeded(ctx context.Context, task *ap
 		}
 	}
 
+	// the following block is for stage gate task check
+	{
+		stageGatePolicy, err := s.server.store.GetStageGatePolicy(ctx, task.Instance.EnvironmentID, api.GetPolicyTagList(task.Instance, task.Database))
+		if err != nil {
+			return nil, err
+		}
+		if len(stageGatePolicy.GateList) > 0 {
+			flag, err := s.shouldSchedulePollingTaskCheck(ctx, task, api.TaskCheckGeneralStageGate, stageGatePollInterval, !skipIfAlreadyTerminated /* forceSchedule */, false /* repollPassed */)
+			if err != nil {
+				return nil, err
+			}
+
+			if flag {
+				taskCheckPayload, err := json.Marshal(api.TaskCheckStageGatePayload{
+					GateList: stageGatePolicy.GateList,
+				})
+				if err != nil {
+					return nil, err
+				}
+				_, err = s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+					CreatorID:               creatorID,
+					TaskID:                  task.ID,
+					Type:                    api.TaskCheckGeneralStageGate,
+					Payload:                 string(taskCheckPayload),
+					SkipIfAlreadyTerminated: false,
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// the following block is for replication lag task check
+	if task.Type == api.TaskDatabaseSchemaUpdateGhostCutover || task.Type == api.TaskDatabasePITRCutover {
+		replicationLagPolicy, err := s.server.store.GetReplicationLagPolicy(ctx, task.Instance.EnvironmentID, api.GetPolicyTagList(task.Instance, task.Database))
+		if err != nil {
+			return nil, err
+		}
+		// The lag changes over time, so the passed check is measured again once the task is approved and about to run.
+		flag, err := s.shouldSchedulePollingTaskCheck(ctx, task, api.TaskCheckDatabaseReplicationLag, replicationLagPollInterval, !skipIfAlreadyTerminated /* forceSchedule */, task.Status == api.TaskPending /* repollPassed */)
+		if err != nil {
+			return nil, err
+		}
+
+		if flag {
+			taskCheckPayload, err := json.Marshal(api.TaskCheckReplicationLagPayload{
+				MaxLagSeconds: replicationLagPolicy.MaxLagSeconds,
+			})
+			if err != nil {
+				return nil, err
+			}
+			_, err = s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+				CreatorID:               creatorID,
+				TaskID:                  task.ID,
+				Type:                    api.TaskCheckDatabaseReplicationLag,
+				Payload:                 string(taskCheckPayload),
+				SkipIfAlreadyTerminated: false,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if task.Type == api.TaskDatabaseCreate {
+		taskPayload := &api.TaskDatabaseCreatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+			return nil, fmt.Errorf("invalid database create payload: %w", err)
+		}
+		instance, err := s.server.store.GetInstanceByID(ctx, task.InstanceID)
+		if err != nil {
+			return nil, err
+		}
+		if instance == nil {
+			return nil, fmt.Errorf("instance ID not found %v", task.InstanceID)
+		}
+		// The tasks created before the project is recorded in the payload have no database name pattern.
+		pattern := ""
+		project, err := s.server.store.GetProjectByID(ctx, taskPayload.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if project != nil {
+			pattern = project.DBNamePattern
+		}
+
+		payload, err := json.Marshal(api.TaskCheckDatabaseCreateNamePayload{
+			DatabaseName: taskPayload.DatabaseName,
+			DbType:       instance.Engine,
+			Pattern:      pattern,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal database name payload: %v, err: %w", task.Name, err)
+		}
+		if _, err := s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+			CreatorID:               creatorID,
+			TaskID:                  task.ID,
+			Type:                    api.TaskCheckDatabaseCreateName,
+			Payload:                 string(payload),
+			SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
+		}); err != nil {
+			return nil, err
+		}
+
+		taskCheckRunList, err := s.server.store.FindTaskCheckRun(ctx, &api.TaskCheckRunFind{
+			TaskID: &task.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		task.TaskCheckRunList = taskCheckRunList
+
+		return task, nil
+	}
+
+	if task.Type == api.TaskDatabaseDrop {
+		taskPayload := &api.TaskDatabaseDropPayload{}
+		if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+			return nil, fmt.Errorf("invalid database drop payload: %w", err)
+		}
+		payload, err := json.Marshal(api.TaskCheckDatabaseDropActivityPayload{
+			DatabaseName: taskPayload.DatabaseName,
+			DbType:       task.Instance.Engine,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal database activity payload: %v, err: %w", task.Name, err)
+		}
+		if _, err := s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+			CreatorID:               creatorID,
+			TaskID:                  task.ID,
+			Type:                    api.TaskCheckDatabaseDropActivity,
+			Payload:                 string(payload),
+			SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
+		}); err != nil {
+			return nil, err
+		}
+
+		taskCheckRunList, err := s.server.store.FindTaskCheckRun(ctx, &api.TaskCheckRunFind{
+			TaskID: &task.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		task.TaskCheckRunList = taskCheckRunList
+
+		return task, nil
+	}
+
 	if task.Type == api.TaskDatabaseSchemaUpdate || task.Type == api.TaskDatabaseDataUpdate || task.Type == api.TaskDatabaseSchemaUpdateGhostSync {
 		statement := ""
 
@@ -335,7 +525,7 @@ func (s *TaskCheckScheduler) ScheduleCheckIfNeeded(ctx context.Context, task *ap
 		}
 
 		if s.server.feature(api.FeatureSQLReviewPolicy) && api.IsSQLReviewSupported(database.Instance.Engine, s.server.profile.Mode) {
-			policyID, err := s.server.store.GetSQLReviewPolicyIDByEnvID(ctx, task.Instance.EnvironmentID)
+			policyID, err := s.server.store.GetSQLReviewPolicyIDByEnvID(ctx, task.Instance.EnvironmentID, api.GetPolicyTagList(task.Instance, database))
 			if err != nil {
 				return nil, fmt.Errorf("failed to get SQL review policy ID for task: %v, in environment: %v, err: %w", task.Name, task.Instance.EnvironmentID, err)
 			}
@@ -360,6 +550,31 @@ func (s *TaskCheckScheduler) ScheduleCheckIfNeeded(ctx context.Context, task *ap
 			}
 		}
 
+		externalAdvisorSetting, err := s.server.getExternalAdvisorSetting(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get external advisor setting for task: %v, err: %w", task.Name, err)
+		}
+		if externalAdvisorSetting.Enabled() {
+			payload, err := json.Marshal(api.TaskCheckDatabaseStatementExternalAdvisePayload{
+				Statement: statement,
+				DbType:    database.Instance.Engine,
+				Charset:   database.CharacterSet,
+				Collation: database.Collation,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal statement external advise payload: %v, err: %w", task.Name, err)
+			}
+			if _, err := s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+				CreatorID:               creatorID,
+				TaskID:                  task.ID,
+				Type:                    api.TaskCheckDatabaseStatementExternalAdvise,
+				Payload:                 string(payload),
+				SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
+			}); err != nil {
+				return nil, err
+			}
+		}
+
 		if database.Instance.Engine == db.Postgres {
 			payload, err := json.Marshal(api.TaskCheckDatabaseStatementTypePayload{
 				Statement: statement,
@@ -379,6 +594,45 @@ func (s *TaskCheckScheduler) ScheduleCheckIfNeeded(ctx context.Context, task *ap
 			}
 		}
 
+		// The server can't query the dependency catalogs of the instance behind an agent.
+		if isImpactAnalysisSupported(database.Instance.Engine) && database.Instance.AgentID == nil {
+			payload, err := json.Marshal(api.TaskCheckDatabaseImpactAnalysisPayload{
+				Statement: statement,
+				DbType:    database.Instance.Engine,
+				Charset:   database.CharacterSet,
+				Collation: database.Collation,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal impact analysis payload: %v, err: %w", task.Name, err)
+			}
+			if _, err := s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+				CreatorID:               creatorID,
+				TaskID:                  task.ID,
+				Type:                    api.TaskCheckDatabaseImpactAnalysis,
+				Payload:                 string(payload),
+				SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		payload, err := json.Marshal(api.TaskCheckDatabaseMigrationDuplicatePayload{
+			Statement: statement,
+			DbType:    database.Instance.Engine,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal migration duplicate payload: %v, err: %w", task.Name, err)
+		}
+		if _, err := s.server.store.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+			CreatorID:               creatorID,
+			TaskID:                  task.ID,
+			Type:                    api.TaskCheckDatabaseMigrationDuplicate,
+			Payload:                 string(payload),
+			SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
+		}); err != nil {
+			return nil, err
+		}
+
 		taskCheckRunFind := &api.TaskCheckRunFind{
 			TaskID: &task.ID,
 		}
@@ -412,7 +666,7 @@ func (s *Server) passCheck(ctx context.Context, task *api.Task, checkType api.Ta
 	}
 
 	if len(taskCheckRunList) == 0 || taskCheckRunList[0].Status == api.TaskCheckRunFailed {
-		log.Debug("Task is waiting for check to pass",
+		schedulerLog.Debug("Task is waiting for check to pass",
 			zap.Int("task_id", task.ID),
 			zap.String("task_name", task.Name),
 			zap.String("task_type", string(task.Type)),
@@ -427,7 +681,7 @@ func (s *Server) passCheck(ctx context.Context, task *api.Task, checkType api.Ta
 	}
 	for _, result := range checkResult.ResultList {
 		if result.Status.LessThan(allowedStatus) {
-			log.Debug("Task is waiting for check to pass",
+			schedulerLog.Debug("Task is waiting for check to pass",
 				zap.Int("task_id", task.ID),
 				zap.String("task_name", task.Name),
 				zap.String("task_type", string(task.Type)),
@@ -51,7 +51,7 @@ func RunTaskExecutorOnce(ctx context.Context, exec TaskExecutor, server *Server,
 	return exec.RunOnce(ctx, server, task)
 }
 
-func preMigration(ctx context.Context, server *Server, task *api.Task, migrationType db.MigrationType, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent) (*db.MigrationInfo, error) {
+func preMigration(ctx context.Context, server *Server, task *api.Task, migrationType db.MigrationType, statement, downStatement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent) (*db.MigrationInfo, error) {
 	if task.Database == nil {
 		msg := "missing database when updating schema"
 		if migrationType == db.Data {
@@ -86,7 +86,8 @@ func preMigration(ctx context.Context, server *Server, task *api.Task, migration
 		if err != nil {
 			return nil, err
 		}
-		mi, err = db.ParseMigrationInfo(
+		mi, err = db.ParseMigrationFile(
+			repo.FileFormat,
 			vcsPushEvent.FileCommit.Added,
 			filepath.Join(vcsPushEvent.BaseDirectory, repo.FilePathTemplate),
 		)
@@ -95,13 +96,17 @@ func preMigration(ctx context.Context, server *Server, task *api.Task, migration
 			return nil, fmt.Errorf("failed to prepare for database migration, error: %w", err)
 		}
 		mi.Creator = vcsPushEvent.FileCommit.AuthorName
+	}
 
-		miPayload := &db.MigrationInfoPayload{
-			VCSPushEvent: vcsPushEvent,
-		}
+	// Record the down statement in the migration history, so the migration can be reverted later.
+	miPayload := &db.MigrationInfoPayload{
+		VCSPushEvent:  vcsPushEvent,
+		DownStatement: strings.TrimSpace(downStatement),
+	}
+	if miPayload.VCSPushEvent != nil || miPayload.DownStatement != "" {
 		bytes, err := json.Marshal(miPayload)
 		if err != nil {
-			return nil, fmt.Errorf("failed to prepare for database migration, unable to marshal vcs push event payload, error: %w", err)
+			return nil, fmt.Errorf("failed to prepare for database migration, unable to marshal migration info payload, error: %w", err)
 		}
 		mi.Payload = string(bytes)
 	}
@@ -135,6 +140,22 @@ func executeMigration(ctx context.Context, server *Server, task *api.Task, state
 	statement = strings.TrimSpace(statement)
 	databaseName := task.Database.Name
 
+	if task.Instance.AgentID != nil {
+		result, err := server.runAgentTask(ctx, task.Instance, api.AgentTaskDatabaseMigrate, &api.AgentTaskPayload{
+			Database:       databaseName,
+			Statement:      statement,
+			MigrationInfo:  mi,
+			SessionSetting: db.SessionSettingFromContext(ctx),
+		})
+		if err != nil {
+			return 0, "", err
+		}
+		if result.Migrate == nil {
+			return 0, "", fmt.Errorf("missing migration result from the agent")
+		}
+		return result.Migrate.MigrationID, result.Migrate.Schema, nil
+	}
+
 	driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, databaseName)
 	if err != nil {
 		return 0, "", err
@@ -159,6 +180,10 @@ func executeMigration(ctx context.Context, server *Server, task *api.Task, state
 
 	migrationID, schema, err = driver.ExecuteMigration(ctx, mi, statement)
 	if err != nil {
+		if mi.Type == db.Migrate && !driver.Capabilities().TransactionalDDL {
+			// The DDL statements are committed one by one, so the ones before the failed statement stay applied.
+			return 0, "", fmt.Errorf("%w, the statements before the failed one may have been applied since %s commits DDL statements implicitly", err, task.Instance.Engine)
+		}
 		return 0, "", err
 	}
 	return migrationID, schema, nil
@@ -288,6 +313,9 @@ func postMigration(ctx context.Context, server *Server, task *api.Task, vcsPushE
 		}
 	}
 
+	server.recordSchemaSnapshot(ctx, task.Instance, task.Database.ID, api.SchemaSnapshotMigration, mi.Version, schema)
+	server.publishSchemaChangeEvent(ctx, task, issue, project, mi, migrationID)
+
 	detail := fmt.Sprintf("Applied migration version %s to database %q.", mi.Version, databaseName)
 	if mi.Type == db.Baseline {
 		detail = fmt.Sprintf("Established baseline version %s for database %q.", mi.Version, databaseName)
@@ -300,16 +328,151 @@ func postMigration(ctx context.Context, server *Server, task *api.Task, vcsPushE
 	}, nil
 }
 
-func runMigration(ctx context.Context, server *Server, task *api.Task, migrationType db.MigrationType, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent) (terminated bool, result *api.TaskRunResultPayload, err error) {
-	mi, err := preMigration(ctx, server, task, migrationType, statement, schemaVersion, vcsPushEvent)
+func runMigration(ctx context.Context, server *Server, task *api.Task, migrationType db.MigrationType, statement, downStatement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent, verification *api.TaskVerification, sessionSetting *db.SessionSetting) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	// Resolve the statement variables first, so that the migration history records the statement executed on the database.
+	if task.Database != nil {
+		if statement, err = resolveMigrationStatement(ctx, server, task.Database, statement); err != nil {
+			return true, nil, err
+		}
+		if downStatement, err = resolveMigrationStatement(ctx, server, task.Database, downStatement); err != nil {
+			return true, nil, err
+		}
+	}
+	// The task settings exceeding the caps of the environment fail the task before touching the database.
+	sessionSettingPolicy, err := server.store.GetSessionSettingPolicy(ctx, task.Instance.EnvironmentID, api.GetPolicyTagList(task.Instance, task.Database))
 	if err != nil {
 		return true, nil, err
 	}
-	migrationID, schema, err := executeMigration(ctx, server, task, statement, mi)
+	if sessionSetting, err = sessionSettingPolicy.Resolve(task.Instance.Engine, sessionSetting); err != nil {
+		return true, nil, common.Errorf(common.Invalid, "invalid session settings, error: %v", err)
+	}
+	mi, err := preMigration(ctx, server, task, migrationType, statement, downStatement, schemaVersion, vcsPushEvent)
 	if err != nil {
 		return true, nil, err
 	}
-	return postMigration(ctx, server, task, vcsPushEvent, mi, migrationID, schema)
+	// The driver applies the session settings carried by the context before executing the statement,
+	// and so does the agent with the settings passed along with the statement.
+	migrationID, schema, err := executeMigration(db.WithSessionSetting(ctx, sessionSetting), server, task, statement, mi)
+	if err != nil {
+		return true, nil, err
+	}
+	terminated, result, err = postMigration(ctx, server, task, vcsPushEvent, mi, migrationID, schema)
+	if err != nil {
+		return terminated, result, err
+	}
+
+	// The foreign keys are still enforced for the new rows if the validation fails, so the failure doesn't fail the task.
+	validated, err := validateForeignKeys(ctx, server, task, statement)
+	if err != nil {
+		log.Warn("Failed to validate foreign keys after migration",
+			zap.Int("task_id", task.ID),
+			zap.String("database", task.Database.Name),
+			zap.Error(err),
+		)
+		result.Detail = fmt.Sprintf("%s Failed to validate foreign keys: %v.", result.Detail, err)
+	} else if validated > 0 {
+		result.Detail = fmt.Sprintf("%s Validated %d foreign key(s).", result.Detail, validated)
+	}
+
+	// The stale statistics only degrade the query plans, so the failure doesn't fail the task.
+	count, err := refreshStatistics(ctx, server, task, statement)
+	if err != nil {
+		log.Warn("Failed to refresh statistics after migration",
+			zap.Int("task_id", task.ID),
+			zap.String("database", task.Database.Name),
+			zap.Error(err),
+		)
+		result.Detail = fmt.Sprintf("%s Failed to refresh statistics: %v.", result.Detail, err)
+	} else if count > 0 {
+		result.Detail = fmt.Sprintf("%s Refreshed statistics of %d table(s).", result.Detail, count)
+	}
+
+	// The invalid indexes and constraints don't fail the task, they're flagged on the result and tracked by the anomaly until resolved.
+	invalidObjectList, err := checkInvalidObjects(ctx, server, task)
+	if err != nil {
+		log.Warn("Failed to check invalid indexes and constraints after migration",
+			zap.Int("task_id", task.ID),
+			zap.String("database", task.Database.Name),
+			zap.Error(err),
+		)
+		result.Detail = fmt.Sprintf("%s Failed to check invalid indexes and constraints: %v.", result.Detail, err)
+	} else if len(invalidObjectList) > 0 {
+		result.InvalidObjectList = invalidObjectList
+		result.Detail = fmt.Sprintf("%s Found %d invalid index(es) or constraint(s).", result.Detail, len(invalidObjectList))
+	}
+
+	if verification == nil {
+		return terminated, result, nil
+	}
+
+	// The migration is applied and recorded at this point, the failed verification only fails the task.
+	output, err := runVerification(ctx, server, task, verification)
+	if err != nil {
+		if common.ErrorCode(err) == common.TaskVerificationFailed {
+			return true, nil, common.Errorf(common.TaskVerificationFailed, "%s Verification failed. %s.", result.Detail, common.ErrorMessage(err))
+		}
+		return true, nil, fmt.Errorf("%s Failed to run the verification query, error: %w", result.Detail, err)
+	}
+	result.Verification = output
+	return terminated, result, nil
+}
+
+// runVerification runs the verification query on the database of the task and checks the result.
+func runVerification(ctx context.Context, server *Server, task *api.Task, verification *api.TaskVerification) (string, error) {
+	query, err := resolveMigrationStatement(ctx, server, task.Database, verification.Query)
+	if err != nil {
+		return "", err
+	}
+
+	var rowSet []interface{}
+	if task.Instance.AgentID != nil {
+		ctx, cancel := context.WithTimeout(ctx, agentQueryTimeout)
+		defer cancel()
+		result, err := server.runAgentTask(ctx, task.Instance, api.AgentTaskDatabaseQuery, &api.AgentTaskPayload{
+			Database:  task.Database.Name,
+			Statement: query,
+			Limit:     verification.GetQueryLimit(),
+		})
+		if err != nil {
+			return "", err
+		}
+		if result.Query == nil {
+			return "", fmt.Errorf("missing query result from the agent")
+		}
+		rowSet = result.Query.RowSet
+	} else {
+		driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, task.Database.Name)
+		if err != nil {
+			return "", err
+		}
+		defer driver.Close(ctx)
+
+		if rowSet, err = driver.Query(ctx, query, verification.GetQueryLimit()); err != nil {
+			return "", err
+		}
+	}
+	return verification.Check(rowSet)
+}
+
+// resolveMigrationStatement resolves the statement variables for the target database.
+// The placeholders are {{NAME}}, where NAME is either a built-in variable or a variable of the database project.
+func resolveMigrationStatement(ctx context.Context, server *Server, database *api.Database, statement string) (string, error) {
+	if !strings.Contains(statement, "{{") {
+		return statement, nil
+	}
+	variableList, err := server.store.FindProjectVariable(ctx, &api.ProjectVariableFind{ProjectID: &database.ProjectID})
+	if err != nil {
+		return "", fmt.Errorf("failed to find variable list for project ID %d, error: %w", database.ProjectID, err)
+	}
+	variableMap, err := api.GetStatementVariableMap(database.Name, database.Labels, variableList)
+	if err != nil {
+		return "", fmt.Errorf("failed to get statement variables for database %q, error: %w", database.Name, err)
+	}
+	resolved, err := api.ResolveStatementVariables(statement, variableMap)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve statement for database %q, error: %w", database.Name, err)
+	}
+	return resolved, nil
 }
 
 func findIssueByTask(ctx context.Context, server *Server, task *api.Task) (*api.Issue, error) {
@@ -341,6 +504,9 @@ func findRepositoryByTask(ctx context.Context, server *Server, task *api.Task) (
 // Writes back the latest schema to the repository after migration
 // Returns the commit id on success.
 func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.Repository, pushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, branch string, latestSchemaFile string, schema string, bytebaseURL string) (string, error) {
+	if err := server.refreshGitHubAppToken(ctx, repository); err != nil {
+		return "", err
+	}
 	schemaFileMeta, err := vcsPlugin.Get(repository.VCS.Type, vcsPlugin.ProviderConfig{}).ReadFileMeta(
 		ctx,
 		common.OauthContext{
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// NewAccessChangeTaskExecutor creates an access change task executor.
+func NewAccessChangeTaskExecutor() TaskExecutor {
+	return &AccessChangeTaskExecutor{}
+}
+
+// AccessChangeTaskExecutor is the task executor changing the users and the grants.
+// Each change is recorded into the access change history instead of the migration history,
+// and the passwords are removed from the task once it's run.
+type AccessChangeTaskExecutor struct {
+	completed int32
+}
+
+// RunOnce will run the access change task executor once.
+func (exec *AccessChangeTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskDatabaseAccessChangePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, fmt.Errorf("invalid database access change payload: %w", err)
+	}
+	if task.Database == nil {
+		return true, nil, fmt.Errorf("missing database when changing access")
+	}
+	defer removeAccessChangePassword(ctx, server, task, payload)
+	for _, change := range payload.ChangeList {
+		if change.EncryptedPassword == "" {
+			continue
+		}
+		password, err := common.DecryptWithSecret(change.EncryptedPassword, server.secret)
+		if err != nil {
+			return true, nil, fmt.Errorf("failed to decrypt the password of %q, error: %w", change.UserName, err)
+		}
+		change.Password = password
+	}
+
+	engine := task.Instance.Engine
+	// The policy may be tightened after the issue is created.
+	policy, err := server.store.GetAccessChangePolicy(ctx, task.Instance.EnvironmentID, api.GetPolicyTagList(task.Instance, task.Database))
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to get the access change policy, error: %w", err)
+	}
+	for _, change := range payload.ChangeList {
+		if err := api.NormalizeAccessChange(engine, change); err != nil {
+			return true, nil, err
+		}
+		if err := policy.Review(engine, change); err != nil {
+			return true, nil, err
+		}
+	}
+
+	issueID := 0
+	issue, err := server.store.GetIssueByPipelineID(ctx, task.PipelineID)
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to find the issue of pipeline ID %d, error: %w", task.PipelineID, err)
+	}
+	if issue != nil {
+		issueID = issue.ID
+	}
+
+	driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, task.Database.Name)
+	if err != nil {
+		return true, nil, err
+	}
+	defer driver.Close(ctx)
+
+	for _, change := range payload.ChangeList {
+		execErr := executeAccessChange(ctx, driver, engine, task.Database.Name, change)
+		historyCreate := &api.AccessChangeHistoryCreate{
+			CreatorID:  task.CreatorID,
+			InstanceID: task.InstanceID,
+			DatabaseID: task.Database.ID,
+			IssueID:    issueID,
+			TaskID:     task.ID,
+			Action:     change.Action,
+			UserName:   change.UserName,
+			Statement:  api.GetAccessChangeStatement(engine, task.Database.Name, change, true /* maskPassword */),
+		}
+		if execErr != nil {
+			historyCreate.Error = execErr.Error()
+		}
+		if _, err := server.store.CreateAccessChangeHistory(ctx, historyCreate); err != nil {
+			return true, nil, fmt.Errorf("failed to record the access change history, error: %w", err)
+		}
+		if execErr != nil {
+			return true, nil, fmt.Errorf("failed to %s %q, error: %w", change.Action, change.UserName, execErr)
+		}
+	}
+
+	return true, &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Applied %d access change(s) on %q", len(payload.ChangeList), task.Database.Name),
+	}, nil
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *AccessChangeTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (*AccessChangeTaskExecutor) GetProgress() api.Progress {
+	return api.Progress{}
+}
+
+// isAccessChangeSupported returns true if the engine supports the access change.
+func isAccessChangeSupported(engine db.Type) bool {
+	return engine == db.Postgres || engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+}
+
+func executeAccessChange(ctx context.Context, driver db.Driver, engine db.Type, databaseName string, change *api.AccessChange) error {
+	return driver.Execute(ctx, api.GetAccessChangeStatement(engine, databaseName, change, false /* maskPassword */))
+}
+
+// removeAccessChangePassword removes the passwords from the task payload, so that they aren't kept after the task is run.
+// Rerunning the task requires a new issue for the user creation and the password change.
+func removeAccessChangePassword(ctx context.Context, server *Server, task *api.Task, payload *api.TaskDatabaseAccessChangePayload) {
+	hasPassword := false
+	for _, change := range payload.ChangeList {
+		if change.Password != "" || change.EncryptedPassword != "" {
+			change.Password, change.EncryptedPassword = "", ""
+			hasPassword = true
+		}
+	}
+	if !hasPassword {
+		return
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		log.Error("Failed to marshal access change payload", zap.Int("task_id", task.ID), zap.Error(err))
+		return
+	}
+	payloadStr := string(bytes)
+	if _, err := server.store.PatchTask(ctx, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadStr,
+	}); err != nil {
+		log.Error("Failed to remove the passwords from the access change task", zap.Int("task_id", task.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestGetPipelineCreateForDatabaseAccessChangePassword(t *testing.T) {
+	s := newTestServer(t)
+	s.secret = "secret"
+	ctx := context.Background()
+
+	// The demo database 7004 is the blog database on the MySQL instance in project 3003.
+	createContext, err := json.Marshal(api.AccessChangeContext{
+		DatabaseID: 7004,
+		ChangeList: []*api.AccessChange{
+			{Action: api.AccessChangeCreateUser, UserName: "reader", Password: "pa55w0rd"},
+			{Action: api.AccessChangeGrant, UserName: "reader", PrivilegeList: []string{"SELECT"}},
+		},
+	})
+	require.NoError(t, err)
+	issueCreate := &api.IssueCreate{
+		ProjectID:     3003,
+		Type:          api.IssueDatabaseAccessChange,
+		CreateContext: string(createContext),
+	}
+	pipelineCreate, err := s.getPipelineCreateForDatabaseAccessChange(ctx, issueCreate)
+	require.NoError(t, err)
+
+	// Neither the issue create context nor the task payload keeps the password.
+	require.NotContains(t, issueCreate.CreateContext, "pa55w0rd")
+	c := api.AccessChangeContext{}
+	require.NoError(t, json.Unmarshal([]byte(issueCreate.CreateContext), &c))
+	require.Len(t, c.ChangeList, 2)
+	require.Empty(t, c.ChangeList[0].Password)
+	require.Empty(t, c.ChangeList[0].EncryptedPassword)
+
+	task := pipelineCreate.StageList[0].TaskList[0]
+	require.NotContains(t, task.Payload, "pa55w0rd")
+	payload := api.TaskDatabaseAccessChangePayload{}
+	require.NoError(t, json.Unmarshal([]byte(task.Payload), &payload))
+	require.Len(t, payload.ChangeList, 2)
+	require.Empty(t, payload.ChangeList[0].Password)
+	require.Empty(t, payload.ChangeList[1].EncryptedPassword)
+
+	// Only the workspace secret decrypts the password.
+	password, err := common.DecryptWithSecret(payload.ChangeList[0].EncryptedPassword, s.secret)
+	require.NoError(t, err)
+	require.Equal(t, "pa55w0rd", password)
+	_, err = common.DecryptWithSecret(payload.ChangeList[0].EncryptedPassword, "another")
+	require.Error(t, err)
+}
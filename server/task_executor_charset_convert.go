@@ -0,0 +1,179 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/github/gh-ost/go/logic"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// NewCharsetConvertTaskExecutor creates a charset conversion task executor.
+func NewCharsetConvertTaskExecutor() TaskExecutor {
+	return &CharsetConvertTaskExecutor{}
+}
+
+// CharsetConvertTaskExecutor is the charset conversion task executor.
+// It converts the tables one by one, the large tables are converted with gh-ost.
+type CharsetConvertTaskExecutor struct {
+	completed int32
+	progress  atomic.Value // api.Progress
+}
+
+// RunOnce will run the charset conversion task executor once.
+func (exec *CharsetConvertTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskDatabaseCharsetConvertPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, fmt.Errorf("invalid database charset convert payload: %w", err)
+	}
+	if payload.Plan == nil {
+		return true, nil, fmt.Errorf("missing charset conversion plan")
+	}
+	plan := payload.Plan
+	statement := plan.GetStatement()
+
+	mi, err := preMigration(ctx, server, task, db.Migrate, statement, "" /* downStatement */, payload.SchemaVersion, nil /* vcsPushEvent */)
+	if err != nil {
+		return true, nil, err
+	}
+	migrationID, schema, err := func() (migrationHistoryID int64, updatedSchema string, resErr error) {
+		driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, task.Database.Name)
+		if err != nil {
+			return -1, "", err
+		}
+		defer driver.Close(ctx)
+		needsSetup, err := driver.NeedsSetupMigration(ctx)
+		if err != nil {
+			return -1, "", fmt.Errorf("failed to check migration setup for instance %q: %w", task.Instance.Name, err)
+		}
+		if needsSetup {
+			return -1, "", common.Errorf(common.MigrationSchemaMissing, "missing migration schema for instance %q", task.Instance.Name)
+		}
+
+		executor := driver.(util.MigrationExecutor)
+
+		var prevSchemaBuf bytes.Buffer
+		if _, err := driver.Dump(ctx, mi.Database, &prevSchemaBuf, true); err != nil {
+			return -1, "", err
+		}
+
+		insertedID, err := util.BeginMigration(ctx, executor, mi, prevSchemaBuf.String(), statement, db.BytebaseDatabase)
+		if err != nil {
+			if common.ErrorCode(err) == common.MigrationAlreadyApplied {
+				return insertedID, prevSchemaBuf.String(), nil
+			}
+			return -1, "", err
+		}
+		startedNs := time.Now().UnixNano()
+
+		defer func() {
+			if err := util.EndMigration(ctx, executor, startedNs, insertedID, updatedSchema, db.BytebaseDatabase, resErr == nil /*isDone*/); err != nil {
+				log.Error("failed to update migration history record",
+					zap.Error(err),
+					zap.Int64("migration_id", migrationHistoryID),
+				)
+			}
+		}()
+
+		sqldb, err := driver.GetDBConnection(ctx, task.Database.Name)
+		if err != nil {
+			return -1, "", err
+		}
+		createdTs := time.Now().Unix()
+		for i, table := range plan.TableList {
+			exec.progress.Store(api.Progress{
+				TotalUnit:     int64(len(plan.TableList)),
+				CompletedUnit: int64(i),
+				CreatedTs:     createdTs,
+				UpdatedTs:     time.Now().Unix(),
+			})
+			tableStatement := plan.GetTableStatement(table)
+			switch table.Method {
+			case api.CharsetConversionGhost:
+				if err := runGhostCharsetConversion(task, table.Name, tableStatement); err != nil {
+					return -1, "", fmt.Errorf("failed to convert table %q with gh-ost, error: %w", table.Name, err)
+				}
+			default:
+				if _, err := sqldb.ExecContext(ctx, tableStatement); err != nil {
+					return -1, "", fmt.Errorf("failed to convert table %q, error: %w", table.Name, util.FormatError(err))
+				}
+			}
+		}
+		if _, err := sqldb.ExecContext(ctx, plan.GetDatabaseStatement()); err != nil {
+			return -1, "", fmt.Errorf("failed to alter the default character set of database %q, error: %w", task.Database.Name, util.FormatError(err))
+		}
+		exec.progress.Store(api.Progress{
+			TotalUnit:     int64(len(plan.TableList)),
+			CompletedUnit: int64(len(plan.TableList)),
+			CreatedTs:     createdTs,
+			UpdatedTs:     time.Now().Unix(),
+		})
+
+		var afterSchemaBuf bytes.Buffer
+		if _, err := executor.Dump(ctx, mi.Database, &afterSchemaBuf, true /*schemaOnly*/); err != nil {
+			return -1, "", util.FormatError(err)
+		}
+
+		return insertedID, afterSchemaBuf.String(), nil
+	}()
+	if err != nil {
+		return true, nil, err
+	}
+
+	return postMigration(ctx, server, task, nil /* vcsPushEvent */, mi, migrationID, schema)
+}
+
+// runGhostCharsetConversion converts the table with gh-ost and waits for the cutover.
+// Unlike the gh-ost schema update, there is no separate cutover task, so no postpone flag file is used.
+func runGhostCharsetConversion(task *api.Task, tableName, statement string) error {
+	instance := task.Instance
+	databaseName := task.Database.Name
+	adminDataSource := api.DataSourceFromInstanceWithType(instance, api.Admin)
+	if adminDataSource == nil {
+		return common.Errorf(common.Internal, "admin data source not found for instance %d", instance.ID)
+	}
+
+	migrationContext, err := newMigrationContext(ghostConfig{
+		host:           instance.Host,
+		port:           instance.Port,
+		user:           adminDataSource.Username,
+		password:       adminDataSource.Password,
+		database:       databaseName,
+		table:          tableName,
+		alterStatement: strings.TrimSuffix(statement, ";"),
+		socketFilename: getSocketFilename(task.ID, task.Database.ID, databaseName, tableName),
+		noop:           false,
+		// Use the same server ID offset as the gh-ost sync task, the tables are converted one at a time.
+		serverID: 10000000 + uint(task.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init migrationContext for gh-ost, error: %w", err)
+	}
+	return logic.NewMigrator(migrationContext, "bb").Migrate()
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *CharsetConvertTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (exec *CharsetConvertTaskExecutor) GetProgress() api.Progress {
+	progress := exec.progress.Load()
+	if progress == nil {
+		return api.Progress{}
+	}
+	return progress.(api.Progress)
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// NewDataBackfillTaskExecutor creates a data backfill task executor.
+func NewDataBackfillTaskExecutor() TaskExecutor {
+	return &DataBackfillTaskExecutor{}
+}
+
+// DataBackfillTaskExecutor is the data backfill task executor.
+// It runs the batch statement in separate transactions until no rows are affected, so that the locks are held briefly.
+type DataBackfillTaskExecutor struct {
+	completed int32
+	progress  atomic.Value // api.Progress
+}
+
+// RunOnce will run the data backfill task executor once.
+func (exec *DataBackfillTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskDatabaseDataBackfillPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, fmt.Errorf("invalid database data backfill payload: %w", err)
+	}
+	if task.Database == nil {
+		return true, nil, fmt.Errorf("missing database when backfilling data")
+	}
+
+	driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, task.Database.Name)
+	if err != nil {
+		return true, nil, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, task.Database.Name)
+	if err != nil {
+		return true, nil, err
+	}
+
+	createdTs := time.Now().Unix()
+	var total, batchCount int64
+	for {
+		res, err := sqldb.ExecContext(ctx, payload.BatchStatement)
+		if err != nil {
+			return true, nil, fmt.Errorf("failed to backfill after %d row(s), error: %w", total, util.FormatError(err))
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return true, nil, err
+		}
+		if rowsAffected == 0 {
+			break
+		}
+		total += rowsAffected
+		batchCount++
+		totalUnit := payload.EstimatedRowCount
+		if totalUnit < total {
+			totalUnit = total
+		}
+		exec.progress.Store(api.Progress{
+			TotalUnit:     totalUnit,
+			CompletedUnit: total,
+			CreatedTs:     createdTs,
+			UpdatedTs:     time.Now().Unix(),
+		})
+	}
+
+	return true, &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Backfilled %d row(s) in %d batch(es)", total, batchCount),
+	}, nil
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *DataBackfillTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (exec *DataBackfillTaskExecutor) GetProgress() api.Progress {
+	progress := exec.progress.Load()
+	if progress == nil {
+		return api.Progress{}
+	}
+	return progress.(api.Progress)
+}
@@ -27,7 +27,7 @@ func (*DataUpdateTaskExecutor) RunOnce(ctx context.Context, server *Server, task
 		return true, nil, fmt.Errorf("invalid database data update payload: %w", err)
 	}
 
-	return runMigration(ctx, server, task, db.Data, payload.Statement, payload.SchemaVersion, payload.VCSPushEvent)
+	return runMigration(ctx, server, task, db.Data, payload.Statement, payload.DownStatement, payload.SchemaVersion, payload.VCSPushEvent, payload.Verification, payload.SessionSetting)
 }
 
 // IsCompleted tells the scheduler if the task execution has completed.
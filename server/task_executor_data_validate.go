@@ -0,0 +1,436 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// NewDataValidateTaskExecutor creates a data validation task executor.
+func NewDataValidateTaskExecutor() TaskExecutor {
+	return &DataValidateTaskExecutor{}
+}
+
+// DataValidateTaskExecutor is the data validation task executor.
+// Like pt-table-checksum, it splits each table into chunks by the primary key ranges of the source database,
+// and compares the row count and the checksum of each chunk between the source and the target databases.
+// The task fails if any chunk mismatches, and the result lists the mismatched chunks.
+type DataValidateTaskExecutor struct {
+	completed int32
+	progress  atomic.Value // api.Progress
+}
+
+// RunOnce will run the data validation task executor once.
+func (exec *DataValidateTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskDatabaseDataValidatePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, fmt.Errorf("invalid database data validate payload: %w", err)
+	}
+	if task.Database == nil {
+		return true, nil, fmt.Errorf("missing database when validating data")
+	}
+	sourceDatabase, err := server.store.GetDatabase(ctx, &api.DatabaseFind{ID: &payload.SourceDatabaseID})
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to find source database ID %d, error: %w", payload.SourceDatabaseID, err)
+	}
+	if sourceDatabase == nil {
+		return true, nil, fmt.Errorf("source database ID not found %d", payload.SourceDatabaseID)
+	}
+	engine := task.Instance.Engine
+	chunkSize, err := api.ValidateDataValidationChunkSize(payload.ChunkSize)
+	if err != nil {
+		return true, nil, err
+	}
+
+	sourceDriver, err := server.getAdminDatabaseDriver(ctx, sourceDatabase.Instance, sourceDatabase.Name)
+	if err != nil {
+		return true, nil, err
+	}
+	defer sourceDriver.Close(ctx)
+	sourceDB, err := sourceDriver.GetDBConnection(ctx, sourceDatabase.Name)
+	if err != nil {
+		return true, nil, err
+	}
+	targetDriver, err := server.getAdminDatabaseDriver(ctx, task.Instance, task.Database.Name)
+	if err != nil {
+		return true, nil, err
+	}
+	defer targetDriver.Close(ctx)
+	targetDB, err := targetDriver.GetDBConnection(ctx, task.Database.Name)
+	if err != nil {
+		return true, nil, err
+	}
+
+	tableList := payload.TableList
+	if len(tableList) == 0 {
+		if tableList, err = getDataValidationTableList(ctx, sourceDB, engine, sourceDatabase.Name); err != nil {
+			return true, nil, fmt.Errorf("failed to list the tables of source database %q, error: %w", sourceDatabase.Name, err)
+		}
+	}
+
+	createdTs := time.Now().Unix()
+	var tableResultList []*api.DataValidationTableResult
+	for i, tableName := range tableList {
+		exec.progress.Store(api.Progress{
+			TotalUnit:     int64(len(tableList)),
+			CompletedUnit: int64(i),
+			CreatedTs:     createdTs,
+			UpdatedTs:     time.Now().Unix(),
+		})
+		tableResult, err := validateTableData(ctx, engine, sourceDB, targetDB, sourceDatabase.Name, task.Database.Name, tableName, chunkSize)
+		if err != nil {
+			return true, nil, fmt.Errorf("failed to validate table %q, error: %w", tableName, err)
+		}
+		tableResultList = append(tableResultList, tableResult)
+	}
+
+	detail, match := api.FormatDataValidationResult(sourceDatabase.Name, task.Database.Name, tableResultList)
+	if !match {
+		return true, nil, fmt.Errorf("%s", detail)
+	}
+	return true, &api.TaskRunResultPayload{
+		Detail: detail,
+	}, nil
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *DataValidateTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (exec *DataValidateTaskExecutor) GetProgress() api.Progress {
+	progress := exec.progress.Load()
+	if progress == nil {
+		return api.Progress{}
+	}
+	return progress.(api.Progress)
+}
+
+// isDataValidationSupported returns true if the engine supports the data validation.
+func isDataValidationSupported(engine db.Type) bool {
+	return engine == db.Postgres || engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+}
+
+// dataValidationTable is the table compared by the data validation.
+type dataValidationTable struct {
+	engine db.Type
+	// schema is only applicable to Postgres.
+	schema string
+	name   string
+	// columnList is all the columns in the ordinal order, they are all checksummed.
+	columnList []string
+	// primaryKeyList and primaryKeyTypeList are the primary key columns and their types, the types are only used in Postgres.
+	primaryKeyList     []string
+	primaryKeyTypeList []string
+}
+
+func newDataValidationTable(engine db.Type, tableName string) *dataValidationTable {
+	table := &dataValidationTable{
+		engine: engine,
+		name:   tableName,
+	}
+	if engine == db.Postgres {
+		table.schema = "public"
+		if i := strings.Index(tableName, "."); i >= 0 {
+			table.schema, table.name = tableName[:i], tableName[i+1:]
+		}
+	}
+	return table
+}
+
+func (t *dataValidationTable) quote(name string) string {
+	if t.engine == db.Postgres {
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+	}
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+}
+
+func (t *dataValidationTable) identifier() string {
+	if t.engine == db.Postgres {
+		return fmt.Sprintf("%s.%s", t.quote(t.schema), t.quote(t.name))
+	}
+	return t.quote(t.name)
+}
+
+// primaryKeyTuple returns the row constructor of the primary key columns, e.g. (`a`, `b`).
+func (t *dataValidationTable) primaryKeyTuple() string {
+	var list []string
+	for _, column := range t.primaryKeyList {
+		list = append(list, t.quote(column))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(list, ", "))
+}
+
+// boundTuple returns the row constructor of the placeholders of a chunk bound starting at the argument index.
+// The bounds are passed as text, so they are cast to the column types in Postgres.
+func (t *dataValidationTable) boundTuple(index int) string {
+	var list []string
+	for i := range t.primaryKeyList {
+		if t.engine == db.Postgres {
+			list = append(list, fmt.Sprintf("CAST(CAST($%d AS TEXT) AS %s)", index+i, t.primaryKeyTypeList[i]))
+		} else {
+			list = append(list, "?")
+		}
+	}
+	return fmt.Sprintf("(%s)", strings.Join(list, ", "))
+}
+
+// getBoundaryQuery returns the query selecting the upper bound of the chunk after the lower bound.
+func (t *dataValidationTable) getBoundaryQuery(hasLowerBound bool, chunkSize int) string {
+	var selectList, orderList []string
+	for _, column := range t.primaryKeyList {
+		if t.engine == db.Postgres {
+			selectList = append(selectList, fmt.Sprintf("%s::TEXT", t.quote(column)))
+		} else {
+			selectList = append(selectList, fmt.Sprintf("CAST(%s AS CHAR)", t.quote(column)))
+		}
+		orderList = append(orderList, t.quote(column))
+	}
+	where := ""
+	if hasLowerBound {
+		where = fmt.Sprintf(" WHERE %s > %s", t.primaryKeyTuple(), t.boundTuple(1))
+	}
+	return fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s LIMIT 1 OFFSET %d",
+		strings.Join(selectList, ", "), t.identifier(), where, strings.Join(orderList, ", "), chunkSize-1)
+}
+
+// getChecksumQuery returns the query of the row count and the order-independent checksum of the chunk (lower, upper].
+func (t *dataValidationTable) getChecksumQuery(hasLowerBound, hasUpperBound bool) string {
+	var columnList []string
+	for _, column := range t.columnList {
+		columnList = append(columnList, t.quote(column))
+	}
+	var checksum string
+	if t.engine == db.Postgres {
+		checksum = fmt.Sprintf("COALESCE(SUM(('x' || SUBSTR(MD5(ROW(%s)::TEXT), 1, 8))::BIT(32)::BIGINT), 0)::TEXT", strings.Join(columnList, ", "))
+	} else {
+		// CONCAT_WS skips the NULLs, so the NULL flags are appended to tell NULL from the empty string.
+		var isNullList []string
+		for _, column := range columnList {
+			isNullList = append(isNullList, fmt.Sprintf("ISNULL(%s)", column))
+		}
+		checksum = fmt.Sprintf("CAST(COALESCE(BIT_XOR(CAST(CRC32(CONCAT_WS('#', %s, CONCAT(%s))) AS UNSIGNED)), 0) AS CHAR)",
+			strings.Join(columnList, ", "), strings.Join(isNullList, ", "))
+	}
+	var whereList []string
+	index := 1
+	if hasLowerBound {
+		whereList = append(whereList, fmt.Sprintf("%s > %s", t.primaryKeyTuple(), t.boundTuple(index)))
+		index += len(t.primaryKeyList)
+	}
+	if hasUpperBound {
+		whereList = append(whereList, fmt.Sprintf("%s <= %s", t.primaryKeyTuple(), t.boundTuple(index)))
+	}
+	where := ""
+	if len(whereList) > 0 {
+		where = " WHERE " + strings.Join(whereList, " AND ")
+	}
+	return fmt.Sprintf("SELECT COUNT(*), %s FROM %s%s", checksum, t.identifier(), where)
+}
+
+// getDataValidationTableList returns the base tables of the database, they are in the form of "schema.table" in Postgres.
+func getDataValidationTableList(ctx context.Context, sqldb *sql.DB, engine db.Type, databaseName string) ([]string, error) {
+	query := `
+		SELECT TABLE_NAME FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE IN ('BASE TABLE', 'SYSTEM VERSIONED')
+		ORDER BY TABLE_NAME`
+	args := []interface{}{databaseName}
+	if engine == db.Postgres {
+		query = `
+			SELECT table_schema || '.' || table_name FROM information_schema.tables
+			WHERE table_schema NOT IN ('pg_catalog', 'information_schema') AND table_type = 'BASE TABLE'
+			ORDER BY table_schema, table_name`
+		args = nil
+	}
+	rows, err := sqldb.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tableList []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tableList = append(tableList, name)
+	}
+	return tableList, rows.Err()
+}
+
+// syncDataValidationTable fills the columns and the primary key of the table, the column list is empty if the table doesn't exist.
+func syncDataValidationTable(ctx context.Context, sqldb *sql.DB, databaseName string, table *dataValidationTable) error {
+	columnQuery := `
+		SELECT COLUMN_NAME FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY ORDINAL_POSITION`
+	columnArgs := []interface{}{databaseName, table.name}
+	primaryKeyQuery := `
+		SELECT COLUMN_NAME, '' FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'
+		ORDER BY ORDINAL_POSITION`
+	primaryKeyArgs := columnArgs
+	if table.engine == db.Postgres {
+		columnQuery = `
+			SELECT column_name FROM information_schema.columns
+			WHERE table_schema = $1 AND table_name = $2
+			ORDER BY ordinal_position`
+		columnArgs = []interface{}{table.schema, table.name}
+		primaryKeyQuery = `
+			SELECT a.attname, format_type(a.atttypid, a.atttypmod)
+			FROM pg_index i
+				JOIN pg_class c ON c.oid = i.indrelid
+				JOIN pg_namespace n ON n.oid = c.relnamespace
+				JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(i.indkey)
+			WHERE n.nspname = $1 AND c.relname = $2 AND i.indisprimary
+			ORDER BY array_position(i.indkey::SMALLINT[], a.attnum)`
+		primaryKeyArgs = columnArgs
+	}
+
+	table.columnList = nil
+	rows, err := sqldb.QueryContext(ctx, columnQuery, columnArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return err
+		}
+		table.columnList = append(table.columnList, column)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	table.primaryKeyList, table.primaryKeyTypeList = nil, nil
+	pkRows, err := sqldb.QueryContext(ctx, primaryKeyQuery, primaryKeyArgs...)
+	if err != nil {
+		return err
+	}
+	defer pkRows.Close()
+	for pkRows.Next() {
+		var column, columnType string
+		if err := pkRows.Scan(&column, &columnType); err != nil {
+			return err
+		}
+		table.primaryKeyList = append(table.primaryKeyList, column)
+		table.primaryKeyTypeList = append(table.primaryKeyTypeList, columnType)
+	}
+	return pkRows.Err()
+}
+
+// validateTableData compares the table chunk by chunk. The chunk bounds are decided on the source,
+// so that a missing or an extra row in the target only mismatches the chunk containing it.
+// The table without the primary key is compared as a single chunk.
+func validateTableData(ctx context.Context, engine db.Type, sourceDB, targetDB *sql.DB, sourceDatabaseName, targetDatabaseName, tableName string, chunkSize int) (*api.DataValidationTableResult, error) {
+	result := &api.DataValidationTableResult{Name: tableName}
+	source := newDataValidationTable(engine, tableName)
+	if err := syncDataValidationTable(ctx, sourceDB, sourceDatabaseName, source); err != nil {
+		return nil, err
+	}
+	if len(source.columnList) == 0 {
+		result.Error = "table not found in source database"
+		return result, nil
+	}
+	target := newDataValidationTable(engine, tableName)
+	if err := syncDataValidationTable(ctx, targetDB, targetDatabaseName, target); err != nil {
+		return nil, err
+	}
+	if len(target.columnList) == 0 {
+		result.Error = "table not found in target database"
+		return result, nil
+	}
+	if strings.Join(source.columnList, ",") != strings.Join(target.columnList, ",") {
+		result.Error = fmt.Sprintf("columns are different, source has (%s) but target has (%s)",
+			strings.Join(source.columnList, ", "), strings.Join(target.columnList, ", "))
+		return result, nil
+	}
+
+	var lowerBound []string
+	for {
+		var upperBound []string
+		if len(source.primaryKeyList) > 0 {
+			bound, err := getDataValidationBoundary(ctx, sourceDB, source, lowerBound, chunkSize)
+			if err != nil {
+				return nil, err
+			}
+			upperBound = bound
+		}
+		chunk := &api.DataValidationChunkResult{
+			LowerBound: strings.Join(lowerBound, ", "),
+			UpperBound: strings.Join(upperBound, ", "),
+		}
+		sourceRowCount, sourceChecksum, err := getDataValidationChecksum(ctx, sourceDB, source, lowerBound, upperBound)
+		if err != nil {
+			return nil, err
+		}
+		targetRowCount, targetChecksum, err := getDataValidationChecksum(ctx, targetDB, source, lowerBound, upperBound)
+		if err != nil {
+			return nil, err
+		}
+		chunk.SourceRowCount, chunk.SourceChecksum = sourceRowCount, sourceChecksum
+		chunk.TargetRowCount, chunk.TargetChecksum = targetRowCount, targetChecksum
+		result.SourceRowCount += sourceRowCount
+		result.TargetRowCount += targetRowCount
+		result.ChunkCount++
+		if !chunk.Match() {
+			result.MismatchedChunkList = append(result.MismatchedChunkList, chunk)
+		}
+		if upperBound == nil {
+			break
+		}
+		lowerBound = upperBound
+	}
+	return result, nil
+}
+
+// getDataValidationBoundary returns the primary key of the last row in the chunk after the lower bound, nil if the chunk is the last one.
+func getDataValidationBoundary(ctx context.Context, sqldb *sql.DB, table *dataValidationTable, lowerBound []string, chunkSize int) ([]string, error) {
+	var args []interface{}
+	for _, v := range lowerBound {
+		args = append(args, v)
+	}
+	bound := make([]sql.NullString, len(table.primaryKeyList))
+	var dest []interface{}
+	for i := range bound {
+		dest = append(dest, &bound[i])
+	}
+	if err := sqldb.QueryRowContext(ctx, table.getBoundaryQuery(lowerBound != nil, chunkSize), args...).Scan(dest...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var result []string
+	for _, v := range bound {
+		result = append(result, v.String)
+	}
+	return result, nil
+}
+
+func getDataValidationChecksum(ctx context.Context, sqldb *sql.DB, table *dataValidationTable, lowerBound, upperBound []string) (int64, string, error) {
+	var args []interface{}
+	for _, v := range lowerBound {
+		args = append(args, v)
+	}
+	for _, v := range upperBound {
+		args = append(args, v)
+	}
+	var rowCount int64
+	var checksum string
+	if err := sqldb.QueryRowContext(ctx, table.getChecksumQuery(lowerBound != nil, upperBound != nil), args...).Scan(&rowCount, &checksum); err != nil {
+		return 0, "", err
+	}
+	return rowCount, checksum, nil
+}
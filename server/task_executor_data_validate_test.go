@@ -0,0 +1,32 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataValidationTableQuery(t *testing.T) {
+	mysqlTable := newDataValidationTable(db.MySQL, "orders")
+	mysqlTable.columnList = []string{"id", "note"}
+	mysqlTable.primaryKeyList = []string{"id"}
+	mysqlTable.primaryKeyTypeList = []string{""}
+	require.Equal(t, "SELECT CAST(`id` AS CHAR) FROM `orders` ORDER BY `id` LIMIT 1 OFFSET 999", mysqlTable.getBoundaryQuery(false, 1000))
+	require.Equal(t, "SELECT CAST(`id` AS CHAR) FROM `orders` WHERE (`id`) > (?) ORDER BY `id` LIMIT 1 OFFSET 999", mysqlTable.getBoundaryQuery(true, 1000))
+	require.Equal(t, "SELECT COUNT(*), CAST(COALESCE(BIT_XOR(CAST(CRC32(CONCAT_WS('#', `id`, `note`, CONCAT(ISNULL(`id`), ISNULL(`note`)))) AS UNSIGNED)), 0) AS CHAR) FROM `orders` WHERE (`id`) > (?) AND (`id`) <= (?)",
+		mysqlTable.getChecksumQuery(true, true))
+
+	pgTable := newDataValidationTable(db.Postgres, "sales.orders")
+	require.Equal(t, "sales", pgTable.schema)
+	require.Equal(t, "orders", pgTable.name)
+	pgTable.columnList = []string{"tenant", "id", "note"}
+	pgTable.primaryKeyList = []string{"tenant", "id"}
+	pgTable.primaryKeyTypeList = []string{"text", "bigint"}
+	require.Equal(t, `SELECT "tenant"::TEXT, "id"::TEXT FROM "sales"."orders" WHERE ("tenant", "id") > (CAST(CAST($1 AS TEXT) AS text), CAST(CAST($2 AS TEXT) AS bigint)) ORDER BY "tenant", "id" LIMIT 1 OFFSET 99`,
+		pgTable.getBoundaryQuery(true, 100))
+	require.Equal(t, `SELECT COUNT(*), COALESCE(SUM(('x' || SUBSTR(MD5(ROW("tenant", "id", "note")::TEXT), 1, 8))::BIT(32)::BIGINT), 0)::TEXT FROM "sales"."orders" WHERE ("tenant", "id") <= (CAST(CAST($1 AS TEXT) AS text), CAST(CAST($2 AS TEXT) AS bigint))`,
+		pgTable.getChecksumQuery(false, true))
+
+	require.Equal(t, `"public"."orders"`, newDataValidationTable(db.Postgres, "orders").identifier())
+}
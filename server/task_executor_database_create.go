@@ -57,11 +57,19 @@ func (exec *DatabaseCreateTaskExecutor) RunOnce(ctx context.Context, server *Ser
 	log.Debug("Start creating database...",
 		zap.String("instance", instance.Name),
 		zap.String("database", payload.DatabaseName),
+		zap.String("owner", payload.Owner),
+		zap.String("tablespace", payload.Tablespace),
+		zap.Strings("extensions", payload.ExtensionList),
 		zap.String("statement", statement),
 	)
 
 	// Create a baseline migration history upon creating the database.
+	// The statement of the database template, if any, follows the CREATE DATABASE statement, so it's recorded as part of the baseline.
 	// TODO(d): support semantic versioning.
+	description := "Create database"
+	if payload.TemplateName != "" {
+		description = fmt.Sprintf("Create database from template %q", payload.TemplateName)
+	}
 	mi := &db.MigrationInfo{
 		ReleaseVersion: server.profile.Version,
 		Version:        payload.SchemaVersion,
@@ -70,7 +78,7 @@ func (exec *DatabaseCreateTaskExecutor) RunOnce(ctx context.Context, server *Ser
 		Environment:    instance.Environment.Name,
 		Source:         db.UI,
 		Type:           db.Baseline,
-		Description:    "Create database",
+		Description:    description,
 		CreateDatabase: true,
 		Force:          true,
 	}
@@ -179,8 +187,15 @@ func (exec *DatabaseCreateTaskExecutor) RunOnce(ctx context.Context, server *Ser
 		}
 	}
 
+	detail := fmt.Sprintf("Created database %q", payload.DatabaseName)
+	if payload.TemplateName != "" {
+		detail = fmt.Sprintf("Created database %q from template %q", payload.DatabaseName, payload.TemplateName)
+	}
+	if len(payload.ExtensionList) > 0 {
+		detail = fmt.Sprintf("%s with extension(s) %s", detail, strings.Join(payload.ExtensionList, ", "))
+	}
 	return true, &api.TaskRunResultPayload{
-		Detail:      fmt.Sprintf("Created database %q", payload.DatabaseName),
+		Detail:      detail,
 		MigrationID: migrationID,
 		Version:     mi.Version,
 	}, nil
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+// NewDatabaseDropTaskExecutor creates a database drop task executor.
+func NewDatabaseDropTaskExecutor() TaskExecutor {
+	return &DatabaseDropTaskExecutor{}
+}
+
+// DatabaseDropTaskExecutor is the database drop task executor.
+// It takes a final backup of the database, drops the database and archives it.
+type DatabaseDropTaskExecutor struct {
+	completed int32
+}
+
+// RunOnce will run the database drop task executor once.
+func (exec *DatabaseDropTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskDatabaseDropPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, fmt.Errorf("invalid database drop payload: %w", err)
+	}
+	database := task.Database
+	if database == nil {
+		return true, nil, fmt.Errorf("database %q not found", payload.DatabaseName)
+	}
+
+	// The connections may be established after the activity check, so we check them again right before dropping.
+	activity, err := getDatabaseActivity(ctx, server, task.Instance, database.Name)
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to check the activity of database %q, error: %w", database.Name, err)
+	}
+	if activity.connectionCount > 0 {
+		return true, nil, fmt.Errorf("database %q has %d active connection(s)", database.Name, activity.connectionCount)
+	}
+
+	backup, err := exec.backupDatabase(ctx, server, task)
+	if err != nil {
+		return true, nil, err
+	}
+
+	log.Debug("Dropping database",
+		zap.String("instance", task.Instance.Name),
+		zap.String("database", database.Name),
+		zap.String("backup", backup.Name),
+	)
+	if err := dropDatabase(ctx, server, task.Instance, database.Name); err != nil {
+		return true, nil, fmt.Errorf("failed to drop database %q, error: %w", database.Name, err)
+	}
+
+	// Archive the database so that it's no longer listed, the backups are kept for restoring.
+	syncStatus := api.NotFound
+	rowStatus := api.Archived
+	notFoundTs := time.Now().Unix()
+	if _, err := server.store.PatchDatabase(ctx, &api.DatabasePatch{
+		ID:         database.ID,
+		UpdaterID:  api.SystemBotID,
+		SyncStatus: &syncStatus,
+		RowStatus:  &rowStatus,
+		NotFoundTs: &notFoundTs,
+	}); err != nil {
+		return true, nil, fmt.Errorf("failed to archive database %q after dropping, error: %w", database.Name, err)
+	}
+
+	return true, &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Dropped database %q after taking the final backup %q", database.Name, backup.Name),
+	}, nil
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *DatabaseDropTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (*DatabaseDropTaskExecutor) GetProgress() api.Progress {
+	return api.Progress{}
+}
+
+// backupDatabase takes the final backup of the database synchronously, the database is not dropped if the backup fails.
+func (*DatabaseDropTaskExecutor) backupDatabase(ctx context.Context, server *Server, task *api.Task) (*api.Backup, error) {
+	database := task.Database
+	backupName := fmt.Sprintf("%s-%s-drop-%d", api.ProjectShortSlug(database.Project), api.EnvSlug(database.Instance.Environment), time.Now().Unix())
+	backup, err := server.createBackup(ctx, database, backupName, api.BackupTypeManual, task.CreatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the final backup for database %q, error: %w", database.Name, err)
+	}
+
+	backupPayload, backupErr := (&DatabaseBackupTaskExecutor{}).backupDatabase(ctx, server, task.Instance, database.Name, backup)
+	backupPatch := api.BackupPatch{
+		ID:        backup.ID,
+		Status:    string(api.BackupStatusDone),
+		UpdaterID: api.SystemBotID,
+		Payload:   backupPayload,
+	}
+	if backupErr != nil {
+		backupPatch.Status = string(api.BackupStatusFailed)
+		backupPatch.Comment = backupErr.Error()
+	}
+	backup, err = server.store.PatchBackup(ctx, &backupPatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch backup, error: %w", err)
+	}
+	if backupErr != nil {
+		return nil, fmt.Errorf("failed to take the final backup for database %q, error: %w", database.Name, backupErr)
+	}
+	return backup, nil
+}
+
+// dropDatabase drops the database on the instance.
+func dropDatabase(ctx context.Context, server *Server, instance *api.Instance, databaseName string) error {
+	driver, err := server.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	// Postgres can't drop the connected database, and DROP DATABASE can't run inside a transaction,
+	// so we execute it on the connection to another database instead of using driver.Execute.
+	connectDatabase := ""
+	if instance.Engine == db.Postgres {
+		connectDatabase = "postgres"
+	}
+	sqlDB, err := driver.GetDBConnection(ctx, connectDatabase)
+	if err != nil {
+		return err
+	}
+	_, err = sqlDB.ExecContext(ctx, getDropDatabaseStatement(instance.Engine, databaseName))
+	return err
+}
+
+func getDropDatabaseStatement(dbType db.Type, databaseName string) string {
+	return fmt.Sprintf("DROP DATABASE %s;", api.QuoteIdentifier(dbType, databaseName))
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestDatabaseDropTaskExecutor(t *testing.T) {
+	s := newTestServer(t)
+	s.profile.DataDir = t.TempDir()
+	s.profile.BackupStorageBackend = api.BackupStorageBackendLocal
+	ctx := context.Background()
+
+	database := newTestPgDatabase(ctx, t, s, "drop_me")
+	instance := database.Instance
+	databaseExists := func() bool {
+		driver, err := s.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+		require.NoError(t, err)
+		defer driver.Close(ctx)
+		sqlDB, err := driver.GetDBConnection(ctx, "postgres")
+		require.NoError(t, err)
+		var exists bool
+		require.NoError(t, sqlDB.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)", database.Name).Scan(&exists))
+		return exists
+	}
+	require.True(t, databaseExists())
+
+	payload, err := json.Marshal(api.TaskDatabaseDropPayload{DatabaseName: database.Name})
+	require.NoError(t, err)
+	task := &api.Task{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		Instance:   instance,
+		DatabaseID: &database.ID,
+		Database:   database,
+		Type:       api.TaskDatabaseDrop,
+		Payload:    string(payload),
+	}
+
+	t.Run("active connections", func(t *testing.T) {
+		conn, err := sql.Open("pgx", fmt.Sprintf("host=%s port=%d user=root dbname=%s", instance.Host, testPgPort, database.Name))
+		require.NoError(t, err)
+		defer conn.Close()
+		require.NoError(t, conn.PingContext(ctx))
+
+		activity, err := getDatabaseActivity(ctx, s, instance, database.Name)
+		require.NoError(t, err)
+		require.Equal(t, 1, activity.connectionCount)
+
+		exec := NewDatabaseDropTaskExecutor()
+		terminated, result, err := exec.RunOnce(ctx, s, task)
+		require.True(t, terminated)
+		require.Nil(t, result)
+		require.ErrorContains(t, err, "1 active connection(s)")
+		require.True(t, exec.IsCompleted())
+		require.True(t, databaseExists())
+
+		// No backup is taken before the connections are closed.
+		backupList, err := s.store.FindBackup(ctx, &api.BackupFind{DatabaseID: &database.ID})
+		require.NoError(t, err)
+		require.Empty(t, backupList)
+	})
+
+	t.Run("backup failure", func(t *testing.T) {
+		activity, err := getDatabaseActivity(ctx, s, instance, database.Name)
+		require.NoError(t, err)
+		require.Equal(t, 0, activity.connectionCount)
+
+		// The test server has no Postgres installation to run pg_dump, so the final backup fails.
+		exec := NewDatabaseDropTaskExecutor()
+		terminated, result, err := exec.RunOnce(ctx, s, task)
+		require.True(t, terminated)
+		require.Nil(t, result)
+		require.ErrorContains(t, err, "failed to take the final backup")
+		require.True(t, databaseExists())
+
+		backupList, err := s.store.FindBackup(ctx, &api.BackupFind{DatabaseID: &database.ID})
+		require.NoError(t, err)
+		require.Len(t, backupList, 1)
+		require.Equal(t, api.BackupStatusFailed, backupList[0].Status)
+		found, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &database.ID})
+		require.NoError(t, err)
+		require.Equal(t, api.Normal, found.RowStatus)
+	})
+}
+
+func TestValidateDatabaseDropApprover(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	// The demo principals 101, 102 and 103 are the Owner, DBA and Developer.
+	tests := []struct {
+		principalID int
+		wantCode    int
+	}{
+		{principalID: 101},
+		{principalID: 102},
+		{principalID: 103, wantCode: http.StatusUnauthorized},
+		{principalID: 999, wantCode: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		err := s.validateDatabaseDropApprover(ctx, test.principalID)
+		if test.wantCode == 0 {
+			require.NoError(t, err, test.principalID)
+			continue
+		}
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok, test.principalID)
+		require.Equal(t, test.wantCode, httpErr.Code, test.principalID)
+	}
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// parameterRestartCheckInterval is the interval of checking whether the instance has restarted for the pending parameters.
+	parameterRestartCheckInterval = time.Duration(1) * time.Minute
+	// mysqlErrorReadOnlyVariable is the MySQL error number of setting a read-only variable, ER_INCORRECT_GLOBAL_LOCAL_VAR.
+	mysqlErrorReadOnlyVariable = 1238
+)
+
+// NewParameterChangeTaskExecutor creates a parameter change task executor.
+func NewParameterChangeTaskExecutor() TaskExecutor {
+	return &ParameterChangeTaskExecutor{}
+}
+
+// ParameterChangeTaskExecutor is the task executor changing the instance parameters.
+// If some parameters only take effect after the restart, the task keeps running as pending restart
+// and is done once the instance restarts, instead of claiming the changes are applied.
+type ParameterChangeTaskExecutor struct {
+	completed int32
+	mu        sync.Mutex
+	comment   string
+}
+
+// RunOnce will run the parameter change task executor once.
+func (exec *ParameterChangeTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskInstanceParameterChangePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, fmt.Errorf("invalid instance parameter change payload: %w", err)
+	}
+	if err := api.ValidateParameterChangeList(payload.ParameterList); err != nil {
+		return true, nil, err
+	}
+
+	driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, "" /* databaseName */)
+	if err != nil {
+		// The instance may be restarting, so retry later.
+		return false, nil, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, "")
+	if err != nil {
+		return false, nil, err
+	}
+
+	if payload.AppliedTs == 0 {
+		if err := applyParameterChange(ctx, sqldb, task.Instance, payload); err != nil {
+			return true, nil, err
+		}
+		bytes, err := json.Marshal(payload)
+		if err != nil {
+			return true, nil, fmt.Errorf("failed to marshal instance parameter change payload, error: %w", err)
+		}
+		payloadStr := string(bytes)
+		// The changes are recorded, so that they aren't applied again when the task is rerun for the pending restart.
+		if _, err := server.store.PatchTask(ctx, &api.TaskPatch{
+			ID:        task.ID,
+			UpdaterID: api.SystemBotID,
+			Payload:   &payloadStr,
+		}); err != nil {
+			return true, nil, fmt.Errorf("failed to record the applied parameter changes, error: %w", err)
+		}
+	}
+
+	if len(payload.PendingRestartList) > 0 {
+		startTs, _, err := getInstanceStartAndNowTs(ctx, sqldb, task.Instance.Engine)
+		if err != nil {
+			return false, nil, err
+		}
+		if startTs <= payload.AppliedTs {
+			exec.setComment(fmt.Sprintf("Pending restart of instance %q for %s", task.Instance.Name, strings.Join(payload.PendingRestartList, ", ")))
+			// Wait before the scheduler reruns the task to check again.
+			select {
+			case <-time.After(parameterRestartCheckInterval):
+			case <-ctx.Done():
+			}
+			return false, nil, nil
+		}
+		return true, &api.TaskRunResultPayload{
+			Detail: fmt.Sprintf("Applied %d parameter change(s) on %q after the restart", len(payload.ParameterList), task.Instance.Name),
+		}, nil
+	}
+
+	return true, &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Applied %d parameter change(s) on %q", len(payload.ParameterList), task.Instance.Name),
+	}, nil
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *ParameterChangeTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (exec *ParameterChangeTaskExecutor) GetProgress() api.Progress {
+	exec.mu.Lock()
+	defer exec.mu.Unlock()
+	if exec.comment == "" {
+		return api.Progress{}
+	}
+	bytes, err := json.Marshal(map[string]string{"comment": exec.comment})
+	if err != nil {
+		return api.Progress{}
+	}
+	return api.Progress{
+		UpdatedTs: time.Now().Unix(),
+		Payload:   string(bytes),
+	}
+}
+
+func (exec *ParameterChangeTaskExecutor) setComment(comment string) {
+	exec.mu.Lock()
+	defer exec.mu.Unlock()
+	exec.comment = comment
+}
+
+// isParameterChangeSupported returns true if the engine supports the parameter change.
+func isParameterChangeSupported(engine db.Type) bool {
+	return engine == db.Postgres || engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB
+}
+
+// applyParameterChange applies the parameter changes, and sets the applied time and the parameters pending restart in the payload.
+func applyParameterChange(ctx context.Context, sqldb *sql.DB, instance *api.Instance, payload *api.TaskInstanceParameterChangePayload) error {
+	// Use the time of the instance to compare with its start time.
+	_, nowTs, err := getInstanceStartAndNowTs(ctx, sqldb, instance.Engine)
+	if err != nil {
+		return err
+	}
+	payload.PendingRestartList = nil
+	if instance.Engine == db.Postgres {
+		for _, change := range payload.ParameterList {
+			var parameterContext string
+			if err := sqldb.QueryRowContext(ctx, "SELECT context FROM pg_catalog.pg_settings WHERE name = $1", change.Name).Scan(&parameterContext); err != nil {
+				if err == sql.ErrNoRows {
+					return fmt.Errorf("unrecognized parameter %q", change.Name)
+				}
+				return err
+			}
+			if parameterContext == "internal" {
+				return fmt.Errorf("parameter %q cannot be changed", change.Name)
+			}
+			// ALTER SYSTEM cannot run inside a transaction block.
+			if _, err := sqldb.ExecContext(ctx, api.GetParameterChangeStatement(instance.Engine, "", change)); err != nil {
+				return fmt.Errorf("failed to change parameter %q, error: %w", change.Name, err)
+			}
+			if parameterContext == "postmaster" {
+				payload.PendingRestartList = append(payload.PendingRestartList, change.Name)
+			}
+		}
+		if _, err := sqldb.ExecContext(ctx, "SELECT pg_catalog.pg_reload_conf()"); err != nil {
+			return fmt.Errorf("failed to reload the configuration, error: %w", err)
+		}
+	} else {
+		scope := api.GetMySQLParameterScope(instance.Engine, instance.EngineVersion)
+		for _, change := range payload.ParameterList {
+			_, err := sqldb.ExecContext(ctx, api.GetParameterChangeStatement(instance.Engine, scope, change))
+			if err == nil {
+				continue
+			}
+			mysqlErr, ok := err.(*mysql.MySQLError)
+			if !ok || mysqlErr.Number != mysqlErrorReadOnlyVariable {
+				return fmt.Errorf("failed to change parameter %q, error: %w", change.Name, err)
+			}
+			// Only MySQL 8.0 can persist the read-only variables for the restart, otherwise the option file must be changed.
+			if scope != api.ParameterScopePersist {
+				return fmt.Errorf("parameter %q is read-only, change it in the option file and restart the instance instead", change.Name)
+			}
+			if _, err := sqldb.ExecContext(ctx, api.GetParameterChangeStatement(instance.Engine, api.ParameterScopePersistOnly, change)); err != nil {
+				return fmt.Errorf("failed to change parameter %q, error: %w", change.Name, err)
+			}
+			payload.PendingRestartList = append(payload.PendingRestartList, change.Name)
+		}
+	}
+	payload.AppliedTs = nowTs
+	return nil
+}
+
+// getInstanceStartAndNowTs returns the start time and the current time of the instance.
+func getInstanceStartAndNowTs(ctx context.Context, sqldb *sql.DB, engine db.Type) (int64, int64, error) {
+	var startTs, nowTs int64
+	if engine == db.Postgres {
+		if err := sqldb.QueryRowContext(ctx, "SELECT EXTRACT(EPOCH FROM pg_catalog.pg_postmaster_start_time())::BIGINT, EXTRACT(EPOCH FROM now())::BIGINT").Scan(&startTs, &nowTs); err != nil {
+			return 0, 0, err
+		}
+		return startTs, nowTs, nil
+	}
+	var name string
+	var uptime int64
+	if err := sqldb.QueryRowContext(ctx, "SHOW GLOBAL STATUS LIKE 'Uptime'").Scan(&name, &uptime); err != nil {
+		return 0, 0, err
+	}
+	if err := sqldb.QueryRowContext(ctx, "SELECT UNIX_TIMESTAMP()").Scan(&nowTs); err != nil {
+		return 0, 0, err
+	}
+	return nowTs - uptime, nowTs, nil
+}
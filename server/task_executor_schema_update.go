@@ -27,7 +27,7 @@ func (exec *SchemaUpdateTaskExecutor) RunOnce(ctx context.Context, server *Serve
 		return true, nil, fmt.Errorf("invalid database schema update payload: %w", err)
 	}
 
-	return runMigration(ctx, server, task, payload.MigrationType, payload.Statement, payload.SchemaVersion, payload.VCSPushEvent)
+	return runMigration(ctx, server, task, payload.MigrationType, payload.Statement, payload.DownStatement, payload.SchemaVersion, payload.VCSPushEvent, payload.Verification, payload.SessionSetting)
 }
 
 // IsCompleted tells the scheduler if the task execution has completed.
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+// NewUpgradeCheckTaskExecutor creates an upgrade check task executor.
+func NewUpgradeCheckTaskExecutor() TaskExecutor {
+	return &UpgradeCheckTaskExecutor{}
+}
+
+// UpgradeCheckTaskExecutor is the task executor checking the readiness of the Postgres major version upgrade.
+// It runs the compatibility checks of pg_upgrade in all the databases of the instance, and attaches the report to the issue.
+// The task fails if any check blocks the upgrade.
+type UpgradeCheckTaskExecutor struct {
+	completed int32
+	progress  atomic.Value // api.Progress
+}
+
+// RunOnce will run the upgrade check task executor once.
+func (exec *UpgradeCheckTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskInstanceUpgradeCheckPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, fmt.Errorf("invalid instance upgrade check payload: %w", err)
+	}
+	targetVersionNum, err := api.GetPostgresVersionNum(payload.TargetVersion)
+	if err != nil {
+		return true, nil, err
+	}
+
+	driver, err := server.getAdminDatabaseDriver(ctx, task.Instance, "" /* databaseName */)
+	if err != nil {
+		return true, nil, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, "")
+	if err != nil {
+		return true, nil, err
+	}
+	var sourceVersion string
+	var sourceVersionNum int
+	if err := sqldb.QueryRowContext(ctx, "SELECT current_setting('server_version'), current_setting('server_version_num')::INTEGER").Scan(&sourceVersion, &sourceVersionNum); err != nil {
+		return true, nil, fmt.Errorf("failed to get the server version, error: %w", err)
+	}
+	if sourceVersionNum >= targetVersionNum {
+		return true, nil, fmt.Errorf("PostgreSQL %s is not older than the target version %s", sourceVersion, payload.TargetVersion)
+	}
+	databaseList, err := getUpgradeCheckDatabaseList(ctx, sqldb)
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to list the databases, error: %w", err)
+	}
+
+	var availableSet map[string]bool
+	if payload.TargetInstanceID != 0 {
+		if availableSet, err = server.getAvailableExtensionSet(ctx, payload.TargetInstanceID); err != nil {
+			return true, nil, err
+		}
+	}
+
+	checkList := api.GetPostgresUpgradeCheckList(sourceVersionNum, targetVersionNum)
+	createdTs := time.Now().Unix()
+	var itemList []*api.UpgradeCheckItem
+	for i, databaseName := range databaseList {
+		exec.progress.Store(api.Progress{
+			TotalUnit:     int64(len(databaseList)),
+			CompletedUnit: int64(i),
+			CreatedTs:     createdTs,
+			UpdatedTs:     time.Now().Unix(),
+		})
+		databaseItemList, err := server.checkDatabaseUpgrade(ctx, task.Instance, databaseName, checkList, targetVersionNum, availableSet)
+		if err != nil {
+			return true, nil, fmt.Errorf("failed to check database %q, error: %w", databaseName, err)
+		}
+		itemList = append(itemList, databaseItemList...)
+	}
+
+	report, ready := api.FormatUpgradeCheckReport(sourceVersion, payload.TargetVersion, len(databaseList), itemList)
+	server.attachUpgradeCheckReport(ctx, task, report)
+	if !ready {
+		return true, nil, fmt.Errorf("%s", report)
+	}
+	return true, &api.TaskRunResultPayload{
+		Detail: report,
+	}, nil
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *UpgradeCheckTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (exec *UpgradeCheckTaskExecutor) GetProgress() api.Progress {
+	progress := exec.progress.Load()
+	if progress == nil {
+		return api.Progress{}
+	}
+	return progress.(api.Progress)
+}
+
+// getUpgradeCheckDatabaseList returns the databases checked by pg_upgrade, which are all the connectable ones.
+func getUpgradeCheckDatabaseList(ctx context.Context, sqldb *sql.DB) ([]string, error) {
+	return queryStringList(ctx, sqldb, "SELECT datname FROM pg_catalog.pg_database WHERE datallowconn ORDER BY datname")
+}
+
+// getAvailableExtensionSet returns the extensions available in the target Postgres instance.
+func (s *Server) getAvailableExtensionSet(ctx context.Context, instanceID int) (map[string]bool, error) {
+	instance, err := s.store.GetInstanceByID(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find target instance ID %d, error: %w", instanceID, err)
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("target instance ID not found %d", instanceID)
+	}
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	extensionList, err := queryStringList(ctx, sqldb, "SELECT name FROM pg_catalog.pg_available_extensions")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the available extensions of target instance %q, error: %w", instance.Name, err)
+	}
+	availableSet := make(map[string]bool)
+	for _, extension := range extensionList {
+		availableSet[extension] = true
+	}
+	return availableSet, nil
+}
+
+// checkDatabaseUpgrade runs the upgrade checks in the database.
+func (s *Server) checkDatabaseUpgrade(ctx context.Context, instance *api.Instance, databaseName string, checkList []*api.PostgresUpgradeCheck, targetVersionNum int, availableSet map[string]bool) ([]*api.UpgradeCheckItem, error) {
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	sqldb, err := driver.GetDBConnection(ctx, databaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	var itemList []*api.UpgradeCheckItem
+	for _, check := range checkList {
+		objectList, err := queryStringList(ctx, sqldb, check.Query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %q, error: %w", check.Title, err)
+		}
+		if len(objectList) == 0 {
+			continue
+		}
+		itemList = append(itemList, &api.UpgradeCheckItem{
+			Severity:   api.UpgradeCheckError,
+			Title:      check.Title,
+			Database:   databaseName,
+			ObjectList: objectList,
+		})
+	}
+
+	extensionList, err := queryStringList(ctx, sqldb, "SELECT extname FROM pg_catalog.pg_extension ORDER BY extname")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the extensions, error: %w", err)
+	}
+	itemList = append(itemList, api.GetPostgresExtensionUpgradeCheckItemList(databaseName, extensionList, targetVersionNum, availableSet)...)
+	return itemList, nil
+}
+
+// attachUpgradeCheckReport comments the report on the issue of the task.
+func (s *Server) attachUpgradeCheckReport(ctx context.Context, task *api.Task, report string) {
+	issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
+	if err != nil {
+		log.Error("Failed to find the issue of the upgrade check task", zap.Int("task_id", task.ID), zap.Error(err))
+		return
+	}
+	if issue == nil {
+		return
+	}
+	bytes, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Name,
+	})
+	if err != nil {
+		log.Error("Failed to marshal activity payload", zap.Int("task_id", task.ID), zap.Error(err))
+		return
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.ID,
+		Type:        api.ActivityIssueCommentCreate,
+		Level:       api.ActivityInfo,
+		Comment:     report,
+		Payload:     string(bytes),
+	}, &ActivityMeta{issue: issue}); err != nil {
+		log.Error("Failed to attach the upgrade check report to the issue", zap.Int("issue_id", issue.ID), zap.Error(err))
+	}
+}
+
+func queryStringList(ctx context.Context, sqldb *sql.DB, query string) ([]string, error) {
+	rows, err := sqldb.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
+	metricAPI "github.com/bytebase/bytebase/metric"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/metric"
 
 	"go.uber.org/zap"
 )
@@ -20,6 +22,9 @@ const (
 	taskSchedulerInterval = time.Duration(1) * time.Second
 )
 
+// schedulerLog logs on behalf of the scheduler subsystem whose level can be changed at runtime.
+var schedulerLog = log.Named(log.SubsystemScheduler)
+
 // NewTaskScheduler creates a new task scheduler.
 func NewTaskScheduler(server *Server) *TaskScheduler {
 	return &TaskScheduler{
@@ -43,7 +48,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 	ticker := time.NewTicker(taskSchedulerInterval)
 	defer ticker.Stop()
 	defer wg.Done()
-	log.Debug(fmt.Sprintf("Task scheduler started and will run every %v", taskSchedulerInterval))
+	schedulerLog.Debug(fmt.Sprintf("Task scheduler started and will run every %v", taskSchedulerInterval))
 	for {
 		select {
 		case <-ticker.C:
@@ -54,7 +59,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 						if !ok {
 							err = fmt.Errorf("%v", r)
 						}
-						log.Error("Task scheduler PANIC RECOVER", zap.Error(err))
+						schedulerLog.Error("Task scheduler PANIC RECOVER", zap.Error(err))
 					}
 				}()
 
@@ -80,7 +85,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 				}
 				pipelineList, err := s.server.store.FindPipeline(ctx, pipelineFind, false)
 				if err != nil {
-					log.Error("Failed to retrieve open pipelines", zap.Error(err))
+					schedulerLog.Error("Failed to retrieve open pipelines", zap.Error(err))
 					return
 				}
 				for _, pipeline := range pipelineList {
@@ -89,7 +94,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					}
 
 					if _, err := s.server.ScheduleNextTaskIfNeeded(ctx, pipeline); err != nil {
-						log.Error("Failed to schedule next running task",
+						schedulerLog.Error("Failed to schedule next running task",
 							zap.Int("pipeline_id", pipeline.ID),
 							zap.Error(err),
 						)
@@ -105,7 +110,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 				// We may optimize this in the future since only some relationship info is needed by the executor
 				taskList, err := s.server.store.FindTask(ctx, taskFind, false)
 				if err != nil {
-					log.Error("Failed to retrieve running tasks", zap.Error(err))
+					schedulerLog.Error("Failed to retrieve running tasks", zap.Error(err))
 					return
 				}
 
@@ -121,7 +126,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 					executorGetter, ok := s.executorGetters[task.Type]
 					if !ok {
-						log.Error("Skip running task with unknown type",
+						schedulerLog.Error("Skip running task with unknown type",
 							zap.Int("id", task.ID),
 							zap.String("name", task.Name),
 							zap.String("type", string(task.Type)),
@@ -137,7 +142,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					go func(task *api.Task, executor TaskExecutor) {
 						done, result, err := RunTaskExecutorOnce(ctx, executor, s.server, task)
 						if !done && err != nil {
-							log.Debug("Encountered transient error running task, will retry",
+							schedulerLog.Debug("Encountered transient error running task, will retry",
 								zap.Int("id", task.ID),
 								zap.String("name", task.Name),
 								zap.String("type", string(task.Type)),
@@ -146,7 +151,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							return
 						}
 						if done && err != nil {
-							log.Warn("Failed to run task",
+							schedulerLog.Warn("Failed to run task",
 								zap.Int("id", task.ID),
 								zap.String("name", task.Name),
 								zap.String("type", string(task.Type)),
@@ -156,7 +161,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								Detail: err.Error(),
 							})
 							if marshalErr != nil {
-								log.Error("Failed to marshal task run result",
+								schedulerLog.Error("Failed to marshal task run result",
 									zap.Int("task_id", task.ID),
 									zap.String("type", string(task.Type)),
 									zap.Error(marshalErr),
@@ -174,18 +179,28 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							}
 							_, err = s.server.patchTaskStatus(ctx, task, taskStatusPatch)
 							if err != nil {
-								log.Error("Failed to mark task as FAILED",
+								schedulerLog.Error("Failed to mark task as FAILED",
 									zap.Int("id", task.ID),
 									zap.String("name", task.Name),
 									zap.Error(err),
 								)
 							}
+							if s.server.MetricReporter != nil {
+								s.server.MetricReporter.report(&metric.Metric{
+									Name:  metricAPI.TaskRunMetricName,
+									Value: 1,
+									Labels: map[string]string{
+										"type":   string(task.Type),
+										"status": string(api.TaskFailed),
+									},
+								})
+							}
 							return
 						}
 						if done && err == nil {
 							bytes, err := json.Marshal(*result)
 							if err != nil {
-								log.Error("Failed to marshal task run result",
+								schedulerLog.Error("Failed to marshal task run result",
 									zap.Int("task_id", task.ID),
 									zap.String("type", string(task.Type)),
 									zap.Error(err),
@@ -203,12 +218,22 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							}
 							_, err = s.server.patchTaskStatus(ctx, task, taskStatusPatch)
 							if err != nil {
-								log.Error("Failed to mark task as DONE",
+								schedulerLog.Error("Failed to mark task as DONE",
 									zap.Int("id", task.ID),
 									zap.String("name", task.Name),
 									zap.Error(err),
 								)
 							}
+							if s.server.MetricReporter != nil {
+								s.server.MetricReporter.report(&metric.Metric{
+									Name:  metricAPI.TaskRunMetricName,
+									Value: 1,
+									Labels: map[string]string{
+										"type":   string(task.Type),
+										"status": string(api.TaskDone),
+									},
+								})
+							}
 							return
 						}
 					}(task, s.runningExecutors[task.ID])
@@ -250,6 +275,15 @@ func (s *TaskScheduler) passAllCheck(ctx context.Context, task *api.Task, allowe
 			return false, nil
 		}
 
+		// The duplicate migration only warns, so it stops the auto-approval but not the approved task.
+		pass, err = s.server.passCheck(ctx, task, api.TaskCheckDatabaseMigrationDuplicate, allowedStatus)
+		if err != nil {
+			return false, err
+		}
+		if !pass {
+			return false, nil
+		}
+
 		instance, err := s.server.store.GetInstanceByID(ctx, task.InstanceID)
 		if err != nil {
 			return false, err
@@ -278,6 +312,20 @@ func (s *TaskScheduler) passAllCheck(ctx context.Context, task *api.Task, allowe
 			}
 		}
 
+		externalAdvisorSetting, err := s.server.getExternalAdvisorSetting(ctx)
+		if err != nil {
+			return false, err
+		}
+		if externalAdvisorSetting.Enabled() {
+			pass, err = s.server.passCheck(ctx, task, api.TaskCheckDatabaseStatementExternalAdvise, allowedStatus)
+			if err != nil {
+				return false, err
+			}
+			if !pass {
+				return false, nil
+			}
+		}
+
 		if instance.Engine == db.Postgres {
 			pass, err = s.server.passCheck(ctx, task, api.TaskCheckDatabaseStatementType, allowedStatus)
 			if err != nil {
@@ -289,6 +337,36 @@ func (s *TaskScheduler) passAllCheck(ctx context.Context, task *api.Task, allowe
 		}
 	}
 
+	if task.Type == api.TaskDatabaseCreate {
+		pass, err := s.server.passCheck(ctx, task, api.TaskCheckDatabaseCreateName, allowedStatus)
+		if err != nil {
+			return false, err
+		}
+		if !pass {
+			return false, nil
+		}
+	}
+
+	if task.Type == api.TaskDatabaseDrop {
+		pass, err := s.server.passCheck(ctx, task, api.TaskCheckDatabaseDropActivity, allowedStatus)
+		if err != nil {
+			return false, err
+		}
+		if !pass {
+			return false, nil
+		}
+	}
+
+	if task.Type == api.TaskDatabaseSchemaUpdateGhostCutover || task.Type == api.TaskDatabasePITRCutover {
+		pass, err := s.server.passCheck(ctx, task, api.TaskCheckDatabaseReplicationLag, allowedStatus)
+		if err != nil {
+			return false, err
+		}
+		if !pass {
+			return false, nil
+		}
+	}
+
 	if task.Type == api.TaskDatabaseSchemaUpdateGhostSync {
 		pass, err := s.server.passCheck(ctx, task, api.TaskCheckGhostSync, allowedStatus)
 		if err != nil {
@@ -315,6 +393,13 @@ func (s *TaskScheduler) canSchedule(ctx context.Context, task *api.Task) (bool,
 	if blocked {
 		return false, nil
 	}
+	blocked, err = s.isBlockedByIssue(ctx, task)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if task is blocked by issues, error: %w", err)
+	}
+	if blocked {
+		return false, nil
+	}
 	// timing task check
 	if task.EarliestAllowedTs != 0 {
 		pass, err := s.server.passCheck(ctx, task, api.TaskCheckGeneralEarliestAllowedTime, api.TaskCheckStatusSuccess)
@@ -325,14 +410,59 @@ func (s *TaskScheduler) canSchedule(ctx context.Context, task *api.Task) (bool,
 			return false, nil
 		}
 	}
+	// stage gate task check
+	stageGatePolicy, err := s.server.store.GetStageGatePolicy(ctx, task.Instance.EnvironmentID, api.GetPolicyTagList(task.Instance, task.Database))
+	if err != nil {
+		return false, err
+	}
+	if len(stageGatePolicy.GateList) > 0 {
+		pass, err := s.server.passCheck(ctx, task, api.TaskCheckGeneralStageGate, api.TaskCheckStatusSuccess)
+		if err != nil {
+			return false, err
+		}
+		if !pass {
+			return false, nil
+		}
+	}
+	// replication lag task check, the lag changes over time so it must be measured recently.
+	if task.Type == api.TaskDatabaseSchemaUpdateGhostCutover || task.Type == api.TaskDatabasePITRCutover {
+		recent, err := s.isTaskCheckRecent(ctx, task, api.TaskCheckDatabaseReplicationLag, replicationLagPollInterval)
+		if err != nil {
+			return false, err
+		}
+		if !recent {
+			return false, nil
+		}
+	}
 
 	return s.passAllCheck(ctx, task, api.TaskCheckStatusWarn)
 }
 
+// isTaskCheckRecent returns true if the latest task check of the type has finished within the period.
+func (s *TaskScheduler) isTaskCheckRecent(ctx context.Context, task *api.Task, checkType api.TaskCheckType, period time.Duration) (bool, error) {
+	statusList := []api.TaskCheckRunStatus{api.TaskCheckRunDone, api.TaskCheckRunFailed}
+	taskCheckRunList, err := s.server.store.FindTaskCheckRun(ctx, &api.TaskCheckRunFind{
+		TaskID:     &task.ID,
+		Type:       &checkType,
+		StatusList: &statusList,
+		Latest:     true,
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(taskCheckRunList) == 0 {
+		return false, nil
+	}
+	return time.Since(time.Unix(taskCheckRunList[0].UpdatedTs, 0)) < period, nil
+}
+
 // ScheduleIfNeeded schedules the task if
 //   1. its required check does not contain error in the latest run.
 //   2. it has no blocking tasks.
-//   3. it has passed the earliest allowed time.
+//   3. it's not in the first stage of an issue blocked by unfinished issues.
+//   4. it has passed the earliest allowed time.
+//   5. it has passed the stage gates.
+//   6. it has the replication lag under the threshold recently if it's a cutover task.
 func (s *TaskScheduler) ScheduleIfNeeded(ctx context.Context, task *api.Task) (*api.Task, error) {
 	schedule, err := s.canSchedule(ctx, task)
 	if err != nil {
@@ -370,3 +500,27 @@ func (s *TaskScheduler) isTaskBlocked(ctx context.Context, task *api.Task) (bool
 	}
 	return false, nil
 }
+
+// isBlockedByIssue returns true if the task is in the first stage of its issue, and any issue blocking the issue isn't done.
+// The later stages aren't blocked, because the issue has started before the dependency is declared.
+func (s *TaskScheduler) isBlockedByIssue(ctx context.Context, task *api.Task) (bool, error) {
+	stageList, err := s.server.store.FindStage(ctx, &api.StageFind{PipelineID: &task.PipelineID})
+	if err != nil {
+		return true, fmt.Errorf("failed to fetch the stages of pipeline %d, error: %w", task.PipelineID, err)
+	}
+	if len(stageList) == 0 || stageList[0].ID != task.StageID {
+		return false, nil
+	}
+	issue, err := s.server.store.GetIssueByPipelineID(ctx, task.PipelineID)
+	if err != nil {
+		return true, fmt.Errorf("failed to fetch the issue of pipeline %d, error: %w", task.PipelineID, err)
+	}
+	if issue == nil {
+		return false, nil
+	}
+	blockingIssueList, err := s.server.getUnfinishedBlockingIssueList(ctx, issue.ID)
+	if err != nil {
+		return true, fmt.Errorf("failed to fetch the issues blocking issue %d, error: %w", issue.ID, err)
+	}
+	return len(blockingIssueList) > 0, nil
+}
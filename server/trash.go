@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerTrashRoutes(g *echo.Group) {
+	// Deleting a project, an instance or a database archives it and moves it to the trash,
+	// it can be restored before it's purged after the retention days of the workspace trash setting.
+	g.DELETE("/project/:projectID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		if id == api.DefaultProjectID {
+			return echo.NewHTTPError(http.StatusBadRequest, "The default project cannot be deleted")
+		}
+		project, err := s.store.GetProjectByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", id)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project not found with ID %d", id))
+		}
+		databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{ProjectID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find databases in the project %d", id)).SetInternal(err)
+		}
+		if len(databaseList) > 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "You should transfer all databases under the project before deleting the project.")
+		}
+
+		updaterID := c.Get(getPrincipalIDContextKey()).(int)
+		if project.RowStatus != api.Archived {
+			rowStatus := string(api.Archived)
+			if _, err := s.store.PatchProject(ctx, &api.ProjectPatch{
+				ID:        id,
+				UpdaterID: updaterID,
+				RowStatus: &rowStatus,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to archive project ID: %v", id)).SetInternal(err)
+			}
+		}
+		return s.createTrash(c, &api.TrashCreate{
+			CreatorID:    updaterID,
+			ResourceType: api.TrashProject,
+			ResourceID:   id,
+			Name:         project.Name,
+		})
+	})
+
+	g.DELETE("/instance/:instanceID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+		instance, err := s.store.GetInstanceByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", id)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", id))
+		}
+		databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{InstanceID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find databases in the instance %d", id)).SetInternal(err)
+		}
+		var databaseNameList []string
+		for _, database := range databaseList {
+			if database.ProjectID != api.DefaultProjectID {
+				databaseNameList = append(databaseNameList, database.Name)
+			}
+		}
+		if len(databaseNameList) > 0 {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Sprintf("You should transfer these databases to the default project before deleting the instance: %s.", strings.Join(databaseNameList, ", ")))
+		}
+
+		updaterID := c.Get(getPrincipalIDContextKey()).(int)
+		if instance.RowStatus != api.Archived {
+			rowStatus := string(api.Archived)
+			if _, err := s.store.PatchInstance(ctx, &api.InstancePatch{
+				ID:        id,
+				UpdaterID: updaterID,
+				RowStatus: &rowStatus,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to archive instance ID: %v", id)).SetInternal(err)
+			}
+		}
+		return s.createTrash(c, &api.TrashCreate{
+			CreatorID:    updaterID,
+			ResourceType: api.TrashInstance,
+			ResourceID:   id,
+			Name:         instance.Name,
+		})
+	})
+
+	// Only the databases missing from the instance can be deleted, otherwise the schema syncer finds them again.
+	// The existing databases should be dropped by the drop database issue first.
+	g.DELETE("/database/:databaseID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
+		}
+		if database.SyncStatus != api.NotFound {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q still exists on the instance, drop it first.", database.Name))
+		}
+
+		updaterID := c.Get(getPrincipalIDContextKey()).(int)
+		if database.RowStatus != api.Archived {
+			rowStatus := api.Archived
+			if _, err := s.store.PatchDatabase(ctx, &api.DatabasePatch{
+				ID:        id,
+				UpdaterID: updaterID,
+				RowStatus: &rowStatus,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to archive database ID: %v", id)).SetInternal(err)
+			}
+		}
+		return s.createTrash(c, &api.TrashCreate{
+			CreatorID:    updaterID,
+			ResourceType: api.TrashDatabase,
+			ResourceID:   id,
+			Name:         database.Name,
+		})
+	})
+
+	g.GET("/trash", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find := &api.TrashFind{}
+		if resourceType := c.QueryParam("resourceType"); resourceType != "" {
+			t := api.TrashResourceType(resourceType)
+			find.ResourceType = &t
+		}
+		trashList, err := s.store.FindTrash(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch trash list").SetInternal(err)
+		}
+		setting, err := s.getTrashSetting(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get trash setting").SetInternal(err)
+		}
+		for _, trash := range trashList {
+			trash.PurgeTs = setting.PurgeTs(trash.CreatedTs)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, trashList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal trash list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/trash/:trashID/restore", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("trashID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Trash ID is not a number: %s", c.Param("trashID"))).SetInternal(err)
+		}
+		trash, err := s.store.GetTrash(ctx, &api.TrashFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch trash ID: %v", id)).SetInternal(err)
+		}
+		if trash == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Trash ID not found: %d", id))
+		}
+		setting, err := s.getTrashSetting(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get trash setting").SetInternal(err)
+		}
+		if time.Now().Unix() >= setting.PurgeTs(trash.CreatedTs) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%q has passed the %d days retention window and is being purged", trash.Name, setting.RetentionDays))
+		}
+
+		updaterID := c.Get(getPrincipalIDContextKey()).(int)
+		normal := api.Normal
+		switch trash.ResourceType {
+		case api.TrashProject:
+			rowStatus := string(normal)
+			if _, err := s.store.PatchProject(ctx, &api.ProjectPatch{
+				ID:        trash.ResourceID,
+				UpdaterID: updaterID,
+				RowStatus: &rowStatus,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to restore project ID: %v", trash.ResourceID)).SetInternal(err)
+			}
+		case api.TrashInstance:
+			if err := s.instanceCountGuard(ctx); err != nil {
+				return err
+			}
+			rowStatus := string(normal)
+			if _, err := s.store.PatchInstance(ctx, &api.InstancePatch{
+				ID:        trash.ResourceID,
+				UpdaterID: updaterID,
+				RowStatus: &rowStatus,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to restore instance ID: %v", trash.ResourceID)).SetInternal(err)
+			}
+		case api.TrashDatabase:
+			// The databases of the archived instances are not found, the instance should be restored first.
+			database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &trash.ResourceID})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", trash.ResourceID)).SetInternal(err)
+			}
+			if database == nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The instance of database %q is deleted or archived, restore the instance first", trash.Name))
+			}
+			if _, err := s.store.PatchDatabase(ctx, &api.DatabasePatch{
+				ID:        trash.ResourceID,
+				UpdaterID: updaterID,
+				RowStatus: &normal,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to restore database ID: %v", trash.ResourceID)).SetInternal(err)
+			}
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Unsupported trash resource type %q", trash.ResourceType))
+		}
+		if err := s.store.DeleteTrash(ctx, trash.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to remove %q from the trash", trash.Name)).SetInternal(err)
+		}
+
+		trash.PurgeTs = setting.PurgeTs(trash.CreatedTs)
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, trash); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal restore trash response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+func (s *Server) createTrash(c echo.Context, create *api.TrashCreate) error {
+	ctx := c.Request().Context()
+	trash, err := s.store.CreateTrash(ctx, create)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to move %q to the trash", create.Name)).SetInternal(err)
+	}
+	setting, err := s.getTrashSetting(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get trash setting").SetInternal(err)
+	}
+	trash.PurgeTs = setting.PurgeTs(trash.CreatedTs)
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	if err := jsonapi.MarshalPayload(c.Response().Writer, trash); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal trash response").SetInternal(err)
+	}
+	return nil
+}
+
+// getTrashSetting returns the workspace trash setting, or the default one if it's not set.
+func (s *Server) getTrashSetting(ctx context.Context) (*api.TrashSetting, error) {
+	name := api.SettingWorkspaceTrash
+	settingList, err := s.store.FindSetting(ctx, &api.SettingFind{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+	setting := &api.TrashSetting{
+		RetentionDays: api.DefaultTrashRetentionDays,
+	}
+	if len(settingList) == 0 {
+		return setting, nil
+	}
+	if err := json.Unmarshal([]byte(settingList[0].Value), setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trash setting %q, error: %w", settingList[0].Value, err)
+	}
+	return setting, nil
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// The retention window is in days, so the hourly check purges the resources in time.
+	trashPurgerInterval = time.Duration(1) * time.Hour
+)
+
+// NewTrashPurger creates a trash purger.
+func NewTrashPurger(server *Server) *TrashPurger {
+	return &TrashPurger{
+		server: server,
+	}
+}
+
+// TrashPurger purges the resources in the trash after the retention days of the workspace trash setting.
+type TrashPurger struct {
+	server *Server
+}
+
+// Run will run the trash purger.
+func (p *TrashPurger) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(trashPurgerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Trash purger started and will run every %v", trashPurgerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						log.Error("Trash purger PANIC RECOVER", zap.Error(err))
+					}
+				}()
+				p.purge(ctx, time.Now())
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (p *TrashPurger) purge(ctx context.Context, now time.Time) {
+	setting, err := p.server.getTrashSetting(ctx)
+	if err != nil {
+		log.Error("Failed to get trash setting", zap.Error(err))
+		return
+	}
+	createdBeforeTs := now.AddDate(0, 0, -setting.RetentionDays).Unix()
+	trashList, err := p.server.store.FindTrash(ctx, &api.TrashFind{CreatedBeforeTs: &createdBeforeTs})
+	if err != nil {
+		log.Error("Failed to find the trash to purge", zap.Error(err))
+		return
+	}
+	for _, trash := range trashList {
+		deleted, err := p.server.store.PurgeTrash(ctx, trash)
+		if err != nil {
+			log.Error("Failed to purge trash",
+				zap.String("type", string(trash.ResourceType)),
+				zap.Int("id", trash.ResourceID),
+				zap.String("name", trash.Name),
+				zap.Error(err),
+			)
+			continue
+		}
+		log.Info("Purged trash",
+			zap.String("type", string(trash.ResourceType)),
+			zap.Int("id", trash.ResourceID),
+			zap.String("name", trash.Name),
+			zap.Bool("deleted", deleted),
+		)
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerUsageMetricRoutes(g *echo.Group) {
+	// The usage metrics are aggregated daily in the workspace regardless of the report level,
+	// so the workspace owners can see exactly what is collected.
+	// The from and to query parameters are the inclusive UTC dates in YYYY-MM-DD format.
+	g.GET("/metric/usage", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find := &api.UsageMetricFind{}
+		if v := c.QueryParam("from"); v != "" {
+			if _, err := time.Parse(api.UsageMetricDateFormat, v); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter from is not a date in YYYY-MM-DD format: %s", v)).SetInternal(err)
+			}
+			find.FromDate = &v
+		}
+		if v := c.QueryParam("to"); v != "" {
+			if _, err := time.Parse(api.UsageMetricDateFormat, v); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter to is not a date in YYYY-MM-DD format: %s", v)).SetInternal(err)
+			}
+			find.ToDate = &v
+		}
+		if v := c.QueryParam("name"); v != "" {
+			find.Name = &v
+		}
+
+		usageMetricList, err := s.store.FindUsageMetric(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch usage metric list").SetInternal(err)
+		}
+		if usageMetricList == nil {
+			usageMetricList = []*api.UsageMetric{}
+		}
+		return c.JSON(http.StatusOK, usageMetricList)
+	})
+}
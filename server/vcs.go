@@ -12,6 +12,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/github"
 )
 
 func (s *Server) registerVCSRoutes(g *echo.Group) {
@@ -23,6 +24,15 @@ func (s *Server) registerVCSRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, vcsCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create VCS request").SetInternal(err)
 		}
+		if err := s.checkVCSReachable(vcsCreate.Type); err != nil {
+			return err
+		}
+		if vcsCreate.AuthMode == "" {
+			vcsCreate.AuthMode = vcs.AuthModeOAuth
+		}
+		if err := validateVCSAuth(vcsCreate.Type, vcsCreate.AuthMode, vcsCreate.ApplicationID, vcsCreate.Secret, vcsCreate.WebhookSecret); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create VCS request: %s", err.Error()))
+		}
 		// Trim ending "/"
 		vcsCreate.InstanceURL = strings.TrimRight(vcsCreate.InstanceURL, "/")
 		vcsCreate.APIURL = vcs.Get(vcsCreate.Type, vcs.ProviderConfig{}).APIURL(vcsCreate.InstanceURL)
@@ -88,6 +98,29 @@ func (s *Server) registerVCSRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed change VCS request").SetInternal(err)
 		}
 
+		if vcsPatch.ApplicationID != nil || vcsPatch.Secret != nil || vcsPatch.WebhookSecret != nil {
+			vcsFound, err := s.store.GetVCSByID(ctx, id)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch VCS ID: %v", id)).SetInternal(err)
+			}
+			if vcsFound == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS ID not found: %d", id))
+			}
+			applicationID, secret, webhookSecret := vcsFound.ApplicationID, vcsFound.Secret, vcsFound.WebhookSecret
+			if vcsPatch.ApplicationID != nil {
+				applicationID = *vcsPatch.ApplicationID
+			}
+			if vcsPatch.Secret != nil {
+				secret = *vcsPatch.Secret
+			}
+			if vcsPatch.WebhookSecret != nil {
+				webhookSecret = *vcsPatch.WebhookSecret
+			}
+			if err := validateVCSAuth(vcsFound.Type, vcsFound.AuthMode, applicationID, secret, webhookSecret); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed change VCS request: %s", err.Error()))
+			}
+		}
+
 		vcs, err := s.store.PatchVCS(ctx, vcsPatch)
 		if err != nil {
 			if common.ErrorCode(err) == common.NotFound {
@@ -162,17 +195,45 @@ func (s *Server) registerVCSRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Failed to find VCS, ID: %v", id))
 		}
 
-		repoList, err := vcs.Get(vcsFound.Type, vcs.ProviderConfig{}).FetchAllRepositoryList(
-			ctx,
-			common.OauthContext{
-				ClientID:     vcsFound.ApplicationID,
-				ClientSecret: vcsFound.Secret,
-				AccessToken:  accessToken,
-				RefreshToken: refreshToken,
-				Refresher:    nil,
-			},
-			vcsFound.InstanceURL,
-		)
+		var repoList []*vcs.Repository
+		if groupID := c.QueryParam("groupId"); groupID != "" {
+			// Browse the repositories in the GitLab group and its nested subgroups.
+			if vcsFound.Type != vcs.GitLabSelfHost {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Groups are only supported by %s, but VCS %q is %s", vcs.GitLabSelfHost, vcsFound.Name, vcsFound.Type))
+			}
+			repoList, err = getGitLabProvider().FetchGroupRepositoryList(
+				ctx,
+				common.OauthContext{
+					ClientID:     vcsFound.ApplicationID,
+					ClientSecret: vcsFound.Secret,
+					AccessToken:  accessToken,
+					RefreshToken: refreshToken,
+					Refresher:    nil,
+				},
+				vcsFound.InstanceURL,
+				groupID,
+			)
+		} else if vcsFound.AuthMode == vcs.AuthModeGitHubApp {
+			// The GitHub App lists the repositories it's installed on, regardless of the user.
+			var appClient *github.AppClient
+			appClient, err = github.NewAppClient(nil, vcsFound.ApplicationID, vcsFound.Secret)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create GitHub App client, VCS ID: %v", id)).SetInternal(err)
+			}
+			repoList, err = appClient.FetchAllRepositoryList(ctx, vcsFound.InstanceURL)
+		} else {
+			repoList, err = vcs.Get(vcsFound.Type, vcs.ProviderConfig{}).FetchAllRepositoryList(
+				ctx,
+				common.OauthContext{
+					ClientID:     vcsFound.ApplicationID,
+					ClientSecret: vcsFound.Secret,
+					AccessToken:  accessToken,
+					RefreshToken: refreshToken,
+					Refresher:    nil,
+				},
+				vcsFound.InstanceURL,
+			)
+		}
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find external repository, instance URL: %s", vcsFound.InstanceURL)).SetInternal(err)
 		}
@@ -195,3 +256,24 @@ func (s *Server) registerVCSRoutes(g *echo.Group) {
 		return nil
 	})
 }
+
+// validateVCSAuth validates the credentials of the auth mode.
+// The GitHub App authenticates with the app ID and the private key, and verifies the webhook events with the webhook secret.
+func validateVCSAuth(vcsType vcs.Type, authMode vcs.AuthMode, applicationID, secret, webhookSecret string) error {
+	switch authMode {
+	case vcs.AuthModeOAuth:
+		return nil
+	case vcs.AuthModeGitHubApp:
+		if vcsType != vcs.GitHubCom {
+			return fmt.Errorf("auth mode %s is only supported by %s", authMode, vcs.GitHubCom)
+		}
+		if _, err := github.NewAppClient(nil, applicationID, secret); err != nil {
+			return fmt.Errorf("invalid GitHub App credentials: %w", err)
+		}
+		if webhookSecret == "" {
+			return fmt.Errorf("webhook secret is required by the GitHub App")
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid auth mode %q", authMode)
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
+)
+
+func (s *Server) registerVCSGroupWebhookRoutes(g *echo.Group) {
+	// The groups are listed as a flat list, and the frontend builds the tree of the nested subgroups by the parent IDs.
+	g.GET("/vcs/:vcsID/external-group", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		vcs, err := s.getGitLabVCS(ctx, c.Param("vcsID"))
+		if err != nil {
+			return err
+		}
+
+		groupList, err := getGitLabProvider().FetchGroupList(
+			ctx,
+			common.OauthContext{
+				ClientID:     vcs.ApplicationID,
+				ClientSecret: vcs.Secret,
+				AccessToken:  c.Request().Header.Get("accessToken"),
+				RefreshToken: c.Request().Header.Get("refreshToken"),
+				Refresher:    nil,
+			},
+			vcs.InstanceURL,
+		)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find external group, instance URL: %s", vcs.InstanceURL)).SetInternal(err)
+		}
+
+		var externalGroupList []*api.ExternalGroup
+		for _, group := range groupList {
+			externalGroupList = append(externalGroupList, &api.ExternalGroup{
+				ID:       group.ID,
+				Name:     group.Name,
+				FullPath: group.FullPath,
+				ParentID: group.ParentID,
+				WebURL:   group.WebURL,
+			})
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, externalGroupList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/vcs/:vcsID/group-webhook", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("vcsID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("vcsID"))).SetInternal(err)
+		}
+
+		webhookList, err := s.store.FindVCSGroupWebhook(ctx, &api.VCSGroupWebhookFind{VCSID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch group webhook list for VCS ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, webhookList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal group webhook list response for VCS ID: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/vcs/:vcsID/group-webhook", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		vcs, err := s.getGitLabVCS(ctx, c.Param("vcsID"))
+		if err != nil {
+			return err
+		}
+
+		webhookCreate := &api.VCSGroupWebhookCreate{
+			CreatorID: c.Get(getPrincipalIDContextKey()).(int),
+			VCSID:     vcs.ID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, webhookCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create group webhook request").SetInternal(err)
+		}
+		if webhookCreate.ExternalGroupID == "" || webhookCreate.GroupFullPath == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create group webhook request, the group is required")
+		}
+		webhookList, err := s.store.FindVCSGroupWebhook(ctx, &api.VCSGroupWebhookFind{VCSID: &vcs.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch group webhook list for VCS ID: %v", vcs.ID)).SetInternal(err)
+		}
+		if err := validateGroupWebhookOverlap(webhookList, webhookCreate.GroupFullPath); err != nil {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+
+		webhookCreate.WebhookURLHost = fmt.Sprintf("%s:%d", s.profile.BackendHost, s.profile.BackendPort)
+		webhookCreate.WebhookEndpointID = uuid.New().String()
+		secretToken, err := common.RandomString(gitlab.SecretTokenLength)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate random secret token for GitLab").SetInternal(err)
+		}
+		webhookCreate.WebhookSecretToken = secretToken
+
+		// The group webhook delivers the push events of all branches, the branch filter of each linked repository is applied on receiving.
+		payload, err := json.Marshal(gitlab.WebhookCreate{
+			URL:                   fmt.Sprintf("%s/%s/%s", webhookCreate.WebhookURLHost, gitlabGroupWebhookPath, webhookCreate.WebhookEndpointID),
+			SecretToken:           webhookCreate.WebhookSecretToken,
+			PushEvents:            true,
+			EnableSSLVerification: false,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal request body for creating webhook for group %s", webhookCreate.GroupFullPath)).SetInternal(err)
+		}
+		webhookID, err := getGitLabProvider().CreateGroupWebhook(
+			ctx,
+			common.OauthContext{
+				ClientID:     vcs.ApplicationID,
+				ClientSecret: vcs.Secret,
+				AccessToken:  webhookCreate.AccessToken,
+				RefreshToken: webhookCreate.RefreshToken,
+				// We use refreshTokenNoop() because the group webhook isn't created yet.
+				Refresher: refreshTokenNoop(),
+			},
+			vcs.InstanceURL,
+			webhookCreate.ExternalGroupID,
+			payload,
+		)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create webhook for group %s, which requires the owner role of the group and GitLab Premium", webhookCreate.GroupFullPath)).SetInternal(err)
+		}
+		webhookCreate.ExternalWebhookID = webhookID
+
+		webhook, err := s.store.CreateVCSGroupWebhook(ctx, webhookCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Group %s already has a webhook", webhookCreate.GroupFullPath))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create group webhook for group %s", webhookCreate.GroupFullPath)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, webhook); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create group webhook response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/vcs/:vcsID/group-webhook/:webhookID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		vcs, err := s.getGitLabVCS(ctx, c.Param("vcsID"))
+		if err != nil {
+			return err
+		}
+		id, err := strconv.Atoi(c.Param("webhookID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Group webhook ID is not a number: %s", c.Param("webhookID"))).SetInternal(err)
+		}
+
+		webhook, err := s.store.GetVCSGroupWebhook(ctx, &api.VCSGroupWebhookFind{ID: &id, VCSID: &vcs.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch group webhook ID: %v", id)).SetInternal(err)
+		}
+		if webhook == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Group webhook not found with ID %d", id))
+		}
+
+		// The repositories linked under the group have no webhooks of their own, they would stop receiving the push events.
+		repoList, err := s.store.FindRepository(ctx, &api.RepositoryFind{VCSID: &vcs.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch repository list for VCS ID: %v", vcs.ID)).SetInternal(err)
+		}
+		for _, repo := range repoList {
+			if repo.ExternalWebhookID == "" && webhook.Covers(repo.FullPath) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Repository %s of project %q relies on the webhook of group %s, please unlink it first", repo.FullPath, repo.Project.Name, webhook.GroupFullPath))
+			}
+		}
+
+		if err := s.store.DeleteVCSGroupWebhook(ctx, &api.VCSGroupWebhookDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete group webhook ID: %v", id)).SetInternal(err)
+		}
+
+		// Delete the webhook after we successfully delete the record, the same as unlinking the repository.
+		if err := getGitLabProvider().DeleteGroupWebhook(
+			ctx,
+			common.OauthContext{
+				ClientID:     vcs.ApplicationID,
+				ClientSecret: vcs.Secret,
+				AccessToken:  webhook.AccessToken,
+				RefreshToken: webhook.RefreshToken,
+				Refresher:    s.refreshGroupWebhookToken(ctx, webhook.ID),
+			},
+			vcs.InstanceURL,
+			webhook.ExternalGroupID,
+			webhook.ExternalWebhookID,
+		); err != nil {
+			// Despite the error here, we have deleted the group webhook in the database, we still return success.
+			log.Error("Failed to delete webhook for group", zap.String("group", webhook.GroupFullPath), zap.Error(err))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// getGitLabVCS gets the VCS of the ID, which must be GitLab since only the GitLab groups are supported.
+func (s *Server) getGitLabVCS(ctx context.Context, idStr string) (*api.VCS, error) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", idStr)).SetInternal(err)
+	}
+	vcs, err := s.store.GetVCSByID(ctx, id)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch VCS, ID: %v", id)).SetInternal(err)
+	}
+	if vcs == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Failed to find VCS, ID: %v", id))
+	}
+	if vcs.Type != vcsPlugin.GitLabSelfHost {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Groups are only supported by %s, but VCS %q is %s", vcsPlugin.GitLabSelfHost, vcs.Name, vcs.Type))
+	}
+	return vcs, nil
+}
+
+// validateGroupWebhookOverlap returns an error if the group is the same as, or nested with, a group already having the webhook.
+// GitLab delivers the push event to the webhooks of all the ancestor groups, so the nested webhooks would process it twice.
+func validateGroupWebhookOverlap(webhookList []*api.VCSGroupWebhook, groupFullPath string) error {
+	for _, webhook := range webhookList {
+		if webhook.GroupFullPath == groupFullPath {
+			return fmt.Errorf("group %s already has a webhook", groupFullPath)
+		}
+		if webhook.Covers(groupFullPath) || strings.HasPrefix(webhook.GroupFullPath, groupFullPath+"/") {
+			return fmt.Errorf("group %s overlaps group %s which already has a webhook", groupFullPath, webhook.GroupFullPath)
+		}
+	}
+	return nil
+}
+
+// findCoveringGroupWebhook returns the group webhook of the VCS covering the repository of the full path, or nil if there is none.
+func (s *Server) findCoveringGroupWebhook(ctx context.Context, vcsID int, repositoryFullPath string) (*api.VCSGroupWebhook, error) {
+	webhookList, err := s.store.FindVCSGroupWebhook(ctx, &api.VCSGroupWebhookFind{VCSID: &vcsID})
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhookList {
+		if webhook.Covers(repositoryFullPath) {
+			return webhook, nil
+		}
+	}
+	return nil, nil
+}
+
+// refreshGroupWebhookToken returns the token refresher storing the refreshed token of the group webhook.
+func (s *Server) refreshGroupWebhookToken(ctx context.Context, webhookID int) common.TokenRefresher {
+	return func(token, refreshToken string, expiresTs int64) error {
+		if _, err := s.store.PatchVCSGroupWebhook(ctx, &api.VCSGroupWebhookPatch{
+			ID:           webhookID,
+			UpdaterID:    api.SystemBotID,
+			AccessToken:  &token,
+			ExpiresTs:    &expiresTs,
+			RefreshToken: &refreshToken,
+		}); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+func getGitLabProvider() *gitlab.Provider {
+	return vcsPlugin.Get(vcsPlugin.GitLabSelfHost, vcsPlugin.ProviderConfig{}).(*gitlab.Provider)
+}
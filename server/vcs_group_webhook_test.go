@@ -0,0 +1,37 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGroupWebhookOverlap(t *testing.T) {
+	webhookList := []*api.VCSGroupWebhook{
+		{GroupFullPath: "acme/backend"},
+		{GroupFullPath: "tools"},
+	}
+	tests := []struct {
+		groupFullPath string
+		wantErr       bool
+	}{
+		// The duplicate webhook of the same group.
+		{groupFullPath: "acme/backend", wantErr: true},
+		// The subgroup of the group with the webhook.
+		{groupFullPath: "acme/backend/payment", wantErr: true},
+		// The parent group of the group with the webhook.
+		{groupFullPath: "acme", wantErr: true},
+		{groupFullPath: "acme/frontend", wantErr: false},
+		{groupFullPath: "acme/backend-legacy", wantErr: false},
+		{groupFullPath: "toolsmith", wantErr: false},
+	}
+	for _, test := range tests {
+		err := validateGroupWebhookOverlap(webhookList, test.groupFullPath)
+		if test.wantErr {
+			require.Error(t, err, test.groupFullPath)
+		} else {
+			require.NoError(t, err, test.groupFullPath)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerVersionAdvisoryRoutes(g *echo.Group) {
+	// Returns the version advisory of each instance in the environment, including the supported ones.
+	g.GET("/environment/:environmentID/version-advisory", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		environmentID, err := strconv.Atoi(c.Param("environmentID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Environment ID is not a number: %s", c.Param("environmentID"))).SetInternal(err)
+		}
+
+		rowStatus := api.Normal
+		instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{
+			EnvironmentID: &environmentID,
+			RowStatus:     &rowStatus,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance list for environment ID: %d", environmentID)).SetInternal(err)
+		}
+
+		now := time.Now()
+		advisoryList := []*api.VersionAdvisory{}
+		for _, instance := range instanceList {
+			advisory := api.GetVersionAdvisory(instance.Engine, instance.EngineVersion, now)
+			advisory.InstanceID = instance.ID
+			advisory.InstanceName = instance.Name
+			advisory.EnvironmentID = instance.EnvironmentID
+			advisoryList = append(advisoryList, advisory)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, advisoryList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal version advisory response for environment ID: %d", environmentID)).SetInternal(err)
+		}
+		return nil
+	})
+}
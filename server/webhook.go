@@ -29,10 +29,14 @@ import (
 )
 
 var (
-	gitlabWebhookPath = "hook/gitlab"
-	githubWebhookPath = "hook/github"
+	gitlabWebhookPath      = "hook/gitlab"
+	gitlabGroupWebhookPath = "hook/gitlab/group"
+	githubWebhookPath      = "hook/github"
 )
 
+// vcsLog logs on behalf of the vcs subsystem whose level can be changed at runtime.
+var vcsLog = log.Named(log.SubsystemVCS)
+
 func (s *Server) registerWebhookRoutes(g *echo.Group) {
 	g.POST("/gitlab/:id", func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -73,54 +77,63 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project mismatch, got %d, want %s", pushEvent.Project.ID, repo.ExternalID))
 		}
 
-		log.Debug("Processing GitLab webhook push event...",
-			zap.String("project", repo.Project.Name),
-		)
+		return s.processGitLabPushEvent(c, repo, *pushEvent)
+	})
+	// The group webhook delivers the push events of all the projects under the group and its subgroups,
+	// which are dispatched to the repositories linked without the webhook of their own.
+	g.POST("/gitlab/group/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		webhookEndpointID := c.Param("id")
+		webhook, err := s.store.GetVCSGroupWebhook(ctx, &api.VCSGroupWebhookFind{WebhookEndpointID: &webhookEndpointID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to respond group webhook event for endpoint: %v", webhookEndpointID)).SetInternal(err)
+		}
+		if webhook == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Group webhook endpoint not found: %v", webhookEndpointID))
+		}
 
-		distinctFileList := dedupMigrationFilesFromCommitList(pushEvent.CommitList)
-		var createdMessageList []string
-		for _, item := range distinctFileList {
-			createdMessage, created, httpErr := s.createIssueFromPushEvent(
-				ctx,
-				repo,
-				vcs.PushEvent{
-					VCSType:            repo.VCS.Type,
-					BaseDirectory:      repo.BaseDirectory,
-					Ref:                pushEvent.Ref,
-					RepositoryID:       strconv.Itoa(pushEvent.Project.ID),
-					RepositoryURL:      pushEvent.Project.WebURL,
-					RepositoryFullPath: pushEvent.Project.FullPath,
-					AuthorName:         pushEvent.AuthorName,
-					FileCommit: vcs.FileCommit{
-						ID:          item.commit.ID,
-						Title:       item.commit.Title,
-						Message:     item.commit.Message,
-						CreatedTs:   item.createdTime.Unix(),
-						URL:         item.commit.URL,
-						AuthorName:  item.commit.Author.Name,
-						AuthorEmail: item.commit.Author.Email,
-						Added:       common.EscapeForLogging(item.fileName),
-					},
-				},
-				item.fileName,
-				webhookEndpointID,
-			)
-			if httpErr != nil {
-				return httpErr
-			}
+		// The token is compared in constant time, so that the response time doesn't reveal the prefix of the token.
+		if subtle.ConstantTimeCompare([]byte(c.Request().Header.Get("X-Gitlab-Token")), []byte(webhook.WebhookSecretToken)) != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Secret token mismatch")
+		}
 
-			if created {
-				createdMessageList = append(createdMessageList, createdMessage)
-			}
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		pushEvent := &gitlab.WebhookPushEvent{}
+		if err := json.Unmarshal(body, pushEvent); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+		}
+		if pushEvent.ObjectKind != gitlab.WebhookPush {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want push", pushEvent.ObjectKind))
+		}
+		if !webhook.Covers(pushEvent.Project.FullPath) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project mismatch, %s isn't under group %s", pushEvent.Project.FullPath, webhook.GroupFullPath))
 		}
 
-		if len(createdMessageList) == 0 {
-			msg := "Ignored push event. No applicable file found in the commit list."
-			log.Warn(msg,
-				zap.String("project", repo.Project.Name),
-			)
+		repoList, err := s.store.FindRepository(ctx, &api.RepositoryFind{VCSID: &webhook.VCSID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch repository list for VCS ID: %v", webhook.VCSID)).SetInternal(err)
+		}
+		branch, err := vcs.Branch(pushEvent.Ref)
+		if err != nil {
+			return c.String(http.StatusOK, fmt.Sprintf("Ignored push event, %s", err.Error()))
+		}
+		for _, repo := range repoList {
+			// The repository with the webhook of its own receives the push event through that one.
+			if repo.ExternalID != strconv.Itoa(pushEvent.Project.ID) || repo.ExternalWebhookID != "" {
+				continue
+			}
+			// The group webhook has no branch filter, so the one of the repository is applied the same way as GitLab does.
+			if repo.BranchFilter != "" {
+				if matched, err := path.Match(repo.BranchFilter, branch); err != nil || !matched {
+					return c.String(http.StatusOK, fmt.Sprintf("Ignored push event, branch %s doesn't match the branch filter %s", branch, repo.BranchFilter))
+				}
+			}
+			return s.processGitLabPushEvent(c, repo, *pushEvent)
 		}
-		return c.String(http.StatusOK, strings.Join(createdMessageList, "\n"))
+		return c.String(http.StatusOK, fmt.Sprintf("Ignored push event, project %s isn't linked to any project", pushEvent.Project.FullPath))
 	})
 	g.POST("/github/:id", func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -169,64 +182,171 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project mismatch, got %s, want %s", pushEvent.Repository.FullName, repo.ExternalID))
 		}
 
-		log.Debug("Processing GitHub webhook push event...",
+		return s.processGitHubPushEvent(c, repo, pushEvent)
+	})
+	// The GitHub App delivers the events of all the repositories it's installed on to this endpoint,
+	// which are verified by the webhook secret of the app instead of the per-repository secret.
+	g.POST("/github/app/:vcsID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		vcsID, err := strconv.Atoi(c.Param("vcsID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("VCS ID is not a number: %s", c.Param("vcsID"))).SetInternal(err)
+		}
+		vcsFound, err := s.store.GetVCSByID(ctx, vcsID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch VCS ID: %v", vcsID)).SetInternal(err)
+		}
+		if vcsFound == nil || vcsFound.AuthMode != vcs.AuthModeGitHubApp {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("GitHub App not found with VCS ID: %d", vcsID))
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		validated, err := validateGitHubWebhookSignature256(c.Request().Header.Get("X-Hub-Signature-256"), vcsFound.WebhookSecret, body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate GitHub webhook signature").SetInternal(err)
+		}
+		if !validated {
+			return echo.NewHTTPError(http.StatusBadRequest, "Mismatched payload signature")
+		}
+
+		// The app also receives the events such as ping and installation, which we don't act on.
+		eventType := github.WebhookType(c.Request().Header.Get("X-GitHub-Event"))
+		if eventType != github.WebhookPush {
+			return c.String(http.StatusOK, fmt.Sprintf("Ignored %s event", eventType))
+		}
+
+		var pushEvent github.WebhookPushEvent
+		if err := json.Unmarshal(body, &pushEvent); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+		}
+
+		repoList, err := s.store.FindRepository(ctx, &api.RepositoryFind{VCSID: &vcsID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch repository list for VCS ID: %v", vcsID)).SetInternal(err)
+		}
+		for _, repo := range repoList {
+			if repo.ExternalID == pushEvent.Repository.FullName {
+				return s.processGitHubPushEvent(c, repo, pushEvent)
+			}
+		}
+		return c.String(http.StatusOK, fmt.Sprintf("Ignored push event, repository %s isn't linked to any project", pushEvent.Repository.FullName))
+	})
+}
+
+// processGitLabPushEvent creates the issues for the migration files added by the GitLab push event.
+func (s *Server) processGitLabPushEvent(c echo.Context, repo *api.Repository, pushEvent gitlab.WebhookPushEvent) error {
+	ctx := c.Request().Context()
+	vcsLog.Debug("Processing GitLab webhook push event...",
+		zap.String("project", repo.Project.Name),
+	)
+
+	distinctFileList := dedupMigrationFilesFromCommitList(pushEvent.CommitList)
+	var createdMessageList []string
+	for _, item := range distinctFileList {
+		status, message, httpErr := s.processPushEventFile(
+			ctx,
+			repo,
+			vcs.PushEvent{
+				VCSType:            repo.VCS.Type,
+				BaseDirectory:      repo.BaseDirectory,
+				Ref:                pushEvent.Ref,
+				RepositoryID:       strconv.Itoa(pushEvent.Project.ID),
+				RepositoryURL:      pushEvent.Project.WebURL,
+				RepositoryFullPath: pushEvent.Project.FullPath,
+				AuthorName:         pushEvent.AuthorName,
+				FileCommit: vcs.FileCommit{
+					ID:          item.commit.ID,
+					Title:       item.commit.Title,
+					Message:     item.commit.Message,
+					CreatedTs:   item.createdTime.Unix(),
+					URL:         item.commit.URL,
+					AuthorName:  item.commit.Author.Name,
+					AuthorEmail: item.commit.Author.Email,
+					Added:       common.EscapeForLogging(item.fileName),
+				},
+			},
+			item.fileName,
+		)
+		if httpErr != nil {
+			return httpErr
+		}
+
+		if status == api.RepositoryPushEventDone {
+			createdMessageList = append(createdMessageList, message)
+		}
+	}
+
+	if len(createdMessageList) == 0 {
+		msg := "Ignored push event. No applicable file found in the commit list."
+		vcsLog.Warn(msg,
 			zap.String("project", repo.Project.Name),
 		)
+	}
+	return c.String(http.StatusOK, strings.Join(createdMessageList, "\n"))
+}
 
-		var createdMessageList []string
-		for _, commit := range pushEvent.Commits {
-			// The Distinct is false if the commit is superseded by a later commit.
-			if !commit.Distinct {
-				continue
-			}
+// processGitHubPushEvent creates the issues for the migration files added by the GitHub push event.
+func (s *Server) processGitHubPushEvent(c echo.Context, repo *api.Repository, pushEvent github.WebhookPushEvent) error {
+	ctx := c.Request().Context()
+	vcsLog.Debug("Processing GitHub webhook push event...",
+		zap.String("project", repo.Project.Name),
+	)
 
-			for _, added := range commit.Added {
-				// Per Git convention, the message title and body are separated by two new line characters.
-				messages := strings.SplitN(commit.Message, "\n\n", 2)
-				messageTitle := messages[0]
-
-				createdMessage, created, httpErr := s.createIssueFromPushEvent(
-					ctx,
-					repo,
-					vcs.PushEvent{
-						VCSType:            repo.VCS.Type,
-						BaseDirectory:      repo.BaseDirectory,
-						Ref:                pushEvent.Ref,
-						RepositoryID:       strconv.Itoa(pushEvent.Repository.ID),
-						RepositoryURL:      pushEvent.Repository.HTMLURL,
-						RepositoryFullPath: pushEvent.Repository.FullName,
-						AuthorName:         pushEvent.Sender.Login,
-						FileCommit: vcs.FileCommit{
-							ID:          commit.ID,
-							Title:       messageTitle,
-							Message:     commit.Message,
-							CreatedTs:   commit.Timestamp.Unix(),
-							URL:         commit.URL,
-							AuthorName:  commit.Author.Name,
-							AuthorEmail: commit.Author.Email,
-							Added:       common.EscapeForLogging(added),
-						},
+	var createdMessageList []string
+	for _, commit := range pushEvent.Commits {
+		// The Distinct is false if the commit is superseded by a later commit.
+		if !commit.Distinct {
+			continue
+		}
+
+		for _, added := range commit.Added {
+			// Per Git convention, the message title and body are separated by two new line characters.
+			messages := strings.SplitN(commit.Message, "\n\n", 2)
+			messageTitle := messages[0]
+
+			status, message, httpErr := s.processPushEventFile(
+				ctx,
+				repo,
+				vcs.PushEvent{
+					VCSType:            repo.VCS.Type,
+					BaseDirectory:      repo.BaseDirectory,
+					Ref:                pushEvent.Ref,
+					RepositoryID:       strconv.Itoa(pushEvent.Repository.ID),
+					RepositoryURL:      pushEvent.Repository.HTMLURL,
+					RepositoryFullPath: pushEvent.Repository.FullName,
+					AuthorName:         pushEvent.Sender.Login,
+					FileCommit: vcs.FileCommit{
+						ID:          commit.ID,
+						Title:       messageTitle,
+						Message:     commit.Message,
+						CreatedTs:   commit.Timestamp.Unix(),
+						URL:         commit.URL,
+						AuthorName:  commit.Author.Name,
+						AuthorEmail: commit.Author.Email,
+						Added:       common.EscapeForLogging(added),
 					},
-					added,
-					webhookEndpointID,
-				)
-				if httpErr != nil {
-					return httpErr
-				}
+				},
+				added,
+			)
+			if httpErr != nil {
+				return httpErr
+			}
 
-				if created {
-					createdMessageList = append(createdMessageList, createdMessage)
-				}
+			if status == api.RepositoryPushEventDone {
+				createdMessageList = append(createdMessageList, message)
 			}
 		}
+	}
 
-		if len(createdMessageList) == 0 {
-			log.Warn("Ignored push event. No applicable file found in the commit list.",
-				zap.String("project", repo.Project.Name),
-			)
-		}
-		return c.String(http.StatusOK, strings.Join(createdMessageList, "\n"))
-	})
+	if len(createdMessageList) == 0 {
+		vcsLog.Warn("Ignored push event. No applicable file found in the commit list.",
+			zap.String("project", repo.Project.Name),
+		)
+	}
+	return c.String(http.StatusOK, strings.Join(createdMessageList, "\n"))
 }
 
 // validateGitHubWebhookSignature256 returns true if the signature matches the
@@ -275,14 +395,14 @@ func dedupMigrationFilesFromCommitList(commitList []gitlab.WebhookCommit) []dist
 	// Use list instead of map because we need to maintain the relative commit order in the source branch.
 	var distinctFileList []distinctFileItem
 	for _, commit := range commitList {
-		log.Debug("Pre-processing commit to dedup migration files...",
+		vcsLog.Debug("Pre-processing commit to dedup migration files...",
 			zap.String("id", common.EscapeForLogging(commit.ID)),
 			zap.String("title", common.EscapeForLogging(commit.Title)),
 		)
 
 		createdTime, err := time.Parse(time.RFC3339, commit.Timestamp)
 		if err != nil {
-			log.Warn("Ignored commit, failed to parse commit timestamp.", zap.String("commit", common.EscapeForLogging(commit.ID)), zap.String("timestamp", common.EscapeForLogging(commit.Timestamp)), zap.Error(err))
+			vcsLog.Warn("Ignored commit, failed to parse commit timestamp.", zap.String("commit", common.EscapeForLogging(commit.ID)), zap.String("timestamp", common.EscapeForLogging(commit.Timestamp)), zap.Error(err))
 		}
 
 		for _, added := range commit.AddedList {
@@ -311,7 +431,7 @@ func dedupMigrationFilesFromCommitList(commitList []gitlab.WebhookCommit) []dist
 	return distinctFileList
 }
 
-func (s *Server) createSchemaUpdateIssue(ctx context.Context, repository *api.Repository, mi *db.MigrationInfo, vcsPushEvent vcs.PushEvent, added string, statement string) (string, error) {
+func (s *Server) createSchemaUpdateIssue(ctx context.Context, repository *api.Repository, mi *db.MigrationInfo, vcsPushEvent vcs.PushEvent, added string, statement string, downStatement string) (string, error) {
 	// Find matching database list
 	databaseFind := &api.DatabaseFind{
 		ProjectID: &repository.ProjectID,
@@ -375,8 +495,9 @@ func (s *Server) createSchemaUpdateIssue(ctx context.Context, repository *api.Re
 	for _, database := range filteredDatabaseList {
 		m.DetailList = append(m.DetailList,
 			&api.UpdateSchemaDetail{
-				DatabaseID: database.ID,
-				Statement:  statement,
+				DatabaseID:    database.ID,
+				Statement:     statement,
+				DownStatement: downStatement,
 			})
 	}
 	createContext, err := json.Marshal(m)
@@ -386,7 +507,7 @@ func (s *Server) createSchemaUpdateIssue(ctx context.Context, repository *api.Re
 	return string(createContext), nil
 }
 
-func createTenantSchemaUpdateIssue(mi *db.MigrationInfo, vcsPushEvent vcs.PushEvent, statement string) (string, error) {
+func createTenantSchemaUpdateIssue(mi *db.MigrationInfo, vcsPushEvent vcs.PushEvent, statement string, downStatement string) (string, error) {
 	// We don't take environment for tenant mode project because the databases needing schema update are determined by database name and deployment configuration.
 	if mi.Environment != "" {
 		return "", fmt.Errorf("environment isn't accepted in schema update for tenant mode project")
@@ -396,8 +517,9 @@ func createTenantSchemaUpdateIssue(mi *db.MigrationInfo, vcsPushEvent vcs.PushEv
 		VCSPushEvent:  &vcsPushEvent,
 		DetailList: []*api.UpdateSchemaDetail{
 			{
-				DatabaseName: mi.Database,
-				Statement:    statement,
+				DatabaseName:  mi.Database,
+				Statement:     statement,
+				DownStatement: downStatement,
 			},
 		},
 	}
@@ -408,36 +530,91 @@ func createTenantSchemaUpdateIssue(mi *db.MigrationInfo, vcsPushEvent vcs.PushEv
 	return string(createContext), nil
 }
 
+// processPushEventFile creates the issue for the given file of the push event
+// received from the VCS, and records the result so that the failed or ignored
+// ones can be inspected and replayed later.
+func (s *Server) processPushEventFile(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent, file string) (api.RepositoryPushEventStatus, string, error) {
+	status, message, err := s.createIssueFromPushEvent(ctx, repo, pushEvent, file, repo.WebhookEndpointID)
+	recordStatus, recordMessage := status, message
+	if err != nil {
+		recordStatus, recordMessage = api.RepositoryPushEventFailed, err.Error()
+	}
+	if _, recordErr := s.store.CreateRepositoryPushEvent(ctx, &api.RepositoryPushEventCreate{
+		ProjectID: repo.ProjectID,
+		File:      file,
+		PushEvent: pushEvent,
+		Status:    recordStatus,
+		Message:   recordMessage,
+	}); recordErr != nil {
+		// The push event has been processed, failing to record it shouldn't fail the webhook.
+		vcsLog.Warn("Failed to record repository push event",
+			zap.String("file", common.EscapeForLogging(file)),
+			zap.Error(recordErr),
+		)
+	}
+	return status, message, err
+}
+
 // createIssueFromPushEvent attempts to create a new issue for the given file of
-// the push event. It returns "created=true" when a new issue has been created,
-// along with the creation message to be presented in the UI. An *echo.HTTPError
-// is returned in case of the error during the process.
-func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent, file, webhookEndpointID string) (message string, created bool, _ error) {
+// the push event. It returns the DONE status when a new issue has been created,
+// along with the creation message to be presented in the UI, or the IGNORED and
+// FAILED status along with the reason. An *echo.HTTPError is returned in case of
+// the error during the process.
+func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent, file, webhookEndpointID string) (status api.RepositoryPushEventStatus, message string, _ error) {
 	fileEscaped := common.EscapeForLogging(file)
-	log.Debug("Processing added file...",
+	vcsLog.Debug("Processing added file...",
 		zap.String("file", fileEscaped),
 		zap.String("commit", common.EscapeForLogging(pushEvent.FileCommit.ID)),
 	)
 
 	if !strings.HasPrefix(fileEscaped, repo.BaseDirectory) {
-		log.Debug("Ignored committed file, not under base directory.",
+		vcsLog.Debug("Ignored committed file, not under base directory.",
 			zap.String("file", fileEscaped),
 			zap.String("base_directory", repo.BaseDirectory),
 		)
-		return "", false, nil
+		return api.RepositoryPushEventIgnored, fmt.Sprintf("not under base directory %q", repo.BaseDirectory), nil
 	}
 
 	// Ignore the schema file we auto generated to the repository.
 	if isSkipGeneratedSchemaFile(repo, fileEscaped) {
-		log.Debug("Ignored generated latest schema file.",
+		vcsLog.Debug("Ignored generated latest schema file.",
+			zap.String("file", fileEscaped),
+		)
+		return api.RepositoryPushEventIgnored, "generated latest schema file", nil
+	}
+
+	// The down migration files are read along with the paired migration files instead of being applied directly.
+	if db.IsMigrationDownFile(repo.FileFormat, fileEscaped) {
+		vcsLog.Debug("Ignored down migration file.",
 			zap.String("file", fileEscaped),
 		)
-		return "", false, nil
+		return api.RepositoryPushEventIgnored, "down migration file", nil
+	}
+
+	// The environment is determined by the branch or the directory of the file if the environment mapping is configured.
+	environmentMapping, err := api.ValidateAndGetRepositoryEnvironmentMapping(repo.EnvironmentMapping, repo.Project.TenantMode)
+	if err != nil {
+		return api.RepositoryPushEventFailed, err.Error(), nil
+	}
+	mappedEnvironment := ""
+	if len(environmentMapping.RuleList) > 0 {
+		branch, err := vcs.Branch(pushEvent.Ref)
+		if err != nil {
+			return api.RepositoryPushEventFailed, err.Error(), nil
+		}
+		mappedEnvironment = environmentMapping.Match(branch, strings.TrimPrefix(fileEscaped, repo.BaseDirectory+"/"))
+		if mappedEnvironment == "" {
+			vcsLog.Debug("Ignored committed file, no environment mapping rule matches.",
+				zap.String("file", fileEscaped),
+				zap.String("branch", common.EscapeForLogging(branch)),
+			)
+			return api.RepositoryPushEventIgnored, fmt.Sprintf("no environment mapping rule matches branch %q", branch), nil
+		}
 	}
 
 	// Create a WARNING project activity if committed file is ignored
 	var createIgnoredFileActivity = func(err error) {
-		log.Warn("Ignored committed file",
+		vcsLog.Warn("Ignored committed file",
 			zap.String("file", fileEscaped),
 			zap.Error(err),
 		)
@@ -447,7 +624,7 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 			},
 		)
 		if marshalErr != nil {
-			log.Warn("Failed to construct project activity payload to record ignored repository committed file",
+			vcsLog.Warn("Failed to construct project activity payload to record ignored repository committed file",
 				zap.Error(marshalErr),
 			)
 			return
@@ -463,17 +640,25 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 		}
 		_, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{})
 		if err != nil {
-			log.Warn("Failed to create project activity to record ignored repository committed file",
+			vcsLog.Warn("Failed to create project activity to record ignored repository committed file",
 				zap.Error(err),
 			)
 		}
 	}
 
 	// NOTE: We do not want to use filepath.Join here because we always need "/" as the path separator.
-	mi, err := db.ParseMigrationInfo(fileEscaped, path.Join(repo.BaseDirectory, repo.FilePathTemplate))
+	mi, err := db.ParseMigrationFile(repo.FileFormat, fileEscaped, path.Join(repo.BaseDirectory, repo.FilePathTemplate))
 	if err != nil {
 		createIgnoredFileActivity(err)
-		return "", false, nil
+		return api.RepositoryPushEventFailed, err.Error(), nil
+	}
+	if mappedEnvironment != "" {
+		if mi.Environment != "" && !strings.EqualFold(mi.Environment, mappedEnvironment) {
+			err := fmt.Errorf("environment %q in the file path conflicts with environment %q of the environment mapping", mi.Environment, mappedEnvironment)
+			createIgnoredFileActivity(err)
+			return api.RepositoryPushEventFailed, err.Error(), nil
+		}
+		mi.Environment = mappedEnvironment
 	}
 
 	// Retrieve the latest AccessToken and RefreshToken as the previous
@@ -482,10 +667,13 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 	// expired.
 	repo2, err := s.store.GetRepository(ctx, &api.RepositoryFind{WebhookEndpointID: &webhookEndpointID})
 	if err != nil {
-		return "", false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to respond webhook event for endpoint: %v", webhookEndpointID)).SetInternal(err)
+		return "", "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to respond webhook event for endpoint: %v", webhookEndpointID)).SetInternal(err)
 	}
 	if repo2 == nil {
-		return "", false, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Webhook endpoint not found: %v", webhookEndpointID))
+		return "", "", echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Webhook endpoint not found: %v", webhookEndpointID))
+	}
+	if err := s.refreshGitHubAppToken(ctx, repo2); err != nil {
+		return "", "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to refresh token for webhook endpoint: %v", webhookEndpointID)).SetInternal(err)
 	}
 
 	// Retrieve migration SQL script by reading the file content
@@ -505,7 +693,27 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 	)
 	if err != nil {
 		createIgnoredFileActivity(err)
-		return "", false, nil
+		return api.RepositoryPushEventFailed, err.Error(), nil
+	}
+	downStatement, err := s.readMigrationDownStatement(ctx, repo.FileFormat, pushEvent, fileEscaped, content, webhookEndpointID)
+	if err != nil {
+		createIgnoredFileActivity(err)
+		return api.RepositoryPushEventFailed, err.Error(), nil
+	}
+	// Convert the changelog in other formats such as the Liquibase XML to the SQL statement.
+	content, err = db.ParseMigrationStatement(repo.FileFormat, fileEscaped, content)
+	if err != nil {
+		createIgnoredFileActivity(err)
+		return api.RepositoryPushEventFailed, err.Error(), nil
+	}
+
+	if repo.RequireSignedCommit {
+		signature, err := s.verifyPushEventCommitSignature(ctx, pushEvent, webhookEndpointID)
+		if err != nil {
+			createIgnoredFileActivity(err)
+			return api.RepositoryPushEventFailed, err.Error(), nil
+		}
+		pushEvent.FileCommit.Signature = signature
 	}
 
 	// Create schema update issue.
@@ -513,13 +721,13 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 	if pushEvent.FileCommit.AuthorEmail != "" {
 		committerPrincipal, err := s.store.GetPrincipalByEmail(ctx, pushEvent.FileCommit.AuthorEmail)
 		if err != nil {
-			log.Error("failed to find the principal with committer email",
+			vcsLog.Error("failed to find the principal with committer email",
 				zap.String("email", common.EscapeForLogging(pushEvent.FileCommit.AuthorEmail)),
 				zap.Error(err),
 			)
 		}
 		if committerPrincipal == nil {
-			log.Debug("cannot find the principal with committer email, use system bot instead",
+			vcsLog.Debug("cannot find the principal with committer email, use system bot instead",
 				zap.String("email", common.EscapeForLogging(pushEvent.FileCommit.AuthorEmail)),
 			)
 		} else {
@@ -530,15 +738,15 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 	var createContext string
 	if repo.Project.TenantMode == api.TenantModeTenant {
 		if !s.feature(api.FeatureMultiTenancy) {
-			return "", false, echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+			return "", "", echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
 		}
-		createContext, err = createTenantSchemaUpdateIssue(mi, pushEvent, content)
+		createContext, err = createTenantSchemaUpdateIssue(mi, pushEvent, content, downStatement)
 	} else {
-		createContext, err = s.createSchemaUpdateIssue(ctx, repo, mi, pushEvent, fileEscaped, content)
+		createContext, err = s.createSchemaUpdateIssue(ctx, repo, mi, pushEvent, fileEscaped, content, downStatement)
 	}
 	if err != nil {
 		createIgnoredFileActivity(err)
-		return "", false, nil
+		return api.RepositoryPushEventFailed, err.Error(), nil
 	}
 
 	issueType := api.IssueDatabaseSchemaUpdate
@@ -559,7 +767,7 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 		if issueType == api.IssueDatabaseDataUpdate {
 			errMsg = "Failed to create data update issue"
 		}
-		return "", false, echo.NewHTTPError(http.StatusInternalServerError, errMsg).SetInternal(err)
+		return "", "", echo.NewHTTPError(http.StatusInternalServerError, errMsg).SetInternal(err)
 	}
 
 	// Create a project activity after successfully creating the issue as the result of the push event
@@ -571,7 +779,7 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 		},
 	)
 	if err != nil {
-		return "", false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		return "", "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
 	}
 
 	activityCreate := &api.ActivityCreate{
@@ -583,10 +791,116 @@ func (s *Server) createIssueFromPushEvent(ctx context.Context, repo *api.Reposit
 		Payload:     string(bytes),
 	}
 	if _, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
-		return "", false, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create project activity after creating issue from repository push event: %d", issue.ID)).SetInternal(err)
+		return "", "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create project activity after creating issue from repository push event: %d", issue.ID)).SetInternal(err)
+	}
+
+	if signature := pushEvent.FileCommit.Signature; signature != nil {
+		s.recordCommitSignatureOnIssue(ctx, issue, pushEvent.FileCommit.ID, signature)
+	}
+
+	return api.RepositoryPushEventDone, fmt.Sprintf("Created issue %q on adding %s", issue.Name, fileEscaped), nil
+}
+
+// verifyPushEventCommitSignature fetches the signature of the commit of the push event from the VCS,
+// and returns it if it's verified and signed by one of the trusted signing keys of the repository.
+func (s *Server) verifyPushEventCommitSignature(ctx context.Context, pushEvent vcs.PushEvent, webhookEndpointID string) (*vcs.CommitSignature, error) {
+	// Retrieve the latest AccessToken and RefreshToken as the previous ReadFileContent call may have updated the stored token pair.
+	repo, err := s.store.GetRepository(ctx, &api.RepositoryFind{WebhookEndpointID: &webhookEndpointID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repository for webhook endpoint %q, error: %w", webhookEndpointID, err)
+	}
+	if repo == nil {
+		return nil, fmt.Errorf("webhook endpoint not found: %v", webhookEndpointID)
+	}
+	if err := s.refreshGitHubAppToken(ctx, repo); err != nil {
+		return nil, err
+	}
+	signature, err := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{}).FetchCommitSignature(
+		ctx,
+		common.OauthContext{
+			ClientID:     repo.VCS.ApplicationID,
+			ClientSecret: repo.VCS.Secret,
+			AccessToken:  repo.AccessToken,
+			RefreshToken: repo.RefreshToken,
+			Refresher:    s.refreshToken(ctx, repo.ID),
+		},
+		repo.VCS.InstanceURL,
+		repo.ExternalID,
+		pushEvent.FileCommit.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the signature of commit %s, error: %w", pushEvent.FileCommit.ID, err)
+	}
+	if err := api.VerifyCommitSignature(signature, repo.TrustedSigningKeys); err != nil {
+		return nil, fmt.Errorf("signature verification failed for commit %s, %w", pushEvent.FileCommit.ID, err)
 	}
+	return signature, nil
+}
 
-	return fmt.Sprintf("Created issue %q on adding %s", issue.Name, fileEscaped), true, nil
+// recordCommitSignatureOnIssue comments the verified commit signature on the issue created from the commit.
+func (s *Server) recordCommitSignatureOnIssue(ctx context.Context, issue *api.Issue, commitID string, signature *vcs.CommitSignature) {
+	bytes, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Name,
+	})
+	if err != nil {
+		vcsLog.Warn("Failed to marshal activity payload", zap.Int("issue_id", issue.ID), zap.Error(err))
+		return
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.ID,
+		Type:        api.ActivityIssueCommentCreate,
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("Verified the %s signature of commit %s signed by key %q.", signature.Type, commitID, signature.KeyID),
+		Payload:     string(bytes),
+	}, &ActivityMeta{issue: issue}); err != nil {
+		vcsLog.Warn("Failed to record the commit signature on the issue", zap.Int("issue_id", issue.ID), zap.Error(err))
+	}
+}
+
+// readMigrationDownStatement returns the down statement of the migration file, which is either declared in the migration file
+// such as the Liquibase rollback, or in the paired down migration file such as the Flyway undo migration.
+// The down statement is optional, so it returns empty if the paired down migration file isn't found in the commit.
+func (s *Server) readMigrationDownStatement(ctx context.Context, format db.MigrationFileFormat, pushEvent vcs.PushEvent, file, content, webhookEndpointID string) (string, error) {
+	downFile := db.MigrationDownFilePath(format, file)
+	if downFile == "" {
+		return db.ParseMigrationDownStatement(format, file, content)
+	}
+
+	// Retrieve the latest AccessToken and RefreshToken as the previous ReadFileContent call may have updated the stored token pair.
+	repo, err := s.store.GetRepository(ctx, &api.RepositoryFind{WebhookEndpointID: &webhookEndpointID})
+	if err != nil {
+		return "", fmt.Errorf("failed to find repository for webhook endpoint %q, error: %w", webhookEndpointID, err)
+	}
+	if repo == nil {
+		return "", fmt.Errorf("webhook endpoint not found: %v", webhookEndpointID)
+	}
+	if err := s.refreshGitHubAppToken(ctx, repo); err != nil {
+		return "", err
+	}
+	downContent, err := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{}).ReadFileContent(
+		ctx,
+		common.OauthContext{
+			ClientID:     repo.VCS.ApplicationID,
+			ClientSecret: repo.VCS.Secret,
+			AccessToken:  repo.AccessToken,
+			RefreshToken: repo.RefreshToken,
+			Refresher:    s.refreshToken(ctx, repo.ID),
+		},
+		repo.VCS.InstanceURL,
+		repo.ExternalID,
+		downFile,
+		pushEvent.FileCommit.ID,
+	)
+	if err != nil {
+		vcsLog.Debug("Down migration file not found, the migration can't be reverted.",
+			zap.String("file", file),
+			zap.String("down_file", downFile),
+			zap.Error(err),
+		)
+		return "", nil
+	}
+	return downContent, nil
 }
 
 // We may write back the latest schema file to the repository after migration and we need to ignore
@@ -603,7 +917,7 @@ func isSkipGeneratedSchemaFile(repository *api.Repository, added string) bool {
 		}
 		myRegex, err := regexp.Compile(schemafilePathRegex)
 		if err != nil {
-			log.Warn("Invalid schema path template.", zap.String("schema_path_template",
+			vcsLog.Warn("Invalid schema path template.", zap.String("schema_path_template",
 				repository.SchemaPathTemplate),
 				zap.Error(err),
 			)
@@ -0,0 +1,228 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// accessChangeHistoryRaw is the store model for an AccessChangeHistory.
+// Fields have exactly the same meanings as AccessChangeHistory.
+type accessChangeHistoryRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	InstanceID int
+	DatabaseID int
+	IssueID    int
+	TaskID     int
+
+	// Domain specific fields
+	Action    api.AccessChangeAction
+	UserName  string
+	Statement string
+	Error     string
+}
+
+// toAccessChangeHistory creates an instance of AccessChangeHistory based on the accessChangeHistoryRaw.
+// This is intended to be called when we need to compose an AccessChangeHistory relationship.
+func (raw *accessChangeHistoryRaw) toAccessChangeHistory() *api.AccessChangeHistory {
+	return &api.AccessChangeHistory{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		InstanceID: raw.InstanceID,
+		DatabaseID: raw.DatabaseID,
+		IssueID:    raw.IssueID,
+		TaskID:     raw.TaskID,
+
+		// Domain specific fields
+		Action:    raw.Action,
+		UserName:  raw.UserName,
+		Statement: raw.Statement,
+		Error:     raw.Error,
+	}
+}
+
+// CreateAccessChangeHistory creates an instance of AccessChangeHistory.
+func (s *Store) CreateAccessChangeHistory(ctx context.Context, create *api.AccessChangeHistoryCreate) (*api.AccessChangeHistory, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createAccessChangeHistoryImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access change history with AccessChangeHistoryCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeAccessChangeHistory(ctx, raw)
+}
+
+// FindAccessChangeHistory finds a list of AccessChangeHistory instances in the descending ID order.
+func (s *Store) FindAccessChangeHistory(ctx context.Context, find *api.AccessChangeHistoryFind) ([]*api.AccessChangeHistory, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findAccessChangeHistoryImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find access change history list with AccessChangeHistoryFind[%+v], error: %w", find, err)
+	}
+	var historyList []*api.AccessChangeHistory
+	for _, raw := range rawList {
+		history, err := s.composeAccessChangeHistory(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		historyList = append(historyList, history)
+	}
+	return historyList, nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeAccessChangeHistory(ctx context.Context, raw *accessChangeHistoryRaw) (*api.AccessChangeHistory, error) {
+	history := raw.toAccessChangeHistory()
+
+	creator, err := s.GetPrincipalByID(ctx, history.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	history.Creator = creator
+
+	return history, nil
+}
+
+func createAccessChangeHistoryImpl(ctx context.Context, tx *sql.Tx, create *api.AccessChangeHistoryCreate) (*accessChangeHistoryRaw, error) {
+	query := `
+		INSERT INTO access_change_history (
+			creator_id,
+			instance_id,
+			database_id,
+			issue_id,
+			task_id,
+			action,
+			user_name,
+			statement,
+			error
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, creator_id, created_ts, instance_id, database_id, issue_id, task_id, action, user_name, statement, error
+	`
+	var raw accessChangeHistoryRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.InstanceID,
+		create.DatabaseID,
+		create.IssueID,
+		create.TaskID,
+		create.Action,
+		create.UserName,
+		create.Statement,
+		create.Error,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.InstanceID,
+		&raw.DatabaseID,
+		&raw.IssueID,
+		&raw.TaskID,
+		&raw.Action,
+		&raw.UserName,
+		&raw.Statement,
+		&raw.Error,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findAccessChangeHistoryImpl(ctx context.Context, tx *sql.Tx, find *api.AccessChangeHistoryFind) ([]*accessChangeHistoryRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			instance_id,
+			database_id,
+			issue_id,
+			task_id,
+			action,
+			user_name,
+			statement,
+			error
+		FROM access_change_history
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*accessChangeHistoryRaw
+	for rows.Next() {
+		var raw accessChangeHistoryRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.InstanceID,
+			&raw.DatabaseID,
+			&raw.IssueID,
+			&raw.TaskID,
+			&raw.Action,
+			&raw.UserName,
+			&raw.Statement,
+			&raw.Error,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// accountReportRaw is the store model for an AccountReport.
+// Fields have exactly the same meanings as AccountReport.
+type accountReportRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Domain specific fields
+	AccountCount int
+	FlaggedCount int
+	Payload      string
+}
+
+// toAccountReport creates an instance of AccountReport based on the accountReportRaw.
+// This is intended to be called when we need to compose an AccountReport relationship.
+func (raw *accountReportRaw) toAccountReport() *api.AccountReport {
+	return &api.AccountReport{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Domain specific fields
+		AccountCount: raw.AccountCount,
+		FlaggedCount: raw.FlaggedCount,
+		Payload:      raw.Payload,
+	}
+}
+
+// CreateAccountReport creates an instance of AccountReport.
+func (s *Store) CreateAccountReport(ctx context.Context, create *api.AccountReportCreate) (*api.AccountReport, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createAccountReportImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account report, error: %w", err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeAccountReport(ctx, raw)
+}
+
+// GetAccountReportByID gets an instance of AccountReport.
+func (s *Store) GetAccountReportByID(ctx context.Context, id int) (*api.AccountReport, error) {
+	list, err := s.FindAccountReport(ctx, &api.AccountReportFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d account reports with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// GetLatestAccountReport gets the latest AccountReport, nil if there is none.
+func (s *Store) GetLatestAccountReport(ctx context.Context) (*api.AccountReport, error) {
+	limit := 1
+	list, err := s.FindAccountReport(ctx, &api.AccountReportFind{Limit: &limit})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// FindAccountReport finds a list of AccountReport instances in the descending ID order.
+func (s *Store) FindAccountReport(ctx context.Context, find *api.AccountReportFind) ([]*api.AccountReport, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findAccountReportImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account report list with AccountReportFind[%+v], error: %w", find, err)
+	}
+	var reportList []*api.AccountReport
+	for _, raw := range rawList {
+		report, err := s.composeAccountReport(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		reportList = append(reportList, report)
+	}
+	return reportList, nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeAccountReport(ctx context.Context, raw *accountReportRaw) (*api.AccountReport, error) {
+	report := raw.toAccountReport()
+
+	creator, err := s.GetPrincipalByID(ctx, report.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	report.Creator = creator
+
+	return report, nil
+}
+
+func createAccountReportImpl(ctx context.Context, tx *sql.Tx, create *api.AccountReportCreate) (*accountReportRaw, error) {
+	query := `
+		INSERT INTO account_report (
+			creator_id,
+			account_count,
+			flagged_count,
+			payload
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, creator_id, created_ts, account_count, flagged_count, payload
+	`
+	var raw accountReportRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.AccountCount,
+		create.FlaggedCount,
+		create.Payload,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.AccountCount,
+		&raw.FlaggedCount,
+		&raw.Payload,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findAccountReportImpl(ctx context.Context, tx *sql.Tx, find *api.AccountReportFind) ([]*accountReportRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	query := `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			account_count,
+			flagged_count,
+			payload
+		FROM account_report
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*accountReportRaw
+	for rows.Next() {
+		var raw accountReportRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.AccountCount,
+			&raw.FlaggedCount,
+			&raw.Payload,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
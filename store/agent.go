@@ -0,0 +1,575 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// agentRaw is the store model for an Agent.
+// Fields have exactly the same meanings as Agent.
+type agentRaw struct {
+	ID int
+
+	// Standard fields
+	RowStatus api.RowStatus
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Domain specific fields
+	Name            string
+	TokenHash       string
+	Version         string
+	LastHeartbeatTs int64
+}
+
+// toAgent creates an instance of Agent based on the agentRaw.
+// This is intended to be called when we need to compose an Agent relationship.
+func (raw *agentRaw) toAgent() *api.Agent {
+	return &api.Agent{
+		ID: raw.ID,
+
+		// Standard fields
+		RowStatus: raw.RowStatus,
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Domain specific fields
+		Name:            raw.Name,
+		TokenHash:       raw.TokenHash,
+		Version:         raw.Version,
+		LastHeartbeatTs: raw.LastHeartbeatTs,
+	}
+}
+
+// CreateAgent creates an instance of Agent.
+// The token is only returned upon creation.
+func (s *Store) CreateAgent(ctx context.Context, create *api.AgentCreate) (*api.Agent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	agentRaw, err := createAgentImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Agent with name %q, error: %w", create.Name, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	agent, err := s.composeAgent(ctx, agentRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose Agent with agentRaw[%+v], error: %w", agentRaw, err)
+	}
+	agent.Token = create.Token
+	return agent, nil
+}
+
+// GetAgent gets an instance of Agent.
+func (s *Store) GetAgent(ctx context.Context, find *api.AgentFind) (*api.Agent, error) {
+	agentRawList, err := s.findAgentRaw(ctx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Agent with AgentFind[%+v], error: %w", find, err)
+	}
+	if len(agentRawList) == 0 {
+		return nil, nil
+	} else if len(agentRawList) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d agents with filter %+v, expect 1", len(agentRawList), find)}
+	}
+	agent, err := s.composeAgent(ctx, agentRawList[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose Agent with agentRaw[%+v], error: %w", agentRawList[0], err)
+	}
+	return agent, nil
+}
+
+// FindAgent finds a list of Agent instances.
+func (s *Store) FindAgent(ctx context.Context, find *api.AgentFind) ([]*api.Agent, error) {
+	agentRawList, err := s.findAgentRaw(ctx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Agent list with AgentFind[%+v], error: %w", find, err)
+	}
+	var agentList []*api.Agent
+	for _, raw := range agentRawList {
+		agent, err := s.composeAgent(ctx, raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compose Agent with agentRaw[%+v], error: %w", raw, err)
+		}
+		agentList = append(agentList, agent)
+	}
+	return agentList, nil
+}
+
+// PatchAgent patches an instance of Agent.
+func (s *Store) PatchAgent(ctx context.Context, patch *api.AgentPatch) (*api.Agent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	agentRaw, err := patchAgentImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch Agent with AgentPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	agent, err := s.composeAgent(ctx, agentRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose Agent with agentRaw[%+v], error: %w", agentRaw, err)
+	}
+	return agent, nil
+}
+
+// CreateAgentTask creates an instance of AgentTask.
+func (s *Store) CreateAgentTask(ctx context.Context, create *api.AgentTaskCreate) (*api.AgentTask, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	agentTask, err := createAgentTaskImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AgentTask with AgentTaskCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return agentTask, nil
+}
+
+// FindAgentTask finds a list of AgentTask instances.
+func (s *Store) FindAgentTask(ctx context.Context, find *api.AgentTaskFind) ([]*api.AgentTask, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findAgentTaskImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find AgentTask list with AgentTaskFind[%+v], error: %w", find, err)
+	}
+	return list, nil
+}
+
+// ClaimAgentTask marks the oldest PENDING task of the agent as RUNNING and returns it.
+// Returns nil if there is no pending task.
+func (s *Store) ClaimAgentTask(ctx context.Context, agentID int) (*api.AgentTask, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	// FOR UPDATE SKIP LOCKED makes sure the same task is never claimed twice by the concurrent polls.
+	var agentTask api.AgentTask
+	if err := tx.PTx.QueryRowContext(ctx, `
+		UPDATE agent_task
+		SET status = $1, updater_id = $2
+		WHERE id = (
+			SELECT id FROM agent_task
+			WHERE agent_id = $3 AND status = $4
+			ORDER BY id ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, agent_id, instance_id, type, status, payload, result
+	`, api.AgentTaskRunning, api.SystemBotID, agentID, api.AgentTaskPending).Scan(
+		&agentTask.ID,
+		&agentTask.CreatorID,
+		&agentTask.CreatedTs,
+		&agentTask.UpdaterID,
+		&agentTask.UpdatedTs,
+		&agentTask.AgentID,
+		&agentTask.InstanceID,
+		&agentTask.Type,
+		&agentTask.Status,
+		&agentTask.Payload,
+		&agentTask.Result,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return &agentTask, nil
+}
+
+// PatchAgentTaskStatus patches the status of an AgentTask.
+func (s *Store) PatchAgentTaskStatus(ctx context.Context, patch *api.AgentTaskStatusPatch) (*api.AgentTask, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	set, args := []string{"updater_id = $1", "status = $2"}, []interface{}{patch.UpdaterID, patch.Status}
+	if v := patch.Result; v != nil {
+		set, args = append(set, fmt.Sprintf("result = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	var agentTask api.AgentTask
+	if err := tx.PTx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE agent_task
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, agent_id, instance_id, type, status, payload, result
+	`, len(args)),
+		args...,
+	).Scan(
+		&agentTask.ID,
+		&agentTask.CreatorID,
+		&agentTask.CreatedTs,
+		&agentTask.UpdaterID,
+		&agentTask.UpdatedTs,
+		&agentTask.AgentID,
+		&agentTask.InstanceID,
+		&agentTask.Type,
+		&agentTask.Status,
+		&agentTask.Payload,
+		&agentTask.Result,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("agent task ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return &agentTask, nil
+}
+
+//
+// private function
+//
+
+func (s *Store) composeAgent(ctx context.Context, raw *agentRaw) (*api.Agent, error) {
+	agent := raw.toAgent()
+
+	creator, err := s.GetPrincipalByID(ctx, agent.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	agent.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, agent.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	agent.Updater = updater
+
+	return agent, nil
+}
+
+// findAgentRaw retrieves a list of agents based on find.
+func (s *Store) findAgentRaw(ctx context.Context, find *api.AgentFind) ([]*agentRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findAgentImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// createAgentImpl creates a new agent, only the hash of the token is stored.
+func createAgentImpl(ctx context.Context, tx *sql.Tx, create *api.AgentCreate) (*agentRaw, error) {
+	// Insert row into database.
+	query := `
+		INSERT INTO agent (
+			creator_id,
+			updater_id,
+			name,
+			token_hash
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, token_hash, version, last_heartbeat_ts
+	`
+	var agentRaw agentRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.Name,
+		common.HashToken(create.Token),
+	).Scan(
+		&agentRaw.ID,
+		&agentRaw.RowStatus,
+		&agentRaw.CreatorID,
+		&agentRaw.CreatedTs,
+		&agentRaw.UpdaterID,
+		&agentRaw.UpdatedTs,
+		&agentRaw.Name,
+		&agentRaw.TokenHash,
+		&agentRaw.Version,
+		&agentRaw.LastHeartbeatTs,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &agentRaw, nil
+}
+
+func findAgentImpl(ctx context.Context, tx *sql.Tx, find *api.AgentFind) ([]*agentRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Token; v != nil {
+		where, args = append(where, fmt.Sprintf("token_hash = $%d", len(args)+1)), append(args, common.HashToken(*v))
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			row_status,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			name,
+			token_hash,
+			version,
+			last_heartbeat_ts
+		FROM agent
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into agentRawList.
+	var agentRawList []*agentRaw
+	for rows.Next() {
+		var agentRaw agentRaw
+		if err := rows.Scan(
+			&agentRaw.ID,
+			&agentRaw.RowStatus,
+			&agentRaw.CreatorID,
+			&agentRaw.CreatedTs,
+			&agentRaw.UpdaterID,
+			&agentRaw.UpdatedTs,
+			&agentRaw.Name,
+			&agentRaw.TokenHash,
+			&agentRaw.Version,
+			&agentRaw.LastHeartbeatTs,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		agentRawList = append(agentRawList, &agentRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return agentRawList, nil
+}
+
+// patchAgentImpl updates an agent by ID. Returns the new state of the agent after update.
+func patchAgentImpl(ctx context.Context, tx *sql.Tx, patch *api.AgentPatch) (*agentRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.RowStatus; v != nil {
+		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.RowStatus(*v))
+	}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Version; v != nil {
+		set, args = append(set, fmt.Sprintf("version = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LastHeartbeatTs; v != nil {
+		set, args = append(set, fmt.Sprintf("last_heartbeat_ts = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	var agentRaw agentRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE agent
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, token_hash, version, last_heartbeat_ts
+	`, len(args)),
+		args...,
+	).Scan(
+		&agentRaw.ID,
+		&agentRaw.RowStatus,
+		&agentRaw.CreatorID,
+		&agentRaw.CreatedTs,
+		&agentRaw.UpdaterID,
+		&agentRaw.UpdatedTs,
+		&agentRaw.Name,
+		&agentRaw.TokenHash,
+		&agentRaw.Version,
+		&agentRaw.LastHeartbeatTs,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("agent ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &agentRaw, nil
+}
+
+// createAgentTaskImpl creates a new agent task.
+func createAgentTaskImpl(ctx context.Context, tx *sql.Tx, create *api.AgentTaskCreate) (*api.AgentTask, error) {
+	// Insert row into database.
+	query := `
+		INSERT INTO agent_task (
+			creator_id,
+			updater_id,
+			agent_id,
+			instance_id,
+			type,
+			status,
+			payload
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, agent_id, instance_id, type, status, payload, result
+	`
+	var agentTask api.AgentTask
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.AgentID,
+		create.InstanceID,
+		create.Type,
+		api.AgentTaskPending,
+		create.Payload,
+	).Scan(
+		&agentTask.ID,
+		&agentTask.CreatorID,
+		&agentTask.CreatedTs,
+		&agentTask.UpdaterID,
+		&agentTask.UpdatedTs,
+		&agentTask.AgentID,
+		&agentTask.InstanceID,
+		&agentTask.Type,
+		&agentTask.Status,
+		&agentTask.Payload,
+		&agentTask.Result,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &agentTask, nil
+}
+
+func findAgentTaskImpl(ctx context.Context, tx *sql.Tx, find *api.AgentTaskFind) ([]*api.AgentTask, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.AgentID; v != nil {
+		where, args = append(where, fmt.Sprintf("agent_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Type; v != nil {
+		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.StatusList; v != nil {
+		list := []string{}
+		for _, status := range *v {
+			list = append(list, fmt.Sprintf("$%d", len(args)+1))
+			args = append(args, status)
+		}
+		where = append(where, fmt.Sprintf("status in (%s)", strings.Join(list, ",")))
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			agent_id,
+			instance_id,
+			type,
+			status,
+			payload,
+			result
+		FROM agent_task
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into agentTaskList.
+	var agentTaskList []*api.AgentTask
+	for rows.Next() {
+		var agentTask api.AgentTask
+		if err := rows.Scan(
+			&agentTask.ID,
+			&agentTask.CreatorID,
+			&agentTask.CreatedTs,
+			&agentTask.UpdaterID,
+			&agentTask.UpdatedTs,
+			&agentTask.AgentID,
+			&agentTask.InstanceID,
+			&agentTask.Type,
+			&agentTask.Status,
+			&agentTask.Payload,
+			&agentTask.Result,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		agentTaskList = append(agentTaskList, &agentTask)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return agentTaskList, nil
+}
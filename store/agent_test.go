@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestAgentToken(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	agent, err := s.CreateAgent(ctx, &api.AgentCreate{
+		CreatorID: api.SystemBotID,
+		Name:      "runner",
+		Token:     "agent-token",
+	})
+	require.NoError(t, err)
+	// The token is only returned upon creation.
+	require.Equal(t, "agent-token", agent.Token)
+
+	// Only the hash of the token is stored.
+	var tokenHash string
+	require.NoError(t, s.db.db.QueryRowContext(ctx, "SELECT token_hash FROM agent WHERE id = $1", agent.ID).Scan(&tokenHash))
+	require.Equal(t, common.HashToken("agent-token"), tokenHash)
+
+	_, err = s.CreateAgent(ctx, &api.AgentCreate{
+		CreatorID: api.SystemBotID,
+		Name:      "runner",
+		Token:     "other-token",
+	})
+	require.Equal(t, common.Conflict, common.ErrorCode(err))
+
+	token, otherToken := "agent-token", "other-token"
+	normal := api.Normal
+	found, err := s.GetAgent(ctx, &api.AgentFind{RowStatus: &normal, Token: &token})
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	require.Equal(t, agent.ID, found.ID)
+	require.Empty(t, found.Token)
+	require.Equal(t, tokenHash, found.TokenHash)
+	found, err = s.GetAgent(ctx, &api.AgentFind{RowStatus: &normal, Token: &otherToken})
+	require.NoError(t, err)
+	require.Nil(t, found)
+	found, err = s.GetAgent(ctx, &api.AgentFind{RowStatus: &normal, Token: &tokenHash})
+	require.NoError(t, err)
+	require.Nil(t, found)
+
+	// Heartbeat.
+	version, ts := "1.2.0", int64(1654000000)
+	patched, err := s.PatchAgent(ctx, &api.AgentPatch{
+		ID:              agent.ID,
+		UpdaterID:       api.SystemBotID,
+		Version:         &version,
+		LastHeartbeatTs: &ts,
+	})
+	require.NoError(t, err)
+	require.Equal(t, version, patched.Version)
+	require.Equal(t, ts, patched.LastHeartbeatTs)
+	require.Empty(t, patched.Token)
+
+	// Revocation.
+	archived := string(api.Archived)
+	_, err = s.PatchAgent(ctx, &api.AgentPatch{
+		ID:        agent.ID,
+		UpdaterID: api.SystemBotID,
+		RowStatus: &archived,
+	})
+	require.NoError(t, err)
+	found, err = s.GetAgent(ctx, &api.AgentFind{RowStatus: &normal, Token: &token})
+	require.NoError(t, err)
+	require.Nil(t, found)
+}
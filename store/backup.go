@@ -364,7 +364,14 @@ func (s *Store) patchBackupRaw(ctx context.Context, patch *api.BackupPatch) (*ba
 
 // upsertBackupSettingRaw sets the backup settings for a database.
 func (s *Store) upsertBackupSettingRaw(ctx context.Context, upsert *api.BackupSettingUpsert) (*backupSettingRaw, error) {
-	backupPlanPolicy, err := s.GetBackupPlanPolicyByEnvID(ctx, upsert.EnvironmentID)
+	database, err := s.GetDatabase(ctx, &api.DatabaseFind{ID: &upsert.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	if database == nil {
+		return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("database ID not found: %d", upsert.DatabaseID)}
+	}
+	backupPlanPolicy, err := s.GetBackupPlanPolicyByEnvID(ctx, upsert.EnvironmentID, api.GetPolicyTagList(database.Instance, database))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,262 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// changeSetRaw is the store model for a ChangeSet.
+// Fields have exactly the same meanings as ChangeSet.
+type changeSetRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	PipelineID int
+
+	// Domain specific fields
+	Status  api.ChangeSetStatus
+	Payload string
+}
+
+// toChangeSet creates an instance of ChangeSet based on the changeSetRaw.
+// This is intended to be called when we need to compose a ChangeSet relationship.
+func (raw *changeSetRaw) toChangeSet() *api.ChangeSet {
+	return &api.ChangeSet{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		PipelineID: raw.PipelineID,
+
+		// Domain specific fields
+		Status:  raw.Status,
+		Payload: raw.Payload,
+	}
+}
+
+// CreateChangeSet creates an instance of ChangeSet.
+func (s *Store) CreateChangeSet(ctx context.Context, create *api.ChangeSetCreate) (*api.ChangeSet, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createChangeSetImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create change set with ChangeSetCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeChangeSet(ctx, raw)
+}
+
+// GetChangeSetByPipelineID gets the change set of a pipeline.
+// Returns nil if the pipeline isn't a change set.
+func (s *Store) GetChangeSetByPipelineID(ctx context.Context, pipelineID int) (*api.ChangeSet, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findChangeSetImpl(ctx, tx.PTx, &api.ChangeSetFind{PipelineID: &pipelineID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change set with pipeline ID %d, error: %w", pipelineID, err)
+	}
+	if len(rawList) == 0 {
+		return nil, nil
+	} else if len(rawList) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d change sets with pipeline ID %d, expect 1", len(rawList), pipelineID)}
+	}
+	return s.composeChangeSet(ctx, rawList[0])
+}
+
+// PatchChangeSet patches an instance of ChangeSet.
+func (s *Store) PatchChangeSet(ctx context.Context, patch *api.ChangeSetPatch) (*api.ChangeSet, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchChangeSetImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch change set with ChangeSetPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeChangeSet(ctx, raw)
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeChangeSet(ctx context.Context, raw *changeSetRaw) (*api.ChangeSet, error) {
+	changeSet := raw.toChangeSet()
+
+	creator, err := s.GetPrincipalByID(ctx, changeSet.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	changeSet.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, changeSet.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	changeSet.Updater = updater
+
+	return changeSet, nil
+}
+
+func createChangeSetImpl(ctx context.Context, tx *sql.Tx, create *api.ChangeSetCreate) (*changeSetRaw, error) {
+	query := `
+		INSERT INTO change_set (
+			creator_id,
+			updater_id,
+			pipeline_id
+		)
+		VALUES ($1, $2, $3)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, status, payload
+	`
+	var raw changeSetRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.PipelineID,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.PipelineID,
+		&raw.Status,
+		&raw.Payload,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findChangeSetImpl(ctx context.Context, tx *sql.Tx, find *api.ChangeSetFind) ([]*changeSetRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PipelineID; v != nil {
+		where, args = append(where, fmt.Sprintf("pipeline_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			pipeline_id,
+			status,
+			payload
+		FROM change_set
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*changeSetRaw
+	for rows.Next() {
+		var raw changeSetRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.PipelineID,
+			&raw.Status,
+			&raw.Payload,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+// patchChangeSetImpl updates a change set by ID. Returns the new state of the change set after update.
+func patchChangeSetImpl(ctx context.Context, tx *sql.Tx, patch *api.ChangeSetPatch) (*changeSetRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Status; v != nil {
+		set, args = append(set, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Payload; v != nil {
+		set, args = append(set, fmt.Sprintf("payload = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	var raw changeSetRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE change_set
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, status, payload
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.PipelineID,
+		&raw.Status,
+		&raw.Payload,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("unable to find change set ID to update: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
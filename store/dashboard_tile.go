@@ -0,0 +1,372 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// dashboardTileRaw is the store model for a DashboardTile.
+// Fields have exactly the same meanings as DashboardTile.
+type dashboardTileRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID  int
+	DatabaseID int
+
+	// Domain specific fields
+	Name        string
+	Statement   string
+	Parameters  string
+	Refresh     api.DashboardTileRefresh
+	Result      string
+	ResultError string
+	RefreshedTs int64
+}
+
+// toDashboardTile creates an instance of DashboardTile based on the dashboardTileRaw.
+// This is intended to be called when we need to compose a DashboardTile relationship.
+func (raw *dashboardTileRaw) toDashboardTile() *api.DashboardTile {
+	return &api.DashboardTile{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID:  raw.ProjectID,
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		Name:        raw.Name,
+		Statement:   raw.Statement,
+		Parameters:  raw.Parameters,
+		Refresh:     raw.Refresh,
+		Result:      raw.Result,
+		ResultError: raw.ResultError,
+		RefreshedTs: raw.RefreshedTs,
+	}
+}
+
+// CreateDashboardTile creates an instance of DashboardTile.
+func (s *Store) CreateDashboardTile(ctx context.Context, create *api.DashboardTileCreate) (*api.DashboardTile, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createDashboardTileImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dashboard tile with DashboardTileCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDashboardTile(ctx, raw)
+}
+
+// GetDashboardTileByID gets an instance of DashboardTile.
+func (s *Store) GetDashboardTileByID(ctx context.Context, id int) (*api.DashboardTile, error) {
+	list, err := s.FindDashboardTile(ctx, &api.DashboardTileFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d dashboard tiles with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// FindDashboardTile finds a list of DashboardTile instances in the ascending name order.
+func (s *Store) FindDashboardTile(ctx context.Context, find *api.DashboardTileFind) ([]*api.DashboardTile, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findDashboardTileImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dashboard tile list with DashboardTileFind[%+v], error: %w", find, err)
+	}
+	var tileList []*api.DashboardTile
+	for _, raw := range rawList {
+		tile, err := s.composeDashboardTile(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		tileList = append(tileList, tile)
+	}
+	return tileList, nil
+}
+
+// PatchDashboardTile patches an instance of DashboardTile.
+func (s *Store) PatchDashboardTile(ctx context.Context, patch *api.DashboardTilePatch) (*api.DashboardTile, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchDashboardTileImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch dashboard tile with DashboardTilePatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDashboardTile(ctx, raw)
+}
+
+// DeleteDashboardTile deletes an existing dashboard tile by ID.
+func (s *Store) DeleteDashboardTile(ctx context.Context, delete *api.DashboardTileDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM dashboard_tile WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeDashboardTile(ctx context.Context, raw *dashboardTileRaw) (*api.DashboardTile, error) {
+	tile := raw.toDashboardTile()
+
+	creator, err := s.GetPrincipalByID(ctx, tile.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	tile.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, tile.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	tile.Updater = updater
+
+	database, err := s.GetDatabase(ctx, &api.DatabaseFind{ID: &tile.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	tile.Database = database
+
+	return tile, nil
+}
+
+func createDashboardTileImpl(ctx context.Context, tx *sql.Tx, create *api.DashboardTileCreate) (*dashboardTileRaw, error) {
+	query := `
+		INSERT INTO dashboard_tile (
+			creator_id,
+			updater_id,
+			project_id,
+			database_id,
+			name,
+			statement,
+			parameters,
+			refresh
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, database_id, name, statement, parameters, refresh, result, result_error, refreshed_ts
+	`
+	parameters := create.Parameters
+	if parameters == "" {
+		parameters = "{}"
+	}
+	var raw dashboardTileRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.ProjectID,
+		create.DatabaseID,
+		create.Name,
+		create.Statement,
+		parameters,
+		create.Refresh,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.DatabaseID,
+		&raw.Name,
+		&raw.Statement,
+		&raw.Parameters,
+		&raw.Refresh,
+		&raw.Result,
+		&raw.ResultError,
+		&raw.RefreshedTs,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findDashboardTileImpl(ctx context.Context, tx *sql.Tx, find *api.DashboardTileFind) ([]*dashboardTileRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if find.Scheduled {
+		where, args = append(where, fmt.Sprintf("refresh <> $%d", len(args)+1)), append(args, api.DashboardTileRefreshOnView)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			database_id,
+			name,
+			statement,
+			parameters,
+			refresh,
+			result,
+			result_error,
+			refreshed_ts
+		FROM dashboard_tile
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*dashboardTileRaw
+	for rows.Next() {
+		var raw dashboardTileRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.ProjectID,
+			&raw.DatabaseID,
+			&raw.Name,
+			&raw.Statement,
+			&raw.Parameters,
+			&raw.Refresh,
+			&raw.Result,
+			&raw.ResultError,
+			&raw.RefreshedTs,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchDashboardTileImpl(ctx context.Context, tx *sql.Tx, patch *api.DashboardTilePatch) (*dashboardTileRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.DatabaseID; v != nil {
+		set, args = append(set, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Statement; v != nil {
+		set, args = append(set, fmt.Sprintf("statement = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Parameters; v != nil {
+		set, args = append(set, fmt.Sprintf("parameters = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Refresh; v != nil {
+		set, args = append(set, fmt.Sprintf("refresh = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Result; v != nil {
+		set, args = append(set, fmt.Sprintf("result = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ResultError; v != nil {
+		set, args = append(set, fmt.Sprintf("result_error = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.RefreshedTs; v != nil {
+		set, args = append(set, fmt.Sprintf("refreshed_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	var raw dashboardTileRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE dashboard_tile
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, database_id, name, statement, parameters, refresh, result, result_error, refreshed_ts
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.DatabaseID,
+		&raw.Name,
+		&raw.Statement,
+		&raw.Parameters,
+		&raw.Refresh,
+		&raw.Result,
+		&raw.ResultError,
+		&raw.RefreshedTs,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("dashboard tile not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
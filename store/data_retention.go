@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+var (
+	// principalReassignQueryList is the list of the queries moving the ownership references of the erased principal to the successor.
+	// The queries take the erased principal ID and the successor ID as the arguments, and are executed in order.
+	principalReassignQueryList = []string{
+		`UPDATE issue SET assignee_id = $2 WHERE assignee_id = $1 AND status = 'OPEN'`,
+		`UPDATE recurring_issue SET assignee_id = $2 WHERE assignee_id = $1`,
+		`UPDATE partition_policy SET assignee_id = $2 WHERE assignee_id = $1`,
+		`UPDATE db_role_mapping SET principal_id = $2 WHERE principal_id = $1`,
+		`UPDATE sheet SET creator_id = $2 WHERE creator_id = $1`,
+		// The project roles are moved unless the successor is already a member of the project, the rest is deleted below.
+		`UPDATE project_member SET principal_id = $2
+			WHERE principal_id = $1
+			AND project_id NOT IN (SELECT project_id FROM project_member WHERE principal_id = $2)`,
+	}
+	// principalDeleteQueryList is the list of the queries deleting the personal data of the erased principal.
+	// The queries take the erased principal ID as the only argument, and are executed in order.
+	principalDeleteQueryList = []string{
+		`DELETE FROM project_member WHERE principal_id = $1`,
+		`DELETE FROM issue_subscriber WHERE subscriber_id = $1`,
+		`DELETE FROM sheet_organizer WHERE principal_id = $1`,
+		`DELETE FROM bookmark WHERE creator_id = $1`,
+		`DELETE FROM inbox WHERE receiver_id = $1`,
+		// The statements in the query history are personal data as well.
+		`DELETE FROM inbox WHERE activity_id IN (SELECT id FROM activity WHERE creator_id = $1 AND type = 'bb.sql-editor.query')`,
+		`DELETE FROM activity WHERE creator_id = $1 AND type = 'bb.sql-editor.query'`,
+	}
+)
+
+// PurgeActivity deletes the activities created before the time and their inbox messages, it returns the number of deleted activities.
+// The activities created by the members on legal hold are kept.
+func (s *Store) PurgeActivity(ctx context.Context, purge *api.ActivityPurge) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	where, args := []string{"created_ts < $1", "creator_id NOT IN (SELECT principal_id FROM member WHERE legal_hold)"}, []interface{}{purge.CreatedBeforeTs}
+	if v := purge.TypeList; len(v) > 0 {
+		where, args = append(where, fmt.Sprintf("type = ANY($%d)", len(args)+1)), append(args, activityTypeListToStringList(v))
+	}
+	if v := purge.ExcludeTypeList; len(v) > 0 {
+		where, args = append(where, fmt.Sprintf("type <> ALL($%d)", len(args)+1)), append(args, activityTypeListToStringList(v))
+	}
+	activityQuery := `SELECT id FROM activity WHERE ` + strings.Join(where, " AND ")
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM inbox WHERE activity_id IN (`+activityQuery+`)`, args...); err != nil {
+		return 0, FormatError(err)
+	}
+	result, err := tx.PTx.ExecContext(ctx, `DELETE FROM activity WHERE id IN (`+activityQuery+`)`, args...)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	deletedCount, err := result.RowsAffected()
+	if err != nil {
+		return 0, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return 0, FormatError(err)
+	}
+	return deletedCount, nil
+}
+
+// ErasePrincipal erases the personal data of a departed user.
+// The ownership references such as the open issues and the project roles are moved to the successor, the personal data
+// such as the inbox and the query history is deleted, and the name and the email are anonymized in the principal and
+// the member activities. The principal row is kept since the history such as the issues and the migrations references it.
+// Returns EINVALID if the user isn't deactivated or is on legal hold.
+func (s *Store) ErasePrincipal(ctx context.Context, erase *api.PrincipalErase) (*api.PrincipalEraseResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	var rowStatus api.RowStatus
+	var legalHold bool
+	var oldName, oldEmail string
+	if err := tx.PTx.QueryRowContext(ctx, `
+		SELECT member.row_status, member.legal_hold, principal.name, principal.email
+		FROM member, principal
+		WHERE member.principal_id = principal.id AND member.principal_id = $1`,
+		erase.ID,
+	).Scan(&rowStatus, &legalHold, &oldName, &oldEmail); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("member not found with principal ID %d", erase.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	if rowStatus != api.Archived {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("principal ID %d must be deactivated before the erasure", erase.ID)}
+	}
+	if legalHold {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("principal ID %d is on legal hold", erase.ID)}
+	}
+
+	result := &api.PrincipalEraseResult{
+		ID:          erase.ID,
+		SuccessorID: erase.SuccessorID,
+	}
+	for _, query := range principalReassignQueryList {
+		res, err := tx.PTx.ExecContext(ctx, query, erase.ID, erase.SuccessorID)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		result.ReassignedCount += count
+	}
+	for _, query := range principalDeleteQueryList {
+		res, err := tx.PTx.ExecContext(ctx, query, erase.ID)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		result.DeletedCount += count
+	}
+
+	// The email must stay unique, so it's derived from the principal ID.
+	name := fmt.Sprintf("Erased User %d", erase.ID)
+	email := fmt.Sprintf("erased-%d@erased.bytebase.com", erase.ID)
+	if _, err := tx.PTx.ExecContext(ctx, `
+		UPDATE activity
+		SET payload = payload || jsonb_build_object('principalName', $2::TEXT, 'principalEmail', $3::TEXT)
+		WHERE type LIKE 'bb.member.%' AND payload->>'principalId' = $1::TEXT`,
+		erase.ID, name, email,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	// The project member activities mention the user as "name (email)" in the comment.
+	if _, err := tx.PTx.ExecContext(ctx, `
+		UPDATE activity
+		SET comment = replace(comment, $1, $2)
+		WHERE type LIKE 'bb.project.member.%' AND position($1 in comment) > 0`,
+		fmt.Sprintf("%s (%s)", oldName, oldEmail), fmt.Sprintf("%s (%s)", name, email),
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	passwordHash := ""
+	principal, err := patchPrincipalImpl(ctx, tx.PTx, &api.PrincipalPatch{
+		ID:           erase.ID,
+		UpdaterID:    erase.UpdaterID,
+		Name:         &name,
+		Email:        &email,
+		PasswordHash: &passwordHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := s.cache.UpsertCache(api.PrincipalCache, principal.ID, principal); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func activityTypeListToStringList(typeList []api.ActivityType) []string {
+	var list []string
+	for _, t := range typeList {
+		list = append(list, string(t))
+	}
+	return list
+}
@@ -0,0 +1,380 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// dataSourceRotationRaw is the store model for a DataSourceRotation.
+// Fields have exactly the same meanings as DataSourceRotation.
+type dataSourceRotationRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DataSourceID int
+
+	// Domain specific fields
+	Status   api.DataSourceRotationStatus
+	Username string
+	Password string
+	SslCa    string
+	SslCert  string
+	SslKey   string
+	Error    string
+}
+
+// toDataSourceRotation creates an instance of DataSourceRotation based on the dataSourceRotationRaw.
+// This is intended to be called when we need to compose a DataSourceRotation relationship.
+func (raw *dataSourceRotationRaw) toDataSourceRotation() *api.DataSourceRotation {
+	return &api.DataSourceRotation{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DataSourceID: raw.DataSourceID,
+
+		// Domain specific fields
+		Status:   raw.Status,
+		Username: raw.Username,
+		Password: raw.Password,
+		SslCa:    raw.SslCa,
+		SslCert:  raw.SslCert,
+		SslKey:   raw.SslKey,
+		Error:    raw.Error,
+	}
+}
+
+// CreateDataSourceRotation creates an instance of DataSourceRotation.
+func (s *Store) CreateDataSourceRotation(ctx context.Context, create *api.DataSourceRotationCreate) (*api.DataSourceRotation, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createDataSourceRotationImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data source rotation with DataSourceRotationCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDataSourceRotation(ctx, raw)
+}
+
+// GetDataSourceRotation gets an instance of DataSourceRotation.
+func (s *Store) GetDataSourceRotation(ctx context.Context, find *api.DataSourceRotationFind) (*api.DataSourceRotation, error) {
+	list, err := s.FindDataSourceRotation(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d data source rotations with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// FindDataSourceRotation finds a list of DataSourceRotation instances.
+func (s *Store) FindDataSourceRotation(ctx context.Context, find *api.DataSourceRotationFind) ([]*api.DataSourceRotation, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findDataSourceRotationImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data source rotation list with DataSourceRotationFind[%+v], error: %w", find, err)
+	}
+	var rotationList []*api.DataSourceRotation
+	for _, raw := range rawList {
+		rotation, err := s.composeDataSourceRotation(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		rotationList = append(rotationList, rotation)
+	}
+	return rotationList, nil
+}
+
+// PatchDataSourceRotationStatus patches the status of a DataSourceRotation.
+func (s *Store) PatchDataSourceRotationStatus(ctx context.Context, patch *api.DataSourceRotationStatusPatch) (*api.DataSourceRotation, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchDataSourceRotationStatusImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch data source rotation with DataSourceRotationStatusPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDataSourceRotation(ctx, raw)
+}
+
+// CommitDataSourceRotation switches the data source to the credential staged in the rotation.
+// The data source and the rotation are updated in a single transaction, and the other rotations
+// staged on the same data source are canceled.
+func (s *Store) CommitDataSourceRotation(ctx context.Context, rotation *api.DataSourceRotation, updaterID int) (*api.DataSourceRotation, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	// Lock the data source first, so that the concurrent commits of the rotations staged on the same data source are serialized
+	// rather than deadlocked by canceling each other, then lock the rotation so that it switches the credential only once.
+	if _, err := tx.PTx.ExecContext(ctx, `
+		SELECT id FROM data_source WHERE id = $1 FOR UPDATE
+	`, rotation.DataSourceID); err != nil {
+		return nil, FormatError(err)
+	}
+	var status api.DataSourceRotationStatus
+	if err := tx.PTx.QueryRowContext(ctx, `
+		SELECT status FROM data_source_rotation WHERE id = $1 FOR UPDATE
+	`, rotation.ID).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("data source rotation not found with ID %d", rotation.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	if status != api.DataSourceRotationValidated {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("data source rotation %d is %s, expect %s", rotation.ID, status, api.DataSourceRotationValidated)}
+	}
+
+	if _, err := s.patchDataSourceImpl(ctx, tx.PTx, &api.DataSourcePatch{
+		ID:        rotation.DataSourceID,
+		UpdaterID: updaterID,
+		Username:  &rotation.Username,
+		Password:  &rotation.Password,
+		SslCa:     &rotation.SslCa,
+		SslCert:   &rotation.SslCert,
+		SslKey:    &rotation.SslKey,
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.PTx.ExecContext(ctx, `
+		UPDATE data_source_rotation
+		SET updater_id = $1, status = $2
+		WHERE data_source_id = $3 AND status = $4 AND id != $5
+	`, updaterID, api.DataSourceRotationCanceled, rotation.DataSourceID, api.DataSourceRotationValidated, rotation.ID); err != nil {
+		return nil, FormatError(err)
+	}
+
+	raw, err := patchDataSourceRotationStatusImpl(ctx, tx.PTx, &api.DataSourceRotationStatusPatch{
+		ID:        rotation.ID,
+		UpdaterID: updaterID,
+		Status:    api.DataSourceRotationCommitted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDataSourceRotation(ctx, raw)
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeDataSourceRotation(ctx context.Context, raw *dataSourceRotationRaw) (*api.DataSourceRotation, error) {
+	rotation := raw.toDataSourceRotation()
+
+	creator, err := s.GetPrincipalByID(ctx, rotation.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	rotation.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, rotation.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	rotation.Updater = updater
+
+	return rotation, nil
+}
+
+func createDataSourceRotationImpl(ctx context.Context, tx *sql.Tx, create *api.DataSourceRotationCreate) (*dataSourceRotationRaw, error) {
+	query := `
+		INSERT INTO data_source_rotation (
+			creator_id,
+			updater_id,
+			data_source_id,
+			status,
+			username,
+			password,
+			ssl_key,
+			ssl_cert,
+			ssl_ca,
+			error
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, data_source_id, status, username, password, ssl_key, ssl_cert, ssl_ca, error
+	`
+	var raw dataSourceRotationRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.DataSourceID,
+		create.Status,
+		create.Username,
+		create.Password,
+		create.SslKey,
+		create.SslCert,
+		create.SslCa,
+		create.Error,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.DataSourceID,
+		&raw.Status,
+		&raw.Username,
+		&raw.Password,
+		&raw.SslKey,
+		&raw.SslCert,
+		&raw.SslCa,
+		&raw.Error,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findDataSourceRotationImpl(ctx context.Context, tx *sql.Tx, find *api.DataSourceRotationFind) ([]*dataSourceRotationRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DataSourceID; v != nil {
+		where, args = append(where, fmt.Sprintf("data_source_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			data_source_id,
+			status,
+			username,
+			password,
+			ssl_key,
+			ssl_cert,
+			ssl_ca,
+			error
+		FROM data_source_rotation
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*dataSourceRotationRaw
+	for rows.Next() {
+		var raw dataSourceRotationRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.DataSourceID,
+			&raw.Status,
+			&raw.Username,
+			&raw.Password,
+			&raw.SslKey,
+			&raw.SslCert,
+			&raw.SslCa,
+			&raw.Error,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchDataSourceRotationStatusImpl(ctx context.Context, tx *sql.Tx, patch *api.DataSourceRotationStatusPatch) (*dataSourceRotationRaw, error) {
+	var raw dataSourceRotationRaw
+	if err := tx.QueryRowContext(ctx, `
+		UPDATE data_source_rotation
+		SET updater_id = $1, status = $2
+		WHERE id = $3
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, data_source_id, status, username, password, ssl_key, ssl_cert, ssl_ca, error
+	`,
+		patch.UpdaterID,
+		patch.Status,
+		patch.ID,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.DataSourceID,
+		&raw.Status,
+		&raw.Username,
+		&raw.Password,
+		&raw.SslKey,
+		&raw.SslCert,
+		&raw.SslCa,
+		&raw.Error,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("data source rotation not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestCommitDataSourceRotation(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	var dataSourceID int
+	require.NoError(t, s.db.db.QueryRowContext(ctx, "SELECT id FROM data_source ORDER BY id LIMIT 1").Scan(&dataSourceID))
+	before, err := s.GetDataSource(ctx, &api.DataSourceFind{ID: &dataSourceID})
+	require.NoError(t, err)
+	require.NotNil(t, before)
+
+	failed := createTestDataSourceRotation(ctx, t, s, dataSourceID, "failed", api.DataSourceRotationFailed)
+	committed := createTestDataSourceRotation(ctx, t, s, dataSourceID, "committed", api.DataSourceRotationValidated)
+	canceled := createTestDataSourceRotation(ctx, t, s, dataSourceID, "canceled", api.DataSourceRotationValidated)
+
+	// Only the validated rotation can be committed.
+	_, err = s.CommitDataSourceRotation(ctx, failed, api.SystemBotID)
+	require.Equal(t, common.Conflict, common.ErrorCode(err))
+	requireDataSourceUsername(ctx, t, s, dataSourceID, before.Username)
+
+	rotation, err := s.CommitDataSourceRotation(ctx, committed, api.SystemBotID)
+	require.NoError(t, err)
+	require.Equal(t, api.DataSourceRotationCommitted, rotation.Status)
+	requireDataSourceUsername(ctx, t, s, dataSourceID, "committed")
+	requireDataSourceRotationStatus(ctx, t, s, canceled.ID, api.DataSourceRotationCanceled)
+	requireDataSourceRotationStatus(ctx, t, s, failed.ID, api.DataSourceRotationFailed)
+
+	// Neither the committed nor the canceled rotation can be committed again.
+	_, err = s.CommitDataSourceRotation(ctx, committed, api.SystemBotID)
+	require.Equal(t, common.Conflict, common.ErrorCode(err))
+	_, err = s.CommitDataSourceRotation(ctx, canceled, api.SystemBotID)
+	require.Equal(t, common.Conflict, common.ErrorCode(err))
+	requireDataSourceUsername(ctx, t, s, dataSourceID, "committed")
+}
+
+func TestCommitDataSourceRotationRollback(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	var dataSourceID int
+	require.NoError(t, s.db.db.QueryRowContext(ctx, "SELECT id FROM data_source ORDER BY id LIMIT 1").Scan(&dataSourceID))
+	before, err := s.GetDataSource(ctx, &api.DataSourceFind{ID: &dataSourceID})
+	require.NoError(t, err)
+	require.NotNil(t, before)
+
+	committed := createTestDataSourceRotation(ctx, t, s, dataSourceID, "committed", api.DataSourceRotationValidated)
+	other := createTestDataSourceRotation(ctx, t, s, dataSourceID, "other", api.DataSourceRotationValidated)
+
+	// Fail the last step of the commit, after the data source is switched and the other rotation is canceled.
+	_, err = s.db.db.ExecContext(ctx, `
+		CREATE FUNCTION test_fail_data_source_rotation_commit() RETURNS TRIGGER AS $$
+		BEGIN
+			RAISE EXCEPTION 'commit failed';
+		END;
+		$$ LANGUAGE plpgsql;
+		CREATE TRIGGER test_fail_data_source_rotation_commit
+		BEFORE UPDATE ON data_source_rotation
+		FOR EACH ROW WHEN (NEW.status = 'COMMITTED')
+		EXECUTE FUNCTION test_fail_data_source_rotation_commit();
+	`)
+	require.NoError(t, err)
+
+	_, err = s.CommitDataSourceRotation(ctx, committed, api.SystemBotID)
+	require.Error(t, err)
+	requireDataSourceUsername(ctx, t, s, dataSourceID, before.Username)
+	requireDataSourceRotationStatus(ctx, t, s, committed.ID, api.DataSourceRotationValidated)
+	requireDataSourceRotationStatus(ctx, t, s, other.ID, api.DataSourceRotationValidated)
+}
+
+func TestCommitDataSourceRotationRace(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	var dataSourceID int
+	require.NoError(t, s.db.db.QueryRowContext(ctx, "SELECT id FROM data_source ORDER BY id LIMIT 1").Scan(&dataSourceID))
+
+	tests := []struct {
+		name       string
+		usernameA  string
+		usernameB  string
+		sameCommit bool
+	}{
+		{
+			name:      "two rotations",
+			usernameA: "rotation-a",
+			usernameB: "rotation-b",
+		},
+		{
+			name:       "same rotation",
+			usernameA:  "rotation-c",
+			sameCommit: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := createTestDataSourceRotation(ctx, t, s, dataSourceID, test.usernameA, api.DataSourceRotationValidated)
+			b := a
+			if !test.sameCommit {
+				b = createTestDataSourceRotation(ctx, t, s, dataSourceID, test.usernameB, api.DataSourceRotationValidated)
+			}
+
+			var wg sync.WaitGroup
+			results := make([]*api.DataSourceRotation, 2)
+			errs := make([]error, 2)
+			for i, rotation := range []*api.DataSourceRotation{a, b} {
+				wg.Add(1)
+				go func(i int, rotation *api.DataSourceRotation) {
+					defer wg.Done()
+					results[i], errs[i] = s.CommitDataSourceRotation(ctx, rotation, api.SystemBotID)
+				}(i, rotation)
+			}
+			wg.Wait()
+
+			// Exactly one commit wins, and the other one sees the rotation is no longer validated.
+			winner := -1
+			for i, err := range errs {
+				if err == nil {
+					require.Equal(t, -1, winner, "both commits succeeded")
+					winner = i
+					continue
+				}
+				require.Equal(t, common.Conflict, common.ErrorCode(err), err)
+			}
+			require.NotEqual(t, -1, winner, "no commit succeeded")
+			requireDataSourceUsername(ctx, t, s, dataSourceID, results[winner].Username)
+			requireDataSourceRotationStatus(ctx, t, s, results[winner].ID, api.DataSourceRotationCommitted)
+			if !test.sameCommit {
+				loser := []*api.DataSourceRotation{a, b}[1-winner]
+				requireDataSourceRotationStatus(ctx, t, s, loser.ID, api.DataSourceRotationCanceled)
+			}
+		})
+	}
+}
+
+func createTestDataSourceRotation(ctx context.Context, t *testing.T, s *Store, dataSourceID int, username string, status api.DataSourceRotationStatus) *api.DataSourceRotation {
+	rotation, err := s.CreateDataSourceRotation(ctx, &api.DataSourceRotationCreate{
+		CreatorID:    api.SystemBotID,
+		DataSourceID: dataSourceID,
+		Username:     username,
+		Password:     username + "-password",
+		Status:       status,
+	})
+	require.NoError(t, err)
+	return rotation
+}
+
+func requireDataSourceUsername(ctx context.Context, t *testing.T, s *Store, dataSourceID int, username string) {
+	dataSource, err := s.GetDataSource(ctx, &api.DataSourceFind{ID: &dataSourceID})
+	require.NoError(t, err)
+	require.NotNil(t, dataSource)
+	require.Equal(t, username, dataSource.Username)
+}
+
+func requireDataSourceRotationStatus(ctx context.Context, t *testing.T, s *Store, id int, status api.DataSourceRotationStatus) {
+	rotation, err := s.GetDataSourceRotation(ctx, &api.DataSourceRotationFind{ID: &id})
+	require.NoError(t, err)
+	require.NotNil(t, rotation)
+	require.Equal(t, status, rotation.Status)
+}
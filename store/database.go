@@ -36,6 +36,9 @@ type databaseRaw struct {
 	SchemaVersion        string
 	SyncStatus           api.SyncStatus
 	LastSuccessfulSyncTs int64
+	RowStatus            api.RowStatus
+	NotFoundTs           int64
+	TagList              string
 }
 
 // toDatabase creates an instance of Database based on the databaseRaw.
@@ -62,6 +65,9 @@ func (raw *databaseRaw) toDatabase() *api.Database {
 		SchemaVersion:        raw.SchemaVersion,
 		SyncStatus:           raw.SyncStatus,
 		LastSuccessfulSyncTs: raw.LastSuccessfulSyncTs,
+		RowStatus:            raw.RowStatus,
+		NotFoundTs:           raw.NotFoundTs,
+		TagList:              raw.TagList,
 	}
 }
 
@@ -306,7 +312,12 @@ func (s *Store) createDatabaseRaw(ctx context.Context, create *api.DatabaseCreat
 
 // createDatabaseRawTx creates a database with a transaction.
 func (s *Store) createDatabaseRawTx(ctx context.Context, tx *sql.Tx, create *api.DatabaseCreate) (*databaseRaw, error) {
-	backupPlanPolicy, err := s.GetBackupPlanPolicyByEnvID(ctx, create.EnvironmentID)
+	// The new database only has the tags inherited from the instance.
+	instance, err := s.GetInstanceByID(ctx, create.InstanceID)
+	if err != nil {
+		return nil, err
+	}
+	backupPlanPolicy, err := s.GetBackupPlanPolicyByEnvID(ctx, create.EnvironmentID, api.GetPolicyTagList(instance, nil))
 	if err != nil {
 		return nil, err
 	}
@@ -461,7 +472,10 @@ func (*Store) createDatabaseImpl(ctx context.Context, tx *sql.Tx, create *api.Da
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			row_status,
+			not_found_ts,
+			schema_version,
+			tag_list
 	`
 	var databaseRaw databaseRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -486,7 +500,10 @@ func (*Store) createDatabaseImpl(ctx context.Context, tx *sql.Tx, create *api.Da
 		&databaseRaw.Collation,
 		&databaseRaw.SyncStatus,
 		&databaseRaw.LastSuccessfulSyncTs,
+		&databaseRaw.RowStatus,
+		&databaseRaw.NotFoundTs,
 		&databaseRaw.SchemaVersion,
+		&databaseRaw.TagList,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -514,6 +531,9 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 	if v := find.SyncStatus; v != nil {
 		where, args = append(where, fmt.Sprintf("sync_status = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
+	}
 	if !find.IncludeAllDatabase {
 		where = append(where, "name != '"+api.AllDatabaseName+"'")
 	}
@@ -533,7 +553,10 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			row_status,
+			not_found_ts,
+			schema_version,
+			tag_list
 		FROM db
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -562,7 +585,10 @@ func (*Store) findDatabaseImpl(ctx context.Context, tx *sql.Tx, find *api.Databa
 			&databaseRaw.Collation,
 			&databaseRaw.SyncStatus,
 			&databaseRaw.LastSuccessfulSyncTs,
+			&databaseRaw.RowStatus,
+			&databaseRaw.NotFoundTs,
 			&databaseRaw.SchemaVersion,
+			&databaseRaw.TagList,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -589,6 +615,9 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 	if v := patch.SourceBackupID; v != nil {
 		set, args = append(set, fmt.Sprintf("source_backup_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := patch.SchemaVersion; v != nil {
 		set, args = append(set, fmt.Sprintf("schema_version = $%d", len(args)+1)), append(args, *v)
 	}
@@ -598,6 +627,15 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 	if v := patch.LastSuccessfulSyncTs; v != nil {
 		set, args = append(set, fmt.Sprintf("last_successful_sync_ts = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.RowStatus; v != nil {
+		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.NotFoundTs; v != nil {
+		set, args = append(set, fmt.Sprintf("not_found_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.TagList; v != nil {
+		set, args = append(set, fmt.Sprintf("tag_list = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -622,7 +660,10 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			row_status,
+			not_found_ts,
+			schema_version,
+			tag_list
 	`, len(args)),
 		args...,
 	).Scan(
@@ -639,7 +680,10 @@ func (*Store) patchDatabaseImpl(ctx context.Context, tx *sql.Tx, patch *api.Data
 		&databaseRaw.Collation,
 		&databaseRaw.SyncStatus,
 		&databaseRaw.LastSuccessfulSyncTs,
+		&databaseRaw.RowStatus,
+		&databaseRaw.NotFoundTs,
 		&databaseRaw.SchemaVersion,
+		&databaseRaw.TagList,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("database ID not found: %d", patch.ID)}
@@ -0,0 +1,363 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// databaseTemplateRaw is the store model for a DatabaseTemplate.
+// Fields have exactly the same meanings as DatabaseTemplate.
+type databaseTemplateRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Domain specific fields
+	Name          string
+	Engine        db.Type
+	Description   string
+	Statement     string
+	Labels        string
+	ExtensionList []string
+}
+
+// toDatabaseTemplate creates an instance of DatabaseTemplate based on the databaseTemplateRaw.
+// This is intended to be called when we need to compose a DatabaseTemplate relationship.
+func (raw *databaseTemplateRaw) toDatabaseTemplate() *api.DatabaseTemplate {
+	return &api.DatabaseTemplate{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Domain specific fields
+		Name:          raw.Name,
+		Engine:        raw.Engine,
+		Description:   raw.Description,
+		Statement:     raw.Statement,
+		Labels:        raw.Labels,
+		ExtensionList: raw.ExtensionList,
+	}
+}
+
+// CreateDatabaseTemplate creates an instance of DatabaseTemplate.
+func (s *Store) CreateDatabaseTemplate(ctx context.Context, create *api.DatabaseTemplateCreate) (*api.DatabaseTemplate, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createDatabaseTemplateImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database template with DatabaseTemplateCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDatabaseTemplate(ctx, raw)
+}
+
+// GetDatabaseTemplateByID gets an instance of DatabaseTemplate.
+func (s *Store) GetDatabaseTemplateByID(ctx context.Context, id int) (*api.DatabaseTemplate, error) {
+	list, err := s.FindDatabaseTemplate(ctx, &api.DatabaseTemplateFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d database templates with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// FindDatabaseTemplate finds a list of DatabaseTemplate instances in the ascending name order.
+func (s *Store) FindDatabaseTemplate(ctx context.Context, find *api.DatabaseTemplateFind) ([]*api.DatabaseTemplate, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findDatabaseTemplateImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find database template list with DatabaseTemplateFind[%+v], error: %w", find, err)
+	}
+	var templateList []*api.DatabaseTemplate
+	for _, raw := range rawList {
+		template, err := s.composeDatabaseTemplate(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		templateList = append(templateList, template)
+	}
+	return templateList, nil
+}
+
+// PatchDatabaseTemplate patches an instance of DatabaseTemplate.
+func (s *Store) PatchDatabaseTemplate(ctx context.Context, patch *api.DatabaseTemplatePatch) (*api.DatabaseTemplate, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchDatabaseTemplateImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch database template with DatabaseTemplatePatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDatabaseTemplate(ctx, raw)
+}
+
+// DeleteDatabaseTemplate deletes an existing database template by ID.
+// The databases created from the template are not affected since the template is applied upon creation.
+func (s *Store) DeleteDatabaseTemplate(ctx context.Context, delete *api.DatabaseTemplateDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM database_template WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeDatabaseTemplate(ctx context.Context, raw *databaseTemplateRaw) (*api.DatabaseTemplate, error) {
+	template := raw.toDatabaseTemplate()
+
+	creator, err := s.GetPrincipalByID(ctx, template.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	template.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, template.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	template.Updater = updater
+
+	return template, nil
+}
+
+func createDatabaseTemplateImpl(ctx context.Context, tx *sql.Tx, create *api.DatabaseTemplateCreate) (*databaseTemplateRaw, error) {
+	extensionList := create.ExtensionList
+	if extensionList == nil {
+		extensionList = []string{}
+	}
+	labels := create.Labels
+	if labels == "" {
+		labels = "[]"
+	}
+	query := `
+		INSERT INTO database_template (
+			creator_id,
+			updater_id,
+			name,
+			engine,
+			description,
+			statement,
+			labels,
+			extension_list
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, engine, description, statement, labels, extension_list
+	`
+	var raw databaseTemplateRaw
+	var txtArray pgtype.TextArray
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.Name,
+		create.Engine,
+		create.Description,
+		create.Statement,
+		labels,
+		extensionList,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.Name,
+		&raw.Engine,
+		&raw.Description,
+		&raw.Statement,
+		&raw.Labels,
+		&txtArray,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	if err := txtArray.AssignTo(&raw.ExtensionList); err != nil {
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findDatabaseTemplateImpl(ctx context.Context, tx *sql.Tx, find *api.DatabaseTemplateFind) ([]*databaseTemplateRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Engine; v != nil {
+		where, args = append(where, fmt.Sprintf("engine = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			name,
+			engine,
+			description,
+			statement,
+			labels,
+			extension_list
+		FROM database_template
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*databaseTemplateRaw
+	for rows.Next() {
+		var raw databaseTemplateRaw
+		var txtArray pgtype.TextArray
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.Name,
+			&raw.Engine,
+			&raw.Description,
+			&raw.Statement,
+			&raw.Labels,
+			&txtArray,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if err := txtArray.AssignTo(&raw.ExtensionList); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchDatabaseTemplateImpl(ctx context.Context, tx *sql.Tx, patch *api.DatabaseTemplatePatch) (*databaseTemplateRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Description; v != nil {
+		set, args = append(set, fmt.Sprintf("description = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Statement; v != nil {
+		set, args = append(set, fmt.Sprintf("statement = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Labels; v != nil {
+		labels := *v
+		if labels == "" {
+			labels = "[]"
+		}
+		set, args = append(set, fmt.Sprintf("labels = $%d", len(args)+1)), append(args, labels)
+	}
+	if v := patch.ExtensionList; v != nil {
+		extensionList := []string{}
+		if *v != "" {
+			extensionList = strings.Split(*v, ",")
+		}
+		set, args = append(set, fmt.Sprintf("extension_list = $%d", len(args)+1)), append(args, extensionList)
+	}
+	args = append(args, patch.ID)
+
+	var raw databaseTemplateRaw
+	var txtArray pgtype.TextArray
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE database_template
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, engine, description, statement, labels, extension_list
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.Name,
+		&raw.Engine,
+		&raw.Description,
+		&raw.Statement,
+		&raw.Labels,
+		&txtArray,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("database template not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	if err := txtArray.AssignTo(&raw.ExtensionList); err != nil {
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
@@ -0,0 +1,318 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// databaseAssignmentRuleRaw is the store model for a DatabaseAssignmentRule.
+// Fields have exactly the same meanings as DatabaseAssignmentRule.
+type databaseAssignmentRuleRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID  int
+	InstanceID *int
+
+	// Domain specific fields
+	NamePattern   string
+	LabelSelector string
+}
+
+// toDatabaseAssignmentRule creates an instance of DatabaseAssignmentRule based on the databaseAssignmentRuleRaw.
+// This is intended to be called when we need to compose a DatabaseAssignmentRule relationship.
+func (raw *databaseAssignmentRuleRaw) toDatabaseAssignmentRule() *api.DatabaseAssignmentRule {
+	return &api.DatabaseAssignmentRule{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID:  raw.ProjectID,
+		InstanceID: raw.InstanceID,
+
+		// Domain specific fields
+		NamePattern:   raw.NamePattern,
+		LabelSelector: raw.LabelSelector,
+	}
+}
+
+// CreateDatabaseAssignmentRule creates an instance of DatabaseAssignmentRule.
+func (s *Store) CreateDatabaseAssignmentRule(ctx context.Context, create *api.DatabaseAssignmentRuleCreate) (*api.DatabaseAssignmentRule, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createDatabaseAssignmentRuleImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database assignment rule with DatabaseAssignmentRuleCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDatabaseAssignmentRule(ctx, raw)
+}
+
+// GetDatabaseAssignmentRuleByID gets an instance of DatabaseAssignmentRule.
+func (s *Store) GetDatabaseAssignmentRuleByID(ctx context.Context, id int) (*api.DatabaseAssignmentRule, error) {
+	list, err := s.FindDatabaseAssignmentRule(ctx, &api.DatabaseAssignmentRuleFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d database assignment rules with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// FindDatabaseAssignmentRule finds a list of DatabaseAssignmentRule instances in the ascending ID order.
+func (s *Store) FindDatabaseAssignmentRule(ctx context.Context, find *api.DatabaseAssignmentRuleFind) ([]*api.DatabaseAssignmentRule, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findDatabaseAssignmentRuleImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find database assignment rule list with DatabaseAssignmentRuleFind[%+v], error: %w", find, err)
+	}
+	var ruleList []*api.DatabaseAssignmentRule
+	for _, raw := range rawList {
+		rule, err := s.composeDatabaseAssignmentRule(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		ruleList = append(ruleList, rule)
+	}
+	return ruleList, nil
+}
+
+// PatchDatabaseAssignmentRule patches an instance of DatabaseAssignmentRule.
+func (s *Store) PatchDatabaseAssignmentRule(ctx context.Context, patch *api.DatabaseAssignmentRulePatch) (*api.DatabaseAssignmentRule, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchDatabaseAssignmentRuleImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch database assignment rule with DatabaseAssignmentRulePatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDatabaseAssignmentRule(ctx, raw)
+}
+
+// DeleteDatabaseAssignmentRule deletes an existing database assignment rule by ID.
+func (s *Store) DeleteDatabaseAssignmentRule(ctx context.Context, delete *api.DatabaseAssignmentRuleDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM db_assignment_rule WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeDatabaseAssignmentRule(ctx context.Context, raw *databaseAssignmentRuleRaw) (*api.DatabaseAssignmentRule, error) {
+	rule := raw.toDatabaseAssignmentRule()
+
+	creator, err := s.GetPrincipalByID(ctx, rule.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	rule.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, rule.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	rule.Updater = updater
+
+	return rule, nil
+}
+
+func createDatabaseAssignmentRuleImpl(ctx context.Context, tx *sql.Tx, create *api.DatabaseAssignmentRuleCreate) (*databaseAssignmentRuleRaw, error) {
+	labelSelector := "{}"
+	if create.LabelSelector != "" {
+		labelSelector = create.LabelSelector
+	}
+	query := `
+		INSERT INTO db_assignment_rule (
+			creator_id,
+			updater_id,
+			project_id,
+			instance_id,
+			name_pattern,
+			label_selector
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, instance_id, name_pattern, label_selector
+	`
+	var raw databaseAssignmentRuleRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.ProjectID,
+		create.InstanceID,
+		create.NamePattern,
+		labelSelector,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.InstanceID,
+		&raw.NamePattern,
+		&raw.LabelSelector,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findDatabaseAssignmentRuleImpl(ctx context.Context, tx *sql.Tx, find *api.DatabaseAssignmentRuleFind) ([]*databaseAssignmentRuleRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			instance_id,
+			name_pattern,
+			label_selector
+		FROM db_assignment_rule
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*databaseAssignmentRuleRaw
+	for rows.Next() {
+		var raw databaseAssignmentRuleRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.ProjectID,
+			&raw.InstanceID,
+			&raw.NamePattern,
+			&raw.LabelSelector,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchDatabaseAssignmentRuleImpl(ctx context.Context, tx *sql.Tx, patch *api.DatabaseAssignmentRulePatch) (*databaseAssignmentRuleRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.InstanceID; v != nil {
+		set, args = append(set, fmt.Sprintf("instance_id = NULLIF($%d, 0)", len(args)+1)), append(args, *v)
+	}
+	if v := patch.NamePattern; v != nil {
+		set, args = append(set, fmt.Sprintf("name_pattern = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LabelSelector; v != nil {
+		labelSelector := *v
+		if labelSelector == "" {
+			labelSelector = "{}"
+		}
+		set, args = append(set, fmt.Sprintf("label_selector = $%d", len(args)+1)), append(args, labelSelector)
+	}
+	args = append(args, patch.ID)
+
+	var raw databaseAssignmentRuleRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE db_assignment_rule
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, instance_id, name_pattern, label_selector
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.InstanceID,
+		&raw.NamePattern,
+		&raw.LabelSelector,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("database assignment rule not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
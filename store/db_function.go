@@ -0,0 +1,330 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// dbFunctionRaw is the store model for a DBFunction.
+// Fields have exactly the same meanings as DBFunction.
+type dbFunctionRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name       string
+	Schema     string
+	Arguments  string
+	Type       string
+	Language   string
+	Definition string
+}
+
+// toDBFunction creates an instance of DBFunction based on the dbFunctionRaw.
+// This is intended to be called when we need to compose a DBFunction relationship.
+func (raw *dbFunctionRaw) toDBFunction() *api.DBFunction {
+	return &api.DBFunction{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		Name:       raw.Name,
+		Schema:     raw.Schema,
+		Arguments:  raw.Arguments,
+		Type:       raw.Type,
+		Language:   raw.Language,
+		Definition: raw.Definition,
+	}
+}
+
+// FindDBFunction finds a list of dbFunction instances.
+func (s *Store) FindDBFunction(ctx context.Context, find *api.DBFunctionFind) ([]*api.DBFunction, error) {
+	dbFunctionRawList, err := s.findDBFunctionRaw(ctx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dbFunction list with dbFunctionFind[%+v], error: %w", find, err)
+	}
+	var dbFunctionList []*api.DBFunction
+	for _, raw := range dbFunctionRawList {
+		dbFunction, err := s.composeDBFunction(ctx, raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compose dbFunction with dbFunctionRaw[%+v], error: %w", raw, err)
+		}
+		dbFunctionList = append(dbFunctionList, dbFunction)
+	}
+	return dbFunctionList, nil
+}
+
+// functionKey identifies a function, the overloaded functions share the same name with different arguments.
+type functionKey struct {
+	schema    string
+	name      string
+	arguments string
+}
+
+// SetDBFunctionList sets the functions for a database.
+func (s *Store) SetDBFunctionList(ctx context.Context, schema *db.Schema, databaseID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	oldDBFunctionRawList, err := s.findDBFunctionImpl(ctx, tx.PTx, &api.DBFunctionFind{
+		DatabaseID: &databaseID,
+	})
+	if err != nil {
+		return FormatError(err)
+	}
+
+	deletes, creates := generateDBFunctionActions(oldDBFunctionRawList, schema.FunctionList, databaseID)
+	for _, d := range deletes {
+		if err := s.deleteDBFunctionImpl(ctx, tx.PTx, d); err != nil {
+			return err
+		}
+	}
+	for _, c := range creates {
+		if _, err := s.createDBFunctionImpl(ctx, tx.PTx, c); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions.
+//
+
+func generateDBFunctionActions(oldDBFunctionRawList []*dbFunctionRaw, functionList []db.Function, databaseID int) ([]*api.DBFunctionDelete, []*api.DBFunctionCreate) {
+	var newDBFunctionList []*api.DBFunctionCreate
+	for _, function := range functionList {
+		newDBFunctionList = append(newDBFunctionList, &api.DBFunctionCreate{
+			CreatorID:  api.SystemBotID,
+			DatabaseID: databaseID,
+			Name:       function.Name,
+			Schema:     function.Schema,
+			Arguments:  function.Arguments,
+			Type:       function.Type,
+			Language:   function.Language,
+			Definition: function.Definition,
+		})
+	}
+	oldDBFunctionMap := make(map[functionKey]*dbFunctionRaw)
+	for _, f := range oldDBFunctionRawList {
+		oldDBFunctionMap[functionKey{schema: f.Schema, name: f.Name, arguments: f.Arguments}] = f
+	}
+	newDBFunctionMap := make(map[functionKey]*api.DBFunctionCreate)
+	for _, f := range newDBFunctionList {
+		newDBFunctionMap[functionKey{schema: f.Schema, name: f.Name, arguments: f.Arguments}] = f
+	}
+
+	var deletes []*api.DBFunctionDelete
+	var creates []*api.DBFunctionCreate
+	for _, oldValue := range oldDBFunctionRawList {
+		k := functionKey{schema: oldValue.Schema, name: oldValue.Name, arguments: oldValue.Arguments}
+		newValue, ok := newDBFunctionMap[k]
+		if !ok {
+			deletes = append(deletes, &api.DBFunctionDelete{ID: oldValue.ID})
+		} else if oldValue.Type != newValue.Type || oldValue.Language != newValue.Language || oldValue.Definition != newValue.Definition {
+			deletes = append(deletes, &api.DBFunctionDelete{ID: oldValue.ID})
+			creates = append(creates, newValue)
+		}
+	}
+	for _, newValue := range newDBFunctionList {
+		k := functionKey{schema: newValue.Schema, name: newValue.Name, arguments: newValue.Arguments}
+		if _, ok := oldDBFunctionMap[k]; !ok {
+			creates = append(creates, newValue)
+		}
+	}
+	return deletes, creates
+}
+
+func (s *Store) composeDBFunction(ctx context.Context, raw *dbFunctionRaw) (*api.DBFunction, error) {
+	dbFunction := raw.toDBFunction()
+
+	creator, err := s.GetPrincipalByID(ctx, dbFunction.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	dbFunction.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, dbFunction.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	dbFunction.Updater = updater
+
+	database, err := s.GetDatabase(ctx, &api.DatabaseFind{ID: &dbFunction.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	dbFunction.Database = database
+
+	return dbFunction, nil
+}
+
+// findDBFunctionRaw retrieves a list of DBFunctions based on find.
+func (s *Store) findDBFunctionRaw(ctx context.Context, find *api.DBFunctionFind) ([]*dbFunctionRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := s.findDBFunctionImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// createDBFunctionImpl creates a new DBFunction.
+func (*Store) createDBFunctionImpl(ctx context.Context, tx *sql.Tx, create *api.DBFunctionCreate) (*dbFunctionRaw, error) {
+	// Insert row into db_function.
+	query := `
+		INSERT INTO db_function (
+			creator_id,
+			updater_id,
+			database_id,
+			name,
+			schema,
+			arguments,
+			type,
+			language,
+			definition
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, schema, arguments, type, language, definition
+	`
+	var dbFunctionRaw dbFunctionRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.Name,
+		create.Schema,
+		create.Arguments,
+		create.Type,
+		create.Language,
+		create.Definition,
+	).Scan(
+		&dbFunctionRaw.ID,
+		&dbFunctionRaw.CreatorID,
+		&dbFunctionRaw.CreatedTs,
+		&dbFunctionRaw.UpdaterID,
+		&dbFunctionRaw.UpdatedTs,
+		&dbFunctionRaw.DatabaseID,
+		&dbFunctionRaw.Name,
+		&dbFunctionRaw.Schema,
+		&dbFunctionRaw.Arguments,
+		&dbFunctionRaw.Type,
+		&dbFunctionRaw.Language,
+		&dbFunctionRaw.Definition,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &dbFunctionRaw, nil
+}
+
+func (*Store) findDBFunctionImpl(ctx context.Context, tx *sql.Tx, find *api.DBFunctionFind) ([]*dbFunctionRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			name,
+			schema,
+			arguments,
+			type,
+			language,
+			definition
+		FROM db_function
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id, schema, name, arguments ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into dbFunctionRawList.
+	var dbFunctionRawList []*dbFunctionRaw
+	for rows.Next() {
+		var dbFunctionRaw dbFunctionRaw
+		if err := rows.Scan(
+			&dbFunctionRaw.ID,
+			&dbFunctionRaw.CreatorID,
+			&dbFunctionRaw.CreatedTs,
+			&dbFunctionRaw.UpdaterID,
+			&dbFunctionRaw.UpdatedTs,
+			&dbFunctionRaw.DatabaseID,
+			&dbFunctionRaw.Name,
+			&dbFunctionRaw.Schema,
+			&dbFunctionRaw.Arguments,
+			&dbFunctionRaw.Type,
+			&dbFunctionRaw.Language,
+			&dbFunctionRaw.Definition,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		dbFunctionRawList = append(dbFunctionRawList, &dbFunctionRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return dbFunctionRawList, nil
+}
+
+// deleteDBFunctionImpl permanently deletes a DBFunction from a database.
+func (*Store) deleteDBFunctionImpl(ctx context.Context, tx *sql.Tx, delete *api.DBFunctionDelete) error {
+	// Remove row from database.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM db_function WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDBFunctionActions(t *testing.T) {
+	databaseID := 198
+	tests := []struct {
+		oldDBFunctionRawList []*dbFunctionRaw
+		functionList         []db.Function
+		wantDeletes          []*api.DBFunctionDelete
+		wantCreates          []*api.DBFunctionCreate
+	}{
+		{
+			oldDBFunctionRawList: []*dbFunctionRaw{
+				{ID: 123, Name: "add", Schema: "public", Arguments: "a integer, b integer", Type: "FUNCTION", Language: "sql", Definition: "def1"},
+			},
+			functionList: []db.Function{
+				{Name: "add", Schema: "public", Arguments: "a integer, b integer", Type: "FUNCTION", Language: "sql", Definition: "def2"},
+				{Name: "add", Schema: "public", Arguments: "a bigint, b bigint", Type: "FUNCTION", Language: "sql", Definition: "def3"},
+			},
+			wantDeletes: []*api.DBFunctionDelete{
+				{ID: 123},
+			},
+			wantCreates: []*api.DBFunctionCreate{
+				{Name: "add", Schema: "public", Arguments: "a integer, b integer", Type: "FUNCTION", Language: "sql", Definition: "def2", CreatorID: api.SystemBotID, DatabaseID: databaseID},
+				{Name: "add", Schema: "public", Arguments: "a bigint, b bigint", Type: "FUNCTION", Language: "sql", Definition: "def3", CreatorID: api.SystemBotID, DatabaseID: databaseID},
+			},
+		},
+		{
+			oldDBFunctionRawList: []*dbFunctionRaw{
+				{ID: 123, Name: "archive", Schema: "public", Arguments: "", Type: "PROCEDURE", Language: "plpgsql", Definition: "def1"},
+			},
+			functionList: nil,
+			wantDeletes: []*api.DBFunctionDelete{
+				{ID: 123},
+			},
+			wantCreates: nil,
+		},
+		{
+			oldDBFunctionRawList: []*dbFunctionRaw{
+				{ID: 123, Name: "archive", Schema: "public", Arguments: "", Type: "PROCEDURE", Language: "plpgsql", Definition: "def1"},
+			},
+			functionList: []db.Function{
+				{Name: "archive", Schema: "public", Arguments: "", Type: "PROCEDURE", Language: "plpgsql", Definition: "def1"},
+			},
+			wantDeletes: nil,
+			wantCreates: nil,
+		},
+	}
+
+	for _, test := range tests {
+		deletes, creates := generateDBFunctionActions(test.oldDBFunctionRawList, test.functionList, databaseID)
+		require.Equal(t, test.wantDeletes, deletes)
+		require.Equal(t, test.wantCreates, creates)
+	}
+}
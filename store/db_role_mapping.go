@@ -0,0 +1,308 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// databaseRoleMappingRaw is the store model for a DatabaseRoleMapping.
+// Fields have exactly the same meanings as DatabaseRoleMapping.
+type databaseRoleMappingRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	InstanceID  int
+	PrincipalID int
+
+	// Domain specific fields
+	RoleName string
+}
+
+// toDatabaseRoleMapping creates an instance of DatabaseRoleMapping based on the databaseRoleMappingRaw.
+// This is intended to be called when we need to compose a DatabaseRoleMapping relationship.
+func (raw *databaseRoleMappingRaw) toDatabaseRoleMapping() *api.DatabaseRoleMapping {
+	return &api.DatabaseRoleMapping{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		InstanceID:  raw.InstanceID,
+		PrincipalID: raw.PrincipalID,
+
+		// Domain specific fields
+		RoleName: raw.RoleName,
+	}
+}
+
+// CreateDatabaseRoleMapping creates an instance of DatabaseRoleMapping.
+func (s *Store) CreateDatabaseRoleMapping(ctx context.Context, create *api.DatabaseRoleMappingCreate) (*api.DatabaseRoleMapping, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createDatabaseRoleMappingImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database role mapping with DatabaseRoleMappingCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDatabaseRoleMapping(ctx, raw)
+}
+
+// GetDatabaseRoleMappingByID gets an instance of DatabaseRoleMapping.
+func (s *Store) GetDatabaseRoleMappingByID(ctx context.Context, id int) (*api.DatabaseRoleMapping, error) {
+	list, err := s.FindDatabaseRoleMapping(ctx, &api.DatabaseRoleMappingFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d database role mappings with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// FindDatabaseRoleMapping finds a list of DatabaseRoleMapping instances.
+func (s *Store) FindDatabaseRoleMapping(ctx context.Context, find *api.DatabaseRoleMappingFind) ([]*api.DatabaseRoleMapping, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findDatabaseRoleMappingImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find database role mapping list with DatabaseRoleMappingFind[%+v], error: %w", find, err)
+	}
+	var mappingList []*api.DatabaseRoleMapping
+	for _, raw := range rawList {
+		mapping, err := s.composeDatabaseRoleMapping(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		mappingList = append(mappingList, mapping)
+	}
+	return mappingList, nil
+}
+
+// PatchDatabaseRoleMapping patches an instance of DatabaseRoleMapping.
+func (s *Store) PatchDatabaseRoleMapping(ctx context.Context, patch *api.DatabaseRoleMappingPatch) (*api.DatabaseRoleMapping, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchDatabaseRoleMappingImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch database role mapping with DatabaseRoleMappingPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeDatabaseRoleMapping(ctx, raw)
+}
+
+// DeleteDatabaseRoleMapping deletes an existing database role mapping by ID.
+func (s *Store) DeleteDatabaseRoleMapping(ctx context.Context, delete *api.DatabaseRoleMappingDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM db_role_mapping WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeDatabaseRoleMapping(ctx context.Context, raw *databaseRoleMappingRaw) (*api.DatabaseRoleMapping, error) {
+	mapping := raw.toDatabaseRoleMapping()
+
+	creator, err := s.GetPrincipalByID(ctx, mapping.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	mapping.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, mapping.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	mapping.Updater = updater
+
+	principal, err := s.GetPrincipalByID(ctx, mapping.PrincipalID)
+	if err != nil {
+		return nil, err
+	}
+	mapping.Principal = principal
+
+	return mapping, nil
+}
+
+func createDatabaseRoleMappingImpl(ctx context.Context, tx *sql.Tx, create *api.DatabaseRoleMappingCreate) (*databaseRoleMappingRaw, error) {
+	query := `
+		INSERT INTO db_role_mapping (
+			creator_id,
+			updater_id,
+			instance_id,
+			role_name,
+			principal_id
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, role_name, principal_id
+	`
+	var raw databaseRoleMappingRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.InstanceID,
+		create.RoleName,
+		create.PrincipalID,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.InstanceID,
+		&raw.RoleName,
+		&raw.PrincipalID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findDatabaseRoleMappingImpl(ctx context.Context, tx *sql.Tx, find *api.DatabaseRoleMappingFind) ([]*databaseRoleMappingRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PrincipalID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.RoleName; v != nil {
+		where, args = append(where, fmt.Sprintf("role_name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			instance_id,
+			role_name,
+			principal_id
+		FROM db_role_mapping
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY instance_id ASC, role_name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*databaseRoleMappingRaw
+	for rows.Next() {
+		var raw databaseRoleMappingRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.InstanceID,
+			&raw.RoleName,
+			&raw.PrincipalID,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchDatabaseRoleMappingImpl(ctx context.Context, tx *sql.Tx, patch *api.DatabaseRoleMappingPatch) (*databaseRoleMappingRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.PrincipalID; v != nil {
+		set, args = append(set, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	var raw databaseRoleMappingRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE db_role_mapping
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, role_name, principal_id
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.InstanceID,
+		&raw.RoleName,
+		&raw.PrincipalID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("database role mapping not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
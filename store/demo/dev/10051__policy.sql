@@ -150,7 +150,7 @@ VALUES
         'bb.policy.pipeline-approval',
         '{"value":"MANUAL_APPROVAL_ALWAYS"}'
     )
-    ON CONFLICT(environment_id, type) DO UPDATE SET
+    ON CONFLICT(environment_id, tag, type) DO UPDATE SET
 				payload = excluded.payload;
 
 ALTER SEQUENCE policy_id_seq RESTART WITH 5109;
@@ -5,6 +5,12 @@ DELETE FROM
 DELETE FROM
     anomaly;
 
+DELETE FROM
+    usage_metric;
+
+DELETE FROM
+    database_template;
+
 DELETE FROM
     repository;
 
@@ -26,12 +32,21 @@ DELETE FROM
 DELETE FROM
     sheet;
 
+DELETE FROM
+    issue_field_value;
+
+DELETE FROM
+    issue_attachment;
+
 DELETE FROM
     issue_subscriber;
 
 DELETE FROM
     issue;
 
+DELETE FROM
+    access_change_history;
+
 DELETE FROM
     task_check_run;
 
@@ -71,6 +86,12 @@ DELETE FROM
 DELETE FROM
     backup_setting;
 
+DELETE FROM
+    partition_policy;
+
+DELETE FROM
+    account_report;
+
 -- Delete in this order following foreign constraints.
 DELETE FROM
     db_label;
@@ -84,6 +105,9 @@ DELETE FROM
 DELETE FROM
     db;
 
+DELETE FROM
+    instance_parameter;
+
 DELETE FROM
     instance_user;
 
@@ -96,6 +120,15 @@ DELETE FROM
 DELETE FROM
     environment;
 
+DELETE FROM
+    recurring_issue;
+
+DELETE FROM
+    issue_field;
+
+DELETE FROM
+    project_variable;
+
 DELETE FROM
     project_webhook;
 
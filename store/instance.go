@@ -26,14 +26,17 @@ type instanceRaw struct {
 
 	// Related fields
 	EnvironmentID int
+	AgentID       *int
 
 	// Domain specific fields
 	Name          string
 	Engine        db.Type
 	EngineVersion string
+	Dialect       db.Dialect
 	ExternalLink  string
 	Host          string
 	Port          string
+	TagList       string
 }
 
 // toInstance creates an instance of Instance based on the instanceRaw.
@@ -51,14 +54,17 @@ func (raw *instanceRaw) toInstance() *api.Instance {
 
 		// Related fields
 		EnvironmentID: raw.EnvironmentID,
+		AgentID:       raw.AgentID,
 
 		// Domain specific fields
 		Name:          raw.Name,
 		Engine:        raw.Engine,
 		EngineVersion: raw.EngineVersion,
+		Dialect:       raw.Dialect,
 		ExternalLink:  raw.ExternalLink,
 		Host:          raw.Host,
 		Port:          raw.Port,
+		TagList:       raw.TagList,
 	}
 }
 
@@ -190,12 +196,15 @@ func (s *Store) FindInstanceWithDatabaseBackupEnabled(ctx context.Context, engin
 			instance.updater_id,
 			instance.updated_ts,
 			instance.environment_id,
+			instance.agent_id,
 			instance.name,
 			instance.engine,
 			instance.engine_version,
+			instance.dialect,
 			instance.external_link,
 			instance.host,
-			instance.port
+			instance.port,
+			instance.tag_list
 		FROM instance
 		JOIN db ON db.instance_id = instance.id
 		JOIN backup_setting AS bs ON db.id = bs.database_id
@@ -219,12 +228,15 @@ func (s *Store) FindInstanceWithDatabaseBackupEnabled(ctx context.Context, engin
 			&instanceRaw.UpdaterID,
 			&instanceRaw.UpdatedTs,
 			&instanceRaw.EnvironmentID,
+			&instanceRaw.AgentID,
 			&instanceRaw.Name,
 			&instanceRaw.Engine,
 			&instanceRaw.EngineVersion,
+			&instanceRaw.Dialect,
 			&instanceRaw.ExternalLink,
 			&instanceRaw.Host,
 			&instanceRaw.Port,
+			&instanceRaw.TagList,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -486,7 +498,7 @@ func createInstanceImpl(ctx context.Context, tx *sql.Tx, create *api.InstanceCre
 			port
 		)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, environment_id, name, engine, engine_version, external_link, host, port
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, environment_id, agent_id, name, engine, engine_version, dialect, external_link, host, port, tag_list
 	`
 	var instanceRaw instanceRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -506,12 +518,15 @@ func createInstanceImpl(ctx context.Context, tx *sql.Tx, create *api.InstanceCre
 		&instanceRaw.UpdaterID,
 		&instanceRaw.UpdatedTs,
 		&instanceRaw.EnvironmentID,
+		&instanceRaw.AgentID,
 		&instanceRaw.Name,
 		&instanceRaw.Engine,
 		&instanceRaw.EngineVersion,
+		&instanceRaw.Dialect,
 		&instanceRaw.ExternalLink,
 		&instanceRaw.Host,
 		&instanceRaw.Port,
+		&instanceRaw.TagList,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -533,12 +548,15 @@ func findInstanceImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceFind) (
 			updater_id,
 			updated_ts,
 			environment_id,
+			agent_id,
 			name,
 			engine,
 			engine_version,
+			dialect,
 			external_link,
 			host,
-			port
+			port,
+			tag_list
 		FROM instance
 		WHERE `+where,
 		args...,
@@ -560,12 +578,15 @@ func findInstanceImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceFind) (
 			&instanceRaw.UpdaterID,
 			&instanceRaw.UpdatedTs,
 			&instanceRaw.EnvironmentID,
+			&instanceRaw.AgentID,
 			&instanceRaw.Name,
 			&instanceRaw.Engine,
 			&instanceRaw.EngineVersion,
+			&instanceRaw.Dialect,
 			&instanceRaw.ExternalLink,
 			&instanceRaw.Host,
 			&instanceRaw.Port,
+			&instanceRaw.TagList,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -588,9 +609,19 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 	if v := patch.Name; v != nil {
 		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.Engine; v != nil {
+		set, args = append(set, fmt.Sprintf("engine = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := patch.EngineVersion; v != nil {
 		set, args = append(set, fmt.Sprintf("engine_version = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.Dialect; v != nil {
+		set, args = append(set, fmt.Sprintf("dialect = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AgentID; v != nil {
+		// 0 means executing the instance work from the Bytebase server directly.
+		set, args = append(set, fmt.Sprintf("agent_id = NULLIF($%d, 0)", len(args)+1)), append(args, *v)
+	}
 	if v := patch.ExternalLink; v != nil {
 		set, args = append(set, fmt.Sprintf("external_link = $%d", len(args)+1)), append(args, *v)
 	}
@@ -600,6 +631,9 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 	if v := patch.Port; v != nil {
 		set, args = append(set, fmt.Sprintf("port = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.TagList; v != nil {
+		set, args = append(set, fmt.Sprintf("tag_list = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -609,7 +643,7 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 		UPDATE instance
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, environment_id, name, engine, engine_version, external_link, host, port
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, environment_id, agent_id, name, engine, engine_version, dialect, external_link, host, port, tag_list
 	`, len(args)),
 		args...,
 	).Scan(
@@ -620,12 +654,15 @@ func patchInstanceImpl(ctx context.Context, tx *sql.Tx, patch *api.InstancePatch
 		&instanceRaw.UpdaterID,
 		&instanceRaw.UpdatedTs,
 		&instanceRaw.EnvironmentID,
+		&instanceRaw.AgentID,
 		&instanceRaw.Name,
 		&instanceRaw.Engine,
 		&instanceRaw.EngineVersion,
+		&instanceRaw.Dialect,
 		&instanceRaw.ExternalLink,
 		&instanceRaw.Host,
 		&instanceRaw.Port,
+		&instanceRaw.TagList,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("instance ID not found: %d", patch.ID)}
@@ -647,6 +684,12 @@ func findInstanceQuery(find *api.InstanceFind) (string, []interface{}) {
 	if v := find.EnvironmentID; v != nil {
 		where, args = append(where, fmt.Sprintf("environment_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.AgentID; v != nil {
+		where, args = append(where, fmt.Sprintf("agent_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.Host; v != nil {
 		where, args = append(where, fmt.Sprintf("host = $%d", len(args)+1)), append(args, *v)
 	}
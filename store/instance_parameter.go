@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// instanceParameterRaw is the store model for an InstanceParameter.
+// Fields have exactly the same meanings as InstanceParameter.
+type instanceParameterRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	Name  string
+	Value string
+}
+
+// toInstanceParameter creates an instance of InstanceParameter based on the instanceParameterRaw.
+// This is intended to be called when we need to compose an InstanceParameter relationship.
+func (raw *instanceParameterRaw) toInstanceParameter() *api.InstanceParameter {
+	return &api.InstanceParameter{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		InstanceID: raw.InstanceID,
+
+		// Domain specific fields
+		Name:  raw.Name,
+		Value: raw.Value,
+	}
+}
+
+// CreateInstanceParameter creates an instance of InstanceParameter.
+func (s *Store) CreateInstanceParameter(ctx context.Context, create *api.InstanceParameterCreate) (*api.InstanceParameter, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createInstanceParameterImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instance parameter with InstanceParameterCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return raw.toInstanceParameter(), nil
+}
+
+// FindInstanceParameter finds a list of InstanceParameter instances ordered by the name and the descending ID.
+func (s *Store) FindInstanceParameter(ctx context.Context, find *api.InstanceParameterFind) ([]*api.InstanceParameter, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findInstanceParameterImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find instance parameter list with InstanceParameterFind[%+v], error: %w", find, err)
+	}
+	var parameterList []*api.InstanceParameter
+	for _, raw := range rawList {
+		parameterList = append(parameterList, raw.toInstanceParameter())
+	}
+	return parameterList, nil
+}
+
+//
+// private functions
+//
+
+func createInstanceParameterImpl(ctx context.Context, tx *sql.Tx, create *api.InstanceParameterCreate) (*instanceParameterRaw, error) {
+	query := `
+		INSERT INTO instance_parameter (
+			creator_id,
+			instance_id,
+			name,
+			value
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, creator_id, created_ts, instance_id, name, value
+	`
+	var raw instanceParameterRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.InstanceID,
+		create.Name,
+		create.Value,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.InstanceID,
+		&raw.Name,
+		&raw.Value,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findInstanceParameterImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceParameterFind) ([]*instanceParameterRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	distinct := ""
+	if find.Latest {
+		distinct = "DISTINCT ON (instance_id, name)"
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+distinct+`
+			id,
+			creator_id,
+			created_ts,
+			instance_id,
+			name,
+			value
+		FROM instance_parameter
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY instance_id, name, id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*instanceParameterRaw
+	for rows.Next() {
+		var raw instanceParameterRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.InstanceID,
+			&raw.Name,
+			&raw.Value,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
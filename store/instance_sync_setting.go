@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// instanceSyncSettingRaw is the store model for an InstanceSyncSetting.
+// Fields have exactly the same meanings as InstanceSyncSetting.
+type instanceSyncSettingRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	IntervalMinutes    int
+	BlackoutWindowList string
+}
+
+// toInstanceSyncSetting creates an instance of InstanceSyncSetting based on the instanceSyncSettingRaw.
+// This is intended to be called when we need to compose an InstanceSyncSetting relationship.
+func (raw *instanceSyncSettingRaw) toInstanceSyncSetting() *api.InstanceSyncSetting {
+	return &api.InstanceSyncSetting{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		InstanceID: raw.InstanceID,
+
+		// Domain specific fields
+		IntervalMinutes:    raw.IntervalMinutes,
+		BlackoutWindowList: raw.BlackoutWindowList,
+	}
+}
+
+// UpsertInstanceSyncSetting upserts the schema sync schedule of an instance.
+func (s *Store) UpsertInstanceSyncSetting(ctx context.Context, upsert *api.InstanceSyncSettingUpsert) (*api.InstanceSyncSetting, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := upsertInstanceSyncSettingImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert instance sync setting with InstanceSyncSettingUpsert[%+v], error: %w", upsert, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeInstanceSyncSetting(ctx, raw)
+}
+
+// GetInstanceSyncSettingByInstanceID gets the schema sync schedule of an instance.
+// Returns nil if the instance has no setting.
+func (s *Store) GetInstanceSyncSettingByInstanceID(ctx context.Context, instanceID int) (*api.InstanceSyncSetting, error) {
+	list, err := s.FindInstanceSyncSetting(ctx, &api.InstanceSyncSettingFind{InstanceID: &instanceID})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d instance sync settings with instance ID %d, expect 1", len(list), instanceID)}
+	}
+	return list[0], nil
+}
+
+// FindInstanceSyncSetting finds a list of InstanceSyncSetting instances.
+func (s *Store) FindInstanceSyncSetting(ctx context.Context, find *api.InstanceSyncSettingFind) ([]*api.InstanceSyncSetting, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findInstanceSyncSettingImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find instance sync setting list with InstanceSyncSettingFind[%+v], error: %w", find, err)
+	}
+	var settingList []*api.InstanceSyncSetting
+	for _, raw := range rawList {
+		setting, err := s.composeInstanceSyncSetting(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		settingList = append(settingList, setting)
+	}
+	return settingList, nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeInstanceSyncSetting(ctx context.Context, raw *instanceSyncSettingRaw) (*api.InstanceSyncSetting, error) {
+	setting := raw.toInstanceSyncSetting()
+
+	creator, err := s.GetPrincipalByID(ctx, setting.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	setting.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, setting.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	setting.Updater = updater
+
+	return setting, nil
+}
+
+func upsertInstanceSyncSettingImpl(ctx context.Context, tx *sql.Tx, upsert *api.InstanceSyncSettingUpsert) (*instanceSyncSettingRaw, error) {
+	query := `
+		INSERT INTO instance_sync_setting (
+			creator_id,
+			updater_id,
+			instance_id,
+			interval_minutes,
+			blackout_window_list
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(instance_id) DO UPDATE SET
+				updater_id = EXCLUDED.updater_id,
+				interval_minutes = EXCLUDED.interval_minutes,
+				blackout_window_list = EXCLUDED.blackout_window_list
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, interval_minutes, blackout_window_list
+	`
+	var raw instanceSyncSettingRaw
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.InstanceID,
+		upsert.IntervalMinutes,
+		upsert.BlackoutWindowList,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.InstanceID,
+		&raw.IntervalMinutes,
+		&raw.BlackoutWindowList,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findInstanceSyncSettingImpl(ctx context.Context, tx *sql.Tx, find *api.InstanceSyncSettingFind) ([]*instanceSyncSettingRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			instance_id,
+			interval_minutes,
+			blackout_window_list
+		FROM instance_sync_setting
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*instanceSyncSettingRaw
+	for rows.Next() {
+		var raw instanceSyncSettingRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.InstanceID,
+			&raw.IntervalMinutes,
+			&raw.BlackoutWindowList,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
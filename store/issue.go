@@ -111,6 +111,23 @@ func (s *Store) GetIssueByPipelineID(ctx context.Context, id int) (*api.Issue, e
 	return issue, nil
 }
 
+// GetIssueByIdempotencyKey gets the issue created by the creator with the idempotency key.
+func (s *Store) GetIssueByIdempotencyKey(ctx context.Context, creatorID int, idempotencyKey string) (*api.Issue, error) {
+	find := &api.IssueFind{CreatorID: &creatorID, IdempotencyKey: &idempotencyKey}
+	issueRaw, err := s.getIssueRaw(ctx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Issue with CreatorID %d and IdempotencyKey %q, error: %w", creatorID, idempotencyKey, err)
+	}
+	if issueRaw == nil {
+		return nil, nil
+	}
+	issue, err := s.composeIssue(ctx, issueRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose Issue with issueRaw[%+v], error: %w", issueRaw, err)
+	}
+	return issue, nil
+}
+
 // FindIssue finds a list of Issue instances.
 func (s *Store) FindIssue(ctx context.Context, find *api.IssueFind) ([]*api.Issue, error) {
 	issueRawList, err := s.findIssueRaw(ctx, find)
@@ -480,9 +497,10 @@ func (*Store) createIssueImpl(ctx context.Context, tx *sql.Tx, create *api.Issue
 			type,
 			description,
 			assignee_id,
-			payload
+			payload,
+			idempotency_key
 		)
-		VALUES ($1, $2, $3, $4, $5, 'OPEN', $6, $7, $8, $9)
+		VALUES ($1, $2, $3, $4, $5, 'OPEN', $6, $7, $8, $9, $10)
 		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, pipeline_id, name, status, type, description, assignee_id, payload
 	`
 	var issueRaw issueRaw
@@ -496,6 +514,7 @@ func (*Store) createIssueImpl(ctx context.Context, tx *sql.Tx, create *api.Issue
 		create.Description,
 		create.AssigneeID,
 		create.Payload,
+		create.IdempotencyKey,
 	).Scan(
 		&issueRaw.ID,
 		&issueRaw.CreatorID,
@@ -537,6 +556,12 @@ func (*Store) findIssueImpl(ctx context.Context, tx *sql.Tx, find *api.IssueFind
 		args = append(args, *v)
 		args = append(args, *v)
 	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.IdempotencyKey; v != nil {
+		where, args = append(where, fmt.Sprintf("idempotency_key = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.StatusList; v != nil {
 		list := []string{}
 		for _, status := range *v {
@@ -0,0 +1,307 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// issueAttachmentRaw is the store model for an IssueAttachment.
+// Fields have exactly the same meanings as IssueAttachment.
+type issueAttachmentRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	IssueID int
+
+	// Domain specific fields
+	Name        string
+	ContentType string
+	Size        int64
+	BlobKey     string
+}
+
+// toIssueAttachment creates an instance of IssueAttachment based on the issueAttachmentRaw.
+// This is intended to be called when we need to compose an IssueAttachment relationship.
+func (raw *issueAttachmentRaw) toIssueAttachment() *api.IssueAttachment {
+	return &api.IssueAttachment{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		IssueID: raw.IssueID,
+
+		// Domain specific fields
+		Name:        raw.Name,
+		ContentType: raw.ContentType,
+		Size:        raw.Size,
+		BlobKey:     raw.BlobKey,
+	}
+}
+
+// CreateIssueAttachment creates an instance of IssueAttachment.
+func (s *Store) CreateIssueAttachment(ctx context.Context, create *api.IssueAttachmentCreate) (*api.IssueAttachment, error) {
+	issueAttachmentRaw, err := s.createIssueAttachmentRaw(ctx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IssueAttachment with IssueAttachmentCreate[%+v], error: %w", create, err)
+	}
+	issueAttachment, err := s.composeIssueAttachment(ctx, issueAttachmentRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose IssueAttachment with issueAttachmentRaw[%+v], error: %w", issueAttachmentRaw, err)
+	}
+	return issueAttachment, nil
+}
+
+// GetIssueAttachmentByID gets an instance of IssueAttachment.
+func (s *Store) GetIssueAttachmentByID(ctx context.Context, id int) (*api.IssueAttachment, error) {
+	find := &api.IssueAttachmentFind{ID: &id}
+	issueAttachmentRaw, err := s.getIssueAttachmentRaw(ctx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IssueAttachment with ID %d, error: %w", id, err)
+	}
+	if issueAttachmentRaw == nil {
+		return nil, nil
+	}
+	issueAttachment, err := s.composeIssueAttachment(ctx, issueAttachmentRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose IssueAttachment with issueAttachmentRaw[%+v], error: %w", issueAttachmentRaw, err)
+	}
+	return issueAttachment, nil
+}
+
+// FindIssueAttachment finds a list of IssueAttachment instances.
+func (s *Store) FindIssueAttachment(ctx context.Context, find *api.IssueAttachmentFind) ([]*api.IssueAttachment, error) {
+	issueAttachmentRawList, err := s.findIssueAttachmentRaw(ctx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find IssueAttachment list with IssueAttachmentFind[%+v], error: %w", find, err)
+	}
+	var issueAttachmentList []*api.IssueAttachment
+	for _, raw := range issueAttachmentRawList {
+		issueAttachment, err := s.composeIssueAttachment(ctx, raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compose IssueAttachment with issueAttachmentRaw[%+v], error: %w", raw, err)
+		}
+		issueAttachmentList = append(issueAttachmentList, issueAttachment)
+	}
+	return issueAttachmentList, nil
+}
+
+// DeleteIssueAttachment deletes an existing issue attachment by ID.
+// Returns ENOTFOUND if issue attachment does not exist.
+func (s *Store) DeleteIssueAttachment(ctx context.Context, delete *api.IssueAttachmentDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if err := deleteIssueAttachmentImpl(ctx, tx.PTx, delete); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private function
+//
+
+func (s *Store) composeIssueAttachment(ctx context.Context, raw *issueAttachmentRaw) (*api.IssueAttachment, error) {
+	issueAttachment := raw.toIssueAttachment()
+
+	creator, err := s.GetPrincipalByID(ctx, issueAttachment.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	issueAttachment.Creator = creator
+
+	return issueAttachment, nil
+}
+
+// createIssueAttachmentRaw creates a new issue attachment.
+func (s *Store) createIssueAttachmentRaw(ctx context.Context, create *api.IssueAttachmentCreate) (*issueAttachmentRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	issueAttachment, err := createIssueAttachmentImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return issueAttachment, nil
+}
+
+// findIssueAttachmentRaw retrieves a list of issue attachments based on find.
+func (s *Store) findIssueAttachmentRaw(ctx context.Context, find *api.IssueAttachmentFind) ([]*issueAttachmentRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findIssueAttachmentImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// getIssueAttachmentRaw retrieves a single issue attachment based on find.
+// Returns ECONFLICT if finding more than 1 matching records.
+func (s *Store) getIssueAttachmentRaw(ctx context.Context, find *api.IssueAttachmentFind) (*issueAttachmentRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findIssueAttachmentImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d issue attachments with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// createIssueAttachmentImpl creates a new issue attachment.
+func createIssueAttachmentImpl(ctx context.Context, tx *sql.Tx, create *api.IssueAttachmentCreate) (*issueAttachmentRaw, error) {
+	// Insert row into database.
+	query := `
+		INSERT INTO issue_attachment (
+			creator_id,
+			updater_id,
+			issue_id,
+			name,
+			content_type,
+			size,
+			blob_key
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, issue_id, name, content_type, size, blob_key
+	`
+	var issueAttachmentRaw issueAttachmentRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.IssueID,
+		create.Name,
+		create.ContentType,
+		create.Size,
+		create.BlobKey,
+	).Scan(
+		&issueAttachmentRaw.ID,
+		&issueAttachmentRaw.CreatorID,
+		&issueAttachmentRaw.CreatedTs,
+		&issueAttachmentRaw.IssueID,
+		&issueAttachmentRaw.Name,
+		&issueAttachmentRaw.ContentType,
+		&issueAttachmentRaw.Size,
+		&issueAttachmentRaw.BlobKey,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &issueAttachmentRaw, nil
+}
+
+func findIssueAttachmentImpl(ctx context.Context, tx *sql.Tx, find *api.IssueAttachmentFind) ([]*issueAttachmentRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.IssueID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			issue_id,
+			name,
+			content_type,
+			size,
+			blob_key
+		FROM issue_attachment
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into issueAttachmentRawList.
+	var issueAttachmentRawList []*issueAttachmentRaw
+	for rows.Next() {
+		var issueAttachment issueAttachmentRaw
+		if err := rows.Scan(
+			&issueAttachment.ID,
+			&issueAttachment.CreatorID,
+			&issueAttachment.CreatedTs,
+			&issueAttachment.IssueID,
+			&issueAttachment.Name,
+			&issueAttachment.ContentType,
+			&issueAttachment.Size,
+			&issueAttachment.BlobKey,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		issueAttachmentRawList = append(issueAttachmentRawList, &issueAttachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return issueAttachmentRawList, nil
+}
+
+// deleteIssueAttachmentImpl permanently deletes an issue attachment by ID.
+func deleteIssueAttachmentImpl(ctx context.Context, tx *sql.Tx, delete *api.IssueAttachmentDelete) error {
+	// Remove row from database.
+	result, err := tx.ExecContext(ctx, `DELETE FROM issue_attachment WHERE id = $1`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: fmt.Errorf("issue attachment ID not found: %d", delete.ID)}
+	}
+
+	return nil
+}
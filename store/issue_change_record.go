@@ -0,0 +1,189 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// issueChangeRecordRaw is the store model for an IssueChangeRecord.
+// Fields have exactly the same meanings as IssueChangeRecord.
+type issueChangeRecordRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	IssueID int
+
+	// Domain specific fields
+	Payload   string
+	Signature string
+}
+
+// toIssueChangeRecord creates an instance of IssueChangeRecord based on the issueChangeRecordRaw.
+// This is intended to be called when we need to compose an IssueChangeRecord relationship.
+func (raw *issueChangeRecordRaw) toIssueChangeRecord() *api.IssueChangeRecord {
+	return &api.IssueChangeRecord{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		IssueID: raw.IssueID,
+
+		// Domain specific fields
+		Payload:   raw.Payload,
+		Signature: raw.Signature,
+	}
+}
+
+// CreateIssueChangeRecord creates an instance of IssueChangeRecord signed by the signature key.
+func (s *Store) CreateIssueChangeRecord(ctx context.Context, create *api.IssueChangeRecordCreate, signatureKey []byte) (*api.IssueChangeRecord, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	create.Signature = api.GetIssueChangeRecordSignature(signatureKey, create)
+	raw, err := createIssueChangeRecordImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeIssueChangeRecord(ctx, raw)
+}
+
+// GetIssueChangeRecordByIssueID gets the change record of the issue, it returns nil if the record isn't taken yet.
+func (s *Store) GetIssueChangeRecordByIssueID(ctx context.Context, issueID int) (*api.IssueChangeRecord, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findIssueChangeRecordImpl(ctx, tx.PTx, &api.IssueChangeRecordFind{IssueID: &issueID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find change record of issue ID %d, error: %w", issueID, err)
+	}
+	if len(rawList) == 0 {
+		return nil, nil
+	} else if len(rawList) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d change records for issue ID %d, expect 1", len(rawList), issueID)}
+	}
+	return s.composeIssueChangeRecord(ctx, rawList[0])
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeIssueChangeRecord(ctx context.Context, raw *issueChangeRecordRaw) (*api.IssueChangeRecord, error) {
+	record := raw.toIssueChangeRecord()
+
+	creator, err := s.GetPrincipalByID(ctx, record.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	record.Creator = creator
+
+	return record, nil
+}
+
+func createIssueChangeRecordImpl(ctx context.Context, tx *sql.Tx, create *api.IssueChangeRecordCreate) (*issueChangeRecordRaw, error) {
+	query := `
+		INSERT INTO issue_change_record (
+			creator_id,
+			created_ts,
+			issue_id,
+			payload,
+			signature
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, creator_id, created_ts, issue_id, payload, signature
+	`
+	var raw issueChangeRecordRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatedTs,
+		create.IssueID,
+		create.Payload,
+		create.Signature,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.IssueID,
+		&raw.Payload,
+		&raw.Signature,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findIssueChangeRecordImpl(ctx context.Context, tx *sql.Tx, find *api.IssueChangeRecordFind) ([]*issueChangeRecordRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.IssueID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			issue_id,
+			payload,
+			signature
+		FROM issue_change_record
+		WHERE `+strings.Join(where, " AND "),
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*issueChangeRecordRaw
+	for rows.Next() {
+		var raw issueChangeRecordRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.IssueID,
+			&raw.Payload,
+			&raw.Signature,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// issueDependencyRaw is the store model for an IssueDependency.
+// Fields have exactly the same meanings as IssueDependency.
+type issueDependencyRaw struct {
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Domain specific fields
+	BlockingIssueID int
+	BlockedIssueID  int
+}
+
+// toIssueDependency creates an instance of IssueDependency based on the issueDependencyRaw.
+// This is intended to be called when we need to compose an IssueDependency relationship.
+func (raw *issueDependencyRaw) toIssueDependency() *api.IssueDependency {
+	return &api.IssueDependency{
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Domain specific fields
+		BlockingIssueID: raw.BlockingIssueID,
+		BlockedIssueID:  raw.BlockedIssueID,
+	}
+}
+
+// CreateIssueDependency creates an instance of IssueDependency.
+func (s *Store) CreateIssueDependency(ctx context.Context, create *api.IssueDependencyCreate) (*api.IssueDependency, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createIssueDependencyImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IssueDependency with IssueDependencyCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeIssueDependency(ctx, raw)
+}
+
+// FindIssueDependency finds a list of IssueDependency instances.
+func (s *Store) FindIssueDependency(ctx context.Context, find *api.IssueDependencyFind) ([]*api.IssueDependency, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findIssueDependencyImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find IssueDependency list with IssueDependencyFind[%+v], error: %w", find, err)
+	}
+	var dependencyList []*api.IssueDependency
+	for _, raw := range rawList {
+		dependency, err := s.composeIssueDependency(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		dependencyList = append(dependencyList, dependency)
+	}
+	return dependencyList, nil
+}
+
+// DeleteIssueDependency deletes an existing issue dependency.
+func (s *Store) DeleteIssueDependency(ctx context.Context, delete *api.IssueDependencyDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	result, err := tx.PTx.ExecContext(ctx, `DELETE FROM issue_dependency WHERE blocking_issue_id = $1 AND blocked_issue_id = $2`, delete.BlockingIssueID, delete.BlockedIssueID)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: fmt.Errorf("issue %d is not blocked by issue %d", delete.BlockedIssueID, delete.BlockingIssueID)}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeIssueDependency(ctx context.Context, raw *issueDependencyRaw) (*api.IssueDependency, error) {
+	dependency := raw.toIssueDependency()
+
+	creator, err := s.GetPrincipalByID(ctx, dependency.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	dependency.Creator = creator
+
+	return dependency, nil
+}
+
+func createIssueDependencyImpl(ctx context.Context, tx *sql.Tx, create *api.IssueDependencyCreate) (*issueDependencyRaw, error) {
+	query := `
+		INSERT INTO issue_dependency (
+			creator_id,
+			blocking_issue_id,
+			blocked_issue_id
+		)
+		VALUES ($1, $2, $3)
+		RETURNING creator_id, created_ts, blocking_issue_id, blocked_issue_id
+	`
+	var raw issueDependencyRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.BlockingIssueID,
+		create.BlockedIssueID,
+	).Scan(
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.BlockingIssueID,
+		&raw.BlockedIssueID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findIssueDependencyImpl(ctx context.Context, tx *sql.Tx, find *api.IssueDependencyFind) ([]*issueDependencyRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.BlockingIssueID; v != nil {
+		where, args = append(where, fmt.Sprintf("blocking_issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.BlockedIssueID; v != nil {
+		where, args = append(where, fmt.Sprintf("blocked_issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			creator_id,
+			created_ts,
+			blocking_issue_id,
+			blocked_issue_id
+		FROM issue_dependency
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY created_ts ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*issueDependencyRaw
+	for rows.Next() {
+		var raw issueDependencyRaw
+		if err := rows.Scan(
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.BlockingIssueID,
+			&raw.BlockedIssueID,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
@@ -0,0 +1,544 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// issueFieldRaw is the store model for an IssueField.
+// Fields have exactly the same meanings as IssueField.
+type issueFieldRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Name          string
+	Type          api.IssueFieldType
+	EnumValueList []string
+}
+
+// toIssueField creates an instance of IssueField based on the issueFieldRaw.
+// This is intended to be called when we need to compose an IssueField relationship.
+func (raw *issueFieldRaw) toIssueField() *api.IssueField {
+	return &api.IssueField{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID: raw.ProjectID,
+
+		// Domain specific fields
+		Name:          raw.Name,
+		Type:          raw.Type,
+		EnumValueList: raw.EnumValueList,
+	}
+}
+
+// issueFieldValueRaw is the store model for an IssueFieldValue.
+// Fields have exactly the same meanings as IssueFieldValue.
+type issueFieldValueRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	IssueID int
+	FieldID int
+
+	// Domain specific fields
+	Value string
+}
+
+// toIssueFieldValue creates an instance of IssueFieldValue based on the issueFieldValueRaw.
+// This is intended to be called when we need to compose an IssueFieldValue relationship.
+func (raw *issueFieldValueRaw) toIssueFieldValue() *api.IssueFieldValue {
+	return &api.IssueFieldValue{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		IssueID: raw.IssueID,
+		FieldID: raw.FieldID,
+
+		// Domain specific fields
+		Value: raw.Value,
+	}
+}
+
+// CreateIssueField creates an instance of IssueField.
+func (s *Store) CreateIssueField(ctx context.Context, create *api.IssueFieldCreate) (*api.IssueField, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createIssueFieldImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue field with IssueFieldCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeIssueField(ctx, raw)
+}
+
+// GetIssueFieldByID gets an instance of IssueField.
+func (s *Store) GetIssueFieldByID(ctx context.Context, id int) (*api.IssueField, error) {
+	list, err := s.FindIssueField(ctx, &api.IssueFieldFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d issue fields with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// FindIssueField finds a list of IssueField instances in the ascending ID order.
+func (s *Store) FindIssueField(ctx context.Context, find *api.IssueFieldFind) ([]*api.IssueField, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findIssueFieldImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find issue field list with IssueFieldFind[%+v], error: %w", find, err)
+	}
+	var fieldList []*api.IssueField
+	for _, raw := range rawList {
+		field, err := s.composeIssueField(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		fieldList = append(fieldList, field)
+	}
+	return fieldList, nil
+}
+
+// PatchIssueField patches an instance of IssueField.
+func (s *Store) PatchIssueField(ctx context.Context, patch *api.IssueFieldPatch) (*api.IssueField, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchIssueFieldImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch issue field with IssueFieldPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeIssueField(ctx, raw)
+}
+
+// DeleteIssueField deletes an existing issue field by ID, the values of the field are deleted by cascade.
+func (s *Store) DeleteIssueField(ctx context.Context, delete *api.IssueFieldDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM issue_field WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// UpsertIssueFieldValue sets the value of an issue field.
+func (s *Store) UpsertIssueFieldValue(ctx context.Context, upsert *api.IssueFieldValueUpsert) (*api.IssueFieldValue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := upsertIssueFieldValueImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert issue field value with IssueFieldValueUpsert[%+v], error: %w", upsert, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeIssueFieldValue(ctx, raw)
+}
+
+// FindIssueFieldValue finds a list of IssueFieldValue instances in the ascending field ID order.
+func (s *Store) FindIssueFieldValue(ctx context.Context, find *api.IssueFieldValueFind) ([]*api.IssueFieldValue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findIssueFieldValueImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find issue field value list with IssueFieldValueFind[%+v], error: %w", find, err)
+	}
+	var valueList []*api.IssueFieldValue
+	for _, raw := range rawList {
+		value, err := s.composeIssueFieldValue(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		valueList = append(valueList, value)
+	}
+	return valueList, nil
+}
+
+// DeleteIssueFieldValue clears the value of an issue field.
+func (s *Store) DeleteIssueFieldValue(ctx context.Context, delete *api.IssueFieldValueDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM issue_field_value WHERE issue_id = $1 AND field_id = $2`, delete.IssueID, delete.FieldID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeIssueField(ctx context.Context, raw *issueFieldRaw) (*api.IssueField, error) {
+	field := raw.toIssueField()
+
+	creator, err := s.GetPrincipalByID(ctx, field.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	field.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, field.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	field.Updater = updater
+
+	return field, nil
+}
+
+func (s *Store) composeIssueFieldValue(ctx context.Context, raw *issueFieldValueRaw) (*api.IssueFieldValue, error) {
+	value := raw.toIssueFieldValue()
+
+	creator, err := s.GetPrincipalByID(ctx, value.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	value.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, value.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	value.Updater = updater
+
+	return value, nil
+}
+
+func createIssueFieldImpl(ctx context.Context, tx *sql.Tx, create *api.IssueFieldCreate) (*issueFieldRaw, error) {
+	enumValueList := create.EnumValueList
+	if enumValueList == nil {
+		enumValueList = []string{}
+	}
+	query := `
+		INSERT INTO issue_field (
+			creator_id,
+			updater_id,
+			project_id,
+			name,
+			type,
+			enum_value_list
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, name, type, enum_value_list
+	`
+	var raw issueFieldRaw
+	var txtArray pgtype.TextArray
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.ProjectID,
+		create.Name,
+		create.Type,
+		enumValueList,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.Name,
+		&raw.Type,
+		&txtArray,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	if err := txtArray.AssignTo(&raw.EnumValueList); err != nil {
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findIssueFieldImpl(ctx context.Context, tx *sql.Tx, find *api.IssueFieldFind) ([]*issueFieldRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			name,
+			type,
+			enum_value_list
+		FROM issue_field
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*issueFieldRaw
+	for rows.Next() {
+		var raw issueFieldRaw
+		var txtArray pgtype.TextArray
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.ProjectID,
+			&raw.Name,
+			&raw.Type,
+			&txtArray,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if err := txtArray.AssignTo(&raw.EnumValueList); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchIssueFieldImpl(ctx context.Context, tx *sql.Tx, patch *api.IssueFieldPatch) (*issueFieldRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.EnumValueList; v != nil {
+		enumValueList := []string{}
+		if *v != "" {
+			enumValueList = strings.Split(*v, ",")
+		}
+		set, args = append(set, fmt.Sprintf("enum_value_list = $%d", len(args)+1)), append(args, enumValueList)
+	}
+	args = append(args, patch.ID)
+
+	var raw issueFieldRaw
+	var txtArray pgtype.TextArray
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE issue_field
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, name, type, enum_value_list
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.Name,
+		&raw.Type,
+		&txtArray,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("issue field not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	if err := txtArray.AssignTo(&raw.EnumValueList); err != nil {
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func upsertIssueFieldValueImpl(ctx context.Context, tx *sql.Tx, upsert *api.IssueFieldValueUpsert) (*issueFieldValueRaw, error) {
+	query := `
+		INSERT INTO issue_field_value (
+			creator_id,
+			updater_id,
+			issue_id,
+			field_id,
+			value
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(issue_id, field_id) DO UPDATE SET
+			updater_id = excluded.updater_id,
+			value = excluded.value
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, issue_id, field_id, value
+	`
+	var raw issueFieldValueRaw
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.IssueID,
+		upsert.FieldID,
+		upsert.Value,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.IssueID,
+		&raw.FieldID,
+		&raw.Value,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findIssueFieldValueImpl(ctx context.Context, tx *sql.Tx, find *api.IssueFieldValueFind) ([]*issueFieldValueRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.IssueID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.FieldID; v != nil {
+		where, args = append(where, fmt.Sprintf("field_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			issue_id,
+			field_id,
+			value
+		FROM issue_field_value
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY field_id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*issueFieldValueRaw
+	for rows.Next() {
+		var raw issueFieldValueRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.IssueID,
+			&raw.FieldID,
+			&raw.Value,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
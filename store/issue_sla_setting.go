@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// issueSLASettingRaw is the store model for an IssueSLASetting.
+// Fields have exactly the same meanings as IssueSLASetting.
+type issueSLASettingRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	TimeToApprove     int64
+	TimeToExecute     int64
+	ReminderThreshold int
+}
+
+// toIssueSLASetting creates an instance of IssueSLASetting based on the issueSLASettingRaw.
+// This is intended to be called when we need to compose an IssueSLASetting relationship.
+func (raw *issueSLASettingRaw) toIssueSLASetting() *api.IssueSLASetting {
+	return &api.IssueSLASetting{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID: raw.ProjectID,
+
+		// Domain specific fields
+		TimeToApprove:     raw.TimeToApprove,
+		TimeToExecute:     raw.TimeToExecute,
+		ReminderThreshold: raw.ReminderThreshold,
+	}
+}
+
+// UpsertIssueSLASetting upserts the issue SLA setting of a project.
+func (s *Store) UpsertIssueSLASetting(ctx context.Context, upsert *api.IssueSLASettingUpsert) (*api.IssueSLASetting, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := upsertIssueSLASettingImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert issue SLA setting with IssueSLASettingUpsert[%+v], error: %w", upsert, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeIssueSLASetting(ctx, raw)
+}
+
+// GetIssueSLASettingByProjectID gets the issue SLA setting of a project.
+// Returns nil if the project has no setting.
+func (s *Store) GetIssueSLASettingByProjectID(ctx context.Context, projectID int) (*api.IssueSLASetting, error) {
+	list, err := s.FindIssueSLASetting(ctx, &api.IssueSLASettingFind{ProjectID: &projectID})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d issue SLA settings with project ID %d, expect 1", len(list), projectID)}
+	}
+	return list[0], nil
+}
+
+// FindIssueSLASetting finds a list of IssueSLASetting instances.
+func (s *Store) FindIssueSLASetting(ctx context.Context, find *api.IssueSLASettingFind) ([]*api.IssueSLASetting, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findIssueSLASettingImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find issue SLA setting list with IssueSLASettingFind[%+v], error: %w", find, err)
+	}
+	var settingList []*api.IssueSLASetting
+	for _, raw := range rawList {
+		setting, err := s.composeIssueSLASetting(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		settingList = append(settingList, setting)
+	}
+	return settingList, nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeIssueSLASetting(ctx context.Context, raw *issueSLASettingRaw) (*api.IssueSLASetting, error) {
+	setting := raw.toIssueSLASetting()
+
+	creator, err := s.GetPrincipalByID(ctx, setting.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	setting.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, setting.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	setting.Updater = updater
+
+	return setting, nil
+}
+
+func upsertIssueSLASettingImpl(ctx context.Context, tx *sql.Tx, upsert *api.IssueSLASettingUpsert) (*issueSLASettingRaw, error) {
+	query := `
+		INSERT INTO issue_sla_setting (
+			creator_id,
+			updater_id,
+			project_id,
+			time_to_approve,
+			time_to_execute,
+			reminder_threshold
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT(project_id) DO UPDATE SET
+				updater_id = EXCLUDED.updater_id,
+				time_to_approve = EXCLUDED.time_to_approve,
+				time_to_execute = EXCLUDED.time_to_execute,
+				reminder_threshold = EXCLUDED.reminder_threshold
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, time_to_approve, time_to_execute, reminder_threshold
+	`
+	var raw issueSLASettingRaw
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.ProjectID,
+		upsert.TimeToApprove,
+		upsert.TimeToExecute,
+		upsert.ReminderThreshold,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.TimeToApprove,
+		&raw.TimeToExecute,
+		&raw.ReminderThreshold,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findIssueSLASettingImpl(ctx context.Context, tx *sql.Tx, find *api.IssueSLASettingFind) ([]*issueSLASettingRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			time_to_approve,
+			time_to_execute,
+			reminder_threshold
+		FROM issue_sla_setting
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*issueSLASettingRaw
+	for rows.Next() {
+		var raw issueSLASettingRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.ProjectID,
+			&raw.TimeToApprove,
+			&raw.TimeToExecute,
+			&raw.ReminderThreshold,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
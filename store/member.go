@@ -27,6 +27,7 @@ type memberRaw struct {
 	Status      api.MemberStatus
 	Role        api.Role
 	PrincipalID int
+	LegalHold   bool
 }
 
 // toMember creates an instance of Member based on the memberRaw.
@@ -46,6 +47,7 @@ func (raw *memberRaw) toMember() *api.Member {
 		Status:      raw.Status,
 		Role:        raw.Role,
 		PrincipalID: raw.PrincipalID,
+		LegalHold:   raw.LegalHold,
 	}
 }
 
@@ -307,7 +309,7 @@ func createMemberImpl(ctx context.Context, tx *sql.Tx, create *api.MemberCreate)
 			principal_id
 		)
 		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, status, role, principal_id
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, status, role, principal_id, legal_hold
 	`
 	var memberRaw memberRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -326,6 +328,7 @@ func createMemberImpl(ctx context.Context, tx *sql.Tx, create *api.MemberCreate)
 		&memberRaw.Status,
 		&memberRaw.Role,
 		&memberRaw.PrincipalID,
+		&memberRaw.LegalHold,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -358,7 +361,8 @@ func findMemberImpl(ctx context.Context, tx *sql.Tx, find *api.MemberFind) ([]*m
 			updated_ts,
 			status,
 			role,
-			principal_id
+			principal_id,
+			legal_hold
 		FROM member
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -382,6 +386,7 @@ func findMemberImpl(ctx context.Context, tx *sql.Tx, find *api.MemberFind) ([]*m
 			&memberRaw.Status,
 			&memberRaw.Role,
 			&memberRaw.PrincipalID,
+			&memberRaw.LegalHold,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -405,6 +410,9 @@ func patchMemberImpl(ctx context.Context, tx *sql.Tx, patch *api.MemberPatch) (*
 	if v := patch.Role; v != nil {
 		set, args = append(set, fmt.Sprintf("role = $%d", len(args)+1)), append(args, api.Role(*v))
 	}
+	if v := patch.LegalHold; v != nil {
+		set, args = append(set, fmt.Sprintf("legal_hold = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -414,7 +422,7 @@ func patchMemberImpl(ctx context.Context, tx *sql.Tx, patch *api.MemberPatch) (*
 		UPDATE member
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, status, role, principal_id
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, status, role, principal_id, legal_hold
 	`, len(args)),
 		args...,
 	).Scan(
@@ -427,6 +435,7 @@ func patchMemberImpl(ctx context.Context, tx *sql.Tx, patch *api.MemberPatch) (*
 		&memberRaw.Status,
 		&memberRaw.Role,
 		&memberRaw.PrincipalID,
+		&memberRaw.LegalHold,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("member ID not found: %d", patch.ID)}
@@ -0,0 +1,283 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// redactedValue replaces the values of the sensitive columns in the metadata query result.
+const redactedValue = "******"
+
+var (
+	// sensitiveColumnRegexp matches the columns keeping the credentials, e.g. data_source.password, principal.password_hash,
+	// vcs.secret and repository.access_token.
+	sensitiveColumnRegexp = regexp.MustCompile(`(?i)(password|secret|token|ssl_key)`)
+	// compositeTypeRegexp matches the type names of the whole-row values, e.g. SELECT d FROM data_source d.
+	// The driver reports the OID for the row types of the tables as they aren't registered.
+	compositeTypeRegexp = regexp.MustCompile(`^(RECORD|\d+)$`)
+	// metadataQueryTableAllowList is the tables the metadata query can read. The tables keeping the credentials, e.g. principal,
+	// setting, data_source, vcs, repository and agent, and the system catalogs aren't on the list, so they can't be read in any form,
+	// e.g. SELECT d::text FROM data_source d.
+	metadataQueryTableAllowList = map[string]bool{
+		"access_change_history": true,
+		"account_report":        true,
+		"activity":              true,
+		"anomaly":               true,
+		"backup":                true,
+		"bookmark":              true,
+		"change_set":            true,
+		"col":                   true,
+		"database_template":     true,
+		"db":                    true,
+		"db_assignment_rule":    true,
+		"db_extension":          true,
+		"db_function":           true,
+		"db_label":              true,
+		"db_role_mapping":       true,
+		"deployment_config":     true,
+		"environment":           true,
+		"fk":                    true,
+		"idx":                   true,
+		"inbox":                 true,
+		"instance":              true,
+		"instance_parameter":    true,
+		"instance_sync_setting": true,
+		"instance_user":         true,
+		"issue":                 true,
+		"issue_attachment":      true,
+		"issue_dependency":      true,
+		"issue_field":           true,
+		"issue_field_value":     true,
+		"issue_sla_setting":     true,
+		"issue_subscriber":      true,
+		"label_key":             true,
+		"label_value":           true,
+		"member":                true,
+		"partition_policy":      true,
+		"pipeline":              true,
+		"policy":                true,
+		"project":               true,
+		"project_member":        true,
+		"recurring_issue":       true,
+		"schema_description":    true,
+		"schema_doc_setting":    true,
+		"schema_snapshot":       true,
+		"sheet":                 true,
+		"sheet_organizer":       true,
+		"sheet_version":         true,
+		"stage":                 true,
+		"task":                  true,
+		"task_check_run":        true,
+		"task_dag":              true,
+		"task_run":              true,
+		"tbl":                   true,
+		"trash":                 true,
+		"usage_metric":          true,
+		"vw":                    true,
+	}
+	// metadataQueryFunctionAllowList is the functions the metadata query can call, along with the keywords and the type names
+	// followed by the parenthesis in the expressions of the plan, e.g. FILTER (WHERE ...) and character varying(64).
+	// The functions running the statements, e.g. query_to_xml and ts_stat, reading the files, e.g. pg_read_file,
+	// or touching the server, e.g. set_config and pg_sleep, aren't on the list.
+	metadataQueryFunctionAllowList = map[string]bool{
+		// Keywords.
+		"all": true, "and": true, "any": true, "array": true, "by": true, "case": true, "else": true, "exists": true,
+		"filter": true, "from": true, "group": true, "in": true, "not": true, "or": true, "over": true, "row": true,
+		"then": true, "values": true, "when": true, "where": true,
+		// Type names.
+		"bit": true, "char": true, "character": true, "decimal": true, "interval": true, "numeric": true, "time": true,
+		"timestamp": true, "varchar": true, "varying": true,
+		// Conditional expressions.
+		"coalesce": true, "greatest": true, "least": true, "nullif": true,
+		// Aggregate and window functions.
+		"array_agg": true, "avg": true, "bool_and": true, "bool_or": true, "count": true, "cume_dist": true,
+		"dense_rank": true, "every": true, "first_value": true, "json_agg": true, "json_object_agg": true,
+		"jsonb_agg": true, "jsonb_object_agg": true, "lag": true, "last_value": true, "lead": true, "max": true,
+		"min": true, "mode": true, "nth_value": true, "ntile": true, "percent_rank": true, "percentile_cont": true,
+		"percentile_disc": true, "rank": true, "row_number": true, "string_agg": true, "sum": true,
+		// String functions.
+		"array_to_string": true, "btrim": true, "char_length": true, "character_length": true, "concat": true,
+		"concat_ws": true, "format": true, "initcap": true, "left": true, "length": true, "lower": true, "lpad": true,
+		"ltrim": true, "md5": true, "octet_length": true, "position": true, "regexp_match": true,
+		"regexp_matches": true, "regexp_replace": true, "regexp_split_to_array": true, "regexp_split_to_table": true,
+		"repeat": true, "replace": true, "reverse": true, "right": true, "rpad": true, "rtrim": true,
+		"split_part": true, "starts_with": true, "string_to_array": true, "strpos": true, "substr": true,
+		"substring": true, "to_char": true, "to_number": true, "trim": true, "upper": true,
+		// Date and time functions.
+		"age": true, "date_part": true, "date_trunc": true, "extract": true, "make_date": true,
+		"make_interval": true, "make_timestamp": true, "now": true, "timezone": true, "to_date": true,
+		"to_timestamp": true,
+		// Math functions.
+		"abs": true, "ceil": true, "ceiling": true, "div": true, "floor": true, "mod": true, "power": true,
+		"round": true, "sign": true, "sqrt": true, "trunc": true,
+		// Array functions.
+		"array_append": true, "array_cat": true, "array_length": true, "array_lower": true, "array_position": true,
+		"array_positions": true, "array_remove": true, "array_upper": true, "cardinality": true,
+		"generate_series": true, "unnest": true,
+		// JSON functions.
+		"json_array_elements": true, "json_array_elements_text": true, "json_array_length": true,
+		"json_build_array": true, "json_build_object": true, "json_each": true, "json_each_text": true,
+		"json_extract_path": true, "json_extract_path_text": true, "json_object_keys": true, "json_typeof": true,
+		"jsonb_array_elements": true, "jsonb_array_elements_text": true, "jsonb_array_length": true,
+		"jsonb_build_array": true, "jsonb_build_object": true, "jsonb_each": true, "jsonb_each_text": true,
+		"jsonb_extract_path": true, "jsonb_extract_path_text": true, "jsonb_object_keys": true, "jsonb_pretty": true,
+		"jsonb_typeof": true, "row_to_json": true, "to_json": true, "to_jsonb": true,
+	}
+	// metadataQueryTokenRegexp matches the string literals and the identifiers in the expressions of the plan, the identifier followed
+	// by the parenthesis is a function call. The expressions are deparsed by the server, so the functions show the actual names
+	// no matter how they are written in the query, e.g. U&"pg\005fread\005ffile"(...) is deparsed as pg_read_file(...).
+	// The literals and the quoted identifiers are matched as a whole, so the quotes in one can't hide the calls after it.
+	metadataQueryTokenRegexp = regexp.MustCompile(`'(?:[^']|'')*'|("(?:[^"]|"")+"|[A-Za-z_][A-Za-z0-9_$]*)(\s*\()?`)
+)
+
+// QueryMetadata runs the read-only query against the Bytebase metadata database, it's for the support and debugging.
+// The query can only read the tables and call the functions on the allow lists, and the values of the sensitive columns are redacted.
+func (s *Store) QueryMetadata(ctx context.Context, statement string, limit int) ([]interface{}, error) {
+	tx, err := s.db.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	plan, err := explainMetadataQuery(ctx, tx, statement)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMetadataQueryPlan(plan); err != nil {
+		return nil, err
+	}
+
+	result, err := util.QueryTx(ctx, tx, statement, limit)
+	if err != nil {
+		return nil, err
+	}
+	redactMetadataQueryResult(result)
+	return result, nil
+}
+
+// explainMetadataQuery returns the plan of the statement. The statement is prepared, so it can't contain multiple statements.
+func explainMetadataQuery(ctx context.Context, tx *sql.Tx, statement string) (string, error) {
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("EXPLAIN (VERBOSE, FORMAT JSON) %s", strings.TrimRight(strings.TrimSpace(statement), ";")))
+	if err != nil {
+		return "", util.FormatErrorWithQuery(err, statement)
+	}
+	defer stmt.Close()
+
+	var plan string
+	if err := stmt.QueryRowContext(ctx).Scan(&plan); err != nil {
+		return "", util.FormatErrorWithQuery(err, statement)
+	}
+	return plan, nil
+}
+
+// validateMetadataQueryPlan returns an Invalid error if the plan reads any table or calls any function not on the allow lists.
+// The views are expanded to their tables in the plan, and so are the tables referenced in any form, e.g. the casts of the whole rows.
+// The functions are checked in all the expressions of the plan, including the ones running the queries not in the plan, e.g. ts_stat.
+func validateMetadataQueryPlan(plan string) error {
+	var explainList []struct {
+		Plan map[string]interface{} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explainList); err != nil {
+		return fmt.Errorf("failed to unmarshal metadata query plan, error: %w", err)
+	}
+
+	var nodeList []map[string]interface{}
+	for _, explain := range explainList {
+		nodeList = append(nodeList, explain.Plan)
+	}
+	for len(nodeList) > 0 {
+		node := nodeList[0]
+		nodeList = nodeList[1:]
+		if node == nil {
+			continue
+		}
+		if relationName, ok := node["Relation Name"].(string); ok {
+			schema, _ := node["Schema"].(string)
+			if schema != "public" || !metadataQueryTableAllowList[relationName] {
+				return common.Errorf(common.Invalid, "table %q is not allowed in the metadata query", fmt.Sprintf("%s.%s", schema, relationName))
+			}
+		}
+		for key, value := range node {
+			if key == "Plans" {
+				childList, _ := value.([]interface{})
+				for _, child := range childList {
+					childNode, _ := child.(map[string]interface{})
+					nodeList = append(nodeList, childNode)
+				}
+				continue
+			}
+			if err := validateMetadataQueryExpression(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateMetadataQueryExpression returns an Invalid error if the expression, or any expression in the list, calls a function not on the allow list.
+func validateMetadataQueryExpression(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		for _, match := range metadataQueryTokenRegexp.FindAllStringSubmatch(v, -1) {
+			name, isCall := match[1], match[2] != ""
+			if !isCall {
+				continue
+			}
+			// The quoted identifiers are case sensitive, e.g. "substring"(...) as it's a keyword.
+			if strings.HasPrefix(name, `"`) {
+				name = strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+			} else {
+				name = strings.ToLower(name)
+			}
+			if !metadataQueryFunctionAllowList[name] {
+				return common.Errorf(common.Invalid, "function %q is not allowed in the metadata query", name)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := validateMetadataQueryExpression(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// redactMetadataQueryResult redacts the values of the sensitive columns and the whole-row values in the result of util.Query in place.
+func redactMetadataQueryResult(result []interface{}) {
+	if len(result) != 3 {
+		return
+	}
+	columnNames, ok := result[0].([]string)
+	if !ok {
+		return
+	}
+	columnTypeNames, _ := result[1].([]string)
+	data, ok := result[2].([]interface{})
+	if !ok {
+		return
+	}
+	for i, name := range columnNames {
+		sensitive := sensitiveColumnRegexp.MatchString(strings.TrimSpace(name))
+		if i < len(columnTypeNames) && compositeTypeRegexp.MatchString(columnTypeNames[i]) {
+			sensitive = true
+		}
+		if !sensitive {
+			continue
+		}
+		for _, row := range data {
+			rowData, ok := row.([]interface{})
+			if !ok || i >= len(rowData) || rowData[i] == nil {
+				continue
+			}
+			rowData[i] = redactedValue
+		}
+	}
+}
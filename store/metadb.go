@@ -1,10 +1,14 @@
 package store
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"io"
 	"net"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
@@ -80,17 +84,28 @@ func (m *MetadataDB) connectEmbed(datastorePort int, pgUser string, readonly boo
 
 // connectExternal returns an instance of store.DB.
 func (m *MetadataDB) connectExternal(readonly bool, version string) (*DB, error) {
-	u, err := url.Parse(m.pgURL)
+	connCfg, err := getExternalConnectionConfig(m.pgURL)
 	if err != nil {
 		return nil, err
 	}
 
+	db := NewDB(connCfg, m.pgInstance.BaseDir, m.demoDataDir, readonly, version, m.mode)
+	return db, nil
+}
+
+// getExternalConnectionConfig returns the connection config of the external Postgres connection url.
+func getExternalConnectionConfig(pgURL string) (dbdriver.ConnectionConfig, error) {
+	u, err := url.Parse(pgURL)
+	if err != nil {
+		return dbdriver.ConnectionConfig{}, err
+	}
+
 	q := u.Query()
 
 	log.Info("Establishing external PostgreSQL connection...", zap.String("pgURL", u.Redacted()))
 
 	if u.Scheme != "postgresql" {
-		return nil, fmt.Errorf("invalid connection protocol: %s", u.Scheme)
+		return dbdriver.ConnectionConfig{}, fmt.Errorf("invalid connection protocol: %s", u.Scheme)
 	}
 
 	connCfg := dbdriver.ConnectionConfig{
@@ -103,7 +118,7 @@ func (m *MetadataDB) connectExternal(readonly bool, version string) (*DB, error)
 	}
 
 	if connCfg.Username == "" {
-		return nil, fmt.Errorf("missing user in the --pg connection string")
+		return dbdriver.ConnectionConfig{}, fmt.Errorf("missing user in the --pg connection string")
 	}
 
 	if host, port, err := net.SplitHostPort(u.Host); err != nil {
@@ -120,7 +135,7 @@ func (m *MetadataDB) connectExternal(readonly bool, version string) (*DB, error)
 		hostInQuery := q.Get("host")
 		if hostInQuery != "" && host != "" {
 			// In this case, it is impossible to decide whether to use socket or tcp.
-			return nil, fmt.Errorf("please only using socket or host instead of both")
+			return dbdriver.ConnectionConfig{}, fmt.Errorf("please only using socket or host instead of both")
 		}
 		connCfg.Host = host
 		if hostInQuery != "" {
@@ -130,7 +145,7 @@ func (m *MetadataDB) connectExternal(readonly bool, version string) (*DB, error)
 	}
 
 	if u.Path == "" {
-		return nil, fmt.Errorf("missing database in the --pg connection string")
+		return dbdriver.ConnectionConfig{}, fmt.Errorf("missing database in the --pg connection string")
 	}
 	connCfg.Database = u.Path[1:]
 
@@ -139,9 +154,7 @@ func (m *MetadataDB) connectExternal(readonly bool, version string) (*DB, error)
 		SslKey:  q.Get("sslkey"),
 		SslCert: q.Get("sslcert"),
 	}
-
-	db := NewDB(connCfg, m.pgInstance.BaseDir, m.demoDataDir, readonly, version, m.mode)
-	return db, nil
+	return connCfg, nil
 }
 
 // Close will stop postgres server if using embed postgres.
@@ -157,3 +170,125 @@ func (m *MetadataDB) Close() error {
 	m.pgStarted = false
 	return nil
 }
+
+// migrationHistoryColumnList is the columns of the migration history copied to the external Postgres, the ID is generated by the external Postgres.
+var migrationHistoryColumnList = []string{
+	"created_by", "created_ts", "updated_by", "updated_ts", "release_version", "namespace", "sequence", "source", "type",
+	"status", "version", "description", "statement", "schema", "schema_prev", "execution_duration_ns", "issue_id", "payload",
+}
+
+// MigrateToExternal copies the metadata in the embedded Postgres to the database of the external Postgres connection url.
+// The embedded Postgres is started on the datastore port, and the external database must be empty.
+// The migration history of the metadata is copied as well, so that Bytebase can start with the --pg flag pointing to the external database afterwards.
+func (m *MetadataDB) MigrateToExternal(ctx context.Context, datastorePort int, pgURL string) error {
+	if !m.embed {
+		return fmt.Errorf("metadata is not stored in the embedded PostgreSQL")
+	}
+	targetConnCfg, err := getExternalConnectionConfig(pgURL)
+	if err != nil {
+		return err
+	}
+
+	if err := m.pgInstance.Start(datastorePort, os.Stderr, os.Stderr); err != nil {
+		return err
+	}
+	m.pgStarted = true
+	sourceConnCfg := dbdriver.ConnectionConfig{
+		Username: m.pgUser,
+		Password: "",
+		Host:     common.GetPostgresSocketDir(),
+		Port:     fmt.Sprintf("%d", datastorePort),
+	}
+	driverCfg := dbdriver.DriverConfig{PgInstanceDir: m.pgInstance.BaseDir}
+	source, err := dbdriver.Open(ctx, dbdriver.Postgres, driverCfg, sourceConnCfg, dbdriver.ConnectionContext{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to the embedded PostgreSQL, error: %w", err)
+	}
+	defer source.Close(ctx)
+	target, err := dbdriver.Open(ctx, dbdriver.Postgres, driverCfg, targetConnCfg, dbdriver.ConnectionContext{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to the external PostgreSQL, error: %w", err)
+	}
+	defer target.Close(ctx)
+
+	targetDB, err := target.GetDBConnection(ctx, targetConnCfg.Database)
+	if err != nil {
+		return err
+	}
+	var tableCount int
+	if err := targetDB.QueryRowContext(ctx, "SELECT COUNT(1) FROM information_schema.tables WHERE table_schema NOT IN ('pg_catalog', 'information_schema')").Scan(&tableCount); err != nil {
+		return fmt.Errorf("failed to check the external database %q, error: %w", targetConnCfg.Database, err)
+	}
+	if tableCount > 0 {
+		return fmt.Errorf("external database %q is not empty, it has %d table(s)", targetConnCfg.Database, tableCount)
+	}
+
+	// The metadata database is named after the user, and the dump is restored in a single transaction.
+	log.Info("Copying the metadata to the external PostgreSQL...", zap.String("database", targetConnCfg.Database))
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := source.Dump(ctx, m.pgUser, writer, false /* schemaOnly */)
+		writer.CloseWithError(err)
+	}()
+	if err := target.Restore(ctx, reader); err != nil {
+		reader.CloseWithError(err)
+		return fmt.Errorf("failed to copy the metadata, error: %w", err)
+	}
+
+	log.Info("Copying the migration history to the external PostgreSQL...")
+	if err := target.SetupMigrationIfNeeded(ctx); err != nil {
+		return err
+	}
+	sourceDB, err := source.GetDBConnection(ctx, dbdriver.BytebaseDatabase)
+	if err != nil {
+		return err
+	}
+	if err := copyMigrationHistory(ctx, sourceDB, targetDB, m.pgUser, targetConnCfg.Database); err != nil {
+		return fmt.Errorf("failed to copy the migration history, error: %w", err)
+	}
+	log.Info("Successfully migrated the metadata to the external PostgreSQL.", zap.String("database", targetConnCfg.Database))
+	return nil
+}
+
+// copyMigrationHistory copies the migration history of the source namespace to the target namespace.
+func copyMigrationHistory(ctx context.Context, sourceDB, targetDB *sql.DB, sourceNamespace, targetNamespace string) error {
+	columns := strings.Join(migrationHistoryColumnList, ", ")
+	rows, err := sourceDB.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM migration_history WHERE namespace = $1 ORDER BY id", columns), sourceNamespace)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tx, err := targetDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var placeholders []string
+	for i := range migrationHistoryColumnList {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+	}
+	insert := fmt.Sprintf("INSERT INTO migration_history (%s) VALUES (%s)", columns, strings.Join(placeholders, ", "))
+	for rows.Next() {
+		values := make([]interface{}, len(migrationHistoryColumnList))
+		pointers := make([]interface{}, len(migrationHistoryColumnList))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		for i, column := range migrationHistoryColumnList {
+			if column == "namespace" {
+				values[i] = targetNamespace
+			}
+		}
+		if _, err := tx.ExecContext(ctx, insert, values...); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
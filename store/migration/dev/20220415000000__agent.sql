@@ -0,0 +1,52 @@
+-- agent stores the runner agents deployed inside the private networks.
+CREATE TABLE agent (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    name TEXT NOT NULL,
+    token TEXT NOT NULL,
+    version TEXT NOT NULL DEFAULT '',
+    last_heartbeat_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_agent_unique_name ON agent(name);
+
+CREATE UNIQUE INDEX idx_agent_unique_token ON agent(token);
+
+ALTER SEQUENCE agent_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_agent_updated_ts
+BEFORE
+UPDATE
+    ON agent FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+ALTER TABLE instance ADD agent_id INTEGER REFERENCES agent (id);
+
+-- agent_task is the work queue polled by the runner agents.
+CREATE TABLE agent_task (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    agent_id INTEGER NOT NULL REFERENCES agent (id),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    type TEXT NOT NULL CHECK (type LIKE 'bb.agent-task.%'),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'DONE', 'FAILED')),
+    payload JSONB NOT NULL DEFAULT '{}',
+    result JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_agent_task_agent_id_status ON agent_task(agent_id, status);
+
+ALTER SEQUENCE agent_task_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_agent_task_updated_ts
+BEFORE
+UPDATE
+    ON agent_task FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,27 @@
+-- data_source_rotation stores the credential rotations of the data sources.
+-- The new credential is staged and validated first, then switched over when the rotation is committed.
+CREATE TABLE data_source_rotation (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    data_source_id INTEGER NOT NULL REFERENCES data_source (id),
+    status TEXT NOT NULL CHECK (status IN ('VALIDATED', 'FAILED', 'COMMITTED', 'CANCELED')),
+    username TEXT NOT NULL,
+    password TEXT NOT NULL,
+    ssl_key TEXT NOT NULL DEFAULT '',
+    ssl_cert TEXT NOT NULL DEFAULT '',
+    ssl_ca TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_data_source_rotation_data_source_id ON data_source_rotation(data_source_id);
+
+ALTER SEQUENCE data_source_rotation_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_data_source_rotation_updated_ts
+BEFORE
+UPDATE
+    ON data_source_rotation FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,24 @@
+-- db_assignment_rule stores the rules assigning the newly synced databases to the projects.
+CREATE TABLE db_assignment_rule (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    -- Empty instance_id matches databases on any instance.
+    instance_id INTEGER REFERENCES instance (id),
+    -- Empty name_pattern matches any database name.
+    name_pattern TEXT NOT NULL DEFAULT '',
+    label_selector JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_db_assignment_rule_project_id ON db_assignment_rule(project_id);
+
+ALTER SEQUENCE db_assignment_rule_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_assignment_rule_updated_ts
+BEFORE
+UPDATE
+    ON db_assignment_rule FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,5 @@
+-- not_found_ts records when the database was first found missing from the instance.
+ALTER TABLE db ADD not_found_ts BIGINT NOT NULL DEFAULT 0;
+
+-- The missing databases had last_successful_sync_ts updated when they were marked as NOT_FOUND.
+UPDATE db SET not_found_ts = last_successful_sync_ts WHERE sync_status = 'NOT_FOUND';
@@ -0,0 +1,28 @@
+-- owner is the database role owning the table, only collected for Postgres.
+ALTER TABLE tbl ADD owner TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_tbl_owner ON tbl(owner);
+
+-- db_role_mapping maps the database roles of an instance to the Bytebase principals.
+CREATE TABLE db_role_mapping (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    role_name TEXT NOT NULL,
+    principal_id INTEGER NOT NULL REFERENCES principal (id)
+);
+
+CREATE UNIQUE INDEX idx_db_role_mapping_unique_instance_id_role_name ON db_role_mapping(instance_id, role_name);
+
+CREATE INDEX idx_db_role_mapping_principal_id ON db_role_mapping(principal_id);
+
+ALTER SEQUENCE db_role_mapping_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_role_mapping_updated_ts
+BEFORE
+UPDATE
+    ON db_role_mapping FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,27 @@
+-- schema_doc_setting stores the branding and the publish schedule of the schema documentation for a project.
+CREATE TABLE schema_doc_setting (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    title TEXT NOT NULL DEFAULT '',
+    logo_url TEXT NOT NULL DEFAULT '',
+    footer TEXT NOT NULL DEFAULT '',
+    theme_color TEXT NOT NULL DEFAULT '',
+    publish_schedule TEXT NOT NULL CHECK (publish_schedule IN ('DISABLED', 'DAILY', 'WEEKLY')) DEFAULT 'DISABLED',
+    publish_format TEXT NOT NULL CHECK (publish_format IN ('MARKDOWN', 'HTML', 'PDF')) DEFAULT 'MARKDOWN',
+    publish_url TEXT NOT NULL DEFAULT '',
+    last_published_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_schema_doc_setting_unique_project_id ON schema_doc_setting(project_id);
+
+ALTER SEQUENCE schema_doc_setting_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_doc_setting_updated_ts
+BEFORE
+UPDATE
+    ON schema_doc_setting FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,24 @@
+-- schema_description stores the logical descriptions of the tables and the columns, a.k.a. the data dictionary.
+-- They're keyed by the names since the synced table and column rows may be recreated.
+CREATE TABLE schema_description (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    table_name TEXT NOT NULL,
+    -- Empty column_name is the description of the table.
+    column_name TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_schema_description_unique_database_id_table_name_column_name ON schema_description(database_id, table_name, column_name);
+
+ALTER SEQUENCE schema_description_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_description_updated_ts
+BEFORE
+UPDATE
+    ON schema_description FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
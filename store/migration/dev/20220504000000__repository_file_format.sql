@@ -0,0 +1,2 @@
+-- file_format is the naming convention and the content format of the migration files, so the existing Flyway, Liquibase and golang-migrate files can be used as is.
+ALTER TABLE repository ADD file_format TEXT NOT NULL CHECK (file_format IN ('BYTEBASE', 'FLYWAY', 'LIQUIBASE', 'GOLANG_MIGRATE')) DEFAULT 'BYTEBASE';
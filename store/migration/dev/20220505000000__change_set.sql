@@ -0,0 +1,23 @@
+-- change_set marks the pipeline whose tasks must be applied to several databases together.
+-- None of the tasks executes until all tasks pass the task checks, and the pipeline halts with a compensation plan if a task fails.
+CREATE TABLE change_set (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    pipeline_id INTEGER NOT NULL REFERENCES pipeline (id),
+    status TEXT NOT NULL CHECK (status IN ('VERIFYING', 'RUNNING', 'HALTED', 'DONE')) DEFAULT 'VERIFYING',
+    -- The compensation plan is recorded in the payload when the change set halts.
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_change_set_unique_pipeline_id ON change_set(pipeline_id);
+
+ALTER SEQUENCE change_set_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_change_set_updated_ts
+BEFORE
+UPDATE
+    ON change_set FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,24 @@
+-- issue_sla_setting stores the time to approve and the time to execute the issues in a project.
+CREATE TABLE issue_sla_setting (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    -- The SLA in seconds, 0 means no SLA.
+    time_to_approve BIGINT NOT NULL CHECK (time_to_approve >= 0) DEFAULT 0,
+    time_to_execute BIGINT NOT NULL CHECK (time_to_execute >= 0) DEFAULT 0,
+    -- The percentage of the SLA elapsed before reminding the assignee.
+    reminder_threshold INTEGER NOT NULL CHECK (reminder_threshold > 0 AND reminder_threshold <= 100) DEFAULT 80
+);
+
+CREATE UNIQUE INDEX idx_issue_sla_setting_unique_project_id ON issue_sla_setting(project_id);
+
+ALTER SEQUENCE issue_sla_setting_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_sla_setting_updated_ts
+BEFORE
+UPDATE
+    ON issue_sla_setting FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,2 @@
+-- pinned_note is shown on top of the project pages, e.g. the maintenance notice.
+ALTER TABLE project ADD pinned_note TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- idempotency_key is the client token of the creation request, retrying the request with the same key returns the created issue.
+ALTER TABLE issue ADD idempotency_key TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_issue_unique_creator_id_idempotency_key ON issue(creator_id, idempotency_key) WHERE idempotency_key != '';
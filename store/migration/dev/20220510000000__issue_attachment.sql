@@ -0,0 +1,24 @@
+-- issue_attachment stores the files attached to the issues, the file contents are saved in the blob store.
+CREATE TABLE issue_attachment (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    -- blob_key is the key of the file content in the blob store.
+    blob_key TEXT NOT NULL
+);
+
+CREATE INDEX idx_issue_attachment_issue_id ON issue_attachment(issue_id);
+
+ALTER SEQUENCE issue_attachment_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_attachment_updated_ts
+BEFORE
+UPDATE
+    ON issue_attachment FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
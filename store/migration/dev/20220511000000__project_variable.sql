@@ -0,0 +1,21 @@
+-- project_variable stores the variables referenced as {{NAME}} in the migration statements of the project.
+CREATE TABLE project_variable (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    name TEXT NOT NULL,
+    value TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_project_variable_unique_project_id_name ON project_variable(project_id, name);
+
+ALTER SEQUENCE project_variable_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_project_variable_updated_ts
+BEFORE
+UPDATE
+    ON project_variable FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
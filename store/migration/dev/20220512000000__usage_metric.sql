@@ -0,0 +1,23 @@
+-- usage_metric stores the daily usage metrics aggregated locally before they are reported.
+CREATE TABLE usage_metric (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- The UTC date in YYYY-MM-DD format.
+    date TEXT NOT NULL,
+    name TEXT NOT NULL,
+    -- The labels in JSON format with sorted keys, so that the same labels are stored as the same text.
+    labels TEXT NOT NULL DEFAULT '{}',
+    value BIGINT NOT NULL DEFAULT 0,
+    reported BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX idx_usage_metric_unique_date_name_labels ON usage_metric(date, name, labels);
+
+ALTER SEQUENCE usage_metric_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_usage_metric_updated_ts
+BEFORE
+UPDATE
+    ON usage_metric FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
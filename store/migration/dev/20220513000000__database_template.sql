@@ -0,0 +1,27 @@
+-- database_template stores the reusable baselines applied right after creating the databases.
+CREATE TABLE database_template (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    name TEXT NOT NULL,
+    engine TEXT NOT NULL CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE')),
+    description TEXT NOT NULL DEFAULT '',
+    -- The baseline DDL executed in the new database.
+    statement TEXT NOT NULL DEFAULT '',
+    -- The labels in JSON format from a list of DatabaseLabel, the labels of the create database issue take precedence.
+    labels TEXT NOT NULL DEFAULT '[]',
+    -- The extensions created in the new database, only applicable to Postgres.
+    extension_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_database_template_unique_name ON database_template(name);
+
+ALTER SEQUENCE database_template_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_database_template_updated_ts
+BEFORE
+UPDATE
+    ON database_template FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
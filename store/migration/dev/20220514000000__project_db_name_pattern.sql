@@ -0,0 +1,3 @@
+-- db_name_pattern is the regular expression the names of the databases created in the project must match.
+-- Empty value means no restriction.
+ALTER TABLE project ADD db_name_pattern TEXT NOT NULL DEFAULT '';
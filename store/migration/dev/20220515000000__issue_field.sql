@@ -0,0 +1,48 @@
+-- issue_field stores the custom fields of the issues defined in the project, e.g. the internal change ticket ID and the risk category.
+CREATE TABLE issue_field (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('STRING', 'ENUM', 'USER', 'DATE')),
+    -- The allowed values of the ENUM field.
+    enum_value_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_issue_field_unique_project_id_name ON issue_field(project_id, name);
+
+ALTER SEQUENCE issue_field_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_field_updated_ts
+BEFORE
+UPDATE
+    ON issue_field FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- issue_field_value stores the values of the issue custom fields.
+CREATE TABLE issue_field_value (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    field_id INTEGER NOT NULL REFERENCES issue_field (id) ON DELETE CASCADE,
+    -- The value is the principal ID for the USER field and YYYY-MM-DD for the DATE field.
+    value TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_issue_field_value_unique_issue_id_field_id ON issue_field_value(issue_id, field_id);
+
+CREATE INDEX idx_issue_field_value_field_id ON issue_field_value(field_id);
+
+ALTER SEQUENCE issue_field_value_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_field_value_updated_ts
+BEFORE
+UPDATE
+    ON issue_field_value FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
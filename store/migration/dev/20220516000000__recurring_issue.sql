@@ -0,0 +1,35 @@
+-- recurring_issue stores the issue templates instantiated on the cron schedule.
+CREATE TABLE recurring_issue (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    name TEXT NOT NULL,
+    -- The 5-field cron expression evaluated in UTC.
+    schedule TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('bb.issue.database.schema.update', 'bb.issue.database.data.update')),
+    description TEXT NOT NULL DEFAULT '',
+    assignee_id INTEGER NOT NULL REFERENCES principal (id),
+    -- The issue create context, e.g. the databases and the statement to apply.
+    create_context TEXT NOT NULL DEFAULT '{}',
+    auto_approve BOOLEAN NOT NULL DEFAULT FALSE,
+    next_run_ts BIGINT NOT NULL,
+    last_run_ts BIGINT NOT NULL DEFAULT 0,
+    -- The ID of the last instantiated issue, 0 if none.
+    last_issue_id INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_recurring_issue_unique_project_id_name ON recurring_issue(project_id, name);
+
+CREATE INDEX idx_recurring_issue_next_run_ts ON recurring_issue(next_run_ts);
+
+ALTER SEQUENCE recurring_issue_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_recurring_issue_updated_ts
+BEFORE
+UPDATE
+    ON recurring_issue FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
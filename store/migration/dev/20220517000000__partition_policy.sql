@@ -0,0 +1,31 @@
+-- partition_policy stores the partitions managed for the time-partitioned tables.
+CREATE TABLE partition_policy (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- The schema of the table, only applicable to Postgres.
+    schema_name TEXT NOT NULL DEFAULT '',
+    table_name TEXT NOT NULL,
+    range_key TEXT NOT NULL,
+    range_interval TEXT NOT NULL CHECK (range_interval IN ('DAY', 'WEEK', 'MONTH')),
+    retention_count INTEGER NOT NULL DEFAULT 0 CHECK (retention_count >= 0),
+    precreate_count INTEGER NOT NULL DEFAULT 0 CHECK (precreate_count >= 0),
+    assignee_id INTEGER NOT NULL REFERENCES principal (id),
+    last_run_ts BIGINT NOT NULL DEFAULT 0,
+    -- The ID of the last partition maintenance issue, 0 if none.
+    last_issue_id INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_partition_policy_unique_database_id_schema_name_table_name ON partition_policy(database_id, schema_name, table_name);
+
+ALTER SEQUENCE partition_policy_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_partition_policy_updated_ts
+BEFORE
+UPDATE
+    ON partition_policy FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
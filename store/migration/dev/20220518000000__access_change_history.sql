@@ -0,0 +1,22 @@
+-- access_change_history records the user and grant changes, it's separate from the schema migration history.
+-- The rows are immutable, so there is no updater.
+CREATE TABLE access_change_history (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- The ID of the issue, 0 if the task doesn't belong to an issue.
+    issue_id INTEGER NOT NULL DEFAULT 0,
+    task_id INTEGER NOT NULL REFERENCES task (id),
+    action TEXT NOT NULL CHECK (action IN ('CREATE_USER', 'ALTER_USER', 'DROP_USER', 'GRANT', 'REVOKE')),
+    user_name TEXT NOT NULL,
+    -- The executed statement with the password masked.
+    statement TEXT NOT NULL,
+    -- The error executing the statement, empty if it succeeded.
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_access_change_history_instance_id ON access_change_history(instance_id);
+
+ALTER SEQUENCE access_change_history_id_seq RESTART WITH 101;
@@ -0,0 +1,14 @@
+-- account_report stores the database account inventory reports for the access recertification.
+-- The reports are immutable, so there is no updater.
+CREATE TABLE account_report (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    account_count INTEGER NOT NULL,
+    -- The number of the accounts with any flag, e.g. superuser or unused.
+    flagged_count INTEGER NOT NULL,
+    -- The JSON encoded AccountReportPayload.
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+ALTER SEQUENCE account_report_id_seq RESTART WITH 101;
@@ -0,0 +1,13 @@
+-- instance_parameter records the synced instance parameter values, a new row is inserted only when the value changes.
+CREATE TABLE instance_parameter (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    name TEXT NOT NULL,
+    value TEXT NOT NULL
+);
+
+CREATE INDEX idx_instance_parameter_instance_id_name ON instance_parameter(instance_id, name);
+
+ALTER SEQUENCE instance_parameter_id_seq RESTART WITH 101;
@@ -0,0 +1,2 @@
+-- request_id is the ID of the API request starting the task run, it's empty if the task run is started by the scheduler.
+ALTER TABLE task_run ADD request_id TEXT NOT NULL DEFAULT '';
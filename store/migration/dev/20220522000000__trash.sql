@@ -0,0 +1,15 @@
+-- trash records the deleted projects, instances and databases, which are archived and can be restored within the retention window.
+CREATE TABLE trash (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    resource_type TEXT NOT NULL CHECK (resource_type IN ('PROJECT', 'INSTANCE', 'DATABASE')),
+    resource_id INTEGER NOT NULL,
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_trash_unique_resource_type_resource_id ON trash(resource_type, resource_id);
+
+CREATE INDEX idx_trash_created_ts ON trash(created_ts);
+
+ALTER SEQUENCE trash_id_seq RESTART WITH 101;
@@ -0,0 +1,23 @@
+-- instance_sync_setting stores the automatic schema sync schedule of an instance.
+CREATE TABLE instance_sync_setting (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    -- 0 disables the automatic schema sync.
+    interval_minutes INTEGER NOT NULL CHECK (interval_minutes >= 0),
+    -- JSON encoded list of the weekly windows during which the automatic schema sync doesn't run.
+    blackout_window_list TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE UNIQUE INDEX idx_instance_sync_setting_unique_instance_id ON instance_sync_setting(instance_id);
+
+ALTER SEQUENCE instance_sync_setting_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_instance_sync_setting_updated_ts
+BEFORE
+UPDATE
+    ON instance_sync_setting FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
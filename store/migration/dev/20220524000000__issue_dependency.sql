@@ -0,0 +1,11 @@
+-- issue_dependency stores the issues blocking other issues, the first stage of the blocked issue can't start until the blocking issue is done.
+CREATE TABLE issue_dependency (
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    blocking_issue_id INTEGER NOT NULL REFERENCES issue (id),
+    blocked_issue_id INTEGER NOT NULL REFERENCES issue (id),
+    PRIMARY KEY (blocking_issue_id, blocked_issue_id),
+    CHECK (blocking_issue_id <> blocked_issue_id)
+);
+
+CREATE INDEX idx_issue_dependency_blocked_issue_id ON issue_dependency(blocked_issue_id);
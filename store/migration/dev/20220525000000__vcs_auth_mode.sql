@@ -0,0 +1,4 @@
+-- auth_mode is OAUTH for the OAuth application, or GITHUB_APP for the GitHub App authenticating with short-lived installation tokens.
+ALTER TABLE vcs ADD COLUMN auth_mode TEXT NOT NULL CHECK (auth_mode IN ('OAUTH', 'GITHUB_APP')) DEFAULT 'OAUTH';
+-- webhook_secret verifies the webhook events delivered to the GitHub App.
+ALTER TABLE vcs ADD COLUMN webhook_secret TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,34 @@
+-- vcs_group_webhook stores the webhooks registered on the VCS groups, i.e. the GitLab groups, which deliver the push events
+-- of all the repositories in the group and its subgroups. The repositories linked under the group have no webhooks of their own.
+CREATE TABLE vcs_group_webhook (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    vcs_id INTEGER NOT NULL REFERENCES vcs (id),
+    -- Group id from the corresponding VCS provider, e.g. 123.
+    external_group_id TEXT NOT NULL,
+    -- Group full path from the corresponding VCS provider, e.g. group1/subgroup1.
+    group_full_path TEXT NOT NULL,
+    external_webhook_id TEXT NOT NULL,
+    webhook_url_host TEXT NOT NULL,
+    webhook_endpoint_id TEXT NOT NULL,
+    webhook_secret_token TEXT NOT NULL,
+    -- access_token, expires_ts, refresh_token belongs to the user registering the webhook.
+    access_token TEXT NOT NULL,
+    expires_ts BIGINT NOT NULL,
+    refresh_token TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_vcs_group_webhook_unique_vcs_id_external_group_id ON vcs_group_webhook(vcs_id, external_group_id);
+
+CREATE UNIQUE INDEX idx_vcs_group_webhook_unique_webhook_endpoint_id ON vcs_group_webhook(webhook_endpoint_id);
+
+ALTER SEQUENCE vcs_group_webhook_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_vcs_group_webhook_updated_ts
+BEFORE
+UPDATE
+    ON vcs_group_webhook FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
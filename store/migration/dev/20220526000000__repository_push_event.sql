@@ -0,0 +1,25 @@
+-- repository_push_event stores the files added by the push events received from the VCS and the processing results,
+-- so the failed ones can be inspected and replayed.
+CREATE TABLE repository_push_event (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    file TEXT NOT NULL,
+    -- JSON encoded vcs.PushEvent of the file.
+    push_event TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('DONE', 'IGNORED', 'FAILED')),
+    message TEXT NOT NULL DEFAULT '',
+    replay_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_repository_push_event_project_id_status ON repository_push_event(project_id, status);
+
+ALTER SEQUENCE repository_push_event_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_repository_push_event_updated_ts
+BEFORE
+UPDATE
+    ON repository_push_event FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
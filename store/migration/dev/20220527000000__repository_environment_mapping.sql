@@ -0,0 +1,2 @@
+-- environment_mapping maps the branches or directories of the repository to the environments in json format, e.g. main to Prod and develop to Staging.
+ALTER TABLE repository ADD COLUMN environment_mapping TEXT NOT NULL DEFAULT '';
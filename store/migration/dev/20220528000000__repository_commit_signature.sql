@@ -0,0 +1,4 @@
+-- require_signed_commit requires the commits of the migration files to be signed and verified by the VCS before creating the issues.
+ALTER TABLE repository ADD COLUMN require_signed_commit BOOLEAN NOT NULL DEFAULT FALSE;
+-- trusted_signing_keys is the comma separated list of the trusted signing keys. If empty, any verified signature is trusted.
+ALTER TABLE repository ADD COLUMN trusted_signing_keys TEXT NOT NULL DEFAULT '';
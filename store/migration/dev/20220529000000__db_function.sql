@@ -0,0 +1,29 @@
+-- db_function stores the functions and procedures for a particular database.
+-- data is synced periodically from the instance.
+CREATE TABLE db_function (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    schema TEXT NOT NULL,
+    arguments TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('FUNCTION', 'PROCEDURE')),
+    language TEXT NOT NULL,
+    definition TEXT NOT NULL
+);
+
+CREATE INDEX idx_db_function_database_id ON db_function(database_id);
+
+CREATE UNIQUE INDEX idx_db_function_unique_database_id_schema_name_arguments ON db_function(database_id, schema, name, arguments);
+
+ALTER SEQUENCE db_function_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_function_updated_ts
+BEFORE
+UPDATE
+    ON db_function FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
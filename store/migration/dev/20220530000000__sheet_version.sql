@@ -0,0 +1,18 @@
+-- sheet_version table stores the immutable revisions of the sheet statement.
+-- A new version is recorded whenever the statement changes, so an issue can refer to the reviewed revision.
+CREATE TABLE sheet_version (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    sheet_id INTEGER NOT NULL REFERENCES sheet (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    statement TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_sheet_version_unique_sheet_id_version ON sheet_version(sheet_id, version);
+
+ALTER SEQUENCE sheet_version_id_seq RESTART WITH 101;
+
+-- The current statement of the existing sheets becomes their first version.
+INSERT INTO sheet_version (creator_id, created_ts, sheet_id, version, statement)
+SELECT updater_id, updated_ts, id, 1, statement FROM sheet;
@@ -0,0 +1,15 @@
+-- schema_snapshot records the schema dump of a database at each sync and migration, a new row is inserted only when the schema changes.
+-- It allows viewing the schema of a database as of a past time.
+CREATE TABLE schema_snapshot (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    source TEXT NOT NULL CHECK (source IN ('SYNC', 'MIGRATION')),
+    -- version is the migration version for the MIGRATION source.
+    version TEXT NOT NULL DEFAULT '',
+    schema TEXT NOT NULL
+);
+
+CREATE INDEX idx_schema_snapshot_database_id_created_ts ON schema_snapshot(database_id, created_ts);
+
+ALTER SEQUENCE schema_snapshot_id_seq RESTART WITH 101;
@@ -0,0 +1,5 @@
+-- partition_key is only set for the partitioned tables, and parent_table and partition_bound are only set for the partitions.
+-- They're only collected for Postgres.
+ALTER TABLE tbl ADD partition_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE tbl ADD parent_table TEXT NOT NULL DEFAULT '';
+ALTER TABLE tbl ADD partition_bound TEXT NOT NULL DEFAULT '';
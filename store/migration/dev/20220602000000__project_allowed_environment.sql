@@ -0,0 +1,3 @@
+-- allowed_environment_list is the JSON encoded IDs of the environments the project can deploy to.
+-- Empty list means no restriction.
+ALTER TABLE project ADD allowed_environment_list TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,11 @@
+-- tag_list is the JSON encoded free-form tags, e.g. ["pci","gdpr"]. The databases inherit the tags of their instance.
+ALTER TABLE instance ADD tag_list TEXT NOT NULL DEFAULT '[]';
+
+ALTER TABLE db ADD tag_list TEXT NOT NULL DEFAULT '[]';
+
+-- tag scopes the policy to the databases with the tag in the environment, and it's empty for the environment policy.
+ALTER TABLE policy ADD tag TEXT NOT NULL DEFAULT '';
+
+DROP INDEX idx_policy_unique_environment_id_type;
+
+CREATE UNIQUE INDEX idx_policy_unique_environment_id_tag_type ON policy(environment_id, tag, type);
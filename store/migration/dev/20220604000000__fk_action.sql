@@ -0,0 +1,4 @@
+-- on_delete and on_update are the referential actions of the foreign key, e.g. CASCADE, they're empty until the next schema sync.
+ALTER TABLE fk ADD on_delete TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE fk ADD on_update TEXT NOT NULL DEFAULT '';
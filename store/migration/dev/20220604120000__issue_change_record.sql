@@ -0,0 +1,29 @@
+-- issue_change_record is the signed snapshot of a done issue for the auditors. It's taken when first requested and
+-- can't be updated or deleted afterwards.
+CREATE TABLE issue_change_record (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL,
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    -- The JSON encoded snapshot. It's TEXT instead of JSONB to keep the signed text byte for byte.
+    payload TEXT NOT NULL,
+    -- The HMAC of the fields above, keyed by the workspace secret.
+    signature TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_issue_change_record_unique_issue_id ON issue_change_record(issue_id);
+
+ALTER SEQUENCE issue_change_record_id_seq RESTART WITH 101;
+
+CREATE OR REPLACE FUNCTION trigger_reject_issue_change_record_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'issue change records are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reject_issue_change_record_change
+BEFORE
+UPDATE OR DELETE
+    ON issue_change_record FOR EACH ROW
+EXECUTE FUNCTION trigger_reject_issue_change_record_change();
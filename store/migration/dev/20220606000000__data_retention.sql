@@ -0,0 +1 @@
+ALTER TABLE member ADD COLUMN legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,29 @@
+-- dashboard_tile stores the read-only queries pinned as the dashboard tiles of a project and their cached results.
+CREATE TABLE dashboard_tile (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    -- The JSON encoded map from the parameter name to the default value.
+    parameters TEXT NOT NULL DEFAULT '{}',
+    refresh TEXT NOT NULL CHECK (refresh IN ('ON_VIEW', 'HOURLY', 'DAILY')),
+    -- The JSON encoded row set of the latest run with the default parameter values.
+    result TEXT NOT NULL DEFAULT '',
+    result_error TEXT NOT NULL DEFAULT '',
+    refreshed_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_dashboard_tile_unique_project_id_name ON dashboard_tile(project_id, name);
+
+ALTER SEQUENCE dashboard_tile_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_dashboard_tile_updated_ts
+BEFORE
+UPDATE
+    ON dashboard_tile FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
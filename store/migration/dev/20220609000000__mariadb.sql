@@ -0,0 +1,5 @@
+ALTER TABLE instance DROP CONSTRAINT instance_engine_check;
+ALTER TABLE instance ADD CONSTRAINT instance_engine_check CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE', 'MSSQL', 'MONGODB', 'MARIADB'));
+
+ALTER TABLE database_template DROP CONSTRAINT database_template_engine_check;
+ALTER TABLE database_template ADD CONSTRAINT database_template_engine_check CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE', 'MSSQL', 'MONGODB', 'MARIADB'));
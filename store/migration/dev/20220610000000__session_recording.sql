@@ -0,0 +1,44 @@
+-- session_recording records the statements and the result metadata of the SQL editor and the proxied sessions on the
+-- databases tagged sensitive. Each row is chained to the previous one by hash, and the rows can't be updated or deleted,
+-- so that the recordings are tamper-evident.
+CREATE TABLE session_recording (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL,
+    -- The recordings outlive the instances and the databases, so they aren't referenced by the foreign keys.
+    instance_id INTEGER NOT NULL,
+    instance_name TEXT NOT NULL,
+    -- database_id is 0 and database_name is empty for the instance-level queries.
+    database_id INTEGER NOT NULL DEFAULT 0,
+    database_name TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL CHECK (source IN ('SQL_EDITOR', 'PROXY')),
+    session_id TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    -- The JSON encoded column names of the result, the values aren't recorded.
+    column_names TEXT NOT NULL DEFAULT '[]',
+    row_count INTEGER NOT NULL DEFAULT 0,
+    duration_ns BIGINT NOT NULL DEFAULT 0,
+    -- The reason the statement is rejected or fails, empty if it succeeded.
+    error TEXT NOT NULL DEFAULT '',
+    -- The HMAC of the hash of the previous row and the fields above, keyed by the workspace secret.
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX idx_session_recording_session_id ON session_recording(session_id);
+
+CREATE INDEX idx_session_recording_database_id ON session_recording(database_id);
+
+ALTER SEQUENCE session_recording_id_seq RESTART WITH 101;
+
+CREATE OR REPLACE FUNCTION trigger_reject_session_recording_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'session recordings are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reject_session_recording_change
+BEFORE
+UPDATE OR DELETE
+    ON session_recording FOR EACH ROW
+EXECUTE FUNCTION trigger_reject_session_recording_change();
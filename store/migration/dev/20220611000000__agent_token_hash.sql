@@ -0,0 +1,6 @@
+-- The agent tokens are stored as their SHA-256 hashes, the tokens themselves are only returned upon the agent creation.
+ALTER TABLE agent RENAME COLUMN token TO token_hash;
+
+UPDATE agent SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
+
+ALTER INDEX idx_agent_unique_token RENAME TO idx_agent_unique_token_hash;
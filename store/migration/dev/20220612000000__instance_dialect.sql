@@ -0,0 +1,3 @@
+-- The dialect is the server speaking the protocol of the engine detected by the instance sync, e.g. REDSHIFT for POSTGRES.
+-- The features are gated on it along with the engine.
+ALTER TABLE instance ADD COLUMN dialect TEXT NOT NULL DEFAULT '';
@@ -89,8 +89,10 @@ CREATE TABLE member (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     status TEXT NOT NULL CHECK (status IN ('INVITED', 'ACTIVE')),
-    role TEXT NOT NULL CHECK (role IN ('OWNER', 'DBA', 'DEVELOPER')),
-    principal_id INTEGER NOT NULL REFERENCES principal (id)
+    role TEXT NOT NULL CHECK (role IN ('OWNER', 'DBA', 'DEVELOPER', 'AUDITOR')),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    -- The data of the members on legal hold is kept from the retention purge and the erasure.
+    legal_hold BOOLEAN NOT NULL DEFAULT FALSE
 );
 
 CREATE UNIQUE INDEX idx_member_unique_principal_id ON member(principal_id);
@@ -136,13 +138,15 @@ CREATE TABLE policy (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     environment_id INTEGER NOT NULL REFERENCES environment (id),
+    -- tag scopes the policy to the databases with the tag in the environment, and it's empty for the environment policy.
+    tag TEXT NOT NULL DEFAULT '',
     type TEXT NOT NULL CHECK (type LIKE 'bb.policy.%'),
     payload JSONB NOT NULL DEFAULT '{}'
 );
 
 CREATE INDEX idx_policy_environment_id ON policy(environment_id);
 
-CREATE UNIQUE INDEX idx_policy_unique_environment_id_type ON policy(environment_id, type);
+CREATE UNIQUE INDEX idx_policy_unique_environment_id_tag_type ON policy(environment_id, tag, type);
 
 ALTER SEQUENCE policy_id_seq RESTART WITH 101;
 
@@ -169,7 +173,14 @@ CREATE TABLE project (
     -- Empty value means {{DB_NAME}}.
     db_name_template TEXT NOT NULL,
     role_provider TEXT NOT NULL CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM')) DEFAULT 'BYTEBASE',
-    schema_version_type TEXT NOT NULL CHECK (schema_version_type IN ('TIMESTAMP', 'SEMANTIC')) DEFAULT 'TIMESTAMP'
+    schema_version_type TEXT NOT NULL CHECK (schema_version_type IN ('TIMESTAMP', 'SEMANTIC')) DEFAULT 'TIMESTAMP',
+    pinned_note TEXT NOT NULL DEFAULT '',
+    -- db_name_pattern is the regular expression the names of the databases created in the project must match.
+    -- Empty value means no restriction.
+    db_name_pattern TEXT NOT NULL DEFAULT '',
+    -- allowed_environment_list is the JSON encoded IDs of the environments the project can deploy to.
+    -- Empty list means no restriction.
+    allowed_environment_list TEXT NOT NULL DEFAULT '[]'
 );
 
 CREATE UNIQUE INDEX idx_project_unique_key ON project(key);
@@ -260,6 +271,33 @@ UPDATE
     ON project_webhook FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- agent stores the runner agents deployed inside the private networks.
+CREATE TABLE agent (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    name TEXT NOT NULL,
+    -- token_hash is the hex encoded SHA-256 hash of the token, which is only returned upon creation.
+    token_hash TEXT NOT NULL,
+    version TEXT NOT NULL DEFAULT '',
+    last_heartbeat_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_agent_unique_name ON agent(name);
+
+CREATE UNIQUE INDEX idx_agent_unique_token_hash ON agent(token_hash);
+
+ALTER SEQUENCE agent_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_agent_updated_ts
+BEFORE
+UPDATE
+    ON agent FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- Instance
 CREATE TABLE instance (
     id SERIAL PRIMARY KEY,
@@ -270,11 +308,16 @@ CREATE TABLE instance (
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     environment_id INTEGER NOT NULL REFERENCES environment (id),
     name TEXT NOT NULL,
-    engine TEXT NOT NULL CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE')),
+    engine TEXT NOT NULL CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE', 'MSSQL', 'MONGODB', 'MARIADB')),
     engine_version TEXT NOT NULL DEFAULT '',
+    -- dialect is the server speaking the protocol of the engine detected by the instance sync, e.g. REDSHIFT for POSTGRES.
+    dialect TEXT NOT NULL DEFAULT '',
     host TEXT NOT NULL,
     port TEXT NOT NULL,
-    external_link TEXT NOT NULL DEFAULT ''
+    external_link TEXT NOT NULL DEFAULT '',
+    agent_id INTEGER REFERENCES agent (id),
+    -- tag_list is the JSON encoded free-form tags, e.g. ["pci","gdpr"]. The databases inherit the tags of their instance.
+    tag_list TEXT NOT NULL DEFAULT '[]'
 );
 
 ALTER SEQUENCE instance_id_seq RESTART WITH 101;
@@ -285,6 +328,53 @@ UPDATE
     ON instance FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- db_assignment_rule stores the rules assigning the newly synced databases to the projects.
+CREATE TABLE db_assignment_rule (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    -- Empty instance_id matches databases on any instance.
+    instance_id INTEGER REFERENCES instance (id),
+    -- Empty name_pattern matches any database name.
+    name_pattern TEXT NOT NULL DEFAULT '',
+    label_selector JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_db_assignment_rule_project_id ON db_assignment_rule(project_id);
+
+ALTER SEQUENCE db_assignment_rule_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_assignment_rule_updated_ts
+BEFORE
+UPDATE
+    ON db_assignment_rule FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- project_variable stores the variables referenced as {{NAME}} in the migration statements of the project.
+CREATE TABLE project_variable (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    name TEXT NOT NULL,
+    value TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_project_variable_unique_project_id_name ON project_variable(project_id, name);
+
+ALTER SEQUENCE project_variable_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_project_variable_updated_ts
+BEFORE
+UPDATE
+    ON project_variable FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- Instance user stores the users for a particular instance
 CREATE TABLE instance_user (
     id SERIAL PRIMARY KEY,
@@ -323,10 +413,14 @@ CREATE TABLE db (
     source_backup_id INTEGER,
     sync_status TEXT NOT NULL CHECK (sync_status IN ('OK', 'NOT_FOUND')),
     last_successful_sync_ts BIGINT NOT NULL,
+    -- not_found_ts records when the database was first found missing from the instance.
+    not_found_ts BIGINT NOT NULL DEFAULT 0,
     schema_version TEXT NOT NULL,
     name TEXT NOT NULL,
     character_set TEXT NOT NULL,
-    "collation" TEXT NOT NULL
+    "collation" TEXT NOT NULL,
+    -- tag_list is the JSON encoded free-form tags in addition to the ones inherited from the instance.
+    tag_list TEXT NOT NULL DEFAULT '[]'
 );
 
 CREATE INDEX idx_db_instance_id ON db(instance_id);
@@ -360,11 +454,20 @@ CREATE TABLE tbl (
     index_size BIGINT NOT NULL,
     data_free BIGINT NOT NULL,
     create_options TEXT NOT NULL,
-    comment TEXT NOT NULL
+    comment TEXT NOT NULL,
+    -- owner is the database role owning the table, only collected for Postgres.
+    owner TEXT NOT NULL DEFAULT '',
+    -- partition_key is only set for the partitioned tables, and parent_table and partition_bound are only set for the partitions.
+    -- They're only collected for Postgres.
+    partition_key TEXT NOT NULL DEFAULT '',
+    parent_table TEXT NOT NULL DEFAULT '',
+    partition_bound TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX idx_tbl_database_id ON tbl(database_id);
 
+CREATE INDEX idx_tbl_owner ON tbl(owner);
+
 CREATE UNIQUE INDEX idx_tbl_unique_database_id_name ON tbl(database_id, name);
 
 ALTER SEQUENCE tbl_id_seq RESTART WITH 101;
@@ -442,6 +545,37 @@ UPDATE
     ON idx FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- fk stores the foreign keys, one row per column in the key.
+CREATE TABLE fk (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    table_id INTEGER NOT NULL REFERENCES tbl (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    column_name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    referenced_table TEXT NOT NULL,
+    referenced_column TEXT NOT NULL,
+    -- on_delete and on_update are the referential actions, e.g. CASCADE.
+    on_delete TEXT NOT NULL DEFAULT '',
+    on_update TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_fk_database_id_table_id ON fk(database_id, table_id);
+
+CREATE UNIQUE INDEX idx_fk_unique_database_id_table_id_name_position ON fk(database_id, table_id, name, position);
+
+ALTER SEQUENCE fk_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_fk_updated_ts
+BEFORE
+UPDATE
+    ON fk FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- db_extension stores the extensions for a particular database.
 -- data is synced periodically from the instance.
 CREATE TABLE db_extension (
@@ -470,6 +604,36 @@ UPDATE
     ON db_extension FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- db_function stores the functions and procedures for a particular database.
+-- data is synced periodically from the instance.
+CREATE TABLE db_function (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    schema TEXT NOT NULL,
+    arguments TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('FUNCTION', 'PROCEDURE')),
+    language TEXT NOT NULL,
+    definition TEXT NOT NULL
+);
+
+CREATE INDEX idx_db_function_database_id ON db_function(database_id);
+
+CREATE UNIQUE INDEX idx_db_function_unique_database_id_schema_name_arguments ON db_function(database_id, schema, name, arguments);
+
+ALTER SEQUENCE db_function_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_function_updated_ts
+BEFORE
+UPDATE
+    ON db_function FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- vw stores the view for a particular database
 -- data is synced periodically from the instance
 CREATE TABLE vw (
@@ -528,6 +692,34 @@ UPDATE
     ON data_source FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- data_source_rotation stores the credential rotations of the data sources.
+-- The new credential is staged and validated first, then switched over when the rotation is committed.
+CREATE TABLE data_source_rotation (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    data_source_id INTEGER NOT NULL REFERENCES data_source (id),
+    status TEXT NOT NULL CHECK (status IN ('VALIDATED', 'FAILED', 'COMMITTED', 'CANCELED')),
+    username TEXT NOT NULL,
+    password TEXT NOT NULL,
+    ssl_key TEXT NOT NULL DEFAULT '',
+    ssl_cert TEXT NOT NULL DEFAULT '',
+    ssl_ca TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_data_source_rotation_data_source_id ON data_source_rotation(data_source_id);
+
+ALTER SEQUENCE data_source_rotation_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_data_source_rotation_updated_ts
+BEFORE
+UPDATE
+    ON data_source_rotation FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- backup stores the backups for a particular database.
 CREATE TABLE backup (
     id SERIAL PRIMARY KEY,
@@ -709,7 +901,9 @@ CREATE TABLE task_run (
     comment TEXT NOT NULL DEFAULT '',
     -- result saves the task run result in json format
     result  JSONB NOT NULL DEFAULT '{}',
-    payload JSONB NOT NULL DEFAULT '{}'
+    payload JSONB NOT NULL DEFAULT '{}',
+    -- request_id is the ID of the API request starting the task run, it's empty if the task run is started by the scheduler.
+    request_id TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX idx_task_run_task_id ON task_run(task_id);
@@ -768,7 +962,9 @@ CREATE TABLE issue (
     description TEXT NOT NULL DEFAULT '',
     -- While changing assignee_id, one should only change it to a non-robot DBA/owner.
     assignee_id INTEGER NOT NULL REFERENCES principal (id),
-    payload JSONB NOT NULL DEFAULT '{}'
+    payload JSONB NOT NULL DEFAULT '{}',
+    -- idempotency_key is the client token of the creation request, retrying the request with the same key returns the created issue.
+    idempotency_key TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX idx_issue_project_id ON issue(project_id);
@@ -781,6 +977,8 @@ CREATE INDEX idx_issue_assignee_id ON issue(assignee_id);
 
 CREATE INDEX idx_issue_created_ts ON issue(created_ts);
 
+CREATE UNIQUE INDEX idx_issue_unique_creator_id_idempotency_key ON issue(creator_id, idempotency_key) WHERE idempotency_key != '';
+
 ALTER SEQUENCE issue_id_seq RESTART WITH 101;
 
 CREATE TRIGGER update_issue_updated_ts
@@ -800,6 +998,80 @@ CREATE TABLE issue_subscriber (
 
 CREATE INDEX idx_issue_subscriber_subscriber_id ON issue_subscriber(subscriber_id);
 
+-- issue_attachment stores the files attached to the issues, the file contents are saved in the blob store.
+CREATE TABLE issue_attachment (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    -- blob_key is the key of the file content in the blob store.
+    blob_key TEXT NOT NULL
+);
+
+CREATE INDEX idx_issue_attachment_issue_id ON issue_attachment(issue_id);
+
+ALTER SEQUENCE issue_attachment_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_attachment_updated_ts
+BEFORE
+UPDATE
+    ON issue_attachment FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- issue_field stores the custom fields of the issues defined in the project, e.g. the internal change ticket ID and the risk category.
+CREATE TABLE issue_field (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('STRING', 'ENUM', 'USER', 'DATE')),
+    -- The allowed values of the ENUM field.
+    enum_value_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_issue_field_unique_project_id_name ON issue_field(project_id, name);
+
+ALTER SEQUENCE issue_field_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_field_updated_ts
+BEFORE
+UPDATE
+    ON issue_field FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- issue_field_value stores the values of the issue custom fields.
+CREATE TABLE issue_field_value (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    field_id INTEGER NOT NULL REFERENCES issue_field (id) ON DELETE CASCADE,
+    -- The value is the principal ID for the USER field and YYYY-MM-DD for the DATE field.
+    value TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_issue_field_value_unique_issue_id_field_id ON issue_field_value(issue_id, field_id);
+
+CREATE INDEX idx_issue_field_value_field_id ON issue_field_value(field_id);
+
+ALTER SEQUENCE issue_field_value_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_field_value_updated_ts
+BEFORE
+UPDATE
+    ON issue_field_value FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- activity table stores the activity for the container such as issue
 CREATE TABLE activity (
     id SERIAL PRIMARY KEY,
@@ -880,7 +1152,11 @@ CREATE TABLE vcs (
     instance_url TEXT NOT NULL CHECK ((instance_url LIKE 'http://%' OR instance_url LIKE 'https://%') AND instance_url = rtrim(instance_url, '/')),
     api_url TEXT NOT NULL CHECK ((api_url LIKE 'http://%' OR api_url LIKE 'https://%') AND api_url = rtrim(api_url, '/')),
     application_id TEXT NOT NULL,
-    secret TEXT NOT NULL
+    -- auth_mode is OAUTH for the OAuth application, or GITHUB_APP for the GitHub App authenticating with short-lived installation tokens.
+    auth_mode TEXT NOT NULL CHECK (auth_mode IN ('OAUTH', 'GITHUB_APP')) DEFAULT 'OAUTH',
+    secret TEXT NOT NULL,
+    -- webhook_secret verifies the webhook events delivered to the GitHub App.
+    webhook_secret TEXT NOT NULL DEFAULT ''
 );
 
 ALTER SEQUENCE vcs_id_seq RESTART WITH 101;
@@ -919,11 +1195,22 @@ CREATE TABLE repository (
     base_directory TEXT NOT NULL DEFAULT '',
     -- The file path template for matching the commited migration script.
     file_path_template TEXT NOT NULL DEFAULT '',
+    -- The naming convention and the content format of the migration files.
+    -- For the formats other than BYTEBASE, file_path_template only matches the directory containing the migration files.
+    file_format TEXT NOT NULL CHECK (file_format IN ('BYTEBASE', 'FLYWAY', 'LIQUIBASE', 'GOLANG_MIGRATE')) DEFAULT 'BYTEBASE',
     -- The file path template for storing the latest schema auto-generated by Bytebase after migration.
     -- If empty, then Bytebase won't auto generate it.
     schema_path_template TEXT NOT NULL DEFAULT '',
     -- The file path template to match the script file for sheet.
     sheet_path_template TEXT NOT NULL DEFAULT '',
+    -- The mapping from the branches or directories of the repository to the environments in json format.
+    -- If empty, the environment is determined by the file path template.
+    environment_mapping TEXT NOT NULL DEFAULT '',
+    -- Require the commits of the migration files to be signed and verified by the VCS before creating the issues.
+    require_signed_commit BOOLEAN NOT NULL DEFAULT FALSE,
+    -- The comma separated list of the trusted signing keys, i.e. the GPG key IDs, the X.509 certificate emails or the SSH key fingerprints.
+    -- If empty, any verified signature is trusted.
+    trusted_signing_keys TEXT NOT NULL DEFAULT '',
     -- Repository id from the corresponding VCS provider.
     -- For GitLab, this is the project id. e.g. 123
     external_id TEXT NOT NULL,
@@ -1125,3 +1412,575 @@ CREATE TABLE sheet_organizer (
 CREATE UNIQUE INDEX idx_sheet_organizer_unique_sheet_id_principal_id ON sheet_organizer(sheet_id, principal_id);
 
 CREATE INDEX idx_sheet_organizer_principal_id ON sheet_organizer(principal_id);
+
+-- sheet_version table stores the immutable revisions of the sheet statement.
+-- A new version is recorded whenever the statement changes, so an issue can refer to the reviewed revision.
+CREATE TABLE sheet_version (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    sheet_id INTEGER NOT NULL REFERENCES sheet (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    statement TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_sheet_version_unique_sheet_id_version ON sheet_version(sheet_id, version);
+
+ALTER SEQUENCE sheet_version_id_seq RESTART WITH 101;
+
+-- agent_task is the work queue polled by the runner agents.
+CREATE TABLE agent_task (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    agent_id INTEGER NOT NULL REFERENCES agent (id),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    type TEXT NOT NULL CHECK (type LIKE 'bb.agent-task.%'),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'DONE', 'FAILED')),
+    payload JSONB NOT NULL DEFAULT '{}',
+    result JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_agent_task_agent_id_status ON agent_task(agent_id, status);
+
+ALTER SEQUENCE agent_task_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_agent_task_updated_ts
+BEFORE
+UPDATE
+    ON agent_task FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- db_role_mapping maps the database roles of an instance to the Bytebase principals.
+CREATE TABLE db_role_mapping (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    role_name TEXT NOT NULL,
+    principal_id INTEGER NOT NULL REFERENCES principal (id)
+);
+
+CREATE UNIQUE INDEX idx_db_role_mapping_unique_instance_id_role_name ON db_role_mapping(instance_id, role_name);
+
+CREATE INDEX idx_db_role_mapping_principal_id ON db_role_mapping(principal_id);
+
+ALTER SEQUENCE db_role_mapping_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_role_mapping_updated_ts
+BEFORE
+UPDATE
+    ON db_role_mapping FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- schema_doc_setting stores the branding and the publish schedule of the schema documentation for a project.
+CREATE TABLE schema_doc_setting (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    title TEXT NOT NULL DEFAULT '',
+    logo_url TEXT NOT NULL DEFAULT '',
+    footer TEXT NOT NULL DEFAULT '',
+    theme_color TEXT NOT NULL DEFAULT '',
+    publish_schedule TEXT NOT NULL CHECK (publish_schedule IN ('DISABLED', 'DAILY', 'WEEKLY')) DEFAULT 'DISABLED',
+    publish_format TEXT NOT NULL CHECK (publish_format IN ('MARKDOWN', 'HTML', 'PDF')) DEFAULT 'MARKDOWN',
+    publish_url TEXT NOT NULL DEFAULT '',
+    last_published_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_schema_doc_setting_unique_project_id ON schema_doc_setting(project_id);
+
+ALTER SEQUENCE schema_doc_setting_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_doc_setting_updated_ts
+BEFORE
+UPDATE
+    ON schema_doc_setting FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- schema_description stores the logical descriptions of the tables and the columns, a.k.a. the data dictionary.
+-- They're keyed by the names since the synced table and column rows may be recreated.
+CREATE TABLE schema_description (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    table_name TEXT NOT NULL,
+    -- Empty column_name is the description of the table.
+    column_name TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_schema_description_unique_database_id_table_name_column_name ON schema_description(database_id, table_name, column_name);
+
+ALTER SEQUENCE schema_description_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_description_updated_ts
+BEFORE
+UPDATE
+    ON schema_description FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- change_set marks the pipeline whose tasks must be applied to several databases together.
+-- None of the tasks executes until all tasks pass the task checks, and the pipeline halts with a compensation plan if a task fails.
+CREATE TABLE change_set (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    pipeline_id INTEGER NOT NULL REFERENCES pipeline (id),
+    status TEXT NOT NULL CHECK (status IN ('VERIFYING', 'RUNNING', 'HALTED', 'DONE')) DEFAULT 'VERIFYING',
+    -- The compensation plan is recorded in the payload when the change set halts.
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_change_set_unique_pipeline_id ON change_set(pipeline_id);
+
+ALTER SEQUENCE change_set_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_change_set_updated_ts
+BEFORE
+UPDATE
+    ON change_set FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- issue_sla_setting stores the time to approve and the time to execute the issues in a project.
+CREATE TABLE issue_sla_setting (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    -- The SLA in seconds, 0 means no SLA.
+    time_to_approve BIGINT NOT NULL CHECK (time_to_approve >= 0) DEFAULT 0,
+    time_to_execute BIGINT NOT NULL CHECK (time_to_execute >= 0) DEFAULT 0,
+    -- The percentage of the SLA elapsed before reminding the assignee.
+    reminder_threshold INTEGER NOT NULL CHECK (reminder_threshold > 0 AND reminder_threshold <= 100) DEFAULT 80
+);
+
+CREATE UNIQUE INDEX idx_issue_sla_setting_unique_project_id ON issue_sla_setting(project_id);
+
+ALTER SEQUENCE issue_sla_setting_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_issue_sla_setting_updated_ts
+BEFORE
+UPDATE
+    ON issue_sla_setting FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- usage_metric stores the daily usage metrics aggregated locally before they are reported.
+CREATE TABLE usage_metric (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- The UTC date in YYYY-MM-DD format.
+    date TEXT NOT NULL,
+    name TEXT NOT NULL,
+    -- The labels in JSON format with sorted keys, so that the same labels are stored as the same text.
+    labels TEXT NOT NULL DEFAULT '{}',
+    value BIGINT NOT NULL DEFAULT 0,
+    reported BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX idx_usage_metric_unique_date_name_labels ON usage_metric(date, name, labels);
+
+ALTER SEQUENCE usage_metric_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_usage_metric_updated_ts
+BEFORE
+UPDATE
+    ON usage_metric FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- database_template stores the reusable baselines applied right after creating the databases.
+CREATE TABLE database_template (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    name TEXT NOT NULL,
+    engine TEXT NOT NULL CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE', 'MSSQL', 'MONGODB', 'MARIADB')),
+    description TEXT NOT NULL DEFAULT '',
+    -- The baseline DDL executed in the new database.
+    statement TEXT NOT NULL DEFAULT '',
+    -- The labels in JSON format from a list of DatabaseLabel, the labels of the create database issue take precedence.
+    labels TEXT NOT NULL DEFAULT '[]',
+    -- The extensions created in the new database, only applicable to Postgres.
+    extension_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_database_template_unique_name ON database_template(name);
+
+ALTER SEQUENCE database_template_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_database_template_updated_ts
+BEFORE
+UPDATE
+    ON database_template FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- recurring_issue stores the issue templates instantiated on the cron schedule.
+CREATE TABLE recurring_issue (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    name TEXT NOT NULL,
+    -- The 5-field cron expression evaluated in UTC.
+    schedule TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('bb.issue.database.schema.update', 'bb.issue.database.data.update')),
+    description TEXT NOT NULL DEFAULT '',
+    assignee_id INTEGER NOT NULL REFERENCES principal (id),
+    -- The issue create context, e.g. the databases and the statement to apply.
+    create_context TEXT NOT NULL DEFAULT '{}',
+    auto_approve BOOLEAN NOT NULL DEFAULT FALSE,
+    next_run_ts BIGINT NOT NULL,
+    last_run_ts BIGINT NOT NULL DEFAULT 0,
+    -- The ID of the last instantiated issue, 0 if none.
+    last_issue_id INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_recurring_issue_unique_project_id_name ON recurring_issue(project_id, name);
+
+CREATE INDEX idx_recurring_issue_next_run_ts ON recurring_issue(next_run_ts);
+
+ALTER SEQUENCE recurring_issue_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_recurring_issue_updated_ts
+BEFORE
+UPDATE
+    ON recurring_issue FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- partition_policy stores the partitions managed for the time-partitioned tables.
+CREATE TABLE partition_policy (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- The schema of the table, only applicable to Postgres.
+    schema_name TEXT NOT NULL DEFAULT '',
+    table_name TEXT NOT NULL,
+    range_key TEXT NOT NULL,
+    range_interval TEXT NOT NULL CHECK (range_interval IN ('DAY', 'WEEK', 'MONTH')),
+    retention_count INTEGER NOT NULL DEFAULT 0 CHECK (retention_count >= 0),
+    precreate_count INTEGER NOT NULL DEFAULT 0 CHECK (precreate_count >= 0),
+    assignee_id INTEGER NOT NULL REFERENCES principal (id),
+    last_run_ts BIGINT NOT NULL DEFAULT 0,
+    -- The ID of the last partition maintenance issue, 0 if none.
+    last_issue_id INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_partition_policy_unique_database_id_schema_name_table_name ON partition_policy(database_id, schema_name, table_name);
+
+ALTER SEQUENCE partition_policy_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_partition_policy_updated_ts
+BEFORE
+UPDATE
+    ON partition_policy FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- access_change_history records the user and grant changes, it's separate from the schema migration history.
+-- The rows are immutable, so there is no updater.
+CREATE TABLE access_change_history (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- The ID of the issue, 0 if the task doesn't belong to an issue.
+    issue_id INTEGER NOT NULL DEFAULT 0,
+    task_id INTEGER NOT NULL REFERENCES task (id),
+    action TEXT NOT NULL CHECK (action IN ('CREATE_USER', 'ALTER_USER', 'DROP_USER', 'GRANT', 'REVOKE')),
+    user_name TEXT NOT NULL,
+    -- The executed statement with the password masked.
+    statement TEXT NOT NULL,
+    -- The error executing the statement, empty if it succeeded.
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_access_change_history_instance_id ON access_change_history(instance_id);
+
+ALTER SEQUENCE access_change_history_id_seq RESTART WITH 101;
+
+-- account_report stores the database account inventory reports for the access recertification.
+-- The reports are immutable, so there is no updater.
+CREATE TABLE account_report (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    account_count INTEGER NOT NULL,
+    -- The number of the accounts with any flag, e.g. superuser or unused.
+    flagged_count INTEGER NOT NULL,
+    -- The JSON encoded AccountReportPayload.
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+ALTER SEQUENCE account_report_id_seq RESTART WITH 101;
+
+-- instance_parameter records the synced instance parameter values, a new row is inserted only when the value changes.
+CREATE TABLE instance_parameter (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    name TEXT NOT NULL,
+    value TEXT NOT NULL
+);
+
+CREATE INDEX idx_instance_parameter_instance_id_name ON instance_parameter(instance_id, name);
+
+ALTER SEQUENCE instance_parameter_id_seq RESTART WITH 101;
+
+-- trash records the deleted projects, instances and databases, which are archived and can be restored within the retention window.
+CREATE TABLE trash (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    resource_type TEXT NOT NULL CHECK (resource_type IN ('PROJECT', 'INSTANCE', 'DATABASE')),
+    resource_id INTEGER NOT NULL,
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_trash_unique_resource_type_resource_id ON trash(resource_type, resource_id);
+
+CREATE INDEX idx_trash_created_ts ON trash(created_ts);
+
+ALTER SEQUENCE trash_id_seq RESTART WITH 101;
+
+-- instance_sync_setting stores the automatic schema sync schedule of an instance.
+CREATE TABLE instance_sync_setting (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    -- 0 disables the automatic schema sync.
+    interval_minutes INTEGER NOT NULL CHECK (interval_minutes >= 0),
+    -- JSON encoded list of the weekly windows during which the automatic schema sync doesn't run.
+    blackout_window_list TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE UNIQUE INDEX idx_instance_sync_setting_unique_instance_id ON instance_sync_setting(instance_id);
+
+ALTER SEQUENCE instance_sync_setting_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_instance_sync_setting_updated_ts
+BEFORE
+UPDATE
+    ON instance_sync_setting FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- issue_dependency stores the issues blocking other issues, the first stage of the blocked issue can't start until the blocking issue is done.
+CREATE TABLE issue_dependency (
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    blocking_issue_id INTEGER NOT NULL REFERENCES issue (id),
+    blocked_issue_id INTEGER NOT NULL REFERENCES issue (id),
+    PRIMARY KEY (blocking_issue_id, blocked_issue_id),
+    CHECK (blocking_issue_id <> blocked_issue_id)
+);
+
+CREATE INDEX idx_issue_dependency_blocked_issue_id ON issue_dependency(blocked_issue_id);
+
+-- vcs_group_webhook stores the webhooks registered on the VCS groups, i.e. the GitLab groups, which deliver the push events
+-- of all the repositories in the group and its subgroups. The repositories linked under the group have no webhooks of their own.
+CREATE TABLE vcs_group_webhook (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    vcs_id INTEGER NOT NULL REFERENCES vcs (id),
+    -- Group id from the corresponding VCS provider, e.g. 123.
+    external_group_id TEXT NOT NULL,
+    -- Group full path from the corresponding VCS provider, e.g. group1/subgroup1.
+    group_full_path TEXT NOT NULL,
+    external_webhook_id TEXT NOT NULL,
+    webhook_url_host TEXT NOT NULL,
+    webhook_endpoint_id TEXT NOT NULL,
+    webhook_secret_token TEXT NOT NULL,
+    -- access_token, expires_ts, refresh_token belongs to the user registering the webhook.
+    access_token TEXT NOT NULL,
+    expires_ts BIGINT NOT NULL,
+    refresh_token TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_vcs_group_webhook_unique_vcs_id_external_group_id ON vcs_group_webhook(vcs_id, external_group_id);
+
+CREATE UNIQUE INDEX idx_vcs_group_webhook_unique_webhook_endpoint_id ON vcs_group_webhook(webhook_endpoint_id);
+
+ALTER SEQUENCE vcs_group_webhook_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_vcs_group_webhook_updated_ts
+BEFORE
+UPDATE
+    ON vcs_group_webhook FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- repository_push_event stores the files added by the push events received from the VCS and the processing results,
+-- so the failed ones can be inspected and replayed.
+CREATE TABLE repository_push_event (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    file TEXT NOT NULL,
+    -- JSON encoded vcs.PushEvent of the file.
+    push_event TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('DONE', 'IGNORED', 'FAILED')),
+    message TEXT NOT NULL DEFAULT '',
+    replay_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_repository_push_event_project_id_status ON repository_push_event(project_id, status);
+
+ALTER SEQUENCE repository_push_event_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_repository_push_event_updated_ts
+BEFORE
+UPDATE
+    ON repository_push_event FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- schema_snapshot records the schema dump of a database at each sync and migration, a new row is inserted only when the schema changes.
+-- It allows viewing the schema of a database as of a past time.
+CREATE TABLE schema_snapshot (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    source TEXT NOT NULL CHECK (source IN ('SYNC', 'MIGRATION')),
+    -- version is the migration version for the MIGRATION source.
+    version TEXT NOT NULL DEFAULT '',
+    schema TEXT NOT NULL
+);
+
+CREATE INDEX idx_schema_snapshot_database_id_created_ts ON schema_snapshot(database_id, created_ts);
+
+ALTER SEQUENCE schema_snapshot_id_seq RESTART WITH 101;
+
+-- issue_change_record is the signed snapshot of a done issue for the auditors. It's taken when first requested and
+-- can't be updated or deleted afterwards.
+CREATE TABLE issue_change_record (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL,
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    -- The JSON encoded snapshot. It's TEXT instead of JSONB to keep the signed text byte for byte.
+    payload TEXT NOT NULL,
+    -- The HMAC of the fields above, keyed by the workspace secret.
+    signature TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_issue_change_record_unique_issue_id ON issue_change_record(issue_id);
+
+ALTER SEQUENCE issue_change_record_id_seq RESTART WITH 101;
+
+CREATE OR REPLACE FUNCTION trigger_reject_issue_change_record_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'issue change records are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reject_issue_change_record_change
+BEFORE
+UPDATE OR DELETE
+    ON issue_change_record FOR EACH ROW
+EXECUTE FUNCTION trigger_reject_issue_change_record_change();
+
+-- dashboard_tile stores the read-only queries pinned as the dashboard tiles of a project and their cached results.
+CREATE TABLE dashboard_tile (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    -- The JSON encoded map from the parameter name to the default value.
+    parameters TEXT NOT NULL DEFAULT '{}',
+    refresh TEXT NOT NULL CHECK (refresh IN ('ON_VIEW', 'HOURLY', 'DAILY')),
+    -- The JSON encoded row set of the latest run with the default parameter values.
+    result TEXT NOT NULL DEFAULT '',
+    result_error TEXT NOT NULL DEFAULT '',
+    refreshed_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_dashboard_tile_unique_project_id_name ON dashboard_tile(project_id, name);
+
+ALTER SEQUENCE dashboard_tile_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_dashboard_tile_updated_ts
+BEFORE
+UPDATE
+    ON dashboard_tile FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- session_recording records the statements and the result metadata of the SQL editor and the proxied sessions on the
+-- databases tagged sensitive. Each row is chained to the previous one by hash, and the rows can't be updated or deleted,
+-- so that the recordings are tamper-evident.
+CREATE TABLE session_recording (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL,
+    -- The recordings outlive the instances and the databases, so they aren't referenced by the foreign keys.
+    instance_id INTEGER NOT NULL,
+    instance_name TEXT NOT NULL,
+    -- database_id is 0 and database_name is empty for the instance-level queries.
+    database_id INTEGER NOT NULL DEFAULT 0,
+    database_name TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL CHECK (source IN ('SQL_EDITOR', 'PROXY')),
+    session_id TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    -- The JSON encoded column names of the result, the values aren't recorded.
+    column_names TEXT NOT NULL DEFAULT '[]',
+    row_count INTEGER NOT NULL DEFAULT 0,
+    duration_ns BIGINT NOT NULL DEFAULT 0,
+    -- The reason the statement is rejected or fails, empty if it succeeded.
+    error TEXT NOT NULL DEFAULT '',
+    -- The HMAC of the hash of the previous row and the fields above, keyed by the workspace secret.
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX idx_session_recording_session_id ON session_recording(session_id);
+
+CREATE INDEX idx_session_recording_database_id ON session_recording(database_id);
+
+ALTER SEQUENCE session_recording_id_seq RESTART WITH 101;
+
+CREATE OR REPLACE FUNCTION trigger_reject_session_recording_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'session recordings are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reject_session_recording_change
+BEFORE
+UPDATE OR DELETE
+    ON session_recording FOR EACH ROW
+EXECUTE FUNCTION trigger_reject_session_recording_change();
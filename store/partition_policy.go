@@ -0,0 +1,372 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// partitionPolicyRaw is the store model for a PartitionPolicy.
+// Fields have exactly the same meanings as PartitionPolicy.
+type partitionPolicyRaw struct {
+	ID int
+
+	// Standard fields
+	RowStatus api.RowStatus
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	SchemaName     string
+	TableName      string
+	RangeKey       string
+	Interval       api.PartitionInterval
+	RetentionCount int
+	PrecreateCount int
+	AssigneeID     int
+	LastRunTs      int64
+	LastIssueID    int
+}
+
+// toPartitionPolicy creates an instance of PartitionPolicy based on the partitionPolicyRaw.
+// This is intended to be called when we need to compose a PartitionPolicy relationship.
+func (raw *partitionPolicyRaw) toPartitionPolicy() *api.PartitionPolicy {
+	return &api.PartitionPolicy{
+		ID: raw.ID,
+
+		// Standard fields
+		RowStatus: raw.RowStatus,
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		SchemaName:     raw.SchemaName,
+		TableName:      raw.TableName,
+		RangeKey:       raw.RangeKey,
+		Interval:       raw.Interval,
+		RetentionCount: raw.RetentionCount,
+		PrecreateCount: raw.PrecreateCount,
+		AssigneeID:     raw.AssigneeID,
+		LastRunTs:      raw.LastRunTs,
+		LastIssueID:    raw.LastIssueID,
+	}
+}
+
+// CreatePartitionPolicy creates an instance of PartitionPolicy.
+func (s *Store) CreatePartitionPolicy(ctx context.Context, create *api.PartitionPolicyCreate) (*api.PartitionPolicy, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createPartitionPolicyImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create partition policy with PartitionPolicyCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composePartitionPolicy(ctx, raw)
+}
+
+// GetPartitionPolicyByID gets an instance of PartitionPolicy.
+func (s *Store) GetPartitionPolicyByID(ctx context.Context, id int) (*api.PartitionPolicy, error) {
+	list, err := s.FindPartitionPolicy(ctx, &api.PartitionPolicyFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d partition policies with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// FindPartitionPolicy finds a list of PartitionPolicy instances in the ascending ID order.
+func (s *Store) FindPartitionPolicy(ctx context.Context, find *api.PartitionPolicyFind) ([]*api.PartitionPolicy, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findPartitionPolicyImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find partition policy list with PartitionPolicyFind[%+v], error: %w", find, err)
+	}
+	var policyList []*api.PartitionPolicy
+	for _, raw := range rawList {
+		policy, err := s.composePartitionPolicy(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		policyList = append(policyList, policy)
+	}
+	return policyList, nil
+}
+
+// PatchPartitionPolicy patches an instance of PartitionPolicy.
+func (s *Store) PatchPartitionPolicy(ctx context.Context, patch *api.PartitionPolicyPatch) (*api.PartitionPolicy, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchPartitionPolicyImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch partition policy with PartitionPolicyPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composePartitionPolicy(ctx, raw)
+}
+
+// DeletePartitionPolicy deletes an existing partition policy by ID.
+func (s *Store) DeletePartitionPolicy(ctx context.Context, delete *api.PartitionPolicyDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM partition_policy WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composePartitionPolicy(ctx context.Context, raw *partitionPolicyRaw) (*api.PartitionPolicy, error) {
+	policy := raw.toPartitionPolicy()
+
+	creator, err := s.GetPrincipalByID(ctx, policy.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	policy.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, policy.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	policy.Updater = updater
+
+	return policy, nil
+}
+
+func createPartitionPolicyImpl(ctx context.Context, tx *sql.Tx, create *api.PartitionPolicyCreate) (*partitionPolicyRaw, error) {
+	query := `
+		INSERT INTO partition_policy (
+			creator_id,
+			updater_id,
+			database_id,
+			schema_name,
+			table_name,
+			range_key,
+			range_interval,
+			retention_count,
+			precreate_count,
+			assignee_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, database_id, schema_name, table_name, range_key, range_interval, retention_count, precreate_count, assignee_id, last_run_ts, last_issue_id
+	`
+	var raw partitionPolicyRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.SchemaName,
+		create.TableName,
+		create.RangeKey,
+		create.Interval,
+		create.RetentionCount,
+		create.PrecreateCount,
+		create.AssigneeID,
+	).Scan(
+		&raw.ID,
+		&raw.RowStatus,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.DatabaseID,
+		&raw.SchemaName,
+		&raw.TableName,
+		&raw.RangeKey,
+		&raw.Interval,
+		&raw.RetentionCount,
+		&raw.PrecreateCount,
+		&raw.AssigneeID,
+		&raw.LastRunTs,
+		&raw.LastIssueID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findPartitionPolicyImpl(ctx context.Context, tx *sql.Tx, find *api.PartitionPolicyFind) ([]*partitionPolicyRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			row_status,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			schema_name,
+			table_name,
+			range_key,
+			range_interval,
+			retention_count,
+			precreate_count,
+			assignee_id,
+			last_run_ts,
+			last_issue_id
+		FROM partition_policy
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*partitionPolicyRaw
+	for rows.Next() {
+		var raw partitionPolicyRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.RowStatus,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.DatabaseID,
+			&raw.SchemaName,
+			&raw.TableName,
+			&raw.RangeKey,
+			&raw.Interval,
+			&raw.RetentionCount,
+			&raw.PrecreateCount,
+			&raw.AssigneeID,
+			&raw.LastRunTs,
+			&raw.LastIssueID,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchPartitionPolicyImpl(ctx context.Context, tx *sql.Tx, patch *api.PartitionPolicyPatch) (*partitionPolicyRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.RowStatus; v != nil {
+		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.RowStatus(*v))
+	}
+	if v := patch.RetentionCount; v != nil {
+		set, args = append(set, fmt.Sprintf("retention_count = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.PrecreateCount; v != nil {
+		set, args = append(set, fmt.Sprintf("precreate_count = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AssigneeID; v != nil {
+		set, args = append(set, fmt.Sprintf("assignee_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LastRunTs; v != nil {
+		set, args = append(set, fmt.Sprintf("last_run_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LastIssueID; v != nil {
+		set, args = append(set, fmt.Sprintf("last_issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	var raw partitionPolicyRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE partition_policy
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, database_id, schema_name, table_name, range_key, range_interval, retention_count, precreate_count, assignee_id, last_run_ts, last_issue_id
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.RowStatus,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.DatabaseID,
+		&raw.SchemaName,
+		&raw.TableName,
+		&raw.RangeKey,
+		&raw.Interval,
+		&raw.RetentionCount,
+		&raw.PrecreateCount,
+		&raw.AssigneeID,
+		&raw.LastRunTs,
+		&raw.LastIssueID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("partition policy not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
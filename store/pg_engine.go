@@ -701,8 +701,8 @@ func FormatError(err error) error {
 			return common.Errorf(common.Conflict, "member already exists")
 		case strings.Contains(err.Error(), "idx_environment_unique_name"):
 			return common.Errorf(common.Conflict, "environment name already exists")
-		case strings.Contains(err.Error(), "idx_policy_unique_environment_id_type"):
-			return common.Errorf(common.Conflict, "policy environment and type already exists")
+		case strings.Contains(err.Error(), "idx_policy_unique_environment_id_tag_type"):
+			return common.Errorf(common.Conflict, "policy environment, tag and type already exists")
 		case strings.Contains(err.Error(), "idx_project_unique_key"):
 			return common.Errorf(common.Conflict, "project key already exists")
 		case strings.Contains(err.Error(), "idx_project_member_unique_project_id_role_provider_principal_id"):
@@ -719,6 +719,8 @@ func FormatError(err error) error {
 			return common.Errorf(common.Conflict, "database id, table id and name already exists")
 		case strings.Contains(err.Error(), "idx_idx_unique_database_id_table_id_name_expression"):
 			return common.Errorf(common.Conflict, "database id, table id, name and expression already exists")
+		case strings.Contains(err.Error(), "idx_fk_unique_database_id_table_id_name_position"):
+			return common.Errorf(common.Conflict, "database id, table id, name and position already exists")
 		case strings.Contains(err.Error(), "idx_vw_unique_database_id_name"):
 			return common.Errorf(common.Conflict, "database id and name already exists")
 		case strings.Contains(err.Error(), "idx_data_source_unique_database_id_name"):
@@ -743,6 +745,30 @@ func FormatError(err error) error {
 			return common.Errorf(common.Conflict, "project deployment configuration already exists")
 		case strings.Contains(err.Error(), "issue_subscriber_pkey"):
 			return common.Errorf(common.Conflict, "issue subscriber already exists")
+		case strings.Contains(err.Error(), "idx_db_role_mapping_unique_instance_id_role_name"):
+			return common.Errorf(common.Conflict, "database role mapping already exists")
+		case strings.Contains(err.Error(), "idx_issue_unique_creator_id_idempotency_key"):
+			return common.Errorf(common.Conflict, "issue idempotency key already exists")
+		case strings.Contains(err.Error(), "idx_project_variable_unique_project_id_name"):
+			return common.Errorf(common.Conflict, "project variable already exists")
+		case strings.Contains(err.Error(), "idx_database_template_unique_name"):
+			return common.Errorf(common.Conflict, "database template already exists")
+		case strings.Contains(err.Error(), "idx_issue_field_unique_project_id_name"):
+			return common.Errorf(common.Conflict, "issue field already exists")
+		case strings.Contains(err.Error(), "idx_recurring_issue_unique_project_id_name"):
+			return common.Errorf(common.Conflict, "recurring issue already exists")
+		case strings.Contains(err.Error(), "idx_dashboard_tile_unique_project_id_name"):
+			return common.Errorf(common.Conflict, "dashboard tile already exists")
+		case strings.Contains(err.Error(), "idx_partition_policy_unique_database_id_schema_name_table_name"):
+			return common.Errorf(common.Conflict, "partition policy already exists")
+		case strings.Contains(err.Error(), "idx_trash_unique_resource_type_resource_id"):
+			return common.Errorf(common.Conflict, "resource is already in the trash")
+		case strings.Contains(err.Error(), "idx_vcs_group_webhook_unique_vcs_id_external_group_id"):
+			return common.Errorf(common.Conflict, "group webhook already exists")
+		case strings.Contains(err.Error(), "idx_issue_change_record_unique_issue_id"):
+			return common.Errorf(common.Conflict, "issue change record already exists")
+		case strings.Contains(err.Error(), "idx_agent_unique_name"):
+			return common.Errorf(common.Conflict, "agent name already exists")
 		}
 	}
 	return err
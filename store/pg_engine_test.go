@@ -217,3 +217,11 @@ func TestGetCutoffVersion(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, semver.MustParse("1.3.3"), releaseVersion)
 }
+
+func TestFormatErrorConflict(t *testing.T) {
+	err := FormatError(fmt.Errorf(`pq: duplicate key value violates unique constraint "idx_vcs_group_webhook_unique_vcs_id_external_group_id"`))
+	require.Equal(t, common.Conflict, common.ErrorCode(err))
+
+	err = FormatError(fmt.Errorf(`pq: duplicate key value violates unique constraint "idx_unknown"`))
+	require.Equal(t, common.Internal, common.ErrorCode(err))
+}
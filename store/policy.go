@@ -27,6 +27,7 @@ type policyRaw struct {
 	EnvironmentID int
 
 	// Domain specific fields
+	Tag     string
 	Type    api.PolicyType
 	Payload string
 }
@@ -48,6 +49,7 @@ func (raw *policyRaw) toPolicy() *api.Policy {
 		EnvironmentID: raw.EnvironmentID,
 
 		// Domain specific fields
+		Tag:     raw.Tag,
 		Type:    raw.Type,
 		Payload: raw.Payload,
 	}
@@ -95,6 +97,7 @@ func (s *Store) DeletePolicy(ctx context.Context, delete *api.PolicyDelete) erro
 
 	find := &api.PolicyFind{
 		EnvironmentID: &delete.EnvironmentID,
+		Tag:           &delete.Tag,
 		Type:          &delete.Type,
 	}
 	policyRawList, err := findPolicyImpl(ctx, tx.PTx, find)
@@ -151,11 +154,12 @@ func (s *Store) ListPolicy(ctx context.Context, find *api.PolicyFind) ([]*api.Po
 	return policyList, nil
 }
 
-// GetBackupPlanPolicyByEnvID will get the backup plan policy for an environment.
-func (s *Store) GetBackupPlanPolicyByEnvID(ctx context.Context, environmentID int) (*api.BackupPlanPolicy, error) {
+// GetBackupPlanPolicyByEnvID will get the backup plan policy for an environment and the tags.
+func (s *Store) GetBackupPlanPolicyByEnvID(ctx context.Context, environmentID int, tagList []string) (*api.BackupPlanPolicy, error) {
 	pType := api.PolicyTypeBackupPlan
 	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
 		EnvironmentID: &environmentID,
+		TagList:       tagList,
 		Type:          &pType,
 	})
 	if err != nil {
@@ -164,11 +168,12 @@ func (s *Store) GetBackupPlanPolicyByEnvID(ctx context.Context, environmentID in
 	return api.UnmarshalBackupPlanPolicy(policy.Payload)
 }
 
-// GetPipelineApprovalPolicy will get the pipeline approval policy for an environment.
-func (s *Store) GetPipelineApprovalPolicy(ctx context.Context, environmentID int) (*api.PipelineApprovalPolicy, error) {
+// GetPipelineApprovalPolicy will get the pipeline approval policy for an environment and the tags.
+func (s *Store) GetPipelineApprovalPolicy(ctx context.Context, environmentID int, tagList []string) (*api.PipelineApprovalPolicy, error) {
 	pType := api.PolicyTypePipelineApproval
 	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
 		EnvironmentID: &environmentID,
+		TagList:       tagList,
 		Type:          &pType,
 	})
 	if err != nil {
@@ -177,6 +182,146 @@ func (s *Store) GetPipelineApprovalPolicy(ctx context.Context, environmentID int
 	return api.UnmarshalPipelineApprovalPolicy(policy.Payload)
 }
 
+// GetDataSourcePolicy will get the data source policy for an environment and the tags.
+func (s *Store) GetDataSourcePolicy(ctx context.Context, environmentID int, tagList []string) (*api.DataSourcePolicy, error) {
+	pType := api.PolicyTypeDataSource
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalDataSourcePolicy(policy.Payload)
+}
+
+// GetDatabasePurgePolicy will get the database purge policy for an environment and the tags.
+func (s *Store) GetDatabasePurgePolicy(ctx context.Context, environmentID int, tagList []string) (*api.DatabasePurgePolicy, error) {
+	pType := api.PolicyTypeDatabasePurge
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalDatabasePurgePolicy(policy.Payload)
+}
+
+// GetStatisticsRefreshPolicy will get the statistics refresh policy for an environment and the tags.
+func (s *Store) GetStatisticsRefreshPolicy(ctx context.Context, environmentID int, tagList []string) (*api.StatisticsRefreshPolicy, error) {
+	pType := api.PolicyTypeStatisticsRefresh
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalStatisticsRefreshPolicy(policy.Payload)
+}
+
+// GetStageGatePolicy will get the stage gate policy for an environment and the tags.
+func (s *Store) GetStageGatePolicy(ctx context.Context, environmentID int, tagList []string) (*api.StageGatePolicy, error) {
+	pType := api.PolicyTypeStageGate
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalStageGatePolicy(policy.Payload)
+}
+
+// GetReplicationLagPolicy will get the replication lag policy for an environment and the tags.
+func (s *Store) GetReplicationLagPolicy(ctx context.Context, environmentID int, tagList []string) (*api.ReplicationLagPolicy, error) {
+	pType := api.PolicyTypeReplicationLag
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalReplicationLagPolicy(policy.Payload)
+}
+
+// GetAccessChangePolicy will get the access change policy for an environment and the tags.
+func (s *Store) GetAccessChangePolicy(ctx context.Context, environmentID int, tagList []string) (*api.AccessChangePolicy, error) {
+	pType := api.PolicyTypeAccessChange
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalAccessChangePolicy(policy.Payload)
+}
+
+// GetParameterBaselinePolicy will get the parameter baseline policy for an environment and the tags.
+func (s *Store) GetParameterBaselinePolicy(ctx context.Context, environmentID int, tagList []string) (*api.ParameterBaselinePolicy, error) {
+	pType := api.PolicyTypeParameterBaseline
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalParameterBaselinePolicy(policy.Payload)
+}
+
+// GetSessionSettingPolicy will get the session setting policy for an environment and the tags.
+func (s *Store) GetSessionSettingPolicy(ctx context.Context, environmentID int, tagList []string) (*api.SessionSettingPolicy, error) {
+	pType := api.PolicyTypeSessionSetting
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalSessionSettingPolicy(policy.Payload)
+}
+
+// GetSQLEditorQueryPolicy will get the SQL editor query policy for an environment and the tags.
+func (s *Store) GetSQLEditorQueryPolicy(ctx context.Context, environmentID int, tagList []string) (*api.SQLEditorQueryPolicy, error) {
+	pType := api.PolicyTypeSQLEditorQuery
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalSQLEditorQueryPolicy(policy.Payload)
+}
+
+// GetDataMaskingPolicy will get the data masking policy for an environment and the tags.
+func (s *Store) GetDataMaskingPolicy(ctx context.Context, environmentID int, tagList []string) (*api.DataMaskingPolicy, error) {
+	pType := api.PolicyTypeDataMasking
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		TagList:       tagList,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalDataMaskingPolicy(policy.Payload)
+}
+
 // GetNormalSQLReviewPolicy will get the normal SQL review policy for an environment.
 func (s *Store) GetNormalSQLReviewPolicy(ctx context.Context, find *api.PolicyFind) (*advisor.SQLReviewPolicy, error) {
 	if find.ID != nil && *find.ID == api.DefaultPolicyID {
@@ -198,11 +343,12 @@ func (s *Store) GetNormalSQLReviewPolicy(ctx context.Context, find *api.PolicyFi
 	return api.UnmarshalSQLReviewPolicy(policy.Payload)
 }
 
-// GetSQLReviewPolicyIDByEnvID will get the SQL review policy ID for an environment.
-func (s *Store) GetSQLReviewPolicyIDByEnvID(ctx context.Context, environmentID int) (int, error) {
+// GetSQLReviewPolicyIDByEnvID will get the SQL review policy ID for an environment and the tags.
+func (s *Store) GetSQLReviewPolicyIDByEnvID(ctx context.Context, environmentID int, tagList []string) (int, error) {
 	pType := api.PolicyTypeSQLReview
 	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
 		EnvironmentID: &environmentID,
+		TagList:       tagList,
 		Type:          &pType,
 	})
 	if err != nil {
@@ -240,6 +386,8 @@ func (s *Store) composePolicy(ctx context.Context, raw *policyRaw) (*api.Policy,
 }
 
 // getPolicyRaw finds the policy for an environment.
+// If finding by the environment without the exact tag, the tag policies of find.TagList are selected in order,
+// and the environment policy applies if none of the tags has one.
 // Returns ECONFLICT if finding more than 1 matching records.
 func (s *Store) getPolicyRaw(ctx context.Context, find *api.PolicyFind) (*policyRaw, error) {
 	// Validate policy type existence.
@@ -259,6 +407,9 @@ func (s *Store) getPolicyRaw(ctx context.Context, find *api.PolicyFind) (*policy
 	if err != nil {
 		return nil, err
 	}
+	if find.ID == nil && find.Tag == nil {
+		policyRawList = selectPolicyByTag(policyRawList, find.TagList)
+	}
 
 	if len(policyRawList) == 0 {
 		ret = &policyRaw{
@@ -269,6 +420,9 @@ func (s *Store) getPolicyRaw(ctx context.Context, find *api.PolicyFind) (*policy
 		if find.EnvironmentID != nil {
 			ret.EnvironmentID = *find.EnvironmentID
 		}
+		if find.Tag != nil {
+			ret.Tag = *find.Tag
+		}
 	} else if len(policyRawList) > 1 {
 		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d policy with filter %+v, expect 1. ", len(policyRawList), find)}
 	} else {
@@ -287,6 +441,23 @@ func (s *Store) getPolicyRaw(ctx context.Context, find *api.PolicyFind) (*policy
 	return ret, nil
 }
 
+// selectPolicyByTag selects the policies of the first tag in tagList having any, or the environment policies with the empty tag.
+func selectPolicyByTag(policyRawList []*policyRaw, tagList []string) []*policyRaw {
+	candidateList := append(append([]string{}, tagList...), "")
+	for _, tag := range candidateList {
+		var selected []*policyRaw
+		for _, policyRaw := range policyRawList {
+			if policyRaw.Tag == tag {
+				selected = append(selected, policyRaw)
+			}
+		}
+		if len(selected) > 0 {
+			return selected
+		}
+	}
+	return nil
+}
+
 func findPolicyImpl(ctx context.Context, tx *sql.Tx, find *api.PolicyFind) ([]*policyRaw, error) {
 	// Build WHERE clause.
 	where, args := []string{"1 = 1"}, []interface{}{}
@@ -296,6 +467,9 @@ func findPolicyImpl(ctx context.Context, tx *sql.Tx, find *api.PolicyFind) ([]*p
 	if v := find.EnvironmentID; v != nil {
 		where, args = append(where, fmt.Sprintf("environment_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Tag; v != nil {
+		where, args = append(where, fmt.Sprintf("tag = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.Type; v != nil {
 		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
 	}
@@ -309,6 +483,7 @@ func findPolicyImpl(ctx context.Context, tx *sql.Tx, find *api.PolicyFind) ([]*p
 			updated_ts,
 			row_status,
 			environment_id,
+			tag,
 			type,
 			payload
 		FROM policy
@@ -332,6 +507,7 @@ func findPolicyImpl(ctx context.Context, tx *sql.Tx, find *api.PolicyFind) ([]*p
 			&policyRaw.UpdatedTs,
 			&policyRaw.RowStatus,
 			&policyRaw.EnvironmentID,
+			&policyRaw.Tag,
 			&policyRaw.Type,
 			&policyRaw.Payload,
 		); err != nil {
@@ -355,6 +531,11 @@ func (s *Store) upsertPolicyRaw(ctx context.Context, upsert *api.PolicyUpsert) (
 			return nil, &common.Error{Code: common.Invalid, Err: err}
 		}
 	}
+	if upsert.Tag != "" {
+		if err := api.ValidateTag(upsert.Tag); err != nil {
+			return nil, &common.Error{Code: common.Invalid, Err: err}
+		}
+	}
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, FormatError(err)
@@ -373,7 +554,7 @@ func (s *Store) upsertPolicyRaw(ctx context.Context, upsert *api.PolicyUpsert) (
 	return policy, nil
 }
 
-// upsertPolicyImpl updates an existing policy by environment id and type.
+// upsertPolicyImpl updates an existing policy by environment id, tag and type.
 func upsertPolicyImpl(ctx context.Context, tx *sql.Tx, upsert *api.PolicyUpsert) (*policyRaw, error) {
 	// Upsert row into policy.
 	var set []string
@@ -402,20 +583,22 @@ func upsertPolicyImpl(ctx context.Context, tx *sql.Tx, upsert *api.PolicyUpsert)
 			creator_id,
 			updater_id,
 			environment_id,
+			tag,
 			type,
 			payload,
 			row_status
 		)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT(environment_id, type) DO UPDATE SET
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT(environment_id, tag, type) DO UPDATE SET
 			%s
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, row_status, environment_id, type, payload
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, row_status, environment_id, tag, type, payload
 	`, strings.Join(set, ","))
 	var policyRaw policyRaw
 	if err := tx.QueryRowContext(ctx, query,
 		upsert.UpdaterID,
 		upsert.UpdaterID,
 		upsert.EnvironmentID,
+		upsert.Tag,
 		upsert.Type,
 		upsert.Payload,
 		upsert.RowStatus,
@@ -427,6 +610,7 @@ func upsertPolicyImpl(ctx context.Context, tx *sql.Tx, upsert *api.PolicyUpsert)
 		&policyRaw.UpdatedTs,
 		&policyRaw.RowStatus,
 		&policyRaw.EnvironmentID,
+		&policyRaw.Tag,
 		&policyRaw.Type,
 		&policyRaw.Payload,
 	); err != nil {
@@ -438,14 +622,15 @@ func upsertPolicyImpl(ctx context.Context, tx *sql.Tx, upsert *api.PolicyUpsert)
 	return &policyRaw, nil
 }
 
-// deletePolicyImpl deletes an existing ARCHIVED policy by id and type.
+// deletePolicyImpl deletes an existing ARCHIVED policy by environment id, tag and type.
 func (*Store) deletePolicyImpl(ctx context.Context, tx *sql.Tx, delete *api.PolicyDelete) error {
 	// Remove row from policy.
 	if _, err := tx.ExecContext(ctx, `
 		DELETE FROM policy
-			WHERE environment_id = $1 AND type = $2 AND row_status = $3
+			WHERE environment_id = $1 AND tag = $2 AND type = $3 AND row_status = $4
 		`,
 		delete.EnvironmentID,
+		delete.Tag,
 		delete.Type,
 		api.Archived,
 	); err != nil {
@@ -375,6 +375,9 @@ func patchPrincipalImpl(ctx context.Context, tx *sql.Tx, patch *api.PrincipalPat
 	if v := patch.PasswordHash; v != nil {
 		set, args = append(set, fmt.Sprintf("password_hash = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.Email; v != nil {
+		set, args = append(set, fmt.Sprintf("email = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -24,13 +24,16 @@ type projectRaw struct {
 	UpdatedTs int64
 
 	// Domain specific fields
-	Name           string
-	Key            string
-	WorkflowType   api.ProjectWorkflowType
-	Visibility     api.ProjectVisibility
-	TenantMode     api.ProjectTenantMode
-	DBNameTemplate string
-	RoleProvider   api.ProjectRoleProvider
+	Name                   string
+	Key                    string
+	WorkflowType           api.ProjectWorkflowType
+	Visibility             api.ProjectVisibility
+	TenantMode             api.ProjectTenantMode
+	DBNameTemplate         string
+	RoleProvider           api.ProjectRoleProvider
+	PinnedNote             string
+	DBNamePattern          string
+	AllowedEnvironmentList string
 }
 
 // toProject creates an instance of Project based on the projectRaw.
@@ -45,13 +48,16 @@ func (raw *projectRaw) toProject() *api.Project {
 		UpdaterID: raw.UpdaterID,
 		UpdatedTs: raw.UpdatedTs,
 
-		Name:           raw.Name,
-		Key:            raw.Key,
-		WorkflowType:   raw.WorkflowType,
-		Visibility:     raw.Visibility,
-		TenantMode:     raw.TenantMode,
-		DBNameTemplate: raw.DBNameTemplate,
-		RoleProvider:   raw.RoleProvider,
+		Name:                   raw.Name,
+		Key:                    raw.Key,
+		WorkflowType:           raw.WorkflowType,
+		Visibility:             raw.Visibility,
+		TenantMode:             raw.TenantMode,
+		DBNameTemplate:         raw.DBNameTemplate,
+		RoleProvider:           raw.RoleProvider,
+		PinnedNote:             raw.PinnedNote,
+		DBNamePattern:          raw.DBNamePattern,
+		AllowedEnvironmentList: raw.AllowedEnvironmentList,
 	}
 }
 
@@ -321,7 +327,7 @@ func createProjectImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectCreat
 			role_provider
 		)
 		VALUES ($1, $2, $3, $4, 'UI', 'PUBLIC', $5, $6, $7)
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider, pinned_note, db_name_pattern, allowed_environment_list
 	`
 	var project projectRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -346,6 +352,9 @@ func createProjectImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectCreat
 		&project.TenantMode,
 		&project.DBNameTemplate,
 		&project.RoleProvider,
+		&project.PinnedNote,
+		&project.DBNamePattern,
+		&project.AllowedEnvironmentList,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -364,6 +373,9 @@ func findProjectImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectFind) ([]
 	if v := find.RowStatus; v != nil {
 		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Key; v != nil {
+		where, args = append(where, fmt.Sprintf("key = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.PrincipalID; v != nil {
 		where, args = append(where, fmt.Sprintf("id IN (SELECT project_id FROM project_member WHERE principal_id = $%d)", len(args)+1)), append(args, *v)
 	}
@@ -382,7 +394,10 @@ func findProjectImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectFind) ([]
 			visibility,
 			tenant_mode,
 			db_name_template,
-			role_provider
+			role_provider,
+			pinned_note,
+			db_name_pattern,
+			allowed_environment_list
 		FROM project
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -410,6 +425,9 @@ func findProjectImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectFind) ([]
 			&project.TenantMode,
 			&project.DBNameTemplate,
 			&project.RoleProvider,
+			&project.PinnedNote,
+			&project.DBNamePattern,
+			&project.AllowedEnvironmentList,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -442,6 +460,15 @@ func patchProjectImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectPatch)
 	if v := patch.RoleProvider; v != nil {
 		set, args = append(set, fmt.Sprintf("role_provider = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.PinnedNote; v != nil {
+		set, args = append(set, fmt.Sprintf("pinned_note = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.DBNamePattern; v != nil {
+		set, args = append(set, fmt.Sprintf("db_name_pattern = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AllowedEnvironmentList; v != nil {
+		set, args = append(set, fmt.Sprintf("allowed_environment_list = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -451,7 +478,7 @@ func patchProjectImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectPatch)
 		UPDATE project
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, name, key, workflow_type, visibility, tenant_mode, db_name_template, role_provider, pinned_note, db_name_pattern, allowed_environment_list
 	`, len(args)),
 		args...,
 	).Scan(
@@ -468,6 +495,9 @@ func patchProjectImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectPatch)
 		&project.TenantMode,
 		&project.DBNameTemplate,
 		&project.RoleProvider,
+		&project.PinnedNote,
+		&project.DBNamePattern,
+		&project.AllowedEnvironmentList,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("project ID not found: %d", patch.ID)}
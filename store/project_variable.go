@@ -0,0 +1,296 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// projectVariableRaw is the store model for a ProjectVariable.
+// Fields have exactly the same meanings as ProjectVariable.
+type projectVariableRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Name  string
+	Value string
+}
+
+// toProjectVariable creates an instance of ProjectVariable based on the projectVariableRaw.
+// This is intended to be called when we need to compose a ProjectVariable relationship.
+func (raw *projectVariableRaw) toProjectVariable() *api.ProjectVariable {
+	return &api.ProjectVariable{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID: raw.ProjectID,
+
+		// Domain specific fields
+		Name:  raw.Name,
+		Value: raw.Value,
+	}
+}
+
+// CreateProjectVariable creates an instance of ProjectVariable.
+func (s *Store) CreateProjectVariable(ctx context.Context, create *api.ProjectVariableCreate) (*api.ProjectVariable, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createProjectVariableImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project variable with ProjectVariableCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeProjectVariable(ctx, raw)
+}
+
+// GetProjectVariableByID gets an instance of ProjectVariable.
+func (s *Store) GetProjectVariableByID(ctx context.Context, id int) (*api.ProjectVariable, error) {
+	list, err := s.FindProjectVariable(ctx, &api.ProjectVariableFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d project variables with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// FindProjectVariable finds a list of ProjectVariable instances in the ascending name order.
+func (s *Store) FindProjectVariable(ctx context.Context, find *api.ProjectVariableFind) ([]*api.ProjectVariable, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findProjectVariableImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project variable list with ProjectVariableFind[%+v], error: %w", find, err)
+	}
+	var variableList []*api.ProjectVariable
+	for _, raw := range rawList {
+		variable, err := s.composeProjectVariable(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		variableList = append(variableList, variable)
+	}
+	return variableList, nil
+}
+
+// PatchProjectVariable patches an instance of ProjectVariable.
+func (s *Store) PatchProjectVariable(ctx context.Context, patch *api.ProjectVariablePatch) (*api.ProjectVariable, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchProjectVariableImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch project variable with ProjectVariablePatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeProjectVariable(ctx, raw)
+}
+
+// DeleteProjectVariable deletes an existing project variable by ID.
+func (s *Store) DeleteProjectVariable(ctx context.Context, delete *api.ProjectVariableDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM project_variable WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeProjectVariable(ctx context.Context, raw *projectVariableRaw) (*api.ProjectVariable, error) {
+	variable := raw.toProjectVariable()
+
+	creator, err := s.GetPrincipalByID(ctx, variable.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	variable.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, variable.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	variable.Updater = updater
+
+	return variable, nil
+}
+
+func createProjectVariableImpl(ctx context.Context, tx *sql.Tx, create *api.ProjectVariableCreate) (*projectVariableRaw, error) {
+	query := `
+		INSERT INTO project_variable (
+			creator_id,
+			updater_id,
+			project_id,
+			name,
+			value
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, name, value
+	`
+	var raw projectVariableRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.ProjectID,
+		create.Name,
+		create.Value,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.Name,
+		&raw.Value,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findProjectVariableImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectVariableFind) ([]*projectVariableRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			name,
+			value
+		FROM project_variable
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*projectVariableRaw
+	for rows.Next() {
+		var raw projectVariableRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.ProjectID,
+			&raw.Name,
+			&raw.Value,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchProjectVariableImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectVariablePatch) (*projectVariableRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Value; v != nil {
+		set, args = append(set, fmt.Sprintf("value = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	var raw projectVariableRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE project_variable
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, name, value
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.Name,
+		&raw.Value,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("project variable not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
@@ -342,12 +342,9 @@ func findProjectWebhookImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectWe
 			return nil, FormatError(err)
 		}
 
-		if v := find.ActivityType; v != nil {
-			for _, activity := range projectWebhookRaw.ActivityList {
-				if api.ActivityType(activity) == *v {
-					projectWebhookRawList = append(projectWebhookRawList, &projectWebhookRaw)
-					break
-				}
+		if v := find.ActivityTypeList; v != nil {
+			if hasProjectWebhookActivity(projectWebhookRaw.ActivityList, v) {
+				projectWebhookRawList = append(projectWebhookRawList, &projectWebhookRaw)
 			}
 		} else {
 			projectWebhookRawList = append(projectWebhookRawList, &projectWebhookRaw)
@@ -360,6 +357,18 @@ func findProjectWebhookImpl(ctx context.Context, tx *sql.Tx, find *api.ProjectWe
 	return projectWebhookRawList, nil
 }
 
+// hasProjectWebhookActivity returns true if the webhook activity list contains any of the activity types.
+func hasProjectWebhookActivity(activityList []string, activityTypeList []api.ActivityType) bool {
+	for _, activity := range activityList {
+		for _, activityType := range activityTypeList {
+			if api.ActivityType(activity) == activityType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // patchProjectWebhookImpl updates a projectWebhook by ID. Returns the new state of the projectWebhook after update.
 func patchProjectWebhookImpl(ctx context.Context, tx *sql.Tx, patch *api.ProjectWebhookPatch) (*projectWebhookRaw, error) {
 	// Build UPDATE clause.
@@ -0,0 +1,395 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// recurringIssueRaw is the store model for a RecurringIssue.
+// Fields have exactly the same meanings as RecurringIssue.
+type recurringIssueRaw struct {
+	ID int
+
+	// Standard fields
+	RowStatus api.RowStatus
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Name          string
+	Schedule      string
+	Type          api.IssueType
+	Description   string
+	AssigneeID    int
+	CreateContext string
+	AutoApprove   bool
+	NextRunTs     int64
+	LastRunTs     int64
+	LastIssueID   int
+}
+
+// toRecurringIssue creates an instance of RecurringIssue based on the recurringIssueRaw.
+// This is intended to be called when we need to compose a RecurringIssue relationship.
+func (raw *recurringIssueRaw) toRecurringIssue() *api.RecurringIssue {
+	return &api.RecurringIssue{
+		ID: raw.ID,
+
+		// Standard fields
+		RowStatus: raw.RowStatus,
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID: raw.ProjectID,
+
+		// Domain specific fields
+		Name:          raw.Name,
+		Schedule:      raw.Schedule,
+		Type:          raw.Type,
+		Description:   raw.Description,
+		AssigneeID:    raw.AssigneeID,
+		CreateContext: raw.CreateContext,
+		AutoApprove:   raw.AutoApprove,
+		NextRunTs:     raw.NextRunTs,
+		LastRunTs:     raw.LastRunTs,
+		LastIssueID:   raw.LastIssueID,
+	}
+}
+
+// CreateRecurringIssue creates an instance of RecurringIssue.
+func (s *Store) CreateRecurringIssue(ctx context.Context, create *api.RecurringIssueCreate) (*api.RecurringIssue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createRecurringIssueImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recurring issue with RecurringIssueCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeRecurringIssue(ctx, raw)
+}
+
+// GetRecurringIssueByID gets an instance of RecurringIssue.
+func (s *Store) GetRecurringIssueByID(ctx context.Context, id int) (*api.RecurringIssue, error) {
+	list, err := s.FindRecurringIssue(ctx, &api.RecurringIssueFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d recurring issues with ID %d, expect 1", len(list), id)}
+	}
+	return list[0], nil
+}
+
+// FindRecurringIssue finds a list of RecurringIssue instances in the ascending ID order.
+func (s *Store) FindRecurringIssue(ctx context.Context, find *api.RecurringIssueFind) ([]*api.RecurringIssue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findRecurringIssueImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recurring issue list with RecurringIssueFind[%+v], error: %w", find, err)
+	}
+	var recurringIssueList []*api.RecurringIssue
+	for _, raw := range rawList {
+		recurringIssue, err := s.composeRecurringIssue(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		recurringIssueList = append(recurringIssueList, recurringIssue)
+	}
+	return recurringIssueList, nil
+}
+
+// PatchRecurringIssue patches an instance of RecurringIssue.
+func (s *Store) PatchRecurringIssue(ctx context.Context, patch *api.RecurringIssuePatch) (*api.RecurringIssue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchRecurringIssueImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch recurring issue with RecurringIssuePatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeRecurringIssue(ctx, raw)
+}
+
+// DeleteRecurringIssue deletes an existing recurring issue by ID.
+func (s *Store) DeleteRecurringIssue(ctx context.Context, delete *api.RecurringIssueDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM recurring_issue WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeRecurringIssue(ctx context.Context, raw *recurringIssueRaw) (*api.RecurringIssue, error) {
+	recurringIssue := raw.toRecurringIssue()
+
+	creator, err := s.GetPrincipalByID(ctx, recurringIssue.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	recurringIssue.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, recurringIssue.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	recurringIssue.Updater = updater
+
+	return recurringIssue, nil
+}
+
+func createRecurringIssueImpl(ctx context.Context, tx *sql.Tx, create *api.RecurringIssueCreate) (*recurringIssueRaw, error) {
+	query := `
+		INSERT INTO recurring_issue (
+			creator_id,
+			updater_id,
+			project_id,
+			name,
+			schedule,
+			type,
+			description,
+			assignee_id,
+			create_context,
+			auto_approve,
+			next_run_ts
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, project_id, name, schedule, type, description, assignee_id, create_context, auto_approve, next_run_ts, last_run_ts, last_issue_id
+	`
+	var raw recurringIssueRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.ProjectID,
+		create.Name,
+		create.Schedule,
+		create.Type,
+		create.Description,
+		create.AssigneeID,
+		create.CreateContext,
+		create.AutoApprove,
+		create.NextRunTs,
+	).Scan(
+		&raw.ID,
+		&raw.RowStatus,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.Name,
+		&raw.Schedule,
+		&raw.Type,
+		&raw.Description,
+		&raw.AssigneeID,
+		&raw.CreateContext,
+		&raw.AutoApprove,
+		&raw.NextRunTs,
+		&raw.LastRunTs,
+		&raw.LastIssueID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findRecurringIssueImpl(ctx context.Context, tx *sql.Tx, find *api.RecurringIssueFind) ([]*recurringIssueRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DueBeforeTs; v != nil {
+		where, args = append(where, fmt.Sprintf("next_run_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			row_status,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			name,
+			schedule,
+			type,
+			description,
+			assignee_id,
+			create_context,
+			auto_approve,
+			next_run_ts,
+			last_run_ts,
+			last_issue_id
+		FROM recurring_issue
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*recurringIssueRaw
+	for rows.Next() {
+		var raw recurringIssueRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.RowStatus,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.ProjectID,
+			&raw.Name,
+			&raw.Schedule,
+			&raw.Type,
+			&raw.Description,
+			&raw.AssigneeID,
+			&raw.CreateContext,
+			&raw.AutoApprove,
+			&raw.NextRunTs,
+			&raw.LastRunTs,
+			&raw.LastIssueID,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchRecurringIssueImpl(ctx context.Context, tx *sql.Tx, patch *api.RecurringIssuePatch) (*recurringIssueRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.RowStatus; v != nil {
+		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.RowStatus(*v))
+	}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Schedule; v != nil {
+		set, args = append(set, fmt.Sprintf("schedule = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Description; v != nil {
+		set, args = append(set, fmt.Sprintf("description = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AssigneeID; v != nil {
+		set, args = append(set, fmt.Sprintf("assignee_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.CreateContext; v != nil {
+		set, args = append(set, fmt.Sprintf("create_context = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AutoApprove; v != nil {
+		set, args = append(set, fmt.Sprintf("auto_approve = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.NextRunTs; v != nil {
+		set, args = append(set, fmt.Sprintf("next_run_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LastRunTs; v != nil {
+		set, args = append(set, fmt.Sprintf("last_run_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.LastIssueID; v != nil {
+		set, args = append(set, fmt.Sprintf("last_issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	var raw recurringIssueRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE recurring_issue
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, row_status, creator_id, created_ts, updater_id, updated_ts, project_id, name, schedule, type, description, assignee_id, create_context, auto_approve, next_run_ts, last_run_ts, last_issue_id
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.RowStatus,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.Name,
+		&raw.Schedule,
+		&raw.Type,
+		&raw.Description,
+		&raw.AssigneeID,
+		&raw.CreateContext,
+		&raw.AutoApprove,
+		&raw.NextRunTs,
+		&raw.LastRunTs,
+		&raw.LastIssueID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("recurring issue not found with ID %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 // repositoryRaw is the store model for a Repository.
@@ -26,22 +27,26 @@ type repositoryRaw struct {
 	ProjectID int
 
 	// Domain specific fields
-	Name               string
-	FullPath           string
-	WebURL             string
-	BranchFilter       string
-	BaseDirectory      string
-	FilePathTemplate   string
-	SchemaPathTemplate string
-	SheetPathTemplate  string
-	ExternalID         string
-	ExternalWebhookID  string
-	WebhookURLHost     string
-	WebhookEndpointID  string
-	WebhookSecretToken string
-	AccessToken        string
-	ExpiresTs          int64
-	RefreshToken       string
+	Name                string
+	FullPath            string
+	WebURL              string
+	BranchFilter        string
+	BaseDirectory       string
+	FilePathTemplate    string
+	FileFormat          db.MigrationFileFormat
+	SchemaPathTemplate  string
+	SheetPathTemplate   string
+	EnvironmentMapping  string
+	RequireSignedCommit bool
+	TrustedSigningKeys  string
+	ExternalID          string
+	ExternalWebhookID   string
+	WebhookURLHost      string
+	WebhookEndpointID   string
+	WebhookSecretToken  string
+	AccessToken         string
+	ExpiresTs           int64
+	RefreshToken        string
 }
 
 // toRepository creates an instance of Repository based on the repositoryRaw.
@@ -58,22 +63,26 @@ func (raw *repositoryRaw) toRepository() *api.Repository {
 		VCSID:     raw.VCSID,
 		ProjectID: raw.ProjectID,
 
-		Name:               raw.Name,
-		FullPath:           raw.FullPath,
-		WebURL:             raw.WebURL,
-		BranchFilter:       raw.BranchFilter,
-		BaseDirectory:      raw.BaseDirectory,
-		FilePathTemplate:   raw.FilePathTemplate,
-		SchemaPathTemplate: raw.SchemaPathTemplate,
-		SheetPathTemplate:  raw.SheetPathTemplate,
-		ExternalID:         raw.ExternalID,
-		ExternalWebhookID:  raw.ExternalWebhookID,
-		WebhookURLHost:     raw.WebhookURLHost,
-		WebhookEndpointID:  raw.WebhookEndpointID,
-		WebhookSecretToken: raw.WebhookSecretToken,
-		AccessToken:        raw.AccessToken,
-		ExpiresTs:          raw.ExpiresTs,
-		RefreshToken:       raw.RefreshToken,
+		Name:                raw.Name,
+		FullPath:            raw.FullPath,
+		WebURL:              raw.WebURL,
+		BranchFilter:        raw.BranchFilter,
+		BaseDirectory:       raw.BaseDirectory,
+		FilePathTemplate:    raw.FilePathTemplate,
+		FileFormat:          raw.FileFormat,
+		SchemaPathTemplate:  raw.SchemaPathTemplate,
+		SheetPathTemplate:   raw.SheetPathTemplate,
+		EnvironmentMapping:  raw.EnvironmentMapping,
+		RequireSignedCommit: raw.RequireSignedCommit,
+		TrustedSigningKeys:  raw.TrustedSigningKeys,
+		ExternalID:          raw.ExternalID,
+		ExternalWebhookID:   raw.ExternalWebhookID,
+		WebhookURLHost:      raw.WebhookURLHost,
+		WebhookEndpointID:   raw.WebhookEndpointID,
+		WebhookSecretToken:  raw.WebhookSecretToken,
+		AccessToken:         raw.AccessToken,
+		ExpiresTs:           raw.ExpiresTs,
+		RefreshToken:        raw.RefreshToken,
 	}
 }
 
@@ -300,8 +309,12 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *sql.Tx, create *ap
 				branch_filter,
 				base_directory,
 				file_path_template,
+				file_format,
 				schema_path_template,
 				sheet_path_template,
+				environment_mapping,
+				require_signed_commit,
+				trusted_signing_keys,
 				external_id,
 				external_webhook_id,
 				webhook_url_host,
@@ -311,8 +324,8 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *sql.Tx, create *ap
 				expires_ts,
 				refresh_token
 			)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
-			RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, project_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, schema_path_template, sheet_path_template, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+			RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, project_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, file_format, schema_path_template, sheet_path_template, environment_mapping, require_signed_commit, trusted_signing_keys, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
 		`
 		if err := tx.QueryRowContext(ctx, query,
 			create.CreatorID,
@@ -325,8 +338,12 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *sql.Tx, create *ap
 			create.BranchFilter,
 			create.BaseDirectory,
 			create.FilePathTemplate,
+			create.FileFormat,
 			create.SchemaPathTemplate,
 			create.SheetPathTemplate,
+			create.EnvironmentMapping,
+			create.RequireSignedCommit,
+			create.TrustedSigningKeys,
 			create.ExternalID,
 			create.ExternalWebhookID,
 			create.WebhookURLHost,
@@ -349,8 +366,12 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *sql.Tx, create *ap
 			&repository.BranchFilter,
 			&repository.BaseDirectory,
 			&repository.FilePathTemplate,
+			&repository.FileFormat,
 			&repository.SchemaPathTemplate,
 			&repository.SheetPathTemplate,
+			&repository.EnvironmentMapping,
+			&repository.RequireSignedCommit,
+			&repository.TrustedSigningKeys,
 			&repository.ExternalID,
 			&repository.ExternalWebhookID,
 			&repository.WebhookURLHost,
@@ -475,8 +496,12 @@ func findRepositoryImpl(ctx context.Context, tx *sql.Tx, find *api.RepositoryFin
 			branch_filter,
 			base_directory,
 			file_path_template,
+			file_format,
 			schema_path_template,
 			sheet_path_template,
+			environment_mapping,
+			require_signed_commit,
+			trusted_signing_keys,
 			external_id,
 			external_webhook_id,
 			webhook_url_host,
@@ -512,8 +537,12 @@ func findRepositoryImpl(ctx context.Context, tx *sql.Tx, find *api.RepositoryFin
 			&repository.BranchFilter,
 			&repository.BaseDirectory,
 			&repository.FilePathTemplate,
+			&repository.FileFormat,
 			&repository.SchemaPathTemplate,
 			&repository.SheetPathTemplate,
+			&repository.EnvironmentMapping,
+			&repository.RequireSignedCommit,
+			&repository.TrustedSigningKeys,
 			&repository.ExternalID,
 			&repository.ExternalWebhookID,
 			&repository.WebhookURLHost,
@@ -548,12 +577,24 @@ func patchRepositoryImpl(ctx context.Context, tx *sql.Tx, patch *api.RepositoryP
 	if v := patch.FilePathTemplate; v != nil {
 		set, args = append(set, fmt.Sprintf("file_path_template = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.FileFormat; v != nil {
+		set, args = append(set, fmt.Sprintf("file_format = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := patch.SchemaPathTemplate; v != nil {
 		set, args = append(set, fmt.Sprintf("schema_path_template = $%d", len(args)+1)), append(args, *v)
 	}
 	if v := patch.SheetPathTemplate; v != nil {
 		set, args = append(set, fmt.Sprintf("sheet_path_template = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.EnvironmentMapping; v != nil {
+		set, args = append(set, fmt.Sprintf("environment_mapping = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.RequireSignedCommit; v != nil {
+		set, args = append(set, fmt.Sprintf("require_signed_commit = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.TrustedSigningKeys; v != nil {
+		set, args = append(set, fmt.Sprintf("trusted_signing_keys = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := patch.AccessToken; v != nil {
 		set, args = append(set, fmt.Sprintf("access_token = $%d", len(args)+1)), append(args, *v)
 	}
@@ -572,7 +613,7 @@ func patchRepositoryImpl(ctx context.Context, tx *sql.Tx, patch *api.RepositoryP
 		UPDATE repository
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, project_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, schema_path_template, sheet_path_template, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, project_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, file_format, schema_path_template, sheet_path_template, environment_mapping, require_signed_commit, trusted_signing_keys, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
 		`, len(args)),
 		args...,
 	).Scan(
@@ -589,8 +630,12 @@ func patchRepositoryImpl(ctx context.Context, tx *sql.Tx, patch *api.RepositoryP
 		&repository.BranchFilter,
 		&repository.BaseDirectory,
 		&repository.FilePathTemplate,
+		&repository.FileFormat,
 		&repository.SchemaPathTemplate,
 		&repository.SheetPathTemplate,
+		&repository.EnvironmentMapping,
+		&repository.RequireSignedCommit,
+		&repository.TrustedSigningKeys,
 		&repository.ExternalID,
 		&repository.ExternalWebhookID,
 		&repository.WebhookURLHost,
@@ -0,0 +1,300 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+// repositoryPushEventRaw is the store model for a RepositoryPushEvent.
+// Fields have exactly the same meanings as RepositoryPushEvent.
+type repositoryPushEventRaw struct {
+	ID int
+
+	// Standard fields
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	File        string
+	PushEvent   vcs.PushEvent
+	Status      api.RepositoryPushEventStatus
+	Message     string
+	ReplayCount int
+}
+
+// toRepositoryPushEvent creates an instance of RepositoryPushEvent based on the repositoryPushEventRaw.
+// This is intended to be called when we need to compose a RepositoryPushEvent relationship.
+func (raw *repositoryPushEventRaw) toRepositoryPushEvent() *api.RepositoryPushEvent {
+	return &api.RepositoryPushEvent{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID: raw.ProjectID,
+
+		// Domain specific fields
+		File:        raw.File,
+		PushEvent:   raw.PushEvent,
+		Status:      raw.Status,
+		Message:     raw.Message,
+		ReplayCount: raw.ReplayCount,
+	}
+}
+
+// CreateRepositoryPushEvent creates an instance of RepositoryPushEvent.
+func (s *Store) CreateRepositoryPushEvent(ctx context.Context, create *api.RepositoryPushEventCreate) (*api.RepositoryPushEvent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createRepositoryPushEventImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RepositoryPushEvent with RepositoryPushEventCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeRepositoryPushEvent(ctx, raw)
+}
+
+// FindRepositoryPushEvent finds a list of RepositoryPushEvent instances.
+func (s *Store) FindRepositoryPushEvent(ctx context.Context, find *api.RepositoryPushEventFind) ([]*api.RepositoryPushEvent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findRepositoryPushEventImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find RepositoryPushEvent list with RepositoryPushEventFind[%+v], error: %w", find, err)
+	}
+	var eventList []*api.RepositoryPushEvent
+	for _, raw := range rawList {
+		event, err := s.composeRepositoryPushEvent(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		eventList = append(eventList, event)
+	}
+	return eventList, nil
+}
+
+// GetRepositoryPushEventByID gets an instance of RepositoryPushEvent by ID.
+func (s *Store) GetRepositoryPushEventByID(ctx context.Context, id int) (*api.RepositoryPushEvent, error) {
+	eventList, err := s.FindRepositoryPushEvent(ctx, &api.RepositoryPushEventFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(eventList) == 0 {
+		return nil, nil
+	}
+	return eventList[0], nil
+}
+
+// PatchRepositoryPushEvent patches the result of replaying an instance of RepositoryPushEvent.
+func (s *Store) PatchRepositoryPushEvent(ctx context.Context, patch *api.RepositoryPushEventPatch) (*api.RepositoryPushEvent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchRepositoryPushEventImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch RepositoryPushEvent with RepositoryPushEventPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeRepositoryPushEvent(ctx, raw)
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeRepositoryPushEvent(ctx context.Context, raw *repositoryPushEventRaw) (*api.RepositoryPushEvent, error) {
+	event := raw.toRepositoryPushEvent()
+
+	updater, err := s.GetPrincipalByID(ctx, event.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	event.Updater = updater
+
+	return event, nil
+}
+
+func createRepositoryPushEventImpl(ctx context.Context, tx *sql.Tx, create *api.RepositoryPushEventCreate) (*repositoryPushEventRaw, error) {
+	pushEvent, err := json.Marshal(create.PushEvent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal push event, error: %w", err)
+	}
+	query := `
+		INSERT INTO repository_push_event (
+			updater_id,
+			project_id,
+			file,
+			push_event,
+			status,
+			message
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_ts, updater_id, updated_ts, project_id, file, push_event, status, message, replay_count
+	`
+	raw, err := scanRepositoryPushEvent(tx.QueryRowContext(ctx, query,
+		api.SystemBotID,
+		create.ProjectID,
+		create.File,
+		string(pushEvent),
+		create.Status,
+		create.Message,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return raw, nil
+}
+
+func findRepositoryPushEventImpl(ctx context.Context, tx *sql.Tx, find *api.RepositoryPushEventFind) ([]*repositoryPushEventRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			file,
+			push_event,
+			status,
+			message,
+			replay_count
+		FROM repository_push_event
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*repositoryPushEventRaw
+	for rows.Next() {
+		raw, err := scanRepositoryPushEvent(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+// patchRepositoryPushEventImpl updates the result of replaying a repository push event by ID.
+// The event which has already created the issue is DONE for good, patching it returns the Conflict error.
+// Returns the new state of the repository push event after update.
+func patchRepositoryPushEventImpl(ctx context.Context, tx *sql.Tx, patch *api.RepositoryPushEventPatch) (*repositoryPushEventRaw, error) {
+	var status api.RepositoryPushEventStatus
+	if err := tx.QueryRowContext(ctx, `
+		SELECT status FROM repository_push_event WHERE id = $1 FOR UPDATE
+	`, patch.ID).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("repository push event ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	if status == api.RepositoryPushEventDone {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("repository push event %d is already %s", patch.ID, status)}
+	}
+
+	query := `
+		UPDATE repository_push_event
+		SET updater_id = $1, status = $2, message = $3, replay_count = replay_count + 1
+		WHERE id = $4
+		RETURNING id, created_ts, updater_id, updated_ts, project_id, file, push_event, status, message, replay_count
+	`
+	raw, err := scanRepositoryPushEvent(tx.QueryRowContext(ctx, query,
+		patch.UpdaterID,
+		patch.Status,
+		patch.Message,
+		patch.ID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return raw, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRepositoryPushEvent scans a repository push event from the row selected in the column order of the table.
+func scanRepositoryPushEvent(row rowScanner) (*repositoryPushEventRaw, error) {
+	var raw repositoryPushEventRaw
+	var pushEvent string
+	if err := row.Scan(
+		&raw.ID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.File,
+		&pushEvent,
+		&raw.Status,
+		&raw.Message,
+		&raw.ReplayCount,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(pushEvent), &raw.PushEvent); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal push event of repository push event %d, error: %w", raw.ID, err)
+	}
+	return &raw, nil
+}
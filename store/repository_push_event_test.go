@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestPatchRepositoryPushEvent(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	event, err := s.CreateRepositoryPushEvent(ctx, &api.RepositoryPushEventCreate{
+		ProjectID: 3003,
+		File:      "bytebase/prod/blog__202206010000__migrate__add_post.sql",
+		PushEvent: vcs.PushEvent{VCSType: vcs.GitLabSelfHost, BaseDirectory: "bytebase"},
+		Status:    api.RepositoryPushEventFailed,
+		Message:   "database not found",
+	})
+	require.NoError(t, err)
+	require.Equal(t, api.RepositoryPushEventFailed, event.Status)
+	require.Equal(t, 0, event.ReplayCount)
+	require.Equal(t, vcs.GitLabSelfHost, event.PushEvent.VCSType)
+
+	// The failed event can be replayed until it creates the issue.
+	patched, err := s.PatchRepositoryPushEvent(ctx, &api.RepositoryPushEventPatch{
+		ID:        event.ID,
+		UpdaterID: api.SystemBotID,
+		Status:    api.RepositoryPushEventFailed,
+		Message:   "database is archived",
+	})
+	require.NoError(t, err)
+	require.Equal(t, api.RepositoryPushEventFailed, patched.Status)
+	require.Equal(t, "database is archived", patched.Message)
+	require.Equal(t, 1, patched.ReplayCount)
+
+	patched, err = s.PatchRepositoryPushEvent(ctx, &api.RepositoryPushEventPatch{
+		ID:        event.ID,
+		UpdaterID: api.SystemBotID,
+		Status:    api.RepositoryPushEventDone,
+		Message:   "created issue 101",
+	})
+	require.NoError(t, err)
+	require.Equal(t, api.RepositoryPushEventDone, patched.Status)
+	require.Equal(t, 2, patched.ReplayCount)
+
+	// The event which has created the issue is never processed again.
+	_, err = s.PatchRepositoryPushEvent(ctx, &api.RepositoryPushEventPatch{
+		ID:        event.ID,
+		UpdaterID: api.SystemBotID,
+		Status:    api.RepositoryPushEventDone,
+		Message:   "created issue 102",
+	})
+	require.Equal(t, common.Conflict, common.ErrorCode(err))
+	found, err := s.GetRepositoryPushEventByID(ctx, event.ID)
+	require.NoError(t, err)
+	require.Equal(t, api.RepositoryPushEventDone, found.Status)
+	require.Equal(t, "created issue 101", found.Message)
+	require.Equal(t, 2, found.ReplayCount)
+
+	done := api.RepositoryPushEventDone
+	failed := api.RepositoryPushEventFailed
+	projectID := 3003
+	eventList, err := s.FindRepositoryPushEvent(ctx, &api.RepositoryPushEventFind{ProjectID: &projectID, Status: &done})
+	require.NoError(t, err)
+	require.Len(t, eventList, 1)
+	eventList, err = s.FindRepositoryPushEvent(ctx, &api.RepositoryPushEventFind{ProjectID: &projectID, Status: &failed})
+	require.NoError(t, err)
+	require.Empty(t, eventList)
+
+	_, err = s.PatchRepositoryPushEvent(ctx, &api.RepositoryPushEventPatch{
+		ID:        event.ID + 1,
+		UpdaterID: api.SystemBotID,
+		Status:    api.RepositoryPushEventFailed,
+	})
+	require.Equal(t, common.NotFound, common.ErrorCode(err))
+}
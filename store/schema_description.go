@@ -0,0 +1,240 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// schemaDescriptionRaw is the store model for a SchemaDescription.
+// Fields have exactly the same meanings as SchemaDescription.
+type schemaDescriptionRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	TableName   string
+	ColumnName  string
+	Description string
+}
+
+// toSchemaDescription creates an instance of SchemaDescription based on the schemaDescriptionRaw.
+// This is intended to be called when we need to compose a SchemaDescription relationship.
+func (raw *schemaDescriptionRaw) toSchemaDescription() *api.SchemaDescription {
+	return &api.SchemaDescription{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		TableName:   raw.TableName,
+		ColumnName:  raw.ColumnName,
+		Description: raw.Description,
+	}
+}
+
+// UpsertSchemaDescription upserts the description of a table or a column.
+func (s *Store) UpsertSchemaDescription(ctx context.Context, upsert *api.SchemaDescriptionUpsert) (*api.SchemaDescription, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := upsertSchemaDescriptionImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert schema description with SchemaDescriptionUpsert[%+v], error: %w", upsert, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeSchemaDescription(ctx, raw)
+}
+
+// FindSchemaDescription finds a list of SchemaDescription instances ordered by the table name and the column name.
+func (s *Store) FindSchemaDescription(ctx context.Context, find *api.SchemaDescriptionFind) ([]*api.SchemaDescription, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findSchemaDescriptionImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schema description list with SchemaDescriptionFind[%+v], error: %w", find, err)
+	}
+	var descriptionList []*api.SchemaDescription
+	for _, raw := range rawList {
+		description, err := s.composeSchemaDescription(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		descriptionList = append(descriptionList, description)
+	}
+	return descriptionList, nil
+}
+
+// DeleteSchemaDescription deletes the description of a table or a column.
+func (s *Store) DeleteSchemaDescription(ctx context.Context, delete *api.SchemaDescriptionDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `
+		DELETE FROM schema_description
+		WHERE database_id = $1 AND table_name = $2 AND column_name = $3
+	`, delete.DatabaseID, delete.TableName, delete.ColumnName); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeSchemaDescription(ctx context.Context, raw *schemaDescriptionRaw) (*api.SchemaDescription, error) {
+	description := raw.toSchemaDescription()
+
+	creator, err := s.GetPrincipalByID(ctx, description.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	description.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, description.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	description.Updater = updater
+
+	return description, nil
+}
+
+func upsertSchemaDescriptionImpl(ctx context.Context, tx *sql.Tx, upsert *api.SchemaDescriptionUpsert) (*schemaDescriptionRaw, error) {
+	query := `
+		INSERT INTO schema_description (
+			creator_id,
+			updater_id,
+			database_id,
+			table_name,
+			column_name,
+			description
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT(database_id, table_name, column_name) DO UPDATE SET
+				updater_id = EXCLUDED.updater_id,
+				description = EXCLUDED.description
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_name, column_name, description
+	`
+	var raw schemaDescriptionRaw
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.DatabaseID,
+		upsert.TableName,
+		upsert.ColumnName,
+		upsert.Description,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.DatabaseID,
+		&raw.TableName,
+		&raw.ColumnName,
+		&raw.Description,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findSchemaDescriptionImpl(ctx context.Context, tx *sql.Tx, find *api.SchemaDescriptionFind) ([]*schemaDescriptionRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TableName; v != nil {
+		where, args = append(where, fmt.Sprintf("table_name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			table_name,
+			column_name,
+			description
+		FROM schema_description
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id, table_name, column_name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*schemaDescriptionRaw
+	for rows.Next() {
+		var raw schemaDescriptionRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.DatabaseID,
+			&raw.TableName,
+			&raw.ColumnName,
+			&raw.Description,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
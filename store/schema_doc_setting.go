@@ -0,0 +1,296 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// schemaDocSettingRaw is the store model for a SchemaDocSetting.
+// Fields have exactly the same meanings as SchemaDocSetting.
+type schemaDocSettingRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	Title           string
+	LogoURL         string
+	Footer          string
+	ThemeColor      string
+	PublishSchedule api.SchemaDocPublishSchedule
+	PublishFormat   api.SchemaDocFormat
+	PublishURL      string
+	LastPublishedTs int64
+}
+
+// toSchemaDocSetting creates an instance of SchemaDocSetting based on the schemaDocSettingRaw.
+// This is intended to be called when we need to compose a SchemaDocSetting relationship.
+func (raw *schemaDocSettingRaw) toSchemaDocSetting() *api.SchemaDocSetting {
+	return &api.SchemaDocSetting{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID: raw.ProjectID,
+
+		// Domain specific fields
+		Title:           raw.Title,
+		LogoURL:         raw.LogoURL,
+		Footer:          raw.Footer,
+		ThemeColor:      raw.ThemeColor,
+		PublishSchedule: raw.PublishSchedule,
+		PublishFormat:   raw.PublishFormat,
+		PublishURL:      raw.PublishURL,
+		LastPublishedTs: raw.LastPublishedTs,
+	}
+}
+
+// UpsertSchemaDocSetting upserts the schema documentation setting of a project.
+func (s *Store) UpsertSchemaDocSetting(ctx context.Context, upsert *api.SchemaDocSettingUpsert) (*api.SchemaDocSetting, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := upsertSchemaDocSettingImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert schema doc setting with SchemaDocSettingUpsert[%+v], error: %w", upsert, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeSchemaDocSetting(ctx, raw)
+}
+
+// GetSchemaDocSettingByProjectID gets the schema documentation setting of a project.
+// Returns nil if the project has no setting.
+func (s *Store) GetSchemaDocSettingByProjectID(ctx context.Context, projectID int) (*api.SchemaDocSetting, error) {
+	list, err := s.FindSchemaDocSetting(ctx, &api.SchemaDocSettingFind{ProjectID: &projectID})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d schema doc settings with project ID %d, expect 1", len(list), projectID)}
+	}
+	return list[0], nil
+}
+
+// FindSchemaDocSetting finds a list of SchemaDocSetting instances.
+func (s *Store) FindSchemaDocSetting(ctx context.Context, find *api.SchemaDocSettingFind) ([]*api.SchemaDocSetting, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findSchemaDocSettingImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schema doc setting list with SchemaDocSettingFind[%+v], error: %w", find, err)
+	}
+	var settingList []*api.SchemaDocSetting
+	for _, raw := range rawList {
+		setting, err := s.composeSchemaDocSetting(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		settingList = append(settingList, setting)
+	}
+	return settingList, nil
+}
+
+// PublishSchemaDocSetting records the last publication time of the schema documentation.
+func (s *Store) PublishSchemaDocSetting(ctx context.Context, publish *api.SchemaDocSettingPublish) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	// We don't touch the updater_id and updated_ts since the publication isn't a user change.
+	if _, err := tx.PTx.ExecContext(ctx, `
+		UPDATE schema_doc_setting
+		SET last_published_ts = $1
+		WHERE id = $2
+	`, publish.LastPublishedTs, publish.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeSchemaDocSetting(ctx context.Context, raw *schemaDocSettingRaw) (*api.SchemaDocSetting, error) {
+	setting := raw.toSchemaDocSetting()
+
+	creator, err := s.GetPrincipalByID(ctx, setting.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	setting.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, setting.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	setting.Updater = updater
+
+	return setting, nil
+}
+
+func upsertSchemaDocSettingImpl(ctx context.Context, tx *sql.Tx, upsert *api.SchemaDocSettingUpsert) (*schemaDocSettingRaw, error) {
+	query := `
+		INSERT INTO schema_doc_setting (
+			creator_id,
+			updater_id,
+			project_id,
+			title,
+			logo_url,
+			footer,
+			theme_color,
+			publish_schedule,
+			publish_format,
+			publish_url
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT(project_id) DO UPDATE SET
+				updater_id = EXCLUDED.updater_id,
+				title = EXCLUDED.title,
+				logo_url = EXCLUDED.logo_url,
+				footer = EXCLUDED.footer,
+				theme_color = EXCLUDED.theme_color,
+				publish_schedule = EXCLUDED.publish_schedule,
+				publish_format = EXCLUDED.publish_format,
+				publish_url = EXCLUDED.publish_url
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, title, logo_url, footer, theme_color, publish_schedule, publish_format, publish_url, last_published_ts
+	`
+	var raw schemaDocSettingRaw
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.ProjectID,
+		upsert.Title,
+		upsert.LogoURL,
+		upsert.Footer,
+		upsert.ThemeColor,
+		upsert.PublishSchedule,
+		upsert.PublishFormat,
+		upsert.PublishURL,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.ProjectID,
+		&raw.Title,
+		&raw.LogoURL,
+		&raw.Footer,
+		&raw.ThemeColor,
+		&raw.PublishSchedule,
+		&raw.PublishFormat,
+		&raw.PublishURL,
+		&raw.LastPublishedTs,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findSchemaDocSettingImpl(ctx context.Context, tx *sql.Tx, find *api.SchemaDocSettingFind) ([]*schemaDocSettingRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PublishSchedule; v != nil {
+		where, args = append(where, fmt.Sprintf("publish_schedule = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			title,
+			logo_url,
+			footer,
+			theme_color,
+			publish_schedule,
+			publish_format,
+			publish_url,
+			last_published_ts
+		FROM schema_doc_setting
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*schemaDocSettingRaw
+	for rows.Next() {
+		var raw schemaDocSettingRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.ProjectID,
+			&raw.Title,
+			&raw.LogoURL,
+			&raw.Footer,
+			&raw.ThemeColor,
+			&raw.PublishSchedule,
+			&raw.PublishFormat,
+			&raw.PublishURL,
+			&raw.LastPublishedTs,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// schemaSnapshotRaw is the store model for a SchemaSnapshot.
+// Fields have exactly the same meanings as SchemaSnapshot.
+type schemaSnapshotRaw struct {
+	ID int
+
+	// Standard fields
+	CreatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Source  api.SchemaSnapshotSource
+	Version string
+	Schema  string
+}
+
+// toSchemaSnapshot creates an instance of SchemaSnapshot based on the schemaSnapshotRaw.
+// This is intended to be called when we need to compose a SchemaSnapshot relationship.
+func (raw *schemaSnapshotRaw) toSchemaSnapshot() *api.SchemaSnapshot {
+	return &api.SchemaSnapshot{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		Source:  raw.Source,
+		Version: raw.Version,
+		Schema:  raw.Schema,
+	}
+}
+
+// CreateSchemaSnapshot creates an instance of SchemaSnapshot.
+func (s *Store) CreateSchemaSnapshot(ctx context.Context, create *api.SchemaSnapshotCreate) (*api.SchemaSnapshot, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createSchemaSnapshotImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema snapshot with SchemaSnapshotCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return raw.toSchemaSnapshot(), nil
+}
+
+// FindSchemaSnapshot finds a list of SchemaSnapshot instances, the latest snapshot comes first.
+func (s *Store) FindSchemaSnapshot(ctx context.Context, find *api.SchemaSnapshotFind) ([]*api.SchemaSnapshot, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findSchemaSnapshotImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schema snapshot list with SchemaSnapshotFind[%+v], error: %w", find, err)
+	}
+	var snapshotList []*api.SchemaSnapshot
+	for _, raw := range rawList {
+		snapshotList = append(snapshotList, raw.toSchemaSnapshot())
+	}
+	return snapshotList, nil
+}
+
+// GetSchemaSnapshotAsOf gets the schema snapshot of a database in effect at the time, i.e. the latest one created at or before the time.
+// Returns nil if there is no snapshot before the time.
+func (s *Store) GetSchemaSnapshotAsOf(ctx context.Context, databaseID int, ts int64) (*api.SchemaSnapshot, error) {
+	limit := 1
+	snapshotList, err := s.FindSchemaSnapshot(ctx, &api.SchemaSnapshotFind{
+		DatabaseID:      &databaseID,
+		CreatedTsBefore: &ts,
+		Limit:           &limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshotList) == 0 {
+		return nil, nil
+	}
+	return snapshotList[0], nil
+}
+
+//
+// private functions
+//
+
+func createSchemaSnapshotImpl(ctx context.Context, tx *sql.Tx, create *api.SchemaSnapshotCreate) (*schemaSnapshotRaw, error) {
+	query := `
+		INSERT INTO schema_snapshot (
+			database_id,
+			source,
+			version,
+			schema
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_ts, database_id, source, version, schema
+	`
+	var raw schemaSnapshotRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.DatabaseID,
+		create.Source,
+		create.Version,
+		create.Schema,
+	).Scan(
+		&raw.ID,
+		&raw.CreatedTs,
+		&raw.DatabaseID,
+		&raw.Source,
+		&raw.Version,
+		&raw.Schema,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findSchemaSnapshotImpl(ctx context.Context, tx *sql.Tx, find *api.SchemaSnapshotFind) ([]*schemaSnapshotRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+
+	query := `
+		SELECT
+			id,
+			created_ts,
+			database_id,
+			source,
+			version,
+			schema
+		FROM schema_snapshot
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY created_ts DESC, id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*schemaSnapshotRaw
+	for rows.Next() {
+		var raw schemaSnapshotRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatedTs,
+			&raw.DatabaseID,
+			&raw.Source,
+			&raw.Version,
+			&raw.Schema,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetSchemaSnapshotAsOf(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	// The demo databases 7004 and 7008 are the blog databases on the MySQL instances.
+	databaseID, otherDatabaseID := 7004, 7008
+	first := createTestSchemaSnapshot(ctx, t, s, databaseID, "first", 1000)
+	second := createTestSchemaSnapshot(ctx, t, s, databaseID, "second", 2000)
+	// The later snapshot wins if both are captured in the same second.
+	third := createTestSchemaSnapshot(ctx, t, s, databaseID, "third", 2000)
+	createTestSchemaSnapshot(ctx, t, s, otherDatabaseID, "other", 500)
+
+	tests := []struct {
+		ts   int64
+		want *api.SchemaSnapshot
+	}{
+		// Before the first snapshot.
+		{ts: 0, want: nil},
+		{ts: 999, want: nil},
+		// Exactly at the first snapshot.
+		{ts: 1000, want: first},
+		// Between the two snapshots.
+		{ts: 1001, want: first},
+		{ts: 1999, want: first},
+		// Exactly at the snapshots captured in the same second.
+		{ts: 2000, want: third},
+		{ts: 3000, want: third},
+	}
+	for _, test := range tests {
+		snapshot, err := s.GetSchemaSnapshotAsOf(ctx, databaseID, test.ts)
+		require.NoError(t, err)
+		require.Equal(t, test.want, snapshot, test.ts)
+	}
+
+	snapshotList, err := s.FindSchemaSnapshot(ctx, &api.SchemaSnapshotFind{DatabaseID: &databaseID})
+	require.NoError(t, err)
+	require.Equal(t, []*api.SchemaSnapshot{third, second, first}, snapshotList)
+
+	snapshot, err := s.GetSchemaSnapshotAsOf(ctx, otherDatabaseID, 999)
+	require.NoError(t, err)
+	require.Equal(t, "other", snapshot.Schema)
+}
+
+// createTestSchemaSnapshot creates the schema snapshot captured at the time.
+func createTestSchemaSnapshot(ctx context.Context, t *testing.T, s *Store, databaseID int, schema string, ts int64) *api.SchemaSnapshot {
+	snapshot, err := s.CreateSchemaSnapshot(ctx, &api.SchemaSnapshotCreate{
+		DatabaseID: databaseID,
+		Source:     api.SchemaSnapshotSync,
+		Schema:     schema,
+	})
+	require.NoError(t, err)
+	_, err = s.db.db.ExecContext(ctx, "UPDATE schema_snapshot SET created_ts = $1 WHERE id = $2", ts, snapshot.ID)
+	require.NoError(t, err)
+	snapshot.CreatedTs = ts
+	return snapshot
+}
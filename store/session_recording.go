@@ -0,0 +1,290 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// sessionRecordingRaw is the store model for a SessionRecording.
+// Fields have exactly the same meanings as SessionRecording.
+type sessionRecordingRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	InstanceID   int
+	InstanceName string
+	DatabaseID   int
+	DatabaseName string
+
+	// Domain specific fields
+	Source      api.SessionSource
+	SessionID   string
+	Statement   string
+	ColumnNames string
+	RowCount    int
+	DurationNs  int64
+	Error       string
+	Hash        string
+}
+
+// toSessionRecording creates an instance of SessionRecording based on the sessionRecordingRaw.
+// This is intended to be called when we need to compose a SessionRecording relationship.
+func (raw *sessionRecordingRaw) toSessionRecording() (*api.SessionRecording, error) {
+	var columnNameList []string
+	if err := json.Unmarshal([]byte(raw.ColumnNames), &columnNameList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal column names %q of session recording %d, error: %w", raw.ColumnNames, raw.ID, err)
+	}
+	return &api.SessionRecording{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		InstanceID:   raw.InstanceID,
+		InstanceName: raw.InstanceName,
+		DatabaseID:   raw.DatabaseID,
+		DatabaseName: raw.DatabaseName,
+
+		// Domain specific fields
+		Source:         raw.Source,
+		SessionID:      raw.SessionID,
+		Statement:      raw.Statement,
+		ColumnNameList: columnNameList,
+		RowCount:       raw.RowCount,
+		DurationNs:     raw.DurationNs,
+		Error:          raw.Error,
+		Hash:           raw.Hash,
+	}, nil
+}
+
+// CreateSessionRecording creates an instance of SessionRecording chained to the latest recording by the HMAC keyed by hashKey.
+// The recordings are created one at a time, so that the chain follows the ID order.
+func (s *Store) CreateSessionRecording(ctx context.Context, create *api.SessionRecordingCreate, hashKey []byte) (*api.SessionRecording, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createSessionRecordingImpl(ctx, tx.PTx, create, hashKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session recording of session %q, error: %w", create.SessionID, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeSessionRecording(ctx, raw)
+}
+
+// FindSessionRecording finds a list of SessionRecording instances in the ascending ID order, i.e. the order of the statements.
+func (s *Store) FindSessionRecording(ctx context.Context, find *api.SessionRecordingFind) ([]*api.SessionRecording, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findSessionRecordingImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session recording list with SessionRecordingFind[%+v], error: %w", find, err)
+	}
+	var recordingList []*api.SessionRecording
+	for _, raw := range rawList {
+		recording, err := s.composeSessionRecording(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		recordingList = append(recordingList, recording)
+	}
+	return recordingList, nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeSessionRecording(ctx context.Context, raw *sessionRecordingRaw) (*api.SessionRecording, error) {
+	recording, err := raw.toSessionRecording()
+	if err != nil {
+		return nil, err
+	}
+
+	creator, err := s.GetPrincipalByID(ctx, recording.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	recording.Creator = creator
+
+	return recording, nil
+}
+
+func createSessionRecordingImpl(ctx context.Context, tx *sql.Tx, create *api.SessionRecordingCreate, hashKey []byte) (*sessionRecordingRaw, error) {
+	// Block the concurrent recordings until the commit, while the reviews can still read the table.
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE session_recording IN EXCLUSIVE MODE`); err != nil {
+		return nil, FormatError(err)
+	}
+	prevHash := ""
+	if err := tx.QueryRowContext(ctx, `SELECT hash FROM session_recording ORDER BY id DESC LIMIT 1`).Scan(&prevHash); err != nil && err != sql.ErrNoRows {
+		return nil, FormatError(err)
+	}
+
+	columnNameList := create.ColumnNameList
+	if columnNameList == nil {
+		columnNameList = []string{}
+	}
+	columnNames, err := json.Marshal(columnNameList)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		INSERT INTO session_recording (
+			creator_id,
+			created_ts,
+			instance_id,
+			instance_name,
+			database_id,
+			database_name,
+			source,
+			session_id,
+			statement,
+			column_names,
+			row_count,
+			duration_ns,
+			error,
+			hash
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, creator_id, created_ts, instance_id, instance_name, database_id, database_name, source, session_id, statement, column_names, row_count, duration_ns, error, hash
+	`
+	var raw sessionRecordingRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatedTs,
+		create.InstanceID,
+		create.InstanceName,
+		create.DatabaseID,
+		create.DatabaseName,
+		create.Source,
+		create.SessionID,
+		create.Statement,
+		string(columnNames),
+		create.RowCount,
+		create.DurationNs,
+		create.Error,
+		api.GetSessionRecordingHash(hashKey, prevHash, create),
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.InstanceID,
+		&raw.InstanceName,
+		&raw.DatabaseID,
+		&raw.DatabaseName,
+		&raw.Source,
+		&raw.SessionID,
+		&raw.Statement,
+		&raw.ColumnNames,
+		&raw.RowCount,
+		&raw.DurationNs,
+		&raw.Error,
+		&raw.Hash,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findSessionRecordingImpl(ctx context.Context, tx *sql.Tx, find *api.SessionRecordingFind) ([]*sessionRecordingRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.SessionID; v != nil {
+		where, args = append(where, fmt.Sprintf("session_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			instance_id,
+			instance_name,
+			database_id,
+			database_name,
+			source,
+			session_id,
+			statement,
+			column_names,
+			row_count,
+			duration_ns,
+			error,
+			hash
+		FROM session_recording
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*sessionRecordingRaw
+	for rows.Next() {
+		var raw sessionRecordingRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.InstanceID,
+			&raw.InstanceName,
+			&raw.DatabaseID,
+			&raw.DatabaseName,
+			&raw.Source,
+			&raw.SessionID,
+			&raw.Statement,
+			&raw.ColumnNames,
+			&raw.RowCount,
+			&raw.DurationNs,
+			&raw.Error,
+			&raw.Hash,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
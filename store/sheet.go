@@ -235,7 +235,7 @@ func (s *Store) composeSheet(ctx context.Context, raw *sheetRaw, currentPrincipa
 	return sheet, nil
 }
 
-// createSheetRaw creates a new sheet.
+// createSheetRaw creates a new sheet with its statement as the first version.
 func (s *Store) createSheetRaw(ctx context.Context, create *api.SheetCreate) (*sheetRaw, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -247,6 +247,13 @@ func (s *Store) createSheetRaw(ctx context.Context, create *api.SheetCreate) (*s
 	if err != nil {
 		return nil, err
 	}
+	if err := createSheetVersionImpl(ctx, tx.PTx, &api.SheetVersionCreate{
+		CreatorID: create.CreatorID,
+		SheetID:   sheet.ID,
+		Statement: sheet.Statement,
+	}); err != nil {
+		return nil, err
+	}
 
 	if err := tx.PTx.Commit(); err != nil {
 		return nil, FormatError(err)
@@ -255,7 +262,7 @@ func (s *Store) createSheetRaw(ctx context.Context, create *api.SheetCreate) (*s
 	return sheet, nil
 }
 
-// patchSheetRaw updates an existing sheet by ID.
+// patchSheetRaw updates an existing sheet by ID, and records a new version if the statement is changed.
 func (s *Store) patchSheetRaw(ctx context.Context, patch *api.SheetPatch) (*sheetRaw, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -267,6 +274,15 @@ func (s *Store) patchSheetRaw(ctx context.Context, patch *api.SheetPatch) (*shee
 	if err != nil {
 		return nil, err
 	}
+	if patch.Statement != nil {
+		if err := createSheetVersionImpl(ctx, tx.PTx, &api.SheetVersionCreate{
+			CreatorID: patch.UpdaterID,
+			SheetID:   sheet.ID,
+			Statement: sheet.Statement,
+		}); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := tx.PTx.Commit(); err != nil {
 		return nil, FormatError(err)
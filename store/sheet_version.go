@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// sheetVersionRaw is the store model for a SheetVersion.
+// Fields have exactly the same meanings as SheetVersion.
+type sheetVersionRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	SheetID int
+
+	// Domain specific fields
+	Version   int
+	Statement string
+}
+
+// toSheetVersion creates an instance of SheetVersion based on the sheetVersionRaw.
+// This is intended to be called when we need to compose a SheetVersion relationship.
+func (raw *sheetVersionRaw) toSheetVersion() *api.SheetVersion {
+	return &api.SheetVersion{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		SheetID: raw.SheetID,
+
+		// Domain specific fields
+		Version:   raw.Version,
+		Statement: raw.Statement,
+	}
+}
+
+// FindSheetVersion finds a list of SheetVersion instances, the latest version comes first.
+func (s *Store) FindSheetVersion(ctx context.Context, find *api.SheetVersionFind) ([]*api.SheetVersion, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findSheetVersionImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find SheetVersion list with SheetVersionFind[%+v], error: %w", find, err)
+	}
+	var sheetVersionList []*api.SheetVersion
+	for _, raw := range rawList {
+		sheetVersion, err := s.composeSheetVersion(ctx, raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compose SheetVersion with sheetVersionRaw[%+v], error: %w", raw, err)
+		}
+		sheetVersionList = append(sheetVersionList, sheetVersion)
+	}
+	return sheetVersionList, nil
+}
+
+// GetSheetVersion gets a version of a sheet.
+func (s *Store) GetSheetVersion(ctx context.Context, sheetID int, version int) (*api.SheetVersion, error) {
+	sheetVersionList, err := s.FindSheetVersion(ctx, &api.SheetVersionFind{SheetID: &sheetID, Version: &version})
+	if err != nil {
+		return nil, err
+	}
+	if len(sheetVersionList) == 0 {
+		return nil, nil
+	}
+	return sheetVersionList[0], nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeSheetVersion(ctx context.Context, raw *sheetVersionRaw) (*api.SheetVersion, error) {
+	sheetVersion := raw.toSheetVersion()
+
+	creator, err := s.GetPrincipalByID(ctx, sheetVersion.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	sheetVersion.Creator = creator
+
+	return sheetVersion, nil
+}
+
+// createSheetVersionImpl records the statement as the next version of the sheet.
+// No version is created if the statement is the same as the latest version.
+func createSheetVersionImpl(ctx context.Context, tx *sql.Tx, create *api.SheetVersionCreate) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO sheet_version (
+			creator_id,
+			sheet_id,
+			version,
+			statement
+		)
+		SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3
+		FROM sheet_version
+		WHERE sheet_id = $2
+		HAVING COALESCE((ARRAY_AGG(statement ORDER BY version DESC))[1] <> $3, TRUE)
+	`,
+		create.CreatorID,
+		create.SheetID,
+		create.Statement,
+	); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+func findSheetVersionImpl(ctx context.Context, tx *sql.Tx, find *api.SheetVersionFind) ([]*sheetVersionRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.SheetID; v != nil {
+		where, args = append(where, fmt.Sprintf("sheet_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Version; v != nil {
+		where, args = append(where, fmt.Sprintf("version = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			sheet_id,
+			version,
+			statement
+		FROM sheet_version
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY sheet_id, version DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*sheetVersionRaw
+	for rows.Next() {
+		var raw sheetVersionRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.SheetID,
+			&raw.Version,
+			&raw.Statement,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	dbdriver "github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/resources/postgres"
+)
+
+// testCacheService never hits, so the tests always read through the database.
+type testCacheService struct{}
+
+func (*testCacheService) FindCache(_ api.CacheNamespace, _ int, _ interface{}) (bool, error) {
+	return false, nil
+}
+
+func (*testCacheService) UpsertCache(_ api.CacheNamespace, _ int, _ interface{}) error {
+	return nil
+}
+
+func (*testCacheService) DeleteCache(_ api.CacheNamespace, _ int) {}
+
+// newTestStore returns the store on a new Postgres instance migrated to the dev schema with the dev demo data.
+// The instance is stopped when the test finishes.
+func newTestStore(t *testing.T) *Store {
+	pgInstance, stopInstance := postgres.SetupTestInstance(t, pgPort)
+	t.Cleanup(stopInstance)
+
+	connCfg := dbdriver.ConnectionConfig{
+		Username: "root",
+		Password: "",
+		Host:     common.GetPostgresSocketDir(),
+		Port:     fmt.Sprintf("%d", pgPort),
+	}
+	db := NewDB(connCfg, pgInstance.BaseDir, "demo/dev", false /* readonly */, serverVersion, common.ReleaseModeDev)
+	require.NoError(t, db.Open(context.Background()))
+	s := New(db, &testCacheService{})
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+	return s
+}
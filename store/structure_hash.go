@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// The structure hash identifies a table or a database by its structure regardless of its name,
+// so that a renamed object can be linked to its prior identity instead of appearing as a drop + create.
+// An empty structure hash means the structure is too trivial to identify the object.
+
+// GetSchemaStructureHash returns the structure hash of the synced database schema.
+func GetSchemaStructureHash(schema *db.Schema) string {
+	tableMap := make(map[string]string)
+	for _, table := range schema.TableList {
+		tableMap[table.Name] = getTableStructureHash(getSyncedColumnEntryList(table.ColumnList))
+	}
+	return getDatabaseStructureHash(tableMap)
+}
+
+// GetDatabaseStructureHash returns the structure hash of the database schema stored in Bytebase.
+func (s *Store) GetDatabaseStructureHash(ctx context.Context, databaseID int) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	tableRawList, err := s.findTableImpl(ctx, tx.PTx, &api.TableFind{DatabaseID: &databaseID})
+	if err != nil {
+		return "", err
+	}
+	tableHashMap, err := s.getStoredTableStructureHashMap(ctx, tx.PTx, databaseID)
+	if err != nil {
+		return "", err
+	}
+	tableMap := make(map[string]string)
+	for _, table := range tableRawList {
+		tableMap[table.Name] = tableHashMap[table.ID]
+	}
+	return getDatabaseStructureHash(tableMap), nil
+}
+
+// getStoredTableStructureHashMap returns the mapping from the table ID to the structure hash of the tables stored in Bytebase.
+func (s *Store) getStoredTableStructureHashMap(ctx context.Context, tx *sql.Tx, databaseID int) (map[int]string, error) {
+	columnList, err := s.findColumnImpl(ctx, tx, &api.ColumnFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, err
+	}
+	entryListMap := make(map[int][]string)
+	for _, column := range columnList {
+		entryListMap[column.TableID] = append(entryListMap[column.TableID], getColumnEntry(column.Name, column.Type, column.Nullable))
+	}
+	hashMap := make(map[int]string)
+	for tableID, entryList := range entryListMap {
+		hashMap[tableID] = getTableStructureHash(entryList)
+	}
+	return hashMap, nil
+}
+
+// detectTableRename returns the mapping from the ID of the renamed table to its new name.
+// A table is considered renamed if it disappears in the synced schema while a new table shows up with the same
+// structure, and the structure is unique among both the disappeared and the new tables.
+func detectTableRename(oldTableRawList []*tableRaw, oldHashMap map[int]string, tableList []db.Table) map[int]string {
+	newTableMap := make(map[string]bool)
+	for _, table := range tableList {
+		newTableMap[table.Name] = true
+	}
+	oldTableMap := make(map[string]bool)
+	for _, table := range oldTableRawList {
+		oldTableMap[table.Name] = true
+	}
+
+	deletedMap := make(map[string][]int)
+	for _, table := range oldTableRawList {
+		if newTableMap[table.Name] {
+			continue
+		}
+		if hash := oldHashMap[table.ID]; hash != "" {
+			deletedMap[hash] = append(deletedMap[hash], table.ID)
+		}
+	}
+	createdMap := make(map[string][]string)
+	for _, table := range tableList {
+		if oldTableMap[table.Name] {
+			continue
+		}
+		if hash := getTableStructureHash(getSyncedColumnEntryList(table.ColumnList)); hash != "" {
+			createdMap[hash] = append(createdMap[hash], table.Name)
+		}
+	}
+
+	renameMap := make(map[int]string)
+	for hash, idList := range deletedMap {
+		nameList := createdMap[hash]
+		if len(idList) == 1 && len(nameList) == 1 {
+			renameMap[idList[0]] = nameList[0]
+		}
+	}
+	return renameMap
+}
+
+func getSyncedColumnEntryList(columnList []db.Column) []string {
+	var entryList []string
+	for _, column := range columnList {
+		entryList = append(entryList, getColumnEntry(column.Name, column.Type, column.Nullable))
+	}
+	return entryList
+}
+
+func getColumnEntry(name, columnType string, nullable bool) string {
+	return fmt.Sprintf("%s %s %t", name, strings.ToLower(columnType), nullable)
+}
+
+func getTableStructureHash(columnEntryList []string) string {
+	if len(columnEntryList) == 0 {
+		return ""
+	}
+	entryList := append([]string{}, columnEntryList...)
+	sort.Strings(entryList)
+	return hashEntryList(entryList)
+}
+
+// getDatabaseStructureHash returns the hash of the table names and their structure hashes.
+func getDatabaseStructureHash(tableMap map[string]string) string {
+	if len(tableMap) == 0 {
+		return ""
+	}
+	var entryList []string
+	for name, hash := range tableMap {
+		entryList = append(entryList, fmt.Sprintf("%s %s", name, hash))
+	}
+	sort.Strings(entryList)
+	return hashEntryList(entryList)
+}
+
+func hashEntryList(entryList []string) string {
+	h := sha256.Sum256([]byte(strings.Join(entryList, "\n")))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTableRename(t *testing.T) {
+	userColumnList := []db.Column{
+		{Name: "id", Type: "int", Nullable: false},
+		{Name: "name", Type: "varchar(64)", Nullable: true},
+	}
+	orderColumnList := []db.Column{
+		{Name: "id", Type: "int", Nullable: false},
+		{Name: "amount", Type: "decimal", Nullable: false},
+	}
+	oldTableRawList := []*tableRaw{
+		{ID: 1, Name: "user"},
+		{ID: 2, Name: "order"},
+	}
+	oldHashMap := map[int]string{
+		1: getTableStructureHash(getSyncedColumnEntryList(userColumnList)),
+		2: getTableStructureHash(getSyncedColumnEntryList(orderColumnList)),
+	}
+
+	tests := []struct {
+		tableList []db.Table
+		want      map[int]string
+	}{
+		// Unchanged.
+		{
+			tableList: []db.Table{
+				{Name: "user", ColumnList: userColumnList},
+				{Name: "order", ColumnList: orderColumnList},
+			},
+			want: map[int]string{},
+		},
+		// Renamed.
+		{
+			tableList: []db.Table{
+				{Name: "member", ColumnList: userColumnList},
+				{Name: "order", ColumnList: orderColumnList},
+			},
+			want: map[int]string{1: "member"},
+		},
+		// Renamed and the structure changed.
+		{
+			tableList: []db.Table{
+				{Name: "member", ColumnList: append([]db.Column{{Name: "email", Type: "text"}}, userColumnList...)},
+				{Name: "order", ColumnList: orderColumnList},
+			},
+			want: map[int]string{},
+		},
+		// Ambiguous.
+		{
+			tableList: []db.Table{
+				{Name: "member", ColumnList: userColumnList},
+				{Name: "member_copy", ColumnList: userColumnList},
+				{Name: "order", ColumnList: orderColumnList},
+			},
+			want: map[int]string{},
+		},
+		// Both renamed.
+		{
+			tableList: []db.Table{
+				{Name: "member", ColumnList: userColumnList},
+				{Name: "purchase", ColumnList: orderColumnList},
+			},
+			want: map[int]string{1: "member", 2: "purchase"},
+		},
+	}
+
+	for _, test := range tests {
+		renameMap := detectTableRename(oldTableRawList, oldHashMap, test.tableList)
+		require.Equal(t, test.want, renameMap)
+	}
+}
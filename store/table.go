@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 
+	"go.uber.org/zap"
+
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
@@ -27,16 +29,20 @@ type tableRaw struct {
 	DatabaseID int
 
 	// Domain specific fields
-	Name          string
-	Type          string
-	Engine        string
-	Collation     string
-	RowCount      int64
-	DataSize      int64
-	IndexSize     int64
-	DataFree      int64
-	CreateOptions string
-	Comment       string
+	Name           string
+	Type           string
+	Engine         string
+	Collation      string
+	RowCount       int64
+	DataSize       int64
+	IndexSize      int64
+	DataFree       int64
+	CreateOptions  string
+	Comment        string
+	Owner          string
+	PartitionKey   string
+	ParentTable    string
+	PartitionBound string
 }
 
 // toTable creates an instance of Table based on the tableRaw.
@@ -55,16 +61,20 @@ func (raw *tableRaw) toTable() *api.Table {
 		DatabaseID: raw.DatabaseID,
 
 		// Domain specific fields
-		Name:          raw.Name,
-		Type:          raw.Type,
-		Engine:        raw.Engine,
-		Collation:     raw.Collation,
-		RowCount:      raw.RowCount,
-		DataSize:      raw.DataSize,
-		IndexSize:     raw.IndexSize,
-		DataFree:      raw.DataFree,
-		CreateOptions: raw.CreateOptions,
-		Comment:       raw.Comment,
+		Name:           raw.Name,
+		Type:           raw.Type,
+		Engine:         raw.Engine,
+		Collation:      raw.Collation,
+		RowCount:       raw.RowCount,
+		DataSize:       raw.DataSize,
+		IndexSize:      raw.IndexSize,
+		DataFree:       raw.DataFree,
+		CreateOptions:  raw.CreateOptions,
+		Comment:        raw.Comment,
+		Owner:          raw.Owner,
+		PartitionKey:   raw.PartitionKey,
+		ParentTable:    raw.ParentTable,
+		PartitionBound: raw.PartitionBound,
 	}
 }
 
@@ -115,6 +125,26 @@ func (s *Store) SetTableList(ctx context.Context, schema *db.Schema, databaseID
 	if err != nil {
 		return FormatError(err)
 	}
+	// Rename the tables in place to keep their identity, the columns and indexes are then synced by the table ID.
+	oldHashMap, err := s.getStoredTableStructureHashMap(ctx, tx.PTx, databaseID)
+	if err != nil {
+		return err
+	}
+	renameMap := detectTableRename(oldTableRawList, oldHashMap, schema.TableList)
+	for _, table := range oldTableRawList {
+		newName, ok := renameMap[table.ID]
+		if !ok {
+			continue
+		}
+		if err := s.renameTableImpl(ctx, tx.PTx, table.ID, newName); err != nil {
+			return err
+		}
+		log.Info("Detected renamed table",
+			zap.Int("databaseID", databaseID),
+			zap.String("oldName", table.Name),
+			zap.String("newName", newName))
+		table.Name = newName
+	}
 	creates, patches, deletes := generateTableActions(oldTableRawList, schema.TableList, databaseID)
 	for _, d := range deletes {
 		if err := s.deleteTableImpl(ctx, tx.PTx, d); err != nil {
@@ -184,6 +214,24 @@ func (s *Store) SetTableList(ctx context.Context, schema *db.Schema, databaseID
 				return err
 			}
 		}
+
+		foreignKeyList, err := s.findForeignKeyImpl(ctx, tx.PTx, &api.ForeignKeyFind{
+			TableID: &tableID,
+		})
+		if err != nil {
+			return err
+		}
+		fkDeletes, fkCreates := generateForeignKeyActions(foreignKeyList, table.ForeignKeyList, databaseID, tableID)
+		for _, d := range fkDeletes {
+			if err := s.deleteForeignKeyImpl(ctx, tx.PTx, d); err != nil {
+				return err
+			}
+		}
+		for _, c := range fkCreates {
+			if _, err := s.createForeignKeyImpl(ctx, tx.PTx, c); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := tx.PTx.Commit(); err != nil {
@@ -220,20 +268,28 @@ func generateTableActions(oldTableRawList []*tableRaw, tableList []db.Table, dat
 				oldValue.IndexSize != newValue.IndexSize ||
 				oldValue.DataFree != newValue.DataFree ||
 				oldValue.CreateOptions != newValue.CreateOptions ||
-				oldValue.Comment != newValue.Comment) {
+				oldValue.Comment != newValue.Comment ||
+				oldValue.Owner != newValue.Owner ||
+				oldValue.PartitionKey != newValue.PartitionKey ||
+				oldValue.ParentTable != newValue.ParentTable ||
+				oldValue.PartitionBound != newValue.PartitionBound) {
 			patches = append(patches,
 				&api.TablePatch{
-					ID:            oldValue.ID,
-					UpdaterID:     api.SystemBotID,
-					Type:          newValue.Type,
-					Engine:        newValue.Engine,
-					Collation:     newValue.Collation,
-					RowCount:      newValue.RowCount,
-					DataSize:      newValue.DataSize,
-					IndexSize:     newValue.IndexSize,
-					DataFree:      newValue.DataFree,
-					CreateOptions: newValue.CreateOptions,
-					Comment:       newValue.Comment,
+					ID:             oldValue.ID,
+					UpdaterID:      api.SystemBotID,
+					Type:           newValue.Type,
+					Engine:         newValue.Engine,
+					Collation:      newValue.Collation,
+					RowCount:       newValue.RowCount,
+					DataSize:       newValue.DataSize,
+					IndexSize:      newValue.IndexSize,
+					DataFree:       newValue.DataFree,
+					CreateOptions:  newValue.CreateOptions,
+					Comment:        newValue.Comment,
+					Owner:          newValue.Owner,
+					PartitionKey:   newValue.PartitionKey,
+					ParentTable:    newValue.ParentTable,
+					PartitionBound: newValue.PartitionBound,
 				},
 			)
 		}
@@ -242,20 +298,24 @@ func generateTableActions(oldTableRawList []*tableRaw, tableList []db.Table, dat
 		k := newValue.Name
 		if _, ok := oldTableMap[k]; !ok {
 			creates = append(creates, &api.TableCreate{
-				CreatorID:     api.SystemBotID,
-				CreatedTs:     newValue.CreatedTs,
-				UpdatedTs:     newValue.UpdatedTs,
-				DatabaseID:    databaseID,
-				Name:          newValue.Name,
-				Type:          newValue.Type,
-				Engine:        newValue.Engine,
-				Collation:     newValue.Collation,
-				RowCount:      newValue.RowCount,
-				DataSize:      newValue.DataSize,
-				IndexSize:     newValue.IndexSize,
-				DataFree:      newValue.DataFree,
-				CreateOptions: newValue.CreateOptions,
-				Comment:       newValue.Comment,
+				CreatorID:      api.SystemBotID,
+				CreatedTs:      newValue.CreatedTs,
+				UpdatedTs:      newValue.UpdatedTs,
+				DatabaseID:     databaseID,
+				Name:           newValue.Name,
+				Type:           newValue.Type,
+				Engine:         newValue.Engine,
+				Collation:      newValue.Collation,
+				RowCount:       newValue.RowCount,
+				DataSize:       newValue.DataSize,
+				IndexSize:      newValue.IndexSize,
+				DataFree:       newValue.DataFree,
+				CreateOptions:  newValue.CreateOptions,
+				Comment:        newValue.Comment,
+				Owner:          newValue.Owner,
+				PartitionKey:   newValue.PartitionKey,
+				ParentTable:    newValue.ParentTable,
+				PartitionBound: newValue.PartitionBound,
 			})
 		}
 	}
@@ -347,10 +407,14 @@ func (*Store) createTableImpl(ctx context.Context, tx *sql.Tx, create *api.Table
 			index_size,
 			data_free,
 			create_options,
-			comment
+			comment,
+			owner,
+			partition_key,
+			parent_table,
+			partition_bound
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, type, engine, "collation", row_count, data_size, index_size, data_free, create_options, comment
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, type, engine, "collation", row_count, data_size, index_size, data_free, create_options, comment, owner, partition_key, parent_table, partition_bound
 	`
 	var tableRaw tableRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -369,6 +433,10 @@ func (*Store) createTableImpl(ctx context.Context, tx *sql.Tx, create *api.Table
 		create.DataFree,
 		create.CreateOptions,
 		create.Comment,
+		create.Owner,
+		create.PartitionKey,
+		create.ParentTable,
+		create.PartitionBound,
 	).Scan(
 		&tableRaw.ID,
 		&tableRaw.CreatorID,
@@ -386,6 +454,10 @@ func (*Store) createTableImpl(ctx context.Context, tx *sql.Tx, create *api.Table
 		&tableRaw.DataFree,
 		&tableRaw.CreateOptions,
 		&tableRaw.Comment,
+		&tableRaw.Owner,
+		&tableRaw.PartitionKey,
+		&tableRaw.ParentTable,
+		&tableRaw.PartitionBound,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -401,9 +473,9 @@ func (*Store) patchTableImpl(ctx context.Context, tx *sql.Tx, patch *api.TablePa
 	// Execute update query with RETURNING.
 	if err := tx.QueryRowContext(ctx, `
 		UPDATE tbl
-		SET	type=$1, engine=$2, "collation"=$3, row_count=$4, data_size=$5, index_size=$6, data_free=$7, create_options=$8, comment=$9
-		WHERE id = $10
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, type, engine, "collation", row_count, data_size, index_size, data_free, create_options, comment`,
+		SET	type=$1, engine=$2, "collation"=$3, row_count=$4, data_size=$5, index_size=$6, data_free=$7, create_options=$8, comment=$9, owner=$10, partition_key=$11, parent_table=$12, partition_bound=$13
+		WHERE id = $14
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, type, engine, "collation", row_count, data_size, index_size, data_free, create_options, comment, owner, partition_key, parent_table, partition_bound`,
 		patch.Type,
 		patch.Engine,
 		patch.Collation,
@@ -413,6 +485,10 @@ func (*Store) patchTableImpl(ctx context.Context, tx *sql.Tx, patch *api.TablePa
 		patch.DataFree,
 		patch.CreateOptions,
 		patch.Comment,
+		patch.Owner,
+		patch.PartitionKey,
+		patch.ParentTable,
+		patch.PartitionBound,
 		patch.ID,
 	).Scan(
 		&tableRaw.ID,
@@ -431,6 +507,10 @@ func (*Store) patchTableImpl(ctx context.Context, tx *sql.Tx, patch *api.TablePa
 		&tableRaw.DataFree,
 		&tableRaw.CreateOptions,
 		&tableRaw.Comment,
+		&tableRaw.Owner,
+		&tableRaw.PartitionKey,
+		&tableRaw.ParentTable,
+		&tableRaw.PartitionBound,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("table ID not found: %d", patch.ID)}
@@ -449,9 +529,15 @@ func (*Store) findTableImpl(ctx context.Context, tx *sql.Tx, find *api.TableFind
 	if v := find.DatabaseID; v != nil {
 		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id IN (SELECT id FROM db WHERE instance_id = $%d)", len(args)+1)), append(args, *v)
+	}
 	if v := find.Name; v != nil {
 		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Owner; v != nil {
+		where, args = append(where, fmt.Sprintf("owner = $%d", len(args)+1)), append(args, *v)
+	}
 
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
@@ -470,7 +556,11 @@ func (*Store) findTableImpl(ctx context.Context, tx *sql.Tx, find *api.TableFind
 			index_size,
 			data_free,
 			create_options,
-			comment
+			comment,
+			owner,
+			partition_key,
+			parent_table,
+			partition_bound
 		FROM tbl
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -501,6 +591,10 @@ func (*Store) findTableImpl(ctx context.Context, tx *sql.Tx, find *api.TableFind
 			&tableRaw.DataFree,
 			&tableRaw.CreateOptions,
 			&tableRaw.Comment,
+			&tableRaw.Owner,
+			&tableRaw.PartitionKey,
+			&tableRaw.ParentTable,
+			&tableRaw.PartitionBound,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -514,6 +608,23 @@ func (*Store) findTableImpl(ctx context.Context, tx *sql.Tx, find *api.TableFind
 	return tableRawList, nil
 }
 
+// renameTableImpl renames a table by ID.
+func (*Store) renameTableImpl(ctx context.Context, tx *sql.Tx, id int, name string) error {
+	// Carry over the schema descriptions keyed by the old table name.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE schema_description
+		SET table_name = $1
+		FROM tbl
+		WHERE tbl.id = $2 AND schema_description.database_id = tbl.database_id AND schema_description.table_name = tbl.name
+	`, name, id); err != nil {
+		return FormatError(err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE tbl SET updater_id = $1, name = $2 WHERE id = $3`, api.SystemBotID, name, id); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
 // deleteTableImpl permanently deletes tables from a database.
 func (*Store) deleteTableImpl(ctx context.Context, tx *sql.Tx, delete *api.TableDelete) error {
 	// Remove row from database.
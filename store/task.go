@@ -488,6 +488,9 @@ func (s *Store) findTaskImpl(ctx context.Context, tx *sql.Tx, find *api.TaskFind
 	if v := find.StageID; v != nil {
 		where, args = append(where, fmt.Sprintf("stage_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.StatusList; v != nil {
 		list := []string{}
 		for _, status := range *v {
@@ -496,6 +499,12 @@ func (s *Store) findTaskImpl(ctx context.Context, tx *sql.Tx, find *api.TaskFind
 		}
 		where = append(where, fmt.Sprintf("status in (%s)", strings.Join(list, ",")))
 	}
+	if v := find.CommitID; v != nil {
+		where, args = append(where, fmt.Sprintf("payload->'pushEvent'->'fileCommit'->>'id' = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.FilePath; v != nil {
+		where, args = append(where, fmt.Sprintf("payload->'pushEvent'->'fileCommit'->>'added' = $%d", len(args)+1)), append(args, *v)
+	}
 
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
@@ -658,6 +667,7 @@ func (s *Store) patchTaskStatusImpl(ctx context.Context, tx *sql.Tx, patch *api.
 				Name:      fmt.Sprintf("%s %d", taskRawObj.Name, time.Now().Unix()),
 				Type:      taskRawObj.Type,
 				Payload:   taskRawObj.Payload,
+				RequestID: patch.RequestID,
 			}
 			// insert a running taskRun
 			if _, err := s.createTaskRunImpl(ctx, tx, taskRunCreate); err != nil {
@@ -666,7 +676,7 @@ func (s *Store) patchTaskStatusImpl(ctx context.Context, tx *sql.Tx, patch *api.
 		}
 	} else {
 		if patch.Status == api.TaskRunning {
-			return nil, fmt.Errorf("task is already running: %v", taskRawObj.Name)
+			return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("task is already running: %v", taskRawObj.Name)}
 		}
 		taskRunStatusPatch := &api.TaskRunStatusPatch{
 			ID:        &taskRunRaw.ID,
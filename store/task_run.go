@@ -25,13 +25,14 @@ type taskRunRaw struct {
 	TaskID int
 
 	// Domain specific fields
-	Name    string
-	Status  api.TaskRunStatus
-	Type    api.TaskType
-	Code    common.Code
-	Comment string
-	Result  string
-	Payload string
+	Name      string
+	Status    api.TaskRunStatus
+	Type      api.TaskType
+	Code      common.Code
+	Comment   string
+	Result    string
+	Payload   string
+	RequestID string
 }
 
 // toTaskRun creates an instance of TaskRun based on the taskRunRaw.
@@ -50,16 +51,50 @@ func (raw *taskRunRaw) toTaskRun() *api.TaskRun {
 		TaskID: raw.TaskID,
 
 		// Domain specific fields
-		Name:    raw.Name,
-		Status:  raw.Status,
-		Type:    raw.Type,
-		Code:    raw.Code,
-		Comment: raw.Comment,
-		Result:  raw.Result,
-		Payload: raw.Payload,
+		Name:      raw.Name,
+		Status:    raw.Status,
+		Type:      raw.Type,
+		Code:      raw.Code,
+		Comment:   raw.Comment,
+		Result:    raw.Result,
+		Payload:   raw.Payload,
+		RequestID: raw.RequestID,
 	}
 }
 
+// FindTaskRun finds a list of TaskRun instances.
+func (s *Store) FindTaskRun(ctx context.Context, find *api.TaskRunFind) ([]*api.TaskRun, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	taskRunRawList, err := s.findTaskRunImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task run list with TaskRunFind[%+v], error: %w", find, err)
+	}
+	var taskRunList []*api.TaskRun
+	for _, raw := range taskRunRawList {
+		taskRun := raw.toTaskRun()
+
+		creator, err := s.GetPrincipalByID(ctx, taskRun.CreatorID)
+		if err != nil {
+			return nil, err
+		}
+		taskRun.Creator = creator
+
+		updater, err := s.GetPrincipalByID(ctx, taskRun.UpdaterID)
+		if err != nil {
+			return nil, err
+		}
+		taskRun.Updater = updater
+
+		taskRunList = append(taskRunList, taskRun)
+	}
+	return taskRunList, nil
+}
+
 // createTaskRunImpl creates a new taskRun.
 func (*Store) createTaskRunImpl(ctx context.Context, tx *sql.Tx, create *api.TaskRunCreate) (*taskRunRaw, error) {
 	if create.Payload == "" {
@@ -73,10 +108,11 @@ func (*Store) createTaskRunImpl(ctx context.Context, tx *sql.Tx, create *api.Tas
 			name,
 			status,
 			type,
-			payload
+			payload,
+			request_id
 		)
-		VALUES ($1, $2, $3, $4, 'RUNNING', $5, $6)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, name, status, type, code, comment, result, payload
+		VALUES ($1, $2, $3, $4, 'RUNNING', $5, $6, $7)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, name, status, type, code, comment, result, payload, request_id
 	`
 	var taskRunRaw taskRunRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -86,6 +122,7 @@ func (*Store) createTaskRunImpl(ctx context.Context, tx *sql.Tx, create *api.Tas
 		create.Name,
 		create.Type,
 		create.Payload,
+		create.RequestID,
 	).Scan(
 		&taskRunRaw.ID,
 		&taskRunRaw.CreatorID,
@@ -100,6 +137,7 @@ func (*Store) createTaskRunImpl(ctx context.Context, tx *sql.Tx, create *api.Tas
 		&taskRunRaw.Comment,
 		&taskRunRaw.Result,
 		&taskRunRaw.Payload,
+		&taskRunRaw.RequestID,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -157,7 +195,7 @@ func (*Store) patchTaskRunStatusImpl(ctx context.Context, tx *sql.Tx, patch *api
 		UPDATE task_run
 		SET `+strings.Join(set, ", ")+`
 		WHERE `+strings.Join(where, " AND ")+`
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, name, status, type, code, comment, result, payload
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, task_id, name, status, type, code, comment, result, payload, request_id
 	`,
 		args...,
 	).Scan(
@@ -174,6 +212,7 @@ func (*Store) patchTaskRunStatusImpl(ctx context.Context, tx *sql.Tx, patch *api
 		&taskRunRaw.Comment,
 		&taskRunRaw.Result,
 		&taskRunRaw.Payload,
+		&taskRunRaw.RequestID,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("project ID not found: %d", patch.ID)}
@@ -201,6 +240,12 @@ func (*Store) findTaskRunImpl(ctx context.Context, tx *sql.Tx, find *api.TaskRun
 		}
 		where = append(where, fmt.Sprintf("status in (%s)", strings.Join(list, ",")))
 	}
+	if v := find.CreatedAfterTs; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedBeforeTs; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts <= $%d", len(args)+1)), append(args, *v)
+	}
 
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
@@ -216,7 +261,8 @@ func (*Store) findTaskRunImpl(ctx context.Context, tx *sql.Tx, find *api.TaskRun
 			code,
 			comment,
 			result,
-			payload
+			payload,
+			request_id
 		FROM task_run
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -244,6 +290,7 @@ func (*Store) findTaskRunImpl(ctx context.Context, tx *sql.Tx, find *api.TaskRun
 			&taskRunRaw.Comment,
 			&taskRunRaw.Result,
 			&taskRunRaw.Payload,
+			&taskRunRaw.RequestID,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -0,0 +1,349 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// trashRaw is the store model for a Trash.
+// Fields have exactly the same meanings as Trash.
+type trashRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Domain specific fields
+	ResourceType api.TrashResourceType
+	ResourceID   int
+	Name         string
+}
+
+// toTrash creates an instance of Trash based on the trashRaw.
+// This is intended to be called when we need to compose a Trash relationship.
+func (raw *trashRaw) toTrash() *api.Trash {
+	return &api.Trash{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Domain specific fields
+		ResourceType: raw.ResourceType,
+		ResourceID:   raw.ResourceID,
+		Name:         raw.Name,
+	}
+}
+
+var (
+	// trashConfigDeleteQueryList is the list of the queries deleting the configuration owned by the resource when it's purged.
+	// The queries take the resource ID as the only argument, and are executed in order.
+	trashConfigDeleteQueryList = map[api.TrashResourceType][]string{
+		api.TrashProject: {
+			`DELETE FROM project_member WHERE project_id = $1`,
+			`DELETE FROM project_webhook WHERE project_id = $1`,
+			`DELETE FROM db_assignment_rule WHERE project_id = $1`,
+			`DELETE FROM project_variable WHERE project_id = $1`,
+			`DELETE FROM deployment_config WHERE project_id = $1`,
+			`DELETE FROM schema_doc_setting WHERE project_id = $1`,
+			`DELETE FROM issue_field WHERE project_id = $1`,
+			`DELETE FROM issue_sla_setting WHERE project_id = $1`,
+			`DELETE FROM recurring_issue WHERE project_id = $1`,
+			`DELETE FROM dashboard_tile WHERE project_id = $1`,
+			`DELETE FROM repository_push_event WHERE project_id = $1`,
+			`DELETE FROM repository WHERE project_id = $1`,
+		},
+		api.TrashInstance: {
+			`DELETE FROM data_source_rotation WHERE data_source_id IN (SELECT id FROM data_source WHERE instance_id = $1)`,
+			`DELETE FROM data_source WHERE instance_id = $1`,
+			`DELETE FROM instance_user WHERE instance_id = $1`,
+			`DELETE FROM db_role_mapping WHERE instance_id = $1`,
+			`DELETE FROM instance_parameter WHERE instance_id = $1`,
+			`DELETE FROM instance_sync_setting WHERE instance_id = $1`,
+			`DELETE FROM db_assignment_rule WHERE instance_id = $1`,
+			`DELETE FROM anomaly WHERE instance_id = $1`,
+			`DELETE FROM col WHERE database_id IN (SELECT id FROM db WHERE instance_id = $1)`,
+			`DELETE FROM idx WHERE database_id IN (SELECT id FROM db WHERE instance_id = $1)`,
+			`DELETE FROM fk WHERE database_id IN (SELECT id FROM db WHERE instance_id = $1)`,
+			`DELETE FROM backup_setting WHERE database_id IN (SELECT id FROM db WHERE instance_id = $1)`,
+			`DELETE FROM db_label WHERE database_id IN (SELECT id FROM db WHERE instance_id = $1)`,
+			`DELETE FROM partition_policy WHERE database_id IN (SELECT id FROM db WHERE instance_id = $1)`,
+			`DELETE FROM db WHERE instance_id = $1`,
+		},
+		api.TrashDatabase: {
+			`DELETE FROM data_source_rotation WHERE data_source_id IN (SELECT id FROM data_source WHERE database_id = $1)`,
+			`DELETE FROM data_source WHERE database_id = $1`,
+			`DELETE FROM anomaly WHERE database_id = $1`,
+			`DELETE FROM col WHERE database_id = $1`,
+			`DELETE FROM idx WHERE database_id = $1`,
+			`DELETE FROM fk WHERE database_id = $1`,
+			`DELETE FROM backup_setting WHERE database_id = $1`,
+			`DELETE FROM db_label WHERE database_id = $1`,
+			`DELETE FROM partition_policy WHERE database_id = $1`,
+		},
+	}
+	// trashResourceTableMap is the table of each trash resource type.
+	trashResourceTableMap = map[api.TrashResourceType]string{
+		api.TrashProject:  "project",
+		api.TrashInstance: "instance",
+		api.TrashDatabase: "db",
+	}
+	// trashResourceCacheMap is the cache namespace of each trash resource type.
+	trashResourceCacheMap = map[api.TrashResourceType]api.CacheNamespace{
+		api.TrashProject:  api.ProjectCache,
+		api.TrashInstance: api.InstanceCache,
+		api.TrashDatabase: api.DatabaseCache,
+	}
+)
+
+// CreateTrash moves a resource to the trash.
+// The caller should archive the resource first.
+func (s *Store) CreateTrash(ctx context.Context, create *api.TrashCreate) (*api.Trash, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	query := `
+		INSERT INTO trash (
+			creator_id,
+			resource_type,
+			resource_id,
+			name
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, creator_id, created_ts, resource_type, resource_id, name
+	`
+	var raw trashRaw
+	if err := tx.PTx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.ResourceType,
+		create.ResourceID,
+		create.Name,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.ResourceType,
+		&raw.ResourceID,
+		&raw.Name,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeTrash(ctx, &raw)
+}
+
+// GetTrash gets a resource in the trash.
+func (s *Store) GetTrash(ctx context.Context, find *api.TrashFind) (*api.Trash, error) {
+	list, err := s.FindTrash(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d trash with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// FindTrash finds the resources in the trash in the descending deleted time order.
+func (s *Store) FindTrash(ctx context.Context, find *api.TrashFind) ([]*api.Trash, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ResourceType; v != nil {
+		where, args = append(where, fmt.Sprintf("resource_type = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ResourceID; v != nil {
+		where, args = append(where, fmt.Sprintf("resource_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedBeforeTs; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts < $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.PTx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			resource_type,
+			resource_id,
+			name
+		FROM trash
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY created_ts DESC, id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var rawList []*trashRaw
+	for rows.Next() {
+		var raw trashRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.ResourceType,
+			&raw.ResourceID,
+			&raw.Name,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	var trashList []*api.Trash
+	for _, raw := range rawList {
+		trash, err := s.composeTrash(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		trashList = append(trashList, trash)
+	}
+	return trashList, nil
+}
+
+// DeleteTrash removes a resource from the trash, e.g. after it's restored.
+func (s *Store) DeleteTrash(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM trash WHERE id = $1`, id); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+// PurgeTrash removes a resource from the trash for good, it returns true if the resource row is deleted.
+// The configuration owned by the resource and the resource row are deleted together, unless anything else
+// such as the issues, the tasks and the backups still references the resource. In that case, the deletion
+// is rolled back and the resource stays archived for the history.
+func (s *Store) PurgeTrash(ctx context.Context, trash *api.Trash) (bool, error) {
+	table, ok := trashResourceTableMap[trash.ResourceType]
+	if !ok {
+		return false, fmt.Errorf("unsupported trash resource type %q", trash.ResourceType)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM trash WHERE id = $1`, trash.ID); err != nil {
+		return false, FormatError(err)
+	}
+
+	// Only purge the archived resource in case it's restored in other ways, e.g. the schema syncer finds the database again.
+	var rowStatus api.RowStatus
+	if err := tx.PTx.QueryRowContext(ctx, fmt.Sprintf(`SELECT row_status FROM %s WHERE id = $1`, table), trash.ResourceID).Scan(&rowStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return false, FormatError(tx.PTx.Commit())
+		}
+		return false, FormatError(err)
+	}
+	if rowStatus != api.Archived {
+		return false, FormatError(tx.PTx.Commit())
+	}
+
+	// The databases of the instance are deleted along with the instance, so their cache is evicted as well.
+	var databaseIDList []int
+	if trash.ResourceType == api.TrashInstance {
+		rows, err := tx.PTx.QueryContext(ctx, `SELECT id FROM db WHERE instance_id = $1`, trash.ResourceID)
+		if err != nil {
+			return false, FormatError(err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				return false, FormatError(err)
+			}
+			databaseIDList = append(databaseIDList, id)
+		}
+		if err := rows.Err(); err != nil {
+			return false, FormatError(err)
+		}
+	}
+
+	if _, err := tx.PTx.ExecContext(ctx, `SAVEPOINT purge_trash`); err != nil {
+		return false, FormatError(err)
+	}
+	deleted := true
+	var queryList []string
+	queryList = append(queryList, trashConfigDeleteQueryList[trash.ResourceType]...)
+	queryList = append(queryList, fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, table))
+	for _, query := range queryList {
+		if _, err := tx.PTx.ExecContext(ctx, query, trash.ResourceID); err != nil {
+			if !strings.Contains(err.Error(), "violates foreign key constraint") {
+				return false, FormatError(err)
+			}
+			if _, err := tx.PTx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT purge_trash`); err != nil {
+				return false, FormatError(err)
+			}
+			deleted = false
+			break
+		}
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return false, FormatError(err)
+	}
+
+	if deleted {
+		s.cache.DeleteCache(trashResourceCacheMap[trash.ResourceType], trash.ResourceID)
+		for _, id := range databaseIDList {
+			s.cache.DeleteCache(api.DatabaseCache, id)
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) composeTrash(ctx context.Context, raw *trashRaw) (*api.Trash, error) {
+	trash := raw.toTrash()
+
+	creator, err := s.GetPrincipalByID(ctx, trash.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	trash.Creator = creator
+
+	return trash, nil
+}
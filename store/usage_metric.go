@@ -0,0 +1,267 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// usageMetricRaw is the store model for an UsageMetric.
+// Fields have exactly the same meanings as UsageMetric.
+type usageMetricRaw struct {
+	ID int
+
+	// Standard fields
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Domain specific fields
+	Date     string
+	Name     string
+	Labels   string
+	Value    int64
+	Reported bool
+}
+
+// toUsageMetric creates an instance of UsageMetric based on the usageMetricRaw.
+// This is intended to be called when we need to compose an UsageMetric relationship.
+func (raw *usageMetricRaw) toUsageMetric() (*api.UsageMetric, error) {
+	labels := make(map[string]string)
+	if err := json.Unmarshal([]byte(raw.Labels), &labels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal labels %q of usage metric %d, error: %w", raw.Labels, raw.ID, err)
+	}
+	return &api.UsageMetric{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatedTs: raw.CreatedTs,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Domain specific fields
+		Date:     raw.Date,
+		Name:     raw.Name,
+		Labels:   labels,
+		Value:    raw.Value,
+		Reported: raw.Reported,
+	}, nil
+}
+
+// UpsertUsageMetric upserts the usage metric of a day.
+func (s *Store) UpsertUsageMetric(ctx context.Context, upsert *api.UsageMetricUpsert) (*api.UsageMetric, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := upsertUsageMetricImpl(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert usage metric with UsageMetricUpsert[%+v], error: %w", upsert, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return raw.toUsageMetric()
+}
+
+// FindUsageMetric finds a list of UsageMetric instances.
+func (s *Store) FindUsageMetric(ctx context.Context, find *api.UsageMetricFind) ([]*api.UsageMetric, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findUsageMetricImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find usage metric list with UsageMetricFind[%+v], error: %w", find, err)
+	}
+	var usageMetricList []*api.UsageMetric
+	for _, raw := range rawList {
+		usageMetric, err := raw.toUsageMetric()
+		if err != nil {
+			return nil, err
+		}
+		usageMetricList = append(usageMetricList, usageMetric)
+	}
+	return usageMetricList, nil
+}
+
+// PatchUsageMetric patches an instance of UsageMetric.
+func (s *Store) PatchUsageMetric(ctx context.Context, patch *api.UsageMetricPatch) (*api.UsageMetric, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchUsageMetricImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch usage metric with UsageMetricPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return raw.toUsageMetric()
+}
+
+//
+// private functions
+//
+
+func upsertUsageMetricImpl(ctx context.Context, tx *sql.Tx, upsert *api.UsageMetricUpsert) (*usageMetricRaw, error) {
+	if upsert.Labels == nil {
+		upsert.Labels = map[string]string{}
+	}
+	// The map keys are sorted by json.Marshal, so the same labels are marshaled to the same text.
+	labels, err := json.Marshal(upsert.Labels)
+	if err != nil {
+		return nil, err
+	}
+	set := "value = EXCLUDED.value"
+	if upsert.Increment {
+		set = "value = usage_metric.value + EXCLUDED.value"
+	}
+	query := `
+		INSERT INTO usage_metric (
+			date,
+			name,
+			labels,
+			value
+		)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(date, name, labels) DO UPDATE SET
+				` + set + `,
+				reported = FALSE
+		RETURNING id, created_ts, updated_ts, date, name, labels, value, reported
+	`
+	var raw usageMetricRaw
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.Date,
+		upsert.Name,
+		string(labels),
+		upsert.Value,
+	).Scan(
+		&raw.ID,
+		&raw.CreatedTs,
+		&raw.UpdatedTs,
+		&raw.Date,
+		&raw.Name,
+		&raw.Labels,
+		&raw.Value,
+		&raw.Reported,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findUsageMetricImpl(ctx context.Context, tx *sql.Tx, find *api.UsageMetricFind) ([]*usageMetricRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.FromDate; v != nil {
+		where, args = append(where, fmt.Sprintf("date >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ToDate; v != nil {
+		where, args = append(where, fmt.Sprintf("date <= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Reported; v != nil {
+		where, args = append(where, fmt.Sprintf("reported = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			updated_ts,
+			date,
+			name,
+			labels,
+			value,
+			reported
+		FROM usage_metric
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY date ASC, name ASC, id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*usageMetricRaw
+	for rows.Next() {
+		var raw usageMetricRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatedTs,
+			&raw.UpdatedTs,
+			&raw.Date,
+			&raw.Name,
+			&raw.Labels,
+			&raw.Value,
+			&raw.Reported,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchUsageMetricImpl(ctx context.Context, tx *sql.Tx, patch *api.UsageMetricPatch) (*usageMetricRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{}, []interface{}{}
+	if v := patch.Reported; v != nil {
+		set, args = append(set, fmt.Sprintf("reported = $%d", len(args)+1)), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("no field to patch for usage metric %d", patch.ID)}
+	}
+	args = append(args, patch.ID)
+
+	var raw usageMetricRaw
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE usage_metric
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, created_ts, updated_ts, date, name, labels, value, reported
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatedTs,
+		&raw.UpdatedTs,
+		&raw.Date,
+		&raw.Name,
+		&raw.Labels,
+		&raw.Value,
+		&raw.Reported,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("usage metric ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
@@ -28,7 +28,9 @@ type vcsRaw struct {
 	InstanceURL   string
 	APIURL        string
 	ApplicationID string
+	AuthMode      vcs.AuthMode
 	Secret        string
+	WebhookSecret string
 }
 
 // toVCS creates an instance of VCS based on the VCSRaw.
@@ -47,7 +49,9 @@ func (raw *vcsRaw) toVCS() *api.VCS {
 		InstanceURL:   raw.InstanceURL,
 		APIURL:        raw.APIURL,
 		ApplicationID: raw.ApplicationID,
+		AuthMode:      raw.AuthMode,
 		Secret:        raw.Secret,
+		WebhookSecret: raw.WebhookSecret,
 	}
 }
 
@@ -258,10 +262,12 @@ func createVCSImpl(ctx context.Context, tx *sql.Tx, create *api.VCSCreate) (*vcs
 			instance_url,
 			api_url,
 			application_id,
-			secret
+			auth_mode,
+			secret,
+			webhook_secret
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, type, instance_url, api_url, application_id, secret
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, type, instance_url, api_url, application_id, auth_mode, secret, webhook_secret
 	`
 	var vcs vcsRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -272,7 +278,9 @@ func createVCSImpl(ctx context.Context, tx *sql.Tx, create *api.VCSCreate) (*vcs
 		create.InstanceURL,
 		create.APIURL,
 		create.ApplicationID,
+		create.AuthMode,
 		create.Secret,
+		create.WebhookSecret,
 	).Scan(
 		&vcs.ID,
 		&vcs.CreatorID,
@@ -284,7 +292,9 @@ func createVCSImpl(ctx context.Context, tx *sql.Tx, create *api.VCSCreate) (*vcs
 		&vcs.InstanceURL,
 		&vcs.APIURL,
 		&vcs.ApplicationID,
+		&vcs.AuthMode,
 		&vcs.Secret,
+		&vcs.WebhookSecret,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -313,7 +323,9 @@ func findVCSImpl(ctx context.Context, tx *sql.Tx, find *api.VCSFind) ([]*vcsRaw,
 			instance_url,
 			api_url,
 			application_id,
-			secret
+			auth_mode,
+			secret,
+			webhook_secret
 		FROM vcs
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -338,7 +350,9 @@ func findVCSImpl(ctx context.Context, tx *sql.Tx, find *api.VCSFind) ([]*vcsRaw,
 			&vcs.InstanceURL,
 			&vcs.APIURL,
 			&vcs.ApplicationID,
+			&vcs.AuthMode,
 			&vcs.Secret,
+			&vcs.WebhookSecret,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -365,6 +379,9 @@ func patchVCSImpl(ctx context.Context, tx *sql.Tx, patch *api.VCSPatch) (*vcsRaw
 	if v := patch.Secret; v != nil {
 		set, args = append(set, fmt.Sprintf("secret = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.WebhookSecret; v != nil {
+		set, args = append(set, fmt.Sprintf("webhook_secret = $%d", len(args)+1)), append(args, *v)
+	}
 	args = append(args, patch.ID)
 
 	var vcs vcsRaw
@@ -373,7 +390,7 @@ func patchVCSImpl(ctx context.Context, tx *sql.Tx, patch *api.VCSPatch) (*vcsRaw
 		UPDATE vcs
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, type, instance_url, api_url, application_id, secret
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, type, instance_url, api_url, application_id, auth_mode, secret, webhook_secret
 	`, len(args)),
 		args...,
 	).Scan(
@@ -387,7 +404,9 @@ func patchVCSImpl(ctx context.Context, tx *sql.Tx, patch *api.VCSPatch) (*vcsRaw
 		&vcs.InstanceURL,
 		&vcs.APIURL,
 		&vcs.ApplicationID,
+		&vcs.AuthMode,
 		&vcs.Secret,
+		&vcs.WebhookSecret,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("vcs ID not found: %d", patch.ID)}
@@ -0,0 +1,362 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// vcsGroupWebhookRaw is the store model for a VCSGroupWebhook.
+// Fields have exactly the same meanings as VCSGroupWebhook.
+type vcsGroupWebhookRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	VCSID int
+
+	// Domain specific fields
+	ExternalGroupID    string
+	GroupFullPath      string
+	ExternalWebhookID  string
+	WebhookURLHost     string
+	WebhookEndpointID  string
+	WebhookSecretToken string
+	AccessToken        string
+	ExpiresTs          int64
+	RefreshToken       string
+}
+
+// toVCSGroupWebhook creates an instance of VCSGroupWebhook based on the vcsGroupWebhookRaw.
+// This is intended to be called when we need to compose a VCSGroupWebhook relationship.
+func (raw *vcsGroupWebhookRaw) toVCSGroupWebhook() *api.VCSGroupWebhook {
+	return &api.VCSGroupWebhook{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		VCSID: raw.VCSID,
+
+		// Domain specific fields
+		ExternalGroupID:    raw.ExternalGroupID,
+		GroupFullPath:      raw.GroupFullPath,
+		ExternalWebhookID:  raw.ExternalWebhookID,
+		WebhookURLHost:     raw.WebhookURLHost,
+		WebhookEndpointID:  raw.WebhookEndpointID,
+		WebhookSecretToken: raw.WebhookSecretToken,
+		AccessToken:        raw.AccessToken,
+		ExpiresTs:          raw.ExpiresTs,
+		RefreshToken:       raw.RefreshToken,
+	}
+}
+
+// CreateVCSGroupWebhook creates an instance of VCSGroupWebhook.
+func (s *Store) CreateVCSGroupWebhook(ctx context.Context, create *api.VCSGroupWebhookCreate) (*api.VCSGroupWebhook, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := createVCSGroupWebhookImpl(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create VCSGroupWebhook with VCSGroupWebhookCreate[%+v], error: %w", create, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeVCSGroupWebhook(ctx, raw)
+}
+
+// GetVCSGroupWebhook gets an instance of VCSGroupWebhook.
+func (s *Store) GetVCSGroupWebhook(ctx context.Context, find *api.VCSGroupWebhookFind) (*api.VCSGroupWebhook, error) {
+	webhookList, err := s.FindVCSGroupWebhook(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhookList) == 0 {
+		return nil, nil
+	} else if len(webhookList) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d VCS group webhooks with filter %+v, expect 1", len(webhookList), find)}
+	}
+	return webhookList[0], nil
+}
+
+// FindVCSGroupWebhook finds a list of VCSGroupWebhook instances.
+func (s *Store) FindVCSGroupWebhook(ctx context.Context, find *api.VCSGroupWebhookFind) ([]*api.VCSGroupWebhook, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	rawList, err := findVCSGroupWebhookImpl(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find VCSGroupWebhook list with VCSGroupWebhookFind[%+v], error: %w", find, err)
+	}
+	var webhookList []*api.VCSGroupWebhook
+	for _, raw := range rawList {
+		webhook, err := s.composeVCSGroupWebhook(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		webhookList = append(webhookList, webhook)
+	}
+	return webhookList, nil
+}
+
+// PatchVCSGroupWebhook patches an instance of VCSGroupWebhook.
+func (s *Store) PatchVCSGroupWebhook(ctx context.Context, patch *api.VCSGroupWebhookPatch) (*api.VCSGroupWebhook, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	raw, err := patchVCSGroupWebhookImpl(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch VCSGroupWebhook with VCSGroupWebhookPatch[%+v], error: %w", patch, err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return s.composeVCSGroupWebhook(ctx, raw)
+}
+
+// DeleteVCSGroupWebhook deletes an existing VCSGroupWebhook by ID.
+func (s *Store) DeleteVCSGroupWebhook(ctx context.Context, delete *api.VCSGroupWebhookDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `DELETE FROM vcs_group_webhook WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeVCSGroupWebhook(ctx context.Context, raw *vcsGroupWebhookRaw) (*api.VCSGroupWebhook, error) {
+	webhook := raw.toVCSGroupWebhook()
+
+	creator, err := s.GetPrincipalByID(ctx, webhook.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, webhook.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Updater = updater
+
+	return webhook, nil
+}
+
+func createVCSGroupWebhookImpl(ctx context.Context, tx *sql.Tx, create *api.VCSGroupWebhookCreate) (*vcsGroupWebhookRaw, error) {
+	query := `
+		INSERT INTO vcs_group_webhook (
+			creator_id,
+			updater_id,
+			vcs_id,
+			external_group_id,
+			group_full_path,
+			external_webhook_id,
+			webhook_url_host,
+			webhook_endpoint_id,
+			webhook_secret_token,
+			access_token,
+			expires_ts,
+			refresh_token
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, external_group_id, group_full_path, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
+	`
+	var raw vcsGroupWebhookRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.VCSID,
+		create.ExternalGroupID,
+		create.GroupFullPath,
+		create.ExternalWebhookID,
+		create.WebhookURLHost,
+		create.WebhookEndpointID,
+		create.WebhookSecretToken,
+		create.AccessToken,
+		create.ExpiresTs,
+		create.RefreshToken,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.VCSID,
+		&raw.ExternalGroupID,
+		&raw.GroupFullPath,
+		&raw.ExternalWebhookID,
+		&raw.WebhookURLHost,
+		&raw.WebhookEndpointID,
+		&raw.WebhookSecretToken,
+		&raw.AccessToken,
+		&raw.ExpiresTs,
+		&raw.RefreshToken,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findVCSGroupWebhookImpl(ctx context.Context, tx *sql.Tx, find *api.VCSGroupWebhookFind) ([]*vcsGroupWebhookRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.VCSID; v != nil {
+		where, args = append(where, fmt.Sprintf("vcs_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WebhookEndpointID; v != nil {
+		where, args = append(where, fmt.Sprintf("webhook_endpoint_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			vcs_id,
+			external_group_id,
+			group_full_path,
+			external_webhook_id,
+			webhook_url_host,
+			webhook_endpoint_id,
+			webhook_secret_token,
+			access_token,
+			expires_ts,
+			refresh_token
+		FROM vcs_group_webhook
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*vcsGroupWebhookRaw
+	for rows.Next() {
+		var raw vcsGroupWebhookRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.VCSID,
+			&raw.ExternalGroupID,
+			&raw.GroupFullPath,
+			&raw.ExternalWebhookID,
+			&raw.WebhookURLHost,
+			&raw.WebhookEndpointID,
+			&raw.WebhookSecretToken,
+			&raw.AccessToken,
+			&raw.ExpiresTs,
+			&raw.RefreshToken,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+func patchVCSGroupWebhookImpl(ctx context.Context, tx *sql.Tx, patch *api.VCSGroupWebhookPatch) (*vcsGroupWebhookRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.AccessToken; v != nil {
+		set, args = append(set, fmt.Sprintf("access_token = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ExpiresTs; v != nil {
+		set, args = append(set, fmt.Sprintf("expires_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.RefreshToken; v != nil {
+		set, args = append(set, fmt.Sprintf("refresh_token = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	var raw vcsGroupWebhookRaw
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE vcs_group_webhook
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, external_group_id, group_full_path, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
+	`, len(args)),
+		args...,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.VCSID,
+		&raw.ExternalGroupID,
+		&raw.GroupFullPath,
+		&raw.ExternalWebhookID,
+		&raw.WebhookURLHost,
+		&raw.WebhookEndpointID,
+		&raw.WebhookSecretToken,
+		&raw.AccessToken,
+		&raw.ExpiresTs,
+		&raw.RefreshToken,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("VCS group webhook ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
@@ -1476,6 +1476,10 @@ func setDefaultSQLReviewRulePayload(ruleTp advisor.SQLReviewRuleType) (string, e
 		payload, err = json.Marshal(advisor.NamingRulePayload{
 			Format: "^fk_{{referencing_table}}_{{referencing_column}}_{{referenced_table}}_{{referenced_column}}$",
 		})
+	case advisor.SchemaRuleTableFKNotValid:
+		payload, err = json.Marshal(advisor.TableFKNotValidRulePayload{
+			MinRowCount: 1000000,
+		})
 	case advisor.SchemaRuleRequiredColumn:
 		payload, err = json.Marshal(advisor.RequiredColumnRulePayload{
 			ColumnList: []string{